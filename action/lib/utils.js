@@ -233,6 +233,9 @@ class Utils {
             catch (error) {
                 throw new Error(`Getting openID Connect JSON web token failed: ${error.message}`);
             }
+            // Frogbot exchanges a new JSON web token if the access token expires during the scan
+            process.env.JF_OIDC_PROVIDER_NAME = oidcProviderName;
+            process.env.JF_OIDC_AUDIENCE = audience;
             try {
                 return yield this.initJfrogAccessTokenThroughOidcProtocol(jfrogUrl, jsonWebToken, oidcProviderName);
             }
@@ -269,6 +272,10 @@ class Utils {
             if (responseJson.access_token) {
                 core.setSecret(responseJson.access_token);
             }
+            if (responseJson.refresh_token) {
+                process.env.JF_REFRESH_TOKEN = responseJson.refresh_token;
+                core.setSecret(responseJson.refresh_token);
+            }
             if (responseJson.errors) {
                 throw new Error(`${JSON.stringify(responseJson.errors)}`);
             }
@@ -202,6 +202,9 @@ export class Utils {
             throw new Error(`Getting openID Connect JSON web token failed: ${error.message}`);
         }
 
+        // Frogbot exchanges a new JSON web token if the access token expires during the scan
+        process.env.JF_OIDC_PROVIDER_NAME = oidcProviderName;
+        process.env.JF_OIDC_AUDIENCE = audience;
         try {
             return await this.initJfrogAccessTokenThroughOidcProtocol(jfrogUrl, jsonWebToken, oidcProviderName);
         } catch (error: any) {
@@ -242,6 +245,10 @@ export class Utils {
         if (responseJson.access_token) {
             core.setSecret(responseJson.access_token);
         }
+        if (responseJson.refresh_token) {
+            process.env.JF_REFRESH_TOKEN = responseJson.refresh_token;
+            core.setSecret(responseJson.refresh_token);
+        }
         if (responseJson.errors) {
             throw new Error(`${JSON.stringify(responseJson.errors)}`);
         }
@@ -249,5 +256,6 @@ export class Utils {
 }
 export interface TokenExchangeResponseData {
     access_token: string;
+    refresh_token?: string;
     errors: string;
 }
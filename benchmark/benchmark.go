@@ -0,0 +1,244 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/scanpullrequest"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	IssuesFlag      = "issues"
+	IterationsFlag  = "iterations"
+	ParallelismFlag = "parallelism"
+
+	DefaultIssues     = 1000
+	DefaultIterations = 10
+)
+
+// BenchmarkCmd measures the processing of large scan results, without scanning and without connecting to the JFrog platform or to the Git provider.
+// It runs the finding IDs hashing, the pull request diff and the comments rendering over synthetic scan results, and prints their timings,
+// so performance regressions can be detected on the hardware that runs Frogbot, such as ARM machines that scan large monorepos.
+type BenchmarkCmd struct {
+	// The number of issues in the synthetic scan results
+	Issues int
+	// The number of times each stage runs
+	Iterations int
+	// The number of stage runs that are executed concurrently, the number of CPUs by default
+	Parallelism int
+	// The writer the report is printed to, the standard output by default
+	output io.Writer
+}
+
+type stage struct {
+	name string
+	run  func()
+}
+
+type stageResult struct {
+	name    string
+	runs    int
+	total   time.Duration
+	average time.Duration
+	median  time.Duration
+	max     time.Duration
+}
+
+func (bc *BenchmarkCmd) Run() (err error) {
+	if err = bc.setDefaults(); err != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("Benchmarking %d issues, %d iterations with parallelism %d on %s/%s", bc.Issues, bc.Iterations, bc.Parallelism, runtime.GOOS, runtime.GOARCH))
+	stageResults := make([]stageResult, 0)
+	for _, benchmarkStage := range bc.getStages() {
+		log.Debug("Running the benchmark stage:", benchmarkStage.name)
+		stageResults = append(stageResults, bc.runStage(benchmarkStage))
+	}
+	return bc.printReport(stageResults)
+}
+
+func (bc *BenchmarkCmd) setDefaults() error {
+	if bc.Issues < 0 || bc.Iterations < 0 || bc.Parallelism < 0 {
+		return errors.New("the number of issues, iterations and the parallelism must not be negative")
+	}
+	if bc.Issues == 0 {
+		bc.Issues = DefaultIssues
+	}
+	if bc.Iterations == 0 {
+		bc.Iterations = DefaultIterations
+	}
+	if bc.Parallelism == 0 {
+		bc.Parallelism = runtime.NumCPU()
+	}
+	return nil
+}
+
+// The target branch results share half of their issues with the source branch results, so the diff finds both new and fixed issues
+func (bc *BenchmarkCmd) getStages() []stage {
+	sourceResults := generateSimpleJsonResults(bc.Issues, 0)
+	targetResults := generateSimpleJsonResults(bc.Issues, bc.Issues/2)
+	newIssues := scanpullrequest.GetNewIssues(targetResults, sourceResults)
+	resultContext := results.ResultContext{IncludeVulnerabilities: true}
+	renderComments := func(provider vcsutils.VcsProvider) func() {
+		return func() {
+			utils.GeneratePullRequestComments(newIssues, resultContext, provider, utils.Params{})
+		}
+	}
+	return []stage{
+		{name: "Finding IDs hashing", run: func() { hashFindingIds(sourceResults) }},
+		{name: "Pull request diff", run: func() { scanpullrequest.GetNewIssues(targetResults, sourceResults) }},
+		{name: "Standard comments rendering", run: renderComments(vcsutils.GitHub)},
+		{name: "Simplified comments rendering", run: renderComments(vcsutils.BitbucketServer)},
+	}
+}
+
+// Runs the iterations of the stage by the configured number of workers
+func (bc *BenchmarkCmd) runStage(benchmarkStage stage) stageResult {
+	durations := make([]time.Duration, bc.Iterations)
+	iterations := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < bc.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for iteration := range iterations {
+				iterationStart := time.Now()
+				benchmarkStage.run()
+				durations[iteration] = time.Since(iterationStart)
+			}
+		}()
+	}
+	for i := 0; i < bc.Iterations; i++ {
+		iterations <- i
+	}
+	close(iterations)
+	wg.Wait()
+	return newStageResult(benchmarkStage.name, time.Since(start), durations)
+}
+
+func newStageResult(name string, total time.Duration, durations []time.Duration) stageResult {
+	result := stageResult{name: name, runs: len(durations), total: total}
+	if len(durations) == 0 {
+		return result
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, duration := range sorted {
+		sum += duration
+	}
+	result.average = sum / time.Duration(len(sorted))
+	result.median = sorted[len(sorted)/2]
+	result.max = sorted[len(sorted)-1]
+	return result
+}
+
+func (bc *BenchmarkCmd) printReport(stageResults []stageResult) (err error) {
+	output := bc.output
+	if output == nil {
+		output = os.Stdout
+	}
+	if _, err = fmt.Fprintf(output, "Frogbot %s benchmark on %s/%s with %d CPUs: %d issues, %d iterations, parallelism %d\n\n",
+		utils.FrogbotVersion, runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), bc.Issues, bc.Iterations, bc.Parallelism); err != nil {
+		return
+	}
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	if _, err = fmt.Fprintln(writer, "STAGE\tRUNS\tTOTAL\tAVERAGE\tMEDIAN\tMAX"); err != nil {
+		return
+	}
+	for _, result := range stageResults {
+		if _, err = fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t%s\n", result.name, result.runs, formatDuration(result.total), formatDuration(result.average), formatDuration(result.median), formatDuration(result.max)); err != nil {
+			return
+		}
+	}
+	return writer.Flush()
+}
+
+func formatDuration(duration time.Duration) string {
+	return duration.Round(time.Microsecond).String()
+}
+
+func hashFindingIds(simpleJsonResults formats.SimpleJsonResults) {
+	for _, row := range append(simpleJsonResults.Vulnerabilities, simpleJsonResults.SecurityViolations...) {
+		issues.GetScaFindingId(row)
+	}
+	for _, rows := range [][]formats.SourceCodeRow{simpleJsonResults.IacsVulnerabilities, simpleJsonResults.SecretsVulnerabilities, simpleJsonResults.SastVulnerabilities} {
+		for _, row := range rows {
+			issues.GetSourceCodeFindingId(row)
+		}
+	}
+}
+
+// Generates scan results with the given number of issues, numbered from the given offset.
+// Half of the issues are SCA vulnerabilities and violations, and the rest are IaC, Secrets and SAST findings.
+func generateSimpleJsonResults(issuesCount, offset int) (simpleJsonResults formats.SimpleJsonResults) {
+	severities := []string{"Critical", "High", "Medium", "Low"}
+	scaStatus, jasStatus := 0, 0
+	simpleJsonResults.Statuses = formats.ScanStatus{ScaStatusCode: &scaStatus, IacStatusCode: &jasStatus, SecretsStatusCode: &jasStatus, SastStatusCode: &jasStatus, ApplicabilityStatusCode: &jasStatus}
+	for i := offset; i < offset+issuesCount; i++ {
+		severity := formats.SeverityDetails{Severity: severities[i%len(severities)]}
+		switch {
+		case i%2 == 0:
+			row := generateScaRow(i, severity)
+			if i%4 == 0 {
+				row.ViolationContext = formats.ViolationContext{Watch: "benchmark-watch", Policies: []string{"benchmark-policy"}}
+				simpleJsonResults.SecurityViolations = append(simpleJsonResults.SecurityViolations, row)
+			} else {
+				simpleJsonResults.Vulnerabilities = append(simpleJsonResults.Vulnerabilities, row)
+			}
+		case i%6 == 1:
+			simpleJsonResults.IacsVulnerabilities = append(simpleJsonResults.IacsVulnerabilities, generateSourceCodeRow(i, severity, "terraform/main.tf", "aws_s3_public"))
+		case i%6 == 3:
+			simpleJsonResults.SecretsVulnerabilities = append(simpleJsonResults.SecretsVulnerabilities, generateSourceCodeRow(i, severity, "config/secrets.yml", "REQ.SECRET.KEYS"))
+		default:
+			simpleJsonResults.SastVulnerabilities = append(simpleJsonResults.SastVulnerabilities, generateSourceCodeRow(i, severity, "src/app.js", "js-insecure-random"))
+		}
+	}
+	return
+}
+
+func generateScaRow(index int, severity formats.SeverityDetails) formats.VulnerabilityOrViolationRow {
+	packageName := fmt.Sprintf("benchmark-package-%d", index)
+	return formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           severity,
+			ImpactedDependencyName:    packageName,
+			ImpactedDependencyVersion: "1.0.0",
+			ImpactedDependencyType:    "npm",
+			Components:                []formats.ComponentRow{{Name: packageName, Version: "1.0.0"}},
+		},
+		Summary:       fmt.Sprintf("A synthetic vulnerability of %s", packageName),
+		Applicable:    "Applicable",
+		FixedVersions: []string{"[1.0.1]"},
+		Cves:          []formats.CveRow{{Id: fmt.Sprintf("CVE-2024-%d", 10000+index), CvssV3: "9.8"}},
+		IssueId:       fmt.Sprintf("XRAY-%d", index),
+		JfrogResearchInformation: &formats.JfrogResearchInformation{
+			Summary:     "A synthetic JFrog research summary",
+			Details:     "Synthetic JFrog research details of the vulnerability, with enough text to be rendered as a paragraph.",
+			Remediation: "Upgrade the package to the fixed version.",
+		},
+	}
+}
+
+func generateSourceCodeRow(index int, severity formats.SeverityDetails, file, ruleId string) formats.SourceCodeRow {
+	return formats.SourceCodeRow{
+		SeverityDetails: severity,
+		ScannerInfo:     formats.ScannerInfo{RuleId: ruleId, ScannerDescription: fmt.Sprintf("A synthetic %s finding", ruleId)},
+		Location:        formats.Location{File: file, StartLine: index, StartColumn: 1, EndLine: index, EndColumn: 20, Snippet: fmt.Sprintf("synthetic snippet %d", index)},
+		Finding:         fmt.Sprintf("Synthetic finding %d", index),
+	}
+}
@@ -0,0 +1,51 @@
+package benchmark
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/scanpullrequest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchmarkRun(t *testing.T) {
+	output := &bytes.Buffer{}
+	cmd := &BenchmarkCmd{Issues: 60, Iterations: 4, Parallelism: 2, output: output}
+	require.NoError(t, cmd.Run())
+	report := output.String()
+	assert.Contains(t, report, "60 issues, 4 iterations, parallelism 2")
+	for _, stageName := range []string{"Finding IDs hashing", "Pull request diff", "Standard comments rendering", "Simplified comments rendering"} {
+		assert.Contains(t, report, stageName)
+	}
+}
+
+func TestBenchmarkSetDefaults(t *testing.T) {
+	cmd := &BenchmarkCmd{}
+	require.NoError(t, cmd.setDefaults())
+	assert.Equal(t, DefaultIssues, cmd.Issues)
+	assert.Equal(t, DefaultIterations, cmd.Iterations)
+	assert.Positive(t, cmd.Parallelism)
+
+	assert.Error(t, (&BenchmarkCmd{Iterations: -1}).setDefaults())
+}
+
+func TestGenerateSimpleJsonResults(t *testing.T) {
+	source := generateSimpleJsonResults(12, 0)
+	assert.Len(t, source.Vulnerabilities, 3)
+	assert.Len(t, source.SecurityViolations, 3)
+	assert.Len(t, source.IacsVulnerabilities, 2)
+	assert.Len(t, source.SecretsVulnerabilities, 2)
+	assert.Len(t, source.SastVulnerabilities, 2)
+
+	// Half of the issues are shared with the target results, so the diff finds new and fixed issues
+	newIssues := scanpullrequest.GetNewIssues(generateSimpleJsonResults(12, 6), source)
+	assert.Equal(t, 6, newIssues.GetAllIssuesCount(true))
+	assert.NotEmpty(t, newIssues.FixedScaIssues)
+}
+
+func TestNewStageResult(t *testing.T) {
+	result := newStageResult("stage", 5*time.Second, []time.Duration{3 * time.Second, time.Second, 2 * time.Second})
+	assert.Equal(t, stageResult{name: "stage", runs: 3, total: 5 * time.Second, average: 2 * time.Second, median: 2 * time.Second, max: 3 * time.Second}, result)
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/jfrog/frogbot/v2/benchmark"
+	"github.com/jfrog/frogbot/v2/reportcoverage"
 	"github.com/jfrog/frogbot/v2/scanpullrequest"
 	"github.com/jfrog/frogbot/v2/scanrepository"
+	"github.com/jfrog/frogbot/v2/serve"
+	"github.com/jfrog/frogbot/v2/upgrade"
 	"github.com/jfrog/frogbot/v2/utils"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/validateconfig"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/usage"
@@ -40,9 +45,33 @@ func GetCommands() []*clitool.Command {
 			Aliases: []string{"cfpr", "create-fix-pull-requests"},
 			Usage:   "Scan the current branch and create pull requests with fixes if needed",
 			Action: func(ctx *clitool.Context) error {
-				return Exec(&scanrepository.ScanRepositoryCmd{}, ctx.Command.Name)
+				return Exec(&scanrepository.ScanRepositoryCmd{Preview: ctx.Bool(scanrepository.DryRunFlag), FixCvesFile: ctx.String(scanrepository.FixCvesFlag)}, ctx.Command.Name)
+			},
+			Flags: []clitool.Flag{
+				&clitool.BoolFlag{
+					Name:  scanrepository.DryRunFlag,
+					Usage: "Preview the fixes without pushing them or opening pull requests. The diff and the pull request details of each fix are printed",
+				},
+				&clitool.StringFlag{
+					Name:  scanrepository.FixCvesFlag,
+					Usage: "Path of a file that lists the CVE IDs or package names to fix, one per line. All other vulnerabilities are left untouched. Overrides the target CVEs of the configuration",
+				},
+			},
+		},
+		{
+			Name:    utils.GenerateBaseline,
+			Aliases: []string{"gb"},
+			Usage:   "Scans a branch and writes all its findings to a baseline file. Pull requests to a branch with the baseline file don't report these findings when all the issues are reported",
+			Action: func(ctx *clitool.Context) error {
+				return Exec(&scanpullrequest.GenerateBaselineCmd{OutputPath: ctx.String(scanpullrequest.BaselineOutputFlag)}, ctx.Command.Name)
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  scanpullrequest.BaselineOutputFlag,
+					Usage: "The path the baseline file is written to",
+					Value: utils.BaselineFilePath,
+				},
 			},
-			Flags: []clitool.Flag{},
 		},
 		{
 			Name:    utils.ScanAllPullRequests,
@@ -58,16 +87,169 @@ func GetCommands() []*clitool.Command {
 			Aliases: []string{"scan-and-fix-repos", "safr"},
 			Usage:   "Scan single or multiple repositories and create pull requests with fixes if any security vulnerabilities are found",
 			Action: func(ctx *clitool.Context) error {
-				return Exec(&scanrepository.ScanMultipleRepositories{}, ctx.Command.Name)
+				// The environment variables are kept before the command removes them, for the processes that scan the repositories
+				return Exec(&scanrepository.ScanMultipleRepositories{Repository: ctx.String(scanrepository.RepositoryFlag), Env: os.Environ()}, ctx.Command.Name)
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  scanrepository.RepositoryFlag,
+					Usage: "Scan only this repository of the configuration. Each repository is scanned in a separate process with this flag, up to JF_MAX_CONCURRENT_REPOS at a time",
+				},
+			},
+		},
+		{
+			Name:    utils.FixCampaign,
+			Aliases: []string{"fc"},
+			Usage:   "Fix a single CVE or package@version across multiple repositories, opening pull requests that share a campaign identifier",
+			Action: func(ctx *clitool.Context) error {
+				return Exec(&scanrepository.FixCampaignCmd{}, ctx.Command.Name)
+			},
+			Flags: []clitool.Flag{},
+		},
+		{
+			Name:    utils.ValidateConfig,
+			Aliases: []string{"vc"},
+			Usage:   "Validates the Frogbot configuration of a command and the connection to the JFrog platform and the Git provider, and prints what the command would do without scanning",
+			Action: func(ctx *clitool.Context) error {
+				log.Info("Frogbot version:", utils.FrogbotVersion)
+				return (&validateconfig.ValidateConfigCmd{TargetCommand: ctx.String(validateconfig.TargetCommandFlag)}).Run()
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  validateconfig.TargetCommandFlag,
+					Usage: "The Frogbot command to validate its configuration",
+					Value: utils.ScanRepository,
+				},
+			},
+		},
+		{
+			Name:    utils.Benchmark,
+			Aliases: []string{"bm"},
+			Usage:   "Measures the finding IDs hashing, the pull request diff and the comments rendering over synthetic large scan results, and prints their timings",
+			Action: func(ctx *clitool.Context) error {
+				log.Info("Frogbot version:", utils.FrogbotVersion)
+				return (&benchmark.BenchmarkCmd{
+					Issues:      ctx.Int(benchmark.IssuesFlag),
+					Iterations:  ctx.Int(benchmark.IterationsFlag),
+					Parallelism: ctx.Int(benchmark.ParallelismFlag),
+				}).Run()
+			},
+			Flags: []clitool.Flag{
+				&clitool.IntFlag{
+					Name:  benchmark.IssuesFlag,
+					Usage: "The number of issues in the synthetic scan results",
+					Value: benchmark.DefaultIssues,
+				},
+				&clitool.IntFlag{
+					Name:  benchmark.IterationsFlag,
+					Usage: "The number of times each stage runs",
+					Value: benchmark.DefaultIterations,
+				},
+				&clitool.IntFlag{
+					Name:  benchmark.ParallelismFlag,
+					Usage: "The number of stage runs that are executed concurrently. Defaults to the number of CPUs",
+				},
+			},
+		},
+		{
+			Name:  utils.Serve,
+			Usage: "Runs a server that receives the webhooks of the Git provider, and scans the pull requests when they're opened or updated and the branches when they're pushed. The webhooks are validated with the secret of JF_WEBHOOK_SECRET",
+			Action: func(ctx *clitool.Context) error {
+				log.Info("Frogbot version:", utils.FrogbotVersion)
+				return (&serve.ServeCmd{Address: ctx.String(serve.AddressFlag), Workers: ctx.Int(serve.WorkersFlag)}).Run()
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  serve.AddressFlag,
+					Usage: "The address the server listens on",
+					Value: serve.DefaultAddress,
+				},
+				&clitool.IntFlag{
+					Name:  serve.WorkersFlag,
+					Usage: "The number of scan jobs that run at the same time. Each job runs in a separate Frogbot process",
+					Value: serve.DefaultWorkers,
+				},
+			},
+		},
+		{
+			Name:    utils.ReportCoverage,
+			Aliases: []string{"rc"},
+			Usage:   "Lists the repositories of an owner, and reports which of them have Frogbot configured and when Frogbot last scanned them. Only the Git provider details are required",
+			Action: func(ctx *clitool.Context) error {
+				log.Info("Frogbot version:", utils.FrogbotVersion)
+				return (&reportcoverage.ReportCoverageCmd{
+					Owner:                 ctx.String(reportcoverage.OwnerFlag),
+					Format:                ctx.String(reportcoverage.FormatFlag),
+					OutputPath:            ctx.String(reportcoverage.OutputFlag),
+					PullRequestsStateFile: ctx.String(reportcoverage.PullRequestsStateFileFlag),
+					ScanHistoryFile:       ctx.String(reportcoverage.ScanHistoryFileFlag),
+				}).Run()
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  reportcoverage.OwnerFlag,
+					Usage: "The organization, group, workspace or project whose repositories are reported. Defaults to JF_GIT_OWNER",
+				},
+				&clitool.StringFlag{
+					Name:  reportcoverage.FormatFlag,
+					Usage: "The format of the report: markdown, json or csv",
+					Value: reportcoverage.DefaultFormat,
+				},
+				&clitool.StringFlag{
+					Name:  reportcoverage.OutputFlag,
+					Usage: "The path the report is written to. The report is printed if it isn't set",
+				},
+				&clitool.StringFlag{
+					Name:  reportcoverage.PullRequestsStateFileFlag,
+					Usage: "The pull requests state file of the scan-all-pull-requests command, which the times of the pull request scans are read from. Defaults to JF_PULL_REQUESTS_STATE_FILE",
+				},
+				&clitool.StringFlag{
+					Name:  reportcoverage.ScanHistoryFileFlag,
+					Usage: "The path of the scan history files, which the times of the repository scans are read from. {REPOSITORY} is replaced with the name of each repository",
+				},
+			},
+		},
+		{
+			Name:    utils.ScanLocal,
+			Aliases: []string{"sl"},
+			Usage:   "Scans the current directory as a pull request is scanned, and prints the results. Only the JFrog platform details are required, so it can run before pushing, for example as a pre-commit hook",
+			Action: func(ctx *clitool.Context) error {
+				log.Info("Frogbot version:", utils.FrogbotVersion)
+				return (&scanpullrequest.ScanLocalCmd{}).Run()
 			},
 			Flags: []clitool.Flag{},
 		},
+		{
+			Name:  utils.Upgrade,
+			Usage: "Upgrades the running Frogbot executable to the latest version, or to a specific version. The releases repository is used if JF_RELEASES_REPO is set, and the GitHub releases otherwise",
+			Action: func(ctx *clitool.Context) error {
+				log.Info("Frogbot version:", utils.FrogbotVersion)
+				return (&upgrade.UpgradeCmd{Version: ctx.String(upgrade.VersionFlag), CheckOnly: ctx.Bool(upgrade.CheckFlag)}).Run()
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  upgrade.VersionFlag,
+					Usage: "The version to upgrade to. Defaults to the latest version",
+				},
+				&clitool.BoolFlag{
+					Name:  upgrade.CheckFlag,
+					Usage: "Only check whether a newer version is available, without upgrading",
+				},
+			},
+		},
 	}
 }
 
 func Exec(command FrogbotCommand, commandName string) (err error) {
 	// Get frogbotDetails that contains the config, server, and VCS client
 	log.Info("Frogbot version:", utils.FrogbotVersion)
+	// The run metrics are collected from the loading of the configuration, if a metrics sink is configured.
+	// The run summary is printed last, and sets the exit code of a run that failed because of the security issues that were found.
+	utils.StartRunSummary(commandName)
+	defer func() {
+		utils.FinishRunMetrics(err)
+		err = utils.FinishRunSummary(err)
+	}()
 	frogbotDetails, err := utils.GetFrogbotDetails(commandName)
 	if err != nil {
 		return err
@@ -0,0 +1,214 @@
+package packagehandlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/bazel"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The files of a Bazel workspace that pin the versions of its Maven artifacts and Go modules
+var bazelPinningFiles = []string{bazel.ModuleFile, "WORKSPACE", "WORKSPACE.bazel"}
+
+// BazelPackageHandler updates the versions of the vulnerable dependencies that the files of Bazel workspaces pin, and repins the lockfiles.
+// The Maven artifacts are pinned by the coordinates of MODULE.bazel, WORKSPACE or the Starlark macros files, and repinned by rules_jvm_external.
+// The Go modules are pinned by the go_repository rules of the Starlark macros files, or by go.mod when the go_deps extension of Bzlmod reads it.
+type BazelPackageHandler struct {
+	CommonPackageHandler
+	// The command that repins the lockfiles after the versions are updated, instead of the default command of the technology
+	repinCommand string
+}
+
+func (bph *BazelPackageHandler) UpdateDependency(vulnDetails *utils.VulnerabilityDetails) (err error) {
+	// The versions of the transitive dependencies are resolved when repinning, so only the pinned dependencies can be updated
+	if !vulnDetails.IsDirectDependency {
+		return &utils.ErrUnsupportedFix{
+			PackageName:  vulnDetails.ImpactedDependencyName,
+			FixedVersion: vulnDetails.SuggestedFixedVersion,
+			ErrorType:    utils.IndirectDependencyFixNotSupported,
+		}
+	}
+	var defaultRepinCommand []string
+	switch vulnDetails.ImpactedDependencyType {
+	case techutils.Maven.ToFormal():
+		if err = bph.updateMavenArtifact(vulnDetails); err != nil {
+			return
+		}
+		defaultRepinCommand = []string{"RULES_JVM_EXTERNAL_REPIN=1", "bazel", "run", "@unpinned_maven//:pin"}
+	case techutils.Go.ToFormal():
+		var isModuleLockUsed bool
+		if isModuleLockUsed, err = bph.updateGoModule(vulnDetails); err != nil {
+			return
+		}
+		if isModuleLockUsed {
+			defaultRepinCommand = []string{"bazel", "mod", "deps", "--lockfile_mode=update"}
+		}
+	default:
+		return fmt.Errorf("fixing the %s dependencies of Bazel workspaces isn't supported", vulnDetails.ImpactedDependencyType)
+	}
+	repinCommand := defaultRepinCommand
+	if bph.repinCommand != "" {
+		repinCommand = strings.Fields(bph.repinCommand)
+	}
+	return runRepinCommand(repinCommand)
+}
+
+// Replaces the 'group:artifact:version' coordinates of the artifact in the files that pin the Maven artifacts
+func (bph *BazelPackageHandler) updateMavenArtifact(vulnDetails *utils.VulnerabilityDetails) error {
+	files, err := findBazelPinningFiles()
+	if err != nil {
+		return err
+	}
+	currentCoordinate := fmt.Sprintf(`"%s:%s"`, vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion)
+	fixedCoordinate := fmt.Sprintf(`"%s:%s"`, vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion)
+	isAnyFileChanged := false
+	for _, file := range files {
+		var isFileChanged bool
+		if isFileChanged, err = replaceInFile(file, func(content string) string {
+			return strings.ReplaceAll(content, currentCoordinate, fixedCoordinate)
+		}); err != nil {
+			return err
+		}
+		isAnyFileChanged = isAnyFileChanged || isFileChanged
+	}
+	if !isAnyFileChanged {
+		return fmt.Errorf("the Maven artifact %s was not found in the %s files of the project", strings.Trim(currentCoordinate, `"`), strings.Join(bazelPinningFiles, ", "))
+	}
+	return nil
+}
+
+// Updates the version and the checksum of the go_repository rules of the module.
+// If no rule declares the module, the go_deps extension of Bzlmod reads it from go.mod, which is updated with 'go get'. Returns true in this case.
+func (bph *BazelPackageHandler) updateGoModule(vulnDetails *utils.VulnerabilityDetails) (isModuleLockUsed bool, err error) {
+	files, err := findBazelPinningFiles()
+	if err != nil {
+		return
+	}
+	// The versions of Go modules start with 'v', which the fixed versions of Xray may omit
+	module, fixedVersion := vulnDetails.ImpactedDependencyName, "v"+strings.TrimPrefix(vulnDetails.SuggestedFixedVersion, "v")
+	ruleRegex := regexp.MustCompile(`(?s)go_repository\([^)]*?importpath\s*=\s*"` + regexp.QuoteMeta(module) + `"[^)]*?\n\s*\)`)
+	var sum string
+	var sumErr error
+	isAnyFileChanged := false
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".bzl") {
+			continue
+		}
+		var isFileChanged bool
+		if isFileChanged, err = replaceInFile(file, func(content string) string {
+			return ruleRegex.ReplaceAllStringFunc(content, func(rule string) string {
+				if sum == "" {
+					if sum, sumErr = getGoModuleSum(module, fixedVersion); sumErr != nil {
+						return rule
+					}
+				}
+				rule = regexp.MustCompile(`version\s*=\s*"[^"]*"`).ReplaceAllString(rule, fmt.Sprintf(`version = "%s"`, fixedVersion))
+				return regexp.MustCompile(`sum\s*=\s*"[^"]*"`).ReplaceAllString(rule, fmt.Sprintf(`sum = "%s"`, sum))
+			})
+		}); err != nil {
+			return
+		}
+		if sumErr != nil {
+			return false, sumErr
+		}
+		isAnyFileChanged = isAnyFileChanged || isFileChanged
+	}
+	if isAnyFileChanged {
+		return
+	}
+	if _, e := os.Stat("go.mod"); e != nil {
+		return false, fmt.Errorf("the Go module %s was not found in the go_repository rules or the go.mod file of the project", module)
+	}
+	log.Debug(fmt.Sprintf("The Go module %s isn't declared by a go_repository rule, so it's updated in go.mod", module))
+	return true, runPackageMangerCommand("go", techutils.Go.String(), []string{"get", module + "@" + fixedVersion})
+}
+
+// Returns the checksum of the module version that go_repository rules verify, as it's listed in go.sum
+func getGoModuleSum(module, version string) (string, error) {
+	//#nosec G204 -- The module and the version are read from the lockfiles of the repository.
+	output, err := exec.Command("go", "mod", "download", "-json", module+"@"+version).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to download the Go module %s@%s: %s", module, version, err.Error())
+	}
+	var download struct {
+		Sum string `json:"Sum"`
+	}
+	if err = json.Unmarshal(output, &download); err != nil {
+		return "", err
+	}
+	return download.Sum, nil
+}
+
+// Returns the paths of the files of the Bazel workspaces in the project that pin dependencies, including the Starlark macros files
+func findBazelPinningFiles() (files []string, err error) {
+	err = filepath.WalkDir(".", func(path string, d fs.DirEntry, innerErr error) error {
+		if innerErr != nil {
+			return innerErr
+		}
+		if d.IsDir() {
+			if path != "." && (d.Name() == ".git" || strings.HasPrefix(d.Name(), "bazel-")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, name := range bazelPinningFiles {
+			if d.Name() == name {
+				files = append(files, path)
+				return nil
+			}
+		}
+		if strings.HasSuffix(d.Name(), ".bzl") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to look for the files of the Bazel workspaces: %s", err.Error())
+	}
+	return
+}
+
+func replaceInFile(path string, replace func(content string) string) (isFileChanged bool, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read '%s': %s", path, err.Error())
+	}
+	fixedContent := replace(string(content))
+	if fixedContent == string(content) {
+		return false, nil
+	}
+	if err = os.WriteFile(path, []byte(fixedContent), 0600); err != nil {
+		return false, fmt.Errorf("failed to write '%s': %s", path, err.Error())
+	}
+	return true, nil
+}
+
+// Runs the repin command in the working directory of the project. The variables that precede the command, such as 'REPIN=1', are set in its environment.
+func runRepinCommand(command []string) error {
+	var env []string
+	for len(command) > 0 && strings.Contains(command[0], "=") {
+		env = append(env, command[0])
+		command = command[1:]
+	}
+	if len(command) == 0 {
+		return nil
+	}
+	fullCommand := strings.Join(command, " ")
+	log.Info(fmt.Sprintf("Repinning the Bazel lockfiles with '%s'", fullCommand))
+	//#nosec G204 -- The repin command is set by the user in the Frogbot configuration.
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to repin the Bazel lockfiles: '%s' command failed: %s\n%s", fullCommand, err.Error(), output)
+	}
+	return nil
+}
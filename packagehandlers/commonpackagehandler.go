@@ -9,6 +9,9 @@ import (
 	"strings"
 
 	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/bazel"
+	"github.com/jfrog/frogbot/v2/utils/composer"
+	"github.com/jfrog/frogbot/v2/utils/githubactions"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -20,7 +23,21 @@ type PackageHandler interface {
 	SetCommonParams(serverDetails *config.ServerDetails, depsRepo string)
 }
 
+// Returns the package handler of the vulnerability's technology.
+// The handlers added by Register are consulted first, then the package handler plugins of the configuration, and then the built-in handlers.
 func GetCompatiblePackageHandler(vulnDetails *utils.VulnerabilityDetails, details *utils.ScanDetails) (handler PackageHandler) {
+	if factory, exists := getRegisteredHandler(vulnDetails.Technology.String()); exists {
+		handler = factory(vulnDetails, details)
+	} else if pluginPath := details.PackageHandlerPlugin(vulnDetails.Technology.String()); pluginPath != "" {
+		handler = &ExecPackageHandler{Path: pluginPath}
+	} else {
+		handler = getBuiltInPackageHandler(vulnDetails, details)
+	}
+	handler.SetCommonParams(details.ServerDetails, details.DepsRepo)
+	return
+}
+
+func getBuiltInPackageHandler(vulnDetails *utils.VulnerabilityDetails, details *utils.ScanDetails) (handler PackageHandler) {
 	switch vulnDetails.Technology {
 	case techutils.Go:
 		handler = &GoPackageHandler{}
@@ -44,10 +61,17 @@ func GetCompatiblePackageHandler(vulnDetails *utils.VulnerabilityDetails, detail
 		handler = &PnpmPackageHandler{}
 	case techutils.Conan:
 		handler = &ConanPackageHandler{}
+	case techutils.Docker:
+		handler = &DockerPackageHandler{}
+	case githubactions.Technology:
+		handler = newGitHubActionsPackageHandler(details)
+	case bazel.Technology:
+		handler = &BazelPackageHandler{repinCommand: details.BazelRepinCommand}
+	case composer.Technology:
+		handler = &ComposerPackageHandler{}
 	default:
 		handler = &UnsupportedPackageHandler{}
 	}
-	handler.SetCommonParams(details.ServerDetails, details.DepsRepo)
 	return
 }
 
@@ -0,0 +1,179 @@
+package packagehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/composer"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+var (
+	// Matches the 'require' and 'require-dev' sections of composer.json, whose values are the version constraints of the packages
+	composerRequireSectionRegex = regexp.MustCompile(`"require(-dev)?"\s*:\s*\{[^}]*\}`)
+	// The directories that don't contain the composer.json files of the projects
+	composerSkippedDirs = []string{".git", "node_modules", "vendor"}
+)
+
+// ComposerPackageHandler updates the version constraints of the vulnerable packages in the composer.json files of the project,
+// and regenerates the composer.lock files with 'composer update <package> --with-dependencies'.
+// If a dependencies repository is set, the packages are resolved from the Composer repository of Artifactory instead of Packagist.
+type ComposerPackageHandler struct {
+	CommonPackageHandler
+}
+
+func (cph *ComposerPackageHandler) UpdateDependency(vulnDetails *utils.VulnerabilityDetails) (err error) {
+	// The versions of the transitive packages are resolved by the constraints of the packages that require them
+	if !vulnDetails.IsDirectDependency {
+		return &utils.ErrUnsupportedFix{
+			PackageName:  vulnDetails.ImpactedDependencyName,
+			FixedVersion: vulnDetails.SuggestedFixedVersion,
+			ErrorType:    utils.IndirectDependencyFixNotSupported,
+		}
+	}
+	projectDirs, err := cph.updateConstraints(vulnDetails)
+	if err != nil {
+		return
+	}
+	env, cleanup, err := cph.getResolutionEnv()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, cleanup())
+	}()
+	for _, projectDir := range projectDirs {
+		if err = runComposerUpdate(projectDir, strings.ToLower(vulnDetails.ImpactedDependencyName), env); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Replaces the version constraints of the package in the composer.json files of the project that require it with '^<fixed version>'.
+// Returns the directories of the changed files, whose lockfiles are regenerated.
+func (cph *ComposerPackageHandler) updateConstraints(vulnDetails *utils.VulnerabilityDetails) (projectDirs []string, err error) {
+	descriptors, err := findComposerDescriptors()
+	if err != nil {
+		return
+	}
+	// The names of the packages are case-insensitive
+	constraintRegex := regexp.MustCompile(`(?i)("` + regexp.QuoteMeta(vulnDetails.ImpactedDependencyName) + `"\s*:\s*")[^"]*(")`)
+	fixedConstraint := "^" + strings.TrimPrefix(vulnDetails.SuggestedFixedVersion, "v")
+	for _, descriptor := range descriptors {
+		var isFileChanged bool
+		if isFileChanged, err = replaceInFile(descriptor, func(content string) string {
+			return composerRequireSectionRegex.ReplaceAllStringFunc(content, func(section string) string {
+				return constraintRegex.ReplaceAllString(section, "${1}"+fixedConstraint+"${2}")
+			})
+		}); err != nil {
+			return
+		}
+		if isFileChanged {
+			projectDirs = append(projectDirs, filepath.Dir(descriptor))
+		}
+	}
+	if len(projectDirs) == 0 {
+		err = fmt.Errorf("the Composer package %s was not found in the %s files of the project, or its constraint already allows %s", vulnDetails.ImpactedDependencyName, composer.DescriptorFile, vulnDetails.SuggestedFixedVersion)
+	}
+	return
+}
+
+// Returns the paths of the composer.json files of the project
+func findComposerDescriptors() (descriptors []string, err error) {
+	err = filepath.WalkDir(".", func(path string, d fs.DirEntry, innerErr error) error {
+		if innerErr != nil {
+			return innerErr
+		}
+		if d.IsDir() {
+			if path != "." && slices.Contains(composerSkippedDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == composer.DescriptorFile {
+			descriptors = append(descriptors, path)
+		}
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to look for the %s files of the project: %s", composer.DescriptorFile, err.Error())
+	}
+	return
+}
+
+// Returns the environment variables that make Composer resolve the packages from the Composer repository of Artifactory.
+// The repository is set in the global configuration of a temporary Composer home directory, which the cleanup function removes.
+func (cph *ComposerPackageHandler) getResolutionEnv() (env []string, cleanup func() error, err error) {
+	cleanup = func() error { return nil }
+	if cph.depsRepo == "" || cph.serverDetails == nil {
+		return
+	}
+	repositoryUrl := strings.TrimSuffix(cph.serverDetails.ArtifactoryUrl, "/") + "/api/composer/" + cph.depsRepo
+	parsedUrl, err := url.Parse(repositoryUrl)
+	if err != nil {
+		return
+	}
+	composerHome, err := os.MkdirTemp("", "frogbot-composer-")
+	if err != nil {
+		return
+	}
+	cleanup = func() error { return os.RemoveAll(composerHome) }
+	globalConfig, err := json.Marshal(map[string]any{"repositories": map[string]any{
+		"artifactory":   map[string]string{"type": "composer", "url": repositoryUrl},
+		"packagist.org": false,
+	}})
+	if err != nil {
+		return
+	}
+	if err = os.WriteFile(filepath.Join(composerHome, "config.json"), globalConfig, 0600); err != nil {
+		return
+	}
+	env = []string{"COMPOSER_HOME=" + composerHome}
+	var auth map[string]any
+	switch {
+	case cph.serverDetails.User != "" && cph.serverDetails.Password != "":
+		auth = map[string]any{"http-basic": map[string]any{parsedUrl.Host: map[string]string{"username": cph.serverDetails.User, "password": cph.serverDetails.Password}}}
+	case cph.serverDetails.User != "" && cph.serverDetails.AccessToken != "":
+		auth = map[string]any{"http-basic": map[string]any{parsedUrl.Host: map[string]string{"username": cph.serverDetails.User, "password": cph.serverDetails.AccessToken}}}
+	case cph.serverDetails.AccessToken != "":
+		auth = map[string]any{"bearer": map[string]string{parsedUrl.Host: cph.serverDetails.AccessToken}}
+	default:
+		return
+	}
+	authJson, err := json.Marshal(auth)
+	if err != nil {
+		return
+	}
+	env = append(env, "COMPOSER_AUTH="+string(authJson))
+	return
+}
+
+// Regenerates the lockfile of the project with the fixed version of the package and the versions of the packages it requires.
+// The packages are installed only if the project installed them before.
+func runComposerUpdate(projectDir, packageName string, env []string) error {
+	args := []string{"update", packageName, "--with-dependencies", "--no-interaction", "--no-scripts"}
+	if _, err := os.Stat(filepath.Join(projectDir, "vendor")); err != nil {
+		args = append(args, "--no-install")
+	}
+	fullCommand := "composer " + strings.Join(args, " ")
+	log.Debug(fmt.Sprintf("Running '%s' in '%s'", fullCommand, projectDir))
+	//#nosec G204 -- The package name is read from the lockfiles of the repository.
+	cmd := exec.Command("composer", args...)
+	cmd.Dir = projectDir
+	cmd.Env = append(os.Environ(), env...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update %s dependency: '%s' command failed: %s\n%s", composer.Technology, fullCommand, err.Error(), output)
+	}
+	return nil
+}
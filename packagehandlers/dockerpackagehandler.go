@@ -0,0 +1,70 @@
+package packagehandlers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/dockerimage"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// DockerPackageHandler bumps the tags of vulnerable base images in the FROM instructions of the Dockerfiles
+type DockerPackageHandler struct {
+	CommonPackageHandler
+}
+
+func (docker *DockerPackageHandler) UpdateDependency(vulnDetails *utils.VulnerabilityDetails) error {
+	dockerfiles, err := dockerimage.FindDockerfiles(".")
+	if err != nil {
+		return err
+	}
+	isAnyDockerfileChanged := false
+	for _, dockerfile := range dockerfiles {
+		var isFileChanged bool
+		if isFileChanged, err = updateBaseImageTag(dockerfile, vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion); err != nil {
+			return err
+		}
+		isAnyDockerfileChanged = isAnyDockerfileChanged || isFileChanged
+	}
+	if !isAnyDockerfileChanged {
+		return fmt.Errorf("the base image '%s:%s' was not found in the Dockerfiles of the project", vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion)
+	}
+	return nil
+}
+
+// Replaces the tag of the base image in the FROM instructions of the Dockerfile.
+// A digest that pins the image is removed, as it belongs to the vulnerable tag.
+func updateBaseImageTag(dockerfilePath, imageName, currentTag, fixedTag string) (isFileChanged bool, err error) {
+	content, err := os.ReadFile(dockerfilePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the Dockerfile '%s': %s", dockerfilePath, err.Error())
+	}
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "--") {
+				continue
+			}
+			if name, tag := dockerimage.SplitImageReference(field); name == imageName && tag == currentTag {
+				lines[i] = strings.Replace(line, field, imageName+":"+fixedTag, 1)
+				isFileChanged = true
+			}
+			// The image is the first argument that isn't a flag
+			break
+		}
+	}
+	if !isFileChanged {
+		log.Debug(fmt.Sprintf("The base image '%s:%s' wasn't found in the Dockerfile '%s'", imageName, currentTag, dockerfilePath))
+		return
+	}
+	if err = os.WriteFile(dockerfilePath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		err = fmt.Errorf("failed to write the fixed base image tag to the Dockerfile '%s': %s", dockerfilePath, err.Error())
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package packagehandlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The exit code of a package handler plugin that doesn't support the requested fix
+const ExecPluginUnsupportedFixExitCode = 3
+
+// ExecPluginRequest is the fix request that a package handler plugin receives as a JSON object on its standard input
+type ExecPluginRequest struct {
+	Technology         string   `json:"technology"`
+	PackageName        string   `json:"packageName"`
+	CurrentVersion     string   `json:"currentVersion"`
+	FixedVersion       string   `json:"fixedVersion"`
+	IsDirectDependency bool     `json:"isDirectDependency"`
+	Cves               []string `json:"cves,omitempty"`
+	// The Artifactory repository that the dependencies are resolved from, if one is configured
+	DepsRepo string `json:"depsRepo,omitempty"`
+}
+
+// ExecPackageHandler delegates the fixes to a plugin, an external executable that updates the descriptor files of the fixed project.
+// This allows fixing package managers that Frogbot doesn't support, without changing Frogbot.
+// The plugin runs in the working directory of the fixed project and receives an ExecPluginRequest on its standard input.
+// Exiting with ExecPluginUnsupportedFixExitCode reports the fix as unsupported, and any other non-zero exit code fails the fix.
+type ExecPackageHandler struct {
+	CommonPackageHandler
+	// The path of the plugin executable
+	Path string
+}
+
+func (eph *ExecPackageHandler) UpdateDependency(vulnDetails *utils.VulnerabilityDetails) error {
+	request := ExecPluginRequest{
+		Technology:         vulnDetails.Technology.String(),
+		PackageName:        vulnDetails.ImpactedDependencyName,
+		CurrentVersion:     vulnDetails.ImpactedDependencyVersion,
+		FixedVersion:       vulnDetails.SuggestedFixedVersion,
+		IsDirectDependency: vulnDetails.IsDirectDependency,
+		Cves:               vulnDetails.Cves,
+		DepsRepo:           eph.depsRepo,
+	}
+	content, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	log.Debug(fmt.Sprintf("Running the package handler plugin '%s' to update %s to version %s", eph.Path, request.PackageName, request.FixedVersion))
+	//#nosec G204 -- The plugin is set by the user in the Frogbot configuration.
+	cmd := exec.Command(eph.Path)
+	cmd.Stdin = bytes.NewReader(content)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == ExecPluginUnsupportedFixExitCode {
+		log.Info(fmt.Sprintf("The package handler plugin doesn't support updating %s to version %s: %s", request.PackageName, request.FixedVersion, strings.TrimSpace(string(output))))
+		return &utils.ErrUnsupportedFix{
+			PackageName:  request.PackageName,
+			FixedVersion: request.FixedVersion,
+			ErrorType:    utils.UnsupportedByPackageHandlerPlugin,
+		}
+	}
+	return fmt.Errorf("failed to update %s dependency: the package handler plugin '%s' failed: %s\n%s", request.Technology, eph.Path, err.Error(), output)
+}
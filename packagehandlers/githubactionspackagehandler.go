@@ -0,0 +1,66 @@
+package packagehandlers
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/githubactions"
+)
+
+// GitHubActionsPackageHandler bumps the vulnerable actions of the GitHub Actions workflows, and pins actions to commit SHAs.
+// The workflows are updated in the root directory of the repository.
+type GitHubActionsPackageHandler struct {
+	CommonPackageHandler
+	// Pin the bumped actions to the commit SHAs of their fixed versions
+	pin  bool
+	feed githubactions.AdvisoryFeed
+}
+
+func newGitHubActionsPackageHandler(details *utils.ScanDetails) *GitHubActionsPackageHandler {
+	git := details.Git
+	if git == nil {
+		git = &utils.Git{}
+	}
+	return &GitHubActionsPackageHandler{pin: git.PinGitHubActions, feed: githubactions.NewGitHubClient(git.GitProvider, git.VcsInfo)}
+}
+
+// The suggested fixed version is either the version that fixes the vulnerable action, or the commit SHA that pins a mutable reference.
+// The comment after a commit SHA keeps the version that the SHA points to.
+func (gh *GitHubActionsPackageHandler) UpdateDependency(vulnDetails *utils.VulnerabilityDetails) error {
+	references, err := githubactions.FindReferences(".")
+	if err != nil {
+		return err
+	}
+	isPinned := false
+	var workflows []string
+	for _, reference := range references {
+		if reference.Repository() == vulnDetails.ImpactedDependencyName && reference.Version == vulnDetails.ImpactedDependencyVersion {
+			isPinned = isPinned || reference.IsPinned()
+			workflows = append(workflows, reference.Workflow)
+		}
+	}
+	if len(workflows) == 0 {
+		return fmt.Errorf("the action '%s@%s' was not found in the GitHub Actions workflows", vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion)
+	}
+	newRef, comment := vulnDetails.SuggestedFixedVersion, ""
+	if githubactions.IsCommitSha(newRef) {
+		comment = vulnDetails.ImpactedDependencyVersion
+	} else if gh.pin || isPinned {
+		// An action that is already pinned to a commit SHA stays pinned
+		if newRef, err = gh.feed.GetCommitSha(vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion); err != nil {
+			return fmt.Errorf("failed to resolve the commit SHA of '%s@%s': %s", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion, err.Error())
+		}
+		comment = vulnDetails.SuggestedFixedVersion
+	}
+	updated := map[string]bool{}
+	for _, workflow := range workflows {
+		if updated[workflow] {
+			continue
+		}
+		updated[workflow] = true
+		if _, err = githubactions.UpdateReference(workflow, vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, newRef, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
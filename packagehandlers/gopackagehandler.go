@@ -1,10 +1,16 @@
 package packagehandlers
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
 	"github.com/jfrog/frogbot/v2/utils"
 	golangutils "github.com/jfrog/jfrog-cli-artifactory/artifactory/commands/golang"
 )
 
+var goModTidyArgs = []string{"mod", "tidy"}
+
 type GoPackageHandler struct {
 	CommonPackageHandler
 }
@@ -16,6 +22,47 @@ func (golang *GoPackageHandler) UpdateDependency(vulnDetails *utils.Vulnerabilit
 			return err
 		}
 	}
+	// In a Go workspace, the dependency is updated in the go.mod of each module that requires it
+	modules, err := getRequiringGoModules(vulnDetails.ImpactedDependencyName)
+	if err != nil {
+		return err
+	}
+	if len(modules) > 0 {
+		for _, module := range modules {
+			if err = golang.updateModuleDependency(vulnDetails, module); err != nil {
+				return err
+			}
+		}
+		vulnDetails.UpdatedWorkspaces = modules
+		return nil
+	}
 	// In Golang, we can address every dependency as a direct dependency.
-	return golang.CommonPackageHandler.UpdateDependency(vulnDetails, vulnDetails.Technology.GetPackageInstallationCommand())
+	if err = golang.CommonPackageHandler.UpdateDependency(vulnDetails, vulnDetails.Technology.GetPackageInstallationCommand()); err != nil {
+		return err
+	}
+	// In a module of a Go workspace, 'go get' records the checksums in go.work.sum, so the go.sum of the module is tidied
+	goWorkPath, err := utils.FindGoWorkFile(".")
+	if err != nil || goWorkPath == "" {
+		return err
+	}
+	return goModTidy(vulnDetails)
+}
+
+// Updates the dependency in the go.mod of the module of the workspace, and tidies the module
+func (golang *GoPackageHandler) updateModuleDependency(vulnDetails *utils.VulnerabilityDetails, module string) (err error) {
+	restoreDir, err := utils.Chdir(filepath.FromSlash(module))
+	if err != nil {
+		return fmt.Errorf("failed to change directory to the Go module '%s': %s", module, err.Error())
+	}
+	defer func() {
+		err = errors.Join(err, restoreDir())
+	}()
+	if err = golang.CommonPackageHandler.UpdateDependency(vulnDetails, vulnDetails.Technology.GetPackageInstallationCommand()); err != nil {
+		return
+	}
+	return goModTidy(vulnDetails)
+}
+
+func goModTidy(vulnDetails *utils.VulnerabilityDetails) error {
+	return runPackageMangerCommand(vulnDetails.Technology.GetExecCommandName(), vulnDetails.Technology.String(), goModTidyArgs)
 }
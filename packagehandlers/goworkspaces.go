@@ -0,0 +1,61 @@
+package packagehandlers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"golang.org/x/mod/modfile"
+)
+
+const goDescriptorFile = "go.mod"
+
+// Returns the modules of the Go workspace in the current directory that require the dependency in their go.mod.
+// Returns nil if the current directory has no go.work file, or if the go.mod of the current directory requires the dependency, which is updated as in any other project.
+func getRequiringGoModules(dependencyName string) (modules []string, err error) {
+	workspaceModules, err := utils.GetGoWorkspaceModules(".")
+	if err != nil || len(workspaceModules) == 0 {
+		return
+	}
+	var requires bool
+	if requires, err = goModRequires(goDescriptorFile, dependencyName); err != nil || requires {
+		return
+	}
+	for _, module := range workspaceModules {
+		if module == utils.RootDir {
+			continue
+		}
+		if requires, err = goModRequires(filepath.Join(filepath.FromSlash(module), goDescriptorFile), dependencyName); err != nil {
+			return nil, err
+		}
+		if requires {
+			modules = append(modules, module)
+		}
+	}
+	return
+}
+
+// Returns true if the go.mod file requires the dependency, directly or indirectly. A missing go.mod file requires nothing.
+func goModRequires(goModPath, dependencyName string) (bool, error) {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read '%s': %s", goModPath, err.Error())
+	}
+	goMod, err := modfile.ParseLax(goModPath, content, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse '%s': %s", goModPath, err.Error())
+	}
+	for _, require := range goMod.Require {
+		// The dependency names are lowered by the package handlers
+		if strings.EqualFold(require.Mod.Path, dependencyName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
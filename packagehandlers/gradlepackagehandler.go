@@ -11,6 +11,7 @@ import (
 const (
 	groovyDescriptorFileSuffix    = "build.gradle"
 	kotlinDescriptorFileSuffix    = "build.gradle.kts"
+	versionCatalogFileSuffix      = ".versions.toml"
 	apostrophes                   = "[\\\"|\\']"
 	directMapRegexpEntry          = "\\s*%s\\s*[:|=]\\s*"
 	directStringWithVersionFormat = "%s:%s:%s"
@@ -20,7 +21,20 @@ const (
 // Example: group: "junit", name: "junit", version: "1.0.0" | group = "junit", name = "junit", version = "1.0.0"
 var directMapWithVersionRegexp = getMapRegexpEntry("group") + "," + getMapRegexpEntry("name") + "," + getMapRegexpEntry("version")
 
-var gradleDescriptorsSuffixes = []string{groovyDescriptorFileSuffix, kotlinDescriptorFileSuffix}
+var gradleDescriptorsSuffixes = []string{groovyDescriptorFileSuffix, kotlinDescriptorFileSuffix, versionCatalogFileSuffix}
+
+// Regexps for the version catalog files, such as gradle/libs.versions.toml
+var (
+	// Example: [libraries]
+	tomlTableHeaderRegexp = regexp.MustCompile(`^\s*\[\s*([\w.-]+)\s*\]`)
+	// Example: junit = { module = "junit:junit", version.ref = "junit" }
+	tomlKeyValueRegexp  = regexp.MustCompile(`^\s*["']?([\w.-]+)["']?\s*=\s*(.+)$`)
+	catalogModuleRegexp = regexp.MustCompile(`\bmodule\s*=\s*["']([^"']+)["']`)
+	catalogGroupRegexp  = regexp.MustCompile(`\bgroup\s*=\s*["']([^"']+)["']`)
+	catalogNameRegexp   = regexp.MustCompile(`(?:^|[{,\s])name\s*=\s*["']([^"']+)["']`)
+	// Example: version.ref = "junit" | version = { ref = "junit" }
+	catalogVersionRefRegexp = regexp.MustCompile(`\bversion(?:\.ref\s*=|\s*=\s*\{\s*ref\s*=)\s*["']([^"']+)["']`)
+)
 
 func getMapRegexpEntry(mapEntry string) string {
 	return fmt.Sprintf(directMapRegexpEntry, mapEntry) + apostrophes + "%s" + apostrophes
@@ -62,7 +76,11 @@ func (gph *GradlePackageHandler) updateDirectDependency(vulnDetails *utils.Vulne
 	isAnyDescriptorFileChanged := false
 	for _, descriptorFilePath := range descriptorFilesFullPaths {
 		var isFileChanged bool
-		isFileChanged, err = gph.fixVulnerabilityIfExists(descriptorFilePath, vulnDetails)
+		if strings.HasSuffix(descriptorFilePath, versionCatalogFileSuffix) {
+			isFileChanged, err = gph.fixVersionCatalogIfExists(descriptorFilePath, vulnDetails)
+		} else {
+			isFileChanged, err = gph.fixVulnerabilityIfExists(descriptorFilePath, vulnDetails)
+		}
 		if err != nil {
 			return
 		}
@@ -131,6 +149,86 @@ func (gph *GradlePackageHandler) fixVulnerabilityIfExists(descriptorFilePath str
 	return
 }
 
+// Fixes the vulnerable library in a version catalog file, if the library is declared in it.
+// A library declares its version inline, or refers to an entry of the [versions] table that is updated instead.
+// Bundles refer to the libraries by their aliases, so they use the fixed version as well.
+func (gph *GradlePackageHandler) fixVersionCatalogIfExists(catalogFilePath string, vulnDetails *utils.VulnerabilityDetails) (isFileChanged bool, err error) {
+	byteFileContent, err := os.ReadFile(catalogFilePath)
+	if err != nil {
+		err = fmt.Errorf("couldn't read file '%s': %s", catalogFilePath, err.Error())
+		return
+	}
+	depGroup, depName, err := getVulnerabilityGroupAndName(vulnDetails.ImpactedDependencyName)
+	if err != nil {
+		return
+	}
+	originalFile := string(byteFileContent)
+	// Fixing the libraries given in a string format. For Example: junit = "junit:junit:4.7"
+	directStringVulnerableRow := fmt.Sprintf(directStringWithVersionFormat, depGroup, depName, vulnDetails.ImpactedDependencyVersion)
+	directStringFixedRow := fmt.Sprintf(directStringWithVersionFormat, depGroup, depName, vulnDetails.SuggestedFixedVersion)
+	lines := strings.Split(strings.ReplaceAll(originalFile, directStringVulnerableRow, directStringFixedRow), "\n")
+
+	// Fixing the libraries given in a table format, and collecting the version entries they refer to.
+	// For Example: junit = { module = "junit:junit", version = "4.7" } | junit = { group = "junit", name = "junit", version.ref = "junit" }
+	versionRefs := map[string]bool{}
+	forEachTomlEntry(lines, func(table, _, value string, lineIndex int) {
+		if table != "libraries" || !isCatalogLibrary(value, depGroup, depName) {
+			return
+		}
+		if versionRef := catalogVersionRefRegexp.FindStringSubmatch(value); versionRef != nil {
+			versionRefs[versionRef[1]] = true
+			return
+		}
+		lines[lineIndex] = replaceQuotedVersion(lines[lineIndex], vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion)
+	})
+	// Fixing the version entries. Other libraries that refer to the same entry are upgraded as well, as they are usually released together.
+	// For Example: junit = "4.7" | junit = { strictly = "4.7" }
+	forEachTomlEntry(lines, func(table, key, _ string, lineIndex int) {
+		if table == "versions" && versionRefs[key] {
+			lines[lineIndex] = replaceQuotedVersion(lines[lineIndex], vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion)
+		}
+	})
+
+	fileContent := strings.Join(lines, "\n")
+	if fileContent == originalFile {
+		return
+	}
+	isFileChanged = true
+	err = writeUpdatedBuildFile(catalogFilePath, fileContent)
+	return
+}
+
+// Calls the handler with the table, the key and the value of each key/value line of a TOML file
+func forEachTomlEntry(lines []string, handler func(table, key, value string, lineIndex int)) {
+	table := ""
+	for i, line := range lines {
+		if header := tomlTableHeaderRegexp.FindStringSubmatch(line); header != nil {
+			table = header[1]
+			continue
+		}
+		if entry := tomlKeyValueRegexp.FindStringSubmatch(line); entry != nil {
+			handler(table, entry[1], entry[2], i)
+		}
+	}
+}
+
+// Returns true if the value of a [libraries] entry declares the given library, either by its module or by its group and name
+func isCatalogLibrary(value, depGroup, depName string) bool {
+	if module := catalogModuleRegexp.FindStringSubmatch(value); module != nil {
+		return module[1] == depGroup+":"+depName
+	}
+	group, name := catalogGroupRegexp.FindStringSubmatch(value), catalogNameRegexp.FindStringSubmatch(value)
+	return group != nil && name != nil && group[1] == depGroup && name[1] == depName
+}
+
+// Replaces the quoted version in the line, keeping its quotes
+func replaceQuotedVersion(line, impactedVersion, fixedVersion string) string {
+	for _, quote := range []string{"\"", "'"} {
+		line = strings.ReplaceAll(line, quote+impactedVersion+quote, quote+fixedVersion+quote)
+	}
+	return line
+}
+
 // Returns separated 'group' and 'name' for a given vulnerability name. In addition replaces every '.' char into '\\.' since the output will be used for a regexp
 func getVulnerabilityGroupAndName(impactedDependencyName string) (depGroup string, depName string, err error) {
 	seperatedImpactedDepName := strings.Split(impactedDependencyName, ":")
@@ -78,7 +78,8 @@ func (mp *mavenPlugin) collectMavenPlugins() []gavCoordinate {
 
 // fillDependenciesMap collects direct dependencies from the pomPath pom.xml file.
 // If the version of a dependency is set in another property section, it is added as its value in the map.
-func (mph *MavenPackageHandler) fillDependenciesMap(pomPath string) error {
+// outsideProject is true if the pom.xml is a parent of the project POMs that isn't part of the project, so the versions it declares are updated in it directly.
+func (mph *MavenPackageHandler) fillDependenciesMap(pomPath string, outsideProject bool) error {
 	contentBytes, err := os.ReadFile(filepath.Clean(pomPath))
 	if err != nil {
 		return errors.New("couldn't read pom.xml file: " + err.Error())
@@ -93,17 +94,27 @@ func (mph *MavenPackageHandler) fillDependenciesMap(pomPath string) error {
 		}
 		depName := fmt.Sprintf("%s:%s", dependency.GroupId, dependency.ArtifactId)
 		if _, exist := mph.pomDependencies[depName]; !exist {
-			mph.pomDependencies[depName] = pomDependencyDetails{foundInDependencyManagement: dependency.foundInDependencyManagement, currentVersion: dependency.Version}
+			details := pomDependencyDetails{foundInDependencyManagement: dependency.foundInDependencyManagement, currentVersion: dependency.Version}
+			if outsideProject {
+				details.declaringParentPom = pomPath
+			}
+			mph.pomDependencies[depName] = details
 		}
 		if strings.HasPrefix(dependency.Version, "${") {
 			trimmedVersion := strings.Trim(dependency.Version, "${}")
-			if !slices.Contains(mph.pomDependencies[depName].properties, trimmedVersion) {
-				mph.pomDependencies[depName] = pomDependencyDetails{
-					properties:                  append(mph.pomDependencies[depName].properties, trimmedVersion),
+			details := mph.pomDependencies[depName]
+			if !slices.Contains(details.properties, trimmedVersion) {
+				details = pomDependencyDetails{
+					properties:                  append(details.properties, trimmedVersion),
+					propertyPoms:                details.propertyPoms,
 					currentVersion:              dependency.Version,
 					foundInDependencyManagement: dependency.foundInDependencyManagement,
 				}
 			}
+			if !containsPath(details.propertyPoms, pomPath) {
+				details.propertyPoms = append(details.propertyPoms, pomPath)
+			}
+			mph.pomDependencies[depName] = details
 		}
 	}
 	return nil
@@ -126,9 +137,13 @@ type pomPath struct {
 }
 
 type pomDependencyDetails struct {
-	properties                  []string
+	properties []string
+	// propertyPoms holds the paths to the pom.xml files that reference the properties.
+	propertyPoms                []string
 	currentVersion              string
 	foundInDependencyManagement bool
+	// declaringParentPom holds the path to a parent pom.xml outside the project that declares the version of the dependency.
+	declaringParentPom string
 }
 
 func NewMavenPackageHandler(scanDetails *utils.ScanDetails) *MavenPackageHandler {
@@ -176,7 +191,17 @@ func (mph *MavenPackageHandler) UpdateDependency(vulnDetails *utils.Vulnerabilit
 		mph.pomDependencies = make(map[string]pomDependencyDetails)
 	}
 	for _, pp := range mph.pomPaths {
-		if err = mph.fillDependenciesMap(pp.PomPath); err != nil {
+		if err = mph.fillDependenciesMap(pp.PomPath, false); err != nil {
+			return err
+		}
+	}
+	// Get the dependencies that the parent poms outside the project declare or manage
+	parentPoms, err := mph.getParentPomsOutsideProject()
+	if err != nil {
+		return err
+	}
+	for _, parentPom := range parentPoms {
+		if err = mph.fillDependenciesMap(parentPom, true); err != nil {
 			return err
 		}
 	}
@@ -195,6 +220,10 @@ func (mph *MavenPackageHandler) UpdateDependency(vulnDetails *utils.Vulnerabilit
 	if len(depDetails.properties) > 0 {
 		return mph.updateProperties(&depDetails, vulnDetails.SuggestedFixedVersion)
 	}
+	if depDetails.declaringParentPom != "" {
+		log.Debug(fmt.Sprintf("Updating the version of %s in the parent pom '%s'", impactedDependency, depDetails.declaringParentPom))
+		return setPomDependencyVersion(depDetails.declaringParentPom, impactedDependency, vulnDetails.SuggestedFixedVersion)
+	}
 
 	return mph.updatePackageVersion(vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion, depDetails.foundInDependencyManagement)
 }
@@ -262,8 +291,15 @@ func (mph *MavenPackageHandler) updatePackageVersion(impactedPackage, fixedVersi
 }
 
 // Update properties that represent this package's version.
+// A property that is defined in a pom.xml outside the project, such as a parent pom in another directory, is updated in that pom.xml.
+// A property that is defined in a remote parent is overridden in the topmost local pom.xml.
+// The other properties are updated by Maven in the project poms.
 func (mph *MavenPackageHandler) updateProperties(depDetails *pomDependencyDetails, fixedVersion string) error {
-	for _, property := range depDetails.properties {
+	projectProperties, err := mph.updatePropertiesOutsideProject(depDetails, fixedVersion)
+	if err != nil {
+		return err
+	}
+	for _, property := range projectProperties {
 		updatePropertyArgs := []string{
 			"-U", "-B", "org.codehaus.mojo:versions-maven-plugin:set-property", "-Dproperty=" + property,
 			"-DnewVersion=" + fixedVersion, "-DgenerateBackupPoms=false",
@@ -277,3 +313,36 @@ func (mph *MavenPackageHandler) updateProperties(depDetails *pomDependencyDetail
 	}
 	return nil
 }
+
+// Updates the definitions of the properties that Maven can't update in the project poms, and returns the properties that are left for Maven to update
+func (mph *MavenPackageHandler) updatePropertiesOutsideProject(depDetails *pomDependencyDetails, fixedVersion string) (projectProperties []string, err error) {
+	updated := map[propertyDefinition]bool{}
+	for _, property := range depDetails.properties {
+		for _, pomPath := range depDetails.propertyPoms {
+			definition, e := resolvePropertyDefinition(pomPath, property)
+			if e != nil {
+				log.Debug(fmt.Sprintf("Couldn't resolve the definition of the %s property, so Maven updates it: %s", property, e.Error()))
+				definition = &propertyDefinition{property: property}
+			}
+			if definition.pomPath == "" || (mph.isProjectPom(definition.pomPath) && !definition.override) {
+				if !slices.Contains(projectProperties, definition.property) {
+					projectProperties = append(projectProperties, definition.property)
+				}
+				continue
+			}
+			if updated[*definition] {
+				continue
+			}
+			log.Debug(fmt.Sprintf("Setting the %s property in '%s'", definition.property, definition.pomPath))
+			if err = setPomProperty(definition.pomPath, definition.property, fixedVersion); err != nil {
+				return nil, fmt.Errorf("failed updating %s property: %s", definition.property, err.Error())
+			}
+			updated[*definition] = true
+		}
+		// The poms that reference the property are unknown
+		if len(depDetails.propertyPoms) == 0 {
+			projectProperties = append(projectProperties, property)
+		}
+	}
+	return
+}
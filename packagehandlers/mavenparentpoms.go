@@ -0,0 +1,350 @@
+package packagehandlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	pomFileName                = "pom.xml"
+	defaultParentRelativePath  = "../pom.xml"
+	pomPropertiesPath          = "project>properties"
+	pomDependencyPath          = "project>dependencies>dependency"
+	pomManagedDependencyPath   = "project>dependencyManagement>dependencies>dependency"
+	defaultPomIndentation      = "    "
+	maxPropertyReferencesDepth = 10
+)
+
+var propertyReferenceRegex = regexp.MustCompile(`^\$\{([^}]+)}$`)
+
+// An element of a POM, with the position of its text in the POM content
+type pomElement struct {
+	// The names of the element and its ancestors, joined by '>', such as 'project>parent>groupId'
+	path  string
+	start int
+	end   int
+	text  string
+}
+
+type pomFile struct {
+	path     string
+	content  []byte
+	elements []pomElement
+}
+
+func readPomFile(pomPath string) (*pomFile, error) {
+	content, err := os.ReadFile(filepath.Clean(pomPath))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read '%s': %s", pomPath, err.Error())
+	}
+	pom := &pomFile{path: pomPath, content: content}
+	if err = pom.parseElements(); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %s", pomPath, err.Error())
+	}
+	return pom, nil
+}
+
+// Collects the elements of the POM in the order of their end tags, so the children of an element precede it
+func (pf *pomFile) parseElements() error {
+	pf.elements = nil
+	decoder := xml.NewDecoder(bytes.NewReader(pf.content))
+	var names []string
+	var starts []int
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			names = append(names, element.Name.Local)
+			starts = append(starts, int(decoder.InputOffset()))
+		case xml.EndElement:
+			last := len(names) - 1
+			start := starts[last]
+			pf.elements = append(pf.elements, pomElement{path: strings.Join(names, ">"), start: start, end: offset, text: strings.TrimSpace(string(pf.content[start:offset]))})
+			names, starts = names[:last], starts[:last]
+		}
+	}
+}
+
+// Returns the first element of the POM in the path, or nil if the POM has no such element
+func (pf *pomFile) find(path string) *pomElement {
+	for i := range pf.elements {
+		if pf.elements[i].path == path {
+			return &pf.elements[i]
+		}
+	}
+	return nil
+}
+
+func (pf *pomFile) text(path string) string {
+	if element := pf.find(path); element != nil {
+		return element.text
+	}
+	return ""
+}
+
+func (pf *pomFile) groupId() string {
+	if groupId := pf.text("project>groupId"); groupId != "" {
+		return groupId
+	}
+	// The group ID is inherited from the parent
+	return pf.text("project>parent>groupId")
+}
+
+// Returns the path of the parent POM in the local file system, or an empty string if the POM has no parent or if the parent is resolved from a remote repository.
+// Like Maven, the parent is looked up in the relative path of the parent element, which defaults to the POM of the parent directory, if its coordinates match the parent.
+func (pf *pomFile) localParentPath() (string, error) {
+	if pf.find("project>parent") == nil {
+		return "", nil
+	}
+	relativePath := defaultParentRelativePath
+	if element := pf.find("project>parent>relativePath"); element != nil {
+		// An empty relative path disables the lookup of the parent in the local file system
+		if element.text == "" {
+			return "", nil
+		}
+		relativePath = element.text
+	}
+	parentPath := filepath.Join(filepath.Dir(pf.path), filepath.FromSlash(relativePath))
+	info, err := os.Stat(parentPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		parentPath = filepath.Join(parentPath, pomFileName)
+		if _, err = os.Stat(parentPath); errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+	}
+	parent, err := readPomFile(parentPath)
+	if err != nil {
+		return "", err
+	}
+	if parent.text("project>artifactId") != pf.text("project>parent>artifactId") || parent.groupId() != pf.text("project>parent>groupId") {
+		return "", nil
+	}
+	return parentPath, nil
+}
+
+// Returns the POM and its ancestors in the local file system, from the POM to its topmost local ancestor.
+// remoteParent is true if the topmost local ancestor has a parent that is resolved from a remote repository.
+func getLocalPomsChain(pomPath string) (chain []*pomFile, remoteParent bool, err error) {
+	visited := map[string]bool{}
+	for pomPath != "" {
+		if pomPath, err = filepath.Abs(pomPath); err != nil {
+			return
+		}
+		if visited[pomPath] {
+			return nil, false, fmt.Errorf("the parent of '%s' is one of its descendants", pomPath)
+		}
+		visited[pomPath] = true
+		var pom *pomFile
+		if pom, err = readPomFile(pomPath); err != nil {
+			return
+		}
+		chain = append(chain, pom)
+		if pomPath, err = pom.localParentPath(); err != nil {
+			return
+		}
+		remoteParent = pomPath == "" && pom.find("project>parent") != nil
+	}
+	return
+}
+
+// Returns the local ancestors of the project POMs that aren't POMs of the project, such as a parent POM in another directory of the repository.
+// These POMs may declare or manage the versions of the dependencies of the project.
+func (mph *MavenPackageHandler) getParentPomsOutsideProject() (parentPoms []string, err error) {
+	for _, pp := range mph.pomPaths {
+		var chain []*pomFile
+		if chain, _, err = getLocalPomsChain(pp.PomPath); err != nil {
+			return
+		}
+		for _, parent := range chain[1:] {
+			if !mph.isProjectPom(parent.path) && !containsPath(parentPoms, parent.path) {
+				parentPoms = append(parentPoms, parent.path)
+			}
+		}
+	}
+	return
+}
+
+func (mph *MavenPackageHandler) isProjectPom(pomPath string) bool {
+	for _, pp := range mph.pomPaths {
+		if samePath(pp.PomPath, pomPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// The POM that defines the value of a property that a POM references
+type propertyDefinition struct {
+	pomPath  string
+	property string
+	// True if the property is defined in a remote parent, so it's overridden in the topmost local POM
+	override bool
+}
+
+// Resolves the definition of the property that the POM references, from the POM and its ancestors.
+// If the value of the property is a reference to another property, the definition of the other property is resolved.
+func resolvePropertyDefinition(pomPath, property string) (*propertyDefinition, error) {
+	chain, remoteParent, err := getLocalPomsChain(pomPath)
+	if err != nil {
+		return nil, err
+	}
+	for depth := 0; depth < maxPropertyReferencesDepth; depth++ {
+		definingPom := findPropertyInChain(chain, property)
+		if definingPom == nil {
+			if !remoteParent {
+				return nil, fmt.Errorf("the %s property isn't defined in '%s' or in its parents", property, pomPath)
+			}
+			return &propertyDefinition{pomPath: chain[len(chain)-1].path, property: property, override: true}, nil
+		}
+		reference := propertyReferenceRegex.FindStringSubmatch(definingPom.text(pomPropertiesPath + ">" + property))
+		if reference == nil {
+			return &propertyDefinition{pomPath: definingPom.path, property: property}, nil
+		}
+		property = reference[1]
+	}
+	return nil, fmt.Errorf("the %s property references more than %d other properties", property, maxPropertyReferencesDepth)
+}
+
+// Returns the nearest POM of the chain that defines the property, or nil if none of them defines it
+func findPropertyInChain(chain []*pomFile, property string) *pomFile {
+	for _, pom := range chain {
+		if pom.find(pomPropertiesPath+">"+property) != nil {
+			return pom
+		}
+	}
+	return nil
+}
+
+// Sets the value of the property in the POM, and adds the property to the POM if it doesn't define it.
+// The POM is edited as text, to keep its formatting.
+func setPomProperty(pomPath, property, value string) error {
+	pom, err := readPomFile(pomPath)
+	if err != nil {
+		return err
+	}
+	if element := pom.find(pomPropertiesPath + ">" + property); element != nil {
+		return pom.replaceText(element, value)
+	}
+	if properties := pom.find(pomPropertiesPath); properties != nil {
+		return pom.insertBeforeEndTag(properties, fmt.Sprintf("<%s>%s</%s>", property, value, property))
+	}
+	project := pom.find("project")
+	if project == nil {
+		return fmt.Errorf("'%s' has no project element", pomPath)
+	}
+	return pom.insertBeforeEndTag(project, fmt.Sprintf("<properties>\n%s%s<%s>%s</%s>\n%s</properties>", defaultPomIndentation, defaultPomIndentation, property, value, property, defaultPomIndentation))
+}
+
+// Sets the version of the dependency in the dependencies and in the dependency management of the POM
+func setPomDependencyVersion(pomPath, dependencyName, version string) error {
+	pom, err := readPomFile(pomPath)
+	if err != nil {
+		return err
+	}
+	var versions []pomElement
+	for _, element := range pom.elements {
+		if element.path != pomDependencyPath && element.path != pomManagedDependencyPath {
+			continue
+		}
+		if pom.childText(&element, "groupId")+":"+pom.childText(&element, "artifactId") != dependencyName {
+			continue
+		}
+		if versionElement := pom.child(&element, "version"); versionElement != nil {
+			versions = append(versions, *versionElement)
+		}
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("couldn't find the version of %s in '%s'", dependencyName, pomPath)
+	}
+	// Replace from the end of the content, so the positions of the other versions remain valid
+	for i := len(versions) - 1; i >= 0; i-- {
+		pom.content = append(pom.content[:versions[i].start], append([]byte(version), pom.content[versions[i].end:]...)...)
+	}
+	return pom.write()
+}
+
+// Returns the child of the element, which precedes it in the elements of the POM
+func (pf *pomFile) child(parent *pomElement, name string) *pomElement {
+	for i := range pf.elements {
+		element := &pf.elements[i]
+		if element.path == parent.path+">"+name && element.start > parent.start && element.end < parent.end {
+			return element
+		}
+	}
+	return nil
+}
+
+func (pf *pomFile) childText(parent *pomElement, name string) string {
+	if element := pf.child(parent, name); element != nil {
+		return element.text
+	}
+	return ""
+}
+
+func (pf *pomFile) replaceText(element *pomElement, text string) error {
+	pf.content = append(pf.content[:element.start], append([]byte(text), pf.content[element.end:]...)...)
+	return pf.write()
+}
+
+// Inserts a line before the end tag of the element, with the indentation of a child of the element
+func (pf *pomFile) insertBeforeEndTag(element *pomElement, line string) error {
+	lineStart := bytes.LastIndexByte(pf.content[:element.end], '\n') + 1
+	endTagIndentation := string(pf.content[lineStart:element.end])
+	if strings.TrimSpace(endTagIndentation) != "" {
+		// The end tag isn't on a line of its own
+		lineStart, endTagIndentation = element.end, ""
+	}
+	indentation := endTagIndentation + defaultPomIndentation
+	if element.path != "project" && endTagIndentation != "" {
+		// The element is a child of the project, so its indentation is the indentation unit of the POM
+		indentation = endTagIndentation + endTagIndentation
+	}
+	insertion := indentation + strings.ReplaceAll(line, "\n", "\n"+endTagIndentation) + "\n"
+	pf.content = append(pf.content[:lineStart], append([]byte(insertion), pf.content[lineStart:]...)...)
+	return pf.write()
+}
+
+func (pf *pomFile) write() error {
+	if err := os.WriteFile(pf.path, pf.content, 0644); err != nil {
+		return fmt.Errorf("couldn't write '%s': %s", pf.path, err.Error())
+	}
+	return nil
+}
+
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if samePath(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func samePath(first, second string) bool {
+	firstAbs, err := filepath.Abs(first)
+	if err != nil {
+		return false
+	}
+	secondAbs, err := filepath.Abs(second)
+	return err == nil && firstAbs == secondAbs
+}
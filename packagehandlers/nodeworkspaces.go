@@ -0,0 +1,121 @@
+package packagehandlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const nodeDescriptorFile = "package.json"
+
+// A workspace of an npm or Yarn monorepo, whose package.json is updated instead of the package.json of the root
+type nodeWorkspace struct {
+	// The path of the workspace directory, relative to the root of the project
+	Path string
+	// The package name of the workspace, which Yarn commands select the workspace by
+	Name string
+}
+
+type nodeDescriptor struct {
+	Name                 string            `json:"name"`
+	Workspaces           json.RawMessage   `json:"workspaces"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+func readNodeDescriptor(path string) (*nodeDescriptor, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %s", path, err.Error())
+	}
+	descriptor := &nodeDescriptor{}
+	if err = json.Unmarshal(content, descriptor); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %s", path, err.Error())
+	}
+	return descriptor, nil
+}
+
+func (nd *nodeDescriptor) declares(dependencyName string) bool {
+	for _, dependencies := range []map[string]string{nd.Dependencies, nd.DevDependencies, nd.OptionalDependencies, nd.PeerDependencies} {
+		if _, exists := dependencies[dependencyName]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// The workspaces are listed as an array of glob patterns, or in the 'packages' field of an object in Yarn V1
+func (nd *nodeDescriptor) workspacePatterns() (patterns []string) {
+	if len(nd.Workspaces) == 0 {
+		return
+	}
+	if err := json.Unmarshal(nd.Workspaces, &patterns); err == nil {
+		return
+	}
+	var workspacesConfig struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(nd.Workspaces, &workspacesConfig); err != nil {
+		log.Debug(fmt.Sprintf("Failed to parse the workspaces of the %s file: %s", nodeDescriptorFile, err.Error()))
+		return
+	}
+	return workspacesConfig.Packages
+}
+
+// Returns the workspaces of the npm or Yarn project in the current directory that declare the dependency in their package.json.
+// Returns nil if the project has no workspaces, or if the root package.json declares the dependency, which is updated as in any other project.
+func getDeclaringWorkspaces(dependencyName string) (workspaces []nodeWorkspace, err error) {
+	if _, e := os.Stat(nodeDescriptorFile); e != nil {
+		return
+	}
+	root, err := readNodeDescriptor(nodeDescriptorFile)
+	if err != nil || root.declares(dependencyName) {
+		return
+	}
+	visited := map[string]bool{}
+	for _, pattern := range root.workspacePatterns() {
+		// Negated patterns exclude directories that other patterns match, and Frogbot only updates the workspaces that declare the dependency
+		if strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		var dirs []string
+		if dirs, err = filepath.Glob(filepath.FromSlash(pattern)); err != nil {
+			return nil, fmt.Errorf("invalid workspaces pattern '%s': %s", pattern, err.Error())
+		}
+		for _, dir := range dirs {
+			descriptorPath := filepath.Join(dir, nodeDescriptorFile)
+			if visited[dir] || strings.Contains(dir, "node_modules") {
+				continue
+			}
+			visited[dir] = true
+			if _, e := os.Stat(descriptorPath); e != nil {
+				continue
+			}
+			var descriptor *nodeDescriptor
+			if descriptor, err = readNodeDescriptor(descriptorPath); err != nil {
+				return nil, err
+			}
+			if descriptor.declares(dependencyName) {
+				workspaces = append(workspaces, nodeWorkspace{Path: filepath.ToSlash(dir), Name: descriptor.Name})
+			}
+		}
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].Path < workspaces[j].Path
+	})
+	return
+}
+
+func getWorkspacePaths(workspaces []nodeWorkspace) (paths []string) {
+	for _, workspace := range workspaces {
+		paths = append(paths, workspace.Path)
+	}
+	return
+}
@@ -11,6 +11,7 @@ import (
 const (
 	npmInstallPackageLockOnlyFlag = "--package-lock-only"
 	npmInstallIgnoreScriptsFlag   = "--ignore-scripts"
+	npmWorkspaceFlag              = "--workspace="
 )
 
 type NpmPackageHandler struct {
@@ -42,6 +43,15 @@ func (npm *NpmPackageHandler) updateDirectDependency(vulnDetails *utils.Vulnerab
 		commandFlags = append(commandFlags, npmInstallPackageLockOnlyFlag)
 	}
 
+	// In a monorepo, the dependency is updated in the package.json of the workspaces that declare it, and the lockfile of the root is regenerated
+	workspaces, err := getDeclaringWorkspaces(vulnDetails.ImpactedDependencyName)
+	if err != nil {
+		return
+	}
+	for _, workspace := range workspaces {
+		commandFlags = append(commandFlags, npmWorkspaceFlag+workspace.Path)
+	}
+
 	// Configure resolution from an Artifactory server if needed
 	if npm.depsRepo != "" {
 		var clearResolutionServerFunc func() error
@@ -53,5 +63,9 @@ func (npm *NpmPackageHandler) updateDirectDependency(vulnDetails *utils.Vulnerab
 			err = errors.Join(err, clearResolutionServerFunc())
 		}()
 	}
-	return npm.CommonPackageHandler.UpdateDependency(vulnDetails, vulnDetails.Technology.GetPackageInstallationCommand(), commandFlags...)
+	if err = npm.CommonPackageHandler.UpdateDependency(vulnDetails, vulnDetails.Technology.GetPackageInstallationCommand(), commandFlags...); err != nil {
+		return
+	}
+	vulnDetails.UpdatedWorkspaces = getWorkspacePaths(workspaces)
+	return
 }
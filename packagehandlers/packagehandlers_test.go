@@ -1,6 +1,7 @@
 package packagehandlers
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,11 +13,17 @@ import (
 	"github.com/jfrog/build-info-go/tests"
 	biutils "github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/bazel"
+	"github.com/jfrog/frogbot/v2/utils/composer"
+	"github.com/jfrog/frogbot/v2/utils/githubactions"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca/java"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type dependencyFixTest struct {
@@ -687,6 +694,61 @@ func TestUpdatePropertiesVersion(t *testing.T) {
 	assert.Contains(t, string(modifiedPom), "2.39.9")
 }
 
+func TestUpdateDependencyInParentPoms(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fileutils.RemoveTempDir(tmpDir))
+	}()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "packagehandlers", "maven-parent-poms"), tmpDir, true, nil))
+	appPom, modulePom, parentPom := filepath.Join(tmpDir, "app", "pom.xml"), filepath.Join(tmpDir, "app", "module1", "pom.xml"), filepath.Join(tmpDir, "parent", "pom.xml")
+	// The project is the app directory, whose parent is in a sibling directory
+	mvnHandler := &MavenPackageHandler{MavenDepTreeManager: &java.MavenDepTreeManager{}, pomPaths: []pomPath{{PomPath: appPom}, {PomPath: modulePom}}}
+
+	parentPoms, err := mvnHandler.getParentPomsOutsideProject()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{parentPom}, parentPoms)
+
+	// The property chain is resolved to the property that the parent defines
+	definition, err := resolvePropertyDefinition(modulePom, "jackson.version")
+	assert.NoError(t, err)
+	assert.Equal(t, &propertyDefinition{pomPath: parentPom, property: "jackson.base.version"}, definition)
+	definition, err = resolvePropertyDefinition(modulePom, "guava.version")
+	assert.NoError(t, err)
+	assert.Equal(t, &propertyDefinition{pomPath: appPom, property: "guava.version"}, definition)
+	// The property is defined in the remote parent, so it's overridden in the topmost local pom
+	definition, err = resolvePropertyDefinition(modulePom, "snakeyaml.version")
+	assert.NoError(t, err)
+	assert.Equal(t, &propertyDefinition{pomPath: parentPom, property: "snakeyaml.version", override: true}, definition)
+
+	for _, vulnDetails := range []*utils.VulnerabilityDetails{
+		{SuggestedFixedVersion: "2.13.4", VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "com.fasterxml.jackson.core:jackson-databind"}}},
+		{SuggestedFixedVersion: "1.33", VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "org.yaml:snakeyaml"}}},
+		{SuggestedFixedVersion: "2.7", VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "commons-io:commons-io"}}},
+	} {
+		assert.NoError(t, mvnHandler.UpdateDependency(vulnDetails))
+	}
+	modifiedParentPom, err := os.ReadFile(parentPom)
+	assert.NoError(t, err)
+	assert.Contains(t, string(modifiedParentPom), "<jackson.base.version>2.13.4</jackson.base.version>\n        <jackson.version>${jackson.base.version}</jackson.version>")
+	assert.Contains(t, string(modifiedParentPom), "<jackson.version>${jackson.base.version}</jackson.version>\n        <snakeyaml.version>1.33</snakeyaml.version>\n    </properties>")
+	assert.Contains(t, string(modifiedParentPom), "<artifactId>commons-io</artifactId>\n                <version>2.7</version>")
+	modifiedModulePom, err := os.ReadFile(modulePom)
+	assert.NoError(t, err)
+	assert.Contains(t, string(modifiedModulePom), "<version>${jackson.version}</version>")
+}
+
+func TestSetPomProperty(t *testing.T) {
+	pomPath := filepath.Join(t.TempDir(), "pom.xml")
+	assert.NoError(t, os.WriteFile(pomPath, []byte("<project>\n    <artifactId>app</artifactId>\n</project>\n"), 0644))
+	// The properties element is added to a pom without properties
+	assert.NoError(t, setPomProperty(pomPath, "guava.version", "32.0.0-jre"))
+	assert.NoError(t, setPomProperty(pomPath, "guava.version", "32.1.0-jre"))
+	content, err := os.ReadFile(pomPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "<project>\n    <artifactId>app</artifactId>\n    <properties>\n        <guava.version>32.1.0-jre</guava.version>\n    </properties>\n</project>\n", string(content))
+}
+
 func getTestDataDir(t *testing.T, directDependency bool) string {
 	var projectDir string
 	if directDependency {
@@ -896,6 +958,39 @@ func TestGradleFixVulnerabilityIfExists(t *testing.T) {
 
 }
 
+func TestGradleFixVersionCatalogIfExists(t *testing.T) {
+	newVulnerabilityDetails := func(name, version, fixedVersion string) *utils.VulnerabilityDetails {
+		return &utils.VulnerabilityDetails{
+			SuggestedFixedVersion:       fixedVersion,
+			IsDirectDependency:          true,
+			VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{Technology: techutils.Gradle, ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: name, ImpactedDependencyVersion: version}},
+		}
+	}
+	cleanup := createTempDirAndChdir(t, filepath.Join("..", "testdata", "projects"), "gradle-version-catalog")
+	defer cleanup()
+	gph := GradlePackageHandler{}
+
+	// The version is declared in a string format, inline, in a version entry, and in a rich version entry
+	for _, vulnDetails := range []*utils.VulnerabilityDetails{
+		newVulnerabilityDetails("commons-collections:commons-collections", "3.2.1", "3.2.2"),
+		newVulnerabilityDetails("com.google.guava:guava", "30.0-jre", "32.0.0-jre"),
+		newVulnerabilityDetails("org.apache.logging.log4j:log4j-core", "2.14.1", "2.17.1"),
+		newVulnerabilityDetails("junit:junit", "4.7", "4.13.1"),
+	} {
+		assert.NoError(t, gph.UpdateDependency(vulnDetails))
+	}
+	catalogContent, err := os.ReadFile(filepath.Join("gradle", "libs.versions.toml"))
+	assert.NoError(t, err)
+	expectedContent, err := os.ReadFile(filepath.Join("gradle", "fixedLibsVersionsTomlForCompare.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expectedContent), string(catalogContent))
+
+	// A library that isn't declared with the vulnerable version isn't fixed
+	isFileChanged, err := gph.fixVersionCatalogIfExists(filepath.Join("gradle", "libs.versions.toml"), newVulnerabilityDetails("org.mockito:mockito-core", "4.8", "4.11.0"))
+	assert.NoError(t, err)
+	assert.False(t, isFileChanged)
+}
+
 func compareFixedFileToComparisonFile(t *testing.T, descriptorFileAbsPath string) {
 	var compareFilePath string
 	if strings.HasSuffix(descriptorFileAbsPath, groovyDescriptorFileSuffix) {
@@ -1044,7 +1139,454 @@ func TestPnpmFixVulnerabilityIfExists(t *testing.T) {
 	assert.NotContains(t, fixedFileContentString, "\"minimist\": \"1.2.5\"")
 	assert.Contains(t, fixedFileContentString, "\"minimist\": \"1.2.6\"")
 
+	// The project has no pnpm lock file, so only the package.json file is updated
+	lockFileExists, err := fileutils.IsFileExists(filepath.Join(tmpDir, pnpmLockFile), false)
+	assert.NoError(t, err)
+	assert.False(t, lockFileExists)
 	nodeModulesExist, err := fileutils.IsDirExists(filepath.Join(tmpDir, "node_modules"), false)
 	assert.NoError(t, err)
 	assert.False(t, nodeModulesExist)
 }
+
+func TestIsPnpmLockFileExists(t *testing.T) {
+	rootDir := t.TempDir()
+	workspacePackageDir := filepath.Join(rootDir, "packages", "app")
+	assert.NoError(t, os.MkdirAll(workspacePackageDir, 0755))
+
+	exists, err := isPnpmLockFileExists(workspacePackageDir, rootDir)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// The lock file of a pnpm workspace is located in the workspace root
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, pnpmLockFile), []byte("lockfileVersion: '9.0'\n"), 0644))
+	exists, err = isPnpmLockFileExists(workspacePackageDir, rootDir)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// Lock files outside the project aren't used
+	exists, err = isPnpmLockFileExists(workspacePackageDir, filepath.Join(rootDir, "packages"))
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+type registeredTestPackageHandler struct {
+	CommonPackageHandler
+}
+
+func (rph *registeredTestPackageHandler) UpdateDependency(_ *utils.VulnerabilityDetails) error {
+	return nil
+}
+
+func TestGetCompatiblePackageHandlerOfPlugins(t *testing.T) {
+	scanDetails := (&utils.ScanDetails{Project: &utils.Project{DepsRepo: "deps-remote"}}).SetPackageHandlerPlugins(map[string]string{"Conan": "/opt/frogbot/fix-conan"})
+	conanVulnerability := &utils.VulnerabilityDetails{VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{Technology: techutils.Conan}}
+	npmVulnerability := &utils.VulnerabilityDetails{VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{Technology: techutils.Npm}}
+
+	// The plugins of the configuration are used instead of the built-in handlers
+	assert.Equal(t, &ExecPackageHandler{Path: "/opt/frogbot/fix-conan", CommonPackageHandler: CommonPackageHandler{depsRepo: "deps-remote"}}, GetCompatiblePackageHandler(conanVulnerability, scanDetails))
+	assert.IsType(t, &NpmPackageHandler{}, GetCompatiblePackageHandler(npmVulnerability, scanDetails))
+
+	// The registered handlers are consulted first
+	Register("CONAN", func(_ *utils.VulnerabilityDetails, _ *utils.ScanDetails) PackageHandler {
+		return &registeredTestPackageHandler{}
+	})
+	defer Unregister("conan")
+	assert.Equal(t, &registeredTestPackageHandler{CommonPackageHandler: CommonPackageHandler{depsRepo: "deps-remote"}}, GetCompatiblePackageHandler(conanVulnerability, scanDetails))
+	assert.IsType(t, &NpmPackageHandler{}, GetCompatiblePackageHandler(npmVulnerability, scanDetails))
+}
+
+func TestExecPackageHandler(t *testing.T) {
+	if coreutils.IsWindows() {
+		t.Skip("The test plugins are shell scripts")
+	}
+	vulnDetails := &utils.VulnerabilityDetails{
+		SuggestedFixedVersion: "1.2.13",
+		IsDirectDependency:    true,
+		Cves:                  []string{"CVE-2022-37434"},
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			Technology:                techutils.Conan,
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "zlib", ImpactedDependencyVersion: "1.2.11"},
+		},
+	}
+	testCases := []struct {
+		name          string
+		script        string
+		expectedError error
+	}{
+		{name: "Fixed", script: "cat > request.json"},
+		{name: "Unsupported fix", script: "echo 'zlib is vendored'\nexit 3", expectedError: &utils.ErrUnsupportedFix{PackageName: "zlib", FixedVersion: "1.2.13", ErrorType: utils.UnsupportedByPackageHandlerPlugin}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			pluginPath := filepath.Join(tmpDir, "plugin.sh")
+			require.NoError(t, os.WriteFile(pluginPath, []byte("#!/bin/sh\n"+tc.script+"\n"), 0755))
+			currDir, err := os.Getwd()
+			require.NoError(t, err)
+			require.NoError(t, os.Chdir(tmpDir))
+			defer func() {
+				assert.NoError(t, os.Chdir(currDir))
+			}()
+
+			handler := &ExecPackageHandler{Path: pluginPath}
+			handler.SetCommonParams(nil, "deps-remote")
+			err = handler.UpdateDependency(vulnDetails)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+				return
+			}
+			require.NoError(t, err)
+			// The plugin runs in the working directory of the project, and receives the fix request on its standard input
+			request, err := os.ReadFile(filepath.Join(tmpDir, "request.json"))
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"technology":"conan","packageName":"zlib","currentVersion":"1.2.11","fixedVersion":"1.2.13","isDirectDependency":true,"cves":["CVE-2022-37434"],"depsRepo":"deps-remote"}`, string(request))
+		})
+	}
+
+	t.Run("Failure", func(t *testing.T) {
+		pluginPath := filepath.Join(t.TempDir(), "plugin.sh")
+		require.NoError(t, os.WriteFile(pluginPath, []byte("#!/bin/sh\necho 'registry is unreachable'\nexit 1\n"), 0755))
+		err := (&ExecPackageHandler{Path: pluginPath}).UpdateDependency(vulnDetails)
+		assert.ErrorContains(t, err, "the package handler plugin '"+pluginPath+"' failed")
+		assert.ErrorContains(t, err, "registry is unreachable")
+	})
+}
+
+func TestDockerPackageHandler(t *testing.T) {
+	tmpDir := t.TempDir()
+	dockerfile := "FROM --platform=linux/amd64 nginx:1.19@sha256:0123456789abcdef AS web\nFROM nginx:1.19-alpine\nRUN echo nginx:1.19\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "web"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "web", "Dockerfile"), []byte(dockerfile), 0644))
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+	vulnDetails := &utils.VulnerabilityDetails{
+		SuggestedFixedVersion: "1.21.0",
+		IsDirectDependency:    true,
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			Technology:                techutils.Docker,
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "nginx", ImpactedDependencyVersion: "1.19"},
+		},
+	}
+	handler := GetCompatiblePackageHandler(vulnDetails, &utils.ScanDetails{Project: &utils.Project{}})
+	assert.IsType(t, &DockerPackageHandler{}, handler)
+	require.NoError(t, handler.UpdateDependency(vulnDetails))
+	content, err := os.ReadFile(filepath.Join(tmpDir, "web", "Dockerfile"))
+	require.NoError(t, err)
+	// Only the image of the vulnerable tag is bumped, and its digest is removed
+	assert.Equal(t, "FROM --platform=linux/amd64 nginx:1.21.0 AS web\nFROM nginx:1.19-alpine\nRUN echo nginx:1.19\n", string(content))
+
+	vulnDetails.ImpactedDependencyName = "httpd"
+	assert.ErrorContains(t, handler.UpdateDependency(vulnDetails), "the base image 'httpd:1.19' was not found in the Dockerfiles of the project")
+}
+
+func TestBazelPackageHandler(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "projects", "bazel"), tmpDir, true, nil))
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+	vulnDetails := &utils.VulnerabilityDetails{
+		SuggestedFixedVersion: "32.0.0-jre",
+		IsDirectDependency:    true,
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			Technology: bazel.Technology,
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				ImpactedDependencyName:    "com.google.guava:guava",
+				ImpactedDependencyVersion: "31.1-jre",
+				ImpactedDependencyType:    techutils.Maven.ToFormal(),
+			},
+		},
+	}
+	// The variables that precede the repin command are set in its environment
+	handler := GetCompatiblePackageHandler(vulnDetails, &utils.ScanDetails{Project: &utils.Project{BazelRepinCommand: "REPIN=1 touch repinned"}})
+	assert.IsType(t, &BazelPackageHandler{}, handler)
+	require.NoError(t, handler.UpdateDependency(vulnDetails))
+	content, err := os.ReadFile(bazel.ModuleFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"com.google.guava:guava:32.0.0-jre",`)
+	assert.Contains(t, string(content), `"org.yaml:snakeyaml:1.33",`)
+	assert.FileExists(t, "repinned")
+
+	// The artifact isn't pinned by the workspace
+	assert.ErrorContains(t, handler.UpdateDependency(vulnDetails), "the Maven artifact com.google.guava:guava:31.1-jre was not found")
+
+	// The Go module isn't declared by a go_repository rule, and the workspace has no go.mod file
+	vulnDetails.ImpactedDependencyType, vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion = techutils.Go.ToFormal(), "golang.org/x/net", "v0.17.0"
+	assert.ErrorContains(t, handler.UpdateDependency(vulnDetails), "the Go module golang.org/x/net was not found")
+
+	// The transitive dependencies are resolved when repinning
+	vulnDetails.IsDirectDependency = false
+	var unsupportedFixErr *utils.ErrUnsupportedFix
+	assert.ErrorAs(t, handler.UpdateDependency(vulnDetails), &unsupportedFixErr)
+}
+
+func TestComposerPackageHandler(t *testing.T) {
+	if coreutils.IsWindows() {
+		t.Skip("The test composer executable is a shell script")
+	}
+	tmpDir := t.TempDir()
+	require.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "projects", "composer"), tmpDir, true, nil))
+	// The test composer executable records its arguments, its working directory and the Composer home directory
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "composer"), []byte("#!/bin/sh\necho \"$@\" > composer-args\ncat \"$COMPOSER_HOME/config.json\" > composer-config\necho \"$COMPOSER_AUTH\" > composer-auth\n"), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+	vulnDetails := &utils.VulnerabilityDetails{
+		SuggestedFixedVersion: "7.4.5",
+		IsDirectDependency:    true,
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			Technology: composer.Technology,
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				ImpactedDependencyName:    "guzzlehttp/guzzle",
+				ImpactedDependencyVersion: "7.4.0",
+			},
+		},
+	}
+	// The packages are resolved from the Composer repository of Artifactory
+	handler := GetCompatiblePackageHandler(vulnDetails, &utils.ScanDetails{
+		Project:       &utils.Project{DepsRepo: "composer-remote"},
+		ServerDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", AccessToken: "token"},
+	})
+	assert.IsType(t, &ComposerPackageHandler{}, handler)
+	require.NoError(t, handler.UpdateDependency(vulnDetails))
+	content, err := os.ReadFile(composer.DescriptorFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"guzzlehttp/guzzle": "^7.4.5",`)
+	assert.Contains(t, string(content), `"symfony/http-kernel": "^6.4"`)
+	args, err := os.ReadFile("composer-args")
+	require.NoError(t, err)
+	// The project has no vendor directory, so only the lockfile is regenerated
+	assert.Equal(t, "update guzzlehttp/guzzle --with-dependencies --no-interaction --no-scripts --no-install\n", string(args))
+	composerConfig, err := os.ReadFile("composer-config")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"repositories":{"artifactory":{"type":"composer","url":"https://acme.jfrog.io/artifactory/api/composer/composer-remote"},"packagist.org":false}}`, string(composerConfig))
+	auth, err := os.ReadFile("composer-auth")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bearer":{"acme.jfrog.io":"token"}}`, string(auth))
+
+	// The constraint already allows the fixed version
+	assert.ErrorContains(t, handler.UpdateDependency(vulnDetails), "the Composer package guzzlehttp/guzzle was not found in the composer.json files of the project")
+
+	// The versions of the transitive packages are resolved by the constraints of the packages that require them
+	vulnDetails.IsDirectDependency = false
+	var unsupportedFixErr *utils.ErrUnsupportedFix
+	assert.ErrorAs(t, handler.UpdateDependency(vulnDetails), &unsupportedFixErr)
+}
+
+type testCommitShaFeed map[string]string
+
+func (tf testCommitShaFeed) GetAdvisories(string) ([]githubactions.Advisory, error) {
+	return nil, nil
+}
+
+func (tf testCommitShaFeed) GetCommitSha(repository, ref string) (string, error) {
+	if sha, exists := tf[repository+"@"+ref]; exists {
+		return sha, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestGitHubActionsPackageHandler(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGoSha, fixedSetupGoSha := "0c52d547c9bc32b1aa3301fd7a9cb496313a4491", "cdcb36043654635271a94b9a6d1392de5bb323a7"
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "build.yml"), []byte("steps:\n  - uses: tj-actions/changed-files@v45.0.7\n  - uses: actions/setup-go@"+setupGoSha+" # v5.0.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "release.yaml"), []byte("steps:\n  - uses: tj-actions/changed-files@v45.0.7\n  - uses: actions/checkout@v4\n"), 0644))
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+	vulnDetails := &utils.VulnerabilityDetails{
+		SuggestedFixedVersion: "v46.0.1",
+		IsDirectDependency:    true,
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			Technology:                githubactions.Technology,
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "tj-actions/changed-files", ImpactedDependencyVersion: "v45.0.7"},
+		},
+	}
+	handler := GetCompatiblePackageHandler(vulnDetails, &utils.ScanDetails{Project: &utils.Project{}})
+	assert.IsType(t, &GitHubActionsPackageHandler{}, handler)
+	actionsHandler := handler.(*GitHubActionsPackageHandler)
+	actionsHandler.feed = testCommitShaFeed{"actions/setup-go@v5.0.1": fixedSetupGoSha}
+
+	// The action is bumped in all the workflows
+	require.NoError(t, handler.UpdateDependency(vulnDetails))
+	// An action that is pinned to a commit SHA stays pinned
+	vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion = "actions/setup-go", "v5.0.0", "v5.0.1"
+	require.NoError(t, handler.UpdateDependency(vulnDetails))
+	// A mutable reference is pinned to the suggested commit SHA
+	vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion = "actions/checkout", "v4", "b4ffde65f46336ab88eb53be808477a3936bae11"
+	require.NoError(t, handler.UpdateDependency(vulnDetails))
+
+	content, err := os.ReadFile(filepath.Join(workflowsDir, "build.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "steps:\n  - uses: tj-actions/changed-files@v46.0.1\n  - uses: actions/setup-go@"+fixedSetupGoSha+" # v5.0.1\n", string(content))
+	content, err = os.ReadFile(filepath.Join(workflowsDir, "release.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "steps:\n  - uses: tj-actions/changed-files@v46.0.1\n  - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4\n", string(content))
+
+	// The commit SHA of the fixed version can't be resolved
+	actionsHandler.pin = true
+	vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion = "tj-actions/changed-files", "v46.0.1", "v46.0.2"
+	assert.ErrorContains(t, handler.UpdateDependency(vulnDetails), "failed to resolve the commit SHA of 'tj-actions/changed-files@v46.0.2'")
+	vulnDetails.ImpactedDependencyVersion = "v44.0.0"
+	assert.ErrorContains(t, handler.UpdateDependency(vulnDetails), "the action 'tj-actions/changed-files@v44.0.0' was not found in the GitHub Actions workflows")
+}
+
+func TestGetDeclaringWorkspaces(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "projects", "npm-workspaces"), tmpDir, true, nil))
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+
+	testCases := []struct {
+		dependencyName     string
+		expectedWorkspaces []nodeWorkspace
+	}{
+		{dependencyName: "minimist", expectedWorkspaces: []nodeWorkspace{{Path: "packages/api", Name: "@frogbot/api"}, {Path: "packages/web", Name: "@frogbot/web"}}},
+		{dependencyName: "lodash", expectedWorkspaces: []nodeWorkspace{{Path: "packages/web", Name: "@frogbot/web"}}},
+		// The root package.json declares the dependency, so it's updated in the root
+		{dependencyName: "typescript"},
+		{dependencyName: "express"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.dependencyName, func(t *testing.T) {
+			workspaces, err := getDeclaringWorkspaces(tc.dependencyName)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedWorkspaces, workspaces)
+		})
+	}
+
+	// Yarn V1 lists the workspaces in the 'packages' field of an object
+	require.NoError(t, os.WriteFile(nodeDescriptorFile, []byte(`{"private": true, "workspaces": {"packages": ["packages/api"], "nohoist": ["**/react-native"]}}`), 0644))
+	workspaces, err := getDeclaringWorkspaces("minimist")
+	require.NoError(t, err)
+	assert.Equal(t, []nodeWorkspace{{Path: "packages/api", Name: "@frogbot/api"}}, workspaces)
+
+	// A project without workspaces
+	require.NoError(t, os.WriteFile(nodeDescriptorFile, []byte(`{"name": "single-package"}`), 0644))
+	workspaces, err = getDeclaringWorkspaces("minimist")
+	require.NoError(t, err)
+	assert.Empty(t, workspaces)
+}
+
+func TestGetRequiringGoModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "projects", "go-workspace"), tmpDir, true, nil))
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+
+	testCases := []struct {
+		dependencyName  string
+		expectedModules []string
+	}{
+		{dependencyName: "golang.org/x/net", expectedModules: []string{"services/api", "services/web"}},
+		{dependencyName: "github.com/gin-gonic/gin", expectedModules: []string{"services/web"}},
+		{dependencyName: "github.com/google/uuid", expectedModules: []string{"services/api"}},
+		{dependencyName: "github.com/sirupsen/logrus"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.dependencyName, func(t *testing.T) {
+			modules, err := getRequiringGoModules(tc.dependencyName)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedModules, modules)
+		})
+	}
+
+	// The go.mod of the root requires the dependency, so it's updated in the root
+	require.NoError(t, os.WriteFile(goDescriptorFile, []byte("module github.com/frogbot/go-workspace\n\ngo 1.22\n\nrequire golang.org/x/net v0.17.0\n"), 0644))
+	modules, err := getRequiringGoModules("golang.org/x/net")
+	require.NoError(t, err)
+	assert.Empty(t, modules)
+
+	// A project without a go.work file
+	require.NoError(t, os.Remove(utils.GoWorkFile))
+	modules, err = getRequiringGoModules("github.com/gin-gonic/gin")
+	require.NoError(t, err)
+	assert.Empty(t, modules)
+}
+
+func TestPipCompile(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "projects", "pip-tools"), tmpDir, true, nil))
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+
+	assert.Equal(t, "requirements.in", getPipCompileSourceFile("requirements.txt"))
+	assert.Empty(t, getPipCompileSourceFile("setup.py"))
+	assert.Empty(t, getPipCompileSourceFile("dev-requirements.txt"))
+
+	// The referenced constraints files and the constraints.txt file of the directory
+	assert.Equal(t, []string{filepath.Join("constraints", "base.txt"), "constraints.txt"}, getConstraintsFiles("requirements.in", "requirements.txt"))
+
+	// The requirements file was compiled with hashes, so it's compiled with hashes again
+	vulnDetails := &utils.VulnerabilityDetails{SuggestedFixedVersion: "1.26.18", VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{Technology: techutils.Pip, ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "urllib3"}}}
+	assert.Equal(t, []string{"--output-file", "requirements.txt", "--upgrade-package", "urllib3==1.26.18", "--generate-hashes", "requirements.in"}, getPipCompileArgs("requirements.in", "requirements.txt", vulnDetails))
+	require.NoError(t, os.WriteFile("plain.txt", []byte("urllib3==1.26.5\n    # via requests\n"), 0644))
+	assert.Equal(t, []string{"--output-file", "plain.txt", "--upgrade-package", "urllib3==1.26.18", "plain.in"}, getPipCompileArgs("plain.in", "plain.txt", vulnDetails))
+
+	// The constraints file that pins the package is updated
+	pinned, err := pinInConstraintsFiles(vulnDetails, "requirements.in")
+	require.NoError(t, err)
+	assert.True(t, pinned)
+	content, err := os.ReadFile(filepath.Join("constraints", "base.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "urllib3==1.26.18\n", string(content))
+	content, err = os.ReadFile("constraints.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "idna==3.4\n", string(content))
+}
+
+func TestPipConstraintsFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	currDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(currDir))
+	}()
+	require.NoError(t, os.WriteFile("requirements.txt", []byte("requests==2.31.0\n"), 0644))
+	require.NoError(t, os.WriteFile(defaultConstraintsFile, []byte("urllib3==1.26.5\n"), 0644))
+
+	// The package is pinned only in the constraints file, which is updated instead of the requirements file
+	handler := &PythonPackageHandler{pipRequirementsFile: "requirements.txt"}
+	vulnDetails := &utils.VulnerabilityDetails{SuggestedFixedVersion: "1.26.18", IsDirectDependency: true, VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{Technology: techutils.Pip, ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "urllib3"}}}
+	require.NoError(t, handler.UpdateDependency(vulnDetails))
+	content, err := os.ReadFile(defaultConstraintsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "urllib3==1.26.18\n", string(content))
+	content, err = os.ReadFile("requirements.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "requests==2.31.0\n", string(content))
+
+	// A package that no file pins isn't fixed
+	vulnDetails.ImpactedDependencyName = "idna"
+	assert.ErrorContains(t, handler.UpdateDependency(vulnDetails), "impacted package idna not found, fix failed")
+}
@@ -0,0 +1,111 @@
+package packagehandlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	pipCompileCommand      = "pip-compile"
+	pipCompileSourceExt    = ".in"
+	pipCompiledExt         = ".txt"
+	defaultConstraintsFile = "constraints.txt"
+	generateHashesFlag     = "--generate-hashes"
+	hashOption             = "--hash="
+)
+
+// Matches the references of requirements files to constraints files, such as '-c constraints.txt' and '--constraint=constraints.txt'
+var constraintsReferenceRegex = regexp.MustCompile(`(?m)^\s*(?:-c|--constraint)(?:\s+|=)(\S+)`)
+
+// Returns the pip-tools source file that the requirements file is compiled from, such as requirements.in of requirements.txt,
+// or an empty string if the requirements file isn't compiled by pip-compile
+func getPipCompileSourceFile(requirementsFile string) string {
+	if filepath.Ext(requirementsFile) != pipCompiledExt {
+		return ""
+	}
+	sourceFile := strings.TrimSuffix(requirementsFile, pipCompiledExt) + pipCompileSourceExt
+	if _, err := os.Stat(sourceFile); err != nil {
+		return ""
+	}
+	return sourceFile
+}
+
+// Updates the package in the pip-tools source file if it pins the package, and compiles the requirements file again with the fixed version.
+// The package is upgraded by pip-compile even if the source file doesn't pin it, so the indirect dependencies are fixed as well.
+func (py *PythonPackageHandler) handlePipCompile(vulnDetails *utils.VulnerabilityDetails, sourceFile string) (err error) {
+	log.Debug(fmt.Sprintf("The requirements file '%s' is compiled from '%s' by pip-compile", py.pipRequirementsFile, sourceFile))
+	if _, err = pinInConstraintsFiles(vulnDetails, sourceFile, py.pipRequirementsFile); err != nil {
+		return
+	}
+	if _, err = pinPythonRequirement(sourceFile, vulnDetails); err != nil {
+		return
+	}
+	return runPackageMangerCommand(pipCompileCommand, techutils.Pip.String(), getPipCompileArgs(sourceFile, py.pipRequirementsFile, vulnDetails))
+}
+
+func getPipCompileArgs(sourceFile, compiledFile string, vulnDetails *utils.VulnerabilityDetails) []string {
+	args := []string{"--output-file", compiledFile, "--upgrade-package", strings.ToLower(vulnDetails.ImpactedDependencyName) + "==" + vulnDetails.SuggestedFixedVersion}
+	if isCompiledWithHashes(compiledFile) {
+		args = append(args, generateHashesFlag)
+	}
+	return append(args, sourceFile)
+}
+
+// The compiled requirements file has hashes if it was compiled with --generate-hashes, which its header records
+func isCompiledWithHashes(compiledFile string) bool {
+	content, err := os.ReadFile(compiledFile)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), generateHashesFlag) || strings.Contains(string(content), hashOption)
+}
+
+// Returns the constraints files that the requirements files reference, and the constraints.txt file of the current directory if it exists.
+// The referenced paths are relative to the directories of the referencing files.
+func getConstraintsFiles(requirementsFiles ...string) (constraintsFiles []string) {
+	visited := map[string]bool{}
+	addConstraintsFile := func(constraintsFile string) {
+		constraintsFile = filepath.Clean(constraintsFile)
+		if visited[constraintsFile] {
+			return
+		}
+		visited[constraintsFile] = true
+		if _, err := os.Stat(constraintsFile); err == nil {
+			constraintsFiles = append(constraintsFiles, constraintsFile)
+		}
+	}
+	for _, requirementsFile := range requirementsFiles {
+		content, err := os.ReadFile(requirementsFile)
+		if err != nil {
+			continue
+		}
+		for _, match := range constraintsReferenceRegex.FindAllStringSubmatch(string(content), -1) {
+			addConstraintsFile(filepath.Join(filepath.Dir(requirementsFile), filepath.FromSlash(match[1])))
+		}
+	}
+	addConstraintsFile(defaultConstraintsFile)
+	return
+}
+
+// Pins the fixed version of the package in the constraints files of the requirements files that pin the package.
+// Returns true if one of the constraints files pins the package.
+func pinInConstraintsFiles(vulnDetails *utils.VulnerabilityDetails, requirementsFiles ...string) (pinned bool, err error) {
+	for _, constraintsFile := range getConstraintsFiles(requirementsFiles...) {
+		var filePinned bool
+		if filePinned, err = pinPythonRequirement(constraintsFile, vulnDetails); err != nil {
+			return
+		}
+		if filePinned {
+			log.Debug(fmt.Sprintf("The constraints file '%s' pins the package '%s', so it's updated as well", constraintsFile, vulnDetails.ImpactedDependencyName))
+		}
+		pinned = pinned || filePinned
+	}
+	return
+}
@@ -4,9 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jfrog/frogbot/v2/utils"
+	npmCommand "github.com/jfrog/jfrog-cli-artifactory/artifactory/commands/npm"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"os"
-	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -15,6 +15,9 @@ import (
 const (
 	pnpmDependencyRegexpPattern = "\\s*\"%s\"\\s*:\\s*\"[~|^]?%s\""
 	pnpmDescriptorFileSuffix    = "package.json"
+	pnpmLockFile                = "pnpm-lock.yaml"
+	pnpmInstallCommand          = "install"
+	pnpmLockfileOnlyFlag        = "--lockfile-only"
 	nodeModulesPathPattern      = ".*node_modules.*"
 )
 
@@ -63,6 +66,8 @@ func (pnpm *PnpmPackageHandler) updateDirectDependency(vulnDetails *utils.Vulner
 	return err
 }
 
+// Updates the version of the vulnerable dependency in the given package.json file, and refreshes the pnpm lock file of the project if it exists.
+// The lock file is refreshed without installing the dependencies, so no node_modules directory is created.
 func (pnpm *PnpmPackageHandler) fixVulnerabilityIfExists(vulnDetails *utils.VulnerabilityDetails, descriptorFilePath, originalWd string, vulnRegexpCompiler *regexp.Regexp) (isFileChanged bool, err error) {
 	var descriptorFileData []byte
 	descriptorFileData, err = os.ReadFile(descriptorFilePath)
@@ -72,32 +77,67 @@ func (pnpm *PnpmPackageHandler) fixVulnerabilityIfExists(vulnDetails *utils.Vuln
 	}
 
 	// Only if the vulnerable dependency is detected in the current descriptor, we initiate a fix
-	if match := vulnRegexpCompiler.FindString(strings.ToLower(string(descriptorFileData))); match != "" {
-		modulePath := path.Dir(descriptorFilePath)
-		if err = os.Chdir(modulePath); err != nil {
-			err = fmt.Errorf("failed to change directory to '%s': %s", modulePath, err.Error())
-			return isFileChanged, err
+	if match := vulnRegexpCompiler.FindString(strings.ToLower(string(descriptorFileData))); match == "" {
+		return isFileChanged, err
+	}
+	// The version range prefix (^ or ~) of the dependency is kept
+	caseInsensitiveRegexpCompiler := regexp.MustCompile("(?i)" + vulnRegexpCompiler.String())
+	fixedFileContent := caseInsensitiveRegexpCompiler.ReplaceAllStringFunc(string(descriptorFileData), func(dependencyEntry string) string {
+		versionIndex := strings.LastIndex(strings.ToLower(dependencyEntry), strings.ToLower(vulnDetails.ImpactedDependencyVersion))
+		if versionIndex == -1 {
+			return dependencyEntry
 		}
-		defer func() {
-			err = errors.Join(err, os.Chdir(originalWd))
-		}()
+		return dependencyEntry[:versionIndex] + vulnDetails.SuggestedFixedVersion + dependencyEntry[versionIndex+len(vulnDetails.ImpactedDependencyVersion):]
+	})
+	if err = writeUpdatedBuildFile(descriptorFilePath, fixedFileContent); err != nil {
+		return isFileChanged, err
+	}
+	isFileChanged = true
 
-		var nodeModulesDirExist bool
-		if nodeModulesDirExist, err = fileutils.IsDirExists(filepath.Join(modulePath, "node_modules"), false); err != nil {
-			return isFileChanged, err
-		}
+	modulePath := filepath.Dir(descriptorFilePath)
+	var lockFileExists bool
+	if lockFileExists, err = isPnpmLockFileExists(modulePath, originalWd); err != nil || !lockFileExists {
+		return isFileChanged, err
+	}
+	if err = os.Chdir(modulePath); err != nil {
+		err = fmt.Errorf("failed to change directory to '%s': %s", modulePath, err.Error())
+		return isFileChanged, err
+	}
+	defer func() {
+		err = errors.Join(err, os.Chdir(originalWd))
+	}()
+	if err = pnpm.refreshLockFile(); err != nil {
+		return isFileChanged, fmt.Errorf("failed to update dependency '%s' from version '%s' to '%s': %s", vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion, err.Error())
+	}
+	return isFileChanged, err
+}
 
-		if !nodeModulesDirExist {
-			defer func() {
-				// If node_modules directory doesn't exist prior to the dependency update we aim remove it after the update.
-				err = errors.Join(err, fileutils.RemoveTempDir(filepath.Join(modulePath, "node_modules")))
-			}()
+// Updates the pnpm lock file according to the package.json file in the current directory
+func (pnpm *PnpmPackageHandler) refreshLockFile() (err error) {
+	// Configure resolution from an Artifactory server if needed
+	if pnpm.depsRepo != "" {
+		var clearResolutionServerFunc func() error
+		clearResolutionServerFunc, err = npmCommand.SetArtifactoryAsResolutionServer(pnpm.serverDetails, pnpm.depsRepo)
+		if err != nil {
+			return
 		}
+		defer func() {
+			err = errors.Join(err, clearResolutionServerFunc())
+		}()
+	}
+	return runPackageMangerCommand("pnpm", "pnpm", []string{pnpmInstallCommand, pnpmLockfileOnlyFlag, npmInstallIgnoreScriptsFlag})
+}
 
-		if err = pnpm.CommonPackageHandler.UpdateDependency(vulnDetails, vulnDetails.Technology.GetPackageInstallationCommand()); err != nil {
-			return isFileChanged, fmt.Errorf("failed to update dependency '%s' from version '%s' to '%s': %s", vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion, err.Error())
+// Checks if a pnpm lock file exists in the module directory, or in one of its parent directories up to the root directory of the project.
+// The lock file of a pnpm workspace is located in the workspace root, and is shared by all the workspace packages.
+func isPnpmLockFileExists(modulePath, rootDir string) (bool, error) {
+	for dir := modulePath; ; dir = filepath.Dir(dir) {
+		exists, err := fileutils.IsFileExists(filepath.Join(dir, pnpmLockFile), false)
+		if err != nil || exists {
+			return exists, err
+		}
+		if relPath, err := filepath.Rel(rootDir, dir); err != nil || relPath == "." || strings.HasPrefix(relPath, "..") || dir == filepath.Dir(dir) {
+			return false, nil
 		}
-		isFileChanged = true
 	}
-	return isFileChanged, err
 }
@@ -30,6 +30,10 @@ func (py *PythonPackageHandler) UpdateDependency(vulnDetails *utils.Vulnerabilit
 	if vulnDetails.IsDirectDependency {
 		return py.updateDirectDependency(vulnDetails)
 	}
+	// pip-compile upgrades the indirect dependencies too, since the compiled requirements file pins all of them
+	if vulnDetails.Technology == techutils.Pip && getPipCompileSourceFile(py.pipRequirementsFile) != "" {
+		return py.handlePip(vulnDetails)
+	}
 
 	return &utils.ErrUnsupportedFix{
 		PackageName:  vulnDetails.ImpactedDependencyName,
@@ -61,9 +65,7 @@ func (py *PythonPackageHandler) handlePoetry(vulnDetails *utils.VulnerabilityDet
 }
 
 func (py *PythonPackageHandler) handlePip(vulnDetails *utils.VulnerabilityDetails) (err error) {
-	var fixedFile string
 	// This function assumes that the version of the dependencies is statically pinned in the requirements file or inside the 'install_requires' array in the setup.py file
-	fixedPackage := vulnDetails.ImpactedDependencyName + "==" + vulnDetails.SuggestedFixedVersion
 	if py.pipRequirementsFile == "" {
 		py.pipRequirementsFile = "setup.py"
 	}
@@ -75,23 +77,44 @@ func (py *PythonPackageHandler) handlePip(vulnDetails *utils.VulnerabilityDetail
 	if !strings.HasPrefix(filepath.Clean(fullPath), wd) {
 		return errors.New("wrong requirements file input")
 	}
-	data, err := os.ReadFile(filepath.Clean(py.pipRequirementsFile))
+	if sourceFile := getPipCompileSourceFile(py.pipRequirementsFile); sourceFile != "" {
+		return py.handlePipCompile(vulnDetails, sourceFile)
+	}
+	// The constraints files may pin the package as well, and a pin that isn't updated conflicts with the fixed version
+	constraintsFixed, err := pinInConstraintsFiles(vulnDetails, py.pipRequirementsFile)
+	if err != nil {
+		return
+	}
+	fixed, err := pinPythonRequirement(py.pipRequirementsFile, vulnDetails)
 	if err != nil {
-		return errors.New("an error occurred while attempting to read the requirements file:\n" + err.Error())
+		return
+	}
+	if !fixed && !constraintsFixed {
+		return fmt.Errorf("impacted package %s not found, fix failed", vulnDetails.ImpactedDependencyName)
+	}
+	return
+}
+
+// Replaces the version of the package in the requirements file with the fixed version, if the file pins the package.
+// Returns false if the file doesn't pin the package.
+func pinPythonRequirement(requirementsFile string, vulnDetails *utils.VulnerabilityDetails) (pinned bool, err error) {
+	data, err := os.ReadFile(filepath.Clean(requirementsFile))
+	if err != nil {
+		return false, errors.New("an error occurred while attempting to read the requirements file:\n" + err.Error())
 	}
 	currentFile := string(data)
 
 	// Check both original and lowered package name and replace to only one lowered result
 	// This regex will match the impactedPackage with it's pinned version e.py. PyJWT==1.7.1
 	re := regexp.MustCompile(PythonPackageRegexPrefix + "(" + vulnDetails.ImpactedDependencyName + "|" + strings.ToLower(vulnDetails.ImpactedDependencyName) + ")" + PythonPackageRegexSuffix)
-	if packageToReplace := re.FindString(currentFile); packageToReplace != "" {
-		fixedFile = strings.Replace(currentFile, packageToReplace, strings.ToLower(fixedPackage), 1)
+	packageToReplace := re.FindString(currentFile)
+	if packageToReplace == "" {
+		return false, nil
 	}
-	if fixedFile == "" {
-		return fmt.Errorf("impacted package %s not found, fix failed", vulnDetails.ImpactedDependencyName)
-	}
-	if err = os.WriteFile(py.pipRequirementsFile, []byte(fixedFile), 0600); err != nil {
-		err = fmt.Errorf("an error occured while writing the fixed version of %s to the requirements file:\n%s", vulnDetails.SuggestedFixedVersion, err.Error())
+	fixedPackage := vulnDetails.ImpactedDependencyName + "==" + vulnDetails.SuggestedFixedVersion
+	fixedFile := strings.Replace(currentFile, packageToReplace, strings.ToLower(fixedPackage), 1)
+	if err = os.WriteFile(requirementsFile, []byte(fixedFile), 0600); err != nil {
+		return false, fmt.Errorf("an error occured while writing the fixed version of %s to the requirements file:\n%s", vulnDetails.SuggestedFixedVersion, err.Error())
 	}
-	return
+	return true, nil
 }
@@ -0,0 +1,39 @@
+package packagehandlers
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jfrog/frogbot/v2/utils"
+)
+
+// HandlerFactory creates the package handler that fixes the vulnerabilities of a technology
+type HandlerFactory func(vulnDetails *utils.VulnerabilityDetails, details *utils.ScanDetails) PackageHandler
+
+var (
+	registeredHandlersMutex sync.RWMutex
+	registeredHandlers      = map[string]HandlerFactory{}
+)
+
+// Register adds a package handler for a technology, which overrides the built-in handler of the technology if there is one.
+// Builds of Frogbot that fix proprietary package managers register their handlers in the init functions of their packages.
+// The technology is matched case-insensitively against the technology of the fixed vulnerability, such as 'conan' or 'generic'.
+func Register(tech string, factory HandlerFactory) {
+	registeredHandlersMutex.Lock()
+	defer registeredHandlersMutex.Unlock()
+	registeredHandlers[strings.ToLower(tech)] = factory
+}
+
+// Unregister removes the package handler of a technology that was added by Register
+func Unregister(tech string) {
+	registeredHandlersMutex.Lock()
+	defer registeredHandlersMutex.Unlock()
+	delete(registeredHandlers, strings.ToLower(tech))
+}
+
+func getRegisteredHandler(tech string) (factory HandlerFactory, exists bool) {
+	registeredHandlersMutex.RLock()
+	defer registeredHandlersMutex.RUnlock()
+	factory, exists = registeredHandlers[strings.ToLower(tech)]
+	return
+}
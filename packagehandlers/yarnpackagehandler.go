@@ -8,6 +8,7 @@ import (
 	"github.com/jfrog/gofrog/version"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"strings"
 )
 
 const (
@@ -15,6 +16,7 @@ const (
 	yarnV1PackageUpdateCmd = "upgrade"
 	yarnV2PackageUpdateCmd = "up"
 	modulesFolderFlag      = "--modules-folder="
+	yarnWorkspaceCmd       = "workspace"
 )
 
 type YarnPackageHandler struct {
@@ -58,17 +60,43 @@ func (yarn *YarnPackageHandler) updateDirectDependency(vulnDetails *utils.Vulner
 	} else {
 		installationCommand = yarnV2PackageUpdateCmd
 	}
-	err = yarn.CommonPackageHandler.UpdateDependency(vulnDetails, installationCommand, extraArgs...)
+	// In a monorepo, the dependency is updated in the package.json of the workspaces that declare it. The workspaces share the lockfile of the root.
+	workspaces, err := getDeclaringWorkspaces(vulnDetails.ImpactedDependencyName)
+	if err != nil {
+		return
+	}
+	if len(workspaces) == 0 {
+		err = yarn.CommonPackageHandler.UpdateDependency(vulnDetails, installationCommand, extraArgs...)
+	} else {
+		err = yarn.updateWorkspacesDependency(vulnDetails, workspaces, installationCommand, extraArgs...)
+	}
 	if err != nil {
 		err = fmt.Errorf("running 'yarn %s for '%s' failed:\n%s\nHint: The Yarn version that was used is: %s. If your project was built with a different major version of Yarn, please configure your CI runner to include it",
 			installationCommand,
 			vulnDetails.ImpactedDependencyName,
 			err.Error(),
 			executableYarnVersion)
+		return
 	}
+	vulnDetails.UpdatedWorkspaces = getWorkspacePaths(workspaces)
 	return
 }
 
+// Runs the installation command in each workspace with 'yarn workspace <name>', which selects the workspace by its package name
+func (yarn *YarnPackageHandler) updateWorkspacesDependency(vulnDetails *utils.VulnerabilityDetails, workspaces []nodeWorkspace, installationCommand string, extraArgs ...string) error {
+	fixedPackageArgs := getFixedPackage(strings.ToLower(vulnDetails.ImpactedDependencyName), vulnDetails.Technology.GetPackageVersionOperator(), vulnDetails.SuggestedFixedVersion)
+	for _, workspace := range workspaces {
+		if workspace.Name == "" {
+			return fmt.Errorf("the workspace '%s' declares '%s', but has no package name that Yarn can select it by", workspace.Path, vulnDetails.ImpactedDependencyName)
+		}
+		commandArgs := append([]string{yarnWorkspaceCmd, workspace.Name, installationCommand}, extraArgs...)
+		if err := runPackageMangerCommand(vulnDetails.Technology.GetExecCommandName(), vulnDetails.Technology.String(), append(commandArgs, fixedPackageArgs...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // isYarnV1Project gets the current executed yarn version and returns whether the current yarn version is V1 or not
 func isYarnV1Project() (isYarn1 bool, executableYarnVersion string, err error) {
 	// NOTICE: in case your global yarn version is 1.x this function will always return true even if the project is originally in higher yarn version
@@ -9,7 +9,7 @@ build () {
   exeName="$4"
   echo "Building $exeName for $GOOS-$GOARCH ..."
 
-  CGO_ENABLED=0 jf go build -o "$exeName" -ldflags '-w -extldflags "-static" -X github.com/jfrog/frogbot/v2/utils.FrogbotVersion='"$version"
+  CGO_ENABLED=0 jf go build -o "$exeName" -ldflags '-w -extldflags "-static" -X github.com/jfrog/frogbot/v2/utils.FrogbotVersion='"$version"' -X github.com/jfrog/frogbot/v2/upgrade.releasePublicKey='"$publicKey"
   chmod +x "$exeName"
 
   # Run verification after building plugin for the correct platform of this image.
@@ -27,10 +27,13 @@ buildAndUpload () {
   exeName="frogbot$fileExtension"
 
   build "$pkg" "$goos" "$goarch" "$exeName"
+  # The upgrade command verifies the detached signature of the executable with the public key pinned to the running executable
+  openssl pkeyutl -sign -rawin -inkey "$signingKeyPath" -in "$exeName" -out "$exeName.sig"
 
   destPath="$pkgPath/$version/$pkg/$exeName"
   echo "Uploading $exeName to $destPath ..."
   jf rt u "./$exeName" "$destPath"
+  jf rt u "./$exeName.sig" "$destPath.sig"
 }
 
 # Verify version provided in pipelines UI matches version in frogbot source code.
@@ -55,7 +58,11 @@ verifyVersionMatching () {
 }
 
 version="$1"
+# The path of the PEM encoded Ed25519 private key that signs the executables
+signingKeyPath="$2"
 pkgPath="ecosys-frogbot/v2"
+# The base64 encoded raw public key, which is the last 32 bytes of its DER encoding
+publicKey=$(openssl pkey -in "$signingKeyPath" -pubout -outform DER | tail -c 32 | base64)
 
 # Build and upload for every architecture.
 # Keep 'linux-386' first to prevent unnecessary uploads in case the built version doesn't match the provided one.
@@ -0,0 +1,339 @@
+package reportcoverage
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/scanpullrequest"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/repodiscovery"
+	"github.com/jfrog/frogbot/v2/utils/scanhistory"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	OwnerFlag                 = "owner"
+	FormatFlag                = "format"
+	OutputFlag                = "output"
+	PullRequestsStateFileFlag = "pull-requests-state-file"
+	ScanHistoryFileFlag       = "scan-history-file"
+
+	// Replaced with the name of each repository in the path of the scan history files
+	RepositoryPlaceHolder = "{REPOSITORY}"
+
+	MarkdownFormat = "markdown"
+	JsonFormat     = "json"
+	CsvFormat      = "csv"
+	DefaultFormat  = MarkdownFormat
+
+	// The layout of the times in the CSV and markdown reports
+	timeLayout = time.RFC3339
+)
+
+var supportedFormats = []string{MarkdownFormat, JsonFormat, CsvFormat}
+
+// The CI files that run Frogbot in the repositories of each Git provider, as in the templates of the Frogbot documentation.
+// A CI file configures Frogbot only if it mentions Frogbot, since the CI files of some providers run all the pipelines of the repository.
+var ciFiles = map[vcsutils.VcsProvider][]string{
+	vcsutils.GitHub:          {".github/workflows/frogbot-scan-pull-request.yml", ".github/workflows/frogbot-scan-repository.yml"},
+	vcsutils.GitLab:          {".gitlab-ci.yml"},
+	vcsutils.BitbucketServer: {"Jenkinsfile"},
+	vcsutils.BitbucketCloud:  {"bitbucket-pipelines.yml"},
+	vcsutils.AzureRepos:      {"azure-pipelines.yml"},
+}
+
+// ReportCoverageCmd lists the repositories of an owner, and reports which of them have Frogbot configured and when Frogbot last scanned them,
+// so the security teams can track the rollout of Frogbot. A repository has Frogbot configured if its default branch has a frogbot-config.yml file,
+// or a CI file that runs Frogbot. Only the API of the Git provider is used, so the JFrog platform details aren't required.
+// The scan times are read from the pull requests state file of the scan-all-pull-requests command, and from the scan history files of the repositories.
+type ReportCoverageCmd struct {
+	// The organization, group, workspace or project whose repositories are reported. Defaults to JF_GIT_OWNER.
+	Owner string
+	// The pull requests state file that the pull request scans are read from. Defaults to JF_PULL_REQUESTS_STATE_FILE.
+	PullRequestsStateFile string
+	// The path of the scan history files that the repository scans are read from, in which {REPOSITORY} is replaced with the name of each repository
+	ScanHistoryFile string
+	// The format of the report, markdown, json or csv
+	Format string
+	// The path the report is written to. The report is printed if it isn't set.
+	OutputPath string
+	// The writer the report is printed to, the standard output by default
+	output io.Writer
+}
+
+// CoverageReport is the Frogbot coverage of the repositories of an owner
+type CoverageReport struct {
+	Owner       string    `json:"owner"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	// The repositories that aren't archived, which the coverage is calculated of
+	ActiveRepositories int `json:"activeRepositories"`
+	// The active repositories that have Frogbot configured
+	ConfiguredRepositories int `json:"configuredRepositories"`
+	// The percentage of the active repositories that have Frogbot configured
+	Coverage     float64              `json:"coverage"`
+	Repositories []RepositoryCoverage `json:"repositories"`
+}
+
+// RepositoryCoverage is the Frogbot coverage of a repository
+type RepositoryCoverage struct {
+	Name          string `json:"name"`
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	Archived      bool   `json:"archived"`
+	Fork          bool   `json:"fork"`
+	Configured    bool   `json:"configured"`
+	// The files of the default branch that configure Frogbot
+	ConfigFiles []string `json:"configFiles,omitempty"`
+	// The time of the latest scan of the repository or of its pull requests. Nil if no scan was recorded.
+	LastScanned *time.Time `json:"lastScanned,omitempty"`
+	// Set if the coverage of the repository couldn't be checked completely
+	Error string `json:"error,omitempty"`
+}
+
+func (rc *ReportCoverageCmd) Run() (err error) {
+	if rc.Format == "" {
+		rc.Format = DefaultFormat
+	}
+	if !slices.Contains(supportedFormats, rc.Format) {
+		return fmt.Errorf("unsupported format '%s'. The supported formats are: %s", rc.Format, strings.Join(supportedFormats, ", "))
+	}
+	gitParams, client, err := utils.GetGitDetailsFromEnv(utils.ReportCoverage)
+	if err != nil {
+		return
+	}
+	owner := rc.Owner
+	if owner == "" {
+		if owner = gitParams.RepoOwner; owner == "" {
+			return fmt.Errorf("the owner of the repositories is missing. Set the --%s flag or the %s environment variable", OwnerFlag, utils.GitRepoOwnerEnv)
+		}
+	}
+	scans, err := rc.loadScanTimes(owner)
+	if err != nil {
+		return
+	}
+	lister, err := repodiscovery.NewLister(gitParams.GitProvider, gitParams.VcsInfo, owner)
+	if err != nil {
+		return
+	}
+	report, err := buildReport(gitParams.GitProvider, owner, lister, client, scans)
+	if err != nil {
+		return
+	}
+	return rc.writeReport(report)
+}
+
+// The records of the scans that the times Frogbot last scanned the repositories are read from
+type scanTimes struct {
+	owner string
+	// The time of the latest pull request scan of each repository, by the owner and the name of the repository
+	pullRequestScans map[string]time.Time
+	// The path of the scan history files, in which {REPOSITORY} is replaced with the name of each repository
+	scanHistoryFile string
+}
+
+func (rc *ReportCoverageCmd) loadScanTimes(owner string) (scans *scanTimes, err error) {
+	scans = &scanTimes{owner: owner, scanHistoryFile: rc.ScanHistoryFile}
+	stateFile := rc.PullRequestsStateFile
+	if stateFile == "" {
+		stateFile = strings.TrimSpace(os.Getenv(utils.PullRequestsStateFileEnv))
+	}
+	if stateFile != "" {
+		if scans.pullRequestScans, err = scanpullrequest.LoadLastScanTimes(stateFile); err != nil {
+			return nil, err
+		}
+	}
+	if stateFile == "" && scans.scanHistoryFile == "" {
+		log.Warn(fmt.Sprintf("The times of the last scans aren't reported, since neither the pull requests state file nor the scan history files are set. Set the --%s or --%s flags", PullRequestsStateFileFlag, ScanHistoryFileFlag))
+	}
+	return
+}
+
+// Returns the time of the latest scan of the repository or of its pull requests, or nil if no scan was recorded
+func (st *scanTimes) getLastScanTime(repoName string) (lastScanned *time.Time, err error) {
+	if st == nil {
+		return
+	}
+	if pullRequestScan, exists := st.pullRequestScans[st.owner+"/"+repoName]; exists {
+		lastScanned = &pullRequestScan
+	}
+	if st.scanHistoryFile == "" {
+		return
+	}
+	history, err := scanhistory.Load(scanhistory.NewStorage(strings.ReplaceAll(st.scanHistoryFile, RepositoryPlaceHolder, repoName), "", nil))
+	if err != nil {
+		return lastScanned, fmt.Errorf("couldn't read the scan history: %s", err.Error())
+	}
+	if repositoryScan := history.LastScanTime(); repositoryScan != nil && (lastScanned == nil || repositoryScan.After(*lastScanned)) {
+		lastScanned = repositoryScan
+	}
+	return
+}
+
+func buildReport(provider vcsutils.VcsProvider, owner string, lister repodiscovery.Lister, client vcsclient.VcsClient, scans *scanTimes) (*CoverageReport, error) {
+	repositories, err := lister.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the repositories of '%s': %s", owner, err.Error())
+	}
+	log.Info(fmt.Sprintf("Checking the Frogbot coverage of %d repositories of '%s'", len(repositories), owner))
+	slices.SortFunc(repositories, func(a, b repodiscovery.Repository) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	report := &CoverageReport{Owner: owner, GeneratedAt: time.Now().UTC()}
+	for _, repository := range repositories {
+		coverage := getRepositoryCoverage(provider, owner, repository, client, scans)
+		if !coverage.Archived {
+			report.ActiveRepositories++
+			if coverage.Configured {
+				report.ConfiguredRepositories++
+			}
+		}
+		report.Repositories = append(report.Repositories, coverage)
+	}
+	if report.ActiveRepositories > 0 {
+		report.Coverage = float64(report.ConfiguredRepositories*100) / float64(report.ActiveRepositories)
+	}
+	log.Info(fmt.Sprintf("Frogbot is configured in %d of the %d active repositories of '%s'", report.ConfiguredRepositories, report.ActiveRepositories, owner))
+	return report, nil
+}
+
+// The errors of a repository are reported with its coverage, so the other repositories are still reported
+func getRepositoryCoverage(provider vcsutils.VcsProvider, owner string, repository repodiscovery.Repository, client vcsclient.VcsClient, scans *scanTimes) RepositoryCoverage {
+	coverage := RepositoryCoverage{Name: repository.Name, DefaultBranch: repository.DefaultBranch, Archived: repository.Archived, Fork: repository.Fork}
+	var errs []string
+	for _, path := range append([]string{utils.FrogbotConfigFilePath}, ciFiles[provider]...) {
+		configures, err := configuresFrogbot(owner, repository, path, client)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if configures {
+			coverage.ConfigFiles = append(coverage.ConfigFiles, path)
+		}
+	}
+	coverage.Configured = len(coverage.ConfigFiles) > 0
+	lastScanned, err := scans.getLastScanTime(repository.Name)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	coverage.LastScanned = lastScanned
+	if len(errs) > 0 {
+		coverage.Error = strings.Join(errs, "; ")
+		log.Warn(fmt.Sprintf("Couldn't check the Frogbot coverage of '%s' completely: %s", repository.Name, coverage.Error))
+	}
+	return coverage
+}
+
+// Returns true if the file exists in the default branch, and is the config file or a CI file that mentions Frogbot
+func configuresFrogbot(owner string, repository repodiscovery.Repository, path string, client vcsclient.VcsClient) (bool, error) {
+	content, statusCode, err := client.DownloadFileFromRepo(context.Background(), owner, repository.Name, repository.DefaultBranch, path)
+	if statusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("couldn't download the %s file: %s", path, err.Error())
+	}
+	return path == utils.FrogbotConfigFilePath || strings.Contains(strings.ToLower(string(content)), "frogbot"), nil
+}
+
+func (rc *ReportCoverageCmd) writeReport(report *CoverageReport) (err error) {
+	output := rc.output
+	if rc.OutputPath != "" {
+		var file *os.File
+		if file, err = os.Create(rc.OutputPath); err != nil {
+			return
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		output = file
+	} else if output == nil {
+		output = os.Stdout
+	}
+	switch rc.Format {
+	case JsonFormat:
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	case CsvFormat:
+		err = writeCsv(output, report)
+	default:
+		_, err = io.WriteString(output, toMarkdown(report))
+	}
+	if err == nil && rc.OutputPath != "" {
+		log.Info("The coverage report was written to:", rc.OutputPath)
+	}
+	return
+}
+
+func writeCsv(output io.Writer, report *CoverageReport) error {
+	writer := csv.NewWriter(output)
+	records := [][]string{{"repository", "defaultBranch", "archived", "fork", "configured", "configFiles", "lastScanned", "error"}}
+	for _, repository := range report.Repositories {
+		records = append(records, []string{
+			repository.Name,
+			repository.DefaultBranch,
+			strconv.FormatBool(repository.Archived),
+			strconv.FormatBool(repository.Fork),
+			strconv.FormatBool(repository.Configured),
+			strings.Join(repository.ConfigFiles, ";"),
+			formatTime(repository.LastScanned),
+			repository.Error,
+		})
+	}
+	return writer.WriteAll(records)
+}
+
+func toMarkdown(report *CoverageReport) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# Frogbot Coverage of %s\n\n", report.Owner))
+	builder.WriteString(fmt.Sprintf("Frogbot is configured in **%d of %d** active repositories (%.1f%%). Generated at %s.\n\n", report.ConfiguredRepositories, report.ActiveRepositories, report.Coverage, report.GeneratedAt.Format(timeLayout)))
+	builder.WriteString("| Repository | Configured | Config Files | Last Scanned | Notes |\n")
+	builder.WriteString("| --- | :---: | --- | --- | --- |\n")
+	for _, repository := range report.Repositories {
+		configured := "❌"
+		if repository.Configured {
+			configured = "✅"
+		}
+		var notes []string
+		if repository.Archived {
+			notes = append(notes, "Archived")
+		}
+		if repository.Fork {
+			notes = append(notes, "Fork")
+		}
+		if repository.Error != "" {
+			notes = append(notes, "Error: "+repository.Error)
+		}
+		builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			escapeTableCell(repository.Name),
+			configured,
+			escapeTableCell(strings.Join(repository.ConfigFiles, "<br>")),
+			formatTime(repository.LastScanned),
+			escapeTableCell(strings.Join(notes, "<br>"))))
+	}
+	return builder.String()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(timeLayout)
+}
+
+// The pipes and the line breaks of a cell break the markdown table
+func escapeTableCell(cell string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(cell)
+}
@@ -0,0 +1,174 @@
+package reportcoverage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/repodiscovery"
+	"github.com/jfrog/frogbot/v2/utils/scanhistory"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pullRequestWorkflow = ".github/workflows/frogbot-scan-pull-request.yml"
+	repositoryWorkflow  = ".github/workflows/frogbot-scan-repository.yml"
+)
+
+type listerMock struct {
+	repositories []repodiscovery.Repository
+	err          error
+}
+
+func (lm *listerMock) List() ([]repodiscovery.Repository, error) {
+	return lm.repositories, lm.err
+}
+
+func TestBuildReport(t *testing.T) {
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	lister := &listerMock{repositories: []repodiscovery.Repository{
+		{Name: "web", DefaultBranch: "main"},
+		{Name: "api", DefaultBranch: "master"},
+		{Name: "legacy", Archived: true},
+	}}
+	// The api repository is configured by its config file and its workflow
+	client.EXPECT().DownloadFileFromRepo(gomock.Any(), "jfrog", "api", "master", utils.FrogbotConfigFilePath).Return([]byte("- params:"), http.StatusOK, nil)
+	client.EXPECT().DownloadFileFromRepo(gomock.Any(), "jfrog", "api", "master", pullRequestWorkflow).Return([]byte("uses: jfrog/frogbot@v2"), http.StatusOK, nil)
+	client.EXPECT().DownloadFileFromRepo(gomock.Any(), "jfrog", "api", "master", repositoryWorkflow).Return(nil, http.StatusNotFound, errors.New("not found"))
+	// A workflow that doesn't mention Frogbot doesn't configure it
+	client.EXPECT().DownloadFileFromRepo(gomock.Any(), "jfrog", "web", "main", utils.FrogbotConfigFilePath).Return(nil, http.StatusNotFound, nil)
+	client.EXPECT().DownloadFileFromRepo(gomock.Any(), "jfrog", "web", "main", pullRequestWorkflow).Return([]byte("run: npm test"), http.StatusOK, nil)
+	client.EXPECT().DownloadFileFromRepo(gomock.Any(), "jfrog", "web", "main", repositoryWorkflow).Return(nil, http.StatusNotFound, nil)
+	client.EXPECT().DownloadFileFromRepo(gomock.Any(), "jfrog", "legacy", "", gomock.Any()).Return(nil, http.StatusNotFound, nil).Times(3)
+
+	// The api repository was last scanned by a repository scan, and the web repository only by a pull request scan
+	historyDir := t.TempDir()
+	pullRequestScan := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	repositoryScan := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	writeHistory(t, filepath.Join(historyDir, "api.json"), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), repositoryScan)
+	// The errors of a repository are reported with it
+	require.NoError(t, os.WriteFile(filepath.Join(historyDir, "legacy.json"), []byte("{"), 0644))
+	scans := &scanTimes{
+		owner:            "jfrog",
+		pullRequestScans: map[string]time.Time{"jfrog/api": pullRequestScan, "jfrog/web": pullRequestScan, "other/legacy": repositoryScan},
+		scanHistoryFile:  filepath.Join(historyDir, RepositoryPlaceHolder+".json"),
+	}
+
+	report, err := buildReport(vcsutils.GitHub, "jfrog", lister, client, scans)
+	require.NoError(t, err)
+	assert.Equal(t, []RepositoryCoverage{
+		{Name: "api", DefaultBranch: "master", Configured: true, ConfigFiles: []string{utils.FrogbotConfigFilePath, pullRequestWorkflow}, LastScanned: &repositoryScan},
+		{Name: "legacy", Archived: true, Error: "couldn't read the scan history: failed to parse the scan history: unexpected end of JSON input"},
+		{Name: "web", DefaultBranch: "main", LastScanned: &pullRequestScan},
+	}, report.Repositories)
+	// The archived repositories aren't counted
+	assert.Equal(t, 2, report.ActiveRepositories)
+	assert.Equal(t, 1, report.ConfiguredRepositories)
+	assert.Equal(t, 50.0, report.Coverage)
+
+	_, err = buildReport(vcsutils.GitHub, "jfrog", &listerMock{err: errors.New("unauthorized")}, client, scans)
+	assert.ErrorContains(t, err, "failed to list the repositories of 'jfrog': unauthorized")
+}
+
+func writeHistory(t *testing.T, historyFile string, scanTimes ...time.Time) {
+	history := &scanhistory.History{}
+	for _, scanTime := range scanTimes {
+		history.Add(scanhistory.Summary{Time: scanTime, Branch: "master"})
+	}
+	require.NoError(t, history.Save(scanhistory.NewStorage(historyFile, "", nil)))
+}
+
+func TestLoadScanTimes(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(stateFile, []byte(`{"jfrog/api":{"1":{"headCommit":"abc","scanTime":"2024-03-01T00:00:00Z"},"2":{"headCommit":"def","scanTime":"2024-03-05T00:00:00Z"}}}`), 0644))
+	scans, err := (&ReportCoverageCmd{PullRequestsStateFile: stateFile}).loadScanTimes("jfrog")
+	require.NoError(t, err)
+	lastScanned, err := scans.getLastScanTime("api")
+	require.NoError(t, err)
+	require.NotNil(t, lastScanned)
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), *lastScanned)
+
+	// Without records of the scans, the times of the last scans aren't reported
+	t.Setenv(utils.PullRequestsStateFileEnv, "")
+	scans, err = (&ReportCoverageCmd{}).loadScanTimes("jfrog")
+	require.NoError(t, err)
+	lastScanned, err = scans.getLastScanTime("api")
+	assert.NoError(t, err)
+	assert.Nil(t, lastScanned)
+}
+
+func TestConfiguresFrogbotDownloadError(t *testing.T) {
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	client.EXPECT().DownloadFileFromRepo(context.Background(), "jfrog", "api", "main", ".gitlab-ci.yml").Return(nil, http.StatusInternalServerError, errors.New("server error"))
+	_, err := configuresFrogbot("jfrog", repodiscovery.Repository{Name: "api", DefaultBranch: "main"}, ".gitlab-ci.yml", client)
+	assert.ErrorContains(t, err, "couldn't download the .gitlab-ci.yml file: server error")
+}
+
+func TestWriteReport(t *testing.T) {
+	lastScanned := time.Date(2024, 3, 9, 16, 0, 0, 0, time.UTC)
+	report := &CoverageReport{
+		Owner:                  "jfrog",
+		GeneratedAt:            time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC),
+		ActiveRepositories:     2,
+		ConfiguredRepositories: 1,
+		Coverage:               50,
+		Repositories: []RepositoryCoverage{
+			{Name: "api", DefaultBranch: "master", Configured: true, ConfigFiles: []string{utils.FrogbotConfigFilePath, pullRequestWorkflow}, LastScanned: &lastScanned},
+			{Name: "web", DefaultBranch: "main", Fork: true, Error: "couldn't list the branches: forbidden"},
+		},
+	}
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: MarkdownFormat,
+			expected: `# Frogbot Coverage of jfrog
+
+Frogbot is configured in **1 of 2** active repositories (50.0%). Generated at 2024-03-10T08:00:00Z.
+
+| Repository | Configured | Config Files | Last Scanned | Notes |
+| --- | :---: | --- | --- | --- |
+| api | ✅ | .frogbot/frogbot-config.yml<br>.github/workflows/frogbot-scan-pull-request.yml | 2024-03-09T16:00:00Z |  |
+| web | ❌ |  |  | Fork<br>Error: couldn't list the branches: forbidden |
+`,
+		},
+		{
+			format: CsvFormat,
+			expected: `repository,defaultBranch,archived,fork,configured,configFiles,lastScanned,error
+api,master,false,false,true,.frogbot/frogbot-config.yml;.github/workflows/frogbot-scan-pull-request.yml,2024-03-09T16:00:00Z,
+web,main,false,true,false,,,couldn't list the branches: forbidden
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			require.NoError(t, (&ReportCoverageCmd{Format: tc.format, output: output}).writeReport(report))
+			assert.Equal(t, tc.expected, output.String())
+		})
+	}
+
+	t.Run(JsonFormat, func(t *testing.T) {
+		output := &bytes.Buffer{}
+		require.NoError(t, (&ReportCoverageCmd{Format: JsonFormat, output: output}).writeReport(report))
+		assert.Contains(t, output.String(), `"coverage": 50,`)
+		assert.Contains(t, output.String(), `"lastScanned": "2024-03-09T16:00:00Z"`)
+	})
+}
+
+func TestReportCoverageUnsupportedFormat(t *testing.T) {
+	err := (&ReportCoverageCmd{Format: "xml"}).Run()
+	assert.ErrorContains(t, err, "unsupported format 'xml'")
+}
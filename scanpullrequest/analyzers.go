@@ -0,0 +1,144 @@
+package scanpullrequest
+
+import (
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/bazel"
+	"github.com/jfrog/frogbot/v2/utils/composer"
+	"github.com/jfrog/frogbot/v2/utils/curation"
+	"github.com/jfrog/frogbot/v2/utils/dockerimage"
+	"github.com/jfrog/frogbot/v2/utils/githubactions"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/systempackages"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// sourceBranchAnalyzer reports the issues of the source branch that the audit doesn't find.
+// When the target branch is set first, only the issues that the pull request adds are reported.
+// Failing to read the target branch or to analyze the source branch doesn't fail the scan of the pull request.
+type sourceBranchAnalyzer struct {
+	setTargetBranch func(targetBranchWd string, workingDirs []string) error
+	analyze         func(auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, workingDirs []string) error
+	// The warnings that are logged with the errors of setTargetBranch and analyze
+	targetBranchWarning string
+	analyzeWarning      string
+}
+
+// Returns the analyzers of the source branch, which run in this order after the locations of the audit issues are converted to the working directories.
+// The analyzers that aren't enabled analyze nothing.
+func newSourceBranchAnalyzers(repoConfig *utils.Repository, scanDetails *utils.ScanDetails) ([]sourceBranchAnalyzer, error) {
+	dockerImageAnalyzer, err := dockerimage.NewAnalyzer(repoConfig.ScanDockerfiles, scanDetails.ServerDetails, scanDetails.XrayVersion)
+	if err != nil {
+		return nil, err
+	}
+	systemPackagesAnalyzer, err := systempackages.NewAnalyzer(repoConfig.ScanSystemPackages, scanDetails.ServerDetails, scanDetails.XrayVersion)
+	if err != nil {
+		return nil, err
+	}
+	bazelAnalyzer, err := bazel.NewAnalyzer(repoConfig.ScanBazel, scanDetails.ServerDetails, scanDetails.XrayVersion)
+	if err != nil {
+		return nil, err
+	}
+	composerAnalyzer, err := composer.NewAnalyzer(repoConfig.ScanComposer, scanDetails.ServerDetails, scanDetails.XrayVersion)
+	if err != nil {
+		return nil, err
+	}
+	gitHubActionsAnalyzer := githubactions.NewAnalyzer(repoConfig.ScanGitHubActions, false, repoConfig.GitProvider, repoConfig.VcsInfo)
+	curationAnalyzer := curation.NewAnalyzer(repoConfig.ScanCuration, scanDetails.ServerDetails)
+	return []sourceBranchAnalyzer{
+		{
+			// Only the base images and OS packages that the pull request adds to the Dockerfiles are scanned
+			setTargetBranch: func(_ string, workingDirs []string) error {
+				return dockerImageAnalyzer.SetTargetBranch(workingDirs...)
+			},
+			analyze: func(auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, workingDirs []string) (err error) {
+				auditIssues.DockerImageVulnerabilities, err = dockerImageAnalyzer.Analyze(sourceBranchWd, workingDirs...)
+				return
+			},
+			targetBranchWarning: "Couldn't read the Dockerfiles of the target branch, so all the components of the Dockerfiles are scanned:",
+			analyzeWarning:      "Couldn't scan the base images and OS packages of the Dockerfiles:",
+		},
+		{
+			// Only the packages that the pull request adds to the Conda environment files and the OS package lists are scanned
+			setTargetBranch: func(_ string, workingDirs []string) error {
+				return systemPackagesAnalyzer.SetTargetBranch(workingDirs...)
+			},
+			analyze: func(auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, workingDirs []string) (err error) {
+				auditIssues.SystemPackageVulnerabilities, err = systemPackagesAnalyzer.Analyze(sourceBranchWd, workingDirs...)
+				return
+			},
+			targetBranchWarning: "Couldn't read the package lists of the target branch, so all the packages of the package lists are scanned:",
+			analyzeWarning:      "Couldn't scan the packages of the Conda environment files and the OS package lists:",
+		},
+		{
+			// Only the dependencies that the pull request adds to the Bazel lockfiles are scanned.
+			// Their vulnerabilities are reported with the SCA vulnerabilities of the audit, with the lockfiles as their locations.
+			setTargetBranch: func(_ string, workingDirs []string) error {
+				return bazelAnalyzer.SetTargetBranch(workingDirs...)
+			},
+			analyze: func(auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, workingDirs []string) error {
+				vulnerabilities, err := bazelAnalyzer.Analyze(sourceBranchWd, workingDirs...)
+				auditIssues.ScaVulnerabilities = append(auditIssues.ScaVulnerabilities, vulnerabilities...)
+				return err
+			},
+			targetBranchWarning: "Couldn't read the Bazel lockfiles of the target branch, so all the dependencies of the lockfiles are scanned:",
+			analyzeWarning:      "Couldn't scan the dependencies of the Bazel lockfiles:",
+		},
+		{
+			// Only the packages that the pull request adds to the Composer lockfiles are scanned.
+			// Their vulnerabilities are reported with the SCA vulnerabilities of the audit, with the lockfiles as their locations.
+			setTargetBranch: func(_ string, workingDirs []string) error {
+				return composerAnalyzer.SetTargetBranch(workingDirs...)
+			},
+			analyze: func(auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, workingDirs []string) error {
+				vulnerabilities, err := composerAnalyzer.Analyze(sourceBranchWd, workingDirs...)
+				auditIssues.ScaVulnerabilities = append(auditIssues.ScaVulnerabilities, vulnerabilities...)
+				return err
+			},
+			targetBranchWarning: "Couldn't read the Composer lockfiles of the target branch, so all the packages of the lockfiles are scanned:",
+			analyzeWarning:      "Couldn't scan the packages of the Composer lockfiles:",
+		},
+		{
+			// Only the actions that the pull request adds to the workflows are reported.
+			// The workflows are at the root of the repository, so they're analyzed once for all the projects.
+			setTargetBranch: func(targetBranchWd string, _ []string) error {
+				return gitHubActionsAnalyzer.SetTargetBranch(targetBranchWd)
+			},
+			analyze: func(auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, _ []string) (err error) {
+				auditIssues.GitHubActionIssues, err = gitHubActionsAnalyzer.Analyze(sourceBranchWd)
+				return
+			},
+			targetBranchWarning: "Couldn't read the GitHub Actions workflows of the target branch, so all the actions of the workflows are checked:",
+			analyzeWarning:      "Couldn't check the actions of the GitHub Actions workflows:",
+		},
+		{
+			// Only the blocked dependencies that the pull request adds are reported
+			setTargetBranch: func(_ string, workingDirs []string) error {
+				return curationAnalyzer.SetTargetBranch(scanDetails.Project.DepsRepo, workingDirs...)
+			},
+			analyze: func(auditIssues *issues.ScansIssuesCollection, _ string, workingDirs []string) (err error) {
+				auditIssues.CurationBlockedPackages, err = curationAnalyzer.Analyze(scanDetails.Project.DepsRepo, workingDirs...)
+				return
+			},
+			targetBranchWarning: "Couldn't run the curation audit of the target branch, so all the blocked dependencies are reported:",
+			analyzeWarning:      "Couldn't check the dependencies against the curation policies:",
+		},
+	}, nil
+}
+
+// Sets the target branch of the pull request to the analyzers, before the target branch is audited and removed
+func setAnalyzersTargetBranch(analyzers []sourceBranchAnalyzer, targetBranchWd string, workingDirs []string) {
+	for _, analyzer := range analyzers {
+		if err := analyzer.setTargetBranch(targetBranchWd, workingDirs); err != nil {
+			log.Warn(analyzer.targetBranchWarning, err.Error())
+		}
+	}
+}
+
+// Adds the issues that the analyzers report in the source branch to the issues of the audit
+func runSourceBranchAnalyzers(analyzers []sourceBranchAnalyzer, auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, workingDirs []string) {
+	for _, analyzer := range analyzers {
+		if err := analyzer.analyze(auditIssues, sourceBranchWd, workingDirs); err != nil {
+			log.Warn(analyzer.analyzeWarning, err.Error())
+		}
+	}
+}
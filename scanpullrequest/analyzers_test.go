@@ -0,0 +1,54 @@
+package scanpullrequest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceBranchAnalyzers(t *testing.T) {
+	var calls []string
+	newTestAnalyzer := func(name string, err error) sourceBranchAnalyzer {
+		return sourceBranchAnalyzer{
+			setTargetBranch: func(targetBranchWd string, workingDirs []string) error {
+				calls = append(calls, "target "+name+" "+targetBranchWd)
+				return err
+			},
+			analyze: func(auditIssues *issues.ScansIssuesCollection, sourceBranchWd string, workingDirs []string) error {
+				calls = append(calls, "analyze "+name+" "+sourceBranchWd)
+				if err == nil {
+					auditIssues.ScaVulnerabilities = append(auditIssues.ScaVulnerabilities, formats.VulnerabilityOrViolationRow{IssueId: name})
+				}
+				return err
+			},
+		}
+	}
+	// A failing analyzer doesn't stop the next analyzers
+	analyzers := []sourceBranchAnalyzer{newTestAnalyzer("first", errors.New("failed")), newTestAnalyzer("second", nil)}
+	setAnalyzersTargetBranch(analyzers, "target", []string{"target/dir"})
+	auditIssues := &issues.ScansIssuesCollection{}
+	runSourceBranchAnalyzers(analyzers, auditIssues, "source", []string{"source/dir"})
+	assert.Equal(t, []string{"target first target", "target second target", "analyze first source", "analyze second source"}, calls)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{{IssueId: "second"}}, auditIssues.ScaVulnerabilities)
+}
+
+func TestNewSourceBranchAnalyzersDisabled(t *testing.T) {
+	repoConfig := &utils.Repository{}
+	scanDetails := &utils.ScanDetails{Project: &utils.Project{}}
+	analyzers, err := newSourceBranchAnalyzers(repoConfig, scanDetails)
+	assert.NoError(t, err)
+	assert.Len(t, analyzers, 6)
+	// The analyzers that aren't enabled read and report nothing
+	tempDir := t.TempDir()
+	setAnalyzersTargetBranch(analyzers, tempDir, []string{tempDir})
+	auditIssues := &issues.ScansIssuesCollection{}
+	runSourceBranchAnalyzers(analyzers, auditIssues, tempDir, []string{tempDir})
+	assert.Empty(t, auditIssues.ScaVulnerabilities)
+	assert.Empty(t, auditIssues.DockerImageVulnerabilities)
+	assert.Empty(t, auditIssues.GitHubActionIssues)
+	assert.Empty(t, auditIssues.CurationBlockedPackages)
+}
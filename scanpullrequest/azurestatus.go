@@ -0,0 +1,33 @@
+package scanpullrequest
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/azurepullrequests"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// On Azure Repos, the outcome of the scan is posted as a status of the pull request, which branch policies may require to succeed.
+// Failing to post the status doesn't fail the scan.
+func setAzurePullRequestStatus(repo *utils.Repository, pullRequestID int, scanErr error) {
+	if repo.GitProvider != vcsutils.AzureRepos {
+		return
+	}
+	state, description := azurepullrequests.Succeeded, "No security issues that fail the scan were found"
+	switch {
+	case scanErr == nil:
+	case scanErr.Error() == SecurityIssueFoundErr:
+		state, description = azurepullrequests.Failed, "Security issues were found"
+	case scanErr.Error() == SecretsFoundErr:
+		state, description = azurepullrequests.Failed, "New secrets were exposed, rotate them now"
+	case scanErr.Error() == CurationBlockedErr:
+		state, description = azurepullrequests.Failed, "Dependencies blocked by curation policies were added"
+	default:
+		state, description = azurepullrequests.Error, "The scan couldn't be completed"
+	}
+	if err := azurepullrequests.NewClient(repo.VcsInfo, repo.RepoName).SetStatus(pullRequestID, state, description); err != nil {
+		log.Warn(fmt.Sprintf("Couldn't set the '%s' status of pull request #%d: %s", state, pullRequestID, err.Error()))
+	}
+}
@@ -0,0 +1,43 @@
+package scanpullrequest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAzurePullRequestStatus(t *testing.T) {
+	var states []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jfrog/security/_apis/git/repositories/frogbot/pullrequests/3/statuses", r.URL.Path)
+		var status struct {
+			State string `json:"state"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+		states = append(states, status.State)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	repo := &utils.Repository{Params: utils.Params{Git: utils.Git{
+		GitProvider: vcsutils.AzureRepos,
+		RepoName:    "frogbot",
+		VcsInfo:     vcsclient.VcsInfo{APIEndpoint: server.URL + "/jfrog", Project: "security"},
+	}}}
+
+	for _, scanErr := range []error{nil, errors.New(SecurityIssueFoundErr), errors.New(CurationBlockedErr), errors.New("audit failed")} {
+		setAzurePullRequestStatus(repo, 3, scanErr)
+	}
+	assert.Equal(t, []string{"succeeded", "failed", "failed", "error"}, states)
+
+	// The status is posted only to Azure Repos pull requests
+	repo.GitProvider = vcsutils.GitHub
+	setAzurePullRequestStatus(repo, 3, nil)
+	assert.Len(t, states, 4)
+}
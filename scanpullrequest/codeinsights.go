@@ -0,0 +1,32 @@
+package scanpullrequest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/codeinsights"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// On Bitbucket Server, the results of the scan are published as a Code Insights report of the latest commit of the pull request,
+// which annotates the findings on the changed files. Failing to publish the report doesn't fail the scan, as the summary comment holds the results.
+func publishCodeInsightsReport(repo *utils.Repository, client vcsclient.VcsClient, issuesCollection *issues.ScansIssuesCollection) {
+	if !repo.CodeInsights {
+		return
+	}
+	source := repo.PullRequestDetails.Source
+	commit, err := client.GetLatestCommit(context.Background(), source.Owner, source.Repository, source.Name)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't get the latest commit of the <%s/%s/%s> branch, so the Code Insights report isn't published: %s", source.Owner, source.Repository, source.Name, err.Error()))
+		return
+	}
+	report, annotations := codeinsights.NewReport(issuesCollection, repo.PullRequestSecretComments)
+	if err = codeinsights.NewClient(repo.VcsInfo, source.Owner, source.Repository).PublishReport(commit.Hash, report, annotations); err != nil {
+		log.Warn(fmt.Sprintf("Couldn't publish the Code Insights report of commit %s: %s", commit.Hash, err.Error()))
+		return
+	}
+	log.Info(fmt.Sprintf("Published the Code Insights report of commit %s with %d annotations", commit.Hash, len(annotations)))
+}
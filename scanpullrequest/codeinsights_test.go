@@ -0,0 +1,50 @@
+package scanpullrequest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/codeinsights"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishCodeInsightsReport(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	repo := &utils.Repository{Params: utils.Params{Git: utils.Git{
+		GitProvider:        vcsutils.BitbucketServer,
+		CodeInsights:       true,
+		VcsInfo:            vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"},
+		PullRequestDetails: vcsclient.PullRequestInfo{Source: vcsclient.BranchInfo{Owner: "SEC", Repository: "frogbot", Name: "feature"}},
+	}}}
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	client.EXPECT().GetLatestCommit(context.Background(), "SEC", "frogbot", "feature").Return(vcsclient.CommitInfo{Hash: "abc123"}, nil)
+	publishCodeInsightsReport(repo, client, &issues.ScansIssuesCollection{})
+	assert.Equal(t, []string{
+		"PUT /rest/insights/1.0/projects/SEC/repos/frogbot/commits/abc123/reports/" + codeinsights.ReportKey,
+		"DELETE /rest/insights/1.0/projects/SEC/repos/frogbot/commits/abc123/reports/" + codeinsights.ReportKey + "/annotations",
+	}, requests)
+
+	// Failing to get the latest commit skips the report
+	client.EXPECT().GetLatestCommit(context.Background(), "SEC", "frogbot", "feature").Return(vcsclient.CommitInfo{}, errors.New("not found"))
+	publishCodeInsightsReport(repo, client, &issues.ScansIssuesCollection{})
+	assert.Len(t, requests, 2)
+
+	// The report is published only when it is enabled
+	repo.CodeInsights = false
+	publishCodeInsightsReport(repo, client, &issues.ScansIssuesCollection{})
+	assert.Len(t, requests, 2)
+}
@@ -0,0 +1,48 @@
+package scanpullrequest
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const BaselineOutputFlag = "output"
+
+// GenerateBaselineCmd scans a branch of the repository the same way scan-pull-request scans a pull request when all the issues are reported,
+// and writes all the findings to a baseline file.
+// Committing the file to the target branch of the pull requests accepts these findings, so they aren't reported in the pull requests.
+type GenerateBaselineCmd struct {
+	// The path the baseline file is written to
+	OutputPath string
+}
+
+func (cmd *GenerateBaselineCmd) Run(configAggregator utils.RepoAggregator, client vcsclient.VcsClient, _ *utils.UrlAccessChecker) (err error) {
+	if err = utils.ValidateSingleRepoConfiguration(&configAggregator); err != nil {
+		return
+	}
+	repoConfig := &(configAggregator)[0]
+	if len(repoConfig.Branches) == 0 {
+		return fmt.Errorf("the branch to generate the baseline from is missing. Please set it as the %s environment variable", utils.GitBaseBranchEnv)
+	}
+	branch := repoConfig.Branches[0]
+	log.Info(fmt.Sprintf("Generating the baseline of <%s/%s/%s>", repoConfig.RepoOwner, repoConfig.RepoName, branch))
+	// The branch is scanned as the source branch of a pull request that reports all the issues, so the finding IDs match the ones of the pull requests
+	repoConfig.IncludeAllVulnerabilities = true
+	repoConfig.PullRequestDetails.Source = vcsclient.BranchInfo{Name: branch, Repository: repoConfig.RepoName, Owner: repoConfig.RepoOwner}
+	issuesCollection, _, err := auditPullRequest(repoConfig, client)
+	if err != nil {
+		return
+	}
+	baseline := utils.NewBaseline(issuesCollection)
+	outputPath := cmd.OutputPath
+	if outputPath == "" {
+		outputPath = utils.BaselineFilePath
+	}
+	if err = baseline.Write(outputPath); err != nil {
+		return fmt.Errorf("couldn't write the baseline file: %s", err.Error())
+	}
+	log.Info(fmt.Sprintf("The baseline with %d findings was written to %s. Commit it to the %s path of the target branches to accept these findings", len(baseline.Findings), outputPath, utils.BaselineFilePath))
+	return
+}
@@ -0,0 +1,48 @@
+package scanpullrequest
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateBaselineBranch(t *testing.T) {
+	testCases := []struct {
+		name             string
+		baseBranch       string
+		expectedBranches []string
+	}{
+		{name: "Base branch", baseBranch: "dev", expectedBranches: []string{"dev"}},
+		{name: "No base branch"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := map[string]string{
+				utils.GitProvider:     string(utils.GitHub),
+				utils.GitRepoOwnerEnv: "jfrog",
+				utils.GitRepoEnv:      "frogbot",
+				utils.GitTokenEnv:     "123456789",
+			}
+			if tc.baseBranch != "" {
+				env[utils.GitBaseBranchEnv] = tc.baseBranch
+			}
+			utils.SetEnvAndAssert(t, env)
+			defer func() {
+				assert.NoError(t, utils.SanitizeEnv())
+			}()
+			gitParams, client, err := utils.GetGitDetailsFromEnv(utils.GenerateBaseline)
+			require.NoError(t, err)
+			configAggregator, err := utils.BuildRepoAggregator("xrayVersion", "xscVersion", client, nil, gitParams, &coreconfig.ServerDetails{}, utils.GenerateBaseline)
+			require.NoError(t, err)
+			require.Len(t, configAggregator, 1)
+			assert.Equal(t, tc.expectedBranches, configAggregator[0].Branches)
+			if len(tc.expectedBranches) == 0 {
+				cmd := &GenerateBaselineCmd{OutputPath: t.TempDir()}
+				assert.ErrorContains(t, cmd.Run(configAggregator, client, nil), utils.GitBaseBranchEnv)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package scanpullrequest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/policy"
+	"github.com/jfrog/frogbot/v2/utils/prcomments"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The length of the hash of the configuration that the summary comment records
+const configHashLength = 12
+
+const previousScanIssuesFoundErr = "the previous scan of the pull request detected issues, and the pull request didn't change since\n " + SecurityIssueFoundErr
+
+// When a pull request is updated, the incremental scan compares the latest commit of its source branch with the commit of the previous scan,
+// which the summary comment of the previous scan records. Only the summary comments that Frogbot added are trusted.
+// The scan is skipped if none of the projects changed since the previous scan, and only the changed projects are scanned if the previous scan reported no issues.
+// If the previous scan reported issues, all the projects are scanned, so the comments keep reporting the issues of the projects that didn't change.
+// All the projects are also scanned if the target branch, the configuration or the policy changed since the previous scan, as the results depend on them.
+type incrementalScan struct {
+	// The latest commit of the source branch
+	sourceCommit string
+	// The latest commit of the target branch
+	targetCommit string
+	// The hash of the configuration and the policy of the scan
+	configHash string
+	// The commit of the previous scan, nil if the changes since the previous scan are unknown
+	previousCommit *outputwriter.ScannedCommit
+	// The projects with working directories that changed since the previous scan
+	changedProjects []utils.Project
+}
+
+// Returns the incremental scan of the pull request. Failing to find the changes since the previous scan only scans all the projects.
+// Returns nil if the latest commit of the source or the target branch is unknown, so the scanned commit isn't recorded.
+func getIncrementalScan(repo *utils.Repository, client vcsclient.VcsClient) *incrementalScan {
+	source, target := repo.PullRequestDetails.Source, repo.PullRequestDetails.Target
+	commit, err := getLatestCommit(client, source)
+	if err != nil {
+		return nil
+	}
+	targetCommit, err := getLatestCommit(client, target)
+	if err != nil {
+		return nil
+	}
+	scan := &incrementalScan{sourceCommit: commit.Hash, targetCommit: targetCommit.Hash, configHash: getConfigHash(repo)}
+	previousCommit, err := getPreviousScannedCommit(repo, client)
+	if err != nil {
+		log.Warn("Couldn't find the commit of the previous scan, so all the projects are scanned:", err.Error())
+		return scan
+	}
+	if previousCommit == nil {
+		log.Debug("The pull request wasn't scanned before, so all the projects are scanned")
+		return scan
+	}
+	if previousCommit.TargetHash != scan.targetCommit || previousCommit.ConfigHash != scan.configHash {
+		log.Info("The target branch, the configuration or the policy changed since the previous scan, so all the projects are scanned")
+		return scan
+	}
+	if previousCommit.Hash != scan.sourceCommit {
+		modifiedFiles, err := client.GetModifiedFiles(context.Background(), source.Owner, source.Repository, previousCommit.Hash, scan.sourceCommit)
+		if err != nil {
+			// The commit of the previous scan may not exist anymore, for example after a force push
+			log.Warn(fmt.Sprintf("Couldn't get the files that changed since commit %s of the previous scan, so all the projects are scanned: %s", previousCommit.Hash, err.Error()))
+			return scan
+		}
+		scan.changedProjects = getChangedProjects(repo.Projects, modifiedFiles)
+	}
+	scan.previousCommit = previousCommit
+	return scan
+}
+
+func getLatestCommit(client vcsclient.VcsClient, branch vcsclient.BranchInfo) (vcsclient.CommitInfo, error) {
+	commit, err := client.GetLatestCommit(context.Background(), branch.Owner, branch.Repository, branch.Name)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't get the latest commit of the <%s/%s/%s> branch, so all the projects are scanned: %s", branch.Owner, branch.Repository, branch.Name, err.Error()))
+	}
+	return commit, err
+}
+
+// Returns the hash of the scan configuration, the JFrog Platform configuration and the policy, which the results of the scan depend on.
+// The email details are left out, as they hold the SMTP credentials and don't change the results.
+func getConfigHash(repo *utils.Repository) string {
+	scan := repo.Scan
+	scan.EmailDetails = utils.EmailDetails{}
+	content, err := json.Marshal(struct {
+		Scan                   utils.Scan
+		Policy                 *policy.Policy
+		Watches                []string
+		IncludeVulnerabilities bool
+		JFrogProjectKey        string
+	}{scan, repo.Policy, repo.Watches, repo.IncludeVulnerabilities, repo.JFrogProjectKey})
+	if err != nil {
+		log.Debug("Couldn't hash the configuration of the scan, so the next scan scans all the projects:", err.Error())
+		return ""
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])[:configHashLength]
+}
+
+// Returns the scanned commit of the newest summary comment of Frogbot, or nil if none of the comments records a scanned commit.
+// The comments of other users are ignored, even if they copy the hidden marker of the scanned commit.
+func getPreviousScannedCommit(repo *utils.Repository, client vcsclient.VcsClient) (*outputwriter.ScannedCommit, error) {
+	prDetails := repo.PullRequestDetails
+	comments, err := utils.GetSortedPullRequestComments(client, prDetails.Target.Owner, prDetails.Target.Repository, int(prDetails.ID))
+	if err != nil {
+		return nil, err
+	}
+	ownCommentIds, err := prcomments.ListOwnCommentIds(repo.GitProvider, repo.VcsInfo, prDetails.Target.Owner, prDetails.Target.Repository, int(prDetails.ID))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find the comments that Frogbot added: %s", err.Error())
+	}
+	for _, comment := range comments {
+		if !outputwriter.IsFrogbotComment(comment.Content) || !ownCommentIds.Exists(comment.ID) {
+			continue
+		}
+		if scannedCommit := outputwriter.GetScannedCommit(comment.Content); scannedCommit != nil {
+			return scannedCommit, nil
+		}
+	}
+	return nil, nil
+}
+
+// Returns the projects with a working directory that contains any of the modified files
+func getChangedProjects(projects []utils.Project, modifiedFiles []string) (changedProjects []utils.Project) {
+	for _, project := range projects {
+		var changedWorkingDirs []string
+		for _, workingDir := range project.WorkingDirs {
+			if containsModifiedFile(workingDir, modifiedFiles) {
+				changedWorkingDirs = append(changedWorkingDirs, workingDir)
+			}
+		}
+		if len(changedWorkingDirs) > 0 {
+			project.WorkingDirs = changedWorkingDirs
+			changedProjects = append(changedProjects, project)
+		}
+	}
+	return
+}
+
+func containsModifiedFile(workingDir string, modifiedFiles []string) bool {
+	workingDir = path.Clean(strings.TrimPrefix(strings.ReplaceAll(workingDir, "\\", "/"), "/"))
+	if workingDir == "." {
+		return len(modifiedFiles) > 0
+	}
+	for _, modifiedFile := range modifiedFiles {
+		if strings.HasPrefix(strings.TrimPrefix(modifiedFile, "/"), workingDir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if none of the projects changed since the previous scan, so the comments of the previous scan still describe the pull request
+func (is *incrementalScan) isUpToDate() bool {
+	return is != nil && is.previousCommit != nil && len(is.changedProjects) == 0
+}
+
+// Returns the projects to scan. Only the changed projects are scanned if the previous scan reported no issues,
+// since the projects that didn't change have no issues to report either.
+func (is *incrementalScan) projectsToScan(projects []utils.Project) []utils.Project {
+	if is == nil || is.previousCommit == nil || is.previousCommit.Status != outputwriter.ScannedCommitClean {
+		return projects
+	}
+	log.Info(fmt.Sprintf("Scanning only the %d projects that changed since commit %s of the previous scan", len(is.changedProjects), is.previousCommit.Hash))
+	return is.changedProjects
+}
+
+// Returns the error of the skipped scan, which fails the task if the previous scan failed it
+func (is *incrementalScan) previousScanError() error {
+	if is.previousCommit.Status == outputwriter.ScannedCommitFailed {
+		return &utils.ErrPolicyFailure{Message: previousScanIssuesFoundErr}
+	}
+	return nil
+}
+
+// Returns the scanned commit to record in the summary comment, or nil if the latest commit of the source branch is unknown
+func (is *incrementalScan) scannedCommit(issuesReported, failed bool) *outputwriter.ScannedCommit {
+	if is == nil {
+		return nil
+	}
+	status := outputwriter.ScannedCommitClean
+	switch {
+	case failed:
+		status = outputwriter.ScannedCommitFailed
+	case issuesReported:
+		status = outputwriter.ScannedCommitIssues
+	}
+	return &outputwriter.ScannedCommit{Hash: is.sourceCommit, Status: status, TargetHash: is.targetCommit, ConfigHash: is.configHash}
+}
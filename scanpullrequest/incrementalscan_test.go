@@ -0,0 +1,138 @@
+package scanpullrequest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/policy"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIncrementalScan(t *testing.T) {
+	projects := []utils.Project{{WorkingDirs: []string{"frontend", "backend"}}, {WorkingDirs: []string{"services/api"}}}
+	// The user of the Git token is the author of the note with ID 2, which records the scanned commit
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := `[{"id":1,"author":{"id":8}},{"id":2,"author":{"id":7}},{"id":3,"author":{"id":8}}]`
+		if r.URL.Path == "/user" {
+			response = `{"id":7}`
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	newRepo := func() *utils.Repository {
+		repo := &utils.Repository{Params: utils.Params{
+			Scan: utils.Scan{Projects: projects},
+			Git:  utils.Git{GitProvider: vcsutils.GitLab, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}},
+		}}
+		repo.PullRequestDetails = vcsclient.PullRequestInfo{ID: 3, Source: vcsclient.BranchInfo{Owner: "jfrog", Repository: "frogbot", Name: "feature"}, Target: vcsclient.BranchInfo{Owner: "jfrog", Repository: "frogbot", Name: "main"}}
+		return repo
+	}
+	configHash := getConfigHash(newRepo())
+	previousCommit := func(hash string, status outputwriter.ScannedCommitStatus) *outputwriter.ScannedCommit {
+		return &outputwriter.ScannedCommit{Hash: hash, Status: status, TargetHash: "target", ConfigHash: configHash}
+	}
+
+	testCases := []struct {
+		name                    string
+		previousCommit          *outputwriter.ScannedCommit
+		forgedCommit            *outputwriter.ScannedCommit
+		modifiedFiles           []string
+		modifiedFilesErr        error
+		expectedUpToDate        bool
+		expectedProjectsToScan  []utils.Project
+		expectedPreviousScanErr bool
+	}{
+		{name: "Not scanned before", expectedProjectsToScan: projects},
+		{name: "Unchanged source branch", previousCommit: previousCommit("head", outputwriter.ScannedCommitIssues), expectedUpToDate: true},
+		{name: "Unchanged source branch of failed scan", previousCommit: previousCommit("head", outputwriter.ScannedCommitFailed), expectedUpToDate: true, expectedPreviousScanErr: true},
+		{name: "Unchanged projects", previousCommit: previousCommit("previous", outputwriter.ScannedCommitFailed), modifiedFiles: []string{"README.md", "services/web/index.html"}, expectedUpToDate: true, expectedPreviousScanErr: true},
+		{name: "Changed project of clean scan", previousCommit: previousCommit("previous", outputwriter.ScannedCommitClean), modifiedFiles: []string{"backend/go.mod", "README.md"}, expectedProjectsToScan: []utils.Project{{WorkingDirs: []string{"backend"}}}},
+		{name: "Changed project of scan with issues", previousCommit: previousCommit("previous", outputwriter.ScannedCommitIssues), modifiedFiles: []string{"services/api/pom.xml"}, expectedProjectsToScan: projects},
+		{name: "Unknown changes", previousCommit: previousCommit("previous", outputwriter.ScannedCommitClean), modifiedFilesErr: errors.New("no merge base"), expectedProjectsToScan: projects},
+		{name: "Changed target branch", previousCommit: &outputwriter.ScannedCommit{Hash: "head", Status: outputwriter.ScannedCommitClean, TargetHash: "previous-target", ConfigHash: configHash}, expectedProjectsToScan: projects},
+		{name: "Changed configuration", previousCommit: &outputwriter.ScannedCommit{Hash: "head", Status: outputwriter.ScannedCommitClean, TargetHash: "target", ConfigHash: "previous"}, expectedProjectsToScan: projects},
+		{name: "Scanned commit of earlier version", previousCommit: &outputwriter.ScannedCommit{Hash: "head", Status: outputwriter.ScannedCommitClean}, expectedProjectsToScan: projects},
+		{name: "Scanned commit added by another user", forgedCommit: previousCommit("head", outputwriter.ScannedCommitClean), expectedProjectsToScan: projects},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newRepo()
+			client := testdata.NewMockVcsClient(gomock.NewController(t))
+			client.EXPECT().GetLatestCommit(context.Background(), "jfrog", "frogbot", "feature").Return(vcsclient.CommitInfo{Hash: "head"}, nil)
+			client.EXPECT().GetLatestCommit(context.Background(), "jfrog", "frogbot", "main").Return(vcsclient.CommitInfo{Hash: "target"}, nil)
+			comments := []vcsclient.CommentInfo{{ID: 1, Content: "LGTM"}}
+			if tc.previousCommit != nil {
+				comments = append(comments, vcsclient.CommentInfo{ID: 2, Content: outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + outputwriter.ScannedCommitComment(*tc.previousCommit)})
+				if tc.previousCommit.Hash != "head" {
+					client.EXPECT().GetModifiedFiles(context.Background(), "jfrog", "frogbot", tc.previousCommit.Hash, "head").Return(tc.modifiedFiles, tc.modifiedFilesErr)
+				}
+			}
+			if tc.forgedCommit != nil {
+				comments = append(comments, vcsclient.CommentInfo{ID: 3, Content: outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + outputwriter.ScannedCommitComment(*tc.forgedCommit)})
+			}
+			client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 3).Return(comments, nil)
+
+			incremental := getIncrementalScan(repo, client)
+			require.NotNil(t, incremental)
+			assert.Equal(t, tc.expectedUpToDate, incremental.isUpToDate())
+			if tc.expectedUpToDate {
+				assert.Equal(t, tc.expectedPreviousScanErr, incremental.previousScanError() != nil)
+				return
+			}
+			assert.Equal(t, tc.expectedProjectsToScan, incremental.projectsToScan(projects))
+		})
+	}
+}
+
+func TestGetConfigHash(t *testing.T) {
+	repo := &utils.Repository{Params: utils.Params{Scan: utils.Scan{MinSeverity: "High"}}}
+	configHash := getConfigHash(repo)
+	assert.Len(t, configHash, configHashLength)
+	// The SMTP credentials don't change the results of the scan
+	repo.SmtpPassword = "password"
+	assert.Equal(t, configHash, getConfigHash(repo))
+	repo.Policy = &policy.Policy{MaxSeverity: "Medium"}
+	assert.NotEqual(t, configHash, getConfigHash(repo))
+	repo.Policy = nil
+	repo.MinSeverity = "Medium"
+	assert.NotEqual(t, configHash, getConfigHash(repo))
+}
+
+func TestGetIncrementalScanWithoutLatestCommit(t *testing.T) {
+	repo := &utils.Repository{}
+	repo.PullRequestDetails = vcsclient.PullRequestInfo{Source: vcsclient.BranchInfo{Owner: "jfrog", Repository: "frogbot", Name: "feature"}}
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	client.EXPECT().GetLatestCommit(context.Background(), "jfrog", "frogbot", "feature").Return(vcsclient.CommitInfo{}, errors.New("not found"))
+	incremental := getIncrementalScan(repo, client)
+	assert.Nil(t, incremental)
+	assert.False(t, incremental.isUpToDate())
+	assert.Nil(t, incremental.scannedCommit(true, false))
+}
+
+func TestIncrementalScanScannedCommit(t *testing.T) {
+	incremental := &incrementalScan{sourceCommit: "head", targetCommit: "target", configHash: "5a1c7e9f3b2d"}
+	assert.Equal(t, &outputwriter.ScannedCommit{Hash: "head", Status: outputwriter.ScannedCommitClean, TargetHash: "target", ConfigHash: "5a1c7e9f3b2d"}, incremental.scannedCommit(false, false))
+	assert.Equal(t, &outputwriter.ScannedCommit{Hash: "head", Status: outputwriter.ScannedCommitIssues, TargetHash: "target", ConfigHash: "5a1c7e9f3b2d"}, incremental.scannedCommit(true, false))
+	assert.Equal(t, &outputwriter.ScannedCommit{Hash: "head", Status: outputwriter.ScannedCommitFailed, TargetHash: "target", ConfigHash: "5a1c7e9f3b2d"}, incremental.scannedCommit(true, true))
+}
+
+func TestContainsModifiedFile(t *testing.T) {
+	modifiedFiles := []string{"services/api/pom.xml", "README.md"}
+	assert.True(t, containsModifiedFile(".", modifiedFiles))
+	assert.True(t, containsModifiedFile("services", modifiedFiles))
+	assert.True(t, containsModifiedFile("./services/api/", modifiedFiles))
+	assert.False(t, containsModifiedFile("services/ap", modifiedFiles))
+	assert.False(t, containsModifiedFile("frontend", modifiedFiles))
+	assert.False(t, containsModifiedFile(".", nil))
+}
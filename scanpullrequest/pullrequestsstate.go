@@ -0,0 +1,117 @@
+package scanpullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The state of a scanned pull request is the latest commit of its source branch, at the time of its last successful scan
+type pullRequestState struct {
+	HeadCommit string    `json:"headCommit"`
+	ScanTime   time.Time `json:"scanTime"`
+}
+
+// The states of the scanned pull requests of each repository, by their IDs.
+// Pull requests without new commits since their last scan aren't scanned again.
+type pullRequestsState map[string]map[string]pullRequestState
+
+// Loads the states of the pull requests. A missing state file means that no pull request was scanned yet.
+func loadPullRequestsState(stateFile string) (pullRequestsState, error) {
+	state := pullRequestsState{}
+	content, err := os.ReadFile(stateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse the pull requests state file '%s': %s", stateFile, err.Error())
+	}
+	return state, nil
+}
+
+// Returns the time of the latest pull request scan of each repository in the state file, by the owner and the name of the repository, such as 'jfrog/frogbot'
+func LoadLastScanTimes(stateFile string) (map[string]time.Time, error) {
+	state, err := loadPullRequestsState(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	lastScanTimes := map[string]time.Time{}
+	for repositoryKey, repositoryStates := range state {
+		for _, pullRequestState := range repositoryStates {
+			if pullRequestState.ScanTime.After(lastScanTimes[repositoryKey]) {
+				lastScanTimes[repositoryKey] = pullRequestState.ScanTime
+			}
+		}
+	}
+	return lastScanTimes, nil
+}
+
+func (prs pullRequestsState) write(stateFile string) error {
+	content, err := json.MarshalIndent(prs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		return err
+	}
+	log.Debug("Writing the pull requests state to:", stateFile)
+	return os.WriteFile(stateFile, content, 0644)
+}
+
+func (prs pullRequestsState) get(repo *utils.Repository, pullRequestId int64) (state pullRequestState, exists bool) {
+	state, exists = prs[getRepositoryKey(repo)][strconv.FormatInt(pullRequestId, 10)]
+	return
+}
+
+func (prs pullRequestsState) set(repo *utils.Repository, pullRequestId int64, headCommit string) {
+	repositoryKey := getRepositoryKey(repo)
+	if prs[repositoryKey] == nil {
+		prs[repositoryKey] = map[string]pullRequestState{}
+	}
+	prs[repositoryKey][strconv.FormatInt(pullRequestId, 10)] = pullRequestState{HeadCommit: headCommit, ScanTime: time.Now()}
+}
+
+// Removes the states of the pull requests that are no longer open
+func (prs pullRequestsState) prune(repo *utils.Repository, openPullRequests []vcsclient.PullRequestInfo) {
+	repositoryStates := prs[getRepositoryKey(repo)]
+	for pullRequestId := range repositoryStates {
+		isOpen := false
+		for _, pr := range openPullRequests {
+			if strconv.FormatInt(pr.ID, 10) == pullRequestId {
+				isOpen = true
+				break
+			}
+		}
+		if !isOpen {
+			delete(repositoryStates, pullRequestId)
+		}
+	}
+}
+
+func getRepositoryKey(repo *utils.Repository) string {
+	return repo.RepoOwner + "/" + repo.RepoName
+}
+
+// Returns the latest commit of the source branch of the pull request, which may be in a fork of the repository
+func getHeadCommit(repo *utils.Repository, client vcsclient.VcsClient, pr vcsclient.PullRequestInfo) (string, error) {
+	owner, repository := pr.Source.Owner, pr.Source.Repository
+	if owner == "" || repository == "" {
+		owner, repository = repo.RepoOwner, repo.RepoName
+	}
+	commit, err := client.GetLatestCommit(context.Background(), owner, repository, pr.Source.Name)
+	if err != nil {
+		return "", err
+	}
+	return commit.Hash, nil
+}
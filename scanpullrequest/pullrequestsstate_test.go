@@ -0,0 +1,82 @@
+package scanpullrequest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestsState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state", "frogbot-pull-requests.json")
+	// A missing state file means that no pull request was scanned yet
+	state, err := loadPullRequestsState(stateFile)
+	require.NoError(t, err)
+	assert.Empty(t, state)
+
+	state.set(gitParams, 1, "commit-1")
+	state.set(gitParams, 2, "commit-2")
+	require.NoError(t, state.write(stateFile))
+	state, err = loadPullRequestsState(stateFile)
+	require.NoError(t, err)
+	prState, exists := state.get(gitParams, 1)
+	assert.True(t, exists)
+	assert.Equal(t, "commit-1", prState.HeadCommit)
+	assert.False(t, prState.ScanTime.IsZero())
+
+	// Closed pull requests are removed
+	state.prune(gitParams, []vcsclient.PullRequestInfo{{ID: 2}})
+	_, exists = state.get(gitParams, 1)
+	assert.False(t, exists)
+	_, exists = state.get(gitParams, 2)
+	assert.True(t, exists)
+
+	require.NoError(t, os.WriteFile(stateFile, []byte("invalid"), 0644))
+	_, err = loadPullRequestsState(stateFile)
+	assert.ErrorContains(t, err, "failed to parse the pull requests state file")
+}
+
+func TestShouldScanPullRequestByState(t *testing.T) {
+	pr := vcsclient.PullRequestInfo{ID: 1, Source: vcsclient.BranchInfo{Name: "feature", Owner: "fork-owner", Repository: gitParams.RepoName}}
+	frogbotComment := []vcsclient.CommentInfo{{Content: outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + outputwriter.MarkAsBold(outputwriter.GetSimplifiedTitle(outputwriter.NoVulnerabilityPrBannerSource)), Created: time.Unix(1, 0)}}
+	testCases := []struct {
+		name            string
+		previousCommit  string
+		comments        []vcsclient.CommentInfo
+		expectComments  bool
+		expectedScan    bool
+		expectedInState bool
+	}{
+		{name: "New commits", previousCommit: "old-commit", expectedScan: true, expectedInState: true},
+		{name: "No new commits", previousCommit: "head-commit", comments: frogbotComment, expectComments: true, expectedInState: true},
+		{name: "No new commits, rescan requested", previousCommit: "head-commit", comments: append(frogbotComment, vcsclient.CommentInfo{Content: utils.RescanRequestComment, Created: time.Unix(2, 0)}), expectComments: true, expectedScan: true, expectedInState: true},
+		{name: "No state, scanned before", comments: frogbotComment, expectComments: true, expectedInState: true},
+		{name: "No state, new pull request", expectComments: true, expectedScan: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := CreateMockVcsClient(t)
+			client.EXPECT().GetLatestCommit(context.Background(), "fork-owner", gitParams.RepoName, "feature").Return(vcsclient.CommitInfo{Hash: "head-commit"}, nil)
+			if tc.expectComments {
+				client.EXPECT().ListPullRequestComments(context.Background(), gitParams.RepoOwner, gitParams.RepoName, 1).Return(tc.comments, nil)
+			}
+			state := pullRequestsState{}
+			if tc.previousCommit != "" {
+				state.set(gitParams, pr.ID, tc.previousCommit)
+			}
+			shouldScan, headCommit, err := shouldScanPullRequestByState(*gitParams, client, state, pr)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedScan, shouldScan)
+			assert.Equal(t, "head-commit", headCommit)
+			_, exists := state.get(gitParams, pr.ID)
+			assert.Equal(t, tc.expectedInState, exists)
+		})
+	}
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/jfrog/froggit-go/vcsclient"
 )
 
-var errPullRequestScan = "pull request #%d scan in the '%s' repository returned the following error:\n%s"
+var errPullRequestScan = "pull request #%d scan in the '%s' repository returned the following error:\n%w"
 
 type ScanAllPullRequestsCmd struct {
 }
@@ -32,18 +32,40 @@ func (cmd ScanAllPullRequestsCmd) Run(configAggregator utils.RepoAggregator, cli
 
 // Scan pull requests as follows:
 // a. Retrieve all open pull requests
-// b. Find the ones that should be scanned (new PRs or PRs with a 're-scan' comment)
+// b. Find the ones that should be scanned (new PRs, PRs with new commits since their last scan if a state file is used, or PRs with a 're-scan' comment)
 // c. Audit the dependencies of the source and the target branches.
 // d. Compare the vulnerabilities found in source and target branches, and show only the new vulnerabilities added by the pull request.
+// If the rate limit of the Git provider is about to be exhausted and doesn't reset soon enough, the remaining pull requests are left to the next run.
 func scanAllPullRequests(repo utils.Repository, client vcsclient.VcsClient) (err error) {
 	openPullRequests, err := client.ListOpenPullRequests(context.Background(), repo.RepoOwner, repo.RepoName)
 	if err != nil {
 		return err
 	}
+	var state pullRequestsState
+	if repo.PullRequestsStateFile != "" {
+		if state, err = loadPullRequestsState(repo.PullRequestsStateFile); err != nil {
+			return
+		}
+		defer func() {
+			state.prune(&repo, openPullRequests)
+			err = errors.Join(err, state.write(repo.PullRequestsStateFile))
+		}()
+	}
 	for _, pr := range openPullRequests {
-		shouldScan, e := shouldScanPullRequest(repo, client, int(pr.ID))
+		if e := utils.WaitForRateLimit(repo.RateLimitThreshold, repo.RateLimitMaxWait); e != nil {
+			log.Warn(fmt.Sprintf("Stopped scanning the pull requests of the '%s' repository, since %s. The remaining pull requests are scanned in the next run.", repo.RepoName, e.Error()))
+			return
+		}
+		var shouldScan bool
+		var headCommit string
+		var e error
+		if state != nil {
+			shouldScan, headCommit, e = shouldScanPullRequestByState(repo, client, state, pr)
+		} else {
+			shouldScan, e = shouldScanPullRequest(repo, client, int(pr.ID))
+		}
 		if e != nil {
-			err = errors.Join(err, fmt.Errorf(errPullRequestScan, int(pr.ID), repo.RepoName, e.Error()))
+			err = errors.Join(err, fmt.Errorf(errPullRequestScan, int(pr.ID), repo.RepoName, e))
 		}
 		if !shouldScan {
 			log.Info("Pull Request", pr.ID, "has already been scanned before. If you wish to scan it again, please comment \"rescan\".")
@@ -52,7 +74,11 @@ func scanAllPullRequests(repo utils.Repository, client vcsclient.VcsClient) (err
 		repo.PullRequestDetails = pr
 		if e = scanPullRequest(&repo, client); e != nil {
 			// If error, write it in errList and continue to the next PR.
-			err = errors.Join(err, fmt.Errorf(errPullRequestScan, int(pr.ID), repo.RepoName, e.Error()))
+			err = errors.Join(err, fmt.Errorf(errPullRequestScan, int(pr.ID), repo.RepoName, e))
+			continue
+		}
+		if state != nil && headCommit != "" {
+			state.set(&repo, pr.ID, headCommit)
 		}
 	}
 	return
@@ -77,3 +103,27 @@ func shouldScanPullRequest(repo utils.Repository, client vcsclient.VcsClient, pr
 	// This is a new pull request, and it therefore should be scanned.
 	return true, nil
 }
+
+// Pull requests are scanned if their source branch has new commits since their last scan, or if a rescan was requested.
+// Pull requests without a state, like the ones that were scanned before the state file was used, are scanned according to their comments.
+// The head commit is returned, so the state of the pull request can be updated after the scan.
+func shouldScanPullRequestByState(repo utils.Repository, client vcsclient.VcsClient, state pullRequestsState, pr vcsclient.PullRequestInfo) (shouldScan bool, headCommit string, err error) {
+	if headCommit, err = getHeadCommit(&repo, client, pr); err != nil {
+		// Without the head commit, the pull request is scanned according to its comments
+		shouldScan, _ = shouldScanPullRequest(repo, client, int(pr.ID))
+		return
+	}
+	previousState, exists := state.get(&repo, pr.ID)
+	if exists && previousState.HeadCommit != headCommit {
+		log.Debug(fmt.Sprintf("Pull Request %d has new commits since its last scan", pr.ID))
+		return true, headCommit, nil
+	}
+	if shouldScan, err = shouldScanPullRequest(repo, client, int(pr.ID)); err != nil || shouldScan {
+		return
+	}
+	if !exists {
+		// The pull request was scanned before the state file was used, so its current head commit is considered scanned
+		state.set(&repo, pr.ID, headCommit)
+	}
+	return
+}
@@ -2,8 +2,10 @@ package scanpullrequest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-security/utils/xsc"
+	"net/http"
 	"path/filepath"
 	"testing"
 	"time"
@@ -219,6 +221,10 @@ func getMockClient(t *testing.T, frogbotMessages *[]string, mockParams ...MockPa
 		// Return empty comments slice so expect the code to scan both pull requests.
 		client.EXPECT().ListPullRequestComments(context.Background(), params.repoOwner, params.repoName, gomock.Any()).Return([]vcsclient.CommentInfo{}, nil).AnyTimes()
 		client.EXPECT().ListPullRequestReviewComments(context.Background(), params.repoOwner, params.repoName, gomock.Any()).Return([]vcsclient.CommentInfo{}, nil).AnyTimes()
+		// The repositories have no policy, baseline and alternative packages files
+		client.EXPECT().DownloadFileFromRepo(context.Background(), params.repoOwner, params.repoName, gomock.Any(), utils.PolicyFilePath).Return(nil, http.StatusNotFound, errors.New("file not found")).AnyTimes()
+		client.EXPECT().DownloadFileFromRepo(context.Background(), params.repoOwner, params.repoName, gomock.Any(), utils.AlternativePackagesFilePath).Return(nil, http.StatusNotFound, errors.New("file not found")).AnyTimes()
+		client.EXPECT().DownloadFileFromRepo(context.Background(), params.repoOwner, params.repoName, gomock.Any(), utils.BaselineFilePath).Return(nil, http.StatusNotFound, errors.New("file not found")).AnyTimes()
 		// Copy test project according to the given branch name, instead of download it.
 		client.EXPECT().DownloadRepository(context.Background(), params.repoOwner, params.repoName, gomock.Any(), gomock.Any()).DoAndReturn(fakeRepoDownload).AnyTimes()
 		// Capture the result comment post
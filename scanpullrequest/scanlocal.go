@@ -0,0 +1,126 @@
+package scanpullrequest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/policy"
+	"github.com/jfrog/frogbot/v2/utils/report"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// ScanLocalCmd scans the current directory the same way scan-pull-request scans a pull request when all the issues are reported, and prints the results to the terminal.
+// Only the JFrog platform details are required, so developers can run it before pushing their changes, for example as a pre-commit hook.
+// The policy and the baseline files of the current directory apply to the scan.
+type ScanLocalCmd struct {
+	// The writer the results are printed to, the standard output by default
+	output io.Writer
+}
+
+func (cmd *ScanLocalCmd) Run() (err error) {
+	repoConfig, err := utils.GetLocalScanRepository()
+	if err != nil {
+		return
+	}
+	// The server configuration is required by the scanners, as in the other commands
+	originalJfrogHomeDir, tempJFrogHomeDir, err := utils.BuildServerConfigFile(&repoConfig.Server)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, os.Setenv(utils.JfrogHomeDirEnv, originalJfrogHomeDir), fileutils.RemoveTempDir(tempJFrogHomeDir))
+	}()
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	log.Info("Scanning the local directory:", currentDir)
+	issuesCollection, resultContext, err := scanLocalDirectory(repoConfig, currentDir)
+	if err != nil {
+		return
+	}
+	scanReport := &report.ScanReport{
+		Subject:        currentDir,
+		Issues:         *issuesCollection,
+		ResultContext:  resultContext,
+		IncludeSecrets: repoConfig.PullRequestSecretComments,
+		Writer:         repoConfig.OutputWriter,
+	}
+	output := cmd.output
+	if output == nil {
+		output = os.Stdout
+	}
+	// The report is rendered by the terminal writer of the repository, as plain text
+	if _, err = fmt.Fprintln(output, scanReport.MarkdownContent()); err != nil {
+		return
+	}
+	if toFailTaskStatus(repoConfig, issuesCollection) {
+		err = &utils.ErrPolicyFailure{Message: SecurityIssueFoundErr}
+	}
+	return
+}
+
+// Audits the local directory, and applies its policy and baseline files to the issues
+func scanLocalDirectory(repoConfig *utils.Repository, localDir string) (issuesCollection *issues.ScansIssuesCollection, resultContext results.ResultContext, err error) {
+	if repoConfig.Policy, err = readLocalPolicy(localDir); err != nil {
+		return
+	}
+	baseline, err := readLocalBaseline(localDir)
+	if err != nil {
+		return
+	}
+	repoConfig.LocalSourceDir = localDir
+	repoConfig.IncludeAllVulnerabilities = true
+	if issuesCollection, resultContext, err = auditPullRequest(repoConfig, nil); err != nil {
+		return
+	}
+	baseline.FilterIssues(issuesCollection)
+	issuesCollection.PolicyRuleViolations = repoConfig.Policy.Evaluate(issuesCollection, repoConfig.PullRequestSecretComments)
+	return
+}
+
+// Returns the blocking rules of the policy file of the local directory, or nil if it has no policy file
+func readLocalPolicy(localDir string) (*policy.Policy, error) {
+	content, err := readLocalFrogbotFile(localDir, utils.PolicyFilePath)
+	if err != nil || content == nil {
+		return nil, err
+	}
+	localPolicy, err := policy.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the %s file: %s", utils.PolicyFilePath, err.Error())
+	}
+	log.Info(fmt.Sprintf("The blocking rules of the %s file are enforced", utils.PolicyFilePath))
+	return localPolicy, nil
+}
+
+// Returns the baseline file of the local directory, or nil if it has no baseline file
+func readLocalBaseline(localDir string) (*utils.Baseline, error) {
+	content, err := readLocalFrogbotFile(localDir, utils.BaselineFilePath)
+	if err != nil || content == nil {
+		return nil, err
+	}
+	baseline, err := utils.ParseBaseline(content)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the %s file: %s", utils.BaselineFilePath, err.Error())
+	}
+	log.Info(fmt.Sprintf("The %d findings of the %s file aren't reported", len(baseline.Findings), utils.BaselineFilePath))
+	return baseline, nil
+}
+
+func readLocalFrogbotFile(localDir, relativePath string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(localDir, filepath.FromSlash(relativePath)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the %s file: %s", relativePath, err.Error())
+	}
+	return content, nil
+}
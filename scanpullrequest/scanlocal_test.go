@@ -0,0 +1,55 @@
+package scanpullrequest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLocalFrogbotFiles(t *testing.T) {
+	localDir := t.TempDir()
+	// A directory without Frogbot files has no policy and no baseline
+	localPolicy, err := readLocalPolicy(localDir)
+	require.NoError(t, err)
+	assert.Nil(t, localPolicy)
+	baseline, err := readLocalBaseline(localDir)
+	require.NoError(t, err)
+	assert.Nil(t, baseline)
+
+	writeLocalFrogbotFile(t, localDir, utils.PolicyFilePath, "maxSeverity: high\nbannedLicenses: [GPL-3.0]\n")
+	writeLocalFrogbotFile(t, localDir, utils.BaselineFilePath, `{"findings":[{"id":"sca-CVE-2022-1234","type":"sca"}]}`)
+	localPolicy, err = readLocalPolicy(localDir)
+	require.NoError(t, err)
+	require.NotNil(t, localPolicy)
+	assert.Equal(t, "High", localPolicy.MaxSeverity)
+	assert.Equal(t, []string{"GPL-3.0"}, localPolicy.BannedLicenses)
+	baseline, err = readLocalBaseline(localDir)
+	require.NoError(t, err)
+	require.NotNil(t, baseline)
+	assert.Len(t, baseline.Findings, 1)
+
+	writeLocalFrogbotFile(t, localDir, utils.BaselineFilePath, "not json")
+	_, err = readLocalBaseline(localDir)
+	assert.ErrorContains(t, err, "couldn't parse the "+utils.BaselineFilePath+" file")
+}
+
+func TestDownloadSourceBranchOfLocalScan(t *testing.T) {
+	localDir := t.TempDir()
+	scanDetails := &utils.ScanDetails{Git: &utils.Git{LocalSourceDir: localDir}}
+	sourceDir, cleanup, err := downloadSourceBranch(scanDetails)
+	require.NoError(t, err)
+	assert.Equal(t, localDir, sourceDir)
+	// The local directory isn't removed by the cleanup
+	require.NoError(t, cleanup())
+	assert.DirExists(t, localDir)
+}
+
+func writeLocalFrogbotFile(t *testing.T, localDir, relativePath, content string) {
+	filePath := filepath.Join(localDir, filepath.FromSlash(relativePath))
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+}
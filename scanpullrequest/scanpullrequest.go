@@ -5,9 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/dependencyconfusion"
+	"github.com/jfrog/frogbot/v2/utils/dependencyscope"
+	"github.com/jfrog/frogbot/v2/utils/externalsarif"
 	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/report"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/gofrog/datastructures"
@@ -22,6 +28,8 @@ import (
 
 const (
 	SecurityIssueFoundErr   = "issues were detected by Frogbot\n You can avoid marking the Frogbot scan as failed by setting failOnSecurityIssues to false in the " + utils.FrogbotConfigFile + " file"
+	SecretsFoundErr         = "new secrets were exposed in the pull request, and blockOnSecrets is set in the " + utils.FrogbotConfigFile + " file\n Rotate the exposed secrets and remove them from the pull request"
+	CurationBlockedErr      = "the pull request adds dependencies that the curation policies block, and failOnCurationBlocked is set in the " + utils.FrogbotConfigFile + " file\n Replace the blocked dependencies with versions that the policies allow"
 	noGitHubEnvErr          = "frogbot did not scan this PR, because a GitHub Environment named 'frogbot' does not exist. Please refer to the Frogbot documentation for instructions on how to create the Environment"
 	noGitHubEnvReviewersErr = "frogbot did not scan this PR, because the existing GitHub Environment named 'frogbot' doesn't have reviewers selected. Please refer to the Frogbot documentation for instructions on how to create the Environment"
 	analyticsScanPrScanType = "PR"
@@ -91,42 +99,197 @@ func scanPullRequest(repo *utils.Repository, client vcsclient.VcsClient) (err er
 		pullRequestDetails.Source.Owner, pullRequestDetails.Source.Repository, pullRequestDetails.Source.Name,
 		pullRequestDetails.Target.Owner, pullRequestDetails.Target.Repository, pullRequestDetails.Target.Name))
 	log.Info("-----------------------------------------------------------")
+	if utils.IsForkPullRequest(pullRequestDetails) {
+		forkRepository := pullRequestDetails.Source.Owner + "/" + pullRequestDetails.Source.Repository
+		if repo.DisallowForkPullRequests {
+			log.Info(fmt.Sprintf("Pull Request #%d is from the fork <%s>, and the scans of pull requests from forks are disallowed. Skipping...", pullRequestDetails.ID, forkRepository))
+			return
+		}
+		log.Info(fmt.Sprintf("Pull Request #%d is from the fork <%s>", pullRequestDetails.ID, forkRepository))
+		repo.OutputWriter.SetForkRepository(forkRepository)
+	}
+	defer func() {
+		setAzurePullRequestStatus(repo, int(pullRequestDetails.ID), err)
+	}()
+	// The policy file is read before the scan, as its rules may require the licenses of the dependencies,
+	// and before the changes since the previous scan are found, as all the projects are scanned when the policy changes
+	if repo.Policy, err = utils.GetPullRequestPolicy(repo, client); err != nil {
+		return
+	}
+	var incremental *incrementalScan
+	if repo.IncrementalScan {
+		if incremental = getIncrementalScan(repo, client); incremental.isUpToDate() {
+			log.Info(fmt.Sprintf("None of the projects changed since commit %s of the previous scan of Pull Request #%d. Skipping...", incremental.previousCommit.Hash, pullRequestDetails.ID))
+			return incremental.previousScanError()
+		}
+		allProjects := repo.Projects
+		repo.Projects = incremental.projectsToScan(allProjects)
+		defer func() {
+			repo.Projects = allProjects
+		}()
+	}
+	// Long scans leave the reviewers without a sign of Frogbot, so a progress comment is added until the results replace it
+	progressComment := utils.AddScanProgressComment(repo, client, int(pullRequestDetails.ID))
+	defer func() {
+		progressComment.Fail(err)
+	}()
+
+	// When all the issues are reported, the findings of the baseline file are accepted, so only the findings that were added since it was generated are reported
+	var baseline *utils.Baseline
+	if repo.IncludeAllVulnerabilities {
+		if baseline, err = utils.GetPullRequestBaseline(repo, client); err != nil {
+			return
+		}
+	}
 
 	// Audit PR code
+	scanStartTime := time.Now()
 	issues, resultContext, err := auditPullRequest(repo, client)
+	utils.RecordScanDuration(repo.RepoOwner+"/"+repo.RepoName, time.Since(scanStartTime))
 	if err != nil {
 		return
 	}
+	if repo.ShowRuntimeDetails {
+		repo.OutputWriter.SetRuntimeDetails(utils.NewRuntimeDetails(repo.XrayVersion, repo.XscVersion, scanStartTime))
+	}
+
+	baseline.FilterIssues(issues)
+	// The policy is evaluated before the suppressions are applied, so suppressing a finding in the discussions can't unblock the pull request
+	issues.PolicyRuleViolations = repo.Policy.Evaluate(issues, repo.PullRequestSecretComments)
+	// Findings that were suppressed in the pull request discussions aren't reported again, except for secrets and the findings that break the policy
+	suppressions, e := utils.GetPullRequestSuppressions(repo, int(pullRequestDetails.ID))
+	if e != nil {
+		log.Warn("Couldn't get the suppressed findings, so they may be reported again:", e.Error())
+	}
+	suppressions.FilterIssues(issues, repo.Policy)
+	// Failing to find the owners of the findings only skips their mentions
+	if ruleset, e := utils.GetPullRequestCodeOwners(repo, client); e != nil {
+		log.Warn("Couldn't get the owners of the paths of the repository, so they aren't mentioned:", e.Error())
+	} else {
+		issues.SecurityChampions = utils.GetSecurityChampions(ruleset, issues)
+	}
+	utils.RecordIssues(issues)
+	if repo.ExploitabilityEnrichment {
+		repo.OutputWriter.SetExploitability(utils.GetIssuesExploitability(issues))
+	}
+	if repo.ResearchEnrichment {
+		repo.OutputWriter.SetResearchDetails(utils.GetIssuesResearchDetails(&repo.Server, issues))
+	}
+	// Failing to read the alternative packages file only skips their suggestions
+	if alternativePackages, e := utils.GetPullRequestAlternativePackages(repo, client); e != nil {
+		log.Warn("Couldn't get the alternative packages, so they aren't suggested:", e.Error())
+	} else {
+		issues.AlternativePackages = alternativePackages.Suggest(slices.Concat(issues.ScaVulnerabilities, issues.ScaViolations), repo.OutputWriter.ResearchDetails())
+	}
 
 	// Output results
-	shouldSendExposedSecretsEmail := issues.SecretsIssuesExists() && repo.SmtpServer != ""
-	if shouldSendExposedSecretsEmail {
-		secretsEmailDetails := utils.NewSecretsEmailDetails(client, repo, append(issues.SecretsVulnerabilities, issues.SecretsViolations...))
-		if err = utils.AlertSecretsExposed(secretsEmailDetails); err != nil {
+	if repo.SmtpServer != "" {
+		if err = utils.NewEmailNotifier(client, repo).AlertIssues(issues); err != nil {
 			return
 		}
 	}
 
+	scanReport := &report.ScanReport{
+		Subject:        fmt.Sprintf("%s/%s pull request #%d", repo.RepoOwner, repo.RepoName, pullRequestDetails.ID),
+		Issues:         *issues,
+		ResultContext:  resultContext,
+		IncludeSecrets: repo.PullRequestSecretComments,
+		Writer:         repo.OutputWriter,
+	}
+	// The results are shown in the workflow run page of GitHub Actions, even if the comments can't be added
+	utils.WriteJobSummaryIfNeeded(scanReport)
+
+	// The result of the scan is recorded in the summary comment of the incremental scans, so it's determined before the comments are added
+	scanFailure := getScanFailure(repo, issues)
+	if repo.IncrementalScan {
+		repo.OutputWriter.SetScannedCommit(incremental.scannedCommit(utils.IsReportingPullRequestIssues(issues, repo), scanFailure != nil))
+	}
+
 	// Handle PR comments for scan output
-	if err = utils.HandlePullRequestCommentsAfterScan(issues, resultContext, repo, client, int(pullRequestDetails.ID)); err != nil {
+	if err = utils.HandlePullRequestCommentsAfterScan(issues, resultContext, repo, client, int(pullRequestDetails.ID), suppressions); err != nil {
 		return
 	}
+	progressComment.Remove()
+	publishCodeInsightsReport(repo, client, issues)
+
+	// Write the scan report file, so the CI can upload it as a build artifact
+	if repo.ReportPath != "" {
+		if err = scanReport.Write(repo.ReportPath); err != nil {
+			return
+		}
+	}
 
+	err = scanFailure
+	return
+}
+
+// Returns the error that fails the Frogbot task because of the issues of the pull request, or nil if the issues don't fail it
+func getScanFailure(repo *utils.Repository, issues *issues.ScansIssuesCollection) error {
+	// Exposed secrets are compromised even if the pull request isn't merged, so they fail the task regardless of the fail flag and the onboarding period
+	if repo.BlockOnSecrets && issues.SecretsIssuesExists() {
+		return &utils.ErrPolicyFailure{Message: SecretsFoundErr}
+	}
+	// The blocked dependencies can't be installed once the pull request is merged, so they fail the task regardless of the fail flag of the security issues
+	if repo.FailOnCurationBlocked && issues.CurationBlockedPackagesExists() {
+		return &utils.ErrPolicyFailure{Message: CurationBlockedErr}
+	}
 	// Fail the Frogbot task if a security issue is found and Frogbot isn't configured to avoid the failure.
 	if toFailTaskStatus(repo, issues) {
-		err = errors.New(SecurityIssueFoundErr)
-		return
+		return &utils.ErrPolicyFailure{Message: SecurityIssueFoundErr}
 	}
-	return
+	return nil
 }
 
 func toFailTaskStatus(repo *utils.Repository, issues *issues.ScansIssuesCollection) bool {
 	failFlagSet := repo.FailOnSecurityIssues != nil && *repo.FailOnSecurityIssues
-	return failFlagSet && issues.IssuesExists(repo.PullRequestSecretComments)
+	// Breaking the blocking rules of the policy file fails the task regardless of the fail flag
+	if (!failFlagSet || !blockingIssuesExist(repo, issues)) && !issues.PolicyRuleViolationsExists() {
+		return false
+	}
+	if repo.IsInOnboardingPeriod() {
+		log.Info(fmt.Sprintf("Security issues were found, but the task will not fail until %s, since Frogbot is in its onboarding period", repo.FailAfterDate))
+		return false
+	}
+	return true
+}
+
+// When only applicable CVEs fail the task, the SCA vulnerabilities and violations that aren't applicable according to the contextual analysis are reported without failing it.
+// When development dependencies are skipped, the SCA vulnerabilities and violations that only development and test dependencies bring in are reported without failing it.
+func blockingIssuesExist(repo *utils.Repository, issues *issues.ScansIssuesCollection) bool {
+	if repo.SkipDevDependencies {
+		issues = withoutDevDependencies(issues, repo.OutputWriter.DependencyScopes())
+	}
+	if !repo.FailOnApplicableOnly {
+		return issues.IssuesExists(repo.PullRequestSecretComments)
+	}
+	if issues.PossiblyApplicableScaIssuesExists() || len(issues.LicensesViolations) > 0 || issues.IacIssuesExists() || issues.SastIssuesExists() || (repo.PullRequestSecretComments && issues.SecretsIssuesExists()) {
+		return true
+	}
+	if issues.IssuesExists(repo.PullRequestSecretComments) {
+		log.Info("Security issues were found, but the task will not fail, since all the vulnerable CVEs are not applicable")
+	}
+	return false
+}
+
+// Returns a copy of the issues without the SCA vulnerabilities and violations that only development and test dependencies bring in
+func withoutDevDependencies(issuesCollection *issues.ScansIssuesCollection, scopes dependencyscope.Scopes) *issues.ScansIssuesCollection {
+	productionIssues := *issuesCollection
+	isProduction := func(issue formats.VulnerabilityOrViolationRow) bool {
+		return scopes.GetScope(issue.Components).IsProduction()
+	}
+	productionIssues.ScaVulnerabilities = slices.DeleteFunc(slices.Clone(issuesCollection.ScaVulnerabilities), func(issue formats.VulnerabilityOrViolationRow) bool { return !isProduction(issue) })
+	productionIssues.ScaViolations = slices.DeleteFunc(slices.Clone(issuesCollection.ScaViolations), func(issue formats.VulnerabilityOrViolationRow) bool { return !isProduction(issue) })
+	if skipped := len(issuesCollection.ScaVulnerabilities) + len(issuesCollection.ScaViolations) - len(productionIssues.ScaVulnerabilities) - len(productionIssues.ScaViolations); skipped > 0 {
+		log.Info(fmt.Sprintf("%d vulnerable dependencies don't fail the task, since only development and test dependencies bring them in", skipped))
+	}
+	return &productionIssues
 }
 
 // Downloads Pull Requests branches code and audits them
 func auditPullRequest(repoConfig *utils.Repository, client vcsclient.VcsClient) (issuesCollection *issues.ScansIssuesCollection, resultContext results.ResultContext, err error) {
+	if err = utils.ValidateRepositoryViolationsContext(repoConfig); err != nil {
+		return
+	}
 	repositoryCloneUrl, err := repoConfig.GetRepositoryHttpsCloneUrl(client)
 	if err != nil {
 		return
@@ -134,12 +297,14 @@ func auditPullRequest(repoConfig *utils.Repository, client vcsclient.VcsClient)
 
 	scanDetails := utils.NewScanDetails(client, &repoConfig.Server, &repoConfig.Git).
 		SetJfrogVersions(repoConfig.XrayVersion, repoConfig.XscVersion).
-		SetResultsContext(repositoryCloneUrl, repoConfig.Watches, repoConfig.JFrogProjectKey, repoConfig.IncludeVulnerabilities, len(repoConfig.AllowedLicenses) > 0).
+		SetResultsContext(repositoryCloneUrl, repoConfig.Watches, repoConfig.JFrogProjectKey, repoConfig.IncludeVulnerabilities, len(repoConfig.AllowedLicenses) > 0 || repoConfig.Policy.RequiresLicenses()).
 		SetFixableOnly(repoConfig.FixableOnly).
 		SetFailOnInstallationErrors(*repoConfig.FailOnSecurityIssues).
 		SetConfigProfile(repoConfig.ConfigProfile).
 		SetSkipAutoInstall(repoConfig.SkipAutoInstall).
-		SetDisableJas(repoConfig.DisableJas)
+		SetTargetCves(repoConfig.GetScannedTargetCves()).
+		SetDisableJas(repoConfig.DisableJas).
+		SetJasScanners(repoConfig.Jas)
 
 	if scanDetails, err = scanDetails.SetMinSeverity(repoConfig.MinSeverity); err != nil {
 		return
@@ -158,27 +323,56 @@ func auditPullRequest(repoConfig *utils.Repository, client vcsclient.VcsClient)
 		}
 	}()
 
+	dependencyConfusionAnalyzer := dependencyconfusion.NewAnalyzer(repoConfig.InternalNamespaces)
+	analyzers, err := newSourceBranchAnalyzers(repoConfig, scanDetails)
+	if err != nil {
+		return
+	}
 	issuesCollection = &issues.ScansIssuesCollection{}
 	for i := range repoConfig.Projects {
 		scanDetails.SetProject(&repoConfig.Projects[i])
+		// The comments report violations if any of the projects is scanned with watches or a JFrog project
+		if i == 0 || !resultContext.HasViolationContext() {
+			resultContext = scanDetails.ResultContext
+		}
 		var projectIssues *issues.ScansIssuesCollection
-		if projectIssues, err = auditPullRequestInProject(repoConfig, scanDetails); err != nil {
+		if projectIssues, err = auditPullRequestInProject(repoConfig, scanDetails, dependencyConfusionAnalyzer, analyzers); err != nil {
 			if projectIssues != nil {
 				// Make sure status on scans are passed to show in the summary
 				issuesCollection.AppendStatus(projectIssues.ScanStatus)
 			}
 			return
 		}
+		utils.FilterIssuesByTargetCves(projectIssues, repoConfig.GetScannedTargetCves())
 		issuesCollection.Append(projectIssues)
 	}
-	resultContext = scanDetails.ResultContext
+	// The working directories of the projects may overlap, so the issues of the projects are merged as well
+	issuesCollection.DeduplicateScaIssues()
+	addExternalScannerIssues(repoConfig.ExternalSarifPaths, scanDetails.ExcludePatterns(), issuesCollection)
+	if repoConfig.ValidateSecrets {
+		// The active secrets of all the projects are listed first
+		utils.SortSecretsByValidationStatus(issuesCollection.SecretsVulnerabilities)
+		utils.SortSecretsByValidationStatus(issuesCollection.SecretsViolations)
+	}
+	if issuesCollection.IssuesExists(true) {
+		filterIgnoredIssues(repoConfig, issuesCollection)
+	}
 	return
 }
 
-func auditPullRequestInProject(repoConfig *utils.Repository, scanDetails *utils.ScanDetails) (auditIssues *issues.ScansIssuesCollection, err error) {
-	// Download source branch
-	sourcePullRequestInfo := scanDetails.PullRequestDetails.Source
-	sourceBranchWd, cleanupSource, err := utils.DownloadRepoToTempDir(scanDetails.Client(), sourcePullRequestInfo.Owner, sourcePullRequestInfo.Repository, sourcePullRequestInfo.Name)
+// Findings that match Xray ignore rules are accepted risks, so they aren't reported as issues of the pull request.
+// Some watch configurations still report them, so the rules are applied here as well.
+func filterIgnoredIssues(repoConfig *utils.Repository, issuesCollection *issues.ScansIssuesCollection) {
+	ignoreRules, err := utils.GetXrayIgnoreRules(&repoConfig.Server)
+	if err != nil {
+		log.Warn("Couldn't get the Xray ignore rules, so findings that match them may be reported:", err.Error())
+		return
+	}
+	utils.FilterIgnoredIssues(issuesCollection, ignoreRules, repoConfig.RepoOwner+"/"+repoConfig.RepoName)
+}
+
+func auditPullRequestInProject(repoConfig *utils.Repository, scanDetails *utils.ScanDetails, dependencyConfusionAnalyzer *dependencyconfusion.Analyzer, analyzers []sourceBranchAnalyzer) (auditIssues *issues.ScansIssuesCollection, err error) {
+	sourceBranchWd, cleanupSource, err := downloadSourceBranch(scanDetails)
 	if err != nil {
 		return
 	}
@@ -186,11 +380,20 @@ func auditPullRequestInProject(repoConfig *utils.Repository, scanDetails *utils.
 		err = errors.Join(err, cleanupSource())
 	}()
 
+	// The exclude patterns of the source branch apply to the scans of both branches
+	scanDetails.SetExcludePatterns(utils.GetExcludePatterns(sourceBranchWd, repoConfig.ExcludePatterns))
+
 	// Audit source branch
 	var sourceResults *results.SecurityCommandResults
-	workingDirs := utils.GetFullPathWorkingDirs(scanDetails.Project.WorkingDirs, sourceBranchWd)
+	workingDirs, submodulePaths, err := utils.GetFullPathWorkingDirsWithSubmodules(scanDetails.Project.WorkingDirs, sourceBranchWd, scanDetails.Submodules)
+	if err != nil {
+		return
+	}
+	// The scopes of the direct dependencies are read from the descriptors of the source branch
+	repoConfig.OutputWriter.SetDependencyScopes(repoConfig.OutputWriter.DependencyScopes().Merge(dependencyscope.Detect(workingDirs...)))
 	log.Info("Scanning source branch...")
 	sourceResults = scanDetails.RunInstallAndAudit(workingDirs...)
+	utils.AttributeResultsToSubmodules(sourceResults, sourceBranchWd, submodulePaths)
 	if err = sourceResults.GetErrors(); err != nil {
 		// We get the scan status even if the scan failed to report the scan status in the summary
 		auditIssues = getResultScanStatues(sourceResults)
@@ -199,24 +402,68 @@ func auditPullRequestInProject(repoConfig *utils.Repository, scanDetails *utils.
 
 	// Set JAS output flags
 	repoConfig.OutputWriter.SetJasOutputFlags(sourceResults.EntitledForJas, sourceResults.HasJasScansResults(jasutils.Applicability))
+	repoConfig.OutputWriter.SetJasStatusUnknown(scanDetails.JasStatusUnknown())
+	repoConfig.OutputWriter.SetJasUnsupportedReason(scanDetails.JasUnsupportedReason())
+	// The direct dependencies of the source branch are analyzed before the target branch scan replaces them
+	dependencyConfusionRisks := dependencyConfusionAnalyzer.Analyze(scanDetails.DirectDependencies())
+	defer func() {
+		if auditIssues != nil {
+			auditIssues.DependencyConfusionRisks = dependencyConfusionRisks
+		}
+	}()
 	// Get all issues that exist in the source branch
 	if repoConfig.IncludeAllVulnerabilities {
 		if auditIssues, err = getAllIssues(sourceResults, repoConfig.AllowedLicenses); err != nil {
 			return
 		}
+		if repoConfig.ValidateSecrets {
+			utils.ValidateSecrets(auditIssues)
+		}
 		utils.ConvertSarifPathsToRelative(auditIssues, sourceBranchWd)
+		utils.ConvertScaLocationsToWorkingDirs(auditIssues, workingDirs, sourceBranchWd)
+		utils.FilterJasIssuesByExclusions(auditIssues, repoConfig.Jas, scanDetails.ExcludePatterns())
+		runSourceBranchAnalyzers(analyzers, auditIssues, sourceBranchWd, workingDirs)
 		return
 	}
 
 	var targetBranchWd string
-	if auditIssues, targetBranchWd, err = auditTargetBranch(repoConfig, scanDetails, sourceResults); err != nil {
+	if auditIssues, targetBranchWd, err = auditTargetBranch(repoConfig, scanDetails, sourceResults, analyzers); err != nil {
 		return
 	}
+	// The secrets are validated while the source branch files still exist, as their values are read from the files
+	if repoConfig.ValidateSecrets {
+		utils.ValidateSecrets(auditIssues)
+	}
 	utils.ConvertSarifPathsToRelative(auditIssues, sourceBranchWd, targetBranchWd)
+	utils.ConvertScaLocationsToWorkingDirs(auditIssues, workingDirs, sourceBranchWd, targetBranchWd)
+	utils.FilterJasIssuesByExclusions(auditIssues, repoConfig.Jas, scanDetails.ExcludePatterns())
+	// The analyzers were given the target branch, so they report only what the pull request adds
+	runSourceBranchAnalyzers(analyzers, auditIssues, sourceBranchWd, workingDirs)
 	return
 }
 
-func auditTargetBranch(repoConfig *utils.Repository, scanDetails *utils.ScanDetails, sourceScanResults *results.SecurityCommandResults) (newIssues *issues.ScansIssuesCollection, targetBranchWd string, err error) {
+// Reports the findings of the third-party scanners from their SARIF files, which other steps of the CI run produced in the current working directory.
+// Failing to read the files doesn't fail the scan of the pull request.
+func addExternalScannerIssues(sarifPaths, excludePatterns []string, issuesCollection *issues.ScansIssuesCollection) {
+	if len(sarifPaths) == 0 {
+		return
+	}
+	repositoryDir, err := os.Getwd()
+	if err != nil {
+		log.Warn("Couldn't read the results of the external scanners:", err.Error())
+		return
+	}
+	externalIssues, err := externalsarif.Load(repositoryDir, sarifPaths...)
+	if err != nil {
+		log.Warn("Couldn't read the results of some of the external scanners:", err.Error())
+	}
+	issuesCollection.ExternalScannerIssues = slices.DeleteFunc(externalIssues, func(externalIssue issues.ExternalScannerIssue) bool {
+		return utils.MatchesExcludePatterns(externalIssue.File, excludePatterns)
+	})
+	log.Debug(fmt.Sprintf("Read %d findings of the external scanners", len(issuesCollection.ExternalScannerIssues)))
+}
+
+func auditTargetBranch(repoConfig *utils.Repository, scanDetails *utils.ScanDetails, sourceScanResults *results.SecurityCommandResults, analyzers []sourceBranchAnalyzer) (newIssues *issues.ScansIssuesCollection, targetBranchWd string, err error) {
 	// Download target branch (if needed)
 	cleanupTarget := func() error { return nil }
 	if !repoConfig.IncludeAllVulnerabilities {
@@ -230,9 +477,14 @@ func auditTargetBranch(repoConfig *utils.Repository, scanDetails *utils.ScanDeta
 
 	// Set target branch scan details
 	var targetResults *results.SecurityCommandResults
-	workingDirs := utils.GetFullPathWorkingDirs(scanDetails.Project.WorkingDirs, targetBranchWd)
+	workingDirs, submodulePaths, err := utils.GetFullPathWorkingDirsWithSubmodules(scanDetails.Project.WorkingDirs, targetBranchWd, scanDetails.Submodules)
+	if err != nil {
+		return
+	}
+	setAnalyzersTargetBranch(analyzers, targetBranchWd, workingDirs)
 	log.Info("Scanning target branch...")
 	targetResults = scanDetails.RunInstallAndAudit(workingDirs...)
+	utils.AttributeResultsToSubmodules(targetResults, targetBranchWd, submodulePaths)
 	if err = targetResults.GetErrors(); err != nil {
 		// We get the scan status even if the scan failed to report the scan status in the summary
 		newIssues = getResultScanStatues(sourceScanResults, targetResults)
@@ -240,19 +492,37 @@ func auditTargetBranch(repoConfig *utils.Repository, scanDetails *utils.ScanDeta
 	}
 
 	// Get newly added issues
-	newIssues, err = getNewlyAddedIssues(targetResults, sourceScanResults, repoConfig.AllowedLicenses, targetResults.IncludesVulnerabilities(), targetResults.HasViolationContext())
+	newIssues, err = getNewlyAddedIssues(targetResults, sourceScanResults, repoConfig.AllowedLicenses, repoConfig.Policy.RequiresLicenses(), targetResults.IncludesVulnerabilities(), targetResults.HasViolationContext())
 	return
 }
 
+// Downloads the source branch of the pull request, from the fork of the repository if the pull request is from a fork
+func downloadSourceBranch(scanDetails *utils.ScanDetails) (sourceBranchWd string, cleanupSource func() error, err error) {
+	// The local scan scans its directory in place
+	if scanDetails.LocalSourceDir != "" {
+		return scanDetails.LocalSourceDir, func() error { return nil }, nil
+	}
+	if utils.IsForkPullRequest(scanDetails.PullRequestDetails) {
+		return utils.DownloadForkToTempDir(scanDetails.Client(), scanDetails.Git)
+	}
+	source := scanDetails.PullRequestDetails.Source
+	return utils.DownloadRepoToTempDir(scanDetails.Client(), source.Owner, source.Repository, source.Name, scanDetails.Git)
+}
+
 func prepareTargetForScan(gitDetails utils.Git, scanDetails *utils.ScanDetails) (targetBranchWd string, cleanupTarget func() error, err error) {
 	target := gitDetails.PullRequestDetails.Target
 	// Download target branch
-	if targetBranchWd, cleanupTarget, err = utils.DownloadRepoToTempDir(scanDetails.Client(), target.Owner, target.Repository, target.Name); err != nil {
+	if targetBranchWd, cleanupTarget, err = utils.DownloadRepoToTempDir(scanDetails.Client(), target.Owner, target.Repository, target.Name, scanDetails.Git); err != nil {
 		return
 	}
 	if !scanDetails.Git.UseMostCommonAncestorAsTarget {
 		return
 	}
+	if utils.IsForkPullRequest(gitDetails.PullRequestDetails) {
+		// The source branch can't be fetched from the repository with its credentials
+		log.Debug("The pull request is from a fork, so the target branch commit is scanned instead of the most common ancestor commit")
+		return
+	}
 	log.Debug("Using most common ancestor commit as target branch commit")
 
 	// Get common parent commit between source and target and use it (checkout) to the target branch commit
@@ -296,31 +566,13 @@ func tryCheckoutToMostCommonAncestor(scanDetails *utils.ScanDetails, baseBranch,
 
 func getAllIssues(cmdResults *results.SecurityCommandResults, allowedLicenses []string) (*issues.ScansIssuesCollection, error) {
 	log.Info("Frogbot is configured to show all issues")
-	simpleJsonResults, err := conversion.NewCommandResultsConvertor(conversion.ResultConvertParams{
-		IncludeVulnerabilities: cmdResults.IncludesVulnerabilities(),
-		HasViolationContext:    cmdResults.HasViolationContext(),
-		AllowedLicenses:        allowedLicenses,
-		IncludeLicenses:        true,
-		SimplifiedOutput:       true,
-	}).ConvertToSimpleJson(cmdResults)
+	issuesCollection, err := utils.ConvertToIssuesCollection(cmdResults, allowedLicenses)
 	if err != nil {
 		return nil, err
 	}
-	return &issues.ScansIssuesCollection{
-		ScanStatus:         simpleJsonResults.Statuses,
-		ScaVulnerabilities: simpleJsonResults.Vulnerabilities,
-		ScaViolations:      simpleJsonResults.SecurityViolations,
-		LicensesViolations: simpleJsonResults.LicensesViolations,
-
-		IacVulnerabilities: simpleJsonResults.IacsVulnerabilities,
-		IacViolations:      simpleJsonResults.IacsViolations,
-
-		SecretsVulnerabilities: simpleJsonResults.SecretsVulnerabilities,
-		SecretsViolations:      simpleJsonResults.SecretsViolations,
-
-		SastVulnerabilities: simpleJsonResults.SastVulnerabilities,
-		SastViolations:      simpleJsonResults.SastViolations,
-	}, nil
+	// The same issue may be found in more than one working directory
+	issuesCollection.DeduplicateScaIssues()
+	return issuesCollection, nil
 }
 
 func getResultScanStatues(cmdResults ...*results.SecurityCommandResults) *issues.ScansIssuesCollection {
@@ -337,8 +589,9 @@ func getResultScanStatues(cmdResults ...*results.SecurityCommandResults) *issues
 }
 
 // Returns all the issues found in the source branch that didn't exist in the target branch.
-func getNewlyAddedIssues(targetResults, sourceResults *results.SecurityCommandResults, allowedLicenses []string, includeVulnerabilities, hasViolationContext bool) (newIssues *issues.ScansIssuesCollection, err error) {
-	convertor := conversion.NewCommandResultsConvertor(conversion.ResultConvertParams{IncludeVulnerabilities: includeVulnerabilities, HasViolationContext: hasViolationContext, IncludeLicenses: len(allowedLicenses) > 0, AllowedLicenses: allowedLicenses, SimplifiedOutput: true})
+// If includeLicenses is true, the licenses of the dependencies that were added in the source branch are returned as well.
+func getNewlyAddedIssues(targetResults, sourceResults *results.SecurityCommandResults, allowedLicenses []string, includeLicenses, includeVulnerabilities, hasViolationContext bool) (newIssues *issues.ScansIssuesCollection, err error) {
+	convertor := conversion.NewCommandResultsConvertor(conversion.ResultConvertParams{IncludeVulnerabilities: includeVulnerabilities, HasViolationContext: hasViolationContext, IncludeLicenses: includeLicenses || len(allowedLicenses) > 0, AllowedLicenses: allowedLicenses, SimplifiedOutput: true})
 	simpleJsonSource, err := convertor.ConvertToSimpleJson(sourceResults)
 	if err != nil {
 		return
@@ -347,13 +600,22 @@ func getNewlyAddedIssues(targetResults, sourceResults *results.SecurityCommandRe
 	if err != nil {
 		return
 	}
-	// ResultContext is general attribute similar for all results, taking it from the source results
+	newIssues = GetNewIssues(simpleJsonTarget, simpleJsonSource)
+	// The same issue may be found in more than one working directory
+	newIssues.DeduplicateScaIssues()
+	return
+}
+
+// Returns the issues of the source branch results that don't exist in the target branch results,
+// and the SCA issues of the target branch results that were fixed in the source branch results.
+func GetNewIssues(simpleJsonTarget, simpleJsonSource formats.SimpleJsonResults) (newIssues *issues.ScansIssuesCollection) {
 	newIssues = &issues.ScansIssuesCollection{}
 	newIssues.ScanStatus = getScanStatus(simpleJsonTarget, simpleJsonSource)
 	// Get the unique sca vulnerabilities and violations between the source and target branches
 	newIssues.ScaVulnerabilities = getUniqueVulnerabilityOrViolationRows(simpleJsonTarget.Vulnerabilities, simpleJsonSource.Vulnerabilities)
 	newIssues.ScaViolations = getUniqueVulnerabilityOrViolationRows(simpleJsonTarget.SecurityViolations, simpleJsonSource.SecurityViolations)
 	newIssues.LicensesViolations = getUniqueLicenseRows(simpleJsonTarget.LicensesViolations, simpleJsonSource.LicensesViolations)
+	newIssues.Licenses = getAddedDependenciesLicenses(simpleJsonTarget.Licenses, simpleJsonSource.Licenses)
 	// Get the unique source code vulnerabilities and violations between the source and target branches
 	newIssues.IacVulnerabilities = createNewSourceCodeRows(simpleJsonTarget.IacsVulnerabilities, simpleJsonSource.IacsVulnerabilities)
 	newIssues.IacViolations = createNewSourceCodeRows(simpleJsonTarget.IacsViolations, simpleJsonSource.IacsViolations)
@@ -361,6 +623,11 @@ func getNewlyAddedIssues(targetResults, sourceResults *results.SecurityCommandRe
 	newIssues.SecretsViolations = createNewSourceCodeRows(simpleJsonTarget.SecretsViolations, simpleJsonSource.SecretsViolations)
 	newIssues.SastVulnerabilities = createNewSourceCodeRows(simpleJsonTarget.SastVulnerabilities, simpleJsonSource.SastVulnerabilities)
 	newIssues.SastViolations = createNewSourceCodeRows(simpleJsonTarget.SastViolations, simpleJsonSource.SastViolations)
+	// Get the sca issues of the target branch that no longer exist in the source branch
+	newIssues.FixedScaIssues = append(
+		getUniqueVulnerabilityOrViolationRows(simpleJsonSource.Vulnerabilities, simpleJsonTarget.Vulnerabilities),
+		getUniqueVulnerabilityOrViolationRows(simpleJsonSource.SecurityViolations, simpleJsonTarget.SecurityViolations)...,
+	)
 	return
 }
 
@@ -445,6 +712,20 @@ func getUniqueLicenseRows(targetRows, sourceRows []formats.LicenseViolationRow)
 	return newLicenses
 }
 
+// Returns the licenses of the dependency versions of the source rows that don't exist in the target rows
+func getAddedDependenciesLicenses(targetRows, sourceRows []formats.LicenseRow) (addedLicenses []formats.LicenseRow) {
+	existingLicenses := datastructures.MakeSet[string]()
+	for _, row := range targetRows {
+		existingLicenses.Add(getUniqueLicenseKey(row) + row.ImpactedDependencyVersion)
+	}
+	for _, row := range sourceRows {
+		if !existingLicenses.Exists(getUniqueLicenseKey(row) + row.ImpactedDependencyVersion) {
+			addedLicenses = append(addedLicenses, row)
+		}
+	}
+	return
+}
+
 func getUniqueLicenseKey(license formats.LicenseRow) string {
 	return license.LicenseKey + license.ImpactedDependencyName + license.ImpactedDependencyType
 }
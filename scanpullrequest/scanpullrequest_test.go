@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/dependencyscope"
 	"github.com/jfrog/frogbot/v2/utils/issues"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
 	"github.com/jfrog/froggit-go/vcsclient"
@@ -441,6 +442,10 @@ func TestGetNewVulnerabilitiesCaseNoNewVulnerabilities(t *testing.T) {
 
 	assert.Len(t, securityViolationsRows, 0)
 	assert.Len(t, licenseViolations, 0)
+
+	// Both vulnerabilities of the target branch are fixed by the source branch
+	fixedRows, _ := createScaDiff(t, currentScan, previousScan, false)
+	assert.Len(t, fixedRows, 2)
 }
 
 func TestGetAllIssues(t *testing.T) {
@@ -777,6 +782,9 @@ func createGitLabHandler(t *testing.T, projectName string) http.HandlerFunc {
 		// Return 200 on ping
 		case r.RequestURI == "/api/v4/":
 			w.WriteHeader(http.StatusOK)
+		// The repository has no policy file
+		case strings.HasPrefix(r.RequestURI, fmt.Sprintf("/api/v4/projects/jfrog%s/repository/files/.frogbot%spolicy.yml", "%2F"+projectName, "%2F")):
+			w.WriteHeader(http.StatusNotFound)
 		// Mimic get pull request by ID
 		case r.RequestURI == fmt.Sprintf("/api/v4/projects/jfrog%s/merge_requests/1", "%2F"+projectName):
 			w.WriteHeader(http.StatusOK)
@@ -1161,7 +1169,7 @@ func TestDeletePreviousPullRequestMessages(t *testing.T) {
 			// Test with comment returned
 			client.EXPECT().ListPullRequestComments(context.Background(), "owner", "repo", 17).Return(tc.commentsOnPR, tc.err)
 			client.EXPECT().DeletePullRequestComment(context.Background(), "owner", "repo", 17, 20).Return(nil).AnyTimes()
-			err := utils.DeleteExistingPullRequestComments(repository, client)
+			err := utils.DeleteExistingPullRequestComments(repository, client, nil)
 			if tc.err == nil {
 				assert.NoError(t, err)
 			} else {
@@ -1210,7 +1218,7 @@ func TestDeletePreviousPullRequestReviewMessages(t *testing.T) {
 			// Test with comment returned
 			client.EXPECT().ListPullRequestReviewComments(context.Background(), "", "", 17).Return(tc.commentsOnPR, tc.err)
 			client.EXPECT().DeletePullRequestReviewComments(context.Background(), "", "", 17, tc.commentsOnPR).Return(nil).AnyTimes()
-			err := utils.DeleteExistingPullRequestReviewComments(repository, 17, client)
+			err := utils.DeleteExistingPullRequestReviewComments(repository, 17, client, nil)
 			if tc.err == nil {
 				assert.NoError(t, err)
 			} else {
@@ -1262,3 +1270,84 @@ func redirectLogOutputToNil() (previousLog log.Log) {
 	log.SetLogger(newLog)
 	return previousLog
 }
+
+func TestScanDisallowedForkPullRequest(t *testing.T) {
+	repo := &utils.Repository{Params: utils.Params{Git: utils.Git{
+		DisallowForkPullRequests: true,
+		PullRequestDetails: vcsclient.PullRequestInfo{
+			ID:     1,
+			Source: vcsclient.BranchInfo{Name: "feature", Owner: "octocat", Repository: "frogbot"},
+			Target: vcsclient.BranchInfo{Name: "main", Owner: "jfrog", Repository: "frogbot"},
+		},
+	}}}
+	// The pull request is skipped before the Git provider is called
+	assert.NoError(t, scanPullRequest(repo, CreateMockVcsClient(t)))
+}
+
+func TestAddExternalScannerIssues(t *testing.T) {
+	repositoryDir := t.TempDir()
+	content, err := os.ReadFile(filepath.Join("..", "testdata", "externalsarif", "results.sarif"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(repositoryDir, "results.sarif"), []byte(strings.ReplaceAll(string(content), "REPOSITORY_DIR", filepath.ToSlash(repositoryDir))), 0644))
+	restoreDir, err := utils.Chdir(repositoryDir)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+
+	issuesCollection := &issues.ScansIssuesCollection{}
+	addExternalScannerIssues(nil, nil, issuesCollection)
+	assert.False(t, issuesCollection.ExternalScannerIssuesExists())
+	// The findings in excluded files aren't reported, and the missing files don't fail the scan
+	addExternalScannerIssues([]string{filepath.Join(repositoryDir, "results.sarif"), filepath.Join(repositoryDir, "missing.sarif")}, []string{"docker/"}, issuesCollection)
+	assert.Len(t, issuesCollection.ExternalScannerIssues, 2)
+	for _, externalIssue := range issuesCollection.ExternalScannerIssues {
+		assert.Equal(t, "Checkov", externalIssue.Scanner)
+		assert.Equal(t, "terraform/main.tf", externalIssue.File)
+	}
+}
+
+func TestToFailTaskStatus(t *testing.T) {
+	issuesFound := &issues.ScansIssuesCollection{ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1"}}}
+	policyViolationsFound := &issues.ScansIssuesCollection{PolicyRuleViolations: []issues.PolicyRuleViolation{{Rule: "deniedPackages", Subject: "lodash:4.17.20", Reason: "The package is denied by 'lodash'"}}}
+	notApplicableFound := &issues.ScansIssuesCollection{ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1", Applicable: "Not Applicable"}, {IssueId: "XRAY-2", Applicable: "Not Applicable"}}}
+	undeterminedFound := &issues.ScansIssuesCollection{ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1", Applicable: "Not Applicable"}, {IssueId: "XRAY-2", Applicable: "Undetermined"}}}
+	notScannedFound := &issues.ScansIssuesCollection{ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1", Applicable: "Not Applicable"}, {IssueId: "XRAY-2"}}}
+	applicableFound := &issues.ScansIssuesCollection{ScaViolations: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1", Applicable: "Not Applicable"}, {IssueId: "XRAY-2", Applicable: "Applicable"}}}
+	notApplicableWithSastFound := &issues.ScansIssuesCollection{ScaVulnerabilities: notApplicableFound.ScaVulnerabilities, SastVulnerabilities: []formats.SourceCodeRow{{Location: formats.Location{File: "app.js"}}}}
+	devDependencyFound := &issues.ScansIssuesCollection{ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1", ImpactedDependencyDetails: formats.ImpactedDependencyDetails{Components: []formats.ComponentRow{{Name: "jest"}}}}}}
+	prodDependencyFound := &issues.ScansIssuesCollection{ScaViolations: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-2", ImpactedDependencyDetails: formats.ImpactedDependencyDetails{Components: []formats.ComponentRow{{Name: "jest"}, {Name: "express"}}}}}}
+	testCases := []struct {
+		name                 string
+		failOnIssues         bool
+		failOnApplicableOnly bool
+		failAfterDate        string
+		skipDevDependencies  bool
+		issues               *issues.ScansIssuesCollection
+		expected             bool
+	}{
+		{name: "Issues found", failOnIssues: true, issues: issuesFound, expected: true},
+		{name: "No issues found", failOnIssues: true, issues: &issues.ScansIssuesCollection{}, expected: false},
+		{name: "Fail flag not set", failOnIssues: false, issues: issuesFound, expected: false},
+		{name: "Onboarding period", failOnIssues: true, failAfterDate: time.Now().AddDate(0, 1, 0).Format("2006-01-02"), issues: issuesFound, expected: false},
+		{name: "Onboarding period ended", failOnIssues: true, failAfterDate: time.Now().AddDate(0, -1, 0).Format("2006-01-02"), issues: issuesFound, expected: true},
+		{name: "Policy rule violations", failOnIssues: false, issues: policyViolationsFound, expected: true},
+		{name: "Policy rule violations in onboarding period", failOnIssues: false, failAfterDate: time.Now().AddDate(0, 1, 0).Format("2006-01-02"), issues: policyViolationsFound, expected: false},
+		{name: "Not applicable issues", failOnIssues: true, issues: notApplicableFound, expected: true},
+		{name: "Not applicable issues when failing on applicable only", failOnIssues: true, failOnApplicableOnly: true, issues: notApplicableFound, expected: false},
+		{name: "Applicable issues when failing on applicable only", failOnIssues: true, failOnApplicableOnly: true, issues: applicableFound, expected: true},
+		{name: "Undetermined issues when failing on applicable only", failOnIssues: true, failOnApplicableOnly: true, issues: undeterminedFound, expected: true},
+		{name: "Issues without contextual analysis when failing on applicable only", failOnIssues: true, failOnApplicableOnly: true, issues: notScannedFound, expected: true},
+		{name: "SAST issues when failing on applicable only", failOnIssues: true, failOnApplicableOnly: true, issues: notApplicableWithSastFound, expected: true},
+		{name: "Dev dependency issues", failOnIssues: true, issues: devDependencyFound, expected: true},
+		{name: "Dev dependency issues when skipping dev dependencies", failOnIssues: true, skipDevDependencies: true, issues: devDependencyFound, expected: false},
+		{name: "Prod dependency issues when skipping dev dependencies", failOnIssues: true, skipDevDependencies: true, issues: prodDependencyFound, expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &utils.Repository{Params: utils.Params{Scan: utils.Scan{FailOnSecurityIssues: &tc.failOnIssues, FailOnApplicableOnly: tc.failOnApplicableOnly, FailAfterDate: tc.failAfterDate, SkipDevDependencies: tc.skipDevDependencies}}, OutputWriter: &outputwriter.StandardOutput{}}
+			repo.OutputWriter.SetDependencyScopes(dependencyscope.Scopes{"jest": dependencyscope.Dev, "express": dependencyscope.Prod})
+			assert.Equal(t, tc.expected, toFailTaskStatus(repo, tc.issues))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package scanrepository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/automerge"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Marks the fix pull requests that are merged once their checks passed. The pull requests are marked when they're opened or updated,
+// since their checks run after the run that opened them, and the next runs merge them.
+const autoMergeMarker = "Frogbot auto-merge: patch upgrades"
+
+// Returns the hidden marker of the auto-merged pull requests if auto-merge is enabled and all the fixes upgrade only patch versions
+func (cfp *ScanRepositoryCmd) getAutoMergeMarker(vulnerabilities []*utils.VulnerabilityDetails) string {
+	if cfp.scanDetails == nil || cfp.scanDetails.Git == nil || !cfp.scanDetails.AutoMerge {
+		return ""
+	}
+	for _, vulnDetails := range vulnerabilities {
+		if utils.GetVersionChange(vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion) != utils.PatchUpgrade {
+			return ""
+		}
+	}
+	return outputwriter.MarkdownComment(autoMergeMarker)
+}
+
+// Merges the open fix pull requests that are marked for auto-merge, once all their checks passed and the Git provider allows merging them.
+// A failure to merge a pull request is reported and doesn't fail the scan.
+func (cfp *ScanRepositoryCmd) mergeReadyFixPullRequests(repository *utils.Repository) {
+	if !repository.AutoMerge || cfp.Preview {
+		return
+	}
+	pullRequests, err := cfp.scanDetails.Client().ListOpenPullRequestsWithBody(context.Background(), repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		log.Warn("Couldn't list the open pull requests to merge:", err.Error())
+		return
+	}
+	merger, err := automerge.NewMerger(repository.GitProvider, repository.VcsInfo, repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	for _, pullRequest := range pullRequests {
+		if !outputwriter.IsFrogbotFixPullRequest(pullRequest.Body) || !strings.Contains(pullRequest.Body, autoMergeMarker) {
+			continue
+		}
+		reason, err := merger.GetBlockingReason(pullRequest.ID)
+		switch {
+		case err != nil:
+			log.Warn(fmt.Sprintf("Couldn't check whether pull request #%d can be merged: %s", pullRequest.ID, err.Error()))
+		case reason != "":
+			log.Info(fmt.Sprintf("Pull request #%d isn't merged yet, since %s", pullRequest.ID, reason))
+		default:
+			if err = merger.Merge(pullRequest.ID, repository.AutoMergeStrategy); err != nil {
+				log.Warn(fmt.Sprintf("Failed to merge pull request #%d: %s", pullRequest.ID, err.Error()))
+				continue
+			}
+			log.Info(fmt.Sprintf("Merged pull request #%d from '%s' to '%s' with the %s strategy", pullRequest.ID, pullRequest.Source.Name, pullRequest.Target.Name, repository.AutoMergeStrategy))
+		}
+	}
+}
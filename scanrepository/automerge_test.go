@@ -0,0 +1,77 @@
+package scanrepository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAutoMergeMarker(t *testing.T) {
+	patchUpgrade := &utils.VulnerabilityDetails{SuggestedFixedVersion: "1.2.4"}
+	patchUpgrade.ImpactedDependencyVersion = "1.2.3"
+	minorUpgrade := &utils.VulnerabilityDetails{SuggestedFixedVersion: "1.3.0"}
+	minorUpgrade.ImpactedDependencyVersion = "1.2.3"
+
+	cfp := &ScanRepositoryCmd{scanDetails: &utils.ScanDetails{Git: &utils.Git{AutoMerge: true}}}
+	assert.Equal(t, outputwriter.MarkdownComment(autoMergeMarker), cfp.getAutoMergeMarker([]*utils.VulnerabilityDetails{patchUpgrade}))
+	// A pull request with any fix that isn't a patch upgrade is reviewed before it's merged
+	assert.Empty(t, cfp.getAutoMergeMarker([]*utils.VulnerabilityDetails{patchUpgrade, minorUpgrade}))
+
+	cfp = &ScanRepositoryCmd{scanDetails: &utils.ScanDetails{Git: &utils.Git{}}}
+	assert.Empty(t, cfp.getAutoMergeMarker([]*utils.VulnerabilityDetails{patchUpgrade}))
+}
+
+func TestMergeReadyFixPullRequests(t *testing.T) {
+	var merged []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response string
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/jfrog/frogbot/pulls/1", "GET /repos/jfrog/frogbot/pulls/2":
+			response = `{"mergeable_state":"clean","head":{"sha":"` + r.URL.Path[len(r.URL.Path)-1:] + `"}}`
+		case "GET /repos/jfrog/frogbot/commits/1/check-runs":
+			response = `{"check_runs":[{"name":"build","status":"completed","conclusion":"success"}]}`
+		case "GET /repos/jfrog/frogbot/commits/2/check-runs":
+			response = `{"check_runs":[{"name":"build","status":"in_progress"}]}`
+		case "PUT /repos/jfrog/frogbot/pulls/1/merge", "PUT /repos/jfrog/frogbot/pulls/2/merge", "PUT /repos/jfrog/frogbot/pulls/3/merge":
+			merged = append(merged, r.URL.Path)
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	fixBody := outputwriter.GetSimplifiedTitle(outputwriter.VulnerabilitiesFixPrBannerSource)
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().ListOpenPullRequestsWithBody(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{
+		// Ready to merge
+		{ID: 1, Body: fixBody + outputwriter.MarkdownComment(autoMergeMarker)},
+		// Its checks are still running
+		{ID: 2, Body: fixBody + outputwriter.MarkdownComment(autoMergeMarker)},
+		// Not marked for auto-merge
+		{ID: 3, Body: fixBody},
+		// Not a fix pull request of Frogbot
+		{ID: 4, Body: outputwriter.MarkdownComment(autoMergeMarker)},
+	}, nil)
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{
+		GitProvider: vcsutils.GitHub, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL},
+		RepoOwner: "jfrog", RepoName: "frogbot", AutoMerge: true, AutoMergeStrategy: "squash",
+	}}}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(mockVcsClient, nil, &repository.Git)}
+	cfp.mergeReadyFixPullRequests(repository)
+	assert.Equal(t, []string{"/repos/jfrog/frogbot/pulls/1/merge"}, merged)
+
+	// The pull requests aren't merged in preview mode
+	merged = nil
+	cfp.Preview = true
+	cfp.mergeReadyFixPullRequests(repository)
+	assert.Empty(t, merged)
+}
@@ -0,0 +1,161 @@
+package scanrepository
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/packagehandlers"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/maps"
+)
+
+// The result of backporting a fix pull request of a scanned branch to one of the backport branches
+type backportResult struct {
+	FixBranch    string
+	TargetBranch string
+	// The backport was queued for the next runs, since a limit of the fix pull requests was reached
+	Queued bool
+	Err    error
+}
+
+// Applies the fixes of the current fix branch to each of the backport branches, and opens their pull requests.
+// The fixes are applied by running the package handlers again on each backport branch, since its descriptors and lockfiles usually differ from the fixed branch.
+// The fixes of the backport branches themselves aren't backported. A failure to backport the fix to a branch is reported and doesn't fail the fix.
+func (cfp *ScanRepositoryCmd) backportFix(repository *utils.Repository, fixBranchName string, fixesByWd map[string][]*utils.VulnerabilityDetails) {
+	baseBranch := cfp.scanDetails.BaseBranch()
+	if len(repository.BackportBranches) == 0 || slices.Contains(repository.BackportBranches, baseBranch) {
+		return
+	}
+	// The pull requests of the backports are opened against the backport branches
+	defer cfp.scanDetails.SetBaseBranch(baseBranch)
+	for _, targetBranch := range repository.BackportBranches {
+		cfp.scanDetails.SetBaseBranch(targetBranch)
+		queued, err := cfp.backportFixToBranch(repository, targetBranch, fixesByWd)
+		switch {
+		case err != nil:
+			log.Warn(fmt.Sprintf("Failed to backport the fix of the '%s' branch to the '%s' branch: %s", fixBranchName, targetBranch, err.Error()))
+		case !queued:
+			log.Info(fmt.Sprintf("Backported the fix of the '%s' branch to the '%s' branch", fixBranchName, targetBranch))
+		}
+		cfp.backportResults = append(cfp.backportResults, backportResult{FixBranch: fixBranchName, TargetBranch: targetBranch, Queued: queued, Err: err})
+	}
+}
+
+// Creates the backport branch from the target branch, fixes the vulnerabilities in it, and opens its pull request.
+// An open pull request of a previous backport of the fix is updated. A new pull request is queued if a limit of the fix pull requests was reached.
+func (cfp *ScanRepositoryCmd) backportFixToBranch(repository *utils.Repository, targetBranch string, fixesByWd map[string][]*utils.VulnerabilityDetails) (queued bool, err error) {
+	vulnerabilities := getBackportedVulnerabilities(fixesByWd)
+	backportBranchName, err := cfp.generateFixBranchName(vulnerabilities...)
+	if err != nil {
+		return
+	}
+	existingPullRequest, err := cfp.getOpenPullRequestBySourceBranch(backportBranchName)
+	if err != nil {
+		return
+	}
+	if existingPullRequest == nil && cfp.queueFixIfLimited(fmt.Sprintf("the backport '%s'", backportBranchName), vulnerabilities...) {
+		return true, nil
+	}
+	if err = cfp.gitManager.FetchBranch(targetBranch); err != nil {
+		return
+	}
+	if err = cfp.gitManager.CreateBranchFromAndCheckout(backportBranchName, targetBranch); err != nil {
+		return
+	}
+	if err = cfp.applyBackportFixes(fixesByWd); err != nil {
+		return
+	}
+	isClean, err := cfp.gitManager.IsClean()
+	if err != nil {
+		return
+	}
+	if isClean {
+		return false, fmt.Errorf("the vulnerable packages are already fixed or aren't used in the '%s' branch", targetBranch)
+	}
+	if err = cfp.gitManager.AddAllAndCommit(cfp.generateCommitMessage(vulnerabilities...)); err != nil {
+		return
+	}
+	// The backport branch is recreated from the target branch on each backport
+	if err = cfp.pushFixBranch(true, backportBranchName); err != nil {
+		return
+	}
+	return false, cfp.handleFixPullRequestContent(repository, backportBranchName, existingPullRequest, vulnerabilities...)
+}
+
+// Updates the vulnerable packages to their fixed versions in the working directories of their projects in the current branch.
+// New package handlers are used, so nothing that the handlers cached from the fixed branch is reused.
+func (cfp *ScanRepositoryCmd) applyBackportFixes(fixesByWd map[string][]*utils.VulnerabilityDetails) (err error) {
+	for workingDir, vulnerabilities := range fixesByWd {
+		var restoreDir func() error
+		if restoreDir, err = utils.Chdir(filepath.Join(cfp.baseWd, workingDir)); err != nil {
+			return
+		}
+		for _, vulnDetails := range vulnerabilities {
+			if err = isBuildToolsDependency(vulnDetails); err == nil {
+				err = packagehandlers.GetCompatiblePackageHandler(vulnDetails, cfp.scanDetails).UpdateDependency(vulnDetails)
+			}
+			if err != nil {
+				err = fmt.Errorf("failed to update dependency '%s' to version '%s': %s", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion, err.Error())
+				break
+			}
+		}
+		if err = errors.Join(err, restoreDir()); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Returns the backported vulnerabilities ordered by their working directories
+func getBackportedVulnerabilities(fixesByWd map[string][]*utils.VulnerabilityDetails) (vulnerabilities []*utils.VulnerabilityDetails) {
+	workingDirs := maps.Keys(fixesByWd)
+	sort.Strings(workingDirs)
+	for _, workingDir := range workingDirs {
+		vulnerabilities = append(vulnerabilities, fixesByWd[workingDir]...)
+	}
+	return
+}
+
+// Returns the commit message of the fix of the vulnerabilities against the current base branch, according to the current fix mode
+func (cfp *ScanRepositoryCmd) generateCommitMessage(vulnerabilities ...*utils.VulnerabilityDetails) string {
+	if cfp.aggregateFixes {
+		return cfp.gitManager.GenerateAggregatedCommitMessage(cfp.scanDetails.BaseBranch(), cfp.projectTech)
+	}
+	return cfp.gitManager.GenerateCommitMessage(cfp.scanDetails.BaseBranch(), cfp.projectWorkingDir, vulnerabilities[0])
+}
+
+// Returns the name of the fix branch of the vulnerabilities against the current base branch, according to the current fix mode
+func (cfp *ScanRepositoryCmd) generateFixBranchName(vulnerabilities ...*utils.VulnerabilityDetails) (string, error) {
+	if cfp.aggregateFixes {
+		return cfp.gitManager.GenerateAggregatedFixBranchName(cfp.scanDetails.BaseBranch(), cfp.projectTech)
+	}
+	// In separate pull requests there is only one vulnerability
+	return cfp.gitManager.GenerateFixBranchName(cfp.scanDetails.BaseBranch(), cfp.projectWorkingDir, vulnerabilities[0])
+}
+
+// Logs the result of backporting each fix to each of the backport branches
+func (cfp *ScanRepositoryCmd) logBackportsSummary() {
+	if len(cfp.backportResults) == 0 {
+		return
+	}
+	failures := 0
+	var lines []string
+	for _, result := range cfp.backportResults {
+		status := "Succeeded"
+		switch {
+		case result.Err != nil:
+			status = "Failed: " + result.Err.Error()
+			failures++
+		case result.Queued:
+			status = "Queued for the next run"
+			failures++
+		}
+		lines = append(lines, fmt.Sprintf("%s -> %s: %s", result.FixBranch, result.TargetBranch, status))
+	}
+	log.Info(fmt.Sprintf("%d of the %d backports succeeded:\n%s", len(cfp.backportResults)-failures, len(cfp.backportResults), strings.Join(lines, "\n")))
+}
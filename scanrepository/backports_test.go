@@ -0,0 +1,129 @@
+package scanrepository
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/packagehandlers"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Bumps minimist in the package.json file of the current directory, if it uses the vulnerable version
+type backportTestPackageHandler struct {
+	packagehandlers.CommonPackageHandler
+}
+
+func (handler *backportTestPackageHandler) UpdateDependency(vulnDetails *utils.VulnerabilityDetails) error {
+	content, err := os.ReadFile("package.json")
+	if err != nil {
+		return err
+	}
+	fixed := strings.ReplaceAll(string(content), `"minimist":"1.2.5"`, `"minimist":"`+vulnDetails.SuggestedFixedVersion+`"`)
+	return os.WriteFile("package.json", []byte(fixed), 0644)
+}
+
+func TestBackportFix(t *testing.T) {
+	repoDir := t.TempDir()
+	restoreWd, err := utils.Chdir(repoDir)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreWd())
+	}()
+	packagehandlers.Register("backport-test", func(*utils.VulnerabilityDetails, *utils.ScanDetails) packagehandlers.PackageHandler {
+		return &backportTestPackageHandler{}
+	})
+	defer packagehandlers.Unregister("backport-test")
+	_, err = git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	// On dry run the backport branches are created from the local branches
+	gitManager := utils.NewGitManager().SetDryRun(true, repoDir).SetEmailAuthor("frogbot@jfrog.com")
+	require.NoError(t, gitManager.SetLocalRepository())
+	require.NoError(t, os.WriteFile("package.json", []byte(`{"dependencies":{"minimist":"1.2.5"}}`), 0644))
+	require.NoError(t, gitManager.AddAllAndCommit("Initial commit"))
+	// The release/1.x branch changed other files, the release/2.x branch changed the fixed file, and the release/3.x branch doesn't use the vulnerable version
+	releases := map[string]string{
+		"release/1.x": `{"version":"1.x"}`,
+		"release/2.x": `{"dependencies":{"lodash":"4.17.21","minimist":"1.2.5"}}`,
+		"release/3.x": `{"dependencies":{"minimist":"1.2.8"}}`,
+	}
+	for branch, content := range releases {
+		file := "package.json"
+		if branch == "release/1.x" {
+			file = "CHANGELOG.md"
+		}
+		require.NoError(t, gitManager.CreateBranchAndCheckout(branch, false))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		require.NoError(t, gitManager.AddAllAndCommit("Release "+branch))
+		require.NoError(t, gitManager.Checkout("master"))
+	}
+
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().ListOpenPullRequestsWithBody(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{}, nil).Times(6)
+	output := &strings.Builder{}
+	cfp := ScanRepositoryCmd{
+		Preview:       true,
+		previewOutput: output,
+		OutputWriter:  &outputwriter.StandardOutput{},
+		gitManager:    gitManager,
+		baseWd:        repoDir,
+		scanDetails:   utils.NewScanDetails(mockVcsClient, nil, &utils.Git{RepoOwner: "jfrog", RepoName: "frogbot"}).SetProject(&utils.Project{}).SetBaseBranch("master"),
+	}
+	vulnDetails := &utils.VulnerabilityDetails{
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    "minimist",
+				ImpactedDependencyVersion: "1.2.5",
+			},
+			Technology: "backport-test",
+		},
+		SuggestedFixedVersion: "1.2.6",
+	}
+	require.NoError(t, gitManager.CreateBranchAndCheckout("frogbot-minimist", false))
+	require.NoError(t, os.WriteFile("package.json", []byte(`{"dependencies":{"minimist":"1.2.6"}}`), 0644))
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{BackportBranches: []string{"release/1.x", "release/2.x", "release/3.x"}}}}
+	require.NoError(t, cfp.openFixingPullRequest(repository, "frogbot-minimist", nil, vulnDetails))
+
+	// The base branch of the fix is restored after the backports
+	assert.Equal(t, "master", cfp.scanDetails.BaseBranch())
+	require.Len(t, cfp.backportResults, 3)
+	assert.Equal(t, backportResult{FixBranch: "frogbot-minimist", TargetBranch: "release/1.x"}, cfp.backportResults[0])
+	assert.Equal(t, backportResult{FixBranch: "frogbot-minimist", TargetBranch: "release/2.x"}, cfp.backportResults[1])
+	assert.Equal(t, "release/3.x", cfp.backportResults[2].TargetBranch)
+	assert.ErrorContains(t, cfp.backportResults[2].Err, "already fixed")
+
+	preview := output.String()
+	assert.Contains(t, preview, "===== Preview: Frogbot would open a pull request from 'frogbot-minimist' to 'master'")
+	for _, targetBranch := range []string{"release/1.x", "release/2.x"} {
+		backportBranch, err := gitManager.GenerateFixBranchName(targetBranch, "", vulnDetails)
+		require.NoError(t, err)
+		assert.Contains(t, preview, "===== Preview: Frogbot would open a pull request from '"+backportBranch+"' to '"+targetBranch+"'")
+	}
+	assert.NotContains(t, preview, "to 'release/3.x'")
+	// The backport commits fix the package in the descriptors of the backport branches, and don't change their other files
+	backportPreview := preview[strings.Index(preview, "to 'release/1.x'"):strings.Index(preview, "to 'release/2.x'")]
+	assert.Contains(t, backportPreview, `+{"dependencies":{"minimist":"1.2.6"}}`)
+	assert.NotContains(t, backportPreview, "CHANGELOG.md")
+	assert.Contains(t, preview[strings.Index(preview, "to 'release/2.x'"):], `+{"dependencies":{"lodash":"4.17.21","minimist":"1.2.6"}}`)
+
+	// The backports count against the limit of the new fix pull requests
+	cfp.backportResults = nil
+	cfp.maxNewFixPullRequests, cfp.newFixPullRequests = 1, 1
+	cfp.backportFix(repository, "frogbot-minimist", map[string][]*utils.VulnerabilityDetails{"": {vulnDetails}})
+	require.Len(t, cfp.backportResults, 3)
+	for _, result := range cfp.backportResults {
+		assert.True(t, result.Queued)
+		assert.NoError(t, result.Err)
+	}
+	assert.Len(t, cfp.queuedFixes, 3)
+}
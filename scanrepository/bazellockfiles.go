@@ -0,0 +1,23 @@
+package scanrepository
+
+import (
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Adds the vulnerable dependencies of the Bazel lockfiles in the working directory to the vulnerabilities to fix.
+// The dependencies that the workspace pins are fixed by the Bazel package handler, which updates their versions and repins the lockfiles.
+// Failing to scan the lockfiles doesn't fail the scan of the repository.
+func (cfp *ScanRepositoryCmd) addBazelVulnerabilities(fullPathWd string, vulnerabilitiesMap map[string]*utils.VulnerabilityDetails) error {
+	vulnerabilities, err := cfp.bazelAnalyzer.Analyze(cfp.baseWd, fullPathWd)
+	if err != nil {
+		log.Warn("Couldn't scan the dependencies of the Bazel lockfiles:", err.Error())
+		return nil
+	}
+	for i := range vulnerabilities {
+		if err = cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package scanrepository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/botpullrequests"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Loads the open pull requests of the other dependency bots of the repository, so the packages they bump aren't fixed twice.
+// Failing to list the pull requests doesn't fail the scan, and all the packages are fixed.
+func (cfp *ScanRepositoryCmd) loadBotPullRequests(repository *utils.Repository) {
+	cfp.botPullRequestAuthors = repository.BotPullRequestAuthors
+	cfp.botPullRequests = nil
+	if len(cfp.botPullRequestAuthors) == 0 {
+		return
+	}
+	lister, err := botpullrequests.NewLister(repository.GitProvider, repository.VcsInfo, repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	if cfp.botPullRequests, err = lister.ListOpen(); err != nil {
+		log.Warn("Couldn't list the open pull requests of", strings.Join(cfp.botPullRequestAuthors, ", "), "-", err.Error())
+	}
+}
+
+// Returns the open pull request of another bot that bumps the vulnerable package, or nil if there is none
+func (cfp *ScanRepositoryCmd) getBotPullRequest(vulnDetails *utils.VulnerabilityDetails) *botpullrequests.PullRequest {
+	return botpullrequests.FindPackagePullRequest(cfp.botPullRequests, cfp.botPullRequestAuthors, vulnDetails.ImpactedDependencyName)
+}
+
+// Links the pull request of the other bot instead of opening a duplicate fix pull request.
+// The bot pull request is commented with the vulnerabilities it fixes, once for each package.
+func (cfp *ScanRepositoryCmd) linkBotPullRequest(botPullRequest *botpullrequests.PullRequest, vulnDetails *utils.VulnerabilityDetails) {
+	log.Info(fmt.Sprintf("Pull request #%d of %s already bumps the dependency '%s' (%s). Skipping...", botPullRequest.ID, botPullRequest.Author, vulnDetails.ImpactedDependencyName, botPullRequest.Url))
+	if cfp.Preview {
+		return
+	}
+	content := getBotPullRequestComment(vulnDetails)
+	client := cfp.scanDetails.Client()
+	comments, err := client.ListPullRequestComments(context.Background(), cfp.scanDetails.RepoOwner, cfp.scanDetails.RepoName, int(botPullRequest.ID))
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't list the comments of pull request #%d: %s", botPullRequest.ID, err.Error()))
+		return
+	}
+	for _, comment := range comments {
+		if strings.TrimSpace(comment.Content) == content {
+			log.Debug(fmt.Sprintf("Pull request #%d is already commented with the vulnerabilities of '%s'", botPullRequest.ID, vulnDetails.ImpactedDependencyName))
+			return
+		}
+	}
+	if err = client.AddPullRequestComment(context.Background(), cfp.scanDetails.RepoOwner, cfp.scanDetails.RepoName, content, int(botPullRequest.ID)); err != nil {
+		log.Warn(fmt.Sprintf("Couldn't comment on pull request #%d: %s", botPullRequest.ID, err.Error()))
+	}
+}
+
+func getBotPullRequestComment(vulnDetails *utils.VulnerabilityDetails) string {
+	vulnerabilities := []string{vulnDetails.IssueId}
+	if len(vulnDetails.Cves) > 0 {
+		vulnerabilities = vulnDetails.Cves
+	}
+	return fmt.Sprintf("Frogbot found that `%s:%s` is vulnerable to %s, which version `%s` fixes. Frogbot doesn't open a pull request of its own while this pull request bumps `%s`.",
+		vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, strings.Join(vulnerabilities, ", "), vulnDetails.SuggestedFixedVersion, vulnDetails.ImpactedDependencyName)
+}
@@ -0,0 +1,52 @@
+package scanrepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/botpullrequests"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotPullRequests(t *testing.T) {
+	vulnDetails := &utils.VulnerabilityDetails{
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"},
+		},
+		SuggestedFixedVersion: "4.17.21",
+		Cves:                  []string{"CVE-2021-23337"},
+	}
+	expectedComment := "Frogbot found that `lodash:4.17.20` is vulnerable to CVE-2021-23337, which version `4.17.21` fixes. Frogbot doesn't open a pull request of its own while this pull request bumps `lodash`."
+	assert.Equal(t, expectedComment, getBotPullRequestComment(vulnDetails))
+
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	git := &utils.Git{RepoOwner: "jfrog", RepoName: "frogbot"}
+	cfp := ScanRepositoryCmd{
+		scanDetails:           utils.NewScanDetails(mockVcsClient, nil, git),
+		botPullRequestAuthors: []string{"dependabot[bot]"},
+		botPullRequests: []botpullrequests.PullRequest{
+			{ID: 3, Title: "Bump lodash from 4.17.20 to 4.17.21", Author: "developer"},
+			{ID: 7, Title: "Bump lodash from 4.17.20 to 4.17.21", Author: "dependabot[bot]"},
+		},
+	}
+	botPullRequest := cfp.getBotPullRequest(vulnDetails)
+	require.NotNil(t, botPullRequest)
+	assert.Equal(t, int64(7), botPullRequest.ID)
+
+	// The bot pull request is commented once
+	mockVcsClient.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 7).Return([]vcsclient.CommentInfo{}, nil)
+	mockVcsClient.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", expectedComment, 7).Return(nil)
+	cfp.linkBotPullRequest(botPullRequest, vulnDetails)
+	mockVcsClient.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 7).Return([]vcsclient.CommentInfo{{Content: expectedComment}}, nil)
+	cfp.linkBotPullRequest(botPullRequest, vulnDetails)
+
+	// Without bot authors, all the packages are fixed
+	cfp.botPullRequestAuthors = nil
+	assert.Nil(t, cfp.getBotPullRequest(vulnDetails))
+}
@@ -0,0 +1,87 @@
+package scanrepository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Deletes the Frogbot fix branches of the repository whose pull requests were merged or closed, once they were merged or closed before the retention period.
+// The branches without a pull request are kept, since they may be pushed by another run that didn't create its pull request yet.
+// Failing to delete a branch doesn't fail the scan.
+func (cfp *ScanRepositoryCmd) cleanupMergedBranches(repository *utils.Repository) {
+	if !repository.CleanupMergedBranches {
+		return
+	}
+	staleBranches, err := cfp.getStaleFixBranches(repository, time.Now())
+	if err != nil {
+		log.Warn("Couldn't find the fix branches of the merged and closed pull requests:", err.Error())
+		return
+	}
+	if len(staleBranches) == 0 {
+		log.Debug("No fix branches of merged or closed pull requests to delete")
+		return
+	}
+	if cfp.Preview {
+		log.Info("The fix branches of the merged and closed pull requests that would be deleted:", strings.Join(staleBranches, ", "))
+		return
+	}
+	var deleted []string
+	for _, branch := range staleBranches {
+		if err = cfp.gitManager.RemoveRemoteBranch(branch); err != nil {
+			log.Warn(fmt.Sprintf("Couldn't delete the fix branch '%s': %s", branch, err.Error()))
+			continue
+		}
+		deleted = append(deleted, branch)
+	}
+	log.Info(fmt.Sprintf("Deleted %d fix branches of merged and closed pull requests: %s", len(deleted), strings.Join(deleted, ", ")))
+}
+
+// Returns the sorted Frogbot fix branches that have no open pull request, and whose latest pull request was merged or closed before the retention period.
+// The fix branches are matched by the branch name template. The scanned branches are never returned, even if their names look like fix branches.
+func (cfp *ScanRepositoryCmd) getStaleFixBranches(repository *utils.Repository, now time.Time) (staleBranches []string, err error) {
+	fixBranchName, err := utils.FixBranchNameRegexp(repository.BranchNameTemplate)
+	if err != nil {
+		return
+	}
+	client := cfp.scanDetails.Client()
+	branches, err := client.ListBranches(context.Background(), repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list the branches: %s", err.Error())
+	}
+	pullRequests, err := client.ListOpenPullRequests(context.Background(), repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list the open pull requests: %s", err.Error())
+	}
+	openPullRequestBranches := map[string]bool{}
+	for _, pullRequest := range pullRequests {
+		openPullRequestBranches[pullRequest.Source.Name] = true
+	}
+	closedPullRequests, err := listClosedPullRequests(repository.GitProvider, repository.VcsInfo, repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list the merged and closed pull requests: %s", err.Error())
+	}
+	// A branch may be the source of several pull requests, so the latest one is kept
+	closedPullRequestBranches := map[string]time.Time{}
+	for _, pullRequest := range closedPullRequests {
+		if pullRequest.closedAt.After(closedPullRequestBranches[pullRequest.sourceBranch]) {
+			closedPullRequestBranches[pullRequest.sourceBranch] = pullRequest.closedAt
+		}
+	}
+	retentionStart := now.AddDate(0, 0, -repository.CleanupRetentionDays)
+	for _, branch := range branches {
+		if !fixBranchName.MatchString(branch) || openPullRequestBranches[branch] || slices.Contains(repository.Branches, branch) {
+			continue
+		}
+		if closedAt, found := closedPullRequestBranches[branch]; found && closedAt.Before(retentionStart) {
+			staleBranches = append(staleBranches, branch)
+		}
+	}
+	slices.Sort(staleBranches)
+	return
+}
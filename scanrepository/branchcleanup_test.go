@@ -0,0 +1,92 @@
+package scanrepository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStaleFixBranches(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	server := newClosedGitHubPullRequestsServer(t, map[string]time.Time{
+		"frogbot-minimist-3c4d":            now.AddDate(0, 0, -30),
+		"frogbot-express-5e6f":             now.AddDate(0, 0, -2),
+		"frogbot-update-7a8b-dependencies": now.AddDate(0, 0, -8),
+		"feature-old":                      now.AddDate(0, 0, -60),
+		"frogbot-master":                   now.AddDate(0, 0, -60),
+	})
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().ListBranches(context.Background(), "jfrog", "frogbot").Return([]string{
+		"master", "feature-old", "frogbot-master", "frogbot-lodash-1a2b", "frogbot-minimist-3c4d", "frogbot-express-5e6f", "frogbot-update-7a8b-dependencies", "frogbot-axios-9c0d",
+	}, nil)
+	mockVcsClient.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{
+		{ID: 1, Source: vcsclient.BranchInfo{Name: "frogbot-lodash-1a2b"}},
+	}, nil)
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{
+		GitProvider: vcsutils.GitHub, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL},
+		RepoOwner: "jfrog", RepoName: "frogbot", Branches: []string{"master", "frogbot-master"}, CleanupMergedBranches: true, CleanupRetentionDays: 7,
+	}}}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(mockVcsClient, nil, &repository.Git)}
+
+	// The branches of the open pull requests, the branches without a pull request, the branches that don't match the fix branch names,
+	// the scanned branches and the branches of pull requests closed within the retention period are kept
+	staleBranches, err := cfp.getStaleFixBranches(repository, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frogbot-minimist-3c4d", "frogbot-update-7a8b-dependencies"}, staleBranches)
+}
+
+func TestGetStaleFixBranchesCustomTemplate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	server := newClosedGitHubPullRequestsServer(t, map[string]time.Time{
+		"security/fix-lodash-1a2b": now.AddDate(0, 0, -30),
+		"frogbot-minimist-3c4d":    now.AddDate(0, 0, -30),
+	})
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().ListBranches(context.Background(), "jfrog", "frogbot").Return([]string{"master", "security/fix-lodash-1a2b", "frogbot-minimist-3c4d"}, nil)
+	mockVcsClient.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return(nil, nil)
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{
+		GitProvider: vcsutils.GitHub, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL},
+		RepoOwner: "jfrog", RepoName: "frogbot", BranchNameTemplate: "security/fix-{IMPACTED_PACKAGE}-{BRANCH_NAME_HASH}", CleanupMergedBranches: true, CleanupRetentionDays: 7,
+	}}}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(mockVcsClient, nil, &repository.Git)}
+	staleBranches, err := cfp.getStaleFixBranches(repository, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security/fix-lodash-1a2b"}, staleBranches)
+}
+
+func TestGetStaleFixBranchesListError(t *testing.T) {
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().ListBranches(context.Background(), "jfrog", "frogbot").Return([]string{"frogbot-lodash-1a2b"}, nil)
+	mockVcsClient.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return(nil, errors.New("unauthorized"))
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", CleanupMergedBranches: true}}}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(mockVcsClient, nil, &repository.Git)}
+	_, err := cfp.getStaleFixBranches(repository, time.Now())
+	assert.ErrorContains(t, err, "couldn't list the open pull requests: unauthorized")
+}
+
+// Returns a GitHub server that lists a closed pull request for each source branch, closed at the given time
+func newClosedGitHubPullRequestsServer(t *testing.T, closedAt map[string]time.Time) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/jfrog/frogbot/pulls", r.URL.Path)
+		assert.Equal(t, "closed", r.URL.Query().Get("state"))
+		var pullRequests []map[string]any
+		for branch, closedTime := range closedAt {
+			pullRequests = append(pullRequests, map[string]any{"head": map[string]string{"ref": branch}, "closed_at": closedTime.Format(time.RFC3339)})
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(pullRequests))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,204 @@
+package scanrepository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/workitems"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// Unchanged branches are scanned again after this period, to detect newly published vulnerabilities
+	branchBaselineMaxAge = 24 * time.Hour
+
+	branchScanned = "Scanned"
+	branchSkipped = "Skipped, unchanged"
+	branchFailed  = "Failed"
+)
+
+// The status of a scanned branch, for the summary of all the branches of the repository
+type branchSummary struct {
+	Branch              string
+	Status              string
+	Findings            int
+	PullRequestsOpened  int
+	PullRequestsUpdated int
+	// The fixes that are queued for the next runs, since a limit of the fix pull requests was reached
+	FixesQueued int
+}
+
+// The baseline of a branch is the checksum of its latest commit and of the scan configuration, at the time of its last successful scan
+type branchBaseline struct {
+	Checksum string    `json:"checksum"`
+	ScanTime time.Time `json:"scanTime"`
+}
+
+func (cfp *ScanRepositoryCmd) startBranchSummary(branch string) *branchSummary {
+	summary := &branchSummary{Branch: branch, Status: branchScanned}
+	cfp.branchSummaries = append(cfp.branchSummaries, summary)
+	cfp.currentBranchSummary = summary
+	return summary
+}
+
+// Records the fix pull request in the summary of the current branch
+func (cfp *ScanRepositoryCmd) recordFixPullRequest(opened bool) {
+	if cfp.currentBranchSummary == nil {
+		return
+	}
+	if opened {
+		cfp.currentBranchSummary.PullRequestsOpened++
+	} else {
+		cfp.currentBranchSummary.PullRequestsUpdated++
+	}
+}
+
+// Returns the checksum of the latest commit of the branch and of the scan configuration, so changing either of them triggers a new scan
+func (cfp *ScanRepositoryCmd) getBranchChecksum(repository *utils.Repository, branch string) (string, error) {
+	latestCommit, err := cfp.scanDetails.Client().GetLatestCommit(context.Background(), repository.RepoOwner, repository.RepoName, branch)
+	if err != nil {
+		return "", err
+	}
+	scanConfig, err := json.Marshal(struct {
+		FrogbotVersion           string
+		Scan                     utils.Scan
+		AggregateFixes           bool
+		SeparateFixesMinSeverity string
+	}{utils.FrogbotVersion, repository.Scan, repository.AggregateFixes, repository.SeparateFixesMinSeverity})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write([]byte(latestCommit.Hash))
+	hash.Write(scanConfig)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Returns true if the branch is unchanged since its last successful scan, which is recent enough to skip scanning it again.
+// The checksum of the branch is returned, so its baseline can be updated after the scan.
+func (cfp *ScanRepositoryCmd) isBranchUnchanged(repository *utils.Repository, branch string) (unchanged bool, checksum string) {
+	checksum, err := cfp.getBranchChecksum(repository, branch)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't get the latest commit of the '%s' branch, so it is scanned: %s", branch, err.Error()))
+		return false, ""
+	}
+	baseline, exists := cfp.branchBaselines[branch]
+	return exists && baseline.Checksum == checksum && time.Since(baseline.ScanTime) < branchBaselineMaxAge, checksum
+}
+
+// Loads the baselines of the branches. A missing baselines file means that no branch was scanned yet.
+func (cfp *ScanRepositoryCmd) loadBranchBaselines(baselinesFile string) error {
+	cfp.branchBaselines = make(map[string]branchBaseline)
+	content, err := os.ReadFile(baselinesFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err = json.Unmarshal(content, &cfp.branchBaselines); err != nil {
+		return fmt.Errorf("failed to parse the branch baselines file '%s': %s", baselinesFile, err.Error())
+	}
+	return nil
+}
+
+func (cfp *ScanRepositoryCmd) writeBranchBaselines(baselinesFile string) error {
+	content, err := json.MarshalIndent(cfp.branchBaselines, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(baselinesFile), 0755); err != nil {
+		return err
+	}
+	log.Debug("Writing the branch baselines to:", baselinesFile)
+	return os.WriteFile(baselinesFile, content, 0644)
+}
+
+// Logs the summary of all the scanned branches, and posts it to the branches status issue if requested
+func (cfp *ScanRepositoryCmd) reportBranchesSummary(repository *utils.Repository) error {
+	if len(cfp.branchSummaries) == 0 {
+		return nil
+	}
+	summaryContent := &branchesSummaryContent{repository: fmt.Sprintf("%s/%s", repository.RepoOwner, repository.RepoName), summaries: cfp.branchSummaries}
+	if len(repository.Branches) > 1 {
+		log.Info("Branches summary:\n" + summaryContent.text())
+	}
+	if !repository.BranchesSummaryIssue || cfp.Preview {
+		return nil
+	}
+	tracker, err := workitems.NewTracker(repository.GitProvider, repository.VcsInfo, repository.RepoOwner, repository.RepoName, repository.AzureWorkItemType)
+	if err != nil {
+		return err
+	}
+	if err = workitems.CreateOrUpdate(tracker, summaryContent); err != nil {
+		return errors.New("couldn't post the branches summary: " + err.Error())
+	}
+	return nil
+}
+
+// The content of the branches status issue, which is updated on each run
+type branchesSummaryContent struct {
+	repository string
+	summaries  []*branchSummary
+}
+
+func (bsc *branchesSummaryContent) Title() string {
+	return fmt.Sprintf("%s Branches security status", outputwriter.FrogbotTitlePrefix)
+}
+
+func (bsc *branchesSummaryContent) rows() [][]string {
+	rows := [][]string{{"Branch", "Status", "Findings", "Fix Pull Requests Opened", "Fix Pull Requests Updated", "Fixes Queued"}}
+	for _, summary := range bsc.summaries {
+		findings := fmt.Sprint(summary.Findings)
+		if summary.Status == branchSkipped {
+			findings = "-"
+		}
+		rows = append(rows, []string{summary.Branch, summary.Status, findings, fmt.Sprint(summary.PullRequestsOpened), fmt.Sprint(summary.PullRequestsUpdated), fmt.Sprint(summary.FixesQueued)})
+	}
+	return rows
+}
+
+func (bsc *branchesSummaryContent) text() string {
+	var lines []string
+	for _, row := range bsc.rows() {
+		lines = append(lines, fmt.Sprintf("%-30s %-20s %-10s %-25s %-26s %s", row[0], row[1], row[2], row[3], row[4], row[5]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (bsc *branchesSummaryContent) MarkdownDescription() string {
+	table := outputwriter.NewMarkdownTable(bsc.rows()[0]...)
+	for _, row := range bsc.rows()[1:] {
+		table.AddRow(row...)
+	}
+	return fmt.Sprintf("The security status of the scanned branches of %s, as of the last Frogbot run at %s.\n\n%s", bsc.repository, time.Now().UTC().Format(time.RFC1123), table.Build())
+}
+
+func (bsc *branchesSummaryContent) HtmlDescription() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("<p>The security status of the scanned branches of %s, as of the last Frogbot run at %s.</p><table>", html.EscapeString(bsc.repository), time.Now().UTC().Format(time.RFC1123)))
+	for i, row := range bsc.rows() {
+		cellTag := "td"
+		if i == 0 {
+			cellTag = "th"
+		}
+		builder.WriteString("<tr>")
+		for _, cell := range row {
+			builder.WriteString(fmt.Sprintf("<%s>%s</%s>", cellTag, html.EscapeString(cell), cellTag))
+		}
+		builder.WriteString("</tr>")
+	}
+	builder.WriteString("</table>")
+	return builder.String()
+}
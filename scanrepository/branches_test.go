@@ -0,0 +1,103 @@
+package scanrepository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchBaselines(t *testing.T) {
+	baselinesFile := filepath.Join(t.TempDir(), "baselines", "frogbot-baselines.json")
+	cfp := ScanRepositoryCmd{}
+	// A missing baselines file means that no branch was scanned yet
+	require.NoError(t, cfp.loadBranchBaselines(baselinesFile))
+	assert.Empty(t, cfp.branchBaselines)
+
+	scanTime := time.Now().Truncate(time.Second)
+	cfp.branchBaselines["main"] = branchBaseline{Checksum: "checksum", ScanTime: scanTime}
+	require.NoError(t, cfp.writeBranchBaselines(baselinesFile))
+
+	loaded := ScanRepositoryCmd{}
+	require.NoError(t, loaded.loadBranchBaselines(baselinesFile))
+	require.Contains(t, loaded.branchBaselines, "main")
+	assert.Equal(t, "checksum", loaded.branchBaselines["main"].Checksum)
+	assert.True(t, scanTime.Equal(loaded.branchBaselines["main"].ScanTime))
+}
+
+func TestIsBranchUnchanged(t *testing.T) {
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().GetLatestCommit(context.Background(), "jfrog", "frogbot", "main").Return(vcsclient.CommitInfo{Hash: "abc"}, nil).Times(4)
+	mockVcsClient.EXPECT().GetLatestCommit(context.Background(), "jfrog", "frogbot", "dev").Return(vcsclient.CommitInfo{}, errors.New("not found"))
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot"}}}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(mockVcsClient, nil, &repository.Git), branchBaselines: map[string]branchBaseline{}}
+
+	// A branch without a baseline is scanned
+	unchanged, checksum := cfp.isBranchUnchanged(repository, "main")
+	assert.False(t, unchanged)
+	assert.NotEmpty(t, checksum)
+
+	// A branch with a recent baseline of the same checksum is skipped
+	cfp.branchBaselines["main"] = branchBaseline{Checksum: checksum, ScanTime: time.Now()}
+	unchanged, _ = cfp.isBranchUnchanged(repository, "main")
+	assert.True(t, unchanged)
+
+	// An outdated baseline is scanned again
+	cfp.branchBaselines["main"] = branchBaseline{Checksum: checksum, ScanTime: time.Now().Add(-branchBaselineMaxAge)}
+	unchanged, _ = cfp.isBranchUnchanged(repository, "main")
+	assert.False(t, unchanged)
+
+	// Changing the scan configuration changes the checksum
+	repository.AggregateFixes = true
+	cfp.branchBaselines["main"] = branchBaseline{Checksum: checksum, ScanTime: time.Now()}
+	unchanged, newChecksum := cfp.isBranchUnchanged(repository, "main")
+	assert.False(t, unchanged)
+	assert.NotEqual(t, checksum, newChecksum)
+
+	// A branch whose latest commit can't be retrieved is scanned
+	unchanged, checksum = cfp.isBranchUnchanged(repository, "dev")
+	assert.False(t, unchanged)
+	assert.Empty(t, checksum)
+}
+
+func TestBranchesSummary(t *testing.T) {
+	cfp := ScanRepositoryCmd{}
+	// Pull requests aren't recorded before a branch is scanned
+	cfp.recordFixPullRequest(true)
+	assert.Empty(t, cfp.branchSummaries)
+
+	main := cfp.startBranchSummary("main")
+	main.Findings = 3
+	cfp.recordFixPullRequest(true)
+	cfp.recordFixPullRequest(true)
+	cfp.recordFixPullRequest(false)
+	main.FixesQueued = 4
+	cfp.startBranchSummary("dev").Status = branchSkipped
+	assert.Equal(t, []*branchSummary{
+		{Branch: "main", Status: branchScanned, Findings: 3, PullRequestsOpened: 2, PullRequestsUpdated: 1, FixesQueued: 4},
+		{Branch: "dev", Status: branchSkipped},
+	}, cfp.branchSummaries)
+
+	content := &branchesSummaryContent{repository: "jfrog/frogbot", summaries: cfp.branchSummaries}
+	assert.Equal(t, "[🐸 Frogbot] Branches security status", content.Title())
+	assert.Equal(t, [][]string{
+		{"Branch", "Status", "Findings", "Fix Pull Requests Opened", "Fix Pull Requests Updated", "Fixes Queued"},
+		{"main", branchScanned, "3", "2", "1", "4"},
+		{"dev", branchSkipped, "-", "0", "0", "0"},
+	}, content.rows())
+	assert.Contains(t, content.MarkdownDescription(), "The security status of the scanned branches of jfrog/frogbot")
+	assert.Regexp(t, `\| main\s+\| Scanned\s+\| 3\s+\| 2\s+\| 1\s+\| 4\s+\|`, content.MarkdownDescription())
+	assert.Contains(t, content.HtmlDescription(), "<tr><td>dev</td><td>Skipped, unchanged</td><td>-</td><td>0</td><td>0</td><td>0</td></tr>")
+
+	// The summary isn't posted in preview mode
+	cfp.Preview = true
+	assert.NoError(t, cfp.reportBranchesSummary(&utils.Repository{Params: utils.Params{Git: utils.Git{Branches: []string{"main", "dev"}, BranchesSummaryIssue: true}}}))
+}
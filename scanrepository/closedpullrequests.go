@@ -0,0 +1,147 @@
+package scanrepository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// The source branch of a merged or closed pull request, and the time the pull request was merged or closed
+type closedPullRequest struct {
+	sourceBranch string
+	closedAt     time.Time
+}
+
+// Returns the merged and closed pull requests of the repository. The Git clients list only the open pull requests.
+func listClosedPullRequests(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName string) ([]closedPullRequest, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	repositoryUrl := client.RepositoryUrl(repoOwner, repoName)
+	switch provider {
+	case vcsutils.GitHub:
+		return listGitHubClosedPullRequests(client, repositoryUrl+"/pulls?state=closed")
+	case vcsutils.GitLab:
+		return listGitLabClosedPullRequests(client, repositoryUrl+"/merge_requests")
+	case vcsutils.BitbucketServer:
+		return listBitbucketServerClosedPullRequests(client, repositoryUrl+"/pull-requests?state=ALL")
+	case vcsutils.BitbucketCloud:
+		return listBitbucketCloudClosedPullRequests(client, repositoryUrl+"/pullrequests?state=MERGED&state=DECLINED&state=SUPERSEDED")
+	case vcsutils.AzureRepos:
+		return listAzureReposClosedPullRequests(client, repositoryUrl+"/pullrequests")
+	default:
+		return nil, fmt.Errorf("listing the closed pull requests isn't supported for %s", provider.String())
+	}
+}
+
+// GitHub lists the merged pull requests as closed, with the time they were merged as the time they were closed
+func listGitHubClosedPullRequests(client *vcsapi.Client, pullRequestsUrl string) (closedPullRequests []closedPullRequest, err error) {
+	type gitHubPullRequest struct {
+		Head struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+		ClosedAt time.Time `json:"closed_at"`
+	}
+	pullRequests, err := vcsapi.List[gitHubPullRequest](client, pullRequestsUrl, vcsapi.PageSize)
+	if err != nil {
+		return
+	}
+	for _, pullRequest := range pullRequests {
+		closedPullRequests = append(closedPullRequests, closedPullRequest{sourceBranch: pullRequest.Head.Ref, closedAt: pullRequest.ClosedAt})
+	}
+	return
+}
+
+// GitLab sets the time the merge request was closed only for the closed merge requests, and the time it was merged for the merged ones
+func listGitLabClosedPullRequests(client *vcsapi.Client, mergeRequestsUrl string) (closedPullRequests []closedPullRequest, err error) {
+	type gitLabMergeRequest struct {
+		SourceBranch string     `json:"source_branch"`
+		MergedAt     *time.Time `json:"merged_at"`
+		ClosedAt     *time.Time `json:"closed_at"`
+	}
+	for _, state := range []string{"merged", "closed"} {
+		var mergeRequests []gitLabMergeRequest
+		if mergeRequests, err = vcsapi.List[gitLabMergeRequest](client, mergeRequestsUrl+"?state="+state, vcsapi.PageSize); err != nil {
+			return
+		}
+		for _, mergeRequest := range mergeRequests {
+			closedAt := mergeRequest.ClosedAt
+			if closedAt == nil {
+				closedAt = mergeRequest.MergedAt
+			}
+			if closedAt != nil {
+				closedPullRequests = append(closedPullRequests, closedPullRequest{sourceBranch: mergeRequest.SourceBranch, closedAt: *closedAt})
+			}
+		}
+	}
+	return
+}
+
+// Bitbucket Server returns the times in milliseconds since the epoch. Older servers don't return the time the pull request was closed,
+// so the time it was last updated is used instead.
+func listBitbucketServerClosedPullRequests(client *vcsapi.Client, pullRequestsUrl string) (closedPullRequests []closedPullRequest, err error) {
+	type bitbucketServerPullRequest struct {
+		State   string `json:"state"`
+		FromRef struct {
+			DisplayId string `json:"displayId"`
+		} `json:"fromRef"`
+		ClosedDate  int64 `json:"closedDate"`
+		UpdatedDate int64 `json:"updatedDate"`
+	}
+	pullRequests, err := vcsapi.List[bitbucketServerPullRequest](client, pullRequestsUrl, vcsapi.PageSize)
+	if err != nil {
+		return
+	}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.State == "OPEN" {
+			continue
+		}
+		closedDate := pullRequest.ClosedDate
+		if closedDate == 0 {
+			closedDate = pullRequest.UpdatedDate
+		}
+		closedPullRequests = append(closedPullRequests, closedPullRequest{sourceBranch: pullRequest.FromRef.DisplayId, closedAt: time.UnixMilli(closedDate)})
+	}
+	return
+}
+
+// Bitbucket Cloud doesn't return the time the pull request was closed, so the time it was last updated is used instead
+func listBitbucketCloudClosedPullRequests(client *vcsapi.Client, pullRequestsUrl string) (closedPullRequests []closedPullRequest, err error) {
+	type bitbucketCloudPullRequest struct {
+		Source struct {
+			Branch struct {
+				Name string `json:"name"`
+			} `json:"branch"`
+		} `json:"source"`
+		UpdatedOn time.Time `json:"updated_on"`
+	}
+	pullRequests, err := vcsapi.List[bitbucketCloudPullRequest](client, pullRequestsUrl, vcsapi.BitbucketCloudPullRequestsPageSize)
+	if err != nil {
+		return
+	}
+	for _, pullRequest := range pullRequests {
+		closedPullRequests = append(closedPullRequests, closedPullRequest{sourceBranch: pullRequest.Source.Branch.Name, closedAt: pullRequest.UpdatedOn})
+	}
+	return
+}
+
+// Azure Repos names the merged pull requests completed, and the closed ones abandoned
+func listAzureReposClosedPullRequests(client *vcsapi.Client, pullRequestsUrl string) (closedPullRequests []closedPullRequest, err error) {
+	type azureReposPullRequest struct {
+		SourceRefName string    `json:"sourceRefName"`
+		ClosedDate    time.Time `json:"closedDate"`
+	}
+	for _, status := range []string{"completed", "abandoned"} {
+		var pullRequests []azureReposPullRequest
+		listUrl := fmt.Sprintf("%s?searchCriteria.status=%s&api-version=%s", pullRequestsUrl, status, vcsapi.AzureApiVersion)
+		if pullRequests, err = vcsapi.List[azureReposPullRequest](client, listUrl, vcsapi.PageSize); err != nil {
+			return
+		}
+		for _, pullRequest := range pullRequests {
+			closedPullRequests = append(closedPullRequests, closedPullRequest{sourceBranch: strings.TrimPrefix(pullRequest.SourceRefName, "refs/heads/"), closedAt: pullRequest.ClosedDate})
+		}
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package scanrepository
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListClosedPullRequests(t *testing.T) {
+	closedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		provider  vcsutils.VcsProvider
+		responses map[string]string
+		expected  []closedPullRequest
+	}{
+		{
+			name:     "GitLab",
+			provider: vcsutils.GitLab,
+			responses: map[string]string{
+				"merged": `[{"source_branch":"frogbot-lodash-1a2b","merged_at":"2024-06-01T10:00:00Z","closed_at":null}]`,
+				"closed": `[{"source_branch":"frogbot-minimist-3c4d","merged_at":null,"closed_at":"2024-06-01T10:00:00Z"}]`,
+			},
+			expected: []closedPullRequest{{sourceBranch: "frogbot-lodash-1a2b", closedAt: closedAt}, {sourceBranch: "frogbot-minimist-3c4d", closedAt: closedAt}},
+		},
+		{
+			name:     "Bitbucket Server",
+			provider: vcsutils.BitbucketServer,
+			responses: map[string]string{
+				"ALL": `{"isLastPage":true,"values":[{"state":"OPEN","fromRef":{"displayId":"frogbot-open"},"updatedDate":1717236000000},` +
+					`{"state":"MERGED","fromRef":{"displayId":"frogbot-lodash-1a2b"},"closedDate":1717236000000,"updatedDate":1717322400000},` +
+					`{"state":"DECLINED","fromRef":{"displayId":"frogbot-minimist-3c4d"},"updatedDate":1717236000000}]}`,
+			},
+			expected: []closedPullRequest{{sourceBranch: "frogbot-lodash-1a2b", closedAt: closedAt}, {sourceBranch: "frogbot-minimist-3c4d", closedAt: closedAt}},
+		},
+		{
+			name:     "Azure Repos",
+			provider: vcsutils.AzureRepos,
+			responses: map[string]string{
+				"completed": `{"value":[{"sourceRefName":"refs/heads/frogbot-lodash-1a2b","closedDate":"2024-06-01T10:00:00Z"}]}`,
+				"abandoned": `{"value":[{"sourceRefName":"refs/heads/frogbot-minimist-3c4d","closedDate":"2024-06-01T10:00:00Z"}]}`,
+			},
+			expected: []closedPullRequest{{sourceBranch: "frogbot-lodash-1a2b", closedAt: closedAt}, {sourceBranch: "frogbot-minimist-3c4d", closedAt: closedAt}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				state := r.URL.Query().Get("state")
+				if tc.provider == vcsutils.AzureRepos {
+					state = r.URL.Query().Get("searchCriteria.status")
+				}
+				response, found := tc.responses[state]
+				assert.True(t, found, r.URL.String())
+				_, err := w.Write([]byte(response))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			closedPullRequests, err := listClosedPullRequests(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Project: "frogbot"}, "jfrog", "frogbot")
+			require.NoError(t, err)
+			require.Len(t, closedPullRequests, len(tc.expected))
+			for i, expected := range tc.expected {
+				assert.Equal(t, expected.sourceBranch, closedPullRequests[i].sourceBranch)
+				assert.True(t, expected.closedAt.Equal(closedPullRequests[i].closedAt), closedPullRequests[i].closedAt)
+			}
+		})
+	}
+}
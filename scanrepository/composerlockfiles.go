@@ -0,0 +1,23 @@
+package scanrepository
+
+import (
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Adds the vulnerable packages of the Composer lockfiles in the working directory to the vulnerabilities to fix.
+// The packages that composer.json requires are fixed by the Composer package handler, which updates their constraints and regenerates the lockfiles.
+// Failing to scan the lockfiles doesn't fail the scan of the repository.
+func (cfp *ScanRepositoryCmd) addComposerVulnerabilities(fullPathWd string, vulnerabilitiesMap map[string]*utils.VulnerabilityDetails) error {
+	vulnerabilities, err := cfp.composerAnalyzer.Analyze(cfp.baseWd, fullPathWd)
+	if err != nil {
+		log.Warn("Couldn't scan the packages of the Composer lockfiles:", err.Error())
+		return nil
+	}
+	for i := range vulnerabilities {
+		if err = cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
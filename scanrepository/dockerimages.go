@@ -0,0 +1,56 @@
+package scanrepository
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/dockerimage"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Adds the vulnerable base images of the Dockerfiles in the working directory to the vulnerabilities to fix, so their tags are bumped to fixed versions.
+// The OS packages of the Dockerfiles can't be fixed by bumping a tag, so they are only logged.
+// Failing to scan the Dockerfiles doesn't fail the scan of the repository.
+func (cfp *ScanRepositoryCmd) addDockerImageVulnerabilities(fullPathWd string, vulnerabilitiesMap map[string]*utils.VulnerabilityDetails) error {
+	vulnerabilities, err := cfp.dockerImageAnalyzer.Analyze(cfp.baseWd, fullPathWd)
+	if err != nil {
+		log.Warn("Couldn't scan the base images and OS packages of the Dockerfiles:", err.Error())
+		return nil
+	}
+	for _, vulnerability := range vulnerabilities {
+		if vulnerability.ComponentType != dockerimage.BaseImageType {
+			log.Info(fmt.Sprintf("The OS package %s %s of %s has the %s vulnerability. Update it in the Dockerfile manually", vulnerability.Name, vulnerability.Version, vulnerability.Dockerfile, vulnerability.IssueId))
+			continue
+		}
+		row := toVulnerabilityRow(vulnerability)
+		if err = cfp.addVulnerabilityToFixVersionsMap(&row, vulnerabilitiesMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The base image is a direct dependency of the Dockerfile, and is fixed by the Docker package handler
+func toVulnerabilityRow(vulnerability issues.DockerImageVulnerability) formats.VulnerabilityOrViolationRow {
+	component := formats.ComponentRow{Name: vulnerability.Name, Version: vulnerability.Version, Location: &formats.Location{File: vulnerability.Dockerfile, StartLine: vulnerability.Line}}
+	row := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           formats.SeverityDetails{Severity: vulnerability.Severity},
+			ImpactedDependencyName:    vulnerability.Name,
+			ImpactedDependencyVersion: vulnerability.Version,
+			ImpactedDependencyType:    techutils.Docker.ToFormal(),
+			Components:                []formats.ComponentRow{component},
+		},
+		FixedVersions: vulnerability.FixedVersions,
+		IssueId:       vulnerability.IssueId,
+		ImpactPaths:   [][]formats.ComponentRow{{component}},
+		Technology:    techutils.Docker,
+	}
+	for _, cve := range vulnerability.Cves {
+		row.Cves = append(row.Cves, formats.CveRow{Id: cve})
+	}
+	return row
+}
@@ -0,0 +1,31 @@
+package scanrepository
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/dockerimage"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerImageVulnerabilityFix(t *testing.T) {
+	cfp := ScanRepositoryCmd{}
+	vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+	for _, vulnerability := range []issues.DockerImageVulnerability{
+		{Dockerfile: "Dockerfile", Line: 1, ComponentType: dockerimage.BaseImageType, Name: "nginx", Version: "1.19", Severity: "High", IssueId: "XRAY-1", Cves: []string{"CVE-2021-23017"}, FixedVersions: []string{"[1.21.0]"}},
+		{Dockerfile: "Dockerfile", Line: 1, ComponentType: dockerimage.BaseImageType, Name: "nginx", Version: "1.19", Severity: "Medium", IssueId: "XRAY-2", FixedVersions: []string{"[1.20.1]"}},
+	} {
+		row := toVulnerabilityRow(vulnerability)
+		require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&row, vulnerabilitiesMap))
+	}
+	require.Contains(t, vulnerabilitiesMap, "nginx")
+	vulnDetails := vulnerabilitiesMap["nginx"]
+	// The tag that fixes all the vulnerabilities of the base image is suggested
+	assert.Equal(t, "1.21.0", vulnDetails.SuggestedFixedVersion)
+	assert.Equal(t, techutils.Docker, vulnDetails.Technology)
+	assert.True(t, vulnDetails.IsDirectDependency)
+	assert.Equal(t, []string{"CVE-2021-23017"}, vulnDetails.Cves)
+}
@@ -0,0 +1,36 @@
+package scanrepository
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// FixCampaignCmd fixes a single CVE or package across all the configured repositories.
+// The pull requests opened by the campaign share the campaign identifier in their titles and labels.
+// The repositories are fixed one after the other, as the fixes of a repository change the working directory and the environment of the process.
+type FixCampaignCmd struct {
+	// dryRun is used for testing purposes, mocking part of the git commands that requires networking
+	dryRun bool
+	// When dryRun is enabled, dryRunRepoPath specifies the repository local path to clone
+	dryRunRepoPath string
+}
+
+func (fc *FixCampaignCmd) Run(repoAggregator utils.RepoAggregator, client vcsclient.VcsClient, frogbotRepoConnection *utils.UrlAccessChecker) error {
+	for _, repository := range repoAggregator {
+		if repository.Campaign == nil {
+			return fmt.Errorf("no fix campaign was configured for the '%s' repository", repository.RepoName)
+		}
+		if repository.DetectionOnly {
+			log.Warn(fmt.Sprintf("The '%s' repository is configured to skip auto fixes. The '%s' campaign won't open a pull request for it", repository.RepoName, repository.Campaign.Id))
+		}
+	}
+	if len(repoAggregator) > 0 {
+		log.Info(fmt.Sprintf("Starting the '%s' fix campaign across %d repositories", repoAggregator[0].Campaign.Id, len(repoAggregator)))
+	}
+	// The scan of multiple repositories handles the repositories serially, and doesn't stop on the failure of a repository
+	scanMultipleRepositories := &ScanMultipleRepositories{dryRun: fc.dryRun, dryRunRepoPath: fc.dryRunRepoPath}
+	return scanMultipleRepositories.Run(repoAggregator, client, frogbotRepoConnection)
+}
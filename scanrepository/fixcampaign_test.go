@@ -0,0 +1,47 @@
+package scanrepository
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixCampaignWithoutCampaign(t *testing.T) {
+	repoAggregator := utils.RepoAggregator{
+		{Params: utils.Params{Git: utils.Git{RepoName: "repo-1", Campaign: &utils.Campaign{Id: "log4shell"}}}},
+		{Params: utils.Params{Git: utils.Git{RepoName: "repo-2"}}},
+	}
+	// No repository is fixed if any of them isn't part of the campaign
+	assert.EqualError(t, (&FixCampaignCmd{}).Run(repoAggregator, nil, nil), "no fix campaign was configured for the 'repo-2' repository")
+}
+
+func TestRoutePullRequestWithCampaignLabel(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	git := utils.Git{
+		GitProvider:       vcsutils.GitHub,
+		VcsInfo:           vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"},
+		RepoOwner:         "jfrog",
+		RepoName:          "frogbot",
+		PullRequestLabels: []string{"security"},
+		Campaign:          &utils.Campaign{Id: "log4shell"},
+	}
+	repository := &utils.Repository{Params: utils.Params{Git: git}}
+	cfp := &ScanRepositoryCmd{scanDetails: &utils.ScanDetails{Git: &repository.Git}}
+	// The campaign label is added to the configured labels
+	cfp.routePullRequest(repository, 7)
+	assert.Equal(t, []string{`POST /repos/jfrog/frogbot/issues/7/labels {"labels":["security","campaign:log4shell"]}`}, requests)
+	assert.Equal(t, []string{"security"}, repository.PullRequestLabels)
+}
@@ -0,0 +1,136 @@
+package scanrepository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/maps"
+)
+
+// A fix that wasn't opened in a pull request, since a limit of the fix pull requests was reached or the run is outside the allowed windows.
+// The next runs find the vulnerability again and open its fix.
+type queuedFix struct {
+	Description string
+	Reason      string
+}
+
+// Loads the limits of the new fix pull requests, and counts the open fix pull requests of the repository if their number is limited
+func (cfp *ScanRepositoryCmd) loadFixPullRequestsLimits(repository *utils.Repository) (err error) {
+	cfp.maxOpenFixPullRequests = repository.MaxOpenFixPullRequests
+	cfp.maxNewFixPullRequests = repository.MaxNewFixPullRequests
+	cfp.openFixPullRequests, cfp.newFixPullRequests = 0, 0
+	cfp.queuedFixes = nil
+	cfp.maxFixesPerSeverity = repository.FixMaxPullRequestsPerSeverity
+	cfp.fixesPerSeverity = map[string]int{}
+	if cfp.fixPullRequestsWindows, err = utils.ParseFixWindows(repository.FixPullRequestsWindows); err != nil {
+		return
+	}
+	if cfp.maxOpenFixPullRequests == 0 {
+		return
+	}
+	pullRequests, err := cfp.scanDetails.Client().ListOpenPullRequestsWithBody(context.Background(), repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		return fmt.Errorf("couldn't count the open fix pull requests: %s", err.Error())
+	}
+	for _, pullRequest := range pullRequests {
+		if outputwriter.IsFrogbotFixPullRequest(pullRequest.Body) {
+			cfp.openFixPullRequests++
+		}
+	}
+	log.Debug(fmt.Sprintf("%d of the %d open pull requests are Frogbot fix pull requests", cfp.openFixPullRequests, len(pullRequests)))
+	return
+}
+
+// Returns the reason that a new fix pull request of the vulnerabilities can't be opened, or an empty string if it can.
+// The pull request counts towards the limit of the highest severity of its fixes. The existing fix pull requests are updated regardless of the limits.
+func (cfp *ScanRepositoryCmd) getFixPullRequestsLimitReason(vulnerabilities ...*utils.VulnerabilityDetails) string {
+	if len(vulnerabilities) > 0 {
+		severity := getHighestSeverity(vulnerabilities...).String()
+		if limit, limited := cfp.maxFixesPerSeverity[severity]; limited && cfp.fixesPerSeverity[severity] >= limit {
+			return fmt.Sprintf("the limit of %d %s fix pull requests per run was reached", limit, severity)
+		}
+	}
+	switch {
+	case !utils.IsInFixWindows(cfp.fixPullRequestsWindows, time.Now()):
+		return "the run is outside the allowed fix pull requests windows"
+	case cfp.maxNewFixPullRequests > 0 && cfp.newFixPullRequests >= cfp.maxNewFixPullRequests:
+		return fmt.Sprintf("the limit of %d new fix pull requests per run was reached", cfp.maxNewFixPullRequests)
+	case cfp.maxOpenFixPullRequests > 0 && cfp.openFixPullRequests+cfp.newFixPullRequests >= cfp.maxOpenFixPullRequests:
+		return fmt.Sprintf("the limit of %d open fix pull requests was reached", cfp.maxOpenFixPullRequests)
+	}
+	return ""
+}
+
+// Queues the fix for the next runs if a new fix pull request can't be opened. Returns true if the fix was queued.
+func (cfp *ScanRepositoryCmd) queueFixIfLimited(description string, vulnerabilities ...*utils.VulnerabilityDetails) bool {
+	reason := cfp.getFixPullRequestsLimitReason(vulnerabilities...)
+	if reason == "" {
+		return false
+	}
+	cfp.queueFix(description, reason)
+	return true
+}
+
+func (cfp *ScanRepositoryCmd) queueFix(description, reason string) {
+	log.Info(fmt.Sprintf("The fix of %s is queued for the next run, since %s", description, reason))
+	cfp.queuedFixes = append(cfp.queuedFixes, queuedFix{Description: description, Reason: reason})
+	if cfp.currentBranchSummary != nil {
+		cfp.currentBranchSummary.FixesQueued++
+	}
+}
+
+// Counts a new fix pull request towards the limits of the run and of the highest severity of its fixes
+func (cfp *ScanRepositoryCmd) countNewFixPullRequest(vulnerabilities ...*utils.VulnerabilityDetails) {
+	cfp.newFixPullRequests++
+	if len(cfp.maxFixesPerSeverity) == 0 || len(vulnerabilities) == 0 {
+		return
+	}
+	if cfp.fixesPerSeverity == nil {
+		cfp.fixesPerSeverity = map[string]int{}
+	}
+	cfp.fixesPerSeverity[getHighestSeverity(vulnerabilities...).String()]++
+}
+
+// Returns the highest severity of the fixes, which is the severity of their pull request
+func getHighestSeverity(vulnerabilities ...*utils.VulnerabilityDetails) (highest severityutils.Severity) {
+	for i, vulnDetails := range vulnerabilities {
+		if severity := severityutils.GetSeverity(vulnDetails.Severity); i == 0 || severityutils.CompareSeverity(severity, highest) > 0 {
+			highest = severity
+		}
+	}
+	return
+}
+
+// Returns the fixes of all the working directories of the aggregated pull request
+func getAggregatedVulnerabilities(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) (vulnerabilities []*utils.VulnerabilityDetails) {
+	for _, wdVulnerabilities := range vulnerabilitiesMap {
+		vulnerabilities = append(vulnerabilities, maps.Values(wdVulnerabilities)...)
+	}
+	return
+}
+
+// Logs the fixes that were queued for the next runs, for each reason
+func (cfp *ScanRepositoryCmd) logQueuedFixesSummary() {
+	if len(cfp.queuedFixes) == 0 {
+		return
+	}
+	descriptionsByReason := map[string][]string{}
+	var reasons []string
+	for _, fix := range cfp.queuedFixes {
+		if len(descriptionsByReason[fix.Reason]) == 0 {
+			reasons = append(reasons, fix.Reason)
+		}
+		descriptionsByReason[fix.Reason] = append(descriptionsByReason[fix.Reason], fix.Description)
+	}
+	var summary []string
+	for _, reason := range reasons {
+		summary = append(summary, fmt.Sprintf("Since %s: %s", reason, strings.Join(descriptionsByReason[reason], ", ")))
+	}
+	log.Info(fmt.Sprintf("%d fixes are queued, and their pull requests will be opened by the next runs:\n%s", len(cfp.queuedFixes), strings.Join(summary, "\n")))
+}
@@ -0,0 +1,90 @@
+package scanrepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixPullRequestsLimits(t *testing.T) {
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().ListOpenPullRequestsWithBody(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{
+		{ID: 1, Body: outputwriter.GetBanner(outputwriter.VulnerabilitiesFixPrBannerSource) + "fix details"},
+		{ID: 2, Body: "Bumps lodash from 4.17.20 to 4.17.21"},
+	}, nil)
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", MaxOpenFixPullRequests: 3, MaxNewFixPullRequests: 1}}}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(mockVcsClient, nil, &repository.Git)}
+	require.NoError(t, cfp.loadFixPullRequestsLimits(repository))
+	assert.Equal(t, 1, cfp.openFixPullRequests)
+	summary := cfp.startBranchSummary("main")
+
+	// A new fix pull request can be opened until the limit of the run is reached
+	assert.False(t, cfp.queueFixIfLimited("'lodash' to version '4.17.21'"))
+	cfp.newFixPullRequests++
+	assert.True(t, cfp.queueFixIfLimited("'minimist' to version '1.2.6'"))
+	assert.Equal(t, []queuedFix{{Description: "'minimist' to version '1.2.6'", Reason: "the limit of 1 new fix pull requests per run was reached"}}, cfp.queuedFixes)
+
+	// The open fix pull requests of the repository and of the run are limited together
+	cfp.maxNewFixPullRequests = 0
+	assert.False(t, cfp.queueFixIfLimited("'express' to version '4.19.2'"))
+	cfp.newFixPullRequests++
+	assert.True(t, cfp.queueFixIfLimited("'express' to version '4.19.2'"))
+	assert.Equal(t, "the limit of 3 open fix pull requests was reached", cfp.queuedFixes[1].Reason)
+	assert.Equal(t, 2, summary.FixesQueued)
+	cfp.logQueuedFixesSummary()
+
+	// The fixes are queued outside the windows
+	repository.MaxOpenFixPullRequests, repository.MaxNewFixPullRequests = 0, 0
+	repository.FixPullRequestsWindows = []string{time.Now().UTC().Add(72 * time.Hour).Weekday().String()[:3] + " 00:00-24:00"}
+	require.NoError(t, cfp.loadFixPullRequestsLimits(repository))
+	assert.Empty(t, cfp.queuedFixes)
+	assert.Equal(t, "the run is outside the allowed fix pull requests windows", cfp.getFixPullRequestsLimitReason())
+	repository.FixPullRequestsWindows = []string{"* 00:00-24:00"}
+	require.NoError(t, cfp.loadFixPullRequestsLimits(repository))
+	assert.Empty(t, cfp.getFixPullRequestsLimitReason())
+}
+
+func TestLimitFixesPerSeverity(t *testing.T) {
+	newVulnDetails := func(severity string) *utils.VulnerabilityDetails {
+		return &utils.VulnerabilityDetails{
+			VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: severity}}},
+			SuggestedFixedVersion:       "2.0.0",
+		}
+	}
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{FixMaxPullRequestsPerSeverity: map[string]int{"Critical": 1, "Low": 0}}}}
+	cfp := ScanRepositoryCmd{}
+	require.NoError(t, cfp.loadFixPullRequestsLimits(repository))
+
+	critical, high, low := newVulnDetails("Critical"), newVulnDetails("High"), newVulnDetails("Low")
+
+	// The severity is counted only when a new pull request is created
+	assert.False(t, cfp.queueFixIfLimited("'lodash' to version '2.0.0'", critical))
+	assert.False(t, cfp.queueFixIfLimited("'lodash' to version '2.0.0'", critical))
+	cfp.countNewFixPullRequest(critical)
+	assert.True(t, cfp.queueFixIfLimited("'minimist' to version '2.0.0'", critical))
+	assert.False(t, cfp.queueFixIfLimited("'express' to version '2.0.0'", high))
+	assert.True(t, cfp.queueFixIfLimited("'debug' to version '2.0.0'", low))
+	assert.Equal(t, []queuedFix{
+		{Description: "'minimist' to version '2.0.0'", Reason: "the limit of 1 Critical fix pull requests per run was reached"},
+		{Description: "'debug' to version '2.0.0'", Reason: "the limit of 0 Low fix pull requests per run was reached"},
+	}, cfp.queuedFixes)
+
+	// An aggregated pull request counts as a single pull request of the highest severity of its fixes
+	require.NoError(t, cfp.loadFixPullRequestsLimits(repository))
+	aggregatedFixes := getAggregatedVulnerabilities(map[string]map[string]*utils.VulnerabilityDetails{"/project": {"lodash": critical}, "/project/web": {"debug": low}})
+	assert.Equal(t, severityutils.Critical, getHighestSeverity(aggregatedFixes...))
+	assert.False(t, cfp.queueFixIfLimited("the aggregated pull request", aggregatedFixes...))
+	cfp.countNewFixPullRequest(aggregatedFixes...)
+	assert.Equal(t, map[string]int{"Critical": 1}, cfp.fixesPerSeverity)
+	assert.True(t, cfp.queueFixIfLimited("the aggregated pull request", aggregatedFixes...))
+}
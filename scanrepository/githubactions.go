@@ -0,0 +1,93 @@
+package scanrepository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/githubactions"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Adds the issues of the actions of the GitHub Actions workflows to the vulnerabilities to fix in the root directory of the repository, where the workflows are.
+// The vulnerable actions are bumped to fixed versions, and the mutable references are pinned to commit SHAs when pinning is enabled.
+// Each action is fixed once per run, so the other references of the action are fixed by the next runs.
+// Failing to check the workflows doesn't fail the scan of the repository.
+func (cfp *ScanRepositoryCmd) addGitHubActionsVulnerabilities(vulnerabilitiesByPathMap map[string]map[string]*utils.VulnerabilityDetails) (isAdded bool) {
+	actionIssues, err := cfp.gitHubActionsAnalyzer.Analyze(cfp.baseWd)
+	if err != nil {
+		log.Warn("Couldn't check the actions of the GitHub Actions workflows:", err.Error())
+		return
+	}
+	// Bumping a vulnerable action fixes its reference too, so the vulnerable versions are fixed first
+	sort.SliceStable(actionIssues, func(i, j int) bool {
+		return actionIssues[i].Type == githubactions.VulnerableVersionType && actionIssues[j].Type != githubactions.VulnerableVersionType
+	})
+	rootVulnerabilities := vulnerabilitiesByPathMap[cfp.baseWd]
+	if rootVulnerabilities == nil {
+		rootVulnerabilities = make(map[string]*utils.VulnerabilityDetails)
+	}
+	for _, actionIssue := range actionIssues {
+		if actionIssue.Type == githubactions.MutableReferenceType && !cfp.scanDetails.PinGitHubActions {
+			log.Info(fmt.Sprintf("The %s action is referenced by the mutable reference '%s' in %s:%d. Pin it to a commit SHA, or set %s to pin it automatically", actionIssue.Action, actionIssue.Ref, actionIssue.Workflow, actionIssue.Line, utils.PinGitHubActionsEnv))
+			continue
+		}
+		if actionIssue.FixedVersion == "" {
+			log.Info(fmt.Sprintf("The %s action of %s:%d has no fixed version for the %s issue", actionIssue.Action, actionIssue.Workflow, actionIssue.Line, actionIssue.Type))
+			continue
+		}
+		row := toGitHubActionRow(actionIssue)
+		if !cfp.isTargetedVulnerability(&row) {
+			continue
+		}
+		if vulnDetails, exists := rootVulnerabilities[row.ImpactedDependencyName]; exists {
+			// The version that fixes all the vulnerabilities of the action is suggested
+			if vulnDetails.ImpactedDependencyVersion == row.ImpactedDependencyVersion && !githubactions.IsCommitSha(vulnDetails.SuggestedFixedVersion) && !githubactions.IsCommitSha(actionIssue.FixedVersion) {
+				updateActionFixVersionIfMax(vulnDetails, actionIssue.FixedVersion)
+			}
+			continue
+		}
+		vulnDetails := utils.NewVulnerabilityDetails(row, actionIssue.FixedVersion)
+		vulnDetails.SetIsDirectDependency(true)
+		rootVulnerabilities[row.ImpactedDependencyName] = vulnDetails
+		isAdded = true
+	}
+	if isAdded {
+		vulnerabilitiesByPathMap[cfp.baseWd] = rootVulnerabilities
+	}
+	return
+}
+
+// The action is a direct dependency of the workflow, identified by its repository.
+// The version of an action that is pinned to a commit SHA is the version in the comment that follows the SHA.
+func toGitHubActionRow(actionIssue issues.GitHubActionIssue) formats.VulnerabilityOrViolationRow {
+	currentVersion := actionIssue.Version
+	component := formats.ComponentRow{Name: githubactions.Repository(actionIssue.Action), Version: currentVersion, Location: &formats.Location{File: actionIssue.Workflow, StartLine: actionIssue.Line}}
+	row := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           formats.SeverityDetails{Severity: actionIssue.Severity},
+			ImpactedDependencyName:    component.Name,
+			ImpactedDependencyVersion: currentVersion,
+			ImpactedDependencyType:    "GitHub Actions",
+			Components:                []formats.ComponentRow{component},
+		},
+		FixedVersions: []string{"[" + actionIssue.FixedVersion + "]"},
+		IssueId:       actionIssue.IssueId,
+		ImpactPaths:   [][]formats.ComponentRow{{component}},
+		Technology:    githubactions.Technology,
+	}
+	for _, cve := range actionIssue.Cves {
+		row.Cves = append(row.Cves, formats.CveRow{Id: cve})
+	}
+	return row
+}
+
+func updateActionFixVersionIfMax(vulnDetails *utils.VulnerabilityDetails, fixVersion string) {
+	if version.NewVersion(strings.TrimPrefix(vulnDetails.SuggestedFixedVersion, "v")).Compare(strings.TrimPrefix(fixVersion, "v")) > 0 {
+		vulnDetails.SuggestedFixedVersion = fixVersion
+	}
+}
@@ -0,0 +1,69 @@
+package scanrepository
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/githubactions"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const checkoutSha = "b4ffde65f46336ab88eb53be808477a3936bae11"
+
+func TestAddGitHubActionsVulnerabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/advisories":
+			_, err := w.Write([]byte(`[
+				{"ghsa_id":"GHSA-0000-0000-0001","severity":"high","vulnerabilities":[{"package":{"ecosystem":"actions","name":"tj-actions/changed-files"},"vulnerable_version_range":"< 45.0.8","first_patched_version":"45.0.8"}]},
+				{"ghsa_id":"GHSA-mrrh-fwg8-r2c3","cve_id":"CVE-2025-30066","severity":"high","vulnerabilities":[{"package":{"ecosystem":"actions","name":"tj-actions/changed-files"},"vulnerable_version_range":"< 46.0.1","first_patched_version":"46.0.1"}]}]`))
+			assert.NoError(t, err)
+		case "/repos/actions/checkout/commits/v4":
+			_, err := w.Write([]byte(checkoutSha))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	baseWd := t.TempDir()
+	workflowsDir := filepath.Join(baseWd, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "build.yml"), []byte("steps:\n  - uses: actions/checkout@v4\n  - uses: tj-actions/changed-files@v45.0.7\n  - uses: actions/setup-go@main\n"), 0644))
+
+	testCases := []struct {
+		name     string
+		pin      bool
+		expected map[string]string
+	}{
+		{name: "Vulnerable versions", expected: map[string]string{"tj-actions/changed-files": "v46.0.1"}},
+		// The mutable references that can't be resolved to commit SHAs aren't fixed
+		{name: "Pin mutable references", pin: true, expected: map[string]string{"tj-actions/changed-files": "v46.0.1", "actions/checkout": checkoutSha}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfp := ScanRepositoryCmd{
+				baseWd:                baseWd,
+				scanDetails:           &utils.ScanDetails{Git: &utils.Git{PinGitHubActions: tc.pin}},
+				gitHubActionsAnalyzer: githubactions.NewAnalyzer(true, tc.pin, vcsutils.GitHub, vcsclient.VcsInfo{APIEndpoint: server.URL}),
+			}
+			vulnerabilitiesByPathMap := map[string]map[string]*utils.VulnerabilityDetails{}
+			assert.True(t, cfp.addGitHubActionsVulnerabilities(vulnerabilitiesByPathMap))
+			require.Contains(t, vulnerabilitiesByPathMap, baseWd)
+			actual := map[string]string{}
+			for name, vulnDetails := range vulnerabilitiesByPathMap[baseWd] {
+				assert.Equal(t, githubactions.Technology, vulnDetails.Technology)
+				actual[name] = vulnDetails.SuggestedFixedVersion
+			}
+			// The version that fixes all the vulnerabilities of the action is suggested
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
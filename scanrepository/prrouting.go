@@ -0,0 +1,44 @@
+package scanrepository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/prrouting"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Adds the configured labels, assignees and reviewers to a new fix pull request.
+// The pull requests of a fix campaign are also labeled with the campaign identifier.
+// A failure to route the pull request is reported and doesn't fail the fix.
+func (cfp *ScanRepositoryCmd) routePullRequest(repository *utils.Repository, pullRequestId int64) {
+	labels := repository.PullRequestLabels
+	if campaign := cfp.getCampaign(); campaign != nil {
+		labels = append(slices.Clone(labels), campaign.PullRequestLabel())
+	}
+	if len(labels)+len(repository.PullRequestAssignees)+len(repository.PullRequestReviewers)+len(repository.PullRequestTeamReviewers) == 0 {
+		return
+	}
+	router, err := prrouting.NewRouter(repository.GitProvider, repository.VcsInfo, repository.RepoOwner, repository.RepoName)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	if len(labels) > 0 {
+		if err = router.AddLabels(pullRequestId, labels); err != nil {
+			log.Warn(fmt.Sprintf("Couldn't add the labels %s to pull request #%d: %s", strings.Join(labels, ", "), pullRequestId, err.Error()))
+		}
+	}
+	if len(repository.PullRequestAssignees) > 0 {
+		if err = router.AddAssignees(pullRequestId, repository.PullRequestAssignees); err != nil {
+			log.Warn(fmt.Sprintf("Couldn't assign pull request #%d to %s: %s", pullRequestId, strings.Join(repository.PullRequestAssignees, ", "), err.Error()))
+		}
+	}
+	if len(repository.PullRequestReviewers)+len(repository.PullRequestTeamReviewers) > 0 {
+		if err = router.RequestReviewers(pullRequestId, repository.PullRequestReviewers, repository.PullRequestTeamReviewers); err != nil {
+			log.Warn(fmt.Sprintf("Couldn't request the reviewers of pull request #%d: %s", pullRequestId, err.Error()))
+		}
+	}
+}
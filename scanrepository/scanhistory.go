@@ -0,0 +1,44 @@
+package scanrepository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/scanhistory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Loads the history of the scans, when it is kept in a file or in Artifactory
+func (cfp *ScanRepositoryCmd) loadScanHistory(repository *utils.Repository) (err error) {
+	cfp.scanHistory = nil
+	if cfp.scanHistoryStorage = scanhistory.NewStorage(repository.ScanHistoryFile, repository.ScanHistoryArtifactoryPath, &repository.Server); cfp.scanHistoryStorage == nil {
+		return
+	}
+	cfp.scanHistory, err = scanhistory.Load(cfp.scanHistoryStorage)
+	return
+}
+
+func (cfp *ScanRepositoryCmd) saveScanHistory() error {
+	if cfp.scanHistory == nil {
+		return nil
+	}
+	return cfp.scanHistory.Save(cfp.scanHistoryStorage)
+}
+
+// Records the summary of the scan of the current project in the history, and logs the change since its last scan.
+// The change is added to the aggregated fix pull request of the project.
+func (cfp *ScanRepositoryCmd) recordScanSummary(projectIssues *issues.ScansIssuesCollection) {
+	cfp.scanDelta = nil
+	if cfp.scanHistory == nil {
+		return
+	}
+	branch, project := cfp.scanDetails.BaseBranch(), strings.Join(cfp.scanDetails.Project.WorkingDirs, ", ")
+	cfp.scanDelta = cfp.scanHistory.Add(scanhistory.NewSummary(branch, project, projectIssues))
+	if cfp.scanDelta == nil {
+		log.Info(fmt.Sprintf("The first scan of the '%s' branch is recorded in the scan history", branch))
+		return
+	}
+	log.Info(fmt.Sprintf("Since the last scan of the '%s' branch: %s", branch, cfp.scanDelta.String()))
+}
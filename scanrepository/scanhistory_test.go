@@ -0,0 +1,46 @@
+package scanrepository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/scanhistory"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordScanSummary(t *testing.T) {
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{ScanHistoryFile: filepath.Join(t.TempDir(), "frogbot-history.json")}}}
+	cfp := &ScanRepositoryCmd{scanDetails: &utils.ScanDetails{Git: &repository.Git}}
+	cfp.scanDetails.SetBaseBranch("main")
+	cfp.scanDetails.SetProject(&utils.Project{WorkingDirs: []string{"."}})
+	require.NoError(t, cfp.loadScanHistory(repository))
+
+	newIssues := func(cves ...string) *issues.ScansIssuesCollection {
+		scanIssues := &issues.ScansIssuesCollection{}
+		for _, cve := range cves {
+			scanIssues.ScaVulnerabilities = append(scanIssues.ScaVulnerabilities, formats.VulnerabilityOrViolationRow{Cves: []formats.CveRow{{Id: cve}}})
+		}
+		return scanIssues
+	}
+	// The first scan has no previous scan to compare to
+	cfp.recordScanSummary(newIssues("CVE-2022-3517", "CVE-2021-44906"))
+	assert.Nil(t, cfp.scanDelta)
+	require.NoError(t, cfp.saveScanHistory())
+
+	// The history is kept between the runs
+	require.NoError(t, cfp.loadScanHistory(repository))
+	cfp.recordScanSummary(newIssues("CVE-2021-44906", "CVE-2020-28469"))
+	require.NotNil(t, cfp.scanDelta)
+	assert.Equal(t, &scanhistory.Delta{New: []string{"CVE-2020-28469"}, Resolved: []string{"CVE-2022-3517"}}, cfp.scanDelta)
+
+	// The history isn't kept if its storage isn't set
+	repository.ScanHistoryFile = ""
+	require.NoError(t, cfp.loadScanHistory(repository))
+	cfp.recordScanSummary(newIssues("CVE-2021-44906"))
+	assert.Nil(t, cfp.scanDelta)
+	assert.NoError(t, cfp.saveScanHistory())
+}
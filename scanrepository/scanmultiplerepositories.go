@@ -2,27 +2,203 @@ package scanrepository
 
 import (
 	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+
 	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/workitems"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// Scans only the repository of the configuration with this name. The scan of multiple repositories runs each repository
+	// in a separate process with this flag.
+	RepositoryFlag = "repository"
+
+	repoScanSucceeded = "Succeeded"
+	repoScanFailed    = "Failed"
 )
 
 type ScanMultipleRepositories struct {
+	// The repository to scan, out of the repositories of the configuration. All the repositories are scanned if it's empty.
+	Repository string
+	// The environment variables of the process before the command started, which the processes that scan the repositories run with.
+	// The repositories are scanned in the process of the command, one after the other, if it's nil.
+	Env []string
 	// dryRun is used for testing purposes, mocking part of the git commands that requires networking
 	dryRun bool
 	// When dryRun is enabled, dryRunRepoPath specifies the repository local path to clone
 	dryRunRepoPath string
 }
 
+// repositoryScanStatus holds the outcome of scanning and fixing a single repository
+type repositoryScanStatus struct {
+	repoName string
+	err      error
+}
+
 func (saf *ScanMultipleRepositories) Run(repoAggregator utils.RepoAggregator, client vcsclient.VcsClient, frogbotRepoConnection *utils.UrlAccessChecker) (err error) {
+	if saf.Repository != "" {
+		return saf.scanSingleRepository(repoAggregator, client, frogbotRepoConnection)
+	}
+	if len(repoAggregator) == 0 {
+		return
+	}
+	// The scan of a repository changes the working directory and the environment variables of its process.
+	// The repositories are scanned concurrently only in separate processes.
+	scanFunc, maxConcurrentRepos := saf.scanRepositoryProcess, repoAggregator[0].MaxConcurrentRepos
+	if saf.Env == nil {
+		scanFunc, maxConcurrentRepos = func(repository *utils.Repository) error {
+			return saf.scanRepository(repository, client, frogbotRepoConnection)
+		}, 1
+	}
+	statuses := scanRepositoriesConcurrently(repoAggregator, maxConcurrentRepos, scanFunc)
+	summaryContent := &repositoriesSummaryContent{statuses: statuses}
+	log.Info("Scan multiple repositories summary:\n" + summaryContent.MarkdownDescription())
+	for _, status := range statuses {
+		if status.err != nil {
+			err = errors.Join(err, fmt.Errorf("repository '%s' scan failed with the following error:\n%s", status.repoName, status.err.Error()))
+		}
+	}
+	if e := postRepositoriesSummary(&repoAggregator[0], summaryContent); e != nil {
+		err = errors.Join(err, e)
+	}
+	return
+}
+
+// Scans the repository of the Repository field, in the process that the scan of multiple repositories started for it
+func (saf *ScanMultipleRepositories) scanSingleRepository(repoAggregator utils.RepoAggregator, client vcsclient.VcsClient, frogbotRepoConnection *utils.UrlAccessChecker) error {
+	for i := range repoAggregator {
+		if repoAggregator[i].RepoName == saf.Repository {
+			return saf.scanRepository(&repoAggregator[i], client, frogbotRepoConnection)
+		}
+	}
+	return fmt.Errorf("the '%s' repository isn't one of the repositories of the configuration", saf.Repository)
+}
+
+func (saf *ScanMultipleRepositories) scanRepository(repository *utils.Repository, client vcsclient.VcsClient, frogbotRepoConnection *utils.UrlAccessChecker) error {
+	// Each repository is handled by its own command instance, so that no state is shared between the repositories
 	scanRepositoryCmd := &ScanRepositoryCmd{dryRun: saf.dryRun, dryRunRepoPath: saf.dryRunRepoPath, baseWd: saf.dryRunRepoPath}
+	repository.OutputWriter.SetHasInternetConnection(frogbotRepoConnection.IsConnected())
+	scanRepositoryCmd.XrayVersion = repository.XrayVersion
+	scanRepositoryCmd.XscVersion = repository.XscVersion
+	return scanRepositoryCmd.scanAndFixRepository(repository, client)
+}
 
+// Scans the repository in a new Frogbot process, which loads the configuration from the environment variables of the command.
+// The output of the process is logged once it's done, so the outputs of the repositories that are scanned concurrently aren't mixed.
+func (saf *ScanMultipleRepositories) scanRepositoryProcess(repository *utils.Repository) error {
+	output, err := utils.RunCommandProcess(saf.Env, getRepositoryProcessArgs(repository)...)
+	log.Info(fmt.Sprintf("The output of the scan of the '%s' repository:\n%s", repository.RepoName, output))
+	return err
+}
+
+func getRepositoryProcessArgs(repository *utils.Repository) []string {
+	return []string{utils.ScanMultipleRepositories, "--" + RepositoryFlag, repository.RepoName}
+}
+
+// Scans the given repositories using a bounded pool of workers.
+// A failure in one repository is recorded in its status and doesn't stop the scan of the other repositories.
+func scanRepositoriesConcurrently(repoAggregator utils.RepoAggregator, maxConcurrentRepos int, scanFunc func(repository *utils.Repository) error) []repositoryScanStatus {
+	if maxConcurrentRepos < 1 {
+		maxConcurrentRepos = 1
+	}
+	statuses := make([]repositoryScanStatus, len(repoAggregator))
+	repoIndexes := make(chan int)
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < maxConcurrentRepos; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for repoNum := range repoIndexes {
+				statuses[repoNum] = scanRepositoryIsolated(&repoAggregator[repoNum], scanFunc)
+			}
+		}()
+	}
 	for repoNum := range repoAggregator {
-		repoAggregator[repoNum].OutputWriter.SetHasInternetConnection(frogbotRepoConnection.IsConnected())
-		scanRepositoryCmd.XrayVersion = repoAggregator[repoNum].XrayVersion
-		scanRepositoryCmd.XscVersion = repoAggregator[repoNum].XscVersion
-		if e := scanRepositoryCmd.scanAndFixRepository(&repoAggregator[repoNum], client); e != nil {
-			err = errors.Join(err, e)
-		}
+		repoIndexes <- repoNum
 	}
+	close(repoIndexes)
+	waitGroup.Wait()
+	return statuses
+}
+
+// Runs the scan of a single repository, converting an unexpected panic into an error to keep the other workers running
+func scanRepositoryIsolated(repository *utils.Repository, scanFunc func(repository *utils.Repository) error) (status repositoryScanStatus) {
+	status.repoName = repository.RepoName
+	defer func() {
+		if r := recover(); r != nil {
+			status.err = fmt.Errorf("unexpected error: %v", r)
+		}
+	}()
+	log.Info("Scanning repository:", repository.RepoName)
+	status.err = scanFunc(repository)
 	return
 }
+
+// Posts the summary to the issue of the summary repository, if it's configured.
+// The scanned repositories share the owner and the Git provider, so the summary repository is of the same owner.
+func postRepositoriesSummary(repository *utils.Repository, summaryContent *repositoriesSummaryContent) error {
+	if repository.RepositoriesSummaryRepo == "" {
+		return nil
+	}
+	tracker, err := workitems.NewTracker(repository.GitProvider, repository.VcsInfo, repository.RepoOwner, repository.RepositoriesSummaryRepo, repository.AzureWorkItemType)
+	if err != nil {
+		return err
+	}
+	if err = workitems.CreateOrUpdate(tracker, summaryContent); err != nil {
+		return errors.New("couldn't post the repositories summary: " + err.Error())
+	}
+	return nil
+}
+
+// The content of the repositories status issue, which is updated on each run
+type repositoriesSummaryContent struct {
+	statuses []repositoryScanStatus
+}
+
+func (rsc *repositoriesSummaryContent) Title() string {
+	return fmt.Sprintf("%s Repositories scan status", outputwriter.FrogbotTitlePrefix)
+}
+
+func (rsc *repositoriesSummaryContent) rows() [][]string {
+	rows := [][]string{{"Repository", "Status", "Details"}}
+	for _, status := range rsc.statuses {
+		if status.err != nil {
+			rows = append(rows, []string{status.repoName, repoScanFailed, strings.Join(strings.Fields(status.err.Error()), " ")})
+			continue
+		}
+		rows = append(rows, []string{status.repoName, repoScanSucceeded, ""})
+	}
+	return rows
+}
+
+func (rsc *repositoriesSummaryContent) MarkdownDescription() string {
+	table := outputwriter.NewMarkdownTable(rsc.rows()[0]...)
+	for _, row := range rsc.rows()[1:] {
+		table.AddRow(row...)
+	}
+	return table.Build()
+}
+
+func (rsc *repositoriesSummaryContent) HtmlDescription() string {
+	var builder strings.Builder
+	builder.WriteString("<table>")
+	for i, row := range rsc.rows() {
+		cellTag := "td"
+		if i == 0 {
+			cellTag = "th"
+		}
+		builder.WriteString("<tr>")
+		for _, cell := range row {
+			builder.WriteString(fmt.Sprintf("<%s>%s</%s>", cellTag, html.EscapeString(cell), cellTag))
+		}
+		builder.WriteString("</tr>")
+	}
+	builder.WriteString("</table>")
+	return builder.String()
+}
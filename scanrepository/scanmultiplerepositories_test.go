@@ -3,6 +3,7 @@ package scanrepository
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-security/utils/xsc"
 	"net/http"
@@ -10,7 +11,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
@@ -140,3 +143,99 @@ func createScanRepoGitHubHandler(t *testing.T, port *string, response interface{
 		}
 	}
 }
+
+func TestScanRepositoriesConcurrently(t *testing.T) {
+	repoAggregator := utils.RepoAggregator{
+		{Params: utils.Params{Git: utils.Git{RepoName: "repo-1"}}},
+		{Params: utils.Params{Git: utils.Git{RepoName: "repo-2"}}},
+		{Params: utils.Params{Git: utils.Git{RepoName: "repo-3"}}},
+		{Params: utils.Params{Git: utils.Git{RepoName: "repo-4"}}},
+	}
+	testCases := []struct {
+		name               string
+		maxConcurrentRepos int
+	}{
+		{name: "Sequential", maxConcurrentRepos: 1},
+		{name: "Concurrent", maxConcurrentRepos: 3},
+		{name: "More workers than repositories", maxConcurrentRepos: 10},
+		{name: "Invalid workers number", maxConcurrentRepos: 0},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var running, maxRunning atomic.Int32
+			statuses := scanRepositoriesConcurrently(repoAggregator, test.maxConcurrentRepos, func(repository *utils.Repository) error {
+				current := running.Add(1)
+				defer running.Add(-1)
+				for observed := maxRunning.Load(); current > observed && !maxRunning.CompareAndSwap(observed, current); observed = maxRunning.Load() {
+				}
+				time.Sleep(10 * time.Millisecond)
+				switch repository.RepoName {
+				case "repo-2":
+					return errors.New("scan failed")
+				case "repo-3":
+					panic("unexpected failure")
+				}
+				return nil
+			})
+			assert.LessOrEqual(t, int(maxRunning.Load()), max(test.maxConcurrentRepos, 1))
+			assert.Len(t, statuses, len(repoAggregator))
+			for i, status := range statuses {
+				assert.Equal(t, repoAggregator[i].RepoName, status.repoName)
+			}
+			assert.NoError(t, statuses[0].err)
+			assert.EqualError(t, statuses[1].err, "scan failed")
+			assert.EqualError(t, statuses[2].err, "unexpected error: unexpected failure")
+			assert.NoError(t, statuses[3].err)
+		})
+	}
+}
+
+func TestScanSingleRepository(t *testing.T) {
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{RepoName: "repo-2"}}}
+	assert.Equal(t, []string{utils.ScanMultipleRepositories, "--repository", "repo-2"}, getRepositoryProcessArgs(repository))
+
+	// The process of a repository fails if the repository isn't in the configuration
+	cmd := ScanMultipleRepositories{Repository: "repo-3"}
+	repoAggregator := utils.RepoAggregator{{Params: utils.Params{Git: utils.Git{RepoName: "repo-1"}}}, *repository}
+	assert.EqualError(t, cmd.Run(repoAggregator, nil, utils.MockHasConnection()), "the 'repo-3' repository isn't one of the repositories of the configuration")
+}
+
+func TestRepositoriesSummary(t *testing.T) {
+	content := &repositoriesSummaryContent{statuses: []repositoryScanStatus{
+		{repoName: "repo-1"},
+		{repoName: "repo-2", err: errors.New("scan failed\nwith <details>")},
+	}}
+	assert.Equal(t, "[🐸 Frogbot] Repositories scan status", content.Title())
+	assert.Equal(t, [][]string{
+		{"Repository", "Status", "Details"},
+		{"repo-1", repoScanSucceeded, ""},
+		{"repo-2", repoScanFailed, "scan failed with <details>"},
+	}, content.rows())
+	assert.Regexp(t, `\| repo-2\s+\| Failed\s+\| scan failed with <details>\s+\|`, content.MarkdownDescription())
+	assert.Contains(t, content.HtmlDescription(), "<tr><td>repo-2</td><td>Failed</td><td>scan failed with &lt;details&gt;</td></tr>")
+
+	// The summary is posted to the issue of the summary repository, which is updated on the next runs
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			_, err := w.Write([]byte(`[{"number":5,"title":"[🐸 Frogbot] Repositories scan status"}]`))
+			assert.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+	repository := &utils.Repository{Params: utils.Params{Git: utils.Git{
+		GitProvider:             vcsutils.GitHub,
+		VcsInfo:                 vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"},
+		RepoOwner:               "jfrog",
+		RepoName:                "repo-1",
+		RepositoriesSummaryRepo: "frogbot-dashboard",
+	}}}
+	assert.NoError(t, postRepositoriesSummary(repository, content))
+	assert.Equal(t, []string{"GET /repos/jfrog/frogbot-dashboard/issues", "PATCH /repos/jfrog/frogbot-dashboard/issues/5"}, requests)
+
+	// The summary isn't posted if no summary repository is configured
+	repository.RepositoriesSummaryRepo = ""
+	assert.NoError(t, postRepositoriesSummary(repository, content))
+	assert.Len(t, requests, 2)
+}
@@ -4,17 +4,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	biutils "github.com/jfrog/build-info-go/utils"
 
 	"github.com/jfrog/frogbot/v2/packagehandlers"
 	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/alternatives"
+	"github.com/jfrog/frogbot/v2/utils/bazel"
+	"github.com/jfrog/frogbot/v2/utils/botpullrequests"
+	"github.com/jfrog/frogbot/v2/utils/composer"
+	"github.com/jfrog/frogbot/v2/utils/dependencyscope"
+	"github.com/jfrog/frogbot/v2/utils/dockerimage"
+	"github.com/jfrog/frogbot/v2/utils/exploitability"
+	"github.com/jfrog/frogbot/v2/utils/githubactions"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/mergeconflicts"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/report"
+	"github.com/jfrog/frogbot/v2/utils/sbom"
+	"github.com/jfrog/frogbot/v2/utils/scanhistory"
+	"github.com/jfrog/frogbot/v2/utils/workitems"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/gofrog/version"
@@ -22,6 +38,7 @@ import (
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	"github.com/jfrog/jfrog-cli-security/utils/results"
 	"github.com/jfrog/jfrog-cli-security/utils/results/conversion"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xsc"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -30,7 +47,15 @@ import (
 	"golang.org/x/exp/slices"
 )
 
-const analyticsScanRepositoryScanType = "monitor"
+const (
+	analyticsScanRepositoryScanType = "monitor"
+	// The flag of the preview mode of the command
+	DryRunFlag = "dry-run"
+	// The flag of the input file that lists the CVE IDs and packages to fix
+	FixCvesFlag = "fix-cves"
+	// The reason of the unsupported fixes of the vulnerable dependencies without a fixed version
+	noFixedVersionReason = "No fixed version"
+)
 
 type ScanRepositoryCmd struct {
 	// The interface that Frogbot utilizes to format and style the displayed messages on the Git providers
@@ -39,6 +64,21 @@ type ScanRepositoryCmd struct {
 	dryRun bool
 	// When dryRun is enabled, dryRunRepoPath specifies the repository local path to clone
 	dryRunRepoPath string
+	// In preview mode the fixes are committed in the temporary clone only, and their diffs and pull request details are printed,
+	// without pushing them, opening pull requests or uploading the scan results to the Git provider
+	Preview bool
+	// The writer the preview is printed to, the standard output by default
+	previewOutput io.Writer
+	// The path of an input file that lists the CVE IDs and packages to fix. Overrides the targetCves of the configuration, in the fix-only mode
+	FixCvesFile string
+	// When set, only the vulnerabilities of these CVE IDs or packages are fixed
+	targetCves []string
+	// Skip the fixes of the vulnerabilities that aren't applicable according to the contextual analysis
+	fixApplicableOnly bool
+	// Skip the fixes of the vulnerabilities that only development and test dependencies bring in
+	skipDevDependencies bool
+	// Skip the fixes of the vulnerabilities below this severity, while still reporting them
+	fixMinSeverity string
 	// The scanDetails of the current scan
 	scanDetails *utils.ScanDetails
 	// The base working directory
@@ -47,10 +87,67 @@ type ScanRepositoryCmd struct {
 	gitManager *utils.GitManager
 	// Determines whether to open a pull request for each vulnerability fix or to aggregate all fixes into one pull request
 	aggregateFixes bool
+	// When set, the fixes of vulnerabilities with this severity or higher are opened immediately in separate pull requests,
+	// and the rest of the fixes are aggregated into one pull request
+	separateFixesMinSeverity string
+	// Fix the known exploited vulnerabilities first, each in a separate pull request, regardless of their severity
+	prioritizeExploitedFixes bool
 	// The current project technology
 	projectTech []techutils.Technology
+	// The relative path of the current fixed project from the repository root
+	projectWorkingDir string
 	// Stores all package manager handlers for detected issues
 	handlers map[techutils.Technology]packagehandlers.PackageHandler
+	// The issues of all the scanned branches and working directories, collected when a scan report or a GitHub Actions job summary is requested
+	reportIssues *issues.ScansIssuesCollection
+	// Collects the components of all the scanned branches and working directories, when an SBOM is requested
+	sbomBuilder *sbom.CycloneDxBuilder
+	sbomPath    string
+	// The vulnerable dependencies that couldn't be fixed automatically during the run
+	unsupportedFixes []outputwriter.UnsupportedFixRow
+	// The unsupported fixes of the aggregated pull request that is currently opened
+	pullRequestUnsupportedFixes []outputwriter.UnsupportedFixRow
+	// The fixes of the aggregated pull request that is currently opened, which failed and were rolled back
+	pullRequestFailedFixes []outputwriter.FailedFixRow
+	// The alternative packages of the current branch, suggested for the vulnerable dependencies without a fixed version
+	alternativePackages *alternatives.Mapping
+	// The work items of the vulnerabilities without a fixed version, collected when their tracking is enabled
+	unfixableWorkItems []workitems.WorkItem
+	// The status of each branch of the repository, and of the branch that is currently scanned
+	branchSummaries      []*branchSummary
+	currentBranchSummary *branchSummary
+	// The baselines of the branches, by their names, loaded when unchanged branches are skipped
+	branchBaselines map[string]branchBaseline
+	// Scans the base images and OS packages of the Dockerfiles of the current branch, when the scan of Dockerfiles is enabled
+	dockerImageAnalyzer *dockerimage.Analyzer
+	// Scans the dependencies of the Bazel lockfiles of the current branch, when the scan of Bazel workspaces is enabled
+	bazelAnalyzer *bazel.Analyzer
+	// Scans the packages of the Composer lockfiles of the current branch, when the scan of Composer projects is enabled
+	composerAnalyzer *composer.Analyzer
+	// Checks the actions of the GitHub Actions workflows of the current branch, when the scan of the workflows is enabled
+	gitHubActionsAnalyzer *githubactions.Analyzer
+	// Whether each project has tests, by the paths of the projects, detected when the upgrade risk analysis is enabled
+	projectsWithTests map[string]bool
+	// The history of the scans of the branches, loaded when it is kept, and the change of the vulnerabilities of the current project since its last scan
+	scanHistory        *scanhistory.History
+	scanHistoryStorage scanhistory.Storage
+	scanDelta          *scanhistory.Delta
+	// The open pull requests of the other dependency bots, and their authors. The packages they bump aren't fixed
+	botPullRequestAuthors []string
+	botPullRequests       []botpullrequests.PullRequest
+	// The limits of the new fix pull requests, the counts of the open fix pull requests of the repository and of the pull requests
+	// opened by the run, and the fixes that are queued for the next runs since a limit was reached
+	maxOpenFixPullRequests int
+	maxNewFixPullRequests  int
+	fixPullRequestsWindows []utils.FixWindow
+	openFixPullRequests    int
+	newFixPullRequests     int
+	queuedFixes            []queuedFix
+	// The limits of the fixes of each severity per run, and the numbers of the fixes of each severity that the run selected, by the severities
+	maxFixesPerSeverity map[string]int
+	fixesPerSeverity    map[string]int
+	// The results of backporting the fixes to the backport branches
+	backportResults []backportResult
 
 	XrayVersion string
 	XscVersion  string
@@ -64,6 +161,12 @@ func (cfp *ScanRepositoryCmd) Run(repoAggregator utils.RepoAggregator, client vc
 	repository.OutputWriter.SetHasInternetConnection(frogbotRepoConnection.IsConnected())
 	cfp.XrayVersion = repository.XrayVersion
 	cfp.XscVersion = repository.XscVersion
+	if cfp.FixCvesFile != "" {
+		if repository.TargetCves, err = utils.ReadTargetCvesFile(cfp.FixCvesFile); err != nil {
+			return err
+		}
+		repository.TargetCvesFixOnly = true
+	}
 	return cfp.scanAndFixRepository(&repository, client)
 }
 
@@ -71,14 +174,133 @@ func (cfp *ScanRepositoryCmd) scanAndFixRepository(repository *utils.Repository,
 	if err = cfp.setCommandPrerequisites(repository, client); err != nil {
 		return
 	}
+	if cfp.Preview {
+		log.Info("Running in preview mode. The fixes are printed, and no branches are pushed and no pull requests are opened")
+	}
+	if len(cfp.targetCves) > 0 {
+		log.Info("Fixing only the vulnerabilities of:", strings.Join(cfp.targetCves, ", "))
+	}
+	if err = utils.ValidateRepositoryViolationsContext(repository); err != nil {
+		return
+	}
+	cfp.loadBotPullRequests(repository)
+	// The merged pull requests aren't counted by the limits of the open fix pull requests
+	cfp.mergeReadyFixPullRequests(repository)
+	if err = cfp.loadFixPullRequestsLimits(repository); err != nil {
+		return
+	}
+	if repository.BranchBaselinesFile != "" {
+		if err = cfp.loadBranchBaselines(repository.BranchBaselinesFile); err != nil {
+			return
+		}
+	}
+	if err = cfp.loadScanHistory(repository); err != nil {
+		return
+	}
+	// The summary includes the branches that were scanned before a failure
+	defer func() {
+		err = errors.Join(err, cfp.reportBranchesSummary(repository))
+		if repository.BranchBaselinesFile != "" && !cfp.Preview {
+			err = errors.Join(err, cfp.writeBranchBaselines(repository.BranchBaselinesFile))
+		}
+		if !cfp.Preview {
+			err = errors.Join(err, cfp.saveScanHistory())
+		}
+	}()
 	for _, branch := range repository.Branches {
 		cfp.scanDetails.SetBaseBranch(branch)
+		summary := cfp.startBranchSummary(branch)
+		var checksum string
+		if repository.BranchBaselinesFile != "" {
+			var unchanged bool
+			if unchanged, checksum = cfp.isBranchUnchanged(repository, branch); unchanged {
+				log.Info(fmt.Sprintf("Skipping the '%s' branch, since it is unchanged since its last scan", branch))
+				summary.Status = branchSkipped
+				continue
+			}
+		}
 		cfp.scanDetails.SetXscGitInfoContext(branch, repository.Project, client)
 		if err = cfp.scanAndFixBranch(repository); err != nil {
+			summary.Status = branchFailed
 			return
 		}
+		if checksum != "" {
+			cfp.branchBaselines[branch] = branchBaseline{Checksum: checksum, ScanTime: time.Now()}
+		}
 	}
-	return
+	cfp.logUnsupportedFixesSummary()
+	cfp.logQueuedFixesSummary()
+	cfp.logBackportsSummary()
+	cfp.cleanupMergedBranches(repository)
+	if repository.TrackUnfixableVulnerabilities {
+		cfp.trackUnfixableVulnerabilities(repository)
+	}
+	if err = cfp.writeSbomIfNeeded(repository); err != nil {
+		return
+	}
+	return cfp.writeScanReportIfNeeded(repository)
+}
+
+// Logs the number of vulnerable dependencies that need a manual remediation, for each reason
+func (cfp *ScanRepositoryCmd) logUnsupportedFixesSummary() {
+	if len(cfp.unsupportedFixes) == 0 {
+		return
+	}
+	countByReason := map[string]int{}
+	var reasons []string
+	for _, unsupportedFix := range cfp.unsupportedFixes {
+		if countByReason[unsupportedFix.Reason] == 0 {
+			reasons = append(reasons, unsupportedFix.Reason)
+		}
+		countByReason[unsupportedFix.Reason]++
+	}
+	var summary []string
+	for _, reason := range reasons {
+		summary = append(summary, fmt.Sprintf("%s: %d", reason, countByReason[reason]))
+	}
+	log.Info(fmt.Sprintf("%d vulnerable dependencies couldn't be fixed automatically and need to be remediated manually (%s)", len(cfp.unsupportedFixes), strings.Join(summary, ", ")))
+}
+
+func (cfp *ScanRepositoryCmd) writeSbomIfNeeded(repository *utils.Repository) error {
+	if cfp.sbomBuilder == nil {
+		return nil
+	}
+	return cfp.sbomBuilder.Write(cfp.sbomPath, fmt.Sprintf("%s/%s", repository.RepoOwner, repository.RepoName), utils.FrogbotVersion)
+}
+
+// Writes the scan report file, and adds the report to the job summary when running in GitHub Actions
+func (cfp *ScanRepositoryCmd) writeScanReportIfNeeded(repository *utils.Repository) error {
+	if cfp.reportIssues == nil {
+		return nil
+	}
+	scanReport := &report.ScanReport{
+		Subject:          fmt.Sprintf("%s/%s (branches: %s)", repository.RepoOwner, repository.RepoName, strings.Join(repository.Branches, ", ")),
+		Issues:           *cfp.reportIssues,
+		ResultContext:    cfp.scanDetails.ResultContext,
+		IncludeSecrets:   repository.PullRequestSecretComments,
+		UnsupportedFixes: cfp.unsupportedFixes,
+		Writer:           cfp.OutputWriter,
+	}
+	utils.WriteJobSummaryIfNeeded(scanReport)
+	if repository.ReportPath == "" {
+		return nil
+	}
+	return scanReport.Write(repository.ReportPath)
+}
+
+func (cfp *ScanRepositoryCmd) addReportIssues(repository *utils.Repository, scanResults *results.SecurityCommandResults) error {
+	if repository.ReportPath == "" && os.Getenv(utils.GitHubStepSummaryEnv) == "" {
+		return nil
+	}
+	scanIssues, err := utils.ConvertToIssuesCollection(scanResults, repository.AllowedLicenses)
+	if err != nil {
+		return err
+	}
+	if cfp.reportIssues == nil {
+		cfp.reportIssues = &issues.ScansIssuesCollection{}
+	}
+	cfp.reportIssues.Append(scanIssues)
+	return nil
 }
 
 func (cfp *ScanRepositoryCmd) scanAndFixBranch(repository *utils.Repository) (err error) {
@@ -87,6 +309,12 @@ func (cfp *ScanRepositoryCmd) scanAndFixBranch(repository *utils.Repository) (er
 		return
 	}
 	cfp.baseWd = repoDir
+	cfp.scanDetails.SetExcludePatterns(utils.GetExcludePatterns(repoDir, repository.ExcludePatterns))
+	// Failing to read the alternative packages file only skips their suggestions
+	if cfp.alternativePackages, err = utils.GetAlternativePackages(repoDir); err != nil {
+		log.Warn("Couldn't get the alternative packages, so they aren't suggested:", err.Error())
+		err = nil
+	}
 	defer func() {
 		// On dry run don't delete the folder as we want to validate results
 		if cfp.dryRun {
@@ -105,11 +333,30 @@ func (cfp *ScanRepositoryCmd) scanAndFixBranch(repository *utils.Repository) (er
 	totalFindings := 0
 
 	defer func() {
+		if cfp.currentBranchSummary != nil {
+			cfp.currentBranchSummary.Findings = totalFindings
+		}
 		xsc.SendScanEndedEvent(cfp.scanDetails.XrayVersion, cfp.scanDetails.XscVersion, cfp.scanDetails.ServerDetails, cfp.scanDetails.MultiScanId, cfp.scanDetails.StartTime, totalFindings, &cfp.scanDetails.ResultContext, err)
 	}()
 
+	// Each vulnerable component of the Dockerfiles is fixed once for all the projects of the branch
+	if cfp.dockerImageAnalyzer, err = dockerimage.NewAnalyzer(repository.ScanDockerfiles, cfp.scanDetails.ServerDetails, cfp.XrayVersion); err != nil {
+		return
+	}
+	if cfp.bazelAnalyzer, err = bazel.NewAnalyzer(repository.ScanBazel, cfp.scanDetails.ServerDetails, cfp.XrayVersion); err != nil {
+		return
+	}
+	if cfp.composerAnalyzer, err = composer.NewAnalyzer(repository.ScanComposer, cfp.scanDetails.ServerDetails, cfp.XrayVersion); err != nil {
+		return
+	}
+	cfp.gitHubActionsAnalyzer = githubactions.NewAnalyzer(repository.ScanGitHubActions, repository.PinGitHubActions, repository.GitProvider, repository.VcsInfo)
 	for i := range repository.Projects {
-		cfp.scanDetails.Project = &repository.Projects[i]
+		// The projects are detected in each branch, since the layout of the branches may differ
+		project, e := repository.Projects[i].WithDetectedWorkingDirs(repoDir)
+		if e != nil {
+			return e
+		}
+		cfp.scanDetails.SetProject(&project)
 		cfp.projectTech = []techutils.Technology{}
 		if findings, e := cfp.scanAndFixProject(repository); e != nil {
 			return e
@@ -129,13 +376,17 @@ func (cfp *ScanRepositoryCmd) setCommandPrerequisites(repository *utils.Reposito
 	// Set the scan details
 	cfp.scanDetails = utils.NewScanDetails(client, &repository.Server, &repository.Git).
 		SetJfrogVersions(cfp.XrayVersion, cfp.XscVersion).
-		SetResultsContext(repositoryCloneUrl, repository.Watches, repository.JFrogProjectKey, repository.IncludeVulnerabilities, len(repository.AllowedLicenses) > 0).
+		// The SBOM lists the licenses of the components
+		SetResultsContext(repositoryCloneUrl, repository.Watches, repository.JFrogProjectKey, repository.IncludeVulnerabilities, len(repository.AllowedLicenses) > 0 || repository.SbomPath != "").
 		SetFailOnInstallationErrors(*repository.FailOnSecurityIssues).
 		SetFixableOnly(repository.FixableOnly).
 		SetConfigProfile(repository.ConfigProfile).
 		SetSkipAutoInstall(repository.SkipAutoInstall).
 		SetAllowPartialResults(repository.AllowPartialResults).
-		SetDisableJas(repository.DisableJas)
+		SetTargetCves(repository.GetScannedTargetCves()).
+		SetPackageHandlerPlugins(repository.PackageHandlerPlugins).
+		SetDisableJas(repository.DisableJas).
+		SetJasScanners(repository.Jas)
 
 	if cfp.scanDetails, err = cfp.scanDetails.SetMinSeverity(repository.MinSeverity); err != nil {
 		return
@@ -143,6 +394,16 @@ func (cfp *ScanRepositoryCmd) setCommandPrerequisites(repository *utils.Reposito
 
 	// Set the flag for aggregating fixes to generate a unified pull request for fixing vulnerabilities
 	cfp.aggregateFixes = repository.Git.AggregateFixes
+	cfp.separateFixesMinSeverity = repository.Git.SeparateFixesMinSeverity
+	cfp.prioritizeExploitedFixes = repository.PrioritizeExploitedFixes
+	cfp.targetCves = repository.TargetCves
+	cfp.fixApplicableOnly = repository.FixApplicableOnly
+	cfp.skipDevDependencies = repository.SkipDevDependencies
+	cfp.fixMinSeverity = repository.FixMinSeverity
+	if repository.SbomPath != "" {
+		cfp.sbomBuilder = sbom.NewCycloneDxBuilder()
+		cfp.sbomPath = repository.SbomPath
+	}
 	// Set the outputwriter interface for the relevant vcs git provider
 	cfp.OutputWriter = outputwriter.GetCompatibleOutputWriter(repository.GitProvider)
 	cfp.OutputWriter.SetSizeLimit(client)
@@ -165,8 +426,15 @@ func (cfp *ScanRepositoryCmd) scanAndFixProject(repository *utils.Repository) (i
 	// The value is a map of vulnerable package names -> the scanDetails of the vulnerable packages.
 	// That means we have a map of all the vulnerabilities that were found in a specific folder, along with their full scanDetails.
 	vulnerabilitiesByPathMap := make(map[string]map[string]*utils.VulnerabilityDetails)
-	projectFullPathWorkingDirs := utils.GetFullPathWorkingDirs(cfp.scanDetails.Project.WorkingDirs, cfp.baseWd)
+	// The issues of all the working directories of the project, collected when the scan history is kept
+	projectIssues := &issues.ScansIssuesCollection{}
+	projectFullPathWorkingDirs, submodulePaths, err := utils.GetFullPathWorkingDirsWithSubmodules(cfp.scanDetails.Project.WorkingDirs, cfp.baseWd, cfp.scanDetails.Submodules)
+	if err != nil {
+		return totalFindings, err
+	}
 	for _, fullPathWd := range projectFullPathWorkingDirs {
+		// The fix pull requests show the scopes of the vulnerable dependencies
+		cfp.OutputWriter.SetDependencyScopes(cfp.OutputWriter.DependencyScopes().Merge(dependencyscope.Detect(fullPathWd)))
 		scanResults, err := cfp.scan(fullPathWd)
 		if err != nil {
 			if err = utils.CreateErrorIfPartialResultsDisabled(cfp.scanDetails.AllowPartialResults(), fmt.Sprintf("An error occurred during Audit execution for '%s' working directory. Fixes will be skipped for this working directory", fullPathWd), err); err != nil {
@@ -174,6 +442,7 @@ func (cfp *ScanRepositoryCmd) scanAndFixProject(repository *utils.Repository) (i
 			}
 			continue
 		}
+		utils.AttributeResultsToSubmodules(scanResults, cfp.baseWd, submodulePaths)
 		if summary, err := conversion.NewCommandResultsConvertor(conversion.ResultConvertParams{IncludeVulnerabilities: scanResults.IncludesVulnerabilities(), HasViolationContext: scanResults.HasViolationContext()}).ConvertToSummary(scanResults); err != nil {
 			return totalFindings, err
 		} else {
@@ -184,7 +453,22 @@ func (cfp *ScanRepositoryCmd) scanAndFixProject(repository *utils.Repository) (i
 			totalFindings += findingCount
 		}
 
-		if repository.GitProvider.String() == vcsutils.GitHub.String() {
+		utils.RecordScanResults(scanResults, repository.AllowedLicenses)
+		if err = cfp.addReportIssues(repository, scanResults); err != nil {
+			return totalFindings, err
+		}
+		if cfp.scanHistory != nil {
+			scanIssues, err := utils.ConvertToIssuesCollection(scanResults, repository.AllowedLicenses)
+			if err != nil {
+				return totalFindings, err
+			}
+			projectIssues.Append(scanIssues)
+		}
+		if cfp.sbomBuilder != nil {
+			cfp.sbomBuilder.AddScanResults(scanResults)
+		}
+
+		if repository.GitProvider.String() == vcsutils.GitHub.String() && !cfp.Preview {
 			// Uploads Sarif results to GitHub in order to view the scan in the code scanning UI
 			// Currently available on GitHub only
 			if err = utils.UploadSarifResultsToGithubSecurityTab(scanResults, repository, cfp.scanDetails.BaseBranch(), cfp.scanDetails.Client()); err != nil {
@@ -194,6 +478,11 @@ func (cfp *ScanRepositoryCmd) scanAndFixProject(repository *utils.Repository) (i
 		if repository.DetectionOnly {
 			continue
 		}
+		if utils.IsSubmoduleWd(fullPathWd, cfp.baseWd, submodulePaths) {
+			// The content of a submodule belongs to another repository, so it can't be fixed by a pull request to this repository
+			log.Info(fmt.Sprintf("Skipping the fixes of the '%s' submodule", utils.GetRelativeWd(fullPathWd, cfp.baseWd)))
+			continue
+		}
 		// Prepare the vulnerabilities map for each working dir path
 		currPathVulnerabilities, err := cfp.getVulnerabilitiesMap(scanResults)
 		if err != nil {
@@ -202,11 +491,28 @@ func (cfp *ScanRepositoryCmd) scanAndFixProject(repository *utils.Repository) (i
 			}
 			continue
 		}
+		if err = cfp.addDockerImageVulnerabilities(fullPathWd, currPathVulnerabilities); err != nil {
+			return totalFindings, err
+		}
+		if err = cfp.addBazelVulnerabilities(fullPathWd, currPathVulnerabilities); err != nil {
+			return totalFindings, err
+		}
+		if err = cfp.addComposerVulnerabilities(fullPathWd, currPathVulnerabilities); err != nil {
+			return totalFindings, err
+		}
 		if len(currPathVulnerabilities) > 0 {
 			fixNeeded = true
+			if repository.ExploitabilityEnrichment {
+				cfp.addExploitability(utils.SetVulnerabilitiesExploitability(currPathVulnerabilities))
+			}
 		}
 		vulnerabilitiesByPathMap[fullPathWd] = currPathVulnerabilities
 	}
+	// The workflows are at the root of the repository, so their actions are fixed once for all the projects
+	if cfp.addGitHubActionsVulnerabilities(vulnerabilitiesByPathMap) {
+		fixNeeded = true
+	}
+	cfp.recordScanSummary(projectIssues)
 	if repository.DetectionOnly {
 		log.Info(fmt.Sprintf("This command is running in detection mode only. To enable automatic fixing of issues, set the '%s' environment variable to 'false'.", utils.DetectionOnlyEnv))
 	} else if fixNeeded {
@@ -218,12 +524,19 @@ func (cfp *ScanRepositoryCmd) scanAndFixProject(repository *utils.Repository) (i
 // Audit the dependencies of the current commit.
 func (cfp *ScanRepositoryCmd) scan(currentWorkingDir string) (*results.SecurityCommandResults, error) {
 	// Audit commit code
+	scanStartTime := time.Now()
 	auditResults := cfp.scanDetails.RunInstallAndAudit(currentWorkingDir)
+	utils.RecordScanDuration(cfp.scanDetails.RepoOwner+"/"+cfp.scanDetails.RepoName, time.Since(scanStartTime))
 	if err := auditResults.GetErrors(); err != nil {
 		return nil, err
 	}
 	log.Info("Xray scan completed")
+	if cfp.scanDetails.ShowRuntimeDetails {
+		cfp.OutputWriter.SetRuntimeDetails(utils.NewRuntimeDetails(cfp.XrayVersion, cfp.XscVersion, scanStartTime))
+	}
 	cfp.OutputWriter.SetJasOutputFlags(auditResults.EntitledForJas, auditResults.HasJasScansResults(jasutils.Applicability))
+	cfp.OutputWriter.SetJasStatusUnknown(cfp.scanDetails.JasStatusUnknown())
+	cfp.OutputWriter.SetJasUnsupportedReason(cfp.scanDetails.JasUnsupportedReason())
 	cfp.projectTech = auditResults.GetTechnologies(cfp.projectTech...)
 	return auditResults, nil
 }
@@ -241,14 +554,69 @@ func (cfp *ScanRepositoryCmd) getVulnerabilitiesMap(scanResults *results.Securit
 	return vulnerabilitiesMap, nil
 }
 
+// Adds the exploitability of the CVEs to the output, so the fix pull requests show it
+func (cfp *ScanRepositoryCmd) addExploitability(exploitabilityInfo map[string]exploitability.Info) {
+	allExploitabilityInfo := cfp.OutputWriter.Exploitability()
+	if allExploitabilityInfo == nil {
+		allExploitabilityInfo = make(map[string]exploitability.Info)
+	}
+	maps.Copy(allExploitabilityInfo, exploitabilityInfo)
+	cfp.OutputWriter.SetExploitability(allExploitabilityInfo)
+}
+
 func (cfp *ScanRepositoryCmd) fixVulnerablePackages(repository *utils.Repository, vulnerabilitiesByWdMap map[string]map[string]*utils.VulnerabilityDetails) (err error) {
-	if cfp.aggregateFixes {
-		err = cfp.fixIssuesSinglePR(repository, vulnerabilitiesByWdMap)
-	} else {
-		err = cfp.fixIssuesSeparatePRs(repository, vulnerabilitiesByWdMap)
+	if cfp.prioritizeExploitedFixes {
+		var exploitedFixes map[string]map[string]*utils.VulnerabilityDetails
+		if exploitedFixes, vulnerabilitiesByWdMap = splitKnownExploitedVulnerabilities(vulnerabilitiesByWdMap); len(exploitedFixes) > 0 {
+			err = cfp.fixKnownExploitedIssues(repository, exploitedFixes)
+		}
 	}
-	if err != nil {
-		return utils.CreateErrorIfPartialResultsDisabled(cfp.scanDetails.AllowPartialResults(), fmt.Sprintf("failed to fix vulnerable dependencies: %s", err.Error()), err)
+	if len(vulnerabilitiesByWdMap) == 0 {
+		return
+	}
+	var fixErr error
+	switch {
+	case cfp.separateFixesMinSeverity != "":
+		fixErr = cfp.fixIssuesBySeverity(repository, vulnerabilitiesByWdMap)
+	case cfp.aggregateFixes:
+		fixErr = cfp.fixIssuesSinglePR(repository, vulnerabilitiesByWdMap)
+	default:
+		fixErr = cfp.fixIssuesSeparatePRs(repository, vulnerabilitiesByWdMap)
+	}
+	if fixErr != nil {
+		err = errors.Join(err, utils.CreateErrorIfPartialResultsDisabled(cfp.scanDetails.AllowPartialResults(), fmt.Sprintf("failed to fix vulnerable dependencies: %s", fixErr.Error()), fixErr))
+	}
+	return
+}
+
+// Fixes the known exploited vulnerabilities before the rest of the vulnerabilities, each in a separate pull request
+func (cfp *ScanRepositoryCmd) fixKnownExploitedIssues(repository *utils.Repository, vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) (err error) {
+	log.Info("Fixing the vulnerabilities that are known to be exploited first")
+	// The pull request details are generated according to the current fix mode
+	defer func() {
+		cfp.aggregateFixes = repository.Git.AggregateFixes
+	}()
+	cfp.aggregateFixes = false
+	if err = cfp.fixIssuesSeparatePRs(repository, vulnerabilitiesMap); err != nil {
+		return utils.CreateErrorIfPartialResultsDisabled(cfp.scanDetails.AllowPartialResults(), fmt.Sprintf("failed to fix known exploited vulnerable dependencies: %s", err.Error()), err)
+	}
+	return
+}
+
+func splitKnownExploitedVulnerabilities(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) (exploitedFixes, otherFixes map[string]map[string]*utils.VulnerabilityDetails) {
+	exploitedFixes = make(map[string]map[string]*utils.VulnerabilityDetails)
+	otherFixes = make(map[string]map[string]*utils.VulnerabilityDetails)
+	for fullPath, vulnerabilities := range vulnerabilitiesMap {
+		for packageName, vulnDetails := range vulnerabilities {
+			fixes := otherFixes
+			if vulnDetails.IsKnownExploited() {
+				fixes = exploitedFixes
+			}
+			if fixes[fullPath] == nil {
+				fixes[fullPath] = make(map[string]*utils.VulnerabilityDetails)
+			}
+			fixes[fullPath][packageName] = vulnDetails
+		}
 	}
 	return
 }
@@ -263,14 +631,59 @@ func (cfp *ScanRepositoryCmd) fixIssuesSeparatePRs(repository *utils.Repository,
 	return err
 }
 
+// Fixes the vulnerabilities with the configured severity or higher immediately, each in a separate pull request,
+// and defers the fixes of the rest of the vulnerabilities to the aggregated pull request.
+// Vulnerabilities that aren't applicable are always deferred, regardless of their severity.
+func (cfp *ScanRepositoryCmd) fixIssuesBySeverity(repository *utils.Repository, vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) (err error) {
+	immediateFixes, deferredFixes := splitVulnerabilitiesBySeverity(vulnerabilitiesMap, severityutils.GetSeverity(cfp.separateFixesMinSeverity))
+	// The pull request details are generated according to the current fix mode
+	defer func() {
+		cfp.aggregateFixes = repository.Git.AggregateFixes
+	}()
+	if len(immediateFixes) > 0 {
+		cfp.aggregateFixes = false
+		err = cfp.fixIssuesSeparatePRs(repository, immediateFixes)
+	}
+	if len(deferredFixes) > 0 {
+		cfp.aggregateFixes = true
+		err = errors.Join(err, cfp.fixIssuesSinglePR(repository, deferredFixes))
+	}
+	return
+}
+
+func splitVulnerabilitiesBySeverity(vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails, minSeverity severityutils.Severity) (immediateFixes, deferredFixes map[string]map[string]*utils.VulnerabilityDetails) {
+	immediateFixes = make(map[string]map[string]*utils.VulnerabilityDetails)
+	deferredFixes = make(map[string]map[string]*utils.VulnerabilityDetails)
+	for fullPath, vulnerabilities := range vulnerabilitiesMap {
+		for packageName, vulnDetails := range vulnerabilities {
+			fixes := deferredFixes
+			if isImmediateFix(vulnDetails, minSeverity) {
+				fixes = immediateFixes
+			}
+			if fixes[fullPath] == nil {
+				fixes[fullPath] = make(map[string]*utils.VulnerabilityDetails)
+			}
+			fixes[fullPath][packageName] = vulnDetails
+		}
+	}
+	return
+}
+
+func isImmediateFix(vulnDetails *utils.VulnerabilityDetails, minSeverity severityutils.Severity) bool {
+	if vulnDetails.Applicable == jasutils.NotApplicable.String() {
+		return false
+	}
+	return severityutils.CompareSeverity(severityutils.GetSeverity(vulnDetails.Severity), minSeverity) >= 0
+}
+
 func (cfp *ScanRepositoryCmd) fixProjectVulnerabilities(repository *utils.Repository, fullProjectPath string, vulnerabilities map[string]*utils.VulnerabilityDetails) (err error) {
 	// Update the working directory to the project's current working directory
-	projectWorkingDir := utils.GetRelativeWd(fullProjectPath, cfp.baseWd)
+	cfp.projectWorkingDir = utils.GetRelativeWd(fullProjectPath, cfp.baseWd)
 
 	// 'CD' into the relevant working directory
-	if projectWorkingDir != "" {
+	if cfp.projectWorkingDir != "" {
 		var restoreDirFunc func() error
-		if restoreDirFunc, err = utils.Chdir(projectWorkingDir); err != nil {
+		if restoreDirFunc, err = utils.Chdir(cfp.projectWorkingDir); err != nil {
 			return
 		}
 		defer func() {
@@ -281,12 +694,12 @@ func (cfp *ScanRepositoryCmd) fixProjectVulnerabilities(repository *utils.Reposi
 	// Fix every vulnerability in a separate pull request and branch
 	for _, vulnerability := range vulnerabilities {
 		if e := cfp.fixSinglePackageAndCreatePR(repository, vulnerability); e != nil {
-			err = errors.Join(err, cfp.handleUpdatePackageErrors(e))
+			err = errors.Join(err, cfp.handleUpdatePackageErrors(e, vulnerability))
 		}
 
 		// After fixing the current vulnerability, checkout to the base branch to start fixing the next vulnerability
 		if e := cfp.gitManager.Checkout(cfp.scanDetails.BaseBranch()); e != nil {
-			err = errors.Join(err, cfp.handleUpdatePackageErrors(e))
+			err = errors.Join(err, cfp.handleUpdatePackageErrors(e, vulnerability))
 			return
 		}
 	}
@@ -310,23 +723,49 @@ func (cfp *ScanRepositoryCmd) fixMultiplePackages(fullProjectPath string, vulner
 		}()
 	}
 	for _, vulnDetails := range vulnerabilities {
-		if e := cfp.updatePackageToFixedVersion(vulnDetails); e != nil {
-			err = errors.Join(err, cfp.handleUpdatePackageErrors(e))
+		// Each package is updated in isolation, so a failed update doesn't leave partial changes next to the fixes of the other packages
+		snapshot, e := cfp.gitManager.SnapshotWorktree()
+		if e != nil {
+			return fixedVulnerabilities, errors.Join(err, e)
+		}
+		updateErr := cfp.updatePackageAndAnalyzeRisk(vulnDetails)
+		if updateErr == nil {
+			fixedVulnerabilities = append(fixedVulnerabilities, vulnDetails)
+			log.Info(fmt.Sprintf("Updated dependency '%s' to version '%s'", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
 			continue
 		}
-		fixedVulnerabilities = append(fixedVulnerabilities, vulnDetails)
-		log.Info(fmt.Sprintf("Updated dependency '%s' to version '%s'", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
+		if e = cfp.gitManager.RestoreWorktree(snapshot); e != nil {
+			return fixedVulnerabilities, errors.Join(err, updateErr, fmt.Errorf("failed to roll back the update of dependency '%s': %s", vulnDetails.ImpactedDependencyName, e.Error()))
+		}
+		if e = cfp.handleUpdatePackageErrors(updateErr, vulnDetails); e != nil {
+			cfp.addFailedFix(vulnDetails, e)
+			err = errors.Join(err, e)
+		}
 	}
 	return
 }
 
+// Records a dependency that failed to be updated, so it's listed in the aggregated pull request with the reason of the failure
+func (cfp *ScanRepositoryCmd) addFailedFix(vulnDetails *utils.VulnerabilityDetails, err error) {
+	reason, _, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+	log.Warn(fmt.Sprintf("Failed to update dependency '%s' to version '%s', so its changes were rolled back: %s", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion, reason))
+	cfp.pullRequestFailedFixes = append(cfp.pullRequestFailedFixes, outputwriter.FailedFixRow{
+		VulnerabilityOrViolationRow: vulnDetails.VulnerabilityOrViolationRow,
+		SuggestedFixedVersion:       vulnDetails.SuggestedFixedVersion,
+		Reason:                      reason,
+	})
+}
+
 // Fixes all the vulnerabilities in a single aggregated pull request.
 // If an existing aggregated fix is present, it checks for different scan results.
 // If the scan results are the same, no action is taken.
 // Otherwise, it performs a force push to the same branch and reopens the pull request if it was closed.
 // Only one aggregated pull request should remain open at all times.
 func (cfp *ScanRepositoryCmd) fixIssuesSinglePR(repository *utils.Repository, vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails) (err error) {
-	aggregatedFixBranchName := cfp.gitManager.GenerateAggregatedFixBranchName(cfp.scanDetails.BaseBranch(), cfp.projectTech)
+	aggregatedFixBranchName, err := cfp.gitManager.GenerateAggregatedFixBranchName(cfp.scanDetails.BaseBranch(), cfp.projectTech)
+	if err != nil {
+		return
+	}
 	existingPullRequestDetails, err := cfp.getOpenPullRequestBySourceBranch(aggregatedFixBranchName)
 	if err != nil {
 		return
@@ -334,16 +773,35 @@ func (cfp *ScanRepositoryCmd) fixIssuesSinglePR(repository *utils.Repository, vu
 	return cfp.aggregateFixAndOpenPullRequest(repository, vulnerabilitiesMap, aggregatedFixBranchName, existingPullRequestDetails)
 }
 
+// Records a vulnerable dependency without a fixed version as an unsupported fix, with the alternative packages that may replace it
+func (cfp *ScanRepositoryCmd) addNoFixedVersionUnsupportedFix(vulnerability *formats.VulnerabilityOrViolationRow) {
+	reason := noFixedVersionReason
+	if alternativePackages := cfp.alternativePackages.GetAlternatives(vulnerability.ImpactedDependencyName); len(alternativePackages) > 0 {
+		var names []string
+		for _, alternativePackage := range alternativePackages {
+			names = append(names, alternativePackage.Name)
+		}
+		reason = fmt.Sprintf("%s, consider replacing it with: %s", noFixedVersionReason, strings.Join(names, ", "))
+	}
+	log.Info(fmt.Sprintf("Skipping a vulnerability of %s:%s, since it has no fixed version", vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion))
+	cfp.unsupportedFixes = append(cfp.unsupportedFixes, outputwriter.UnsupportedFixRow{VulnerabilityOrViolationRow: *vulnerability, Reason: reason})
+}
+
 // Handles possible error of update package operation
-// When the expected custom error occurs, log to debug.
+// When the expected custom error occurs, log to debug and collect the unsupported fix.
 // else, return the error
-func (cfp *ScanRepositoryCmd) handleUpdatePackageErrors(err error) error {
+func (cfp *ScanRepositoryCmd) handleUpdatePackageErrors(err error, vulnDetails *utils.VulnerabilityDetails) error {
 	var errUnsupportedFix *utils.ErrUnsupportedFix
 	var errNoChangesToCommit *utils.ErrNothingToCommit
 
 	switch {
 	case errors.As(err, &errUnsupportedFix):
 		log.Debug(strings.TrimSpace(err.Error()))
+		cfp.unsupportedFixes = append(cfp.unsupportedFixes, outputwriter.UnsupportedFixRow{
+			VulnerabilityOrViolationRow: vulnDetails.VulnerabilityOrViolationRow,
+			SuggestedFixedVersion:       vulnDetails.SuggestedFixedVersion,
+			Reason:                      errUnsupportedFix.Reason(),
+		})
 	case errors.As(err, &errNoChangesToCommit):
 		log.Info(err.Error())
 	default:
@@ -353,11 +811,18 @@ func (cfp *ScanRepositoryCmd) handleUpdatePackageErrors(err error) error {
 }
 
 // Creates a branch for the fixed package and open pull request against the target branch.
-// In case a branch already exists on remote, we skip it.
+// In case a branch already exists on remote, we skip it, unless its pull request conflicts with the target branch.
+// The branch of a conflicting pull request is recreated from the target branch, and the fix is applied again.
+// Packages that an open pull request of another dependency bot already bumps are skipped, and the bot pull request is commented instead.
+// Fixes that would open a new pull request beyond the limits of the fix pull requests are queued for the next runs.
 func (cfp *ScanRepositoryCmd) fixSinglePackageAndCreatePR(repository *utils.Repository, vulnDetails *utils.VulnerabilityDetails) (err error) {
+	if botPullRequest := cfp.getBotPullRequest(vulnDetails); botPullRequest != nil {
+		cfp.linkBotPullRequest(botPullRequest, vulnDetails)
+		return
+	}
 	fixVersion := vulnDetails.SuggestedFixedVersion
 	log.Debug("Attempting to fix", fmt.Sprintf("%s:%s", vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion), "with", fixVersion)
-	fixBranchName, err := cfp.gitManager.GenerateFixBranchName(cfp.scanDetails.BaseBranch(), vulnDetails.ImpactedDependencyName, fixVersion)
+	fixBranchName, err := cfp.gitManager.GenerateFixBranchName(cfp.scanDetails.BaseBranch(), cfp.projectWorkingDir, vulnDetails)
 	if err != nil {
 		return
 	}
@@ -365,8 +830,18 @@ func (cfp *ScanRepositoryCmd) fixSinglePackageAndCreatePR(repository *utils.Repo
 	if err != nil {
 		return
 	}
+	var conflictingPullRequest *vcsclient.PullRequestInfo
 	if existsInRemote {
-		log.Info(fmt.Sprintf("A pull request updating the dependency '%s' to version '%s' already exists. Skipping...", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
+		if conflictingPullRequest, err = cfp.getConflictingPullRequest(fixBranchName); err != nil {
+			return
+		}
+		if conflictingPullRequest == nil {
+			log.Info(fmt.Sprintf("A pull request updating the dependency '%s' to version '%s' already exists. Skipping...", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
+			return
+		}
+		log.Info(fmt.Sprintf("Pull request #%d updating the dependency '%s' to version '%s' has merge conflicts with the '%s' branch. Recreating its branch from the '%s' branch...",
+			conflictingPullRequest.ID, vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion, cfp.scanDetails.BaseBranch(), cfp.scanDetails.BaseBranch()))
+	} else if cfp.queueFixIfLimited(fmt.Sprintf("'%s' to version '%s'", vulnDetails.ImpactedDependencyName, fixVersion), vulnDetails) {
 		return
 	}
 
@@ -384,17 +859,22 @@ func (cfp *ScanRepositoryCmd) fixSinglePackageAndCreatePR(repository *utils.Repo
 		return
 	}
 
-	if err = cfp.updatePackageToFixedVersion(vulnDetails); err != nil {
+	if err = cfp.updatePackageAndAnalyzeRisk(vulnDetails); err != nil {
 		return
 	}
-	if err = cfp.openFixingPullRequest(repository, fixBranchName, vulnDetails); err != nil {
+	if err = cfp.openFixingPullRequest(repository, fixBranchName, conflictingPullRequest, vulnDetails); err != nil {
 		return errors.Join(fmt.Errorf("failed while creating a fixing pull request for: %s with version: %s with error: ", vulnDetails.ImpactedDependencyName, fixVersion), err)
 	}
+	if cfp.Preview {
+		return
+	}
 	log.Info(fmt.Sprintf("Created Pull Request updating dependency '%s' to version '%s'", vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion))
 	return
 }
 
-func (cfp *ScanRepositoryCmd) openFixingPullRequest(repository *utils.Repository, fixBranchName string, vulnDetails *utils.VulnerabilityDetails) (err error) {
+// Commits the fix and opens its pull request. If the pull request already exists, its branch is replaced with the fix.
+// The fix is then backported to the backport branches.
+func (cfp *ScanRepositoryCmd) openFixingPullRequest(repository *utils.Repository, fixBranchName string, pullRequestInfo *vcsclient.PullRequestInfo, vulnDetails *utils.VulnerabilityDetails) (err error) {
 	log.Debug("Checking if there are changes to commit")
 	isClean, err := cfp.gitManager.IsClean()
 	if err != nil {
@@ -404,17 +884,21 @@ func (cfp *ScanRepositoryCmd) openFixingPullRequest(repository *utils.Repository
 		// In instances where a fix is required that Frogbot does not support, the worktree will remain clean, and there will be nothing to push
 		return &utils.ErrNothingToCommit{PackageName: vulnDetails.ImpactedDependencyName}
 	}
-	commitMessage := cfp.gitManager.GenerateCommitMessage(vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion)
+	commitMessage := cfp.gitManager.GenerateCommitMessage(cfp.scanDetails.BaseBranch(), cfp.projectWorkingDir, vulnDetails)
 	if err = cfp.cleanNewFilesMissingInRemote(); err != nil {
 		log.Warn(fmt.Sprintf("failed fo clean untracked files from '%s' due to the following errors: %s", cfp.baseWd, err.Error()))
 	}
 	if err = cfp.gitManager.AddAllAndCommit(commitMessage); err != nil {
 		return
 	}
-	if err = cfp.gitManager.Push(false, fixBranchName); err != nil {
+	if err = cfp.pushFixBranch(pullRequestInfo != nil, fixBranchName); err != nil {
+		return
+	}
+	if err = cfp.handleFixPullRequestContent(repository, fixBranchName, pullRequestInfo, vulnDetails); err != nil {
 		return
 	}
-	return cfp.handleFixPullRequestContent(repository, fixBranchName, nil, vulnDetails)
+	cfp.backportFix(repository, fixBranchName, map[string][]*utils.VulnerabilityDetails{cfp.projectWorkingDir: {vulnDetails}})
+	return
 }
 
 func (cfp *ScanRepositoryCmd) handleFixPullRequestContent(repository *utils.Repository, fixBranchName string, pullRequestInfo *vcsclient.PullRequestInfo, vulnerabilities ...*utils.VulnerabilityDetails) (err error) {
@@ -422,8 +906,14 @@ func (cfp *ScanRepositoryCmd) handleFixPullRequestContent(repository *utils.Repo
 	if err != nil {
 		return
 	}
+	if campaign := cfp.getCampaign(); campaign != nil {
+		pullRequestTitle = campaign.PullRequestTitle(pullRequestTitle)
+	}
+	if cfp.Preview {
+		return cfp.printPullRequestPreview(fixBranchName, pullRequestInfo, pullRequestTitle, prBody, extraComments)
+	}
 	// Update PR description
-	if pullRequestInfo, err = cfp.createOrUpdatePullRequest(repository, pullRequestInfo, fixBranchName, pullRequestTitle, prBody); err != nil {
+	if pullRequestInfo, err = cfp.createOrUpdatePullRequest(repository, pullRequestInfo, fixBranchName, pullRequestTitle, prBody, vulnerabilities...); err != nil {
 		return
 	}
 	// Update PR extra comments
@@ -437,36 +927,84 @@ func (cfp *ScanRepositoryCmd) handleFixPullRequestContent(repository *utils.Repo
 	return
 }
 
-func (cfp *ScanRepositoryCmd) createOrUpdatePullRequest(repository *utils.Repository, pullRequestInfo *vcsclient.PullRequestInfo, fixBranchName, pullRequestTitle, prBody string) (prInfo *vcsclient.PullRequestInfo, err error) {
+func (cfp *ScanRepositoryCmd) createOrUpdatePullRequest(repository *utils.Repository, pullRequestInfo *vcsclient.PullRequestInfo, fixBranchName, pullRequestTitle, prBody string, vulnerabilities ...*utils.VulnerabilityDetails) (prInfo *vcsclient.PullRequestInfo, err error) {
 	if pullRequestInfo == nil {
 		log.Info("Creating Pull Request from:", fixBranchName, "to:", cfp.scanDetails.BaseBranch())
 		if err = cfp.scanDetails.Client().CreatePullRequest(context.Background(), cfp.scanDetails.RepoOwner, cfp.scanDetails.RepoName, fixBranchName, cfp.scanDetails.BaseBranch(), pullRequestTitle, prBody); err != nil {
 			return
 		}
-		return cfp.getOpenPullRequestBySourceBranch(fixBranchName)
+		cfp.countNewFixPullRequest(vulnerabilities...)
+		cfp.recordFixPullRequest(true)
+		if prInfo, err = cfp.getOpenPullRequestBySourceBranch(fixBranchName); err != nil || prInfo == nil {
+			return
+		}
+		cfp.routePullRequest(repository, prInfo.ID)
+		return
 	}
 	log.Info("Updating Pull Request from:", fixBranchName, "to:", cfp.scanDetails.BaseBranch())
 	if err = cfp.scanDetails.Client().UpdatePullRequest(context.Background(), cfp.scanDetails.RepoOwner, cfp.scanDetails.RepoName, pullRequestTitle, prBody, pullRequestInfo.Target.Name, int(pullRequestInfo.ID), vcsutils.Open); err != nil {
 		return
 	}
+	cfp.recordFixPullRequest(false)
 	// Delete old extra comments
-	return pullRequestInfo, utils.DeletePullRequestComments(repository, cfp.scanDetails.Client(), int(pullRequestInfo.ID))
+	return pullRequestInfo, utils.DeletePullRequestComments(repository, cfp.scanDetails.Client(), int(pullRequestInfo.ID), nil)
 }
 
 // Handles the opening or updating of a pull request when the aggregate mode is active.
 // If a pull request is already open, Frogbot will update the branch and the pull request body.
-func (cfp *ScanRepositoryCmd) openAggregatedPullRequest(repository *utils.Repository, fixBranchName string, pullRequestInfo *vcsclient.PullRequestInfo, vulnerabilities []*utils.VulnerabilityDetails) (err error) {
-	commitMessage := cfp.gitManager.GenerateAggregatedCommitMessage(cfp.projectTech)
+// The fixed vulnerabilities by the working directories of their projects are backported to the backport branches.
+func (cfp *ScanRepositoryCmd) openAggregatedPullRequest(repository *utils.Repository, fixBranchName string, pullRequestInfo *vcsclient.PullRequestInfo, vulnerabilities []*utils.VulnerabilityDetails, fixesByWd map[string][]*utils.VulnerabilityDetails) (err error) {
+	commitMessage := cfp.gitManager.GenerateAggregatedCommitMessage(cfp.scanDetails.BaseBranch(), cfp.projectTech)
 	if err = cfp.cleanNewFilesMissingInRemote(); err != nil {
 		return
 	}
 	if err = cfp.gitManager.AddAllAndCommit(commitMessage); err != nil {
 		return
 	}
-	if err = cfp.gitManager.Push(true, fixBranchName); err != nil {
+	if err = cfp.pushFixBranch(true, fixBranchName); err != nil {
+		return
+	}
+	if err = cfp.handleFixPullRequestContent(repository, fixBranchName, pullRequestInfo, vulnerabilities...); err != nil {
 		return
 	}
-	return cfp.handleFixPullRequestContent(repository, fixBranchName, pullRequestInfo, vulnerabilities...)
+	cfp.backportFix(repository, fixBranchName, fixesByWd)
+	return
+}
+
+// In preview mode the fix branch is kept in the temporary clone only
+func (cfp *ScanRepositoryCmd) pushFixBranch(force bool, fixBranchName string) error {
+	if cfp.Preview {
+		return nil
+	}
+	return cfp.gitManager.Push(force, fixBranchName)
+}
+
+// Prints the pull request that would be opened or updated with the fix, and the diff of the fix commit
+func (cfp *ScanRepositoryCmd) printPullRequestPreview(fixBranchName string, pullRequestInfo *vcsclient.PullRequestInfo, pullRequestTitle, prBody string, extraComments []string) (err error) {
+	diff, err := cfp.gitManager.GetLastCommitDiff()
+	if err != nil {
+		return
+	}
+	action := "open a pull request"
+	if pullRequestInfo != nil {
+		action = fmt.Sprintf("update pull request #%d", pullRequestInfo.ID)
+	}
+	preview := &strings.Builder{}
+	preview.WriteString(fmt.Sprintf("===== Preview: Frogbot would %s from '%s' to '%s'\n", action, fixBranchName, cfp.scanDetails.BaseBranch()))
+	preview.WriteString(fmt.Sprintf("Title: %s\n\n%s\n", pullRequestTitle, prBody))
+	for _, comment := range extraComments {
+		preview.WriteString(fmt.Sprintf("\n----- Pull request comment:\n%s\n", comment))
+	}
+	preview.WriteString(fmt.Sprintf("\n----- Changes:\n%s\n", diff))
+	_, err = io.WriteString(cfp.getPreviewOutput(), preview.String())
+	return
+}
+
+func (cfp *ScanRepositoryCmd) getPreviewOutput() io.Writer {
+	if cfp.previewOutput == nil {
+		return os.Stdout
+	}
+	return cfp.previewOutput
 }
 
 func (cfp *ScanRepositoryCmd) cleanNewFilesMissingInRemote() error {
@@ -504,22 +1042,46 @@ func (cfp *ScanRepositoryCmd) cleanNewFilesMissingInRemote() error {
 func (cfp *ScanRepositoryCmd) preparePullRequestDetails(vulnerabilitiesDetails ...*utils.VulnerabilityDetails) (prTitle, prBody string, otherComments []string, err error) {
 	if cfp.dryRun && cfp.aggregateFixes {
 		// For testings, don't compare pull request body as scan results order may change.
-		return cfp.gitManager.GenerateAggregatedPullRequestTitle(cfp.projectTech), "", []string{}, nil
+		return cfp.gitManager.GenerateAggregatedPullRequestTitle(cfp.scanDetails.BaseBranch(), cfp.projectTech), "", []string{}, nil
 	}
 	vulnerabilitiesRows := utils.ExtractVulnerabilitiesDetailsToRows(vulnerabilitiesDetails)
 
-	prBody, extraComments := utils.GenerateFixPullRequestDetails(vulnerabilitiesRows, cfp.OutputWriter)
+	var extraContent []string
+	if cfp.aggregateFixes && len(cfp.pullRequestFailedFixes) > 0 {
+		extraContent = append(extraContent, outputwriter.FailedFixesContent(cfp.pullRequestFailedFixes, cfp.OutputWriter))
+	}
+	if cfp.aggregateFixes && cfp.scanDetails != nil && cfp.scanDetails.ShowUnsupportedFixes && len(cfp.pullRequestUnsupportedFixes) > 0 {
+		extraContent = append(extraContent, outputwriter.UnsupportedFixesContent(cfp.pullRequestUnsupportedFixes, cfp.OutputWriter))
+	}
+	if updatedWorkspacesRows := utils.GetUpdatedWorkspacesRows(vulnerabilitiesDetails); len(updatedWorkspacesRows) > 0 {
+		extraContent = append(extraContent, outputwriter.UpdatedWorkspacesContent(updatedWorkspacesRows, cfp.OutputWriter))
+	}
+	if upgradeRiskRows := utils.GetUpgradeRiskRows(vulnerabilitiesDetails); len(upgradeRiskRows) > 0 {
+		extraContent = append(extraContent, outputwriter.UpgradeRiskContent(upgradeRiskRows, cfp.OutputWriter))
+	}
+	if releaseNotesRows := utils.GetReleaseNotesRows(vulnerabilitiesDetails, cfp.scanDetails != nil && cfp.scanDetails.NpmDiffLinks); len(releaseNotesRows) > 0 {
+		extraContent = append(extraContent, outputwriter.ReleaseNotesContent(releaseNotesRows, cfp.OutputWriter))
+	}
+	if cfp.aggregateFixes && cfp.scanDelta != nil {
+		extraContent = append(extraContent, outputwriter.ScanDeltaContent(cfp.scanDelta.String()))
+	}
+	if cfp.sbomBuilder != nil {
+		extraContent = append(extraContent, outputwriter.SbomContent(filepath.Base(cfp.sbomPath), utils.GetCiRunUrl(), cfp.OutputWriter))
+	}
+	prBody, extraComments := utils.GenerateFixPullRequestDetails(vulnerabilitiesRows, cfp.OutputWriter, extraContent...)
+	prBody += cfp.getAutoMergeMarker(vulnerabilitiesDetails)
 
 	if cfp.aggregateFixes {
 		var scanHash string
 		if scanHash, err = utils.VulnerabilityDetailsToMD5Hash(vulnerabilitiesRows...); err != nil {
 			return
 		}
-		return cfp.gitManager.GenerateAggregatedPullRequestTitle(cfp.projectTech), prBody + outputwriter.MarkdownComment(fmt.Sprintf("Checksum: %s", scanHash)), extraComments, nil
+		pullRequestTitle := utils.AddUpgradeRiskToTitle(cfp.gitManager.GenerateAggregatedPullRequestTitle(cfp.scanDetails.BaseBranch(), cfp.projectTech), vulnerabilitiesDetails)
+		return pullRequestTitle, prBody + outputwriter.MarkdownComment(fmt.Sprintf("Checksum: %s", scanHash)), extraComments, nil
 	}
 	// In separate pull requests there is only one vulnerability
 	vulnDetails := vulnerabilitiesDetails[0]
-	pullRequestTitle := cfp.gitManager.GeneratePullRequestTitle(vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion)
+	pullRequestTitle := utils.AddUpgradeRiskToTitle(cfp.gitManager.GeneratePullRequestTitle(cfp.scanDetails.BaseBranch(), cfp.projectWorkingDir, vulnDetails), vulnerabilitiesDetails)
 	return pullRequestTitle, prBody, extraComments, nil
 }
 
@@ -587,7 +1149,26 @@ func (cfp *ScanRepositoryCmd) createVulnerabilitiesMap(scanResults *results.Secu
 }
 
 func (cfp *ScanRepositoryCmd) addVulnerabilityToFixVersionsMap(vulnerability *formats.VulnerabilityOrViolationRow, vulnerabilitiesMap map[string]*utils.VulnerabilityDetails) error {
+	if !cfp.isTargetedVulnerability(vulnerability) {
+		return nil
+	}
+	if cfp.fixApplicableOnly && vulnerability.Applicable == jasutils.NotApplicable.String() {
+		log.Info(fmt.Sprintf("Skipping a vulnerability of %s:%s, since it isn't applicable according to the Contextual Analysis", vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion))
+		return nil
+	}
+	if cfp.fixMinSeverity != "" && severityutils.CompareSeverity(severityutils.GetSeverity(vulnerability.Severity), severityutils.GetSeverity(cfp.fixMinSeverity)) < 0 {
+		log.Info(fmt.Sprintf("Skipping a %s vulnerability of %s:%s, since only the vulnerabilities with %s severity or higher are fixed", vulnerability.Severity, vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion, cfp.fixMinSeverity))
+		return nil
+	}
+	if cfp.skipDevDependencies && !cfp.OutputWriter.DependencyScopes().GetScope(vulnerability.Components).IsProduction() {
+		log.Info(fmt.Sprintf("Skipping a vulnerability of %s:%s, since only development and test dependencies bring it in", vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion))
+		return nil
+	}
 	if len(vulnerability.FixedVersions) == 0 {
+		if cfp.scanDetails != nil && cfp.scanDetails.TrackUnfixableVulnerabilities {
+			cfp.addUnfixableWorkItems(vulnerability)
+		}
+		cfp.addNoFixedVersionUnsupportedFix(vulnerability)
 		return nil
 	}
 	if len(cfp.projectTech) == 0 {
@@ -672,9 +1253,64 @@ func (cfp *ScanRepositoryCmd) getOpenPullRequestBySourceBranch(branchName string
 	return
 }
 
+// Returns the open pull request of the branch if it has merge conflicts with the base branch, or nil otherwise
+func (cfp *ScanRepositoryCmd) getConflictingPullRequest(branchName string) (prInfo *vcsclient.PullRequestInfo, err error) {
+	if prInfo, err = cfp.getOpenPullRequestBySourceBranch(branchName); err != nil || prInfo == nil {
+		return
+	}
+	if !cfp.hasMergeConflicts(prInfo) {
+		return nil, nil
+	}
+	return
+}
+
+// Returns true if the pull request can't be merged, since its branch conflicts with the base branch.
+// Failures to check the pull request are logged, and the pull request is considered mergeable.
+func (cfp *ScanRepositoryCmd) hasMergeConflicts(prInfo *vcsclient.PullRequestInfo) bool {
+	checker, err := mergeconflicts.NewChecker(cfp.scanDetails.Git.GitProvider, cfp.scanDetails.Git.VcsInfo, cfp.scanDetails.RepoOwner, cfp.scanDetails.RepoName)
+	if err != nil {
+		log.Debug(err.Error())
+		return false
+	}
+	hasConflicts, err := checker.HasConflicts(prInfo.ID)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't check whether pull request #%d has merge conflicts: %s", prInfo.ID, err.Error()))
+		return false
+	}
+	return hasConflicts
+}
+
+// Returns true if the base branch has at least the configured number of commits that the branch of the pull request doesn't have.
+// Without a configured number, the pull requests are refreshed only if they have merge conflicts.
+// Failures to check the pull request are logged, and the pull request is considered up to date.
+func (cfp *ScanRepositoryCmd) isBehindBaseBranch(prInfo *vcsclient.PullRequestInfo) bool {
+	threshold := cfp.scanDetails.Git.RefreshBehindBaseCommits
+	if threshold == 0 {
+		return false
+	}
+	checker, err := mergeconflicts.NewChecker(cfp.scanDetails.Git.GitProvider, cfp.scanDetails.Git.VcsInfo, cfp.scanDetails.RepoOwner, cfp.scanDetails.RepoName)
+	if err != nil {
+		log.Debug(err.Error())
+		return false
+	}
+	pullRequest := *prInfo
+	if pullRequest.Target.Name == "" {
+		pullRequest.Target.Name = cfp.scanDetails.BaseBranch()
+	}
+	behindBy, err := checker.BehindBy(&pullRequest, threshold)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't check whether pull request #%d is behind the '%s' branch: %s", prInfo.ID, pullRequest.Target.Name, err.Error()))
+		return false
+	}
+	return behindBy >= threshold
+}
+
 func (cfp *ScanRepositoryCmd) aggregateFixAndOpenPullRequest(repository *utils.Repository, vulnerabilitiesMap map[string]map[string]*utils.VulnerabilityDetails, aggregatedFixBranchName string, existingPullRequestInfo *vcsclient.PullRequestInfo) (err error) {
 	log.Info("-----------------------------------------------------------------")
 	log.Info("Starting aggregated dependencies fix")
+	if existingPullRequestInfo == nil && cfp.queueFixIfLimited(fmt.Sprintf("the aggregated pull request '%s'", aggregatedFixBranchName), getAggregatedVulnerabilities(vulnerabilitiesMap)...) {
+		return
+	}
 
 	workTreeIsClean, err := cfp.gitManager.IsClean()
 	if err != nil {
@@ -690,16 +1326,22 @@ func (cfp *ScanRepositoryCmd) aggregateFixAndOpenPullRequest(repository *utils.R
 		return
 	}
 
-	// Fix all packages in the same branch if expected error accrued, log and continue.
+	// Fix all packages in the same branch. The packages that fail to be updated are rolled back and listed in the pull request, and the rest are fixed.
 	var fixedVulnerabilities []*utils.VulnerabilityDetails
+	fixesByWd := map[string][]*utils.VulnerabilityDetails{}
+	firstUnsupportedFix := len(cfp.unsupportedFixes)
+	cfp.pullRequestFailedFixes = nil
 	for fullPath, vulnerabilities := range vulnerabilitiesMap {
 		currentFixes, e := cfp.fixMultiplePackages(fullPath, vulnerabilities)
+		fixedVulnerabilities = append(fixedVulnerabilities, currentFixes...)
+		if len(currentFixes) > 0 {
+			fixesByWd[utils.GetRelativeWd(fullPath, cfp.baseWd)] = currentFixes
+		}
 		if e != nil {
 			err = errors.Join(err, fmt.Errorf("the following errors occurred while fixing vulnerabilities in %s:\n%s", fullPath, e))
-			continue
 		}
-		fixedVulnerabilities = append(fixedVulnerabilities, currentFixes...)
 	}
+	cfp.pullRequestUnsupportedFixes = cfp.unsupportedFixes[firstUnsupportedFix:]
 	updateRequired, e := cfp.isUpdateRequired(fixedVulnerabilities, existingPullRequestInfo)
 	if e != nil {
 		err = errors.Join(err, e)
@@ -711,7 +1353,7 @@ func (cfp *ScanRepositoryCmd) aggregateFixAndOpenPullRequest(repository *utils.R
 		return
 	}
 	if len(fixedVulnerabilities) > 0 {
-		if e = cfp.openAggregatedPullRequest(repository, aggregatedFixBranchName, existingPullRequestInfo, fixedVulnerabilities); e != nil {
+		if e = cfp.openAggregatedPullRequest(repository, aggregatedFixBranchName, existingPullRequestInfo, fixedVulnerabilities, fixesByWd); e != nil {
 			err = errors.Join(err, fmt.Errorf("failed while creating aggregated pull request. Error: \n%s", e.Error()))
 		}
 	}
@@ -723,6 +1365,7 @@ func (cfp *ScanRepositoryCmd) aggregateFixAndOpenPullRequest(repository *utils.R
 // Determines whether an update is necessary:
 // First, checks if the working tree is clean. If so, no update is required.
 // Second, checks if there is an already open pull request for the fix. If so, no update is needed.
+// Then, checks if the existing pull request has merge conflicts with the base branch, or is behind it by the configured number of commits. If so, it's updated from the base branch.
 // Lastly, performs a comparison of Xray scan result hashes between an existing pull request's remote source branch and the current source branch to identify any differences.
 func (cfp *ScanRepositoryCmd) isUpdateRequired(fixedVulnerabilities []*utils.VulnerabilityDetails, prInfo *vcsclient.PullRequestInfo) (updateRequired bool, err error) {
 	isClean, err := cfp.gitManager.IsClean()
@@ -740,6 +1383,18 @@ func (cfp *ScanRepositoryCmd) isUpdateRequired(fixedVulnerabilities []*utils.Vul
 		return
 	}
 	log.Info("Aggregated pull request already exists, verifying if update is needed...")
+	if cfp.hasMergeConflicts(prInfo) {
+		// The fix branch is created from the base branch, so the force push resolves the conflicts
+		log.Info(fmt.Sprintf("The existing pull request has merge conflicts with the '%s' branch, updating pull request...", cfp.scanDetails.BaseBranch()))
+		updateRequired = true
+		return
+	}
+	if cfp.isBehindBaseBranch(prInfo) {
+		// The fix branch is recreated from the base branch, so it's refreshed even if the scan results didn't change
+		log.Info(fmt.Sprintf("The existing pull request is %d or more commits behind the '%s' branch, updating pull request...", cfp.scanDetails.Git.RefreshBehindBaseCommits, cfp.scanDetails.BaseBranch()))
+		updateRequired = true
+		return
+	}
 	log.Debug("Comparing current scan results to existing", prInfo.Target.Name, "scan results")
 	fixedVulnerabilitiesRows := utils.ExtractVulnerabilitiesDetailsToRows(fixedVulnerabilities)
 	currentScanHash, err := utils.VulnerabilityDetailsToMD5Hash(fixedVulnerabilitiesRows...)
@@ -799,3 +1454,20 @@ func isBuildToolsDependency(vulnDetails *utils.VulnerabilityDetails) error {
 	}
 	return nil
 }
+
+// Returns false if the vulnerability isn't related to one of the target CVEs or packages, or isn't fixed by the running fix campaign
+func (cfp *ScanRepositoryCmd) isTargetedVulnerability(vulnerability *formats.VulnerabilityOrViolationRow) bool {
+	if len(cfp.targetCves) > 0 && !utils.IsTargetedVulnerability(*vulnerability, cfp.targetCves) {
+		return false
+	}
+	campaign := cfp.getCampaign()
+	return campaign == nil || campaign.IsTargeted(*vulnerability)
+}
+
+// Returns the fix campaign the command runs as part of, or nil if no campaign is running
+func (cfp *ScanRepositoryCmd) getCampaign() *utils.Campaign {
+	if cfp.scanDetails == nil || cfp.scanDetails.Git == nil {
+		return nil
+	}
+	return cfp.scanDetails.Campaign
+}
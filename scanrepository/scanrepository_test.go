@@ -1,9 +1,11 @@
 package scanrepository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-security/utils/xsc"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
@@ -11,15 +13,24 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/golang/mock/gomock"
 	"github.com/google/go-github/v45/github"
 	biutils "github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/frogbot/v2/testdata"
 	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/alternatives"
+	"github.com/jfrog/frogbot/v2/utils/dependencyscope"
+	"github.com/jfrog/frogbot/v2/utils/exploitability"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/sbom"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/validations"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -27,6 +38,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
 )
 
 const rootTestDir = "scanrepository"
@@ -390,7 +402,9 @@ func TestGenerateFixBranchName(t *testing.T) {
 	gitManager := utils.GitManager{}
 	for _, test := range tests {
 		t.Run(test.expectedName, func(t *testing.T) {
-			branchName, err := gitManager.GenerateFixBranchName(test.baseBranch, test.impactedPackage, test.fixVersion)
+			vulnDetails := &utils.VulnerabilityDetails{SuggestedFixedVersion: test.fixVersion}
+			vulnDetails.ImpactedDependencyName = test.impactedPackage
+			branchName, err := gitManager.GenerateFixBranchName(test.baseBranch, "", vulnDetails)
 			assert.NoError(t, err)
 			assert.Equal(t, test.expectedName, branchName)
 		})
@@ -645,7 +659,7 @@ random body
 }
 
 func TestPreparePullRequestDetails(t *testing.T) {
-	cfp := ScanRepositoryCmd{OutputWriter: &outputwriter.StandardOutput{}, gitManager: &utils.GitManager{}}
+	cfp := ScanRepositoryCmd{OutputWriter: &outputwriter.StandardOutput{}, gitManager: &utils.GitManager{}, scanDetails: &utils.ScanDetails{Git: &utils.Git{}}}
 	cfp.OutputWriter.SetJasOutputFlags(true, false)
 	vulnerabilities := []*utils.VulnerabilityDetails{
 		{
@@ -686,7 +700,7 @@ func TestPreparePullRequestDetails(t *testing.T) {
 	expectedPrBody += outputwriter.MarkdownComment("Checksum: bec823edaceb5d0478b789798e819bde")
 	prTitle, prBody, extraComments, err = cfp.preparePullRequestDetails(vulnerabilities...)
 	assert.NoError(t, err)
-	assert.Equal(t, cfp.gitManager.GenerateAggregatedPullRequestTitle([]techutils.Technology{}), prTitle)
+	assert.Equal(t, cfp.gitManager.GenerateAggregatedPullRequestTitle("", []techutils.Technology{}), prTitle)
 	assert.Equal(t, expectedPrBody, prBody)
 	assert.ElementsMatch(t, expectedExtraComments, extraComments)
 	cfp.OutputWriter = &outputwriter.SimplifiedOutput{}
@@ -694,9 +708,205 @@ func TestPreparePullRequestDetails(t *testing.T) {
 	expectedPrBody += outputwriter.MarkdownComment("Checksum: bec823edaceb5d0478b789798e819bde")
 	prTitle, prBody, extraComments, err = cfp.preparePullRequestDetails(vulnerabilities...)
 	assert.NoError(t, err)
-	assert.Equal(t, cfp.gitManager.GenerateAggregatedPullRequestTitle([]techutils.Technology{}), prTitle)
+	assert.Equal(t, cfp.gitManager.GenerateAggregatedPullRequestTitle("", []techutils.Technology{}), prTitle)
 	assert.Equal(t, expectedPrBody, prBody)
 	assert.ElementsMatch(t, expectedExtraComments, extraComments)
+	// The aggregated pull request body lists the vulnerabilities that couldn't be fixed
+	cfp.scanDetails = &utils.ScanDetails{Git: &utils.Git{ShowUnsupportedFixes: true}}
+	cfp.pullRequestUnsupportedFixes = []outputwriter.UnsupportedFixRow{{VulnerabilityOrViolationRow: vulnerabilities[0].VulnerabilityOrViolationRow, Reason: "Indirect dependency"}}
+	_, prBody, _, err = cfp.preparePullRequestDetails(vulnerabilities...)
+	assert.NoError(t, err)
+	assert.Contains(t, prBody, outputwriter.UnsupportedFixesContent(cfp.pullRequestUnsupportedFixes, cfp.OutputWriter))
+	// The aggregated pull request body lists the updates that failed and were rolled back
+	cfp.addFailedFix(vulnerabilities[0], errors.New("npm install failed\nnpm ERR! code ERESOLVE"))
+	assert.Equal(t, "npm install failed", cfp.pullRequestFailedFixes[0].Reason)
+	_, prBody, _, err = cfp.preparePullRequestDetails(vulnerabilities...)
+	assert.NoError(t, err)
+	assert.Contains(t, prBody, outputwriter.FailedFixesContent(cfp.pullRequestFailedFixes, cfp.OutputWriter))
+	// The pull request body points to the generated SBOM
+	cfp.sbomBuilder, cfp.sbomPath = sbom.NewCycloneDxBuilder(), filepath.Join("reports", "frogbot-sbom.json")
+	_, prBody, _, err = cfp.preparePullRequestDetails(vulnerabilities...)
+	assert.NoError(t, err)
+	assert.Contains(t, prBody, outputwriter.SbomContent("frogbot-sbom.json", utils.GetCiRunUrl(), cfp.OutputWriter))
+}
+
+func TestPreviewFixPullRequest(t *testing.T) {
+	repoDir := t.TempDir()
+	restoreWd, err := utils.Chdir(repoDir)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreWd())
+	}()
+	_, err = git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	gitManager := utils.NewGitManager().SetEmailAuthor("frogbot@jfrog.com")
+	require.NoError(t, gitManager.SetLocalRepository())
+	require.NoError(t, os.WriteFile("package.json", []byte(`{"dependencies":{"minimist":"1.2.5"}}`), 0644))
+	require.NoError(t, gitManager.AddAllAndCommit("Initial commit"))
+
+	output := &strings.Builder{}
+	cfp := ScanRepositoryCmd{
+		Preview:       true,
+		previewOutput: output,
+		OutputWriter:  &outputwriter.StandardOutput{},
+		gitManager:    gitManager,
+		scanDetails:   utils.NewScanDetails(nil, nil, &utils.Git{}).SetBaseBranch("master"),
+	}
+	vulnDetails := &utils.VulnerabilityDetails{
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    "minimist",
+				ImpactedDependencyVersion: "1.2.5",
+			},
+			Cves: []formats.CveRow{{Id: "CVE-2021-44906"}},
+		},
+		SuggestedFixedVersion: "1.2.6",
+	}
+	require.NoError(t, gitManager.CreateBranchAndCheckout("frogbot-minimist", false))
+	require.NoError(t, os.WriteFile("package.json", []byte(`{"dependencies":{"minimist":"1.2.6"}}`), 0644))
+	// The client isn't set, so the test fails if the fix is pushed or a pull request is opened
+	require.NoError(t, cfp.openFixingPullRequest(&utils.Repository{}, "frogbot-minimist", nil, vulnDetails))
+
+	preview := output.String()
+	assert.Contains(t, preview, "===== Preview: Frogbot would open a pull request from 'frogbot-minimist' to 'master'")
+	assert.Contains(t, preview, "Title: [🐸 Frogbot] Update version of minimist to 1.2.6")
+	assert.Contains(t, preview, "--- a/package.json\n+++ b/package.json\n")
+	assert.Contains(t, preview, `+{"dependencies":{"minimist":"1.2.6"}}`)
+
+	// An existing pull request is updated
+	output.Reset()
+	require.NoError(t, cfp.handleFixPullRequestContent(&utils.Repository{}, "frogbot-minimist", &vcsclient.PullRequestInfo{ID: 12}, vulnDetails))
+	assert.Contains(t, output.String(), "Frogbot would update pull request #12 from 'frogbot-minimist' to 'master'")
+}
+
+func TestGetConflictingPullRequest(t *testing.T) {
+	mergeable := map[string]bool{"/repos/jfrog/frogbot/pulls/1": true, "/repos/jfrog/frogbot/pulls/2": false}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintf(w, `{"mergeable":%t}`, mergeable[r.URL.Path])
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().ListOpenPullRequestsWithBody(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{
+		{ID: 1, Source: vcsclient.BranchInfo{Name: "frogbot-mergeable"}},
+		{ID: 2, Source: vcsclient.BranchInfo{Name: "frogbot-conflicting"}},
+	}, nil).Times(3)
+	git := &utils.Git{GitProvider: vcsutils.GitHub, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, RepoOwner: "jfrog", RepoName: "frogbot"}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(mockVcsClient, nil, git)}
+
+	prInfo, err := cfp.getConflictingPullRequest("frogbot-conflicting")
+	require.NoError(t, err)
+	require.NotNil(t, prInfo)
+	assert.Equal(t, int64(2), prInfo.ID)
+	// Mergeable pull requests and branches without open pull requests are skipped
+	prInfo, err = cfp.getConflictingPullRequest("frogbot-mergeable")
+	require.NoError(t, err)
+	assert.Nil(t, prInfo)
+	prInfo, err = cfp.getConflictingPullRequest("frogbot-closed")
+	require.NoError(t, err)
+	assert.Nil(t, prInfo)
+}
+
+func TestIsBehindBaseBranch(t *testing.T) {
+	behindBy := map[string]int{"/repos/jfrog/frogbot/compare/master...frogbot-behind": 2, "/repos/jfrog/frogbot/compare/master...frogbot-up-to-date": 0}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, exists := behindBy[r.URL.Path]; !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := fmt.Fprintf(w, `{"behind_by":%d}`, behindBy[r.URL.Path])
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	git := &utils.Git{GitProvider: vcsutils.GitHub, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, RepoOwner: "jfrog", RepoName: "frogbot", Branches: []string{"master"}}
+	cfp := ScanRepositoryCmd{scanDetails: utils.NewScanDetails(nil, nil, git).SetBaseBranch("master")}
+	behindPullRequest := &vcsclient.PullRequestInfo{ID: 1, Source: vcsclient.BranchInfo{Name: "frogbot-behind"}, Target: vcsclient.BranchInfo{Name: "master"}}
+
+	// Without a threshold, only the pull requests with merge conflicts are refreshed
+	assert.False(t, cfp.isBehindBaseBranch(behindPullRequest))
+	// The pull requests are refreshed from the threshold
+	git.RefreshBehindBaseCommits = 3
+	assert.False(t, cfp.isBehindBaseBranch(behindPullRequest))
+	git.RefreshBehindBaseCommits = 2
+	assert.True(t, cfp.isBehindBaseBranch(behindPullRequest))
+	// The base branch is compared if the target branch of the pull request is unknown
+	assert.False(t, cfp.isBehindBaseBranch(&vcsclient.PullRequestInfo{ID: 2, Source: vcsclient.BranchInfo{Name: "frogbot-up-to-date"}}))
+	// Failures to compare the branches are considered up to date
+	assert.False(t, cfp.isBehindBaseBranch(&vcsclient.PullRequestInfo{ID: 3, Source: vcsclient.BranchInfo{Name: "frogbot-deleted"}, Target: vcsclient.BranchInfo{Name: "master"}}))
+}
+
+func TestHandleUpdatePackageErrors(t *testing.T) {
+	cfp := ScanRepositoryCmd{}
+	vulnDetails := &utils.VulnerabilityDetails{
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "minimatch", ImpactedDependencyVersion: "3.0.4"},
+		},
+		SuggestedFixedVersion: "3.0.5",
+	}
+	assert.NoError(t, cfp.handleUpdatePackageErrors(&utils.ErrUnsupportedFix{PackageName: "minimatch", FixedVersion: "3.0.5", ErrorType: utils.IndirectDependencyFixNotSupported}, vulnDetails))
+	assert.NoError(t, cfp.handleUpdatePackageErrors(&utils.ErrNothingToCommit{PackageName: "minimatch"}, vulnDetails))
+	assert.Error(t, cfp.handleUpdatePackageErrors(errors.New("failed"), vulnDetails))
+	// Only the unsupported fix is collected
+	assert.Equal(t, []outputwriter.UnsupportedFixRow{{VulnerabilityOrViolationRow: vulnDetails.VulnerabilityOrViolationRow, SuggestedFixedVersion: "3.0.5", Reason: "Indirect dependency"}}, cfp.unsupportedFixes)
+}
+
+func TestSplitVulnerabilitiesBySeverity(t *testing.T) {
+	newVulnDetails := func(name, severity, applicable string) *utils.VulnerabilityDetails {
+		return &utils.VulnerabilityDetails{VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				ImpactedDependencyName: name,
+				SeverityDetails:        formats.SeverityDetails{Severity: severity},
+			},
+			Applicable: applicable,
+		}}
+	}
+	log4j := newVulnDetails("log4j-core", "Critical", jasutils.Applicable.String())
+	jackson := newVulnDetails("jackson-databind", "High", "")
+	commonsText := newVulnDetails("commons-text", "Critical", jasutils.NotApplicable.String())
+	minimist := newVulnDetails("minimist", "Medium", jasutils.Applicable.String())
+	lodash := newVulnDetails("lodash", "Low", "")
+	vulnerabilitiesMap := map[string]map[string]*utils.VulnerabilityDetails{
+		"backend":  {"log4j-core": log4j, "jackson-databind": jackson, "commons-text": commonsText},
+		"frontend": {"minimist": minimist, "lodash": lodash},
+	}
+
+	immediateFixes, deferredFixes := splitVulnerabilitiesBySeverity(vulnerabilitiesMap, severityutils.High)
+	assert.Equal(t, map[string]map[string]*utils.VulnerabilityDetails{
+		"backend": {"log4j-core": log4j, "jackson-databind": jackson},
+	}, immediateFixes)
+	assert.Equal(t, map[string]map[string]*utils.VulnerabilityDetails{
+		"backend":  {"commons-text": commonsText},
+		"frontend": {"minimist": minimist, "lodash": lodash},
+	}, deferredFixes)
+
+	immediateFixes, deferredFixes = splitVulnerabilitiesBySeverity(vulnerabilitiesMap, severityutils.Critical)
+	assert.Equal(t, map[string]map[string]*utils.VulnerabilityDetails{"backend": {"log4j-core": log4j}}, immediateFixes)
+	assert.Len(t, deferredFixes["backend"], 2)
+	assert.Len(t, deferredFixes["frontend"], 2)
+}
+
+func TestSplitKnownExploitedVulnerabilities(t *testing.T) {
+	newVulnDetails := func(name string, exploitabilityInfo *exploitability.Info) *utils.VulnerabilityDetails {
+		return &utils.VulnerabilityDetails{
+			VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: name}},
+			Exploitability:              exploitabilityInfo,
+		}
+	}
+	log4j := newVulnDetails("log4j-core", &exploitability.Info{KnownExploited: true, EpssScore: 0.97, HasEpss: true})
+	jackson := newVulnDetails("jackson-databind", &exploitability.Info{EpssScore: 0.5, HasEpss: true})
+	minimist := newVulnDetails("minimist", nil)
+	vulnerabilitiesMap := map[string]map[string]*utils.VulnerabilityDetails{
+		"backend":  {"log4j-core": log4j, "jackson-databind": jackson},
+		"frontend": {"minimist": minimist},
+	}
+
+	exploitedFixes, otherFixes := splitKnownExploitedVulnerabilities(vulnerabilitiesMap)
+	assert.Equal(t, map[string]map[string]*utils.VulnerabilityDetails{"backend": {"log4j-core": log4j}}, exploitedFixes)
+	assert.Equal(t, map[string]map[string]*utils.VulnerabilityDetails{
+		"backend":  {"jackson-databind": jackson},
+		"frontend": {"minimist": minimist},
+	}, otherFixes)
 }
 
 // This test simulates the cleaning action of cleanNewFilesMissingInRemote.
@@ -815,3 +1025,139 @@ func verifyLockFileDiff(branchToInspect string, lockFiles ...string) (output []b
 	}
 	return
 }
+
+func TestIsTargetedVulnerabilityWithTargetCves(t *testing.T) {
+	log4j := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "log4j-core", ImpactedDependencyVersion: "2.14.1"},
+		Cves:                      []formats.CveRow{{Id: "CVE-2021-44228"}},
+	}
+	minimist := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"},
+		Cves:                      []formats.CveRow{{Id: "CVE-2021-44906"}},
+	}
+	cfp := ScanRepositoryCmd{}
+	assert.True(t, cfp.isTargetedVulnerability(&log4j))
+	assert.True(t, cfp.isTargetedVulnerability(&minimist))
+
+	cfp.targetCves = []string{"CVE-2021-44228"}
+	assert.True(t, cfp.isTargetedVulnerability(&log4j))
+	assert.False(t, cfp.isTargetedVulnerability(&minimist))
+
+	cfp.targetCves = []string{"minimist"}
+	assert.False(t, cfp.isTargetedVulnerability(&log4j))
+	assert.True(t, cfp.isTargetedVulnerability(&minimist))
+}
+
+func TestAddVulnerabilityToFixVersionsMapWithFixApplicableOnly(t *testing.T) {
+	newVulnerability := func(name, applicable string) formats.VulnerabilityOrViolationRow {
+		return formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				ImpactedDependencyName:    name,
+				ImpactedDependencyVersion: "1.0.0",
+				Components:                []formats.ComponentRow{{Name: name, Version: "1.0.0"}},
+			},
+			FixedVersions: []string{"[1.0.1]"},
+			ImpactPaths:   [][]formats.ComponentRow{{{Name: "root"}, {Name: name, Version: "1.0.0"}}},
+			Applicable:    applicable,
+		}
+	}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		newVulnerability("applicable", "Applicable"),
+		newVulnerability("not-applicable", "Not Applicable"),
+		newVulnerability("undetermined", "Undetermined"),
+	}
+	for _, fixApplicableOnly := range []bool{false, true} {
+		cfp := ScanRepositoryCmd{scanDetails: &utils.ScanDetails{}, fixApplicableOnly: fixApplicableOnly}
+		vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+		for i := range vulnerabilities {
+			require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap))
+		}
+		expected := []string{"applicable", "undetermined"}
+		if !fixApplicableOnly {
+			expected = append(expected, "not-applicable")
+		}
+		assert.ElementsMatch(t, expected, maps.Keys(vulnerabilitiesMap))
+	}
+}
+
+func TestAddVulnerabilityToFixVersionsMapWithFixMinSeverity(t *testing.T) {
+	newVulnerability := func(name, severity string) formats.VulnerabilityOrViolationRow {
+		return formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: severity},
+				ImpactedDependencyName:    name,
+				ImpactedDependencyVersion: "1.0.0",
+			},
+			FixedVersions: []string{"[1.0.1]"},
+			ImpactPaths:   [][]formats.ComponentRow{{{Name: "root"}, {Name: name, Version: "1.0.0"}}},
+		}
+	}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		newVulnerability("critical", "Critical"),
+		newVulnerability("high", "High"),
+		newVulnerability("medium", "Medium"),
+		newVulnerability("low", "Low"),
+	}
+	cfp := ScanRepositoryCmd{scanDetails: &utils.ScanDetails{}, fixMinSeverity: "High"}
+	vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+	for i := range vulnerabilities {
+		require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap))
+	}
+	assert.ElementsMatch(t, []string{"critical", "high"}, maps.Keys(vulnerabilitiesMap))
+}
+
+func TestAddVulnerabilityToFixVersionsMapWithSkipDevDependencies(t *testing.T) {
+	newVulnerability := func(name string, directDependencies ...string) formats.VulnerabilityOrViolationRow {
+		vulnerability := formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: name, ImpactedDependencyVersion: "1.0.0"},
+			FixedVersions:             []string{"[1.0.1]"},
+			ImpactPaths:               [][]formats.ComponentRow{{{Name: "root"}, {Name: name, Version: "1.0.0"}}},
+		}
+		for _, directDependency := range directDependencies {
+			vulnerability.Components = append(vulnerability.Components, formats.ComponentRow{Name: directDependency})
+		}
+		return vulnerability
+	}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		newVulnerability("express", "express"),
+		newVulnerability("jest", "jest"),
+		newVulnerability("minimist", "jest", "express"),
+		newVulnerability("unknown", "unknown"),
+	}
+	for _, skipDevDependencies := range []bool{false, true} {
+		cfp := ScanRepositoryCmd{scanDetails: &utils.ScanDetails{}, skipDevDependencies: skipDevDependencies, OutputWriter: &outputwriter.StandardOutput{}}
+		cfp.OutputWriter.SetDependencyScopes(dependencyscope.Scopes{"express": dependencyscope.Prod, "jest": dependencyscope.Dev})
+		vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+		for i := range vulnerabilities {
+			require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap))
+		}
+		expected := []string{"express", "minimist", "unknown"}
+		if !skipDevDependencies {
+			expected = append(expected, "jest")
+		}
+		assert.ElementsMatch(t, expected, maps.Keys(vulnerabilitiesMap))
+	}
+}
+
+func TestAddVulnerabilityToFixVersionsMapWithoutFixedVersion(t *testing.T) {
+	newVulnerability := func(name string) formats.VulnerabilityOrViolationRow {
+		return formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: name, ImpactedDependencyVersion: "1.0.0"},
+			ImpactPaths:               [][]formats.ComponentRow{{{Name: "root"}, {Name: name, Version: "1.0.0"}}},
+		}
+	}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{newVulnerability("request"), newVulnerability("minimist")}
+	alternativePackages, err := alternatives.Parse([]byte("packages:\n  - name: request\n    alternatives:\n      - name: axios\n      - name: got\n"))
+	require.NoError(t, err)
+	cfp := ScanRepositoryCmd{scanDetails: &utils.ScanDetails{Git: &utils.Git{}}, alternativePackages: alternativePackages}
+	vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+	for i := range vulnerabilities {
+		require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&vulnerabilities[i], vulnerabilitiesMap))
+	}
+	// The vulnerable dependencies without a fixed version aren't fixed, but are reported with their alternatives
+	assert.Empty(t, vulnerabilitiesMap)
+	assert.Equal(t, []outputwriter.UnsupportedFixRow{
+		{VulnerabilityOrViolationRow: vulnerabilities[0], Reason: "No fixed version, consider replacing it with: axios, got"},
+		{VulnerabilityOrViolationRow: vulnerabilities[1], Reason: "No fixed version"},
+	}, cfp.unsupportedFixes)
+}
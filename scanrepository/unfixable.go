@@ -0,0 +1,55 @@
+package scanrepository
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/workitems"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Collects a work item for each CVE of a vulnerability without a fixed version, so the risk is tracked although it can't be fixed
+func (cfp *ScanRepositoryCmd) addUnfixableWorkItems(vulnerability *formats.VulnerabilityOrViolationRow) {
+	workItem := workitems.WorkItem{
+		IssueId:          vulnerability.IssueId,
+		Component:        vulnerability.ImpactedDependencyName,
+		ComponentVersion: vulnerability.ImpactedDependencyVersion,
+		Severity:         vulnerability.Severity,
+		Summary:          vulnerability.Summary,
+		Repository:       fmt.Sprintf("%s/%s", cfp.scanDetails.RepoOwner, cfp.scanDetails.RepoName),
+		Branch:           cfp.scanDetails.BaseBranch(),
+	}
+	if len(vulnerability.Cves) == 0 {
+		cfp.unfixableWorkItems = append(cfp.unfixableWorkItems, workItem)
+		return
+	}
+	for _, cve := range vulnerability.Cves {
+		workItem.IssueId = cve.Id
+		cfp.unfixableWorkItems = append(cfp.unfixableWorkItems, workItem)
+	}
+}
+
+// Files the work items of the vulnerabilities without a fixed version that aren't tracked yet.
+// Failing to file the work items doesn't fail the scan.
+func (cfp *ScanRepositoryCmd) trackUnfixableVulnerabilities(repository *utils.Repository) {
+	if len(cfp.unfixableWorkItems) == 0 {
+		return
+	}
+	if cfp.Preview {
+		for _, workItem := range cfp.unfixableWorkItems {
+			fmt.Fprintf(cfp.getPreviewOutput(), "===== Preview: Frogbot would file a work item, unless it's already open: %s\n", workItem.Title())
+		}
+		return
+	}
+	tracker, err := workitems.NewTracker(repository.GitProvider, repository.VcsInfo, repository.RepoOwner, repository.RepoName, repository.AzureWorkItemType)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	created, err := workitems.FileWorkItems(tracker, cfp.unfixableWorkItems)
+	if err != nil {
+		log.Warn("Couldn't file the work items of the vulnerabilities without a fixed version:", err.Error())
+	}
+	log.Info(fmt.Sprintf("Filed %d new work items for vulnerabilities without a fixed version", created))
+}
@@ -0,0 +1,54 @@
+package scanrepository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/workitems"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddUnfixableWorkItems(t *testing.T) {
+	scanDetails := utils.NewScanDetails(nil, nil, &utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", TrackUnfixableVulnerabilities: true}).SetBaseBranch("main")
+	cfp := ScanRepositoryCmd{scanDetails: scanDetails}
+	vulnerabilitiesMap := map[string]*utils.VulnerabilityDetails{}
+	unfixable := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+			ImpactedDependencyName:    "minimist",
+			ImpactedDependencyVersion: "1.2.5",
+		},
+		Cves: []formats.CveRow{{Id: "CVE-2021-44906"}, {Id: "CVE-2020-7598"}},
+	}
+	withoutCve := unfixable
+	withoutCve.Cves = nil
+	withoutCve.IssueId = "XRAY-123"
+	require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&unfixable, vulnerabilitiesMap))
+	require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&withoutCve, vulnerabilitiesMap))
+	assert.Empty(t, vulnerabilitiesMap)
+
+	expectedWorkItem := workitems.WorkItem{Component: "minimist", ComponentVersion: "1.2.5", Severity: "High", Repository: "jfrog/frogbot", Branch: "main"}
+	var expectedWorkItems []workitems.WorkItem
+	for _, issueId := range []string{"CVE-2021-44906", "CVE-2020-7598", "XRAY-123"} {
+		expectedWorkItem.IssueId = issueId
+		expectedWorkItems = append(expectedWorkItems, expectedWorkItem)
+	}
+	assert.Equal(t, expectedWorkItems, cfp.unfixableWorkItems)
+
+	// The work items are printed in preview mode
+	output := &strings.Builder{}
+	cfp.Preview, cfp.previewOutput = true, output
+	cfp.trackUnfixableVulnerabilities(&utils.Repository{})
+	assert.Equal(t, "===== Preview: Frogbot would file a work item, unless it's already open: [🐸 Frogbot] CVE-2021-44906 in minimist\n"+
+		"===== Preview: Frogbot would file a work item, unless it's already open: [🐸 Frogbot] CVE-2020-7598 in minimist\n"+
+		"===== Preview: Frogbot would file a work item, unless it's already open: [🐸 Frogbot] XRAY-123 in minimist\n", output.String())
+
+	// No work items are collected when the tracking is disabled
+	cfp.unfixableWorkItems = nil
+	scanDetails.TrackUnfixableVulnerabilities = false
+	require.NoError(t, cfp.addVulnerabilityToFixVersionsMap(&unfixable, vulnerabilitiesMap))
+	assert.Empty(t, cfp.unfixableWorkItems)
+}
@@ -0,0 +1,150 @@
+package scanrepository
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The lines of the lockfiles that hold the versions of the dependencies, by the names of the lockfiles
+var lockfileVersionLineRegexes = map[string]*regexp.Regexp{
+	"package-lock.json":   regexp.MustCompile(`^\s*"version":\s*"`),
+	"npm-shrinkwrap.json": regexp.MustCompile(`^\s*"version":\s*"`),
+	"yarn.lock":           regexp.MustCompile(`^\s+version:?\s+"?\d`),
+	"pnpm-lock.yaml":      regexp.MustCompile(`^\s+'?/?@?[^@\s']+@\d[^\s:']*'?:\s*$`),
+	"go.sum":              regexp.MustCompile(`^\S+ v[^/\s]+ h1:`),
+	"poetry.lock":         regexp.MustCompile(`^version\s*=\s*"`),
+	"Pipfile.lock":        regexp.MustCompile(`^\s*"version":\s*"`),
+	"Cargo.lock":          regexp.MustCompile(`^version\s*=\s*"`),
+	"composer.lock":       regexp.MustCompile(`^\s*"version":\s*"`),
+	"Gemfile.lock":        regexp.MustCompile(`^ {4}\S+ \(\d[^)]*\)$`),
+	"packages.lock.json":  regexp.MustCompile(`^\s*"resolved":\s*"`),
+	"gradle.lockfile":     regexp.MustCompile(`^[^#\s]+:[^:\s]+:\d\S*=`),
+}
+
+// The names of the test files and the test directories of the common languages
+var (
+	testFileRegex    = regexp.MustCompile(`(_test\.go|\.(test|spec)\.[cm]?[jt]sx?|^test_.+\.py|_test\.py|Tests?\.(java|kt|cs|scala)|_spec\.rb|_test\.rb)$`)
+	testDirNames     = []string{"test", "tests", "__tests__", "spec", "specs"}
+	skippedDirsNames = []string{".git", "node_modules", "vendor", "venv", ".venv", "target", "build", "dist"}
+)
+
+// Updates the vulnerable dependency, and estimates the risk of the upgrade when the upgrade risk analysis is enabled.
+// The lockfiles of the project are read before the update, to count the transitive dependencies that the update changes.
+func (cfp *ScanRepositoryCmd) updatePackageAndAnalyzeRisk(vulnDetails *utils.VulnerabilityDetails) (err error) {
+	if !cfp.scanDetails.AnalyzeUpgradeRisk {
+		return cfp.updatePackageToFixedVersion(vulnDetails)
+	}
+	lockfilesBefore := readLockfiles()
+	if err = cfp.updatePackageToFixedVersion(vulnDetails); err != nil {
+		return
+	}
+	cfp.analyzeUpgradeRisk(vulnDetails, lockfilesBefore)
+	return
+}
+
+func (cfp *ScanRepositoryCmd) analyzeUpgradeRisk(vulnDetails *utils.VulnerabilityDetails, lockfilesBefore map[string]string) {
+	transitiveChanges := countLockfileChanges(lockfilesBefore, readLockfiles())
+	// The fixed dependency itself is one of the changes
+	if transitiveChanges > 0 {
+		transitiveChanges--
+	}
+	projectDir, err := os.Getwd()
+	if err != nil {
+		log.Warn("Failed to get the working directory of the project, its tests aren't detected:", err.Error())
+	}
+	hasTests, exists := cfp.projectsWithTests[projectDir]
+	if !exists && err == nil {
+		hasTests = hasTestFiles(projectDir)
+		if cfp.projectsWithTests == nil {
+			cfp.projectsWithTests = map[string]bool{}
+		}
+		cfp.projectsWithTests[projectDir] = hasTests
+	}
+	vulnDetails.UpgradeRisk = utils.NewUpgradeRisk(vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion, transitiveChanges, hasTests)
+	log.Debug(fmt.Sprintf("The upgrade of '%s' from version '%s' to version '%s' is a %s upgrade that changes %d transitive dependencies. Risk: %s",
+		vulnDetails.ImpactedDependencyName, vulnDetails.ImpactedDependencyVersion, vulnDetails.SuggestedFixedVersion, vulnDetails.UpgradeRisk.VersionChange, transitiveChanges, vulnDetails.UpgradeRisk.Level))
+}
+
+// Returns the contents of the lockfiles of the project in the working directory, by their names
+func readLockfiles() map[string]string {
+	lockfiles := map[string]string{}
+	for name := range lockfileVersionLineRegexes {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				log.Debug(fmt.Sprintf("Failed to read the lockfile %s: %s", name, err.Error()))
+			}
+			continue
+		}
+		lockfiles[name] = string(content)
+	}
+	return lockfiles
+}
+
+// Counts the dependency versions that were added to or removed from the lockfiles.
+// An upgraded dependency both adds a version and removes one, so it counts once.
+func countLockfileChanges(before, after map[string]string) (changes int) {
+	for name, content := range after {
+		versionLineRegex := lockfileVersionLineRegexes[name]
+		versionLines := map[string]int{}
+		for _, line := range strings.Split(before[name], "\n") {
+			if versionLineRegex.MatchString(line) {
+				versionLines[line]++
+			}
+		}
+		added := 0
+		for _, line := range strings.Split(content, "\n") {
+			if !versionLineRegex.MatchString(line) {
+				continue
+			}
+			if versionLines[line] > 0 {
+				versionLines[line]--
+			} else {
+				added++
+			}
+		}
+		removed := 0
+		for _, count := range versionLines {
+			removed += count
+		}
+		changes += max(added, removed)
+	}
+	return
+}
+
+// Returns true if the project has test files or test directories
+func hasTestFiles(projectDir string) (hasTests bool) {
+	err := filepath.WalkDir(projectDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path == projectDir {
+				return nil
+			}
+			if slices.Contains(skippedDirsNames, entry.Name()) {
+				return filepath.SkipDir
+			}
+			hasTests = slices.Contains(testDirNames, entry.Name())
+		} else {
+			hasTests = testFileRegex.MatchString(entry.Name())
+		}
+		if hasTests {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to look for the tests of %s: %s", projectDir, err.Error()))
+	}
+	return
+}
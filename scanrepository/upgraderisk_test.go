@@ -0,0 +1,53 @@
+package scanrepository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountLockfileChanges(t *testing.T) {
+	before := map[string]string{
+		"package-lock.json": "{\"packages\": {\n  \"node_modules/minimist\": {\n    \"version\": \"1.2.5\"\n  },\n  \"node_modules/mkdirp\": {\n    \"version\": \"0.5.5\"\n  }\n}}",
+		"go.sum":            "github.com/a/b v1.0.0 h1:abc=\ngithub.com/a/b v1.0.0/go.mod h1:def=\n",
+	}
+	after := map[string]string{
+		// An upgraded dependency and an added dependency
+		"package-lock.json": "{\"packages\": {\n  \"node_modules/minimist\": {\n    \"version\": \"1.2.6\"\n  },\n  \"node_modules/mkdirp\": {\n    \"version\": \"0.5.5\"\n  },\n  \"node_modules/ms\": {\n    \"version\": \"2.1.3\"\n  }\n}}",
+		"go.sum":            "github.com/a/b v1.1.0 h1:abc=\ngithub.com/a/b v1.1.0/go.mod h1:def=\n",
+		// A new lockfile
+		"yarn.lock": "minimist@^1.2.6:\n  version \"1.2.6\"\n",
+	}
+	assert.Equal(t, 4, countLockfileChanges(before, after))
+	assert.Zero(t, countLockfileChanges(before, before))
+}
+
+func TestHasTestFiles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    []string
+		expected bool
+	}{
+		{name: "Go tests", files: []string{"main.go", "utils/utils_test.go"}, expected: true},
+		{name: "JavaScript tests", files: []string{"src/index.js", "src/index.spec.ts"}, expected: true},
+		{name: "Python tests", files: []string{"app/main.py", "app/test_main.py"}, expected: true},
+		{name: "Test directory", files: []string{"src/main/java/App.java", "src/test/resources/data.json"}, expected: true},
+		// The tests of the dependencies don't count
+		{name: "Tests of dependencies", files: []string{"index.js", "node_modules/minimist/test/parse.js"}},
+		{name: "No tests", files: []string{"index.js", "package.json"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			for _, file := range tc.files {
+				filePath := filepath.Join(projectDir, file)
+				require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+				require.NoError(t, os.WriteFile(filePath, []byte{}, 0644))
+			}
+			assert.Equal(t, tc.expected, hasTestFiles(projectDir))
+		})
+	}
+}
@@ -48,7 +48,8 @@ func TestBadFrogbotSchemas(t *testing.T) {
 		{"additional-prop", "Additional property additionalProp is not allowed"},
 		{"no-array", "Expected: array, given: object"},
 		{"no-git", "git is required"},
-		{"no-repo", "repoName is required"},
+		{"no-repo", `Must validate "else" as "if" was not valid`},
+		{"repo-and-repositories", `Must validate "then" as "if" was valid`},
 		{"empty-repo", "Expected: string, given: null"},
 	}
 	for _, testCase := range testCases {
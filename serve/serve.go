@@ -0,0 +1,275 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/froggit-go/vcsutils/webhookparser"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	AddressFlag    = "address"
+	DefaultAddress = ":8080"
+	WorkersFlag    = "workers"
+	DefaultWorkers = 1
+
+	webhookPath = "/webhook"
+	healthPath  = "/health"
+	// The number of jobs that wait for the workers. Webhooks that arrive when the queue is full are rejected, so the VCS provider can redeliver them.
+	queueSize       = 100
+	shutdownTimeout = 30 * time.Second
+)
+
+// Job is a scan that a webhook triggers
+type Job struct {
+	// The Frogbot command that runs, scan-pull-request or scan-repository
+	Command    string
+	Owner      string
+	Repository string
+	// The pull request that scan-pull-request scans
+	PullRequestId int
+	// The branch that scan-repository scans
+	Branch string
+}
+
+// Identifies the scans of the same pull request or branch, so the webhooks of a job that is still queued are merged into it
+func (j Job) key() string {
+	return strings.Join([]string{j.Command, j.Owner, j.Repository, strconv.Itoa(j.PullRequestId), j.Branch}, "|")
+}
+
+func (j Job) String() string {
+	if j.Command == utils.ScanPullRequest {
+		return fmt.Sprintf("%s of %s/%s pull request #%d", j.Command, j.Owner, j.Repository, j.PullRequestId)
+	}
+	return fmt.Sprintf("%s of %s/%s branch '%s'", j.Command, j.Owner, j.Repository, j.Branch)
+}
+
+// The environment variables that select the repository, and the pull request or the branch, of the job
+func (j Job) env() map[string]string {
+	env := map[string]string{utils.GitRepoOwnerEnv: j.Owner, utils.GitRepoEnv: j.Repository}
+	if j.Command == utils.ScanPullRequest {
+		env[utils.GitPullRequestIDEnv] = strconv.Itoa(j.PullRequestId)
+	} else {
+		env[utils.GitBaseBranchEnv] = j.Branch
+	}
+	return env
+}
+
+// JobRunner runs the Frogbot command of a job with the configuration of the environment variables
+type JobRunner func(job Job, env []string) error
+
+// ServeCmd listens for the webhooks of the Git provider, and runs scan-pull-request when pull requests are opened or updated,
+// and scan-repository when branches are pushed. A single Frogbot service replaces the CI pipelines of the repositories.
+// The webhooks are validated with the secret of JF_WEBHOOK_SECRET, and the configuration of the commands is read from the other
+// environment variables, as in a CI run, with the repository, the pull request and the branch of each webhook.
+type ServeCmd struct {
+	// The address the server listens on, such as ':8080'
+	Address string
+	// The number of jobs that run at the same time
+	Workers int
+	// Runs the commands of the jobs. Each job runs in a new Frogbot process by default.
+	Runner JobRunner
+
+	provider    vcsutils.VcsProvider
+	apiEndpoint string
+	secret      []byte
+	// The environment variables of the server, which the jobs run with
+	env   []string
+	queue chan Job
+	// The keys of the queued jobs
+	queued     map[string]bool
+	queueMutex sync.Mutex
+}
+
+func (sc *ServeCmd) Run() (err error) {
+	if err = sc.setDefaults(); err != nil {
+		return
+	}
+	server := &http.Server{Addr: sc.Address, Handler: sc.handler(), ReadHeaderTimeout: 10 * time.Second}
+	workersDone := sc.startWorkers()
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info(fmt.Sprintf("Listening for %s webhooks on %s%s with %d workers", sc.provider, sc.Address, webhookPath, sc.Workers))
+		serverErr <- server.ListenAndServe()
+	}()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	select {
+	case err = <-serverErr:
+	case <-signals:
+		log.Info("Shutting down, the running jobs are completed first...")
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err = server.Shutdown(ctx)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	close(sc.queue)
+	<-workersDone
+	return
+}
+
+func (sc *ServeCmd) setDefaults() (err error) {
+	if sc.provider, err = utils.ExtractVcsProviderFromEnv(); err != nil {
+		return
+	}
+	if sc.provider == vcsutils.AzureRepos {
+		return fmt.Errorf("the webhooks of %s aren't supported, run Frogbot in Azure Pipelines instead", vcsutils.AzureRepos)
+	}
+	sc.secret = []byte(os.Getenv(utils.WebhookSecretEnv))
+	if len(sc.secret) == 0 {
+		return fmt.Errorf("%s is required, so only the webhooks of the Git provider are accepted", utils.WebhookSecretEnv)
+	}
+	sc.apiEndpoint = os.Getenv(utils.GitApiEndpointEnv)
+	if sc.Address == "" {
+		sc.Address = DefaultAddress
+	}
+	if sc.Workers <= 0 {
+		sc.Workers = DefaultWorkers
+	}
+	if sc.Runner == nil {
+		sc.Runner = runJobProcess
+	}
+	sc.env = os.Environ()
+	sc.queue = make(chan Job, queueSize)
+	sc.queued = map[string]bool{}
+	return
+}
+
+func (sc *ServeCmd) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(webhookPath, sc.handleWebhook)
+	mux.HandleFunc(healthPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+func (sc *ServeCmd) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	origin := webhookparser.WebhookOrigin{VcsProvider: sc.provider, OriginURL: sc.apiEndpoint, Token: sc.secret}
+	webhook, err := webhookparser.ParseIncomingWebhook(r.Context(), log.GetLogger(), origin, r)
+	if err != nil {
+		log.Warn("Rejected a webhook:", err.Error())
+		http.Error(w, "invalid webhook", http.StatusBadRequest)
+		return
+	}
+	job, isScanned := getJob(webhook)
+	if !isScanned {
+		log.Debug("Ignored a webhook of an event that isn't scanned")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err = sc.enqueue(job); err != nil {
+		log.Warn(fmt.Sprintf("Couldn't queue the %s: %s", job, err.Error()))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Returns the job that the webhook triggers. Returns false if the webhook doesn't trigger a scan.
+// The webhook is nil if the parser doesn't support its event, such as the labeling of a pull request.
+func getJob(webhook *webhookparser.WebhookInfo) (job Job, isScanned bool) {
+	if webhook == nil {
+		return
+	}
+	switch webhook.Event {
+	case vcsutils.PrOpened, vcsutils.PrEdited:
+		job = Job{Command: utils.ScanPullRequest, Owner: webhook.TargetRepositoryDetails.Owner, Repository: webhook.TargetRepositoryDetails.Name, PullRequestId: webhook.PullRequestId}
+		if webhook.PullRequest != nil {
+			job.Owner, job.Repository, job.PullRequestId = webhook.PullRequest.TargetRepository.Owner, webhook.PullRequest.TargetRepository.Name, webhook.PullRequest.ID
+		}
+		return job, true
+	case vcsutils.Push:
+		// The fix branches that Frogbot pushes aren't scanned, as scanning them opens fix pull requests of fix pull requests
+		if webhook.BranchStatus == webhookparser.WebhookInfoBranchStatusDeleted || strings.HasPrefix(webhook.TargetBranch, utils.FixBranchPrefix) {
+			return
+		}
+		return Job{Command: utils.ScanRepository, Owner: webhook.TargetRepositoryDetails.Owner, Repository: webhook.TargetRepositoryDetails.Name, Branch: webhook.TargetBranch}, true
+	}
+	return
+}
+
+// Queues the job, unless the same job is already queued. A queued job scans the latest commit when it runs, so it covers the new webhook as well.
+func (sc *ServeCmd) enqueue(job Job) error {
+	sc.queueMutex.Lock()
+	defer sc.queueMutex.Unlock()
+	if sc.queued[job.key()] {
+		log.Debug(fmt.Sprintf("The %s is already queued", job))
+		return nil
+	}
+	select {
+	case sc.queue <- job:
+		sc.queued[job.key()] = true
+		log.Info(fmt.Sprintf("Queued the %s", job))
+		return nil
+	default:
+		return errors.New("the queue of the scan jobs is full")
+	}
+}
+
+// Starts the workers that run the queued jobs. The returned channel is closed once the queue is closed and all its jobs ran.
+func (sc *ServeCmd) startWorkers() <-chan struct{} {
+	var workers sync.WaitGroup
+	for i := 0; i < sc.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range sc.queue {
+				sc.queueMutex.Lock()
+				// Webhooks that arrive while the job runs queue it again, since the job may have missed their commits
+				delete(sc.queued, job.key())
+				sc.queueMutex.Unlock()
+				sc.runJob(job)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// Runs the job with the environment variables of the server and of the job. Failing jobs are logged, and don't stop the server.
+func (sc *ServeCmd) runJob(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error(fmt.Sprintf("The %s failed with an unexpected error: %v", job, r))
+		}
+	}()
+	log.Info(fmt.Sprintf("Running the %s", job))
+	if err := sc.Runner(job, utils.EnvWithOverrides(sc.env, job.env())); err != nil {
+		log.Error(fmt.Sprintf("The %s failed: %s", job, err.Error()))
+		return
+	}
+	log.Info(fmt.Sprintf("The %s finished successfully", job))
+}
+
+// Runs the command of the job in a new Frogbot process, in the same way as the command runs in a CI pipeline.
+// The commands change the working directory and the environment variables of their process, so each job runs in its own process.
+// The output of the process is logged once it's done, so the outputs of the jobs that run at the same time aren't mixed.
+func runJobProcess(job Job, env []string) error {
+	output, err := utils.RunCommandProcess(env, job.Command)
+	log.Info(fmt.Sprintf("The output of the %s:\n%s", job, output))
+	return err
+}
@@ -0,0 +1,149 @@
+package serve
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret          = "webhook-secret"
+	pullRequestPayload  = `{"action":"%s","number":3,"pull_request":{"number":3,"base":{"ref":"main","repo":{"name":"frogbot","owner":{"login":"jfrog"}}},"head":{"ref":"feature","repo":{"name":"frogbot","owner":{"login":"jfrog"}}}}}`
+	pushPayloadTemplate = `{"ref":"refs/heads/%s","before":"1111111111111111111111111111111111111111","after":"2222222222222222222222222222222222222222","repository":{"name":"frogbot","owner":{"login":"jfrog"}}}`
+)
+
+func newTestServeCmd(t *testing.T, runner JobRunner) *ServeCmd {
+	t.Setenv(utils.GitProvider, string(utils.GitHub))
+	t.Setenv(utils.WebhookSecretEnv, testSecret)
+	t.Setenv(utils.JFrogUrlEnv, "https://jfrog.example.com")
+	sc := &ServeCmd{Runner: runner}
+	require.NoError(t, sc.setDefaults())
+	return sc
+}
+
+func sendGitHubWebhook(t *testing.T, handler http.Handler, event, payload, secret string) int {
+	request := httptest.NewRequest(http.MethodPost, webhookPath, bytes.NewBufferString(payload))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-GitHub-Event", event)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	request.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestHandleWebhook(t *testing.T) {
+	sc := newTestServeCmd(t, nil)
+	handler := sc.handler()
+
+	// Opened and updated pull requests are scanned, and the webhooks of a queued job are merged into it
+	assert.Equal(t, http.StatusAccepted, sendGitHubWebhook(t, handler, "pull_request", fmt.Sprintf(pullRequestPayload, "opened"), testSecret))
+	assert.Equal(t, http.StatusAccepted, sendGitHubWebhook(t, handler, "pull_request", fmt.Sprintf(pullRequestPayload, "synchronize"), testSecret))
+	// Pushed branches are scanned, except for the fix branches of Frogbot
+	assert.Equal(t, http.StatusAccepted, sendGitHubWebhook(t, handler, "push", fmt.Sprintf(pushPayloadTemplate, "main"), testSecret))
+	assert.Equal(t, http.StatusNoContent, sendGitHubWebhook(t, handler, "push", fmt.Sprintf(pushPayloadTemplate, "frogbot-lodash-1234"), testSecret))
+	// Other events aren't scanned
+	assert.Equal(t, http.StatusNoContent, sendGitHubWebhook(t, handler, "pull_request", fmt.Sprintf(pullRequestPayload, "labeled"), testSecret))
+	assert.Equal(t, http.StatusNoContent, sendGitHubWebhook(t, handler, "pull_request", fmt.Sprintf(pullRequestPayload, "closed"), testSecret))
+	// Webhooks that aren't signed with the secret are rejected
+	assert.Equal(t, http.StatusBadRequest, sendGitHubWebhook(t, handler, "pull_request", fmt.Sprintf(pullRequestPayload, "opened"), "wrong-secret"))
+
+	require.Len(t, sc.queue, 2)
+	assert.Equal(t, Job{Command: utils.ScanPullRequest, Owner: "jfrog", Repository: "frogbot", PullRequestId: 3}, <-sc.queue)
+	assert.Equal(t, Job{Command: utils.ScanRepository, Owner: "jfrog", Repository: "frogbot", Branch: "main"}, <-sc.queue)
+
+	// Only POST requests are accepted
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, webhookPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, healthPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestEnqueueFullQueue(t *testing.T) {
+	sc := newTestServeCmd(t, nil)
+	for i := 0; i < queueSize; i++ {
+		require.NoError(t, sc.enqueue(Job{Command: utils.ScanPullRequest, Owner: "jfrog", Repository: "frogbot", PullRequestId: i}))
+	}
+	assert.ErrorContains(t, sc.enqueue(Job{Command: utils.ScanPullRequest, Owner: "jfrog", Repository: "frogbot", PullRequestId: queueSize}), "the queue of the scan jobs is full")
+}
+
+func getEnv(env []string, name string) string {
+	value := ""
+	for _, variable := range env {
+		if variableName, variableValue, _ := strings.Cut(variable, "="); variableName == name {
+			value = variableValue
+		}
+	}
+	return value
+}
+
+func TestRunJobs(t *testing.T) {
+	var ranJobs []Job
+	var ranJobsMutex sync.Mutex
+	sc := newTestServeCmd(t, func(job Job, env []string) error {
+		// Each job runs with the environment variables of the server and of the job
+		assert.Equal(t, "https://jfrog.example.com", getEnv(env, utils.JFrogUrlEnv))
+		assert.Equal(t, "jfrog", getEnv(env, utils.GitRepoOwnerEnv))
+		if job.Command == utils.ScanPullRequest {
+			assert.Equal(t, "3", getEnv(env, utils.GitPullRequestIDEnv))
+			assert.Empty(t, getEnv(env, utils.GitBaseBranchEnv))
+		} else {
+			assert.Equal(t, "main", getEnv(env, utils.GitBaseBranchEnv))
+			assert.Empty(t, getEnv(env, utils.GitPullRequestIDEnv))
+		}
+		ranJobsMutex.Lock()
+		ranJobs = append(ranJobs, job)
+		ranJobsMutex.Unlock()
+		if job.Repository == "panicking" {
+			panic("unexpected failure")
+		}
+		return nil
+	})
+	sc.Workers = 2
+	pullRequestJob := Job{Command: utils.ScanPullRequest, Owner: "jfrog", Repository: "frogbot", PullRequestId: 3}
+	panickingJob := Job{Command: utils.ScanRepository, Owner: "jfrog", Repository: "panicking", Branch: "main"}
+	branchJob := Job{Command: utils.ScanRepository, Owner: "jfrog", Repository: "frogbot", Branch: "main"}
+	require.NoError(t, sc.enqueue(pullRequestJob))
+	require.NoError(t, sc.enqueue(panickingJob))
+	require.NoError(t, sc.enqueue(branchJob))
+	workersDone := sc.startWorkers()
+	close(sc.queue)
+	<-workersDone
+	// A panicking job doesn't stop the server from running the other jobs
+	assert.ElementsMatch(t, []Job{pullRequestJob, panickingJob, branchJob}, ranJobs)
+	assert.Empty(t, sc.queued)
+}
+
+func TestSetDefaults(t *testing.T) {
+	t.Setenv(utils.GitProvider, string(utils.GitHub))
+	t.Setenv(utils.WebhookSecretEnv, "")
+	assert.ErrorContains(t, (&ServeCmd{}).setDefaults(), utils.WebhookSecretEnv+" is required")
+
+	t.Setenv(utils.GitProvider, string(utils.AzureRepos))
+	t.Setenv(utils.WebhookSecretEnv, testSecret)
+	assert.ErrorContains(t, (&ServeCmd{}).setDefaults(), "aren't supported")
+
+	t.Setenv(utils.GitProvider, string(utils.GitLab))
+	sc := &ServeCmd{}
+	require.NoError(t, sc.setDefaults())
+	assert.Equal(t, vcsutils.GitLab, sc.provider)
+	assert.Equal(t, DefaultAddress, sc.Address)
+	assert.Equal(t, DefaultWorkers, sc.Workers)
+	assert.NotNil(t, sc.Runner)
+	assert.Equal(t, testSecret, getEnv(sc.env, utils.WebhookSecretEnv))
+}
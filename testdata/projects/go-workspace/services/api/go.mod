@@ -0,0 +1,7 @@
+module github.com/frogbot/go-workspace/services/api
+
+go 1.22
+
+require github.com/google/uuid v1.3.0
+
+require golang.org/x/net v0.17.0 // indirect
@@ -0,0 +1,8 @@
+module github.com/frogbot/go-workspace/services/web
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.7.0
+	golang.org/x/net v0.17.0
+)
@@ -0,0 +1,3 @@
+module github.com/frogbot/go-workspace/tools
+
+go 1.22
@@ -0,0 +1,294 @@
+package upgrade
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	VersionFlag = "version"
+	CheckFlag   = "check"
+
+	defaultPlatformUrl  = "https://releases.jfrog.io"
+	defaultGitHubApiUrl = "https://api.github.com"
+	frogbotGitHubRepo   = "jfrog/frogbot"
+	// The path of the Frogbot releases in the releases repository
+	releasesPath = "frogbot/v2"
+	// The extension of the detached signatures of the executables, published next to them
+	signatureExtension = ".sig"
+)
+
+var releaseVersionRegex = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// The base64 encoded Ed25519 public key of the key that signs the released executables.
+// It's pinned to the executable at build time by the release script, and it's empty in the executables built from source.
+var releasePublicKey = ""
+
+// UpgradeCmd replaces the running Frogbot executable with a newer version.
+// The versions are looked up in the releases repository configured by JF_RELEASES_REPO, which proxies releases.jfrog.io,
+// or in the GitHub releases of Frogbot otherwise. The executable is downloaded for the platform it runs on,
+// and its detached signature is verified with the pinned release key before it replaces the running executable.
+type UpgradeCmd struct {
+	// The version to upgrade to, the latest version by default
+	Version string
+	// Only checks whether a newer version is available, without upgrading
+	CheckOnly bool
+	// The version of the running executable
+	currentVersion string
+	// The path of the running executable
+	executablePath string
+	// The platform of the downloaded executable, such as 'linux-arm64'
+	platform string
+	// The URL of the JFrog platform the executables are downloaded from
+	platformUrl string
+	// The remote repository that proxies releases.jfrog.io, if set
+	releasesRepo string
+	gitHubApiUrl string
+	// The key that verifies the signatures of the downloaded executables
+	publicKey         ed25519.PublicKey
+	httpClientDetails httputils.HttpClientDetails
+}
+
+func (uc *UpgradeCmd) Run() (err error) {
+	if err = uc.setDefaults(); err != nil {
+		return
+	}
+	targetVersion := strings.TrimPrefix(uc.Version, "v")
+	if targetVersion == "" {
+		if targetVersion, err = uc.getLatestVersion(); err != nil {
+			return
+		}
+		if version.NewVersion(uc.currentVersion).AtLeast(targetVersion) {
+			log.Info(fmt.Sprintf("Frogbot is up to date, the latest version is %s", targetVersion))
+			return
+		}
+	}
+	if targetVersion == uc.currentVersion {
+		log.Info(fmt.Sprintf("Frogbot version %s is already installed", targetVersion))
+		return
+	}
+	if uc.CheckOnly {
+		log.Info(fmt.Sprintf("Frogbot version %s is available, the installed version is %s. Run 'frogbot %s' to upgrade", targetVersion, uc.currentVersion, utils.Upgrade))
+		return
+	}
+	log.Info(fmt.Sprintf("Upgrading Frogbot from version %s to version %s (%s)", uc.currentVersion, targetVersion, uc.platform))
+	content, err := uc.downloadExecutable(targetVersion)
+	if err != nil {
+		return
+	}
+	if err = replaceExecutable(uc.executablePath, content); err != nil {
+		return fmt.Errorf("failed to replace the Frogbot executable %s: %s", uc.executablePath, err.Error())
+	}
+	log.Info(fmt.Sprintf("Frogbot was upgraded to version %s", targetVersion))
+	return
+}
+
+func (uc *UpgradeCmd) setDefaults() (err error) {
+	if uc.currentVersion == "" {
+		uc.currentVersion = strings.TrimPrefix(utils.FrogbotVersion, "v")
+	}
+	if uc.platform == "" {
+		uc.platform = GetPlatform(runtime.GOOS, runtime.GOARCH)
+	}
+	if uc.gitHubApiUrl == "" {
+		uc.gitHubApiUrl = defaultGitHubApiUrl
+	}
+	if uc.executablePath == "" {
+		if uc.executablePath, err = os.Executable(); err != nil {
+			return
+		}
+		// The executable may be a link to the actual executable, which is the one to replace
+		if uc.executablePath, err = filepath.EvalSymlinks(uc.executablePath); err != nil {
+			return
+		}
+	}
+	if uc.platformUrl != "" {
+		return
+	}
+	if uc.releasesRepo = os.Getenv(utils.JFrogReleasesRepoEnv); uc.releasesRepo == "" {
+		uc.platformUrl = defaultPlatformUrl
+		return
+	}
+	if uc.platformUrl = strings.TrimSuffix(os.Getenv(utils.JFrogUrlEnv), "/"); uc.platformUrl == "" {
+		return fmt.Errorf("%s must be set to download Frogbot from the releases repository %s", utils.JFrogUrlEnv, uc.releasesRepo)
+	}
+	uc.httpClientDetails = httputils.HttpClientDetails{User: os.Getenv(utils.JFrogUserEnv), Password: os.Getenv(utils.JFrogPasswordEnv), AccessToken: os.Getenv(utils.JFrogTokenEnv)}
+	return
+}
+
+// Returns the platform of the Frogbot executables that runs on the operating system and the architecture.
+// The Linux executables are static, so they run on the distributions that use musl, such as Alpine, too.
+func GetPlatform(goos, goarch string) string {
+	switch goos {
+	case "windows":
+		return "windows-amd64"
+	case "darwin":
+		// The Apple Silicon machines run the amd64 executable
+		return "mac-386"
+	}
+	return "linux-" + goarch
+}
+
+func parsePublicKey(encodedKey string) (ed25519.PublicKey, error) {
+	if encodedKey == "" {
+		return nil, errors.New("this Frogbot executable has no release signing key, so the downloaded executable can't be verified. Download the new version manually")
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("the release signing key of this Frogbot executable is invalid: %s", encodedKey)
+	}
+	return key, nil
+}
+
+// Returns the URL of the releases, in the releases repository if set, or in releases.jfrog.io otherwise
+func (uc *UpgradeCmd) releasesUrl() string {
+	if uc.releasesRepo == "" {
+		return uc.platformUrl + "/artifactory/" + releasesPath
+	}
+	return uc.platformUrl + "/artifactory/" + uc.releasesRepo + "/artifactory/" + releasesPath
+}
+
+func (uc *UpgradeCmd) getLatestVersion() (latestVersion string, err error) {
+	if uc.releasesRepo == "" {
+		latestVersion, err = uc.getLatestGitHubRelease()
+	} else {
+		latestVersion, err = uc.getLatestRepositoryRelease()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get the latest version of Frogbot: %s", err.Error())
+	}
+	log.Debug("The latest version of Frogbot is", latestVersion)
+	return
+}
+
+func (uc *UpgradeCmd) getLatestGitHubRelease() (string, error) {
+	body, err := uc.get(fmt.Sprintf("%s/repos/%s/releases/latest", uc.gitHubApiUrl, frogbotGitHubRepo))
+	if err != nil {
+		return "", err
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err = json.Unmarshal(body, &release); err != nil {
+		return "", err
+	}
+	if latestVersion := strings.TrimPrefix(release.TagName, "v"); releaseVersionRegex.MatchString(latestVersion) {
+		return latestVersion, nil
+	}
+	return "", fmt.Errorf("the latest release has an unexpected tag '%s'", release.TagName)
+}
+
+// Returns the highest version in the folders of the releases in the releases repository, listed by the Artifactory storage API
+func (uc *UpgradeCmd) getLatestRepositoryRelease() (latestVersion string, err error) {
+	body, err := uc.get(fmt.Sprintf("%s/artifactory/api/storage/%s/artifactory/%s", uc.platformUrl, uc.releasesRepo, releasesPath))
+	if err != nil {
+		return
+	}
+	var folder struct {
+		Children []struct {
+			Uri    string `json:"uri"`
+			Folder bool   `json:"folder"`
+		} `json:"children"`
+	}
+	if err = json.Unmarshal(body, &folder); err != nil {
+		return
+	}
+	for _, child := range folder.Children {
+		releaseVersion := strings.TrimPrefix(child.Uri, "/")
+		if child.Folder && releaseVersionRegex.MatchString(releaseVersion) && (latestVersion == "" || version.NewVersion(releaseVersion).AtLeast(latestVersion)) {
+			latestVersion = releaseVersion
+		}
+	}
+	if latestVersion == "" {
+		err = fmt.Errorf("no releases were found in the releases repository %s", uc.releasesRepo)
+	}
+	return
+}
+
+func (uc *UpgradeCmd) get(url string) ([]byte, error) {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, body, _, err := client.SendGet(url, true, uc.httpClientDetails, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded with status %s", url, resp.Status)
+	}
+	return body, nil
+}
+
+// Downloads the executable of the version, and verifies its detached signature with the pinned release key.
+// The checksums that the server returns aren't trusted, since a compromised or spoofed server would return the checksums of its own executables.
+func (uc *UpgradeCmd) downloadExecutable(targetVersion string) (content []byte, err error) {
+	if uc.publicKey == nil {
+		if uc.publicKey, err = parsePublicKey(releasePublicKey); err != nil {
+			return
+		}
+	}
+	url := fmt.Sprintf("%s/%s/frogbot-%s/%s", uc.releasesUrl(), targetVersion, uc.platform, getExecutableName(uc.platform))
+	log.Debug("Downloading Frogbot from:", url)
+	if content, err = uc.get(url); err != nil {
+		return nil, fmt.Errorf("failed to download Frogbot version %s: %s", targetVersion, err.Error())
+	}
+	signature, err := uc.get(url + signatureExtension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the signature of Frogbot version %s: %s", targetVersion, err.Error())
+	}
+	if !ed25519.Verify(uc.publicKey, content, signature) {
+		return nil, fmt.Errorf("the signature of the executable downloaded from %s doesn't match the release signing key", url)
+	}
+	return
+}
+
+func getExecutableName(platform string) string {
+	if strings.HasPrefix(platform, "windows") {
+		return "frogbot.exe"
+	}
+	return "frogbot"
+}
+
+// Replaces the executable with the new content. The new executable is written next to the executable, and then renamed,
+// so the executable is never left partially written. Windows doesn't allow replacing a running executable, so it's moved aside first.
+func replaceExecutable(executablePath string, content []byte) (err error) {
+	info, err := os.Stat(executablePath)
+	if err != nil {
+		return
+	}
+	newExecutablePath := executablePath + ".new"
+	if err = os.WriteFile(newExecutablePath, content, info.Mode().Perm()|0111); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, os.Remove(newExecutablePath))
+		}
+	}()
+	if runtime.GOOS == "windows" {
+		oldExecutablePath := executablePath + ".old"
+		if err = os.Remove(oldExecutablePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		if err = os.Rename(executablePath, oldExecutablePath); err != nil {
+			return
+		}
+	}
+	return os.Rename(newExecutablePath, executablePath)
+}
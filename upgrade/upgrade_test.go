@@ -0,0 +1,122 @@
+package upgrade
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const newExecutable = "new frogbot executable"
+
+func TestUpgrade(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signature := ed25519.Sign(privateKey, []byte(newExecutable))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/jfrog/frogbot/releases/latest":
+			_, err := w.Write([]byte(`{"tag_name":"v2.21.0"}`))
+			assert.NoError(t, err)
+		case "/artifactory/api/storage/frogbot-remote/artifactory/frogbot/v2":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			_, err := w.Write([]byte(`{"children":[{"uri":"/2.9.0","folder":true},{"uri":"/2.21.0","folder":true},{"uri":"/2.22.0-beta","folder":true},{"uri":"/getFrogbot.sh","folder":false}]}`))
+			assert.NoError(t, err)
+		case "/artifactory/frogbot/v2/2.21.0/frogbot-linux-arm64/frogbot", "/artifactory/frogbot-remote/artifactory/frogbot/v2/2.21.0/frogbot-linux-arm64/frogbot":
+			_, err := w.Write([]byte(newExecutable))
+			assert.NoError(t, err)
+		case "/artifactory/frogbot/v2/2.20.0/frogbot-linux-arm64/frogbot":
+			_, err := w.Write([]byte("tampered frogbot executable"))
+			assert.NoError(t, err)
+		case "/artifactory/frogbot/v2/2.21.0/frogbot-linux-arm64/frogbot.sig", "/artifactory/frogbot-remote/artifactory/frogbot/v2/2.21.0/frogbot-linux-arm64/frogbot.sig",
+			"/artifactory/frogbot/v2/2.20.0/frogbot-linux-arm64/frogbot.sig":
+			_, err := w.Write(signature)
+			assert.NoError(t, err)
+		case "/artifactory/frogbot/v2/2.18.0/frogbot-linux-arm64/frogbot":
+			_, err := w.Write([]byte(newExecutable))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name           string
+		cmd            UpgradeCmd
+		expectedBinary string
+		expectedError  string
+	}{
+		{name: "Latest GitHub release", cmd: UpgradeCmd{currentVersion: "2.20.0"}, expectedBinary: newExecutable},
+		{name: "Latest release in the releases repository", cmd: UpgradeCmd{currentVersion: "2.20.0", releasesRepo: "frogbot-remote"}, expectedBinary: newExecutable},
+		{name: "Up to date", cmd: UpgradeCmd{currentVersion: "2.21.0"}},
+		{name: "Check only", cmd: UpgradeCmd{currentVersion: "2.20.0", CheckOnly: true}},
+		{name: "Specific version", cmd: UpgradeCmd{currentVersion: "2.22.0", Version: "v2.21.0"}, expectedBinary: newExecutable},
+		{name: "Signature mismatch", cmd: UpgradeCmd{currentVersion: "2.21.0", Version: "2.20.0"}, expectedError: "doesn't match the release signing key"},
+		{name: "Missing signature", cmd: UpgradeCmd{currentVersion: "2.20.0", Version: "2.18.0"}, expectedError: "failed to download the signature of Frogbot version 2.18.0"},
+		{name: "Missing version", cmd: UpgradeCmd{currentVersion: "2.20.0", Version: "2.19.0"}, expectedError: "failed to download Frogbot version 2.19.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			executablePath := filepath.Join(t.TempDir(), "frogbot")
+			require.NoError(t, os.WriteFile(executablePath, []byte("old frogbot executable"), 0755))
+			tc.cmd.executablePath = executablePath
+			tc.cmd.platform = "linux-arm64"
+			tc.cmd.publicKey = publicKey
+			tc.cmd.platformUrl = server.URL
+			tc.cmd.gitHubApiUrl = server.URL
+			tc.cmd.httpClientDetails.AccessToken = "token"
+			err := tc.cmd.Run()
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			content, err := os.ReadFile(executablePath)
+			require.NoError(t, err)
+			if tc.expectedBinary == "" {
+				// The executable isn't replaced
+				assert.Equal(t, "old frogbot executable", string(content))
+				return
+			}
+			assert.Equal(t, tc.expectedBinary, string(content))
+			assert.NoFileExists(t, executablePath+".new")
+		})
+	}
+}
+
+func TestGetPlatform(t *testing.T) {
+	testCases := []struct {
+		goos     string
+		goarch   string
+		expected string
+	}{
+		{goos: "linux", goarch: "amd64", expected: "linux-amd64"},
+		{goos: "linux", goarch: "arm64", expected: "linux-arm64"},
+		{goos: "linux", goarch: "s390x", expected: "linux-s390x"},
+		{goos: "darwin", goarch: "arm64", expected: "mac-386"},
+		{goos: "windows", goarch: "amd64", expected: "windows-amd64"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, GetPlatform(tc.goos, tc.goarch))
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	parsedKey, err := parsePublicKey(base64.StdEncoding.EncodeToString(publicKey))
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, parsedKey)
+
+	_, err = parsePublicKey("")
+	assert.ErrorContains(t, err, "has no release signing key")
+	_, err = parsePublicKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorContains(t, err, "the release signing key of this Frogbot executable is invalid")
+}
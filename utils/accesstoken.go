@@ -0,0 +1,331 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	oidcTokenExchangeApi = "access/api/v1/oidc/token"
+	refreshTokenApi      = "access/api/v1/tokens"
+	// Access tokens that expire within this margin are refreshed before they are used
+	accessTokenRefreshMargin = 5 * time.Minute
+
+	// The environment variables GitHub Actions sets for jobs with the 'id-token: write' permission
+	githubActionsIdTokenRequestUrlEnv   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	githubActionsIdTokenRequestTokenEnv = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+)
+
+// AccessTokenManager handles the lifecycle of the JFrog access token Frogbot authenticates with.
+// It exchanges OIDC ID tokens for access tokens, and refreshes the access tokens that expire during long runs,
+// either with a refresh token or by exchanging a new OIDC ID token.
+type AccessTokenManager struct {
+	mutex        sync.Mutex
+	platformUrl  string
+	accessToken  string
+	refreshToken string
+	expiry       time.Time
+	// The access tokens the manager issued, so servers that authenticate with other tokens aren't updated
+	issuedTokens map[string]bool
+	// The OIDC integration of the JFrog platform the ID tokens are exchanged with, empty if OIDC isn't used
+	oidcProviderName string
+	oidcAudience     string
+	// A static OIDC ID token. If empty, the ID tokens are requested from GitHub Actions.
+	oidcIdToken         string
+	idTokenRequestUrl   string
+	idTokenRequestToken string
+}
+
+type accessTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// The access token manager of the JFrog platform credentials, nil if the access token can't be refreshed
+var accessTokenManager *AccessTokenManager
+
+// Creates the access token manager of the JFrog platform credentials from the environment variables, if they allow refreshing the access token.
+// If an OIDC provider is configured and the server has no credentials, the OIDC ID token is exchanged for an access token.
+// Must be called before the environment variables are sanitized.
+func setupAccessTokenManager(server *config.ServerDetails) error {
+	accessTokenManager = nil
+	oidcProviderName, refreshToken := getTrimmedEnv(JFrogOidcProviderNameEnv), getTrimmedEnv(JFrogRefreshTokenEnv)
+	if oidcProviderName == "" && refreshToken == "" {
+		return nil
+	}
+	if server.User != "" && server.Password != "" {
+		log.Debug("The JFrog platform credentials are a username and a password, so the OIDC provider and the refresh token are ignored")
+		return nil
+	}
+	if server.Url == "" {
+		return fmt.Errorf("the %s environment variable is required to exchange and refresh JFrog access tokens", JFrogUrlEnv)
+	}
+	manager := &AccessTokenManager{
+		platformUrl:         server.Url,
+		refreshToken:        refreshToken,
+		issuedTokens:        map[string]bool{},
+		oidcProviderName:    oidcProviderName,
+		oidcAudience:        getTrimmedEnv(JFrogOidcAudienceEnv),
+		oidcIdToken:         getTrimmedEnv(JFrogOidcTokenEnv),
+		idTokenRequestUrl:   getTrimmedEnv(githubActionsIdTokenRequestUrlEnv),
+		idTokenRequestToken: getTrimmedEnv(githubActionsIdTokenRequestTokenEnv),
+	}
+	if server.AccessToken != "" {
+		manager.setAccessToken(server.AccessToken, "", 0)
+	} else {
+		if oidcProviderName == "" {
+			return fmt.Errorf("a refresh token was provided without an access token. Set %s or %s", JFrogTokenEnv, JFrogOidcProviderNameEnv)
+		}
+		log.Info(fmt.Sprintf("Exchanging an OIDC ID token for a JFrog access token using the '%s' OIDC provider...", oidcProviderName))
+		if err := manager.exchangeOidcToken(); err != nil {
+			return err
+		}
+		server.AccessToken = manager.accessToken
+	}
+	accessTokenManager = manager
+	return nil
+}
+
+// Runs an operation against the JFrog platform with an access token that isn't about to expire.
+// If the operation fails since the access token expired during the run, the token is refreshed and the operation is retried once.
+func RunWithAccessTokenRefresh(server *config.ServerDetails, operation func() error) error {
+	manager := accessTokenManager
+	if manager == nil || server == nil || !manager.isIssued(server.AccessToken) {
+		return operation()
+	}
+	if manager.isExpiring() {
+		if err := manager.refreshServer(server); err != nil {
+			log.Warn("Couldn't refresh the JFrog access token before it expires:", err.Error())
+		}
+	}
+	err := operation()
+	if !isUnauthorizedError(err) {
+		return err
+	}
+	log.Info("The JFrog platform rejected the access token, refreshing it and retrying...")
+	if refreshErr := manager.refreshServer(server); refreshErr != nil {
+		return errors.Join(err, fmt.Errorf("couldn't refresh the JFrog access token: %w", refreshErr))
+	}
+	return operation()
+}
+
+// Returns true if the JFrog platform responded to one of the requests of the failed operation with the unauthorized status code
+func isUnauthorizedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, match := range responseStatusCodeRegex.FindAllStringSubmatch(err.Error(), -1) {
+		if statusCode, _ := strconv.Atoi(match[1]); statusCode == http.StatusUnauthorized {
+			return true
+		}
+	}
+	return false
+}
+
+func (atm *AccessTokenManager) isIssued(accessToken string) bool {
+	atm.mutex.Lock()
+	defer atm.mutex.Unlock()
+	return accessToken != "" && atm.issuedTokens[accessToken]
+}
+
+func (atm *AccessTokenManager) isExpiring() bool {
+	atm.mutex.Lock()
+	defer atm.mutex.Unlock()
+	return !atm.expiry.IsZero() && time.Now().Add(accessTokenRefreshMargin).After(atm.expiry)
+}
+
+// Refreshes the access token and sets it in the server, unless another operation already refreshed it.
+// The servers of the JFrog home config that use the previous access token are updated as well, since the JFrog CLI commands read their server from it.
+func (atm *AccessTokenManager) refreshServer(server *config.ServerDetails) (err error) {
+	atm.mutex.Lock()
+	defer atm.mutex.Unlock()
+	previousAccessToken := atm.accessToken
+	if server.AccessToken == atm.accessToken {
+		if err = atm.refresh(); err != nil {
+			return
+		}
+	}
+	server.AccessToken = atm.accessToken
+	if previousAccessToken != atm.accessToken {
+		if configErr := updateConfigAccessToken(previousAccessToken, atm.accessToken); configErr != nil {
+			log.Warn("Couldn't save the refreshed JFrog access token in the JFrog home config:", configErr.Error())
+		}
+	}
+	return
+}
+
+// Replaces the access token of the servers of the JFrog home config that use the previous access token
+func updateConfigAccessToken(previousAccessToken, accessToken string) error {
+	servers, err := config.GetAllServersConfigs()
+	if err != nil {
+		return err
+	}
+	updated := false
+	for _, server := range servers {
+		if server.AccessToken == previousAccessToken {
+			server.AccessToken = accessToken
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+	return config.SaveServersConf(servers)
+}
+
+// Refreshes the access token with the refresh token. If there is no refresh token or it was rejected, a new OIDC ID token is exchanged.
+func (atm *AccessTokenManager) refresh() error {
+	if atm.refreshToken != "" {
+		err := atm.refreshWithRefreshToken()
+		if err == nil || atm.oidcProviderName == "" {
+			return err
+		}
+		log.Debug("Couldn't refresh the JFrog access token with the refresh token, exchanging a new OIDC ID token:", err.Error())
+	}
+	if atm.oidcProviderName == "" {
+		return fmt.Errorf("the access token can't be refreshed without a refresh token or an OIDC provider. Set %s or %s", JFrogRefreshTokenEnv, JFrogOidcProviderNameEnv)
+	}
+	return atm.exchangeOidcToken()
+}
+
+func (atm *AccessTokenManager) refreshWithRefreshToken() error {
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {atm.refreshToken}, "access_token": {atm.accessToken}}
+	response, err := atm.requestAccessToken(refreshTokenApi, []byte(form.Encode()), "application/x-www-form-urlencoded")
+	if err != nil {
+		return err
+	}
+	log.Info("Refreshed the JFrog access token")
+	atm.setAccessToken(response.AccessToken, response.RefreshToken, response.ExpiresIn)
+	return nil
+}
+
+func (atm *AccessTokenManager) exchangeOidcToken() error {
+	idToken, err := atm.getOidcIdToken()
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(map[string]string{
+		"grant_type":         "urn:ietf:params:oauth:grant-type:token-exchange",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:id_token",
+		"subject_token":      idToken,
+		"provider_name":      atm.oidcProviderName,
+	})
+	if err != nil {
+		return err
+	}
+	response, err := atm.requestAccessToken(oidcTokenExchangeApi, content, "application/json")
+	if err != nil {
+		return fmt.Errorf("failed to exchange the OIDC ID token for a JFrog access token: %w", err)
+	}
+	atm.setAccessToken(response.AccessToken, response.RefreshToken, response.ExpiresIn)
+	return nil
+}
+
+func (atm *AccessTokenManager) requestAccessToken(api string, content []byte, contentType string) (*accessTokenResponse, error) {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	httpClientDetails := httputils.HttpClientDetails{Headers: map[string]string{"Content-Type": contentType}}
+	resp, body, err := client.SendPost(atm.platformUrl+api, content, httpClientDetails, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the JFrog platform responded with status %s: %s", resp.Status, string(body))
+	}
+	response := &accessTokenResponse{}
+	if err = json.Unmarshal(body, response); err != nil {
+		return nil, err
+	}
+	if response.AccessToken == "" {
+		return nil, errors.New("the JFrog platform responded without an access token")
+	}
+	return response, nil
+}
+
+// Returns the configured OIDC ID token, or requests one from GitHub Actions
+func (atm *AccessTokenManager) getOidcIdToken() (string, error) {
+	if atm.oidcIdToken != "" {
+		return atm.oidcIdToken, nil
+	}
+	if atm.idTokenRequestUrl == "" || atm.idTokenRequestToken == "" {
+		return "", fmt.Errorf("an OIDC ID token is missing. Set %s, or run on GitHub Actions with the 'id-token: write' permission", JFrogOidcTokenEnv)
+	}
+	requestUrl, err := url.Parse(atm.idTokenRequestUrl)
+	if err != nil {
+		return "", err
+	}
+	if atm.oidcAudience != "" {
+		query := requestUrl.Query()
+		query.Set("audience", atm.oidcAudience)
+		requestUrl.RawQuery = query.Encode()
+	}
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return "", err
+	}
+	httpClientDetails := httputils.HttpClientDetails{AccessToken: atm.idTokenRequestToken}
+	resp, body, _, err := client.SendGet(requestUrl.String(), true, httpClientDetails, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request an OIDC ID token from GitHub Actions, status %s: %s", resp.Status, string(body))
+	}
+	var idTokenResponse struct {
+		Value string `json:"value"`
+	}
+	if err = json.Unmarshal(body, &idTokenResponse); err != nil {
+		return "", err
+	}
+	return idTokenResponse.Value, nil
+}
+
+// Sets the current access token. The expiry is taken from the response, or from the token itself if it's a JWT.
+// The refresh token is kept if the response doesn't include a new one.
+func (atm *AccessTokenManager) setAccessToken(accessToken, refreshToken string, expiresInSeconds int64) {
+	atm.accessToken = accessToken
+	atm.issuedTokens[accessToken] = true
+	if refreshToken != "" {
+		atm.refreshToken = refreshToken
+	}
+	if expiresInSeconds > 0 {
+		atm.expiry = time.Now().Add(time.Duration(expiresInSeconds) * time.Second)
+	} else {
+		atm.expiry = getJwtExpiry(accessToken)
+	}
+}
+
+// Returns the expiry of a JWT access token, or a zero time if the token isn't a JWT or doesn't expire
+func getJwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A JFrog platform mock that issues access tokens for OIDC ID tokens and refresh tokens
+type accessTokenServerMock struct {
+	exchanges       int
+	refreshes       int
+	lastExchangeReq map[string]string
+}
+
+func (m *accessTokenServerMock) newServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		switch r.URL.Path {
+		case "/" + oidcTokenExchangeApi:
+			m.exchanges++
+			m.lastExchangeReq = map[string]string{}
+			require.NoError(t, json.Unmarshal(body, &m.lastExchangeReq))
+			if m.lastExchangeReq["subject_token"] != "id-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, err = fmt.Fprintf(w, `{"access_token":"oidc-access-token-%d","refresh_token":"refresh-token","expires_in":3600}`, m.exchanges)
+		case "/" + refreshTokenApi:
+			m.refreshes++
+			form, parseErr := url.ParseQuery(string(body))
+			require.NoError(t, parseErr)
+			if form.Get("grant_type") != "refresh_token" || form.Get("refresh_token") != "refresh-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, err = fmt.Fprintf(w, `{"access_token":"refreshed-access-token-%d","expires_in":3600}`, m.refreshes)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func setupAccessTokenManagerTest(t *testing.T, envs map[string]string) {
+	SetEnvAndAssert(t, envs)
+	t.Cleanup(func() {
+		accessTokenManager = nil
+		assert.NoError(t, SanitizeEnv())
+	})
+}
+
+func TestExtractJFrogCredentialsWithOidc(t *testing.T) {
+	mock := &accessTokenServerMock{}
+	platform := mock.newServer(t)
+	setupAccessTokenManagerTest(t, map[string]string{
+		JFrogUrlEnv:              platform.URL,
+		JFrogOidcProviderNameEnv: "github-oidc",
+		JFrogOidcTokenEnv:        "id-token",
+	})
+	server, err := extractJFrogCredentialsFromEnvs()
+	require.NoError(t, err)
+	assert.Equal(t, "oidc-access-token-1", server.AccessToken)
+	assert.Equal(t, "github-oidc", mock.lastExchangeReq["provider_name"])
+	assert.Equal(t, "urn:ietf:params:oauth:token-type:id_token", mock.lastExchangeReq["subject_token_type"])
+	require.NotNil(t, accessTokenManager)
+	assert.False(t, accessTokenManager.isExpiring())
+
+	// An ID token that is rejected fails the run
+	SetEnvAndAssert(t, map[string]string{JFrogOidcTokenEnv: "invalid-id-token"})
+	_, err = extractJFrogCredentialsFromEnvs()
+	assert.ErrorContains(t, err, "failed to exchange the OIDC ID token for a JFrog access token")
+}
+
+func TestExtractJFrogCredentialsWithGithubActionsOidc(t *testing.T) {
+	mock := &accessTokenServerMock{}
+	platform := mock.newServer(t)
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer request-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "jfrog-github", r.URL.Query().Get("audience"))
+		_, err := w.Write([]byte(`{"value":"id-token"}`))
+		assert.NoError(t, err)
+	}))
+	defer github.Close()
+	t.Setenv(githubActionsIdTokenRequestUrlEnv, github.URL+"/token?api-version=2.0")
+	t.Setenv(githubActionsIdTokenRequestTokenEnv, "request-token")
+	setupAccessTokenManagerTest(t, map[string]string{
+		JFrogUrlEnv:              platform.URL,
+		JFrogOidcProviderNameEnv: "github-oidc",
+		JFrogOidcAudienceEnv:     "jfrog-github",
+	})
+	server, err := extractJFrogCredentialsFromEnvs()
+	require.NoError(t, err)
+	assert.Equal(t, "oidc-access-token-1", server.AccessToken)
+}
+
+func TestRunWithAccessTokenRefresh(t *testing.T) {
+	mock := &accessTokenServerMock{}
+	platform := mock.newServer(t)
+	setupAccessTokenManagerTest(t, map[string]string{
+		JFrogUrlEnv:          platform.URL,
+		JFrogTokenEnv:        "access-token",
+		JFrogRefreshTokenEnv: "refresh-token",
+	})
+	server, err := extractJFrogCredentialsFromEnvs()
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", server.AccessToken)
+	t.Setenv(JfrogHomeDirEnv, t.TempDir())
+	require.NoError(t, config.SaveServersConf([]*config.ServerDetails{{ServerId: "frogbot", Url: server.Url, AccessToken: "access-token"}}))
+
+	// A rejected access token is refreshed and the operation is retried
+	var usedTokens []string
+	err = RunWithAccessTokenRefresh(server, func() error {
+		usedTokens = append(usedTokens, server.AccessToken)
+		if server.AccessToken == "access-token" {
+			return errors.New("server response: 401 Unauthorized")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"access-token", "refreshed-access-token-1"}, usedTokens)
+	// The JFrog CLI commands read the refreshed access token from the JFrog home config
+	configServer, err := config.GetSpecificConfig("frogbot", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed-access-token-1", configServer.AccessToken)
+
+	// Other servers that share the credentials get the refreshed token without refreshing it again
+	otherServer := &config.ServerDetails{Url: server.Url, AccessToken: "access-token"}
+	assert.NoError(t, RunWithAccessTokenRefresh(otherServer, func() error {
+		if otherServer.AccessToken == "access-token" {
+			return errors.New("server response: 401 Unauthorized")
+		}
+		return nil
+	}))
+	assert.Equal(t, "refreshed-access-token-1", otherServer.AccessToken)
+	assert.Equal(t, 1, mock.refreshes)
+
+	// Other errors aren't retried, and servers with other tokens aren't refreshed
+	operationErr := errors.New("server response: 500 Internal Server Error")
+	assert.Equal(t, operationErr, RunWithAccessTokenRefresh(server, func() error { return operationErr }))
+	repositoryServer := &config.ServerDetails{Url: "https://other.jfrog.io/", AccessToken: "other-token"}
+	unauthorizedErr := errors.New("server response: 401 Unauthorized")
+	assert.Equal(t, unauthorizedErr, RunWithAccessTokenRefresh(repositoryServer, func() error { return unauthorizedErr }))
+	assert.Equal(t, 1, mock.refreshes)
+
+	// Tokens that are about to expire are refreshed before the operation
+	accessTokenManager.expiry = time.Now().Add(time.Minute)
+	assert.NoError(t, RunWithAccessTokenRefresh(server, func() error { return nil }))
+	assert.Equal(t, "refreshed-access-token-2", server.AccessToken)
+}
+
+func TestIsUnauthorizedError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{err: nil, expected: false},
+		{err: errors.New("server response: 401 Unauthorized"), expected: true},
+		{err: errors.Join(errors.New("audit failed"), errors.New("server response: 401 Unauthorized\n{\"errors\":[]}")), expected: true},
+		{err: errors.New("server response: 403 Forbidden"), expected: false},
+		{err: errors.New("the dependency lodash@4.0.401 wasn't found"), expected: false},
+		{err: errors.New("Unauthorized operation: go get github.com/example/401"), expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.err), func(t *testing.T) {
+			assert.Equal(t, tc.expected, isUnauthorizedError(tc.err))
+		})
+	}
+}
+
+func TestSetupAccessTokenManagerErrors(t *testing.T) {
+	setupAccessTokenManagerTest(t, map[string]string{JFrogRefreshTokenEnv: "refresh-token"})
+	assert.ErrorContains(t, setupAccessTokenManager(&config.ServerDetails{XrayUrl: "https://jfrog.io/xray/", AccessToken: "token"}), "the JF_URL environment variable is required")
+	assert.ErrorContains(t, setupAccessTokenManager(&config.ServerDetails{Url: "https://jfrog.io/"}), "a refresh token was provided without an access token")
+
+	// Refresh tokens are ignored for basic authentication
+	assert.NoError(t, setupAccessTokenManager(&config.ServerDetails{Url: "https://jfrog.io/", User: "admin", Password: "password"}))
+	assert.Nil(t, accessTokenManager)
+}
+
+func TestGetJwtExpiry(t *testing.T) {
+	encode := func(claims string) string {
+		return "header." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+	}
+	assert.Equal(t, time.Unix(1767225600, 0), getJwtExpiry(encode(`{"sub":"jfrt@01/users/admin","exp":1767225600}`)))
+	assert.True(t, getJwtExpiry(encode(`{"sub":"jfrt@01/users/admin"}`)).IsZero())
+	assert.True(t, getJwtExpiry("cmVmdGtuOjAxOjE3").IsZero())
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/frogbot/v2/utils/alternatives"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The path of the alternative packages file in the repository
+const AlternativePackagesFilePath = frogbotConfigDir + "/alternative-packages.yml"
+
+// Returns the alternative packages of the alternative packages file of the repository, or nil if the repository has no such file.
+// The file is read from the target branch of the pull request, like the other configuration files of the repository.
+func GetPullRequestAlternativePackages(repo *Repository, client vcsclient.VcsClient) (*alternatives.Mapping, error) {
+	target := repo.PullRequestDetails.Target
+	content, statusCode, err := client.DownloadFileFromRepo(context.Background(), target.Owner, target.Repository, target.Name, AlternativePackagesFilePath)
+	if statusCode == http.StatusNotFound {
+		log.Debug(fmt.Sprintf("The %s file wasn't found in <%s/%s/%s>", AlternativePackagesFilePath, target.Owner, target.Repository, target.Name))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't download the %s file from <%s/%s/%s>: %s", AlternativePackagesFilePath, target.Owner, target.Repository, target.Name, err.Error())
+	}
+	return parseAlternativePackages(content)
+}
+
+// Returns the alternative packages of the alternative packages file of the cloned repository, or nil if the repository has no such file
+func GetAlternativePackages(repoDir string) (*alternatives.Mapping, error) {
+	content, err := os.ReadFile(filepath.Join(repoDir, AlternativePackagesFilePath))
+	if errors.Is(err, os.ErrNotExist) {
+		log.Debug(fmt.Sprintf("The %s file wasn't found in the repository", AlternativePackagesFilePath))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the %s file: %s", AlternativePackagesFilePath, err.Error())
+	}
+	return parseAlternativePackages(content)
+}
+
+func parseAlternativePackages(content []byte) (*alternatives.Mapping, error) {
+	mapping, err := alternatives.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the %s file: %s", AlternativePackagesFilePath, err.Error())
+	}
+	log.Info(fmt.Sprintf("The alternative packages of the %s file are suggested for the vulnerable dependencies without a fixed version", AlternativePackagesFilePath))
+	return mapping, nil
+}
@@ -0,0 +1,150 @@
+package alternatives
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/research"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping maps vulnerable packages to the maintained forks or the alternative packages that can replace them.
+// It is defined in a file in the repository, so each team suggests the replacements that it approves.
+type Mapping struct {
+	Packages []Package `yaml:"packages"`
+}
+
+// Package lists the alternatives of a package
+type Package struct {
+	// The name of the package, as the scan results show it, such as 'request' or 'org.apache.logging.log4j:log4j-core'
+	Name         string        `yaml:"name"`
+	Alternatives []Alternative `yaml:"alternatives"`
+}
+
+// Alternative is a maintained fork or an alternative package
+type Alternative struct {
+	Name string `yaml:"name"`
+	// The page or the repository of the alternative
+	Url string `yaml:"url,omitempty"`
+	// How the alternative differs, or how to migrate to it
+	Note string `yaml:"note,omitempty"`
+}
+
+// Parses the content of an alternative packages file, and validates its packages
+func Parse(content []byte) (*Mapping, error) {
+	mapping := &Mapping{}
+	if err := yaml.Unmarshal(content, mapping); err != nil {
+		return nil, fmt.Errorf("the alternative packages file isn't a valid YAML file: %s", err.Error())
+	}
+	for i, pkg := range mapping.Packages {
+		if pkg.Name == "" {
+			return nil, fmt.Errorf("the name of package #%d of the alternative packages file is missing", i+1)
+		}
+		if len(pkg.Alternatives) == 0 {
+			return nil, fmt.Errorf("the '%s' package of the alternative packages file has no alternatives", pkg.Name)
+		}
+		for _, alternative := range pkg.Alternatives {
+			if alternative.Name == "" {
+				return nil, fmt.Errorf("the name of an alternative of the '%s' package of the alternative packages file is missing", pkg.Name)
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// Returns the alternatives of the package, or nil if the mapping has none. Package names are matched case-insensitively.
+func (m *Mapping) GetAlternatives(packageName string) (alternatives []issues.PackageAlternative) {
+	if m == nil {
+		return
+	}
+	for _, pkg := range m.Packages {
+		if !strings.EqualFold(pkg.Name, packageName) {
+			continue
+		}
+		for _, alternative := range pkg.Alternatives {
+			alternatives = append(alternatives, issues.PackageAlternative{Name: alternative.Name, Url: alternative.Url, Note: alternative.Note})
+		}
+	}
+	return
+}
+
+// Returns the suggestions of the vulnerable dependencies that have no fixed version, and so can't be fixed by upgrading them.
+// Each dependency is suggested once, with the alternatives of the mapping and the remediation of the JFrog research of its vulnerabilities.
+// The extended remediation of the research details is preferred over the remediation of the scan results.
+// Returns nil without a mapping, so the suggestions are only made in the repositories that define their alternatives.
+func (m *Mapping) Suggest(vulnerabilities []formats.VulnerabilityOrViolationRow, researchDetails map[string]research.Details) (suggestions []issues.AlternativePackageSuggestion) {
+	if m == nil {
+		return
+	}
+	indexes := map[string]int{}
+	for _, vulnerability := range vulnerabilities {
+		if len(vulnerability.FixedVersions) > 0 {
+			continue
+		}
+		key := vulnerability.ImpactedDependencyName + ":" + vulnerability.ImpactedDependencyVersion
+		index, exists := indexes[key]
+		if !exists {
+			index = len(suggestions)
+			indexes[key] = index
+			suggestions = append(suggestions, issues.AlternativePackageSuggestion{
+				PackageType:  vulnerability.ImpactedDependencyType,
+				PackageName:  vulnerability.ImpactedDependencyName,
+				Version:      vulnerability.ImpactedDependencyVersion,
+				Severity:     vulnerability.Severity,
+				Alternatives: m.GetAlternatives(vulnerability.ImpactedDependencyName),
+			})
+		}
+		addVulnerability(&suggestions[index], vulnerability, researchDetails)
+	}
+	// Dependencies without alternatives or remediation have nothing to suggest
+	filtered := suggestions[:0]
+	for _, suggestion := range suggestions {
+		if len(suggestion.Alternatives) > 0 || len(suggestion.Remediations) > 0 {
+			filtered = append(filtered, suggestion)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+func addVulnerability(suggestion *issues.AlternativePackageSuggestion, vulnerability formats.VulnerabilityOrViolationRow, researchDetails map[string]research.Details) {
+	if severityutils.CompareSeverity(severityutils.GetSeverity(vulnerability.Severity), severityutils.GetSeverity(suggestion.Severity)) > 0 {
+		suggestion.Severity = vulnerability.Severity
+	}
+	var issueIds []string
+	for _, cve := range vulnerability.Cves {
+		if cve.Id != "" {
+			issueIds = append(issueIds, cve.Id)
+		}
+	}
+	if len(issueIds) == 0 && vulnerability.IssueId != "" {
+		issueIds = append(issueIds, vulnerability.IssueId)
+	}
+	for _, issueId := range issueIds {
+		if !slices.Contains(suggestion.IssueIds, issueId) {
+			suggestion.IssueIds = append(suggestion.IssueIds, issueId)
+		}
+	}
+	var remediations []string
+	for _, issueId := range issueIds {
+		if remediation := strings.TrimSpace(researchDetails[issueId].ExtendedRemediation); remediation != "" {
+			remediations = append(remediations, remediation)
+		}
+	}
+	if len(remediations) == 0 && vulnerability.JfrogResearchInformation != nil {
+		if remediation := strings.TrimSpace(vulnerability.JfrogResearchInformation.Remediation); remediation != "" {
+			remediations = append(remediations, remediation)
+		}
+	}
+	for _, remediation := range remediations {
+		if !slices.Contains(suggestion.Remediations, remediation) {
+			suggestion.Remediations = append(suggestion.Remediations, remediation)
+		}
+	}
+}
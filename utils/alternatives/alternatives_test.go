@@ -0,0 +1,123 @@
+package alternatives
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/research"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMapping = `packages:
+  - name: request
+    alternatives:
+      - name: axios
+        url: https://github.com/axios/axios
+        note: Promise based
+      - name: got
+`
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expected      *Mapping
+		expectedError bool
+	}{
+		{
+			name:    "Packages with alternatives",
+			content: testMapping,
+			expected: &Mapping{Packages: []Package{{Name: "request", Alternatives: []Alternative{
+				{Name: "axios", Url: "https://github.com/axios/axios", Note: "Promise based"},
+				{Name: "got"},
+			}}}},
+		},
+		{name: "Empty file", content: "", expected: &Mapping{}},
+		{name: "Missing package name", content: "packages:\n  - alternatives:\n      - name: axios\n", expectedError: true},
+		{name: "No alternatives", content: "packages:\n  - name: request\n", expectedError: true},
+		{name: "Missing alternative name", content: "packages:\n  - name: request\n    alternatives:\n      - url: https://github.com/axios/axios\n", expectedError: true},
+		{name: "Invalid YAML", content: "packages: request: axios", expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mapping, err := Parse([]byte(tc.content))
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, mapping)
+		})
+	}
+}
+
+func TestGetAlternatives(t *testing.T) {
+	mapping, err := Parse([]byte(testMapping))
+	require.NoError(t, err)
+	expected := []issues.PackageAlternative{{Name: "axios", Url: "https://github.com/axios/axios", Note: "Promise based"}, {Name: "got"}}
+	assert.Equal(t, expected, mapping.GetAlternatives("request"))
+	assert.Equal(t, expected, mapping.GetAlternatives("Request"))
+	assert.Empty(t, mapping.GetAlternatives("lodash"))
+	assert.Empty(t, (*Mapping)(nil).GetAlternatives("request"))
+}
+
+func TestSuggest(t *testing.T) {
+	newVulnerability := func(name, version, severity string, cves []string, fixedVersions []string, remediation string) formats.VulnerabilityOrViolationRow {
+		vulnerability := formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: severity},
+				ImpactedDependencyName:    name,
+				ImpactedDependencyVersion: version,
+				ImpactedDependencyType:    "npm",
+			},
+			FixedVersions: fixedVersions,
+			IssueId:       "XRAY-1",
+		}
+		for _, cve := range cves {
+			vulnerability.Cves = append(vulnerability.Cves, formats.CveRow{Id: cve})
+		}
+		if remediation != "" {
+			vulnerability.JfrogResearchInformation = &formats.JfrogResearchInformation{Remediation: remediation}
+		}
+		return vulnerability
+	}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		newVulnerability("request", "2.88.2", "Medium", []string{"CVE-2023-28155"}, nil, "Don't follow redirects"),
+		newVulnerability("request", "2.88.2", "High", []string{"CVE-2024-0001"}, nil, ""),
+		// Dependencies with a fixed version are fixed by upgrading them
+		newVulnerability("lodash", "4.17.20", "Critical", []string{"CVE-2021-23337"}, []string{"[4.17.21]"}, "Upgrade"),
+		// Dependencies without alternatives are suggested with their remediation
+		newVulnerability("minimist", "0.0.8", "Low", nil, nil, "Validate the arguments"),
+		// Dependencies without alternatives and remediation have nothing to suggest
+		newVulnerability("debug", "2.6.8", "Low", nil, nil, ""),
+	}
+	researchDetails := map[string]research.Details{"CVE-2024-0001": {ExtendedRemediation: "Replace the package"}}
+
+	mapping, err := Parse([]byte(testMapping))
+	require.NoError(t, err)
+	assert.Equal(t, []issues.AlternativePackageSuggestion{
+		{
+			PackageType:  "npm",
+			PackageName:  "request",
+			Version:      "2.88.2",
+			Severity:     "High",
+			IssueIds:     []string{"CVE-2023-28155", "CVE-2024-0001"},
+			Alternatives: []issues.PackageAlternative{{Name: "axios", Url: "https://github.com/axios/axios", Note: "Promise based"}, {Name: "got"}},
+			Remediations: []string{"Don't follow redirects", "Replace the package"},
+		},
+		{
+			PackageType:  "npm",
+			PackageName:  "minimist",
+			Version:      "0.0.8",
+			Severity:     "Low",
+			IssueIds:     []string{"XRAY-1"},
+			Remediations: []string{"Validate the arguments"},
+		},
+	}, mapping.Suggest(vulnerabilities, researchDetails))
+
+	// Nothing is suggested without a mapping
+	assert.Nil(t, (*Mapping)(nil).Suggest(vulnerabilities, researchDetails))
+	assert.Nil(t, mapping.Suggest(vulnerabilities[4:], nil))
+}
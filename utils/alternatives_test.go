@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAlternativePackages = "packages:\n  - name: request\n    alternatives:\n      - name: axios\n"
+
+func TestGetPullRequestAlternativePackages(t *testing.T) {
+	repo := &Repository{Params: Params{Git: Git{PullRequestDetails: vcsclient.PullRequestInfo{Target: vcsclient.BranchInfo{Owner: "owner", Repository: "repo", Name: "main"}}}}}
+	testCases := []struct {
+		name                 string
+		content              string
+		statusCode           int
+		downloadErr          error
+		expectedAlternatives []string
+		expectedError        string
+	}{
+		{name: "Alternative packages file", content: testAlternativePackages, statusCode: http.StatusOK, expectedAlternatives: []string{"axios"}},
+		{name: "No alternative packages file", statusCode: http.StatusNotFound, downloadErr: errors.New("not found")},
+		{name: "Invalid alternative packages file", content: "packages:\n  - name: request\n", statusCode: http.StatusOK, expectedError: "couldn't parse the .frogbot/alternative-packages.yml file"},
+		{name: "Failed download", statusCode: http.StatusInternalServerError, downloadErr: errors.New("server error"), expectedError: "couldn't download the .frogbot/alternative-packages.yml file"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := testdata.NewMockVcsClient(gomock.NewController(t))
+			client.EXPECT().DownloadFileFromRepo(context.Background(), "owner", "repo", "main", AlternativePackagesFilePath).Return([]byte(tc.content), tc.statusCode, tc.downloadErr)
+			mapping, err := GetPullRequestAlternativePackages(repo, client)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectedAlternatives == nil {
+				assert.Nil(t, mapping)
+				return
+			}
+			var names []string
+			for _, alternative := range mapping.GetAlternatives("request") {
+				names = append(names, alternative.Name)
+			}
+			assert.Equal(t, tc.expectedAlternatives, names)
+		})
+	}
+}
+
+func TestGetAlternativePackages(t *testing.T) {
+	repoDir := t.TempDir()
+	mapping, err := GetAlternativePackages(repoDir)
+	require.NoError(t, err)
+	assert.Nil(t, mapping)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, frogbotConfigDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, AlternativePackagesFilePath), []byte(testAlternativePackages), 0644))
+	mapping, err = GetAlternativePackages(repoDir)
+	require.NoError(t, err)
+	require.Len(t, mapping.GetAlternatives("request"), 1)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, AlternativePackagesFilePath), []byte("packages: request"), 0644))
+	_, err = GetAlternativePackages(repoDir)
+	assert.ErrorContains(t, err, "couldn't parse the .frogbot/alternative-packages.yml file")
+}
@@ -37,3 +37,22 @@ func resolveCi() string {
 		return ""
 	}
 }
+
+// Returns the URL of the CI run that is currently running the command, or an empty string if it's unknown.
+func GetCiRunUrl() string {
+	switch ciProvider(resolveCi()) {
+	case githubActions:
+		if serverUrl, repository, runId := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"); serverUrl != "" && repository != "" && runId != "" {
+			return strings.TrimSuffix(serverUrl, "/") + "/" + repository + "/actions/runs/" + runId
+		}
+	case gitlab:
+		return os.Getenv("CI_JOB_URL")
+	case jenkins:
+		return os.Getenv("BUILD_URL")
+	case azurePipelines:
+		if collectionUri, project, buildId := os.Getenv("SYSTEM_COLLECTIONURI"), os.Getenv("SYSTEM_TEAMPROJECT"), os.Getenv("BUILD_BUILDID"); collectionUri != "" && project != "" && buildId != "" {
+			return strings.TrimSuffix(collectionUri, "/") + "/" + project + "/_build/results?buildId=" + buildId
+		}
+	}
+	return ""
+}
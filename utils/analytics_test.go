@@ -45,3 +45,49 @@ func TestCreateAnalyticsGeneralEvent(t *testing.T) {
 	assert.NotEmpty(t, analyticsGeneralEvent.OsArchitecture)
 	assert.NotEmpty(t, analyticsGeneralEvent.AnalyzerManagerVersion)
 }
+
+func TestGetCiRunUrl(t *testing.T) {
+	testCases := []struct {
+		name     string
+		envs     map[string]string
+		expected string
+	}{
+		{
+			name:     "GitHub Actions",
+			envs:     map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_SERVER_URL": "https://github.com", "GITHUB_REPOSITORY": "jfrog/frogbot", "GITHUB_RUN_ID": "123"},
+			expected: "https://github.com/jfrog/frogbot/actions/runs/123",
+		},
+		{
+			name:     "GitLab CI",
+			envs:     map[string]string{"GITLAB_CI": "true", "CI_JOB_URL": "https://gitlab.com/jfrog/frogbot/-/jobs/123"},
+			expected: "https://gitlab.com/jfrog/frogbot/-/jobs/123",
+		},
+		{
+			name:     "Jenkins",
+			envs:     map[string]string{"JENKINS_URL": "https://jenkins.example.com/", "BUILD_URL": "https://jenkins.example.com/job/frogbot/123/"},
+			expected: "https://jenkins.example.com/job/frogbot/123/",
+		},
+		{
+			name:     "Azure Pipelines",
+			envs:     map[string]string{"TF_BUILD": "True", "SYSTEM_COLLECTIONURI": "https://dev.azure.com/jfrog/", "SYSTEM_TEAMPROJECT": "frogbot", "BUILD_BUILDID": "123"},
+			expected: "https://dev.azure.com/jfrog/frogbot/_build/results?buildId=123",
+		},
+		{
+			name:     "GitHub Actions without run details",
+			envs:     map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_SERVER_URL": "", "GITHUB_REPOSITORY": "", "GITHUB_RUN_ID": ""},
+			expected: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Clear the CI indicators of the environment the test runs in
+			for _, ciEnv := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "JENKINS_URL", "TF_BUILD"} {
+				t.Setenv(ciEnv, "")
+			}
+			for key, value := range tc.envs {
+				t.Setenv(key, value)
+			}
+			assert.Equal(t, tc.expected, GetCiRunUrl())
+		})
+	}
+}
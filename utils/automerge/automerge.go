@@ -0,0 +1,342 @@
+package automerge
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// The strategies that the pull requests are merged with
+const (
+	MergeStrategy  = "merge"
+	SquashStrategy = "squash"
+	RebaseStrategy = "rebase"
+)
+
+var Strategies = []string{MergeStrategy, SquashStrategy, RebaseStrategy}
+
+// Merger merges the pull requests of a repository once their checks passed.
+// The Git clients can't merge pull requests, so they're merged by the API of the Git provider.
+type Merger interface {
+	// Returns the reason that the pull request can't be merged yet, or an empty string if its checks passed and it can be merged.
+	// A pull request without checks isn't merged, since nothing verified it.
+	GetBlockingReason(pullRequestId int64) (string, error)
+	Merge(pullRequestId int64, strategy string) error
+}
+
+// Returns the merger of the Git provider
+func NewMerger(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName string) (Merger, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	repositoryUrl := client.RepositoryUrl(repoOwner, repoName)
+	switch provider {
+	case vcsutils.GitHub:
+		return &gitHubMerger{client: client, repositoryUrl: repositoryUrl}, nil
+	case vcsutils.GitLab:
+		return &gitLabMerger{client: client, mergeRequestsUrl: repositoryUrl + "/merge_requests"}, nil
+	case vcsutils.BitbucketServer:
+		return &bitbucketServerMerger{client: client, pullRequestsUrl: repositoryUrl + "/pull-requests"}, nil
+	case vcsutils.BitbucketCloud:
+		return &bitbucketCloudMerger{client: client, pullRequestsUrl: repositoryUrl + "/pullrequests"}, nil
+	case vcsutils.AzureRepos:
+		return &azureReposMerger{client: client, pullRequestsUrl: repositoryUrl + "/pullRequests"}, nil
+	default:
+		return nil, fmt.Errorf("merging pull requests isn't supported for %s", provider.String())
+	}
+}
+
+// Returns the reason that the checks block the merge, by the checks that didn't pass and the checks that are still running
+func getChecksBlockingReason(checksCount int, failed, running []string) string {
+	switch {
+	case checksCount == 0:
+		return "it has no checks"
+	case len(failed) > 0:
+		return "the checks didn't pass: " + strings.Join(failed, ", ")
+	case len(running) > 0:
+		return "the checks are still running: " + strings.Join(running, ", ")
+	}
+	return ""
+}
+
+type gitHubMerger struct {
+	client        *vcsapi.Client
+	repositoryUrl string
+}
+
+// GitHub Actions report check runs, and the other CI systems may report commit statuses, so both are checked.
+// The merge state is clean only if the pull request has no conflicts and its required checks and reviews passed.
+func (gm *gitHubMerger) GetBlockingReason(pullRequestId int64) (string, error) {
+	var pullRequest struct {
+		MergeableState string `json:"mergeable_state"`
+		Head           struct {
+			Sha string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := gm.client.Get(fmt.Sprintf("%s/pulls/%d", gm.repositoryUrl, pullRequestId), &pullRequest); err != nil {
+		return "", err
+	}
+	var checkRuns struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := gm.client.Get(fmt.Sprintf("%s/commits/%s/check-runs?per_page=%d", gm.repositoryUrl, pullRequest.Head.Sha, vcsapi.PageSize), &checkRuns); err != nil {
+		return "", err
+	}
+	var combinedStatus struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := gm.client.Get(fmt.Sprintf("%s/commits/%s/status", gm.repositoryUrl, pullRequest.Head.Sha), &combinedStatus); err != nil {
+		return "", err
+	}
+	var failed, running []string
+	for _, checkRun := range checkRuns.CheckRuns {
+		switch {
+		case checkRun.Status != "completed":
+			running = append(running, checkRun.Name)
+		case !slices.Contains([]string{"success", "neutral", "skipped"}, checkRun.Conclusion):
+			failed = append(failed, checkRun.Name)
+		}
+	}
+	for _, status := range combinedStatus.Statuses {
+		switch status.State {
+		case "pending":
+			running = append(running, status.Context)
+		case "failure", "error":
+			failed = append(failed, status.Context)
+		}
+	}
+	if reason := getChecksBlockingReason(len(checkRuns.CheckRuns)+len(combinedStatus.Statuses), failed, running); reason != "" {
+		return reason, nil
+	}
+	if pullRequest.MergeableState != "clean" {
+		return fmt.Sprintf("its merge state is '%s'", pullRequest.MergeableState), nil
+	}
+	return "", nil
+}
+
+func (gm *gitHubMerger) Merge(pullRequestId int64, strategy string) error {
+	return gm.client.Send(http.MethodPut, fmt.Sprintf("%s/pulls/%d/merge", gm.repositoryUrl, pullRequestId), map[string]string{"merge_method": strategy}, nil)
+}
+
+type gitLabMerger struct {
+	client           *vcsapi.Client
+	mergeRequestsUrl string
+}
+
+// The pipeline of the latest commit of the merge request is its check, and the detailed merge status includes the approvals and the discussions
+func (gl *gitLabMerger) GetBlockingReason(pullRequestId int64) (string, error) {
+	var mergeRequest struct {
+		DetailedMergeStatus string `json:"detailed_merge_status"`
+		HeadPipeline        *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	if err := gl.client.Get(fmt.Sprintf("%s/%d", gl.mergeRequestsUrl, pullRequestId), &mergeRequest); err != nil {
+		return "", err
+	}
+	if mergeRequest.HeadPipeline == nil {
+		return getChecksBlockingReason(0, nil, nil), nil
+	}
+	switch mergeRequest.HeadPipeline.Status {
+	case "success":
+	case "failed", "canceled", "skipped":
+		return getChecksBlockingReason(1, []string{"pipeline"}, nil), nil
+	default:
+		return getChecksBlockingReason(1, nil, []string{"pipeline"}), nil
+	}
+	if mergeRequest.DetailedMergeStatus != "mergeable" {
+		return fmt.Sprintf("its merge status is '%s'", mergeRequest.DetailedMergeStatus), nil
+	}
+	return "", nil
+}
+
+// The merge method of GitLab is a setting of the project, so only squashing is chosen by the merge
+func (gl *gitLabMerger) Merge(pullRequestId int64, strategy string) error {
+	if strategy == RebaseStrategy {
+		return fmt.Errorf("the %s merge strategy isn't supported for %s, since the merge method is a setting of the project", strategy, vcsutils.GitLab.String())
+	}
+	body := map[string]bool{"squash": strategy == SquashStrategy}
+	return gl.client.Send(http.MethodPut, fmt.Sprintf("%s/%d/merge", gl.mergeRequestsUrl, pullRequestId), body, nil)
+}
+
+type bitbucketServerMerger struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+type bitbucketServerPullRequest struct {
+	Version int `json:"version"`
+	FromRef struct {
+		LatestCommit string `json:"latestCommit"`
+	} `json:"fromRef"`
+}
+
+func (bs *bitbucketServerMerger) getPullRequest(pullRequestId int64) (pullRequest bitbucketServerPullRequest, err error) {
+	err = bs.client.Get(fmt.Sprintf("%s/%d", bs.pullRequestsUrl, pullRequestId), &pullRequest)
+	return
+}
+
+// The builds of the latest commit are the checks, and the merge vetoes include the merge checks of the repository
+func (bs *bitbucketServerMerger) GetBlockingReason(pullRequestId int64) (string, error) {
+	pullRequest, err := bs.getPullRequest(pullRequestId)
+	if err != nil {
+		return "", err
+	}
+	var buildStats struct {
+		Successful int `json:"successful"`
+		InProgress int `json:"inProgress"`
+		Failed     int `json:"failed"`
+	}
+	if err = bs.client.Get(bs.client.Url("/build-status/1.0/commits/stats/%s", pullRequest.FromRef.LatestCommit), &buildStats); err != nil {
+		return "", err
+	}
+	var failed, running []string
+	if buildStats.Failed > 0 {
+		failed = append(failed, fmt.Sprintf("%d builds", buildStats.Failed))
+	}
+	if buildStats.InProgress > 0 {
+		running = append(running, fmt.Sprintf("%d builds", buildStats.InProgress))
+	}
+	if reason := getChecksBlockingReason(buildStats.Successful+buildStats.InProgress+buildStats.Failed, failed, running); reason != "" {
+		return reason, nil
+	}
+	var mergeStatus struct {
+		CanMerge bool `json:"canMerge"`
+		Vetoes   []struct {
+			SummaryMessage string `json:"summaryMessage"`
+		} `json:"vetoes"`
+	}
+	if err = bs.client.Get(fmt.Sprintf("%s/%d/merge", bs.pullRequestsUrl, pullRequestId), &mergeStatus); err != nil {
+		return "", err
+	}
+	if !mergeStatus.CanMerge {
+		var vetoes []string
+		for _, veto := range mergeStatus.Vetoes {
+			vetoes = append(vetoes, veto.SummaryMessage)
+		}
+		return "it can't be merged: " + strings.Join(vetoes, ", "), nil
+	}
+	return "", nil
+}
+
+// The merge is of the version of the pull request, so it fails if the pull request changed since it was checked
+func (bs *bitbucketServerMerger) Merge(pullRequestId int64, strategy string) error {
+	pullRequest, err := bs.getPullRequest(pullRequestId)
+	if err != nil {
+		return err
+	}
+	strategyId := map[string]string{MergeStrategy: "no-ff", SquashStrategy: "squash", RebaseStrategy: "rebase-no-ff"}[strategy]
+	mergeUrl := fmt.Sprintf("%s/%d/merge?version=%d", bs.pullRequestsUrl, pullRequestId, pullRequest.Version)
+	return bs.client.Send(http.MethodPost, mergeUrl, map[string]string{"strategyId": strategyId}, nil)
+}
+
+type bitbucketCloudMerger struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+// The commit statuses of the pull request are its checks. Bitbucket Cloud checks the merge restrictions of the branch on the merge.
+func (bc *bitbucketCloudMerger) GetBlockingReason(pullRequestId int64) (string, error) {
+	type commitStatus struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	statuses, err := vcsapi.List[commitStatus](bc.client, fmt.Sprintf("%s/%d/statuses", bc.pullRequestsUrl, pullRequestId), vcsapi.PageSize)
+	if err != nil {
+		return "", err
+	}
+	var failed, running []string
+	for _, status := range statuses {
+		switch status.State {
+		case "INPROGRESS":
+			running = append(running, status.Name)
+		case "FAILED", "STOPPED":
+			failed = append(failed, status.Name)
+		}
+	}
+	return getChecksBlockingReason(len(statuses), failed, running), nil
+}
+
+func (bc *bitbucketCloudMerger) Merge(pullRequestId int64, strategy string) error {
+	mergeStrategy := map[string]string{MergeStrategy: "merge_commit", SquashStrategy: "squash", RebaseStrategy: "rebase_merge"}[strategy]
+	return bc.client.Send(http.MethodPost, fmt.Sprintf("%s/%d/merge", bc.pullRequestsUrl, pullRequestId), map[string]string{"merge_strategy": mergeStrategy}, nil)
+}
+
+type azureReposMerger struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+type azureReposPullRequest struct {
+	MergeStatus           string `json:"mergeStatus"`
+	LastMergeSourceCommit struct {
+		CommitId string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+}
+
+func (ar *azureReposMerger) getPullRequest(pullRequestId int64) (pullRequest azureReposPullRequest, err error) {
+	err = ar.client.Get(fmt.Sprintf("%s/%d?api-version=%s", ar.pullRequestsUrl, pullRequestId, vcsapi.AzureApiVersion), &pullRequest)
+	return
+}
+
+// The statuses of the pull request are its checks, which the build policies and the external CI systems post
+func (ar *azureReposMerger) GetBlockingReason(pullRequestId int64) (string, error) {
+	pullRequest, err := ar.getPullRequest(pullRequestId)
+	if err != nil {
+		return "", err
+	}
+	var statuses struct {
+		Value []struct {
+			State   string `json:"state"`
+			Context struct {
+				Name string `json:"name"`
+			} `json:"context"`
+		} `json:"value"`
+	}
+	if err = ar.client.Get(fmt.Sprintf("%s/%d/statuses?api-version=%s", ar.pullRequestsUrl, pullRequestId, vcsapi.AzureApiVersion), &statuses); err != nil {
+		return "", err
+	}
+	var failed, running []string
+	for _, status := range statuses.Value {
+		switch status.State {
+		case "pending", "notSet":
+			running = append(running, status.Context.Name)
+		case "failed", "error":
+			failed = append(failed, status.Context.Name)
+		}
+	}
+	if reason := getChecksBlockingReason(len(statuses.Value), failed, running); reason != "" {
+		return reason, nil
+	}
+	if pullRequest.MergeStatus != "succeeded" {
+		return fmt.Sprintf("its merge status is '%s'", pullRequest.MergeStatus), nil
+	}
+	return "", nil
+}
+
+// The pull request is completed with its last merge source commit, so it fails if the pull request changed since it was checked
+func (ar *azureReposMerger) Merge(pullRequestId int64, strategy string) error {
+	pullRequest, err := ar.getPullRequest(pullRequestId)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"status":                "completed",
+		"lastMergeSourceCommit": map[string]string{"commitId": pullRequest.LastMergeSourceCommit.CommitId},
+		"completionOptions": map[string]any{
+			"mergeStrategy":      map[string]string{MergeStrategy: "noFastForward", SquashStrategy: "squash", RebaseStrategy: "rebase"}[strategy],
+			"deleteSourceBranch": true,
+		},
+	}
+	return ar.client.Send(http.MethodPatch, fmt.Sprintf("%s/%d?api-version=%s", ar.pullRequestsUrl, pullRequestId, vcsapi.AzureApiVersion), body, nil)
+}
@@ -0,0 +1,185 @@
+package automerge
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger(t *testing.T) {
+	testCases := []struct {
+		name             string
+		provider         vcsutils.VcsProvider
+		strategy         string
+		responses        map[string]string
+		expectedReason   string
+		expectedRequests []string
+		expectedMergeErr string
+	}{
+		{
+			name:     "GitHub",
+			provider: vcsutils.GitHub,
+			strategy: SquashStrategy,
+			responses: map[string]string{
+				"GET /repos/jfrog/frogbot/pulls/7":                `{"mergeable_state":"clean","head":{"sha":"abc"}}`,
+				"GET /repos/jfrog/frogbot/commits/abc/check-runs": `{"check_runs":[{"name":"build","status":"completed","conclusion":"success"}]}`,
+				"GET /repos/jfrog/frogbot/commits/abc/status":     `{"statuses":[{"context":"ci/jenkins","state":"success"}]}`,
+			},
+			expectedRequests: []string{
+				`GET /repos/jfrog/frogbot/pulls/7 `,
+				`GET /repos/jfrog/frogbot/commits/abc/check-runs `,
+				`GET /repos/jfrog/frogbot/commits/abc/status `,
+				`PUT /repos/jfrog/frogbot/pulls/7/merge {"merge_method":"squash"}`,
+			},
+		},
+		{
+			name:     "GitHub failed checks",
+			provider: vcsutils.GitHub,
+			strategy: MergeStrategy,
+			responses: map[string]string{
+				"GET /repos/jfrog/frogbot/pulls/7":                `{"mergeable_state":"unstable","head":{"sha":"abc"}}`,
+				"GET /repos/jfrog/frogbot/commits/abc/check-runs": `{"check_runs":[{"name":"build","status":"completed","conclusion":"failure"},{"name":"lint","status":"in_progress"}]}`,
+			},
+			expectedReason: "the checks didn't pass: build",
+		},
+		{
+			name:     "GitHub without checks",
+			provider: vcsutils.GitHub,
+			strategy: MergeStrategy,
+			responses: map[string]string{
+				"GET /repos/jfrog/frogbot/pulls/7": `{"mergeable_state":"clean","head":{"sha":"abc"}}`,
+			},
+			expectedReason: "it has no checks",
+		},
+		{
+			name:     "GitLab",
+			provider: vcsutils.GitLab,
+			strategy: MergeStrategy,
+			responses: map[string]string{
+				"GET /projects/jfrog/frogbot/merge_requests/7": `{"detailed_merge_status":"mergeable","head_pipeline":{"status":"success"}}`,
+			},
+			expectedRequests: []string{
+				`GET /projects/jfrog/frogbot/merge_requests/7 `,
+				`PUT /projects/jfrog/frogbot/merge_requests/7/merge {"squash":false}`,
+			},
+		},
+		{
+			name:     "GitLab running pipeline",
+			provider: vcsutils.GitLab,
+			strategy: RebaseStrategy,
+			responses: map[string]string{
+				"GET /projects/jfrog/frogbot/merge_requests/7": `{"detailed_merge_status":"ci_still_running","head_pipeline":{"status":"running"}}`,
+			},
+			expectedReason: "the checks are still running: pipeline",
+		},
+		{
+			name:     "GitLab rebase",
+			provider: vcsutils.GitLab,
+			strategy: RebaseStrategy,
+			responses: map[string]string{
+				"GET /projects/jfrog/frogbot/merge_requests/7": `{"detailed_merge_status":"mergeable","head_pipeline":{"status":"success"}}`,
+			},
+			expectedRequests: []string{`GET /projects/jfrog/frogbot/merge_requests/7 `},
+			expectedMergeErr: "the rebase merge strategy isn't supported for GitLab, since the merge method is a setting of the project",
+		},
+		{
+			name:     "Bitbucket Server",
+			provider: vcsutils.BitbucketServer,
+			strategy: SquashStrategy,
+			responses: map[string]string{
+				"GET /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7":       `{"version":3,"fromRef":{"latestCommit":"abc"}}`,
+				"GET /rest/build-status/1.0/commits/stats/abc":                         `{"successful":2}`,
+				"GET /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7/merge": `{"canMerge":true}`,
+			},
+			expectedRequests: []string{
+				`GET /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7 `,
+				`GET /rest/build-status/1.0/commits/stats/abc `,
+				`GET /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7/merge `,
+				`GET /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7 `,
+				`POST /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7/merge {"strategyId":"squash"}`,
+			},
+		},
+		{
+			name:     "Bitbucket Server vetoes",
+			provider: vcsutils.BitbucketServer,
+			strategy: MergeStrategy,
+			responses: map[string]string{
+				"GET /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7":       `{"version":3,"fromRef":{"latestCommit":"abc"}}`,
+				"GET /rest/build-status/1.0/commits/stats/abc":                         `{"successful":2}`,
+				"GET /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7/merge": `{"canMerge":false,"vetoes":[{"summaryMessage":"Not enough approvals"}]}`,
+			},
+			expectedReason: "it can't be merged: Not enough approvals",
+		},
+		{
+			name:     "Bitbucket Cloud",
+			provider: vcsutils.BitbucketCloud,
+			strategy: RebaseStrategy,
+			responses: map[string]string{
+				"GET /repositories/jfrog/frogbot/pullrequests/7/statuses": `{"values":[{"name":"pipeline","state":"SUCCESSFUL"}]}`,
+			},
+			expectedRequests: []string{
+				`GET /repositories/jfrog/frogbot/pullrequests/7/statuses `,
+				`POST /repositories/jfrog/frogbot/pullrequests/7/merge {"merge_strategy":"rebase_merge"}`,
+			},
+		},
+		{
+			name:     "Azure Repos",
+			provider: vcsutils.AzureRepos,
+			strategy: MergeStrategy,
+			responses: map[string]string{
+				"GET /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7":          `{"mergeStatus":"succeeded","lastMergeSourceCommit":{"commitId":"abc"}}`,
+				"GET /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7/statuses": `{"value":[{"state":"succeeded","context":{"name":"build"}}]}`,
+			},
+			expectedRequests: []string{
+				`GET /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7 `,
+				`GET /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7/statuses `,
+				`GET /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7 `,
+				`PATCH /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7 {"completionOptions":{"deleteSourceBranch":true,"mergeStrategy":"noFastForward"},"lastMergeSourceCommit":{"commitId":"abc"},"status":"completed"}`,
+			},
+		},
+		{
+			name:     "Azure Repos conflicts",
+			provider: vcsutils.AzureRepos,
+			strategy: MergeStrategy,
+			responses: map[string]string{
+				"GET /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7":          `{"mergeStatus":"conflicts"}`,
+				"GET /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7/statuses": `{"value":[{"state":"succeeded","context":{"name":"build"}}]}`,
+			},
+			expectedReason: "its merge status is 'conflicts'",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+				_, err = w.Write([]byte(tc.responses[r.Method+" "+r.URL.Path]))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			merger, err := NewMerger(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token", Project: "frogbot-project"}, "jfrog", "frogbot")
+			require.NoError(t, err)
+			reason, err := merger.GetBlockingReason(7)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedReason, reason)
+			if reason != "" {
+				return
+			}
+			err = merger.Merge(7, tc.strategy)
+			if tc.expectedMergeErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedMergeErr)
+			}
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
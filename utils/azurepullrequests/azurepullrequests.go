@@ -0,0 +1,113 @@
+package azurepullrequests
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+const (
+	// The genre and the name of the status that Frogbot posts, which identify it among the statuses of the pull request
+	StatusGenre = "frogbot"
+	StatusName  = "security-scan"
+)
+
+// The state of a pull request status
+type StatusState string
+
+const (
+	Succeeded StatusState = "succeeded"
+	Failed    StatusState = "failed"
+	Error     StatusState = "error"
+)
+
+// The status of a pull request comment thread
+type ThreadStatus string
+
+const (
+	ActiveThread ThreadStatus = "active"
+	FixedThread  ThreadStatus = "fixed"
+)
+
+// The ID of the first comment of a thread, which opened the thread
+const firstThreadCommentId = 1
+
+type ThreadComment struct {
+	Content   string `json:"content"`
+	IsDeleted bool   `json:"isDeleted"`
+	Author    struct {
+		Id string `json:"id"`
+	} `json:"author"`
+}
+
+type Thread struct {
+	Id        int64           `json:"id"`
+	Status    ThreadStatus    `json:"status"`
+	IsDeleted bool            `json:"isDeleted"`
+	Comments  []ThreadComment `json:"comments"`
+}
+
+// Returns the content of the first comment of the thread, or an empty string if it was deleted
+func (t Thread) Content() string {
+	if t.IsDeleted || len(t.Comments) == 0 || t.Comments[0].IsDeleted {
+		return ""
+	}
+	return t.Comments[0].Content
+}
+
+// Client posts the statuses of Azure Repos pull requests and resolves their comment threads.
+// The Git clients don't expose the pull request statuses and the thread statuses, so they're requested from the Azure DevOps API.
+type Client struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func NewClient(vcsInfo vcsclient.VcsInfo, repoName string) *Client {
+	client := vcsapi.NewClient(vcsutils.AzureRepos, vcsInfo)
+	return &Client{client: client, pullRequestsUrl: client.RepositoryUrl("", repoName) + "/pullrequests"}
+}
+
+// Posts the Frogbot status of the pull request. A new status replaces the previous one in the checks of the pull request.
+func (c *Client) SetStatus(pullRequestId int, state StatusState, description string) error {
+	status := map[string]any{
+		"state":       state,
+		"description": description,
+		"context":     map[string]string{"genre": StatusGenre, "name": StatusName},
+	}
+	return c.client.Send(http.MethodPost, fmt.Sprintf("%s/%d/statuses?api-version=%s", c.pullRequestsUrl, pullRequestId, vcsapi.AzureApiVersion), status, nil)
+}
+
+// Returns the comment threads of the pull request
+func (c *Client) ListThreads(pullRequestId int) ([]Thread, error) {
+	var threads struct {
+		Value []Thread `json:"value"`
+	}
+	if err := c.client.Get(fmt.Sprintf("%s/%d/threads?api-version=%s", c.pullRequestsUrl, pullRequestId, vcsapi.AzureApiVersion), &threads); err != nil {
+		return nil, err
+	}
+	return threads.Value, nil
+}
+
+// Returns the ID of the user that the token belongs to
+func (c *Client) GetAuthenticatedUserId() (string, error) {
+	var connectionData struct {
+		AuthenticatedUser struct {
+			Id string `json:"id"`
+		} `json:"authenticatedUser"`
+	}
+	err := c.client.Get(c.client.Url("/_apis/connectionData"), &connectionData)
+	return connectionData.AuthenticatedUser.Id, err
+}
+
+func (c *Client) SetThreadStatus(pullRequestId int, threadId int64, status ThreadStatus) error {
+	return c.client.Send(http.MethodPatch, fmt.Sprintf("%s/%d/threads/%d?api-version=%s", c.pullRequestsUrl, pullRequestId, threadId, vcsapi.AzureApiVersion), map[string]ThreadStatus{"status": status}, nil)
+}
+
+// Replaces the content of the first comment of the thread
+func (c *Client) EditThreadComment(pullRequestId int, threadId int64, content string) error {
+	commentUrl := fmt.Sprintf("%s/%d/threads/%d/comments/%d?api-version=%s", c.pullRequestsUrl, pullRequestId, threadId, firstThreadCommentId, vcsapi.AzureApiVersion)
+	return c.client.Send(http.MethodPatch, commentUrl, map[string]string{"content": content}, nil)
+}
@@ -0,0 +1,78 @@
+package azurepullrequests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	client := NewClient(vcsclient.VcsInfo{APIEndpoint: "https://dev.azure.com/jfrog/", Token: "token", Project: "my project"}, "frogbot")
+	assert.Equal(t, "https://dev.azure.com/jfrog/my%20project/_apis/git/repositories/frogbot/pullrequests", client.pullRequestsUrl)
+}
+
+func TestClient(t *testing.T) {
+	var status, threadStatus, threadComment map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Empty(t, user)
+		assert.Equal(t, "token", password)
+		assert.Equal(t, vcsapi.AzureApiVersion, r.URL.Query().Get("api-version"))
+		switch r.URL.Path {
+		case "/jfrog/security/_apis/git/repositories/frogbot/pullrequests/5/statuses":
+			assert.Equal(t, http.MethodPost, r.Method)
+			readJsonBody(t, r, &status)
+			w.WriteHeader(http.StatusCreated)
+		case "/jfrog/security/_apis/git/repositories/frogbot/pullrequests/5/threads":
+			assert.Equal(t, http.MethodGet, r.Method)
+			_, err := w.Write([]byte(`{"value":[{"id":1,"status":"active","comments":[{"content":"first"},{"content":"reply"}]},{"id":2,"status":"fixed","comments":[{"content":"deleted","isDeleted":true}]}],"count":2}`))
+			assert.NoError(t, err)
+		case "/jfrog/security/_apis/git/repositories/frogbot/pullrequests/5/threads/1":
+			assert.Equal(t, http.MethodPatch, r.Method)
+			readJsonBody(t, r, &threadStatus)
+		case "/jfrog/security/_apis/git/repositories/frogbot/pullrequests/5/threads/1/comments/1":
+			assert.Equal(t, http.MethodPatch, r.Method)
+			readJsonBody(t, r, &threadComment)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(vcsclient.VcsInfo{APIEndpoint: server.URL + "/jfrog", Token: "token", Project: "security"}, "frogbot")
+
+	require.NoError(t, client.SetStatus(5, Failed, "Security issues were found"))
+	assert.Equal(t, map[string]any{
+		"state":       "failed",
+		"description": "Security issues were found",
+		"context":     map[string]any{"genre": StatusGenre, "name": StatusName},
+	}, status)
+
+	threads, err := client.ListThreads(5)
+	require.NoError(t, err)
+	require.Len(t, threads, 2)
+	assert.Equal(t, ActiveThread, threads[0].Status)
+	assert.Equal(t, "first", threads[0].Content())
+	assert.Empty(t, threads[1].Content())
+
+	require.NoError(t, client.SetThreadStatus(5, 1, FixedThread))
+	assert.Equal(t, map[string]any{"status": "fixed"}, threadStatus)
+
+	require.NoError(t, client.EditThreadComment(5, 1, "edited"))
+	assert.Equal(t, map[string]any{"content": "edited"}, threadComment)
+
+	assert.Error(t, client.SetThreadStatus(6, 1, FixedThread))
+}
+
+func readJsonBody(t *testing.T, r *http.Request, target any) {
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(body, target))
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils/azurepullrequests"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// On Azure Repos, the Frogbot review comment threads of the findings that no longer exist are resolved instead of being deleted,
+// so their discussions remain in the pull request. The resolved threads, including the threads that previous scans resolved, are kept when rescanning.
+func resolveFixedFindingsThreads(repo *Repository, pullRequestID int, reviewComments []ReviewComment, suppressions *PullRequestSuppressions) error {
+	client := azurepullrequests.NewClient(repo.VcsInfo, repo.RepoName)
+	threads, err := client.ListThreads(pullRequestID)
+	if err != nil {
+		return fmt.Errorf("couldn't list the pull request threads: %s", err.Error())
+	}
+	newFindingIds := datastructures.MakeSet[string]()
+	for _, comment := range reviewComments {
+		for _, findingId := range outputwriter.GetFindingIds(comment.CommentInfo.Content) {
+			newFindingIds.Add(findingId)
+		}
+	}
+	resolved := 0
+	for _, thread := range threads {
+		content := thread.Content()
+		findingIds := outputwriter.GetFindingIds(content)
+		if !outputwriter.IsFrogbotComment(content) || len(findingIds) == 0 {
+			continue
+		}
+		if thread.Status == azurepullrequests.ActiveThread {
+			if containsAny(newFindingIds, findingIds) {
+				continue
+			}
+			if err = client.SetThreadStatus(pullRequestID, thread.Id, azurepullrequests.FixedThread); err != nil {
+				return fmt.Errorf("couldn't resolve thread %d: %s", thread.Id, err.Error())
+			}
+			resolved++
+		}
+		suppressions.keepComments(thread.Id)
+	}
+	if resolved > 0 {
+		log.Info(fmt.Sprintf("Resolved %d pull request threads of findings that no longer exist", resolved))
+	}
+	return nil
+}
+
+func containsAny(set *datastructures.Set[string], values []string) bool {
+	for _, value := range values {
+		if set.Exists(value) {
+			return true
+		}
+	}
+	return false
+}
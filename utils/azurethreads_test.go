@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveFixedFindingsThreads(t *testing.T) {
+	frogbotContent := func(findingIds ...string) string {
+		return "finding details" + outputwriter.FindingIdsComment(findingIds...) + outputwriter.MarkdownComment(outputwriter.ReviewCommentId)
+	}
+	threads := map[string]any{"value": []map[string]any{
+		// The findings of the thread still exist
+		{"id": 1, "status": "active", "comments": []map[string]any{{"content": frogbotContent("sast-1")}}},
+		// The findings of the thread no longer exist
+		{"id": 2, "status": "active", "comments": []map[string]any{{"content": frogbotContent("iac-1", "iac-2")}}},
+		// Resolved by a previous scan
+		{"id": 3, "status": "fixed", "comments": []map[string]any{{"content": frogbotContent("secret-1")}}},
+		// Not a Frogbot thread, or the summary comment
+		{"id": 4, "status": "active", "comments": []map[string]any{{"content": "LGTM"}}},
+		{"id": 5, "status": "active", "comments": []map[string]any{{"content": outputwriter.MarkdownComment(outputwriter.ReviewCommentId)}}},
+	}}
+	var resolvedThreads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			resolvedThreads = append(resolvedThreads, r.URL.Path)
+			return
+		}
+		assert.Equal(t, "/jfrog/security/_apis/git/repositories/frogbot/pullrequests/7/threads", r.URL.Path)
+		assert.NoError(t, json.NewEncoder(w).Encode(threads))
+	}))
+	defer server.Close()
+	repo := &Repository{Params: Params{Git: Git{RepoName: "frogbot", VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL + "/jfrog", Project: "security"}}}}
+	reviewComments := []ReviewComment{{CommentInfo: vcsclient.PullRequestComment{CommentInfo: vcsclient.CommentInfo{Content: frogbotContent("sast-1")}}}}
+
+	suppressions := NewPullRequestSuppressions()
+	require.NoError(t, resolveFixedFindingsThreads(repo, 7, reviewComments, suppressions))
+	assert.Equal(t, []string{"/jfrog/security/_apis/git/repositories/frogbot/pullrequests/7/threads/2"}, resolvedThreads)
+	for id, kept := range map[int64]bool{1: false, 2: true, 3: true, 4: false, 5: false} {
+		assert.Equal(t, kept, suppressions.IsSuppressedFindingsComment(vcsclient.CommentInfo{ID: id}), "thread %d", id)
+	}
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The path of the baseline file in the repository
+const BaselineFilePath = frogbotConfigDir + "/baseline.json"
+
+// Baseline is an accepted snapshot of the findings of a repository.
+// When all the issues of a pull request are reported, the findings of the baseline are reported as ignored findings,
+// so only the findings that were added since the snapshot are shown.
+// The findings are identified by their finding IDs. Licenses don't have finding IDs, so they aren't part of the baseline.
+type Baseline struct {
+	Findings   []BaselineFinding `json:"findings"`
+	findingIds *datastructures.Set[string]
+}
+
+type BaselineFinding struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	// A readable description of the finding, which makes the changes of the baseline file easier to review
+	Summary string `json:"summary,omitempty"`
+}
+
+// Creates a baseline of all the SCA, IaC, Secrets and SAST issues of the collection
+func NewBaseline(issuesCollection *issues.ScansIssuesCollection) *Baseline {
+	baseline := &Baseline{Findings: []BaselineFinding{}, findingIds: datastructures.MakeSet[string]()}
+	if issuesCollection == nil {
+		return baseline
+	}
+	for _, issue := range slices.Concat(issuesCollection.ScaVulnerabilities, issuesCollection.ScaViolations) {
+		baseline.add(BaselineFinding{Id: issues.GetScaFindingId(issue), Type: "SCA", Summary: getScaFindingSummary(issue)})
+	}
+	for scanType, sourceCodeIssues := range map[string][]formats.SourceCodeRow{
+		"IaC":     slices.Concat(issuesCollection.IacVulnerabilities, issuesCollection.IacViolations),
+		"Secrets": slices.Concat(issuesCollection.SecretsVulnerabilities, issuesCollection.SecretsViolations),
+		"SAST":    slices.Concat(issuesCollection.SastVulnerabilities, issuesCollection.SastViolations),
+	} {
+		for _, issue := range sourceCodeIssues {
+			baseline.add(BaselineFinding{Id: issues.GetSourceCodeFindingId(issue), Type: scanType, Summary: getSourceCodeFindingSummary(issue)})
+		}
+	}
+	// The findings are sorted, so regenerating the baseline of the same findings doesn't change the file
+	slices.SortFunc(baseline.Findings, func(a, b BaselineFinding) int {
+		if typeOrder := strings.Compare(a.Type, b.Type); typeOrder != 0 {
+			return typeOrder
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return baseline
+}
+
+func (b *Baseline) add(finding BaselineFinding) {
+	if b.findingIds.Exists(finding.Id) {
+		return
+	}
+	b.findingIds.Add(finding.Id)
+	b.Findings = append(b.Findings, finding)
+}
+
+func getScaFindingSummary(issue formats.VulnerabilityOrViolationRow) string {
+	return fmt.Sprintf("%s in %s:%s", results.GetIssueIdentifier(issue.Cves, issue.IssueId, ", "), issue.ImpactedDependencyName, issue.ImpactedDependencyVersion)
+}
+
+func getSourceCodeFindingSummary(issue formats.SourceCodeRow) string {
+	description := issue.ScannerShortDescription
+	if description == "" {
+		description = issue.RuleId
+	}
+	return fmt.Sprintf("%s in %s", description, issue.File)
+}
+
+func ParseBaseline(content []byte) (*Baseline, error) {
+	baseline := &Baseline{findingIds: datastructures.MakeSet[string]()}
+	if err := json.Unmarshal(content, baseline); err != nil {
+		return nil, err
+	}
+	for _, finding := range baseline.Findings {
+		if finding.Id == "" {
+			return nil, fmt.Errorf("the finding '%s' has no ID", finding.Summary)
+		}
+		baseline.findingIds.Add(finding.Id)
+	}
+	return baseline, nil
+}
+
+// Writes the baseline to the given path, creating its directory if needed
+func (b *Baseline) Write(path string) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// Returns the baseline file of the repository, or nil if the repository has no baseline file.
+// The file is read from the target branch of the pull request, so a pull request can't accept its own findings.
+func GetPullRequestBaseline(repo *Repository, client vcsclient.VcsClient) (*Baseline, error) {
+	target := repo.PullRequestDetails.Target
+	content, statusCode, err := client.DownloadFileFromRepo(context.Background(), target.Owner, target.Repository, target.Name, BaselineFilePath)
+	if statusCode == http.StatusNotFound {
+		log.Debug(fmt.Sprintf("The %s file wasn't found in <%s/%s/%s>", BaselineFilePath, target.Owner, target.Repository, target.Name))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't download the %s file from <%s/%s/%s>: %s", BaselineFilePath, target.Owner, target.Repository, target.Name, err.Error())
+	}
+	baseline, err := ParseBaseline(content)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the %s file: %s", BaselineFilePath, err.Error())
+	}
+	log.Info(fmt.Sprintf("The %d findings of the %s file of the target branch aren't reported", len(baseline.Findings), BaselineFilePath))
+	return baseline, nil
+}
+
+// Moves the issues of the baseline to the ignored issues of the collection, so they aren't reported as findings
+func (b *Baseline) FilterIssues(issuesCollection *issues.ScansIssuesCollection) {
+	if b == nil || issuesCollection == nil || b.findingIds.Size() == 0 {
+		return
+	}
+	ignoreIssuesByFindingId(issuesCollection, b.findingIds)
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	log4j := formats.VulnerabilityOrViolationRow{
+		IssueId:                   "XRAY-191789",
+		Cves:                      []formats.CveRow{{Id: "CVE-2021-44228"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "org.apache.logging.log4j:log4j-core", ImpactedDependencyVersion: "2.14.1"},
+	}
+	lodash := formats.VulnerabilityOrViolationRow{
+		IssueId:                   "XRAY-1234",
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"},
+	}
+	sastFinding := formats.SourceCodeRow{ScannerInfo: formats.ScannerInfo{RuleId: "js-insecure-random", ScannerShortDescription: "Insecure Random"}, Location: formats.Location{File: "src/random.js"}}
+	secretFinding := formats.SourceCodeRow{ScannerInfo: formats.ScannerInfo{RuleId: "REQ.SECRET.KEYS"}, Location: formats.Location{File: "config/secrets.yml"}}
+
+	// The same vulnerability and violation are one finding
+	baseline := NewBaseline(&issues.ScansIssuesCollection{
+		ScaVulnerabilities:  []formats.VulnerabilityOrViolationRow{log4j},
+		ScaViolations:       []formats.VulnerabilityOrViolationRow{log4j},
+		SastVulnerabilities: []formats.SourceCodeRow{sastFinding},
+	})
+	assert.Equal(t, []BaselineFinding{
+		{Id: issues.GetSourceCodeFindingId(sastFinding), Type: "SAST", Summary: "Insecure Random in src/random.js"},
+		{Id: issues.GetScaFindingId(log4j), Type: "SCA", Summary: "CVE-2021-44228 in org.apache.logging.log4j:log4j-core:2.14.1"},
+	}, baseline.Findings)
+
+	// The baseline is read back from its file
+	baselinePath := filepath.Join(t.TempDir(), BaselineFilePath)
+	require.NoError(t, baseline.Write(baselinePath))
+	content, err := os.ReadFile(baselinePath)
+	require.NoError(t, err)
+	baseline, err = ParseBaseline(content)
+	require.NoError(t, err)
+
+	// Only the findings that were added since the baseline are reported
+	issuesCollection := &issues.ScansIssuesCollection{
+		ScaVulnerabilities:     []formats.VulnerabilityOrViolationRow{log4j, lodash},
+		SastVulnerabilities:    []formats.SourceCodeRow{sastFinding},
+		SecretsVulnerabilities: []formats.SourceCodeRow{secretFinding},
+	}
+	baseline.FilterIssues(issuesCollection)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{lodash}, issuesCollection.ScaVulnerabilities)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{log4j}, issuesCollection.IgnoredScaIssues)
+	assert.Empty(t, issuesCollection.SastVulnerabilities)
+	assert.Equal(t, []formats.SourceCodeRow{sastFinding}, issuesCollection.IgnoredSastIssues)
+	assert.Equal(t, []formats.SourceCodeRow{secretFinding}, issuesCollection.SecretsVulnerabilities)
+
+	// No baseline
+	var noBaseline *Baseline
+	assert.NotPanics(t, func() { noBaseline.FilterIssues(issuesCollection) })
+
+	_, err = ParseBaseline([]byte(`{"findings":[{"type":"SCA","summary":"CVE-2021-44228 in log4j-core:2.14.1"}]}`))
+	assert.ErrorContains(t, err, "the finding 'CVE-2021-44228 in log4j-core:2.14.1' has no ID")
+}
+
+func TestGetPullRequestBaseline(t *testing.T) {
+	repo := &Repository{Params: Params{Git: Git{PullRequestDetails: vcsclient.PullRequestInfo{Target: vcsclient.BranchInfo{Owner: "owner", Repository: "repo", Name: "main"}}}}}
+	testCases := []struct {
+		name               string
+		content            string
+		statusCode         int
+		downloadErr        error
+		expectedFindingIds []string
+		expectedError      string
+	}{
+		{name: "Baseline file", content: `{"findings":[{"id":"finding-1","type":"SCA"},{"id":"finding-2","type":"SAST"}]}`, statusCode: http.StatusOK, expectedFindingIds: []string{"finding-1", "finding-2"}},
+		{name: "No baseline file", statusCode: http.StatusNotFound, downloadErr: errors.New("not found")},
+		{name: "Invalid baseline file", content: `{"findings":`, statusCode: http.StatusOK, expectedError: "couldn't parse the .frogbot/baseline.json file"},
+		{name: "Failed download", statusCode: http.StatusInternalServerError, downloadErr: errors.New("server error"), expectedError: "couldn't download the .frogbot/baseline.json file"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := testdata.NewMockVcsClient(gomock.NewController(t))
+			client.EXPECT().DownloadFileFromRepo(context.Background(), "owner", "repo", "main", BaselineFilePath).Return([]byte(tc.content), tc.statusCode, tc.downloadErr)
+			baseline, err := GetPullRequestBaseline(repo, client)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectedFindingIds == nil {
+				assert.Nil(t, baseline)
+				return
+			}
+			assert.ElementsMatch(t, tc.expectedFindingIds, baseline.findingIds.ToSlice())
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package bazel
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayutils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// The technology of the vulnerabilities of the Bazel lockfiles, which are fixed by the Bazel package handler
+const Technology techutils.Technology = "bazel"
+
+// The root node of the scanned graph, whose child nodes are the dependencies of the lockfiles
+const rootNodeId = "frogbot-bazel"
+
+// XrayScanner runs Xray graph scans. The Xray services manager implements it.
+type XrayScanner interface {
+	ScanGraph(params services.XrayGraphScanParams) (scanId string, err error)
+	GetScanGraphResults(scanId, xrayVersion string, includeVulnerabilities, includeLicenses, xscEnabled bool) (*services.ScanResponse, error)
+}
+
+// Analyzer scans the Maven artifacts and the Go modules that the lockfiles of Bazel workspaces pin, with Xray.
+// The audit doesn't support Bazel, so the dependency graph is read from the lockfiles instead of being built by the package managers.
+type Analyzer struct {
+	scanner     XrayScanner
+	xrayVersion string
+	// The Xray IDs of the dependencies of the target branch. Their vulnerabilities aren't added by the pull request, so they aren't reported.
+	targetDependencies *datastructures.Set[string]
+	// The dependencies of lockfiles that were already scanned, so each vulnerable dependency is reported once for all the projects
+	reported *datastructures.Set[string]
+}
+
+// Returns nil if the scan of Bazel workspaces isn't enabled, which disables the analysis
+func NewAnalyzer(enabled bool, serverDetails *config.ServerDetails, xrayVersion string) (*Analyzer, error) {
+	if !enabled {
+		return nil, nil
+	}
+	xrayManager, err := xray.CreateXrayServiceManager(serverDetails)
+	if err != nil {
+		return nil, err
+	}
+	return newAnalyzer(xrayManager, xrayVersion), nil
+}
+
+func newAnalyzer(scanner XrayScanner, xrayVersion string) *Analyzer {
+	return &Analyzer{scanner: scanner, xrayVersion: xrayVersion, reported: datastructures.MakeSet[string]()}
+}
+
+// Sets the lockfiles of the target branch of a pull request, so only the dependencies that the pull request adds are scanned by the next analysis
+func (a *Analyzer) SetTargetBranch(dirs ...string) error {
+	if a == nil {
+		return nil
+	}
+	lockfiles, err := FindLockfiles(dirs...)
+	if err != nil {
+		return err
+	}
+	a.targetDependencies = datastructures.MakeSet[string]()
+	for _, lockfile := range lockfiles {
+		for _, dependency := range lockfile.Dependencies {
+			a.targetDependencies.Add(dependency.XrayId)
+		}
+	}
+	return nil
+}
+
+// Returns the vulnerabilities of the dependencies of the Bazel lockfiles in the directories.
+// The paths of the lockfiles are reported relative to the root directory of the repository.
+func (a *Analyzer) Analyze(rootDir string, dirs ...string) (vulnerabilities []formats.VulnerabilityOrViolationRow, err error) {
+	if a == nil {
+		return
+	}
+	targetDependencies := a.targetDependencies
+	a.targetDependencies = nil
+	lockfiles, err := FindLockfiles(dirs...)
+	if err != nil {
+		return
+	}
+	var lockfilesToScan []scannedLockfile
+	scannedIds := datastructures.MakeSet[string]()
+	for _, lockfile := range lockfiles {
+		if relativePath, e := filepath.Rel(rootDir, lockfile.Path); e == nil {
+			lockfile.Path = filepath.ToSlash(relativePath)
+		}
+		var dependenciesToScan []Dependency
+		for _, dependency := range lockfile.Dependencies {
+			key := lockfile.Path + "|" + dependency.XrayId
+			if a.reported.Exists(key) || (targetDependencies != nil && targetDependencies.Exists(dependency.XrayId)) {
+				continue
+			}
+			a.reported.Add(key)
+			scannedIds.Add(dependency.XrayId)
+			dependenciesToScan = append(dependenciesToScan, dependency)
+		}
+		if len(dependenciesToScan) > 0 {
+			lockfilesToScan = append(lockfilesToScan, scannedLockfile{Lockfile: lockfile, scanned: dependenciesToScan})
+		}
+	}
+	if scannedIds.Size() == 0 {
+		return
+	}
+	log.Info(fmt.Sprintf("Scanning %d dependencies of Bazel lockfiles...", scannedIds.Size()))
+	scanResponse, err := a.scan(scannedIds.ToSlice())
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan the dependencies of the Bazel lockfiles: %s", err.Error())
+	}
+	return getVulnerabilities(scanResponse, lockfilesToScan), nil
+}
+
+// The impact paths are built from all the dependencies of the lockfile, but only its scanned dependencies are reported
+type scannedLockfile struct {
+	Lockfile
+	scanned []Dependency
+}
+
+func (a *Analyzer) scan(xrayIds []string) (*services.ScanResponse, error) {
+	graph := &xrayutils.GraphNode{Id: rootNodeId}
+	for _, xrayId := range xrayIds {
+		graph.Nodes = append(graph.Nodes, &xrayutils.GraphNode{Id: xrayId})
+	}
+	scanId, err := a.scanner.ScanGraph(services.XrayGraphScanParams{
+		DependenciesGraph:      graph,
+		IncludeVulnerabilities: true,
+		ScanType:               services.Dependency,
+		XrayVersion:            a.xrayVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a.scanner.GetScanGraphResults(scanId, a.xrayVersion, true, false, false)
+}
+
+// Returns the vulnerabilities of the scanned dependencies of each lockfile, in the order of the lockfiles and their dependencies
+func getVulnerabilities(scanResponse *services.ScanResponse, lockfiles []scannedLockfile) (vulnerabilities []formats.VulnerabilityOrViolationRow) {
+	if scanResponse == nil {
+		return
+	}
+	for _, lockfile := range lockfiles {
+		for _, dependency := range lockfile.scanned {
+			for _, xrayVulnerability := range scanResponse.Vulnerabilities {
+				if impactedComponent, isImpacted := xrayVulnerability.Components[dependency.XrayId]; isImpacted {
+					vulnerabilities = append(vulnerabilities, toVulnerabilityRow(xrayVulnerability, impactedComponent, dependency, lockfile.Lockfile))
+				}
+			}
+		}
+	}
+	if len(vulnerabilities) > 0 {
+		log.Info(fmt.Sprintf("Found %d vulnerabilities in the dependencies of Bazel lockfiles", len(vulnerabilities)))
+	}
+	return
+}
+
+// The impact path starts with the lockfile, so the dependencies that are pinned directly are direct dependencies of the lockfile
+func toVulnerabilityRow(xrayVulnerability services.Vulnerability, impactedComponent services.Component, dependency Dependency, lockfile Lockfile) formats.VulnerabilityOrViolationRow {
+	versions := map[string]string{}
+	for _, lockfileDependency := range lockfile.Dependencies {
+		versions[lockfileDependency.Name] = lockfileDependency.Version
+	}
+	impactPath := []formats.ComponentRow{{Name: lockfile.Path}}
+	for _, name := range lockfile.ImpactPath(dependency.Name) {
+		impactPath = append(impactPath, formats.ComponentRow{Name: name, Version: versions[name], Location: &formats.Location{File: lockfile.Path}})
+	}
+	row := formats.VulnerabilityOrViolationRow{
+		Summary: xrayVulnerability.Summary,
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           severityutils.GetAsDetails(severityutils.GetSeverity(xrayVulnerability.Severity), jasutils.NotScanned, false),
+			ImpactedDependencyName:    dependency.Name,
+			ImpactedDependencyVersion: dependency.Version,
+			ImpactedDependencyType:    dependency.Tech.ToFormal(),
+			// The dependency that is pinned directly brings in the vulnerable dependency
+			Components: []formats.ComponentRow{impactPath[1]},
+		},
+		FixedVersions: impactedComponent.FixedVersions,
+		IssueId:       xrayVulnerability.IssueId,
+		References:    xrayVulnerability.References,
+		ImpactPaths:   [][]formats.ComponentRow{impactPath},
+		Technology:    Technology,
+	}
+	for _, cve := range xrayVulnerability.Cves {
+		if cve.Id != "" {
+			row.Cves = append(row.Cves, formats.CveRow{Id: cve.Id})
+		}
+	}
+	return row
+}
@@ -0,0 +1,86 @@
+package bazel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockXrayScanner struct {
+	scannedIds []string
+	response   *services.ScanResponse
+}
+
+func (ms *mockXrayScanner) ScanGraph(params services.XrayGraphScanParams) (string, error) {
+	ms.scannedIds = nil
+	for _, node := range params.DependenciesGraph.Nodes {
+		ms.scannedIds = append(ms.scannedIds, node.Id)
+	}
+	return "scan-id", nil
+}
+
+func (ms *mockXrayScanner) GetScanGraphResults(string, string, bool, bool, bool) (*services.ScanResponse, error) {
+	return ms.response, nil
+}
+
+func TestAnalyzer(t *testing.T) {
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	mavenInstall, err := os.ReadFile(filepath.Join(testWorkspaceDir, MavenInstallFile))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "java"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "java", MavenInstallFile), mavenInstall, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(targetDir, MavenInstallFile), []byte(`{"artifacts":{"org.yaml:snakeyaml":{"version":"1.33"}}}`), 0644))
+	scanner := &mockXrayScanner{response: &services.ScanResponse{Vulnerabilities: []services.Vulnerability{
+		{IssueId: "XRAY-1", Severity: "High", Summary: "Improper input validation", Cves: []services.Cve{{Id: "CVE-2022-1471"}}, Components: map[string]services.Component{"gav://org.yaml:snakeyaml:1.33": {FixedVersions: []string{"[2.0]"}}}},
+		{IssueId: "XRAY-2", Severity: "Medium", Components: map[string]services.Component{"gav://com.google.guava:failureaccess:1.0.1": {}}},
+	}}}
+
+	// Only the dependencies that the pull request adds are scanned
+	analyzer := newAnalyzer(scanner, "3.107.0")
+	require.NoError(t, analyzer.SetTargetBranch(targetDir))
+	vulnerabilities, err := analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"gav://com.google.guava:failureaccess:1.0.1", "gav://com.google.guava:guava:31.1-jre"}, scanner.scannedIds)
+	require.Len(t, vulnerabilities, 1)
+	// The transitive dependency is brought in by the pinned artifact
+	assert.Equal(t, [][]formats.ComponentRow{{
+		{Name: "java/maven_install.json"},
+		{Name: "com.google.guava:guava", Version: "31.1-jre", Location: &formats.Location{File: "java/maven_install.json"}},
+		{Name: "com.google.guava:failureaccess", Version: "1.0.1", Location: &formats.Location{File: "java/maven_install.json"}},
+	}}, vulnerabilities[0].ImpactPaths)
+	assert.Equal(t, "com.google.guava:guava", vulnerabilities[0].Components[0].Name)
+	assert.Equal(t, "Maven", vulnerabilities[0].ImpactedDependencyType)
+	assert.Equal(t, Technology, vulnerabilities[0].Technology)
+
+	// All the dependencies are scanned without a target branch
+	analyzer = newAnalyzer(scanner, "3.107.0")
+	vulnerabilities, err = analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.Len(t, scanner.scannedIds, 3)
+	require.Len(t, vulnerabilities, 2)
+	assert.Equal(t, "XRAY-2", vulnerabilities[0].IssueId)
+	assert.Equal(t, "org.yaml:snakeyaml", vulnerabilities[1].ImpactedDependencyName)
+	assert.Equal(t, "High", vulnerabilities[1].Severity)
+	assert.Equal(t, "Improper input validation", vulnerabilities[1].Summary)
+	assert.Equal(t, []string{"[2.0]"}, vulnerabilities[1].FixedVersions)
+	assert.Equal(t, []formats.CveRow{{Id: "CVE-2022-1471"}}, vulnerabilities[1].Cves)
+	assert.Len(t, vulnerabilities[1].ImpactPaths[0], 2)
+
+	// The dependencies are reported once for all the projects
+	scanner.scannedIds = nil
+	vulnerabilities, err = analyzer.Analyze(sourceDir, filepath.Join(sourceDir, "java"))
+	require.NoError(t, err)
+	assert.Empty(t, scanner.scannedIds)
+	assert.Empty(t, vulnerabilities)
+
+	// The analysis is disabled
+	var disabled *Analyzer
+	vulnerabilities, err = disabled.Analyze(sourceDir, sourceDir)
+	assert.NoError(t, err)
+	assert.Empty(t, vulnerabilities)
+}
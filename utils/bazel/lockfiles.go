@@ -0,0 +1,267 @@
+package bazel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+)
+
+const (
+	// The lockfile of rules_jvm_external, which pins the Maven artifacts of the workspace
+	MavenInstallFile = "maven_install.json"
+	// The lockfile of Bzlmod, which includes the Go modules that the go_deps extension of Gazelle resolves
+	ModuleLockFile = "MODULE.bazel.lock"
+	// The file of the Bzlmod module, which declares the versions of the Maven artifacts that are pinned
+	ModuleFile = "MODULE.bazel"
+	// The Starlark macros files, such as go_deps.bzl, that declare the Go modules of a WORKSPACE with go_repository rules
+	macrosFileExtension = ".bzl"
+)
+
+var (
+	// Matches the go_repository rules of the Starlark macros files
+	goRepositoryRegex = regexp.MustCompile(`(?s)go_repository\((.*?)\n\s*\)`)
+	// Matches a string attribute of a Starlark rule, such as: version = "v1.2.3",
+	stringAttributeRegex = regexp.MustCompile(`(?m)^\s*(\w+)\s*=\s*"([^"]*)"`)
+	// The directories that don't contain the lockfiles of the workspace. The output directories of Bazel start with 'bazel-'.
+	skippedDirs = []string{".git", "node_modules", "vendor"}
+)
+
+// Dependency is a Maven artifact or a Go module that a lockfile of a Bazel workspace pins
+type Dependency struct {
+	// techutils.Maven or techutils.Go
+	Tech    techutils.Technology
+	Name    string
+	Version string
+	// The Xray component ID, such as 'gav://com.google.guava:guava:31.1-jre' or 'go://github.com/pkg/errors:v0.9.1'
+	XrayId string
+	// The names of the dependencies of the dependency, in the same lockfile
+	Dependencies []string
+}
+
+// Lockfile holds the dependencies that a lockfile of a Bazel workspace pins
+type Lockfile struct {
+	Path         string
+	Dependencies []Dependency
+}
+
+// Returns true for the lockfiles that are parsed: maven_install.json, MODULE.bazel.lock and the Starlark macros files
+func isLockfileCandidate(fileName string) bool {
+	return fileName == MavenInstallFile || fileName == ModuleLockFile || strings.HasSuffix(fileName, macrosFileExtension)
+}
+
+// Returns the lockfiles of the Bazel workspaces in the directories and their subdirectories that pin any dependencies
+func FindLockfiles(dirs ...string) (lockfiles []Lockfile, err error) {
+	visited := map[string]bool{}
+	for _, dir := range dirs {
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, innerErr error) error {
+			if innerErr != nil {
+				return innerErr
+			}
+			if d.IsDir() {
+				if path != dir && (slices.Contains(skippedDirs, d.Name()) || strings.HasPrefix(d.Name(), "bazel-")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			// The working directories of a project may be nested
+			if !isLockfileCandidate(d.Name()) || visited[path] {
+				return nil
+			}
+			visited[path] = true
+			dependencies, e := ParseLockfile(path)
+			if e != nil {
+				return e
+			}
+			if len(dependencies) > 0 {
+				lockfiles = append(lockfiles, Lockfile{Path: path, Dependencies: dependencies})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for Bazel lockfiles in '%s': %s", dir, err.Error())
+		}
+	}
+	return
+}
+
+// Returns the dependencies that the lockfile pins, according to its name
+func ParseLockfile(path string) ([]Dependency, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch name := filepath.Base(path); {
+	case name == MavenInstallFile:
+		return parseMavenInstall(content)
+	case name == ModuleLockFile:
+		return parseModuleLock(content)
+	default:
+		return parseGoRepositories(content), nil
+	}
+}
+
+// Parses the artifacts of maven_install.json. Both the v2 format, that lists the artifacts and their dependencies in separate maps,
+// and the dependency tree of the v1 format are supported. Artifacts with a classifier, such as sources, are pinned with the artifact.
+func parseMavenInstall(content []byte) (dependencies []Dependency, err error) {
+	var lockfile struct {
+		Artifacts map[string]struct {
+			Version string `json:"version"`
+		} `json:"artifacts"`
+		Dependencies   map[string][]string `json:"dependencies"`
+		DependencyTree struct {
+			Dependencies []struct {
+				Coord        string   `json:"coord"`
+				Dependencies []string `json:"dependencies"`
+			} `json:"dependencies"`
+		} `json:"dependency_tree"`
+	}
+	if err = json.Unmarshal(content, &lockfile); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", MavenInstallFile, err.Error())
+	}
+	byName := map[string]*Dependency{}
+	addArtifact := func(name, version string, artifactDependencies []string) {
+		if version == "" || byName[name] != nil {
+			return
+		}
+		byName[name] = &Dependency{Tech: techutils.Maven, Name: name, Version: version, XrayId: fmt.Sprintf("gav://%s:%s", name, version), Dependencies: artifactDependencies}
+	}
+	for key, artifact := range lockfile.Artifacts {
+		// The keys of the artifacts with a classifier have more parts than 'group:artifact'
+		if strings.Count(key, ":") == 1 {
+			addArtifact(key, artifact.Version, lockfile.Dependencies[key])
+		}
+	}
+	for _, artifact := range lockfile.DependencyTree.Dependencies {
+		name, version, ok := splitMavenCoordinate(artifact.Coord)
+		if !ok {
+			continue
+		}
+		var artifactDependencies []string
+		for _, coordinate := range artifact.Dependencies {
+			if dependencyName, _, isValid := splitMavenCoordinate(coordinate); isValid {
+				artifactDependencies = append(artifactDependencies, dependencyName)
+			}
+		}
+		addArtifact(name, version, artifactDependencies)
+	}
+	return sortedDependencies(byName), nil
+}
+
+// Splits a 'group:artifact:version' coordinate, or a 'group:artifact:packaging:classifier:version' coordinate, which is skipped
+func splitMavenCoordinate(coordinate string) (name, version string, ok bool) {
+	parts := strings.Split(coordinate, ":")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	return parts[0] + ":" + parts[1], parts[2], true
+}
+
+// Parses the Go modules that the go_deps extension resolves in MODULE.bazel.lock.
+// The repositories of the extension are generated with the import path and the version of their modules.
+func parseModuleLock(content []byte) ([]Dependency, error) {
+	var lockfile struct {
+		ModuleExtensions map[string]any `json:"moduleExtensions"`
+	}
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", ModuleLockFile, err.Error())
+	}
+	byName := map[string]*Dependency{}
+	for extension, details := range lockfile.ModuleExtensions {
+		if strings.HasSuffix(extension, "%go_deps") {
+			addGoModules(details, byName)
+		}
+	}
+	return sortedDependencies(byName), nil
+}
+
+// Adds the Go modules of the objects with 'importpath' and 'version' attributes in the JSON value
+func addGoModules(value any, byName map[string]*Dependency) {
+	switch typed := value.(type) {
+	case map[string]any:
+		importPath, _ := typed["importpath"].(string)
+		version, _ := typed["version"].(string)
+		if importPath != "" && version != "" {
+			addGoModule(importPath, version, byName)
+			return
+		}
+		for _, child := range typed {
+			addGoModules(child, byName)
+		}
+	case []any:
+		for _, child := range typed {
+			addGoModules(child, byName)
+		}
+	}
+}
+
+// Parses the go_repository rules of a Starlark macros file, such as the go_deps.bzl file that Gazelle generates
+func parseGoRepositories(content []byte) []Dependency {
+	byName := map[string]*Dependency{}
+	for _, rule := range goRepositoryRegex.FindAllStringSubmatch(string(content), -1) {
+		attributes := map[string]string{}
+		for _, attribute := range stringAttributeRegex.FindAllStringSubmatch(rule[1], -1) {
+			attributes[attribute[1]] = attribute[2]
+		}
+		if attributes["importpath"] != "" && attributes["version"] != "" {
+			addGoModule(attributes["importpath"], attributes["version"], byName)
+		}
+	}
+	return sortedDependencies(byName)
+}
+
+func addGoModule(importPath, version string, byName map[string]*Dependency) {
+	if byName[importPath] == nil {
+		byName[importPath] = &Dependency{Tech: techutils.Go, Name: importPath, Version: version, XrayId: fmt.Sprintf("go://%s:%s", importPath, version)}
+	}
+}
+
+func sortedDependencies(byName map[string]*Dependency) (dependencies []Dependency) {
+	for _, dependency := range byName {
+		dependencies = append(dependencies, *dependency)
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].Name < dependencies[j].Name
+	})
+	return
+}
+
+// Returns the shortest path of dependency names from a dependency that no other dependency of the lockfile brings in, to the dependency.
+// The dependencies that nothing brings in are pinned directly, so their paths include themselves only.
+func (l Lockfile) ImpactPath(name string) []string {
+	parents := map[string][]string{}
+	for _, dependency := range l.Dependencies {
+		for _, child := range dependency.Dependencies {
+			parents[child] = append(parents[child], dependency.Name)
+		}
+	}
+	// Search upwards from the dependency to the nearest dependency without parents
+	previous := map[string]string{name: ""}
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if len(parents[current]) == 0 {
+			var path []string
+			for node := current; node != ""; node = previous[node] {
+				path = append(path, node)
+			}
+			return path
+		}
+		for _, parent := range parents[current] {
+			if _, seen := previous[parent]; !seen {
+				previous[parent] = current
+				queue = append(queue, parent)
+			}
+		}
+	}
+	// The dependency is brought in by a cycle only
+	return []string{name}
+}
@@ -0,0 +1,75 @@
+package bazel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWorkspaceDir = filepath.Join("..", "..", "testdata", "projects", "bazel")
+
+func TestFindLockfiles(t *testing.T) {
+	lockfiles, err := FindLockfiles(testWorkspaceDir, testWorkspaceDir)
+	require.NoError(t, err)
+	require.Len(t, lockfiles, 3)
+
+	// The go_deps extension of MODULE.bazel.lock. The repositories of the other extensions aren't Go modules.
+	assert.Equal(t, filepath.Join(testWorkspaceDir, ModuleLockFile), lockfiles[0].Path)
+	assert.Equal(t, []Dependency{
+		{Tech: techutils.Go, Name: "github.com/pkg/errors", Version: "v0.9.1", XrayId: "go://github.com/pkg/errors:v0.9.1"},
+		{Tech: techutils.Go, Name: "golang.org/x/net", Version: "v0.17.0", XrayId: "go://golang.org/x/net:v0.17.0"},
+	}, lockfiles[0].Dependencies)
+
+	// Gazelle's go_deps.bzl
+	assert.Equal(t, filepath.Join(testWorkspaceDir, "go_deps.bzl"), lockfiles[1].Path)
+	assert.Equal(t, []Dependency{
+		{Tech: techutils.Go, Name: "github.com/pkg/errors", Version: "v0.9.1", XrayId: "go://github.com/pkg/errors:v0.9.1"},
+		{Tech: techutils.Go, Name: "golang.org/x/text", Version: "v0.13.0", XrayId: "go://golang.org/x/text:v0.13.0"},
+	}, lockfiles[1].Dependencies)
+
+	// The artifacts with a classifier are pinned with their artifacts
+	assert.Equal(t, filepath.Join(testWorkspaceDir, MavenInstallFile), lockfiles[2].Path)
+	assert.Equal(t, []Dependency{
+		{Tech: techutils.Maven, Name: "com.google.guava:failureaccess", Version: "1.0.1", XrayId: "gav://com.google.guava:failureaccess:1.0.1"},
+		{Tech: techutils.Maven, Name: "com.google.guava:guava", Version: "31.1-jre", XrayId: "gav://com.google.guava:guava:31.1-jre", Dependencies: []string{"com.google.guava:failureaccess"}},
+		{Tech: techutils.Maven, Name: "org.yaml:snakeyaml", Version: "1.33", XrayId: "gav://org.yaml:snakeyaml:1.33"},
+	}, lockfiles[2].Dependencies)
+}
+
+func TestParseMavenInstallV1(t *testing.T) {
+	lockfile := filepath.Join(t.TempDir(), MavenInstallFile)
+	require.NoError(t, os.WriteFile(lockfile, []byte(`{"dependency_tree":{"dependencies":[
+		{"coord":"com.google.guava:guava:31.1-jre","dependencies":["com.google.guava:failureaccess:1.0.1"]},
+		{"coord":"com.google.guava:failureaccess:1.0.1","dependencies":[]},
+		{"coord":"com.google.guava:guava:jar:sources:31.1-jre","dependencies":[]}
+	]}}`), 0644))
+	dependencies, err := ParseLockfile(lockfile)
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{
+		{Tech: techutils.Maven, Name: "com.google.guava:failureaccess", Version: "1.0.1", XrayId: "gav://com.google.guava:failureaccess:1.0.1"},
+		{Tech: techutils.Maven, Name: "com.google.guava:guava", Version: "31.1-jre", XrayId: "gav://com.google.guava:guava:31.1-jre", Dependencies: []string{"com.google.guava:failureaccess"}},
+	}, dependencies)
+
+	require.NoError(t, os.WriteFile(lockfile, []byte("not json"), 0644))
+	_, err = ParseLockfile(lockfile)
+	assert.ErrorContains(t, err, "failed to parse maven_install.json")
+}
+
+func TestImpactPath(t *testing.T) {
+	lockfile := Lockfile{Dependencies: []Dependency{
+		{Name: "a", Dependencies: []string{"b", "c"}},
+		{Name: "b", Dependencies: []string{"d"}},
+		{Name: "c", Dependencies: []string{"d"}},
+		{Name: "d"},
+		{Name: "e", Dependencies: []string{"f"}},
+		{Name: "f", Dependencies: []string{"e"}},
+	}}
+	assert.Equal(t, []string{"a"}, lockfile.ImpactPath("a"))
+	assert.Equal(t, []string{"a", "b", "d"}, lockfile.ImpactPath("d"))
+	// The dependencies that only a cycle brings in are considered pinned
+	assert.Equal(t, []string{"e"}, lockfile.ImpactPath("e"))
+}
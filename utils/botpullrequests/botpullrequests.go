@@ -0,0 +1,196 @@
+package botpullrequests
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// PullRequest is an open pull request of the repository, with the author that the Git clients don't expose
+type PullRequest struct {
+	ID    int64
+	Title string
+	Url   string
+	// The username of the author, and its display name on the Git providers that have one
+	Author            string
+	AuthorDisplayName string
+}
+
+// Lister lists the open pull requests of a repository, with their authors, from the API of the Git provider
+type Lister interface {
+	ListOpen() ([]PullRequest, error)
+}
+
+// Returns the open pull requests lister of the Git provider
+func NewLister(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName string) (Lister, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	repositoryUrl := client.RepositoryUrl(repoOwner, repoName)
+	switch provider {
+	case vcsutils.GitHub:
+		return &gitHubLister{client: client, pullRequestsUrl: repositoryUrl + "/pulls"}, nil
+	case vcsutils.GitLab:
+		return &gitLabLister{client: client, mergeRequestsUrl: repositoryUrl + "/merge_requests"}, nil
+	case vcsutils.BitbucketServer:
+		return &bitbucketServerLister{client: client, pullRequestsUrl: repositoryUrl + "/pull-requests"}, nil
+	case vcsutils.BitbucketCloud:
+		return &bitbucketCloudLister{client: client, pullRequestsUrl: repositoryUrl + "/pullrequests"}, nil
+	case vcsutils.AzureRepos:
+		return &azureReposLister{client: client, pullRequestsUrl: repositoryUrl + "/pullrequests"}, nil
+	default:
+		return nil, fmt.Errorf("listing the pull requests of other bots isn't supported for %s", provider.String())
+	}
+}
+
+// Returns the first pull request of one of the authors that bumps the package, or nil if there is none.
+// Dependabot and Renovate name the bumped package in the titles of their pull requests, such as
+// "Bump lodash from 4.17.20 to 4.17.21" and "Update dependency lodash to v4.17.21".
+func FindPackagePullRequest(pullRequests []PullRequest, authors []string, packageName string) *PullRequest {
+	if packageName == "" {
+		return nil
+	}
+	// The package name must not be a part of a longer name, such as 'lodash' of 'lodash-es'
+	packageRegex := regexp.MustCompile(`(?i)(^|[\s'"` + "`" + `(\[])` + regexp.QuoteMeta(packageName) + `($|[\s'"` + "`" + `)\]@,])`)
+	for i, pullRequest := range pullRequests {
+		if isAuthoredByOneOf(pullRequest, authors) && packageRegex.MatchString(pullRequest.Title) {
+			return &pullRequests[i]
+		}
+	}
+	return nil
+}
+
+func isAuthoredByOneOf(pullRequest PullRequest, authors []string) bool {
+	for _, author := range authors {
+		if strings.EqualFold(author, pullRequest.Author) || (pullRequest.AuthorDisplayName != "" && strings.EqualFold(author, pullRequest.AuthorDisplayName)) {
+			return true
+		}
+	}
+	return false
+}
+
+type gitHubLister struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (gl *gitHubLister) ListOpen() (pullRequests []PullRequest, err error) {
+	response, err := vcsapi.List[struct {
+		Number  int64  `json:"number"`
+		Title   string `json:"title"`
+		HtmlUrl string `json:"html_url"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}](gl.client, gl.pullRequestsUrl+"?state=open", vcsapi.PageSize)
+	for _, pullRequest := range response {
+		pullRequests = append(pullRequests, PullRequest{ID: pullRequest.Number, Title: pullRequest.Title, Url: pullRequest.HtmlUrl, Author: pullRequest.User.Login})
+	}
+	return
+}
+
+type gitLabLister struct {
+	client           *vcsapi.Client
+	mergeRequestsUrl string
+}
+
+func (gl *gitLabLister) ListOpen() (pullRequests []PullRequest, err error) {
+	response, err := vcsapi.List[struct {
+		Iid    int64  `json:"iid"`
+		Title  string `json:"title"`
+		WebUrl string `json:"web_url"`
+		Author struct {
+			Username string `json:"username"`
+			Name     string `json:"name"`
+		} `json:"author"`
+	}](gl.client, gl.mergeRequestsUrl+"?state=opened", vcsapi.PageSize)
+	for _, mergeRequest := range response {
+		pullRequests = append(pullRequests, PullRequest{ID: mergeRequest.Iid, Title: mergeRequest.Title, Url: mergeRequest.WebUrl, Author: mergeRequest.Author.Username, AuthorDisplayName: mergeRequest.Author.Name})
+	}
+	return
+}
+
+type bitbucketServerLister struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (bs *bitbucketServerLister) ListOpen() (pullRequests []PullRequest, err error) {
+	response, err := vcsapi.List[struct {
+		Id     int64  `json:"id"`
+		Title  string `json:"title"`
+		Author struct {
+			User struct {
+				Name        string `json:"name"`
+				DisplayName string `json:"displayName"`
+			} `json:"user"`
+		} `json:"author"`
+		Links struct {
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+	}](bs.client, bs.pullRequestsUrl+"?state=OPEN", vcsapi.PageSize)
+	for _, pullRequest := range response {
+		var pullRequestUrl string
+		if len(pullRequest.Links.Self) > 0 {
+			pullRequestUrl = pullRequest.Links.Self[0].Href
+		}
+		pullRequests = append(pullRequests, PullRequest{ID: pullRequest.Id, Title: pullRequest.Title, Url: pullRequestUrl, Author: pullRequest.Author.User.Name, AuthorDisplayName: pullRequest.Author.User.DisplayName})
+	}
+	return
+}
+
+type bitbucketCloudLister struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (bc *bitbucketCloudLister) ListOpen() (pullRequests []PullRequest, err error) {
+	response, err := vcsapi.List[struct {
+		Id     int64  `json:"id"`
+		Title  string `json:"title"`
+		Author struct {
+			Nickname    string `json:"nickname"`
+			DisplayName string `json:"display_name"`
+		} `json:"author"`
+		Links struct {
+			Html struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}](bc.client, bc.pullRequestsUrl+"?state=OPEN", vcsapi.BitbucketCloudPullRequestsPageSize)
+	for _, pullRequest := range response {
+		pullRequests = append(pullRequests, PullRequest{ID: pullRequest.Id, Title: pullRequest.Title, Url: pullRequest.Links.Html.Href, Author: pullRequest.Author.Nickname, AuthorDisplayName: pullRequest.Author.DisplayName})
+	}
+	return
+}
+
+type azureReposLister struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (ac *azureReposLister) ListOpen() (pullRequests []PullRequest, err error) {
+	response, err := vcsapi.List[struct {
+		PullRequestId int64  `json:"pullRequestId"`
+		Title         string `json:"title"`
+		CreatedBy     struct {
+			UniqueName  string `json:"uniqueName"`
+			DisplayName string `json:"displayName"`
+		} `json:"createdBy"`
+	}](ac.client, fmt.Sprintf("%s?searchCriteria.status=active&api-version=%s", ac.pullRequestsUrl, vcsapi.AzureApiVersion), vcsapi.PageSize)
+	for _, pullRequest := range response {
+		pullRequests = append(pullRequests, PullRequest{
+			ID:    pullRequest.PullRequestId,
+			Title: pullRequest.Title,
+			// The API returns the URL of the pull request resource, so the URL of its web page is built from the repository URL
+			Url:               fmt.Sprintf("%s/%d", strings.Replace(ac.pullRequestsUrl, "/_apis/git/repositories/", "/_git/", 1), pullRequest.PullRequestId),
+			Author:            pullRequest.CreatedBy.UniqueName,
+			AuthorDisplayName: pullRequest.CreatedBy.DisplayName,
+		})
+	}
+	return
+}
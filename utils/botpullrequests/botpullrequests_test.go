@@ -0,0 +1,107 @@
+package botpullrequests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOpen(t *testing.T) {
+	testCases := []struct {
+		name      string
+		provider  vcsutils.VcsProvider
+		responses map[string]string
+		expected  []PullRequest
+	}{
+		{
+			name:      "GitHub",
+			provider:  vcsutils.GitHub,
+			responses: map[string]string{"/repos/jfrog/frogbot/pulls": `[{"number":7,"title":"Bump lodash from 4.17.20 to 4.17.21","html_url":"https://github.com/jfrog/frogbot/pull/7","user":{"login":"dependabot[bot]"}}]`},
+			expected:  []PullRequest{{ID: 7, Title: "Bump lodash from 4.17.20 to 4.17.21", Url: "https://github.com/jfrog/frogbot/pull/7", Author: "dependabot[bot]"}},
+		},
+		{
+			name:      "GitLab",
+			provider:  vcsutils.GitLab,
+			responses: map[string]string{"/projects/jfrog/frogbot/merge_requests": `[{"iid":7,"title":"Update dependency lodash to v4.17.21","web_url":"https://gitlab.com/jfrog/frogbot/-/merge_requests/7","author":{"username":"renovate-bot","name":"Renovate Bot"}}]`},
+			expected:  []PullRequest{{ID: 7, Title: "Update dependency lodash to v4.17.21", Url: "https://gitlab.com/jfrog/frogbot/-/merge_requests/7", Author: "renovate-bot", AuthorDisplayName: "Renovate Bot"}},
+		},
+		{
+			name:     "Bitbucket Server",
+			provider: vcsutils.BitbucketServer,
+			responses: map[string]string{
+				"/rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests": `{"values":[{"id":7,"title":"Update dependency lodash to v4.17.21","author":{"user":{"name":"renovate","displayName":"Renovate"}},"links":{"self":[{"href":"https://bitbucket.example.com/pr/7"}]}}],"isLastPage":true}`,
+			},
+			expected: []PullRequest{{ID: 7, Title: "Update dependency lodash to v4.17.21", Url: "https://bitbucket.example.com/pr/7", Author: "renovate", AuthorDisplayName: "Renovate"}},
+		},
+		{
+			name:     "Bitbucket Cloud",
+			provider: vcsutils.BitbucketCloud,
+			responses: map[string]string{
+				"/repositories/jfrog/frogbot/pullrequests": `{"values":[{"id":7,"title":"Bump lodash","author":{"nickname":"renovate","display_name":"Renovate Bot"},"links":{"html":{"href":"https://bitbucket.org/jfrog/frogbot/pull-requests/7"}}}],"next":"{server}/page/2"}`,
+				"/page/2": `{"values":[{"id":8,"title":"Fix typo","author":{"nickname":"frogbot"}}]}`,
+			},
+			expected: []PullRequest{
+				{ID: 7, Title: "Bump lodash", Url: "https://bitbucket.org/jfrog/frogbot/pull-requests/7", Author: "renovate", AuthorDisplayName: "Renovate Bot"},
+				{ID: 8, Title: "Fix typo", Author: "frogbot"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response, exists := tc.responses[r.URL.Path]
+				if !exists {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, err := w.Write([]byte(strings.ReplaceAll(response, "{server}", server.URL)))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			lister, err := NewLister(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "jfrog", "frogbot")
+			require.NoError(t, err)
+			pullRequests, err := lister.ListOpen()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, pullRequests)
+		})
+	}
+}
+
+func TestFindPackagePullRequest(t *testing.T) {
+	pullRequests := []PullRequest{
+		{ID: 1, Title: "Bump lodash-es from 4.17.20 to 4.17.21", Author: "dependabot[bot]"},
+		{ID: 2, Title: "Bump org.yaml:snakeyaml from 1.33 to 2.0", Author: "dependabot[bot]"},
+		{ID: 3, Title: "Update dependency lodash to v4.17.21", Author: "renovate-bot", AuthorDisplayName: "Renovate Bot"},
+		{ID: 4, Title: "Bump minimist", Author: "developer"},
+	}
+	authors := []string{"Dependabot[bot]", "renovate bot"}
+	testCases := []struct {
+		packageName string
+		expectedId  int64
+	}{
+		{packageName: "lodash", expectedId: 3},
+		{packageName: "lodash-es", expectedId: 1},
+		{packageName: "org.yaml:snakeyaml", expectedId: 2},
+		// Only the pull requests of the configured authors cover packages
+		{packageName: "minimist"},
+		{packageName: "snakeyaml"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.packageName, func(t *testing.T) {
+			pullRequest := FindPackagePullRequest(pullRequests, authors, tc.packageName)
+			if tc.expectedId == 0 {
+				assert.Nil(t, pullRequest)
+				return
+			}
+			require.NotNil(t, pullRequest)
+			assert.Equal(t, tc.expectedId, pullRequest.ID)
+		})
+	}
+}
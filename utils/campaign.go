@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+)
+
+var cveIdRegex = regexp.MustCompile(`(?i)^CVE-\d{4}-\d+$`)
+
+// Campaign describes a coordinated fix of a single vulnerability across multiple repositories.
+// The campaign targets either a CVE or a package (with an optional version).
+type Campaign struct {
+	// Shared identifier that is added to the titles and labels of all the pull requests opened by the campaign
+	Id string
+	// The targeted CVE ID, empty if the campaign targets a package
+	Cve string
+	// The targeted package, empty if the campaign targets a CVE
+	PackageName    string
+	PackageVersion string
+}
+
+// NewCampaign parses the campaign target, which can be a CVE ID (CVE-2021-44228) or a package with an optional version (log4j-core@2.14.1).
+// If no campaign ID is provided, the target is used as the campaign ID.
+func NewCampaign(target, id string) (*Campaign, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("a fix campaign requires a target. Please set the CVE ID or package@version to fix using the %s environment variable", CampaignTargetEnv)
+	}
+	campaign := &Campaign{Id: strings.TrimSpace(id)}
+	if campaign.Id == "" {
+		campaign.Id = target
+	}
+	if cveIdRegex.MatchString(target) {
+		campaign.Cve = strings.ToUpper(target)
+		return campaign, nil
+	}
+	var valid bool
+	if campaign.PackageName, campaign.PackageVersion, valid = splitPackageTarget(target); !valid {
+		return nil, fmt.Errorf("the fix campaign target '%s' is invalid. Expected a CVE ID or a package in the format: package@version", target)
+	}
+	return campaign, nil
+}
+
+// Splits a package target in the format package@version, where the version is optional.
+// Scoped npm packages start with '@', so the version separator is the last '@' that isn't the first character.
+func splitPackageTarget(target string) (packageName, packageVersion string, valid bool) {
+	if separatorIndex := strings.LastIndex(target, "@"); separatorIndex > 0 {
+		packageName, packageVersion = target[:separatorIndex], target[separatorIndex+1:]
+	} else {
+		packageName = target
+	}
+	valid = packageName != "" && (!strings.HasSuffix(target, "@") || packageVersion != "")
+	return
+}
+
+// Returns true if the vulnerability is in the given package. If no version is provided, all the versions of the package are matched.
+func isTargetedPackage(vulnerability formats.VulnerabilityOrViolationRow, packageName, packageVersion string) bool {
+	if vulnerability.ImpactedDependencyName != packageName {
+		return false
+	}
+	return packageVersion == "" || vulnerability.ImpactedDependencyVersion == packageVersion
+}
+
+// IsTargeted returns true if the vulnerability is one that the campaign is meant to fix.
+func (c *Campaign) IsTargeted(vulnerability formats.VulnerabilityOrViolationRow) bool {
+	if c.Cve != "" {
+		return IsTargetedCve(vulnerability, []string{c.Cve})
+	}
+	return isTargetedPackage(vulnerability, c.PackageName, c.PackageVersion)
+}
+
+// PullRequestTitle adds the campaign identifier to the given pull request title.
+func (c *Campaign) PullRequestTitle(title string) string {
+	return fmt.Sprintf("[%s] %s", c.Id, title)
+}
+
+// PullRequestLabel returns the label of the pull requests opened by the campaign, which lists them across the repositories.
+func (c *Campaign) PullRequestLabel() string {
+	return "campaign:" + c.Id
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCampaign(t *testing.T) {
+	testCases := []struct {
+		name             string
+		target           string
+		id               string
+		expectedCampaign *Campaign
+		expectError      bool
+	}{
+		{name: "CVE", target: "CVE-2021-44228", expectedCampaign: &Campaign{Id: "CVE-2021-44228", Cve: "CVE-2021-44228"}},
+		{name: "Lowercase CVE", target: " cve-2021-44228 ", id: "log4shell", expectedCampaign: &Campaign{Id: "log4shell", Cve: "CVE-2021-44228"}},
+		{name: "Package with version", target: "log4j-core@2.14.1", expectedCampaign: &Campaign{Id: "log4j-core@2.14.1", PackageName: "log4j-core", PackageVersion: "2.14.1"}},
+		{name: "Scoped npm package", target: "@scope/pkg@1.0.0", id: "scoped", expectedCampaign: &Campaign{Id: "scoped", PackageName: "@scope/pkg", PackageVersion: "1.0.0"}},
+		{name: "Package without version", target: "lodash", expectedCampaign: &Campaign{Id: "lodash", PackageName: "lodash"}},
+		{name: "Empty target", target: " ", expectError: true},
+		{name: "Missing version", target: "lodash@", expectError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			campaign, err := NewCampaign(tc.target, tc.id)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCampaign, campaign)
+		})
+	}
+}
+
+func TestCampaignIsTargeted(t *testing.T) {
+	vulnerability := formats.VulnerabilityOrViolationRow{
+		Cves: []formats.CveRow{{Id: "CVE-2021-44228"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			ImpactedDependencyName:    "log4j-core",
+			ImpactedDependencyVersion: "2.14.1",
+		},
+	}
+	testCases := []struct {
+		name     string
+		campaign Campaign
+		expected bool
+	}{
+		{name: "Matching CVE", campaign: Campaign{Cve: "CVE-2021-44228"}, expected: true},
+		{name: "Other CVE", campaign: Campaign{Cve: "CVE-2021-45046"}, expected: false},
+		{name: "Matching package", campaign: Campaign{PackageName: "log4j-core"}, expected: true},
+		{name: "Matching package and version", campaign: Campaign{PackageName: "log4j-core", PackageVersion: "2.14.1"}, expected: true},
+		{name: "Other version", campaign: Campaign{PackageName: "log4j-core", PackageVersion: "2.15.0"}, expected: false},
+		{name: "Other package", campaign: Campaign{PackageName: "log4j-api"}, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.campaign.IsTargeted(vulnerability))
+		})
+	}
+}
+
+func TestCampaignPullRequestTitle(t *testing.T) {
+	campaign := Campaign{Id: "log4shell"}
+	assert.Equal(t, "[log4shell] [🐸 Frogbot] Update version of log4j-core to 2.17.1", campaign.PullRequestTitle("[🐸 Frogbot] Update version of log4j-core to 2.17.1"))
+	assert.Equal(t, "campaign:log4shell", campaign.PullRequestLabel())
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	securityutils "github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray/scangraph"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xscservices "github.com/jfrog/jfrog-client-go/xsc/services"
+)
+
+// Feature is a feature of Frogbot that requires a minimal version of one of the JFrog services
+type Feature string
+
+const (
+	ScaFeature           Feature = "Dependencies scan"
+	JasFeature           Feature = "Advanced Security scans (Contextual Analysis, Secrets, IaC and SAST)"
+	ConfigProfileFeature Feature = "Config profiles"
+	AnalyticsFeature     Feature = "Scan analytics"
+)
+
+type featureRequirement struct {
+	feature    Feature
+	product    clientutils.MinVersionProduct
+	minVersion string
+}
+
+// The minimal versions of the JFrog services that the features require, in the order of the capabilities matrix
+var featureRequirements = []featureRequirement{
+	{feature: ScaFeature, product: clientutils.Xray, minVersion: scangraph.GraphScanMinXrayVersion},
+	{feature: JasFeature, product: clientutils.Xray, minVersion: securityutils.EntitlementsMinVersion},
+	{feature: ConfigProfileFeature, product: clientutils.Xsc, minVersion: xscservices.ConfigProfileMinXscVersion},
+	{feature: AnalyticsFeature, product: clientutils.Xsc, minVersion: xscservices.AnalyticsMetricsMinXscVersion},
+}
+
+// Capabilities are the features that the versions of the JFrog services of the run support.
+// The zero value supports all the features, so the features are available when the versions are unknown.
+type Capabilities struct {
+	xrayVersion string
+	xscVersion  string
+	// The reasons that the unsupported features are unavailable, by the features
+	unsupported map[Feature]string
+}
+
+// Detects the features that the versions of Xray and XSC support. An empty XSC version means that XSC isn't available.
+func DetectCapabilities(xrayVersion, xscVersion string) Capabilities {
+	capabilities := Capabilities{xrayVersion: xrayVersion, xscVersion: xscVersion, unsupported: map[Feature]string{}}
+	for _, requirement := range featureRequirements {
+		currentVersion := xrayVersion
+		if requirement.product == clientutils.Xsc {
+			currentVersion = xscVersion
+		}
+		if currentVersion == "" {
+			capabilities.unsupported[requirement.feature] = fmt.Sprintf("%s isn't available, while version %s or higher is required", requirement.product, requirement.minVersion)
+			continue
+		}
+		if err := clientutils.ValidateMinimumVersion(requirement.product, currentVersion, requirement.minVersion); err != nil {
+			capabilities.unsupported[requirement.feature] = err.Error()
+		}
+	}
+	return capabilities
+}
+
+func (c Capabilities) IsSupported(feature Feature) bool {
+	_, unsupported := c.unsupported[feature]
+	return !unsupported
+}
+
+// Returns the reason that the feature is unavailable, or an empty string if it's supported
+func (c Capabilities) UnsupportedReason(feature Feature) string {
+	return c.unsupported[feature]
+}
+
+// Logs the capabilities matrix, with the minimal version of each feature and whether it's available
+func (c Capabilities) LogMatrix() {
+	rows := []string{fmt.Sprintf("Xray version: %s, XSC version: %s", c.xrayVersion, orNotAvailable(c.xscVersion))}
+	for _, requirement := range featureRequirements {
+		status := "available"
+		if !c.IsSupported(requirement.feature) {
+			status = "unavailable"
+		}
+		rows = append(rows, fmt.Sprintf("%s (requires %s %s): %s", requirement.feature, requirement.product, requirement.minVersion, status))
+	}
+	log.Debug("The capabilities of the JFrog services:\n" + strings.Join(rows, "\n"))
+}
+
+func orNotAvailable(version string) string {
+	if version == "" {
+		return "not available"
+	}
+	return version
+}
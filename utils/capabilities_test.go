@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	testCases := []struct {
+		name                string
+		xrayVersion         string
+		xscVersion          string
+		expectedUnsupported []Feature
+	}{
+		{name: "All supported", xrayVersion: "3.107.0", xscVersion: "1.12.0"},
+		{name: "Without XSC", xrayVersion: "3.107.0", expectedUnsupported: []Feature{ConfigProfileFeature, AnalyticsFeature}},
+		{name: "XSC without config profiles", xrayVersion: "3.107.0", xscVersion: "1.8.0", expectedUnsupported: []Feature{ConfigProfileFeature}},
+		{name: "Xray without JAS", xrayVersion: "3.60.0", xscVersion: "1.12.0", expectedUnsupported: []Feature{JasFeature}},
+		{name: "Xray without graph scan", xrayVersion: "3.20.0", xscVersion: "1.12.0", expectedUnsupported: []Feature{ScaFeature, JasFeature}},
+		{name: "Development version", xrayVersion: "3.x-dev", xscVersion: "1.12.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capabilities := DetectCapabilities(tc.xrayVersion, tc.xscVersion)
+			for _, requirement := range featureRequirements {
+				expectedSupported := !slices.Contains(tc.expectedUnsupported, requirement.feature)
+				assert.Equal(t, expectedSupported, capabilities.IsSupported(requirement.feature), requirement.feature)
+				assert.Equal(t, expectedSupported, capabilities.UnsupportedReason(requirement.feature) == "", requirement.feature)
+			}
+			capabilities.LogMatrix()
+		})
+	}
+	assert.Equal(t, "You are using JFrog Xray version 3.60.0, while this operation requires version 3.66.5 or higher.", DetectCapabilities("3.60.0", "").UnsupportedReason(JasFeature))
+	assert.Equal(t, "JFrog Xsc isn't available, while version 1.11.0 or higher is required", DetectCapabilities("3.60.0", "").UnsupportedReason(ConfigProfileFeature))
+
+	// The features are supported when the versions are unknown
+	assert.True(t, Capabilities{}.IsSupported(JasFeature))
+}
@@ -0,0 +1,155 @@
+package codeinsights
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The key of the report that Frogbot publishes, which identifies it among the reports of the commit
+	ReportKey   = "frogbot-security-scan"
+	reportTitle = "Frogbot Security Scan"
+	reporter    = "JFrog Frogbot"
+	// The limits of Bitbucket Server, longer texts and additional annotations are rejected
+	maxAnnotations   = 1000
+	maxMessageLength = 2000
+	maxDetailsLength = 2000
+	truncationSuffix = "..."
+)
+
+// The result of a report
+type Result string
+
+const (
+	Pass Result = "PASS"
+	Fail Result = "FAIL"
+)
+
+// Report is a Code Insights report of a commit, shown in the pull requests that include the commit
+type Report struct {
+	Title    string       `json:"title"`
+	Details  string       `json:"details,omitempty"`
+	Result   Result       `json:"result"`
+	Reporter string       `json:"reporter"`
+	Data     []ReportData `json:"data,omitempty"`
+}
+
+// ReportData is a metric that is shown in the summary of the report
+type ReportData struct {
+	Title string `json:"title"`
+	Type  string `json:"type"`
+	Value int    `json:"value"`
+}
+
+// Annotation marks a finding on a line of a file of the report. Line 0 marks the whole file.
+type Annotation struct {
+	ExternalId string `json:"externalId,omitempty"`
+	Path       string `json:"path"`
+	Line       int    `json:"line"`
+	Message    string `json:"message"`
+	// 'LOW', 'MEDIUM' or 'HIGH'
+	Severity string `json:"severity"`
+	// 'VULNERABILITY', 'CODE_SMELL' or 'BUG'
+	Type string `json:"type"`
+}
+
+// Returns the report and the annotations of the findings of a pull request scan.
+// The Secrets, IaC and SAST findings are annotated on their locations, while the findings of the dependencies are counted in the report only.
+func NewReport(issuesCollection *issues.ScansIssuesCollection, includeSecrets bool) (report Report, annotations []Annotation) {
+	report = Report{Title: reportTitle, Reporter: reporter, Result: Pass, Details: "No security issues were found by the scan of the pull request."}
+	if total := issuesCollection.GetAllIssuesCount(includeSecrets); total > 0 {
+		report.Result = Fail
+		report.Details = fmt.Sprintf("The scan of the pull request found %d security issues. Their details are in the summary comment of the pull request.", total)
+	}
+	report.Data = []ReportData{
+		{Title: "Vulnerable Dependencies", Type: "NUMBER", Value: len(issuesCollection.ScaVulnerabilities) + len(issuesCollection.ScaViolations)},
+		{Title: "IaC", Type: "NUMBER", Value: len(issuesCollection.IacVulnerabilities) + len(issuesCollection.IacViolations)},
+		{Title: "SAST", Type: "NUMBER", Value: len(issuesCollection.SastVulnerabilities) + len(issuesCollection.SastViolations)},
+	}
+	annotations = append(annotations, getAnnotations(issuesCollection.IacVulnerabilities, issuesCollection.IacViolations, "IaC", "CODE_SMELL")...)
+	annotations = append(annotations, getAnnotations(issuesCollection.SastVulnerabilities, issuesCollection.SastViolations, "SAST", "VULNERABILITY")...)
+	if includeSecrets {
+		report.Data = append(report.Data, ReportData{Title: "Secrets", Type: "NUMBER", Value: len(issuesCollection.SecretsVulnerabilities) + len(issuesCollection.SecretsViolations)})
+		annotations = append(annotations, getAnnotations(issuesCollection.SecretsVulnerabilities, issuesCollection.SecretsViolations, "Secret", "VULNERABILITY")...)
+	}
+	if len(annotations) > maxAnnotations {
+		log.Info(fmt.Sprintf("Only the first %d of the %d findings are annotated in the Code Insights report", maxAnnotations, len(annotations)))
+		annotations = annotations[:maxAnnotations]
+	}
+	return
+}
+
+func getAnnotations(vulnerabilities, violations []formats.SourceCodeRow, scanType, annotationType string) (annotations []Annotation) {
+	for _, issue := range append(append([]formats.SourceCodeRow{}, vulnerabilities...), violations...) {
+		// The finding of a secret doesn't include its value, which is only in the snippet
+		message := issue.Finding
+		if message == "" {
+			message = issue.ScannerShortDescription
+		}
+		annotations = append(annotations, Annotation{
+			ExternalId: issues.GetSourceCodeFindingId(issue),
+			Path:       strings.TrimPrefix(issue.File, "/"),
+			Line:       issue.StartLine,
+			Message:    truncate(fmt.Sprintf("[%s] %s", scanType, message), maxMessageLength),
+			Severity:   toAnnotationSeverity(issue.Severity),
+			Type:       annotationType,
+		})
+	}
+	return
+}
+
+func toAnnotationSeverity(severity string) string {
+	switch severityutils.GetSeverity(severity) {
+	case severityutils.Critical, severityutils.High:
+		return "HIGH"
+	case severityutils.Medium:
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
+
+func truncate(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	return text[:maxLength-len(truncationSuffix)] + truncationSuffix
+}
+
+// Client publishes the Code Insights reports of the commits of a Bitbucket Server repository.
+// The Git clients don't expose the Code Insights API, so the reports are published with the REST API of Bitbucket Server.
+type Client struct {
+	client     *vcsapi.Client
+	commitsUrl string
+}
+
+func NewClient(vcsInfo vcsclient.VcsInfo, repoOwner, repoName string) *Client {
+	client := vcsapi.NewClient(vcsutils.BitbucketServer, vcsInfo)
+	return &Client{client: client, commitsUrl: client.Url("/insights/1.0/projects/%s/repos/%s/commits", repoOwner, repoName)}
+}
+
+// Publishes the report of the commit and its annotations. The report replaces the previous report of Frogbot, and its annotations.
+func (c *Client) PublishReport(commitSha string, report Report, annotations []Annotation) error {
+	report.Details = truncate(report.Details, maxDetailsLength)
+	reportUrl := fmt.Sprintf("%s/%s/reports/%s", c.commitsUrl, url.PathEscape(commitSha), ReportKey)
+	if err := c.client.Send(http.MethodPut, reportUrl, report, nil); err != nil {
+		return err
+	}
+	if err := c.client.Send(http.MethodDelete, reportUrl+"/annotations", nil, nil); err != nil {
+		return err
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return c.client.Send(http.MethodPost, reportUrl+"/annotations", map[string][]Annotation{"annotations": annotations}, nil)
+}
@@ -0,0 +1,99 @@
+package codeinsights
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSourceCodeRow(severity, file string, line int, finding string) formats.SourceCodeRow {
+	return formats.SourceCodeRow{
+		SeverityDetails: formats.SeverityDetails{Severity: severity},
+		ScannerInfo:     formats.ScannerInfo{RuleId: "rule", ScannerShortDescription: "Short description"},
+		Location:        formats.Location{File: file, StartLine: line, Snippet: "snippet"},
+		Finding:         finding,
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	issuesCollection := &issues.ScansIssuesCollection{
+		ScaVulnerabilities:     []formats.VulnerabilityOrViolationRow{{}},
+		IacVulnerabilities:     []formats.SourceCodeRow{newSourceCodeRow("Medium", "terraform/main.tf", 12, "S3 bucket is public")},
+		SastViolations:         []formats.SourceCodeRow{newSourceCodeRow("Critical", "src/app.js", 5, "")},
+		SecretsVulnerabilities: []formats.SourceCodeRow{newSourceCodeRow("Low", "config/.env", 1, "Secret keys were found")},
+	}
+	report, annotations := NewReport(issuesCollection, false)
+	assert.Equal(t, Fail, report.Result)
+	assert.Equal(t, "The scan of the pull request found 3 security issues. Their details are in the summary comment of the pull request.", report.Details)
+	assert.Equal(t, []ReportData{{Title: "Vulnerable Dependencies", Type: "NUMBER", Value: 1}, {Title: "IaC", Type: "NUMBER", Value: 1}, {Title: "SAST", Type: "NUMBER", Value: 1}}, report.Data)
+	assert.Equal(t, []Annotation{
+		{ExternalId: issues.GetSourceCodeFindingId(issuesCollection.IacVulnerabilities[0]), Path: "terraform/main.tf", Line: 12, Message: "[IaC] S3 bucket is public", Severity: "MEDIUM", Type: "CODE_SMELL"},
+		{ExternalId: issues.GetSourceCodeFindingId(issuesCollection.SastViolations[0]), Path: "src/app.js", Line: 5, Message: "[SAST] Short description", Severity: "HIGH", Type: "VULNERABILITY"},
+	}, annotations)
+
+	// The secrets are annotated when they're reported
+	report, annotations = NewReport(issuesCollection, true)
+	require.Len(t, annotations, 3)
+	assert.Equal(t, Annotation{ExternalId: issues.GetSourceCodeFindingId(issuesCollection.SecretsVulnerabilities[0]), Path: "config/.env", Line: 1, Message: "[Secret] Secret keys were found", Severity: "LOW", Type: "VULNERABILITY"}, annotations[2])
+	assert.Equal(t, ReportData{Title: "Secrets", Type: "NUMBER", Value: 1}, report.Data[3])
+
+	report, annotations = NewReport(&issues.ScansIssuesCollection{}, true)
+	assert.Equal(t, Pass, report.Result)
+	assert.Empty(t, annotations)
+}
+
+func TestNewClient(t *testing.T) {
+	client := NewClient(vcsclient.VcsInfo{APIEndpoint: "https://bitbucket.example.com/", Token: "token"}, "SEC", "frogbot")
+	assert.Equal(t, "https://bitbucket.example.com/rest/insights/1.0/projects/SEC/repos/frogbot/commits", client.commitsUrl)
+	client = NewClient(vcsclient.VcsInfo{APIEndpoint: "https://bitbucket.example.com/rest", Username: "frogbot", Token: "token"}, "SEC", "frogbot")
+	assert.Equal(t, "https://bitbucket.example.com/rest/insights/1.0/projects/SEC/repos/frogbot/commits", client.commitsUrl)
+}
+
+func TestPublishReport(t *testing.T) {
+	const reportPath = "/rest/insights/1.0/projects/SEC/repos/frogbot/commits/abc123/reports/" + ReportKey
+	var requests []string
+	var publishedReport Report
+	var publishedAnnotations struct {
+		Annotations []Annotation `json:"annotations"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case http.MethodPut + " " + reportPath:
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&publishedReport))
+		case http.MethodDelete + " " + reportPath + "/annotations":
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPost + " " + reportPath + "/annotations":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&publishedAnnotations))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "SEC", "frogbot")
+
+	report := Report{Title: reportTitle, Reporter: reporter, Result: Fail, Details: strings.Repeat("a", maxDetailsLength+1)}
+	annotations := []Annotation{{Path: "src/app.js", Line: 5, Message: "[SAST] Short description", Severity: "HIGH", Type: "VULNERABILITY"}}
+	require.NoError(t, client.PublishReport("abc123", report, annotations))
+	assert.Equal(t, []string{"PUT " + reportPath, "DELETE " + reportPath + "/annotations", "POST " + reportPath + "/annotations"}, requests)
+	assert.Equal(t, Fail, publishedReport.Result)
+	assert.Len(t, publishedReport.Details, maxDetailsLength)
+	assert.Equal(t, annotations, publishedAnnotations.Annotations)
+
+	// The annotations aren't posted without findings
+	requests = nil
+	require.NoError(t, client.PublishReport("abc123", Report{Title: reportTitle, Reporter: reporter, Result: Pass}, nil))
+	assert.Len(t, requests, 2)
+
+	assert.Error(t, client.PublishReport("unknown", report, annotations))
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/jfrog/frogbot/v2/utils/codeowners"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/maps"
+)
+
+// Returns the owners of the paths of the repository: the owners of the CODEOWNERS file of the target branch, when mentioning them is enabled,
+// and the security champions of the configuration, which take precedence over them. Returns nil if no owners are configured.
+// The CODEOWNERS file is read from the target branch, so the pull request can't change the owners it mentions.
+func GetPullRequestCodeOwners(repo *Repository, client vcsclient.VcsClient) (ruleset *codeowners.Ruleset, err error) {
+	if !repo.MentionCodeOwners && len(repo.SecurityChampions) == 0 {
+		return
+	}
+	ruleset = &codeowners.Ruleset{}
+	if repo.MentionCodeOwners {
+		if ruleset, err = downloadCodeOwners(repo.PullRequestDetails.Target, client); err != nil {
+			return
+		}
+	}
+	for _, champions := range repo.SecurityChampions {
+		if err = ruleset.Add(champions.Path, champions.Owners); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func downloadCodeOwners(target vcsclient.BranchInfo, client vcsclient.VcsClient) (*codeowners.Ruleset, error) {
+	for _, location := range codeowners.Locations {
+		content, statusCode, err := client.DownloadFileFromRepo(context.Background(), target.Owner, target.Repository, target.Name, location)
+		if statusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("couldn't download the %s file from <%s/%s/%s>: %s", location, target.Owner, target.Repository, target.Name, err.Error())
+		}
+		ruleset, err := codeowners.Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse the %s file: %s", location, err.Error())
+		}
+		log.Debug(fmt.Sprintf("The owners of the %s file of the target branch are mentioned", location))
+		return ruleset, nil
+	}
+	log.Debug(fmt.Sprintf("No CODEOWNERS file was found in <%s/%s/%s>", target.Owner, target.Repository, target.Name))
+	return &codeowners.Ruleset{}, nil
+}
+
+// Returns the sorted owners of the paths of the Critical and High findings.
+// The SCA findings are attributed to the working directories of their descriptors.
+func GetSecurityChampions(ruleset *codeowners.Ruleset, issuesCollection *issues.ScansIssuesCollection) []string {
+	if ruleset == nil || issuesCollection == nil {
+		return nil
+	}
+	owners := map[string]bool{}
+	addOwners := func(severity, path string) {
+		if path == "" || severityutils.CompareSeverity(severityutils.GetSeverity(severity), severityutils.High) < 0 {
+			return
+		}
+		for _, owner := range ruleset.Owners(path) {
+			owners[owner] = true
+		}
+	}
+	for _, rows := range [][]formats.VulnerabilityOrViolationRow{issuesCollection.ScaVulnerabilities, issuesCollection.ScaViolations} {
+		for _, row := range rows {
+			for _, component := range row.Components {
+				if component.Location != nil {
+					addOwners(row.Severity, component.Location.File)
+				}
+			}
+		}
+	}
+	for _, rows := range [][]formats.SourceCodeRow{
+		issuesCollection.IacVulnerabilities, issuesCollection.IacViolations,
+		issuesCollection.SecretsVulnerabilities, issuesCollection.SecretsViolations,
+		issuesCollection.SastVulnerabilities, issuesCollection.SastViolations,
+	} {
+		for _, row := range rows {
+			addOwners(row.Severity, row.Location.File)
+		}
+	}
+	champions := maps.Keys(owners)
+	sort.Strings(champions)
+	return champions
+}
@@ -0,0 +1,105 @@
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The locations of the CODEOWNERS file that the Git providers look for, by their precedence
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS", ".bitbucket/CODEOWNERS"}
+
+// Rule assigns owners to the files that match a CODEOWNERS pattern
+type Rule struct {
+	Pattern string
+	Owners  []string
+	regex   *regexp.Regexp
+}
+
+// Ruleset holds the rules of a CODEOWNERS file. As in CODEOWNERS files, the last rule that matches a path takes precedence.
+type Ruleset struct {
+	rules []Rule
+}
+
+// Parse parses the content of a CODEOWNERS file.
+// The section headers of GitLab, such as [Backend], are skipped, and their rules are parsed as the rest of the rules.
+func Parse(content []byte) (*Ruleset, error) {
+	ruleset := &Ruleset{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if commentIndex := strings.Index(line, "#"); commentIndex >= 0 && !strings.HasPrefix(line, `\#`) {
+			line = strings.TrimSpace(line[:commentIndex])
+		}
+		if line == "" || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if err := ruleset.Add(strings.TrimPrefix(fields[0], `\`), fields[1:]); err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNumber, err.Error())
+		}
+	}
+	return ruleset, scanner.Err()
+}
+
+// Add adds a rule that takes precedence over the existing rules. A rule without owners removes the ownership of the matching paths.
+func (rs *Ruleset) Add(pattern string, owners []string) error {
+	regex, err := patternToRegex(pattern)
+	if err != nil {
+		return fmt.Errorf("the CODEOWNERS pattern '%s' is invalid: %s", pattern, err.Error())
+	}
+	rs.rules = append(rs.rules, Rule{Pattern: pattern, Owners: owners, regex: regex})
+	return nil
+}
+
+// Owners returns the owners of the path, relative to the repository root, according to the last matching rule
+func (rs *Ruleset) Owners(path string) []string {
+	if rs == nil {
+		return nil
+	}
+	path = strings.Trim(strings.TrimPrefix(strings.ReplaceAll(path, `\`, "/"), "./"), "/")
+	for i := len(rs.rules) - 1; i >= 0; i-- {
+		if rs.rules[i].regex.MatchString(path) {
+			return rs.rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// Converts the gitignore style pattern of CODEOWNERS to a regular expression, that matches the paths of the files and directories,
+// and the contents of the matching directories.
+// A pattern with a slash at its start or middle is relative to the repository root, and other patterns match at any depth.
+func patternToRegex(pattern string) (*regexp.Regexp, error) {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+	if trimmed == "" || trimmed == "**" {
+		return regexp.Compile(".*")
+	}
+	var regex strings.Builder
+	if anchored {
+		regex.WriteString("^")
+	} else {
+		regex.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			regex.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "/**") && i+3 == len(trimmed):
+			regex.WriteString("/.*")
+			i += 2
+		case trimmed[i] == '*':
+			regex.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			regex.WriteString("[^/]")
+		default:
+			regex.WriteString(regexp.QuoteMeta(string(trimmed[i])))
+		}
+	}
+	regex.WriteString("(?:/.*)?$")
+	return regexp.Compile(regex.String())
+}
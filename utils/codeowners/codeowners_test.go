@@ -0,0 +1,59 @@
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCodeOwners = `# The default owners
+*                   @jfrog/maintainers
+
+[Frontend]
+*.js                @jfrog/frontend # Inline comment
+/docs/              @jfrog/docs
+apps/**/secrets     @jfrog/security
+**/terraform        @jfrog/infra
+/scripts/*.sh       @dev1 dev2@example.com
+/vendor/
+`
+
+func TestOwners(t *testing.T) {
+	ruleset, err := Parse([]byte(testCodeOwners))
+	require.NoError(t, err)
+	testCases := []struct {
+		path           string
+		expectedOwners []string
+	}{
+		{path: "go.mod", expectedOwners: []string{"@jfrog/maintainers"}},
+		{path: "web/src/app.js", expectedOwners: []string{"@jfrog/frontend"}},
+		{path: "docs/guide.md", expectedOwners: []string{"@jfrog/docs"}},
+		{path: "docs/api/app.js", expectedOwners: []string{"@jfrog/docs"}},
+		{path: "src/docs/guide.md", expectedOwners: []string{"@jfrog/maintainers"}},
+		{path: "apps/secrets/key.pem", expectedOwners: []string{"@jfrog/security"}},
+		{path: "apps/web/prod/secrets", expectedOwners: []string{"@jfrog/security"}},
+		{path: "terraform/main.tf", expectedOwners: []string{"@jfrog/infra"}},
+		{path: "deploy/terraform/main.tf", expectedOwners: []string{"@jfrog/infra"}},
+		{path: "./scripts/build.sh", expectedOwners: []string{"@dev1", "dev2@example.com"}},
+		{path: "scripts/ci/build.sh", expectedOwners: []string{"@jfrog/maintainers"}},
+		// A rule without owners removes the ownership
+		{path: "vendor/lib/lib.go"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if len(tc.expectedOwners) == 0 {
+				assert.Empty(t, ruleset.Owners(tc.path))
+				return
+			}
+			assert.Equal(t, tc.expectedOwners, ruleset.Owners(tc.path))
+		})
+	}
+
+	// The added rules take precedence over the rules of the file
+	require.NoError(t, ruleset.Add("/web/", []string{"@jfrog/web-champions"}))
+	assert.Equal(t, []string{"@jfrog/web-champions"}, ruleset.Owners("web/src/app.js"))
+
+	var emptyRuleset *Ruleset
+	assert.Empty(t, emptyRuleset.Owners("go.mod"))
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils/codeowners"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPullRequestCodeOwners(t *testing.T) {
+	repo := &Repository{Params: Params{Git: Git{PullRequestDetails: vcsclient.PullRequestInfo{Target: vcsclient.BranchInfo{Owner: "owner", Repository: "repo", Name: "main"}}}}}
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+
+	// No owners are configured
+	ruleset, err := GetPullRequestCodeOwners(repo, client)
+	require.NoError(t, err)
+	assert.Nil(t, ruleset)
+
+	// The security champions take precedence over the CODEOWNERS file
+	repo.MentionCodeOwners = true
+	repo.SecurityChampions = []SecurityChampions{{Path: "/frontend/", Owners: []string{"@frontend-champion"}}}
+	client.EXPECT().DownloadFileFromRepo(context.Background(), "owner", "repo", "main", ".github/CODEOWNERS").Return(nil, http.StatusNotFound, errors.New("not found"))
+	client.EXPECT().DownloadFileFromRepo(context.Background(), "owner", "repo", "main", "CODEOWNERS").Return([]byte("* @jfrog/maintainers\n/frontend/ @jfrog/frontend\n"), http.StatusOK, nil)
+	ruleset, err = GetPullRequestCodeOwners(repo, client)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@jfrog/maintainers"}, ruleset.Owners("main.go"))
+	assert.Equal(t, []string{"@frontend-champion"}, ruleset.Owners("frontend/app.js"))
+
+	client.EXPECT().DownloadFileFromRepo(context.Background(), "owner", "repo", "main", ".github/CODEOWNERS").Return(nil, http.StatusInternalServerError, errors.New("server error"))
+	_, err = GetPullRequestCodeOwners(repo, client)
+	assert.ErrorContains(t, err, "couldn't download the .github/CODEOWNERS file")
+}
+
+func TestGetSecurityChampions(t *testing.T) {
+	ruleset, err := codeowners.Parse([]byte("* @jfrog/maintainers\n/frontend/ @jfrog/frontend\n/infra/ @jfrog/infra\n*.go @jfrog/backend\n"))
+	require.NoError(t, err)
+	issuesCollection := &issues.ScansIssuesCollection{
+		ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{
+			{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}, Components: []formats.ComponentRow{{Location: &formats.Location{File: "frontend"}}}}},
+		},
+		IacVulnerabilities: []formats.SourceCodeRow{
+			// Findings below High don't mention their owners
+			{SeverityDetails: formats.SeverityDetails{Severity: "Medium"}, Location: formats.Location{File: "infra/main.tf"}},
+		},
+		SastVulnerabilities: []formats.SourceCodeRow{
+			{SeverityDetails: formats.SeverityDetails{Severity: "High"}, Location: formats.Location{File: "cmd/main.go"}},
+			{SeverityDetails: formats.SeverityDetails{Severity: "High"}, Location: formats.Location{File: "frontend/server.go"}},
+		},
+	}
+	assert.Equal(t, []string{"@jfrog/backend", "@jfrog/frontend"}, GetSecurityChampions(ruleset, issuesCollection))
+	assert.Empty(t, GetSecurityChampions(nil, issuesCollection))
+}
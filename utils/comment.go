@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/jfrog/frogbot/v2/utils/issues"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/secretvalidation"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
 	"github.com/jfrog/jfrog-cli-security/utils/results"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -34,46 +38,102 @@ const (
 )
 
 // In Scan PR, if there are no issues, comments will be added to the PR with a message that there are no issues.
-func HandlePullRequestCommentsAfterScan(issues *issues.ScansIssuesCollection, resultContext results.ResultContext, repo *Repository, client vcsclient.VcsClient, pullRequestID int) (err error) {
+// When collapsePreviousPrComments is set, the previous summary comments are collapsed instead of being deleted.
+// The review comments of suppressed findings are kept, so the suppressions persist across scans.
+// On Azure Repos, the review comment threads of the findings that no longer exist are resolved and kept.
+func HandlePullRequestCommentsAfterScan(issues *issues.ScansIssuesCollection, resultContext results.ResultContext, repo *Repository, client vcsclient.VcsClient, pullRequestID int, suppressions *PullRequestSuppressions) (err error) {
+	comments := generatePullRequestComments(issues, resultContext, repo)
+	if repo.GitProvider == vcsutils.AzureRepos {
+		if suppressions == nil {
+			suppressions = NewPullRequestSuppressions()
+		}
+		// The threads that couldn't be resolved are deleted with the other Frogbot comments
+		if e := resolveFixedFindingsThreads(repo, pullRequestID, comments.ReviewComments, suppressions); e != nil {
+			log.Warn("Couldn't resolve the threads of the findings that no longer exist:", e.Error())
+		}
+	}
 	if !repo.Params.AvoidPreviousPrCommentsDeletion {
 		// The removal of comments may fail for various reasons,
 		// such as concurrent scanning of pull requests and attempts
 		// to delete comments that have already been removed in a different process.
 		// Since this task is not mandatory for a Frogbot run,
 		// we will not cause a Frogbot run to fail but will instead log the error.
-		log.Debug("Looking for an existing Frogbot pull request comment. Deleting it if it exists...")
-		if e := DeletePullRequestComments(repo, client, pullRequestID); e != nil {
-			log.Error(fmt.Sprintf("%s:\n%v", commentRemovalErrorMsg, e))
+		if repo.Params.CollapsePreviousPrComments {
+			log.Debug("Looking for an existing Frogbot pull request comment. Collapsing it if it exists...")
+			if e := CollapsePullRequestComments(repo, client, pullRequestID, suppressions); e != nil {
+				log.Error(fmt.Sprintf("%s:\n%v", commentRemovalErrorMsg, e))
+			}
+		} else {
+			log.Debug("Looking for an existing Frogbot pull request comment. Deleting it if it exists...")
+			if e := DeletePullRequestComments(repo, client, pullRequestID, suppressions); e != nil {
+				log.Error(fmt.Sprintf("%s:\n%v", commentRemovalErrorMsg, e))
+			}
 		}
 	}
 
 	// Add summary (SCA, license) scan comment
-	if issues.IssuesExists(repo.PullRequestSecretComments) || repo.AddPrCommentOnSuccess {
-		for _, comment := range generatePullRequestSummaryComment(*issues, resultContext, repo.PullRequestSecretComments, repo.OutputWriter) {
-			if err = client.AddPullRequestComment(context.Background(), repo.RepoOwner, repo.RepoName, comment, pullRequestID); err != nil {
-				err = errors.New("couldn't add pull request comment: " + err.Error())
-				return
-			}
+	for _, comment := range comments.SummaryComments {
+		if err = client.AddPullRequestComment(context.Background(), repo.RepoOwner, repo.RepoName, comment, pullRequestID); err != nil {
+			err = errors.New("couldn't add pull request comment: " + err.Error())
+			return
 		}
 	}
 
 	// Handle review comments at the pull request
-	if err = addReviewComments(repo, pullRequestID, client, issues); err != nil {
+	if err = addReviewComments(repo, pullRequestID, client, comments.ReviewComments); err != nil {
 		err = errors.New("couldn't add pull request review comments: " + err.Error())
 		return
 	}
 	return
 }
 
-func DeletePullRequestComments(repo *Repository, client vcsclient.VcsClient, pullRequestID int) (err error) {
+// PullRequestComments holds the rendered content of the comments Frogbot adds to a pull request after scanning it
+type PullRequestComments struct {
+	// The summary comment of the scan. The content is split into multiple comments if it exceeds the size limit of the comments.
+	// Empty if no summary comment should be added. When blockOnSecrets is set, the urgent comment of the exposed secrets is the first comment.
+	SummaryComments []string
+	// The comments to add at the locations of the applicable CVEs and of the source code findings
+	ReviewComments []ReviewComment
+}
+
+// GeneratePullRequestComments renders the comments Frogbot adds to a pull request for the given issues, without posting them.
+// The content is formatted for the given Git provider, according to the provided params.
+func GeneratePullRequestComments(issuesCollection *issues.ScansIssuesCollection, resultContext results.ResultContext, provider vcsutils.VcsProvider, params Params) PullRequestComments {
+	repo := &Repository{Params: params}
+	repo.GitProvider = provider
+	repo.setOutputWriterDetails()
+	return generatePullRequestComments(issuesCollection, resultContext, repo)
+}
+
+func generatePullRequestComments(issuesCollection *issues.ScansIssuesCollection, resultContext results.ResultContext, repo *Repository) (comments PullRequestComments) {
+	showIgnoredFindings := repo.ShowIgnoredFindings && issuesCollection.IgnoredIssuesExists(repo.PullRequestSecretComments)
+	// The summary comment of the incremental scans records the scanned commit, so it's added even if there's nothing to report
+	if IsReportingPullRequestIssues(issuesCollection, repo) || repo.AddPrCommentOnSuccess || repo.IncrementalScan {
+		comments.SummaryComments = generatePullRequestSummaryComment(*issuesCollection, resultContext, repo.PullRequestSecretComments, showIgnoredFindings, repo.OutputWriter)
+	}
+	if repo.BlockOnSecrets && issuesCollection.SecretsIssuesExists() {
+		comments.SummaryComments = append([]string{generateSecretsRotationComment(issuesCollection, repo)}, comments.SummaryComments...)
+	}
+	comments.ReviewComments = getNewReviewComments(repo, issuesCollection)
+	return
+}
+
+// Returns true if the comments of the pull request report any findings, rather than only that no issues were found
+func IsReportingPullRequestIssues(issuesCollection *issues.ScansIssuesCollection, repo *Repository) bool {
+	showIgnoredFindings := repo.ShowIgnoredFindings && issuesCollection.IgnoredIssuesExists(repo.PullRequestSecretComments)
+	return issuesCollection.IssuesExists(repo.PullRequestSecretComments) || issuesCollection.FixedIssuesExists() || issuesCollection.DependencyConfusionRisksExists() || issuesCollection.DockerImageVulnerabilitiesExists() || issuesCollection.SystemPackageVulnerabilitiesExists() || issuesCollection.CurationBlockedPackagesExists() || issuesCollection.AlternativePackagesExists() || issuesCollection.GitHubActionIssuesExists() || issuesCollection.ExternalScannerIssuesExists() || issuesCollection.PolicyRuleViolationsExists() || showIgnoredFindings
+}
+
+func DeletePullRequestComments(repo *Repository, client vcsclient.VcsClient, pullRequestID int, suppressions *PullRequestSuppressions) (err error) {
 	// Delete previous PR regular comments, if exists (not related to location of a change)
-	err = DeleteExistingPullRequestComments(repo, client)
+	err = DeleteExistingPullRequestComments(repo, client, suppressions)
 	// Delete previous PR review comments, if exists (related to location of a change)
-	return errors.Join(err, DeleteExistingPullRequestReviewComments(repo, pullRequestID, client))
+	return errors.Join(err, DeleteExistingPullRequestReviewComments(repo, pullRequestID, client, suppressions))
 }
 
 // Delete existing pull request regular comments (Summary, Fallback review comments)
-func DeleteExistingPullRequestComments(repository *Repository, client vcsclient.VcsClient) error {
+// In GitLab, the regular comments include the notes of the merge request discussions, so the comments of suppressed findings are kept.
+func DeleteExistingPullRequestComments(repository *Repository, client vcsclient.VcsClient, suppressions *PullRequestSuppressions) error {
 	prDetails := repository.PullRequestDetails
 	comments, err := GetSortedPullRequestComments(client, prDetails.Target.Owner, prDetails.Target.Repository, int(prDetails.ID))
 	if err != nil {
@@ -81,7 +141,7 @@ func DeleteExistingPullRequestComments(repository *Repository, client vcsclient.
 			"failed to get comments. the following details were used in order to fetch the comments: <%s/%s> pull request #%d. the error received: %s",
 			repository.RepoOwner, repository.RepoName, int(repository.PullRequestDetails.ID), err.Error())
 	}
-	commentsToDelete := getFrogbotComments(comments)
+	commentsToDelete := getFrogbotComments(comments, suppressions)
 	// Delete
 	if len(commentsToDelete) > 0 {
 		for _, commentToDelete := range commentsToDelete {
@@ -93,8 +153,9 @@ func DeleteExistingPullRequestComments(repository *Repository, client vcsclient.
 	return err
 }
 
-func GenerateFixPullRequestDetails(vulnerabilities []formats.VulnerabilityOrViolationRow, writer outputwriter.OutputWriter) (description string, extraComments []string) {
-	content := outputwriter.GetMainCommentContent(outputwriter.GetVulnerabilitiesContent(vulnerabilities, writer), true, false, writer)
+// The extra content is added to the description after the vulnerabilities details.
+func GenerateFixPullRequestDetails(vulnerabilities []formats.VulnerabilityOrViolationRow, writer outputwriter.OutputWriter, extraContent ...string) (description string, extraComments []string) {
+	content := outputwriter.GetMainCommentContent(append(outputwriter.GetVulnerabilitiesContent(vulnerabilities, writer), extraContent...), true, false, writer)
 	if len(content) == 1 {
 		// Limit is not reached, use the entire content as the description
 		description = content[0]
@@ -111,10 +172,49 @@ func GenerateFixPullRequestDetails(vulnerabilities []formats.VulnerabilityOrViol
 	return
 }
 
-func generatePullRequestSummaryComment(issuesCollection issues.ScansIssuesCollection, resultContext results.ResultContext, includeSecrets bool, writer outputwriter.OutputWriter) []string {
+func generatePullRequestSummaryComment(issuesCollection issues.ScansIssuesCollection, resultContext results.ResultContext, includeSecrets, showIgnoredFindings bool, writer outputwriter.OutputWriter) []string {
+	additionalContent := []string{}
+	if len(issuesCollection.SecurityChampions) > 0 {
+		additionalContent = append(additionalContent, outputwriter.SecurityChampionsContent(issuesCollection.SecurityChampions, writer))
+	}
+	if issuesCollection.PolicyRuleViolationsExists() {
+		additionalContent = append(additionalContent, outputwriter.PolicyRuleViolationsContent(issuesCollection.PolicyRuleViolations, PolicyFilePath, writer))
+	}
+	if issuesCollection.DependencyConfusionRisksExists() {
+		additionalContent = append(additionalContent, outputwriter.DependencyConfusionContent(issuesCollection.DependencyConfusionRisks, writer))
+	}
+	if issuesCollection.DockerImageVulnerabilitiesExists() {
+		additionalContent = append(additionalContent, outputwriter.DockerImageContent(issuesCollection.DockerImageVulnerabilities, writer))
+	}
+	if issuesCollection.SystemPackageVulnerabilitiesExists() {
+		additionalContent = append(additionalContent, outputwriter.SystemPackagesContent(issuesCollection.SystemPackageVulnerabilities, writer))
+	}
+	if issuesCollection.CurationBlockedPackagesExists() {
+		additionalContent = append(additionalContent, outputwriter.CurationContent(issuesCollection.CurationBlockedPackages, writer))
+	}
+	if issuesCollection.AlternativePackagesExists() {
+		additionalContent = append(additionalContent, outputwriter.AlternativePackagesContent(issuesCollection.AlternativePackages, writer))
+	}
+	if issuesCollection.GitHubActionIssuesExists() {
+		additionalContent = append(additionalContent, outputwriter.GitHubActionsContent(issuesCollection.GitHubActionIssues, writer))
+	}
+	if issuesCollection.ExternalScannerIssuesExists() {
+		additionalContent = append(additionalContent, outputwriter.ExternalScannersContent(issuesCollection.ExternalScannerIssues, writer))
+	}
+	if issuesCollection.FixedIssuesExists() {
+		additionalContent = append(additionalContent, outputwriter.FixedIssuesContent(issuesCollection.FixedScaIssues, writer))
+	}
+	if showIgnoredFindings && issuesCollection.IgnoredIssuesExists(includeSecrets) {
+		// Findings that match Xray ignore rules are accepted risks, so they are listed after the reported issues
+		additionalContent = append(additionalContent, outputwriter.IgnoredIssuesContent(issuesCollection, includeSecrets, writer))
+	}
 	if !issuesCollection.IssuesExists(includeSecrets) {
+		if issuesCollection.PolicyRuleViolationsExists() {
+			// The pull request is blocked by the policy file, so it isn't presented as free of issues
+			return outputwriter.GetMainCommentContent(additionalContent, true, true, writer)
+		}
 		// No Issues
-		return outputwriter.GetMainCommentContent([]string{}, false, true, writer)
+		return outputwriter.GetNoIssuesCommentContent(additionalContent, writer)
 	}
 	// Summary
 	content := []string{outputwriter.ScanSummaryContent(issuesCollection, resultContext, includeSecrets, writer)}
@@ -126,7 +226,37 @@ func generatePullRequestSummaryComment(issuesCollection issues.ScansIssuesCollec
 	if vulnerabilitiesContent := outputwriter.GetVulnerabilitiesContent(issuesCollection.ScaVulnerabilities, writer); len(vulnerabilitiesContent) > 0 {
 		content = append(content, vulnerabilitiesContent...)
 	}
-	return outputwriter.GetMainCommentContent(content, true, true, writer)
+	return outputwriter.GetMainCommentContent(append(content, additionalContent...), true, true, writer)
+}
+
+// The secrets are listed by their locations and types only, so the comment doesn't expose them even if the secret review comments are disabled.
+// The email receivers are the security contacts if the exposed secrets email alert is configured.
+func generateSecretsRotationComment(issuesCollection *issues.ScansIssuesCollection, repo *Repository) string {
+	secrets := append(slices.Clone(issuesCollection.SecretsVulnerabilities), issuesCollection.SecretsViolations...)
+	SortSecretsByValidationStatus(secrets)
+	var rows []outputwriter.SecretRotationRow
+	addedLocations := datastructures.MakeSet[string]()
+	for _, secret := range secrets {
+		// The same secret can be reported both as a vulnerability and as a violation
+		location := fmt.Sprintf("%s:%d:%d", secret.File, secret.StartLine, secret.StartColumn)
+		if addedLocations.Exists(location) {
+			continue
+		}
+		addedLocations.Add(location)
+		secretType := secretvalidation.GetSecretType(secret.Snippet, secret.File)
+		rows = append(rows, outputwriter.SecretRotationRow{
+			File:       secret.File,
+			StartLine:  secret.StartLine,
+			SecretType: string(secretType),
+			Status:     getSecretValidationStatus(secret),
+			Guidance:   secretvalidation.GetRotationGuidance(secretType),
+		})
+	}
+	var securityContacts []string
+	if repo.SmtpServer != "" {
+		securityContacts = repo.EmailReceivers.Secrets
+	}
+	return outputwriter.SecretsRotationComment(rows, securityContacts, repo.OutputWriter)
 }
 
 func IsFrogbotRescanComment(comment string) bool {
@@ -145,11 +275,17 @@ func GetSortedPullRequestComments(client vcsclient.VcsClient, repoOwner, repoNam
 	return pullRequestsComments, nil
 }
 
-func addReviewComments(repo *Repository, pullRequestID int, client vcsclient.VcsClient, issues *issues.ScansIssuesCollection) (err error) {
-	commentsToAdd := getNewReviewComments(repo, issues)
+func addReviewComments(repo *Repository, pullRequestID int, client vcsclient.VcsClient, commentsToAdd []ReviewComment) (err error) {
 	if len(commentsToAdd) == 0 {
 		return
 	}
+	if repo.BatchReviewComments && repo.GitProvider == vcsutils.GitHub {
+		e := addGitHubReview(repo, pullRequestID, commentsToAdd)
+		if e == nil {
+			return
+		}
+		log.Debug("couldn't add the review comments in a single pull request review, so they're added one by one: " + e.Error())
+	}
 	// Add review comments for the given data
 	for _, comment := range commentsToAdd {
 		log.Debug("creating a review comment for", comment.Type, comment.Location.File, comment.Location.StartLine, comment.Location.StartColumn)
@@ -165,7 +301,7 @@ func addReviewComments(repo *Repository, pullRequestID int, client vcsclient.Vcs
 }
 
 // Delete existing pull request review comments (Applicable, Sast, Iac)
-func DeleteExistingPullRequestReviewComments(repo *Repository, pullRequestID int, client vcsclient.VcsClient) (err error) {
+func DeleteExistingPullRequestReviewComments(repo *Repository, pullRequestID int, client vcsclient.VcsClient, suppressions *PullRequestSuppressions) (err error) {
 	// Get all review comments in PR
 	var existingComments []vcsclient.CommentInfo
 	if existingComments, err = client.ListPullRequestReviewComments(context.Background(), repo.RepoOwner, repo.RepoName, pullRequestID); err != nil {
@@ -173,8 +309,8 @@ func DeleteExistingPullRequestReviewComments(repo *Repository, pullRequestID int
 		return
 	}
 	// Delete old review comments
-	if len(existingComments) > 0 {
-		if err = client.DeletePullRequestReviewComments(context.Background(), repo.RepoOwner, repo.RepoName, pullRequestID, getFrogbotComments(existingComments)...); err != nil {
+	if commentsToDelete := getFrogbotComments(existingComments, suppressions); len(commentsToDelete) > 0 {
+		if err = client.DeletePullRequestReviewComments(context.Background(), repo.RepoOwner, repo.RepoName, pullRequestID, commentsToDelete...); err != nil {
 			err = errors.New("couldn't delete pull request review comment: " + err.Error())
 			return
 		}
@@ -182,9 +318,9 @@ func DeleteExistingPullRequestReviewComments(repo *Repository, pullRequestID int
 	return
 }
 
-func getFrogbotComments(existingComments []vcsclient.CommentInfo) (reviewComments []vcsclient.CommentInfo) {
+func getFrogbotComments(existingComments []vcsclient.CommentInfo, suppressions *PullRequestSuppressions) (reviewComments []vcsclient.CommentInfo) {
 	for _, comment := range existingComments {
-		if outputwriter.IsFrogbotComment(comment.Content) {
+		if outputwriter.IsFrogbotComment(comment.Content) && !suppressions.IsSuppressedFindingsComment(comment) {
 			log.Debug("Deleting comment id:", comment.ID)
 			reviewComments = append(reviewComments, comment)
 		}
@@ -194,28 +330,35 @@ func getFrogbotComments(existingComments []vcsclient.CommentInfo) (reviewComment
 
 func getNewReviewComments(repo *Repository, issues *issues.ScansIssuesCollection) (commentsToAdd []ReviewComment) {
 	writer := repo.OutputWriter
+	options := writer.ReportingOptions()
 	// CVE Applicable Evidence review comments
-	for _, applicableEvidences := range issues.GetApplicableEvidences() {
-		commentsToAdd = append(commentsToAdd, generateReviewComment(ApplicableComment, applicableEvidences.Evidence.Location, generateApplicabilityReviewContent(applicableEvidences, writer)))
+	if options.IsSectionShown(outputwriter.VulnerabilitiesSection) {
+		for _, applicableEvidences := range issues.GetApplicableEvidences() {
+			commentsToAdd = append(commentsToAdd, generateReviewComment(ApplicableComment, applicableEvidences.Evidence.Location, generateApplicabilityReviewContent(applicableEvidences, writer)))
+		}
 	}
 	// IAC review comments
-	for _, iac := range issues.IacVulnerabilities {
-		commentsToAdd = append(commentsToAdd, generateReviewComment(IacComment, iac.Location, generateSourceCodeReviewContent(IacComment, false, writer, iac)))
-	}
-	for _, similarIacIssues := range groupSimilarJasIssues(issues.IacViolations) {
-		commentsToAdd = append(commentsToAdd, generateReviewComment(IacComment, similarIacIssues.Location, generateSourceCodeReviewContent(IacComment, true, writer, similarIacIssues.issues...)))
+	if options.IsSectionShown(outputwriter.IacSection) {
+		for _, iac := range issues.IacVulnerabilities {
+			commentsToAdd = append(commentsToAdd, generateReviewComment(IacComment, iac.Location, generateSourceCodeReviewContent(IacComment, false, writer, iac)))
+		}
+		for _, similarIacIssues := range groupSimilarJasIssues(issues.IacViolations) {
+			commentsToAdd = append(commentsToAdd, generateReviewComment(IacComment, similarIacIssues.Location, generateSourceCodeReviewContent(IacComment, true, writer, similarIacIssues.issues...)))
+		}
 	}
 	// SAST review comments
-	for _, sast := range issues.SastVulnerabilities {
-		commentsToAdd = append(commentsToAdd, generateReviewComment(SastComment, sast.Location, generateSourceCodeReviewContent(SastComment, false, writer, sast)))
-	}
-	if len(issues.SastViolations) > 0 {
-		for _, similarSastIssues := range groupSimilarJasIssues(issues.SastViolations) {
-			commentsToAdd = append(commentsToAdd, generateReviewComment(SastComment, similarSastIssues.Location, generateSourceCodeReviewContent(SastComment, true, writer, similarSastIssues.issues...)))
+	if options.IsSectionShown(outputwriter.SastSection) {
+		for _, sast := range issues.SastVulnerabilities {
+			commentsToAdd = append(commentsToAdd, generateReviewComment(SastComment, sast.Location, generateSourceCodeReviewContent(SastComment, false, writer, sast)))
+		}
+		if len(issues.SastViolations) > 0 {
+			for _, similarSastIssues := range groupSimilarJasIssues(issues.SastViolations) {
+				commentsToAdd = append(commentsToAdd, generateReviewComment(SastComment, similarSastIssues.Location, generateSourceCodeReviewContent(SastComment, true, writer, similarSastIssues.issues...)))
+			}
 		}
 	}
 	// Secrets review comments
-	if !repo.Params.PullRequestSecretComments {
+	if !repo.Params.PullRequestSecretComments || !options.IsSectionShown(outputwriter.SecretsSection) {
 		return
 	}
 	for _, secret := range issues.SecretsVulnerabilities {
@@ -277,17 +420,30 @@ func generateReviewComment(commentType ReviewCommentType, location formats.Locat
 }
 
 func generateApplicabilityReviewContent(issue issues.ApplicableEvidences, writer outputwriter.OutputWriter) string {
-	return outputwriter.GenerateReviewCommentContent(outputwriter.ApplicableCveReviewContent(issue, writer), writer)
+	return outputwriter.GenerateReviewCommentContent(outputwriter.ApplicableCveReviewContent(issue, writer), writer) + outputwriter.FindingIdsComment(issue.FindingId)
 }
 
 func generateSourceCodeReviewContent(commentType ReviewCommentType, violation bool, writer outputwriter.OutputWriter, similarIssues ...formats.SourceCodeRow) (content string) {
 	switch commentType {
 	case IacComment:
-		return outputwriter.GenerateReviewCommentContent(outputwriter.IacReviewContent(violation, writer, similarIssues...), writer)
+		content = outputwriter.GenerateReviewCommentContent(outputwriter.IacReviewContent(violation, writer, similarIssues...), writer)
 	case SastComment:
-		return outputwriter.GenerateReviewCommentContent(outputwriter.SastReviewContent(violation, writer, similarIssues...), writer)
+		content = outputwriter.GenerateReviewCommentContent(outputwriter.SastReviewContent(violation, writer, similarIssues...), writer)
 	case SecretComment:
-		return outputwriter.GenerateReviewCommentContent(outputwriter.SecretReviewContent(violation, writer, similarIssues...), writer)
+		content = outputwriter.GenerateReviewCommentContent(outputwriter.SecretReviewContent(violation, writer, similarIssues...), writer)
+	default:
+		return
+	}
+	return content + outputwriter.FindingIdsComment(getSourceCodeFindingIds(similarIssues...)...)
+}
+
+func getSourceCodeFindingIds(similarIssues ...formats.SourceCodeRow) (findingIds []string) {
+	addedIds := datastructures.MakeSet[string]()
+	for _, issue := range similarIssues {
+		if findingId := issues.GetSourceCodeFindingId(issue); !addedIds.Exists(findingId) {
+			addedIds.Add(findingId)
+			findingIds = append(findingIds, findingId)
+		}
 	}
 	return
 }
@@ -1,20 +1,35 @@
 package utils
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
 	"github.com/jfrog/frogbot/v2/utils/issues"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/secretvalidation"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetFrogbotReviewComments(t *testing.T) {
 	testCases := []struct {
 		name             string
 		existingComments []vcsclient.CommentInfo
+		suppressions     *PullRequestSuppressions
 		expectedOutput   []vcsclient.CommentInfo
 	}{
 		{
@@ -40,10 +55,21 @@ func TestGetFrogbotReviewComments(t *testing.T) {
 				{Content: outputwriter.ReviewCommentId},
 			},
 		},
+		{
+			name: "Keeps the comments of suppressed findings",
+			existingComments: []vcsclient.CommentInfo{
+				{ID: 1, Content: outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + "A suppressed finding"},
+				{ID: 2, Content: outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + "A Frogbot review comment"},
+			},
+			suppressions: &PullRequestSuppressions{findingIds: datastructures.MakeSet[string](), commentIds: datastructures.MakeSetFromElements[int64](1)},
+			expectedOutput: []vcsclient.CommentInfo{
+				{ID: 2, Content: outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + "A Frogbot review comment"},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			output := getFrogbotComments(tc.existingComments)
+			output := getFrogbotComments(tc.existingComments, tc.suppressions)
 			assert.ElementsMatch(t, tc.expectedOutput, output)
 		})
 	}
@@ -311,7 +337,7 @@ func TestGetNewReviewComments(t *testing.T) {
 								},
 								Finding:       "secret finding",
 								Applicability: &formats.Applicability{Status: "Inactive"},
-							}), writer),
+							}), writer) + outputwriter.FindingIdsComment(issues.GetJasFindingId("secret-rule", "index.js", "access token exposed")),
 						},
 						PullRequestDiff: vcsclient.PullRequestDiff{
 							OriginalFilePath:    "index.js",
@@ -429,7 +455,7 @@ func TestGetNewReviewComments(t *testing.T) {
 										Watch: "watch2",
 									},
 								},
-							), writer),
+							), writer) + outputwriter.FindingIdsComment(issues.GetJasFindingId("secret-rule", "index.js", "access token exposed")),
 						},
 						PullRequestDiff: vcsclient.PullRequestDiff{
 							OriginalFilePath:    "index.js",
@@ -520,7 +546,7 @@ func TestGetNewReviewComments(t *testing.T) {
 							Content: outputwriter.GenerateReviewCommentContent(outputwriter.ApplicableCveReviewContent(issues.ApplicableEvidences{
 								Evidence: formats.Evidence{Location: formats.Location{File: "file1", StartLine: 1, StartColumn: 10, EndLine: 2, EndColumn: 11, Snippet: "snippet"}},
 								Severity: "Low", IssueId: "CVE-2023-4321", CveSummary: "summary-2", ImpactedDependency: "component-C",
-							}, writer), writer),
+							}, writer), writer) + outputwriter.FindingIdsComment(issues.GetScaRuleFindingId("CVE-2023-4321_component-C_")),
 						},
 						PullRequestDiff: vcsclient.PullRequestDiff{
 							OriginalFilePath:    "file1",
@@ -557,7 +583,7 @@ func TestGetNewReviewComments(t *testing.T) {
 									RuleId: "aws-violation",
 								},
 								Finding: "Missing auto upgrade was detected",
-							}), writer),
+							}), writer) + outputwriter.FindingIdsComment(issues.GetJasFindingId("aws-violation", "file1", "aws-violation")),
 						},
 						PullRequestDiff: vcsclient.PullRequestDiff{
 							OriginalFilePath:    "file1",
@@ -594,7 +620,7 @@ func TestGetNewReviewComments(t *testing.T) {
 									RuleId: "sast-rule",
 								},
 								Finding: "XSS Vulnerability",
-							}), writer),
+							}), writer) + outputwriter.FindingIdsComment(issues.GetJasFindingId("sast-rule", "file1", "snippet")),
 						},
 						PullRequestDiff: vcsclient.PullRequestDiff{
 							OriginalFilePath:    "file1",
@@ -621,3 +647,188 @@ func TestGetNewReviewComments(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNewReviewCommentsOfShownSections(t *testing.T) {
+	sourceCodeIssue := func(ruleId, file string) formats.SourceCodeRow {
+		return formats.SourceCodeRow{
+			SeverityDetails: formats.SeverityDetails{Severity: "High"},
+			ScannerInfo:     formats.ScannerInfo{RuleId: ruleId},
+			Location:        formats.Location{File: file, StartLine: 1, EndLine: 1, Snippet: "snippet"},
+		}
+	}
+	issuesCollection := &issues.ScansIssuesCollection{
+		IacVulnerabilities:     []formats.SourceCodeRow{sourceCodeIssue("iac-rule", "main.tf")},
+		SastVulnerabilities:    []formats.SourceCodeRow{sourceCodeIssue("sast-rule", "index.js")},
+		SecretsVulnerabilities: []formats.SourceCodeRow{sourceCodeIssue("secret-rule", "config.yml")},
+	}
+	testCases := []struct {
+		name          string
+		showSections  []string
+		expectedTypes []ReviewCommentType
+	}{
+		{name: "All sections", expectedTypes: []ReviewCommentType{IacComment, SastComment, SecretComment}},
+		{name: "Secrets only", showSections: []string{outputwriter.SecretsSection}, expectedTypes: []ReviewCommentType{SecretComment}},
+		{name: "Code sections", showSections: []string{outputwriter.IacSection, outputwriter.SastSection}, expectedTypes: []ReviewCommentType{IacComment, SastComment}},
+		{name: "No code sections", showSections: []string{outputwriter.VulnerabilitiesSection}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writer := &outputwriter.StandardOutput{}
+			writer.SetReportingOptions(outputwriter.ReportingOptions{ShowSections: tc.showSections})
+			repo := &Repository{OutputWriter: writer, Params: Params{Git: Git{PullRequestSecretComments: true}}}
+			var types []ReviewCommentType
+			for _, comment := range getNewReviewComments(repo, issuesCollection) {
+				types = append(types, comment.Type)
+			}
+			assert.Equal(t, tc.expectedTypes, types)
+		})
+	}
+}
+
+func TestGeneratePullRequestComments(t *testing.T) {
+	sastIssue := formats.SourceCodeRow{
+		SeverityDetails: formats.SeverityDetails{Severity: "High", SeverityNumValue: 13},
+		Finding:         "Insecure random",
+		ScannerInfo:     formats.ScannerInfo{RuleId: "js-insecure-random"},
+		Location:        formats.Location{File: "index.js", StartLine: 5, StartColumn: 6, EndLine: 7, EndColumn: 8, Snippet: "Math.random()"},
+	}
+	testCases := []struct {
+		name                   string
+		provider               vcsutils.VcsProvider
+		params                 Params
+		issues                 *issues.ScansIssuesCollection
+		expectSummaryComment   bool
+		expectedReviewComments int
+	}{
+		{
+			name:     "No issues",
+			provider: vcsutils.GitHub,
+			issues:   &issues.ScansIssuesCollection{},
+		},
+		{
+			name:                 "No issues with a comment on success",
+			provider:             vcsutils.GitLab,
+			params:               Params{Scan: Scan{AddPrCommentOnSuccess: true}},
+			issues:               &issues.ScansIssuesCollection{},
+			expectSummaryComment: true,
+		},
+		{
+			name:                   "SAST issue",
+			provider:               vcsutils.GitHub,
+			issues:                 &issues.ScansIssuesCollection{SastVulnerabilities: []formats.SourceCodeRow{sastIssue}},
+			expectSummaryComment:   true,
+			expectedReviewComments: 1,
+		},
+		{
+			name:                   "SAST issue in Bitbucket Server",
+			provider:               vcsutils.BitbucketServer,
+			issues:                 &issues.ScansIssuesCollection{SastVulnerabilities: []formats.SourceCodeRow{sastIssue}},
+			expectSummaryComment:   true,
+			expectedReviewComments: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			comments := GeneratePullRequestComments(tc.issues, results.ResultContext{}, tc.provider, tc.params)
+			writer := outputwriter.GetCompatibleOutputWriter(tc.provider)
+			if tc.expectSummaryComment {
+				assert.Equal(t, generatePullRequestSummaryComment(*tc.issues, results.ResultContext{}, false, false, writer), comments.SummaryComments)
+			} else {
+				assert.Empty(t, comments.SummaryComments)
+			}
+			assert.Len(t, comments.ReviewComments, tc.expectedReviewComments)
+			for _, reviewComment := range comments.ReviewComments {
+				assert.Equal(t, SastComment, reviewComment.Type)
+				assert.Equal(t, generateSourceCodeReviewContent(SastComment, false, writer, sastIssue), reviewComment.CommentInfo.Content)
+			}
+		})
+	}
+}
+
+func TestGeneratePullRequestCommentsBlockOnSecrets(t *testing.T) {
+	secret := formats.SourceCodeRow{
+		SeverityDetails: formats.SeverityDetails{Severity: "High", SeverityNumValue: 13},
+		Finding:         "Secret keys were found",
+		ScannerInfo:     formats.ScannerInfo{RuleId: "REQ.SECRET.KEYS"},
+		Location:        formats.Location{File: "config/settings.py", StartLine: 12, StartColumn: 5, EndLine: 12, EndColumn: 25, Snippet: "AKI************"},
+		Applicability:   &formats.Applicability{Status: jasutils.Active.String()},
+	}
+	issuesCollection := &issues.ScansIssuesCollection{SecretsVulnerabilities: []formats.SourceCodeRow{secret}, SecretsViolations: []formats.SourceCodeRow{secret}}
+	params := Params{Scan: Scan{BlockOnSecrets: true, EmailDetails: EmailDetails{SmtpServer: "smtp.example.com", EmailReceivers: EmailReceivers{Secrets: []string{"security@example.com"}}}}}
+
+	comments := GeneratePullRequestComments(issuesCollection, results.ResultContext{}, vcsutils.GitHub, params)
+	require.NotEmpty(t, comments.SummaryComments)
+	urgentComment := comments.SummaryComments[0]
+	assert.True(t, outputwriter.IsFrogbotComment(urgentComment))
+	assert.Contains(t, urgentComment, "Secrets Found – Rotate Now")
+	// The secret that is reported both as a vulnerability and as a violation is listed once, without its snippet
+	assert.Equal(t, 1, strings.Count(urgentComment, "config/settings.py:12"))
+	assert.NotContains(t, urgentComment, secret.Snippet)
+	assert.Contains(t, urgentComment, string(secretvalidation.AwsAccessKey))
+	assert.Contains(t, urgentComment, secretvalidation.GetRotationGuidance(secretvalidation.AwsAccessKey))
+	assert.Contains(t, urgentComment, "[security@example.com](mailto:security@example.com)")
+
+	// The urgent comment isn't added without blockOnSecrets
+	params.BlockOnSecrets = false
+	for _, comment := range GeneratePullRequestComments(issuesCollection, results.ResultContext{}, vcsutils.GitHub, params).SummaryComments {
+		assert.NotContains(t, comment, "Rotate Now")
+	}
+}
+
+func TestAddReviewComments(t *testing.T) {
+	repo := &Repository{Params: Params{Git: Git{RepoOwner: "jfrog", RepoName: "frogbot"}}}
+	repo.GitProvider = vcsutils.GitLab
+	repo.setOutputWriterDetails()
+	changedFileComment := generateReviewComment(SastComment, formats.Location{File: "index.js", StartLine: 5, StartColumn: 6, EndLine: 7, EndColumn: 8}, "changed file finding")
+	unchangedFileComment := generateReviewComment(IacComment, formats.Location{File: "main.tf", StartLine: 1, StartColumn: 1, EndLine: 2, EndColumn: 1}, "unchanged file finding")
+
+	// In GitLab, review comments are added as discussions on the merge request diff.
+	// Locations that aren't part of the diff fall back to regular comments that describe the location.
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	gomock.InOrder(
+		mockVcsClient.EXPECT().AddPullRequestReviewComments(context.Background(), "jfrog", "frogbot", 1, changedFileComment.CommentInfo).Return(nil),
+		mockVcsClient.EXPECT().AddPullRequestReviewComments(context.Background(), "jfrog", "frogbot", 1, unchangedFileComment.CommentInfo).Return(errors.New("could not find changes to main.tf in the current merge request")),
+		mockVcsClient.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", outputwriter.GetFallbackReviewCommentContent("unchanged file finding", unchangedFileComment.Location), 1).Return(nil),
+	)
+	assert.NoError(t, addReviewComments(repo, 1, mockVcsClient, []ReviewComment{changedFileComment, unchangedFileComment}))
+}
+
+func TestAddReviewCommentsInGitHubReview(t *testing.T) {
+	var requests []string
+	var review map[string]any
+	rejectReview := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		if rejectReview {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	repo := &Repository{Params: Params{Git: Git{RepoOwner: "jfrog", RepoName: "frogbot", VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}}}}
+	repo.GitProvider = vcsutils.GitHub
+	repo.BatchReviewComments = true
+	repo.setOutputWriterDetails()
+	multiLineComment := generateReviewComment(SastComment, formats.Location{File: "index.js", StartLine: 5, StartColumn: 6, EndLine: 7, EndColumn: 8}, "multi-line finding")
+	singleLineComment := generateReviewComment(IacComment, formats.Location{File: "main.tf", StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 10}, "single line finding")
+
+	// All the review comments are added in a single request
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	assert.NoError(t, addReviewComments(repo, 1, mockVcsClient, []ReviewComment{multiLineComment, singleLineComment}))
+	assert.Equal(t, []string{"POST /repos/jfrog/frogbot/pulls/1/reviews"}, requests)
+	assert.Equal(t, "COMMENT", review["event"])
+	comments := review["comments"].([]any)
+	require.Len(t, comments, 2)
+	assert.Equal(t, map[string]any{"path": "index.js", "body": multiLineComment.CommentInfo.Content, "start_line": float64(5), "line": float64(7), "side": "RIGHT"}, comments[0])
+	assert.Equal(t, map[string]any{"path": "main.tf", "body": singleLineComment.CommentInfo.Content, "line": float64(1), "side": "RIGHT"}, comments[1])
+
+	// The review comments are added one by one if GitHub rejects the review
+	rejectReview = true
+	gomock.InOrder(
+		mockVcsClient.EXPECT().AddPullRequestReviewComments(context.Background(), "jfrog", "frogbot", 1, multiLineComment.CommentInfo).Return(nil),
+		mockVcsClient.EXPECT().AddPullRequestReviewComments(context.Background(), "jfrog", "frogbot", 1, singleLineComment.CommentInfo).Return(nil),
+	)
+	assert.NoError(t, addReviewComments(repo, 1, mockVcsClient, []ReviewComment{multiLineComment, singleLineComment}))
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils/azurepullrequests"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/prcomments"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Collapses the previous summary comments of Frogbot into a "Resolved in later commits" section instead of deleting them,
+// so the pull request keeps a history of what Frogbot reported at each of its revisions.
+// The comments of the findings are deleted as usual, since the scan adds the comments of the findings that still exist.
+func CollapsePullRequestComments(repo *Repository, client vcsclient.VcsClient, pullRequestID int, suppressions *PullRequestSuppressions) (err error) {
+	err = CollapseExistingPullRequestComments(repo, client, suppressions)
+	return errors.Join(err, DeleteExistingPullRequestReviewComments(repo, pullRequestID, client, suppressions))
+}
+
+// Collapses the existing summary comments of Frogbot, and deletes the other existing regular comments of Frogbot (fallback review comments).
+// A summary comment that couldn't be edited is deleted.
+func CollapseExistingPullRequestComments(repository *Repository, client vcsclient.VcsClient, suppressions *PullRequestSuppressions) error {
+	prDetails := repository.PullRequestDetails
+	comments, err := listPullRequestCommentsContent(repository, client)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get comments. the following details were used in order to fetch the comments: <%s/%s> pull request #%d. the error received: %s",
+			repository.RepoOwner, repository.RepoName, int(prDetails.ID), err.Error())
+	}
+	editor, err := prcomments.NewEditor(repository.GitProvider, repository.VcsInfo, prDetails.Target.Owner, prDetails.Target.Repository)
+	if err != nil {
+		return err
+	}
+	collapsed := 0
+	for _, comment := range getFrogbotComments(comments, suppressions) {
+		// The comments of findings have the IDs of their findings, unlike the summary comments
+		if len(outputwriter.GetFindingIds(comment.Content)) == 0 {
+			e := editor.EditComment(int(prDetails.ID), comment.ID, outputwriter.GetResolvedCommentContent(comment.Content, repository.OutputWriter))
+			if e == nil {
+				collapsed++
+				continue
+			}
+			log.Warn(fmt.Sprintf("Couldn't collapse the previous Frogbot comment %d, so it is deleted: %s", comment.ID, e.Error()))
+		}
+		if err = client.DeletePullRequestComment(context.Background(), prDetails.Target.Owner, prDetails.Target.Repository, int(prDetails.ID), int(comment.ID)); err != nil {
+			return err
+		}
+	}
+	if collapsed > 0 {
+		log.Info(fmt.Sprintf("Collapsed %d previous Frogbot comments of the pull request", collapsed))
+	}
+	return nil
+}
+
+// The Git client joins the comments of each Azure Repos thread with their authors, so the content of the first comment of each thread is read from the thread.
+// The comments of the other Git providers are returned by the Git client.
+func listPullRequestCommentsContent(repository *Repository, client vcsclient.VcsClient) (comments []vcsclient.CommentInfo, err error) {
+	prDetails := repository.PullRequestDetails
+	if repository.GitProvider != vcsutils.AzureRepos {
+		return GetSortedPullRequestComments(client, prDetails.Target.Owner, prDetails.Target.Repository, int(prDetails.ID))
+	}
+	threads, err := azurepullrequests.NewClient(repository.VcsInfo, prDetails.Target.Repository).ListThreads(int(prDetails.ID))
+	if err != nil {
+		return
+	}
+	for _, thread := range threads {
+		if content := thread.Content(); content != "" {
+			comments = append(comments, vcsclient.CommentInfo{ID: thread.Id, Content: content})
+		}
+	}
+	return
+}
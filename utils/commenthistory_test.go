@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapseExistingPullRequestComments(t *testing.T) {
+	summaryComment := outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + "## Summary"
+	fallbackReviewComment := outputwriter.MarkdownComment(outputwriter.ReviewCommentId) + "finding details" + outputwriter.FindingIdsComment("sast-1")
+	editedComments := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		editedComments[r.URL.Path] = body["body"]
+		// The comment was deleted by a concurrent scan
+		if r.URL.Path == "/repos/jfrog/frogbot/issues/comments/4" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo := &Repository{Params: Params{Git: Git{RepoOwner: "jfrog", RepoName: "frogbot", VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL}}}}
+	repo.GitProvider = vcsutils.GitHub
+	repo.PullRequestDetails = vcsclient.PullRequestInfo{ID: 3, Target: vcsclient.BranchInfo{Owner: "jfrog", Repository: "frogbot"}}
+	repo.setOutputWriterDetails()
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 3).Return([]vcsclient.CommentInfo{
+		{ID: 1, Content: summaryComment},
+		{ID: 2, Content: fallbackReviewComment},
+		{ID: 3, Content: "LGTM"},
+		{ID: 4, Content: summaryComment},
+	}, nil)
+	// The fallback review comments are deleted, and so are the summary comments that couldn't be collapsed
+	client.EXPECT().DeletePullRequestComment(context.Background(), "jfrog", "frogbot", 3, 2).Return(nil)
+	client.EXPECT().DeletePullRequestComment(context.Background(), "jfrog", "frogbot", 3, 4).Return(nil)
+
+	require.NoError(t, CollapseExistingPullRequestComments(repo, client, nil))
+	assert.Len(t, editedComments, 2)
+	assert.Equal(t, outputwriter.GetResolvedCommentContent(summaryComment, repo.OutputWriter), editedComments["/repos/jfrog/frogbot/issues/comments/1"])
+}
@@ -0,0 +1,92 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockXrayScanner struct {
+	scannedIds []string
+	response   *services.ScanResponse
+}
+
+func (ms *mockXrayScanner) ScanGraph(params services.XrayGraphScanParams) (string, error) {
+	ms.scannedIds = nil
+	for _, node := range params.DependenciesGraph.Nodes {
+		ms.scannedIds = append(ms.scannedIds, node.Id)
+	}
+	return "scan-id", nil
+}
+
+func (ms *mockXrayScanner) GetScanGraphResults(string, string, bool, bool, bool) (*services.ScanResponse, error) {
+	return ms.response, nil
+}
+
+func TestAnalyzer(t *testing.T) {
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	for _, file := range []string{DescriptorFile, LockFile} {
+		content, err := os.ReadFile(filepath.Join(testProjectDir, file))
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "web"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "web", file), content, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(targetDir, LockFile), []byte(`{"packages":[
+		{"name":"guzzlehttp/guzzle","version":"7.4.0"},
+		{"name":"guzzlehttp/psr7","version":"2.1.0"},
+		{"name":"phpunit/phpunit","version":"10.0.0"}
+	]}`), 0644))
+	scanner := &mockXrayScanner{response: &services.ScanResponse{Vulnerabilities: []services.Vulnerability{
+		{IssueId: "XRAY-1", Severity: "High", Summary: "Improper input validation", Cves: []services.Cve{{Id: "CVE-2022-31090"}}, Components: map[string]services.Component{"composer://guzzlehttp/guzzle:7.4.0": {FixedVersions: []string{"[7.4.5]"}}}},
+		{IssueId: "XRAY-2", Severity: "Medium", Components: map[string]services.Component{"composer://symfony/polyfill-ctype:1.28.0": {}}},
+	}}}
+
+	// Only the packages that the pull request adds are scanned
+	analyzer := newAnalyzer(scanner, "3.107.0")
+	require.NoError(t, analyzer.SetTargetBranch(targetDir))
+	vulnerabilities, err := analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"composer://symfony/http-kernel:6.4.0", "composer://symfony/polyfill-ctype:1.28.0"}, scanner.scannedIds)
+	require.Len(t, vulnerabilities, 1)
+	// The transitive package is brought in by the package that composer.json requires
+	assert.Equal(t, [][]formats.ComponentRow{{
+		{Name: "web/composer.lock"},
+		{Name: "symfony/http-kernel", Version: "6.4.0", Location: &formats.Location{File: "web/composer.lock"}},
+		{Name: "symfony/polyfill-ctype", Version: "1.28.0", Location: &formats.Location{File: "web/composer.lock"}},
+	}}, vulnerabilities[0].ImpactPaths)
+	assert.Equal(t, "symfony/http-kernel", vulnerabilities[0].Components[0].Name)
+	assert.Equal(t, "Composer", vulnerabilities[0].ImpactedDependencyType)
+	assert.Equal(t, Technology, vulnerabilities[0].Technology)
+
+	// All the packages are scanned without a target branch
+	analyzer = newAnalyzer(scanner, "3.107.0")
+	vulnerabilities, err = analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.Len(t, scanner.scannedIds, 5)
+	require.Len(t, vulnerabilities, 2)
+	assert.Equal(t, "guzzlehttp/guzzle", vulnerabilities[0].ImpactedDependencyName)
+	assert.Equal(t, "High", vulnerabilities[0].Severity)
+	assert.Equal(t, "Improper input validation", vulnerabilities[0].Summary)
+	assert.Equal(t, []string{"[7.4.5]"}, vulnerabilities[0].FixedVersions)
+	assert.Equal(t, []formats.CveRow{{Id: "CVE-2022-31090"}}, vulnerabilities[0].Cves)
+	assert.Len(t, vulnerabilities[0].ImpactPaths[0], 2)
+	assert.Equal(t, "XRAY-2", vulnerabilities[1].IssueId)
+
+	// The packages are reported once for all the projects
+	scanner.scannedIds = nil
+	vulnerabilities, err = analyzer.Analyze(sourceDir, filepath.Join(sourceDir, "web"))
+	require.NoError(t, err)
+	assert.Empty(t, scanner.scannedIds)
+	assert.Empty(t, vulnerabilities)
+
+	// The analysis is disabled
+	var disabled *Analyzer
+	vulnerabilities, err = disabled.Analyze(sourceDir, sourceDir)
+	assert.NoError(t, err)
+	assert.Empty(t, vulnerabilities)
+}
@@ -0,0 +1,198 @@
+package composer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// The file of a Composer project, which declares the version constraints of its direct dependencies
+	DescriptorFile = "composer.json"
+	// The lockfile of a Composer project, which pins the versions of all its dependencies
+	LockFile = "composer.lock"
+)
+
+var (
+	// The directories that don't contain the lockfiles of the projects. The installed packages are in the vendor directory.
+	skippedDirs = []string{".git", "node_modules", "vendor"}
+	// Matches the versions of the tags of the packages, such as 'v6.4.0'. Xray identifies them without the 'v' prefix.
+	taggedVersionRegex = regexp.MustCompile(`^v\d`)
+)
+
+// Dependency is a package that a Composer lockfile pins
+type Dependency struct {
+	// The name of the package, such as 'symfony/http-kernel'
+	Name    string
+	Version string
+	// The Xray component ID, such as 'composer://symfony/http-kernel:6.4.0'
+	XrayId string
+	// The names of the packages that the package requires, in the same lockfile
+	Dependencies []string
+}
+
+// Lockfile holds the packages that a composer.lock file pins
+type Lockfile struct {
+	Path         string
+	Dependencies []Dependency
+	// The names of the packages that the composer.json file of the lockfile requires
+	Direct []string
+}
+
+// Returns the Composer lockfiles in the directories and their subdirectories that pin any packages
+func FindLockfiles(dirs ...string) (lockfiles []Lockfile, err error) {
+	visited := map[string]bool{}
+	for _, dir := range dirs {
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, innerErr error) error {
+			if innerErr != nil {
+				return innerErr
+			}
+			if d.IsDir() {
+				if path != dir && slices.Contains(skippedDirs, d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			// The working directories of a project may be nested
+			if d.Name() != LockFile || visited[path] {
+				return nil
+			}
+			visited[path] = true
+			lockfile, e := ParseLockfile(path)
+			if e != nil {
+				return e
+			}
+			if len(lockfile.Dependencies) > 0 {
+				lockfiles = append(lockfiles, lockfile)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for Composer lockfiles in '%s': %s", dir, err.Error())
+		}
+	}
+	return
+}
+
+// Returns the packages that the lockfile pins, including the development packages.
+// The direct dependencies are read from the composer.json file next to the lockfile.
+func ParseLockfile(path string) (lockfile Lockfile, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var lockfileContent struct {
+		Packages    []lockedPackage `json:"packages"`
+		PackagesDev []lockedPackage `json:"packages-dev"`
+	}
+	if err = json.Unmarshal(content, &lockfileContent); err != nil {
+		return lockfile, fmt.Errorf("failed to parse '%s': %s", path, err.Error())
+	}
+	lockfile.Path = path
+	for _, lockedPackage := range append(lockfileContent.Packages, lockfileContent.PackagesDev...) {
+		// The packages of branches, such as 'dev-main', don't have released versions that Xray knows
+		if lockedPackage.Name == "" || lockedPackage.Version == "" || strings.HasPrefix(lockedPackage.Version, "dev-") {
+			continue
+		}
+		version := lockedPackage.Version
+		if taggedVersionRegex.MatchString(version) {
+			version = version[1:]
+		}
+		lockfile.Dependencies = append(lockfile.Dependencies, Dependency{
+			Name:         lockedPackage.Name,
+			Version:      version,
+			XrayId:       fmt.Sprintf("composer://%s:%s", lockedPackage.Name, version),
+			Dependencies: sortedKeys(lockedPackage.Require),
+		})
+	}
+	sort.Slice(lockfile.Dependencies, func(i, j int) bool {
+		return lockfile.Dependencies[i].Name < lockfile.Dependencies[j].Name
+	})
+	lockfile.Direct, err = getDirectDependencies(filepath.Join(filepath.Dir(path), DescriptorFile))
+	return
+}
+
+type lockedPackage struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Require map[string]string `json:"require"`
+}
+
+// Returns the packages that the composer.json file requires, or nothing if the lockfile has no composer.json file
+func getDirectDependencies(descriptorPath string) ([]string, error) {
+	content, err := os.ReadFile(descriptorPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var descriptor struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if err = json.Unmarshal(content, &descriptor); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %s", descriptorPath, err.Error())
+	}
+	direct := sortedKeys(descriptor.Require)
+	for _, name := range sortedKeys(descriptor.RequireDev) {
+		if !slices.Contains(direct, name) {
+			direct = append(direct, name)
+		}
+	}
+	return direct, nil
+}
+
+func sortedKeys(values map[string]string) (keys []string) {
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// Returns the shortest path of package names from a package that the composer.json file requires, to the package.
+// Without a composer.json file, the packages that no other package of the lockfile requires are considered the direct dependencies.
+func (l Lockfile) ImpactPath(name string) []string {
+	parents := map[string][]string{}
+	for _, dependency := range l.Dependencies {
+		for _, child := range dependency.Dependencies {
+			parents[child] = append(parents[child], dependency.Name)
+		}
+	}
+	isDirect := func(current string) bool {
+		if len(l.Direct) > 0 {
+			return slices.Contains(l.Direct, current)
+		}
+		return len(parents[current]) == 0
+	}
+	// Search upwards from the package to the nearest direct dependency
+	previous := map[string]string{name: ""}
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if isDirect(current) {
+			var path []string
+			for node := current; node != ""; node = previous[node] {
+				path = append(path, node)
+			}
+			return path
+		}
+		for _, parent := range parents[current] {
+			if _, seen := previous[parent]; !seen {
+				previous[parent] = current
+				queue = append(queue, parent)
+			}
+		}
+	}
+	// The package isn't required through the direct dependencies
+	return []string{name}
+}
@@ -0,0 +1,68 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testProjectDir = filepath.Join("..", "..", "testdata", "projects", "composer")
+
+func TestFindLockfiles(t *testing.T) {
+	lockfiles, err := FindLockfiles(testProjectDir, testProjectDir)
+	require.NoError(t, err)
+	require.Len(t, lockfiles, 1)
+	assert.Equal(t, filepath.Join(testProjectDir, LockFile), lockfiles[0].Path)
+	// The development packages are pinned as well, and the packages of branches are skipped
+	assert.Equal(t, []Dependency{
+		{Name: "guzzlehttp/guzzle", Version: "7.4.0", XrayId: "composer://guzzlehttp/guzzle:7.4.0", Dependencies: []string{"ext-json", "guzzlehttp/psr7", "php"}},
+		{Name: "guzzlehttp/psr7", Version: "2.1.0", XrayId: "composer://guzzlehttp/psr7:2.1.0", Dependencies: []string{"php"}},
+		{Name: "phpunit/phpunit", Version: "10.0.0", XrayId: "composer://phpunit/phpunit:10.0.0", Dependencies: []string{"php"}},
+		{Name: "symfony/http-kernel", Version: "6.4.0", XrayId: "composer://symfony/http-kernel:6.4.0", Dependencies: []string{"php", "symfony/polyfill-ctype"}},
+		{Name: "symfony/polyfill-ctype", Version: "1.28.0", XrayId: "composer://symfony/polyfill-ctype:1.28.0"},
+	}, lockfiles[0].Dependencies)
+	assert.Equal(t, []string{"guzzlehttp/guzzle", "php", "symfony/http-kernel", "phpunit/phpunit"}, lockfiles[0].Direct)
+}
+
+func TestParseLockfileErrors(t *testing.T) {
+	projectDir := t.TempDir()
+	lockfile := filepath.Join(projectDir, LockFile)
+	require.NoError(t, os.WriteFile(lockfile, []byte("not json"), 0644))
+	_, err := ParseLockfile(lockfile)
+	assert.ErrorContains(t, err, "failed to parse")
+
+	// Without composer.json, the direct dependencies are the packages that nothing requires
+	require.NoError(t, os.WriteFile(lockfile, []byte(`{"packages":[{"name":"a/a","version":"1.0.0"}]}`), 0644))
+	parsed, err := ParseLockfile(lockfile)
+	require.NoError(t, err)
+	assert.Empty(t, parsed.Direct)
+	assert.Len(t, parsed.Dependencies, 1)
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, DescriptorFile), []byte("not json"), 0644))
+	_, err = ParseLockfile(lockfile)
+	assert.ErrorContains(t, err, "failed to parse")
+}
+
+func TestImpactPath(t *testing.T) {
+	lockfile := Lockfile{Dependencies: []Dependency{
+		{Name: "a", Dependencies: []string{"b", "c"}},
+		{Name: "b", Dependencies: []string{"d"}},
+		{Name: "c", Dependencies: []string{"d"}},
+		{Name: "d"},
+		{Name: "e", Dependencies: []string{"f"}},
+		{Name: "f", Dependencies: []string{"e"}},
+	}}
+	assert.Equal(t, []string{"a"}, lockfile.ImpactPath("a"))
+	assert.Equal(t, []string{"a", "b", "d"}, lockfile.ImpactPath("d"))
+	// The packages that only a cycle brings in are considered direct
+	assert.Equal(t, []string{"e"}, lockfile.ImpactPath("e"))
+
+	// The packages that composer.json requires are the direct dependencies, even if other packages require them
+	lockfile.Direct = []string{"a", "c"}
+	assert.Equal(t, []string{"c"}, lockfile.ImpactPath("c"))
+	assert.Equal(t, []string{"c", "d"}, lockfile.ImpactPath("d"))
+	assert.Equal(t, []string{"e"}, lockfile.ImpactPath("e"))
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	configDefaultsKey = "defaults"
+	configParamsKey   = "params"
+)
+
+// Matches ${ENV_VAR} references in the config. The references are escaped by a leading '$', as in $${ENV_VAR}.
+var configEnvVarPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)}`)
+
+// The environment variables of the credentials can't be referred to by the config, so their values can't leak into the parsed config
+var configCredentialEnvVars = []string{JFrogPasswordEnv, JFrogTokenEnv, JFrogRefreshTokenEnv, JFrogOidcTokenEnv, GitTokenEnv, SmtpPasswordEnv, WebhookSecretEnv, GitSigningKeyEnv, GitSigningKeyPassphraseEnv}
+
+// Replaces the ${ENV_VAR} references in the string values of the config with the values of the environment variables.
+// The values are substituted in the parsed string values only, so the values can't change the structure of the config,
+// and the references in the keys and the comments of the config aren't expanded. References to undefined variables fail the config.
+func expandConfigEnvVars(yamlContent []byte) ([]byte, error) {
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(yamlContent, &root); err != nil {
+		return nil, err
+	}
+	undefinedVars, credentialVars := datastructures.MakeSet[string](), datastructures.MakeSet[string]()
+	expandNodeEnvVars(&root, undefinedVars, credentialVars)
+	if credentialVars.Size() > 0 {
+		return nil, fmt.Errorf("the frogbot config can't refer to the environment variables of credentials: %s", sortedJoin(credentialVars))
+	}
+	if undefinedVars.Size() > 0 {
+		return nil, fmt.Errorf("the frogbot config refers to undefined environment variables: %s", sortedJoin(undefinedVars))
+	}
+	return yamlv3.Marshal(&root)
+}
+
+func expandNodeEnvVars(node *yamlv3.Node, undefinedVars, credentialVars *datastructures.Set[string]) {
+	switch node.Kind {
+	case yamlv3.DocumentNode, yamlv3.SequenceNode:
+		for _, child := range node.Content {
+			expandNodeEnvVars(child, undefinedVars, credentialVars)
+		}
+	case yamlv3.MappingNode:
+		// The content of a mapping alternates between the keys and the values
+		for i := 1; i < len(node.Content); i += 2 {
+			expandNodeEnvVars(node.Content[i], undefinedVars, credentialVars)
+		}
+	case yamlv3.ScalarNode:
+		if node.Tag != "!!str" {
+			return
+		}
+		node.Value = configEnvVarPattern.ReplaceAllStringFunc(node.Value, func(reference string) string {
+			if strings.HasPrefix(reference, "$$") {
+				return reference[1:]
+			}
+			name := configEnvVarPattern.FindStringSubmatch(reference)[1]
+			if slices.Contains(configCredentialEnvVars, name) {
+				credentialVars.Add(name)
+				return ""
+			}
+			value, exists := os.LookupEnv(name)
+			if !exists {
+				undefinedVars.Add(name)
+			}
+			return value
+		})
+		// A plain value is parsed by its expanded value, so a reference can set a boolean or a number
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	}
+}
+
+func sortedJoin(values *datastructures.Set[string]) string {
+	sorted := values.ToSlice()
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+// Deep-merges the params of the 'defaults' entry of the config into the params of each repository entry, and removes the 'defaults' entry.
+// The params of a repository override the defaults. Maps are merged by their keys, while lists and other values are replaced as a whole.
+func applyConfigDefaults(yamlContent []byte) ([]byte, error) {
+	var entries []map[interface{}]interface{}
+	if err := yaml.Unmarshal(yamlContent, &entries); err != nil {
+		return nil, err
+	}
+	var defaults map[interface{}]interface{}
+	var repositories []map[interface{}]interface{}
+	for _, entry := range entries {
+		entryDefaults, isDefaults := entry[configDefaultsKey]
+		if !isDefaults {
+			repositories = append(repositories, entry)
+			continue
+		}
+		if defaults != nil {
+			return nil, errors.New("the frogbot config may include only one 'defaults' entry")
+		}
+		if _, hasParams := entry[configParamsKey]; hasParams {
+			return nil, errors.New("the 'defaults' entry of the frogbot config can't include 'params'")
+		}
+		var isMap bool
+		if defaults, isMap = entryDefaults.(map[interface{}]interface{}); !isMap {
+			return nil, errors.New("the 'defaults' entry of the frogbot config must be a map of params")
+		}
+	}
+	if defaults == nil {
+		return yamlContent, nil
+	}
+	for _, repository := range repositories {
+		params, _ := repository[configParamsKey].(map[interface{}]interface{})
+		repository[configParamsKey] = mergeConfigMaps(defaults, params)
+	}
+	return yaml.Marshal(repositories)
+}
+
+// Returns a deep copy of the base map, with the values of the override map merged into it
+func mergeConfigMaps(base, override map[interface{}]interface{}) map[interface{}]interface{} {
+	merged := make(map[interface{}]interface{}, len(base))
+	for key, value := range base {
+		if valueMap, isMap := value.(map[interface{}]interface{}); isMap {
+			value = mergeConfigMaps(valueMap, nil)
+		}
+		merged[key] = value
+	}
+	for key, value := range override {
+		baseMap, isBaseMap := merged[key].(map[interface{}]interface{})
+		valueMap, isMap := value.(map[interface{}]interface{})
+		if isBaseMap && isMap {
+			value = mergeConfigMaps(baseMap, valueMap)
+		}
+		merged[key] = value
+	}
+	return merged
+}
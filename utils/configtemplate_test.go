@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnmarshalFrogbotConfigYamlWithDefaults(t *testing.T) {
+	defer SetEnvsAndAssertWithCallback(t, map[string]string{"FROGBOT_TEST_EMAIL_AUTHOR": "myemail@jfrog.com"})()
+	fileContent, err := os.ReadFile(filepath.Join("..", "testdata", "config", "frogbot-config-test-defaults.yml"))
+	require.NoError(t, err)
+	configAggregator, _, err := unmarshalFrogbotConfigYaml(fileContent)
+	require.NoError(t, err)
+	require.Len(t, configAggregator, 3)
+
+	// Inherits all the defaults
+	npmRepo := configAggregator[0]
+	assert.Equal(t, "npm-repo", npmRepo.RepoName)
+	assert.Equal(t, []string{"master", "main"}, npmRepo.Branches)
+	assert.Equal(t, "myemail@jfrog.com", npmRepo.EmailAuthor)
+	assert.False(t, *npmRepo.FailOnSecurityIssues)
+	assert.Equal(t, []Project{{InstallCommand: "npm ci"}}, npmRepo.Projects)
+	assert.Equal(t, "proj", npmRepo.JFrogProjectKey)
+
+	// Lists replace the defaults as a whole
+	mvnRepo := configAggregator[1]
+	assert.Equal(t, []string{"dev"}, mvnRepo.Branches)
+	assert.Equal(t, "myemail@jfrog.com", mvnRepo.EmailAuthor)
+	assert.Equal(t, []Project{{WorkingDirs: []string{"backend"}}}, mvnRepo.Projects)
+	assert.False(t, *mvnRepo.FailOnSecurityIssues)
+
+	// Anchors and escaped references
+	pipRepo := configAggregator[2]
+	assert.Equal(t, []string{"master", "main"}, pipRepo.Branches)
+	assert.Equal(t, "${NOT_EXPANDED}", pipRepo.EmailAuthor)
+	assert.True(t, *pipRepo.FailOnSecurityIssues)
+	assert.Equal(t, "proj", pipRepo.JFrogProjectKey)
+}
+
+func TestExpandConfigEnvVars(t *testing.T) {
+	defer SetEnvsAndAssertWithCallback(t, map[string]string{
+		"FROGBOT_TEST_REPO":      "npm-repo",
+		"FROGBOT_TEST_EMPTY":     "",
+		"FROGBOT_TEST_FAIL":      "false",
+		"FROGBOT_TEST_INJECTION": "repo\nemailAuthor: attacker@example.com",
+	})()
+	expanded, err := expandConfigEnvVars([]byte("repoName: ${FROGBOT_TEST_REPO}${FROGBOT_TEST_EMPTY}\nbranch: $FROGBOT_TEST_REPO\nescaped: $${FROGBOT_TEST_REPO}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "repoName: npm-repo\nbranch: $FROGBOT_TEST_REPO\nescaped: ${FROGBOT_TEST_REPO}\n", string(expanded))
+
+	// Only the string values are expanded, and the expanded values can't change the structure of the config
+	expanded, err = expandConfigEnvVars([]byte("# ${FROGBOT_TEST_UNDEFINED}\n${FROGBOT_TEST_REPO}: ${FROGBOT_TEST_INJECTION}\nfail: ${FROGBOT_TEST_FAIL}\nquoted: '${FROGBOT_TEST_FAIL}'\n"))
+	require.NoError(t, err)
+	var parsed map[string]any
+	require.NoError(t, yaml.Unmarshal(expanded, &parsed))
+	assert.Equal(t, map[string]any{"${FROGBOT_TEST_REPO}": "repo\nemailAuthor: attacker@example.com", "fail": false, "quoted": "false"}, parsed)
+
+	_, err = expandConfigEnvVars([]byte("repoName: ${FROGBOT_TEST_UNDEFINED_B}\nbranch: ${FROGBOT_TEST_UNDEFINED_A}-${FROGBOT_TEST_UNDEFINED_B}"))
+	assert.EqualError(t, err, "the frogbot config refers to undefined environment variables: FROGBOT_TEST_UNDEFINED_A, FROGBOT_TEST_UNDEFINED_B")
+
+	_, err = expandConfigEnvVars([]byte("emailAuthor: ${JF_GIT_TOKEN}\nsmtp:\n  - ${JF_SMTP_PASSWORD}"))
+	assert.EqualError(t, err, "the frogbot config can't refer to the environment variables of credentials: JF_GIT_TOKEN, JF_SMTP_PASSWORD")
+}
+
+func TestApplyConfigDefaultsErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        string
+		expectedError string
+	}{
+		{name: "Multiple defaults", config: `[{defaults: {}}, {defaults: {}}]`, expectedError: "only one 'defaults' entry"},
+		{name: "Defaults with params", config: `[{defaults: {scan: {}}, params: {git: {repoName: repo}}}]`, expectedError: "can't include 'params'"},
+		{name: "Defaults that aren't a map", config: `[{defaults: [scan]}]`, expectedError: "must be a map of params"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := applyConfigDefaults([]byte(tc.config))
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
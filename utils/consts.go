@@ -28,9 +28,13 @@ const (
 	JFrogUrlEnv              = "JF_URL"
 	jfrogXrayUrlEnv          = "JF_XRAY_URL"
 	jfrogArtifactoryUrlEnv   = "JF_ARTIFACTORY_URL"
-	jfrogReleasesRepoEnv     = "JF_RELEASES_REPO"
+	JFrogReleasesRepoEnv     = "JF_RELEASES_REPO"
 	JFrogPasswordEnv         = "JF_PASSWORD"
 	JFrogTokenEnv            = "JF_ACCESS_TOKEN"
+	JFrogRefreshTokenEnv     = "JF_REFRESH_TOKEN"
+	JFrogOidcProviderNameEnv = "JF_OIDC_PROVIDER_NAME"
+	JFrogOidcAudienceEnv     = "JF_OIDC_AUDIENCE"
+	JFrogOidcTokenEnv        = "JF_OIDC_TOKEN"
 	JfrogUseConfigProfileEnv = "JF_USE_CONFIG_PROFILE"
 	JfrogConfigProfileEnv    = "JF_CONFIG_PROFILE"
 
@@ -42,6 +46,49 @@ const (
 	GitUsernameEnv                   = "JF_GIT_USERNAME"
 	GitUseLocalRepositoryEnv         = "JF_USE_LOCAL_REPOSITORY"
 	UseMostCommonAncestorAsTargetEnv = "JF_USE_MOST_COMMON_ANCESTOR_AS_TARGET"
+	GitDownloadRetriesEnv            = "JF_GIT_DOWNLOAD_RETRIES"
+	GitSubmodulesEnv                 = "JF_GIT_SUBMODULES"
+	GitShallowCloneEnv               = "JF_GIT_SHALLOW_CLONE"
+	GitSeparateFixesMinSeverityEnv   = "JF_GIT_SEPARATE_FIXES_MIN_SEVERITY"
+	TrackUnfixableVulnerabilitiesEnv = "JF_TRACK_UNFIXABLE_VULNERABILITIES"
+	AzureWorkItemTypeEnv             = "JF_AZURE_WORK_ITEM_TYPE"
+	BranchesSummaryIssueEnv          = "JF_BRANCHES_SUMMARY_ISSUE"
+	RepositoriesSummaryRepoEnv       = "JF_REPOSITORIES_SUMMARY_REPO"
+	BranchBaselinesFileEnv           = "JF_BRANCH_BASELINES_FILE"
+	ScanHistoryFileEnv               = "JF_SCAN_HISTORY_FILE"
+	ScanHistoryArtifactoryPathEnv    = "JF_SCAN_HISTORY_ARTIFACTORY_PATH"
+	PullRequestsStateFileEnv         = "JF_PULL_REQUESTS_STATE_FILE"
+	RateLimitThresholdEnv            = "JF_GIT_RATE_LIMIT_THRESHOLD"
+	RateLimitMaxWaitEnv              = "JF_GIT_RATE_LIMIT_MAX_WAIT"
+	BotPullRequestAuthorsEnv         = "JF_BOT_PULL_REQUEST_AUTHORS"
+	MentionCodeOwnersEnv             = "JF_MENTION_CODE_OWNERS"
+	DisallowForkPullRequestsEnv      = "JF_DISALLOW_FORK_PULL_REQUESTS"
+	MaxOpenFixPullRequestsEnv        = "JF_MAX_OPEN_FIX_PULL_REQUESTS"
+	MaxNewFixPullRequestsEnv         = "JF_MAX_NEW_FIX_PULL_REQUESTS"
+	FixMaxPullRequestsPerSeverityEnv = "JF_FIX_MAX_PULL_REQUESTS_PER_SEVERITY"
+	FixPullRequestsWindowsEnv        = "JF_FIX_PULL_REQUESTS_WINDOWS"
+	BackportBranchesEnv              = "JF_BACKPORT_BRANCHES"
+	PullRequestLabelsEnv             = "JF_PR_LABELS"
+	PullRequestAssigneesEnv          = "JF_PR_ASSIGNEES"
+	PullRequestReviewersEnv          = "JF_PR_REVIEWERS"
+	PullRequestTeamReviewersEnv      = "JF_PR_TEAM_REVIEWERS"
+	RefreshBehindBaseCommitsEnv      = "JF_REFRESH_BEHIND_BASE_COMMITS"
+	PinGitHubActionsEnv              = "JF_PIN_GITHUB_ACTIONS"
+	AnalyzeUpgradeRiskEnv            = "JF_ANALYZE_UPGRADE_RISK"
+	NpmDiffLinksEnv                  = "JF_NPM_DIFF_LINKS"
+	CleanupMergedBranchesEnv         = "JF_CLEANUP_MERGED_BRANCHES"
+	CleanupRetentionDaysEnv          = "JF_CLEANUP_RETENTION_DAYS"
+	AutoMergeEnv                     = "JF_AUTO_MERGE"
+	AutoMergeStrategyEnv             = "JF_AUTO_MERGE_STRATEGY"
+
+	// Git HTTP transport environment variables, in seconds
+	GitHttpTimeoutEnv   = "JF_GIT_HTTP_TIMEOUT"
+	GitHttpKeepAliveEnv = "JF_GIT_HTTP_KEEP_ALIVE"
+
+	// Proxy environment variables, applied to the Git provider clients, the JFrog clients and the package managers
+	ProxyUrlEnv     = "JF_PROXY_URL"
+	NoProxyEnv      = "JF_NO_PROXY"
+	CaBundlePathEnv = "JF_CA_BUNDLE_PATH"
 
 	// Git naming template environment variables
 	BranchNameTemplateEnv       = "JF_BRANCH_NAME_TEMPLATE"
@@ -59,30 +106,89 @@ const (
 	PathExclusionsEnv   = "JF_PATH_EXCLUSIONS"
 	jfrogWatchesEnv     = "JF_WATCHES"
 	jfrogProjectEnv     = "JF_PROJECT"
+	// The depth of the directories that are searched for projects when no working directories are set. 0 disables the detection.
+	ProjectDetectionDepthEnv = "JF_PROJECT_DETECTION_DEPTH"
+	// To detect an install command that keeps the dependencies consistent with the lock files, when no install command is set
+	DetectInstallCommandEnv = "JF_DETECT_INSTALL_DEPS_CMD"
+	// Filter the working directories of the projects at runtime, including the projects of the frogbot-config.yml file
+	IncludeWorkingDirsEnv = "JF_INCLUDE_WORKING_DIRS"
+	ExcludeWorkingDirsEnv = "JF_EXCLUDE_WORKING_DIRS"
+	// To fail the scan if the configured watches or JFrog project don't exist, instead of warning
+	FailOnMissingWatchesOrProjectEnv = "JF_FAIL_ON_MISSING_WATCHES_OR_PROJECT"
 	// To include vulnerabilities and violations
 	IncludeVulnerabilitiesEnv = "JF_INCLUDE_VULNERABILITIES"
 	// To include all the vulnerabilities in the source branch at PR scan
 	IncludeAllVulnerabilitiesEnv       = "JF_INCLUDE_ALL_VULNERABILITIES"
 	AvoidPreviousPrCommentsDeletionEnv = "JF_AVOID_PREVIOUS_PR_COMMENTS_DELETION"
+	CollapsePreviousPrCommentsEnv      = "JF_COLLAPSE_PREVIOUS_PR_COMMENTS"
+	BatchReviewCommentsEnv             = "JF_PR_BATCH_REVIEW_COMMENTS"
+	ScanProgressCommentEnv             = "JF_PR_SCAN_PROGRESS_COMMENT"
+	IncrementalPrScanEnv               = "JF_INCREMENTAL_PR_SCAN"
+	CommentSuppressionsEnv             = "JF_PR_COMMENT_SUPPRESSIONS"
+	SuppressionApproversEnv            = "JF_PR_SUPPRESSION_APPROVERS"
 	AddPrCommentOnSuccessEnv           = "JF_PR_ADD_SUCCESS_COMMENT"
 	FailOnSecurityIssuesEnv            = "JF_FAIL"
+	FailAfterDateEnv                   = "JF_FAIL_AFTER_DATE"
 	UseWrapperEnv                      = "JF_USE_WRAPPER"
 	DepsRepoEnv                        = "JF_DEPS_REPO"
 	MinSeverityEnv                     = "JF_MIN_SEVERITY"
 	FixableOnlyEnv                     = "JF_FIXABLE_ONLY"
+	FailOnApplicableOnlyEnv            = "JF_FAIL_ON_APPLICABLE_ONLY"
+	FixApplicableOnlyEnv               = "JF_FIX_APPLICABLE_ONLY"
+	FixMinSeverityEnv                  = "JF_FIX_MIN_SEVERITY"
+	SkipDevDependenciesEnv             = "JF_SKIP_DEV_DEPENDENCIES"
 	DisableJasEnv                      = "JF_DISABLE_ADVANCED_SECURITY"
+	EnableApplicabilityEnv             = "JF_ENABLE_APPLICABILITY"
+	EnableSecretsEnv                   = "JF_ENABLE_SECRETS"
+	EnableIacEnv                       = "JF_ENABLE_IAC"
+	EnableSastEnv                      = "JF_ENABLE_SAST"
+	SecretsExclusionsEnv               = "JF_SECRETS_EXCLUSIONS"
+	IacExclusionsEnv                   = "JF_IAC_EXCLUSIONS"
+	SastExclusionsEnv                  = "JF_SAST_EXCLUSIONS"
+	ExcludePatternsEnv                 = "JF_EXCLUDE_PATTERNS"
 	DetectionOnlyEnv                   = "JF_SKIP_AUTOFIX"
 	AllowedLicensesEnv                 = "JF_ALLOWED_LICENSES"
 	SkipAutoInstallEnv                 = "JF_SKIP_AUTO_INSTALL"
 	AllowPartialResultsEnv             = "JF_ALLOW_PARTIAL_RESULTS"
+	MaxConcurrentReposEnv              = "JF_MAX_CONCURRENT_REPOS"
+	TargetCvesEnv                      = "JF_TARGET_CVES"
+	TargetCvesFixOnlyEnv               = "JF_TARGET_CVES_FIX_ONLY"
+	FixCvesEnv                         = "JF_FIX_CVES"
+	PackageHandlerPluginsEnv           = "JF_PACKAGE_HANDLER_PLUGINS"
+	InternalNamespacesEnv              = "JF_INTERNAL_NAMESPACES"
+	MetricsFileEnv                     = "JF_METRICS_FILE"
+	MetricsPushgatewayUrlEnv           = "JF_METRICS_PUSHGATEWAY_URL"
+	RunSummaryFileEnv                  = "JF_RUN_SUMMARY_FILE"
+	ReportPathEnv                      = "JF_REPORT_PATH"
+	SbomPathEnv                        = "JF_SBOM_PATH"
+	ExploitabilityEnrichmentEnv        = "JF_EXPLOITABILITY_ENRICHMENT"
+	ResearchEnrichmentEnv              = "JF_RESEARCH_ENRICHMENT"
+	PrioritizeExploitedFixesEnv        = "JF_PRIORITIZE_EXPLOITED_FIXES"
+	ValidateSecretsEnv                 = "JF_VALIDATE_SECRETS"
+	BlockOnSecretsEnv                  = "JF_BLOCK_ON_SECRETS"
+	ScanDockerfilesEnv                 = "JF_SCAN_DOCKERFILES"
+	ScanGitHubActionsEnv               = "JF_SCAN_GITHUB_ACTIONS"
+	ScanBazelEnv                       = "JF_SCAN_BAZEL"
+	ScanComposerEnv                    = "JF_SCAN_COMPOSER"
+	ScanCurationEnv                    = "JF_SCAN_CURATION"
+	ScanSystemPackagesEnv              = "JF_SCAN_SYSTEM_PACKAGES"
+	FailOnCurationBlockedEnv           = "JF_FAIL_ON_CURATION_BLOCKED"
+	ExternalSarifPathsEnv              = "JF_EXTERNAL_SARIF_PATHS"
+	BazelRepinCommandEnv               = "JF_BAZEL_REPIN_COMMAND"
 	WatchesDelimiter                   = ","
 
+	// Fix campaign environment variables
+	CampaignTargetEnv = "JF_CAMPAIGN_TARGET"
+	CampaignIdEnv     = "JF_CAMPAIGN_ID"
+
 	// Email related environment variables
 	//#nosec G101 -- False positive - no hardcoded credentials.
-	SmtpPasswordEnv   = "JF_SMTP_PASSWORD"
-	SmtpUserEnv       = "JF_SMTP_USER"
-	SmtpServerEnv     = "JF_SMTP_SERVER"
-	EmailReceiversEnv = "JF_EMAIL_RECEIVERS"
+	SmtpPasswordEnv           = "JF_SMTP_PASSWORD"
+	SmtpUserEnv               = "JF_SMTP_USER"
+	SmtpServerEnv             = "JF_SMTP_SERVER"
+	EmailReceiversEnv         = "JF_EMAIL_RECEIVERS"
+	CriticalEmailReceiversEnv = "JF_CRITICAL_EMAIL_RECEIVERS"
+	LicensesEmailReceiversEnv = "JF_LICENSES_EMAIL_RECEIVERS"
 
 	//#nosec G101 -- False positive - no hardcoded credentials.
 	GitTokenEnv          = "JF_GIT_TOKEN"
@@ -92,23 +198,47 @@ const (
 	GitAggregateFixesEnv = "JF_GIT_AGGREGATE_FIXES"
 	GitEmailAuthorEnv    = "JF_GIT_EMAIL_AUTHOR"
 
+	// The secret that the webhooks received by the serve command are signed with
+	//#nosec G101 -- False positive - no hardcoded credentials.
+	WebhookSecretEnv = "JF_WEBHOOK_SECRET"
+
+	// Commit signing environment variables. The key is a GPG or an SSH private key, or a path to a file that holds it.
+	GitSigningKeyEnv           = "JF_GIT_SIGNING_KEY"
+	GitSigningKeyPassphraseEnv = "JF_GIT_SIGNING_KEY_PASSPHRASE"
+
 	// Product ID for usage reporting
 	productId = "frogbot"
 
 	// The 'GITHUB_ACTIONS' environment variable exists when the CI is GitHub Actions
 	GitHubActionsEnv = "GITHUB_ACTIONS"
+	// The path of the job summary file of the current GitHub Actions step
+	GitHubStepSummaryEnv = "GITHUB_STEP_SUMMARY"
 
 	// Placeholders for templates
 	PackagePlaceHolder    = "{IMPACTED_PACKAGE}"
 	FixVersionPlaceHolder = "{FIX_VERSION}"
 	BranchHashPlaceHolder = "{BRANCH_NAME_HASH}"
+	CvePlaceHolder        = "{CVE}"
+	SeverityPlaceHolder   = "{SEVERITY}"
+	TechnologyPlaceHolder = "{TECHNOLOGY}"
+	WorkingDirPlaceHolder = "{WORKING_DIR}"
+	BaseBranchPlaceHolder = "{BASE_BRANCH}"
 
 	// General flags
-	AvoidExtraMessages = "JF_AVOID_EXTRA_MESSAGES"
+	AvoidExtraMessages      = "JF_AVOID_EXTRA_MESSAGES"
+	ShowRuntimeDetailsEnv   = "JF_SHOW_RUNTIME_DETAILS"
+	ShowUnsupportedFixesEnv = "JF_SHOW_UNSUPPORTED_FIXES"
+	ShowIgnoredFindingsEnv  = "JF_SHOW_IGNORED_FINDINGS"
+	ShowSectionsEnv         = "JF_SHOW_SECTIONS"
+	HideResearchDetailsEnv  = "JF_HIDE_RESEARCH_DETAILS"
+	MaxRowsPerTableEnv      = "JF_MAX_ROWS_PER_TABLE"
+	MessagesFileEnv         = "JF_MESSAGES_FILE"
+	CodeInsightsEnv         = "JF_CODE_INSIGHTS"
 
 	// Default naming templates
-	BranchNameTemplate                       = "frogbot-" + PackagePlaceHolder + "-" + BranchHashPlaceHolder
-	AggregatedBranchNameTemplate             = "frogbot-update-" + BranchHashPlaceHolder + "-dependencies"
+	FixBranchPrefix                          = "frogbot-"
+	BranchNameTemplate                       = FixBranchPrefix + PackagePlaceHolder + "-" + BranchHashPlaceHolder
+	AggregatedBranchNameTemplate             = FixBranchPrefix + "update-" + BranchHashPlaceHolder + "-dependencies"
 	CommitMessageTemplate                    = "Upgrade " + PackagePlaceHolder + " to " + FixVersionPlaceHolder
 	PullRequestTitleTemplate                 = outputwriter.FrogbotTitlePrefix + " Update version of " + PackagePlaceHolder + " to " + FixVersionPlaceHolder
 	AggregatePullRequestTitleDefaultTemplate = outputwriter.FrogbotTitlePrefix + " Update %s dependencies"
@@ -123,4 +253,5 @@ const (
 	IndirectDependencyFixNotSupported   UnsupportedErrorType = "IndirectDependencyFixNotSupported"
 	BuildToolsDependencyFixNotSupported UnsupportedErrorType = "BuildToolsDependencyFixNotSupported"
 	UnsupportedForFixVulnerableVersion  UnsupportedErrorType = "UnsupportedForFixVulnerableVersion"
+	UnsupportedByPackageHandlerPlugin   UnsupportedErrorType = "UnsupportedByPackageHandlerPlugin"
 )
@@ -0,0 +1,160 @@
+package curation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	curationaudit "github.com/jfrog/jfrog-cli-security/commands/curation"
+	securityutils "github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Auditor returns the packages of the projects in the directories that the curation policies block.
+// The curation audit command of the CLI implements it.
+type Auditor func(depsRepo string, dirs ...string) ([]curationaudit.PackageStatus, error)
+
+// Analyzer finds the dependencies that the curation policies of Artifactory block, so they can't be installed once the pull request is merged.
+type Analyzer struct {
+	auditor Auditor
+	// The blocked packages of the target branch. The pull request doesn't add them, so they aren't reported.
+	targetBlocked *datastructures.Set[string]
+	// The blocked packages that were already reported, so each package is reported once for all the projects
+	reported *datastructures.Set[string]
+}
+
+// Returns nil if the curation audit isn't enabled, which disables the analysis
+func NewAnalyzer(enabled bool, serverDetails *config.ServerDetails) *Analyzer {
+	if !enabled {
+		return nil
+	}
+	return newAnalyzer(func(depsRepo string, dirs ...string) ([]curationaudit.PackageStatus, error) {
+		return runCurationAudit(serverDetails, depsRepo, dirs)
+	})
+}
+
+func newAnalyzer(auditor Auditor) *Analyzer {
+	return &Analyzer{auditor: auditor, reported: datastructures.MakeSet[string]()}
+}
+
+// Audits the projects of the target branch of a pull request, so only the blocked packages that the pull request adds are reported by the next analysis
+func (a *Analyzer) SetTargetBranch(depsRepo string, dirs ...string) error {
+	if a == nil {
+		return nil
+	}
+	blockedPackages, err := a.auditor(depsRepo, dirs...)
+	if err != nil {
+		return err
+	}
+	a.targetBlocked = datastructures.MakeSet[string]()
+	for _, blockedPackage := range blockedPackages {
+		a.targetBlocked.Add(getPackageKey(blockedPackage))
+	}
+	return nil
+}
+
+// Returns the packages of the projects in the directories that the curation policies block
+func (a *Analyzer) Analyze(depsRepo string, dirs ...string) (blockedPackages []issues.CurationBlockedPackage, err error) {
+	if a == nil {
+		return
+	}
+	targetBlocked := a.targetBlocked
+	a.targetBlocked = nil
+	log.Info("Running the curation audit of the dependencies...")
+	packagesStatus, err := a.auditor(depsRepo, dirs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the curation audit: %s", err.Error())
+	}
+	for _, packageStatus := range packagesStatus {
+		key := getPackageKey(packageStatus)
+		if a.reported.Exists(key) || (targetBlocked != nil && targetBlocked.Exists(key)) {
+			continue
+		}
+		a.reported.Add(key)
+		blockedPackages = append(blockedPackages, toBlockedPackage(packageStatus))
+	}
+	return
+}
+
+func getPackageKey(packageStatus curationaudit.PackageStatus) string {
+	return strings.Join([]string{packageStatus.PkgType, packageStatus.PackageName, packageStatus.PackageVersion}, "|")
+}
+
+func toBlockedPackage(packageStatus curationaudit.PackageStatus) issues.CurationBlockedPackage {
+	blockedPackage := issues.CurationBlockedPackage{
+		PackageType:    packageStatus.PkgType,
+		PackageName:    packageStatus.PackageName,
+		Version:        packageStatus.PackageVersion,
+		BlockingReason: packageStatus.BlockingReason,
+	}
+	// A direct dependency is its own parent
+	if packageStatus.ParentName != packageStatus.PackageName || packageStatus.ParentVersion != packageStatus.PackageVersion {
+		blockedPackage.ParentName, blockedPackage.ParentVersion = packageStatus.ParentName, packageStatus.ParentVersion
+	}
+	for _, policy := range packageStatus.Policy {
+		blockedPackage.Policies = append(blockedPackage.Policies, issues.CurationPolicy{
+			Name:           policy.Policy,
+			Condition:      policy.Condition,
+			Explanation:    policy.Explanation,
+			Recommendation: policy.Recommendation,
+		})
+	}
+	return blockedPackage
+}
+
+// Runs the curation audit command of the CLI on the directories.
+// The command prints its results instead of returning them, so they're read from its JSON output.
+func runCurationAudit(serverDetails *config.ServerDetails, depsRepo string, dirs []string) (packagesStatus []curationaudit.PackageStatus, err error) {
+	auditCommand := curationaudit.NewCurationAuditCommand().SetWorkingDirs(dirs)
+	auditCommand.AuditParams = (&securityutils.AuditBasicParams{}).
+		SetServerDetails(serverDetails).
+		SetOutputFormat(format.Json).
+		SetDepsRepo(depsRepo).
+		SetIsCurationCmd(true)
+	recorder := newOutputRecorder(log.GetLogger())
+	log.SetLogger(recorder)
+	err = auditCommand.Run()
+	log.SetLogger(recorder.Log)
+	packagesStatus, parseErr := parseAuditOutput(recorder.outputs)
+	return packagesStatus, errors.Join(err, parseErr)
+}
+
+// Returns the blocked packages of the JSON output of the curation audit command.
+// The command outputs a line that counts the blocked packages of each project, followed by their JSON array if there are any.
+func parseAuditOutput(outputs []string) (packagesStatus []curationaudit.PackageStatus, err error) {
+	for _, output := range outputs {
+		output = strings.TrimSpace(output)
+		if !strings.HasPrefix(output, "[") {
+			continue
+		}
+		var projectPackagesStatus []curationaudit.PackageStatus
+		if err = json.Unmarshal([]byte(output), &projectPackagesStatus); err != nil {
+			return nil, fmt.Errorf("failed to parse the output of the curation audit: %s", err.Error())
+		}
+		packagesStatus = append(packagesStatus, projectPackagesStatus...)
+	}
+	return
+}
+
+// outputRecorder records the output of a command instead of printing it, and logs the other messages with the wrapped logger
+type outputRecorder struct {
+	log.Log
+	outputs []string
+	mutex   sync.Mutex
+}
+
+func newOutputRecorder(logger log.Log) *outputRecorder {
+	return &outputRecorder{Log: logger}
+}
+
+func (r *outputRecorder) Output(a ...interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.outputs = append(r.outputs, fmt.Sprint(a...))
+}
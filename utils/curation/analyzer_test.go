@@ -0,0 +1,133 @@
+package curation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	curationaudit "github.com/jfrog/jfrog-cli-security/commands/curation"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	blockedLodash = curationaudit.PackageStatus{
+		Action:         "blocked",
+		ParentName:     "lodash",
+		ParentVersion:  "4.17.20",
+		PackageName:    "lodash",
+		PackageVersion: "4.17.20",
+		BlockingReason: "Policy violations",
+		PkgType:        "npm",
+		Policy:         []curationaudit.Policy{{Policy: "no-critical", Condition: "CVE with critical severity", Explanation: "Package version contains CVE-2021-23337", Recommendation: "Upgrade to version 4.17.21"}},
+	}
+	blockedMinimist = curationaudit.PackageStatus{
+		Action:         "blocked",
+		ParentName:     "mkdirp",
+		ParentVersion:  "0.5.1",
+		PackageName:    "minimist",
+		PackageVersion: "0.0.8",
+		BlockingReason: "Policy violations",
+		PkgType:        "npm",
+		Policy:         []curationaudit.Policy{{Policy: "no-malicious", Condition: "Malicious package"}},
+	}
+)
+
+func TestAnalyzer(t *testing.T) {
+	auditedDirs := map[string][]string{}
+	auditor := func(depsRepo string, dirs ...string) ([]curationaudit.PackageStatus, error) {
+		auditedDirs[depsRepo] = append(auditedDirs[depsRepo], dirs...)
+		if dirs[0] == "target" {
+			return []curationaudit.PackageStatus{blockedMinimist}, nil
+		}
+		return []curationaudit.PackageStatus{blockedLodash, blockedMinimist}, nil
+	}
+
+	// Only the blocked packages that the pull request adds are reported
+	analyzer := newAnalyzer(auditor)
+	require.NoError(t, analyzer.SetTargetBranch("npm-remote", "target"))
+	blockedPackages, err := analyzer.Analyze("npm-remote", "source")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"target", "source"}, auditedDirs["npm-remote"])
+	assert.Equal(t, []issues.CurationBlockedPackage{{
+		PackageType:    "npm",
+		PackageName:    "lodash",
+		Version:        "4.17.20",
+		BlockingReason: "Policy violations",
+		Policies:       []issues.CurationPolicy{{Name: "no-critical", Condition: "CVE with critical severity", Explanation: "Package version contains CVE-2021-23337", Recommendation: "Upgrade to version 4.17.21"}},
+	}}, blockedPackages)
+
+	// All the blocked packages are reported without a target branch
+	analyzer = newAnalyzer(auditor)
+	blockedPackages, err = analyzer.Analyze("", "source")
+	require.NoError(t, err)
+	require.Len(t, blockedPackages, 2)
+	assert.Equal(t, issues.CurationBlockedPackage{
+		PackageType:    "npm",
+		PackageName:    "minimist",
+		Version:        "0.0.8",
+		ParentName:     "mkdirp",
+		ParentVersion:  "0.5.1",
+		BlockingReason: "Policy violations",
+		Policies:       []issues.CurationPolicy{{Name: "no-malicious", Condition: "Malicious package"}},
+	}, blockedPackages[1])
+
+	// The blocked packages are reported once for all the projects
+	blockedPackages, err = analyzer.Analyze("", "source")
+	require.NoError(t, err)
+	assert.Empty(t, blockedPackages)
+
+	// Failing to run the curation audit
+	analyzer = newAnalyzer(func(string, ...string) ([]curationaudit.PackageStatus, error) { return nil, errors.New("audit failed") })
+	assert.Error(t, analyzer.SetTargetBranch("", "target"))
+	_, err = analyzer.Analyze("", "source")
+	assert.ErrorContains(t, err, "audit failed")
+
+	// The analysis is disabled
+	disabled := NewAnalyzer(false, nil)
+	assert.NoError(t, disabled.SetTargetBranch("", "target"))
+	blockedPackages, err = disabled.Analyze("", "source")
+	assert.NoError(t, err)
+	assert.Empty(t, blockedPackages)
+}
+
+func TestParseAuditOutput(t *testing.T) {
+	outputs := []string{
+		"Found 0 blocked packages for project /tmp/source/frontend",
+		"\n",
+		"Found 1 blocked packages for project /tmp/source/backend",
+		`[
+  {
+    "action": "blocked",
+    "direct_dependency_package_name": "mkdirp",
+    "direct_dependency_package_version": "0.5.1",
+    "blocked_package_name": "minimist",
+    "blocked_package_version": "0.0.8",
+    "blocking_reason": "Policy violations",
+    "dependency_relation": "indirect",
+    "type": "npm",
+    "policies": [{"policy": "no-malicious", "condition": "Malicious package", "explanation": "", "recommendation": ""}]
+  }
+]`,
+		"\n",
+	}
+	packagesStatus, err := parseAuditOutput(outputs)
+	require.NoError(t, err)
+	expected := blockedMinimist
+	expected.DepRelation = "indirect"
+	assert.Equal(t, []curationaudit.PackageStatus{expected}, packagesStatus)
+
+	_, err = parseAuditOutput([]string{"[{"})
+	assert.Error(t, err)
+}
+
+func TestOutputRecorder(t *testing.T) {
+	previousLogger := log.GetLogger()
+	recorder := newOutputRecorder(previousLogger)
+	log.SetLogger(recorder)
+	defer log.SetLogger(previousLogger)
+	log.Output("Found 0 blocked packages for project /tmp/source")
+	log.Info("The other messages are logged")
+	assert.Equal(t, []string{"Found 0 blocked packages for project /tmp/source"}, recorder.outputs)
+}
@@ -0,0 +1,121 @@
+package dependencyconfusion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/registries"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Registry looks up the packages of a public registry
+type Registry interface {
+	// Returns the latest version of the package in the registry, or an empty string if the package doesn't exist in the registry
+	GetLatestVersion(packageName string) (string, error)
+	// Returns the page of the package in the registry
+	GetPackageUrl(packageName string) string
+}
+
+// Analyzer checks the direct dependencies of internal namespaces against their public registries.
+// A dependency is at risk if its package exists in the public registry with a higher version,
+// since package managers that resolve from the public registry may install the public package instead of the internal one.
+type Analyzer struct {
+	// Package names, or name prefixes that end with '*', such as '@mycompany/*' or 'com.mycompany*'
+	namespaces []string
+	// Maps the package type prefix of Xray component IDs to the public registry of the package type
+	registries map[string]Registry
+	// Caches the latest public versions, as the same dependency is usually used by several projects
+	latestVersions map[string]string
+	// The dependencies that were already reported, so each risk is reported once for all the projects
+	reported *datastructures.Set[string]
+}
+
+// Returns nil if no internal namespaces are configured, which disables the analysis
+func NewAnalyzer(namespaces []string) *Analyzer {
+	if len(namespaces) == 0 {
+		return nil
+	}
+	return &Analyzer{
+		namespaces: namespaces,
+		registries: map[string]Registry{
+			"npm":   &registries.NpmRegistry{},
+			"pypi":  &registries.PypiRegistry{},
+			"gav":   &registries.MavenRegistry{},
+			"nuget": &registries.NugetRegistry{},
+		},
+		latestVersions: map[string]string{},
+		reported:       datastructures.MakeSet[string](),
+	}
+}
+
+// Returns the dependency confusion risks of the given direct dependencies, which are Xray component IDs such as 'npm://@mycompany/lib:1.0.0'.
+// Dependencies whose public registry can't be checked are skipped.
+func (a *Analyzer) Analyze(directDependencies []string) (risks []issues.DependencyConfusionRisk) {
+	if a == nil {
+		return
+	}
+	for _, dependency := range directDependencies {
+		packageTypeId, _, found := strings.Cut(dependency, "://")
+		registry := a.registries[packageTypeId]
+		if !found || registry == nil || a.reported.Exists(dependency) {
+			continue
+		}
+		packageName, packageVersion, packageType := techutils.SplitComponentId(dependency)
+		namespace := a.getNamespace(packageName)
+		if namespace == "" || packageVersion == "" {
+			continue
+		}
+		latestVersion, err := a.getLatestVersion(registry, packageTypeId, packageName)
+		if err != nil {
+			log.Warn(fmt.Sprintf("Couldn't check the public registry for the dependency confusion risk of %s: %s", packageName, err.Error()))
+			continue
+		}
+		if latestVersion == "" || version.NewVersion(packageVersion).Compare(latestVersion) <= 0 {
+			continue
+		}
+		a.reported.Add(dependency)
+		risks = append(risks, issues.DependencyConfusionRisk{
+			PackageType:   packageType,
+			PackageName:   packageName,
+			Version:       packageVersion,
+			Namespace:     namespace,
+			PublicVersion: latestVersion,
+			PublicUrl:     registry.GetPackageUrl(packageName),
+		})
+	}
+	if len(risks) > 0 {
+		log.Warn(fmt.Sprintf("Found %d dependencies of internal namespaces with a higher version in their public registry", len(risks)))
+	}
+	return
+}
+
+// Returns the internal namespace of the package, or an empty string if the package doesn't belong to an internal namespace
+func (a *Analyzer) getNamespace(packageName string) string {
+	for _, namespace := range a.namespaces {
+		if prefix, isPrefix := strings.CutSuffix(namespace, "*"); isPrefix {
+			if strings.HasPrefix(strings.ToLower(packageName), strings.ToLower(prefix)) {
+				return namespace
+			}
+		} else if strings.EqualFold(packageName, namespace) {
+			return namespace
+		}
+	}
+	return ""
+}
+
+func (a *Analyzer) getLatestVersion(registry Registry, packageTypeId, packageName string) (latestVersion string, err error) {
+	cacheKey := packageTypeId + "://" + packageName
+	latestVersion, exists := a.latestVersions[cacheKey]
+	if exists {
+		return
+	}
+	if latestVersion, err = registry.GetLatestVersion(packageName); err != nil {
+		return
+	}
+	a.latestVersions[cacheKey] = latestVersion
+	return
+}
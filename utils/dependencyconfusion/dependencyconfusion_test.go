@@ -0,0 +1,100 @@
+package dependencyconfusion
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/registries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAnalyzer(t *testing.T) {
+	assert.Nil(t, NewAnalyzer(nil))
+	// Analyzing without internal namespaces finds no risks
+	assert.Empty(t, NewAnalyzer(nil).Analyze([]string{"npm://@mycompany/lib:1.0.0"}))
+}
+
+func TestAnalyze(t *testing.T) {
+	requestsCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestsCount++
+		var response string
+		switch r.URL.EscapedPath() {
+		case "/npm/@mycompany%2Flib":
+			response = `{"dist-tags":{"latest":"99.0.0"}}`
+		case "/npm/@mycompany%2Fui":
+			response = `{"dist-tags":{"latest":"1.0.0"}}`
+		case "/pypi/pypi/mycompany-utils/json":
+			response = `{"info":{"version":"2.1"}}`
+		case "/maven/solrsearch/select":
+			assert.Equal(t, `g:"com.mycompany" AND a:"core"`, r.URL.Query().Get("q"))
+			response = `{"response":{"docs":[{"latestVersion":"1.5.0"}]}}`
+		case "/nuget/v3-flatcontainer/mycompany.logging/index.json":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	analyzer := NewAnalyzer([]string{"@mycompany/*", "mycompany-utils", "com.mycompany*", "MyCompany.*"})
+	analyzer.registries = map[string]Registry{
+		"npm":   &registries.NpmRegistry{Url: server.URL + "/npm"},
+		"pypi":  &registries.PypiRegistry{Url: server.URL + "/pypi"},
+		"gav":   &registries.MavenRegistry{Url: server.URL + "/maven"},
+		"nuget": &registries.NugetRegistry{Url: server.URL + "/nuget"},
+	}
+	directDependencies := []string{
+		// A higher public version
+		"npm://@mycompany/lib:1.2.3",
+		// The same dependency in another project is reported once
+		"npm://@mycompany/lib:1.2.3",
+		// The public version is lower
+		"npm://@mycompany/ui:2.0.0",
+		// Not published publicly
+		"npm://@mycompany/private:1.0.0",
+		// Not in an internal namespace
+		"npm://lodash:4.17.20",
+		"pypi://mycompany-utils:1.0",
+		"gav://com.mycompany:core:1.0.0",
+		// The public registry can't be checked
+		"nuget://MyCompany.Logging:1.0.0",
+		// Unsupported package type
+		"go://github.com/mycompany/module:v1.0.0",
+	}
+	expectedRisks := []issues.DependencyConfusionRisk{
+		{PackageType: "npm", PackageName: "@mycompany/lib", Version: "1.2.3", Namespace: "@mycompany/*", PublicVersion: "99.0.0", PublicUrl: "https://www.npmjs.com/package/@mycompany/lib"},
+		{PackageType: "Python", PackageName: "mycompany-utils", Version: "1.0", Namespace: "mycompany-utils", PublicVersion: "2.1", PublicUrl: "https://pypi.org/project/mycompany-utils/"},
+		{PackageType: "Maven", PackageName: "com.mycompany:core", Version: "1.0.0", Namespace: "com.mycompany*", PublicVersion: "1.5.0", PublicUrl: "https://central.sonatype.com/artifact/com.mycompany/core"},
+	}
+	assert.Equal(t, expectedRisks, analyzer.Analyze(directDependencies))
+	assert.Equal(t, 6, requestsCount)
+	// The risks are reported once for all the projects
+	assert.Empty(t, analyzer.Analyze([]string{"npm://@mycompany/lib:1.2.3"}))
+}
+
+func TestGetNamespace(t *testing.T) {
+	analyzer := NewAnalyzer([]string{"@mycompany/*", "com.mycompany*", "internal-lib"})
+	testCases := []struct {
+		packageName       string
+		expectedNamespace string
+	}{
+		{packageName: "@mycompany/lib", expectedNamespace: "@mycompany/*"},
+		{packageName: "@MyCompany/lib", expectedNamespace: "@mycompany/*"},
+		{packageName: "@mycompanyother/lib"},
+		{packageName: "com.mycompany.tools:core", expectedNamespace: "com.mycompany*"},
+		{packageName: "internal-lib", expectedNamespace: "internal-lib"},
+		{packageName: "internal-lib-extra"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.packageName, func(t *testing.T) {
+			assert.Equal(t, tc.expectedNamespace, analyzer.getNamespace(tc.packageName))
+		})
+	}
+}
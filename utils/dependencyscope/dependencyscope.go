@@ -0,0 +1,267 @@
+package dependencyscope
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/modfile"
+)
+
+// Scope tells whether a dependency is part of the production code of a project, which is its attack surface
+type Scope string
+
+const (
+	Prod     Scope = "prod"
+	Dev      Scope = "dev"
+	Test     Scope = "test"
+	Optional Scope = "optional"
+)
+
+// The dependencies of a scope are exposed if any of the dependencies that bring them in is exposed, so the most exposed scope wins
+var exposure = map[Scope]int{Dev: 1, Test: 2, Optional: 3, Prod: 4}
+
+// Returns true if the scope is part of the production code. Dependencies of an unknown scope are considered production dependencies.
+func (s Scope) IsProduction() bool {
+	return s != Dev && s != Test
+}
+
+// Scopes maps the direct dependencies of the scanned projects to their scopes.
+// The dependencies are identified by their names, as they appear in the components of the scan results.
+type Scopes map[string]Scope
+
+// Returns the scope of an impacted dependency, which is the most exposed scope of the direct dependencies that bring it in.
+// Returns an empty scope if the scope of one of them is unknown and none of them is a production dependency.
+func (s Scopes) GetScope(directDependencies []formats.ComponentRow) (scope Scope) {
+	unknown := len(directDependencies) == 0
+	for _, component := range directDependencies {
+		componentScope, exists := s[component.Name]
+		if !exists {
+			unknown = true
+			continue
+		}
+		if exposure[componentScope] > exposure[scope] {
+			scope = componentScope
+		}
+	}
+	if unknown && scope != Prod {
+		return ""
+	}
+	return
+}
+
+// Returns the scopes of both, or nil if both are empty
+func (s Scopes) Merge(other Scopes) Scopes {
+	if len(s) == 0 {
+		return other
+	}
+	for name, scope := range other {
+		s.add(name, scope)
+	}
+	return s
+}
+
+func (s Scopes) add(name string, scope Scope) {
+	if exposure[scope] > exposure[s[name]] {
+		s[name] = scope
+	}
+}
+
+// Reads the scopes of the direct dependencies from the descriptors in the working directories and their subdirectories:
+// the dependency types of package.json files, the scopes of pom.xml files, and the Go modules that only the tests of a go.mod module import.
+// Returns nil if no scope was found. Descriptors that can't be read are skipped.
+func Detect(workingDirs ...string) Scopes {
+	scopes := Scopes{}
+	for _, workingDir := range workingDirs {
+		err := filepath.WalkDir(workingDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if path != workingDir && isSkippedDir(entry.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			var detectErr error
+			switch entry.Name() {
+			case "package.json":
+				detectErr = detectNpmScopes(path, scopes)
+			case "pom.xml":
+				detectErr = detectMavenScopes(path, scopes)
+			case "go.mod":
+				detectErr = detectGoScopes(path, scopes)
+			}
+			if detectErr != nil {
+				log.Debug(fmt.Sprintf("Couldn't read the dependency scopes of %s: %s", path, detectErr.Error()))
+			}
+			return nil
+		})
+		if err != nil {
+			log.Debug(fmt.Sprintf("Couldn't read the dependency scopes of %s: %s", workingDir, err.Error()))
+		}
+	}
+	if len(scopes) == 0 {
+		return nil
+	}
+	return scopes
+}
+
+func isSkippedDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "target" || name == "testdata"
+}
+
+type packageJson struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+}
+
+func detectNpmScopes(path string, scopes Scopes) error {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	var descriptor packageJson
+	if err = json.Unmarshal(content, &descriptor); err != nil {
+		return err
+	}
+	for dependencies, scope := range map[*map[string]string]Scope{
+		&descriptor.Dependencies:         Prod,
+		&descriptor.PeerDependencies:     Prod,
+		&descriptor.OptionalDependencies: Optional,
+		&descriptor.DevDependencies:      Dev,
+	} {
+		for name := range *dependencies {
+			scopes.add(name, scope)
+		}
+	}
+	return nil
+}
+
+type pomXml struct {
+	// Only the dependencies of the project itself, the managed dependencies aren't necessarily used
+	Dependencies []pomDependency `xml:"dependencies>dependency"`
+}
+
+type pomDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Scope      string `xml:"scope"`
+	Optional   string `xml:"optional"`
+}
+
+func detectMavenScopes(path string, scopes Scopes) error {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	var descriptor pomXml
+	if err = xml.Unmarshal(content, &descriptor); err != nil {
+		return err
+	}
+	for _, dependency := range descriptor.Dependencies {
+		scope := Prod
+		if strings.TrimSpace(dependency.Scope) == "test" {
+			scope = Test
+		} else if strings.TrimSpace(dependency.Optional) == "true" {
+			scope = Optional
+		}
+		scopes.add(strings.TrimSpace(dependency.GroupId)+":"+strings.TrimSpace(dependency.ArtifactId), scope)
+	}
+	return nil
+}
+
+// Go has no dependency scopes, so a direct requirement is a test dependency if only the test files of the module import it
+func detectGoScopes(path string, scopes Scopes) error {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	goMod, err := modfile.ParseLax(path, content, nil)
+	if err != nil {
+		return err
+	}
+	var directRequirements []string
+	for _, requirement := range goMod.Require {
+		if !requirement.Indirect {
+			directRequirements = append(directRequirements, requirement.Mod.Path)
+		}
+	}
+	if len(directRequirements) == 0 {
+		return nil
+	}
+	prodImports, testImports, err := getGoImports(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	for _, modulePath := range directRequirements {
+		if isImported(modulePath, prodImports) {
+			scopes.add(modulePath, Prod)
+		} else if isImported(modulePath, testImports) {
+			scopes.add(modulePath, Test)
+		}
+	}
+	return nil
+}
+
+// Returns the imports of the source files and the test files of the Go module in moduleDir, without its nested modules
+func getGoImports(moduleDir string) (prodImports, testImports map[string]bool, err error) {
+	prodImports, testImports = map[string]bool{}, map[string]bool{}
+	fileSet := token.NewFileSet()
+	err = filepath.WalkDir(moduleDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path == moduleDir {
+				return nil
+			}
+			if isSkippedDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			if _, statErr := os.Stat(filepath.Join(path, "go.mod")); statErr == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(entry.Name(), ".go") {
+			return nil
+		}
+		file, parseErr := parser.ParseFile(fileSet, path, nil, parser.ImportsOnly)
+		if parseErr != nil {
+			log.Debug(fmt.Sprintf("Couldn't read the imports of %s: %s", path, parseErr.Error()))
+			return nil
+		}
+		imports := prodImports
+		if strings.HasSuffix(entry.Name(), "_test.go") {
+			imports = testImports
+		}
+		for _, importSpec := range file.Imports {
+			if importPath, unquoteErr := strconv.Unquote(importSpec.Path.Value); unquoteErr == nil {
+				imports[importPath] = true
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func isImported(modulePath string, imports map[string]bool) bool {
+	for importPath := range imports {
+		if importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package dependencyscope
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestDetect(t *testing.T) {
+	projectDir := t.TempDir()
+	writeFile(t, filepath.Join(projectDir, "package.json"), `{"dependencies":{"express":"4.18.2"},"devDependencies":{"jest":"29.0.0","express":"4.18.2"},"optionalDependencies":{"fsevents":"2.3.3"}}`)
+	writeFile(t, filepath.Join(projectDir, "node_modules", "jest", "package.json"), `{"dependencies":{"chalk":"4.1.2"}}`)
+	writeFile(t, filepath.Join(projectDir, "java", "pom.xml"), `<project>
+  <dependencies>
+    <dependency><groupId>org.apache.logging.log4j</groupId><artifactId>log4j-core</artifactId></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><scope>test</scope></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><optional>true</optional></dependency>
+  </dependencies>
+  <dependencyManagement>
+    <dependencies>
+      <dependency><groupId>org.yaml</groupId><artifactId>snakeyaml</artifactId></dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`)
+	writeFile(t, filepath.Join(projectDir, "go", "go.mod"), `module example.com/app
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/stretchr/testify v1.8.4
+	github.com/unused/module v1.0.0
+	golang.org/x/net v0.17.0 // indirect
+)
+`)
+	writeFile(t, filepath.Join(projectDir, "go", "main.go"), "package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.New() }\n")
+	writeFile(t, filepath.Join(projectDir, "go", "main_test.go"), "package main\n\nimport \"github.com/stretchr/testify/assert\"\n")
+
+	assert.Equal(t, Scopes{
+		"express":                             Prod,
+		"jest":                                Dev,
+		"fsevents":                            Optional,
+		"org.apache.logging.log4j:log4j-core": Prod,
+		"junit:junit":                         Test,
+		"com.google.guava:guava":              Optional,
+		"github.com/gin-gonic/gin":            Prod,
+		"github.com/stretchr/testify":         Test,
+	}, Detect(projectDir))
+	assert.Nil(t, Detect(t.TempDir()))
+}
+
+func TestGetScope(t *testing.T) {
+	scopes := Scopes{"express": Prod, "jest": Dev, "junit:junit": Test, "fsevents": Optional}
+	testCases := []struct {
+		name               string
+		directDependencies []string
+		expected           Scope
+	}{
+		{name: "Production dependency", directDependencies: []string{"express"}, expected: Prod},
+		{name: "Dev dependency", directDependencies: []string{"jest"}, expected: Dev},
+		{name: "Dev and test dependencies", directDependencies: []string{"jest", "junit:junit"}, expected: Test},
+		{name: "Dev and production dependencies", directDependencies: []string{"jest", "express"}, expected: Prod},
+		{name: "Unknown dependency", directDependencies: []string{"lodash"}, expected: ""},
+		{name: "Dev and unknown dependencies", directDependencies: []string{"jest", "lodash"}, expected: ""},
+		{name: "Production and unknown dependencies", directDependencies: []string{"express", "lodash"}, expected: Prod},
+		{name: "No direct dependencies", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var components []formats.ComponentRow
+			for _, name := range tc.directDependencies {
+				components = append(components, formats.ComponentRow{Name: name})
+			}
+			scope := scopes.GetScope(components)
+			assert.Equal(t, tc.expected, scope)
+			assert.Equal(t, tc.expected != Dev && tc.expected != Test, scope.IsProduction())
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	assert.Nil(t, Scopes(nil).Merge(nil))
+	assert.Equal(t, Scopes{"jest": Dev}, Scopes(nil).Merge(Scopes{"jest": Dev}))
+	assert.Equal(t, Scopes{"jest": Prod, "mocha": Test}, Scopes{"jest": Dev}.Merge(Scopes{"jest": Prod, "mocha": Test}))
+}
@@ -0,0 +1,174 @@
+package dockerimage
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayutils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// The root node of the scanned graph, whose child nodes are the components of the Dockerfiles
+const rootNodeId = "frogbot-dockerfiles"
+
+// XrayScanner runs Xray graph scans. The Xray services manager implements it.
+type XrayScanner interface {
+	ScanGraph(params services.XrayGraphScanParams) (scanId string, err error)
+	GetScanGraphResults(scanId, xrayVersion string, includeVulnerabilities, includeLicenses, xscEnabled bool) (*services.ScanResponse, error)
+}
+
+// Analyzer scans the base images of the Dockerfiles, and the OS packages that the Dockerfiles install explicitly, with Xray.
+type Analyzer struct {
+	scanner     XrayScanner
+	xrayVersion string
+	// The Xray IDs of the components of the target branch. Their vulnerabilities aren't added by the pull request, so they aren't reported.
+	targetComponents *datastructures.Set[string]
+	// The components of Dockerfiles that were already scanned, so each vulnerable component is reported once for all the projects
+	reported *datastructures.Set[string]
+}
+
+// Returns nil if the scan of the Dockerfiles isn't enabled, which disables the analysis
+func NewAnalyzer(enabled bool, serverDetails *config.ServerDetails, xrayVersion string) (*Analyzer, error) {
+	if !enabled {
+		return nil, nil
+	}
+	xrayManager, err := xray.CreateXrayServiceManager(serverDetails)
+	if err != nil {
+		return nil, err
+	}
+	return newAnalyzer(xrayManager, xrayVersion), nil
+}
+
+func newAnalyzer(scanner XrayScanner, xrayVersion string) *Analyzer {
+	return &Analyzer{scanner: scanner, xrayVersion: xrayVersion, reported: datastructures.MakeSet[string]()}
+}
+
+// Sets the Dockerfiles of the target branch of a pull request, so only the components that the pull request adds are scanned by the next analysis
+func (a *Analyzer) SetTargetBranch(dirs ...string) error {
+	if a == nil {
+		return nil
+	}
+	components, err := FindComponents(dirs...)
+	if err != nil {
+		return err
+	}
+	a.targetComponents = datastructures.MakeSet[string]()
+	for _, component := range components {
+		a.targetComponents.Add(component.XrayId)
+	}
+	return nil
+}
+
+// Returns the vulnerabilities of the components of the Dockerfiles in the directories.
+// The paths of the Dockerfiles are reported relative to the root directory of the repository.
+func (a *Analyzer) Analyze(rootDir string, dirs ...string) (vulnerabilities []issues.DockerImageVulnerability, err error) {
+	if a == nil {
+		return
+	}
+	targetComponents := a.targetComponents
+	a.targetComponents = nil
+	components, err := FindComponents(dirs...)
+	if err != nil {
+		return
+	}
+	var componentsToScan []Component
+	for _, component := range components {
+		if relativePath, e := filepath.Rel(rootDir, component.Dockerfile); e == nil {
+			component.Dockerfile = filepath.ToSlash(relativePath)
+		}
+		key := component.Dockerfile + "|" + component.XrayId
+		if a.reported.Exists(key) || (targetComponents != nil && targetComponents.Exists(component.XrayId)) {
+			continue
+		}
+		a.reported.Add(key)
+		componentsToScan = append(componentsToScan, component)
+	}
+	if len(componentsToScan) == 0 {
+		return
+	}
+	log.Info(fmt.Sprintf("Scanning %d base images and OS packages of Dockerfiles...", len(componentsToScan)))
+	scanResponse, err := a.scan(componentsToScan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan the components of the Dockerfiles: %s", err.Error())
+	}
+	return getVulnerabilities(scanResponse, componentsToScan), nil
+}
+
+// Returns the components of the Dockerfiles in the directories
+func FindComponents(dirs ...string) (components []Component, err error) {
+	dockerfiles, err := FindDockerfiles(dirs...)
+	if err != nil {
+		return
+	}
+	for _, dockerfile := range dockerfiles {
+		var dockerfileComponents []Component
+		if dockerfileComponents, err = ParseDockerfile(dockerfile); err != nil {
+			return
+		}
+		components = append(components, dockerfileComponents...)
+	}
+	return
+}
+
+func (a *Analyzer) scan(components []Component) (*services.ScanResponse, error) {
+	graph := &xrayutils.GraphNode{Id: rootNodeId}
+	addedIds := datastructures.MakeSet[string]()
+	for _, component := range components {
+		// The same component may be used by several Dockerfiles
+		if !addedIds.Exists(component.XrayId) {
+			addedIds.Add(component.XrayId)
+			graph.Nodes = append(graph.Nodes, &xrayutils.GraphNode{Id: component.XrayId})
+		}
+	}
+	scanId, err := a.scanner.ScanGraph(services.XrayGraphScanParams{
+		DependenciesGraph:      graph,
+		IncludeVulnerabilities: true,
+		ScanType:               services.Dependency,
+		XrayVersion:            a.xrayVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a.scanner.GetScanGraphResults(scanId, a.xrayVersion, true, false, false)
+}
+
+// Returns a vulnerability of each component that the Xray vulnerabilities impact, in the order of the components
+func getVulnerabilities(scanResponse *services.ScanResponse, components []Component) (vulnerabilities []issues.DockerImageVulnerability) {
+	if scanResponse == nil {
+		return
+	}
+	for _, component := range components {
+		for _, xrayVulnerability := range scanResponse.Vulnerabilities {
+			impactedComponent, isImpacted := xrayVulnerability.Components[component.XrayId]
+			if !isImpacted {
+				continue
+			}
+			var cves []string
+			for _, cve := range xrayVulnerability.Cves {
+				if cve.Id != "" {
+					cves = append(cves, cve.Id)
+				}
+			}
+			vulnerabilities = append(vulnerabilities, issues.DockerImageVulnerability{
+				Dockerfile:    component.Dockerfile,
+				Line:          component.Line,
+				ComponentType: component.Type,
+				Name:          component.Name,
+				Version:       component.Version,
+				Severity:      xrayVulnerability.Severity,
+				IssueId:       xrayVulnerability.IssueId,
+				Cves:          cves,
+				FixedVersions: impactedComponent.FixedVersions,
+			})
+		}
+	}
+	if len(vulnerabilities) > 0 {
+		log.Info(fmt.Sprintf("Found %d vulnerabilities in the base images and OS packages of Dockerfiles", len(vulnerabilities)))
+	}
+	return
+}
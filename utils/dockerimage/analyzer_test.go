@@ -0,0 +1,69 @@
+package dockerimage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockXrayScanner struct {
+	scannedIds []string
+	response   *services.ScanResponse
+}
+
+func (ms *mockXrayScanner) ScanGraph(params services.XrayGraphScanParams) (string, error) {
+	ms.scannedIds = nil
+	for _, node := range params.DependenciesGraph.Nodes {
+		ms.scannedIds = append(ms.scannedIds, node.Id)
+	}
+	return "scan-id", nil
+}
+
+func (ms *mockXrayScanner) GetScanGraphResults(string, string, bool, bool, bool) (*services.ScanResponse, error) {
+	return ms.response, nil
+}
+
+func TestAnalyzer(t *testing.T) {
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "app", "Dockerfile"), []byte("FROM nginx:1.19\nRUN apt-get install -y curl=7.68.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(targetDir, "Dockerfile"), []byte("FROM nginx:1.19\n"), 0644))
+	scanner := &mockXrayScanner{response: &services.ScanResponse{Vulnerabilities: []services.Vulnerability{
+		{IssueId: "XRAY-1", Severity: "High", Cves: []services.Cve{{Id: "CVE-2021-23017"}}, Components: map[string]services.Component{"docker://nginx:1.19": {FixedVersions: []string{"[1.21.0]"}}}},
+		{IssueId: "XRAY-2", Severity: "Low", Components: map[string]services.Component{"deb://curl:7.68.0": {}}},
+	}}}
+
+	// Only the components that the pull request adds are scanned
+	analyzer := newAnalyzer(scanner, "3.107.0")
+	require.NoError(t, analyzer.SetTargetBranch(targetDir))
+	vulnerabilities, err := analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deb://curl:7.68.0"}, scanner.scannedIds)
+	assert.Equal(t, []issues.DockerImageVulnerability{{Dockerfile: "app/Dockerfile", Line: 2, ComponentType: OsPackageType, Name: "curl", Version: "7.68.0", Severity: "Low", IssueId: "XRAY-2"}}, vulnerabilities)
+
+	// All the components are scanned without a target branch
+	analyzer = newAnalyzer(scanner, "3.107.0")
+	vulnerabilities, err = analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker://nginx:1.19", "deb://curl:7.68.0"}, scanner.scannedIds)
+	require.Len(t, vulnerabilities, 2)
+	assert.Equal(t, issues.DockerImageVulnerability{Dockerfile: "app/Dockerfile", Line: 1, ComponentType: BaseImageType, Name: "nginx", Version: "1.19", Severity: "High", IssueId: "XRAY-1", Cves: []string{"CVE-2021-23017"}, FixedVersions: []string{"[1.21.0]"}}, vulnerabilities[0])
+
+	// The components are reported once for all the projects
+	scanner.scannedIds = nil
+	vulnerabilities, err = analyzer.Analyze(sourceDir, filepath.Join(sourceDir, "app"))
+	require.NoError(t, err)
+	assert.Empty(t, vulnerabilities)
+	assert.Empty(t, scanner.scannedIds)
+
+	// The analysis is disabled
+	var disabled *Analyzer
+	vulnerabilities, err = disabled.Analyze(sourceDir, sourceDir)
+	assert.NoError(t, err)
+	assert.Empty(t, vulnerabilities)
+}
@@ -0,0 +1,283 @@
+package dockerimage
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	BaseImageType = "Base Image"
+	OsPackageType = "OS Package"
+	// The tag that Docker pulls when an image reference has no tag
+	defaultTag = "latest"
+)
+
+var (
+	// Matches the variable references of Dockerfile instructions: $VAR, ${VAR} and ${VAR:-default}
+	variableRegex = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?}|([A-Za-z_][A-Za-z0-9_]*))`)
+	// RPM packages are installed as 'name-version', where the version starts with a digit
+	rpmPackageRegex = regexp.MustCompile(`^(.+?)-(\d[^-]*(?:-[^-]+)?)$`)
+	// Separates the shell commands of a RUN instruction
+	commandSeparatorRegex = regexp.MustCompile(`&&|\|\||;|\|`)
+	// The directories that don't contain the Dockerfiles of the project
+	skippedDirs = []string{".git", "node_modules", "vendor"}
+)
+
+// Component is a base image or an OS package that a Dockerfile installs explicitly
+type Component struct {
+	// BaseImageType or OsPackageType
+	Type    string
+	Name    string
+	Version string
+	// The Xray component ID, such as 'docker://nginx:1.25.3' or 'deb://openssl:3.0.11'
+	XrayId string
+	// The path of the Dockerfile and the line of the instruction that uses the component
+	Dockerfile string
+	Line       int
+}
+
+// The OS package managers whose installed packages are scanned, by their commands and the Xray package type of their packages.
+// Only packages that are pinned to a version are scanned, since Xray requires the version of the scanned components.
+var osPackageManagers = []struct {
+	command       []string
+	xrayPrefix    string
+	rpmVersioning bool
+}{
+	{command: []string{"apt-get", "install"}, xrayPrefix: "deb://"},
+	{command: []string{"apt", "install"}, xrayPrefix: "deb://"},
+	{command: []string{"apk", "add"}, xrayPrefix: "alpine://"},
+	{command: []string{"yum", "install"}, xrayPrefix: "rpm://", rpmVersioning: true},
+	{command: []string{"dnf", "install"}, xrayPrefix: "rpm://", rpmVersioning: true},
+	{command: []string{"microdnf", "install"}, xrayPrefix: "rpm://", rpmVersioning: true},
+}
+
+// Returns true for the default Dockerfile and Containerfile names, and for names such as 'Dockerfile.prod' or 'app.Dockerfile'
+func IsDockerfile(fileName string) bool {
+	for _, name := range []string{"Dockerfile", "Containerfile"} {
+		if fileName == name || strings.HasPrefix(fileName, name+".") || strings.HasSuffix(fileName, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the paths of the Dockerfiles in the directories and their subdirectories
+func FindDockerfiles(dirs ...string) (dockerfiles []string, err error) {
+	visited := map[string]bool{}
+	for _, dir := range dirs {
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, innerErr error) error {
+			if innerErr != nil {
+				return innerErr
+			}
+			if d.IsDir() {
+				for _, skippedDir := range skippedDirs {
+					if d.Name() == skippedDir {
+						return filepath.SkipDir
+					}
+				}
+				return nil
+			}
+			// The working directories of a project may be nested
+			if IsDockerfile(d.Name()) && !visited[path] {
+				visited[path] = true
+				dockerfiles = append(dockerfiles, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for Dockerfiles in '%s': %s", dir, err.Error())
+		}
+	}
+	return
+}
+
+// Returns the base images and the versioned OS packages of the Dockerfile.
+// Images that are built by earlier stages of the Dockerfile, 'scratch', and images with unresolved build arguments are skipped.
+func ParseDockerfile(dockerfilePath string) (components []Component, err error) {
+	instructions, err := readInstructions(dockerfilePath)
+	if err != nil {
+		return
+	}
+	args := map[string]string{}
+	stages := map[string]bool{}
+	for _, instruction := range instructions {
+		keyword, arguments, _ := strings.Cut(instruction.content, " ")
+		arguments = strings.TrimSpace(arguments)
+		switch strings.ToUpper(keyword) {
+		case "ARG":
+			name, value, _ := strings.Cut(arguments, "=")
+			if _, exists := args[name]; !exists || value != "" {
+				args[name] = strings.Trim(value, `"'`)
+			}
+		case "FROM":
+			image, stage := parseFromInstruction(expandVariables(arguments, args))
+			if stage != "" {
+				stages[strings.ToLower(stage)] = true
+			}
+			if component, ok := newBaseImage(image, stages); ok {
+				component.Dockerfile, component.Line = dockerfilePath, instruction.line
+				components = append(components, component)
+			}
+		case "RUN":
+			for _, component := range parseOsPackages(expandVariables(arguments, args)) {
+				component.Dockerfile, component.Line = dockerfilePath, instruction.line
+				components = append(components, component)
+			}
+		}
+	}
+	return
+}
+
+type instruction struct {
+	content string
+	// The line where the instruction starts
+	line int
+}
+
+// Reads the instructions of the Dockerfile, joining the lines that end with a backslash and skipping the comments
+func readInstructions(dockerfilePath string) (instructions []instruction, err error) {
+	file, err := os.Open(dockerfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Dockerfile '%s': %s", dockerfilePath, err.Error())
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	scanner := bufio.NewScanner(file)
+	var current strings.Builder
+	startLine := 0
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || (line == "" && current.Len() == 0) {
+			continue
+		}
+		if current.Len() == 0 {
+			startLine = lineNumber
+		}
+		continuation, isContinued := strings.CutSuffix(line, "\\")
+		current.WriteString(strings.TrimSpace(continuation) + " ")
+		if !isContinued {
+			instructions = append(instructions, instruction{content: strings.TrimSpace(current.String()), line: startLine})
+			current.Reset()
+		}
+	}
+	if current.Len() > 0 {
+		instructions = append(instructions, instruction{content: strings.TrimSpace(current.String()), line: startLine})
+	}
+	return instructions, scanner.Err()
+}
+
+func expandVariables(content string, args map[string]string) string {
+	return variableRegex.ReplaceAllStringFunc(content, func(reference string) string {
+		groups := variableRegex.FindStringSubmatch(reference)
+		name := groups[1] + groups[3]
+		if value := args[name]; value != "" {
+			return value
+		}
+		if groups[2] != "" {
+			return groups[2]
+		}
+		return reference
+	})
+}
+
+// Returns the image and the stage name of a 'FROM [--platform=<platform>] <image> [AS <name>]' instruction
+func parseFromInstruction(arguments string) (image, stage string) {
+	var fields []string
+	for _, field := range strings.Fields(arguments) {
+		if !strings.HasPrefix(field, "--") {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	image = fields[0]
+	if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+		stage = fields[2]
+	}
+	return
+}
+
+func newBaseImage(image string, stages map[string]bool) (component Component, ok bool) {
+	if image == "" || strings.EqualFold(image, "scratch") || stages[strings.ToLower(image)] {
+		return
+	}
+	if strings.Contains(image, "$") {
+		log.Debug(fmt.Sprintf("Skipping the base image '%s', since its build arguments have no default values", image))
+		return
+	}
+	name, tag := SplitImageReference(image)
+	return Component{Type: BaseImageType, Name: name, Version: tag, XrayId: fmt.Sprintf("docker://%s:%s", name, tag)}, true
+}
+
+// Splits an image reference, such as 'registry:5000/team/app:1.0@sha256:...', to the image name and its tag.
+// The digest is dropped, and the tag defaults to 'latest'.
+func SplitImageReference(image string) (name, tag string) {
+	image, _, _ = strings.Cut(image, "@")
+	name, tag = image, defaultTag
+	// The registry may have a port, so the tag is looked for after the last slash
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		name, tag = image[:colon], image[colon+1:]
+	}
+	return
+}
+
+// Returns the OS packages that are pinned to a version in the install commands of a RUN instruction
+func parseOsPackages(command string) (components []Component) {
+	for _, subCommand := range commandSeparatorRegex.Split(command, -1) {
+		fields := strings.Fields(subCommand)
+		for _, manager := range osPackageManagers {
+			packagesIndex := getPackagesIndex(fields, manager.command)
+			if packagesIndex < 0 {
+				continue
+			}
+			for _, field := range fields[packagesIndex:] {
+				if strings.HasPrefix(field, "-") {
+					continue
+				}
+				name, version, found := strings.Cut(field, "=")
+				if manager.rpmVersioning {
+					groups := rpmPackageRegex.FindStringSubmatch(field)
+					name, found = field, groups != nil
+					if found {
+						name, version = groups[1], groups[2]
+					}
+				}
+				if !found || version == "" {
+					log.Debug(fmt.Sprintf("Skipping the OS package '%s', since it isn't pinned to a version", field))
+					continue
+				}
+				components = append(components, Component{Type: OsPackageType, Name: name, Version: version, XrayId: fmt.Sprintf("%s%s:%s", manager.xrayPrefix, name, version)})
+			}
+			break
+		}
+	}
+	return
+}
+
+// Returns the index of the first package of the install command, or -1 if the fields don't run the install command.
+// Options may appear between the words of the command, such as 'apt-get -y install'.
+func getPackagesIndex(fields, command []string) int {
+	start := slices.Index(fields, command[0])
+	if start < 0 {
+		return -1
+	}
+	for i := start + 1; i < len(fields); i++ {
+		if fields[i] == command[1] {
+			return i + 1
+		}
+		if !strings.HasPrefix(fields[i], "-") {
+			return -1
+		}
+	}
+	return -1
+}
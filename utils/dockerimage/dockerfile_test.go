@@ -0,0 +1,95 @@
+package dockerimage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDockerfile = `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.21
+ARG RUNTIME_IMAGE
+
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION} AS build
+RUN go build -o /app .
+
+FROM build AS test
+RUN go test ./...
+
+FROM ${RUNTIME_IMAGE}
+
+FROM scratch AS empty
+
+FROM registry.example.com:5000/team/base@sha256:0123456789abcdef
+FROM debian:bookworm-slim
+RUN apt-get update && \
+    DEBIAN_FRONTEND=noninteractive apt-get install -y --no-install-recommends \
+      curl=7.88.1-10 \
+      ca-certificates && \
+    rm -rf /var/lib/apt/lists/*
+RUN apk add --no-cache openssl=3.1.4-r0 bash
+RUN yum install -y httpd-2.4.57 git
+COPY --from=build /app /app
+`
+
+func TestParseDockerfile(t *testing.T) {
+	dockerfilePath := filepath.Join(t.TempDir(), "Dockerfile")
+	require.NoError(t, os.WriteFile(dockerfilePath, []byte(testDockerfile), 0644))
+	components, err := ParseDockerfile(dockerfilePath)
+	require.NoError(t, err)
+	expected := []Component{
+		{Type: BaseImageType, Name: "golang", Version: "1.21", XrayId: "docker://golang:1.21", Line: 5},
+		{Type: BaseImageType, Name: "registry.example.com:5000/team/base", Version: "latest", XrayId: "docker://registry.example.com:5000/team/base:latest", Line: 15},
+		{Type: BaseImageType, Name: "debian", Version: "bookworm-slim", XrayId: "docker://debian:bookworm-slim", Line: 16},
+		{Type: OsPackageType, Name: "curl", Version: "7.88.1-10", XrayId: "deb://curl:7.88.1-10", Line: 17},
+		{Type: OsPackageType, Name: "openssl", Version: "3.1.4-r0", XrayId: "alpine://openssl:3.1.4-r0", Line: 22},
+		{Type: OsPackageType, Name: "httpd", Version: "2.4.57", XrayId: "rpm://httpd:2.4.57", Line: 23},
+	}
+	for i := range expected {
+		expected[i].Dockerfile = dockerfilePath
+	}
+	assert.Equal(t, expected, components)
+
+	_, err = ParseDockerfile(filepath.Join(t.TempDir(), "Dockerfile"))
+	assert.ErrorContains(t, err, "failed to read the Dockerfile")
+}
+
+func TestSplitImageReference(t *testing.T) {
+	testCases := []struct {
+		image        string
+		expectedName string
+		expectedTag  string
+	}{
+		{image: "nginx", expectedName: "nginx", expectedTag: "latest"},
+		{image: "nginx:1.25.3-alpine", expectedName: "nginx", expectedTag: "1.25.3-alpine"},
+		{image: "localhost:5000/nginx", expectedName: "localhost:5000/nginx", expectedTag: "latest"},
+		{image: "localhost:5000/nginx:1.25@sha256:abc", expectedName: "localhost:5000/nginx", expectedTag: "1.25"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			name, tag := SplitImageReference(tc.image)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestFindDockerfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, path := range []string{"Dockerfile", "app/Dockerfile.prod", "app/web.Dockerfile", "app/Containerfile", "app/Dockerfiles.md", "node_modules/lib/Dockerfile", ".git/Dockerfile"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, path), []byte("FROM nginx"), 0644))
+	}
+	// Nested working directories list their Dockerfiles once
+	dockerfiles, err := FindDockerfiles(tmpDir, filepath.Join(tmpDir, "app"))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(tmpDir, "Dockerfile"),
+		filepath.Join(tmpDir, "app", "Dockerfile.prod"),
+		filepath.Join(tmpDir, "app", "web.Dockerfile"),
+		filepath.Join(tmpDir, "app", "Containerfile"),
+	}, dockerfiles)
+}
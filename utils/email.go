@@ -3,60 +3,194 @@ package utils
 import (
 	"context"
 	"fmt"
+	"html"
 	"net/smtp"
+	"slices"
 	"strings"
 
+	"github.com/jfrog/frogbot/v2/utils/issues"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jordan-wright/email"
 )
 
 var blacklistedEmailAddresses = []string{"no-reply", "no_reply", "noreply", "no.reply", "frogbot"}
 
-type SecretsEmailDetails struct {
+// The types of the alerts that are sent by email. Each type is sent to the receivers that are configured for it.
+type AlertType string
+
+const (
+	SecretsAlert                 AlertType = "secrets"
+	CriticalVulnerabilitiesAlert AlertType = "critical"
+	LicenseViolationsAlert       AlertType = "licenses"
+)
+
+// EmailAlert is an alert about the issues that a pull request adds
+type EmailAlert struct {
+	Type    AlertType
+	Subject string
+	// The HTML content of the email
+	Content string
+}
+
+// EmailNotifier sends the alerts about the issues of a pull request to the receivers of their types
+type EmailNotifier struct {
 	gitClient       vcsclient.VcsClient
 	gitProvider     vcsutils.VcsProvider
 	branch          string
 	repoName        string
 	repoOwner       string
-	detectedSecrets []formats.SourceCodeRow
 	pullRequestLink string
 	EmailDetails
 }
 
-func NewSecretsEmailDetails(gitClient vcsclient.VcsClient, repoConfig *Repository, secrets []formats.SourceCodeRow) *SecretsEmailDetails {
-	secretsEmailDetails := &SecretsEmailDetails{
+func NewEmailNotifier(gitClient vcsclient.VcsClient, repoConfig *Repository) *EmailNotifier {
+	return &EmailNotifier{
 		gitClient:       gitClient,
 		EmailDetails:    repoConfig.EmailDetails,
 		gitProvider:     repoConfig.GitProvider,
 		repoOwner:       repoConfig.PullRequestDetails.Source.Owner,
 		repoName:        repoConfig.PullRequestDetails.Source.Repository,
 		branch:          repoConfig.PullRequestDetails.Source.Name,
-		detectedSecrets: secrets,
 		pullRequestLink: repoConfig.PullRequestDetails.URL,
 	}
-	return secretsEmailDetails
 }
 
-func AlertSecretsExposed(secretsDetails *SecretsEmailDetails) (err error) {
-	if len(secretsDetails.detectedSecrets) == 0 {
+// Sends the alerts about the exposed secrets, the critical vulnerabilities and the license violations that the pull request adds
+func (en *EmailNotifier) AlertIssues(issuesCollection *issues.ScansIssuesCollection) error {
+	alerts := []*EmailAlert{
+		en.newSecretsAlert(append(slices.Clone(issuesCollection.SecretsVulnerabilities), issuesCollection.SecretsViolations...)),
+		en.newCriticalVulnerabilitiesAlert(append(slices.Clone(issuesCollection.ScaVulnerabilities), issuesCollection.ScaViolations...)),
+		en.newLicenseViolationsAlert(issuesCollection.LicensesViolations),
+	}
+	for _, alert := range alerts {
+		if err := en.Send(alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The exposed secrets alerts are sent to the authors of the commits of the pull request too, as they should rotate the secrets.
+// The other alerts are sent only when receivers are configured for their types.
+func (en *EmailNotifier) Send(alert *EmailAlert) (err error) {
+	if alert == nil {
 		return
 	}
-	var relevantEmailReceivers []string
-	if relevantEmailReceivers, err = getRelevantEmailReceivers(secretsDetails.gitClient, secretsDetails.repoOwner, secretsDetails.repoName, secretsDetails.branch, secretsDetails.EmailReceivers); err != nil {
+	receivers := slices.Clone(en.EmailReceivers.Get(alert.Type))
+	if alert.Type == SecretsAlert {
+		var commitAuthors []string
+		if commitAuthors, err = getRelevantEmailReceivers(en.gitClient, en.repoOwner, en.repoName, en.branch, receivers); err != nil {
+			return
+		}
+		receivers = append(receivers, commitAuthors...)
+	}
+	if len(receivers) == 0 {
+		log.Debug(fmt.Sprintf("No email receivers are configured for the %s alerts", alert.Type))
 		return
 	}
-	secretsDetails.EmailReceivers = append(secretsDetails.EmailReceivers, relevantEmailReceivers...)
-	emailDetails := secretsDetails.EmailDetails
-	emailContent := getSecretsEmailContent(secretsDetails.detectedSecrets, secretsDetails.gitProvider, secretsDetails.pullRequestLink)
-	sender := fmt.Sprintf("JFrog Frogbot <%s>", emailDetails.SmtpUser)
+	sender := fmt.Sprintf("JFrog Frogbot <%s>", en.SmtpUser)
+	return sendEmail(sender, alert.Subject, alert.Content, receivers, en.EmailDetails)
+}
+
+func (en *EmailNotifier) newSecretsAlert(secrets []formats.SourceCodeRow) *EmailAlert {
+	if len(secrets) == 0 {
+		return nil
+	}
 	subject := outputwriter.FrogbotTitlePrefix + " Potential secrets detected"
-	return sendEmail(sender, subject, emailContent, emailDetails)
+	if hasActiveSecrets(secrets) {
+		subject = outputwriter.FrogbotTitlePrefix + " Active secrets detected"
+	}
+	return &EmailAlert{Type: SecretsAlert, Subject: subject, Content: getSecretsEmailContent(secrets, en.gitProvider, en.pullRequestLink)}
+}
+
+// The vulnerabilities that are both reported as vulnerabilities and as violations are listed once
+func (en *EmailNotifier) newCriticalVulnerabilitiesAlert(vulnerabilities []formats.VulnerabilityOrViolationRow) *EmailAlert {
+	var rows [][]string
+	listed := datastructures.MakeSet[string]()
+	for _, vulnerability := range vulnerabilities {
+		if vulnerability.Severity != severityutils.Critical.String() {
+			continue
+		}
+		id := results.GetIssueIdentifier(vulnerability.Cves, vulnerability.IssueId, ", ")
+		key := strings.Join([]string{id, vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion}, "|")
+		if listed.Exists(key) {
+			continue
+		}
+		listed.Add(key)
+		rows = append(rows, []string{id, vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion, strings.Join(vulnerability.FixedVersions, ", ")})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return &EmailAlert{
+		Type:    CriticalVulnerabilitiesAlert,
+		Subject: outputwriter.FrogbotTitlePrefix + " Critical vulnerabilities detected",
+		Content: getAlertEmailContent("Frogbot Critical Vulnerabilities", "critical vulnerabilities", []string{"ID", "DEPENDENCY", "VERSION", "FIXED VERSIONS"}, rows, en.gitProvider, en.pullRequestLink),
+	}
+}
+
+func (en *EmailNotifier) newLicenseViolationsAlert(violations []formats.LicenseViolationRow) *EmailAlert {
+	if len(violations) == 0 {
+		return nil
+	}
+	var rows [][]string
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.LicenseKey, violation.ImpactedDependencyName, violation.ImpactedDependencyVersion})
+	}
+	return &EmailAlert{
+		Type:    LicenseViolationsAlert,
+		Subject: outputwriter.FrogbotTitlePrefix + " License violations detected",
+		Content: getAlertEmailContent("Frogbot License Violations", "license violations", []string{"LICENSE", "DEPENDENCY", "VERSION"}, rows, en.gitProvider, en.pullRequestLink),
+	}
+}
+
+// The values of the table are escaped, as they come from the scanned dependencies
+func getAlertEmailContent(title, issuesDescription string, headers []string, rows [][]string, gitProvider vcsutils.VcsProvider, pullRequestLink string) string {
+	var headerContent strings.Builder
+	for _, header := range headers {
+		headerContent.WriteString(fmt.Sprintf(outputwriter.AlertEmailTableHeaderCell, header))
+	}
+	var tableContent strings.Builder
+	for _, row := range rows {
+		tableContent.WriteString("\n\t\t\t\t<tr>")
+		for _, value := range row {
+			tableContent.WriteString(fmt.Sprintf(outputwriter.AlertEmailTableCell, html.EscapeString(value)))
+		}
+		tableContent.WriteString("\n\t\t\t\t</tr>")
+	}
+	return fmt.Sprintf(
+		outputwriter.AlertEmailHTMLTemplate,
+		title,
+		outputwriter.SecretsEmailCSS,
+		issuesDescription,
+		pullRequestLink,
+		getPullOrMergeRequest(gitProvider),
+		headerContent.String(),
+		tableContent.String(),
+	)
+}
+
+func hasActiveSecrets(secrets []formats.SourceCodeRow) bool {
+	for _, secret := range secrets {
+		if getSecretValidationStatus(secret) == jasutils.Active.String() {
+			return true
+		}
+	}
+	return false
 }
 
 func getSecretsEmailContent(secrets []formats.SourceCodeRow, gitProvider vcsutils.VcsProvider, pullRequestLink string) string {
+	// The active secrets are listed first, as they should be revoked before the others are handled
+	secrets = slices.Clone(secrets)
+	SortSecretsByValidationStatus(secrets)
 	var tableContent strings.Builder
 	for _, secret := range secrets {
 		tableContent.WriteString(
@@ -64,32 +198,35 @@ func getSecretsEmailContent(secrets []formats.SourceCodeRow, gitProvider vcsutil
 				secret.File,
 				secret.StartLine,
 				secret.StartColumn,
-				secret.Snippet))
+				secret.Snippet,
+				getSecretValidationStatus(secret)))
 	}
-	pullOrMergeRequest := "pull request"
-	if gitProvider == vcsutils.GitLab {
-		pullOrMergeRequest = "merge request"
-	}
-
 	return fmt.Sprintf(
 		outputwriter.SecretsEmailHTMLTemplate,
 		outputwriter.SecretsEmailCSS,
 		pullRequestLink,
-		pullOrMergeRequest,
+		getPullOrMergeRequest(gitProvider),
 		tableContent.String(),
 	)
 }
 
-func sendEmail(sender, subject, content string, emailDetails EmailDetails) error {
-	e := prepareEmail(sender, subject, content, emailDetails)
+func getPullOrMergeRequest(gitProvider vcsutils.VcsProvider) string {
+	if gitProvider == vcsutils.GitLab {
+		return "merge request"
+	}
+	return "pull request"
+}
+
+func sendEmail(sender, subject, content string, receivers []string, emailDetails EmailDetails) error {
+	e := prepareEmail(sender, subject, content, receivers)
 	smtpAuth := smtp.PlainAuth("", emailDetails.SmtpUser, emailDetails.SmtpPassword, emailDetails.SmtpServer)
 	return e.Send(strings.Join([]string{emailDetails.SmtpServer, emailDetails.SmtpPort}, ":"), smtpAuth)
 }
 
-func prepareEmail(sender, subject, content string, emailDetails EmailDetails) *email.Email {
+func prepareEmail(sender, subject, content string, receivers []string) *email.Email {
 	e := email.NewEmail()
 	e.From = sender
-	e.To = emailDetails.EmailReceivers
+	e.To = receivers
 	e.Subject = subject
 	e.HTML = []byte(content)
 	return e
@@ -2,14 +2,18 @@ package utils
 
 import (
 	"net/textproto"
+	"strings"
 	"testing"
 
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	"github.com/jordan-wright/email"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestGetSecretsEmailContent(t *testing.T) {
@@ -25,29 +29,49 @@ func TestGetSecretsEmailContent(t *testing.T) {
 				File: "/server-conf.json", StartLine: 15, StartColumn: 20, Snippet: "pass*****"}},
 	}
 	// Test for results including the "Pull Request" keyword
-	expected := "\n<!DOCTYPE html>\n<html>\n<head>\n    <title>Frogbot Secret Detection</title>\n    <style>\n        body {\n            font-family: Arial, sans-serif;\n            background-color: #f5f5f5;\n        }\n        table {\n            border-collapse: collapse;\n            width: 80%;\n        }\n        th, td {\n            padding: 10px;\n            border: 1px solid #ccc;\n        }\n        th {\n            background-color: #f2f2f2;\n        }\n        tr:nth-child(even) {\n            background-color: #f9f9f9;\n        }\n        tr:hover {\n            background-color: #f5f5f5;\n        }\n        .table-container {\n            max-width: 700px;\n            padding: 20px;\n            box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);\n            border-radius: 10px;\n            overflow: hidden;\n            background-color: #fff;\n\t\t\tmargin-top: 10px;\n        }\n        .ignore-comments {\n            margin-top: 10px;\n\t\t\tmargin-bottom: 5px;\n            border-radius: 5px;\n        }\n    </style>\n</head>\n<body>\n\t<div>\n\t\tThe following potential exposed secrets in your <a href=\"https://github.com/owner/repo/pullrequest/1\">pull request</a> have been detected by <a href=\"https://docs.jfrog-applications.jfrog.io/jfrog-applications/frogbot\">Frogbot</a>\n\t\t<br/>\n\t\t<table class=\"table-container\">\n            <thead>\n                <tr>\n                    <th>FILE</th>\n                    <th>LINE:COLUMN</th>\n                    <th>SECRET</th>\n                </tr>\n            </thead>\n            <tbody>\n                \n\t\t\t\t<tr>\n\t\t\t\t\t<td> /config.yaml </td>\n\t\t\t\t\t<td> 12:30 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t</tr>\n\t\t\t\t<tr>\n\t\t\t\t\t<td> /server-conf.json </td>\n\t\t\t\t\t<td> 15:20 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t</tr>\n            </tbody>\n        </table>\n\t\t<div class=\"ignore-comments\">\n\t\t<b>NOTE:</b> If you'd like Frogbot to ignore the lines with the potential secrets, add a comment that includes the <b>jfrog-ignore</b> keyword above the lines with the secrets.\t\n\t\t</div>\n\t</div>\n</body>\n</html>"
+	expected := "\n<!DOCTYPE html>\n<html>\n<head>\n    <title>Frogbot Secret Detection</title>\n    <style>\n        body {\n            font-family: Arial, sans-serif;\n            background-color: #f5f5f5;\n        }\n        table {\n            border-collapse: collapse;\n            width: 80%;\n        }\n        th, td {\n            padding: 10px;\n            border: 1px solid #ccc;\n        }\n        th {\n            background-color: #f2f2f2;\n        }\n        tr:nth-child(even) {\n            background-color: #f9f9f9;\n        }\n        tr:hover {\n            background-color: #f5f5f5;\n        }\n        .table-container {\n            max-width: 700px;\n            padding: 20px;\n            box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);\n            border-radius: 10px;\n            overflow: hidden;\n            background-color: #fff;\n\t\t\tmargin-top: 10px;\n        }\n        .ignore-comments {\n            margin-top: 10px;\n\t\t\tmargin-bottom: 5px;\n            border-radius: 5px;\n        }\n    </style>\n</head>\n<body>\n\t<div>\n\t\tThe following potential exposed secrets in your <a href=\"https://github.com/owner/repo/pullrequest/1\">pull request</a> have been detected by <a href=\"https://docs.jfrog-applications.jfrog.io/jfrog-applications/frogbot\">Frogbot</a>\n\t\t<br/>\n\t\t<table class=\"table-container\">\n            <thead>\n                <tr>\n                    <th>FILE</th>\n                    <th>LINE:COLUMN</th>\n                    <th>SECRET</th>\n                    <th>STATUS</th>\n                </tr>\n            </thead>\n            <tbody>\n                \n\t\t\t\t<tr>\n\t\t\t\t\t<td> /config.yaml </td>\n\t\t\t\t\t<td> 12:30 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t\t<td>  </td>\n\t\t\t\t</tr>\n\t\t\t\t<tr>\n\t\t\t\t\t<td> /server-conf.json </td>\n\t\t\t\t\t<td> 15:20 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t\t<td>  </td>\n\t\t\t\t</tr>\n            </tbody>\n        </table>\n\t\t<div class=\"ignore-comments\">\n\t\t<b>NOTE:</b> If you'd like Frogbot to ignore the lines with the potential secrets, add a comment that includes the <b>jfrog-ignore</b> keyword above the lines with the secrets.\t\n\t\t</div>\n\t</div>\n</body>\n</html>"
 	actualContent := getSecretsEmailContent(secrets, vcsutils.GitHub, "https://github.com/owner/repo/pullrequest/1")
 	assert.Equal(t, expected, actualContent)
 
 	// Test for results including the "Merge Request" keyword
-	expected = "\n<!DOCTYPE html>\n<html>\n<head>\n    <title>Frogbot Secret Detection</title>\n    <style>\n        body {\n            font-family: Arial, sans-serif;\n            background-color: #f5f5f5;\n        }\n        table {\n            border-collapse: collapse;\n            width: 80%;\n        }\n        th, td {\n            padding: 10px;\n            border: 1px solid #ccc;\n        }\n        th {\n            background-color: #f2f2f2;\n        }\n        tr:nth-child(even) {\n            background-color: #f9f9f9;\n        }\n        tr:hover {\n            background-color: #f5f5f5;\n        }\n        .table-container {\n            max-width: 700px;\n            padding: 20px;\n            box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);\n            border-radius: 10px;\n            overflow: hidden;\n            background-color: #fff;\n\t\t\tmargin-top: 10px;\n        }\n        .ignore-comments {\n            margin-top: 10px;\n\t\t\tmargin-bottom: 5px;\n            border-radius: 5px;\n        }\n    </style>\n</head>\n<body>\n\t<div>\n\t\tThe following potential exposed secrets in your <a href=\"https://github.com/owner/repo/pullrequest/1\">merge request</a> have been detected by <a href=\"https://docs.jfrog-applications.jfrog.io/jfrog-applications/frogbot\">Frogbot</a>\n\t\t<br/>\n\t\t<table class=\"table-container\">\n            <thead>\n                <tr>\n                    <th>FILE</th>\n                    <th>LINE:COLUMN</th>\n                    <th>SECRET</th>\n                </tr>\n            </thead>\n            <tbody>\n                \n\t\t\t\t<tr>\n\t\t\t\t\t<td> /config.yaml </td>\n\t\t\t\t\t<td> 12:30 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t</tr>\n\t\t\t\t<tr>\n\t\t\t\t\t<td> /server-conf.json </td>\n\t\t\t\t\t<td> 15:20 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t</tr>\n            </tbody>\n        </table>\n\t\t<div class=\"ignore-comments\">\n\t\t<b>NOTE:</b> If you'd like Frogbot to ignore the lines with the potential secrets, add a comment that includes the <b>jfrog-ignore</b> keyword above the lines with the secrets.\t\n\t\t</div>\n\t</div>\n</body>\n</html>"
+	expected = "\n<!DOCTYPE html>\n<html>\n<head>\n    <title>Frogbot Secret Detection</title>\n    <style>\n        body {\n            font-family: Arial, sans-serif;\n            background-color: #f5f5f5;\n        }\n        table {\n            border-collapse: collapse;\n            width: 80%;\n        }\n        th, td {\n            padding: 10px;\n            border: 1px solid #ccc;\n        }\n        th {\n            background-color: #f2f2f2;\n        }\n        tr:nth-child(even) {\n            background-color: #f9f9f9;\n        }\n        tr:hover {\n            background-color: #f5f5f5;\n        }\n        .table-container {\n            max-width: 700px;\n            padding: 20px;\n            box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);\n            border-radius: 10px;\n            overflow: hidden;\n            background-color: #fff;\n\t\t\tmargin-top: 10px;\n        }\n        .ignore-comments {\n            margin-top: 10px;\n\t\t\tmargin-bottom: 5px;\n            border-radius: 5px;\n        }\n    </style>\n</head>\n<body>\n\t<div>\n\t\tThe following potential exposed secrets in your <a href=\"https://github.com/owner/repo/pullrequest/1\">merge request</a> have been detected by <a href=\"https://docs.jfrog-applications.jfrog.io/jfrog-applications/frogbot\">Frogbot</a>\n\t\t<br/>\n\t\t<table class=\"table-container\">\n            <thead>\n                <tr>\n                    <th>FILE</th>\n                    <th>LINE:COLUMN</th>\n                    <th>SECRET</th>\n                    <th>STATUS</th>\n                </tr>\n            </thead>\n            <tbody>\n                \n\t\t\t\t<tr>\n\t\t\t\t\t<td> /config.yaml </td>\n\t\t\t\t\t<td> 12:30 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t\t<td>  </td>\n\t\t\t\t</tr>\n\t\t\t\t<tr>\n\t\t\t\t\t<td> /server-conf.json </td>\n\t\t\t\t\t<td> 15:20 </td>\n\t\t\t\t\t<td> pass***** </td>\n\t\t\t\t\t<td>  </td>\n\t\t\t\t</tr>\n            </tbody>\n        </table>\n\t\t<div class=\"ignore-comments\">\n\t\t<b>NOTE:</b> If you'd like Frogbot to ignore the lines with the potential secrets, add a comment that includes the <b>jfrog-ignore</b> keyword above the lines with the secrets.\t\n\t\t</div>\n\t</div>\n</body>\n</html>"
 	actualContent = getSecretsEmailContent(secrets, vcsutils.GitLab, "https://github.com/owner/repo/pullrequest/1")
 	assert.Equal(t, expected, actualContent)
 }
 
+func TestGetSecretsEmailContentByValidationStatus(t *testing.T) {
+	secrets := []formats.SourceCodeRow{
+		{Location: formats.Location{File: "/inactive.yaml"}, Applicability: &formats.Applicability{Status: jasutils.Inactive.String()}},
+		{Location: formats.Location{File: "/unknown.yaml"}},
+		{Location: formats.Location{File: "/active.yaml"}, Applicability: &formats.Applicability{Status: jasutils.Active.String()}},
+	}
+	assert.True(t, hasActiveSecrets(secrets))
+	assert.False(t, hasActiveSecrets(secrets[:2]))
+
+	// The active secrets are listed first, and the inactive secrets are listed last
+	content := getSecretsEmailContent(secrets, vcsutils.GitHub, "https://github.com/owner/repo/pullrequest/1")
+	activeIndex, unknownIndex, inactiveIndex := strings.Index(content, "/active.yaml"), strings.Index(content, "/unknown.yaml"), strings.Index(content, "/inactive.yaml")
+	assert.Less(t, activeIndex, unknownIndex)
+	assert.Less(t, unknownIndex, inactiveIndex)
+	assert.Contains(t, content, "<td> Active </td>")
+	assert.Contains(t, content, "<td> Inactive </td>")
+	// The order of the given secrets is kept
+	assert.Equal(t, "/inactive.yaml", secrets[0].File)
+}
+
 func TestPrepareEmail(t *testing.T) {
 	sender := "JFrog Frogbot <frogbot@jfrog.com>"
 	subject := outputwriter.FrogbotTitlePrefix + " Potential secrets detected"
 	content := "content"
-	emailDetails := EmailDetails{EmailReceivers: []string{"receiver@jfrog.com"}}
+	receivers := []string{"receiver@jfrog.com"}
 	expectedEmailObject := &email.Email{
 		From:    sender,
-		To:      emailDetails.EmailReceivers,
+		To:      receivers,
 		Subject: subject,
 		HTML:    []byte(content),
 		Headers: textproto.MIMEHeader{},
 	}
-	actualEmailObject := prepareEmail(sender, subject, content, emailDetails)
+	actualEmailObject := prepareEmail(sender, subject, content, receivers)
 	assert.Equal(t, expectedEmailObject, actualEmailObject)
 }
 
@@ -67,3 +91,58 @@ func TestGetEmailReceiversFromCommits(t *testing.T) {
 	assert.NoError(t, err)
 	assert.ElementsMatch(t, []string{"test1@jfrog.com", "test2@jfrog.com"}, finalEmailReceiversList)
 }
+
+func TestEmailNotifierAlerts(t *testing.T) {
+	notifier := &EmailNotifier{gitProvider: vcsutils.GitLab, pullRequestLink: "https://gitlab.com/owner/repo/-/merge_requests/1"}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}, ImpactedDependencyName: "<script>", ImpactedDependencyVersion: "1.0.0"}, Cves: []formats.CveRow{{Id: "CVE-2024-0001"}}, FixedVersions: []string{"[1.0.1]", "[2.0.0]"}},
+		{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "High"}, ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}, Cves: []formats.CveRow{{Id: "CVE-2024-0002"}}},
+	}
+	// The vulnerabilities that are reported as violations too are listed once
+	alert := notifier.newCriticalVulnerabilitiesAlert(append(vulnerabilities, vulnerabilities[0]))
+	require.NotNil(t, alert)
+	assert.Equal(t, CriticalVulnerabilitiesAlert, alert.Type)
+	assert.Equal(t, outputwriter.FrogbotTitlePrefix+" Critical vulnerabilities detected", alert.Subject)
+	assert.Contains(t, alert.Content, "The following critical vulnerabilities in your <a href=\"https://gitlab.com/owner/repo/-/merge_requests/1\">merge request</a>")
+	assert.Contains(t, alert.Content, "\n                    <th>ID</th>\n                    <th>DEPENDENCY</th>\n                    <th>VERSION</th>\n                    <th>FIXED VERSIONS</th>\n")
+	assert.Contains(t, alert.Content, "\n\t\t\t\t<tr>\n\t\t\t\t\t<td> CVE-2024-0001 </td>\n\t\t\t\t\t<td> &lt;script&gt; </td>\n\t\t\t\t\t<td> 1.0.0 </td>\n\t\t\t\t\t<td> [1.0.1], [2.0.0] </td>\n\t\t\t\t</tr>")
+	assert.Equal(t, 1, strings.Count(alert.Content, "<tr>\n\t\t\t\t\t<td>"))
+	assert.NotContains(t, alert.Content, "minimist")
+	assert.Nil(t, notifier.newCriticalVulnerabilitiesAlert(vulnerabilities[1:]))
+
+	violations := []formats.LicenseViolationRow{{LicenseRow: formats.LicenseRow{LicenseKey: "GPL-3.0", ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "copyleft", ImpactedDependencyVersion: "3.1.0"}}}}
+	alert = notifier.newLicenseViolationsAlert(violations)
+	require.NotNil(t, alert)
+	assert.Equal(t, LicenseViolationsAlert, alert.Type)
+	assert.Contains(t, alert.Content, "<td> GPL-3.0 </td>\n\t\t\t\t\t<td> copyleft </td>\n\t\t\t\t\t<td> 3.1.0 </td>")
+	assert.Nil(t, notifier.newLicenseViolationsAlert(nil))
+	assert.Nil(t, notifier.newSecretsAlert(nil))
+
+	// The alerts of the types that have no receivers aren't sent
+	notifier.EmailReceivers = EmailReceivers{Critical: []string{"security@example.com"}}
+	assert.NoError(t, notifier.Send(alert))
+	assert.NoError(t, notifier.Send(nil))
+}
+
+func TestEmailReceiversYaml(t *testing.T) {
+	// A list of receivers sets the receivers of the exposed secrets alerts
+	var scan Scan
+	require.NoError(t, yaml.Unmarshal([]byte("emailReceivers:\n  - security@example.com\n"), &scan))
+	assert.Equal(t, EmailReceivers{Secrets: []string{"security@example.com"}}, scan.EmailReceivers)
+	content, err := yaml.Marshal(scan.EmailReceivers)
+	require.NoError(t, err)
+	assert.Equal(t, "- security@example.com\n", string(content))
+
+	scan = Scan{}
+	require.NoError(t, yaml.Unmarshal([]byte("emailReceivers:\n  secrets: [security@example.com]\n  critical: [appsec@example.com, oncall@example.com]\n  licenses: [legal@example.com]\n"), &scan))
+	expected := EmailReceivers{Secrets: []string{"security@example.com"}, Critical: []string{"appsec@example.com", "oncall@example.com"}, Licenses: []string{"legal@example.com"}}
+	assert.Equal(t, expected, scan.EmailReceivers)
+	assert.Equal(t, []string{"legal@example.com"}, scan.EmailReceivers.Get(LicenseViolationsAlert))
+	content, err = yaml.Marshal(scan.EmailReceivers)
+	require.NoError(t, err)
+	var copied EmailReceivers
+	require.NoError(t, yaml.Unmarshal(content, &copied))
+	assert.Equal(t, expected, copied)
+
+	assert.Error(t, yaml.Unmarshal([]byte("emailReceivers: security@example.com\n"), &scan))
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The patterns of the vendored and generated files, whose Secrets, IaC and SAST findings aren't reported by default
+var DefaultExcludePatterns = []string{"vendor/", "dist/", "node_modules/", "bower_components/", "*.min.js", "*.min.css", "*.bundle.js"}
+
+// The attributes of .gitattributes that mark vendored and generated files
+var excludedGitAttributes = []string{"linguist-vendored", "linguist-vendored=true", "linguist-generated", "linguist-generated=true"}
+
+// Returns the exclude patterns of the repository: the configured patterns, the files that .gitattributes marks as vendored or generated,
+// and the files that .gitignore ignores, which exist in the working directory when the installation of the dependencies creates them.
+func GetExcludePatterns(repositoryDir string, configuredPatterns []string) []string {
+	patterns := slices.Clone(configuredPatterns)
+	gitAttributesPatterns, err := readGitAttributesExcludePatterns(filepath.Join(repositoryDir, ".gitattributes"))
+	if err != nil {
+		log.Warn("Failed to read the vendored and generated files of .gitattributes:", err.Error())
+	}
+	gitIgnorePatterns, err := readGitIgnorePatterns(filepath.Join(repositoryDir, ".gitignore"))
+	if err != nil {
+		log.Warn("Failed to read the ignored files of .gitignore:", err.Error())
+	}
+	return append(append(patterns, gitAttributesPatterns...), gitIgnorePatterns...)
+}
+
+func readGitAttributesExcludePatterns(gitAttributesPath string) (patterns []string, err error) {
+	err = readGitPatternsFile(gitAttributesPath, func(line string) {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && slices.ContainsFunc(fields[1:], func(attribute string) bool { return slices.Contains(excludedGitAttributes, attribute) }) {
+			patterns = append(patterns, fields[0])
+		}
+	})
+	return
+}
+
+// The negated patterns are skipped, so the files they re-include stay excluded
+func readGitIgnorePatterns(gitIgnorePath string) (patterns []string, err error) {
+	err = readGitPatternsFile(gitIgnorePath, func(line string) {
+		if !strings.HasPrefix(line, "!") {
+			patterns = append(patterns, line)
+		}
+	})
+	return
+}
+
+// Reads the lines of a file of the Git patterns syntax, without the empty lines and the comments. A missing file has no patterns.
+func readGitPatternsFile(filePath string, handleLine func(line string)) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			handleLine(line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %s", filePath, err.Error())
+	}
+	return nil
+}
+
+// Returns true if the file matches one of the exclude patterns, which follow the .gitignore syntax:
+// A pattern with a slash in its beginning or middle is relative to the root of the repository, other patterns match the names of files and directories in any depth,
+// and a pattern that ends with a slash matches directories only. The path of the file must be relative to the root of the repository.
+func MatchesExcludePatterns(file string, patterns []string) bool {
+	fileParts := strings.Split(strings.TrimPrefix(filepath.ToSlash(file), "/"), "/")
+	return slices.ContainsFunc(patterns, func(pattern string) bool { return matchesExcludePattern(pattern, fileParts) })
+}
+
+func matchesExcludePattern(pattern string, fileParts []string) bool {
+	directoriesOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimPrefix(strings.TrimSuffix(pattern, "/"), "**/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	for i := range fileParts {
+		// The last part is the file itself
+		if directoriesOnly && i == len(fileParts)-1 {
+			break
+		}
+		candidate := fileParts[i]
+		if anchored {
+			candidate = strings.Join(fileParts[:i+1], "/")
+		}
+		if matched, err := path.Match(pattern, candidate); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the exclude patterns that the scanners accept, which are the names of the excluded directories.
+// The scanners skip these directories in any depth, while the other patterns are applied to the findings of the scanners.
+func toScannersExclusions(patterns []string) (exclusions []string) {
+	for _, pattern := range patterns {
+		if !strings.HasSuffix(pattern, "/") {
+			continue
+		}
+		if name := strings.TrimPrefix(strings.TrimSuffix(pattern, "/"), "**/"); name != "" && !strings.Contains(name, "/") {
+			exclusions = append(exclusions, name)
+		}
+	}
+	return
+}
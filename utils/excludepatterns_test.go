@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesExcludePatterns(t *testing.T) {
+	testCases := []struct {
+		file     string
+		patterns []string
+		expected bool
+	}{
+		{file: "vendor/github.com/lib/config.go", patterns: DefaultExcludePatterns, expected: true},
+		{file: "services/api/node_modules/lodash/index.js", patterns: DefaultExcludePatterns, expected: true},
+		{file: "web/static/app.min.js", patterns: DefaultExcludePatterns, expected: true},
+		{file: "/web/static/app.js", patterns: DefaultExcludePatterns, expected: false},
+		// The patterns that end with a slash match directories only
+		{file: "src/dist", patterns: []string{"dist/"}, expected: false},
+		{file: "src/dist/main.js", patterns: []string{"dist/"}, expected: true},
+		// The patterns with a slash are relative to the root of the repository
+		{file: "src/generated/client.go", patterns: []string{"/src/generated"}, expected: true},
+		{file: "lib/src/generated/client.go", patterns: []string{"src/generated"}, expected: false},
+		{file: "lib/src/generated/client.go", patterns: []string{"**/generated"}, expected: true},
+		{file: "docs/api/index.html", patterns: []string{"docs/**"}, expected: true},
+		{file: "docs/api/index.html", patterns: nil, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MatchesExcludePatterns(tc.file, tc.patterns))
+		})
+	}
+}
+
+func TestGetExcludePatterns(t *testing.T) {
+	repositoryDir := t.TempDir()
+	// Without .gitattributes and .gitignore, the configured patterns are used
+	assert.Equal(t, []string{"third_party/"}, GetExcludePatterns(repositoryDir, []string{"third_party/"}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(repositoryDir, ".gitattributes"), []byte("# Vendored and generated files\n*.go text eol=lf\nassets/lib/** linguist-vendored\napi/*.pb.go linguist-generated=true\ndocs/** -linguist-vendored\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repositoryDir, ".gitignore"), []byte("# Build output\nbuild/\n\n*.log\n!important.log\n"), 0644))
+	patterns := GetExcludePatterns(repositoryDir, []string{"third_party/"})
+	assert.Equal(t, []string{"third_party/", "assets/lib/**", "api/*.pb.go", "build/", "*.log"}, patterns)
+	assert.True(t, MatchesExcludePatterns("api/service.pb.go", patterns))
+	assert.False(t, MatchesExcludePatterns("api/service.go", patterns))
+
+	// The scanners skip the excluded directories by their names
+	assert.Equal(t, []string{"third_party", "build"}, toScannersExclusions(patterns))
+	assert.Equal(t, []string{"vendor", "dist", "node_modules", "bower_components"}, toScannersExclusions(DefaultExcludePatterns))
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/jfrog/frogbot/v2/utils/exploitability"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+)
+
+// The provider is shared by all the scanned repositories, so each CVE is requested once per run
+var (
+	exploitabilityProvider      *exploitability.Provider
+	exploitabilityProviderMutex sync.Mutex
+)
+
+func getExploitabilityProvider() *exploitability.Provider {
+	exploitabilityProviderMutex.Lock()
+	defer exploitabilityProviderMutex.Unlock()
+	if exploitabilityProvider == nil {
+		exploitabilityProvider = exploitability.NewProvider()
+	}
+	return exploitabilityProvider
+}
+
+// Returns the EPSS scores and the KEV membership of the CVEs of the SCA vulnerabilities and violations
+func GetIssuesExploitability(issuesCollection *issues.ScansIssuesCollection) map[string]exploitability.Info {
+	cves := datastructures.MakeSet[string]()
+	for _, rows := range [][]formats.VulnerabilityOrViolationRow{issuesCollection.ScaVulnerabilities, issuesCollection.ScaViolations} {
+		for _, row := range rows {
+			for _, cve := range row.Cves {
+				if cve.Id != "" {
+					cves.Add(cve.Id)
+				}
+			}
+		}
+	}
+	return getExploitabilityProvider().GetExploitability(cves.ToSlice())
+}
+
+// Sets the exploitability of the most exploitable CVE of each vulnerability, and returns the exploitability of all the CVEs
+func SetVulnerabilitiesExploitability(vulnerabilities map[string]*VulnerabilityDetails) map[string]exploitability.Info {
+	cves := datastructures.MakeSet[string]()
+	for _, vulnerability := range vulnerabilities {
+		for _, cve := range vulnerability.Cves {
+			cves.Add(cve)
+		}
+	}
+	exploitabilityInfo := getExploitabilityProvider().GetExploitability(cves.ToSlice())
+	for _, vulnerability := range vulnerabilities {
+		vulnerability.SetExploitability(exploitabilityInfo)
+	}
+	return exploitabilityInfo
+}
@@ -0,0 +1,290 @@
+package exploitability
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	DefaultEpssUrl = "https://api.first.org/data/v1/epss"
+	DefaultKevUrl  = "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json"
+	// The EPSS scores and the KEV catalog are updated daily
+	cacheTtl      = 24 * time.Hour
+	cacheFileName = "exploitability-cache.json"
+	// The maximal number of CVEs of an EPSS API request
+	epssBatchSize = 100
+)
+
+// Info describes how likely a CVE is to be exploited
+type Info struct {
+	// The probability that the CVE will be exploited in the next 30 days, between 0 and 1, by the Exploit Prediction Scoring System (EPSS)
+	EpssScore float64
+	// The percentile of the EPSS score among the scores of all the CVEs
+	EpssPercentile float64
+	// False if the CVE has no EPSS score
+	HasEpss bool
+	// True if the CVE is in the CISA Known Exploited Vulnerabilities (KEV) catalog
+	KnownExploited bool
+}
+
+// Returns the exploitability of the most exploitable CVE of the given CVEs, or empty info if none of them has exploitability data
+func GetMostExploitable(infos map[string]Info, cves ...string) (mostExploitable Info) {
+	for _, cve := range cves {
+		info, exists := infos[cve]
+		if !exists {
+			continue
+		}
+		mostExploitable.KnownExploited = mostExploitable.KnownExploited || info.KnownExploited
+		if info.HasEpss && (!mostExploitable.HasEpss || info.EpssScore > mostExploitable.EpssScore) {
+			mostExploitable.EpssScore, mostExploitable.EpssPercentile, mostExploitable.HasEpss = info.EpssScore, info.EpssPercentile, true
+		}
+	}
+	return
+}
+
+// Provider gets the EPSS scores and the CISA KEV catalog membership of CVEs.
+// The data is cached in memory, and in a local file between runs, so each CVE is requested at most once a day.
+type Provider struct {
+	EpssUrl string
+	KevUrl  string
+	// The directory of the local cache file, the data isn't cached between runs if empty
+	CacheDir string
+	cache    *cache
+	mutex    sync.Mutex
+}
+
+type cache struct {
+	Kev  *cachedKev            `json:"kev,omitempty"`
+	Epss map[string]cachedEpss `json:"epss,omitempty"`
+}
+
+type cachedKev struct {
+	UpdateTime time.Time `json:"updateTime"`
+	Cves       []string  `json:"cves"`
+	cves       map[string]bool
+}
+
+type cachedEpss struct {
+	UpdateTime time.Time `json:"updateTime"`
+	Score      float64   `json:"score"`
+	Percentile float64   `json:"percentile"`
+	// False if the CVE has no EPSS score
+	Found bool `json:"found"`
+}
+
+func NewProvider() *Provider {
+	provider := &Provider{EpssUrl: DefaultEpssUrl, KevUrl: DefaultKevUrl}
+	if userCacheDir, err := os.UserCacheDir(); err == nil {
+		provider.CacheDir = filepath.Join(userCacheDir, "frogbot")
+	} else {
+		log.Debug("The exploitability data isn't cached between runs, since the user cache directory couldn't be found:", err.Error())
+	}
+	return provider
+}
+
+// Returns the exploitability of the given CVEs.
+// Failures to get the data are logged as warnings, and the CVEs without data are omitted.
+func (p *Provider) GetExploitability(cves []string) map[string]Info {
+	infos := make(map[string]Info)
+	if len(cves) == 0 {
+		return infos
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.loadCache()
+	kevErr := p.updateKevIfNeeded()
+	if kevErr != nil {
+		log.Warn("Couldn't get the CISA Known Exploited Vulnerabilities catalog:", kevErr.Error())
+	}
+	epssErr := p.updateEpssIfNeeded(cves)
+	if epssErr != nil {
+		log.Warn("Couldn't get the EPSS scores of the CVEs:", epssErr.Error())
+	}
+	for _, cve := range cves {
+		info := Info{}
+		exists := false
+		if p.cache.Kev != nil && p.cache.Kev.cves[cve] {
+			info.KnownExploited, exists = true, true
+		}
+		if epss, found := p.cache.Epss[cve]; found && epss.Found {
+			info.EpssScore, info.EpssPercentile, info.HasEpss, exists = epss.Score, epss.Percentile, true, true
+		}
+		if exists || (kevErr == nil && epssErr == nil) {
+			infos[cve] = info
+		}
+	}
+	p.saveCache()
+	return infos
+}
+
+func (p *Provider) updateKevIfNeeded() error {
+	if p.cache.Kev != nil && time.Since(p.cache.Kev.UpdateTime) < cacheTtl {
+		return nil
+	}
+	var catalog struct {
+		Vulnerabilities []struct {
+			CveId string `json:"cveID"`
+		} `json:"vulnerabilities"`
+	}
+	if err := getJson(p.KevUrl, &catalog); err != nil {
+		return err
+	}
+	kev := &cachedKev{UpdateTime: time.Now()}
+	for _, vulnerability := range catalog.Vulnerabilities {
+		kev.Cves = append(kev.Cves, vulnerability.CveId)
+	}
+	kev.setCves()
+	p.cache.Kev = kev
+	return nil
+}
+
+// Requests the EPSS scores of the CVEs that aren't cached, or that their cached score is outdated
+func (p *Provider) updateEpssIfNeeded(cves []string) error {
+	var missingCves []string
+	for _, cve := range cves {
+		if epss, exists := p.cache.Epss[cve]; !exists || time.Since(epss.UpdateTime) >= cacheTtl {
+			missingCves = append(missingCves, cve)
+		}
+	}
+	for start := 0; start < len(missingCves); start += epssBatchSize {
+		batch := missingCves[start:min(start+epssBatchSize, len(missingCves))]
+		if err := p.updateEpss(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Provider) updateEpss(cves []string) error {
+	var response struct {
+		Data []struct {
+			Cve        string `json:"cve"`
+			Epss       string `json:"epss"`
+			Percentile string `json:"percentile"`
+		} `json:"data"`
+	}
+	if err := getJson(fmt.Sprintf("%s?cve=%s", p.EpssUrl, url.QueryEscape(strings.Join(cves, ","))), &response); err != nil {
+		return err
+	}
+	updateTime := time.Now()
+	// CVEs without a score are cached as well, so they aren't requested again
+	for _, cve := range cves {
+		p.cache.Epss[cve] = cachedEpss{UpdateTime: updateTime}
+	}
+	for _, data := range response.Data {
+		score, err := strconv.ParseFloat(data.Epss, 64)
+		if err != nil {
+			return fmt.Errorf("invalid EPSS score '%s' of %s", data.Epss, data.Cve)
+		}
+		percentile, err := strconv.ParseFloat(data.Percentile, 64)
+		if err != nil {
+			return fmt.Errorf("invalid EPSS percentile '%s' of %s", data.Percentile, data.Cve)
+		}
+		p.cache.Epss[data.Cve] = cachedEpss{UpdateTime: updateTime, Score: score, Percentile: percentile, Found: true}
+	}
+	return nil
+}
+
+func (p *Provider) getCacheFilePath() string {
+	return filepath.Join(p.CacheDir, cacheFileName)
+}
+
+// Loads the local cache file once. A missing or invalid cache file is ignored.
+func (p *Provider) loadCache() {
+	if p.cache != nil {
+		return
+	}
+	p.cache = &cache{Epss: make(map[string]cachedEpss)}
+	if p.CacheDir == "" {
+		return
+	}
+	content, err := os.ReadFile(p.getCacheFilePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debug("Couldn't read the exploitability cache file:", err.Error())
+		}
+		return
+	}
+	fileCache := &cache{}
+	if err = json.Unmarshal(content, fileCache); err != nil {
+		log.Debug("Ignoring the invalid exploitability cache file:", err.Error())
+		return
+	}
+	if fileCache.Epss == nil {
+		fileCache.Epss = make(map[string]cachedEpss)
+	}
+	if fileCache.Kev != nil {
+		fileCache.Kev.setCves()
+	}
+	p.cache = fileCache
+}
+
+// Writes the cache to a temporary file that replaces the cache file, so concurrent runs don't read a partially written file
+func (p *Provider) saveCache() {
+	if p.CacheDir == "" {
+		return
+	}
+	if err := p.writeCacheFile(); err != nil {
+		log.Debug("Couldn't write the exploitability cache file:", err.Error())
+	}
+}
+
+func (p *Provider) writeCacheFile() (err error) {
+	content, err := json.Marshal(p.cache)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(p.CacheDir, 0700); err != nil {
+		return
+	}
+	tempFile, err := os.CreateTemp(p.CacheDir, cacheFileName+".*")
+	if err != nil {
+		return
+	}
+	if _, err = tempFile.Write(content); err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+		return
+	}
+	if err = tempFile.Close(); err != nil {
+		_ = os.Remove(tempFile.Name())
+		return
+	}
+	return os.Rename(tempFile.Name(), p.getCacheFilePath())
+}
+
+func (ck *cachedKev) setCves() {
+	ck.cves = make(map[string]bool, len(ck.Cves))
+	for _, cve := range ck.Cves {
+		ck.cves[cve] = true
+	}
+}
+
+// Sends an anonymous GET request and decodes the JSON response into the target
+func getJson(resourceUrl string, target any) error {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return err
+	}
+	log.Debug("Sending HTTP GET request to:", resourceUrl)
+	resp, body, _, err := client.SendGet(resourceUrl, true, httputils.HttpClientDetails{}, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status %s", resourceUrl, resp.Status)
+	}
+	return json.Unmarshal(body, target)
+}
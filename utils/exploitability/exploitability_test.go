@@ -0,0 +1,111 @@
+package exploitability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	kevResponse  = `{"vulnerabilities":[{"cveID":"CVE-2021-44228"},{"cveID":"CVE-2022-22965"}]}`
+	epssResponse = `{"data":[{"cve":"CVE-2021-44228","epss":"0.975660000","percentile":"0.999990000"},{"cve":"CVE-2021-44906","epss":"0.012","percentile":"0.8"}]}`
+)
+
+func newTestServers(t *testing.T, epssRequests, kevRequests *int) (epssUrl, kevUrl string) {
+	epssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*epssRequests++
+		_, err := w.Write([]byte(epssResponse))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(epssServer.Close)
+	kevServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*kevRequests++
+		_, err := w.Write([]byte(kevResponse))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(kevServer.Close)
+	return epssServer.URL, kevServer.URL
+}
+
+func TestGetExploitability(t *testing.T) {
+	var epssRequests, kevRequests int
+	epssUrl, kevUrl := newTestServers(t, &epssRequests, &kevRequests)
+	cacheDir := t.TempDir()
+	cves := []string{"CVE-2021-44228", "CVE-2021-44906", "CVE-2023-0001"}
+	expected := map[string]Info{
+		"CVE-2021-44228": {EpssScore: 0.97566, EpssPercentile: 0.99999, HasEpss: true, KnownExploited: true},
+		"CVE-2021-44906": {EpssScore: 0.012, EpssPercentile: 0.8, HasEpss: true},
+		"CVE-2023-0001":  {},
+	}
+
+	provider := &Provider{EpssUrl: epssUrl, KevUrl: kevUrl, CacheDir: cacheDir}
+	assert.Empty(t, provider.GetExploitability(nil))
+	assert.Equal(t, expected, provider.GetExploitability(cves))
+	assert.Equal(t, 1, epssRequests)
+	assert.Equal(t, 1, kevRequests)
+	assert.FileExists(t, filepath.Join(cacheDir, cacheFileName))
+
+	// The cached data is used by the next runs
+	provider = &Provider{EpssUrl: epssUrl, KevUrl: kevUrl, CacheDir: cacheDir}
+	assert.Equal(t, expected, provider.GetExploitability(cves))
+	assert.Equal(t, 1, epssRequests)
+	assert.Equal(t, 1, kevRequests)
+
+	// Outdated data is requested again
+	provider.cache.Kev.UpdateTime = time.Now().Add(-cacheTtl)
+	assert.Equal(t, expected, provider.GetExploitability(cves))
+	assert.Equal(t, 1, epssRequests)
+	assert.Equal(t, 2, kevRequests)
+}
+
+func TestGetExploitabilityFailure(t *testing.T) {
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failingServer.Close()
+	var epssRequests, kevRequests int
+	_, kevUrl := newTestServers(t, &epssRequests, &kevRequests)
+
+	// CVEs without data are omitted, since their data may be missing due to the failure
+	provider := &Provider{EpssUrl: failingServer.URL, KevUrl: kevUrl}
+	assert.Equal(t, map[string]Info{"CVE-2021-44228": {KnownExploited: true}}, provider.GetExploitability([]string{"CVE-2021-44228", "CVE-2021-44906"}))
+}
+
+func TestLoadInvalidCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, cacheFileName), []byte("invalid"), 0600))
+	provider := &Provider{CacheDir: cacheDir}
+	provider.loadCache()
+	require.NotNil(t, provider.cache)
+	assert.Nil(t, provider.cache.Kev)
+	assert.Empty(t, provider.cache.Epss)
+}
+
+func TestGetMostExploitable(t *testing.T) {
+	infos := map[string]Info{
+		"CVE-1": {EpssScore: 0.1, EpssPercentile: 0.5, HasEpss: true, KnownExploited: true},
+		"CVE-2": {EpssScore: 0.7, EpssPercentile: 0.9, HasEpss: true},
+		"CVE-3": {},
+	}
+	testCases := []struct {
+		name     string
+		cves     []string
+		expected Info
+	}{
+		{name: "No CVEs", expected: Info{}},
+		{name: "Unknown CVE", cves: []string{"CVE-4"}, expected: Info{}},
+		{name: "Single CVE", cves: []string{"CVE-2"}, expected: infos["CVE-2"]},
+		{name: "Highest score and known exploited", cves: []string{"CVE-1", "CVE-2", "CVE-3"}, expected: Info{EpssScore: 0.7, EpssPercentile: 0.9, HasEpss: true, KnownExploited: true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, GetMostExploitable(infos, tc.cves...))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package externalsarif
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-security/utils/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+)
+
+// The property of the rules that scores their severity from 0 to 10, in the GitHub code scanning convention that Trivy and other scanners follow
+const securitySeverityProperty = "security-severity"
+
+// Reads the findings of third-party scanners, such as Checkov, from the SARIF files they produced earlier in the CI run,
+// so one Frogbot comment covers the findings of all the tools. The paths of the findings are made relative to the repository directory.
+// The findings that passed or were suppressed aren't read. Failing to read a file doesn't prevent reading the others.
+func Load(repositoryDir string, sarifPaths ...string) (externalIssues []issues.ExternalScannerIssue, err error) {
+	for _, sarifPath := range sarifPaths {
+		runs, readErr := sarifutils.ReadScanRunsFromFile(sarifPath)
+		if readErr != nil {
+			err = errors.Join(err, readErr)
+			continue
+		}
+		for _, run := range runs {
+			externalIssues = append(externalIssues, getRunIssues(run, repositoryDir)...)
+		}
+	}
+	return
+}
+
+func getRunIssues(run *sarif.Run, repositoryDir string) (externalIssues []issues.ExternalScannerIssue) {
+	scanner := sarifutils.GetRunToolName(run)
+	if scanner == "" {
+		scanner = "Unknown"
+	}
+	for _, result := range run.Results {
+		if !sarifutils.IsResultKindNotPass(result) || len(result.Suppressions) > 0 {
+			continue
+		}
+		externalIssue := issues.ExternalScannerIssue{
+			Scanner:  scanner,
+			RuleId:   sarifutils.GetResultRuleId(result),
+			Severity: getSeverity(run, result),
+			// The cells of the comment tables are single lines
+			Message: strings.Join(strings.Fields(sarifutils.GetResultMsgText(result)), " "),
+		}
+		if len(result.Locations) > 0 {
+			externalIssue.File = sarifutils.ExtractRelativePath(sarifutils.GetLocationFileName(result.Locations[0]), repositoryDir)
+			externalIssue.Line = sarifutils.GetLocationStartLine(result.Locations[0])
+		}
+		externalIssues = append(externalIssues, externalIssue)
+	}
+	return
+}
+
+// Returns the severity of the rule of the result if it's scored, or the severity of the level of the result otherwise
+func getSeverity(run *sarif.Run, result *sarif.Result) string {
+	rule := sarifutils.GetRuleById(run, sarifutils.GetResultRuleId(result))
+	if rule != nil && rule.Properties != nil {
+		if score, err := strconv.ParseFloat(strings.TrimSpace(toString(rule.Properties[securitySeverityProperty])), 64); err == nil {
+			return getSeverityByScore(score).String()
+		}
+	}
+	level := sarifutils.GetResultLevel(result)
+	if level == "" && rule != nil && rule.DefaultConfiguration != nil {
+		level = rule.DefaultConfiguration.Level
+	}
+	severity, _ := severityutils.ParseSeverity(level, true)
+	return severity.String()
+}
+
+// The scores are written as strings or as numbers
+func toString(value interface{}) string {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// The ranges of the scores follow CVSS
+func getSeverityByScore(score float64) severityutils.Severity {
+	switch {
+	case score >= 9:
+		return severityutils.Critical
+	case score >= 7:
+		return severityutils.High
+	case score >= 4:
+		return severityutils.Medium
+	default:
+		return severityutils.Low
+	}
+}
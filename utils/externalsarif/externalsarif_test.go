@@ -0,0 +1,36 @@
+package externalsarif
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	repositoryDir := t.TempDir()
+	content, err := os.ReadFile(filepath.Join("..", "..", "testdata", "externalsarif", "results.sarif"))
+	require.NoError(t, err)
+	sarifPath := filepath.Join(repositoryDir, "results.sarif")
+	require.NoError(t, os.WriteFile(sarifPath, []byte(strings.ReplaceAll(string(content), "REPOSITORY_DIR", filepath.ToSlash(repositoryDir))), 0644))
+
+	externalIssues, err := Load(repositoryDir, sarifPath)
+	require.NoError(t, err)
+	// The passed and the suppressed results aren't read
+	assert.Equal(t, []issues.ExternalScannerIssue{
+		{Scanner: "Checkov", File: "terraform/main.tf", Line: 12, RuleId: "CKV_AWS_20", Severity: "High", Message: "S3 Bucket has an ACL defined which allows public READ access."},
+		// The level of the rule applies to its results without a level
+		{Scanner: "Checkov", File: "terraform/main.tf", Line: 12, RuleId: "CKV_AWS_18", Severity: "Low", Message: "Ensure the S3 bucket has access logging enabled"},
+		// The security severity score of the rule takes precedence over the level of the result
+		{Scanner: "Trivy", File: "docker/Dockerfile", Line: 1, RuleId: "AVD-DS-0002", Severity: "Critical", Message: "Specify at least 1 USER command in Dockerfile with non-root user as argument"},
+	}, externalIssues)
+
+	// The files that can't be read are reported, while the others are read
+	externalIssues, err = Load(repositoryDir, filepath.Join(repositoryDir, "missing.sarif"), sarifPath)
+	assert.ErrorContains(t, err, "missing.sarif")
+	assert.Len(t, externalIssues, 3)
+}
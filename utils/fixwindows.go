@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+var weekdaysByName = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// FixWindow is a weekly time window in UTC, in which new fix pull requests may be opened.
+// A window whose end precedes its start ends on the next day, so 'Fri 22:00-02:00' ends on Saturday at 02:00.
+type FixWindow struct {
+	days [7]bool
+	// The minutes from midnight
+	start, end int
+}
+
+// ParseFixWindows parses windows in the '<days> <HH:MM>-<HH:MM>' format, such as 'Mon-Fri 09:00-17:00' or '* 22:00-06:00'.
+// The days are '*' for all the days, or comma separated days and ranges of days, such as 'Mon,Wed,Fri-Sun'.
+func ParseFixWindows(windows []string) ([]FixWindow, error) {
+	var fixWindows []FixWindow
+	for _, window := range windows {
+		if strings.TrimSpace(window) == "" {
+			continue
+		}
+		fixWindow, err := parseFixWindow(window)
+		if err != nil {
+			return nil, fmt.Errorf("the fix pull requests window '%s' is invalid. Expected the '<days> <HH:MM>-<HH:MM>' format, such as 'Mon-Fri 09:00-17:00': %s", window, err.Error())
+		}
+		fixWindows = append(fixWindows, fixWindow)
+	}
+	return fixWindows, nil
+}
+
+func parseFixWindow(window string) (fixWindow FixWindow, err error) {
+	fields := strings.Fields(window)
+	if len(fields) != 2 {
+		return fixWindow, fmt.Errorf("expected days and hours, separated by a space")
+	}
+	if fixWindow.days, err = parseWindowDays(fields[0]); err != nil {
+		return
+	}
+	start, end, found := strings.Cut(fields[1], "-")
+	if !found {
+		return fixWindow, fmt.Errorf("expected a range of hours, such as 09:00-17:00")
+	}
+	if fixWindow.start, err = parseWindowTime(start); err != nil {
+		return
+	}
+	if fixWindow.end, err = parseWindowTime(end); err != nil {
+		return
+	}
+	if fixWindow.start == fixWindow.end {
+		err = fmt.Errorf("the window starts and ends at %s", start)
+	}
+	return
+}
+
+func parseWindowDays(days string) (parsedDays [7]bool, err error) {
+	if days == "*" {
+		return [7]bool{true, true, true, true, true, true, true}, nil
+	}
+	for _, daysRange := range strings.Split(days, ",") {
+		first, last, isRange := strings.Cut(daysRange, "-")
+		if !isRange {
+			last = first
+		}
+		firstDay, firstExists := weekdaysByName[strings.ToLower(first)]
+		lastDay, lastExists := weekdaysByName[strings.ToLower(last)]
+		if !firstExists || !lastExists {
+			return parsedDays, fmt.Errorf("unknown day in '%s'. Expected the days Mon, Tue, Wed, Thu, Fri, Sat and Sun", daysRange)
+		}
+		// A range may wrap the end of the week, such as Fri-Mon
+		for day := firstDay; ; day = (day + 1) % 7 {
+			parsedDays[day] = true
+			if day == lastDay {
+				break
+			}
+		}
+	}
+	return
+}
+
+// Returns the minutes from midnight of an HH:MM time. 24:00 is accepted as the end of the day.
+func parseWindowTime(value string) (int, error) {
+	if value == "24:00" {
+		return minutesPerDay, nil
+	}
+	parsedTime, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("the time '%s' isn't in the HH:MM format", value)
+	}
+	return parsedTime.Hour()*60 + parsedTime.Minute(), nil
+}
+
+// Contains returns true if the time is in the window
+func (fw FixWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	minutes := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	if fw.start < fw.end {
+		return fw.days[day] && minutes >= fw.start && minutes < fw.end
+	}
+	previousDay := (day + 6) % 7
+	return (fw.days[day] && minutes >= fw.start) || (fw.days[previousDay] && minutes < fw.end)
+}
+
+// IsInFixWindows returns true if the time is in one of the windows, or if no windows are configured
+func IsInFixWindows(fixWindows []FixWindow, t time.Time) bool {
+	if len(fixWindows) == 0 {
+		return true
+	}
+	for _, fixWindow := range fixWindows {
+		if fixWindow.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInFixWindows(t *testing.T) {
+	fixWindows, err := ParseFixWindows([]string{"Mon-Fri 09:00-17:00", "fri,sat 22:00-02:00", ""})
+	require.NoError(t, err)
+	require.Len(t, fixWindows, 2)
+	testCases := []struct {
+		name     string
+		time     time.Time
+		expected bool
+	}{
+		// 2024-01-01 is a Monday
+		{name: "Monday morning", time: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), expected: true},
+		{name: "Monday evening", time: time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), expected: false},
+		{name: "Monday in another time zone", time: time.Date(2024, 1, 1, 11, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)), expected: true},
+		{name: "Friday night", time: time.Date(2024, 1, 5, 23, 30, 0, 0, time.UTC), expected: true},
+		{name: "Saturday after midnight", time: time.Date(2024, 1, 6, 1, 59, 0, 0, time.UTC), expected: true},
+		{name: "Saturday noon", time: time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC), expected: false},
+		{name: "Sunday after midnight", time: time.Date(2024, 1, 7, 1, 0, 0, 0, time.UTC), expected: true},
+		{name: "Monday after midnight", time: time.Date(2024, 1, 8, 1, 0, 0, 0, time.UTC), expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsInFixWindows(fixWindows, tc.time))
+		})
+	}
+
+	// Without windows, the fix pull requests are opened at any time
+	assert.True(t, IsInFixWindows(nil, time.Now()))
+
+	// A range of days may wrap the end of the week
+	fixWindows, err = ParseFixWindows([]string{"Sat-Mon 00:00-24:00"})
+	require.NoError(t, err)
+	assert.True(t, IsInFixWindows(fixWindows, time.Date(2024, 1, 7, 23, 59, 0, 0, time.UTC)))
+	assert.False(t, IsInFixWindows(fixWindows, time.Date(2024, 1, 9, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestParseFixWindowsErrors(t *testing.T) {
+	for _, window := range []string{"Mon-Fri", "Mon-Fri 09:00", "Funday 09:00-17:00", "* 9am-5pm", "* 09:00-09:00", "* 09:00-25:00"} {
+		_, err := ParseFixWindows([]string{window})
+		assert.ErrorContains(t, err, window)
+	}
+}
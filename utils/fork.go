@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Returns true if the source branch of the pull request is in a fork of the repository
+func IsForkPullRequest(pullRequest vcsclient.PullRequestInfo) bool {
+	source, target := pullRequest.Source, pullRequest.Target
+	if source.Owner == "" || source.Repository == "" {
+		return false
+	}
+	return !strings.EqualFold(source.Owner, target.Owner) || !strings.EqualFold(source.Repository, target.Repository)
+}
+
+// Downloads the source branch of a pull request from its fork. Frogbot only reads from forks, and never pushes to them.
+// The token of the repository may have no access to the fork, so a public fork is downloaded anonymously when the download with the token fails.
+func DownloadForkToTempDir(client vcsclient.VcsClient, gitParams *Git) (wd string, cleanup func() error, err error) {
+	source := gitParams.PullRequestDetails.Source
+	log.Info(fmt.Sprintf("Downloading the source branch of the pull request from the fork <%s/%s>", source.Owner, source.Repository))
+	if wd, cleanup, err = downloadOrCleanup(client, gitParams); err == nil {
+		return
+	}
+	log.Debug(fmt.Sprintf("Failed to download the fork <%s/%s> with the token of the repository, downloading it anonymously. Error: %s", source.Owner, source.Repository, err.Error()))
+	anonymousClient, e := vcsclient.NewClientBuilder(gitParams.GitProvider).
+		ApiEndpoint(strings.TrimSuffix(gitParams.APIEndpoint, "/")).
+		Project(gitParams.Project).
+		Logger(log.GetLogger()).
+		Build()
+	if e != nil {
+		return "", nil, errors.Join(err, e)
+	}
+	if wd, cleanup, e = downloadOrCleanup(anonymousClient, gitParams); e != nil {
+		return "", nil, errors.Join(err, e)
+	}
+	return wd, cleanup, nil
+}
+
+// Removes the temp directory of a failed download
+func downloadOrCleanup(client vcsclient.VcsClient, gitParams *Git) (wd string, cleanup func() error, err error) {
+	source := gitParams.PullRequestDetails.Source
+	if wd, cleanup, err = DownloadRepoToTempDir(client, source.Owner, source.Repository, source.Name, gitParams); err != nil && cleanup != nil {
+		err = errors.Join(err, cleanup())
+	}
+	return
+}
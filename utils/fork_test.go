@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsForkPullRequest(t *testing.T) {
+	target := vcsclient.BranchInfo{Name: "main", Owner: "jfrog", Repository: "frogbot"}
+	testCases := []struct {
+		name     string
+		source   vcsclient.BranchInfo
+		expected bool
+	}{
+		{name: "Same repository", source: vcsclient.BranchInfo{Name: "feature", Owner: "jfrog", Repository: "frogbot"}},
+		{name: "Different case", source: vcsclient.BranchInfo{Name: "feature", Owner: "JFrog", Repository: "Frogbot"}},
+		{name: "Unknown source repository", source: vcsclient.BranchInfo{Name: "feature"}},
+		{name: "Fork of another owner", source: vcsclient.BranchInfo{Name: "feature", Owner: "octocat", Repository: "frogbot"}, expected: true},
+		{name: "Fork with another name", source: vcsclient.BranchInfo{Name: "feature", Owner: "jfrog", Repository: "frogbot-fork"}, expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsForkPullRequest(vcsclient.PullRequestInfo{Source: tc.source, Target: target}))
+		})
+	}
+}
+
+func TestDownloadForkToTempDir(t *testing.T) {
+	gitParams := &Git{GitProvider: vcsutils.GitHub, PullRequestDetails: vcsclient.PullRequestInfo{
+		Source: vcsclient.BranchInfo{Name: "feature", Owner: "octocat", Repository: "frogbot"},
+		Target: vcsclient.BranchInfo{Name: "main", Owner: "jfrog", Repository: "frogbot"},
+	}}
+
+	// The fork is downloaded with the token of the repository
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().DownloadRepository(context.Background(), "octocat", "frogbot", "feature", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, _, localPath string) error {
+		return os.WriteFile(filepath.Join(localPath, "package.json"), []byte("{}"), 0644)
+	})
+	wd, cleanup, err := DownloadForkToTempDir(mockVcsClient, gitParams)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(wd, "package.json"))
+	assert.NoError(t, cleanup())
+
+	// When the token has no access to the fork, it's downloaded anonymously
+	var anonymousRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		anonymousRequests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	gitParams.APIEndpoint = server.URL
+	var failedDownloadWd string
+	mockVcsClient = testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().DownloadRepository(context.Background(), "octocat", "frogbot", "feature", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, _, localPath string) error {
+		failedDownloadWd = localPath
+		return errors.New("404 Not Found")
+	})
+	_, cleanup, err = DownloadForkToTempDir(mockVcsClient, gitParams)
+	assert.ErrorContains(t, err, "404")
+	assert.Nil(t, cleanup)
+	assert.NotZero(t, anonymousRequests)
+	// The directories of the failed downloads are removed
+	assert.NoDirExists(t, failedDownloadWd)
+}
@@ -3,9 +3,11 @@ package utils
 import (
 	"errors"
 	"fmt"
-	"net/http"
+	"os"
 	// "os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -36,6 +38,15 @@ const (
 	// Separators used to convert technologies array into string
 	fixBranchTechSeparator        = "-"
 	pullRequestTitleTechSeparator = ","
+
+	// Separators used to convert the CVEs of a vulnerability into string
+	cveBranchSeparator  = "_"
+	cveMessageSeparator = ", "
+)
+
+var (
+	supportedPlaceHolders    = []string{PackagePlaceHolder, FixVersionPlaceHolder, BranchHashPlaceHolder, CvePlaceHolder, SeverityPlaceHolder, TechnologyPlaceHolder, WorkingDirPlaceHolder, BaseBranchPlaceHolder}
+	templatePlaceHolderRegex = regexp.MustCompile(`\$?{[A-Z_]+}`)
 )
 
 type GitManager struct {
@@ -235,6 +246,10 @@ func (gm *GitManager) Clone(destinationPath, branchName string) error {
 		Depth:         1,
 		Tags:          git.NoTags,
 	}
+	if gm.git != nil && gm.git.Submodules {
+		log.Debug("Initializing and updating the Git submodules after the clone")
+		cloneOptions.RecurseSubmodules = git.DefaultSubmoduleRecursionDepth
+	}
 	repo, err := git.PlainClone(destinationPath, false, cloneOptions)
 	if err != nil {
 		return fmt.Errorf("git clone %s from %s failed with error: %s", branchName, credentialsFreeRemoteGitUrl, err.Error())
@@ -359,13 +374,18 @@ func (gm *GitManager) commit(commitMessage string) error {
 	if err != nil {
 		return err
 	}
-	_, err = worktree.Commit(commitMessage, &git.CommitOptions{
+	commitOptions := &git.CommitOptions{
 		Author: &object.Signature{
 			Name:  frogbotAuthorName,
 			Email: gm.git.EmailAuthor,
 			When:  time.Now(),
 		},
-	})
+	}
+	// The commits are unsigned unless a signing key is configured
+	if gm.git.CommitSigner != nil {
+		commitOptions.Signer = gm.git.CommitSigner
+	}
+	_, err = worktree.Commit(commitMessage, commitOptions)
 	if err != nil {
 		err = fmt.Errorf("git commit failed with error: %s", err.Error())
 	}
@@ -436,31 +456,247 @@ func (gm *GitManager) IsClean() (bool, error) {
 	return status.IsClean(), nil
 }
 
-func (gm *GitManager) GenerateCommitMessage(impactedPackage string, fixVersion string) string {
+// WorktreeSnapshot holds the changed and untracked files of the working tree at a point in time, so the changes that are made after it can be rolled back
+type WorktreeSnapshot struct {
+	// The changed and untracked files by their paths, relative to the root of the repository
+	files map[string]snapshotFile
+	// The paths of the deleted files, relative to the root of the repository
+	deletedFiles map[string]bool
+}
+
+type snapshotFile struct {
+	content []byte
+	mode    os.FileMode
+}
+
+// Takes a snapshot of the changed and untracked files of the working tree, with their content
+func (gm *GitManager) SnapshotWorktree() (*WorktreeSnapshot, error) {
+	worktree, err := gm.localGitRepository.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &WorktreeSnapshot{files: map[string]snapshotFile{}, deletedFiles: map[string]bool{}}
+	for path, fileStatus := range status {
+		if fileStatus.Worktree == git.Deleted {
+			snapshot.deletedFiles[path] = true
+			continue
+		}
+		fullPath := filepath.Join(worktree.Filesystem.Root(), path)
+		fileInfo, err := os.Stat(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.files[path] = snapshotFile{content: content, mode: fileInfo.Mode()}
+	}
+	return snapshot, nil
+}
+
+// Rolls back the changes of the working tree since the snapshot. The files that were changed or untracked when the snapshot was taken get
+// their content back, the deleted files are deleted again, the other changed files get their content of the last commit,
+// and the files that were created since the snapshot are removed.
+func (gm *GitManager) RestoreWorktree(snapshot *WorktreeSnapshot) error {
+	worktree, err := gm.localGitRepository.Worktree()
+	if err != nil {
+		return err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return err
+	}
+	head, err := gm.localGitRepository.Head()
+	if err != nil {
+		return err
+	}
+	headCommit, err := gm.localGitRepository.CommitObject(head.Hash())
+	if err != nil {
+		return err
+	}
+	for path := range status {
+		fullPath := filepath.Join(worktree.Filesystem.Root(), path)
+		file, changedBefore := snapshot.files[path]
+		switch {
+		case snapshot.deletedFiles[path]:
+			if e := os.Remove(fullPath); e != nil && !errors.Is(e, os.ErrNotExist) {
+				err = errors.Join(err, e)
+			}
+		case changedBefore:
+			err = errors.Join(err, file.write(fullPath))
+		default:
+			err = errors.Join(err, restoreCommittedFile(headCommit, path, fullPath))
+		}
+	}
+	// The files that were changed when the snapshot was taken and were reverted to their committed content since,
+	// and the untracked files that were removed since
+	for path, file := range snapshot.files {
+		if _, changed := status[path]; !changed {
+			err = errors.Join(err, file.write(filepath.Join(worktree.Filesystem.Root(), path)))
+		}
+	}
+	return err
+}
+
+func (sf snapshotFile) write(fullPath string) error {
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fullPath, sf.content, sf.mode); err != nil {
+		return err
+	}
+	// The mode of an existing file isn't changed by writing it
+	return os.Chmod(fullPath, sf.mode)
+}
+
+// Writes the content of the file in the commit, or removes the file if the commit doesn't have it
+func restoreCommittedFile(commit *object.Commit, path, fullPath string) error {
+	file, err := commit.File(path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		if err = os.Remove(fullPath); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return err
+	}
+	mode, err := file.Mode.ToOSFileMode()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte(content), mode)
+}
+
+// Returns the unified diff of the last commit of the current branch, compared to its parent commit
+func (gm *GitManager) GetLastCommitDiff() (string, error) {
+	head, err := gm.localGitRepository.Head()
+	if err != nil {
+		return "", err
+	}
+	lastCommit, err := gm.localGitRepository.CommitObject(head.Hash())
+	if err != nil {
+		return "", err
+	}
+	parentCommit, err := lastCommit.Parent(0)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the parent of commit %s: %s", lastCommit.Hash, err.Error())
+	}
+	patch, err := parentCommit.Patch(lastCommit)
+	if err != nil {
+		return "", err
+	}
+	return patch.String(), nil
+}
+
+// Fetches the last commit of a branch other than the cloned branch, since the repository is cloned with a single branch
+func (gm *GitManager) FetchBranch(branchName string) error {
+	if gm.dryRun {
+		// On dry run the branches of the local repository are used
+		return nil
+	}
+	log.Debug("Running git fetch for branch:", branchName)
+	err := gm.localGitRepository.Fetch(&git.FetchOptions{
+		RemoteName: gm.remoteName,
+		Auth:       gm.auth,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%[1]s", branchName, gm.remoteName))},
+		Depth:      1,
+		Tags:       git.NoTags,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("git fetch %s failed with error: %s", branchName, err.Error())
+	}
+	return nil
+}
+
+// Creates a new branch from the last commit of the fetched start branch, and switches to it
+func (gm *GitManager) CreateBranchFromAndCheckout(branchName, startBranchName string) error {
+	log.Debug("Creating branch", branchName, "from", startBranchName, "...")
+	startRevision := fmt.Sprintf("%s/%s", gm.remoteName, startBranchName)
+	if gm.dryRun {
+		startRevision = startBranchName
+	}
+	startHash, err := gm.localGitRepository.ResolveRevision(plumbing.Revision(startRevision))
+	if err != nil {
+		return fmt.Errorf("failed to resolve the last commit of branch '%s': %s", startBranchName, err.Error())
+	}
+	worktree, err := gm.localGitRepository.Worktree()
+	if err != nil {
+		return err
+	}
+	if err = worktree.Checkout(&git.CheckoutOptions{Hash: *startHash, Branch: GetFullBranchName(branchName), Create: true, Force: true}); err != nil {
+		return fmt.Errorf("failed upon creating/checkout branch '%s' with error: %s", branchName, err.Error())
+	}
+	return nil
+}
+
+func (gm *GitManager) GenerateCommitMessage(baseBranch, workingDir string, vulnDetails *VulnerabilityDetails) string {
 	template := gm.customTemplates.commitMessageTemplate
 	if template == "" {
 		template = CommitMessageTemplate
 	}
-	return formatStringWithPlaceHolders(template, impactedPackage, fixVersion, "", "", true)
+	return formatStringWithPlaceHolders(template, newFixPlaceHolderValues(baseBranch, workingDir, vulnDetails, cveMessageSeparator), true)
 }
 
-func (gm *GitManager) GenerateAggregatedCommitMessage(tech []techutils.Technology) string {
+func (gm *GitManager) GenerateAggregatedCommitMessage(baseBranch string, tech []techutils.Technology) string {
 	template := gm.customTemplates.commitMessageTemplate
 	if template == "" {
 		// In aggregated mode, commit message and PR title are the same.
-		template = gm.GenerateAggregatedPullRequestTitle(tech)
+		template = gm.GenerateAggregatedPullRequestTitle(baseBranch, tech)
+	}
+	return formatStringWithPlaceHolders(template, placeHolderValues{technology: techArrayToString(tech, pullRequestTitleTechSeparator), baseBranch: baseBranch}, true)
+}
+
+// The values of the templates placeholders.
+// Placeholders without a value are replaced with an empty string.
+type placeHolderValues struct {
+	impactedPackage string
+	fixVersion      string
+	hash            string
+	cve             string
+	severity        string
+	technology      string
+	workingDir      string
+	baseBranch      string
+}
+
+func newFixPlaceHolderValues(baseBranch, workingDir string, vulnDetails *VulnerabilityDetails, cveSeparator string) placeHolderValues {
+	return placeHolderValues{
+		impactedPackage: vulnDetails.ImpactedDependencyName,
+		fixVersion:      vulnDetails.SuggestedFixedVersion,
+		cve:             strings.Join(vulnDetails.Cves, cveSeparator),
+		severity:        vulnDetails.Severity,
+		technology:      techArrayToString([]techutils.Technology{vulnDetails.Technology}, ""),
+		workingDir:      filepath.ToSlash(workingDir),
+		baseBranch:      baseBranch,
 	}
-	return formatStringWithPlaceHolders(template, "", "", "", "", true)
 }
 
-func formatStringWithPlaceHolders(str, impactedPackage, fixVersion, hash, baseBranch string, allowSpaces bool) string {
+func formatStringWithPlaceHolders(str string, values placeHolderValues, allowSpaces bool) string {
 	replacements := []struct {
 		placeholder string
 		value       string
 	}{
-		{PackagePlaceHolder, impactedPackage},
-		{FixVersionPlaceHolder, fixVersion},
-		{BranchHashPlaceHolder, hash},
+		{PackagePlaceHolder, values.impactedPackage},
+		{FixVersionPlaceHolder, values.fixVersion},
+		{BranchHashPlaceHolder, values.hash},
+		{CvePlaceHolder, values.cve},
+		{SeverityPlaceHolder, values.severity},
+		{TechnologyPlaceHolder, values.technology},
+		{WorkingDirPlaceHolder, values.workingDir},
+		{BaseBranchPlaceHolder, values.baseBranch},
 	}
 	for _, r := range replacements {
 		// Replace placeholders with their corresponding values
@@ -471,43 +707,55 @@ func formatStringWithPlaceHolders(str, impactedPackage, fixVersion, hash, baseBr
 	if !allowSpaces {
 		str = strings.ReplaceAll(str, " ", "_")
 	}
-	// Add baseBranch suffix if needed.
-	if baseBranch != "" {
-		str += "-" + baseBranch
-	}
 	return str
 }
 
-func (gm *GitManager) GenerateFixBranchName(branch string, impactedPackage string, fixVersion string) (string, error) {
-	hash, err := Md5Hash("frogbot", branch, impactedPackage, fixVersion)
+func (gm *GitManager) GenerateFixBranchName(baseBranch, workingDir string, vulnDetails *VulnerabilityDetails) (string, error) {
+	hash, err := Md5Hash("frogbot", baseBranch, vulnDetails.ImpactedDependencyName, vulnDetails.SuggestedFixedVersion)
 	if err != nil {
 		return "", err
 	}
-	// Package names in Maven usually contain colons, which are not allowed in a branch name
-	fixedPackageName := strings.ReplaceAll(impactedPackage, ":", "_")
 	branchFormat := gm.customTemplates.branchNameTemplate
 	if branchFormat == "" {
 		branchFormat = BranchNameTemplate
 	}
-	return formatStringWithPlaceHolders(branchFormat, fixedPackageName, fixVersion, hash, "", false), nil
+	values := newFixPlaceHolderValues(baseBranch, workingDir, vulnDetails, cveBranchSeparator)
+	values.hash = hash
+	// Package names in Maven usually contain colons, and scoped npm packages start with '@', which are not allowed in a branch name
+	values.impactedPackage = replaceBranchInvalidChars(values.impactedPackage)
+	values.fixVersion = replaceBranchInvalidChars(values.fixVersion)
+	values.workingDir = replaceBranchInvalidChars(values.workingDir)
+	return validateFixBranchName(formatStringWithPlaceHolders(branchFormat, values, false))
 }
 
-func (gm *GitManager) GeneratePullRequestTitle(impactedPackage string, version string) string {
+func replaceBranchInvalidChars(value string) string {
+	return branchInvalidCharsRegex.ReplaceAllString(value, "_")
+}
+
+func validateFixBranchName(fixBranchName string) (string, error) {
+	if err := validateBranchName(fixBranchName); err != nil {
+		return "", fmt.Errorf("invalid fix branch name %s: %w", fixBranchName, err)
+	}
+	return fixBranchName, nil
+}
+
+func (gm *GitManager) GeneratePullRequestTitle(baseBranch, workingDir string, vulnDetails *VulnerabilityDetails) string {
 	template := PullRequestTitleTemplate
 	pullRequestFormat := gm.customTemplates.pullRequestTitleTemplate
 	if pullRequestFormat != "" {
 		template = pullRequestFormat
 	}
-	return formatStringWithPlaceHolders(template, impactedPackage, version, "", "", true)
+	return formatStringWithPlaceHolders(template, newFixPlaceHolderValues(baseBranch, workingDir, vulnDetails, cveMessageSeparator), true)
 }
 
-func (gm *GitManager) GenerateAggregatedPullRequestTitle(tech []techutils.Technology) string {
-	template := gm.getPullRequestTitleTemplate(tech)
+func (gm *GitManager) GenerateAggregatedPullRequestTitle(baseBranch string, tech []techutils.Technology) string {
+	techString := techArrayToString(tech, pullRequestTitleTechSeparator)
+	title := formatStringWithPlaceHolders(gm.getPullRequestTitleTemplate(tech), placeHolderValues{technology: techString, baseBranch: baseBranch}, true)
 	// If no technologies are provided, return the template as-is
 	if len(tech) == 0 {
-		return normalizeWhitespaces(strings.ReplaceAll(template, "%s", ""))
+		return normalizeWhitespaces(strings.ReplaceAll(title, "%s", ""))
 	}
-	return fmt.Sprintf(template, techArrayToString(tech, pullRequestTitleTechSeparator))
+	return strings.Replace(title, "%s", techString, 1)
 }
 
 func (gm *GitManager) getPullRequestTitleTemplate(tech []techutils.Technology) string {
@@ -521,12 +769,46 @@ func (gm *GitManager) getPullRequestTitleTemplate(tech []techutils.Technology) s
 
 // GenerateAggregatedFixBranchName Generating a consistent branch name to enable branch updates
 // and to ensure that there is only one Frogbot aggregate pull request from each base branch scanned.
-func (gm *GitManager) GenerateAggregatedFixBranchName(baseBranch string, tech []techutils.Technology) (fixBranchName string) {
+func (gm *GitManager) GenerateAggregatedFixBranchName(baseBranch string, tech []techutils.Technology) (string, error) {
 	branchFormat := gm.customTemplates.branchNameTemplate
 	if branchFormat == "" {
 		branchFormat = AggregatedBranchNameTemplate
 	}
-	return formatStringWithPlaceHolders(branchFormat, "", "", techArrayToString(tech, fixBranchTechSeparator), baseBranch, false)
+	techString := techArrayToString(tech, fixBranchTechSeparator)
+	fixBranchName := formatStringWithPlaceHolders(branchFormat, placeHolderValues{hash: techString, technology: techString, baseBranch: baseBranch}, false)
+	// Add the base branch suffix, unless the template already contains the base branch
+	if baseBranch != "" && !strings.Contains(branchFormat, BaseBranchPlaceHolder) {
+		fixBranchName += "-" + baseBranch
+	}
+	return validateFixBranchName(fixBranchName)
+}
+
+// FixBranchNameRegexp returns the expression that matches the names of the fix branches that the branch name template generates.
+// Without a template, it matches the names of both the fix branches of each vulnerability and the aggregated fix branches.
+// A template without fixed text would match any branch, so it's rejected.
+func FixBranchNameRegexp(branchNameTemplate string) (*regexp.Regexp, error) {
+	templates := []string{branchNameTemplate}
+	if branchNameTemplate == "" {
+		templates = []string{BranchNameTemplate, AggregatedBranchNameTemplate}
+	}
+	patterns := make([]string, len(templates))
+	for i, template := range templates {
+		// Spaces are replaced the same way as in the generated branch names
+		pattern := regexp.QuoteMeta(strings.ReplaceAll(template, " ", "_"))
+		fixedText := template
+		for _, placeHolder := range supportedPlaceHolders {
+			quotedPlaceHolder := regexp.QuoteMeta(placeHolder)
+			pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("$")+quotedPlaceHolder, ".+")
+			pattern = strings.ReplaceAll(pattern, quotedPlaceHolder, ".+")
+			fixedText = strings.ReplaceAll(strings.ReplaceAll(fixedText, "$"+placeHolder, ""), placeHolder, "")
+		}
+		if strings.TrimSpace(fixedText) == "" {
+			return nil, fmt.Errorf("the fix branches can't be told apart from the other branches, since the branch name template has no fixed text: %s", template)
+		}
+		patterns[i] = pattern
+	}
+	// The aggregated fix branches end with the base branch, unless their template contains it
+	return regexp.Compile("^(?:" + strings.Join(patterns, "|") + ")(?:-.+)?$")
 }
 
 // dryRunClone clones an existing repository from our testdata folder into the destination folder for testing purposes.
@@ -578,16 +860,30 @@ func loadCustomTemplates(commitMessageTemplate, branchNameTemplate, pullRequestT
 		branchNameTemplate:       branchNameTemplate,
 		pullRequestTitleTemplate: pullRequestTitleTemplate,
 	}
+	for _, template := range []string{commitMessageTemplate, branchNameTemplate, pullRequestTitleTemplate} {
+		warnUnsupportedTemplatePlaceHolders(template)
+	}
 	// Validate correct branch by Git providers restrictions.
 	err = validateBranchName(customTemplates.branchNameTemplate)
 	return
 }
 
+// Unsupported placeholders are kept as is in the generated text, so the user is warned about them
+func warnUnsupportedTemplatePlaceHolders(template string) (unsupportedPlaceHolders []string) {
+	for _, placeHolder := range templatePlaceHolderRegex.FindAllString(template, -1) {
+		if !slices.Contains(supportedPlaceHolders, strings.TrimPrefix(placeHolder, "$")) {
+			unsupportedPlaceHolders = append(unsupportedPlaceHolders, placeHolder)
+		}
+	}
+	if len(unsupportedPlaceHolders) > 0 {
+		log.Warn(fmt.Sprintf("The template '%s' contains the unsupported placeholders %s, which are kept as is. The supported placeholders are: %s", template, strings.Join(unsupportedPlaceHolders, ", "), strings.Join(supportedPlaceHolders, ", ")))
+	}
+	return
+}
+
 func setGoGitCustomClient() {
 	log.Debug("Setting timeout for go-git to", goGitTimeoutSeconds, "seconds ...")
-	customClient := &http.Client{
-		Timeout: goGitTimeoutSeconds * time.Second,
-	}
+	customClient := newVcsHttpClient(goGitTimeoutSeconds * time.Second)
 	client.InstallProtocol("http", githttp.NewClient(customClient))
 	client.InstallProtocol("https", githttp.NewClient(customClient))
 }
@@ -602,7 +898,8 @@ func parseCustomTemplate(customTemplate string, tech []techutils.Technology) str
 	// Remove any middle spaces
 	result = strings.Join(strings.Fields(result), " ")
 	var suffix string
-	if len(tech) > 0 {
+	// The technologies are added as a suffix, unless the template already contains them
+	if len(tech) > 0 && !strings.Contains(customTemplate, TechnologyPlaceHolder) {
 		suffix = " - %s Dependencies"
 	}
 	return normalizeWhitespaces(result) + suffix
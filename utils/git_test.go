@@ -15,51 +15,58 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/tests"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGitManager_GenerateCommitMessage(t *testing.T) {
 	testCases := []struct {
-		gitManager      GitManager
-		impactedPackage string
-		fixVersion      VulnerabilityDetails
-		expected        string
-		description     string
+		gitManager  GitManager
+		vulnDetails *VulnerabilityDetails
+		workingDir  string
+		expected    string
+		description string
 	}{
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>: bump ${IMPACTED_PACKAGE}"}},
-			impactedPackage: "mquery",
-			fixVersion:      VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
-			expected:        "<type>: bump mquery",
-			description:     "Custom prefix",
+			gitManager:  GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>: bump ${IMPACTED_PACKAGE}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expected:    "<type>: bump mquery",
+			description: "Custom prefix",
 		},
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>[scope]: Upgrade package ${IMPACTED_PACKAGE} to ${FIX_VERSION}"}},
-			impactedPackage: "mquery", fixVersion: VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
+			gitManager:  GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>[scope]: Upgrade package ${IMPACTED_PACKAGE} to ${FIX_VERSION}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
 			expected:    "<type>[scope]: Upgrade package mquery to 3.4.5",
 			description: "Default template",
 		}, {
-			gitManager:      GitManager{customTemplates: CustomTemplates{commitMessageTemplate: ""}},
-			impactedPackage: "mquery", fixVersion: VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
+			gitManager:  GitManager{customTemplates: CustomTemplates{commitMessageTemplate: ""}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
 			expected:    "Upgrade mquery to 3.4.5",
 			description: "Default template",
 		},
 		// Test template without $
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>[scope]: Upgrade package {IMPACTED_PACKAGE} to {FIX_VERSION}"}},
-			impactedPackage: "mquery", fixVersion: VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
+			gitManager:  GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>[scope]: Upgrade package {IMPACTED_PACKAGE} to {FIX_VERSION}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
 			expected:    "<type>[scope]: Upgrade package mquery to 3.4.5",
 			description: "Default template",
 		},
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>[scope]: Upgrade package ${IMPACTED_PACKAGE} to {FIX_VERSION}"}},
-			impactedPackage: "mquery", fixVersion: VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
+			gitManager:  GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "<type>[scope]: Upgrade package ${IMPACTED_PACKAGE} to {FIX_VERSION}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
 			expected:    "<type>[scope]: Upgrade package mquery to 3.4.5",
 			description: "Default template",
 		},
+		{
+			gitManager:  GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "fix(${TECHNOLOGY}): upgrade {IMPACTED_PACKAGE} to {FIX_VERSION} in {WORKING_DIR} of {BASE_BRANCH}, fixes {SEVERITY} {CVE}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5", "CVE-2023-1234", "CVE-2023-5678"),
+			workingDir:  filepath.Join("frontend", "app"),
+			expected:    "fix(npm): upgrade mquery to 3.4.5 in frontend/app of main, fixes High CVE-2023-1234, CVE-2023-5678",
+			description: "Vulnerability details placeholders",
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.expected, func(t *testing.T) {
-			commitMessage := test.gitManager.GenerateCommitMessage(test.impactedPackage, test.fixVersion.SuggestedFixedVersion)
+			commitMessage := test.gitManager.GenerateCommitMessage("main", test.workingDir, test.vulnDetails)
 			assert.Equal(t, test.expected, commitMessage)
 		})
 	}
@@ -67,75 +74,96 @@ func TestGitManager_GenerateCommitMessage(t *testing.T) {
 
 func TestGitManager_GenerateFixBranchName(t *testing.T) {
 	testCases := []struct {
-		gitManager      GitManager
-		impactedPackage string
-		fixVersion      VulnerabilityDetails
-		expected        string
-		description     string
+		gitManager  GitManager
+		vulnDetails *VulnerabilityDetails
+		workingDir  string
+		expected    string
+		expectedErr string
+		description string
 	}{
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{branchNameTemplate: "[Feature]-${IMPACTED_PACKAGE}-${BRANCH_NAME_HASH}"}},
-			impactedPackage: "mquery",
-			fixVersion:      VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
-			expected:        "[Feature]-mquery-41b1f45136b25e3624b15999bd57a476",
-			description:     "Custom template",
+			gitManager:  GitManager{customTemplates: CustomTemplates{branchNameTemplate: "Feature-${IMPACTED_PACKAGE}-${BRANCH_NAME_HASH}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expected:    "Feature-mquery-41b1f45136b25e3624b15999bd57a476",
+			description: "Custom template",
 		},
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{branchNameTemplate: ""}},
-			impactedPackage: "mquery",
-			fixVersion:      VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
-			expected:        "frogbot-mquery-41b1f45136b25e3624b15999bd57a476",
-			description:     "No template",
+			gitManager:  GitManager{customTemplates: CustomTemplates{branchNameTemplate: ""}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expected:    "frogbot-mquery-41b1f45136b25e3624b15999bd57a476",
+			description: "No template",
+		}, {
+			gitManager:  GitManager{customTemplates: CustomTemplates{branchNameTemplate: "just-a-branch-${BRANCH_NAME_HASH}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expected:    "just-a-branch-41b1f45136b25e3624b15999bd57a476",
+			description: "Custom template without inputs",
+		}, {
+			gitManager:  GitManager{customTemplates: CustomTemplates{branchNameTemplate: "security/{WORKING_DIR}/{TECHNOLOGY}-{SEVERITY}-{CVE}-{BRANCH_NAME_HASH}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5", "CVE-2023-1234", "CVE-2023-5678"),
+			workingDir:  "frontend",
+			expected:    "security/frontend/npm-High-CVE-2023-1234_CVE-2023-5678-41b1f45136b25e3624b15999bd57a476",
+			description: "Vulnerability details placeholders",
 		}, {
-			gitManager:      GitManager{customTemplates: CustomTemplates{branchNameTemplate: "just-a-branch-${BRANCH_NAME_HASH}"}},
-			impactedPackage: "mquery",
-			fixVersion:      VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
-			expected:        "just-a-branch-41b1f45136b25e3624b15999bd57a476",
-			description:     "Custom template without inputs",
+			gitManager:  GitManager{customTemplates: CustomTemplates{branchNameTemplate: "frogbot-{IMPACTED_PACKAGE}-{FIX_VERSION}"}},
+			vulnDetails: newTestVulnerabilityDetails("@types/node", "1:2.0.0"),
+			expected:    "frogbot-_types/node-1_2.0.0",
+			description: "Values with invalid chars",
+		}, {
+			gitManager:  GitManager{customTemplates: CustomTemplates{branchNameTemplate: "[Feature]-${IMPACTED_PACKAGE}-${BRANCH_NAME_HASH}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expectedErr: branchInvalidChars,
+			description: "Invalid template",
 		},
 	}
 	for _, test := range testCases {
-		t.Run(test.expected, func(t *testing.T) {
-			commitMessage, err := test.gitManager.GenerateFixBranchName("md5Branch", test.impactedPackage, test.fixVersion.SuggestedFixedVersion)
+		t.Run(test.description, func(t *testing.T) {
+			branchName, err := test.gitManager.GenerateFixBranchName("md5Branch", test.workingDir, test.vulnDetails)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
 			assert.NoError(t, err)
-			assert.Equal(t, test.expected, commitMessage)
+			assert.Equal(t, test.expected, branchName)
 		})
 	}
 }
 
 func TestGitManager_GeneratePullRequestTitle(t *testing.T) {
 	testCases := []struct {
-		gitManager      GitManager
-		impactedPackage string
-		fixVersion      VulnerabilityDetails
-		expected        string
-		description     string
+		gitManager  GitManager
+		vulnDetails *VulnerabilityDetails
+		workingDir  string
+		expected    string
+		description string
 	}{
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[CustomPR] update ${IMPACTED_PACKAGE} to ${FIX_VERSION}"}},
-			impactedPackage: "mquery",
-			fixVersion:      VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
-			expected:        "[CustomPR] update mquery to 3.4.5",
-			description:     "Custom template",
+			gitManager:  GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[CustomPR] update ${IMPACTED_PACKAGE} to ${FIX_VERSION}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expected:    "[CustomPR] update mquery to 3.4.5",
+			description: "Custom template",
 		},
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[CustomPR] update ${IMPACTED_PACKAGE}"}},
-			impactedPackage: "mquery",
-			fixVersion:      VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
-			expected:        "[CustomPR] update mquery",
-			description:     "Custom template one var",
+			gitManager:  GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[CustomPR] update ${IMPACTED_PACKAGE}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expected:    "[CustomPR] update mquery",
+			description: "Custom template one var",
 		},
 		{
-			gitManager:      GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: ""}},
-			impactedPackage: "mquery",
-			fixVersion:      VulnerabilityDetails{SuggestedFixedVersion: "3.4.5"},
-			expected:        "[🐸 Frogbot] Update version of mquery to 3.4.5",
-			description:     "No prefix",
+			gitManager:  GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: ""}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5"),
+			expected:    "[🐸 Frogbot] Update version of mquery to 3.4.5",
+			description: "No prefix",
+		},
+		{
+			gitManager:  GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[{BASE_BRANCH}] Fix {CVE} in {IMPACTED_PACKAGE}"}},
+			vulnDetails: newTestVulnerabilityDetails("mquery", "3.4.5", "CVE-2023-1234"),
+			expected:    "[main] Fix CVE-2023-1234 in mquery",
+			description: "Base branch and CVE placeholders",
 		},
 	}
 	for _, test := range testCases {
 		t.Run(test.expected, func(t *testing.T) {
-			titleOutput := test.gitManager.GeneratePullRequestTitle(test.impactedPackage, test.fixVersion.SuggestedFixedVersion)
+			titleOutput := test.gitManager.GeneratePullRequestTitle("main", test.workingDir, test.vulnDetails)
 			assert.Equal(t, test.expected, titleOutput)
 		})
 	}
@@ -160,25 +188,80 @@ func TestGitManager_GenerateAggregatedFixBranchName(t *testing.T) {
 			gitManager: GitManager{},
 		},
 		{
-			expected:   "[feature]-Go-main",
+			expected:   "feature-Go-main",
 			baseBranch: "main",
 			desc:       "Custom template hash only",
-			gitManager: GitManager{customTemplates: CustomTemplates{branchNameTemplate: "[feature]-${BRANCH_NAME_HASH}"}},
+			gitManager: GitManager{customTemplates: CustomTemplates{branchNameTemplate: "feature-${BRANCH_NAME_HASH}"}},
 		}, {
-			expected:   "[feature]-Go-master",
+			expected:   "feature-Go-master",
 			baseBranch: "master",
 			desc:       "Custom template hash only",
-			gitManager: GitManager{customTemplates: CustomTemplates{branchNameTemplate: "[feature]-${BRANCH_NAME_HASH}"}},
+			gitManager: GitManager{customTemplates: CustomTemplates{branchNameTemplate: "feature-${BRANCH_NAME_HASH}"}},
+		}, {
+			expected:   "security/master/Go-update",
+			baseBranch: "master",
+			desc:       "Custom template with base branch",
+			gitManager: GitManager{customTemplates: CustomTemplates{branchNameTemplate: "security/{BASE_BRANCH}/{TECHNOLOGY}-update"}},
 		},
 	}
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
-			titleOutput := test.gitManager.GenerateAggregatedFixBranchName(test.baseBranch, []techutils.Technology{techutils.Go})
+			titleOutput, err := test.gitManager.GenerateAggregatedFixBranchName(test.baseBranch, []techutils.Technology{techutils.Go})
+			assert.NoError(t, err)
 			assert.Equal(t, test.expected, titleOutput)
 		})
 	}
 }
 
+func TestFixBranchNameRegexp(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		template     string
+		matching     []string
+		notMatching  []string
+		errorMessage string
+	}{
+		{
+			desc:        "No template",
+			matching:    []string{"frogbot-lodash-1a2b", "frogbot-update-Go-dependencies-main", "frogbot-update-npm_Go-dependencies"},
+			notMatching: []string{"frogbot-master", "feature-lodash-1a2b", "main"},
+		},
+		{
+			desc:        "Custom template",
+			template:    "[feature]-${BRANCH_NAME_HASH}",
+			matching:    []string{"[feature]-Go-main", "[feature]-1a2b"},
+			notMatching: []string{"feature-1a2b", "frogbot-lodash-1a2b"},
+		},
+		{
+			desc:        "Custom template with base branch",
+			template:    "security/{BASE_BRANCH}/{TECHNOLOGY}-update",
+			matching:    []string{"security/master/Go-update"},
+			notMatching: []string{"security/master/Go", "frogbot-update-Go-dependencies-main"},
+		},
+		{
+			desc:         "Template without fixed text",
+			template:     "{BRANCH_NAME_HASH}",
+			errorMessage: "the branch name template has no fixed text",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			fixBranchName, err := FixBranchNameRegexp(test.template)
+			if test.errorMessage != "" {
+				assert.ErrorContains(t, err, test.errorMessage)
+				return
+			}
+			require.NoError(t, err)
+			for _, branch := range test.matching {
+				assert.True(t, fixBranchName.MatchString(branch), branch)
+			}
+			for _, branch := range test.notMatching {
+				assert.False(t, fixBranchName.MatchString(branch), branch)
+			}
+		})
+	}
+}
+
 func TestGitManager_GenerateAggregatedCommitMessage(t *testing.T) {
 	testCases := []struct {
 		gitManager GitManager
@@ -186,10 +269,11 @@ func TestGitManager_GenerateAggregatedCommitMessage(t *testing.T) {
 	}{
 		{gitManager: GitManager{}, expected: "[🐸 Frogbot] Update Pipenv dependencies"},
 		{gitManager: GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "custom_template"}}, expected: "custom_template"},
+		{gitManager: GitManager{customTemplates: CustomTemplates{commitMessageTemplate: "chore({TECHNOLOGY}): update dependencies of {BASE_BRANCH}"}}, expected: "chore(Pipenv): update dependencies of main"},
 	}
 	for _, test := range testCases {
 		t.Run(test.expected, func(t *testing.T) {
-			commit := test.gitManager.GenerateAggregatedCommitMessage([]techutils.Technology{techutils.Pipenv})
+			commit := test.gitManager.GenerateAggregatedCommitMessage("main", []techutils.Technology{techutils.Pipenv})
 			assert.Equal(t, commit, test.expected)
 		})
 	}
@@ -263,6 +347,81 @@ func TestGitManager_Checkout(t *testing.T) {
 	}
 }
 
+func TestGitManager_GetLastCommitDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	restoreWd, err := Chdir(tmpDir)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreWd())
+	}()
+	gitManager := createFakeDotGit(t, tmpDir).SetEmailAuthor("frogbot@jfrog.com")
+	// The first commit has no parent to compare to
+	_, err = gitManager.GetLastCommitDiff()
+	assert.Error(t, err)
+
+	assert.NoError(t, gitManager.CreateBranchAndCheckout("fix", false))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# My New Repository\n\nThis is a fixed repository."), 0644))
+	assert.NoError(t, gitManager.AddAllAndCommit("Fix"))
+	diff, err := gitManager.GetLastCommitDiff()
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "--- a/README.md\n+++ b/README.md\n")
+	assert.Contains(t, diff, "-This is a sample repository created using go-git.")
+	assert.Contains(t, diff, "+This is a fixed repository.")
+}
+
+func TestGitManager_RestoreWorktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	restoreWd, err := Chdir(tmpDir)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreWd())
+	}()
+	gitManager := createFakeDotGit(t, tmpDir).SetEmailAuthor("frogbot@jfrog.com")
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example\n"), 0644))
+	assert.NoError(t, gitManager.AddAllAndCommit("Add go.mod"))
+	committedReadme, err := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	assert.NoError(t, err)
+
+	// The fix of a previous package changed the README, and the install command created a file
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("First fix"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "installed.txt"), []byte("installed"), 0644))
+	snapshot, err := gitManager.SnapshotWorktree()
+	assert.NoError(t, err)
+
+	// The failed update changed the files, including the untracked file, and created a new one
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("Failed fix"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example\n\nrequire broken v1.0.0\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.sum"), []byte("broken"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "installed.txt"), []byte("partially updated"), 0644))
+	assert.NoError(t, gitManager.RestoreWorktree(snapshot))
+
+	for file, expectedContent := range map[string]string{"README.md": "First fix", "go.mod": "module example\n", "installed.txt": "installed"} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedContent, string(content))
+	}
+	assert.NoFileExists(t, filepath.Join(tmpDir, "go.sum"))
+
+	// A file that was changed when the snapshot was taken is restored, even if the failed update reverted it,
+	// and an untracked file is restored, even if the failed update removed it
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), committedReadme, 0644))
+	assert.NoError(t, os.Remove(filepath.Join(tmpDir, "installed.txt")))
+	assert.NoError(t, gitManager.RestoreWorktree(snapshot))
+	for file, expectedContent := range map[string]string{"README.md": "First fix", "installed.txt": "installed"} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedContent, string(content))
+	}
+
+	// A file that was deleted when the snapshot was taken stays deleted, even if the failed update recreated it
+	assert.NoError(t, os.Remove(filepath.Join(tmpDir, "go.mod")))
+	snapshot, err = gitManager.SnapshotWorktree()
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module recreated\n"), 0644))
+	assert.NoError(t, gitManager.RestoreWorktree(snapshot))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "go.mod"))
+}
+
 func createFakeDotGit(t *testing.T, testPath string) *GitManager {
 	// Initialize a new in-memory repository
 	repo, err := git.PlainInit(testPath, false)
@@ -380,10 +539,12 @@ func TestGetAggregatedPullRequestTitle(t *testing.T) {
 		{gm: GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[Feature] %s %f hello"}}, tech: []techutils.Technology{techutils.Yarn, techutils.Go}, expected: "[Feature] hello - Yarn,Go Dependencies"},
 		{gm: GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[Feature] %s %d hello"}}, tech: []techutils.Technology{techutils.Yarn, techutils.Go, techutils.Npm}, expected: "[Feature] hello - Yarn,Go,npm Dependencies"},
 		{gm: GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[Feature] %s %d hello"}}, tech: []techutils.Technology{}, expected: "[Feature] hello"},
+		{gm: GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[{BASE_BRANCH}] Update {TECHNOLOGY} packages"}}, tech: []techutils.Technology{techutils.Yarn, techutils.Go}, expected: "[main] Update Yarn,Go packages"},
+		{gm: GitManager{customTemplates: CustomTemplates{pullRequestTitleTemplate: "[{BASE_BRANCH}] Security fixes"}}, tech: []techutils.Technology{techutils.Go}, expected: "[main] Security fixes - Go Dependencies"},
 	}
 	for _, test := range testsCases {
 		t.Run(test.expected, func(t *testing.T) {
-			title := test.gm.GenerateAggregatedPullRequestTitle(test.tech)
+			title := test.gm.GenerateAggregatedPullRequestTitle("main", test.tech)
 			assert.Equal(t, test.expected, title)
 		})
 	}
@@ -411,3 +572,29 @@ func TestRemoveCredentialsFromUrlIfNeeded(t *testing.T) {
 		})
 	}
 }
+
+func TestWarnUnsupportedTemplatePlaceHolders(t *testing.T) {
+	testCases := []struct {
+		template                string
+		unsupportedPlaceHolders []string
+	}{
+		{template: ""},
+		{template: "fix: upgrade {IMPACTED_PACKAGE} to ${FIX_VERSION}"},
+		{template: "frogbot-{TECHNOLOGY}-{WORKING_DIR}-{SEVERITY}-{CVE}-{BASE_BRANCH}-{BRANCH_NAME_HASH}"},
+		{template: "fix: upgrade {PACKAGE} to {FIX_VERSION}", unsupportedPlaceHolders: []string{"{PACKAGE}"}},
+		{template: "fix: {IMPACTED_PACKAGE} ${CVES}", unsupportedPlaceHolders: []string{"${CVES}"}},
+	}
+	for _, test := range testCases {
+		t.Run(test.template, func(t *testing.T) {
+			assert.Equal(t, test.unsupportedPlaceHolders, warnUnsupportedTemplatePlaceHolders(test.template))
+		})
+	}
+}
+
+func newTestVulnerabilityDetails(impactedPackage, fixVersion string, cves ...string) *VulnerabilityDetails {
+	vulnDetails := &VulnerabilityDetails{SuggestedFixedVersion: fixVersion, Cves: cves}
+	vulnDetails.ImpactedDependencyName = impactedPackage
+	vulnDetails.Severity = "High"
+	vulnDetails.Technology = techutils.Npm
+	return vulnDetails
+}
@@ -0,0 +1,170 @@
+package githubactions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const defaultGitHubApiEndpoint = "https://api.github.com"
+
+// Advisory is a vulnerability of the versions of an action, from the GitHub Advisory Database
+type Advisory struct {
+	GhsaId   string
+	CveId    string
+	Severity string
+	// The vulnerable versions, such as '>= 1.0.0, < 1.2.3'
+	VulnerableVersionRange string
+	// Empty if no version fixes the vulnerability
+	FirstPatchedVersion string
+}
+
+// Returns true if the version is in the vulnerable range of the advisory
+func (a Advisory) Affects(actionVersion string) bool {
+	currentVersion := version.NewVersion(strings.TrimPrefix(actionVersion, "v"))
+	for _, constraint := range strings.Split(a.VulnerableVersionRange, ",") {
+		constraint = strings.TrimSpace(constraint)
+		bound := strings.TrimLeft(constraint, "<>=")
+		operator := constraint[:len(constraint)-len(bound)]
+		// Compare returns 1 if the bound is greater than the current version
+		comparison := currentVersion.Compare(strings.TrimPrefix(strings.TrimSpace(bound), "v"))
+		var isSatisfied bool
+		switch operator {
+		case "<":
+			isSatisfied = comparison > 0
+		case "<=":
+			isSatisfied = comparison >= 0
+		case ">":
+			isSatisfied = comparison < 0
+		case ">=":
+			isSatisfied = comparison <= 0
+		case "=", "":
+			isSatisfied = comparison == 0
+		default:
+			log.Debug(fmt.Sprintf("Unsupported constraint '%s' in the vulnerable versions of %s", constraint, a.GhsaId))
+		}
+		if !isSatisfied {
+			return false
+		}
+	}
+	return true
+}
+
+// AdvisoryFeed provides the advisories of the actions and resolves their references to commit SHAs
+type AdvisoryFeed interface {
+	GetAdvisories(repository string) ([]Advisory, error)
+	GetCommitSha(repository, ref string) (string, error)
+}
+
+// GitHubClient reads the advisories of the actions from the GitHub Advisory Database, and the commits of the actions from their repositories.
+// The actions are hosted on GitHub, so the API of GitHub is used even when the scanned repository is hosted by another Git provider.
+type GitHubClient struct {
+	apiEndpoint string
+	token       string
+}
+
+// The token and the API endpoint of the Git provider are used only if it's GitHub
+func NewGitHubClient(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo) *GitHubClient {
+	if provider != vcsutils.GitHub {
+		return &GitHubClient{apiEndpoint: defaultGitHubApiEndpoint}
+	}
+	apiEndpoint := strings.TrimSuffix(vcsInfo.APIEndpoint, "/")
+	if apiEndpoint == "" {
+		apiEndpoint = defaultGitHubApiEndpoint
+	}
+	return &GitHubClient{apiEndpoint: apiEndpoint, token: vcsInfo.Token}
+}
+
+// Returns the reviewed advisories of the repository of the actions. An action is expected to have less advisories than a single page.
+func (gc *GitHubClient) GetAdvisories(repository string) (advisories []Advisory, err error) {
+	var response []struct {
+		GhsaId          string `json:"ghsa_id"`
+		CveId           string `json:"cve_id"`
+		Severity        string `json:"severity"`
+		Vulnerabilities []struct {
+			Package struct {
+				Ecosystem string `json:"ecosystem"`
+				Name      string `json:"name"`
+			} `json:"package"`
+			VulnerableVersionRange string `json:"vulnerable_version_range"`
+			FirstPatchedVersion    string `json:"first_patched_version"`
+		} `json:"vulnerabilities"`
+	}
+	advisoriesUrl := fmt.Sprintf("%s/advisories?type=reviewed&ecosystem=actions&affects=%s&per_page=100", gc.apiEndpoint, url.QueryEscape(repository))
+	body, err := gc.sendGetRequest(advisoriesUrl, "application/vnd.github+json")
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return
+	}
+	for _, advisory := range response {
+		for _, vulnerability := range advisory.Vulnerabilities {
+			if vulnerability.Package.Ecosystem != "actions" || !strings.EqualFold(vulnerability.Package.Name, repository) {
+				continue
+			}
+			advisories = append(advisories, Advisory{
+				GhsaId:                 advisory.GhsaId,
+				CveId:                  advisory.CveId,
+				Severity:               toSeverity(advisory.Severity),
+				VulnerableVersionRange: vulnerability.VulnerableVersionRange,
+				FirstPatchedVersion:    vulnerability.FirstPatchedVersion,
+			})
+		}
+	}
+	return
+}
+
+// Returns the SHA of the commit that the tag or the branch of the repository of the actions points to
+func (gc *GitHubClient) GetCommitSha(repository, ref string) (string, error) {
+	body, err := gc.sendGetRequest(fmt.Sprintf("%s/repos/%s/commits/%s", gc.apiEndpoint, repository, url.PathEscape(ref)), "application/vnd.github.sha")
+	if err != nil {
+		return "", err
+	}
+	sha := strings.TrimSpace(string(body))
+	if !IsCommitSha(sha) {
+		return "", fmt.Errorf("unexpected commit SHA of %s@%s: %s", repository, ref, sha)
+	}
+	return sha, nil
+}
+
+func (gc *GitHubClient) sendGetRequest(requestUrl, accept string) ([]byte, error) {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{"Accept": accept}
+	if gc.token != "" {
+		headers["Authorization"] = "Bearer " + gc.token
+	}
+	log.Debug("Sending HTTP GET request to:", requestUrl)
+	resp, body, _, err := client.SendGet(requestUrl, true, httputils.HttpClientDetails{Headers: headers}, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded with status %s: %s", requestUrl, resp.Status, string(body))
+	}
+	return body, nil
+}
+
+// The severities of the advisories are lower case, and 'moderate' is the Medium severity
+func toSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "":
+		return "Unknown"
+	case "moderate":
+		return "Medium"
+	default:
+		return strings.ToUpper(severity[:1]) + strings.ToLower(severity[1:])
+	}
+}
@@ -0,0 +1,170 @@
+package githubactions
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	VulnerableVersionType    = "Vulnerable Version"
+	MutableReferenceType     = "Mutable Reference"
+	mutableReferenceSeverity = "Low"
+)
+
+// Moving tags such as 'v4' follow the latest release of their major version, so only exact versions are matched against the advisories
+var exactVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+
+// Analyzer checks the actions that the GitHub Actions workflows of the repository use against the GitHub Advisory Database,
+// and reports the actions that aren't pinned to commit SHAs, since their tags and branches may be moved to other commits.
+type Analyzer struct {
+	feed AdvisoryFeed
+	// Resolve the commit SHAs that the mutable references should be pinned to
+	pin bool
+	// The references of the target branch. Their issues aren't added by the pull request, so they aren't reported.
+	targetReferences *datastructures.Set[string]
+	// The references that were already analyzed, so each issue is reported once for all the projects
+	reported *datastructures.Set[string]
+	// The advisories of the repositories of the actions, by the repositories
+	advisories map[string][]Advisory
+}
+
+// Returns nil if the scan of the GitHub Actions workflows isn't enabled, which disables the analysis
+func NewAnalyzer(enabled, pin bool, provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo) *Analyzer {
+	if !enabled {
+		return nil
+	}
+	return newAnalyzer(NewGitHubClient(provider, vcsInfo), pin)
+}
+
+func newAnalyzer(feed AdvisoryFeed, pin bool) *Analyzer {
+	return &Analyzer{feed: feed, pin: pin, reported: datastructures.MakeSet[string](), advisories: make(map[string][]Advisory)}
+}
+
+// Sets the workflows of the target branch of a pull request, so only the references that the pull request adds are reported by the next analysis
+func (a *Analyzer) SetTargetBranch(rootDir string) error {
+	if a == nil {
+		return nil
+	}
+	references, err := FindReferences(rootDir)
+	if err != nil {
+		return err
+	}
+	a.targetReferences = datastructures.MakeSet[string]()
+	for _, reference := range references {
+		a.targetReferences.Add(referenceKey(rootDir, reference))
+	}
+	return nil
+}
+
+// Returns the issues of the actions that the workflows of the repository use.
+// The paths of the workflows are reported relative to the root directory of the repository.
+func (a *Analyzer) Analyze(rootDir string) (actionIssues []issues.GitHubActionIssue, err error) {
+	if a == nil {
+		return
+	}
+	targetReferences := a.targetReferences
+	a.targetReferences = nil
+	references, err := FindReferences(rootDir)
+	if err != nil {
+		return
+	}
+	for _, reference := range references {
+		key := referenceKey(rootDir, reference)
+		if a.reported.Exists(key) || (targetReferences != nil && targetReferences.Exists(key)) {
+			continue
+		}
+		a.reported.Add(key)
+		reference.Workflow = relativePath(rootDir, reference.Workflow)
+		var referenceIssues []issues.GitHubActionIssue
+		if referenceIssues, err = a.analyzeReference(reference); err != nil {
+			return nil, fmt.Errorf("failed to check the %s action: %s", reference.Action, err.Error())
+		}
+		actionIssues = append(actionIssues, referenceIssues...)
+	}
+	if len(actionIssues) > 0 {
+		log.Info(fmt.Sprintf("Found %d issues in the actions of the GitHub Actions workflows", len(actionIssues)))
+	}
+	return
+}
+
+func (a *Analyzer) analyzeReference(reference Reference) (referenceIssues []issues.GitHubActionIssue, err error) {
+	if exactVersionPattern.MatchString(reference.Version) {
+		var advisories []Advisory
+		if advisories, err = a.getAdvisories(reference.Repository()); err != nil {
+			return
+		}
+		for _, advisory := range advisories {
+			if !advisory.Affects(reference.Version) {
+				continue
+			}
+			actionIssue := newIssue(reference, VulnerableVersionType, advisory.Severity)
+			actionIssue.IssueId = advisory.GhsaId
+			if advisory.CveId != "" {
+				actionIssue.Cves = []string{advisory.CveId}
+			}
+			if advisory.FirstPatchedVersion != "" {
+				// The fixed version is referenced in the style of the current version
+				actionIssue.FixedVersion = strings.TrimPrefix(advisory.FirstPatchedVersion, "v")
+				if strings.HasPrefix(reference.Version, "v") {
+					actionIssue.FixedVersion = "v" + actionIssue.FixedVersion
+				}
+			}
+			referenceIssues = append(referenceIssues, actionIssue)
+		}
+	}
+	if reference.IsPinned() {
+		return
+	}
+	actionIssue := newIssue(reference, MutableReferenceType, mutableReferenceSeverity)
+	if a.pin {
+		if actionIssue.FixedVersion, err = a.feed.GetCommitSha(reference.Repository(), reference.Ref); err != nil {
+			// The reference is reported without the commit SHA to pin it to
+			log.Debug(fmt.Sprintf("Couldn't resolve the commit SHA of %s@%s: %s", reference.Action, reference.Ref, err.Error()))
+			err = nil
+		}
+	}
+	return append(referenceIssues, actionIssue), nil
+}
+
+func (a *Analyzer) getAdvisories(repository string) (advisories []Advisory, err error) {
+	advisories, exists := a.advisories[repository]
+	if exists {
+		return
+	}
+	if advisories, err = a.feed.GetAdvisories(repository); err != nil {
+		return
+	}
+	a.advisories[repository] = advisories
+	return
+}
+
+func newIssue(reference Reference, issueType, severity string) issues.GitHubActionIssue {
+	return issues.GitHubActionIssue{
+		Workflow: reference.Workflow,
+		Line:     reference.Line,
+		Type:     issueType,
+		Action:   reference.Action,
+		Ref:      reference.Ref,
+		Version:  reference.Version,
+		Severity: severity,
+	}
+}
+
+func referenceKey(rootDir string, reference Reference) string {
+	return relativePath(rootDir, reference.Workflow) + "|" + reference.Action + "@" + reference.Ref
+}
+
+func relativePath(rootDir, path string) string {
+	if relativePath, err := filepath.Rel(rootDir, path); err == nil {
+		return filepath.ToSlash(relativePath)
+	}
+	return path
+}
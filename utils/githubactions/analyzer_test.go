@@ -0,0 +1,151 @@
+package githubactions
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFeed struct {
+	advisories map[string][]Advisory
+	shas       map[string]string
+	requests   int
+}
+
+func (tf *testFeed) GetAdvisories(repository string) ([]Advisory, error) {
+	tf.requests++
+	return tf.advisories[repository], nil
+}
+
+func (tf *testFeed) GetCommitSha(repository, ref string) (string, error) {
+	if sha, exists := tf.shas[repository+"@"+ref]; exists {
+		return sha, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestAdvisoryAffects(t *testing.T) {
+	testCases := []struct {
+		versionRange string
+		version      string
+		expected     bool
+	}{
+		{versionRange: "< 46.0.1", version: "v45.0.7", expected: true},
+		{versionRange: "< 46.0.1", version: "v46.0.1", expected: false},
+		{versionRange: ">= 3.0.0, < 3.24.3", version: "3.24.0", expected: true},
+		{versionRange: ">= 3.0.0, < 3.24.3", version: "v2.9.0", expected: false},
+		{versionRange: "<= 2.0.0", version: "v2.0.0", expected: true},
+		{versionRange: "> 1.0.0, <= 2.0.0", version: "v1.0.0", expected: false},
+		{versionRange: "= 1.2.3", version: "v1.2.3", expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.versionRange+" "+tc.version, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Advisory{VulnerableVersionRange: tc.versionRange}.Affects(tc.version))
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	rootDir := t.TempDir()
+	writeTestWorkflow(t, rootDir)
+	feed := &testFeed{
+		advisories: map[string][]Advisory{
+			"tj-actions/changed-files": {{GhsaId: "GHSA-mrrh-fwg8-r2c3", CveId: "CVE-2025-30066", Severity: "High", VulnerableVersionRange: "< 46.0.1", FirstPatchedVersion: "46.0.1"}},
+			"actions/setup-go":         {{GhsaId: "GHSA-0000-0000-0001", Severity: "Medium", VulnerableVersionRange: "< 5.0.1"}},
+			"github/codeql-action":     {{GhsaId: "GHSA-0000-0000-0002", Severity: "Low", VulnerableVersionRange: "< 3.0.0", FirstPatchedVersion: "3.0.0"}},
+		},
+		shas: map[string]string{"actions/checkout@v4": checkoutSha},
+	}
+	analyzer := newAnalyzer(feed, true)
+	actionIssues, err := analyzer.Analyze(rootDir)
+	require.NoError(t, err)
+	workflow := ".github/workflows/build.yml"
+	assert.Equal(t, []issues.GitHubActionIssue{
+		{Workflow: workflow, Line: 7, Type: MutableReferenceType, Action: "actions/checkout", Ref: "v4", Version: "v4", Severity: "Low", FixedVersion: checkoutSha},
+		{Workflow: workflow, Line: 9, Type: VulnerableVersionType, Action: "actions/setup-go", Ref: setupGoSha, Version: "v5.0.0", Severity: "Medium", IssueId: "GHSA-0000-0000-0001"},
+		{Workflow: workflow, Line: 10, Type: VulnerableVersionType, Action: "tj-actions/changed-files", Ref: "v45.0.7", Version: "v45.0.7", Severity: "High", IssueId: "GHSA-mrrh-fwg8-r2c3", Cves: []string{"CVE-2025-30066"}, FixedVersion: "v46.0.1"},
+		{Workflow: workflow, Line: 10, Type: MutableReferenceType, Action: "tj-actions/changed-files", Ref: "v45.0.7", Version: "v45.0.7", Severity: "Low"},
+		{Workflow: workflow, Line: 15, Type: MutableReferenceType, Action: "octo-org/workflows/.github/workflows/analyze.yml", Ref: "main", Version: "main", Severity: "Low"},
+		{Workflow: workflow, Line: 19, Type: MutableReferenceType, Action: "github/codeql-action/init", Ref: "v3.24.0", Version: "v3.24.0", Severity: "Low"},
+		{Workflow: workflow, Line: 20, Type: MutableReferenceType, Action: "github/codeql-action/analyze", Ref: "v3.24.0", Version: "v3.24.0", Severity: "Low"},
+	}, actionIssues)
+	// The advisories of each repository are requested once
+	assert.Equal(t, 3, feed.requests)
+
+	// The issues are reported once for all the projects
+	actionIssues, err = analyzer.Analyze(rootDir)
+	require.NoError(t, err)
+	assert.Empty(t, actionIssues)
+}
+
+func TestAnalyzeTargetBranch(t *testing.T) {
+	targetDir, sourceDir := t.TempDir(), t.TempDir()
+	writeTestWorkflow(t, targetDir)
+	workflowPath := writeTestWorkflow(t, sourceDir)
+	content, err := os.ReadFile(workflowPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(workflowPath, append(content, "      - uses: actions/upload-artifact@v4\n"...), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, ".github", "workflows", "release.yaml"), []byte("jobs:\n  release:\n    steps:\n      - uses: actions/checkout@v4\n"), 0644))
+
+	analyzer := newAnalyzer(&testFeed{}, false)
+	require.NoError(t, analyzer.SetTargetBranch(targetDir))
+	actionIssues, err := analyzer.Analyze(sourceDir)
+	require.NoError(t, err)
+	assert.Equal(t, []issues.GitHubActionIssue{
+		{Workflow: ".github/workflows/build.yml", Line: 21, Type: MutableReferenceType, Action: "actions/upload-artifact", Ref: "v4", Version: "v4", Severity: "Low"},
+		{Workflow: ".github/workflows/release.yaml", Line: 4, Type: MutableReferenceType, Action: "actions/checkout", Ref: "v4", Version: "v4", Severity: "Low"},
+	}, actionIssues)
+}
+
+func TestDisabledAnalyzer(t *testing.T) {
+	analyzer := NewAnalyzer(false, true, vcsutils.GitHub, vcsclient.VcsInfo{})
+	assert.Nil(t, analyzer)
+	assert.NoError(t, analyzer.SetTargetBranch(t.TempDir()))
+	actionIssues, err := analyzer.Analyze(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, actionIssues)
+}
+
+func TestGitHubClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/advisories":
+			assert.Equal(t, "actions", r.URL.Query().Get("ecosystem"))
+			assert.Equal(t, "tj-actions/changed-files", r.URL.Query().Get("affects"))
+			_, err := w.Write([]byte(`[{"ghsa_id":"GHSA-mrrh-fwg8-r2c3","cve_id":"CVE-2025-30066","severity":"moderate","vulnerabilities":[
+				{"package":{"ecosystem":"actions","name":"tj-actions/changed-files"},"vulnerable_version_range":"< 46.0.1","first_patched_version":"46.0.1"},
+				{"package":{"ecosystem":"actions","name":"tj-actions/other"},"vulnerable_version_range":"< 1.0.0","first_patched_version":"1.0.0"}]}]`))
+			assert.NoError(t, err)
+		case "/repos/actions/checkout/commits/v4":
+			assert.Equal(t, "application/vnd.github.sha", r.Header.Get("Accept"))
+			_, err := w.Write([]byte(checkoutSha))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewGitHubClient(vcsutils.GitHub, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"})
+
+	advisories, err := client.GetAdvisories("tj-actions/changed-files")
+	require.NoError(t, err)
+	assert.Equal(t, []Advisory{{GhsaId: "GHSA-mrrh-fwg8-r2c3", CveId: "CVE-2025-30066", Severity: "Medium", VulnerableVersionRange: "< 46.0.1", FirstPatchedVersion: "46.0.1"}}, advisories)
+	sha, err := client.GetCommitSha("actions/checkout", "v4")
+	require.NoError(t, err)
+	assert.Equal(t, checkoutSha, sha)
+	_, err = client.GetCommitSha("actions/checkout", "v0")
+	assert.ErrorContains(t, err, "404")
+
+	// The actions are hosted on GitHub, whatever the Git provider of the repository is
+	assert.Equal(t, &GitHubClient{apiEndpoint: defaultGitHubApiEndpoint}, NewGitHubClient(vcsutils.GitLab, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}))
+}
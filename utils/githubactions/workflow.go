@@ -0,0 +1,149 @@
+package githubactions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+)
+
+// The technology of the vulnerabilities of GitHub Actions, which the GitHub Actions package handler fixes
+const Technology techutils.Technology = "github-actions"
+
+const workflowsDir = ".github/workflows"
+
+var (
+	// Matches the 'uses' key of a step or a job, and captures the used action and an optional trailing comment
+	usesPattern = regexp.MustCompile(`^(\s*(?:-\s+)?uses:\s*)(['"]?)([^'"\s#]+)(['"]?)(\s*#\s*(\S+))?`)
+	shaPattern  = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// Reference is a use of an action by a workflow
+type Reference struct {
+	// The path of the workflow, and the line of the step that uses the action
+	Workflow string
+	Line     int
+	// The action, such as 'actions/checkout' or 'github/codeql-action/init'
+	Action string
+	// The reference of the action, which is a tag, a branch or a commit SHA
+	Ref string
+	// The version of the action. The version of an action that is pinned to a commit SHA is read from the comment that follows it, as in 'actions/checkout@<sha> # v4.1.1'.
+	Version string
+}
+
+// Returns the repository of the action, which is the owner and the name of the action without its path
+func (r Reference) Repository() string {
+	return Repository(r.Action)
+}
+
+// Returns true if the action is pinned to a commit SHA
+func (r Reference) IsPinned() bool {
+	return IsCommitSha(r.Ref)
+}
+
+func Repository(action string) string {
+	parts := strings.SplitN(action, "/", 3)
+	if len(parts) < 2 {
+		return action
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+func IsCommitSha(ref string) bool {
+	return shaPattern.MatchString(ref)
+}
+
+// Returns the paths of the workflows of the repository
+func FindWorkflows(rootDir string) (workflows []string, err error) {
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		var matches []string
+		if matches, err = filepath.Glob(filepath.Join(rootDir, workflowsDir, pattern)); err != nil {
+			return
+		}
+		workflows = append(workflows, matches...)
+	}
+	return
+}
+
+// Returns the references of the actions that the workflows of the repository use
+func FindReferences(rootDir string) (references []Reference, err error) {
+	workflows, err := FindWorkflows(rootDir)
+	if err != nil {
+		return
+	}
+	for _, workflow := range workflows {
+		var workflowReferences []Reference
+		if workflowReferences, err = ParseWorkflow(workflow); err != nil {
+			return
+		}
+		references = append(references, workflowReferences...)
+	}
+	return
+}
+
+// Returns the references of the actions that the workflow uses.
+// Local actions and Docker images have no versions to fix, so they aren't returned.
+func ParseWorkflow(workflowPath string) (references []Reference, err error) {
+	content, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the workflow '%s': %s", workflowPath, err.Error())
+	}
+	for i, line := range strings.Split(string(content), "\n") {
+		action, ref, comment, isUses := parseUsesLine(line)
+		if !isUses || ref == "" || strings.HasPrefix(action, ".") || strings.HasPrefix(action, "docker://") {
+			continue
+		}
+		version := ref
+		if IsCommitSha(ref) {
+			version = comment
+		}
+		references = append(references, Reference{Workflow: workflowPath, Line: i + 1, Action: action, Ref: ref, Version: version})
+	}
+	return
+}
+
+// Returns the action, its reference and the trailing comment of a 'uses' line of a workflow
+func parseUsesLine(line string) (action, ref, comment string, isUses bool) {
+	match := usesPattern.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+	action, ref, _ = strings.Cut(match[3], "@")
+	return action, ref, match[6], true
+}
+
+// Replaces the reference of the action in the 'uses' lines of the workflow that use its current version.
+// The comment is written after the new reference, or removed if it's empty.
+func UpdateReference(workflowPath, action, currentVersion, newRef, comment string) (isFileChanged bool, err error) {
+	content, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the workflow '%s': %s", workflowPath, err.Error())
+	}
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		lineAction, ref, lineComment, isUses := parseUsesLine(line)
+		if !isUses || Repository(lineAction) != Repository(action) {
+			continue
+		}
+		if ref != currentVersion && !(IsCommitSha(ref) && lineComment == currentVersion) {
+			continue
+		}
+		match := usesPattern.FindStringSubmatchIndex(line)
+		newUses := line[match[2]:match[3]] + line[match[4]:match[5]] + lineAction + "@" + newRef + line[match[8]:match[9]]
+		if comment != "" {
+			newUses += " # " + comment
+		}
+		lines[i] = newUses + line[match[1]:]
+		isFileChanged = true
+	}
+	if !isFileChanged {
+		return
+	}
+	if err = os.WriteFile(workflowPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		err = fmt.Errorf("failed to write the updated action to the workflow '%s': %s", workflowPath, err.Error())
+	}
+	return
+}
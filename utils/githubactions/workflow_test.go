@@ -0,0 +1,91 @@
+package githubactions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	checkoutSha = "b4ffde65f46336ab88eb53be808477a3936bae11"
+	setupGoSha  = "0c52d547c9bc32b1aa3301fd7a9cb496313a4491"
+)
+
+const testWorkflow = `name: Build
+on: [push]
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Setup Go
+        uses: "actions/setup-go@` + setupGoSha + `" # v5.0.0
+      - uses: tj-actions/changed-files@v45.0.7 # Lists the changed files
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3.19
+      - run: echo "uses: actions/cache@v3"
+  analyze:
+    uses: octo-org/workflows/.github/workflows/analyze.yml@main
+  codeql:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: github/codeql-action/init@v3.24.0
+      - uses: github/codeql-action/analyze@v3.24.0
+`
+
+func writeTestWorkflow(t *testing.T, rootDir string) string {
+	workflowPath := filepath.Join(rootDir, ".github", "workflows", "build.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(workflowPath), 0755))
+	require.NoError(t, os.WriteFile(workflowPath, []byte(testWorkflow), 0644))
+	return workflowPath
+}
+
+func TestFindReferences(t *testing.T) {
+	rootDir := t.TempDir()
+	workflowPath := writeTestWorkflow(t, rootDir)
+	// Files that aren't workflows are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, ".github", "workflows", "README.md"), []byte("uses: actions/checkout@v1"), 0644))
+
+	references, err := FindReferences(rootDir)
+	require.NoError(t, err)
+	assert.Equal(t, []Reference{
+		{Workflow: workflowPath, Line: 7, Action: "actions/checkout", Ref: "v4", Version: "v4"},
+		{Workflow: workflowPath, Line: 9, Action: "actions/setup-go", Ref: setupGoSha, Version: "v5.0.0"},
+		{Workflow: workflowPath, Line: 10, Action: "tj-actions/changed-files", Ref: "v45.0.7", Version: "v45.0.7"},
+		{Workflow: workflowPath, Line: 15, Action: "octo-org/workflows/.github/workflows/analyze.yml", Ref: "main", Version: "main"},
+		{Workflow: workflowPath, Line: 19, Action: "github/codeql-action/init", Ref: "v3.24.0", Version: "v3.24.0"},
+		{Workflow: workflowPath, Line: 20, Action: "github/codeql-action/analyze", Ref: "v3.24.0", Version: "v3.24.0"},
+	}, references)
+	assert.Equal(t, "github/codeql-action", references[4].Repository())
+	assert.True(t, references[1].IsPinned())
+	assert.False(t, references[0].IsPinned())
+}
+
+func TestUpdateReference(t *testing.T) {
+	workflowPath := writeTestWorkflow(t, t.TempDir())
+
+	// All the actions of the repository in the version are updated
+	isFileChanged, err := UpdateReference(workflowPath, "github/codeql-action", "v3.24.0", "v3.24.3", "")
+	require.NoError(t, err)
+	assert.True(t, isFileChanged)
+	// The version of a pinned action is read from its comment
+	isFileChanged, err = UpdateReference(workflowPath, "actions/setup-go", "v5.0.0", "v5.0.1", "")
+	require.NoError(t, err)
+	assert.True(t, isFileChanged)
+	isFileChanged, err = UpdateReference(workflowPath, "actions/checkout", "v4", checkoutSha, "v4")
+	require.NoError(t, err)
+	assert.True(t, isFileChanged)
+	isFileChanged, err = UpdateReference(workflowPath, "actions/checkout", "v3", checkoutSha, "v3")
+	require.NoError(t, err)
+	assert.False(t, isFileChanged)
+
+	content, err := os.ReadFile(workflowPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "      - uses: actions/checkout@"+checkoutSha+" # v4\n")
+	assert.Contains(t, string(content), `        uses: "actions/setup-go@v5.0.1"`+"\n")
+	assert.Contains(t, string(content), "      - uses: github/codeql-action/init@v3.24.3\n      - uses: github/codeql-action/analyze@v3.24.3\n")
+	assert.Contains(t, string(content), "      - uses: tj-actions/changed-files@v45.0.7 # Lists the changed files\n")
+}
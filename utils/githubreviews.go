@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The comment of a GitHub pull request review, on the lines of the head commit of the pull request
+type gitHubReviewComment struct {
+	Path string `json:"path"`
+	Body string `json:"body"`
+	// GitHub doesn't accept a start line that equals the line
+	StartLine int    `json:"start_line,omitempty"`
+	Line      int    `json:"line"`
+	Side      string `json:"side"`
+}
+
+// On GitHub, all the review comments are added in a single pull request review, so the pull request gets one notification and the comments take one request.
+// GitHub rejects the review if any of its comments isn't on the diff of the pull request, and then the comments are added one by one.
+func addGitHubReview(repo *Repository, pullRequestID int, reviewComments []ReviewComment) error {
+	client := vcsapi.NewClient(repo.GitProvider, repo.VcsInfo)
+	review := struct {
+		Event    string                `json:"event"`
+		Comments []gitHubReviewComment `json:"comments"`
+	}{Event: "COMMENT"}
+	for _, comment := range reviewComments {
+		reviewComment := gitHubReviewComment{
+			Path: filepath.ToSlash(filepath.Clean(comment.CommentInfo.NewFilePath)),
+			Body: comment.CommentInfo.Content,
+			Line: comment.CommentInfo.NewEndLine,
+			Side: "RIGHT",
+		}
+		if comment.CommentInfo.NewStartLine != comment.CommentInfo.NewEndLine {
+			reviewComment.StartLine = comment.CommentInfo.NewStartLine
+		}
+		review.Comments = append(review.Comments, reviewComment)
+	}
+	reviewsUrl := client.RepositoryUrl(repo.RepoOwner, repo.RepoName) + "/pulls/" + strconv.Itoa(pullRequestID) + "/reviews"
+	if err := client.Send(http.MethodPost, reviewsUrl, review, nil); err != nil {
+		return err
+	}
+	log.Debug(fmt.Sprintf("Added %d review comments in a single pull request review", len(reviewComments)))
+	return nil
+}
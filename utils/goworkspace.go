@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	GoWorkFile = "go.work"
+	// Setting GOWORK to 'off' makes the Go commands ignore the go.work file, so they resolve the dependencies of the module in the current directory only
+	goWorkEnv = "GOWORK"
+	goWorkOff = "off"
+	gitDir    = ".git"
+)
+
+// The workspace mode is set in the environment of the process, so the audits that turn it off run one at a time
+var goWorkspaceModeMutex sync.Mutex
+
+// Returns the path of the go.work file of the Go workspace that the directory belongs to, or an empty string if it doesn't belong to a workspace.
+// The go.work file is searched in the directory and its parents, as the Go commands do, up to the root of the Git repository,
+// so a go.work file outside the checkout is ignored.
+func FindGoWorkFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		goWorkPath := filepath.Join(dir, GoWorkFile)
+		if _, err = os.Stat(goWorkPath); err == nil {
+			return goWorkPath, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		if _, err = os.Stat(filepath.Join(dir, gitDir)); err == nil {
+			return "", nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Returns the sorted directories of the modules that the go.work file of the directory uses, relative to the directory.
+// Returns nil if the directory has no go.work file.
+func GetGoWorkspaceModules(workspaceDir string) (modules []string, err error) {
+	goWorkPath := filepath.Join(workspaceDir, GoWorkFile)
+	content, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read '%s': %s", goWorkPath, err.Error())
+	}
+	goWork, err := modfile.ParseWork(goWorkPath, content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %s", goWorkPath, err.Error())
+	}
+	for _, use := range goWork.Use {
+		modules = append(modules, filepath.ToSlash(filepath.Clean(filepath.FromSlash(use.Path))))
+	}
+	sort.Strings(modules)
+	return
+}
+
+// Turns off the workspace mode of the Go commands if one of the directories belongs to a Go workspace, so each Go module is audited by its own go.mod,
+// rather than with the dependencies of all the modules of the workspace.
+// The workspace mode is kept if a module of the workspace can't be resolved without the workspace.
+// The returned function restores the previous mode, and must be called once the audit is done.
+func disableGoWorkspaceModeIfNeeded(dirs []string) (restore func() error, err error) {
+	restore = func() error { return nil }
+	var goWorkPaths []string
+	for _, dir := range dirs {
+		var goWorkPath string
+		if goWorkPath, err = FindGoWorkFile(dir); err != nil {
+			return
+		}
+		if goWorkPath != "" && !slices.Contains(goWorkPaths, goWorkPath) {
+			goWorkPaths = append(goWorkPaths, goWorkPath)
+		}
+	}
+	if len(goWorkPaths) == 0 {
+		return
+	}
+	for _, goWorkPath := range goWorkPaths {
+		var dependentModule string
+		if dependentModule, err = getWorkspaceDependentModule(goWorkPath); err != nil || dependentModule != "" {
+			if dependentModule != "" {
+				log.Debug(fmt.Sprintf("The Go module '%s' requires other modules of the Go workspace '%s', so the modules of the workspace are audited together", dependentModule, goWorkPath))
+			}
+			return
+		}
+	}
+	log.Debug(fmt.Sprintf("Detected the Go workspaces %s, their modules are audited separately", strings.Join(goWorkPaths, ", ")))
+	goWorkspaceModeMutex.Lock()
+	previousValue, wasSet := os.LookupEnv(goWorkEnv)
+	if err = os.Setenv(goWorkEnv, goWorkOff); err != nil {
+		goWorkspaceModeMutex.Unlock()
+		return
+	}
+	restore = func() error {
+		defer goWorkspaceModeMutex.Unlock()
+		if wasSet {
+			return os.Setenv(goWorkEnv, previousValue)
+		}
+		return os.Unsetenv(goWorkEnv)
+	}
+	return
+}
+
+// Returns the path of a module of the workspace that requires another module of the workspace without replacing it with a local path.
+// Such a module can be resolved only within the workspace. Returns an empty string if every module can be resolved by its own go.mod.
+func getWorkspaceDependentModule(goWorkPath string) (string, error) {
+	workspaceDir := filepath.Dir(goWorkPath)
+	modules, err := GetGoWorkspaceModules(workspaceDir)
+	if err != nil {
+		return "", err
+	}
+	var goModFiles []*modfile.File
+	for _, module := range modules {
+		goModPath := filepath.Join(workspaceDir, filepath.FromSlash(module), "go.mod")
+		content, err := os.ReadFile(goModPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read '%s': %s", goModPath, err.Error())
+		}
+		goMod, err := modfile.Parse(goModPath, content, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse '%s': %s", goModPath, err.Error())
+		}
+		if goMod.Module != nil {
+			goModFiles = append(goModFiles, goMod)
+		}
+	}
+	isWorkspaceModule := func(modulePath string) bool {
+		return slices.ContainsFunc(goModFiles, func(goMod *modfile.File) bool { return goMod.Module.Mod.Path == modulePath })
+	}
+	for _, goMod := range goModFiles {
+		for _, require := range goMod.Require {
+			if isWorkspaceModule(require.Mod.Path) && !isReplaced(goMod, require.Mod.Path) {
+				return goMod.Module.Mod.Path, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func isReplaced(goMod *modfile.File, modulePath string) bool {
+	return slices.ContainsFunc(goMod.Replace, func(replace *modfile.Replace) bool {
+		return replace.Old.Path == modulePath
+	})
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var goWorkspaceTestDir = filepath.Join("..", "testdata", "projects", "go-workspace")
+
+func TestGetGoWorkspaceModules(t *testing.T) {
+	modules, err := GetGoWorkspaceModules(goWorkspaceTestDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"services/api", "services/web", "tools"}, modules)
+
+	// A directory without a go.work file isn't a workspace
+	modules, err = GetGoWorkspaceModules(filepath.Join(goWorkspaceTestDir, "services"))
+	require.NoError(t, err)
+	assert.Nil(t, modules)
+}
+
+func TestFindGoWorkFile(t *testing.T) {
+	expectedGoWorkPath, err := filepath.Abs(filepath.Join(goWorkspaceTestDir, GoWorkFile))
+	require.NoError(t, err)
+	for _, dir := range []string{goWorkspaceTestDir, filepath.Join(goWorkspaceTestDir, "services", "api")} {
+		goWorkPath, err := FindGoWorkFile(dir)
+		require.NoError(t, err)
+		assert.Equal(t, expectedGoWorkPath, goWorkPath)
+	}
+	goWorkPath, err := FindGoWorkFile(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, goWorkPath)
+
+	// A go.work file outside the Git repository is ignored
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		GoWorkFile:           "go 1.22\n\nuse ./repo/module\n",
+		"repo/.git/HEAD":     "ref: refs/heads/main\n",
+		"repo/module/go.mod": "module github.com/frogbot/module\n\ngo 1.22\n",
+	})
+	goWorkPath, err = FindGoWorkFile(filepath.Join(tempDir, "repo", "module"))
+	require.NoError(t, err)
+	assert.Empty(t, goWorkPath)
+}
+
+func TestDisableGoWorkspaceModeIfNeeded(t *testing.T) {
+	t.Setenv(goWorkEnv, "auto")
+	// The workspace mode is kept when no directory belongs to a workspace
+	restore, err := disableGoWorkspaceModeIfNeeded([]string{t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, "auto", os.Getenv(goWorkEnv))
+	assert.NoError(t, restore())
+
+	restore, err = disableGoWorkspaceModeIfNeeded([]string{t.TempDir(), filepath.Join(goWorkspaceTestDir, "services", "web")})
+	require.NoError(t, err)
+	assert.Equal(t, goWorkOff, os.Getenv(goWorkEnv))
+	assert.NoError(t, restore())
+	assert.Equal(t, "auto", os.Getenv(goWorkEnv))
+
+	// The workspace mode is kept when a module can be resolved only within the workspace
+	workspaceDir := createDependentGoWorkspace(t, "")
+	restore, err = disableGoWorkspaceModeIfNeeded([]string{filepath.Join(workspaceDir, "api")})
+	require.NoError(t, err)
+	assert.Equal(t, "auto", os.Getenv(goWorkEnv))
+	assert.NoError(t, restore())
+}
+
+func TestGetWorkspaceDependentModule(t *testing.T) {
+	dependentModule, err := getWorkspaceDependentModule(filepath.Join(goWorkspaceTestDir, GoWorkFile))
+	require.NoError(t, err)
+	assert.Empty(t, dependentModule)
+
+	dependentModule, err = getWorkspaceDependentModule(filepath.Join(createDependentGoWorkspace(t, ""), GoWorkFile))
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/frogbot/workspace/api", dependentModule)
+
+	// A module that replaces the other module of the workspace with its local path is resolved by its own go.mod
+	dependentModule, err = getWorkspaceDependentModule(filepath.Join(createDependentGoWorkspace(t, "replace github.com/frogbot/workspace/lib => ../lib\n"), GoWorkFile))
+	require.NoError(t, err)
+	assert.Empty(t, dependentModule)
+}
+
+// Creates a Go workspace, in which the api module requires the lib module of the workspace
+func createDependentGoWorkspace(t *testing.T, apiReplace string) string {
+	workspaceDir := t.TempDir()
+	writeTestFiles(t, workspaceDir, map[string]string{
+		".git/HEAD":  "ref: refs/heads/main\n",
+		GoWorkFile:   "go 1.22\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod": "module github.com/frogbot/workspace/api\n\ngo 1.22\n\nrequire github.com/frogbot/workspace/lib v0.0.0\n" + apiReplace,
+		"lib/go.mod": "module github.com/frogbot/workspace/lib\n\ngo 1.22\n",
+	})
+	return workspaceDir
+}
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
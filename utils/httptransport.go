@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The transport of the Git provider clients. Nil until it's configured.
+var vcsHttpTransport http.RoundTripper
+
+// Builds the transport of the Git provider clients, with the proxy and the CA bundle of the run, the Git HTTP settings,
+// and the tracking of the rate limits of the Git providers.
+// froggit-go builds its clients internally, without a way to pass them a transport, so they send their requests with the default HTTP transport,
+// which is set to the Git provider transport. The other clients of the run are built by the transport package from a copy of the
+// original default transport, so the Git HTTP settings and the rate limit tracking don't apply to them.
+func configureVcsHttpTransport() error {
+	timeout, err := getDurationInSecondsEnv(GitHttpTimeoutEnv)
+	if err != nil {
+		return err
+	}
+	keepAlive, err := getDurationInSecondsEnv(GitHttpKeepAliveEnv)
+	if err != nil {
+		return err
+	}
+	vcsTransport := transport.New()
+	if timeout > 0 || keepAlive > 0 {
+		vcsTransport = newGitHttpTransport(vcsTransport, timeout, keepAlive)
+		log.Debug(fmt.Sprintf("Git HTTP transport settings: timeout: %v, keep-alive: %v", timeout, keepAlive))
+	}
+	vcsHttpTransport = &rateLimitTransport{base: vcsTransport}
+	http.DefaultTransport = vcsHttpTransport
+	return nil
+}
+
+// Returns an HTTP client with the transport of the Git provider clients. A zero timeout doesn't limit the requests.
+func newVcsHttpClient(timeout time.Duration) *http.Client {
+	if vcsHttpTransport == nil {
+		return transport.NewClient(timeout)
+	}
+	return &http.Client{Transport: vcsHttpTransport, Timeout: timeout}
+}
+
+// Returns a copy of the given transport with the provided settings. Zero values keep the settings of the given transport.
+// The timeout limits the connection, the TLS handshake and the wait for the response headers, but not the reading of the response body,
+// so large repository archives can be downloaded as long as the server keeps sending them.
+func newGitHttpTransport(baseTransport *http.Transport, timeout, keepAlive time.Duration) *http.Transport {
+	transport := baseTransport.Clone()
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if timeout > 0 {
+		dialer.Timeout = timeout
+		transport.TLSHandshakeTimeout = timeout
+		transport.ResponseHeaderTimeout = timeout
+	}
+	if keepAlive > 0 {
+		dialer.KeepAlive = keepAlive
+		transport.IdleConnTimeout = keepAlive
+	}
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func getDurationInSecondsEnv(envKey string) (time.Duration, error) {
+	seconds, err := getIntEnv(envKey, 0)
+	if err != nil {
+		return 0, err
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("the value of the %s environment variable must not be negative, provided: %d", envKey, seconds)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
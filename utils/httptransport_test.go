@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	httptransport "github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGitHttpTransport(t *testing.T) {
+	baseTransport := &http.Transport{TLSHandshakeTimeout: 10 * time.Second, IdleConnTimeout: 90 * time.Second, MaxIdleConns: 100}
+
+	transport := newGitHttpTransport(baseTransport, 2*time.Minute, 0)
+	assert.Equal(t, 2*time.Minute, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 2*time.Minute, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+	assert.Equal(t, 100, transport.MaxIdleConns)
+	assert.NotNil(t, transport.DialContext)
+
+	transport = newGitHttpTransport(baseTransport, 0, 15*time.Second)
+	assert.Equal(t, 10*time.Second, transport.TLSHandshakeTimeout)
+	assert.Zero(t, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 15*time.Second, transport.IdleConnTimeout)
+
+	// The base transport isn't modified
+	assert.Zero(t, baseTransport.ResponseHeaderTimeout)
+	assert.Nil(t, baseTransport.DialContext)
+}
+
+func TestConfigureVcsHttpTransport(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	defer func() {
+		http.DefaultTransport = defaultTransport
+		vcsHttpTransport = nil
+	}()
+
+	// Without a configured transport, the Git clients get the transport of the run
+	assert.NotNil(t, newVcsHttpClient(0).Transport)
+
+	// Without settings, the Git provider transport only tracks the rate limits
+	assert.NoError(t, configureVcsHttpTransport())
+	assert.Same(t, vcsHttpTransport, http.DefaultTransport)
+	assert.Same(t, vcsHttpTransport, newVcsHttpClient(time.Minute).Transport)
+	rateLimitTracking, ok := vcsHttpTransport.(*rateLimitTransport)
+	require.True(t, ok)
+	transport, ok := rateLimitTracking.base.(*http.Transport)
+	require.True(t, ok)
+	assert.Zero(t, transport.ResponseHeaderTimeout)
+
+	SetEnvAndAssert(t, map[string]string{GitHttpTimeoutEnv: "300", GitHttpKeepAliveEnv: "60"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	assert.NoError(t, configureVcsHttpTransport())
+	transport, ok = vcsHttpTransport.(*rateLimitTransport).base.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Minute, transport.ResponseHeaderTimeout)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	// The other clients of the run don't get the Git HTTP settings
+	assert.Zero(t, httptransport.New().ResponseHeaderTimeout)
+
+	SetEnvAndAssert(t, map[string]string{GitHttpTimeoutEnv: "-1"})
+	assert.ErrorContains(t, configureVcsHttpTransport(), GitHttpTimeoutEnv)
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayutils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+const (
+	ignoreRulesApiUrl = "api/v1/ignore_rules"
+	// Matches all the values of an ignore filter, for example: all the vulnerabilities of a component
+	anyIgnoreFilterValue = "any"
+)
+
+type ignoreRulesResponse struct {
+	Data []xrayutils.IgnoreRuleBody `json:"data"`
+}
+
+// Returns the Xray ignore rules.
+// The client has no API for listing ignore rules, so they are retrieved with the Xray REST API.
+func GetXrayIgnoreRules(serverDetails *config.ServerDetails) (ignoreRules []xrayutils.IgnoreRuleBody, err error) {
+	xrayManager, err := xray.CreateXrayServiceManager(serverDetails)
+	if err != nil {
+		return
+	}
+	xrayDetails := xrayManager.Config().GetServiceDetails()
+	httpClientDetails := xrayDetails.CreateHttpClientDetails()
+	resp, body, _, err := xrayManager.Client().SendGet(clientutils.AddTrailingSlashIfNeeded(xrayDetails.GetUrl())+ignoreRulesApiUrl, true, &httpClientDetails)
+	if err != nil {
+		return
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return
+	}
+	var response ignoreRulesResponse
+	if err = errorutils.CheckError(json.Unmarshal(body, &response)); err != nil {
+		return
+	}
+	return response.Data, nil
+}
+
+// Moves the issues that match one of the given ignore rules to the ignored issues of the collection, so they aren't reported as findings.
+// Only active rules that apply to the given git repository (in the 'owner/repo' format) are considered.
+func FilterIgnoredIssues(issuesCollection *issues.ScansIssuesCollection, ignoreRules []xrayutils.IgnoreRuleBody, gitRepository string) {
+	if issuesCollection == nil {
+		return
+	}
+	rules := getApplicableIgnoreRules(ignoreRules, gitRepository)
+	if len(rules) == 0 {
+		return
+	}
+	var ignoredVulnerabilities, ignoredViolations []formats.VulnerabilityOrViolationRow
+	issuesCollection.ScaVulnerabilities, ignoredVulnerabilities = splitIgnoredRows(issuesCollection.ScaVulnerabilities, rules, isScaIssueIgnored)
+	issuesCollection.ScaViolations, ignoredViolations = splitIgnoredRows(issuesCollection.ScaViolations, rules, isScaIssueIgnored)
+	issuesCollection.IgnoredScaIssues = append(issuesCollection.IgnoredScaIssues, append(ignoredVulnerabilities, ignoredViolations...)...)
+
+	var ignoredLicenses []formats.LicenseViolationRow
+	issuesCollection.LicensesViolations, ignoredLicenses = splitIgnoredRows(issuesCollection.LicensesViolations, rules, isLicenseViolationIgnored)
+	issuesCollection.IgnoredLicensesViolations = append(issuesCollection.IgnoredLicensesViolations, ignoredLicenses...)
+
+	issuesCollection.IgnoredIacIssues = append(issuesCollection.IgnoredIacIssues, splitIgnoredSourceCodeIssues(&issuesCollection.IacVulnerabilities, &issuesCollection.IacViolations, rules, isExposureIgnoredFunc(xrayutils.IacExposureType))...)
+	issuesCollection.IgnoredSecretsIssues = append(issuesCollection.IgnoredSecretsIssues, splitIgnoredSourceCodeIssues(&issuesCollection.SecretsVulnerabilities, &issuesCollection.SecretsViolations, rules, isExposureIgnoredFunc(xrayutils.SecretExposureType))...)
+	issuesCollection.IgnoredSastIssues = append(issuesCollection.IgnoredSastIssues, splitIgnoredSourceCodeIssues(&issuesCollection.SastVulnerabilities, &issuesCollection.SastViolations, rules, isSastIssueIgnored)...)
+
+	if ignoredCount := len(issuesCollection.IgnoredScaIssues) + len(issuesCollection.IgnoredLicensesViolations) + len(issuesCollection.IgnoredIacIssues) + len(issuesCollection.IgnoredSecretsIssues) + len(issuesCollection.IgnoredSastIssues); ignoredCount > 0 {
+		log.Info(fmt.Sprintf("%d findings match Xray ignore rules, so they are not reported as issues", ignoredCount))
+	}
+}
+
+// Returns the rules that are active, and apply to the given git repository
+func getApplicableIgnoreRules(ignoreRules []xrayutils.IgnoreRuleBody, gitRepository string) (applicableRules []xrayutils.IgnoreRuleBody) {
+	now := time.Now()
+	for _, rule := range ignoreRules {
+		if rule.IsExpired || (!rule.ExpiresAt.IsZero() && rule.ExpiresAt.Before(now)) {
+			continue
+		}
+		if !matchesIgnoreFilter(rule.IgnoreFilters.GitRepositories, func(repository string) bool { return isSameGitRepository(repository, gitRepository) }) {
+			continue
+		}
+		applicableRules = append(applicableRules, rule)
+	}
+	return
+}
+
+func splitIgnoredRows[T any](rows []T, rules []xrayutils.IgnoreRuleBody, isIgnored func(T, xrayutils.IgnoreFilters) bool) (reportedRows, ignoredRows []T) {
+	for _, row := range rows {
+		if slices.ContainsFunc(rules, func(rule xrayutils.IgnoreRuleBody) bool { return isIgnored(row, rule.IgnoreFilters) }) {
+			ignoredRows = append(ignoredRows, row)
+		} else {
+			reportedRows = append(reportedRows, row)
+		}
+	}
+	return
+}
+
+func splitIgnoredSourceCodeIssues(vulnerabilities, violations *[]formats.SourceCodeRow, rules []xrayutils.IgnoreRuleBody, isIgnored func(formats.SourceCodeRow, xrayutils.IgnoreFilters) bool) []formats.SourceCodeRow {
+	var ignoredVulnerabilities, ignoredViolations []formats.SourceCodeRow
+	*vulnerabilities, ignoredVulnerabilities = splitIgnoredRows(*vulnerabilities, rules, isIgnored)
+	*violations, ignoredViolations = splitIgnoredRows(*violations, rules, isIgnored)
+	return append(ignoredVulnerabilities, ignoredViolations...)
+}
+
+// An SCA issue is ignored by rules that filter its vulnerability, CVEs or component
+func isScaIssueIgnored(issue formats.VulnerabilityOrViolationRow, filters xrayutils.IgnoreFilters) bool {
+	if len(filters.Vulnerabilities) == 0 && len(filters.CVEs) == 0 && len(filters.Components) == 0 {
+		return false
+	}
+	if len(filters.Licenses) > 0 || filters.Sast != nil || filters.Exposures != nil {
+		// The rule ignores other types of issues
+		return false
+	}
+	return isInViolationScope(issue.ViolationContext, filters) &&
+		matchesIgnoreFilter(filters.Vulnerabilities, func(vulnerability string) bool { return strings.EqualFold(vulnerability, issue.IssueId) }) &&
+		matchesIgnoreFilter(filters.CVEs, func(cve string) bool {
+			return slices.ContainsFunc(issue.Cves, func(cveRow formats.CveRow) bool { return strings.EqualFold(cve, cveRow.Id) })
+		}) &&
+		matchesComponentsFilter(filters.Components, issue.ImpactedDependencyDetails)
+}
+
+// A license violation is ignored by rules that filter its license or component
+func isLicenseViolationIgnored(violation formats.LicenseViolationRow, filters xrayutils.IgnoreFilters) bool {
+	if len(filters.Licenses) == 0 && len(filters.Components) == 0 {
+		return false
+	}
+	if len(filters.Vulnerabilities) > 0 || len(filters.CVEs) > 0 || filters.Sast != nil || filters.Exposures != nil {
+		return false
+	}
+	return isInViolationScope(violation.ViolationContext, filters) &&
+		matchesIgnoreFilter(filters.Licenses, func(license string) bool { return strings.EqualFold(license, violation.LicenseKey) }) &&
+		matchesComponentsFilter(filters.Components, violation.ImpactedDependencyDetails)
+}
+
+func isSastIssueIgnored(issue formats.SourceCodeRow, filters xrayutils.IgnoreFilters) bool {
+	sastFilter := filters.Sast
+	if sastFilter == nil || (len(sastFilter.Rule) == 0 && len(sastFilter.Fingerprint) == 0 && len(sastFilter.FilePath) == 0) {
+		return false
+	}
+	return isInViolationScope(issue.ViolationContext, filters) &&
+		matchesIgnoreFilter(sastFilter.Rule, func(rule string) bool { return rule == issue.RuleId }) &&
+		matchesIgnoreFilter(sastFilter.Fingerprint, func(fingerprint string) bool { return fingerprint == issue.Fingerprint }) &&
+		matchesIgnoreFilter(sastFilter.FilePath, func(filePath string) bool { return matchesFilePath(filePath, issue.File) })
+}
+
+// Secrets and IaC findings are ignored by exposures rules of the matching category
+func isExposureIgnoredFunc(category xrayutils.ExposureType) func(formats.SourceCodeRow, xrayutils.IgnoreFilters) bool {
+	return func(issue formats.SourceCodeRow, filters xrayutils.IgnoreFilters) bool {
+		exposuresFilter := filters.Exposures
+		if exposuresFilter == nil || (len(exposuresFilter.Categories) == 0 && len(exposuresFilter.Scanners) == 0 && len(exposuresFilter.FilePath) == 0) {
+			return false
+		}
+		return isInViolationScope(issue.ViolationContext, filters) &&
+			(len(exposuresFilter.Categories) == 0 || slices.Contains(exposuresFilter.Categories, category)) &&
+			matchesIgnoreFilter(exposuresFilter.Scanners, func(scanner string) bool { return scanner == issue.RuleId }) &&
+			matchesIgnoreFilter(exposuresFilter.FilePath, func(filePath string) bool { return matchesFilePath(filePath, issue.File) })
+	}
+}
+
+// Rules that are limited to watches or policies only apply to the violations they generated
+func isInViolationScope(violationContext formats.ViolationContext, filters xrayutils.IgnoreFilters) bool {
+	return matchesIgnoreFilter(filters.Watches, func(watch string) bool { return watch == violationContext.Watch }) &&
+		matchesIgnoreFilter(filters.Policies, func(policy string) bool { return slices.Contains(violationContext.Policies, policy) })
+}
+
+func matchesComponentsFilter(components []xrayutils.IgnoreFilterNameVersion, dependency formats.ImpactedDependencyDetails) bool {
+	if len(components) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(components, func(component xrayutils.IgnoreFilterNameVersion) bool {
+		// Component names may include the package type prefix, for example: npm://lodash
+		name := component.Name
+		if _, nameWithoutType, found := strings.Cut(name, "://"); found {
+			name = nameWithoutType
+		}
+		return name == dependency.ImpactedDependencyName && (component.Version == "" || component.Version == dependency.ImpactedDependencyVersion)
+	})
+}
+
+// An empty filter matches all the values
+func matchesIgnoreFilter(filterValues []string, matches func(string) bool) bool {
+	if len(filterValues) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(filterValues, func(value string) bool {
+		return strings.EqualFold(value, anyIgnoreFilterValue) || matches(value)
+	})
+}
+
+// The file path filter may be a glob pattern, for example: src/test/*.js
+func matchesFilePath(filePathFilter, file string) bool {
+	filePathFilter, file = strings.TrimPrefix(filePathFilter, "/"), strings.TrimPrefix(file, "/")
+	if filePathFilter == file {
+		return true
+	}
+	matched, err := path.Match(filePathFilter, file)
+	return err == nil && matched
+}
+
+// The git repository of an ignore rule may be a URL or a path, for example: https://github.com/jfrog/frogbot.git
+func isSameGitRepository(ruleRepository, gitRepository string) bool {
+	ruleRepository = strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(ruleRepository), "/"), ".git")
+	gitRepository = strings.ToLower(gitRepository)
+	return ruleRepository == gitRepository || strings.HasSuffix(ruleRepository, "/"+gitRepository)
+}
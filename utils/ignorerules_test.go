@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	xrayutils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetXrayIgnoreRules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/xray/api/v1/ignore_rules", r.URL.Path)
+		_, err := w.Write([]byte(`{"data":[{"id":"rule-1","is_expired":false,"ignore_filters":{"cves":["CVE-2021-44228"],"git_repositories":["github.com/jfrog/frogbot"]}}],"total_count":1}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	ignoreRules, err := GetXrayIgnoreRules(&config.ServerDetails{XrayUrl: server.URL + "/xray/", AccessToken: "token"})
+	require.NoError(t, err)
+	require.Len(t, ignoreRules, 1)
+	assert.Equal(t, "rule-1", ignoreRules[0].Id)
+	assert.Equal(t, []string{"CVE-2021-44228"}, ignoreRules[0].IgnoreFilters.CVEs)
+	assert.Equal(t, []string{"github.com/jfrog/frogbot"}, ignoreRules[0].IgnoreFilters.GitRepositories)
+}
+
+func TestFilterIgnoredIssues(t *testing.T) {
+	log4j := formats.VulnerabilityOrViolationRow{
+		IssueId:                   "XRAY-191789",
+		Cves:                      []formats.CveRow{{Id: "CVE-2021-44228"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "org.apache.logging.log4j:log4j-core", ImpactedDependencyVersion: "2.14.1"},
+	}
+	lodashViolation := formats.VulnerabilityOrViolationRow{
+		IssueId:                   "XRAY-1234",
+		ViolationContext:          formats.ViolationContext{Watch: "watch-1", Policies: []string{"policy-1"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"},
+	}
+	gplViolation := formats.LicenseViolationRow{
+		LicenseRow: formats.LicenseRow{LicenseKey: "GPL-3.0", ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}},
+	}
+	sastFinding := formats.SourceCodeRow{ScannerInfo: formats.ScannerInfo{RuleId: "js-insecure-random"}, Location: formats.Location{File: "src/test/random.js"}}
+	secretFinding := formats.SourceCodeRow{ScannerInfo: formats.ScannerInfo{RuleId: "REQ.SECRET.KEYS"}, Location: formats.Location{File: "config/secrets.yml"}}
+	iacFinding := formats.SourceCodeRow{ScannerInfo: formats.ScannerInfo{RuleId: "aws_s3_public"}, Location: formats.Location{File: "terraform/main.tf"}}
+
+	newIssuesCollection := func() *issues.ScansIssuesCollection {
+		return &issues.ScansIssuesCollection{
+			ScaVulnerabilities:     []formats.VulnerabilityOrViolationRow{log4j},
+			ScaViolations:          []formats.VulnerabilityOrViolationRow{lodashViolation},
+			LicensesViolations:     []formats.LicenseViolationRow{gplViolation},
+			SastVulnerabilities:    []formats.SourceCodeRow{sastFinding},
+			SecretsVulnerabilities: []formats.SourceCodeRow{secretFinding},
+			IacVulnerabilities:     []formats.SourceCodeRow{iacFinding},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		ignoreFilters   xrayutils.IgnoreFilters
+		expired         bool
+		expectedIgnored *issues.ScansIssuesCollection
+	}{
+		{
+			name:            "CVE",
+			ignoreFilters:   xrayutils.IgnoreFilters{CVEs: []string{"cve-2021-44228"}},
+			expectedIgnored: &issues.ScansIssuesCollection{IgnoredScaIssues: []formats.VulnerabilityOrViolationRow{log4j}},
+		},
+		{
+			name:          "CVE of another git repository",
+			ignoreFilters: xrayutils.IgnoreFilters{CVEs: []string{"CVE-2021-44228"}, GitRepositories: []string{"https://github.com/jfrog/other.git"}},
+		},
+		{
+			name:            "CVE of the git repository",
+			ignoreFilters:   xrayutils.IgnoreFilters{CVEs: []string{"CVE-2021-44228"}, GitRepositories: []string{"https://github.com/jfrog/frogbot.git"}},
+			expectedIgnored: &issues.ScansIssuesCollection{IgnoredScaIssues: []formats.VulnerabilityOrViolationRow{log4j}},
+		},
+		{
+			name:          "Expired rule",
+			ignoreFilters: xrayutils.IgnoreFilters{CVEs: []string{"CVE-2021-44228"}},
+			expired:       true,
+		},
+		{
+			name:          "Vulnerability of another component version",
+			ignoreFilters: xrayutils.IgnoreFilters{Vulnerabilities: []string{"any"}, Components: []xrayutils.IgnoreFilterNameVersion{{Name: "gav://org.apache.logging.log4j:log4j-core", Version: "2.17.0"}}},
+		},
+		{
+			name:            "Violation of a watch",
+			ignoreFilters:   xrayutils.IgnoreFilters{Vulnerabilities: []string{"any"}, Watches: []string{"watch-1"}},
+			expectedIgnored: &issues.ScansIssuesCollection{IgnoredScaIssues: []formats.VulnerabilityOrViolationRow{lodashViolation}},
+		},
+		{
+			name:          "Violation of another policy",
+			ignoreFilters: xrayutils.IgnoreFilters{Vulnerabilities: []string{"XRAY-1234"}, Policies: []string{"policy-2"}},
+		},
+		{
+			name:          "Component",
+			ignoreFilters: xrayutils.IgnoreFilters{Components: []xrayutils.IgnoreFilterNameVersion{{Name: "minimist"}}},
+			expectedIgnored: &issues.ScansIssuesCollection{
+				IgnoredLicensesViolations: []formats.LicenseViolationRow{gplViolation},
+			},
+		},
+		{
+			name:            "License",
+			ignoreFilters:   xrayutils.IgnoreFilters{Licenses: []string{"GPL-3.0"}},
+			expectedIgnored: &issues.ScansIssuesCollection{IgnoredLicensesViolations: []formats.LicenseViolationRow{gplViolation}},
+		},
+		{
+			name:            "SAST rule in a directory",
+			ignoreFilters:   xrayutils.IgnoreFilters{Sast: &xrayutils.SastFilterName{Rule: []string{"js-insecure-random"}, FilePath: []string{"src/test/*"}}},
+			expectedIgnored: &issues.ScansIssuesCollection{IgnoredSastIssues: []formats.SourceCodeRow{sastFinding}},
+		},
+		{
+			name:            "Secrets exposures",
+			ignoreFilters:   xrayutils.IgnoreFilters{Exposures: &xrayutils.ExposuresFilterName{Categories: []xrayutils.ExposureType{xrayutils.SecretExposureType}}},
+			expectedIgnored: &issues.ScansIssuesCollection{IgnoredSecretsIssues: []formats.SourceCodeRow{secretFinding}},
+		},
+		{
+			name:            "Exposures file",
+			ignoreFilters:   xrayutils.IgnoreFilters{Exposures: &xrayutils.ExposuresFilterName{FilePath: []string{"/terraform/main.tf"}}},
+			expectedIgnored: &issues.ScansIssuesCollection{IgnoredIacIssues: []formats.SourceCodeRow{iacFinding}},
+		},
+		{
+			name:          "Scope only rule",
+			ignoreFilters: xrayutils.IgnoreFilters{GitRepositories: []string{"github.com/jfrog/frogbot"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ignoreRule := xrayutils.IgnoreRuleBody{IgnoreRuleParams: xrayutils.IgnoreRuleParams{IgnoreFilters: tc.ignoreFilters}}
+			if tc.expired {
+				ignoreRule.ExpiresAt = time.Now().Add(-time.Hour)
+			}
+			issuesCollection := newIssuesCollection()
+			FilterIgnoredIssues(issuesCollection, []xrayutils.IgnoreRuleBody{ignoreRule}, "jfrog/frogbot")
+
+			expectedIgnored := tc.expectedIgnored
+			if expectedIgnored == nil {
+				expectedIgnored = &issues.ScansIssuesCollection{}
+			}
+			assert.ElementsMatch(t, expectedIgnored.IgnoredScaIssues, issuesCollection.IgnoredScaIssues)
+			assert.ElementsMatch(t, expectedIgnored.IgnoredLicensesViolations, issuesCollection.IgnoredLicensesViolations)
+			assert.ElementsMatch(t, expectedIgnored.IgnoredSastIssues, issuesCollection.IgnoredSastIssues)
+			assert.ElementsMatch(t, expectedIgnored.IgnoredSecretsIssues, issuesCollection.IgnoredSecretsIssues)
+			assert.ElementsMatch(t, expectedIgnored.IgnoredIacIssues, issuesCollection.IgnoredIacIssues)
+			// The ignored issues aren't reported anymore
+			assert.Equal(t, 6, issuesCollection.GetAllIssuesCount(true)+len(issuesCollection.IgnoredScaIssues)+len(issuesCollection.IgnoredLicensesViolations)+
+				len(issuesCollection.IgnoredSastIssues)+len(issuesCollection.IgnoredSecretsIssues)+len(issuesCollection.IgnoredIacIssues))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	yarnLockFile          = "yarn.lock"
+	yarnBerryConfigFile   = ".yarnrc.yml"
+	pipRequirementsFile   = "requirements.txt"
+	pipCompileHeaderToken = "pip-compile"
+)
+
+// npm and pnpm projects with a lock file are scanned without running an install command, so the dependencies are resolved from the lock file as is
+var lockFileResolutions = map[techutils.Technology]lockFileResolution{
+	techutils.Npm:  {lockFiles: []string{"package-lock.json", "npm-shrinkwrap.json"}, equivalentCommand: "npm ci"},
+	techutils.Pnpm: {lockFiles: []string{"pnpm-lock.yaml"}, equivalentCommand: "pnpm install --frozen-lockfile"},
+}
+
+type lockFileResolution struct {
+	lockFiles []string
+	// The install command that resolves the dependencies the same way
+	equivalentCommand string
+}
+
+// Returns an install command that keeps the installed dependencies consistent with the lock files of the project, or an empty string if there's no such command.
+// The command applies to all the working directories, so it is detected only when they all contain the same single technology and lock file.
+func detectLockfileInstallCommand(workDirs []string, isRecursiveScan bool, exclusions []string, requirementsFile string) (installCommand string, err error) {
+	excludePattern := fspatterns.PrepareExcludePathPattern(exclusions, clientutils.WildCardPattern, isRecursiveScan)
+	var technology techutils.Technology
+	var techDirs []string
+	for _, workDir := range workDirs {
+		var techToDirs map[techutils.Technology]map[string][]string
+		if techToDirs, err = techutils.DetectTechnologiesDescriptors(workDir, isRecursiveScan, []string{}, map[techutils.Technology][]string{}, excludePattern); err != nil {
+			return
+		}
+		for tech, dirs := range techToDirs {
+			if technology != "" && technology != tech {
+				log.Debug("Multiple technologies were detected, so an install command is not detected")
+				return "", nil
+			}
+			technology = tech
+			for dir := range dirs {
+				techDirs = append(techDirs, dir)
+			}
+		}
+	}
+	if resolution, exists := lockFileResolutions[technology]; exists {
+		resolution.logUsage(techDirs)
+		return "", nil
+	}
+	for i, dir := range techDirs {
+		var dirInstallCommand, lockFile string
+		if dirInstallCommand, lockFile, err = getLockfileInstallCommand(technology, dir, requirementsFile); err != nil || dirInstallCommand == "" {
+			return "", err
+		}
+		if i > 0 && dirInstallCommand != installCommand {
+			log.Debug("The working directories require different install commands, so an install command is not detected")
+			return "", nil
+		}
+		installCommand = dirInstallCommand
+		log.Info(fmt.Sprintf("No install command was provided. '%s' was found in '%s', so the '%s' command is used to keep the dependencies consistent with it", lockFile, dir, installCommand))
+	}
+	return
+}
+
+// Returns the lock file consistent install command of the technology in the given directory, and the lock file it is consistent with
+func getLockfileInstallCommand(technology techutils.Technology, dir, requirementsFile string) (installCommand, lockFile string, err error) {
+	switch technology {
+	case techutils.Yarn:
+		var exists bool
+		if exists, err = fileutils.IsFileExists(filepath.Join(dir, yarnLockFile), false); err != nil || !exists {
+			return
+		}
+		if exists, err = fileutils.IsFileExists(filepath.Join(dir, yarnBerryConfigFile), false); err != nil {
+			return
+		}
+		if exists {
+			// Yarn 2 and above
+			return "yarn install --immutable", yarnLockFile, nil
+		}
+		return "yarn install --frozen-lockfile", yarnLockFile, nil
+	case techutils.Pip:
+		// Requirements files compiled by pip-tools pin all the transitive dependencies, so they are installed without resolving new ones, like pip-sync does
+		if requirementsFile == "" {
+			requirementsFile = pipRequirementsFile
+		}
+		var content []byte
+		if content, err = os.ReadFile(filepath.Join(dir, requirementsFile)); err != nil {
+			if os.IsNotExist(err) {
+				err = nil
+			}
+			return "", "", errorutils.CheckError(err)
+		}
+		if strings.Contains(string(content), pipCompileHeaderToken) {
+			return "pip install --no-deps", requirementsFile, nil
+		}
+	}
+	return
+}
+
+func (lfr lockFileResolution) logUsage(dirs []string) {
+	for _, dir := range dirs {
+		for _, lockFile := range lfr.lockFiles {
+			if exists, err := fileutils.IsFileExists(filepath.Join(dir, lockFile), false); err == nil && exists {
+				log.Info(fmt.Sprintf("No install command was provided. '%s' was found in '%s', so the dependencies are resolved from it without modifying it, like '%s' does", lockFile, dir, lfr.equivalentCommand))
+				break
+			}
+		}
+	}
+}
+
+// Returns the install command of the audit, and the pip requirements file it uses.
+// When the detection is enabled, no install command was provided and the installation isn't skipped, a lock file consistent install command is detected.
+// The detection is opt-in, since it changes the install commands of the projects that didn't configure one.
+func (sc *ScanDetails) getInstallCommand(workDirs []string) (installCommandName string, installCommandArgs []string, requirementsFile string) {
+	if sc.InstallCommandName != "" || !sc.DetectInstallCommand || sc.skipAutoInstall {
+		return sc.InstallCommandName, sc.InstallCommandArgs, sc.PipRequirementsFile
+	}
+	installCommand, err := detectLockfileInstallCommand(workDirs, sc.IsRecursiveScan, sc.PathExclusions, sc.PipRequirementsFile)
+	if err != nil {
+		log.Debug("Failed to detect an install command:", err.Error())
+	}
+	if installCommand == "" {
+		return "", nil, sc.PipRequirementsFile
+	}
+	parts := strings.Fields(installCommand)
+	installCommandName, installCommandArgs, requirementsFile = parts[0], parts[1:], sc.PipRequirementsFile
+	if installCommandName == techutils.Pip.String() && requirementsFile == "" {
+		requirementsFile = pipRequirementsFile
+	}
+	return
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLockfileInstallCommand(t *testing.T) {
+	testCases := []struct {
+		name            string
+		files           map[string]string
+		expectedCommand string
+	}{
+		{
+			name:            "Yarn v1 lock file",
+			files:           map[string]string{"package.json": "{}", "yarn.lock": ""},
+			expectedCommand: "yarn install --frozen-lockfile",
+		},
+		{
+			name:            "Yarn v2 lock file",
+			files:           map[string]string{"package.json": "{}", "yarn.lock": "", ".yarnrc.yml": ""},
+			expectedCommand: "yarn install --immutable",
+		},
+		{
+			name:            "Requirements compiled by pip-tools",
+			files:           map[string]string{"requirements.txt": "#\n# This file is autogenerated by pip-compile\n#\nrequests==2.31.0\n"},
+			expectedCommand: "pip install --no-deps",
+		},
+		{
+			name:  "Requirements without pinned dependencies",
+			files: map[string]string{"requirements.txt": "requests\n"},
+		},
+		{
+			name:  "npm lock file",
+			files: map[string]string{"package.json": "{}", "package-lock.json": "{}"},
+		},
+		{
+			name:  "pnpm lock file",
+			files: map[string]string{"package.json": "{}", "pnpm-lock.yaml": "lockfileVersion: '9.0'\n"},
+		},
+		{
+			name:  "Multiple technologies",
+			files: map[string]string{"package.json": "{}", "yarn.lock": "", filepath.Join("python", "requirements.txt"): "# pip-compile\n"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			for path, content := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(projectDir, path)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(projectDir, path), []byte(content), 0644))
+			}
+			installCommand, err := detectLockfileInstallCommand([]string{projectDir}, true, nil, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCommand, installCommand)
+		})
+	}
+}
+
+func TestGetInstallCommand(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "requirements.txt"), []byte("# pip-compile\n"), 0644))
+
+	scanDetails := &ScanDetails{Project: &Project{DetectInstallCommand: true}}
+	name, args, requirementsFile := scanDetails.getInstallCommand([]string{projectDir})
+	assert.Equal(t, "pip", name)
+	assert.Equal(t, []string{"install", "--no-deps"}, args)
+	assert.Equal(t, "requirements.txt", requirementsFile)
+
+	// A provided install command is used as is
+	scanDetails.Project = &Project{InstallCommandName: "pip", InstallCommandArgs: []string{"install", "."}}
+	name, args, requirementsFile = scanDetails.getInstallCommand([]string{projectDir})
+	assert.Equal(t, "pip", name)
+	assert.Equal(t, []string{"install", "."}, args)
+	assert.Empty(t, requirementsFile)
+
+	// No install command is detected when the installation is skipped
+	scanDetails = &ScanDetails{Project: &Project{DetectInstallCommand: true}, skipAutoInstall: true}
+	name, args, _ = scanDetails.getInstallCommand([]string{projectDir})
+	assert.Empty(t, name)
+	assert.Empty(t, args)
+
+	// The detection is opt-in
+	scanDetails = &ScanDetails{Project: &Project{}}
+	name, args, requirementsFile = scanDetails.getInstallCommand([]string{projectDir})
+	assert.Empty(t, name)
+	assert.Empty(t, args)
+	assert.Empty(t, requirementsFile)
+}
@@ -0,0 +1,94 @@
+package issues
+
+import (
+	"slices"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+)
+
+// Merges the SCA issues that are found in more than one working directory, so each CVE of a package is reported once.
+// The merged issue lists the direct dependencies that bring the package into each of the working directories.
+func (ic *ScansIssuesCollection) DeduplicateScaIssues() {
+	ic.ScaVulnerabilities = DeduplicateScaRows(ic.ScaVulnerabilities)
+	ic.ScaViolations = DeduplicateScaRows(ic.ScaViolations)
+	ic.FixedScaIssues = DeduplicateScaRows(ic.FixedScaIssues)
+}
+
+// Merges the rows of the same CVEs on the same package version, keeping the order of their first occurrence.
+// Violations are merged only if the same watch reports them.
+func DeduplicateScaRows(rows []formats.VulnerabilityOrViolationRow) []formats.VulnerabilityOrViolationRow {
+	if len(rows) < 2 {
+		return rows
+	}
+	var deduplicated []formats.VulnerabilityOrViolationRow
+	indexByKey := map[string]int{}
+	for _, row := range rows {
+		key := GetScaFindingId(row) + "|" + row.Watch
+		index, exists := indexByKey[key]
+		if !exists {
+			indexByKey[key] = len(deduplicated)
+			deduplicated = append(deduplicated, row)
+			continue
+		}
+		deduplicated[index] = mergeScaRows(deduplicated[index], row)
+	}
+	return deduplicated
+}
+
+func mergeScaRows(row, other formats.VulnerabilityOrViolationRow) formats.VulnerabilityOrViolationRow {
+	// The rows share their slices with the rows they were merged from, so the slices are cloned before they are extended
+	row.Components = appendMissingComponents(slices.Clone(row.Components), other.Components...)
+	row.ImpactPaths, row.FixedVersions = slices.Clone(row.ImpactPaths), slices.Clone(row.FixedVersions)
+	for _, impactPath := range other.ImpactPaths {
+		if !slices.ContainsFunc(row.ImpactPaths, func(existing []formats.ComponentRow) bool {
+			return slices.EqualFunc(existing, impactPath, isSameComponent)
+		}) {
+			row.ImpactPaths = append(row.ImpactPaths, impactPath)
+		}
+	}
+	for _, fixedVersion := range other.FixedVersions {
+		if !slices.Contains(row.FixedVersions, fixedVersion) {
+			row.FixedVersions = append(row.FixedVersions, fixedVersion)
+		}
+	}
+	// The merged row keeps the applicability of the highest risk among the working directories
+	if getApplicabilityRisk(other.Applicable) > getApplicabilityRisk(row.Applicable) {
+		row.Applicable = other.Applicable
+		row.Cves = other.Cves
+	}
+	return row
+}
+
+// Ranks the applicability statuses by their risk: an applicable CVE is the highest risk, and a CVE that isn't applicable is the lowest.
+// The CVEs whose applicability is unknown, because it's undetermined, not covered or not scanned, may be applicable.
+func getApplicabilityRisk(applicable string) int {
+	switch jasutils.ConvertToApplicabilityStatus(applicable) {
+	case jasutils.Applicable:
+		return 2
+	case jasutils.NotApplicable:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func appendMissingComponents(components []formats.ComponentRow, others ...formats.ComponentRow) []formats.ComponentRow {
+	for _, other := range others {
+		if !slices.ContainsFunc(components, func(component formats.ComponentRow) bool { return isSameComponent(component, other) }) {
+			components = append(components, other)
+		}
+	}
+	return components
+}
+
+func isSameComponent(component, other formats.ComponentRow) bool {
+	return component.Name == other.Name && component.Version == other.Version && getComponentFile(component) == getComponentFile(other)
+}
+
+func getComponentFile(component formats.ComponentRow) string {
+	if component.Location == nil {
+		return ""
+	}
+	return component.Location.File
+}
@@ -0,0 +1,94 @@
+package issues
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createScaRow(cve, workingDir, directDependency, applicable string, fixedVersions ...string) formats.VulnerabilityOrViolationRow {
+	component := formats.ComponentRow{Name: directDependency, Version: "1.0.0", Location: &formats.Location{File: workingDir}}
+	return formats.VulnerabilityOrViolationRow{
+		Cves:          []formats.CveRow{{Id: cve}},
+		Applicable:    applicable,
+		FixedVersions: fixedVersions,
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			ImpactedDependencyName:    "minimist",
+			ImpactedDependencyVersion: "1.2.5",
+			Components:                []formats.ComponentRow{component},
+		},
+		ImpactPaths: [][]formats.ComponentRow{{component, {Name: "minimist", Version: "1.2.5"}}},
+	}
+}
+
+func TestDeduplicateScaRows(t *testing.T) {
+	frontend := createScaRow("CVE-2021-44906", "frontend", "mkdirp", jasutils.NotApplicable.String(), "1.2.6")
+	backend := createScaRow("CVE-2021-44906", "backend", "mkdirp", jasutils.Applicable.String(), "1.2.6", "0.2.4")
+	otherCve := createScaRow("CVE-2020-7598", "backend", "mkdirp", jasutils.NotApplicable.String())
+
+	rows := DeduplicateScaRows([]formats.VulnerabilityOrViolationRow{frontend, otherCve, backend, frontend})
+	if assert.Len(t, rows, 2) {
+		merged := rows[0]
+		assert.Equal(t, []string{"frontend", "backend"}, []string{merged.Components[0].Location.File, merged.Components[1].Location.File})
+		assert.Len(t, merged.ImpactPaths, 2)
+		assert.Equal(t, []string{"1.2.6", "0.2.4"}, merged.FixedVersions)
+		// Applicable in one of the working directories
+		assert.Equal(t, jasutils.Applicable.String(), merged.Applicable)
+		assert.Equal(t, otherCve, rows[1])
+	}
+	// The merged rows are left untouched
+	assert.Len(t, frontend.Components, 1)
+	assert.Equal(t, []string{"1.2.6"}, frontend.FixedVersions)
+
+	// Violations of different watches aren't merged
+	frontend.Watch, backend.Watch = "watch-1", "watch-2"
+	assert.Len(t, DeduplicateScaRows([]formats.VulnerabilityOrViolationRow{frontend, backend}), 2)
+}
+
+func TestDeduplicateScaRowsApplicability(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statuses   []jasutils.ApplicabilityStatus
+		expected   jasutils.ApplicabilityStatus
+		expectedAt int
+	}{
+		{name: "Applicable over undetermined", statuses: []jasutils.ApplicabilityStatus{jasutils.ApplicabilityUndetermined, jasutils.Applicable, jasutils.NotApplicable}, expected: jasutils.Applicable, expectedAt: 1},
+		{name: "Undetermined over not applicable", statuses: []jasutils.ApplicabilityStatus{jasutils.NotApplicable, jasutils.ApplicabilityUndetermined}, expected: jasutils.ApplicabilityUndetermined, expectedAt: 1},
+		{name: "Not covered over not applicable", statuses: []jasutils.ApplicabilityStatus{jasutils.NotApplicable, jasutils.NotCovered}, expected: jasutils.NotCovered, expectedAt: 1},
+		{name: "First of the same risk", statuses: []jasutils.ApplicabilityStatus{jasutils.NotCovered, jasutils.ApplicabilityUndetermined, jasutils.NotApplicable}, expected: jasutils.NotCovered, expectedAt: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var rows []formats.VulnerabilityOrViolationRow
+			for i, status := range tc.statuses {
+				row := createScaRow("CVE-2021-44906", string(rune('a'+i)), "mkdirp", status.String())
+				// The CVE details of the row with the highest risk are kept
+				row.Cves[0].Applicability = &formats.Applicability{Status: status.String()}
+				rows = append(rows, row)
+			}
+			merged := DeduplicateScaRows(rows)
+			if assert.Len(t, merged, 1) {
+				assert.Equal(t, tc.expected.String(), merged[0].Applicable)
+				assert.Equal(t, rows[tc.expectedAt].Cves, merged[0].Cves)
+			}
+		})
+	}
+}
+
+func TestDeduplicateScaIssues(t *testing.T) {
+	frontend := createScaRow("CVE-2021-44906", "frontend", "mkdirp", jasutils.NotApplicable.String())
+	backend := createScaRow("CVE-2021-44906", "backend", "mkdirp", jasutils.NotApplicable.String())
+	issuesCollection := &ScansIssuesCollection{
+		ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{frontend, backend},
+		ScaViolations:      []formats.VulnerabilityOrViolationRow{frontend},
+		FixedScaIssues:     []formats.VulnerabilityOrViolationRow{frontend, frontend},
+	}
+	issuesCollection.DeduplicateScaIssues()
+	assert.Len(t, issuesCollection.ScaVulnerabilities, 1)
+	assert.Len(t, issuesCollection.ScaVulnerabilities[0].Components, 2)
+	assert.Len(t, issuesCollection.ScaViolations, 1)
+	assert.Len(t, issuesCollection.FixedScaIssues, 1)
+	assert.Len(t, issuesCollection.FixedScaIssues[0].Components, 1)
+}
@@ -0,0 +1,40 @@
+package issues
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+)
+
+// Finding IDs are deterministic, so the same finding gets the same ID across scans.
+// They allow external systems to reference findings unambiguously.
+const findingIdLength = 16
+
+// Returns the finding ID of an SCA issue, calculated from its CVEs (or Xray issue ID) and the impacted dependency.
+// The ID matches the one calculated for the SCA rule of the SARIF report.
+func GetScaFindingId(issue formats.VulnerabilityOrViolationRow) string {
+	return GetScaRuleFindingId(results.GetScaIssueId(issue.ImpactedDependencyName, issue.ImpactedDependencyVersion, results.GetIssueIdentifier(issue.Cves, issue.IssueId, "_")))
+}
+
+// Returns the finding ID of an SCA SARIF rule, which identifies the issue and the impacted dependency
+func GetScaRuleFindingId(scaRuleId string) string {
+	return getFindingId(scaRuleId)
+}
+
+// Returns the finding ID of a source code issue (IaC, Secrets and SAST), calculated from its rule and location.
+// The line numbers are not part of the ID, so it remains the same when the code above the finding changes.
+func GetSourceCodeFindingId(issue formats.SourceCodeRow) string {
+	return GetJasFindingId(issue.RuleId, issue.File, issue.Snippet)
+}
+
+func GetJasFindingId(ruleId, file, snippet string) string {
+	return getFindingId(ruleId, file, snippet)
+}
+
+func getFindingId(values ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(values, "|")))
+	return hex.EncodeToString(hash[:])[:findingIdLength]
+}
@@ -0,0 +1,48 @@
+package issues
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScaFindingId(t *testing.T) {
+	issue := formats.VulnerabilityOrViolationRow{
+		IssueId: "XRAY-1",
+		Cves:    []formats.CveRow{{Id: "CVE-2023-1234"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			ImpactedDependencyName:    "lodash",
+			ImpactedDependencyVersion: "4.17.0",
+		},
+	}
+	findingId := GetScaFindingId(issue)
+	assert.Len(t, findingId, findingIdLength)
+	// Stable across calls
+	assert.Equal(t, findingId, GetScaFindingId(issue))
+	// Matches the ID of the SARIF rule of the same issue
+	assert.Equal(t, findingId, GetScaRuleFindingId("CVE-2023-1234_lodash_4.17.0"))
+
+	otherVersion := issue
+	otherVersion.ImpactedDependencyVersion = "4.17.1"
+	assert.NotEqual(t, findingId, GetScaFindingId(otherVersion))
+}
+
+func TestGetSourceCodeFindingId(t *testing.T) {
+	issue := formats.SourceCodeRow{
+		ScannerInfo: formats.ScannerInfo{RuleId: "sast-rule"},
+		Location:    formats.Location{File: "index.js", StartLine: 1, EndLine: 2, Snippet: "eval(input)"},
+	}
+	findingId := GetSourceCodeFindingId(issue)
+	assert.Len(t, findingId, findingIdLength)
+	assert.Equal(t, findingId, GetJasFindingId("sast-rule", "index.js", "eval(input)"))
+
+	// Moving the finding to other lines keeps its ID
+	movedIssue := issue
+	movedIssue.StartLine, movedIssue.EndLine = 10, 11
+	assert.Equal(t, findingId, GetSourceCodeFindingId(movedIssue))
+
+	otherFile := issue
+	otherFile.File = "main.js"
+	assert.NotEqual(t, findingId, GetSourceCodeFindingId(otherFile))
+}
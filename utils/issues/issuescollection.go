@@ -25,6 +25,177 @@ type ScansIssuesCollection struct {
 
 	SastViolations      []formats.SourceCodeRow
 	SastVulnerabilities []formats.SourceCodeRow
+
+	// Sca issues that exist in the target branch and were removed by the pull request
+	FixedScaIssues []formats.VulnerabilityOrViolationRow
+
+	// Issues that match an Xray ignore rule, and are therefore not reported as findings
+	IgnoredScaIssues          []formats.VulnerabilityOrViolationRow
+	IgnoredLicensesViolations []formats.LicenseViolationRow
+	IgnoredIacIssues          []formats.SourceCodeRow
+	IgnoredSecretsIssues      []formats.SourceCodeRow
+	IgnoredSastIssues         []formats.SourceCodeRow
+
+	// Direct dependencies of internal namespaces that may be replaced by public packages
+	DependencyConfusionRisks []DependencyConfusionRisk
+
+	// Vulnerabilities of the base images and the OS packages of Dockerfiles
+	DockerImageVulnerabilities []DockerImageVulnerability
+
+	// Vulnerabilities of the packages that Conda environment files and the package lists of apt and apk pin
+	SystemPackageVulnerabilities []SystemPackageVulnerability
+
+	// Dependencies that the curation policies of Artifactory block
+	CurationBlockedPackages []CurationBlockedPackage
+
+	// Vulnerable dependencies without a fixed version, with the alternative packages that may replace them
+	AlternativePackages []AlternativePackageSuggestion
+
+	// Vulnerable and mutable references of the actions that the GitHub Actions workflows use
+	GitHubActionIssues []GitHubActionIssue
+
+	// The findings of third-party scanners, read from the SARIF files they produced in the CI run
+	ExternalScannerIssues []ExternalScannerIssue
+
+	// The licenses of the dependencies, collected when the rules of the repository policy file require them.
+	// When scanning a pull request, only the dependencies that the pull request adds are listed.
+	Licenses []formats.LicenseRow
+	// The findings and dependencies that break the blocking rules of the repository policy file
+	PolicyRuleViolations []PolicyRuleViolation
+	// The owners of the paths that the pull request adds Critical or High findings to, mentioned in the summary comment
+	SecurityChampions []string
+}
+
+// PolicyRuleViolation is a finding or a dependency that breaks a blocking rule of the repository policy file
+type PolicyRuleViolation struct {
+	// The name of the broken rule, as it appears in the policy file
+	Rule string
+	// The finding or the dependency that breaks the rule
+	Subject string
+	// Why the rule is broken
+	Reason string
+}
+
+// DependencyConfusionRisk is a direct dependency of an internal namespace, with a higher version in its public registry.
+// Package managers that resolve from the public registry may install the public package instead of the internal one.
+type DependencyConfusionRisk struct {
+	// The package type of the dependency, such as 'npm' or 'Maven'
+	PackageType string
+	PackageName string
+	Version     string
+	// The configured internal namespace that the package belongs to
+	Namespace     string
+	PublicVersion string
+	// The page of the package in the public registry
+	PublicUrl string
+}
+
+// DockerImageVulnerability is a vulnerability of a base image, or of an OS package that a Dockerfile installs explicitly
+type DockerImageVulnerability struct {
+	// The path of the Dockerfile, relative to the root of the repository, and the line of the instruction that uses the component
+	Dockerfile string
+	Line       int
+	// 'Base Image' or 'OS Package'
+	ComponentType string
+	Name          string
+	Version       string
+	Severity      string
+	IssueId       string
+	Cves          []string
+	FixedVersions []string
+}
+
+// SystemPackageVulnerability is a vulnerability of a package that a Conda environment file or an OS package list pins
+type SystemPackageVulnerability struct {
+	// The path of the package list, relative to the root of the repository, and the line of the package
+	File string
+	Line int
+	// 'Conda', 'PyPI', 'Debian' or 'Alpine'
+	PackageType   string
+	Name          string
+	Version       string
+	Severity      string
+	IssueId       string
+	Cves          []string
+	FixedVersions []string
+}
+
+// CurationBlockedPackage is a dependency that the curation policies of Artifactory block, so it can't be installed from Artifactory
+type CurationBlockedPackage struct {
+	// The package type of the dependency, such as 'npm' or 'Maven'
+	PackageType string
+	PackageName string
+	Version     string
+	// The direct dependency that brings in the blocked package, empty if the blocked package is a direct dependency
+	ParentName    string
+	ParentVersion string
+	// Why the package is blocked, such as 'Policy violations'
+	BlockingReason string
+	Policies       []CurationPolicy
+}
+
+// CurationPolicy is a curation policy that blocks a package, with the condition that the package violates
+type CurationPolicy struct {
+	Name           string
+	Condition      string
+	Explanation    string
+	Recommendation string
+}
+
+// AlternativePackageSuggestion is a vulnerable dependency without a fixed version, so it can't be fixed by upgrading it,
+// with the maintained forks or the alternative packages that may replace it
+type AlternativePackageSuggestion struct {
+	// The package type of the dependency, such as 'npm' or 'Maven'
+	PackageType string
+	PackageName string
+	Version     string
+	// The highest severity of the vulnerabilities of the dependency
+	Severity string
+	// The CVEs of the vulnerabilities, or their Xray issue IDs if they have no CVEs
+	IssueIds     []string
+	Alternatives []PackageAlternative
+	// The remediation steps of the JFrog research of the vulnerabilities
+	Remediations []string
+}
+
+// PackageAlternative is a maintained fork or an alternative package of the alternative packages file of the repository
+type PackageAlternative struct {
+	Name string
+	Url  string
+	// How the alternative differs, or how to migrate to it
+	Note string
+}
+
+// GitHubActionIssue is an action of a GitHub Actions workflow that is used in a vulnerable version, or by a mutable reference such as a tag or a branch
+type GitHubActionIssue struct {
+	// The path of the workflow, relative to the root of the repository, and the line of the step that uses the action
+	Workflow string
+	Line     int
+	// 'Vulnerable Version' or 'Mutable Reference'
+	Type string
+	// The action, such as 'actions/checkout', and its reference in the workflow
+	Action string
+	Ref    string
+	// The version of the reference. The version of a commit SHA is read from the comment that follows it.
+	Version string
+	// The severity and the IDs of the advisory of a vulnerable version. A mutable reference is a Low severity issue.
+	Severity string
+	IssueId  string
+	Cves     []string
+	// The version that fixes the vulnerability, or the commit SHA that pins the mutable reference
+	FixedVersion string
+}
+
+// ExternalScannerIssue is a finding of a third-party scanner, such as Checkov, read from its SARIF results
+type ExternalScannerIssue struct {
+	// The name of the tool that produced the finding
+	Scanner string
+	// The path of the file, relative to the root of the repository, and the line of the finding
+	File     string
+	Line     int
+	RuleId   string
+	Severity string
+	Message  string
 }
 
 // General methods
@@ -66,6 +237,61 @@ func (ic *ScansIssuesCollection) Append(issues *ScansIssuesCollection) {
 	if len(issues.IacViolations) > 0 {
 		ic.IacViolations = append(ic.IacViolations, issues.IacViolations...)
 	}
+	// Fixed
+	if len(issues.FixedScaIssues) > 0 {
+		ic.FixedScaIssues = append(ic.FixedScaIssues, issues.FixedScaIssues...)
+	}
+	// Ignored
+	if len(issues.IgnoredScaIssues) > 0 {
+		ic.IgnoredScaIssues = append(ic.IgnoredScaIssues, issues.IgnoredScaIssues...)
+	}
+	if len(issues.IgnoredLicensesViolations) > 0 {
+		ic.IgnoredLicensesViolations = append(ic.IgnoredLicensesViolations, issues.IgnoredLicensesViolations...)
+	}
+	if len(issues.IgnoredIacIssues) > 0 {
+		ic.IgnoredIacIssues = append(ic.IgnoredIacIssues, issues.IgnoredIacIssues...)
+	}
+	if len(issues.IgnoredSecretsIssues) > 0 {
+		ic.IgnoredSecretsIssues = append(ic.IgnoredSecretsIssues, issues.IgnoredSecretsIssues...)
+	}
+	if len(issues.IgnoredSastIssues) > 0 {
+		ic.IgnoredSastIssues = append(ic.IgnoredSastIssues, issues.IgnoredSastIssues...)
+	}
+	// Dependency confusion
+	if len(issues.DependencyConfusionRisks) > 0 {
+		ic.DependencyConfusionRisks = append(ic.DependencyConfusionRisks, issues.DependencyConfusionRisks...)
+	}
+	// Docker images
+	if len(issues.DockerImageVulnerabilities) > 0 {
+		ic.DockerImageVulnerabilities = append(ic.DockerImageVulnerabilities, issues.DockerImageVulnerabilities...)
+	}
+	// System packages
+	if len(issues.SystemPackageVulnerabilities) > 0 {
+		ic.SystemPackageVulnerabilities = append(ic.SystemPackageVulnerabilities, issues.SystemPackageVulnerabilities...)
+	}
+	// Curation
+	if len(issues.CurationBlockedPackages) > 0 {
+		ic.CurationBlockedPackages = append(ic.CurationBlockedPackages, issues.CurationBlockedPackages...)
+	}
+	// Alternative packages
+	if len(issues.AlternativePackages) > 0 {
+		ic.AlternativePackages = append(ic.AlternativePackages, issues.AlternativePackages...)
+	}
+	// GitHub Actions
+	if len(issues.GitHubActionIssues) > 0 {
+		ic.GitHubActionIssues = append(ic.GitHubActionIssues, issues.GitHubActionIssues...)
+	}
+	// External scanners
+	if len(issues.ExternalScannerIssues) > 0 {
+		ic.ExternalScannerIssues = append(ic.ExternalScannerIssues, issues.ExternalScannerIssues...)
+	}
+	// Policy file
+	if len(issues.Licenses) > 0 {
+		ic.Licenses = append(ic.Licenses, issues.Licenses...)
+	}
+	if len(issues.PolicyRuleViolations) > 0 {
+		ic.PolicyRuleViolations = append(ic.PolicyRuleViolations, issues.PolicyRuleViolations...)
+	}
 }
 
 func (ic *ScansIssuesCollection) AppendStatus(scanStatus formats.ScanStatus) {
@@ -189,10 +415,63 @@ func (ic *ScansIssuesCollection) IssuesExists(includeSecrets bool) bool {
 	return ic.ScaIssuesExists() || ic.IacIssuesExists() || ic.SastIssuesExists() || (includeSecrets && ic.SecretsIssuesExists())
 }
 
+func (ic *ScansIssuesCollection) FixedIssuesExists() bool {
+	return len(ic.FixedScaIssues) > 0
+}
+
+func (ic *ScansIssuesCollection) DependencyConfusionRisksExists() bool {
+	return len(ic.DependencyConfusionRisks) > 0
+}
+
+func (ic *ScansIssuesCollection) DockerImageVulnerabilitiesExists() bool {
+	return len(ic.DockerImageVulnerabilities) > 0
+}
+
+func (ic *ScansIssuesCollection) SystemPackageVulnerabilitiesExists() bool {
+	return len(ic.SystemPackageVulnerabilities) > 0
+}
+
+func (ic *ScansIssuesCollection) CurationBlockedPackagesExists() bool {
+	return len(ic.CurationBlockedPackages) > 0
+}
+
+func (ic *ScansIssuesCollection) AlternativePackagesExists() bool {
+	return len(ic.AlternativePackages) > 0
+}
+
+func (ic *ScansIssuesCollection) GitHubActionIssuesExists() bool {
+	return len(ic.GitHubActionIssues) > 0
+}
+
+func (ic *ScansIssuesCollection) ExternalScannerIssuesExists() bool {
+	return len(ic.ExternalScannerIssues) > 0
+}
+
+func (ic *ScansIssuesCollection) PolicyRuleViolationsExists() bool {
+	return len(ic.PolicyRuleViolations) > 0
+}
+
+func (ic *ScansIssuesCollection) IgnoredIssuesExists(includeSecrets bool) bool {
+	return len(ic.IgnoredScaIssues) > 0 || len(ic.IgnoredLicensesViolations) > 0 || len(ic.IgnoredIacIssues) > 0 || len(ic.IgnoredSastIssues) > 0 || (includeSecrets && len(ic.IgnoredSecretsIssues) > 0)
+}
+
 func (ic *ScansIssuesCollection) ScaIssuesExists() bool {
 	return len(ic.ScaVulnerabilities) > 0 || len(ic.ScaViolations) > 0 || len(ic.LicensesViolations) > 0
 }
 
+// Returns true if one of the SCA vulnerabilities or violations may be applicable, that is, the contextual analysis didn't find it not applicable.
+// The issues that the contextual analysis couldn't determine, doesn't cover or didn't run on are treated as applicable.
+func (ic *ScansIssuesCollection) PossiblyApplicableScaIssuesExists() bool {
+	for _, rows := range [][]formats.VulnerabilityOrViolationRow{ic.ScaVulnerabilities, ic.ScaViolations} {
+		for _, row := range rows {
+			if row.Applicable != jasutils.NotApplicable.String() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (ic *ScansIssuesCollection) IacIssuesExists() bool {
 	return len(ic.IacVulnerabilities) > 0 || len(ic.IacViolations) > 0
 }
@@ -217,6 +496,7 @@ type ApplicableEvidences struct {
 	CveSummary         string
 	ImpactedDependency string
 	Remediation        string
+	FindingId          string
 }
 
 func toApplicableEvidences(issue formats.VulnerabilityOrViolationRow, cve formats.CveRow, evidence formats.Evidence) ApplicableEvidences {
@@ -232,6 +512,7 @@ func toApplicableEvidences(issue formats.VulnerabilityOrViolationRow, cve format
 		CveSummary:         issue.Summary,
 		ImpactedDependency: results.GetDependencyId(issue.ImpactedDependencyName, issue.ImpactedDependencyVersion),
 		Remediation:        remediation,
+		FindingId:          GetScaFindingId(issue),
 	}
 }
 
@@ -0,0 +1,192 @@
+package mergeconflicts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// The status of the files that conflict with the destination branch, in the diff stats of Bitbucket Cloud pull requests
+const bitbucketCloudConflictStatus = "merge conflict"
+
+// Checker checks whether the pull requests of a repository conflict with their target branches, or are behind them.
+// The Git clients don't expose the mergeability of pull requests, so it's requested from the API of the Git provider.
+type Checker interface {
+	HasConflicts(pullRequestId int64) (bool, error)
+	// Returns the number of commits of the target branch of the pull request that its source branch doesn't have.
+	// The Git providers that list the commits to count them return the limit at most.
+	BehindBy(pullRequest *vcsclient.PullRequestInfo, limit int) (int, error)
+}
+
+// Returns the merge conflicts checker of the Git provider
+func NewChecker(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName string) (Checker, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	repositoryUrl := client.RepositoryUrl(repoOwner, repoName)
+	switch provider {
+	case vcsutils.GitHub:
+		return &gitHubChecker{client: client, repositoryUrl: repositoryUrl}, nil
+	case vcsutils.GitLab:
+		return &gitLabChecker{client: client, mergeRequestsUrl: repositoryUrl + "/merge_requests"}, nil
+	case vcsutils.BitbucketServer:
+		return &bitbucketServerChecker{client: client, repositoryUrl: repositoryUrl}, nil
+	case vcsutils.BitbucketCloud:
+		return &bitbucketCloudChecker{client: client, repositoryUrl: repositoryUrl}, nil
+	case vcsutils.AzureRepos:
+		return &azureReposChecker{client: client, repositoryUrl: repositoryUrl}, nil
+	default:
+		return nil, fmt.Errorf("checking the merge conflicts of pull requests isn't supported for %s", provider.String())
+	}
+}
+
+type gitHubChecker struct {
+	client        *vcsapi.Client
+	repositoryUrl string
+}
+
+func (gc *gitHubChecker) HasConflicts(pullRequestId int64) (bool, error) {
+	var pullRequest struct {
+		// Null while GitHub computes the mergeability of the pull request
+		Mergeable *bool `json:"mergeable"`
+	}
+	if err := gc.client.Get(fmt.Sprintf("%s/pulls/%d", gc.repositoryUrl, pullRequestId), &pullRequest); err != nil {
+		return false, err
+	}
+	return pullRequest.Mergeable != nil && !*pullRequest.Mergeable, nil
+}
+
+func (gc *gitHubChecker) BehindBy(pullRequest *vcsclient.PullRequestInfo, _ int) (int, error) {
+	var comparison struct {
+		BehindBy int `json:"behind_by"`
+	}
+	compareUrl := fmt.Sprintf("%s/compare/%s...%s", gc.repositoryUrl, url.PathEscape(pullRequest.Target.Name), url.PathEscape(pullRequest.Source.Name))
+	if err := gc.client.Get(compareUrl, &comparison); err != nil {
+		return 0, err
+	}
+	return comparison.BehindBy, nil
+}
+
+type gitLabChecker struct {
+	client           *vcsapi.Client
+	mergeRequestsUrl string
+}
+
+func (gl *gitLabChecker) HasConflicts(pullRequestId int64) (bool, error) {
+	var mergeRequest struct {
+		HasConflicts bool `json:"has_conflicts"`
+	}
+	if err := gl.client.Get(fmt.Sprintf("%s/%d", gl.mergeRequestsUrl, pullRequestId), &mergeRequest); err != nil {
+		return false, err
+	}
+	return mergeRequest.HasConflicts, nil
+}
+
+func (gl *gitLabChecker) BehindBy(pullRequest *vcsclient.PullRequestInfo, _ int) (int, error) {
+	var mergeRequest struct {
+		// The number of commits of the target branch that the source branch doesn't have
+		DivergedCommitsCount int `json:"diverged_commits_count"`
+	}
+	mergeRequestUrl := fmt.Sprintf("%s/%d?include_diverged_commits_count=true", gl.mergeRequestsUrl, pullRequest.ID)
+	if err := gl.client.Get(mergeRequestUrl, &mergeRequest); err != nil {
+		return 0, err
+	}
+	return mergeRequest.DivergedCommitsCount, nil
+}
+
+type bitbucketServerChecker struct {
+	client        *vcsapi.Client
+	repositoryUrl string
+}
+
+func (bs *bitbucketServerChecker) HasConflicts(pullRequestId int64) (bool, error) {
+	var mergeStatus struct {
+		Conflicted bool `json:"conflicted"`
+	}
+	if err := bs.client.Get(fmt.Sprintf("%s/pull-requests/%d/merge", bs.repositoryUrl, pullRequestId), &mergeStatus); err != nil {
+		return false, err
+	}
+	return mergeStatus.Conflicted, nil
+}
+
+// The commits that the target branch has and the source branch doesn't are listed, up to the limit
+func (bs *bitbucketServerChecker) BehindBy(pullRequest *vcsclient.PullRequestInfo, limit int) (int, error) {
+	var commits struct {
+		Size int `json:"size"`
+	}
+	commitsUrl := fmt.Sprintf("%s/commits?since=%s&until=%s&limit=%d", bs.repositoryUrl, url.QueryEscape(pullRequest.Source.Name), url.QueryEscape(pullRequest.Target.Name), limit)
+	if err := bs.client.Get(commitsUrl, &commits); err != nil {
+		return 0, err
+	}
+	return commits.Size, nil
+}
+
+type bitbucketCloudChecker struct {
+	client        *vcsapi.Client
+	repositoryUrl string
+}
+
+// Bitbucket Cloud has no mergeability status, so the conflicting files are looked for in the diff stats of the pull request
+func (bc *bitbucketCloudChecker) HasConflicts(pullRequestId int64) (bool, error) {
+	nextUrl := fmt.Sprintf("%s/pullrequests/%d/diffstat", bc.repositoryUrl, pullRequestId)
+	for nextUrl != "" {
+		var diffStat struct {
+			Values []struct {
+				Status string `json:"status"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if err := bc.client.Get(nextUrl, &diffStat); err != nil {
+			return false, err
+		}
+		for _, file := range diffStat.Values {
+			if file.Status == bitbucketCloudConflictStatus {
+				return true, nil
+			}
+		}
+		nextUrl = diffStat.Next
+	}
+	return false, nil
+}
+
+// The commits that the target branch has and the source branch doesn't are listed, up to the limit
+func (bc *bitbucketCloudChecker) BehindBy(pullRequest *vcsclient.PullRequestInfo, limit int) (int, error) {
+	var commits struct {
+		Values []json.RawMessage `json:"values"`
+	}
+	commitsUrl := fmt.Sprintf("%s/commits/%s?exclude=%s&pagelen=%d", bc.repositoryUrl, url.PathEscape(pullRequest.Target.Name), url.QueryEscape(pullRequest.Source.Name), limit)
+	if err := bc.client.Get(commitsUrl, &commits); err != nil {
+		return 0, err
+	}
+	return len(commits.Values), nil
+}
+
+type azureReposChecker struct {
+	client        *vcsapi.Client
+	repositoryUrl string
+}
+
+func (ac *azureReposChecker) HasConflicts(pullRequestId int64) (bool, error) {
+	var pullRequest struct {
+		MergeStatus string `json:"mergeStatus"`
+	}
+	if err := ac.client.Get(fmt.Sprintf("%s/pullrequests/%d?api-version=%s", ac.repositoryUrl, pullRequestId, vcsapi.AzureApiVersion), &pullRequest); err != nil {
+		return false, err
+	}
+	return pullRequest.MergeStatus == "conflicts", nil
+}
+
+// The commits of the source branch are compared to the commits of the target branch, so the behind count is of the source branch
+func (ac *azureReposChecker) BehindBy(pullRequest *vcsclient.PullRequestInfo, _ int) (int, error) {
+	var commitDiffs struct {
+		BehindCount int `json:"behindCount"`
+	}
+	diffsUrl := fmt.Sprintf("%s/diffs/commits?baseVersion=%s&targetVersion=%s&$top=1&api-version=%s",
+		ac.repositoryUrl, url.QueryEscape(pullRequest.Source.Name), url.QueryEscape(pullRequest.Target.Name), vcsapi.AzureApiVersion)
+	if err := ac.client.Get(diffsUrl, &commitDiffs); err != nil {
+		return 0, err
+	}
+	return commitDiffs.BehindCount, nil
+}
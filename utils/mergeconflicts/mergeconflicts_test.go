@@ -0,0 +1,175 @@
+package mergeconflicts
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasConflicts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		provider      vcsutils.VcsProvider
+		username      string
+		expectedAuth  string
+		responses     map[string]string
+		expectedValue bool
+	}{
+		{
+			name:          "GitHub",
+			provider:      vcsutils.GitHub,
+			expectedAuth:  "Bearer token",
+			responses:     map[string]string{"/repos/jfrog/frogbot/pulls/7": `{"mergeable":false}`},
+			expectedValue: true,
+		},
+		{
+			name:         "GitHub mergeability being computed",
+			provider:     vcsutils.GitHub,
+			expectedAuth: "Bearer token",
+			responses:    map[string]string{"/repos/jfrog/frogbot/pulls/7": `{"mergeable":null}`},
+		},
+		{
+			name:          "GitLab",
+			provider:      vcsutils.GitLab,
+			responses:     map[string]string{"/projects/jfrog/frogbot/merge_requests/7": `{"has_conflicts":true}`},
+			expectedValue: true,
+		},
+		{
+			name:          "Bitbucket Server",
+			provider:      vcsutils.BitbucketServer,
+			username:      "frogbot",
+			expectedAuth:  "Basic ZnJvZ2JvdDp0b2tlbg==",
+			responses:     map[string]string{"/rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7/merge": `{"canMerge":false,"conflicted":true}`},
+			expectedValue: true,
+		},
+		{
+			name:         "Bitbucket Cloud",
+			provider:     vcsutils.BitbucketCloud,
+			expectedAuth: "Bearer token",
+			responses: map[string]string{
+				"/repositories/jfrog/frogbot/pullrequests/7/diffstat": `{"values":[{"status":"modified"}],"next":"{server}/page/2"}`,
+				"/page/2": `{"values":[{"status":"merge conflict"}]}`,
+			},
+			expectedValue: true,
+		},
+		{
+			name:         "Azure Repos",
+			provider:     vcsutils.AzureRepos,
+			expectedAuth: "Basic OnRva2Vu",
+			responses:    map[string]string{"/frogbot-project/_apis/git/repositories/frogbot/pullrequests/7": `{"mergeStatus":"succeeded"}`},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response, exists := tc.responses[r.URL.Path]
+				require.True(t, exists, "unexpected request to "+r.URL.Path)
+				if tc.expectedAuth != "" {
+					assert.Equal(t, tc.expectedAuth, r.Header.Get("Authorization"))
+				} else {
+					assert.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+				}
+				_, err := w.Write([]byte(strings.ReplaceAll(response, "{server}", server.URL)))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			checker, err := NewChecker(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token", Username: tc.username, Project: "frogbot-project"}, "jfrog", "frogbot")
+			require.NoError(t, err)
+			hasConflicts, err := checker.HasConflicts(7)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, hasConflicts)
+		})
+	}
+}
+
+func TestHasConflictsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	checker, err := NewChecker(vcsutils.GitHub, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "jfrog", "frogbot")
+	require.NoError(t, err)
+	_, err = checker.HasConflicts(7)
+	assert.ErrorContains(t, err, "responded with status 404")
+}
+
+func TestBehindBy(t *testing.T) {
+	testCases := []struct {
+		name          string
+		provider      vcsutils.VcsProvider
+		expectedUrl   string
+		response      string
+		expectedValue int
+	}{
+		{
+			name:          "GitHub",
+			provider:      vcsutils.GitHub,
+			expectedUrl:   "/repos/jfrog/frogbot/compare/master...frogbot-update-dependencies",
+			response:      `{"ahead_by":1,"behind_by":3}`,
+			expectedValue: 3,
+		},
+		{
+			name:        "GitHub up to date",
+			provider:    vcsutils.GitHub,
+			expectedUrl: "/repos/jfrog/frogbot/compare/master...frogbot-update-dependencies",
+			response:    `{"ahead_by":1,"behind_by":0}`,
+		},
+		{
+			name:          "GitLab",
+			provider:      vcsutils.GitLab,
+			expectedUrl:   "/projects/jfrog%2Ffrogbot/merge_requests/7?include_diverged_commits_count=true",
+			response:      `{"diverged_commits_count":2}`,
+			expectedValue: 2,
+		},
+		{
+			name:          "Bitbucket Server",
+			provider:      vcsutils.BitbucketServer,
+			expectedUrl:   "/rest/api/1.0/projects/jfrog/repos/frogbot/commits?since=frogbot-update-dependencies&until=master&limit=5",
+			response:      `{"size":5,"values":[{"id":"a"},{"id":"b"},{"id":"c"},{"id":"d"},{"id":"e"}],"isLastPage":false}`,
+			expectedValue: 5,
+		},
+		{
+			name:          "Bitbucket Cloud",
+			provider:      vcsutils.BitbucketCloud,
+			expectedUrl:   "/repositories/jfrog/frogbot/commits/master?exclude=frogbot-update-dependencies&pagelen=5",
+			response:      `{"values":[{"hash":"a"},{"hash":"b"}]}`,
+			expectedValue: 2,
+		},
+		{
+			name:        "Bitbucket Cloud up to date",
+			provider:    vcsutils.BitbucketCloud,
+			expectedUrl: "/repositories/jfrog/frogbot/commits/master?exclude=frogbot-update-dependencies&pagelen=5",
+			response:    `{"values":[]}`,
+		},
+		{
+			name:          "Azure Repos",
+			provider:      vcsutils.AzureRepos,
+			expectedUrl:   "/frogbot-project/_apis/git/repositories/frogbot/diffs/commits?baseVersion=frogbot-update-dependencies&targetVersion=master&$top=1&api-version=7.0",
+			response:      `{"aheadCount":1,"behindCount":4}`,
+			expectedValue: 4,
+		},
+	}
+	pullRequest := &vcsclient.PullRequestInfo{ID: 7, Source: vcsclient.BranchInfo{Name: "frogbot-update-dependencies"}, Target: vcsclient.BranchInfo{Name: "master"}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedUrl, r.URL.RequestURI())
+				_, err := w.Write([]byte(tc.response))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			checker, err := NewChecker(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token", Project: "frogbot-project"}, "jfrog", "frogbot")
+			require.NoError(t, err)
+			behindBy, err := checker.BehindBy(pullRequest, 5)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, behindBy)
+		})
+	}
+}
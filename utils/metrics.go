@@ -0,0 +1,342 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type PullRequestAction string
+
+const (
+	PullRequestOpened  PullRequestAction = "opened"
+	PullRequestUpdated PullRequestAction = "updated"
+
+	metricsPushJob = "frogbot"
+)
+
+// RunMetrics holds the statistics of a single Frogbot run, for teams that run Frogbot on schedulers.
+// The metrics are collected only if a metrics sink is configured, and written to the sinks when the command finishes.
+type RunMetrics struct {
+	mutex     sync.Mutex
+	file      string
+	pushUrl   string
+	startTime time.Time
+
+	Command         string  `json:"command"`
+	StartTime       string  `json:"startTime"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Failed          bool    `json:"failed"`
+	// The total scan duration of each repository
+	ScanDurationsSeconds map[string]float64 `json:"scanDurationsSeconds"`
+	// The number of vulnerabilities and violations found, by severity
+	Vulnerabilities map[string]int `json:"vulnerabilities"`
+	// The number of pull requests, by the action that was performed on them
+	PullRequests map[string]int `json:"pullRequests"`
+	// The number of failed Git provider API requests, by API
+	ApiErrors map[string]int `json:"apiErrors"`
+}
+
+// The metrics of the current run, nil if no metrics sink is configured
+var runMetrics *RunMetrics
+
+// Starts collecting the metrics of the run if a metrics sink is configured.
+// Must be called before the environment variables are sanitized.
+func startRunMetrics(command string) {
+	file, pushUrl := getTrimmedEnv(MetricsFileEnv), getTrimmedEnv(MetricsPushgatewayUrlEnv)
+	if file == "" && pushUrl == "" {
+		runMetrics = nil
+		return
+	}
+	runMetrics = newRunMetrics(command, file, pushUrl)
+}
+
+func newRunMetrics(command, file, pushUrl string) *RunMetrics {
+	startTime := time.Now()
+	return &RunMetrics{
+		file:                 file,
+		pushUrl:              strings.TrimSuffix(pushUrl, "/"),
+		startTime:            startTime,
+		Command:              command,
+		StartTime:            startTime.UTC().Format(time.RFC3339),
+		ScanDurationsSeconds: map[string]float64{},
+		Vulnerabilities:      map[string]int{},
+		PullRequests:         map[string]int{},
+		ApiErrors:            map[string]int{},
+	}
+}
+
+// Writes the metrics of the run to the configured sinks. Failing to write the metrics doesn't fail the run.
+func FinishRunMetrics(runErr error) {
+	if runMetrics == nil {
+		return
+	}
+	metrics := runMetrics
+	runMetrics = nil
+	metrics.DurationSeconds = time.Since(metrics.startTime).Seconds()
+	metrics.Failed = runErr != nil
+	if metrics.file != "" {
+		if err := metrics.writeFile(); err != nil {
+			log.Warn("Failed to write the run metrics file:", err.Error())
+		}
+	}
+	if metrics.pushUrl != "" {
+		if err := metrics.push(); err != nil {
+			log.Warn("Failed to push the run metrics to the Pushgateway:", err.Error())
+		}
+	}
+}
+
+// Records the duration of a scan of the repository
+func RecordScanDuration(repository string, duration time.Duration) {
+	if runMetrics == nil {
+		return
+	}
+	runMetrics.mutex.Lock()
+	defer runMetrics.mutex.Unlock()
+	runMetrics.ScanDurationsSeconds[repository] += duration.Seconds()
+}
+
+// Records the issues that were found by a scan, by severity, in the run metrics and the run summary
+func RecordIssues(issuesCollection *issues.ScansIssuesCollection) {
+	if (runMetrics == nil && runSummary == nil) || issuesCollection == nil {
+		return
+	}
+	severityCounts := map[string]int{}
+	for _, scanType := range []utils.SubScanType{utils.ScaScan, utils.IacScan, utils.SecretsScan, utils.SastScan} {
+		for severity, count := range issuesCollection.GetScanIssuesSeverityCount(scanType, true, true) {
+			severityCounts[severity.String()] += count
+		}
+	}
+	recordSummaryVulnerabilities(severityCounts)
+	if runMetrics == nil {
+		return
+	}
+	runMetrics.mutex.Lock()
+	defer runMetrics.mutex.Unlock()
+	for severity, count := range severityCounts {
+		runMetrics.Vulnerabilities[severity] += count
+	}
+}
+
+// Records the issues of the scan results. The results are converted only if the metrics or the summary are collected.
+func RecordScanResults(scanResults *results.SecurityCommandResults, allowedLicenses []string) {
+	if (runMetrics == nil && runSummary == nil) || scanResults == nil {
+		return
+	}
+	issuesCollection, err := ConvertToIssuesCollection(scanResults, allowedLicenses)
+	if err != nil {
+		log.Debug("Failed to record the issues of the scan results in the run metrics and summary:", err.Error())
+		return
+	}
+	RecordIssues(issuesCollection)
+}
+
+func recordPullRequest(action PullRequestAction) {
+	recordSummaryPullRequest(action)
+	if runMetrics == nil {
+		return
+	}
+	runMetrics.mutex.Lock()
+	defer runMetrics.mutex.Unlock()
+	runMetrics.PullRequests[string(action)]++
+}
+
+func recordApiError(api string) {
+	if runMetrics == nil {
+		return
+	}
+	runMetrics.mutex.Lock()
+	defer runMetrics.mutex.Unlock()
+	runMetrics.ApiErrors[api]++
+}
+
+// Files with the '.json' extension are written as JSON, and other files in the Prometheus text format, for the node exporter textfile collector
+func (rm *RunMetrics) writeFile() error {
+	var content []byte
+	if strings.EqualFold(filepath.Ext(rm.file), ".json") {
+		var err error
+		if content, err = json.MarshalIndent(rm, "", "  "); err != nil {
+			return err
+		}
+	} else {
+		content = []byte(rm.toPrometheusText())
+	}
+	log.Info("Writing the run metrics to:", rm.file)
+	return os.WriteFile(rm.file, content, 0644)
+}
+
+// Replaces the metrics of the command in the Pushgateway
+func (rm *RunMetrics) push() error {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return err
+	}
+	pushUrl := fmt.Sprintf("%s/metrics/job/%s/command/%s", rm.pushUrl, metricsPushJob, url.PathEscape(rm.Command))
+	log.Info("Pushing the run metrics to:", pushUrl)
+	httpClientDetails := httputils.HttpClientDetails{Headers: map[string]string{"Content-Type": "text/plain; version=0.0.4"}}
+	resp, body, err := client.SendPut(pushUrl, []byte(rm.toPrometheusText()), httpClientDetails, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("the Pushgateway responded with status %s: %s", resp.Status, string(body))
+	}
+	return nil
+}
+
+func (rm *RunMetrics) toPrometheusText() string {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+	var builder strings.Builder
+	commandLabel := prometheusLabel("command", rm.Command)
+	writePrometheusMetric(&builder, "frogbot_run_timestamp_seconds", "The start time of the run, in seconds since the epoch.", map[string]float64{commandLabel: float64(rm.startTime.Unix())})
+	writePrometheusMetric(&builder, "frogbot_run_duration_seconds", "The duration of the run.", map[string]float64{commandLabel: rm.DurationSeconds})
+	failed := 0.0
+	if rm.Failed {
+		failed = 1
+	}
+	writePrometheusMetric(&builder, "frogbot_run_failed", "Whether the run failed.", map[string]float64{commandLabel: failed})
+	writePrometheusMetric(&builder, "frogbot_scan_duration_seconds", "The total scan duration of each repository.", toPrometheusSamples(commandLabel, "repository", rm.ScanDurationsSeconds))
+	writePrometheusMetric(&builder, "frogbot_vulnerabilities", "The number of vulnerabilities and violations found, by severity.", toPrometheusSamples(commandLabel, "severity", rm.Vulnerabilities))
+	writePrometheusMetric(&builder, "frogbot_pull_requests", "The number of pull requests, by the action that was performed on them.", toPrometheusSamples(commandLabel, "action", rm.PullRequests))
+	writePrometheusMetric(&builder, "frogbot_api_errors", "The number of failed Git provider API requests, by API.", toPrometheusSamples(commandLabel, "api", rm.ApiErrors))
+	return builder.String()
+}
+
+func toPrometheusSamples[T int | float64](commandLabel, labelName string, values map[string]T) map[string]float64 {
+	samples := make(map[string]float64, len(values))
+	for labelValue, value := range values {
+		samples[commandLabel+","+prometheusLabel(labelName, labelValue)] = float64(value)
+	}
+	return samples
+}
+
+// Metrics without samples are omitted. The samples are sorted by their labels, so the output is stable.
+func writePrometheusMetric(builder *strings.Builder, name, help string, samples map[string]float64) {
+	if len(samples) == 0 {
+		return
+	}
+	builder.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s gauge\n", name, help, name))
+	labels := make([]string, 0, len(samples))
+	for label := range samples {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		builder.WriteString(fmt.Sprintf("%s{%s} %v\n", name, label, samples[label]))
+	}
+}
+
+func prometheusLabel(name, value string) string {
+	return fmt.Sprintf("%s=%q", name, strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value))
+}
+
+// metricsVcsClient records the pull requests and the failed API requests of the Git provider client in the run metrics
+type metricsVcsClient struct {
+	vcsclient.VcsClient
+}
+
+// Instruments the client if the run metrics or the run summary are collected
+func newMetricsVcsClient(client vcsclient.VcsClient) vcsclient.VcsClient {
+	if runMetrics == nil && runSummary == nil {
+		return client
+	}
+	return &metricsVcsClient{VcsClient: client}
+}
+
+func recordApiResult(api string, err error) error {
+	if err != nil {
+		recordApiError(api)
+	}
+	return err
+}
+
+func (mc *metricsVcsClient) CreatePullRequest(ctx context.Context, owner, repository, sourceBranch, targetBranch, title, description string) error {
+	err := mc.VcsClient.CreatePullRequest(ctx, owner, repository, sourceBranch, targetBranch, title, description)
+	if err == nil {
+		recordPullRequest(PullRequestOpened)
+	}
+	return recordApiResult("CreatePullRequest", err)
+}
+
+func (mc *metricsVcsClient) UpdatePullRequest(ctx context.Context, owner, repository, title, body, targetBranchName string, prId int, state vcsutils.PullRequestState) error {
+	err := mc.VcsClient.UpdatePullRequest(ctx, owner, repository, title, body, targetBranchName, prId, state)
+	// Frogbot doesn't close pull requests, so closing one isn't recorded as an update
+	if err == nil && state != vcsutils.Closed {
+		recordPullRequest(PullRequestUpdated)
+	}
+	return recordApiResult("UpdatePullRequest", err)
+}
+
+func (mc *metricsVcsClient) DownloadRepository(ctx context.Context, owner, repository, branch, localPath string) error {
+	return recordApiResult("DownloadRepository", mc.VcsClient.DownloadRepository(ctx, owner, repository, branch, localPath))
+}
+
+func (mc *metricsVcsClient) AddPullRequestComment(ctx context.Context, owner, repository, content string, pullRequestID int) error {
+	return recordApiResult("AddPullRequestComment", mc.VcsClient.AddPullRequestComment(ctx, owner, repository, content, pullRequestID))
+}
+
+func (mc *metricsVcsClient) AddPullRequestReviewComments(ctx context.Context, owner, repository string, pullRequestID int, comments ...vcsclient.PullRequestComment) error {
+	return recordApiResult("AddPullRequestReviewComments", mc.VcsClient.AddPullRequestReviewComments(ctx, owner, repository, pullRequestID, comments...))
+}
+
+func (mc *metricsVcsClient) ListPullRequestComments(ctx context.Context, owner, repository string, pullRequestID int) ([]vcsclient.CommentInfo, error) {
+	comments, err := mc.VcsClient.ListPullRequestComments(ctx, owner, repository, pullRequestID)
+	return comments, recordApiResult("ListPullRequestComments", err)
+}
+
+func (mc *metricsVcsClient) ListPullRequestReviewComments(ctx context.Context, owner, repository string, pullRequestID int) ([]vcsclient.CommentInfo, error) {
+	comments, err := mc.VcsClient.ListPullRequestReviewComments(ctx, owner, repository, pullRequestID)
+	return comments, recordApiResult("ListPullRequestReviewComments", err)
+}
+
+func (mc *metricsVcsClient) DeletePullRequestComment(ctx context.Context, owner, repository string, pullRequestID, commentID int) error {
+	return recordApiResult("DeletePullRequestComment", mc.VcsClient.DeletePullRequestComment(ctx, owner, repository, pullRequestID, commentID))
+}
+
+func (mc *metricsVcsClient) DeletePullRequestReviewComments(ctx context.Context, owner, repository string, pullRequestID int, comments ...vcsclient.CommentInfo) error {
+	return recordApiResult("DeletePullRequestReviewComments", mc.VcsClient.DeletePullRequestReviewComments(ctx, owner, repository, pullRequestID, comments...))
+}
+
+func (mc *metricsVcsClient) ListOpenPullRequests(ctx context.Context, owner, repository string) ([]vcsclient.PullRequestInfo, error) {
+	pullRequests, err := mc.VcsClient.ListOpenPullRequests(ctx, owner, repository)
+	return pullRequests, recordApiResult("ListOpenPullRequests", err)
+}
+
+func (mc *metricsVcsClient) ListOpenPullRequestsWithBody(ctx context.Context, owner, repository string) ([]vcsclient.PullRequestInfo, error) {
+	pullRequests, err := mc.VcsClient.ListOpenPullRequestsWithBody(ctx, owner, repository)
+	return pullRequests, recordApiResult("ListOpenPullRequestsWithBody", err)
+}
+
+func (mc *metricsVcsClient) GetPullRequestByID(ctx context.Context, owner, repository string, pullRequestId int) (vcsclient.PullRequestInfo, error) {
+	pullRequest, err := mc.VcsClient.GetPullRequestByID(ctx, owner, repository, pullRequestId)
+	return pullRequest, recordApiResult("GetPullRequestByID", err)
+}
+
+func (mc *metricsVcsClient) GetRepositoryInfo(ctx context.Context, owner, repository string) (vcsclient.RepositoryInfo, error) {
+	repositoryInfo, err := mc.VcsClient.GetRepositoryInfo(ctx, owner, repository)
+	return repositoryInfo, recordApiResult("GetRepositoryInfo", err)
+}
+
+func (mc *metricsVcsClient) UploadCodeScanning(ctx context.Context, owner, repository, branch, scanResults string) (string, error) {
+	id, err := mc.VcsClient.UploadCodeScanning(ctx, owner, repository, branch, scanResults)
+	return id, recordApiResult("UploadCodeScanning", err)
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartRunMetrics(t *testing.T) {
+	defer func() {
+		runMetrics = nil
+	}()
+	startRunMetrics(ScanRepository)
+	assert.Nil(t, runMetrics)
+	// Recording without a metrics sink does nothing
+	RecordScanDuration("jfrog/frogbot", time.Second)
+	recordPullRequest(PullRequestOpened)
+
+	t.Setenv(MetricsFileEnv, "metrics.prom")
+	startRunMetrics(ScanRepository)
+	require.NotNil(t, runMetrics)
+	assert.Equal(t, ScanRepository, runMetrics.Command)
+	assert.Equal(t, "metrics.prom", runMetrics.file)
+}
+
+func TestRunMetricsToPrometheusText(t *testing.T) {
+	defer func() {
+		runMetrics = nil
+	}()
+	runMetrics = newRunMetrics(ScanPullRequest, "", "")
+	runMetrics.startTime = time.Unix(1700000000, 0)
+	runMetrics.DurationSeconds = 12.5
+	RecordScanDuration("jfrog/frogbot", 2*time.Second)
+	RecordScanDuration("jfrog/frogbot", 3*time.Second)
+	RecordIssues(&issues.ScansIssuesCollection{
+		ScaVulnerabilities:  []formats.VulnerabilityOrViolationRow{{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "High"}}}},
+		ScaViolations:       []formats.VulnerabilityOrViolationRow{{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}}}},
+		SastVulnerabilities: []formats.SourceCodeRow{{SeverityDetails: formats.SeverityDetails{Severity: "High"}}},
+	})
+	recordApiError("ListPullRequestComments")
+
+	expectedText := `# HELP frogbot_run_timestamp_seconds The start time of the run, in seconds since the epoch.
+# TYPE frogbot_run_timestamp_seconds gauge
+frogbot_run_timestamp_seconds{command="scan-pull-request"} 1.7e+09
+# HELP frogbot_run_duration_seconds The duration of the run.
+# TYPE frogbot_run_duration_seconds gauge
+frogbot_run_duration_seconds{command="scan-pull-request"} 12.5
+# HELP frogbot_run_failed Whether the run failed.
+# TYPE frogbot_run_failed gauge
+frogbot_run_failed{command="scan-pull-request"} 0
+# HELP frogbot_scan_duration_seconds The total scan duration of each repository.
+# TYPE frogbot_scan_duration_seconds gauge
+frogbot_scan_duration_seconds{command="scan-pull-request",repository="jfrog/frogbot"} 5
+# HELP frogbot_vulnerabilities The number of vulnerabilities and violations found, by severity.
+# TYPE frogbot_vulnerabilities gauge
+frogbot_vulnerabilities{command="scan-pull-request",severity="Critical"} 1
+frogbot_vulnerabilities{command="scan-pull-request",severity="High"} 2
+# HELP frogbot_api_errors The number of failed Git provider API requests, by API.
+# TYPE frogbot_api_errors gauge
+frogbot_api_errors{command="scan-pull-request",api="ListPullRequestComments"} 1
+`
+	assert.Equal(t, expectedText, runMetrics.toPrometheusText())
+}
+
+func TestFinishRunMetrics(t *testing.T) {
+	defer func() {
+		runMetrics = nil
+	}()
+	var pushedMetrics string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/metrics/job/frogbot/command/scan-repository", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		pushedMetrics = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		fileName string
+		pushUrl  string
+	}{
+		{name: "JSON file", fileName: "metrics.json"},
+		{name: "Prometheus textfile", fileName: "frogbot.prom"},
+		{name: "Pushgateway", pushUrl: server.URL + "/"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metricsFile := ""
+			if tc.fileName != "" {
+				metricsFile = filepath.Join(t.TempDir(), tc.fileName)
+			}
+			runMetrics = newRunMetrics(ScanRepository, metricsFile, tc.pushUrl)
+			recordPullRequest(PullRequestOpened)
+			FinishRunMetrics(errors.New("scan failed"))
+			assert.Nil(t, runMetrics)
+
+			switch tc.fileName {
+			case "metrics.json":
+				content, err := os.ReadFile(metricsFile)
+				require.NoError(t, err)
+				var metrics RunMetrics
+				require.NoError(t, json.Unmarshal(content, &metrics))
+				assert.Equal(t, ScanRepository, metrics.Command)
+				assert.True(t, metrics.Failed)
+				assert.Equal(t, map[string]int{"opened": 1}, metrics.PullRequests)
+			case "frogbot.prom":
+				content, err := os.ReadFile(metricsFile)
+				require.NoError(t, err)
+				assert.Contains(t, string(content), `frogbot_run_failed{command="scan-repository"} 1`)
+				assert.Contains(t, string(content), `frogbot_pull_requests{command="scan-repository",action="opened"} 1`)
+			default:
+				assert.Contains(t, pushedMetrics, `frogbot_pull_requests{command="scan-repository",action="opened"} 1`)
+			}
+		})
+	}
+}
+
+func TestMetricsVcsClient(t *testing.T) {
+	defer func() {
+		runMetrics = nil
+	}()
+	mockClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	// The client isn't instrumented without a metrics sink
+	assert.Equal(t, mockClient, newMetricsVcsClient(mockClient))
+
+	runMetrics = newRunMetrics(ScanRepository, "metrics.json", "")
+	client := newMetricsVcsClient(mockClient)
+	ctx := context.Background()
+	mockClient.EXPECT().CreatePullRequest(ctx, "jfrog", "frogbot", "fix", "main", "title", "body").Return(nil)
+	mockClient.EXPECT().UpdatePullRequest(ctx, "jfrog", "frogbot", "title", "body", "main", 1, vcsutils.Open).Return(nil)
+	mockClient.EXPECT().UpdatePullRequest(ctx, "jfrog", "frogbot", "title", "body", "main", 2, vcsutils.Closed).Return(nil)
+	mockClient.EXPECT().UpdatePullRequest(ctx, "jfrog", "frogbot", "title", "body", "main", 3, vcsutils.Open).Return(errors.New("not found"))
+	mockClient.EXPECT().ListOpenPullRequests(ctx, "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{{ID: 1}}, nil)
+	mockClient.EXPECT().ListPullRequestComments(ctx, "jfrog", "frogbot", 1).Return(nil, errors.New("rate limited"))
+
+	assert.NoError(t, client.CreatePullRequest(ctx, "jfrog", "frogbot", "fix", "main", "title", "body"))
+	assert.NoError(t, client.UpdatePullRequest(ctx, "jfrog", "frogbot", "title", "body", "main", 1, vcsutils.Open))
+	assert.NoError(t, client.UpdatePullRequest(ctx, "jfrog", "frogbot", "title", "body", "main", 2, vcsutils.Closed))
+	assert.Error(t, client.UpdatePullRequest(ctx, "jfrog", "frogbot", "title", "body", "main", 3, vcsutils.Open))
+	pullRequests, err := client.ListOpenPullRequests(ctx, "jfrog", "frogbot")
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 1)
+	_, err = client.ListPullRequestComments(ctx, "jfrog", "frogbot", 1)
+	assert.Error(t, err)
+
+	assert.Equal(t, map[string]int{"opened": 1, "updated": 1}, runMetrics.PullRequests)
+	assert.Equal(t, map[string]int{"UpdatePullRequest": 1, "ListPullRequestComments": 1}, runMetrics.ApiErrors)
+}
@@ -0,0 +1,147 @@
+package outputwriter
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v2"
+)
+
+// The keys of the banners of the comments in the messages file
+const (
+	NoIssuesBanner       = "noIssues"
+	IssuesFoundBanner    = "issuesFound"
+	FixPullRequestBanner = "fixPullRequest"
+)
+
+// The keys of the titles of the comment sections in the messages file, by the built-in titles
+var titleKeys = map[string]string{
+	scanSummaryTitle:             "scanSummary",
+	issuesDetailsSubTitle:        "issuesDetails",
+	jfrogResearchDetailsSubTitle: "jfrogResearchDetails",
+	explainFindingSubTitle:       "explainFinding",
+	policyViolationTitle:         "policyViolations",
+	securityViolationTitle:       "securityViolations",
+	licenseViolationTitle:        "licenseViolations",
+	vulnerableDependenciesTitle:  "vulnerableDependencies",
+	runtimeDetailsTitle:          "runtimeDetails",
+	fixedByPullRequestTitle:      "fixedByPullRequest",
+	sbomTitle:                    "sbom",
+	unsupportedFixesTitle:        "unsupportedFixes",
+	failedFixesTitle:             "failedFixes",
+	ignoredFindingsTitle:         "ignoredFindings",
+	releaseNotesTitle:            "releaseNotes",
+	updatedWorkspacesTitle:       "updatedWorkspaces",
+	upgradeRiskTitle:             "upgradeRisk",
+	dependencyConfusionTitle:     "dependencyConfusion",
+	policyRulesTitle:             "policyRules",
+	dockerImageTitle:             "dockerImage",
+	systemPackagesTitle:          "systemPackages",
+	curationTitle:                "curation",
+	alternativePackagesTitle:     "alternativePackages",
+	gitHubActionsTitle:           "gitHubActions",
+	externalScannersTitle:        "externalScanners",
+	securityChampionsTitle:       "securityChampions",
+	secretsRotationTitle:         "secretsRotation",
+	resolvedCommentTitle:         "resolvedComment",
+	scanInProgressTitle:          "scanInProgress",
+	scanFailedTitle:              "scanFailed",
+	secretsTitle:                 "secrets",
+	contextualAnalysisTitle:      "contextualAnalysis",
+	iacTitle:                     "iac",
+	sastTitle:                    "sast",
+}
+
+// The banners of the comments, by their images
+var bannerKeys = map[ImageSource]string{
+	NoVulnerabilityPrBannerSource:    NoIssuesBanner,
+	NoVulnerabilityMrBannerSource:    NoIssuesBanner,
+	VulnerabilitiesPrBannerSource:    IssuesFoundBanner,
+	VulnerabilitiesMrBannerSource:    IssuesFoundBanner,
+	VulnerabilitiesFixPrBannerSource: FixPullRequestBanner,
+	VulnerabilitiesFixMrBannerSource: FixPullRequestBanner,
+}
+
+// Messages customizes the wording of the comments, to localize them or to replace the Frogbot branding.
+// The messages that aren't set keep the built-in English wording.
+type Messages struct {
+	// The titles of the comment sections, by their keys
+	Titles map[string]string `yaml:"titles,omitempty"`
+	// The banners of the comments, by their keys. A customized banner is written as bold text instead of the banner image.
+	Banners map[string]string `yaml:"banners,omitempty"`
+	// Replaces the link to the Frogbot documentation at the bottom of the comments
+	Footer string `yaml:"footer,omitempty"`
+	// The labels of the severities, by the severities
+	Severities map[string]string `yaml:"severities,omitempty"`
+}
+
+// Reads the messages file, and verifies that all its keys are known, so typos don't go unnoticed
+func LoadMessages(path string) (messages Messages, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return messages, fmt.Errorf("failed to read the messages file: %s", err.Error())
+	}
+	if err = yaml.UnmarshalStrict(content, &messages); err != nil {
+		return messages, fmt.Errorf("failed to parse the messages file %s: %s", path, err.Error())
+	}
+	return messages, messages.validate()
+}
+
+func (m Messages) validate() error {
+	if err := validateKeys("title", maps.Keys(m.Titles), maps.Values(titleKeys)); err != nil {
+		return err
+	}
+	if err := validateKeys("banner", maps.Keys(m.Banners), []string{NoIssuesBanner, IssuesFoundBanner, FixPullRequestBanner}); err != nil {
+		return err
+	}
+	var severities []string
+	for _, severity := range []severityutils.Severity{severityutils.Critical, severityutils.High, severityutils.Medium, severityutils.Low, severityutils.Unknown} {
+		severities = append(severities, severity.String())
+	}
+	return validateKeys("severity", maps.Keys(m.Severities), severities)
+}
+
+func validateKeys(kind string, keys, supportedKeys []string) error {
+	for _, key := range keys {
+		if !slices.Contains(supportedKeys, key) {
+			slices.Sort(supportedKeys)
+			return fmt.Errorf("the %s %q of the messages file is unknown, the supported keys are: %s", kind, key, strings.Join(supportedKeys, ", "))
+		}
+	}
+	return nil
+}
+
+func (m Messages) title(builtInTitle string) string {
+	if title, exists := m.Titles[titleKeys[builtInTitle]]; exists {
+		return title
+	}
+	return builtInTitle
+}
+
+func (m Messages) banner(source ImageSource) (banner string, exists bool) {
+	banner, exists = m.Banners[bannerKeys[source]]
+	return
+}
+
+func (m Messages) severity(severity string) string {
+	if label, exists := m.Severities[severity]; exists {
+		return label
+	}
+	return severity
+}
+
+func localizedTitle(builtInTitle string, writer OutputWriter) string {
+	return writer.Messages().title(builtInTitle)
+}
+
+// A customized banner keeps the built-in banner title in a hidden comment, as the fix pull requests are recognized by it
+func localizedBanner(source ImageSource, writer OutputWriter) (banner string, exists bool) {
+	if banner, exists = writer.Messages().banner(source); exists {
+		banner = MarkdownComment(GetSimplifiedTitle(source)) + MarkAsBold(banner)
+	}
+	return
+}
@@ -0,0 +1,80 @@
+package outputwriter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMessages(t *testing.T) {
+	messages, err := LoadMessages(filepath.Join(testMessagesDir, "custom", "messages-de.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "📗 Zusammenfassung des Scans", messages.title(scanSummaryTitle))
+	// The messages that aren't set keep the built-in wording
+	assert.Equal(t, sbomTitle, messages.title(sbomTitle))
+	assert.Equal(t, "Kritisch", messages.severity("Critical"))
+	assert.Equal(t, "Unknown", messages.severity("Unknown"))
+	_, exists := messages.banner(VulnerabilitiesFixPrBannerSource)
+	assert.False(t, exists)
+
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{name: "Unknown title", content: "titles:\n  summary: Zusammenfassung\n", expectedError: `the title "summary" of the messages file is unknown`},
+		{name: "Unknown banner", content: "banners:\n  noVulnerabilities: Keine\n", expectedError: `the banner "noVulnerabilities" of the messages file is unknown`},
+		{name: "Unknown severity", content: "severities:\n  critical: Kritisch\n", expectedError: `the severity "critical" of the messages file is unknown`},
+		{name: "Unknown field", content: "header: Frogbot\n", expectedError: "failed to parse the messages file"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			messagesPath := filepath.Join(t.TempDir(), "messages.yml")
+			require.NoError(t, os.WriteFile(messagesPath, []byte(tc.content), 0644))
+			_, err = LoadMessages(messagesPath)
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+	_, err = LoadMessages(filepath.Join(t.TempDir(), "messages.yml"))
+	assert.ErrorContains(t, err, "failed to read the messages file")
+}
+
+func TestLocalizedComment(t *testing.T) {
+	messages, err := LoadMessages(filepath.Join(testMessagesDir, "custom", "messages-de.yml"))
+	require.NoError(t, err)
+	testIssues := issues.ScansIssuesCollection{
+		ScanStatus: formats.ScanStatus{ScaStatusCode: utils.NewIntPtr(0)},
+		ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{
+			{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}, ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}},
+		},
+	}
+	for _, writer := range []OutputWriter{&StandardOutput{MarkdownOutput{vcsProvider: vcsutils.GitHub, hasInternetConnection: true}}, &SimplifiedOutput{MarkdownOutput{vcsProvider: vcsutils.BitbucketServer}}} {
+		writer.SetMessages(messages)
+		summary := ScanSummaryContent(testIssues, results.ResultContext{IncludeVulnerabilities: true}, false, writer)
+		assert.Contains(t, summary, "📗 Zusammenfassung des Scans")
+		assert.Contains(t, summary, "1 Kritisch")
+		assert.Contains(t, strings.Join(GetVulnerabilitiesContent(testIssues.ScaVulnerabilities, writer), ""), "📦 Anfällige Abhängigkeiten")
+
+		comment := strings.Join(GetMainCommentContent([]string{summary}, true, true, writer), "")
+		assert.Contains(t, comment, MarkAsBold("🚨 Der Scan dieses Pull Requests hat Folgendes gefunden:"))
+		assert.NotContains(t, comment, GetBanner(VulnerabilitiesPrBannerSource))
+		assert.Contains(t, comment, "Gescannt von der Sicherheitsplattform von ACME")
+		assert.NotContains(t, comment, CommentGeneratedByFrogbot)
+	}
+
+	// The fix pull requests are recognized by their built-in banner title, even when their banner is customized
+	writer := &StandardOutput{MarkdownOutput{vcsProvider: vcsutils.GitHub, hasInternetConnection: true}}
+	writer.SetMessages(Messages{Banners: map[string]string{FixPullRequestBanner: "🚨 Automatischer Pull Request von Frogbot"}})
+	body := strings.Join(GetMainCommentContent([]string{"content"}, true, false, writer), "")
+	assert.Contains(t, body, MarkAsBold("🚨 Automatischer Pull Request von Frogbot"))
+	assert.True(t, IsFrogbotFixPullRequest(body))
+}
@@ -2,11 +2,17 @@ package outputwriter
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jfrog/frogbot/v2/utils/dependencyscope"
+	"github.com/jfrog/frogbot/v2/utils/exploitability"
 	"github.com/jfrog/frogbot/v2/utils/issues"
 	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
@@ -21,16 +27,46 @@ const (
 	FrogbotDocumentationUrl = "https://docs.jfrog-applications.jfrog.io/jfrog-applications/frogbot"
 	JfrogSupportUrl         = "https://jfrog.com/support/"
 	ReviewCommentId         = "FrogbotReviewComment"
+	// Replaces the ID of the Frogbot comments that are collapsed, so they're kept by the next scans
+	ResolvedCommentId = "FrogbotResolvedComment"
+	// Identifies the comment that Frogbot adds while it scans a pull request, until the results replace it
+	ScanProgressCommentId   = "FrogbotScanProgressComment"
+	FindingIdsCommentPrefix = "FrogbotFindingIds: "
+	// Precedes the commit of the source branch that the summary comment describes, and the result of its scan
+	ScannedCommitCommentPrefix = "FrogbotScannedCommit: "
 
 	scanSummaryTitle             = "📗 Scan Summary"
 	issuesDetailsSubTitle        = "🔖 Details"
 	jfrogResearchDetailsSubTitle = "🔬 JFrog Research Details"
+	explainFindingSubTitle       = "🧪 Explain This Finding"
 
 	policyViolationTitle   = "🚥 Policy Violations"
 	securityViolationTitle = "🚨 Security Violations"
 	licenseViolationTitle  = "⚖️ License Violations"
 
 	vulnerableDependenciesTitle = "📦 Vulnerable Dependencies"
+	runtimeDetailsTitle         = "Runtime Details"
+	fixedByPullRequestTitle     = "✅ Fixed by this PR"
+	sbomTitle                   = "📄 Software Bill of Materials"
+	unsupportedFixesTitle       = "🚧 Known Unfixable Items"
+	failedFixesTitle            = "⚠️ Could not fix"
+	ignoredFindingsTitle        = "🙈 Ignored Findings"
+	releaseNotesTitle           = "📝 Release Notes"
+	updatedWorkspacesTitle      = "🗂️ Updated Workspaces"
+	upgradeRiskTitle            = "🎲 Upgrade Risk"
+	dependencyConfusionTitle    = "🎭 Dependency Confusion Risk"
+	policyRulesTitle            = "🚫 Blocking Rules"
+	dockerImageTitle            = "🐳 Docker Image"
+	systemPackagesTitle         = "📦 System Packages"
+	curationTitle               = "🚫 Blocked by Curation"
+	alternativePackagesTitle    = "💡 Alternative Packages"
+	gitHubActionsTitle          = "⚙️ GitHub Actions"
+	externalScannersTitle       = "🔌 External Scanners"
+	securityChampionsTitle      = "👥 Security Champions"
+	secretsRotationTitle        = "🔑 Secrets Found – Rotate Now"
+	resolvedCommentTitle        = "✅ Resolved in later commits"
+	scanInProgressTitle         = "🔍 Frogbot scan in progress…"
+	scanFailedTitle             = "❌ Frogbot scan failed"
 
 	//#nosec G101 -- not a secret
 	secretsTitle            = "🤫 Secret"
@@ -42,6 +78,8 @@ const (
 var (
 	CommentGeneratedByFrogbot    = MarkAsLink("🐸 JFrog Frogbot", FrogbotDocumentationUrl)
 	jasFeaturesMsgWhenNotEnabled = MarkAsBold("Frogbot") + " also supports " + MarkAsBold("Contextual Analysis, Secret Detection, IaC and SAST Vulnerabilities Scanning") + ". This features are included as part of the " + MarkAsLink("JFrog Advanced Security", "https://jfrog.com/advanced-security") + " package, which isn't enabled on your system."
+	jasUnsupportedMsg            = MarkAsBold("JFrog Advanced Security unsupported") + ": The version of JFrog Xray doesn't support " + MarkAsLink("JFrog Advanced Security", "https://jfrog.com/advanced-security") + ", so " + MarkAsBold("Contextual Analysis, Secret Detection, IaC and SAST Vulnerabilities Scanning") + " were skipped and only the dependencies were scanned. %s"
+	jasStatusUnknownMsg          = MarkAsBold("JFrog Advanced Security status unknown") + ": Frogbot couldn't check whether " + MarkAsLink("JFrog Advanced Security", "https://jfrog.com/advanced-security") + " is enabled on your system, so " + MarkAsBold("Contextual Analysis, Secret Detection, IaC and SAST Vulnerabilities Scanning") + " were skipped and only the dependencies were scanned."
 )
 
 // For review comment Frogbot creates on Scan PR
@@ -56,6 +94,21 @@ func GenerateReviewCommentContent(content string, writer OutputWriter) string {
 	return contentBuilder.String()
 }
 
+// Hidden markdown comment with the IDs of the findings that the comment describes, to be referenced by external systems
+func FindingIdsComment(findingIds ...string) string {
+	return MarkdownComment(FindingIdsCommentPrefix + strings.Join(findingIds, ", "))
+}
+
+// Returns the IDs of the findings that the given comment describes, from its hidden finding IDs comment
+func GetFindingIds(content string) []string {
+	_, findingIdsAndRest, found := strings.Cut(content, FindingIdsCommentPrefix)
+	if !found {
+		return nil
+	}
+	findingIds, _, _ := strings.Cut(findingIdsAndRest, ")")
+	return strings.Split(findingIds, ", ")
+}
+
 // When can't create review comment, create a fallback comment by adding the location description to the content as a prefix
 func GetFallbackReviewCommentContent(content string, location formats.Location) string {
 	var contentBuilder strings.Builder
@@ -64,22 +117,110 @@ func GetFallbackReviewCommentContent(content string, location formats.Location)
 	return contentBuilder.String()
 }
 
+// ScannedCommit is the commit of the source branch of a pull request that Frogbot scanned, recorded in the summary comment,
+// so the next scans of the pull request can tell what changed since.
+type ScannedCommit struct {
+	Hash   string
+	Status ScannedCommitStatus
+	// The commit of the target branch that the source branch was compared with
+	TargetHash string
+	// The hash of the configuration and the policy of the scan, which the results depend on
+	ConfigHash string
+}
+
+type ScannedCommitStatus string
+
+const (
+	// The summary comment reports no issues
+	ScannedCommitClean ScannedCommitStatus = "clean"
+	// The summary comment reports issues that don't fail the scan
+	ScannedCommitIssues ScannedCommitStatus = "issues"
+	// The summary comment reports issues that fail the scan
+	ScannedCommitFailed ScannedCommitStatus = "failed"
+)
+
+// Hidden markdown comment with the scanned commit, the result of its scan, the commit of the target branch and the hash of the configuration
+func ScannedCommitComment(commit ScannedCommit) string {
+	return MarkdownComment(fmt.Sprintf("%s%s %s %s %s", ScannedCommitCommentPrefix, commit.Hash, commit.Status, commit.TargetHash, commit.ConfigHash))
+}
+
+// Returns the scanned commit that the given comment records, or nil if it doesn't record one
+func GetScannedCommit(content string) *ScannedCommit {
+	_, commitAndRest, found := strings.Cut(content, ScannedCommitCommentPrefix)
+	if !found {
+		return nil
+	}
+	commit, _, _ := strings.Cut(commitAndRest, ")")
+	// The comments of earlier versions record only the scanned commit and the result of its scan
+	fields := strings.Fields(commit)
+	if len(fields) < 2 {
+		return nil
+	}
+	scannedCommit := &ScannedCommit{Hash: fields[0], Status: ScannedCommitStatus(fields[1])}
+	if len(fields) == 4 {
+		scannedCommit.TargetHash, scannedCommit.ConfigHash = fields[2], fields[3]
+	}
+	return scannedCommit
+}
+
 func IsFrogbotComment(content string) bool {
 	return strings.Contains(content, ReviewCommentId)
 }
 
+// Collapses the content of a previous Frogbot comment, so the pull request keeps what Frogbot reported at each revision of the pull request.
+// The collapsed comment isn't identified as a Frogbot comment anymore, so it isn't deleted or collapsed again by the next scans.
+func GetResolvedCommentContent(content string, writer OutputWriter) string {
+	content = strings.ReplaceAll(content, ReviewCommentId, ResolvedCommentId)
+	return writer.MarkAsDetails(localizedTitle(resolvedCommentTitle, writer), 0, fmt.Sprintf("\n\n%s\n\n", content))
+}
+
+// The comment that Frogbot adds when it starts to scan a pull request, so the reviewers know that the scan is running
+func GetScanProgressCommentContent(writer OutputWriter) string {
+	var contentBuilder strings.Builder
+	contentBuilder.WriteString(MarkdownComment(ScanProgressCommentId))
+	WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(scanInProgressTitle, writer), 3), "The results will replace this comment when the scan is completed.", footer(writer))
+	return contentBuilder.String()
+}
+
+// Replaces the content of the scan progress comment when the scan fails. The comment keeps its ID, so the next scan replaces it.
+func GetScanFailedCommentContent(reason string, writer OutputWriter) string {
+	var contentBuilder strings.Builder
+	contentBuilder.WriteString(MarkdownComment(ScanProgressCommentId))
+	WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(scanFailedTitle, writer), 3), MarkAsCodeSnippet(reason), footer(writer))
+	return contentBuilder.String()
+}
+
+func IsScanProgressComment(content string) bool {
+	return strings.Contains(content, ScanProgressCommentId)
+}
+
+// Returns true if the body is of a fix pull request or merge request that Frogbot opened. Both the banner and the simplified title contain the title.
+func IsFrogbotFixPullRequest(body string) bool {
+	return strings.Contains(body, GetSimplifiedTitle(VulnerabilitiesFixPrBannerSource)) || strings.Contains(body, GetSimplifiedTitle(VulnerabilitiesFixMrBannerSource))
+}
+
 func getFallbackCommentLocationDescription(location formats.Location) string {
 	return fmt.Sprintf("%s\nat %s (line %d)", MarkAsCodeSnippet(location.Snippet), MarkAsQuote(location.File), location.StartLine)
 }
 
 // Summary comment, including banner, footer wrapping the content with a decorator
 func GetMainCommentContent(contentForComments []string, issuesExists, isComment bool, writer OutputWriter) (comments []string) {
-	return ConvertContentToComments(contentForComments, writer, func(commentCount int, content string) string {
+	return LabelCommentParts(ConvertContentToComments(contentForComments, writer, func(commentCount int, content string) string {
 		if commentCount == 0 {
 			content = GetPRSummaryMainCommentDecorator(issuesExists, isComment, writer)(commentCount, content)
 		}
-		return GetFrogbotCommentBaseDecorator(writer)(commentCount, content)
-	})
+		return GetFrogbotCommentBaseDecorator(writer)(commentCount, GetCommentPartDecorator()(commentCount, content))
+	}))
+}
+
+// Summary comment for a pull request that didn't add issues, showing the no issues banner followed by the content
+func GetNoIssuesCommentContent(contentForComments []string, writer OutputWriter) (comments []string) {
+	return LabelCommentParts(ConvertContentToComments(contentForComments, writer, func(commentCount int, content string) string {
+		if commentCount == 0 {
+			content = GetPRSummaryMainCommentDecorator(false, true, writer)(commentCount, "") + content
+		}
+		return GetFrogbotCommentBaseDecorator(writer)(commentCount, GetCommentPartDecorator()(commentCount, content))
+	}))
 }
 
 // Adding markdown prefix to identify Frogbot comment and a footer with the link to the documentation
@@ -88,6 +229,9 @@ func GetFrogbotCommentBaseDecorator(writer OutputWriter) CommentDecorator {
 		comment := strings.Builder{}
 		comment.WriteString(MarkdownComment(ReviewCommentId))
 		WriteContent(&comment, content, footer(writer))
+		if details := runtimeDetailsContent(writer); details != "" {
+			WriteContent(&comment, details)
+		}
 		return comment.String()
 	}
 }
@@ -101,6 +245,12 @@ func GetPRSummaryMainCommentDecorator(issuesExists, isComment bool, writer Outpu
 		if customCommentTitle != "" {
 			WriteContent(&comment, writer.MarkAsTitle(MarkAsBold(customCommentTitle), 2))
 		}
+		if forkRepository := writer.ForkRepository(); forkRepository != "" && isComment {
+			WriteContent(&comment, fmt.Sprintf("🍴 This pull request is from the fork %s.\n", MarkAsBold(forkRepository)))
+		}
+		if scannedCommit := writer.ScannedCommit(); scannedCommit != nil && isComment {
+			comment.WriteString(ScannedCommitComment(*scannedCommit))
+		}
 		if issuesExists {
 			WriteContent(&comment, content)
 		}
@@ -141,6 +291,13 @@ func fixCVETitleSrc(vcsProvider vcsutils.VcsProvider) ImageSource {
 }
 
 func untitledForJasMsg(writer OutputWriter) string {
+	if writer.IsJasStatusUnknown() {
+		// The scan coverage was reduced, so the note is added even when extra messages are avoided
+		return writer.MarkAsDetails("Note", 0, fmt.Sprintf("\n%s\n%s", SectionDivider(), writer.MarkInCenter(jasStatusUnknownMsg)))
+	}
+	if reason := writer.JasUnsupportedReason(); reason != "" {
+		return writer.MarkAsDetails("Note", 0, fmt.Sprintf("\n%s\n%s", SectionDivider(), writer.MarkInCenter(fmt.Sprintf(jasUnsupportedMsg, reason))))
+	}
 	if writer.AvoidExtraMessages() || writer.IsEntitledForJas() {
 		return ""
 	}
@@ -148,9 +305,38 @@ func untitledForJasMsg(writer OutputWriter) string {
 }
 
 func footer(writer OutputWriter) string {
+	if customFooter := writer.Messages().Footer; customFooter != "" {
+		return fmt.Sprintf("%s\n%s", SectionDivider(), writer.MarkInCenter(customFooter))
+	}
 	return fmt.Sprintf("%s\n%s", SectionDivider(), writer.MarkInCenter(CommentGeneratedByFrogbot))
 }
 
+// Collapsed section with the versions and scan duration that produced the comment, if provided
+func runtimeDetailsContent(writer OutputWriter) string {
+	details := writer.RuntimeDetails()
+	if details == nil {
+		return ""
+	}
+	var contentBuilder strings.Builder
+	writeRuntimeDetail(&contentBuilder, "Frogbot version", details.FrogbotVersion)
+	writeRuntimeDetail(&contentBuilder, "Xray version", details.XrayVersion)
+	writeRuntimeDetail(&contentBuilder, "Xsc version", details.XscVersion)
+	if writer.IsEntitledForJas() {
+		writeRuntimeDetail(&contentBuilder, "Analyzer Manager version", details.AnalyzerManagerVersion)
+	}
+	if details.ScanDuration > 0 {
+		writeRuntimeDetail(&contentBuilder, "Scan duration", details.ScanDuration.Round(time.Second).String())
+	}
+	return writer.MarkAsDetails(localizedTitle(runtimeDetailsTitle, writer), 0, fmt.Sprintf("\n%s\n", contentBuilder.String()))
+}
+
+func writeRuntimeDetail(builder *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	WriteContent(builder, MarkAsBullet(fmt.Sprintf("%s: %s", name, value)))
+}
+
 // Summary content
 
 func ScanSummaryContent(issues issues.ScansIssuesCollection, context results.ResultContext, includeSecrets bool, writer OutputWriter) string {
@@ -166,7 +352,7 @@ func ScanSummaryContent(issues issues.ScansIssuesCollection, context results.Res
 		totalIssues += issues.GetTotalVulnerabilities(includeSecrets)
 	}
 	// Title
-	WriteContent(&contentBuilder, writer.MarkAsTitle(scanSummaryTitle, 2))
+	WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(scanSummaryTitle, writer), 2))
 	if issues.HasErrors() {
 		WriteContent(&contentBuilder, MarkAsBullet(fmt.Sprintf("Frogbot attempted to scan for %s but encountered an error.", getResultsContextString(context))))
 		return contentBuilder.String()
@@ -260,7 +446,7 @@ func toSeverityDetails(severities map[severityutils.Severity]int, writer OutputW
 			if contentBuilder.Len() > 0 {
 				contentBuilder.WriteString(writer.Separator())
 			}
-			contentBuilder.WriteString(fmt.Sprintf("%s %d %s", writer.SeverityIcon(severity), count, severity.String()))
+			contentBuilder.WriteString(fmt.Sprintf("%s %d %s", writer.SeverityIcon(severity), count, writer.Messages().severity(severity.String())))
 		}
 	}
 	return contentBuilder.String()
@@ -275,6 +461,10 @@ func PolicyViolationsContent(issues issues.ScansIssuesCollection, writer OutputW
 	}
 	policyViolationContent = append(policyViolationContent, getSecurityViolationsContent(issues, writer)...)
 	policyViolationContent = append(policyViolationContent, getLicenseViolationsContent(issues, writer)...)
+	if len(policyViolationContent) == 0 {
+		// The sections of the violations are hidden
+		return []string{}
+	}
 	return ConvertContentToComments(policyViolationContent, writer, getDecoratorWithPolicyViolationTitle(writer))
 }
 
@@ -282,7 +472,7 @@ func getDecoratorWithPolicyViolationTitle(writer OutputWriter) CommentDecorator
 	return func(commentCount int, content string) string {
 		contentBuilder := strings.Builder{}
 		// Decorate each part of the split content with a title as prefix and return the content
-		WriteContent(&contentBuilder, writer.MarkAsTitle(policyViolationTitle, 2))
+		WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(policyViolationTitle, writer), 2))
 		WriteContent(&contentBuilder, content)
 		return contentBuilder.String()
 	}
@@ -291,11 +481,19 @@ func getDecoratorWithPolicyViolationTitle(writer OutputWriter) CommentDecorator
 // Security Violations
 
 func getSecurityViolationsContent(issues issues.ScansIssuesCollection, writer OutputWriter) (content []string) {
-	if len(issues.ScaViolations) == 0 {
+	if len(issues.ScaViolations) == 0 || !writer.ReportingOptions().IsSectionShown(VulnerabilitiesSection) {
 		return []string{}
 	}
-	content = append(content, getSecurityViolationsSummaryTable(issues.ScaViolations, writer))
-	content = append(content, getScaSecurityIssueDetailsContent(issues.ScaViolations, true, writer)...)
+	applicabilityNote := getApplicableOnlyNote(issues.ScaViolations, writer)
+	violations, overflowNote := limitTableRows(issues.ScaViolations, writer)
+	content = append(content, getSecurityViolationsSummaryTable(violations, writer))
+	if overflowNote != "" {
+		content = append(content, overflowNote)
+	}
+	if applicabilityNote != "" {
+		content = append(content, applicabilityNote)
+	}
+	content = append(content, getScaSecurityIssueDetailsContent(violations, true, writer)...)
 	return ConvertContentToComments(content, writer, getDecoratorWithSecurityViolationTitle(writer))
 }
 
@@ -303,7 +501,7 @@ func getDecoratorWithSecurityViolationTitle(writer OutputWriter) CommentDecorato
 	return func(commentCount int, content string) string {
 		contentBuilder := strings.Builder{}
 		// Decorate each part of the split content with a title as prefix and return the content
-		WriteContent(&contentBuilder, writer.MarkAsTitle(securityViolationTitle, 3))
+		WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(securityViolationTitle, writer), 3))
 		WriteContent(&contentBuilder, content)
 		return contentBuilder.String()
 	}
@@ -312,9 +510,19 @@ func getDecoratorWithSecurityViolationTitle(writer OutputWriter) CommentDecorato
 func getSecurityViolationsSummaryTable(violations []formats.VulnerabilityOrViolationRow, writer OutputWriter) string {
 	// Construct table
 	columns := []string{"Severity", "ID"}
+	if writer.Exploitability() != nil {
+		columns = append(columns, "EPSS", "KEV")
+	}
 	if writer.IsShowingCaColumn() {
 		columns = append(columns, "Contextual Analysis")
 	}
+	if writer.DependencyScopes() != nil {
+		columns = append(columns, "Scope")
+	}
+	showWorkingDirs := len(getScaWorkingDirs(violations...)) > 1
+	if showWorkingDirs {
+		columns = append(columns, "Working Directories")
+	}
 	table := NewMarkdownTable(append(columns, "Direct Dependencies", "Impacted Dependency", "Watch Name")...).SetDelimiter(writer.Separator())
 	if _, ok := writer.(*SimplifiedOutput); ok {
 		// The values in this cell can be potentially large, since SimplifiedOutput does not support tags, we need to show each value in a separate row.
@@ -325,9 +533,18 @@ func getSecurityViolationsSummaryTable(violations []formats.VulnerabilityOrViola
 	// Construct rows
 	for _, violation := range violations {
 		row := []CellData{{writer.FormattedSeverity(violation.Severity, violation.Applicable)}, getCveIdsCellData(violation.Cves, violation.IssueId)}
+		if writer.Exploitability() != nil {
+			row = append(row, getExploitabilityCellsData(writer.Exploitability(), violation.Cves)...)
+		}
 		if writer.IsShowingCaColumn() {
 			row = append(row, NewCellData(violation.Applicable))
 		}
+		if writer.DependencyScopes() != nil {
+			row = append(row, getDependencyScopeCellData(writer.DependencyScopes(), violation.Components))
+		}
+		if showWorkingDirs {
+			row = append(row, NewCellData(getScaWorkingDirs(violation)...))
+		}
 		row = append(row,
 			getDirectDependenciesCellData(violation.Components),
 			NewCellData(results.GetDependencyId(violation.ImpactedDependencyName, violation.ImpactedDependencyVersion)),
@@ -341,11 +558,15 @@ func getSecurityViolationsSummaryTable(violations []formats.VulnerabilityOrViola
 // License violations
 
 func getLicenseViolationsContent(issues issues.ScansIssuesCollection, writer OutputWriter) (content []string) {
-	if len(issues.LicensesViolations) == 0 {
+	if len(issues.LicensesViolations) == 0 || !writer.ReportingOptions().IsSectionShown(LicensesSection) {
 		return []string{}
 	}
-	content = append(content, getLicenseViolationsSummaryTable(issues.LicensesViolations, writer))
-	content = append(content, getLicenseViolationsDetailsContent(issues.LicensesViolations, writer)...)
+	licenseViolations, overflowNote := limitTableRows(issues.LicensesViolations, writer)
+	content = append(content, getLicenseViolationsSummaryTable(licenseViolations, writer))
+	if overflowNote != "" {
+		content = append(content, overflowNote)
+	}
+	content = append(content, getLicenseViolationsDetailsContent(licenseViolations, writer)...)
 	return ConvertContentToComments(content, writer, getDecoratorWithLicenseViolationTitle(writer))
 }
 
@@ -353,7 +574,7 @@ func getDecoratorWithLicenseViolationTitle(writer OutputWriter) CommentDecorator
 	return func(commentCount int, content string) string {
 		contentBuilder := strings.Builder{}
 		// Decorate each part of the split content with a title as prefix and return the content
-		WriteContent(&contentBuilder, writer.MarkAsTitle(licenseViolationTitle, 3))
+		WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(licenseViolationTitle, writer), 3))
 		WriteContent(&contentBuilder, content)
 		return contentBuilder.String()
 	}
@@ -399,7 +620,7 @@ func getLicenseViolationsDetailsContent(licenseViolations []formats.LicenseViola
 	// Split content if it exceeds the size limit and decorate each comment with title as prefix
 	return ConvertContentToComments(content, writer, func(commentCount int, detailsContent string) string {
 		contentBuilder := strings.Builder{}
-		WriteContent(&contentBuilder, writer.MarkAsTitle(issuesDetailsSubTitle, 3))
+		WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(issuesDetailsSubTitle, writer), 3))
 		WriteContent(&contentBuilder, detailsContent)
 		return contentBuilder.String()
 	})
@@ -430,10 +651,18 @@ func getScaLicenseViolationDetails(violation formats.LicenseViolationRow, writer
 // Sca Vulnerabilities
 
 func GetVulnerabilitiesContent(vulnerabilities []formats.VulnerabilityOrViolationRow, writer OutputWriter) (content []string) {
-	if len(vulnerabilities) == 0 {
+	if len(vulnerabilities) == 0 || !writer.ReportingOptions().IsSectionShown(VulnerabilitiesSection) {
 		return []string{}
 	}
+	applicabilityNote := getApplicableOnlyNote(vulnerabilities, writer)
+	vulnerabilities, overflowNote := limitTableRows(vulnerabilities, writer)
 	content = append(content, writer.MarkInCenter(getVulnerabilitiesSummaryTable(vulnerabilities, writer)))
+	if overflowNote != "" {
+		content = append(content, overflowNote)
+	}
+	if applicabilityNote != "" {
+		content = append(content, applicabilityNote)
+	}
 	content = append(content, getScaSecurityIssueDetailsContent(vulnerabilities, false, writer)...)
 	return ConvertContentToComments(content, writer, getDecoratorWithScaVulnerabilitiesTitle(writer))
 }
@@ -442,7 +671,7 @@ func getDecoratorWithScaVulnerabilitiesTitle(writer OutputWriter) CommentDecorat
 	return func(commentCount int, content string) string {
 		contentBuilder := strings.Builder{}
 		// Decorate each part of the split content with a title as prefix and return the content
-		WriteContent(&contentBuilder, writer.MarkAsTitle(vulnerableDependenciesTitle, 3))
+		WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(vulnerableDependenciesTitle, writer), 3))
 		WriteContent(&contentBuilder, content)
 		return contentBuilder.String()
 	}
@@ -451,9 +680,20 @@ func getDecoratorWithScaVulnerabilitiesTitle(writer OutputWriter) CommentDecorat
 func getVulnerabilitiesSummaryTable(vulnerabilities []formats.VulnerabilityOrViolationRow, writer OutputWriter) string {
 	// Construct table
 	columns := []string{"Severity", "ID"}
+	if writer.Exploitability() != nil {
+		columns = append(columns, "EPSS", "KEV")
+	}
 	if writer.IsShowingCaColumn() {
 		columns = append(columns, "Contextual Analysis")
 	}
+	if writer.DependencyScopes() != nil {
+		columns = append(columns, "Scope")
+	}
+	// Issues that are found in more than one working directory are merged into a single row, that lists its working directories
+	showWorkingDirs := len(getScaWorkingDirs(vulnerabilities...)) > 1
+	if showWorkingDirs {
+		columns = append(columns, "Working Directories")
+	}
 	columns = append(columns, "Direct Dependencies", "Impacted Dependency", "Fixed Versions")
 	table := NewMarkdownTable(columns...).SetDelimiter(writer.Separator())
 	if _, ok := writer.(*SimplifiedOutput); ok {
@@ -465,9 +705,18 @@ func getVulnerabilitiesSummaryTable(vulnerabilities []formats.VulnerabilityOrVio
 	// Construct rows
 	for _, vulnerability := range vulnerabilities {
 		row := []CellData{{writer.FormattedSeverity(vulnerability.Severity, vulnerability.Applicable)}, getCveIdsCellData(vulnerability.Cves, vulnerability.IssueId)}
+		if writer.Exploitability() != nil {
+			row = append(row, getExploitabilityCellsData(writer.Exploitability(), vulnerability.Cves)...)
+		}
 		if writer.IsShowingCaColumn() {
 			row = append(row, NewCellData(vulnerability.Applicable))
 		}
+		if writer.DependencyScopes() != nil {
+			row = append(row, getDependencyScopeCellData(writer.DependencyScopes(), vulnerability.Components))
+		}
+		if showWorkingDirs {
+			row = append(row, NewCellData(getScaWorkingDirs(vulnerability)...))
+		}
 		row = append(row,
 			getDirectDependenciesCellData(vulnerability.Components),
 			NewCellData(fmt.Sprintf("%s %s", vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion)),
@@ -478,12 +727,524 @@ func getVulnerabilitiesSummaryTable(vulnerabilities []formats.VulnerabilityOrVio
 	return table.Build()
 }
 
+// Lists the issues of the target branch that the pull request resolves
+func FixedIssuesContent(fixedIssues []formats.VulnerabilityOrViolationRow, writer OutputWriter) string {
+	if len(fixedIssues) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "ID", "Impacted Dependency").SetDelimiter(writer.Separator())
+	// The same issue can be reported both as a vulnerability and as a violation
+	addedIssues := datastructures.MakeSet[string]()
+	for _, issue := range fixedIssues {
+		ids := getCveIdsCellData(issue.Cves, issue.IssueId)
+		impactedDependency := fmt.Sprintf("%s %s", issue.ImpactedDependencyName, issue.ImpactedDependencyVersion)
+		if key := strings.Join(ids, ",") + impactedDependency; !addedIssues.Exists(key) {
+			addedIssues.Add(key)
+			table.AddRowWithCellData(NewCellData(writer.FormattedSeverity(issue.Severity, "")), ids, NewCellData(impactedDependency))
+		}
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(fixedByPullRequestTitle, writer), 2),
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// UnsupportedFixRow describes a vulnerable dependency that can't be fixed automatically, and needs to be remediated manually
+type UnsupportedFixRow struct {
+	formats.VulnerabilityOrViolationRow
+	SuggestedFixedVersion string
+	Reason                string
+}
+
+// Lists the vulnerable dependencies that Frogbot couldn't fix automatically
+func UnsupportedFixesContent(unsupportedFixes []UnsupportedFixRow, writer OutputWriter) string {
+	if len(unsupportedFixes) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "ID", "Impacted Dependency", "Fixed Version", "Reason").SetDelimiter(writer.Separator())
+	for _, unsupportedFix := range unsupportedFixes {
+		table.AddRowWithCellData(
+			NewCellData(writer.FormattedSeverity(unsupportedFix.Severity, "")),
+			getCveIdsCellData(unsupportedFix.Cves, unsupportedFix.IssueId),
+			NewCellData(fmt.Sprintf("%s %s", unsupportedFix.ImpactedDependencyName, unsupportedFix.ImpactedDependencyVersion)),
+			NewCellData(unsupportedFix.SuggestedFixedVersion),
+			NewCellData(unsupportedFix.Reason),
+		)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(unsupportedFixesTitle, writer), 2),
+		"The following vulnerable dependencies can't be fixed automatically and need to be remediated manually.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// FailedFixRow describes a vulnerable dependency that failed to be updated, so it's left out of the aggregated pull request
+type FailedFixRow struct {
+	formats.VulnerabilityOrViolationRow
+	SuggestedFixedVersion string
+	Reason                string
+}
+
+// Lists the vulnerable dependencies that failed to be updated, while the other dependencies of the aggregated pull request were fixed
+func FailedFixesContent(failedFixes []FailedFixRow, writer OutputWriter) string {
+	if len(failedFixes) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "ID", "Impacted Dependency", "Fixed Version", "Reason").SetDelimiter(writer.Separator())
+	for _, failedFix := range failedFixes {
+		table.AddRowWithCellData(
+			NewCellData(writer.FormattedSeverity(failedFix.Severity, "")),
+			getCveIdsCellData(failedFix.Cves, failedFix.IssueId),
+			NewCellData(fmt.Sprintf("%s %s", failedFix.ImpactedDependencyName, failedFix.ImpactedDependencyVersion)),
+			NewCellData(failedFix.SuggestedFixedVersion),
+			NewCellData(failedFix.Reason),
+		)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(failedFixesTitle, writer), 2),
+		"The updates of the following vulnerable dependencies failed, so their changes were rolled back and they need to be fixed manually.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// ReleaseNotesRow holds the links to the release information of a dependency that is upgraded by a fix pull request
+type ReleaseNotesRow struct {
+	ImpactedDependencyName    string
+	ImpactedDependencyVersion string
+	FixedVersion              string
+	ReleaseNotesUrl           string
+	DiffUrl                   string
+}
+
+// Lists the release notes and the changes of the upgraded dependencies, to help reviewers assess the upgrade risk
+func ReleaseNotesContent(rows []ReleaseNotesRow, writer OutputWriter) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Dependency", "Current Version", "Fixed Version", "Release Notes", "Changes").SetDelimiter(writer.Separator())
+	for _, row := range rows {
+		table.AddRow(row.ImpactedDependencyName, row.ImpactedDependencyVersion, row.FixedVersion, markAsLinkIfExists(row.FixedVersion, row.ReleaseNotesUrl), markAsLinkIfExists("Compare", row.DiffUrl))
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(releaseNotesTitle, writer), 2),
+		"Review the changes of the upgraded dependencies before merging this pull request.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+type UpdatedWorkspacesRow struct {
+	ImpactedDependencyName string
+	FixedVersion           string
+	Workspaces             []string
+}
+
+// Lists the workspaces of a monorepo whose package.json files the pull request updates, since the root lockfile is regenerated for all of them
+func UpdatedWorkspacesContent(rows []UpdatedWorkspacesRow, writer OutputWriter) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Dependency", "Fixed Version", "Workspaces").SetDelimiter(writer.Separator())
+	for _, row := range rows {
+		table.AddRow(row.ImpactedDependencyName, row.FixedVersion, strings.Join(row.Workspaces, ", "))
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(updatedWorkspacesTitle, writer), 2),
+		"The dependencies are updated in the package.json files of these workspaces, and the lockfile of the root is regenerated.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// UpgradeRiskRow holds the estimated risk of the upgrade of a dependency by a fix pull request
+type UpgradeRiskRow struct {
+	ImpactedDependencyName    string
+	ImpactedDependencyVersion string
+	FixedVersion              string
+	Risk                      string
+	VersionChange             string
+	TransitiveChanges         int
+	HasTests                  bool
+}
+
+// Lists the estimated risk of breaking the project by each upgrade, so reviewers can prioritize the fix pull requests
+func UpgradeRiskContent(rows []UpgradeRiskRow, writer OutputWriter) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Dependency", "Current Version", "Fixed Version", "Risk", "Version Change", "Transitive Changes", "Tests Found").SetDelimiter(writer.Separator())
+	for _, row := range rows {
+		hasTests := "No"
+		if row.HasTests {
+			hasTests = "Yes"
+		}
+		table.AddRow(row.ImpactedDependencyName, row.ImpactedDependencyVersion, row.FixedVersion, MarkAsBold(row.Risk), row.VersionChange, strconv.Itoa(row.TransitiveChanges), hasTests)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(upgradeRiskTitle, writer), 2),
+		"The risk is estimated by the version change of the upgrade, the transitive dependencies it changes in the lockfiles, and whether the project has tests.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// A line with the number of new and resolved vulnerabilities since the last scan of the branch, so teams can track their progress over time
+func ScanDeltaContent(delta string) string {
+	return fmt.Sprintf("\n📈 %s %s\n", MarkAsBold("Since the last scan:"), delta)
+}
+
+func markAsLinkIfExists(content, link string) string {
+	if link == "" {
+		return "-"
+	}
+	return MarkAsLink(content, link)
+}
+
+// Lists the findings that match an Xray ignore rule in a collapsed section, so the accepted risks remain visible
+func IgnoredIssuesContent(issuesCollection issues.ScansIssuesCollection, includeSecrets bool, writer OutputWriter) string {
+	if !issuesCollection.IgnoredIssuesExists(includeSecrets) {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "Type", "ID", "Impacted Dependency / Location").SetDelimiter(writer.Separator())
+	// The same issue can be reported both as a vulnerability and as a violation
+	addedIssues := datastructures.MakeSet[string]()
+	for _, issue := range issuesCollection.IgnoredScaIssues {
+		ids := getCveIdsCellData(issue.Cves, issue.IssueId)
+		impactedDependency := fmt.Sprintf("%s %s", issue.ImpactedDependencyName, issue.ImpactedDependencyVersion)
+		if key := strings.Join(ids, ",") + impactedDependency; !addedIssues.Exists(key) {
+			addedIssues.Add(key)
+			table.AddRowWithCellData(NewCellData(writer.FormattedSeverity(issue.Severity, "")), NewCellData("SCA"), ids, NewCellData(impactedDependency))
+		}
+	}
+	for _, violation := range issuesCollection.IgnoredLicensesViolations {
+		table.AddRow(writer.FormattedSeverity(violation.Severity, ""), "License", violation.LicenseKey, fmt.Sprintf("%s %s", violation.ImpactedDependencyName, violation.ImpactedDependencyVersion))
+	}
+	addSourceCodeRows := func(issueType string, rows []formats.SourceCodeRow) {
+		for _, row := range rows {
+			table.AddRow(writer.FormattedSeverity(row.Severity, ""), issueType, row.RuleId, fmt.Sprintf("%s:%d", row.File, row.StartLine))
+		}
+	}
+	addSourceCodeRows("IaC", issuesCollection.IgnoredIacIssues)
+	if includeSecrets {
+		addSourceCodeRows("Secrets", issuesCollection.IgnoredSecretsIssues)
+	}
+	addSourceCodeRows("SAST", issuesCollection.IgnoredSastIssues)
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		"The following findings match Xray ignore rules, so they are not reported as issues.\n",
+		table.Build(),
+	)
+	return "\n" + writer.MarkAsDetails(localizedTitle(ignoredFindingsTitle, writer), 2, fmt.Sprintf("\n%s\n", contentBuilder.String()))
+}
+
+// Lists the direct dependencies of internal namespaces that have a higher version in their public registry
+func DependencyConfusionContent(risks []issues.DependencyConfusionRisk, writer OutputWriter) string {
+	if len(risks) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Package Type", "Dependency", "Version", "Internal Namespace", "Public Version").SetDelimiter(writer.Separator())
+	for _, risk := range risks {
+		table.AddRow(risk.PackageType, risk.PackageName, risk.Version, risk.Namespace, markAsLinkIfExists(risk.PublicVersion, risk.PublicUrl))
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(dependencyConfusionTitle, writer), 2),
+		"The following dependencies belong to internal namespaces, but a higher version of them exists in the public registry. "+
+			"Package managers that resolve dependencies from the public registry may install the public package instead of the internal one. "+
+			"Make sure these dependencies are resolved only from the internal registry, and consider claiming their names in the public registry.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// Lists the vulnerabilities of the base images and the OS packages of the Dockerfiles
+func DockerImageContent(vulnerabilities []issues.DockerImageVulnerability, writer OutputWriter) string {
+	if len(vulnerabilities) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "ID", "Dockerfile", "Type", "Component", "Fixed Versions").SetDelimiter(writer.Separator())
+	for _, vulnerability := range vulnerabilities {
+		ids := NewCellData(vulnerability.IssueId)
+		if len(vulnerability.Cves) > 0 {
+			ids = NewCellData(vulnerability.Cves...)
+		}
+		table.AddRowWithCellData(
+			NewCellData(writer.FormattedSeverity(vulnerability.Severity, "")),
+			ids,
+			NewCellData(fmt.Sprintf("%s:%d", vulnerability.Dockerfile, vulnerability.Line)),
+			NewCellData(vulnerability.ComponentType),
+			NewCellData(fmt.Sprintf("%s %s", vulnerability.Name, vulnerability.Version)),
+			NewCellData(vulnerability.FixedVersions...),
+		)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(dockerImageTitle, writer), 2),
+		"The following vulnerabilities were found in the base images of the Dockerfiles, and in the OS packages that the Dockerfiles install.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// Lists the vulnerabilities of the packages that the Conda environment files and the OS package lists pin
+func SystemPackagesContent(vulnerabilities []issues.SystemPackageVulnerability, writer OutputWriter) string {
+	if len(vulnerabilities) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "ID", "Package List", "Type", "Package", "Fixed Versions").SetDelimiter(writer.Separator())
+	for _, vulnerability := range vulnerabilities {
+		ids := NewCellData(vulnerability.IssueId)
+		if len(vulnerability.Cves) > 0 {
+			ids = NewCellData(vulnerability.Cves...)
+		}
+		table.AddRowWithCellData(
+			NewCellData(writer.FormattedSeverity(vulnerability.Severity, "")),
+			ids,
+			NewCellData(fmt.Sprintf("%s:%d", vulnerability.File, vulnerability.Line)),
+			NewCellData(vulnerability.PackageType),
+			NewCellData(fmt.Sprintf("%s %s", vulnerability.Name, vulnerability.Version)),
+			NewCellData(vulnerability.FixedVersions...),
+		)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(systemPackagesTitle, writer), 2),
+		"The following vulnerabilities were found in the packages that the Conda environment files and the apt and apk package lists pin.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// Lists the dependencies that the curation policies of Artifactory block, with the policies that block them
+func CurationContent(blockedPackages []issues.CurationBlockedPackage, writer OutputWriter) string {
+	if len(blockedPackages) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Package Type", "Blocked Package", "Direct Dependency", "Blocking Reason", "Violated Policies", "Recommendations").SetDelimiter(writer.Separator())
+	for _, blockedPackage := range blockedPackages {
+		var directDependency string
+		if blockedPackage.ParentName != "" {
+			directDependency = fmt.Sprintf("%s %s", blockedPackage.ParentName, blockedPackage.ParentVersion)
+		}
+		var policies, recommendations []string
+		for _, policy := range blockedPackage.Policies {
+			policies = append(policies, fmt.Sprintf("%s: %s", policy.Name, policy.Condition))
+			if policy.Recommendation != "" {
+				recommendations = append(recommendations, policy.Recommendation)
+			}
+		}
+		table.AddRowWithCellData(
+			NewCellData(blockedPackage.PackageType),
+			NewCellData(fmt.Sprintf("%s %s", blockedPackage.PackageName, blockedPackage.Version)),
+			NewCellData(directDependency),
+			NewCellData(blockedPackage.BlockingReason),
+			NewCellData(policies...),
+			NewCellData(recommendations...),
+		)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(curationTitle, writer), 2),
+		"The following dependencies are blocked by the curation policies of Artifactory, so they can't be installed once the pull request is merged. "+
+			"Replace them with versions that the policies allow.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// Suggests the alternative packages of the vulnerable dependencies that have no fixed version, with the remediation of their JFrog research
+func AlternativePackagesContent(suggestions []issues.AlternativePackageSuggestion, writer OutputWriter) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "ID", "Vulnerable Dependency", "Alternatives").SetDelimiter(writer.Separator())
+	var remediations []string
+	for _, suggestion := range suggestions {
+		var alternatives []string
+		for _, alternative := range suggestion.Alternatives {
+			name := alternative.Name
+			if alternative.Url != "" {
+				name = MarkAsLink(alternative.Name, alternative.Url)
+			}
+			if alternative.Note != "" {
+				name = fmt.Sprintf("%s: %s", name, alternative.Note)
+			}
+			alternatives = append(alternatives, name)
+		}
+		dependency := fmt.Sprintf("%s %s", suggestion.PackageName, suggestion.Version)
+		table.AddRowWithCellData(
+			NewCellData(writer.FormattedSeverity(suggestion.Severity, "")),
+			NewCellData(suggestion.IssueIds...),
+			NewCellData(dependency),
+			NewCellData(alternatives...),
+		)
+		if len(suggestion.Remediations) > 0 && !writer.ReportingOptions().HideResearchDetails {
+			remediations = append(remediations, writer.MarkAsDetails("Remediation of "+dependency, 3, "\n\n"+strings.Join(suggestion.Remediations, "\n\n")+"\n\n"))
+		}
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(alternativePackagesTitle, writer), 2),
+		"The following vulnerable dependencies have no fixed version, so they can't be fixed by upgrading them. "+
+			"Consider replacing them with the suggested alternatives.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	WriteContent(&contentBuilder, remediations...)
+	return contentBuilder.String()
+}
+
+// Mentions the owners of the paths that the pull request adds Critical or High findings to
+func SecurityChampionsContent(owners []string, writer OutputWriter) string {
+	if len(owners) == 0 {
+		return ""
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(securityChampionsTitle, writer), 2),
+		fmt.Sprintf("%s, this pull request adds Critical or High findings to paths that you own. Please review them.\n", strings.Join(owners, ", ")),
+	)
+	return contentBuilder.String()
+}
+
+// SecretRotationRow holds the location and the rotation steps of an exposed secret
+type SecretRotationRow struct {
+	File       string
+	StartLine  int
+	SecretType string
+	// Active or Inactive if the secret was validated with its provider
+	Status   string
+	Guidance string
+}
+
+// The urgent comment of a pull request that is blocked because it exposes new secrets. The exposed secrets are compromised even if the
+// pull request isn't merged, so the comment asks to rotate them and links to the security contacts that were alerted by email.
+func SecretsRotationComment(rows []SecretRotationRow, securityContacts []string, writer OutputWriter) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Location", "Type", "Status", "How to Rotate").SetDelimiter(writer.Separator())
+	table.GetColumnInfo("Status").OmitEmpty = true
+	for _, row := range rows {
+		table.AddRow(fmt.Sprintf("%s:%d", row.File, row.StartLine), row.SecretType, row.Status, row.Guidance)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(secretsRotationTitle, writer), 1),
+		MarkAsBold("This pull request is blocked because it exposes new secrets.")+" Removing them from the code isn't enough, since they remain in the history of the branch: rotate every secret below before merging.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	if len(securityContacts) > 0 {
+		var contacts []string
+		for _, contact := range securityContacts {
+			contacts = append(contacts, MarkAsLink(contact, "mailto:"+contact))
+		}
+		WriteContent(&contentBuilder, fmt.Sprintf("\nThe security contacts were alerted by email. Contact %s for help with the rotation.", strings.Join(contacts, ", ")))
+	}
+	return GetFrogbotCommentBaseDecorator(writer)(0, contentBuilder.String())
+}
+
+// Lists the actions of the GitHub Actions workflows that are used in vulnerable versions, or by mutable references
+func GitHubActionsContent(actionIssues []issues.GitHubActionIssue, writer OutputWriter) string {
+	if len(actionIssues) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "ID", "Workflow", "Type", "Action", "Fixed Version").SetDelimiter(writer.Separator())
+	for _, actionIssue := range actionIssues {
+		ids := NewCellData(actionIssue.IssueId)
+		if len(actionIssue.Cves) > 0 {
+			ids = NewCellData(actionIssue.Cves...)
+		}
+		table.AddRowWithCellData(
+			NewCellData(writer.FormattedSeverity(actionIssue.Severity, "")),
+			ids,
+			NewCellData(fmt.Sprintf("%s:%d", actionIssue.Workflow, actionIssue.Line)),
+			NewCellData(actionIssue.Type),
+			NewCellData(fmt.Sprintf("%s@%s", actionIssue.Action, actionIssue.Ref)),
+			NewCellData(actionIssue.FixedVersion),
+		)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(gitHubActionsTitle, writer), 2),
+		"The following actions of the GitHub Actions workflows are used in vulnerable versions, or by tags and branches that may be moved to other commits. Pin the actions to the commit SHAs of fixed versions.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// Lists the findings of the third-party scanners, attributed to the scanners that produced them
+func ExternalScannersContent(externalIssues []issues.ExternalScannerIssue, writer OutputWriter) string {
+	if len(externalIssues) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Severity", "Scanner", "Rule", "Location", "Finding").SetDelimiter(writer.Separator())
+	for _, externalIssue := range externalIssues {
+		location := externalIssue.File
+		if externalIssue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", externalIssue.File, externalIssue.Line)
+		}
+		// The pipes of the messages would split their cells
+		table.AddRow(writer.FormattedSeverity(externalIssue.Severity, ""), externalIssue.Scanner, externalIssue.RuleId, location, strings.ReplaceAll(externalIssue.Message, "|", "\\|"))
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(externalScannersTitle, writer), 2),
+		"The following findings were reported by other scanners that ran in this pipeline.\n",
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// Lists the findings and dependencies that break the blocking rules of the repository policy file
+func PolicyRuleViolationsContent(violations []issues.PolicyRuleViolation, policyFilePath string, writer OutputWriter) string {
+	if len(violations) == 0 {
+		return ""
+	}
+	table := NewMarkdownTable("Rule", "Finding / Dependency", "Reason").SetDelimiter(writer.Separator())
+	for _, violation := range violations {
+		table.AddRow(violation.Rule, violation.Subject, violation.Reason)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(policyRulesTitle, writer), 2),
+		fmt.Sprintf("The following findings and dependencies break the blocking rules of the %s file of the target branch, so the pull request is blocked.\n", MarkAsQuote(policyFilePath)),
+		writer.MarkInCenter(table.Build()),
+	)
+	return contentBuilder.String()
+}
+
+// Points to the CycloneDX SBOM that was generated by the run that opened the pull request
+func SbomContent(sbomFileName, ciRunUrl string, writer OutputWriter) string {
+	if sbomFileName == "" {
+		return ""
+	}
+	runDescription := "this Frogbot run"
+	if ciRunUrl != "" {
+		runDescription = MarkAsLink(runDescription, ciRunUrl)
+	}
+	var contentBuilder strings.Builder
+	WriteContent(&contentBuilder,
+		writer.MarkAsTitle(localizedTitle(sbomTitle, writer), 2),
+		fmt.Sprintf("A CycloneDX SBOM of the repository, %s, was generated by %s and can be found among its artifacts.", MarkAsQuote(sbomFileName), runDescription),
+	)
+	return contentBuilder.String()
+}
+
 // Applicable CVE Evidence
 
 func ApplicableCveReviewContent(issue issues.ApplicableEvidences, writer OutputWriter) string {
 	var contentBuilder strings.Builder
 	WriteContent(&contentBuilder,
-		writer.MarkAsTitle(contextualAnalysisTitle, 2),
+		writer.MarkAsTitle(localizedTitle(contextualAnalysisTitle, writer), 2),
 		writer.MarkInCenter(GetApplicabilityDescriptionTable(issue.Severity, issue.IssueId, issue.ImpactedDependency, issue.Evidence.Reason, writer)),
 		writer.MarkAsDetails("Description", 3, "\n"+issue.ScannerDescription+"\n"),
 		writer.MarkAsDetails("CVE details", 3, "\n"+issue.CveSummary+"\n"),
@@ -502,22 +1263,24 @@ func GetApplicabilityDescriptionTable(severity, issueId, impactedDependency, fin
 // JAS
 
 func IacReviewContent(violation bool, writer OutputWriter, issues ...formats.SourceCodeRow) string {
-	var contentBuilder strings.Builder
-	WriteContent(&contentBuilder,
-		writer.MarkAsTitle(fmt.Sprintf("%s %s", iacTitle, getIssueType(violation)), 2),
-		writer.MarkInCenter(getJasIssueDescriptionTable(writer, issues...)),
-		getJasFullDescription(violation, writer, getBaseJasDetailsTable, issues...),
-	)
-	return contentBuilder.String()
+	return getJasReviewContent(iacTitle, violation, writer, getJasIssueDescriptionTable, getBaseJasDetailsTable, issues...)
 }
 
 func SastReviewContent(violation bool, writer OutputWriter, issues ...formats.SourceCodeRow) string {
+	return getJasReviewContent(sastTitle, violation, writer, getJasIssueDescriptionTable, getSastRuleFullDescriptionTable, issues...)
+}
+
+func getJasReviewContent(title string, violation bool, writer OutputWriter, generateDescriptionTable func(OutputWriter, ...formats.SourceCodeRow) string, generateRuleTable func(formats.ScannerInfo, OutputWriter) *MarkdownTableBuilder, issues ...formats.SourceCodeRow) string {
+	issues, overflowNote := limitTableRows(issues, writer)
 	var contentBuilder strings.Builder
 	WriteContent(&contentBuilder,
-		writer.MarkAsTitle(fmt.Sprintf("%s %s", sastTitle, getIssueType(violation)), 2),
-		writer.MarkInCenter(getJasIssueDescriptionTable(writer, issues...)),
-		getJasFullDescription(violation, writer, getSastRuleFullDescriptionTable, issues...),
+		writer.MarkAsTitle(fmt.Sprintf("%s %s", localizedTitle(title, writer), getIssueType(violation)), 2),
+		writer.MarkInCenter(generateDescriptionTable(writer, issues...)),
 	)
+	if overflowNote != "" {
+		WriteContent(&contentBuilder, overflowNote)
+	}
+	WriteContent(&contentBuilder, getJasFullDescription(violation, writer, generateRuleTable, issues...))
 	return contentBuilder.String()
 }
 
@@ -528,13 +1291,7 @@ func getSastRuleFullDescriptionTable(info formats.ScannerInfo, writer OutputWrit
 }
 
 func SecretReviewContent(violation bool, writer OutputWriter, issues ...formats.SourceCodeRow) string {
-	var contentBuilder strings.Builder
-	WriteContent(&contentBuilder,
-		writer.MarkAsTitle(fmt.Sprintf("%s %s", secretsTitle, getIssueType(violation)), 2),
-		writer.MarkInCenter(getSecretsDescriptionTable(writer, issues...)),
-		getJasFullDescription(violation, writer, getSecretsRuleFullDescriptionTable, issues...),
-	)
-	return contentBuilder.String()
+	return getJasReviewContent(secretsTitle, violation, writer, getSecretsDescriptionTable, getSecretsRuleFullDescriptionTable, issues...)
 }
 
 func getSecretsDescriptionTable(writer OutputWriter, issues ...formats.SourceCodeRow) string {
@@ -589,11 +1346,58 @@ func getDirectDependenciesCellData(components []formats.ComponentRow) (dependenc
 		return NewCellData()
 	}
 	for _, component := range components {
-		dependencies = append(dependencies, results.GetDependencyId(component.Name, component.Version))
+		// The same direct dependency may bring the issue into more than one working directory
+		if dependencyId := results.GetDependencyId(component.Name, component.Version); !slices.Contains(dependencies, dependencyId) {
+			dependencies = append(dependencies, dependencyId)
+		}
 	}
 	return
 }
 
+// Returns the sorted working directories of the direct dependencies of the issues
+func getScaWorkingDirs(issues ...formats.VulnerabilityOrViolationRow) []string {
+	workingDirs := datastructures.MakeSet[string]()
+	for _, issue := range issues {
+		for _, component := range issue.Components {
+			if component.Location != nil && component.Location.File != "" {
+				workingDirs.Add(component.Location.File)
+			}
+		}
+	}
+	sortedWorkingDirs := workingDirs.ToSlice()
+	slices.Sort(sortedWorkingDirs)
+	return sortedWorkingDirs
+}
+
+func getDependencyScopeCellData(scopes dependencyscope.Scopes, directDependencies []formats.ComponentRow) CellData {
+	if scope := scopes.GetScope(directDependencies); scope != "" {
+		return NewCellData(string(scope))
+	}
+	return NewCellData("-")
+}
+
+// Returns the EPSS and the KEV cells of an issue, by its most exploitable CVE
+func getExploitabilityCellsData(exploitabilityInfo map[string]exploitability.Info, cveRows []formats.CveRow) []CellData {
+	var cves []string
+	for _, cve := range cveRows {
+		cves = append(cves, cve.Id)
+	}
+	info := exploitability.GetMostExploitable(exploitabilityInfo, cves...)
+	epss, kev := "-", "-"
+	if info.HasEpss {
+		epss = FormatEpssScore(info.EpssScore)
+	}
+	if info.KnownExploited {
+		kev = "Yes"
+	}
+	return []CellData{NewCellData(epss), NewCellData(kev)}
+}
+
+// Formats an EPSS score as a percentage, e.g. 0.9734 as 97.34%
+func FormatEpssScore(score float64) string {
+	return fmt.Sprintf("%.2f%%", score*100)
+}
+
 func getCveIdsCellData(cveRows []formats.CveRow, issueId string) (ids CellData) {
 	if len(cveRows) == 0 {
 		return NewCellData(issueId)
@@ -624,12 +1428,43 @@ func getScaSecurityIssueDetailsContent(issues []formats.VulnerabilityOrViolation
 	// Split content if it exceeds the size limit and decorate it with title as prefix
 	return ConvertContentToComments(content, writer, func(commentCount int, detailsContent string) string {
 		contentBuilder := strings.Builder{}
-		WriteContent(&contentBuilder, writer.MarkAsTitle(issuesDetailsSubTitle, 3))
+		WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(issuesDetailsSubTitle, writer), 3))
 		WriteContent(&contentBuilder, detailsContent)
 		return contentBuilder.String()
 	})
 }
 
+// Returns the rows to show in a table, according to the maximal number of rows per table.
+// If rows were left out, a note that points to the full report is returned as well.
+func limitTableRows[T any](rows []T, writer OutputWriter) ([]T, string) {
+	options := writer.ReportingOptions()
+	if options.MaxRowsPerTable <= 0 || len(rows) <= options.MaxRowsPerTable {
+		return rows, ""
+	}
+	note := fmt.Sprintf("Showing %d of %d rows", options.MaxRowsPerTable, len(rows))
+	if options.FullReportUrl != "" {
+		note += ", " + MarkAsLink("see the full report", options.FullReportUrl)
+	}
+	return rows[:options.MaxRowsPerTable], "\n" + note + "."
+}
+
+// When only applicable CVEs fail the scan, returns a note that counts the issues that don't fail it
+func getApplicableOnlyNote(rows []formats.VulnerabilityOrViolationRow, writer OutputWriter) string {
+	if !writer.ReportingOptions().FailOnApplicableOnly {
+		return ""
+	}
+	notApplicable := 0
+	for _, row := range rows {
+		if row.Applicable == jasutils.NotApplicable.String() {
+			notApplicable++
+		}
+	}
+	if notApplicable == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n%s %d of the %d issues aren't applicable according to the Contextual Analysis, so they don't fail the scan.", MarkAsBold("Note:"), notApplicable, len(rows))
+}
+
 func getIssuesWithDetails(issues []formats.VulnerabilityOrViolationRow) (filter []formats.VulnerabilityOrViolationRow) {
 	for i := range issues {
 		if issues[i].JfrogResearchInformation != nil || issues[i].Summary != "" {
@@ -696,25 +1531,71 @@ func getScaSecurityIssueDetails(issue formats.VulnerabilityOrViolationRow, viola
 		WriteContent(&contentBuilder, summary)
 	}
 
-	// Jfrog Research Details
-	if issue.JfrogResearchInformation == nil || (issue.JfrogResearchInformation.Details == "" && issue.JfrogResearchInformation.Remediation == "") {
+	if writer.ReportingOptions().HideResearchDetails {
 		return contentBuilder.String()
 	}
-	WriteNewLine(&contentBuilder)
-	WriteContent(&contentBuilder, writer.MarkAsTitle(jfrogResearchDetailsSubTitle, 3))
-
-	if issue.JfrogResearchInformation.Details != "" {
+	explainFinding := getExplainFindingContent(issue, writer)
+	// Jfrog Research Details
+	if issue.JfrogResearchInformation == nil || (issue.JfrogResearchInformation.Details == "" && issue.JfrogResearchInformation.Remediation == "") {
+		if explainFinding == "" {
+			return contentBuilder.String()
+		}
+	} else {
 		WriteNewLine(&contentBuilder)
-		WriteContent(&contentBuilder, MarkAsBold("Description:"), issue.JfrogResearchInformation.Details)
+		WriteContent(&contentBuilder, writer.MarkAsTitle(localizedTitle(jfrogResearchDetailsSubTitle, writer), 3))
+
+		if issue.JfrogResearchInformation.Details != "" {
+			WriteNewLine(&contentBuilder)
+			WriteContent(&contentBuilder, MarkAsBold("Description:"), issue.JfrogResearchInformation.Details)
+		}
+		if issue.JfrogResearchInformation.Remediation != "" {
+			WriteNewLine(&contentBuilder)
+			WriteContent(&contentBuilder, MarkAsBold("Remediation:"), issue.JfrogResearchInformation.Remediation)
+		}
 	}
-	if issue.JfrogResearchInformation.Remediation != "" {
+	if explainFinding != "" {
 		WriteNewLine(&contentBuilder)
-		WriteContent(&contentBuilder, MarkAsBold("Remediation:"), issue.JfrogResearchInformation.Remediation)
+		WriteContent(&contentBuilder, explainFinding)
 	}
 
 	return contentBuilder.String() + "\n"
 }
 
+// Returns the full JFrog research data of the CVEs of the issue, with a collapsible section for each CVE,
+// or an empty string if the research data wasn't requested or none of the CVEs has it
+func getExplainFindingContent(issue formats.VulnerabilityOrViolationRow, writer OutputWriter) string {
+	researchDetails := writer.ResearchDetails()
+	if len(researchDetails) == 0 {
+		return ""
+	}
+	var sections []string
+	for _, cve := range issue.Cves {
+		details, exists := researchDetails[cve.Id]
+		if !exists || details.IsEmpty() {
+			continue
+		}
+		var detailsBuilder strings.Builder
+		if details.ExploitMaturity != "" {
+			WriteNewLine(&detailsBuilder)
+			WriteContent(&detailsBuilder, MarkAsBold("Exploit Maturity:"), details.ExploitMaturity)
+		}
+		if details.AttackVector != "" {
+			WriteNewLine(&detailsBuilder)
+			WriteContent(&detailsBuilder, MarkAsBold("Attack Vector:"), details.AttackVector)
+		}
+		if details.ExtendedRemediation != "" {
+			WriteNewLine(&detailsBuilder)
+			WriteContent(&detailsBuilder, MarkAsBold("Extended Remediation:"), details.ExtendedRemediation)
+		}
+		WriteNewLine(&detailsBuilder)
+		sections = append(sections, writer.MarkAsDetails(cve.Id, 4, detailsBuilder.String()))
+	}
+	if len(sections) == 0 {
+		return ""
+	}
+	return writer.MarkAsTitle(localizedTitle(explainFindingSubTitle, writer), 3) + "\n" + strings.Join(sections, "\n")
+}
+
 func getJasIssueDescriptionTable(writer OutputWriter, issues ...formats.SourceCodeRow) string {
 	// Construct table
 	table := NewMarkdownTable("Severity", "ID", "Finding", "Watch Name", "Policies").SetDelimiter(writer.Separator())
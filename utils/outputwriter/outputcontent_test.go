@@ -2,9 +2,14 @@ package outputwriter
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jfrog/frogbot/v2/utils/dependencyscope"
+	"github.com/jfrog/frogbot/v2/utils/exploitability"
 	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/research"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
@@ -197,6 +202,18 @@ func TestGetMainCommentContent(t *testing.T) {
 	}
 }
 
+func TestForkPullRequestNote(t *testing.T) {
+	writer := &StandardOutput{MarkdownOutput{vcsProvider: vcsutils.GitHub, hasInternetConnection: true}}
+	forkNote := "🍴 This pull request is from the fork **octocat/frogbot**."
+	assert.NotContains(t, strings.Join(GetMainCommentContent([]string{"content"}, true, true, writer), ""), forkNote)
+
+	writer.SetForkRepository("octocat/frogbot")
+	assert.Contains(t, strings.Join(GetMainCommentContent([]string{"content"}, true, true, writer), ""), forkNote)
+	assert.Contains(t, strings.Join(GetNoIssuesCommentContent([]string{"content"}, writer), ""), forkNote)
+	// The fix pull requests aren't from forks
+	assert.NotContains(t, strings.Join(GetMainCommentContent([]string{"content"}, true, false, writer), ""), forkNote)
+}
+
 func TestScanSummaryContent(t *testing.T) {
 	testScanStatus := formats.ScanStatus{
 		ScaStatusCode:           utils.NewIntPtr(0),
@@ -739,6 +756,531 @@ func TestIsFrogbotReviewComment(t *testing.T) {
 	}
 }
 
+func TestIsFrogbotFixPullRequest(t *testing.T) {
+	assert.False(t, IsFrogbotFixPullRequest("Bumps lodash from 4.17.20 to 4.17.21"))
+	assert.True(t, IsFrogbotFixPullRequest(GetBanner(VulnerabilitiesFixPrBannerSource)+"fix details"))
+	assert.True(t, IsFrogbotFixPullRequest(GetSimplifiedTitle(VulnerabilitiesFixMrBannerSource)+"fix details"))
+	assert.False(t, IsFrogbotFixPullRequest(GetBanner(VulnerabilitiesPrBannerSource)))
+}
+
+func TestGetFindingIds(t *testing.T) {
+	assert.Empty(t, GetFindingIds("This comment is unrelated to Frogbot"))
+	assert.Equal(t, []string{"a1b2c3d4e5f60718"}, GetFindingIds(MarkdownComment(ReviewCommentId)+"review comment"+FindingIdsComment("a1b2c3d4e5f60718")))
+	assert.Equal(t, []string{"a1b2c3d4e5f60718", "0123456789abcdef"}, GetFindingIds(FindingIdsComment("a1b2c3d4e5f60718", "0123456789abcdef")))
+}
+
+func TestGetResolvedCommentContent(t *testing.T) {
+	summaryComment := MarkdownComment(ReviewCommentId) + "## Summary"
+	expectedContent := "<details><summary><b>✅ Resolved in later commits</b></summary>\n\n" + MarkdownComment(ResolvedCommentId) + "## Summary\n\n<br></details>"
+	resolvedComment := GetResolvedCommentContent(summaryComment, &StandardOutput{})
+	assert.Equal(t, expectedContent, resolvedComment)
+	// The collapsed comments are kept by the next scans
+	assert.False(t, IsFrogbotComment(resolvedComment))
+}
+
+func TestGetScanProgressCommentContent(t *testing.T) {
+	progressComment := GetScanProgressCommentContent(&StandardOutput{})
+	assert.True(t, IsScanProgressComment(progressComment))
+	assert.Contains(t, progressComment, "🔍 Frogbot scan in progress…")
+	// The progress comment isn't deleted with the previous Frogbot comments, so it can be replaced after the results are added
+	assert.False(t, IsFrogbotComment(progressComment))
+
+	failedComment := GetScanFailedCommentContent("failed to download the source branch", &StandardOutput{})
+	assert.True(t, IsScanProgressComment(failedComment))
+	assert.Contains(t, failedComment, "❌ Frogbot scan failed")
+	assert.Contains(t, failedComment, "```\nfailed to download the source branch\n```")
+}
+
+func TestFixedIssuesContent(t *testing.T) {
+	log4jVulnerability := formats.VulnerabilityOrViolationRow{
+		Cves: []formats.CveRow{{Id: "CVE-2021-44228"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           formats.SeverityDetails{Severity: "Critical"},
+			ImpactedDependencyName:    "log4j-core",
+			ImpactedDependencyVersion: "2.14.1",
+		},
+	}
+	xrayViolation := formats.VulnerabilityOrViolationRow{
+		IssueId: "XRAY-1234",
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           formats.SeverityDetails{Severity: "Low"},
+			ImpactedDependencyName:    "lodash",
+			ImpactedDependencyVersion: "4.17.20",
+		},
+	}
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, FixedIssuesContent(nil, writer))
+	expectedOutput := `
+
+---
+## ✅ Fixed by this PR
+
+---
+| Severity                | ID                  | Impacted Dependency                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: |
+| Critical | CVE-2021-44228 | log4j-core 2.14.1 |
+| Low | XRAY-1234 | lodash 4.17.20 |`
+	// The same issue, reported as both a vulnerability and a violation, is shown once
+	assert.Equal(t, expectedOutput, FixedIssuesContent([]formats.VulnerabilityOrViolationRow{log4jVulnerability, xrayViolation, log4jVulnerability}, writer))
+}
+
+func TestUnsupportedFixesContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, UnsupportedFixesContent(nil, writer))
+	unsupportedFix := UnsupportedFixRow{
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			Cves: []formats.CveRow{{Id: "CVE-2022-3517"}},
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    "minimatch",
+				ImpactedDependencyVersion: "3.0.4",
+			},
+		},
+		SuggestedFixedVersion: "3.0.5",
+		Reason:                "Indirect dependency",
+	}
+	expectedOutput := `
+
+---
+## 🚧 Known Unfixable Items
+
+---
+The following vulnerable dependencies can't be fixed automatically and need to be remediated manually.
+
+| Severity                | ID                  | Impacted Dependency                  | Fixed Version                  | Reason                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| High | CVE-2022-3517 | minimatch 3.0.4 | 3.0.5 | Indirect dependency |`
+	assert.Equal(t, expectedOutput, UnsupportedFixesContent([]UnsupportedFixRow{unsupportedFix}, writer))
+}
+
+func TestFailedFixesContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, FailedFixesContent(nil, writer))
+	failedFix := FailedFixRow{
+		VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{
+			Cves: []formats.CveRow{{Id: "CVE-2022-3517"}},
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    "minimatch",
+				ImpactedDependencyVersion: "3.0.4",
+			},
+		},
+		SuggestedFixedVersion: "3.0.5",
+		Reason:                "npm install failed",
+	}
+	expectedOutput := `
+
+---
+## ⚠️ Could not fix
+
+---
+The updates of the following vulnerable dependencies failed, so their changes were rolled back and they need to be fixed manually.
+
+| Severity                | ID                  | Impacted Dependency                  | Fixed Version                  | Reason                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| High | CVE-2022-3517 | minimatch 3.0.4 | 3.0.5 | npm install failed |`
+	assert.Equal(t, expectedOutput, FailedFixesContent([]FailedFixRow{failedFix}, writer))
+}
+
+func TestReleaseNotesContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, ReleaseNotesContent(nil, writer))
+	rows := []ReleaseNotesRow{
+		{
+			ImpactedDependencyName:    "minimatch",
+			ImpactedDependencyVersion: "3.0.4",
+			FixedVersion:              "3.0.5",
+			ReleaseNotesUrl:           "https://www.npmjs.com/package/minimatch/v/3.0.5",
+			DiffUrl:                   "https://npmdiff.dev/minimatch/3.0.4/3.0.5/",
+		},
+		{
+			ImpactedDependencyName:    "requests",
+			ImpactedDependencyVersion: "2.30.0",
+			FixedVersion:              "2.31.0",
+			ReleaseNotesUrl:           "https://pypi.org/project/requests/2.31.0/",
+		},
+	}
+	expectedOutput := `
+
+---
+## 📝 Release Notes
+
+---
+Review the changes of the upgraded dependencies before merging this pull request.
+
+| Dependency                | Current Version                  | Fixed Version                  | Release Notes                  | Changes                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| minimatch | 3.0.4 | 3.0.5 | [3.0.5](https://www.npmjs.com/package/minimatch/v/3.0.5) | [Compare](https://npmdiff.dev/minimatch/3.0.4/3.0.5/) |
+| requests | 2.30.0 | 2.31.0 | [2.31.0](https://pypi.org/project/requests/2.31.0/) | - |`
+	assert.Equal(t, expectedOutput, ReleaseNotesContent(rows, writer))
+}
+
+func TestUpdatedWorkspacesContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, UpdatedWorkspacesContent(nil, writer))
+	rows := []UpdatedWorkspacesRow{
+		{ImpactedDependencyName: "minimist", FixedVersion: "1.2.6", Workspaces: []string{"packages/api", "packages/web"}},
+		{ImpactedDependencyName: "lodash", FixedVersion: "4.17.21", Workspaces: []string{"packages/web"}},
+	}
+	expectedOutput := `
+
+---
+## 🗂️ Updated Workspaces
+
+---
+The dependencies are updated in the package.json files of these workspaces, and the lockfile of the root is regenerated.
+
+| Dependency                | Fixed Version                  | Workspaces                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: |
+| minimist | 1.2.6 | packages/api, packages/web |
+| lodash | 4.17.21 | packages/web |`
+	assert.Equal(t, expectedOutput, UpdatedWorkspacesContent(rows, writer))
+}
+
+func TestUpgradeRiskContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, UpgradeRiskContent(nil, writer))
+	rows := []UpgradeRiskRow{
+		{ImpactedDependencyName: "minimatch", ImpactedDependencyVersion: "3.0.4", FixedVersion: "3.0.5", Risk: "Low", VersionChange: "patch", HasTests: true},
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "3.10.1", FixedVersion: "4.17.21", Risk: "High", VersionChange: "major", TransitiveChanges: 12},
+	}
+	expectedOutput := `
+
+---
+## 🎲 Upgrade Risk
+
+---
+The risk is estimated by the version change of the upgrade, the transitive dependencies it changes in the lockfiles, and whether the project has tests.
+
+| Dependency                | Current Version                  | Fixed Version                  | Risk                  | Version Change                  | Transitive Changes                  | Tests Found                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| minimatch | 3.0.4 | 3.0.5 | **Low** | patch | 0 | Yes |
+| lodash | 3.10.1 | 4.17.21 | **High** | major | 12 | No |`
+	assert.Equal(t, expectedOutput, UpgradeRiskContent(rows, writer))
+}
+
+func TestDependencyConfusionContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, DependencyConfusionContent(nil, writer))
+	risks := []issues.DependencyConfusionRisk{{
+		PackageType:   "npm",
+		PackageName:   "@mycompany/lib",
+		Version:       "1.2.3",
+		Namespace:     "@mycompany/*",
+		PublicVersion: "99.0.0",
+		PublicUrl:     "https://www.npmjs.com/package/@mycompany/lib",
+	}}
+	expectedOutput := `
+
+---
+## 🎭 Dependency Confusion Risk
+
+---
+The following dependencies belong to internal namespaces, but a higher version of them exists in the public registry. Package managers that resolve dependencies from the public registry may install the public package instead of the internal one. Make sure these dependencies are resolved only from the internal registry, and consider claiming their names in the public registry.
+
+| Package Type                | Dependency                  | Version                  | Internal Namespace                  | Public Version                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| npm | @mycompany/lib | 1.2.3 | @mycompany/* | [99.0.0](https://www.npmjs.com/package/@mycompany/lib) |`
+	assert.Equal(t, expectedOutput, DependencyConfusionContent(risks, writer))
+}
+
+func TestDockerImageContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, DockerImageContent(nil, writer))
+	vulnerabilities := []issues.DockerImageVulnerability{
+		{Dockerfile: "Dockerfile", Line: 1, ComponentType: "Base Image", Name: "nginx", Version: "1.19", Severity: "High", IssueId: "XRAY-1", Cves: []string{"CVE-2021-23017"}, FixedVersions: []string{"[1.21.0]"}},
+		{Dockerfile: "app/Dockerfile", Line: 3, ComponentType: "OS Package", Name: "curl", Version: "7.68.0", Severity: "Low", IssueId: "XRAY-2"},
+	}
+	expectedOutput := `
+
+---
+## 🐳 Docker Image
+
+---
+The following vulnerabilities were found in the base images of the Dockerfiles, and in the OS packages that the Dockerfiles install.
+
+| Severity                | ID                  | Dockerfile                  | Type                  | Component                  | Fixed Versions                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| High | CVE-2021-23017 | Dockerfile:1 | Base Image | nginx 1.19 | [1.21.0] |
+| Low | XRAY-2 | app/Dockerfile:3 | OS Package | curl 7.68.0 | - |`
+	assert.Equal(t, expectedOutput, DockerImageContent(vulnerabilities, writer))
+}
+
+func TestSystemPackagesContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, SystemPackagesContent(nil, writer))
+	vulnerabilities := []issues.SystemPackageVulnerability{
+		{File: "environment.yml", Line: 4, PackageType: "Conda", Name: "numpy", Version: "1.21.0", Severity: "High", IssueId: "XRAY-1", Cves: []string{"CVE-2021-41495"}, FixedVersions: []string{"[1.22.0]"}},
+		{File: "docker/apt.txt", Line: 2, PackageType: "Debian", Name: "curl", Version: "7.68.0", Severity: "Low", IssueId: "XRAY-2"},
+	}
+	expectedOutput := `
+
+---
+## 📦 System Packages
+
+---
+The following vulnerabilities were found in the packages that the Conda environment files and the apt and apk package lists pin.
+
+| Severity                | ID                  | Package List                  | Type                  | Package                  | Fixed Versions                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| High | CVE-2021-41495 | environment.yml:4 | Conda | numpy 1.21.0 | [1.22.0] |
+| Low | XRAY-2 | docker/apt.txt:2 | Debian | curl 7.68.0 | - |`
+	assert.Equal(t, expectedOutput, SystemPackagesContent(vulnerabilities, writer))
+}
+
+func TestCurationContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, CurationContent(nil, writer))
+	blockedPackages := []issues.CurationBlockedPackage{
+		{PackageType: "npm", PackageName: "lodash", Version: "4.17.20", BlockingReason: "Policy violations", Policies: []issues.CurationPolicy{{Name: "no-critical", Condition: "CVE with critical severity", Recommendation: "Upgrade to version 4.17.21"}}},
+		{PackageType: "npm", PackageName: "minimist", Version: "0.0.8", ParentName: "mkdirp", ParentVersion: "0.5.1", BlockingReason: "Policy violations", Policies: []issues.CurationPolicy{{Name: "no-malicious", Condition: "Malicious package"}}},
+	}
+	expectedOutput := `
+
+---
+## 🚫 Blocked by Curation
+
+---
+The following dependencies are blocked by the curation policies of Artifactory, so they can't be installed once the pull request is merged. Replace them with versions that the policies allow.
+
+| Package Type                | Blocked Package                  | Direct Dependency                  | Blocking Reason                  | Violated Policies                  | Recommendations                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| npm | lodash 4.17.20 | - | Policy violations | no-critical: CVE with critical severity | Upgrade to version 4.17.21 |
+| npm | minimist 0.0.8 | mkdirp 0.5.1 | Policy violations | no-malicious: Malicious package | - |`
+	assert.Equal(t, expectedOutput, CurationContent(blockedPackages, writer))
+}
+
+func TestAlternativePackagesContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, AlternativePackagesContent(nil, writer))
+	suggestions := []issues.AlternativePackageSuggestion{
+		{PackageType: "npm", PackageName: "request", Version: "2.88.2", Severity: "Medium", IssueIds: []string{"CVE-2023-28155"}, Alternatives: []issues.PackageAlternative{{Name: "axios", Url: "https://github.com/axios/axios", Note: "Promise based"}, {Name: "got"}}, Remediations: []string{"Don't follow redirects"}},
+		{PackageType: "npm", PackageName: "minimist", Version: "0.0.8", Severity: "Low", IssueIds: []string{"XRAY-1"}},
+	}
+	expectedOutput := `
+
+---
+## 💡 Alternative Packages
+
+---
+The following vulnerable dependencies have no fixed version, so they can't be fixed by upgrading them. Consider replacing them with the suggested alternatives.
+
+| Severity                | ID                  | Vulnerable Dependency                  | Alternatives                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| Medium | CVE-2023-28155 | request 2.88.2 | [axios](https://github.com/axios/axios): Promise based, got |
+| Low | XRAY-1 | minimist 0.0.8 | - |
+
+---
+### Remediation of request 2.88.2
+
+---
+
+
+Don't follow redirects
+
+`
+	assert.Equal(t, expectedOutput, AlternativePackagesContent(suggestions, writer))
+
+	// The remediation is JFrog research data, so it is hidden with the research details
+	writer.SetReportingOptions(ReportingOptions{HideResearchDetails: true})
+	assert.NotContains(t, AlternativePackagesContent(suggestions, writer), "Remediation of")
+}
+
+func TestGitHubActionsContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, GitHubActionsContent(nil, writer))
+	actionIssues := []issues.GitHubActionIssue{
+		{Workflow: ".github/workflows/build.yml", Line: 12, Type: "Vulnerable Version", Action: "tj-actions/changed-files", Ref: "v45.0.7", Severity: "High", IssueId: "GHSA-mrrh-fwg8-r2c3", Cves: []string{"CVE-2025-30066"}, FixedVersion: "v46.0.1"},
+		{Workflow: ".github/workflows/build.yml", Line: 8, Type: "Mutable Reference", Action: "actions/checkout", Ref: "v4", Severity: "Low"},
+	}
+	expectedOutput := `
+
+---
+## ⚙️ GitHub Actions
+
+---
+The following actions of the GitHub Actions workflows are used in vulnerable versions, or by tags and branches that may be moved to other commits. Pin the actions to the commit SHAs of fixed versions.
+
+| Severity                | ID                  | Workflow                  | Type                  | Action                  | Fixed Version                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| High | CVE-2025-30066 | .github/workflows/build.yml:12 | Vulnerable Version | tj-actions/changed-files@v45.0.7 | v46.0.1 |
+| Low | - | .github/workflows/build.yml:8 | Mutable Reference | actions/checkout@v4 | - |`
+	assert.Equal(t, expectedOutput, GitHubActionsContent(actionIssues, writer))
+}
+
+func TestExternalScannersContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, ExternalScannersContent(nil, writer))
+	externalIssues := []issues.ExternalScannerIssue{
+		{Scanner: "Checkov", File: "terraform/main.tf", Line: 12, RuleId: "CKV_AWS_20", Severity: "High", Message: "S3 Bucket has an ACL defined which allows public READ access."},
+		{Scanner: "Trivy", File: "docker/Dockerfile", RuleId: "AVD-DS-0002", Severity: "Critical", Message: "Use 'RUN apt-get update | tee log'"},
+	}
+	expectedOutput := `
+
+---
+## 🔌 External Scanners
+
+---
+The following findings were reported by other scanners that ran in this pipeline.
+
+| Severity                | Scanner                  | Rule                  | Location                  | Finding                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| High | Checkov | CKV_AWS_20 | terraform/main.tf:12 | S3 Bucket has an ACL defined which allows public READ access. |
+| Critical | Trivy | AVD-DS-0002 | docker/Dockerfile | Use 'RUN apt-get update \| tee log' |`
+	assert.Equal(t, expectedOutput, ExternalScannersContent(externalIssues, writer))
+}
+
+func TestSecurityChampionsContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, SecurityChampionsContent(nil, writer))
+	expectedOutput := `
+
+---
+## 👥 Security Champions
+
+---
+@jfrog/security, @dev1, this pull request adds Critical or High findings to paths that you own. Please review them.
+`
+	assert.Equal(t, expectedOutput, SecurityChampionsContent([]string{"@jfrog/security", "@dev1"}, writer))
+}
+
+func TestPolicyRuleViolationsContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, PolicyRuleViolationsContent(nil, ".frogbot/policy.yml", writer))
+	violations := []issues.PolicyRuleViolation{
+		{Rule: "maxSeverity", Subject: "CVE-2021-23337 in lodash:4.17.20", Reason: "The severity High is higher than Medium"},
+		{Rule: "bannedLicenses", Subject: "gpl-lib:1.0.0", Reason: "The GPL-3.0 license is banned"},
+	}
+	expectedOutput := `
+
+---
+## 🚫 Blocking Rules
+
+---
+The following findings and dependencies break the blocking rules of the ` + "`.frogbot/policy.yml`" + ` file of the target branch, so the pull request is blocked.
+
+| Rule                | Finding / Dependency                  | Reason                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: |
+| maxSeverity | CVE-2021-23337 in lodash:4.17.20 | The severity High is higher than Medium |
+| bannedLicenses | gpl-lib:1.0.0 | The GPL-3.0 license is banned |`
+	assert.Equal(t, expectedOutput, PolicyRuleViolationsContent(violations, ".frogbot/policy.yml", writer))
+}
+
+func TestSbomContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, SbomContent("", "", writer))
+	testCases := []struct {
+		name           string
+		ciRunUrl       string
+		expectedOutput string
+	}{
+		{
+			name:     "Without CI run URL",
+			ciRunUrl: "",
+			expectedOutput: `
+
+---
+## 📄 Software Bill of Materials
+
+---
+A CycloneDX SBOM of the repository, ` + "`frogbot-sbom.json`" + `, was generated by this Frogbot run and can be found among its artifacts.`,
+		},
+		{
+			name:     "With CI run URL",
+			ciRunUrl: "https://github.com/jfrog/frogbot/actions/runs/1",
+			expectedOutput: `
+
+---
+## 📄 Software Bill of Materials
+
+---
+A CycloneDX SBOM of the repository, ` + "`frogbot-sbom.json`" + `, was generated by [this Frogbot run](https://github.com/jfrog/frogbot/actions/runs/1) and can be found among its artifacts.`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, SbomContent("frogbot-sbom.json", tc.ciRunUrl, writer))
+		})
+	}
+}
+
+func TestRuntimeDetailsContent(t *testing.T) {
+	details := &RuntimeDetails{FrogbotVersion: "2.24.0", XrayVersion: "3.107.13", AnalyzerManagerVersion: "1.13.4", ScanDuration: 95400 * time.Millisecond}
+	testCases := []struct {
+		name           string
+		writer         OutputWriter
+		expectedOutput string
+	}{
+		{
+			name:           "No runtime details",
+			writer:         &StandardOutput{},
+			expectedOutput: "",
+		},
+		{
+			name:           "Standard output not entitled",
+			writer:         &StandardOutput{MarkdownOutput{runtimeDetails: details}},
+			expectedOutput: "<details><summary><b>Runtime Details</b></summary>\n\n- Frogbot version: 2.24.0\n- Xray version: 3.107.13\n- Scan duration: 1m35s\n<br></details>",
+		},
+		{
+			name:           "Simplified output entitled",
+			writer:         &SimplifiedOutput{MarkdownOutput{runtimeDetails: details, entitledForJas: true}},
+			expectedOutput: "Runtime Details: \n\n- Frogbot version: 2.24.0\n- Xray version: 3.107.13\n- Analyzer Manager version: 1.13.4\n- Scan duration: 1m35s\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, runtimeDetailsContent(tc.writer))
+		})
+	}
+}
+
+func TestUntitledForJasMsg(t *testing.T) {
+	testCases := []struct {
+		name            string
+		writer          OutputWriter
+		expectedMessage string
+	}{
+		{
+			name:            "Not entitled",
+			writer:          &StandardOutput{},
+			expectedMessage: jasFeaturesMsgWhenNotEnabled,
+		},
+		{
+			name:   "Entitled",
+			writer: &StandardOutput{MarkdownOutput{entitledForJas: true}},
+		},
+		{
+			name:   "Not entitled avoid extra messages",
+			writer: &StandardOutput{MarkdownOutput{avoidExtraMessages: true}},
+		},
+		{
+			name:            "JAS status unknown",
+			writer:          &StandardOutput{MarkdownOutput{jasStatusUnknown: true}},
+			expectedMessage: jasStatusUnknownMsg,
+		},
+		{
+			name:            "JAS status unknown avoid extra messages",
+			writer:          &SimplifiedOutput{MarkdownOutput{jasStatusUnknown: true, avoidExtraMessages: true}},
+			expectedMessage: jasStatusUnknownMsg,
+		},
+		{
+			name:            "JAS unsupported",
+			writer:          &SimplifiedOutput{MarkdownOutput{jasUnsupportedReason: "You are using JFrog Xray version 3.60.0, while this operation requires version 3.66.5 or higher.", avoidExtraMessages: true}},
+			expectedMessage: "were skipped and only the dependencies were scanned. You are using JFrog Xray version 3.60.0, while this operation requires version 3.66.5 or higher.",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			message := untitledForJasMsg(tc.writer)
+			if tc.expectedMessage == "" {
+				assert.Empty(t, message)
+				return
+			}
+			assert.Contains(t, message, tc.expectedMessage)
+		})
+	}
+}
+
 func TestGenerateReviewComment(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -1285,3 +1827,274 @@ func TestSastReviewContent(t *testing.T) {
 		}
 	}
 }
+
+func TestJasReviewContentMaxRowsPerTable(t *testing.T) {
+	issues := []formats.SourceCodeRow{
+		{SeverityDetails: formats.SeverityDetails{Severity: "High"}, Finding: "First finding", ScannerInfo: formats.ScannerInfo{RuleId: "first-rule"}},
+		{SeverityDetails: formats.SeverityDetails{Severity: "Low"}, Finding: "Second finding", ScannerInfo: formats.ScannerInfo{RuleId: "second-rule"}},
+	}
+	writer := &SimplifiedOutput{}
+	writer.SetReportingOptions(ReportingOptions{MaxRowsPerTable: 1})
+	for name, content := range map[string]string{
+		"IaC":     IacReviewContent(false, writer, issues...),
+		"SAST":    SastReviewContent(false, writer, issues...),
+		"Secrets": SecretReviewContent(false, writer, issues...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Contains(t, content, "First finding")
+			assert.NotContains(t, content, "Second finding")
+			assert.NotContains(t, content, "second-rule")
+			assert.Contains(t, content, "\n\nShowing 1 of 2 rows.")
+		})
+	}
+}
+
+func TestIgnoredIssuesContent(t *testing.T) {
+	writer := &SimplifiedOutput{}
+	assert.Empty(t, IgnoredIssuesContent(issues.ScansIssuesCollection{}, true, writer))
+	secret := formats.SourceCodeRow{
+		SeverityDetails: formats.SeverityDetails{Severity: "High"},
+		ScannerInfo:     formats.ScannerInfo{RuleId: "REQ.SECRET.KEYS"},
+		Location:        formats.Location{File: "config.yml", StartLine: 3},
+	}
+	// Ignored secrets aren't shown when secrets aren't included in the comments
+	assert.Empty(t, IgnoredIssuesContent(issues.ScansIssuesCollection{IgnoredSecretsIssues: []formats.SourceCodeRow{secret}}, false, writer))
+
+	issuesCollection := issues.ScansIssuesCollection{
+		IgnoredScaIssues: []formats.VulnerabilityOrViolationRow{{
+			Cves: []formats.CveRow{{Id: "CVE-2021-44228"}},
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "Critical"},
+				ImpactedDependencyName:    "log4j-core",
+				ImpactedDependencyVersion: "2.14.1",
+			},
+		}},
+		IgnoredLicensesViolations: []formats.LicenseViolationRow{{
+			LicenseRow: formats.LicenseRow{
+				ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+					SeverityDetails:           formats.SeverityDetails{Severity: "Medium"},
+					ImpactedDependencyName:    "minimist",
+					ImpactedDependencyVersion: "1.2.5",
+				},
+				LicenseKey: "GPL-3.0",
+			},
+		}},
+		IgnoredSecretsIssues: []formats.SourceCodeRow{secret},
+	}
+	expectedOutput := `
+
+---
+## 🙈 Ignored Findings
+
+---
+
+
+The following findings match Xray ignore rules, so they are not reported as issues.
+
+| Severity                | Type                  | ID                  | Impacted Dependency / Location                  |
+| :---------------------: | :-----------------------------------: | :-----------------------------------: | :-----------------------------------: |
+| Critical | SCA | CVE-2021-44228 | log4j-core 2.14.1 |
+| Medium | License | GPL-3.0 | minimist 1.2.5 |
+| High | Secrets | REQ.SECRET.KEYS | config.yml:3 |
+`
+	assert.Equal(t, expectedOutput, IgnoredIssuesContent(issuesCollection, true, writer))
+}
+
+func TestVulnerabilitiesSummaryTableExploitability(t *testing.T) {
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "Low"},
+				ImpactedDependencyName:    "log4j-core",
+				ImpactedDependencyVersion: "2.14.1",
+			},
+			Cves: []formats.CveRow{{Id: "CVE-2021-44228"}, {Id: "CVE-2021-45046"}},
+		},
+		{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    "minimist",
+				ImpactedDependencyVersion: "1.2.5",
+			},
+			Cves: []formats.CveRow{{Id: "CVE-2021-44906"}},
+		},
+	}
+	writer := &StandardOutput{}
+	assert.NotContains(t, getVulnerabilitiesSummaryTable(vulnerabilities, writer), "EPSS")
+
+	writer.SetExploitability(map[string]exploitability.Info{
+		"CVE-2021-44228": {EpssScore: 0.9757, HasEpss: true, KnownExploited: true},
+		"CVE-2021-45046": {EpssScore: 0.9734, HasEpss: true},
+	})
+	table := getVulnerabilitiesSummaryTable(vulnerabilities, writer)
+	assert.Regexp(t, `^\| Severity +\| ID +\| EPSS +\| KEV +\| Direct Dependencies`, table)
+	assert.Contains(t, table, "| CVE-2021-44228<br>CVE-2021-45046 | 97.57% | Yes |")
+	assert.Contains(t, table, "| CVE-2021-44906 | - | - |")
+}
+
+func TestVulnerabilitiesSummaryTableDependencyScope(t *testing.T) {
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    "minimist",
+				ImpactedDependencyVersion: "1.2.5",
+				Components:                []formats.ComponentRow{{Name: "jest", Version: "29.0.0"}},
+			},
+		},
+		{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "Low"},
+				ImpactedDependencyName:    "debug",
+				ImpactedDependencyVersion: "2.6.8",
+				Components:                []formats.ComponentRow{{Name: "mocha", Version: "10.0.0"}},
+			},
+		},
+	}
+	writer := &StandardOutput{}
+	assert.NotContains(t, getVulnerabilitiesSummaryTable(vulnerabilities, writer), "Scope")
+
+	writer.SetDependencyScopes(dependencyscope.Scopes{"jest": dependencyscope.Dev})
+	table := getVulnerabilitiesSummaryTable(vulnerabilities, writer)
+	assert.Regexp(t, `^\| Severity +\| ID +\| Scope +\| Direct Dependencies`, table)
+	assert.Contains(t, table, "| dev | jest:29.0.0 |")
+	assert.Contains(t, table, "| - | mocha:10.0.0 |")
+}
+
+func TestVulnerabilitiesSummaryTableWorkingDirectories(t *testing.T) {
+	component := func(name, workingDir string) formats.ComponentRow {
+		return formats.ComponentRow{Name: name, Version: "1.0.0", Location: &formats.Location{File: workingDir}}
+	}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{
+		{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    "minimist",
+				ImpactedDependencyVersion: "1.2.5",
+				Components:                []formats.ComponentRow{component("mkdirp", "frontend"), component("mkdirp", "backend")},
+			},
+		},
+	}
+	writer := &StandardOutput{}
+	// The issue is found in more than one working directory, so the same direct dependency is listed once
+	table := getVulnerabilitiesSummaryTable(vulnerabilities, writer)
+	assert.Regexp(t, `^\| Severity +\| ID +\| Working Directories +\| Direct Dependencies`, table)
+	assert.Contains(t, table, "| backend<br>frontend | mkdirp:1.0.0 |")
+
+	vulnerabilities[0].Components = []formats.ComponentRow{component("mkdirp", "frontend"), component("rimraf", "frontend")}
+	assert.NotContains(t, getVulnerabilitiesSummaryTable(vulnerabilities, writer), "Working Directories")
+	violationsTable := getSecurityViolationsSummaryTable(vulnerabilities, writer)
+	assert.NotContains(t, violationsTable, "Working Directories")
+}
+
+func TestFormatEpssScore(t *testing.T) {
+	assert.Equal(t, "97.34%", FormatEpssScore(0.9734))
+	assert.Equal(t, "0.04%", FormatEpssScore(0.00043))
+}
+
+func TestReportingOptions(t *testing.T) {
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{}
+	for _, name := range []string{"lodash", "minimist", "axios"} {
+		vulnerabilities = append(vulnerabilities, formats.VulnerabilityOrViolationRow{
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+				SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+				ImpactedDependencyName:    name,
+				ImpactedDependencyVersion: "1.0.0",
+			},
+			JfrogResearchInformation: &formats.JfrogResearchInformation{Details: "The research details of " + name, Remediation: "Upgrade " + name},
+		})
+	}
+	licenseViolations := []formats.LicenseViolationRow{{LicenseRow: formats.LicenseRow{LicenseKey: "GPL-3.0", ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "gpl-lib", ImpactedDependencyVersion: "1.0.0"}}}}
+
+	writer := &SimplifiedOutput{}
+	content := strings.Join(GetVulnerabilitiesContent(vulnerabilities, writer), "")
+	for _, name := range []string{"lodash", "minimist", "axios"} {
+		assert.Contains(t, content, "The research details of "+name)
+	}
+	assert.NotContains(t, content, "Showing")
+
+	t.Run("Max rows per table", func(t *testing.T) {
+		writer.SetReportingOptions(ReportingOptions{MaxRowsPerTable: 2, FullReportUrl: "https://ci.example.com/runs/1"})
+		content = strings.Join(GetVulnerabilitiesContent(vulnerabilities, writer), "")
+		assert.Contains(t, content, "| lodash 1.0.0 |")
+		assert.Contains(t, content, "| minimist 1.0.0 |")
+		assert.NotContains(t, content, "axios")
+		assert.Contains(t, content, "\n\nShowing 2 of 3 rows, [see the full report](https://ci.example.com/runs/1).")
+
+		writer.SetReportingOptions(ReportingOptions{MaxRowsPerTable: 2})
+		assert.Contains(t, strings.Join(GetVulnerabilitiesContent(vulnerabilities, writer), ""), "\n\nShowing 2 of 3 rows.")
+	})
+
+	t.Run("Hide research details", func(t *testing.T) {
+		writer.SetReportingOptions(ReportingOptions{HideResearchDetails: true})
+		content = strings.Join(GetVulnerabilitiesContent(vulnerabilities, writer), "")
+		assert.Contains(t, content, "| axios 1.0.0 |")
+		assert.NotContains(t, content, "The research details of")
+		assert.NotContains(t, content, jfrogResearchDetailsSubTitle)
+	})
+
+	t.Run("Explain this finding", func(t *testing.T) {
+		writer.SetReportingOptions(ReportingOptions{})
+		writer.SetResearchDetails(map[string]research.Details{"CVE-2022-1000": {ExploitMaturity: "Proof of Concept", AttackVector: "Network", ExtendedRemediation: "Validate the merged objects"}})
+		defer writer.SetResearchDetails(nil)
+		researchVulnerabilities := []formats.VulnerabilityOrViolationRow{{
+			Summary:                   "Prototype pollution",
+			ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "1.0.0"},
+			Cves:                      []formats.CveRow{{Id: "CVE-2022-1000"}, {Id: "CVE-2022-2000"}},
+		}}
+		content = strings.Join(GetVulnerabilitiesContent(researchVulnerabilities, writer), "")
+		assert.Contains(t, content, explainFindingSubTitle)
+		assert.Contains(t, content, writer.MarkAsDetails("CVE-2022-1000", 4, "\n\n**Exploit Maturity:**\nProof of Concept\n\n**Attack Vector:**\nNetwork\n\n**Extended Remediation:**\nValidate the merged objects\n"))
+		assert.NotContains(t, content, "CVE-2022-2000</")
+
+		writer.SetReportingOptions(ReportingOptions{HideResearchDetails: true})
+		assert.NotContains(t, strings.Join(GetVulnerabilitiesContent(researchVulnerabilities, writer), ""), explainFindingSubTitle)
+	})
+
+	t.Run("Show sections", func(t *testing.T) {
+		issuesCollection := issues.ScansIssuesCollection{ScaViolations: vulnerabilities, LicensesViolations: licenseViolations}
+		writer.SetReportingOptions(ReportingOptions{ShowSections: []string{LicensesSection}})
+		assert.Empty(t, GetVulnerabilitiesContent(vulnerabilities, writer))
+		content = strings.Join(PolicyViolationsContent(issuesCollection, writer), "")
+		assert.Contains(t, content, licenseViolationTitle)
+		assert.NotContains(t, content, securityViolationTitle)
+
+		writer.SetReportingOptions(ReportingOptions{ShowSections: []string{SecretsSection}})
+		assert.Empty(t, PolicyViolationsContent(issuesCollection, writer))
+	})
+
+	t.Run("Fail on applicable only", func(t *testing.T) {
+		applicabilityNote := "**Note:** 2 of the 3 issues aren't applicable according to the Contextual Analysis, so they don't fail the scan."
+		// Only the issues that aren't applicable are counted, while the undetermined ones fail the scan
+		vulnerabilities[0].Applicable = "Undetermined"
+		vulnerabilities[1].Applicable = "Not Applicable"
+		vulnerabilities[2].Applicable = "Not Applicable"
+		defer func() {
+			vulnerabilities[0].Applicable, vulnerabilities[1].Applicable, vulnerabilities[2].Applicable = "", "", ""
+		}()
+		writer.SetReportingOptions(ReportingOptions{})
+		assert.NotContains(t, strings.Join(GetVulnerabilitiesContent(vulnerabilities, writer), ""), applicabilityNote)
+
+		writer.SetReportingOptions(ReportingOptions{FailOnApplicableOnly: true})
+		assert.Contains(t, strings.Join(GetVulnerabilitiesContent(vulnerabilities, writer), ""), "\n\n"+applicabilityNote)
+		assert.Contains(t, strings.Join(PolicyViolationsContent(issues.ScansIssuesCollection{ScaViolations: vulnerabilities}, writer), ""), applicabilityNote)
+		// The note counts all the issues, including those that are left out of the table
+		writer.SetReportingOptions(ReportingOptions{FailOnApplicableOnly: true, MaxRowsPerTable: 1})
+		assert.Contains(t, strings.Join(GetVulnerabilitiesContent(vulnerabilities, writer), ""), applicabilityNote)
+	})
+}
+
+func TestScannedCommitComment(t *testing.T) {
+	writer := &StandardOutput{}
+	scannedCommit := &ScannedCommit{Hash: "3f2a9c1", Status: ScannedCommitIssues, TargetHash: "8e4b0d2", ConfigHash: "5a1c7e9f3b2d"}
+	writer.SetScannedCommit(scannedCommit)
+	summaryComments := GetMainCommentContent([]string{"content"}, true, true, writer)
+	assert.Len(t, summaryComments, 1)
+	assert.Equal(t, scannedCommit, GetScannedCommit(summaryComments[0]))
+	// The comments of earlier versions record only the scanned commit and the result of its scan
+	assert.Equal(t, &ScannedCommit{Hash: "3f2a9c1", Status: ScannedCommitClean}, GetScannedCommit(MarkdownComment(ScannedCommitCommentPrefix+"3f2a9c1 clean")))
+	// The scanned commit is recorded in the comments only
+	assert.Nil(t, GetScannedCommit(GetPRSummaryMainCommentDecorator(true, false, writer)(0, "content")))
+	assert.Nil(t, GetScannedCommit(GenerateReviewCommentContent("content", writer)))
+	assert.Nil(t, GetScannedCommit(MarkdownComment(ScannedCommitCommentPrefix)))
+}
@@ -2,8 +2,13 @@ package outputwriter
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/jfrog/frogbot/v2/utils/dependencyscope"
+	"github.com/jfrog/frogbot/v2/utils/exploitability"
+	"github.com/jfrog/frogbot/v2/utils/research"
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
@@ -66,6 +71,7 @@ const (
                     <th>FILE</th>
                     <th>LINE:COLUMN</th>
                     <th>SECRET</th>
+                    <th>STATUS</th>
                 </tr>
             </thead>
             <tbody>
@@ -84,7 +90,38 @@ const (
 					<td> %s </td>
 					<td> %d:%d </td>
 					<td> %s </td>
+					<td> %s </td>
 				</tr>`
+	// The template of the alerts that list the issues of a pull request in a table, by the title, the issues description, the link of the pull request, the header cells and the rows
+	AlertEmailHTMLTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <title>%s</title>
+    <style>
+        %s
+    </style>
+</head>
+<body>
+	<div>
+		The following %s in your <a href="%s">%s</a> have been detected by <a href="https://docs.jfrog-applications.jfrog.io/jfrog-applications/frogbot">Frogbot</a>
+		<br/>
+		<table class="table-container">
+            <thead>
+                <tr>%s
+                </tr>
+            </thead>
+            <tbody>
+                %s
+            </tbody>
+        </table>
+	</div>
+</body>
+</html>`
+	AlertEmailTableHeaderCell = `
+                    <th>%s</th>`
+	AlertEmailTableCell = `
+					<td> %s </td>`
 )
 
 // The OutputWriter interface allows Frogbot output to be written in an appropriate way for each git provider.
@@ -94,6 +131,10 @@ type OutputWriter interface {
 	SetJasOutputFlags(entitled, showCaColumn bool)
 	IsShowingCaColumn() bool
 	IsEntitledForJas() bool
+	SetJasStatusUnknown(unknown bool)
+	IsJasStatusUnknown() bool
+	SetJasUnsupportedReason(reason string)
+	JasUnsupportedReason() string
 	SetAvoidExtraMessages(avoidExtraMessages bool)
 	AvoidExtraMessages() bool
 	SetPullRequestCommentTitle(pullRequestCommentTitle string)
@@ -102,6 +143,22 @@ type OutputWriter interface {
 	HasInternetConnection() bool
 	SizeLimit(comment bool) int
 	SetSizeLimit(client vcsclient.VcsClient)
+	SetRuntimeDetails(details *RuntimeDetails)
+	RuntimeDetails() *RuntimeDetails
+	SetExploitability(exploitabilityInfo map[string]exploitability.Info)
+	Exploitability() map[string]exploitability.Info
+	SetResearchDetails(researchDetails map[string]research.Details)
+	ResearchDetails() map[string]research.Details
+	SetDependencyScopes(scopes dependencyscope.Scopes)
+	DependencyScopes() dependencyscope.Scopes
+	SetReportingOptions(options ReportingOptions)
+	ReportingOptions() ReportingOptions
+	SetMessages(messages Messages)
+	Messages() Messages
+	SetForkRepository(forkRepository string)
+	ForkRepository() string
+	SetScannedCommit(commit *ScannedCommit)
+	ScannedCommit() *ScannedCommit
 	// VCS info
 	VcsProvider() vcsutils.VcsProvider
 	SetVcsProvider(provider vcsutils.VcsProvider)
@@ -120,14 +177,70 @@ type MarkdownOutput struct {
 	avoidExtraMessages      bool
 	showCaColumn            bool
 	entitledForJas          bool
+	jasStatusUnknown        bool
+	jasUnsupportedReason    string
 	hasInternetConnection   bool
 	descriptionSizeLimit    int
 	commentSizeLimit        int
 	vcsProvider             vcsutils.VcsProvider
+	runtimeDetails          *RuntimeDetails
+	// The EPSS scores and the KEV membership of the CVEs, shown as table columns when set
+	exploitability map[string]exploitability.Info
+	// The full JFrog research data of the CVEs, by the CVEs
+	researchDetails map[string]research.Details
+	// The scopes of the direct dependencies, shown as a table column when set
+	dependencyScopes dependencyscope.Scopes
+	reportingOptions ReportingOptions
+	messages         Messages
+	// The fork the scanned pull request is from, noted in the summary comment when set
+	forkRepository string
+	// The commit of the source branch that was scanned, recorded in the summary comment when set
+	scannedCommit *ScannedCommit
+}
+
+// The sections of the pull request comments that can be shown or hidden
+const (
+	VulnerabilitiesSection = "vulnerabilities"
+	LicensesSection        = "licenses"
+	IacSection             = "iac"
+	SecretsSection         = "secrets"
+	SastSection            = "sast"
+)
+
+var CommentSections = []string{VulnerabilitiesSection, LicensesSection, IacSection, SecretsSection, SastSection}
+
+// ReportingOptions customizes the content of the pull request comments, to trim noisy sections and keep large comments readable
+type ReportingOptions struct {
+	// The sections to show, all the sections are shown if empty
+	ShowSections []string
+	// Hides the JFrog research details of the SCA issues
+	HideResearchDetails bool
+	// The maximal number of rows in the issue tables, unlimited if zero
+	MaxRowsPerTable int
+	// Linked from the tables with rows that were left out, usually the CI run that holds the full scan report
+	FullReportUrl string
+	// Notes under the SCA issue tables which of their issues don't fail the scan, since only applicable CVEs fail it
+	FailOnApplicableOnly bool
+}
+
+func (ro ReportingOptions) IsSectionShown(section string) bool {
+	return len(ro.ShowSections) == 0 || slices.Contains(ro.ShowSections, section)
+}
+
+// RuntimeDetails describes the environment that produced the output, to allow reproducing issues from the comment alone
+type RuntimeDetails struct {
+	FrogbotVersion         string
+	XrayVersion            string
+	XscVersion             string
+	AnalyzerManagerVersion string
+	ScanDuration           time.Duration
 }
 
 type CommentDecorator func(int, string) string
 
+// Longer than the longest label of a comment part, so the label always fits in the reserved space
+const commentPartLabelPlaceholder = "\n\n**{COMMENT_PART_LABEL}**"
+
 func (mo *MarkdownOutput) SetVcsProvider(provider vcsutils.VcsProvider) {
 	mo.vcsProvider = provider
 }
@@ -169,6 +282,78 @@ func (mo *MarkdownOutput) IsEntitledForJas() bool {
 	return mo.entitledForJas
 }
 
+func (mo *MarkdownOutput) SetJasStatusUnknown(unknown bool) {
+	mo.jasStatusUnknown = unknown
+}
+
+func (mo *MarkdownOutput) IsJasStatusUnknown() bool {
+	return mo.jasStatusUnknown
+}
+
+func (mo *MarkdownOutput) SetJasUnsupportedReason(reason string) {
+	mo.jasUnsupportedReason = reason
+}
+
+func (mo *MarkdownOutput) JasUnsupportedReason() string {
+	return mo.jasUnsupportedReason
+}
+
+func (mo *MarkdownOutput) SetExploitability(exploitabilityInfo map[string]exploitability.Info) {
+	mo.exploitability = exploitabilityInfo
+}
+
+func (mo *MarkdownOutput) Exploitability() map[string]exploitability.Info {
+	return mo.exploitability
+}
+
+func (mo *MarkdownOutput) SetResearchDetails(researchDetails map[string]research.Details) {
+	mo.researchDetails = researchDetails
+}
+
+func (mo *MarkdownOutput) ResearchDetails() map[string]research.Details {
+	return mo.researchDetails
+}
+
+func (mo *MarkdownOutput) SetDependencyScopes(scopes dependencyscope.Scopes) {
+	mo.dependencyScopes = scopes
+}
+
+func (mo *MarkdownOutput) DependencyScopes() dependencyscope.Scopes {
+	return mo.dependencyScopes
+}
+
+func (mo *MarkdownOutput) SetReportingOptions(options ReportingOptions) {
+	mo.reportingOptions = options
+}
+
+func (mo *MarkdownOutput) ReportingOptions() ReportingOptions {
+	return mo.reportingOptions
+}
+
+func (mo *MarkdownOutput) SetMessages(messages Messages) {
+	mo.messages = messages
+}
+
+func (mo *MarkdownOutput) Messages() Messages {
+	return mo.messages
+}
+
+func (mo *MarkdownOutput) SetForkRepository(forkRepository string) {
+	mo.forkRepository = forkRepository
+}
+
+func (mo *MarkdownOutput) ForkRepository() string {
+	return mo.forkRepository
+}
+
+func (mo *MarkdownOutput) SetScannedCommit(commit *ScannedCommit) {
+	mo.scannedCommit = commit
+}
+
+func (mo *MarkdownOutput) ScannedCommit() *ScannedCommit {
+	return mo.scannedCommit
+}
+
 func (mo *MarkdownOutput) PullRequestCommentTitle() string {
 	return mo.pullRequestCommentTitle
 }
@@ -188,6 +373,14 @@ func (mo *MarkdownOutput) SetSizeLimit(client vcsclient.VcsClient) {
 	mo.descriptionSizeLimit = client.GetPullRequestDetailsSizeLimit()
 }
 
+func (mo *MarkdownOutput) SetRuntimeDetails(details *RuntimeDetails) {
+	mo.runtimeDetails = details
+}
+
+func (mo *MarkdownOutput) RuntimeDetails() *RuntimeDetails {
+	return mo.runtimeDetails
+}
+
 func GetMarkdownSizeLimit(client vcsclient.VcsClient) int {
 	limit := client.GetPullRequestCommentSizeLimit()
 	if client.GetPullRequestDetailsSizeLimit() < limit {
@@ -249,10 +442,12 @@ func WriteNewLine(builder *strings.Builder) {
 func ConvertContentToComments(content []string, writer OutputWriter, commentDecorators ...CommentDecorator) (comments []string) {
 	commentBuilder := strings.Builder{}
 	for _, commentContent := range content {
-		if newContent, limitReached := getContentAndResetBuilderIfLimitReached(len(comments), commentContent, &commentBuilder, writer, commentDecorators...); limitReached {
-			comments = append(comments, newContent)
+		for _, contentPart := range splitOversizedContent(commentContent, writer, commentDecorators...) {
+			if newContent, limitReached := getContentAndResetBuilderIfLimitReached(len(comments), contentPart, &commentBuilder, writer, commentDecorators...); limitReached {
+				comments = append(comments, newContent)
+			}
+			WriteContent(&commentBuilder, contentPart)
 		}
-		WriteContent(&commentBuilder, commentContent)
 	}
 	if commentBuilder.Len() > 0 || len(content) == 0 {
 		comments = append(comments, decorate(len(comments), commentBuilder.String(), commentDecorators...))
@@ -276,6 +471,82 @@ func getContentAndResetBuilderIfLimitReached(commentCount int, newContent string
 	return decorate(commentCount, content, commentDecorators...), true
 }
 
+// Content that doesn't fit in a single comment by itself, such as a large table, is split at line boundaries.
+// Content that only exceeds the limit of the first comment is moved to the next comment instead, which may have a higher limit.
+func splitOversizedContent(content string, writer OutputWriter, commentDecorators ...CommentDecorator) []string {
+	limit := writer.SizeLimit(true)
+	if limit == 0 {
+		//  No limit
+		return []string{content}
+	}
+	// The content is written on a new line of the comment, after the decorators
+	maxPartSize := limit - decoratorsSize(1, commentDecorators...) - 1
+	if len(content) < maxPartSize {
+		return []string{content}
+	}
+	log.Debug(fmt.Sprintf("Content size (%d) exceeds the comments size limit (%d), splitting it by lines", len(content), limit))
+	return splitContentByLines(content, maxPartSize)
+}
+
+// Splits the content at line boundaries into parts that don't exceed the max size, unless a single line exceeds it.
+// When a markdown table is split, its header is repeated at the start of the next part, so each part shows a valid table.
+func splitContentByLines(content string, maxPartSize int) (parts []string) {
+	lines := strings.Split(content, "\n")
+	part := strings.Builder{}
+	tableHeader := ""
+	for i, line := range lines {
+		switch {
+		case i > 0 && isTableDelimiterRow(line) && isTableRow(lines[i-1]):
+			tableHeader = lines[i-1] + "\n" + line
+		case !isTableRow(line):
+			tableHeader = ""
+		}
+		if part.Len() > 0 && part.Len()+len(line)+1 > maxPartSize {
+			parts = append(parts, part.String())
+			part.Reset()
+			if tableHeader != "" && !strings.HasSuffix(tableHeader, line) {
+				part.WriteString(tableHeader)
+			}
+		}
+		if part.Len() > 0 {
+			WriteNewLine(&part)
+		}
+		part.WriteString(line)
+	}
+	if part.Len() > 0 {
+		parts = append(parts, part.String())
+	}
+	return
+}
+
+func isTableRow(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+func isTableDelimiterRow(line string) bool {
+	return isTableRow(line) && strings.Contains(line, "-") && strings.Trim(line, "|:- ") == ""
+}
+
+// Reserves space for the label of the comment part at the end of the content, see LabelCommentParts
+func GetCommentPartDecorator() CommentDecorator {
+	return func(_ int, content string) string {
+		return content + commentPartLabelPlaceholder
+	}
+}
+
+// Labels each of the comments that the content was split into with its part number, such as 'Part 1/2', so they are read in order.
+// The comments must be decorated with GetCommentPartDecorator. The placeholder is removed if the content wasn't split.
+func LabelCommentParts(comments []string) []string {
+	for i := range comments {
+		label := ""
+		if len(comments) > 1 {
+			label = "\n\n" + MarkAsBold(fmt.Sprintf("Part %d/%d", i+1, len(comments)))
+		}
+		comments[i] = strings.Replace(comments[i], commentPartLabelPlaceholder, label, 1)
+	}
+	return comments
+}
+
 func decorate(commentCount int, content string, commentDecorators ...CommentDecorator) string {
 	for _, decorator := range commentDecorators {
 		content = decorator(commentCount, content)
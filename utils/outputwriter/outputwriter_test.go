@@ -1,10 +1,12 @@
 package outputwriter
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMarkdownComment(t *testing.T) {
@@ -136,3 +138,76 @@ func TestWriteContent(t *testing.T) {
 		assert.Equal(t, tc.expectedOutput, builder.String())
 	}
 }
+
+func TestSplitContentByLines(t *testing.T) {
+	table := "| Severity | ID |\n| :---: | :---: |\n| High | CVE-1 |\n| Low | CVE-2 |\n| Medium | CVE-3 |"
+	testCases := []struct {
+		name          string
+		content       string
+		maxPartSize   int
+		expectedParts []string
+	}{
+		{
+			name:          "Content fits",
+			content:       "line 1\nline 2",
+			maxPartSize:   100,
+			expectedParts: []string{"line 1\nline 2"},
+		},
+		{
+			name:          "Split by lines",
+			content:       "line 1\nline 2\nline 3",
+			maxPartSize:   14,
+			expectedParts: []string{"line 1\nline 2", "line 3"},
+		},
+		{
+			name:          "Line longer than the max size",
+			content:       "a long line\nline",
+			maxPartSize:   5,
+			expectedParts: []string{"a long line", "line"},
+		},
+		{
+			name:        "The table header is repeated",
+			content:     "title\n" + table + "\nafter the table",
+			maxPartSize: 70,
+			expectedParts: []string{
+				"title\n| Severity | ID |\n| :---: | :---: |\n| High | CVE-1 |",
+				"| Severity | ID |\n| :---: | :---: |\n| Low | CVE-2 |\n| Medium | CVE-3 |",
+				"after the table",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedParts, splitContentByLines(tc.content, tc.maxPartSize))
+		})
+	}
+}
+
+func TestConvertOversizedContentToComments(t *testing.T) {
+	rows := []string{"| Severity | ID |", "| :---: | :---: |"}
+	for i := 0; i < 100; i++ {
+		rows = append(rows, fmt.Sprintf("| High | CVE-2024-%04d |", i))
+	}
+	table := strings.Join(rows, "\n")
+	writer := &SimplifiedOutput{MarkdownOutput{descriptionSizeLimit: 1000, commentSizeLimit: 1000}}
+	comments := GetMainCommentContent([]string{"summary", table}, true, true, writer)
+	require.Greater(t, len(comments), 2)
+	for i, comment := range comments {
+		assert.LessOrEqual(t, len(comment), 1000)
+		// Each part can be deleted by the following scans
+		assert.True(t, IsFrogbotComment(comment))
+		assert.Contains(t, comment, MarkAsBold(fmt.Sprintf("Part %d/%d", i+1, len(comments))))
+		assert.NotContains(t, comment, commentPartLabelPlaceholder)
+		if strings.Contains(comment, "| High |") {
+			assert.Contains(t, comment, "| Severity | ID |\n| :---: | :---: |\n| High |")
+		}
+	}
+	assert.Equal(t, 100, strings.Count(strings.Join(comments, ""), "| High |"))
+
+	// Content that isn't split isn't labeled
+	writer = &SimplifiedOutput{}
+	comments = GetMainCommentContent([]string{"summary", table}, true, true, writer)
+	require.Len(t, comments, 1)
+	assert.NotContains(t, comments[0], "Part 1")
+	assert.NotContains(t, comments[0], commentPartLabelPlaceholder)
+}
@@ -24,10 +24,13 @@ func (smo *SimplifiedOutput) SeverityIcon(severity severityutils.Severity) strin
 }
 
 func (smo *SimplifiedOutput) FormattedSeverity(severity, _ string) string {
-	return severity
+	return smo.messages.severity(severity)
 }
 
 func (smo *SimplifiedOutput) Image(source ImageSource) string {
+	if banner, exists := localizedBanner(source, smo); exists {
+		return banner
+	}
 	return MarkAsBold(GetSimplifiedTitle(source))
 }
 
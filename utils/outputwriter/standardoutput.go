@@ -25,12 +25,15 @@ func (so *StandardOutput) SeverityIcon(severity severityutils.Severity) string {
 
 func (so *StandardOutput) FormattedSeverity(severity, applicability string) string {
 	if !so.hasInternetConnection {
-		return severity
+		return so.messages.severity(severity)
 	}
-	return fmt.Sprintf("%s%8s", getSeverityTag(IconName(severity), applicability), severity)
+	return fmt.Sprintf("%s%8s", getSeverityTag(IconName(severity), applicability), so.messages.severity(severity))
 }
 
 func (so *StandardOutput) Image(source ImageSource) string {
+	if banner, exists := localizedBanner(source, so); exists {
+		return banner
+	}
 	if so.hasInternetConnection {
 		return GetBanner(source)
 	}
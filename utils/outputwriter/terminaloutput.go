@@ -0,0 +1,40 @@
+package outputwriter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// TerminalOutput writes the content as plain text for the terminal, as the local scan prints its results instead of commenting on a pull request.
+// The titles are underlined instead of marked with markdown, and the details are always expanded.
+type TerminalOutput struct {
+	SimplifiedOutput
+}
+
+func NewTerminalOutput() *TerminalOutput {
+	return &TerminalOutput{SimplifiedOutput{MarkdownOutput{hasInternetConnection: true}}}
+}
+
+func (to *TerminalOutput) Image(source ImageSource) string {
+	if banner, exists := to.messages.banner(source); exists {
+		return banner
+	}
+	return GetSimplifiedTitle(source)
+}
+
+func (to *TerminalOutput) MarkAsDetails(summary string, subTitleDepth int, content string) string {
+	return fmt.Sprintf("%s\n%s", to.MarkAsTitle(summary, subTitleDepth), strings.TrimLeft(content, "\n"))
+}
+
+func (to *TerminalOutput) MarkAsTitle(title string, subTitleDepth int) string {
+	if subTitleDepth == 0 {
+		return title
+	}
+	// The top titles are underlined with a double line
+	underline := "-"
+	if subTitleDepth == 1 {
+		underline = "="
+	}
+	return fmt.Sprintf("\n%s\n%s", title, strings.Repeat(underline, utf8.RuneCountInString(title)))
+}
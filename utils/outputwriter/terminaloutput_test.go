@@ -0,0 +1,19 @@
+package outputwriter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminalOutput(t *testing.T) {
+	to := NewTerminalOutput()
+	assert.Equal(t, "\nFrogbot\n=======", to.MarkAsTitle("Frogbot", 1))
+	assert.Equal(t, "\n🔖 Details\n---------", to.MarkAsTitle("🔖 Details", 3))
+	assert.Equal(t, "Note", to.MarkAsTitle("Note", 0))
+	assert.Equal(t, "Note\nJAS isn't enabled", to.MarkAsDetails("Note", 0, "\nJAS isn't enabled"))
+	assert.Equal(t, "the content", to.MarkInCenter("the content"))
+	assert.Equal(t, GetSimplifiedTitle(VulnerabilitiesPrBannerSource), to.Image(VulnerabilitiesPrBannerSource))
+	to.SetMessages(Messages{Banners: map[string]string{IssuesFoundBanner: "Security issues found"}})
+	assert.Equal(t, "Security issues found", to.Image(VulnerabilitiesPrBannerSource))
+}
@@ -8,16 +8,20 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xsc"
 	"github.com/jfrog/jfrog-client-go/xsc/services"
 	"golang.org/x/exp/slices"
 
+	"github.com/jfrog/frogbot/v2/utils/automerge"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/policy"
 	securityutils "github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 
@@ -31,6 +35,12 @@ import (
 const (
 	frogbotConfigDir  = ".frogbot"
 	FrogbotConfigFile = "frogbot-config.yml"
+	// The path of the config file in the repository
+	FrogbotConfigFilePath = frogbotConfigDir + "/" + FrogbotConfigFile
+	// The expected format of the fail after date
+	failAfterDateLayout = "2006-01-02"
+	// The default number of days that the fix branches of merged and closed pull requests are kept before they are deleted
+	defaultCleanupRetentionDays = 7
 )
 
 var (
@@ -61,10 +71,35 @@ type Repository struct {
 	Server       coreconfig.ServerDetails
 }
 
+// Replaces the default JFrog platform instance with the one configured for the repository, if there is one
+func (r *Repository) setRepositoryServerIfNeeded() (err error) {
+	server, err := r.JFrogPlatform.getServerDetails()
+	if err != nil || server == nil {
+		return
+	}
+	log.Debug("Using the JFrog platform instance configured for the repository:", server.Url)
+	r.Server = *server
+	r.Params.XrayVersion, r.Params.XscVersion, err = xsc.GetJfrogServicesVersion(server)
+	return
+}
+
 func (r *Repository) setOutputWriterDetails() {
-	r.OutputWriter = outputwriter.GetCompatibleOutputWriter(r.Params.GitProvider)
+	r.setOutputWriter(outputwriter.GetCompatibleOutputWriter(r.Params.GitProvider))
+}
+
+// Sets the writer of the repository, configured by the params of the repository
+func (r *Repository) setOutputWriter(writer outputwriter.OutputWriter) {
+	r.OutputWriter = writer
 	r.OutputWriter.SetAvoidExtraMessages(r.Params.AvoidExtraMessages)
 	r.OutputWriter.SetPullRequestCommentTitle(r.Params.PullRequestCommentTitle)
+	r.OutputWriter.SetReportingOptions(outputwriter.ReportingOptions{
+		ShowSections:         r.Params.ShowSections,
+		HideResearchDetails:  r.Params.HideResearchDetails,
+		MaxRowsPerTable:      r.Params.MaxRowsPerTable,
+		FullReportUrl:        GetCiRunUrl(),
+		FailOnApplicableOnly: r.Params.FailOnApplicableOnly,
+	})
+	r.OutputWriter.SetMessages(r.Params.Messages)
 }
 
 type Params struct {
@@ -91,9 +126,21 @@ type Project struct {
 	UseWrapper          *bool    `yaml:"useWrapper,omitempty"`
 	MaxPnpmTreeDepth    string   `yaml:"maxPnpmTreeDepth,omitempty"`
 	DepsRepo            string   `yaml:"repository,omitempty"`
-	InstallCommandName  string
-	InstallCommandArgs  []string
-	IsRecursiveScan     bool
+	// The depth of the directories below the repository root that are searched for projects when no working directories are set.
+	// The projects are detected by the scan of the repository only.
+	DetectionDepth int `yaml:"detectionDepth,omitempty"`
+	// The command that repins the Bazel lockfiles after the versions of the vulnerable dependencies are updated
+	BazelRepinCommand string `yaml:"bazelRepinCommand,omitempty"`
+	// Install commands of specific working directories, which are used instead of the install command of the project
+	InstallCommands map[string]string `yaml:"installCommands,omitempty"`
+	// Detects an install command that keeps the dependencies consistent with the lock files, when no install command is set
+	DetectInstallCommand bool `yaml:"detectInstallCommand,omitempty"`
+	// The Xray watches and the JFrog project the project is scanned with, instead of the ones set for the repository
+	Watches            []string `yaml:"watches,omitempty"`
+	JFrogProjectKey    string   `yaml:"jfrogProjectKey,omitempty"`
+	InstallCommandName string
+	InstallCommandArgs []string
+	IsRecursiveScan    bool
 }
 
 func (p *Project) setDefaultsIfNeeded() error {
@@ -108,6 +155,15 @@ func (p *Project) setDefaultsIfNeeded() error {
 		}
 		p.WorkingDirs = append(p.WorkingDirs, workingDir)
 	}
+	if p.IsRecursiveScan && p.DetectionDepth == 0 {
+		var err error
+		if p.DetectionDepth, err = getIntEnv(ProjectDetectionDepthEnv, defaultProjectDetectionDepth); err != nil {
+			return err
+		}
+	}
+	if p.DetectionDepth < 0 {
+		return fmt.Errorf("the project detection depth must not be negative, provided: %d", p.DetectionDepth)
+	}
 	if len(p.PathExclusions) == 0 {
 		if p.PathExclusions, _ = readArrayParamFromEnv(PathExclusionsEnv, ";"); len(p.PathExclusions) == 0 {
 			p.PathExclusions = securityutils.DefaultScaExcludePatterns
@@ -126,12 +182,21 @@ func (p *Project) setDefaultsIfNeeded() error {
 	if p.InstallCommand != "" {
 		setProjectInstallCommand(p.InstallCommand, p)
 	}
+	if !p.DetectInstallCommand {
+		var err error
+		if p.DetectInstallCommand, err = getBoolEnv(DetectInstallCommandEnv, false); err != nil {
+			return err
+		}
+	}
 	if p.PipRequirementsFile == "" {
 		p.PipRequirementsFile = getTrimmedEnv(RequirementsFileEnv)
 	}
 	if p.DepsRepo == "" {
 		p.DepsRepo = getTrimmedEnv(DepsRepoEnv)
 	}
+	if p.BazelRepinCommand == "" {
+		p.BazelRepinCommand = getTrimmedEnv(BazelRepinCommandEnv)
+	}
 	if p.MaxPnpmTreeDepth == "" {
 		p.MaxPnpmTreeDepth = getTrimmedEnv(MaxPnpmTreeDepthEnv)
 	}
@@ -139,6 +204,98 @@ func (p *Project) setDefaultsIfNeeded() error {
 	return nil
 }
 
+// Splits the working directories that have their own install command into separate projects, since each audit runs a single install command.
+// The rest of the working directories remain in the project.
+func (p *Project) splitByInstallCommands() ([]Project, error) {
+	if len(p.InstallCommands) == 0 {
+		return []Project{*p}, nil
+	}
+	installCommands := make(map[string]string, len(p.InstallCommands))
+	for workingDir, installCommand := range p.InstallCommands {
+		if strings.TrimSpace(installCommand) == "" {
+			return nil, fmt.Errorf("the install command of the '%s' working directory is empty", workingDir)
+		}
+		installCommands[filepath.Clean(workingDir)] = strings.TrimSpace(installCommand)
+	}
+	var projects []Project
+	var remainingWorkingDirs []string
+	for _, workingDir := range p.WorkingDirs {
+		installCommand, exists := installCommands[filepath.Clean(workingDir)]
+		if !exists {
+			remainingWorkingDirs = append(remainingWorkingDirs, workingDir)
+			continue
+		}
+		delete(installCommands, filepath.Clean(workingDir))
+		workingDirProject := *p
+		workingDirProject.WorkingDirs = []string{workingDir}
+		workingDirProject.InstallCommands = nil
+		workingDirProject.InstallCommand = installCommand
+		workingDirProject.InstallCommandArgs = nil
+		setProjectInstallCommand(installCommand, &workingDirProject)
+		projects = append(projects, workingDirProject)
+	}
+	if len(installCommands) > 0 {
+		var unknownWorkingDirs []string
+		for workingDir := range installCommands {
+			unknownWorkingDirs = append(unknownWorkingDirs, workingDir)
+		}
+		slices.Sort(unknownWorkingDirs)
+		return nil, fmt.Errorf("install commands are set for directories that aren't working directories of the project: %s", strings.Join(unknownWorkingDirs, ", "))
+	}
+	if len(remainingWorkingDirs) > 0 {
+		project := *p
+		project.WorkingDirs = remainingWorkingDirs
+		project.InstallCommands = nil
+		projects = append([]Project{project}, projects...)
+	}
+	return projects, nil
+}
+
+// Filters the working directories of the projects by the JF_INCLUDE_WORKING_DIRS and JF_EXCLUDE_WORKING_DIRS environment variables,
+// so a CI job can scan its part of a monorepo without editing the frogbot-config.yml file. The projects that are left without working directories are removed.
+func filterProjectsWorkingDirs(projects []Project) ([]Project, error) {
+	includedWorkingDirs, _ := readArrayParamFromEnv(IncludeWorkingDirsEnv, ";")
+	excludedWorkingDirs, _ := readArrayParamFromEnv(ExcludeWorkingDirsEnv, ";")
+	if len(includedWorkingDirs) == 0 && len(excludedWorkingDirs) == 0 {
+		return projects, nil
+	}
+	var filteredProjects []Project
+	for _, project := range projects {
+		var workingDirs []string
+		for _, workingDir := range project.WorkingDirs {
+			if (len(includedWorkingDirs) == 0 || matchesWorkingDirPatterns(workingDir, includedWorkingDirs)) && !matchesWorkingDirPatterns(workingDir, excludedWorkingDirs) {
+				workingDirs = append(workingDirs, workingDir)
+			} else {
+				log.Debug(fmt.Sprintf("The '%s' working directory is filtered out by the %s and %s environment variables", workingDir, IncludeWorkingDirsEnv, ExcludeWorkingDirsEnv))
+			}
+		}
+		if len(workingDirs) == 0 {
+			continue
+		}
+		project.WorkingDirs = workingDirs
+		filteredProjects = append(filteredProjects, project)
+	}
+	if len(filteredProjects) == 0 {
+		return nil, fmt.Errorf("all the working directories of the projects are filtered out by the %s and %s environment variables", IncludeWorkingDirsEnv, ExcludeWorkingDirsEnv)
+	}
+	return filteredProjects, nil
+}
+
+// The patterns are working directories, or glob patterns of working directories such as 'frontend/*'
+func matchesWorkingDirPatterns(workingDir string, patterns []string) bool {
+	workingDir = filepath.ToSlash(filepath.Clean(workingDir))
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		pattern = filepath.ToSlash(filepath.Clean(pattern))
+		if matched, err := path.Match(pattern, workingDir); pattern == workingDir || (err == nil && matched) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Project) GetTechFromInstallCmdIfExists() []string {
 	var technologies []string
 	if p.InstallCommandName != "" {
@@ -154,21 +311,184 @@ func (p *Project) GetTechFromInstallCmdIfExists() []string {
 	return technologies
 }
 
+// Returns true if the project is scanned with its own watches or JFrog project
+func (p *Project) HasViolationsContext() bool {
+	return len(p.Watches) > 0 || p.JFrogProjectKey != ""
+}
+
+// Returns the watches and the JFrog project key the project is scanned with.
+// The ones set for the project override the given ones of the repository.
+func (p *Project) GetViolationsContext(repositoryWatches []string, repositoryProjectKey string) (watches []string, jfrogProjectKey string) {
+	watches, jfrogProjectKey = repositoryWatches, repositoryProjectKey
+	if len(p.Watches) > 0 {
+		watches = p.Watches
+	}
+	if p.JFrogProjectKey != "" {
+		jfrogProjectKey = p.JFrogProjectKey
+	}
+	return
+}
+
 type Scan struct {
-	IncludeAllVulnerabilities       bool      `yaml:"includeAllVulnerabilities,omitempty"`
-	FixableOnly                     bool      `yaml:"fixableOnly,omitempty"`
-	DetectionOnly                   bool      `yaml:"skipAutoFix,omitempty"`
-	FailOnSecurityIssues            *bool     `yaml:"failOnSecurityIssues,omitempty"`
-	AvoidPreviousPrCommentsDeletion bool      `yaml:"avoidPreviousPrCommentsDeletion,omitempty"`
-	MinSeverity                     string    `yaml:"minSeverity,omitempty"`
-	DisableJas                      bool      `yaml:"disableJas,omitempty"`
-	AddPrCommentOnSuccess           bool      `yaml:"addPrCommentOnSuccess,omitempty"`
-	AllowedLicenses                 []string  `yaml:"allowedLicenses,omitempty"`
-	Projects                        []Project `yaml:"projects,omitempty"`
-	EmailDetails                    `yaml:",inline"`
-	ConfigProfile                   *services.ConfigProfile
-	SkipAutoInstall                 bool
-	AllowPartialResults             bool
+	IncludeAllVulnerabilities       bool        `yaml:"includeAllVulnerabilities,omitempty"`
+	FixableOnly                     bool        `yaml:"fixableOnly,omitempty"`
+	FailOnApplicableOnly            bool        `yaml:"failOnApplicableOnly,omitempty"`
+	FixApplicableOnly               bool        `yaml:"fixApplicableOnly,omitempty"`
+	FixMinSeverity                  string      `yaml:"fixMinSeverity,omitempty"`
+	SkipDevDependencies             bool        `yaml:"skipDevDependencies,omitempty"`
+	DetectionOnly                   bool        `yaml:"skipAutoFix,omitempty"`
+	FailOnSecurityIssues            *bool       `yaml:"failOnSecurityIssues,omitempty"`
+	FailAfterDate                   string      `yaml:"failAfterDate,omitempty"`
+	AvoidPreviousPrCommentsDeletion bool        `yaml:"avoidPreviousPrCommentsDeletion,omitempty"`
+	CollapsePreviousPrComments      bool        `yaml:"collapsePreviousPrComments,omitempty"`
+	BatchReviewComments             bool        `yaml:"batchReviewComments,omitempty"`
+	ScanProgressComment             bool        `yaml:"scanProgressComment,omitempty"`
+	IncrementalScan                 bool        `yaml:"incrementalScan,omitempty"`
+	CommentSuppressions             bool        `yaml:"commentSuppressions,omitempty"`
+	SuppressionApprovers            []string    `yaml:"suppressionApprovers,omitempty"`
+	MinSeverity                     string      `yaml:"minSeverity,omitempty"`
+	DisableJas                      bool        `yaml:"disableJas,omitempty"`
+	Jas                             JasScanners `yaml:"jas,omitempty"`
+	// Path patterns, in the .gitignore syntax, of vendored and generated files whose Secrets, IaC and SAST findings aren't reported
+	ExcludePatterns       []string `yaml:"excludePatterns,omitempty"`
+	AddPrCommentOnSuccess bool     `yaml:"addPrCommentOnSuccess,omitempty"`
+	AllowedLicenses       []string `yaml:"allowedLicenses,omitempty"`
+	TargetCves            []string `yaml:"targetCves,omitempty"`
+	TargetCvesFixOnly     bool     `yaml:"targetCvesFixOnly,omitempty"`
+	// The executables that fix the vulnerabilities of technologies, instead of the built-in package handlers
+	PackageHandlerPlugins    map[string]string `yaml:"packageHandlerPlugins,omitempty"`
+	InternalNamespaces       []string          `yaml:"internalNamespaces,omitempty"`
+	ReportPath               string            `yaml:"reportPath,omitempty"`
+	SbomPath                 string            `yaml:"sbomPath,omitempty"`
+	ExploitabilityEnrichment bool              `yaml:"exploitabilityEnrichment,omitempty"`
+	ResearchEnrichment       bool              `yaml:"researchEnrichment,omitempty"`
+	PrioritizeExploitedFixes bool              `yaml:"prioritizeExploitedFixes,omitempty"`
+	ValidateSecrets          bool              `yaml:"validateSecrets,omitempty"`
+	BlockOnSecrets           bool              `yaml:"blockOnSecrets,omitempty"`
+	ScanDockerfiles          bool              `yaml:"scanDockerfiles,omitempty"`
+	ScanGitHubActions        bool              `yaml:"scanGitHubActions,omitempty"`
+	ScanBazel                bool              `yaml:"scanBazel,omitempty"`
+	ScanComposer             bool              `yaml:"scanComposer,omitempty"`
+	ScanSystemPackages       bool              `yaml:"scanSystemPackages,omitempty"`
+	ScanCuration             bool              `yaml:"scanCuration,omitempty"`
+	FailOnCurationBlocked    bool              `yaml:"failOnCurationBlocked,omitempty"`
+	// The SARIF files of third-party scanners that ran earlier in the CI run, whose results are added to the pull request comment
+	ExternalSarifPaths  []string  `yaml:"externalSarifPaths,omitempty"`
+	Projects            []Project `yaml:"projects,omitempty"`
+	EmailDetails        `yaml:",inline"`
+	ConfigProfile       *services.ConfigProfile
+	Policy              *policy.Policy `yaml:"-"`
+	SkipAutoInstall     bool
+	AllowPartialResults bool
+	MaxConcurrentRepos  int
+}
+
+// Returns the target CVEs and packages that the scan results are limited to.
+// In the fix-only mode, the targets limit only the fixes, so all the issues are scanned and reported.
+func (s *Scan) GetScannedTargetCves() []string {
+	if s.TargetCvesFixOnly {
+		return nil
+	}
+	return s.TargetCves
+}
+
+// Sets the target CVEs and packages of the fix-only mode from JF_FIX_CVES, which is the same as setting JF_TARGET_CVES and JF_TARGET_CVES_FIX_ONLY
+func (s *Scan) setFixCvesFromEnv() error {
+	e := &ErrMissingEnv{}
+	fixCves, err := readArrayParamFromEnv(FixCvesEnv, ",")
+	if err != nil && !e.IsMissingEnvErr(err) {
+		return err
+	}
+	if len(fixCves) == 0 {
+		return nil
+	}
+	if len(s.TargetCves) > 0 {
+		return fmt.Errorf("the %s and %s environment variables can't be set together. Please set %s to only fix the listed CVEs and packages", TargetCvesEnv, FixCvesEnv, FixCvesEnv)
+	}
+	s.TargetCves, s.TargetCvesFixOnly = fixCves, true
+	return nil
+}
+
+// Returns true before the fail after date. During this onboarding period, Frogbot reports the security issues without failing the task.
+func (s *Scan) IsInOnboardingPeriod() bool {
+	if s.FailAfterDate == "" {
+		return false
+	}
+	failAfterDate, err := time.Parse(failAfterDateLayout, s.FailAfterDate)
+	return err == nil && time.Now().Before(failAfterDate)
+}
+
+// JasScanners selects the JFrog Advanced Security scanners that run, so expensive scanners can be disabled on some of the scans.
+// The exclusions are path patterns of files whose findings aren't reported, in addition to the path exclusions of the projects.
+type JasScanners struct {
+	EnableApplicability *bool    `yaml:"enableApplicability,omitempty"`
+	EnableSecrets       *bool    `yaml:"enableSecrets,omitempty"`
+	EnableIac           *bool    `yaml:"enableIaC,omitempty"`
+	EnableSast          *bool    `yaml:"enableSast,omitempty"`
+	SecretsExclusions   []string `yaml:"secretsExclusions,omitempty"`
+	IacExclusions       []string `yaml:"iacExclusions,omitempty"`
+	SastExclusions      []string `yaml:"sastExclusions,omitempty"`
+}
+
+func (j *JasScanners) setDefaultsIfNeeded() (err error) {
+	for env, enabled := range map[string]**bool{
+		EnableApplicabilityEnv: &j.EnableApplicability,
+		EnableSecretsEnv:       &j.EnableSecrets,
+		EnableIacEnv:           &j.EnableIac,
+		EnableSastEnv:          &j.EnableSast,
+	} {
+		if *enabled == nil {
+			var enable bool
+			if enable, err = getBoolEnv(env, true); err != nil {
+				return
+			}
+			*enabled = &enable
+		}
+	}
+	e := &ErrMissingEnv{}
+	for env, exclusions := range map[string]*[]string{
+		SecretsExclusionsEnv: &j.SecretsExclusions,
+		IacExclusionsEnv:     &j.IacExclusions,
+		SastExclusionsEnv:    &j.SastExclusions,
+	} {
+		if len(*exclusions) == 0 {
+			if *exclusions, err = readArrayParamFromEnv(env, ";"); err != nil && !e.IsMissingEnvErr(err) {
+				return
+			}
+			err = nil
+		}
+	}
+	return
+}
+
+// Returns the sub scans to perform, or nil if all the scanners are enabled
+func (j *JasScanners) ScansToPerform() []securityutils.SubScanType {
+	if isEnabled(j.EnableApplicability) && isEnabled(j.EnableSecrets) && isEnabled(j.EnableIac) && isEnabled(j.EnableSast) {
+		return nil
+	}
+	scans := []securityutils.SubScanType{securityutils.ScaScan}
+	if isEnabled(j.EnableApplicability) {
+		scans = append(scans, securityutils.ContextualAnalysisScan)
+	}
+	if isEnabled(j.EnableSecrets) {
+		scans = append(scans, securityutils.SecretsScan)
+	}
+	if isEnabled(j.EnableIac) {
+		scans = append(scans, securityutils.IacScan)
+	}
+	if isEnabled(j.EnableSast) {
+		scans = append(scans, securityutils.SastScan)
+	}
+	return scans
+}
+
+func (j *JasScanners) isAnyEnabled() bool {
+	return isEnabled(j.EnableApplicability) || isEnabled(j.EnableSecrets) || isEnabled(j.EnableIac) || isEnabled(j.EnableSast)
+}
+
+// Options that weren't set are enabled
+func isEnabled(enabled *bool) bool {
+	return enabled == nil || *enabled
 }
 
 type EmailDetails struct {
@@ -176,7 +496,47 @@ type EmailDetails struct {
 	SmtpPort       string
 	SmtpUser       string
 	SmtpPassword   string
-	EmailReceivers []string `yaml:"emailReceivers,omitempty"`
+	EmailReceivers EmailReceivers `yaml:"emailReceivers,omitempty"`
+}
+
+// EmailReceivers are the email addresses that the alerts are sent to, by the types of the alerts.
+// A list of addresses, as in the earlier versions of the config, sets the receivers of the exposed secrets alerts.
+type EmailReceivers struct {
+	Secrets  []string `yaml:"secrets,omitempty"`
+	Critical []string `yaml:"critical,omitempty"`
+	Licenses []string `yaml:"licenses,omitempty"`
+}
+
+func (er *EmailReceivers) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var secretsReceivers []string
+	if err := unmarshal(&secretsReceivers); err == nil {
+		er.Secrets = secretsReceivers
+		return nil
+	}
+	type emailReceivers EmailReceivers
+	return unmarshal((*emailReceivers)(er))
+}
+
+// The receivers of the exposed secrets alerts only are written as a list, so the configs of the earlier versions keep their form
+func (er EmailReceivers) MarshalYAML() (interface{}, error) {
+	if len(er.Critical) == 0 && len(er.Licenses) == 0 {
+		return er.Secrets, nil
+	}
+	type emailReceivers EmailReceivers
+	return emailReceivers(er), nil
+}
+
+// Returns the receivers of the alerts of the given type
+func (er EmailReceivers) Get(alertType AlertType) []string {
+	switch alertType {
+	case SecretsAlert:
+		return er.Secrets
+	case CriticalVulnerabilitiesAlert:
+		return er.Critical
+	case LicenseViolationsAlert:
+		return er.Licenses
+	}
+	return nil
 }
 
 func (s *Scan) SetEmailDetails() error {
@@ -198,9 +558,16 @@ func (s *Scan) SetEmailDetails() error {
 	if s.SmtpPassword == "" {
 		return fmt.Errorf("failed while setting your email details. SMTP password is expected, but the %s environment variable is empty", SmtpPasswordEnv)
 	}
-	if len(s.EmailReceivers) == 0 {
-		if emailReceiversEnv := getTrimmedEnv(EmailReceiversEnv); emailReceiversEnv != "" {
-			s.EmailReceivers = strings.Split(emailReceiversEnv, ",")
+	for envName, receivers := range map[string]*[]string{
+		EmailReceiversEnv:         &s.EmailReceivers.Secrets,
+		CriticalEmailReceiversEnv: &s.EmailReceivers.Critical,
+		LicensesEmailReceiversEnv: &s.EmailReceivers.Licenses,
+	} {
+		if len(*receivers) > 0 {
+			continue
+		}
+		if receiversEnv := getTrimmedEnv(envName); receiversEnv != "" {
+			*receivers = strings.Split(receiversEnv, ",")
 		}
 	}
 	return nil
@@ -218,6 +585,31 @@ func (s *Scan) setDefaultsIfNeeded() (err error) {
 			return
 		}
 	}
+	if !s.CollapsePreviousPrComments {
+		if s.CollapsePreviousPrComments, err = getBoolEnv(CollapsePreviousPrCommentsEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.BatchReviewComments {
+		if s.BatchReviewComments, err = getBoolEnv(BatchReviewCommentsEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ScanProgressComment {
+		if s.ScanProgressComment, err = getBoolEnv(ScanProgressCommentEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.IncrementalScan {
+		if s.IncrementalScan, err = getBoolEnv(IncrementalPrScanEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.CommentSuppressions {
+		if s.CommentSuppressions, err = getBoolEnv(CommentSuppressionsEnv, false); err != nil {
+			return
+		}
+	}
 	if !s.FixableOnly {
 		if s.FixableOnly, err = getBoolEnv(FixableOnlyEnv, false); err != nil {
 			return
@@ -228,6 +620,46 @@ func (s *Scan) setDefaultsIfNeeded() (err error) {
 			return
 		}
 	}
+	if !s.FailOnApplicableOnly {
+		if s.FailOnApplicableOnly, err = getBoolEnv(FailOnApplicableOnlyEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.FixApplicableOnly {
+		if s.FixApplicableOnly, err = getBoolEnv(FixApplicableOnlyEnv, false); err != nil {
+			return
+		}
+	}
+	if s.FixMinSeverity == "" {
+		s.FixMinSeverity = getTrimmedEnv(FixMinSeverityEnv)
+	}
+	if s.FixMinSeverity != "" {
+		var severity severityutils.Severity
+		if severity, err = severityutils.ParseSeverity(s.FixMinSeverity, false); err != nil {
+			return
+		}
+		s.FixMinSeverity = severity.String()
+	}
+	if !s.SkipDevDependencies {
+		if s.SkipDevDependencies, err = getBoolEnv(SkipDevDependenciesEnv, false); err != nil {
+			return
+		}
+	}
+	if err = s.Jas.setDefaultsIfNeeded(); err != nil {
+		return
+	}
+	if len(s.ExcludePatterns) == 0 {
+		if s.ExcludePatterns, _ = readArrayParamFromEnv(ExcludePatternsEnv, ";"); len(s.ExcludePatterns) == 0 {
+			s.ExcludePatterns = DefaultExcludePatterns
+		}
+	}
+	// The applicability of the CVEs is determined by the contextual analysis, which is one of the advanced security scanners
+	if s.DisableJas && (s.FailOnApplicableOnly || s.FixApplicableOnly) {
+		return errors.New("the failOnApplicableOnly and fixApplicableOnly options require the contextual analysis, which can't run while the advanced security scanners are disabled")
+	}
+	if !*s.Jas.EnableApplicability && (s.FailOnApplicableOnly || s.FixApplicableOnly) {
+		return errors.New("the failOnApplicableOnly and fixApplicableOnly options require the contextual analysis, which can't run while the applicability scanner is disabled")
+	}
 	if !s.AddPrCommentOnSuccess {
 		if s.AddPrCommentOnSuccess, err = getBoolEnv(AddPrCommentOnSuccessEnv, true); err != nil {
 			return
@@ -245,6 +677,14 @@ func (s *Scan) setDefaultsIfNeeded() (err error) {
 		}
 		s.FailOnSecurityIssues = &failOnSecurityIssues
 	}
+	if s.FailAfterDate == "" {
+		s.FailAfterDate = getTrimmedEnv(FailAfterDateEnv)
+	}
+	if s.FailAfterDate != "" {
+		if _, err = time.Parse(failAfterDateLayout, s.FailAfterDate); err != nil {
+			return fmt.Errorf("the fail after date '%s' is invalid. Expected a date in the format: YYYY-MM-DD", s.FailAfterDate)
+		}
+	}
 	if s.MinSeverity == "" {
 		if err = readParamFromEnv(MinSeverityEnv, &s.MinSeverity); err != nil && !e.IsMissingEnvErr(err) {
 			return
@@ -270,15 +710,160 @@ func (s *Scan) setDefaultsIfNeeded() (err error) {
 			return
 		}
 	}
+	if len(s.TargetCves) == 0 {
+		if s.TargetCves, err = readArrayParamFromEnv(TargetCvesEnv, ","); err != nil && !e.IsMissingEnvErr(err) {
+			return
+		}
+		if err = s.setFixCvesFromEnv(); err != nil {
+			return
+		}
+	}
+	if len(s.InternalNamespaces) == 0 {
+		if s.InternalNamespaces, err = readArrayParamFromEnv(InternalNamespacesEnv, ","); err != nil && !e.IsMissingEnvErr(err) {
+			return
+		}
+	}
+	if len(s.SuppressionApprovers) == 0 {
+		if s.SuppressionApprovers, err = readArrayParamFromEnv(SuppressionApproversEnv, ","); err != nil && !e.IsMissingEnvErr(err) {
+			return
+		}
+	}
+	if s.TargetCves, err = normalizeTargetCves(s.TargetCves); err != nil {
+		return
+	}
+	if !s.TargetCvesFixOnly {
+		if s.TargetCvesFixOnly, err = getBoolEnv(TargetCvesFixOnlyEnv, false); err != nil {
+			return
+		}
+	}
+	if len(s.PackageHandlerPlugins) == 0 {
+		if s.PackageHandlerPlugins, err = readPackageHandlerPluginsFromEnv(); err != nil {
+			return
+		}
+	}
+	for tech, pluginPath := range s.PackageHandlerPlugins {
+		if strings.TrimSpace(tech) == "" || strings.TrimSpace(pluginPath) == "" {
+			return fmt.Errorf("the package handler plugin '%s=%s' is invalid. Expected a technology and the path of its plugin", tech, pluginPath)
+		}
+		// The fixes run in the working directories of the projects, so the plugin paths are resolved from the current working directory
+		if s.PackageHandlerPlugins[tech], err = filepath.Abs(pluginPath); err != nil {
+			return
+		}
+	}
+	if s.ReportPath == "" {
+		s.ReportPath = getTrimmedEnv(ReportPathEnv)
+	}
+	if s.ReportPath != "" {
+		// The scan runs in a temporary directory, so the report path is resolved from the current working directory
+		if s.ReportPath, err = filepath.Abs(s.ReportPath); err != nil {
+			return
+		}
+	}
+	if s.SbomPath == "" {
+		s.SbomPath = getTrimmedEnv(SbomPathEnv)
+	}
+	if s.SbomPath != "" {
+		if s.SbomPath, err = filepath.Abs(s.SbomPath); err != nil {
+			return
+		}
+	}
 	if !s.AllowPartialResults {
 		if s.AllowPartialResults, err = getBoolEnv(AllowPartialResultsEnv, false); err != nil {
 			return
 		}
 	}
+	if !s.ExploitabilityEnrichment {
+		if s.ExploitabilityEnrichment, err = getBoolEnv(ExploitabilityEnrichmentEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ResearchEnrichment {
+		if s.ResearchEnrichment, err = getBoolEnv(ResearchEnrichmentEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.PrioritizeExploitedFixes {
+		if s.PrioritizeExploitedFixes, err = getBoolEnv(PrioritizeExploitedFixesEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ValidateSecrets {
+		if s.ValidateSecrets, err = getBoolEnv(ValidateSecretsEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.BlockOnSecrets {
+		if s.BlockOnSecrets, err = getBoolEnv(BlockOnSecretsEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ScanDockerfiles {
+		if s.ScanDockerfiles, err = getBoolEnv(ScanDockerfilesEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ScanGitHubActions {
+		if s.ScanGitHubActions, err = getBoolEnv(ScanGitHubActionsEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ScanBazel {
+		if s.ScanBazel, err = getBoolEnv(ScanBazelEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ScanComposer {
+		if s.ScanComposer, err = getBoolEnv(ScanComposerEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ScanSystemPackages {
+		if s.ScanSystemPackages, err = getBoolEnv(ScanSystemPackagesEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.ScanCuration {
+		if s.ScanCuration, err = getBoolEnv(ScanCurationEnv, false); err != nil {
+			return
+		}
+	}
+	if !s.FailOnCurationBlocked {
+		if s.FailOnCurationBlocked, err = getBoolEnv(FailOnCurationBlockedEnv, false); err != nil {
+			return
+		}
+	}
+	if len(s.ExternalSarifPaths) == 0 {
+		s.ExternalSarifPaths, _ = readArrayParamFromEnv(ExternalSarifPathsEnv, ";")
+	}
+	for i := range s.ExternalSarifPaths {
+		// The scan runs in a temporary directory, so the paths are resolved from the current working directory
+		if s.ExternalSarifPaths[i], err = filepath.Abs(s.ExternalSarifPaths[i]); err != nil {
+			return
+		}
+	}
+	// Prioritizing the fixes of the known exploited vulnerabilities requires their exploitability data
+	s.ExploitabilityEnrichment = s.ExploitabilityEnrichment || s.PrioritizeExploitedFixes
+	if s.MaxConcurrentRepos == 0 {
+		if s.MaxConcurrentRepos, err = getIntEnv(MaxConcurrentReposEnv, 1); err != nil {
+			return
+		}
+		if s.MaxConcurrentRepos < 1 {
+			return fmt.Errorf("the value of the %s environment is expected to be a positive number. The value received however is %d", MaxConcurrentReposEnv, s.MaxConcurrentRepos)
+		}
+	}
+	var projects []Project
 	for i := range s.Projects {
 		if err = s.Projects[i].setDefaultsIfNeeded(); err != nil {
 			return
 		}
+		var splitProjects []Project
+		if splitProjects, err = s.Projects[i].splitByInstallCommands(); err != nil {
+			return
+		}
+		projects = append(projects, splitProjects...)
+	}
+	if s.Projects, err = filterProjectsWorkingDirs(projects); err != nil {
+		return
 	}
 	err = s.SetEmailDetails()
 	return
@@ -290,6 +875,41 @@ type JFrogPlatform struct {
 	Watches                []string `yaml:"watches,omitempty"`
 	IncludeVulnerabilities bool     `yaml:"includeVulnerabilities,omitempty"`
 	JFrogProjectKey        string   `yaml:"jfrogProjectKey,omitempty"`
+	// Fail the scan if the configured watches or JFrog project don't exist, instead of logging a warning
+	FailOnMissingWatchesOrProject bool `yaml:"failOnMissingWatchesOrProject,omitempty"`
+	// The JFrog platform instance of the repository, identified by a JFrog CLI server ID or by a URL.
+	// If not set, the instance configured by the environment variables is used.
+	ServerId string `yaml:"serverId,omitempty"`
+	Url      string `yaml:"url,omitempty"`
+	// The names of the environment variables that hold the credentials of the instance set by the URL
+	UserEnv        string `yaml:"userEnv,omitempty"`
+	PasswordEnv    string `yaml:"passwordEnv,omitempty"`
+	AccessTokenEnv string `yaml:"accessTokenEnv,omitempty"`
+}
+
+// Returns the server details of the JFrog platform instance configured for the repository, or nil if none is configured
+func (jp *JFrogPlatform) getServerDetails() (*coreconfig.ServerDetails, error) {
+	if jp.ServerId != "" {
+		server, err := coreconfig.GetSpecificConfig(jp.ServerId, false, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the details of the '%s' JFrog CLI server: %s", jp.ServerId, err.Error())
+		}
+		return server, nil
+	}
+	if jp.Url == "" {
+		return nil, nil
+	}
+	server := &coreconfig.ServerDetails{}
+	setPlatformUrls(server, strings.TrimSuffix(jp.Url, "/"))
+	if user, password := getTrimmedEnv(jp.UserEnv), getTrimmedEnv(jp.PasswordEnv); user != "" && password != "" {
+		server.User = user
+		server.Password = password
+	} else if accessToken := getTrimmedEnv(jp.AccessTokenEnv); accessToken != "" {
+		server.AccessToken = accessToken
+	} else {
+		return nil, fmt.Errorf("the credentials of the JFrog platform %s are missing. Set userEnv and passwordEnv, or accessTokenEnv, to the names of environment variables that hold them", jp.Url)
+	}
+	return server, nil
 }
 
 func (jp *JFrogPlatform) setDefaultsIfNeeded() (err error) {
@@ -311,6 +931,11 @@ func (jp *JFrogPlatform) setDefaultsIfNeeded() (err error) {
 			return
 		}
 	}
+	if !jp.FailOnMissingWatchesOrProject {
+		if jp.FailOnMissingWatchesOrProject, err = getBoolEnv(FailOnMissingWatchesOrProjectEnv, false); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -327,17 +952,100 @@ type Git struct {
 	PullRequestCommentTitle       string   `yaml:"pullRequestCommentTitle,omitempty"`
 	PullRequestSecretComments     bool     `yaml:"pullRequestSecretComments,omitempty"`
 	AvoidExtraMessages            bool     `yaml:"avoidExtraMessages,omitempty"`
+	ShowRuntimeDetails            bool     `yaml:"showRuntimeDetails,omitempty"`
+	ShowUnsupportedFixes          bool     `yaml:"showUnsupportedFixes,omitempty"`
+	ShowIgnoredFindings           bool     `yaml:"showIgnoredFindings,omitempty"`
+	ShowSections                  []string `yaml:"showSections,omitempty"`
+	HideResearchDetails           bool     `yaml:"hideResearchDetails,omitempty"`
+	MaxRowsPerTable               int      `yaml:"maxRowsPerTable,omitempty"`
+	MessagesFile                  string   `yaml:"messagesFile,omitempty"`
+	CodeInsights                  bool     `yaml:"codeInsights,omitempty"`
 	EmailAuthor                   string   `yaml:"emailAuthor,omitempty"`
 	AggregateFixes                bool     `yaml:"aggregateFixes,omitempty"`
-	PullRequestDetails            vcsclient.PullRequestInfo
-	RepositoryCloneUrl            string
-	UseLocalRepository            bool
+	SeparateFixesMinSeverity      string   `yaml:"separateFixesMinSeverity,omitempty"`
+	TrackUnfixableVulnerabilities bool     `yaml:"trackUnfixableVulnerabilities,omitempty"`
+	AzureWorkItemType             string   `yaml:"azureWorkItemType,omitempty"`
+	BranchesSummaryIssue          bool     `yaml:"branchesSummaryIssue,omitempty"`
+	RepositoriesSummaryRepo       string   `yaml:"repositoriesSummaryRepo,omitempty"`
+	BranchBaselinesFile           string   `yaml:"branchBaselinesFile,omitempty"`
+	PullRequestsStateFile         string   `yaml:"pullRequestsStateFile,omitempty"`
+	ScanHistoryFile               string   `yaml:"scanHistoryFile,omitempty"`
+	ScanHistoryArtifactoryPath    string   `yaml:"scanHistoryArtifactoryPath,omitempty"`
+	RateLimitThreshold            int      `yaml:"rateLimitThreshold,omitempty"`
+	// The longest pause until the rate limit of the Git provider resets
+	RateLimitMaxWait time.Duration `yaml:"-"`
+	// The wording of the comments, read from the messages file
+	Messages outputwriter.Messages `yaml:"-"`
+	// The authors of the dependency bump pull requests of other bots, such as Dependabot and Renovate.
+	// The packages that their open pull requests bump aren't fixed by Frogbot.
+	BotPullRequestAuthors []string `yaml:"botPullRequestAuthors,omitempty"`
+	// The limits of the open Frogbot fix pull requests of the repository, and of the fix pull requests that each run opens. Zero is unlimited.
+	// The fixes beyond the limits are left for the next runs.
+	MaxOpenFixPullRequests int `yaml:"maxOpenFixPullRequests,omitempty"`
+	MaxNewFixPullRequests  int `yaml:"maxNewFixPullRequests,omitempty"`
+	// The limits of the fix pull requests of each severity that each run opens, by the severities. The severities without a limit are unlimited.
+	// An aggregated pull request counts as a pull request of the highest severity of its fixes.
+	FixMaxPullRequestsPerSeverity map[string]int `yaml:"fixMaxPullRequestsPerSeverity,omitempty"`
+	// The weekly time windows in UTC, such as 'Mon-Fri 09:00-17:00', in which new fix pull requests are opened. Empty allows any time.
+	FixPullRequestsWindows []string `yaml:"fixPullRequestsWindows,omitempty"`
+	// The maintenance branches that the fixes of the scanned branches are backported to, each in a pull request of its own
+	BackportBranches []string `yaml:"backportBranches,omitempty"`
+	// The labels, the assignees and the reviewers of the new fix pull requests. The team reviewers are the teams of GitHub and Azure Repos.
+	PullRequestLabels        []string `yaml:"prLabels,omitempty"`
+	PullRequestAssignees     []string `yaml:"prAssignees,omitempty"`
+	PullRequestReviewers     []string `yaml:"prReviewers,omitempty"`
+	PullRequestTeamReviewers []string `yaml:"teamReviewers,omitempty"`
+	// The number of commits that the base branch is ahead of an aggregated fix pull request, from which the pull request is refreshed
+	// even if it has no merge conflicts. 0 refreshes only the pull requests with merge conflicts.
+	RefreshBehindBaseCommits int `yaml:"refreshBehindBaseCommits,omitempty"`
+	// Selects the repositories of the owner by patterns of their names, instead of the repository name. Used by the commands that scan multiple repositories.
+	Repositories *RepositoriesSelector `yaml:"repositories,omitempty"`
+	// Pin the actions of the GitHub Actions workflows to commit SHAs in the fix pull requests, when the scan of the workflows is enabled
+	PinGitHubActions bool `yaml:"pinGitHubActions,omitempty"`
+	// Estimate the risk of breaking the project by the upgrades of the fix pull requests, and add it to their titles and descriptions
+	AnalyzeUpgradeRisk bool `yaml:"analyzeUpgradeRisk,omitempty"`
+	// Link the release notes of the npm upgrades of the fix pull requests to their diffs on npmdiff.dev, for the versions published in the public npm registry
+	NpmDiffLinks bool `yaml:"npmDiffLinks,omitempty"`
+	// Delete the Frogbot fix branches whose pull requests were merged or closed before the retention period in days
+	CleanupMergedBranches bool `yaml:"cleanupMergedBranches,omitempty"`
+	CleanupRetentionDays  int  `yaml:"cleanupRetentionDays,omitempty"`
+	// Merge the fix pull requests that only upgrade patch versions once their checks passed, with the merge, squash or rebase strategy
+	AutoMerge         bool   `yaml:"autoMerge,omitempty"`
+	AutoMergeStrategy string `yaml:"autoMergeStrategy,omitempty"`
+	// Mention the owners of the paths of the CODEOWNERS file in the summary comment, when the pull request adds Critical or High findings to their paths
+	MentionCodeOwners bool `yaml:"mentionCodeOwners,omitempty"`
+	// Skip the scans of the pull requests from forks of the repository
+	DisallowForkPullRequests bool `yaml:"disallowForkPullRequests,omitempty"`
+	// The security champions to mention for the paths of the repository. They take precedence over the owners of the CODEOWNERS file.
+	SecurityChampions []SecurityChampions `yaml:"securityChampions,omitempty"`
+	DownloadRetries   int                 `yaml:"downloadRetries,omitempty"`
+	Submodules        bool                `yaml:"submodules,omitempty"`
+	// Download the scanned branches by a shallow clone instead of the archive of the API of the Git provider, which is slow for large repositories and counts against its rate limit
+	ShallowClone       bool `yaml:"shallowClone,omitempty"`
+	PullRequestDetails vcsclient.PullRequestInfo
+	RepositoryCloneUrl string
+	UseLocalRepository bool
+	// The local directory that is scanned instead of the downloaded source branch, as in the local scan
+	LocalSourceDir string
+	Campaign       *Campaign
+	// Signs the commits of the fix pull requests, nil if the commits aren't signed
+	CommitSigner *CommitSigner
+}
+
+// SecurityChampions are mentioned when a pull request adds Critical or High findings to the paths of a CODEOWNERS pattern
+type SecurityChampions struct {
+	Path   string   `yaml:"path,omitempty"`
+	Owners []string `yaml:"owners,omitempty"`
 }
 
 func (g *Git) GetRepositoryHttpsCloneUrl(gitClient vcsclient.VcsClient) (string, error) {
 	if g.RepositoryCloneUrl != "" {
 		return g.RepositoryCloneUrl, nil
 	}
+	// The local scan doesn't connect to the Git provider
+	if gitClient == nil {
+		return "", nil
+	}
 	// If the repository clone URL is not cached, we fetch it from the VCS provider
 	repositoryInfo, err := gitClient.GetRepositoryInfo(context.Background(), g.RepoOwner, g.RepoName)
 	if err != nil {
@@ -363,16 +1071,104 @@ func (g *Git) setDefaultsIfNeeded(gitParamsFromEnv *Git, commandName string) (er
 			g.EmailAuthor = frogbotAuthorEmail
 		}
 	}
+	if !g.ShowRuntimeDetails {
+		if g.ShowRuntimeDetails, err = getBoolEnv(ShowRuntimeDetailsEnv, false); err != nil {
+			return
+		}
+	}
+	if g.DownloadRetries == 0 {
+		if g.DownloadRetries, err = getIntEnv(GitDownloadRetriesEnv, 0); err != nil {
+			return
+		}
+	}
+	if g.DownloadRetries < 0 {
+		return fmt.Errorf("the number of repository download retries must not be negative, provided: %d", g.DownloadRetries)
+	}
+	if !g.Submodules {
+		if g.Submodules, err = getBoolEnv(GitSubmodulesEnv, false); err != nil {
+			return
+		}
+	}
+	if !g.ShallowClone {
+		if g.ShallowClone, err = getBoolEnv(GitShallowCloneEnv, false); err != nil {
+			return
+		}
+	}
+	if !g.ShowUnsupportedFixes {
+		if g.ShowUnsupportedFixes, err = getBoolEnv(ShowUnsupportedFixesEnv, false); err != nil {
+			return
+		}
+	}
+	if err = g.setReportingDefaultsIfNeeded(); err != nil {
+		return
+	}
 	if commandName == ScanPullRequest {
 		if err = g.extractScanPullRequestEnvParams(gitParamsFromEnv); err != nil {
 			return
 		}
 	}
-	if commandName == ScanRepository || commandName == ScanMultipleRepositories {
+	if commandName == ScanRepository || commandName == ScanMultipleRepositories || commandName == FixCampaign {
 		if err = g.extractScanRepositoryEnvParams(gitParamsFromEnv); err != nil {
 			return
 		}
 	}
+	if commandName == ScanAllPullRequests {
+		if err = g.extractScanAllPullRequestsEnvParams(); err != nil {
+			return
+		}
+	}
+	if commandName == GenerateBaseline && len(g.Branches) == 0 {
+		// The baseline is generated from the base branch
+		g.Branches = gitParamsFromEnv.Branches
+	}
+	if commandName == FixCampaign {
+		g.Campaign, err = NewCampaign(getTrimmedEnv(CampaignTargetEnv), getTrimmedEnv(CampaignIdEnv))
+	}
+	return
+}
+
+// Reads the options that customize the content of the comments
+func (g *Git) setReportingDefaultsIfNeeded() (err error) {
+	if len(g.ShowSections) == 0 {
+		if showSectionsEnv := getTrimmedEnv(ShowSectionsEnv); showSectionsEnv != "" {
+			g.ShowSections = strings.Split(showSectionsEnv, ",")
+		}
+	}
+	for i, section := range g.ShowSections {
+		g.ShowSections[i] = strings.ToLower(strings.TrimSpace(section))
+		if !slices.Contains(outputwriter.CommentSections, g.ShowSections[i]) {
+			return fmt.Errorf("the comment section %q is unknown, the supported sections are: %s", section, strings.Join(outputwriter.CommentSections, ", "))
+		}
+	}
+	if !g.HideResearchDetails {
+		if g.HideResearchDetails, err = getBoolEnv(HideResearchDetailsEnv, false); err != nil {
+			return
+		}
+	}
+	if g.MaxRowsPerTable == 0 {
+		if g.MaxRowsPerTable, err = getIntEnv(MaxRowsPerTableEnv, 0); err != nil {
+			return
+		}
+	}
+	if g.MaxRowsPerTable < 0 {
+		return fmt.Errorf("the maximal number of rows per table must not be negative, provided: %d", g.MaxRowsPerTable)
+	}
+	if g.MessagesFile == "" {
+		g.MessagesFile = getTrimmedEnv(MessagesFileEnv)
+	}
+	if g.MessagesFile != "" {
+		if g.Messages, err = outputwriter.LoadMessages(g.MessagesFile); err != nil {
+			return
+		}
+	}
+	if !g.CodeInsights {
+		if g.CodeInsights, err = getBoolEnv(CodeInsightsEnv, false); err != nil {
+			return
+		}
+	}
+	if g.CodeInsights && g.GitProvider != vcsutils.BitbucketServer {
+		return fmt.Errorf("the Code Insights reports are only supported for %s", vcsutils.BitbucketServer.String())
+	}
 	return
 }
 
@@ -389,15 +1185,64 @@ func (g *Git) extractScanPullRequestEnvParams(gitParamsFromEnv *Git) (err error)
 			return
 		}
 	}
+	if !g.ShowIgnoredFindings {
+		if g.ShowIgnoredFindings, err = getBoolEnv(ShowIgnoredFindingsEnv, false); err != nil {
+			return
+		}
+	}
 	if !g.UseMostCommonAncestorAsTarget {
 		if g.UseMostCommonAncestorAsTarget, err = getBoolEnv(UseMostCommonAncestorAsTargetEnv, true); err != nil {
 			return
 		}
 	}
+	if !g.MentionCodeOwners {
+		if g.MentionCodeOwners, err = getBoolEnv(MentionCodeOwnersEnv, false); err != nil {
+			return
+		}
+	}
+	if !g.DisallowForkPullRequests {
+		if g.DisallowForkPullRequests, err = getBoolEnv(DisallowForkPullRequestsEnv, false); err != nil {
+			return
+		}
+	}
+	for _, champions := range g.SecurityChampions {
+		if champions.Path == "" || len(champions.Owners) == 0 {
+			return fmt.Errorf("each of the security champions must have a path and owners, provided: %+v", champions)
+		}
+	}
 	g.AvoidExtraMessages, err = getBoolEnv(AvoidExtraMessages, false)
 	return
 }
 
+func (g *Git) extractScanAllPullRequestsEnvParams() (err error) {
+	if g.PullRequestsStateFile == "" {
+		g.PullRequestsStateFile = getTrimmedEnv(PullRequestsStateFileEnv)
+	}
+	if g.PullRequestsStateFile != "" {
+		// The scan runs in a temporary directory, so the state file path is resolved from the current working directory
+		if g.PullRequestsStateFile, err = filepath.Abs(g.PullRequestsStateFile); err != nil {
+			return
+		}
+	}
+	if g.RateLimitThreshold == 0 {
+		if g.RateLimitThreshold, err = getIntEnv(RateLimitThresholdEnv, defaultRateLimitThreshold); err != nil {
+			return
+		}
+	}
+	if g.RateLimitThreshold < 0 {
+		return fmt.Errorf("the rate limit threshold must not be negative, provided: %d", g.RateLimitThreshold)
+	}
+	// The longest pause isn't part of the config file, so it's read from the environment also when the threshold is configured there.
+	// Zero stops without pausing, so the default is used only if the environment variable is missing.
+	g.RateLimitMaxWait = defaultRateLimitMaxWait
+	if getTrimmedEnv(RateLimitMaxWaitEnv) != "" {
+		if g.RateLimitMaxWait, err = getDurationInSecondsEnv(RateLimitMaxWaitEnv); err != nil {
+			return
+		}
+	}
+	return
+}
+
 func (g *Git) extractScanRepositoryEnvParams(gitParamsFromEnv *Git) (err error) {
 	// Continue to extract ScanRepository related env params
 	noBranchesProvidedViaConfig := len(g.Branches) == 0
@@ -426,11 +1271,187 @@ func (g *Git) extractScanRepositoryEnvParams(gitParamsFromEnv *Git) (err error)
 			return
 		}
 	}
+	if g.SeparateFixesMinSeverity == "" {
+		g.SeparateFixesMinSeverity = getTrimmedEnv(GitSeparateFixesMinSeverityEnv)
+	}
+	if g.SeparateFixesMinSeverity != "" {
+		var severity severityutils.Severity
+		if severity, err = severityutils.ParseSeverity(g.SeparateFixesMinSeverity, false); err != nil {
+			return
+		}
+		g.SeparateFixesMinSeverity = severity.String()
+	}
+	if !g.TrackUnfixableVulnerabilities {
+		if g.TrackUnfixableVulnerabilities, err = getBoolEnv(TrackUnfixableVulnerabilitiesEnv, false); err != nil {
+			return
+		}
+	}
+	if g.TrackUnfixableVulnerabilities && g.GitProvider == vcsutils.BitbucketServer {
+		return fmt.Errorf("tracking the vulnerabilities without a fixed version isn't supported for %s", g.GitProvider.String())
+	}
+	if g.AzureWorkItemType == "" {
+		g.AzureWorkItemType = getTrimmedEnv(AzureWorkItemTypeEnv)
+	}
+	if g.CommitSigner, err = NewCommitSigner(getTrimmedEnv(GitSigningKeyEnv), os.Getenv(GitSigningKeyPassphraseEnv), g.EmailAuthor); err != nil {
+		return
+	}
+	if !g.BranchesSummaryIssue {
+		if g.BranchesSummaryIssue, err = getBoolEnv(BranchesSummaryIssueEnv, false); err != nil {
+			return
+		}
+	}
+	if g.BranchesSummaryIssue && g.GitProvider == vcsutils.BitbucketServer {
+		return fmt.Errorf("posting the branches summary to an issue isn't supported for %s", g.GitProvider.String())
+	}
+	if g.RepositoriesSummaryRepo == "" {
+		g.RepositoriesSummaryRepo = getTrimmedEnv(RepositoriesSummaryRepoEnv)
+	}
+	if g.BranchBaselinesFile == "" {
+		g.BranchBaselinesFile = getTrimmedEnv(BranchBaselinesFileEnv)
+	}
+	if g.BranchBaselinesFile != "" {
+		// The scan runs in a temporary directory, so the baselines file path is resolved from the current working directory
+		if g.BranchBaselinesFile, err = filepath.Abs(g.BranchBaselinesFile); err != nil {
+			return
+		}
+	}
+	if g.ScanHistoryFile == "" {
+		g.ScanHistoryFile = getTrimmedEnv(ScanHistoryFileEnv)
+	}
+	if g.ScanHistoryFile != "" {
+		// The scan runs in a temporary directory, so the history file path is resolved from the current working directory
+		if g.ScanHistoryFile, err = filepath.Abs(g.ScanHistoryFile); err != nil {
+			return
+		}
+	}
+	if g.ScanHistoryArtifactoryPath == "" {
+		g.ScanHistoryArtifactoryPath = getTrimmedEnv(ScanHistoryArtifactoryPathEnv)
+	}
+	if g.ScanHistoryFile != "" && g.ScanHistoryArtifactoryPath != "" {
+		return fmt.Errorf("the scan history can be kept either in a file or in Artifactory, but both %s and %s are set", ScanHistoryFileEnv, ScanHistoryArtifactoryPathEnv)
+	}
+	if len(g.BotPullRequestAuthors) == 0 {
+		e := &ErrMissingEnv{}
+		if g.BotPullRequestAuthors, err = readArrayParamFromEnv(BotPullRequestAuthorsEnv, ","); err != nil {
+			if !e.IsMissingEnvErr(err) {
+				return
+			}
+			err = nil
+		}
+	}
+	if g.MaxOpenFixPullRequests == 0 {
+		if g.MaxOpenFixPullRequests, err = getIntEnv(MaxOpenFixPullRequestsEnv, 0); err != nil {
+			return
+		}
+	}
+	if g.MaxNewFixPullRequests == 0 {
+		if g.MaxNewFixPullRequests, err = getIntEnv(MaxNewFixPullRequestsEnv, 0); err != nil {
+			return
+		}
+	}
+	if g.MaxOpenFixPullRequests < 0 || g.MaxNewFixPullRequests < 0 {
+		return fmt.Errorf("the limits of the fix pull requests must not be negative, provided: %d open and %d new fix pull requests", g.MaxOpenFixPullRequests, g.MaxNewFixPullRequests)
+	}
+	if len(g.FixMaxPullRequestsPerSeverity) == 0 {
+		if g.FixMaxPullRequestsPerSeverity, err = readFixMaxPullRequestsPerSeverityFromEnv(); err != nil {
+			return
+		}
+	}
+	if g.FixMaxPullRequestsPerSeverity, err = normalizeFixMaxPullRequestsPerSeverity(g.FixMaxPullRequestsPerSeverity); err != nil {
+		return
+	}
+	if len(g.FixPullRequestsWindows) == 0 {
+		// The days of a window are separated by commas, so the windows are separated by semicolons
+		if fixPullRequestsWindows := getTrimmedEnv(FixPullRequestsWindowsEnv); fixPullRequestsWindows != "" {
+			for _, window := range strings.Split(fixPullRequestsWindows, ";") {
+				g.FixPullRequestsWindows = append(g.FixPullRequestsWindows, strings.TrimSpace(window))
+			}
+		}
+	}
+	if _, err = ParseFixWindows(g.FixPullRequestsWindows); err != nil {
+		return
+	}
+	if g.RefreshBehindBaseCommits == 0 {
+		if g.RefreshBehindBaseCommits, err = getIntEnv(RefreshBehindBaseCommitsEnv, 0); err != nil {
+			return
+		}
+	}
+	if g.RefreshBehindBaseCommits < 0 {
+		return fmt.Errorf("the number of commits behind the base branch that refreshes the fix pull requests must not be negative, provided: %d", g.RefreshBehindBaseCommits)
+	}
+	if len(g.BackportBranches) == 0 {
+		e := &ErrMissingEnv{}
+		if g.BackportBranches, err = readArrayParamFromEnv(BackportBranchesEnv, ","); err != nil {
+			if !e.IsMissingEnvErr(err) {
+				return
+			}
+			err = nil
+		}
+	}
+	for env, routing := range map[string]*[]string{
+		PullRequestLabelsEnv:        &g.PullRequestLabels,
+		PullRequestAssigneesEnv:     &g.PullRequestAssignees,
+		PullRequestReviewersEnv:     &g.PullRequestReviewers,
+		PullRequestTeamReviewersEnv: &g.PullRequestTeamReviewers,
+	} {
+		if len(*routing) > 0 {
+			continue
+		}
+		e := &ErrMissingEnv{}
+		if *routing, err = readArrayParamFromEnv(env, ","); err != nil {
+			if !e.IsMissingEnvErr(err) {
+				return
+			}
+			err = nil
+		}
+	}
+	if !g.PinGitHubActions {
+		if g.PinGitHubActions, err = getBoolEnv(PinGitHubActionsEnv, false); err != nil {
+			return
+		}
+	}
+	if !g.AnalyzeUpgradeRisk {
+		if g.AnalyzeUpgradeRisk, err = getBoolEnv(AnalyzeUpgradeRiskEnv, false); err != nil {
+			return
+		}
+	}
+	if !g.NpmDiffLinks {
+		if g.NpmDiffLinks, err = getBoolEnv(NpmDiffLinksEnv, false); err != nil {
+			return
+		}
+	}
 	if !g.UseLocalRepository {
 		if g.UseLocalRepository, err = getBoolEnv(GitUseLocalRepositoryEnv, false); err != nil {
 			return
 		}
 	}
+	if !g.CleanupMergedBranches {
+		if g.CleanupMergedBranches, err = getBoolEnv(CleanupMergedBranchesEnv, false); err != nil {
+			return
+		}
+	}
+	if g.CleanupRetentionDays == 0 {
+		if g.CleanupRetentionDays, err = getIntEnv(CleanupRetentionDaysEnv, defaultCleanupRetentionDays); err != nil {
+			return
+		}
+	}
+	if g.CleanupRetentionDays < 0 {
+		return fmt.Errorf("the retention period of the merged branches must not be negative, provided: %d days", g.CleanupRetentionDays)
+	}
+	if !g.AutoMerge {
+		if g.AutoMerge, err = getBoolEnv(AutoMergeEnv, false); err != nil {
+			return
+		}
+	}
+	if g.AutoMergeStrategy == "" {
+		g.AutoMergeStrategy = getTrimmedEnv(AutoMergeStrategyEnv)
+	}
+	if g.AutoMergeStrategy = strings.ToLower(g.AutoMergeStrategy); g.AutoMergeStrategy == "" {
+		g.AutoMergeStrategy = automerge.MergeStrategy
+	}
+	if !slices.Contains(automerge.Strategies, g.AutoMergeStrategy) {
+		return fmt.Errorf("the auto-merge strategy %q is unknown, the supported strategies are: %s", g.AutoMergeStrategy, strings.Join(automerge.Strategies, ", "))
+	}
 	return
 }
 
@@ -445,16 +1466,20 @@ func validateHashPlaceHolder(template string) error {
 }
 
 func GetFrogbotDetails(commandName string) (frogbotDetails *FrogbotDetails, err error) {
-	// Get server and git details
-	jfrogServer, err := extractJFrogCredentialsFromEnvs()
+	// The proxy is configured before any request is sent, including the exchange of the OIDC token for a JFrog access token
+	restoreJfrogHome, err := configureProxy()
+	defer func() {
+		err = errors.Join(err, restoreJfrogHome())
+	}()
 	if err != nil {
 		return
 	}
-	xrayVersion, xscVersion, err := xsc.GetJfrogServicesVersion(jfrogServer)
+
+	// Get server and git details
+	jfrogServer, err := extractJFrogCredentialsFromEnvs()
 	if err != nil {
 		return
 	}
-
 	gitParamsFromEnv, err := extractGitParamsFromEnvs(commandName)
 	if err != nil {
 		return
@@ -464,18 +1489,31 @@ func GetFrogbotDetails(commandName string) (frogbotDetails *FrogbotDetails, err
 		err = errors.Join(err, SanitizeEnv())
 	}()
 
-	// Build a version control client for REST API requests
-	client, err := vcsclient.
-		NewClientBuilder(gitParamsFromEnv.GitProvider).
-		ApiEndpoint(strings.TrimSuffix(gitParamsFromEnv.APIEndpoint, "/")).
-		Token(gitParamsFromEnv.Token).
-		Project(gitParamsFromEnv.Project).
-		Logger(log.GetLogger()).
-		Username(gitParamsFromEnv.Username).
-		Build()
+	if err = checkProxyConnectivity(jfrogServer.Url, gitParamsFromEnv.APIEndpoint); err != nil {
+		return
+	}
+
+	xrayVersion, xscVersion, err := xsc.GetJfrogServicesVersion(jfrogServer)
+	if err != nil {
+		return
+	}
+	// The features that the versions don't support are skipped during the run, except for the dependencies scan that all the commands require
+	capabilities := DetectCapabilities(xrayVersion, xscVersion)
+	capabilities.LogMatrix()
+	if !capabilities.IsSupported(ScaFeature) {
+		return nil, fmt.Errorf("the version of JFrog Xray isn't supported by Frogbot: %s", capabilities.UnsupportedReason(ScaFeature))
+	}
+
+	if err = configureVcsHttpTransport(); err != nil {
+		return
+	}
+	startRunMetrics(commandName)
+
+	client, err := newVcsClient(gitParamsFromEnv)
 	if err != nil {
 		return
 	}
+	client = newMetricsVcsClient(client)
 
 	configAggregator, err := getConfigAggregator(xrayVersion, xscVersion, client, gitParamsFromEnv, jfrogServer, commandName)
 	if err != nil {
@@ -494,10 +1532,92 @@ func GetFrogbotDetails(commandName string) (frogbotDetails *FrogbotDetails, err
 		configAggregator[i].Git.RepositoryCloneUrl = repoCloneUrl
 	}
 
-	frogbotDetails = &FrogbotDetails{XrayVersion: xrayVersion, XscVersion: xscVersion, Repositories: configAggregator, GitClient: client, ServerDetails: jfrogServer, ReleasesRepo: os.Getenv(jfrogReleasesRepoEnv)}
+	frogbotDetails = &FrogbotDetails{XrayVersion: xrayVersion, XscVersion: xscVersion, Repositories: configAggregator, GitClient: client, ServerDetails: jfrogServer, ReleasesRepo: os.Getenv(JFrogReleasesRepoEnv)}
 	return
 }
 
+// GetLocalScanRepository returns the params of the local scan of the current directory, from the environment variables and the frogbot-config.yml file of the current directory, if it exists.
+// Only the JFrog platform details are required, since the local scan doesn't connect to the Git provider. The results are written as plain text for the terminal.
+func GetLocalScanRepository() (repository *Repository, err error) {
+	restoreJfrogHome, err := configureProxy()
+	defer func() {
+		err = errors.Join(err, restoreJfrogHome())
+	}()
+	if err != nil {
+		return
+	}
+	jfrogServer, err := extractJFrogCredentialsFromEnvs()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, SanitizeEnv())
+	}()
+	if err = checkProxyConnectivity(jfrogServer.Url); err != nil {
+		return
+	}
+	xrayVersion, xscVersion, err := xsc.GetJfrogServicesVersion(jfrogServer)
+	if err != nil {
+		return
+	}
+	if capabilities := DetectCapabilities(xrayVersion, xscVersion); !capabilities.IsSupported(ScaFeature) {
+		return nil, fmt.Errorf("the version of JFrog Xray isn't supported by Frogbot: %s", capabilities.UnsupportedReason(ScaFeature))
+	}
+	var errMissingConfig *ErrMissingConfig
+	configFileContent, err := ReadConfigFromFileSystem(osFrogbotConfigPath)
+	if err != nil && !errors.As(err, &errMissingConfig) {
+		return
+	}
+	configAggregator, _, err := unmarshalFrogbotConfigYaml(configFileContent)
+	if err != nil {
+		return
+	}
+	if len(configAggregator) == 0 {
+		configAggregator = newRepoAggregator()
+	}
+	// The params of the first repository of the frogbot-config.yml file apply to the local scan
+	repository = &configAggregator[0]
+	repository.Server = *jfrogServer
+	repository.Params.XrayVersion = xrayVersion
+	repository.Params.XscVersion = xscVersion
+	if err = repository.setRepositoryServerIfNeeded(); err != nil {
+		return
+	}
+	if err = repository.JFrogPlatform.setDefaultsIfNeeded(); err != nil {
+		return
+	}
+	if err = repository.Scan.setDefaultsIfNeeded(); err != nil {
+		return
+	}
+	repository.setOutputWriter(outputwriter.NewTerminalOutput())
+	return
+}
+
+// GetGitDetailsFromEnv returns the Git params of the environment variables and a client of the Git provider,
+// for the commands that only use the API of the Git provider, without a frogbot-config.yml file and without connecting to the JFrog platform.
+func GetGitDetailsFromEnv(commandName string) (gitParams *Git, client vcsclient.VcsClient, err error) {
+	if gitParams, err = extractGitParamsFromEnvs(commandName); err != nil {
+		return
+	}
+	if err = configureVcsHttpTransport(); err != nil {
+		return
+	}
+	client, err = newVcsClient(gitParams)
+	return
+}
+
+// Builds a version control client for REST API requests
+func newVcsClient(gitParams *Git) (vcsclient.VcsClient, error) {
+	return vcsclient.
+		NewClientBuilder(gitParams.GitProvider).
+		ApiEndpoint(strings.TrimSuffix(gitParams.APIEndpoint, "/")).
+		Token(gitParams.Token).
+		Project(gitParams.Project).
+		Logger(log.GetLogger()).
+		Username(gitParams.Username).
+		Build()
+}
+
 // getConfigAggregator returns a RepoAggregator based on frogbot-config.yml and environment variables.
 func getConfigAggregator(xrayVersion, xscVersion string, gitClient vcsclient.VcsClient, gitParamsFromEnv *Git, jfrogServer *coreconfig.ServerDetails, commandName string) (RepoAggregator, error) {
 	configFileContent, err := getConfigFileContent(gitClient, gitParamsFromEnv, commandName)
@@ -514,7 +1634,7 @@ func getConfigAggregator(xrayVersion, xscVersion string, gitClient vcsclient.Vcs
 func getConfigFileContent(gitClient vcsclient.VcsClient, gitParamsFromEnv *Git, commandName string) ([]byte, error) {
 	var errMissingConfig *ErrMissingConfig
 
-	if commandName == ScanRepository || commandName == ScanMultipleRepositories {
+	if commandName == ScanRepository || commandName == ScanMultipleRepositories || commandName == FixCampaign {
 		configFileContent, err := ReadConfigFromFileSystem(osFrogbotConfigPath)
 		if err != nil && !errors.As(err, &errMissingConfig) {
 			return nil, err
@@ -537,14 +1657,24 @@ func getConfigFileContent(gitClient vcsclient.VcsClient, gitParamsFromEnv *Git,
 // Returns a RepoAggregator instance with all the defaults and necessary fields.
 func BuildRepoAggregator(xrayVersion, xscVersion string, gitClient vcsclient.VcsClient, configFileContent []byte, gitParamsFromEnv *Git, server *coreconfig.ServerDetails, commandName string) (resultAggregator RepoAggregator, err error) {
 	var cleanAggregator RepoAggregator
+	var presets topicPresets
 	// Unmarshal the frogbot-config.yml file if exists
-	if cleanAggregator, err = unmarshalFrogbotConfigYaml(configFileContent); err != nil {
+	if cleanAggregator, presets, err = unmarshalFrogbotConfigYaml(configFileContent); err != nil {
+		return
+	}
+	if cleanAggregator, err = expandRepositoriesSelectors(cleanAggregator, gitParamsFromEnv, commandName); err != nil {
+		return
+	}
+	if err = applyTopicPresets(cleanAggregator, presets, gitParamsFromEnv); err != nil {
 		return
 	}
 	for _, repository := range cleanAggregator {
 		repository.Server = *server
 		repository.Params.XrayVersion = xrayVersion
 		repository.Params.XscVersion = xscVersion
+		if err = repository.setRepositoryServerIfNeeded(); err != nil {
+			return
+		}
 		if err = repository.Params.setDefaultsIfNeeded(gitParamsFromEnv, commandName); err != nil {
 			return
 		}
@@ -557,12 +1687,22 @@ func BuildRepoAggregator(xrayVersion, xscVersion string, gitClient vcsclient.Vcs
 }
 
 // unmarshalFrogbotConfigYaml uses the yaml.Unmarshaler interface to parse the yamlContent.
+// The ${ENV_VAR} references are expanded, the 'topicPresets' entry is returned separately, and the 'defaults' entry is merged into each repository, before the parsing.
 // If there is no config file, the function returns a RepoAggregator with an empty repository.
-func unmarshalFrogbotConfigYaml(yamlContent []byte) (result RepoAggregator, err error) {
+func unmarshalFrogbotConfigYaml(yamlContent []byte) (result RepoAggregator, presets topicPresets, err error) {
 	if len(yamlContent) == 0 {
 		result = newRepoAggregator()
 		return
 	}
+	if yamlContent, err = expandConfigEnvVars(yamlContent); err != nil {
+		return
+	}
+	if yamlContent, presets, err = extractTopicPresets(yamlContent); err != nil {
+		return
+	}
+	if yamlContent, err = applyConfigDefaults(yamlContent); err != nil {
+		return
+	}
 	err = yaml.Unmarshal(yamlContent, &result)
 	return
 }
@@ -579,9 +1719,7 @@ func extractJFrogCredentialsFromEnvs() (*coreconfig.ServerDetails, error) {
 		if platformUrl == "" {
 			return nil, fmt.Errorf("%s or %s and %s environment variables are missing", JFrogUrlEnv, jfrogXrayUrlEnv, jfrogArtifactoryUrlEnv)
 		}
-		server.Url = platformUrl + "/"
-		server.XrayUrl = platformUrl + "/xray/"
-		server.ArtifactoryUrl = platformUrl + "/artifactory/"
+		setPlatformUrls(&server, platformUrl)
 	}
 
 	password := getTrimmedEnv(JFrogPasswordEnv)
@@ -591,12 +1729,22 @@ func extractJFrogCredentialsFromEnvs() (*coreconfig.ServerDetails, error) {
 		server.Password = password
 	} else if accessToken := getTrimmedEnv(JFrogTokenEnv); accessToken != "" {
 		server.AccessToken = accessToken
-	} else {
-		return nil, fmt.Errorf("%s and %s or %s environment variables are missing", JFrogUserEnv, JFrogPasswordEnv, JFrogTokenEnv)
+	} else if getTrimmedEnv(JFrogOidcProviderNameEnv) == "" {
+		return nil, fmt.Errorf("%s and %s, %s or %s environment variables are missing", JFrogUserEnv, JFrogPasswordEnv, JFrogTokenEnv, JFrogOidcProviderNameEnv)
+	}
+	// The OIDC ID token is exchanged for an access token if there are no other credentials, and the access token is refreshed when it expires during the run
+	if err := setupAccessTokenManager(&server); err != nil {
+		return nil, err
 	}
 	return &server, nil
 }
 
+func setPlatformUrls(server *coreconfig.ServerDetails, platformUrl string) {
+	server.Url = platformUrl + "/"
+	server.XrayUrl = platformUrl + "/xray/"
+	server.ArtifactoryUrl = platformUrl + "/artifactory/"
+}
+
 func extractGitParamsFromEnvs(commandName string) (*Git, error) {
 	e := &ErrMissingEnv{}
 	var err error
@@ -620,11 +1768,11 @@ func extractGitParamsFromEnvs(commandName string) (*Git, error) {
 		return nil, err
 	}
 	// [Mandatory] Set the Git provider
-	if gitEnvParams.GitProvider, err = extractVcsProviderFromEnv(); err != nil {
+	if gitEnvParams.GitProvider, err = ExtractVcsProviderFromEnv(); err != nil {
 		return nil, err
 	}
-	// [Mandatory] Set the git repository owner name (organization)
-	if err = readParamFromEnv(GitRepoOwnerEnv, &gitEnvParams.RepoOwner); err != nil {
+	// [Mandatory] Set the git repository owner name (organization), except for the report-coverage command that receives the owner as a flag
+	if err = readParamFromEnv(GitRepoOwnerEnv, &gitEnvParams.RepoOwner); err != nil && !(commandName == ReportCoverage && e.IsMissingEnvErr(err)) {
 		return nil, err
 	}
 	// [Mandatory] Set the access token to the git provider
@@ -632,8 +1780,8 @@ func extractGitParamsFromEnvs(commandName string) (*Git, error) {
 		return nil, err
 	}
 
-	// [Mandatory] Set the repository name, except for multi repository.
-	if err = readParamFromEnv(GitRepoEnv, &gitEnvParams.RepoName); err != nil && commandName != ScanMultipleRepositories {
+	// [Mandatory] Set the repository name, except for the commands of multiple repositories.
+	if err = readParamFromEnv(GitRepoEnv, &gitEnvParams.RepoName); err != nil && commandName != ScanMultipleRepositories && commandName != FixCampaign && commandName != ReportCoverage {
 		return nil, err
 	}
 
@@ -673,6 +1821,64 @@ func verifyValidApiEndpoint(apiEndpoint string) error {
 	return nil
 }
 
+// Reads the package handler plugins in the format: tech1=path1,tech2=path2
+func readPackageHandlerPluginsFromEnv() (map[string]string, error) {
+	envValue := getTrimmedEnv(PackageHandlerPluginsEnv)
+	if envValue == "" {
+		return nil, nil
+	}
+	packageHandlerPlugins := map[string]string{}
+	// The paths may contain spaces, so they are kept
+	for _, plugin := range strings.Split(envValue, ",") {
+		tech, pluginPath, found := strings.Cut(plugin, "=")
+		if !found {
+			return nil, fmt.Errorf("the package handler plugin '%s' of the %s environment variable is invalid. Expected the format: tech=path", strings.TrimSpace(plugin), PackageHandlerPluginsEnv)
+		}
+		packageHandlerPlugins[strings.TrimSpace(tech)] = strings.TrimSpace(pluginPath)
+	}
+	return packageHandlerPlugins, nil
+}
+
+// Reads the limits of the fix pull requests of each severity, in the format: severity=limit,severity=limit
+func readFixMaxPullRequestsPerSeverityFromEnv() (map[string]int, error) {
+	envValue := getTrimmedEnv(FixMaxPullRequestsPerSeverityEnv)
+	if envValue == "" {
+		return nil, nil
+	}
+	limits := map[string]int{}
+	for _, severityLimit := range strings.Split(envValue, ",") {
+		severity, limit, found := strings.Cut(severityLimit, "=")
+		if !found {
+			return nil, fmt.Errorf("the limit '%s' of the %s environment variable is invalid. Expected the format: severity=limit", strings.TrimSpace(severityLimit), FixMaxPullRequestsPerSeverityEnv)
+		}
+		parsedLimit, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil {
+			return nil, fmt.Errorf("the limit of the %s severity in the %s environment variable must be a number, provided: %s", strings.TrimSpace(severity), FixMaxPullRequestsPerSeverityEnv, strings.TrimSpace(limit))
+		}
+		limits[strings.TrimSpace(severity)] = parsedLimit
+	}
+	return limits, nil
+}
+
+// Keys the limits by the canonical names of the severities, and verifies that they aren't negative
+func normalizeFixMaxPullRequestsPerSeverity(limits map[string]int) (map[string]int, error) {
+	if len(limits) == 0 {
+		return limits, nil
+	}
+	normalized := make(map[string]int, len(limits))
+	for severityName, limit := range limits {
+		severity, err := severityutils.ParseSeverity(severityName, false)
+		if err != nil {
+			return nil, err
+		}
+		if limit < 0 {
+			return nil, fmt.Errorf("the limit of the %s fix pull requests must not be negative, provided: %d", severity.String(), limit)
+		}
+		normalized[severity.String()] = limit
+	}
+	return normalized, nil
+}
+
 func readArrayParamFromEnv(envKey, delimiter string) ([]string, error) {
 	var envValue string
 	var err error
@@ -700,7 +1906,7 @@ func getTrimmedEnv(envKey string) string {
 	return strings.TrimSpace(os.Getenv(envKey))
 }
 
-func extractVcsProviderFromEnv() (vcsutils.VcsProvider, error) {
+func ExtractVcsProviderFromEnv() (vcsutils.VcsProvider, error) {
 	vcsProvider := getTrimmedEnv(GitProvider)
 	switch vcsProvider {
 	case string(GitHub):
@@ -778,6 +1984,19 @@ func getBoolEnv(envKey string, defaultValue bool) (bool, error) {
 	return defaultValue, nil
 }
 
+func getIntEnv(envKey string, defaultValue int) (int, error) {
+	envValue := getTrimmedEnv(envKey)
+	if envValue != "" {
+		parsedEnv, err := strconv.Atoi(envValue)
+		if err != nil {
+			return 0, fmt.Errorf("the value of the %s environment is expected to be a number. The value received however is %s", envKey, envValue)
+		}
+		return parsedEnv, nil
+	}
+
+	return defaultValue, nil
+}
+
 // readConfigFromTarget reads the .frogbot/frogbot-config.yml from the target repository
 func readConfigFromTarget(client vcsclient.VcsClient, gitParamsFromEnv *Git) (configContent []byte, err error) {
 	// Extract repository details from Git parameters
@@ -801,18 +2020,16 @@ func readConfigFromTarget(client vcsclient.VcsClient, gitParamsFromEnv *Git) (co
 	}
 
 	// Construct the path to the frogbot-config.yml file in the repository
-	gitFrogbotConfigPath := fmt.Sprintf("%s/%s", frogbotConfigDir, FrogbotConfigFile)
-
 	// Download the frogbot-config.yml file from the repository
 	var statusCode int
-	configContent, statusCode, err = client.DownloadFileFromRepo(context.Background(), repoOwner, repoName, branch, gitFrogbotConfigPath)
+	configContent, statusCode, err = client.DownloadFileFromRepo(context.Background(), repoOwner, repoName, branch, FrogbotConfigFilePath)
 
 	// Handle different HTTP status codes
 	switch statusCode {
 	case http.StatusOK:
 		log.Info(fmt.Sprintf("Successfully downloaded %s file from <%s/%s/%s>", FrogbotConfigFile, repoOwner, repoName, branch))
 	case http.StatusNotFound:
-		log.Debug(fmt.Sprintf("The %s file wasn't recognized in <%s/%s>", gitFrogbotConfigPath, repoOwner, repoName))
+		log.Debug(fmt.Sprintf("The %s file wasn't recognized in <%s/%s>", FrogbotConfigFilePath, repoOwner, repoName))
 		// If .frogbot/frogbot-config.yml isn't found, return an ErrMissingConfig
 		configContent = nil
 		err = &ErrMissingConfig{errFrogbotConfigNotFound.Error()}
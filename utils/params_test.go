@@ -12,9 +12,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	securityutils "github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-client-go/utils/tests"
 	"github.com/jfrog/jfrog-client-go/xsc/services"
 
@@ -40,7 +42,7 @@ func TestExtractParamsFromEnvError(t *testing.T) {
 
 	SetEnvAndAssert(t, map[string]string{JFrogUrlEnv: "http://127.0.0.1:8081"})
 	_, err = extractJFrogCredentialsFromEnvs()
-	assert.EqualError(t, err, "JF_USER and JF_PASSWORD or JF_ACCESS_TOKEN environment variables are missing")
+	assert.EqualError(t, err, "JF_USER and JF_PASSWORD, JF_ACCESS_TOKEN or JF_OIDC_PROVIDER_NAME environment variables are missing")
 }
 
 // Test extraction of env params in ScanPullRequest command
@@ -107,29 +109,29 @@ func TestExtractParamsFromEnvToken(t *testing.T) {
 }
 
 func TestExtractVcsProviderFromEnv(t *testing.T) {
-	_, err := extractVcsProviderFromEnv()
+	_, err := ExtractVcsProviderFromEnv()
 	assert.Error(t, err)
 	defer func() {
 		assert.NoError(t, SanitizeEnv())
 	}()
 
 	SetEnvAndAssert(t, map[string]string{GitProvider: string(GitHub)})
-	vcsProvider, err := extractVcsProviderFromEnv()
+	vcsProvider, err := ExtractVcsProviderFromEnv()
 	assert.NoError(t, err)
 	assert.Equal(t, vcsutils.GitHub, vcsProvider)
 
 	SetEnvAndAssert(t, map[string]string{GitProvider: string(GitLab)})
-	vcsProvider, err = extractVcsProviderFromEnv()
+	vcsProvider, err = ExtractVcsProviderFromEnv()
 	assert.NoError(t, err)
 	assert.Equal(t, vcsutils.GitLab, vcsProvider)
 
 	SetEnvAndAssert(t, map[string]string{GitProvider: string(BitbucketServer)})
-	vcsProvider, err = extractVcsProviderFromEnv()
+	vcsProvider, err = ExtractVcsProviderFromEnv()
 	assert.NoError(t, err)
 	assert.Equal(t, vcsutils.BitbucketServer, vcsProvider)
 
 	SetEnvAndAssert(t, map[string]string{GitProvider: string(AzureRepos)})
-	vcsProvider, err = extractVcsProviderFromEnv()
+	vcsProvider, err = ExtractVcsProviderFromEnv()
 	assert.NoError(t, err)
 	assert.Equal(t, vcsutils.AzureRepos, vcsProvider)
 }
@@ -157,24 +159,78 @@ func TestExtractClientInfo(t *testing.T) {
 
 func TestExtractAndAssertRepoParams(t *testing.T) {
 	SetEnvAndAssert(t, map[string]string{
-		JFrogUrlEnv:          "http://127.0.0.1:8081",
-		JFrogUserEnv:         "",
-		JFrogPasswordEnv:     "",
-		JFrogTokenEnv:        "token",
-		GitProvider:          string(GitHub),
-		GitRepoOwnerEnv:      "jfrog",
-		GitRepoEnv:           "frogbot",
-		GitTokenEnv:          "123456789",
-		GitBaseBranchEnv:     "dev",
-		GitPullRequestIDEnv:  "1",
-		GitAggregateFixesEnv: "true",
-		GitEmailAuthorEnv:    "myemail@jfrog.com",
-		MinSeverityEnv:       "high",
-		FixableOnlyEnv:       "true",
-		DisableJasEnv:        "true",
-		DetectionOnlyEnv:     "true",
-		AllowedLicensesEnv:   "MIT, Apache-2.0, ISC",
-		AvoidExtraMessages:   "true",
+		JFrogUrlEnv:                      "http://127.0.0.1:8081",
+		JFrogUserEnv:                     "",
+		JFrogPasswordEnv:                 "",
+		JFrogTokenEnv:                    "token",
+		GitProvider:                      string(GitHub),
+		GitRepoOwnerEnv:                  "jfrog",
+		GitRepoEnv:                       "frogbot",
+		GitTokenEnv:                      "123456789",
+		GitBaseBranchEnv:                 "dev",
+		GitPullRequestIDEnv:              "1",
+		GitAggregateFixesEnv:             "true",
+		GitEmailAuthorEnv:                "myemail@jfrog.com",
+		MinSeverityEnv:                   "high",
+		FixableOnlyEnv:                   "true",
+		DisableJasEnv:                    "true",
+		DetectionOnlyEnv:                 "true",
+		AllowedLicensesEnv:               "MIT, Apache-2.0, ISC",
+		AvoidExtraMessages:               "true",
+		TargetCvesEnv:                    "cve-2021-44228,CVE-2021-45046,log4j-core@2.14.1",
+		TargetCvesFixOnlyEnv:             "true",
+		PackageHandlerPluginsEnv:         "conan=tools/fix-conan, generic = /opt/frogbot/fix generic",
+		InternalNamespacesEnv:            "@mycompany/*, com.mycompany*",
+		ReportPathEnv:                    "frogbot-report.html",
+		SbomPathEnv:                      "frogbot-sbom.json",
+		ShowUnsupportedFixesEnv:          "true",
+		FailAfterDateEnv:                 "2030-01-01",
+		GitDownloadRetriesEnv:            "3",
+		GitSubmodulesEnv:                 "true",
+		GitShallowCloneEnv:               "true",
+		GitSeparateFixesMinSeverityEnv:   "critical",
+		FailOnMissingWatchesOrProjectEnv: "true",
+		PrioritizeExploitedFixesEnv:      "true",
+		ResearchEnrichmentEnv:            "true",
+		ValidateSecretsEnv:               "true",
+		BlockOnSecretsEnv:                "true",
+		ScanDockerfilesEnv:               "true",
+		ScanGitHubActionsEnv:             "true",
+		ScanBazelEnv:                     "true",
+		ScanComposerEnv:                  "true",
+		ScanSystemPackagesEnv:            "true",
+		CollapsePreviousPrCommentsEnv:    "true",
+		BatchReviewCommentsEnv:           "true",
+		ScanProgressCommentEnv:           "true",
+		IncrementalPrScanEnv:             "true",
+		CommentSuppressionsEnv:           "true",
+		SuppressionApproversEnv:          "security-lead,appsec-bot",
+		ScanCurationEnv:                  "true",
+		FailOnCurationBlockedEnv:         "true",
+		ExternalSarifPathsEnv:            "checkov.sarif;reports/trivy.sarif",
+		TrackUnfixableVulnerabilitiesEnv: "true",
+		AzureWorkItemTypeEnv:             "Bug",
+		BranchesSummaryIssueEnv:          "true",
+		RepositoriesSummaryRepoEnv:       "frogbot-dashboard",
+		BranchBaselinesFileEnv:           "frogbot-baselines.json",
+		ScanHistoryFileEnv:               "frogbot-history.json",
+		BotPullRequestAuthorsEnv:         "dependabot[bot], renovate[bot]",
+		MaxOpenFixPullRequestsEnv:        "10",
+		MaxNewFixPullRequestsEnv:         "3",
+		FixMaxPullRequestsPerSeverityEnv: "critical=2, High=5",
+		FixMinSeverityEnv:                "high",
+		FixPullRequestsWindowsEnv:        "Mon-Fri 09:00-17:00; Sat 10:00-12:00",
+		BackportBranchesEnv:              "release/1.x, release/2.x",
+		PullRequestLabelsEnv:             "security, dependencies",
+		PullRequestTeamReviewersEnv:      "security-team",
+		RefreshBehindBaseCommitsEnv:      "20",
+		PinGitHubActionsEnv:              "true",
+		AnalyzeUpgradeRiskEnv:            "true",
+		NpmDiffLinksEnv:                  "true",
+		CleanupMergedBranchesEnv:         "true",
+		CleanupRetentionDaysEnv:          "14",
+		AutoMergeEnv:                     "true",
+		AutoMergeStrategyEnv:             "Squash",
 	})
 	defer func() {
 		assert.NoError(t, SanitizeEnv())
@@ -205,10 +261,77 @@ func TestExtractAndAssertRepoParams(t *testing.T) {
 		assert.True(t, repo.DisableJas)
 		assert.True(t, repo.DetectionOnly)
 		assert.Equal(t, true, repo.AggregateFixes)
+		assert.True(t, repo.ShowUnsupportedFixes)
+		assert.Equal(t, 3, repo.DownloadRetries)
+		assert.True(t, repo.Submodules)
+		assert.True(t, repo.ShallowClone)
+		assert.Equal(t, "Critical", repo.SeparateFixesMinSeverity)
+		assert.True(t, repo.FailOnMissingWatchesOrProject)
 		assert.Equal(t, "myemail@jfrog.com", repo.EmailAuthor)
 		assert.Equal(t, "build 1323", repo.PullRequestCommentTitle)
 		assert.ElementsMatch(t, []string{"watch-2", "watch-1"}, repo.Watches)
 		assert.ElementsMatch(t, []string{"MIT", "ISC", "Apache-2.0"}, repo.AllowedLicenses)
+		assert.Equal(t, []string{"CVE-2021-44228", "CVE-2021-45046", "log4j-core@2.14.1"}, repo.TargetCves)
+		assert.True(t, repo.TargetCvesFixOnly)
+		assert.Nil(t, repo.GetScannedTargetCves())
+		assert.Len(t, repo.PackageHandlerPlugins, 2)
+		assert.True(t, filepath.IsAbs(repo.PackageHandlerPlugins["conan"]))
+		assert.Equal(t, "fix-conan", filepath.Base(repo.PackageHandlerPlugins["conan"]))
+		assert.Equal(t, "tools", filepath.Base(filepath.Dir(repo.PackageHandlerPlugins["conan"])))
+		assert.Equal(t, "fix generic", filepath.Base(repo.PackageHandlerPlugins["generic"]))
+		assert.Equal(t, []string{"@mycompany/*", "com.mycompany*"}, repo.InternalNamespaces)
+		assert.True(t, filepath.IsAbs(repo.ReportPath))
+		assert.Equal(t, "frogbot-report.html", filepath.Base(repo.ReportPath))
+		assert.True(t, filepath.IsAbs(repo.SbomPath))
+		assert.Equal(t, "frogbot-sbom.json", filepath.Base(repo.SbomPath))
+		assert.Equal(t, "2030-01-01", repo.FailAfterDate)
+		assert.True(t, repo.PrioritizeExploitedFixes)
+		assert.True(t, repo.ExploitabilityEnrichment)
+		assert.True(t, repo.ResearchEnrichment)
+		assert.True(t, repo.ValidateSecrets)
+		assert.True(t, repo.BlockOnSecrets)
+		assert.True(t, repo.ScanDockerfiles)
+		assert.True(t, repo.ScanGitHubActions)
+		assert.True(t, repo.ScanBazel)
+		assert.True(t, repo.ScanComposer)
+		assert.True(t, repo.ScanSystemPackages)
+		assert.True(t, repo.CollapsePreviousPrComments)
+		assert.True(t, repo.BatchReviewComments)
+		assert.True(t, repo.ScanProgressComment)
+		assert.True(t, repo.IncrementalScan)
+		assert.True(t, repo.CommentSuppressions)
+		assert.Equal(t, []string{"security-lead", "appsec-bot"}, repo.SuppressionApprovers)
+		assert.True(t, repo.ScanCuration)
+		assert.True(t, repo.FailOnCurationBlocked)
+		require.Len(t, repo.ExternalSarifPaths, 2)
+		assert.True(t, filepath.IsAbs(repo.ExternalSarifPaths[1]))
+		assert.Equal(t, "trivy.sarif", filepath.Base(repo.ExternalSarifPaths[1]))
+		assert.True(t, repo.TrackUnfixableVulnerabilities)
+		assert.Equal(t, "Bug", repo.AzureWorkItemType)
+		assert.True(t, repo.BranchesSummaryIssue)
+		assert.Equal(t, "frogbot-dashboard", repo.RepositoriesSummaryRepo)
+		assert.True(t, filepath.IsAbs(repo.BranchBaselinesFile))
+		assert.Equal(t, "frogbot-baselines.json", filepath.Base(repo.BranchBaselinesFile))
+		assert.True(t, filepath.IsAbs(repo.ScanHistoryFile))
+		assert.Equal(t, "frogbot-history.json", filepath.Base(repo.ScanHistoryFile))
+		assert.Equal(t, []string{"dependabot[bot]", "renovate[bot]"}, repo.BotPullRequestAuthors)
+		assert.Equal(t, 10, repo.MaxOpenFixPullRequests)
+		assert.Equal(t, map[string]int{"Critical": 2, "High": 5}, repo.FixMaxPullRequestsPerSeverity)
+		assert.Equal(t, "High", repo.FixMinSeverity)
+		assert.Equal(t, 3, repo.MaxNewFixPullRequests)
+		assert.Equal(t, []string{"Mon-Fri 09:00-17:00", "Sat 10:00-12:00"}, repo.FixPullRequestsWindows)
+		assert.Equal(t, []string{"release/1.x", "release/2.x"}, repo.BackportBranches)
+		assert.Equal(t, []string{"security", "dependencies"}, repo.PullRequestLabels)
+		assert.Empty(t, repo.PullRequestReviewers)
+		assert.Equal(t, []string{"security-team"}, repo.PullRequestTeamReviewers)
+		assert.Equal(t, 20, repo.RefreshBehindBaseCommits)
+		assert.True(t, repo.PinGitHubActions)
+		assert.True(t, repo.AnalyzeUpgradeRisk)
+		assert.True(t, repo.NpmDiffLinks)
+		assert.True(t, repo.CleanupMergedBranches)
+		assert.Equal(t, 14, repo.CleanupRetentionDays)
+		assert.True(t, repo.AutoMerge)
+		assert.Equal(t, "squash", repo.AutoMergeStrategy)
 		for _, project := range repo.Projects {
 			testExtractAndAssertProjectParams(t, project)
 		}
@@ -238,12 +361,38 @@ func TestBuildRepoAggregatorWithEmptyScan(t *testing.T) {
 	assert.Len(t, configAggregator, 1)
 	assert.Equal(t, frogbotAuthorEmail, configAggregator[0].EmailAuthor)
 	assert.False(t, configAggregator[0].AggregateFixes)
+	assert.False(t, configAggregator[0].ShowUnsupportedFixes)
+	assert.Zero(t, configAggregator[0].DownloadRetries)
+	assert.Empty(t, configAggregator[0].BotPullRequestAuthors)
+	assert.Zero(t, configAggregator[0].MaxOpenFixPullRequests)
+	assert.Zero(t, configAggregator[0].MaxNewFixPullRequests)
+	assert.Empty(t, configAggregator[0].FixPullRequestsWindows)
+	assert.Empty(t, configAggregator[0].BackportBranches)
+	assert.Empty(t, configAggregator[0].PullRequestLabels)
+	assert.Zero(t, configAggregator[0].RefreshBehindBaseCommits)
+	assert.False(t, configAggregator[0].Submodules)
+	assert.False(t, configAggregator[0].ShallowClone)
+	assert.Empty(t, configAggregator[0].SeparateFixesMinSeverity)
+	assert.False(t, configAggregator[0].FailOnMissingWatchesOrProject)
 	scan := configAggregator[0].Scan
 	assert.False(t, scan.IncludeAllVulnerabilities)
 	assert.False(t, scan.FixableOnly)
 	assert.Empty(t, scan.MinSeverity)
 	assert.Empty(t, scan.AllowedLicenses)
+	assert.Empty(t, scan.TargetCves)
+	assert.False(t, scan.TargetCvesFixOnly)
+	assert.Empty(t, scan.PackageHandlerPlugins)
+	assert.Empty(t, scan.InternalNamespaces)
+	assert.Empty(t, scan.ReportPath)
+	assert.Empty(t, scan.SbomPath)
+	assert.Empty(t, scan.FailAfterDate)
 	assert.True(t, *scan.FailOnSecurityIssues)
+	assert.False(t, scan.ScanSystemPackages)
+	assert.False(t, scan.CollapsePreviousPrComments)
+	assert.False(t, scan.ScanProgressComment)
+	assert.False(t, scan.IncrementalScan)
+	assert.False(t, scan.CommentSuppressions)
+	assert.Empty(t, scan.SuppressionApprovers)
 	assert.Len(t, scan.Projects, 1)
 	project := scan.Projects[0]
 	assert.Empty(t, project.InstallCommandName)
@@ -301,6 +450,85 @@ func extractAndAssertParamsFromEnv(t *testing.T, platformUrl, basicAuth bool, co
 	}
 }
 
+func TestBuildRepoAggregatorScanAllPullRequests(t *testing.T) {
+	testCases := []struct {
+		name              string
+		env               map[string]string
+		configContent     string
+		expectedStateFile string
+		expectedThreshold int
+		expectedMaxWait   time.Duration
+	}{
+		{
+			name:              "Defaults",
+			configContent:     "- params:\n    git:\n      repoName: frogbot\n",
+			expectedThreshold: defaultRateLimitThreshold,
+			expectedMaxWait:   defaultRateLimitMaxWait,
+		},
+		{
+			name: "From environment variables",
+			env: map[string]string{
+				PullRequestsStateFileEnv: "frogbot-pull-requests.json",
+				RateLimitThresholdEnv:    "50",
+				RateLimitMaxWaitEnv:      "120",
+			},
+			configContent:     "- params:\n    git:\n      repoName: frogbot\n",
+			expectedStateFile: "frogbot-pull-requests.json",
+			expectedThreshold: 50,
+			expectedMaxWait:   2 * time.Minute,
+		},
+		{
+			name:              "Threshold from the config file",
+			configContent:     "- params:\n    git:\n      repoName: frogbot\n      rateLimitThreshold: 30\n      pullRequestsStateFile: state.json\n",
+			expectedStateFile: "state.json",
+			expectedThreshold: 30,
+			expectedMaxWait:   defaultRateLimitMaxWait,
+		},
+		{
+			name:              "Pausing disabled",
+			env:               map[string]string{RateLimitMaxWaitEnv: "0"},
+			configContent:     "- params:\n    git:\n      repoName: frogbot\n      rateLimitThreshold: 30\n",
+			expectedThreshold: 30,
+			expectedMaxWait:   0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := map[string]string{
+				JFrogUrlEnv:     "http://127.0.0.1:8081",
+				JFrogTokenEnv:   "token",
+				GitProvider:     string(GitHub),
+				GitRepoOwnerEnv: "jfrog",
+				GitRepoEnv:      "frogbot",
+				GitTokenEnv:     "123456789",
+			}
+			for key, value := range tc.env {
+				env[key] = value
+			}
+			SetEnvAndAssert(t, env)
+			defer func() {
+				assert.NoError(t, SanitizeEnv())
+			}()
+			server, err := extractJFrogCredentialsFromEnvs()
+			assert.NoError(t, err)
+			gitParams, err := extractGitParamsFromEnvs(ScanAllPullRequests)
+			require.NoError(t, err)
+			configAggregator, err := BuildRepoAggregator("xrayVersion", "xscVersion", nil, []byte(tc.configContent), gitParams, server, ScanAllPullRequests)
+			require.NoError(t, err)
+			require.Len(t, configAggregator, 1)
+			repo := configAggregator[0]
+			if tc.expectedStateFile == "" {
+				assert.Empty(t, repo.PullRequestsStateFile)
+			} else {
+				assert.True(t, filepath.IsAbs(repo.PullRequestsStateFile))
+				assert.Equal(t, tc.expectedStateFile, filepath.Base(repo.PullRequestsStateFile))
+			}
+			assert.Equal(t, tc.expectedThreshold, repo.RateLimitThreshold)
+			assert.Equal(t, tc.expectedMaxWait, repo.RateLimitMaxWait)
+		})
+	}
+}
+
 func TestExtractInstallationCommandFromEnv(t *testing.T) {
 	defer func() {
 		assert.NoError(t, SanitizeEnv())
@@ -361,6 +589,13 @@ func TestGenerateConfigAggregatorFromEnv(t *testing.T) {
 		AllowedLicensesEnv:                 "MIT, Apache-2.0",
 		AvoidExtraMessages:                 "true",
 		PullRequestCommentTitleEnv:         "build 1323",
+		ShowIgnoredFindingsEnv:             "true",
+		MentionCodeOwnersEnv:               "true",
+		DisallowForkPullRequestsEnv:        "true",
+		MaxConcurrentReposEnv:              "3",
+		ShowSectionsEnv:                    "Vulnerabilities, secrets",
+		HideResearchDetailsEnv:             "true",
+		MaxRowsPerTableEnv:                 "20",
 	})
 	defer func() {
 		assert.NoError(t, SanitizeEnv())
@@ -404,10 +639,18 @@ func validateBuildRepoAggregator(t *testing.T, repo *Repository, gitParams *Git,
 	assert.Equal(t, true, repo.AddPrCommentOnSuccess)
 	assert.Equal(t, true, repo.DetectionOnly)
 	assert.ElementsMatch(t, []string{"MIT", "Apache-2.0"}, repo.AllowedLicenses)
+	assert.Equal(t, 3, repo.MaxConcurrentRepos)
 	assert.Equal(t, gitParams.RepoOwner, repo.RepoOwner)
 	assert.Equal(t, gitParams.Token, repo.Token)
 	assert.Equal(t, gitParams.APIEndpoint, repo.APIEndpoint)
 	assert.Equal(t, gitParams.GitProvider, repo.GitProvider)
+	assert.Equal(t, []string{"vulnerabilities", "secrets"}, repo.ShowSections)
+	assert.True(t, repo.HideResearchDetails)
+	assert.Equal(t, 20, repo.MaxRowsPerTable)
+	reportingOptions := repo.OutputWriter.ReportingOptions()
+	assert.Equal(t, repo.ShowSections, reportingOptions.ShowSections)
+	assert.True(t, reportingOptions.HideResearchDetails)
+	assert.Equal(t, 20, reportingOptions.MaxRowsPerTable)
 
 	assert.Equal(t, server.ArtifactoryUrl, repo.Server.ArtifactoryUrl)
 	assert.Equal(t, server.XrayUrl, repo.Server.XrayUrl)
@@ -425,6 +668,9 @@ func validateBuildRepoAggregator(t *testing.T, repo *Repository, gitParams *Git,
 		assert.NotZero(t, repo.PullRequestDetails.ID)
 		assert.True(t, repo.AvoidExtraMessages)
 		assert.NotEmpty(t, repo.PullRequestCommentTitle)
+		assert.True(t, repo.ShowIgnoredFindings)
+		assert.True(t, repo.MentionCodeOwners)
+		assert.True(t, repo.DisallowForkPullRequests)
 	}
 
 	project := repo.Projects[0]
@@ -451,14 +697,18 @@ func TestExtractProjectParamsFromEnv(t *testing.T) {
 	assert.Equal(t, "", project.InstallCommandName)
 	assert.Equal(t, []string(nil), project.InstallCommandArgs)
 	assert.True(t, project.IsRecursiveScan)
+	assert.Zero(t, project.DetectionDepth)
+	assert.False(t, project.DetectInstallCommand)
 
 	// Test value extraction
 	SetEnvAndAssert(t, map[string]string{
-		WorkingDirectoryEnv: "b/c",
-		RequirementsFileEnv: "r.txt",
-		UseWrapperEnv:       "false",
-		InstallCommandEnv:   "nuget restore",
-		DepsRepoEnv:         "repository",
+		WorkingDirectoryEnv:     "b/c",
+		RequirementsFileEnv:     "r.txt",
+		UseWrapperEnv:           "false",
+		InstallCommandEnv:       "nuget restore",
+		DepsRepoEnv:             "repository",
+		BazelRepinCommandEnv:    "bazel run @maven//:pin",
+		DetectInstallCommandEnv: "true",
 	})
 
 	project = &Project{}
@@ -470,14 +720,24 @@ func TestExtractProjectParamsFromEnv(t *testing.T) {
 	assert.Equal(t, "nuget", project.InstallCommandName)
 	assert.Equal(t, []string{"restore"}, project.InstallCommandArgs)
 	assert.Equal(t, "repository", project.DepsRepo)
+	assert.Equal(t, "bazel run @maven//:pin", project.BazelRepinCommand)
+	assert.True(t, project.DetectInstallCommand)
 	assert.False(t, project.IsRecursiveScan)
+	assert.Zero(t, project.DetectionDepth)
+
+	// The detection of the projects is enabled
+	SetEnvAndAssert(t, map[string]string{WorkingDirectoryEnv: "", ProjectDetectionDepthEnv: "3"})
+	project = &Project{}
+	assert.NoError(t, project.setDefaultsIfNeeded())
+	assert.True(t, project.IsRecursiveScan)
+	assert.Equal(t, 3, project.DetectionDepth)
 }
 
 func TestFrogbotConfigAggregator_unmarshalFrogbotConfigYaml(t *testing.T) {
 	testFilePath := filepath.Join("..", "testdata", "config", "frogbot-config-test-unmarshal.yml")
 	fileContent, err := os.ReadFile(testFilePath)
 	assert.NoError(t, err)
-	configAggregator, err := unmarshalFrogbotConfigYaml(fileContent)
+	configAggregator, _, err := unmarshalFrogbotConfigYaml(fileContent)
 	assert.NoError(t, err)
 	firstRepo := configAggregator[0]
 	assert.Equal(t, "npm-repo", firstRepo.RepoName)
@@ -492,6 +752,7 @@ func TestFrogbotConfigAggregator_unmarshalFrogbotConfigYaml(t *testing.T) {
 	assert.Equal(t, "mvn-repo", secondRepo.RepoName)
 	assert.Equal(t, []string{"dev"}, secondRepo.Branches)
 	assert.False(t, secondRepo.AvoidPreviousPrCommentsDeletion)
+	assert.Equal(t, EmailReceivers{Secrets: []string{"security@jfrog.com"}, Critical: []string{"appsec@jfrog.com"}}, secondRepo.EmailReceivers)
 	thirdRepo := configAggregator[2]
 	assert.Equal(t, "pip-repo", thirdRepo.RepoName)
 	assert.Equal(t, []string{"test"}, thirdRepo.Branches)
@@ -503,6 +764,137 @@ func TestFrogbotConfigAggregator_unmarshalFrogbotConfigYaml(t *testing.T) {
 	assert.ElementsMatch(t, []string{"a/b", "b/c"}, thirdRepoProject.WorkingDirs)
 	assert.ElementsMatch(t, []string{"watch-1", "watch-2"}, thirdRepo.Watches)
 	assert.Equal(t, "proj", thirdRepo.JFrogProjectKey)
+	assert.False(t, thirdRepoProject.HasViolationsContext())
+	secondProject := thirdRepo.Projects[1]
+	assert.ElementsMatch(t, []string{"watch-3"}, secondProject.Watches)
+	assert.Equal(t, "other-proj", secondProject.JFrogProjectKey)
+	assert.True(t, secondProject.HasViolationsContext())
+}
+
+func TestProjectSplitByInstallCommands(t *testing.T) {
+	testCases := []struct {
+		name             string
+		project          Project
+		expectedProjects []Project
+		expectedError    string
+	}{
+		{
+			name:             "No install commands of working directories",
+			project:          Project{WorkingDirs: []string{"a", "b"}, InstallCommand: "npm ci"},
+			expectedProjects: []Project{{WorkingDirs: []string{"a", "b"}, InstallCommand: "npm ci"}},
+		},
+		{
+			name: "Install commands of some working directories",
+			project: Project{
+				WorkingDirs:        []string{"frontend", "backend/", "docs"},
+				InstallCommand:     "npm ci",
+				InstallCommandName: "npm",
+				InstallCommandArgs: []string{"ci"},
+				InstallCommands:    map[string]string{"backend": "yarn install --immutable", "./docs": " pnpm install "},
+			},
+			expectedProjects: []Project{
+				{WorkingDirs: []string{"frontend"}, InstallCommand: "npm ci", InstallCommandName: "npm", InstallCommandArgs: []string{"ci"}},
+				{WorkingDirs: []string{"backend/"}, InstallCommand: "yarn install --immutable", InstallCommandName: "yarn", InstallCommandArgs: []string{"install", "--immutable"}},
+				{WorkingDirs: []string{"docs"}, InstallCommand: "pnpm install", InstallCommandName: "pnpm", InstallCommandArgs: []string{"install"}},
+			},
+		},
+		{
+			name:    "Install commands of all working directories",
+			project: Project{WorkingDirs: []string{"a"}, InstallCommands: map[string]string{"a": "dotnet"}},
+			expectedProjects: []Project{
+				{WorkingDirs: []string{"a"}, InstallCommand: "dotnet", InstallCommandName: "dotnet"},
+			},
+		},
+		{
+			name:          "Install command of an unknown working directory",
+			project:       Project{WorkingDirs: []string{"a"}, InstallCommands: map[string]string{"a": "npm ci", "c": "npm ci", "b": "npm ci"}},
+			expectedError: "install commands are set for directories that aren't working directories of the project: b, c",
+		},
+		{
+			name:          "Empty install command",
+			project:       Project{WorkingDirs: []string{"a"}, InstallCommands: map[string]string{"a": " "}},
+			expectedError: "the install command of the 'a' working directory is empty",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			projects, err := tc.project.splitByInstallCommands()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedProjects, projects)
+		})
+	}
+}
+
+func TestFilterProjectsWorkingDirs(t *testing.T) {
+	projects := []Project{{WorkingDirs: []string{"frontend/web", "frontend/admin", "backend"}}, {WorkingDirs: []string{"./legacy"}, InstallCommand: "npm ci"}}
+	testCases := []struct {
+		name             string
+		env              map[string]string
+		expectedProjects []Project
+		expectedError    string
+	}{
+		{name: "No filters", expectedProjects: projects},
+		{
+			name:             "Included working dirs",
+			env:              map[string]string{IncludeWorkingDirsEnv: "frontend/*;legacy"},
+			expectedProjects: []Project{{WorkingDirs: []string{"frontend/web", "frontend/admin"}}, {WorkingDirs: []string{"./legacy"}, InstallCommand: "npm ci"}},
+		},
+		{
+			name:             "Excluded working dirs",
+			env:              map[string]string{ExcludeWorkingDirsEnv: "legacy;frontend/admin"},
+			expectedProjects: []Project{{WorkingDirs: []string{"frontend/web", "backend"}}},
+		},
+		{
+			name:             "Included and excluded working dirs",
+			env:              map[string]string{IncludeWorkingDirsEnv: "frontend/*", ExcludeWorkingDirsEnv: "frontend/admin"},
+			expectedProjects: []Project{{WorkingDirs: []string{"frontend/web"}}},
+		},
+		{
+			name:          "All working dirs filtered out",
+			env:           map[string]string{IncludeWorkingDirsEnv: "mobile"},
+			expectedError: "all the working directories of the projects are filtered out by the JF_INCLUDE_WORKING_DIRS and JF_EXCLUDE_WORKING_DIRS environment variables",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			filteredProjects, err := filterProjectsWorkingDirs(projects)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedProjects, filteredProjects)
+		})
+	}
+}
+
+func TestProjectGetViolationsContext(t *testing.T) {
+	repositoryWatches := []string{"watch-1", "watch-2"}
+	testCases := []struct {
+		name               string
+		project            Project
+		expectedWatches    []string
+		expectedProjectKey string
+	}{
+		{name: "Repository watches and project", project: Project{}, expectedWatches: repositoryWatches, expectedProjectKey: "proj"},
+		{name: "Project watches", project: Project{Watches: []string{"watch-3"}}, expectedWatches: []string{"watch-3"}, expectedProjectKey: "proj"},
+		{name: "Project JFrog project", project: Project{JFrogProjectKey: "other-proj"}, expectedWatches: repositoryWatches, expectedProjectKey: "other-proj"},
+		{name: "Project watches and JFrog project", project: Project{Watches: []string{"watch-3", "watch-4"}, JFrogProjectKey: "other-proj"}, expectedWatches: []string{"watch-3", "watch-4"}, expectedProjectKey: "other-proj"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			watches, projectKey := tc.project.GetViolationsContext(repositoryWatches, "proj")
+			assert.Equal(t, tc.expectedWatches, watches)
+			assert.Equal(t, tc.expectedProjectKey, projectKey)
+		})
+	}
 }
 
 func TestVerifyValidApiEndpoint(t *testing.T) {
@@ -697,6 +1089,26 @@ func TestSetEmailDetails(t *testing.T) {
 	}
 }
 
+func TestSetEmailReceiversByAlertType(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{
+		SmtpServerEnv:             "smtp.server.com:587",
+		SmtpUserEnv:               "user",
+		SmtpPasswordEnv:           "pass",
+		EmailReceiversEnv:         "security@example.com",
+		CriticalEmailReceiversEnv: "appsec@example.com,oncall@example.com",
+		LicensesEmailReceiversEnv: "legal@example.com",
+	})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	// The receivers of the config take precedence over the environment variables
+	scan := &Scan{EmailDetails: EmailDetails{EmailReceivers: EmailReceivers{Licenses: []string{"compliance@example.com"}}}}
+	require.NoError(t, scan.SetEmailDetails())
+	assert.Equal(t, []string{"security@example.com"}, scan.EmailReceivers.Get(SecretsAlert))
+	assert.Equal(t, []string{"appsec@example.com", "oncall@example.com"}, scan.EmailReceivers.Get(CriticalVulnerabilitiesAlert))
+	assert.Equal(t, []string{"compliance@example.com"}, scan.EmailReceivers.Get(LicenseViolationsAlert))
+}
+
 func TestGetConfigProfileIfExistsAndValid(t *testing.T) {
 	testcases := []struct {
 		name            string
@@ -820,3 +1232,196 @@ func createMockVcsClient(t *testing.T, repoOwner, repoName string) *testdata.Moc
 	}, nil)
 	return mockVcsClient
 }
+
+func TestIsInOnboardingPeriod(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failAfterDate string
+		expected      bool
+	}{
+		{name: "No fail after date", failAfterDate: "", expected: false},
+		{name: "Future date", failAfterDate: time.Now().AddDate(0, 0, 7).Format(failAfterDateLayout), expected: true},
+		{name: "Past date", failAfterDate: time.Now().AddDate(0, 0, -7).Format(failAfterDateLayout), expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scan := &Scan{FailAfterDate: tc.failAfterDate}
+			assert.Equal(t, tc.expected, scan.IsInOnboardingPeriod())
+		})
+	}
+}
+
+func TestInvalidFailAfterDate(t *testing.T) {
+	scan := &Scan{FailAfterDate: "30/06/2025"}
+	assert.ErrorContains(t, scan.setDefaultsIfNeeded(), "the fail after date '30/06/2025' is invalid")
+}
+
+func TestApplicableOnlyOptions(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{FailOnApplicableOnlyEnv: "true", FixApplicableOnlyEnv: "true"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	scan := &Scan{}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.True(t, scan.FailOnApplicableOnly)
+	assert.True(t, scan.FixApplicableOnly)
+
+	// The applicability is determined by the contextual analysis, which doesn't run without the advanced security scanners
+	scan = &Scan{DisableJas: true}
+	assert.ErrorContains(t, scan.setDefaultsIfNeeded(), "require the contextual analysis")
+}
+
+func TestSkipDevDependencies(t *testing.T) {
+	scan := &Scan{}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.False(t, scan.SkipDevDependencies)
+
+	SetEnvAndAssert(t, map[string]string{SkipDevDependenciesEnv: "true"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	scan = &Scan{}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.True(t, scan.SkipDevDependencies)
+}
+
+func TestJasScanners(t *testing.T) {
+	scan := &Scan{}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.True(t, *scan.Jas.EnableApplicability && *scan.Jas.EnableSecrets && *scan.Jas.EnableIac && *scan.Jas.EnableSast)
+	assert.Nil(t, scan.Jas.ScansToPerform())
+
+	SetEnvAndAssert(t, map[string]string{EnableSastEnv: "false", SecretsExclusionsEnv: "tests; docs/examples"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	scan = &Scan{}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.False(t, *scan.Jas.EnableSast)
+	assert.Equal(t, []string{"tests", "docs/examples"}, scan.Jas.SecretsExclusions)
+	assert.Empty(t, scan.Jas.SastExclusions)
+	assert.Equal(t, DefaultExcludePatterns, scan.ExcludePatterns)
+	assert.Equal(t, []securityutils.SubScanType{securityutils.ScaScan, securityutils.ContextualAnalysisScan, securityutils.SecretsScan, securityutils.IacScan}, scan.Jas.ScansToPerform())
+
+	// The configuration takes precedence over the environment variables
+	enabled := true
+	scan = &Scan{Jas: JasScanners{EnableSast: &enabled}}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.True(t, *scan.Jas.EnableSast)
+
+	SetEnvAndAssert(t, map[string]string{ExcludePatternsEnv: "third_party/;*.pb.go"})
+	scan = &Scan{}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.Equal(t, []string{"third_party/", "*.pb.go"}, scan.ExcludePatterns)
+
+	// The applicability of the CVEs requires the contextual analysis
+	disabled := false
+	scan = &Scan{FailOnApplicableOnly: true, Jas: JasScanners{EnableApplicability: &disabled}}
+	assert.ErrorContains(t, scan.setDefaultsIfNeeded(), "while the applicability scanner is disabled")
+}
+
+func TestSetReportingDefaultsIfNeeded(t *testing.T) {
+	git := &Git{ShowSections: []string{"IaC", "sast"}}
+	assert.NoError(t, git.setReportingDefaultsIfNeeded())
+	assert.Equal(t, []string{"iac", "sast"}, git.ShowSections)
+
+	git = &Git{ShowSections: []string{"vulnerabilities", "containers"}}
+	assert.ErrorContains(t, git.setReportingDefaultsIfNeeded(), `the comment section "containers" is unknown`)
+
+	git = &Git{MaxRowsPerTable: -1}
+	assert.ErrorContains(t, git.setReportingDefaultsIfNeeded(), "must not be negative")
+
+	SetEnvAndAssert(t, map[string]string{MessagesFileEnv: filepath.Join("..", "testdata", "messages", "custom", "messages-de.yml")})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	git = &Git{}
+	assert.NoError(t, git.setReportingDefaultsIfNeeded())
+	assert.Equal(t, "Kritisch", git.Messages.Severities["Critical"])
+
+	git = &Git{MessagesFile: "frogbot-messages.yml"}
+	assert.ErrorContains(t, git.setReportingDefaultsIfNeeded(), "failed to read the messages file")
+
+	// The Code Insights reports are published on Bitbucket Server only
+	git = &Git{GitProvider: vcsutils.BitbucketServer, CodeInsights: true}
+	assert.NoError(t, git.setReportingDefaultsIfNeeded())
+	git = &Git{GitProvider: vcsutils.GitHub, CodeInsights: true}
+	assert.ErrorContains(t, git.setReportingDefaultsIfNeeded(), "the Code Insights reports are only supported for Bitbucket Server")
+}
+
+func TestJFrogPlatformGetServerDetails(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{
+		"BU1_JF_ACCESS_TOKEN": "token",
+		"BU2_JF_USER":         "user",
+		"BU2_JF_PASSWORD":     "password",
+		JfrogHomeDirEnv:       t.TempDir(),
+	})
+	defer func() {
+		assert.NoError(t, os.Unsetenv("BU1_JF_ACCESS_TOKEN"))
+		assert.NoError(t, os.Unsetenv("BU2_JF_USER"))
+		assert.NoError(t, os.Unsetenv("BU2_JF_PASSWORD"))
+		assert.NoError(t, os.Unsetenv(JfrogHomeDirEnv))
+	}()
+	testCases := []struct {
+		name           string
+		platform       JFrogPlatform
+		expectedServer *config.ServerDetails
+		expectedError  string
+	}{
+		{
+			name:     "No instance configured",
+			platform: JFrogPlatform{Watches: []string{"watch"}},
+		},
+		{
+			name:           "URL with access token",
+			platform:       JFrogPlatform{Url: "https://bu1.jfrog.io/", AccessTokenEnv: "BU1_JF_ACCESS_TOKEN"},
+			expectedServer: &config.ServerDetails{Url: "https://bu1.jfrog.io/", XrayUrl: "https://bu1.jfrog.io/xray/", ArtifactoryUrl: "https://bu1.jfrog.io/artifactory/", AccessToken: "token"},
+		},
+		{
+			name:           "URL with user and password",
+			platform:       JFrogPlatform{Url: "https://bu2.jfrog.io", UserEnv: "BU2_JF_USER", PasswordEnv: "BU2_JF_PASSWORD"},
+			expectedServer: &config.ServerDetails{Url: "https://bu2.jfrog.io/", XrayUrl: "https://bu2.jfrog.io/xray/", ArtifactoryUrl: "https://bu2.jfrog.io/artifactory/", User: "user", Password: "password"},
+		},
+		{
+			name:          "URL without credentials",
+			platform:      JFrogPlatform{Url: "https://bu3.jfrog.io", AccessTokenEnv: "BU3_JF_ACCESS_TOKEN"},
+			expectedError: "the credentials of the JFrog platform https://bu3.jfrog.io are missing",
+		},
+		{
+			name:          "Unknown server ID",
+			platform:      JFrogPlatform{ServerId: "bu4"},
+			expectedError: "failed to get the details of the 'bu4' JFrog CLI server",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, err := tc.platform.getServerDetails()
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedServer, server)
+		})
+	}
+}
+
+func TestFixCvesEnv(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{FixCvesEnv: "cve-2021-44228, log4j-core@2.14.1"})
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	scan := &Scan{}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.Equal(t, []string{"CVE-2021-44228", "log4j-core@2.14.1"}, scan.TargetCves)
+	assert.True(t, scan.TargetCvesFixOnly)
+
+	// The target CVEs of the configuration override the environment variable
+	scan = &Scan{TargetCves: []string{"CVE-2021-45046"}}
+	assert.NoError(t, scan.setDefaultsIfNeeded())
+	assert.Equal(t, []string{"CVE-2021-45046"}, scan.TargetCves)
+	assert.False(t, scan.TargetCvesFixOnly)
+
+	SetEnvAndAssert(t, map[string]string{TargetCvesEnv: "CVE-2021-45046"})
+	assert.ErrorContains(t, (&Scan{}).setDefaultsIfNeeded(), "the JF_TARGET_CVES and JF_FIX_CVES environment variables can't be set together")
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jfrog/frogbot/v2/utils/policy"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The path of the policy file in the repository
+const PolicyFilePath = frogbotConfigDir + "/policy.yml"
+
+// Returns the blocking rules of the policy file of the repository, or nil if the repository has no policy file.
+// The file is read from the target branch of the pull request, so a pull request can't change the rules that it is checked by.
+func GetPullRequestPolicy(repo *Repository, client vcsclient.VcsClient) (*policy.Policy, error) {
+	target := repo.PullRequestDetails.Target
+	content, statusCode, err := client.DownloadFileFromRepo(context.Background(), target.Owner, target.Repository, target.Name, PolicyFilePath)
+	if statusCode == http.StatusNotFound {
+		log.Debug(fmt.Sprintf("The %s file wasn't found in <%s/%s/%s>", PolicyFilePath, target.Owner, target.Repository, target.Name))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't download the %s file from <%s/%s/%s>: %s", PolicyFilePath, target.Owner, target.Repository, target.Name, err.Error())
+	}
+	repoPolicy, err := policy.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the %s file: %s", PolicyFilePath, err.Error())
+	}
+	log.Info(fmt.Sprintf("The blocking rules of the %s file of the target branch are enforced", PolicyFilePath))
+	return repoPolicy, nil
+}
@@ -0,0 +1,266 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/registries"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+)
+
+// The names of the rules, as they appear in the policy file
+const (
+	MaxSeverityRule          = "maxSeverity"
+	BannedLicensesRule       = "bannedLicenses"
+	DeniedPackagesRule       = "deniedPackages"
+	MaxDependencyAgeDaysRule = "maxDependencyAgeDays"
+)
+
+// Registry looks up the package versions of a public registry
+type Registry interface {
+	// Returns the time the package version was released, or a zero time if the version doesn't exist in the registry
+	GetReleaseTime(packageName, version string) (time.Time, error)
+}
+
+// Policy holds the blocking rules of a repository, which are defined in a policy file in the repository.
+// The rules are enforced by Frogbot itself, so teams without permissions to manage Xray policies and watches can still block pull requests.
+type Policy struct {
+	// Findings with a higher severity break the rule
+	MaxSeverity string `yaml:"maxSeverity,omitempty"`
+	// License keys, such as 'GPL-3.0' or 'AGPL-3.0'
+	BannedLicenses []string `yaml:"bannedLicenses,omitempty"`
+	// Package names, or name prefixes that end with '*', such as 'event-stream' or 'com.example.legacy*'
+	DeniedPackages []string `yaml:"deniedPackages,omitempty"`
+	// Dependency versions that were released more days ago break the rule
+	MaxDependencyAgeDays int `yaml:"maxDependencyAgeDays,omitempty"`
+	// Maps the package types of the dependencies to the public registries that their release times are looked up in
+	registries map[string]Registry
+	// Caches the release times, as the same dependency is usually used by several projects
+	releaseTimes map[string]time.Time
+	now          func() time.Time
+}
+
+// Parses the content of a policy file, and validates its rules
+func Parse(content []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.Unmarshal(content, policy); err != nil {
+		return nil, fmt.Errorf("the policy file isn't a valid YAML file: %s", err.Error())
+	}
+	if policy.MaxSeverity != "" {
+		severity, err := severityutils.ParseSeverity(policy.MaxSeverity, false)
+		if err != nil {
+			return nil, fmt.Errorf("the %s rule of the policy file is invalid: %s", MaxSeverityRule, err.Error())
+		}
+		policy.MaxSeverity = severity.String()
+	}
+	if policy.MaxDependencyAgeDays < 0 {
+		return nil, fmt.Errorf("the %s rule of the policy file must not be negative, provided: %d", MaxDependencyAgeDaysRule, policy.MaxDependencyAgeDays)
+	}
+	policy.registries = map[string]Registry{
+		"npm":    &registries.NpmRegistry{},
+		"python": &registries.PypiRegistry{},
+		"maven":  &registries.MavenRegistry{},
+		"nuget":  &registries.NugetRegistry{},
+		"go":     &registries.GoProxy{},
+	}
+	policy.releaseTimes = map[string]time.Time{}
+	policy.now = time.Now
+	return policy, nil
+}
+
+// Returns true if the rules are evaluated against the dependencies of the scanned projects, so the scan should collect their licenses
+func (p *Policy) RequiresLicenses() bool {
+	return p != nil && (len(p.BannedLicenses) > 0 || len(p.DeniedPackages) > 0 || p.MaxDependencyAgeDays > 0)
+}
+
+// Returns the findings and dependencies of the issues collection that break the rules.
+// The dependencies are taken from the licenses and the SCA issues of the collection.
+func (p *Policy) Evaluate(issuesCollection *issues.ScansIssuesCollection, includeSecrets bool) (violations []issues.PolicyRuleViolation) {
+	if p == nil || issuesCollection == nil {
+		return
+	}
+	violations = append(violations, p.evaluateMaxSeverity(issuesCollection, includeSecrets)...)
+	for _, dependency := range getDependencies(issuesCollection) {
+		violations = append(violations, p.evaluateDependency(dependency)...)
+	}
+	if len(violations) > 0 {
+		log.Info(fmt.Sprintf("Found %d violations of the blocking rules of the policy file", len(violations)))
+	}
+	return
+}
+
+func (p *Policy) evaluateMaxSeverity(issuesCollection *issues.ScansIssuesCollection, includeSecrets bool) (violations []issues.PolicyRuleViolation) {
+	if p.MaxSeverity == "" {
+		return
+	}
+	isAboveMaxSeverity := p.isAboveMaxSeverity
+	reason := func(severity string) string {
+		return fmt.Sprintf("The severity %s is higher than %s", severity, p.MaxSeverity)
+	}
+	for _, rows := range [][]formats.VulnerabilityOrViolationRow{issuesCollection.ScaVulnerabilities, issuesCollection.ScaViolations} {
+		for _, row := range rows {
+			if isAboveMaxSeverity(row.Severity) {
+				subject := fmt.Sprintf("%s in %s:%s", getScaIssueId(row), row.ImpactedDependencyName, row.ImpactedDependencyVersion)
+				violations = append(violations, issues.PolicyRuleViolation{Rule: MaxSeverityRule, Subject: subject, Reason: reason(row.Severity)})
+			}
+		}
+	}
+	sourceCodeRows := [][]formats.SourceCodeRow{issuesCollection.IacVulnerabilities, issuesCollection.IacViolations, issuesCollection.SastVulnerabilities, issuesCollection.SastViolations}
+	if includeSecrets {
+		sourceCodeRows = append(sourceCodeRows, issuesCollection.SecretsVulnerabilities, issuesCollection.SecretsViolations)
+	}
+	for _, rows := range sourceCodeRows {
+		for _, row := range rows {
+			if isAboveMaxSeverity(row.Severity) {
+				subject := fmt.Sprintf("%s in %s:%d", row.ScannerShortDescription, row.File, row.StartLine)
+				violations = append(violations, issues.PolicyRuleViolation{Rule: MaxSeverityRule, Subject: subject, Reason: reason(row.Severity)})
+			}
+		}
+	}
+	return
+}
+
+func (p *Policy) isAboveMaxSeverity(severity string) bool {
+	return p.MaxSeverity != "" && severityutils.CompareSeverity(severityutils.GetSeverity(severity), severityutils.Severity(p.MaxSeverity)) > 0
+}
+
+// Returns true if the SCA issue breaks a blocking rule, by its severity or by its dependency.
+// The licenses are the licenses of the dependencies of the scan, which the rules of the dependency are evaluated with.
+func (p *Policy) BlocksScaIssue(issue formats.VulnerabilityOrViolationRow, licenses []formats.LicenseRow) bool {
+	if p == nil {
+		return false
+	}
+	if p.isAboveMaxSeverity(issue.Severity) {
+		return true
+	}
+	var dependencyLicenses []formats.LicenseRow
+	for _, license := range licenses {
+		if license.ImpactedDependencyType == issue.ImpactedDependencyType && license.ImpactedDependencyName == issue.ImpactedDependencyName && license.ImpactedDependencyVersion == issue.ImpactedDependencyVersion {
+			dependencyLicenses = append(dependencyLicenses, license)
+		}
+	}
+	for _, dependency := range getDependencies(&issues.ScansIssuesCollection{ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{issue}, Licenses: dependencyLicenses}) {
+		if len(p.evaluateDependency(dependency)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the Secrets, IaC or SAST issue breaks a blocking rule by its severity
+func (p *Policy) BlocksSourceCodeIssue(issue formats.SourceCodeRow) bool {
+	return p != nil && p.isAboveMaxSeverity(issue.Severity)
+}
+
+func getScaIssueId(row formats.VulnerabilityOrViolationRow) string {
+	var cves []string
+	for _, cve := range row.Cves {
+		if cve.Id != "" {
+			cves = append(cves, cve.Id)
+		}
+	}
+	if len(cves) > 0 {
+		return strings.Join(cves, ", ")
+	}
+	return row.IssueId
+}
+
+type dependency struct {
+	packageType string
+	name        string
+	version     string
+	licenses    []string
+}
+
+func (d dependency) String() string {
+	return d.name + ":" + d.version
+}
+
+// Returns the unique dependencies of the licenses and of the SCA issues, with their licenses
+func getDependencies(issuesCollection *issues.ScansIssuesCollection) (dependencies []*dependency) {
+	dependenciesById := map[string]*dependency{}
+	getDependency := func(details formats.ImpactedDependencyDetails) *dependency {
+		id := details.ImpactedDependencyType + "://" + details.ImpactedDependencyName + ":" + details.ImpactedDependencyVersion
+		if existing, exists := dependenciesById[id]; exists {
+			return existing
+		}
+		added := &dependency{packageType: details.ImpactedDependencyType, name: details.ImpactedDependencyName, version: details.ImpactedDependencyVersion}
+		dependenciesById[id] = added
+		dependencies = append(dependencies, added)
+		return added
+	}
+	for _, license := range issuesCollection.Licenses {
+		licensed := getDependency(license.ImpactedDependencyDetails)
+		licensed.licenses = append(licensed.licenses, license.LicenseKey)
+	}
+	for _, rows := range [][]formats.VulnerabilityOrViolationRow{issuesCollection.ScaVulnerabilities, issuesCollection.ScaViolations} {
+		for _, row := range rows {
+			getDependency(row.ImpactedDependencyDetails)
+		}
+	}
+	return
+}
+
+func (p *Policy) evaluateDependency(dependency *dependency) (violations []issues.PolicyRuleViolation) {
+	bannedLicenses := datastructures.MakeSet[string]()
+	for _, license := range dependency.licenses {
+		for _, bannedLicense := range p.BannedLicenses {
+			if strings.EqualFold(license, bannedLicense) && !bannedLicenses.Exists(license) {
+				bannedLicenses.Add(license)
+				violations = append(violations, issues.PolicyRuleViolation{Rule: BannedLicensesRule, Subject: dependency.String(), Reason: fmt.Sprintf("The %s license is banned", license)})
+			}
+		}
+	}
+	if deniedPackage := getMatchingPattern(dependency.name, p.DeniedPackages); deniedPackage != "" {
+		violations = append(violations, issues.PolicyRuleViolation{Rule: DeniedPackagesRule, Subject: dependency.String(), Reason: fmt.Sprintf("The package is denied by '%s'", deniedPackage)})
+	}
+	if p.MaxDependencyAgeDays > 0 {
+		if ageDays, found := p.getAgeDays(dependency); found && ageDays > p.MaxDependencyAgeDays {
+			violations = append(violations, issues.PolicyRuleViolation{Rule: MaxDependencyAgeDaysRule, Subject: dependency.String(), Reason: fmt.Sprintf("The version was released %d days ago, more than %d days ago", ageDays, p.MaxDependencyAgeDays)})
+		}
+	}
+	return
+}
+
+// Returns the pattern that matches the package name, or an empty string if no pattern matches it
+func getMatchingPattern(packageName string, patterns []string) string {
+	for _, pattern := range patterns {
+		if prefix, isPrefix := strings.CutSuffix(pattern, "*"); isPrefix {
+			if strings.HasPrefix(strings.ToLower(packageName), strings.ToLower(prefix)) {
+				return pattern
+			}
+		} else if strings.EqualFold(packageName, pattern) {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// Returns the number of days since the dependency version was released.
+// Dependencies whose release time can't be looked up are skipped.
+func (p *Policy) getAgeDays(dependency *dependency) (ageDays int, found bool) {
+	registry := p.registries[strings.ToLower(dependency.packageType)]
+	if registry == nil || dependency.version == "" {
+		return
+	}
+	cacheKey := strings.ToLower(dependency.packageType) + "://" + dependency.String()
+	releaseTime, exists := p.releaseTimes[cacheKey]
+	if !exists {
+		var err error
+		if releaseTime, err = registry.GetReleaseTime(dependency.name, dependency.version); err != nil {
+			log.Warn(fmt.Sprintf("Couldn't check the release time of %s: %s", dependency.String(), err.Error()))
+			return
+		}
+		p.releaseTimes[cacheKey] = releaseTime
+	}
+	if releaseTime.IsZero() {
+		return
+	}
+	return int(p.now().Sub(releaseTime).Hours() / 24), true
+}
@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/registries"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expected      *Policy
+		expectedError bool
+	}{
+		{
+			name:     "All rules",
+			content:  "maxSeverity: medium\nbannedLicenses: [GPL-3.0, AGPL-3.0]\ndeniedPackages: [event-stream, com.example.legacy*]\nmaxDependencyAgeDays: 365\n",
+			expected: &Policy{MaxSeverity: "Medium", BannedLicenses: []string{"GPL-3.0", "AGPL-3.0"}, DeniedPackages: []string{"event-stream", "com.example.legacy*"}, MaxDependencyAgeDays: 365},
+		},
+		{name: "Empty file", content: "", expected: &Policy{}},
+		{name: "Invalid severity", content: "maxSeverity: urgent", expectedError: true},
+		{name: "Negative age", content: "maxDependencyAgeDays: -1", expectedError: true},
+		{name: "Invalid YAML", content: "bannedLicenses: GPL-3.0: AGPL-3.0", expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := Parse([]byte(tc.content))
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected.MaxSeverity, policy.MaxSeverity)
+			assert.Equal(t, tc.expected.BannedLicenses, policy.BannedLicenses)
+			assert.Equal(t, tc.expected.DeniedPackages, policy.DeniedPackages)
+			assert.Equal(t, tc.expected.MaxDependencyAgeDays, policy.MaxDependencyAgeDays)
+		})
+	}
+}
+
+func TestRequiresLicenses(t *testing.T) {
+	var nilPolicy *Policy
+	assert.False(t, nilPolicy.RequiresLicenses())
+	assert.False(t, (&Policy{MaxSeverity: "High"}).RequiresLicenses())
+	assert.True(t, (&Policy{BannedLicenses: []string{"GPL-3.0"}}).RequiresLicenses())
+	assert.True(t, (&Policy{DeniedPackages: []string{"event-stream"}}).RequiresLicenses())
+	assert.True(t, (&Policy{MaxDependencyAgeDays: 30}).RequiresLicenses())
+}
+
+func TestEvaluate(t *testing.T) {
+	requestsCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestsCount++
+		var response string
+		switch r.URL.EscapedPath() {
+		case "/npm/lodash":
+			response = `{"time":{"4.17.20":"2020-08-13T16:53:54.152Z","4.17.21":"2021-02-20T15:42:16.891Z"}}`
+		case "/npm/@mycompany%2Fui":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	policy, err := Parse([]byte("maxSeverity: Medium\nbannedLicenses: [gpl-3.0]\ndeniedPackages: [event-stream, com.example.legacy*]\nmaxDependencyAgeDays: 365"))
+	require.NoError(t, err)
+	policy.registries = map[string]Registry{"npm": &registries.NpmRegistry{Url: server.URL + "/npm"}}
+	policy.now = func() time.Time { return time.Date(2021, 8, 20, 0, 0, 0, 0, time.UTC) }
+
+	npmDependency := func(name, version string) formats.ImpactedDependencyDetails {
+		return formats.ImpactedDependencyDetails{ImpactedDependencyType: "npm", ImpactedDependencyName: name, ImpactedDependencyVersion: version}
+	}
+	vulnerableNpmDependency := func(name, version, severity string) formats.ImpactedDependencyDetails {
+		details := npmDependency(name, version)
+		details.SeverityDetails = formats.SeverityDetails{Severity: severity}
+		return details
+	}
+	issuesCollection := &issues.ScansIssuesCollection{
+		ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{
+			{ImpactedDependencyDetails: vulnerableNpmDependency("lodash", "4.17.20", "High"), Cves: []formats.CveRow{{Id: "CVE-2021-23337"}}},
+			// The same dependency is looked up once
+			{ImpactedDependencyDetails: vulnerableNpmDependency("lodash", "4.17.20", "Low"), IssueId: "XRAY-1"},
+			{ImpactedDependencyDetails: vulnerableNpmDependency("lodash", "4.17.21", "Medium"), IssueId: "XRAY-2"},
+		},
+		Licenses: []formats.LicenseRow{
+			{ImpactedDependencyDetails: npmDependency("gpl-lib", "1.0.0"), LicenseKey: "GPL-3.0"},
+			{ImpactedDependencyDetails: npmDependency("event-stream", "3.3.6"), LicenseKey: "MIT"},
+			{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyType: "Maven", ImpactedDependencyName: "com.example.legacy.tools:core", ImpactedDependencyVersion: "1.0"}, LicenseKey: "MIT"},
+			// The registry can't be checked
+			{ImpactedDependencyDetails: npmDependency("@mycompany/ui", "2.0.0"), LicenseKey: "MIT"},
+		},
+		SastVulnerabilities:    []formats.SourceCodeRow{{ScannerInfo: formats.ScannerInfo{ScannerShortDescription: "SQL Injection"}, Location: formats.Location{File: "app.js", StartLine: 12}, SeverityDetails: formats.SeverityDetails{Severity: "Critical"}}},
+		SecretsVulnerabilities: []formats.SourceCodeRow{{ScannerInfo: formats.ScannerInfo{ScannerShortDescription: "Token"}, Location: formats.Location{File: "config.yml", StartLine: 3}, SeverityDetails: formats.SeverityDetails{Severity: "High"}}},
+	}
+	expected := []issues.PolicyRuleViolation{
+		{Rule: MaxSeverityRule, Subject: "CVE-2021-23337 in lodash:4.17.20", Reason: "The severity High is higher than Medium"},
+		{Rule: MaxSeverityRule, Subject: "SQL Injection in app.js:12", Reason: "The severity Critical is higher than Medium"},
+		{Rule: BannedLicensesRule, Subject: "gpl-lib:1.0.0", Reason: "The GPL-3.0 license is banned"},
+		{Rule: DeniedPackagesRule, Subject: "event-stream:3.3.6", Reason: "The package is denied by 'event-stream'"},
+		{Rule: DeniedPackagesRule, Subject: "com.example.legacy.tools:core:1.0", Reason: "The package is denied by 'com.example.legacy*'"},
+		{Rule: MaxDependencyAgeDaysRule, Subject: "lodash:4.17.20", Reason: "The version was released 371 days ago, more than 365 days ago"},
+	}
+	assert.Equal(t, expected, policy.Evaluate(issuesCollection, false))
+	// The npm registry is called for each dependency once: lodash 4.17.20 and 4.17.21, gpl-lib, event-stream and @mycompany/ui
+	assert.Equal(t, 5, requestsCount)
+
+	// Secrets are evaluated only if they are included in the scan results
+	withSecrets := policy.Evaluate(issuesCollection, true)
+	assert.Contains(t, withSecrets, issues.PolicyRuleViolation{Rule: MaxSeverityRule, Subject: "Token in config.yml:3", Reason: "The severity High is higher than Medium"})
+	// The release times are cached, except for the registry that failed
+	assert.Equal(t, 6, requestsCount)
+}
+
+func TestEvaluateWithoutPolicy(t *testing.T) {
+	var policy *Policy
+	assert.Empty(t, policy.Evaluate(&issues.ScansIssuesCollection{ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}}}}}, true))
+	policy, err := Parse([]byte("maxSeverity: Low"))
+	require.NoError(t, err)
+	assert.Empty(t, policy.Evaluate(nil, true))
+}
+
+func TestBlocksIssue(t *testing.T) {
+	policy := &Policy{MaxSeverity: "High", BannedLicenses: []string{"GPL-3.0"}, DeniedPackages: []string{"event-stream"}}
+	lodash := formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "High"}, ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"}}
+	log4j := formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}, ImpactedDependencyName: "log4j-core", ImpactedDependencyVersion: "2.14.1"}}
+	eventStream := formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Low"}, ImpactedDependencyName: "event-stream", ImpactedDependencyVersion: "3.3.6"}}
+	licenses := []formats.LicenseRow{{LicenseKey: "GPL-3.0", ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "readline", ImpactedDependencyVersion: "1.0.0"}}}
+	readline := formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Low"}, ImpactedDependencyName: "readline", ImpactedDependencyVersion: "1.0.0"}}
+
+	assert.False(t, policy.BlocksScaIssue(lodash, licenses))
+	assert.True(t, policy.BlocksScaIssue(log4j, licenses))
+	assert.True(t, policy.BlocksScaIssue(eventStream, licenses))
+	assert.True(t, policy.BlocksScaIssue(readline, licenses))
+	assert.False(t, policy.BlocksSourceCodeIssue(formats.SourceCodeRow{SeverityDetails: formats.SeverityDetails{Severity: "Medium"}}))
+	assert.True(t, policy.BlocksSourceCodeIssue(formats.SourceCodeRow{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}}))
+
+	var nilPolicy *Policy
+	assert.False(t, nilPolicy.BlocksScaIssue(log4j, licenses))
+	assert.False(t, nilPolicy.BlocksSourceCodeIssue(formats.SourceCodeRow{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}}))
+}
+
+func TestGetMatchingPattern(t *testing.T) {
+	patterns := []string{"event-stream", "@mycompany/*", "com.example.legacy*"}
+	testCases := []struct {
+		packageName     string
+		expectedPattern string
+	}{
+		{packageName: "event-stream", expectedPattern: "event-stream"},
+		{packageName: "Event-Stream", expectedPattern: "event-stream"},
+		{packageName: "event-stream-extra"},
+		{packageName: "@mycompany/lib", expectedPattern: "@mycompany/*"},
+		{packageName: "com.example.legacy.tools:core", expectedPattern: "com.example.legacy*"},
+		{packageName: "com.example:core"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.packageName, func(t *testing.T) {
+			assert.Equal(t, tc.expectedPattern, getMatchingPattern(tc.packageName, patterns))
+		})
+	}
+}
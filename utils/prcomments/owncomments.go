@@ -0,0 +1,164 @@
+package prcomments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/azurepullrequests"
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/datastructures"
+)
+
+// Returns the IDs of the regular comments of the pull request that the user of the Git token added.
+// The Git clients don't return the authors of the comments, so the comments of Frogbot can't be told apart from comments that copy their hidden markers.
+// The IDs are the IDs of the comments that the Git clients list, which are the thread IDs in Azure Repos.
+func ListOwnCommentIds(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName string, pullRequestId int) (*datastructures.Set[int64], error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	repositoryUrl := client.RepositoryUrl(repoOwner, repoName)
+	switch provider {
+	case vcsutils.GitHub:
+		return listGitHubOwnCommentIds(client, fmt.Sprintf("%s/issues/%d/comments", repositoryUrl, pullRequestId))
+	case vcsutils.GitLab:
+		return listGitLabOwnCommentIds(client, fmt.Sprintf("%s/merge_requests/%d/notes", repositoryUrl, pullRequestId))
+	case vcsutils.BitbucketServer:
+		return listBitbucketServerOwnCommentIds(client, fmt.Sprintf("%s/pull-requests/%d/activities", repositoryUrl, pullRequestId), vcsInfo.Username)
+	case vcsutils.BitbucketCloud:
+		return listBitbucketCloudOwnCommentIds(client, fmt.Sprintf("%s/pullrequests/%d/comments", repositoryUrl, pullRequestId))
+	case vcsutils.AzureRepos:
+		return listAzureReposOwnCommentIds(azurepullrequests.NewClient(vcsInfo, repoName), pullRequestId)
+	default:
+		return nil, fmt.Errorf("listing the authors of pull request comments isn't supported for %s", provider.String())
+	}
+}
+
+// The tokens of GitHub Apps, including the token of GitHub Actions, aren't allowed to get their user.
+// Their comments are added by the bot user of the app, which other users can't add comments as.
+func listGitHubOwnCommentIds(client *vcsapi.Client, commentsUrl string) (*datastructures.Set[int64], error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := client.Get(client.Url("/user"), &user); err != nil && !vcsapi.IsForbidden(err) {
+		return nil, err
+	}
+	type gitHubComment struct {
+		Id   int64 `json:"id"`
+		User struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"user"`
+	}
+	comments, err := vcsapi.List[gitHubComment](client, commentsUrl, vcsapi.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	ownCommentIds := datastructures.MakeSet[int64]()
+	for _, comment := range comments {
+		if (user.Login != "" && comment.User.Login == user.Login) || (user.Login == "" && comment.User.Type == "Bot") {
+			ownCommentIds.Add(comment.Id)
+		}
+	}
+	return ownCommentIds, nil
+}
+
+func listGitLabOwnCommentIds(client *vcsapi.Client, notesUrl string) (*datastructures.Set[int64], error) {
+	var user struct {
+		Id int64 `json:"id"`
+	}
+	if err := client.Get(client.Url("/user"), &user); err != nil {
+		return nil, err
+	}
+	type gitLabNote struct {
+		Id     int64 `json:"id"`
+		Author struct {
+			Id int64 `json:"id"`
+		} `json:"author"`
+	}
+	notes, err := vcsapi.List[gitLabNote](client, notesUrl, vcsapi.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	ownCommentIds := datastructures.MakeSet[int64]()
+	for _, note := range notes {
+		if note.Author.Id == user.Id {
+			ownCommentIds.Add(note.Id)
+		}
+	}
+	return ownCommentIds, nil
+}
+
+// Bitbucket Server has no API to get the user of a token, so the comments are compared with the configured username
+func listBitbucketServerOwnCommentIds(client *vcsapi.Client, activitiesUrl, username string) (*datastructures.Set[int64], error) {
+	if username == "" {
+		return nil, errors.New("the username of the Git token isn't configured")
+	}
+	type bitbucketServerActivity struct {
+		Action  string `json:"action"`
+		Comment struct {
+			Id     int64 `json:"id"`
+			Author struct {
+				Name string `json:"name"`
+				Slug string `json:"slug"`
+			} `json:"author"`
+		} `json:"comment"`
+	}
+	activities, err := vcsapi.List[bitbucketServerActivity](client, activitiesUrl, vcsapi.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	ownCommentIds := datastructures.MakeSet[int64]()
+	for _, activity := range activities {
+		author := activity.Comment.Author
+		if activity.Action == "COMMENTED" && (strings.EqualFold(author.Name, username) || strings.EqualFold(author.Slug, username)) {
+			ownCommentIds.Add(activity.Comment.Id)
+		}
+	}
+	return ownCommentIds, nil
+}
+
+func listBitbucketCloudOwnCommentIds(client *vcsapi.Client, commentsUrl string) (*datastructures.Set[int64], error) {
+	var user struct {
+		Uuid string `json:"uuid"`
+	}
+	if err := client.Get(client.Url("/user"), &user); err != nil {
+		return nil, err
+	}
+	type bitbucketCloudComment struct {
+		Id   int64 `json:"id"`
+		User struct {
+			Uuid string `json:"uuid"`
+		} `json:"user"`
+	}
+	comments, err := vcsapi.List[bitbucketCloudComment](client, commentsUrl, vcsapi.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	ownCommentIds := datastructures.MakeSet[int64]()
+	for _, comment := range comments {
+		if comment.User.Uuid == user.Uuid {
+			ownCommentIds.Add(comment.Id)
+		}
+	}
+	return ownCommentIds, nil
+}
+
+// The regular comments of Azure Repos pull requests are comment threads, which belong to the author of their first comment
+func listAzureReposOwnCommentIds(client *azurepullrequests.Client, pullRequestId int) (*datastructures.Set[int64], error) {
+	userId, err := client.GetAuthenticatedUserId()
+	if err != nil {
+		return nil, err
+	}
+	threads, err := client.ListThreads(pullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	ownCommentIds := datastructures.MakeSet[int64]()
+	for _, thread := range threads {
+		if len(thread.Comments) > 0 && strings.EqualFold(thread.Comments[0].Author.Id, userId) {
+			ownCommentIds.Add(thread.Id)
+		}
+	}
+	return ownCommentIds, nil
+}
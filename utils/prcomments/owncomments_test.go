@@ -0,0 +1,99 @@
+package prcomments
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOwnCommentIds(t *testing.T) {
+	testCases := []struct {
+		name        string
+		provider    vcsutils.VcsProvider
+		username    string
+		responses   map[string]string
+		expectedIds []int64
+	}{
+		{
+			name:     "GitHub",
+			provider: vcsutils.GitHub,
+			responses: map[string]string{
+				"/user":                                  `{"login":"frogbot-user"}`,
+				"/repos/jfrog/frogbot/issues/3/comments": `[{"id":1,"user":{"login":"frogbot-user","type":"User"}},{"id":2,"user":{"login":"octocat","type":"User"}},{"id":3,"user":{"login":"dependabot[bot]","type":"Bot"}}]`,
+			},
+			expectedIds: []int64{1},
+		},
+		{
+			name:     "GitHub App",
+			provider: vcsutils.GitHub,
+			responses: map[string]string{
+				"/repos/jfrog/frogbot/issues/3/comments": `[{"id":1,"user":{"login":"github-actions[bot]","type":"Bot"}},{"id":2,"user":{"login":"octocat","type":"User"}}]`,
+			},
+			expectedIds: []int64{1},
+		},
+		{
+			name:     "GitLab",
+			provider: vcsutils.GitLab,
+			responses: map[string]string{
+				"/user": `{"id":7}`,
+				"/projects/jfrog/frogbot/merge_requests/3/notes": `[{"id":1,"author":{"id":7}},{"id":2,"author":{"id":8}}]`,
+			},
+			expectedIds: []int64{1},
+		},
+		{
+			name:     "Bitbucket Server",
+			provider: vcsutils.BitbucketServer,
+			username: "frogbot",
+			responses: map[string]string{
+				"/rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/3/activities": `{"isLastPage":true,"values":[{"action":"COMMENTED","comment":{"id":1,"author":{"name":"Frogbot","slug":"frogbot"}}},{"action":"COMMENTED","comment":{"id":2,"author":{"name":"john","slug":"john"}}},{"action":"APPROVED"}]}`,
+			},
+			expectedIds: []int64{1},
+		},
+		{
+			name:     "Bitbucket Cloud",
+			provider: vcsutils.BitbucketCloud,
+			responses: map[string]string{
+				"/user": `{"uuid":"{frogbot}"}`,
+				"/repositories/jfrog/frogbot/pullrequests/3/comments": `{"values":[{"id":1,"user":{"uuid":"{frogbot}"}},{"id":2,"user":{"uuid":"{john}"}}]}`,
+			},
+			expectedIds: []int64{1},
+		},
+		{
+			name:     "Azure Repos",
+			provider: vcsutils.AzureRepos,
+			responses: map[string]string{
+				"/_apis/connectionData": `{"authenticatedUser":{"id":"frogbot-id"}}`,
+				"/project/_apis/git/repositories/frogbot/pullrequests/3/threads": `{"value":[{"id":1,"comments":[{"author":{"id":"frogbot-id"}},{"author":{"id":"john-id"}}]},{"id":2,"comments":[{"author":{"id":"john-id"}}]}]}`,
+			},
+			expectedIds: []int64{1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response, exists := tc.responses[r.URL.Path]
+				if !exists {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				_, err := w.Write([]byte(response))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			vcsInfo := vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token", Username: tc.username, Project: "project"}
+			ownCommentIds, err := ListOwnCommentIds(tc.provider, vcsInfo, "jfrog", "frogbot", 3)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIds, ownCommentIds.ToSlice())
+		})
+	}
+}
+
+func TestListOwnCommentIdsWithoutBitbucketServerUsername(t *testing.T) {
+	_, err := ListOwnCommentIds(vcsutils.BitbucketServer, vcsclient.VcsInfo{APIEndpoint: "https://bitbucket.example.com", Token: "token"}, "jfrog", "frogbot", 3)
+	assert.ErrorContains(t, err, "username")
+}
@@ -0,0 +1,91 @@
+package prcomments
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jfrog/frogbot/v2/utils/azurepullrequests"
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// Editor edits the regular comments of pull requests. The Git clients can't edit comments, so they're edited with the API of the Git provider.
+type Editor interface {
+	EditComment(pullRequestId int, commentId int64, content string) error
+}
+
+// Returns the comments editor of the Git provider
+func NewEditor(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName string) (Editor, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	repositoryUrl := client.RepositoryUrl(repoOwner, repoName)
+	switch provider {
+	case vcsutils.GitHub:
+		return &gitHubEditor{client: client, commentsUrl: repositoryUrl + "/issues/comments"}, nil
+	case vcsutils.GitLab:
+		return &gitLabEditor{client: client, mergeRequestsUrl: repositoryUrl + "/merge_requests"}, nil
+	case vcsutils.BitbucketServer:
+		return &bitbucketServerEditor{client: client, pullRequestsUrl: repositoryUrl + "/pull-requests"}, nil
+	case vcsutils.BitbucketCloud:
+		return &bitbucketCloudEditor{client: client, pullRequestsUrl: repositoryUrl + "/pullrequests"}, nil
+	case vcsutils.AzureRepos:
+		return &azureReposEditor{client: azurepullrequests.NewClient(vcsInfo, repoName)}, nil
+	default:
+		return nil, fmt.Errorf("editing pull request comments isn't supported for %s", provider.String())
+	}
+}
+
+type gitHubEditor struct {
+	client      *vcsapi.Client
+	commentsUrl string
+}
+
+// The regular comments of GitHub pull requests are the comments of their issues
+func (ge *gitHubEditor) EditComment(_ int, commentId int64, content string) error {
+	return ge.client.Send(http.MethodPatch, fmt.Sprintf("%s/%d", ge.commentsUrl, commentId), map[string]string{"body": content}, nil)
+}
+
+type gitLabEditor struct {
+	client           *vcsapi.Client
+	mergeRequestsUrl string
+}
+
+func (ge *gitLabEditor) EditComment(pullRequestId int, commentId int64, content string) error {
+	return ge.client.Send(http.MethodPut, fmt.Sprintf("%s/%d/notes/%d", ge.mergeRequestsUrl, pullRequestId, commentId), map[string]string{"body": content}, nil)
+}
+
+type bitbucketServerEditor struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+// Bitbucket Server requires the current version of the comment, to reject the edits of outdated comments
+func (be *bitbucketServerEditor) EditComment(pullRequestId int, commentId int64, content string) error {
+	commentUrl := fmt.Sprintf("%s/%d/comments/%d", be.pullRequestsUrl, pullRequestId, commentId)
+	var comment struct {
+		Version int `json:"version"`
+	}
+	if err := be.client.Get(commentUrl, &comment); err != nil {
+		return err
+	}
+	return be.client.Send(http.MethodPut, commentUrl, map[string]any{"text": content, "version": comment.Version}, nil)
+}
+
+type bitbucketCloudEditor struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (be *bitbucketCloudEditor) EditComment(pullRequestId int, commentId int64, content string) error {
+	body := map[string]map[string]string{"content": {"raw": content}}
+	return be.client.Send(http.MethodPut, fmt.Sprintf("%s/%d/comments/%d", be.pullRequestsUrl, pullRequestId, commentId), body, nil)
+}
+
+// The regular comments of Azure Repos pull requests are comment threads, whose first comment is the comment of Frogbot
+type azureReposEditor struct {
+	client *azurepullrequests.Client
+}
+
+func (ae *azureReposEditor) EditComment(pullRequestId int, threadId int64, content string) error {
+	return ae.client.EditThreadComment(pullRequestId, threadId, content)
+}
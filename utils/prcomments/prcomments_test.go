@@ -0,0 +1,74 @@
+package prcomments
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditComment(t *testing.T) {
+	testCases := []struct {
+		provider       vcsutils.VcsProvider
+		expectedMethod string
+		expectedPath   string
+		expectedBody   map[string]any
+	}{
+		{provider: vcsutils.GitHub, expectedMethod: http.MethodPatch, expectedPath: "/repos/jfrog/frogbot/issues/comments/12", expectedBody: map[string]any{"body": "collapsed"}},
+		{provider: vcsutils.GitLab, expectedMethod: http.MethodPut, expectedPath: "/projects/jfrog/frogbot/merge_requests/3/notes/12", expectedBody: map[string]any{"body": "collapsed"}},
+		{provider: vcsutils.BitbucketServer, expectedMethod: http.MethodPut, expectedPath: "/rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/3/comments/12", expectedBody: map[string]any{"text": "collapsed", "version": float64(4)}},
+		{provider: vcsutils.BitbucketCloud, expectedMethod: http.MethodPut, expectedPath: "/repositories/jfrog/frogbot/pullrequests/3/comments/12", expectedBody: map[string]any{"content": map[string]any{"raw": "collapsed"}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.provider.String(), func(t *testing.T) {
+			var body map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedPath, r.URL.Path)
+				// Bitbucket Server requires the current version of the comment
+				if r.Method == http.MethodGet {
+					_, err := w.Write([]byte(`{"id":12,"version":4,"text":"summary"}`))
+					assert.NoError(t, err)
+					return
+				}
+				assert.Equal(t, tc.expectedMethod, r.Method)
+				content, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.NoError(t, json.Unmarshal(content, &body))
+			}))
+			defer server.Close()
+			editor, err := NewEditor(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "jfrog", "frogbot")
+			require.NoError(t, err)
+			require.NoError(t, editor.EditComment(3, 12, "collapsed"))
+			assert.Equal(t, tc.expectedBody, body)
+		})
+	}
+}
+
+func TestEditCommentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	editor, err := NewEditor(vcsutils.GitHub, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "jfrog", "frogbot")
+	require.NoError(t, err)
+	assert.ErrorContains(t, editor.EditComment(3, 12, "collapsed"), "responded with status 403")
+}
+
+func TestNewEditor(t *testing.T) {
+	editor, err := NewEditor(vcsutils.GitHub, vcsclient.VcsInfo{Token: "token"}, "jfrog", "frogbot")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/repos/jfrog/frogbot/issues/comments", editor.(*gitHubEditor).commentsUrl)
+
+	editor, err = NewEditor(vcsutils.BitbucketServer, vcsclient.VcsInfo{APIEndpoint: "https://bitbucket.example.com/rest", Username: "frogbot", Token: "token"}, "SEC", "frogbot")
+	require.NoError(t, err)
+	assert.Equal(t, "https://bitbucket.example.com/rest/api/1.0/projects/SEC/repos/frogbot/pull-requests", editor.(*bitbucketServerEditor).pullRequestsUrl)
+
+	_, err = NewEditor(vcsutils.VcsProvider(100), vcsclient.VcsInfo{}, "jfrog", "frogbot")
+	assert.ErrorContains(t, err, "editing pull request comments isn't supported")
+}
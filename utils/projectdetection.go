@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The detection of the projects is opt-in, as scanning the detected projects separately changes the results of the repositories that were scanned recursively
+const defaultProjectDetectionDepth = 0
+
+// The build files that define the subprojects of a Gradle build
+var gradleSettingsFiles = []string{"settings.gradle", "settings.gradle.kts"}
+
+// The directories of the installed and vendored dependencies are never detected as projects
+var projectDetectionSkippedDirs = []string{"node_modules", "vendor"}
+
+// WithDetectedWorkingDirs returns the project with the projects that are detected in the repository as its working directories,
+// when the project has no working directories and scans the repository recursively.
+// The repository root remains the first working directory, so its descriptors and the source code outside the detected projects are still scanned.
+// If no projects are detected below the repository root, the project is returned as is.
+func (p Project) WithDetectedWorkingDirs(repositoryDir string) (Project, error) {
+	if !p.IsRecursiveScan || p.DetectionDepth == 0 {
+		return p, nil
+	}
+	detectedDirs, err := DetectProjectDirs(repositoryDir, p.DetectionDepth, p.PathExclusions)
+	if err != nil {
+		return p, err
+	}
+	workingDirs := []string{RootDir}
+	for _, dir := range detectedDirs {
+		if dir != RootDir {
+			workingDirs = append(workingDirs, dir)
+		}
+	}
+	if len(workingDirs) == 1 {
+		log.Debug("No projects were detected below the repository root, so the repository is scanned recursively")
+		return p, nil
+	}
+	p.WorkingDirs = workingDirs
+	p.IsRecursiveScan = false
+	return p, nil
+}
+
+// DetectProjectDirs returns the sorted directories of the projects of the repository, relative to its root, up to the max depth below the root.
+// The layout of the detected projects and their technologies is logged.
+func DetectProjectDirs(repositoryDir string, maxDepth int, exclusions []string) ([]string, error) {
+	excludePattern := fspatterns.PrepareExcludePathPattern(exclusions, clientutils.WildCardPattern, true)
+	techToDirs, err := techutils.DetectTechnologiesDescriptors(repositoryDir, true, []string{}, map[techutils.Technology][]string{}, excludePattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect the projects of the repository: %s", err.Error())
+	}
+	dirToTechs := map[string][]string{}
+	for tech, dirs := range techToDirs {
+		if tech == techutils.NoTech {
+			continue
+		}
+		for dir := range dirs {
+			relativeDir, err := filepath.Rel(repositoryDir, dir)
+			if err != nil || !isDetectableProjectDir(relativeDir, maxDepth) {
+				continue
+			}
+			// The modules of a Maven project are already detected with the project, while the subprojects of a Gradle build are detected by themselves
+			if tech == techutils.Gradle {
+				relativeDir = getGradleBuildDir(repositoryDir, relativeDir)
+			}
+			if slices.Contains(dirToTechs[filepath.ToSlash(relativeDir)], tech.ToFormal()) {
+				continue
+			}
+			dirToTechs[filepath.ToSlash(relativeDir)] = append(dirToTechs[filepath.ToSlash(relativeDir)], tech.ToFormal())
+		}
+	}
+	var projectDirs, layout []string
+	for dir := range dirToTechs {
+		projectDirs = append(projectDirs, dir)
+	}
+	sort.Strings(projectDirs)
+	for _, dir := range projectDirs {
+		sort.Strings(dirToTechs[dir])
+		layout = append(layout, fmt.Sprintf("%s (%s)", dir, strings.Join(dirToTechs[dir], ", ")))
+	}
+	if len(layout) > 0 {
+		log.Info(fmt.Sprintf("Detected %d projects up to depth %d:\n%s", len(layout), maxDepth, strings.Join(layout, "\n")))
+	}
+	return projectDirs, nil
+}
+
+// Returns the root directory of the Gradle build of the project directory, which is the outermost directory with a settings file
+// from the project directory up to the repository root. Scanning a subproject without its build fails, so the build is scanned as a whole.
+func getGradleBuildDir(repositoryDir, relativeDir string) string {
+	buildDir := relativeDir
+	for dir := relativeDir; ; dir = filepath.Dir(dir) {
+		for _, settingsFile := range gradleSettingsFiles {
+			if exists, _ := fileutils.IsFileExists(filepath.Join(repositoryDir, dir, settingsFile), false); exists {
+				buildDir = dir
+			}
+		}
+		if dir == RootDir {
+			return buildDir
+		}
+	}
+}
+
+// Returns true if the directory isn't deeper than the max depth, and isn't in a directory of installed or vendored dependencies
+func isDetectableProjectDir(relativeDir string, maxDepth int) bool {
+	if relativeDir == RootDir {
+		return true
+	}
+	parts := strings.Split(filepath.ToSlash(relativeDir), "/")
+	if len(parts) > maxDepth || parts[0] == ".." {
+		return false
+	}
+	for _, part := range parts {
+		if slices.Contains(projectDetectionSkippedDirs, part) {
+			return false
+		}
+	}
+	return true
+}
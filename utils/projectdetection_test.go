@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createProjectsLayout(t *testing.T, descriptors ...string) string {
+	repositoryDir := t.TempDir()
+	for _, descriptor := range descriptors {
+		path := filepath.Join(repositoryDir, filepath.FromSlash(descriptor))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0644))
+	}
+	return repositoryDir
+}
+
+func TestDetectProjectDirs(t *testing.T) {
+	repositoryDir := createProjectsLayout(t,
+		"go.mod",
+		"web/package.json",
+		"services/api/pom.xml",
+		"services/api/core/pom.xml",
+		"a/b/c/d/go.mod",
+		"node_modules/lodash/package.json",
+		"web/vendor/lib/go.mod",
+		"docs/README.md",
+		"gradle/settings.gradle.kts",
+		"gradle/app/build.gradle.kts",
+		"gradle/lib/core/build.gradle.kts",
+	)
+	projectDirs, err := DetectProjectDirs(repositoryDir, 3, nil)
+	require.NoError(t, err)
+	// The modules of a Maven project and the subprojects of a Gradle build are scanned with their build
+	assert.Equal(t, []string{RootDir, "gradle", "services/api", "web"}, projectDirs)
+
+	projectDirs, err = DetectProjectDirs(repositoryDir, 4, []string{"*web*", "*gradle*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{RootDir, "a/b/c/d", "services/api"}, projectDirs)
+}
+
+func TestWithDetectedWorkingDirs(t *testing.T) {
+	repositoryDir := createProjectsLayout(t, "web/package.json", "api/go.mod")
+	project := Project{WorkingDirs: []string{RootDir}, IsRecursiveScan: true, DetectionDepth: 3}
+	detected, err := project.WithDetectedWorkingDirs(repositoryDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{RootDir, "api", "web"}, detected.WorkingDirs)
+	assert.False(t, detected.IsRecursiveScan)
+	// The configured project isn't modified
+	assert.Equal(t, []string{RootDir}, project.WorkingDirs)
+
+	// A repository with a single project at its root is scanned recursively
+	detected, err = project.WithDetectedWorkingDirs(createProjectsLayout(t, "package.json"))
+	require.NoError(t, err)
+	assert.Equal(t, project, detected)
+
+	// The detection is disabled, or the working directories are configured
+	for _, configured := range []Project{
+		{WorkingDirs: []string{RootDir}, IsRecursiveScan: true},
+		{WorkingDirs: []string{"web"}, DetectionDepth: 3},
+	} {
+		detected, err = configured.WithDetectedWorkingDirs(repositoryDir)
+		require.NoError(t, err)
+		assert.Equal(t, configured, detected)
+	}
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	// The name of the CA bundle in the certificates directory of the JFrog home
+	caBundleFileName = "frogbot-ca-bundle.pem"
+	// The time limit of each request of the connectivity check
+	connectivityCheckTimeout = 30 * time.Second
+)
+
+var supportedProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// The absolute path of the CA bundle, which is installed in each JFrog home that Frogbot builds. Empty if no CA bundle is configured
+var caBundlePath string
+
+// Applies the proxy and the CA bundle of the environment variables to all the HTTP clients of the run.
+// It runs before any request is sent, including the exchange of the OIDC token, so every client is built with the proxy and the CA bundle.
+// The clients that Frogbot builds, including the Git provider clients, get their transport from the transport package.
+// The JFrog clients and the package managers that the scans run read the standard proxy environment variables,
+// and the JFrog clients trust the certificates of the certificates directory of the JFrog home. Until the server configuration file is built,
+// a temporary JFrog home with the CA bundle is used, so the returned function restores the JFrog home.
+func configureProxy() (restoreJfrogHome func() error, err error) {
+	restoreJfrogHome = func() error { return nil }
+	proxyUrl, noProxy, caBundle := getTrimmedEnv(ProxyUrlEnv), getTrimmedEnv(NoProxyEnv), getTrimmedEnv(CaBundlePathEnv)
+	if proxyUrl == "" && caBundle == "" {
+		return
+	}
+	proxyFunc, err := newProxyFunc(proxyUrl, noProxy)
+	if err != nil {
+		return
+	}
+	var rootCAs *x509.CertPool
+	if caBundle != "" {
+		if rootCAs, err = loadCaBundle(caBundle); err != nil {
+			return
+		}
+	}
+	transport.Configure(proxyFunc, rootCAs)
+	if proxyUrl != "" {
+		if err = setProxyEnv(proxyUrl, noProxy); err != nil {
+			return
+		}
+	}
+	if caBundle != "" {
+		if caBundlePath, err = filepath.Abs(caBundle); err != nil {
+			return
+		}
+		restoreJfrogHome, err = setTemporaryJfrogHome()
+	}
+	return
+}
+
+// Returns the proxy of the requests, which sends them through the proxy URL, except for the requests to the hosts of noProxy.
+// Returns nil if no proxy URL is provided, so the proxy of the standard environment variables is kept.
+func newProxyFunc(proxyUrl, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyUrl == "" {
+		return nil, nil
+	}
+	parsedUrl, err := url.Parse(proxyUrl)
+	if err != nil || !slices.Contains(supportedProxySchemes, parsedUrl.Scheme) || parsedUrl.Host == "" {
+		return nil, fmt.Errorf("the proxy URL of the %s environment variable is invalid. Expected a URL with one of the schemes: %v", ProxyUrlEnv, supportedProxySchemes)
+	}
+	proxyFunc := (&httpproxy.Config{HTTPProxy: proxyUrl, HTTPSProxy: proxyUrl, NoProxy: noProxy}).ProxyFunc()
+	log.Info("Sending the HTTP requests through the proxy:", parsedUrl.Redacted())
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// Returns the system certificates with the certificates of the CA bundle
+func loadCaBundle(caBundle string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(caBundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA bundle of the %s environment variable: %s", CaBundlePathEnv, err.Error())
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		log.Debug("Couldn't load the system certificates, only the certificates of the CA bundle are trusted:", err.Error())
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no PEM certificates were found in the CA bundle '%s'", caBundle)
+	}
+	return rootCAs, nil
+}
+
+// The proxy environment variables are read by the JFrog clients and by the package managers that the scans run
+func setProxyEnv(proxyUrl, noProxy string) error {
+	proxyEnv := map[string]string{"HTTP_PROXY": proxyUrl, "HTTPS_PROXY": proxyUrl}
+	if noProxy != "" {
+		proxyEnv["NO_PROXY"] = noProxy
+	}
+	for key, value := range proxyEnv {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sets a temporary JFrog home with the CA bundle, and returns a function that restores the previous JFrog home and removes the temporary one
+func setTemporaryJfrogHome() (restoreJfrogHome func() error, err error) {
+	restoreJfrogHome = func() error { return nil }
+	tempJfrogHomeDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	previousJfrogHomeDir := os.Getenv(JfrogHomeDirEnv)
+	restoreJfrogHome = func() error {
+		return errors.Join(os.Setenv(JfrogHomeDirEnv, previousJfrogHomeDir), fileutils.RemoveTempDir(tempJfrogHomeDir))
+	}
+	if err = os.Setenv(JfrogHomeDirEnv, tempJfrogHomeDir); err != nil {
+		return
+	}
+	err = installCaBundle(tempJfrogHomeDir)
+	return
+}
+
+// Copies the CA bundle, if configured, to the certificates directory of the JFrog home, which the JFrog clients trust
+func installCaBundle(jfrogHomeDir string) error {
+	if caBundlePath == "" {
+		return nil
+	}
+	content, err := os.ReadFile(caBundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to read the CA bundle '%s': %s", caBundlePath, err.Error())
+	}
+	certsDir := filepath.Join(jfrogHomeDir, coreutils.JfrogSecurityDirName, coreutils.JfrogCertsDirName)
+	if err = os.MkdirAll(certsDir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(certsDir, caBundleFileName), content, 0600)
+}
+
+// Checks that the JFrog platform and the Git provider are reachable with the configured proxy and CA bundle.
+// Any HTTP response means that the URL is reachable.
+func checkProxyConnectivity(urls ...string) error {
+	if getTrimmedEnv(ProxyUrlEnv) == "" && getTrimmedEnv(CaBundlePathEnv) == "" {
+		return nil
+	}
+	client := transport.NewClient(connectivityCheckTimeout)
+	for _, checkedUrl := range urls {
+		if checkedUrl == "" {
+			continue
+		}
+		log.Debug("Checking the connectivity to:", checkedUrl)
+		resp, err := client.Head(checkedUrl)
+		if err != nil {
+			return fmt.Errorf("the connectivity check with the configured proxy and CA bundle failed: %s", err.Error())
+		}
+		if err = resp.Body.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package prrouting
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// Router labels the pull requests of a repository, and routes them to their assignees and reviewers.
+// The Git clients don't expose the labels and the reviewers of pull requests, so they're set by the API of the Git provider.
+// The labels and the people that a Git provider doesn't support return an error.
+type Router interface {
+	AddLabels(pullRequestId int64, labels []string) error
+	AddAssignees(pullRequestId int64, assignees []string) error
+	// The team reviewers are the slugs of the teams on GitHub, and the IDs of the teams on Azure Repos
+	RequestReviewers(pullRequestId int64, reviewers, teamReviewers []string) error
+}
+
+// Returns the router of the Git provider
+func NewRouter(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName string) (Router, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	repositoryUrl := client.RepositoryUrl(repoOwner, repoName)
+	switch provider {
+	case vcsutils.GitHub:
+		return &gitHubRouter{client: client, repositoryUrl: repositoryUrl}, nil
+	case vcsutils.GitLab:
+		return &gitLabRouter{client: client, mergeRequestsUrl: repositoryUrl + "/merge_requests"}, nil
+	case vcsutils.BitbucketServer:
+		return &bitbucketServerRouter{client: client, pullRequestsUrl: repositoryUrl + "/pull-requests"}, nil
+	case vcsutils.BitbucketCloud:
+		return &bitbucketCloudRouter{client: client, pullRequestsUrl: repositoryUrl + "/pullrequests"}, nil
+	case vcsutils.AzureRepos:
+		return &azureReposRouter{client: client, pullRequestsUrl: repositoryUrl + "/pullRequests"}, nil
+	default:
+		return nil, fmt.Errorf("routing pull requests isn't supported for %s", provider.String())
+	}
+}
+
+func unsupportedErr(feature string, provider vcsutils.VcsProvider) error {
+	return fmt.Errorf("%s of pull requests aren't supported for %s", feature, provider.String())
+}
+
+type gitHubRouter struct {
+	client        *vcsapi.Client
+	repositoryUrl string
+}
+
+// The labels and the assignees of the pull requests are of their issues
+func (gr *gitHubRouter) AddLabels(pullRequestId int64, labels []string) error {
+	return gr.client.Send(http.MethodPost, fmt.Sprintf("%s/issues/%d/labels", gr.repositoryUrl, pullRequestId), map[string][]string{"labels": labels}, nil)
+}
+
+func (gr *gitHubRouter) AddAssignees(pullRequestId int64, assignees []string) error {
+	return gr.client.Send(http.MethodPost, fmt.Sprintf("%s/issues/%d/assignees", gr.repositoryUrl, pullRequestId), map[string][]string{"assignees": assignees}, nil)
+}
+
+func (gr *gitHubRouter) RequestReviewers(pullRequestId int64, reviewers, teamReviewers []string) error {
+	body := map[string][]string{"reviewers": reviewers, "team_reviewers": teamReviewers}
+	return gr.client.Send(http.MethodPost, fmt.Sprintf("%s/pulls/%d/requested_reviewers", gr.repositoryUrl, pullRequestId), body, nil)
+}
+
+type gitLabRouter struct {
+	client           *vcsapi.Client
+	mergeRequestsUrl string
+}
+
+func (gl *gitLabRouter) AddLabels(pullRequestId int64, labels []string) error {
+	return gl.updateMergeRequest(pullRequestId, map[string]any{"add_labels": strings.Join(labels, ",")})
+}
+
+func (gl *gitLabRouter) AddAssignees(pullRequestId int64, assignees []string) error {
+	assigneeIds, err := gl.getUserIds(assignees)
+	if err != nil {
+		return err
+	}
+	return gl.updateMergeRequest(pullRequestId, map[string]any{"assignee_ids": assigneeIds})
+}
+
+// GitLab has no team reviewers, so only the users are requested
+func (gl *gitLabRouter) RequestReviewers(pullRequestId int64, reviewers, teamReviewers []string) error {
+	if len(teamReviewers) > 0 {
+		return unsupportedErr("Team reviewers", vcsutils.GitLab)
+	}
+	reviewerIds, err := gl.getUserIds(reviewers)
+	if err != nil {
+		return err
+	}
+	return gl.updateMergeRequest(pullRequestId, map[string]any{"reviewer_ids": reviewerIds})
+}
+
+func (gl *gitLabRouter) updateMergeRequest(pullRequestId int64, body map[string]any) error {
+	return gl.client.Send(http.MethodPut, fmt.Sprintf("%s/%d", gl.mergeRequestsUrl, pullRequestId), body, nil)
+}
+
+// The merge requests are assigned to the IDs of the users, which are looked up by their usernames
+func (gl *gitLabRouter) getUserIds(usernames []string) (userIds []int64, err error) {
+	for _, username := range usernames {
+		var users []struct {
+			Id int64 `json:"id"`
+		}
+		if err = gl.client.Get(gl.client.Url("/users")+"?username="+url.QueryEscape(username), &users); err != nil {
+			return
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("the GitLab user '%s' wasn't found", username)
+		}
+		userIds = append(userIds, users[0].Id)
+	}
+	return
+}
+
+type bitbucketServerRouter struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (bs *bitbucketServerRouter) AddLabels(int64, []string) error {
+	return unsupportedErr("Labels", vcsutils.BitbucketServer)
+}
+
+func (bs *bitbucketServerRouter) AddAssignees(int64, []string) error {
+	return unsupportedErr("Assignees", vcsutils.BitbucketServer)
+}
+
+// The reviewers are added as participants of the pull request, by their usernames
+func (bs *bitbucketServerRouter) RequestReviewers(pullRequestId int64, reviewers, teamReviewers []string) (err error) {
+	if len(teamReviewers) > 0 {
+		return unsupportedErr("Team reviewers", vcsutils.BitbucketServer)
+	}
+	for _, reviewer := range reviewers {
+		participant := map[string]any{"user": map[string]string{"name": reviewer}, "role": "REVIEWER"}
+		if e := bs.client.Send(http.MethodPost, fmt.Sprintf("%s/%d/participants", bs.pullRequestsUrl, pullRequestId), participant, nil); e != nil {
+			err = errors.Join(err, fmt.Errorf("failed to add the reviewer '%s': %s", reviewer, e.Error()))
+		}
+	}
+	return
+}
+
+type bitbucketCloudRouter struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (bc *bitbucketCloudRouter) AddLabels(int64, []string) error {
+	return unsupportedErr("Labels", vcsutils.BitbucketCloud)
+}
+
+func (bc *bitbucketCloudRouter) AddAssignees(int64, []string) error {
+	return unsupportedErr("Assignees", vcsutils.BitbucketCloud)
+}
+
+// Bitbucket Cloud replaces the reviewers of the pull request, so the current reviewers are kept.
+// The reviewers are identified by their account IDs, since Bitbucket Cloud doesn't accept usernames.
+func (bc *bitbucketCloudRouter) RequestReviewers(pullRequestId int64, reviewers, teamReviewers []string) error {
+	if len(teamReviewers) > 0 {
+		return unsupportedErr("Team reviewers", vcsutils.BitbucketCloud)
+	}
+	type reviewer struct {
+		AccountId string `json:"account_id"`
+	}
+	var pullRequest struct {
+		Title     string     `json:"title"`
+		Reviewers []reviewer `json:"reviewers"`
+	}
+	pullRequestUrl := fmt.Sprintf("%s/%d", bc.pullRequestsUrl, pullRequestId)
+	if err := bc.client.Get(pullRequestUrl, &pullRequest); err != nil {
+		return err
+	}
+	for _, accountId := range reviewers {
+		pullRequest.Reviewers = append(pullRequest.Reviewers, reviewer{AccountId: accountId})
+	}
+	// The title is required by the updates of the pull requests
+	return bc.client.Send(http.MethodPut, pullRequestUrl, pullRequest, nil)
+}
+
+type azureReposRouter struct {
+	client          *vcsapi.Client
+	pullRequestsUrl string
+}
+
+func (ar *azureReposRouter) AddLabels(pullRequestId int64, labels []string) (err error) {
+	labelsUrl := fmt.Sprintf("%s/%d/labels?api-version=%s", ar.pullRequestsUrl, pullRequestId, vcsapi.AzureApiVersion)
+	for _, label := range labels {
+		if e := ar.client.Send(http.MethodPost, labelsUrl, map[string]string{"name": label}, nil); e != nil {
+			err = errors.Join(err, fmt.Errorf("failed to add the label '%s': %s", label, e.Error()))
+		}
+	}
+	return
+}
+
+func (ar *azureReposRouter) AddAssignees(int64, []string) error {
+	return unsupportedErr("Assignees", vcsutils.AzureRepos)
+}
+
+// Azure Repos identifies the users and the teams by the IDs of their identities, and adds both as reviewers without a vote
+func (ar *azureReposRouter) RequestReviewers(pullRequestId int64, reviewers, teamReviewers []string) (err error) {
+	for _, reviewerId := range append(append([]string{}, reviewers...), teamReviewers...) {
+		reviewerUrl := fmt.Sprintf("%s/%d/reviewers/%s?api-version=%s", ar.pullRequestsUrl, pullRequestId, url.PathEscape(reviewerId), vcsapi.AzureApiVersion)
+		if e := ar.client.Send(http.MethodPut, reviewerUrl, map[string]int{"vote": 0}, nil); e != nil {
+			err = errors.Join(err, fmt.Errorf("failed to add the reviewer '%s': %s", reviewerId, e.Error()))
+		}
+	}
+	return
+}
@@ -0,0 +1,119 @@
+package prrouting
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter(t *testing.T) {
+	testCases := []struct {
+		name             string
+		provider         vcsutils.VcsProvider
+		teamReviewers    []string
+		responses        map[string]string
+		expectedRequests []string
+		expectedErrors   []string
+	}{
+		{
+			name:          "GitHub",
+			provider:      vcsutils.GitHub,
+			teamReviewers: []string{"security"},
+			expectedRequests: []string{
+				`POST /repos/jfrog/frogbot/issues/7/labels {"labels":["security","dependencies"]}`,
+				`POST /repos/jfrog/frogbot/issues/7/assignees {"assignees":["octocat"]}`,
+				`POST /repos/jfrog/frogbot/pulls/7/requested_reviewers {"reviewers":["octocat"],"team_reviewers":["security"]}`,
+			},
+		},
+		{
+			name:          "GitLab",
+			provider:      vcsutils.GitLab,
+			teamReviewers: []string{"security"},
+			responses:     map[string]string{"GET /users": `[{"id":42}]`},
+			expectedRequests: []string{
+				`PUT /projects/jfrog/frogbot/merge_requests/7 {"add_labels":"security,dependencies"}`,
+				`GET /users `,
+				`PUT /projects/jfrog/frogbot/merge_requests/7 {"assignee_ids":[42]}`,
+			},
+			expectedErrors: []string{"", "", "Team reviewers of pull requests aren't supported for GitLab"},
+		},
+		{
+			name:     "GitLab reviewers",
+			provider: vcsutils.GitLab,
+			responses: map[string]string{
+				"GET /users": `[{"id":42}]`,
+			},
+			expectedRequests: []string{
+				`PUT /projects/jfrog/frogbot/merge_requests/7 {"add_labels":"security,dependencies"}`,
+				`GET /users `,
+				`PUT /projects/jfrog/frogbot/merge_requests/7 {"assignee_ids":[42]}`,
+				`GET /users `,
+				`PUT /projects/jfrog/frogbot/merge_requests/7 {"reviewer_ids":[42]}`,
+			},
+		},
+		{
+			name:     "Bitbucket Server",
+			provider: vcsutils.BitbucketServer,
+			expectedRequests: []string{
+				`POST /rest/api/1.0/projects/jfrog/repos/frogbot/pull-requests/7/participants {"role":"REVIEWER","user":{"name":"octocat"}}`,
+			},
+			expectedErrors: []string{"Labels of pull requests aren't supported for Bitbucket Server", "Assignees of pull requests aren't supported for Bitbucket Server", ""},
+		},
+		{
+			name:      "Bitbucket Cloud",
+			provider:  vcsutils.BitbucketCloud,
+			responses: map[string]string{"GET /repositories/jfrog/frogbot/pullrequests/7": `{"title":"Fix","reviewers":[{"account_id":"557058:1"}]}`},
+			expectedRequests: []string{
+				`GET /repositories/jfrog/frogbot/pullrequests/7 `,
+				`PUT /repositories/jfrog/frogbot/pullrequests/7 {"title":"Fix","reviewers":[{"account_id":"557058:1"},{"account_id":"octocat"}]}`,
+			},
+			expectedErrors: []string{"Labels of pull requests aren't supported for Bitbucket Cloud", "Assignees of pull requests aren't supported for Bitbucket Cloud", ""},
+		},
+		{
+			name:          "Azure Repos",
+			provider:      vcsutils.AzureRepos,
+			teamReviewers: []string{"security-id"},
+			expectedRequests: []string{
+				`POST /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7/labels {"name":"security"}`,
+				`POST /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7/labels {"name":"dependencies"}`,
+				`PUT /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7/reviewers/octocat {"vote":0}`,
+				`PUT /frogbot-project/_apis/git/repositories/frogbot/pullRequests/7/reviewers/security-id {"vote":0}`,
+			},
+			expectedErrors: []string{"", "Assignees of pull requests aren't supported for Azure Repos", ""},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+				_, err = w.Write([]byte(tc.responses[r.Method+" "+r.URL.Path]))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			router, err := NewRouter(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token", Project: "frogbot-project"}, "jfrog", "frogbot")
+			require.NoError(t, err)
+			errs := []error{
+				router.AddLabels(7, []string{"security", "dependencies"}),
+				router.AddAssignees(7, []string{"octocat"}),
+				router.RequestReviewers(7, []string{"octocat"}, tc.teamReviewers),
+			}
+			for i, err := range errs {
+				if len(tc.expectedErrors) == 0 || tc.expectedErrors[i] == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, tc.expectedErrors[i])
+				}
+			}
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The default number of remaining API requests, below which Frogbot pauses until the rate limit resets
+	defaultRateLimitThreshold = 100
+	// The default longest pause, after which the remaining work is left to the next run
+	defaultRateLimitMaxWait = 10 * time.Minute
+)
+
+// The rate limit headers of the Git providers. GitHub and Azure Repos use the 'X-' prefix, and GitLab doesn't.
+var (
+	rateLimitRemainingHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining"}
+	rateLimitResetHeaders     = []string{"X-RateLimit-Reset", "RateLimit-Reset"}
+)
+
+// Replaced in tests
+var sleep = time.Sleep
+
+// ErrRateLimitExhausted is returned when the rate limit of a Git provider resets later than Frogbot is allowed to wait
+type ErrRateLimitExhausted struct {
+	Host  string
+	Reset time.Time
+}
+
+func (e *ErrRateLimitExhausted) Error() string {
+	return fmt.Sprintf("the API rate limit of %s is about to be exhausted and resets at %s", e.Host, e.Reset.Format(time.RFC3339))
+}
+
+type rateLimitStatus struct {
+	remaining int
+	reset     time.Time
+}
+
+// RateLimitTracker keeps the latest rate limit status that each Git provider host reported in its responses
+type RateLimitTracker struct {
+	mutex    sync.Mutex
+	statuses map[string]rateLimitStatus
+}
+
+var rateLimitTracker = &RateLimitTracker{statuses: map[string]rateLimitStatus{}}
+
+// Records the rate limits of the responses of the Git providers
+type rateLimitTransport struct {
+	base http.RoundTripper
+}
+
+func (rt *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.base.RoundTrip(req)
+	if err == nil {
+		rateLimitTracker.record(req.URL.Host, resp)
+	}
+	return resp, err
+}
+
+func (rlt *RateLimitTracker) record(host string, resp *http.Response) {
+	status, ok := parseRateLimitStatus(resp)
+	if !ok {
+		return
+	}
+	rlt.mutex.Lock()
+	defer rlt.mutex.Unlock()
+	rlt.statuses[host] = status
+}
+
+func parseRateLimitStatus(resp *http.Response) (status rateLimitStatus, ok bool) {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// The request was rejected, so the rate limit is exhausted until the time the server asks to retry after
+		if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return rateLimitStatus{remaining: 0, reset: time.Now().Add(time.Duration(retryAfter) * time.Second)}, true
+		}
+	}
+	remaining, err := strconv.Atoi(getFirstHeader(resp.Header, rateLimitRemainingHeaders))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(getFirstHeader(resp.Header, rateLimitResetHeaders), 10, 64)
+	if err != nil {
+		return
+	}
+	return rateLimitStatus{remaining: remaining, reset: time.Unix(reset, 0)}, true
+}
+
+func getFirstHeader(header http.Header, keys []string) string {
+	for _, key := range keys {
+		if value := header.Get(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Waits until the Git providers have more remaining API requests than the threshold, by pausing until their rate limits reset.
+// Returns ErrRateLimitExhausted if a rate limit resets later than the max wait, so the remaining work can be left to the next run.
+func WaitForRateLimit(threshold int, maxWait time.Duration) error {
+	return rateLimitTracker.waitForRateLimit(threshold, maxWait)
+}
+
+func (rlt *RateLimitTracker) waitForRateLimit(threshold int, maxWait time.Duration) error {
+	rlt.mutex.Lock()
+	defer rlt.mutex.Unlock()
+	for host, status := range rlt.statuses {
+		if status.remaining >= threshold {
+			continue
+		}
+		wait := time.Until(status.reset)
+		if wait > maxWait {
+			return &ErrRateLimitExhausted{Host: host, Reset: status.reset}
+		}
+		if wait > 0 {
+			log.Info(fmt.Sprintf("%d API requests to %s remain before reaching the rate limit. Pausing for %s until it resets...", status.remaining, host, wait.Round(time.Second)))
+			sleep(wait)
+		}
+		// The status is recorded again on the next response
+		delete(rlt.statuses, host)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimitStatus(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	testCases := []struct {
+		name              string
+		statusCode        int
+		headers           map[string]string
+		expectedOk        bool
+		expectedRemaining int
+	}{
+		{name: "GitHub headers", statusCode: http.StatusOK, headers: map[string]string{"X-RateLimit-Remaining": "42", "X-RateLimit-Reset": strconv.FormatInt(reset, 10)}, expectedOk: true, expectedRemaining: 42},
+		{name: "GitLab headers", statusCode: http.StatusOK, headers: map[string]string{"RateLimit-Remaining": "7", "RateLimit-Reset": strconv.FormatInt(reset, 10)}, expectedOk: true, expectedRemaining: 7},
+		{name: "Too many requests", statusCode: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "60"}, expectedOk: true, expectedRemaining: 0},
+		{name: "No headers", statusCode: http.StatusOK, headers: map[string]string{}},
+		{name: "Missing reset", statusCode: http.StatusOK, headers: map[string]string{"X-RateLimit-Remaining": "42"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tc.statusCode, Header: http.Header{}}
+			for key, value := range tc.headers {
+				resp.Header.Set(key, value)
+			}
+			status, ok := parseRateLimitStatus(resp)
+			assert.Equal(t, tc.expectedOk, ok)
+			if tc.expectedOk {
+				assert.Equal(t, tc.expectedRemaining, status.remaining)
+				assert.True(t, status.reset.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "5")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	}))
+	defer server.Close()
+	defer func() {
+		rateLimitTracker = &RateLimitTracker{statuses: map[string]rateLimitStatus{}}
+	}()
+
+	client := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	status, exists := rateLimitTracker.statuses[server.Listener.Addr().String()]
+	require.True(t, exists)
+	assert.Equal(t, 5, status.remaining)
+	assert.Equal(t, reset, status.reset.Unix())
+}
+
+func TestWaitForRateLimit(t *testing.T) {
+	var slept time.Duration
+	sleep = func(d time.Duration) { slept += d }
+	defer func() {
+		sleep = time.Sleep
+	}()
+
+	// Above the threshold
+	tracker := &RateLimitTracker{statuses: map[string]rateLimitStatus{"api.github.com": {remaining: 500, reset: time.Now().Add(time.Hour)}}}
+	assert.NoError(t, tracker.waitForRateLimit(100, time.Minute))
+	assert.Zero(t, slept)
+	assert.Len(t, tracker.statuses, 1)
+
+	// Below the threshold, resets within the max wait
+	tracker.statuses["api.github.com"] = rateLimitStatus{remaining: 10, reset: time.Now().Add(30 * time.Second)}
+	assert.NoError(t, tracker.waitForRateLimit(100, time.Minute))
+	assert.Greater(t, slept, time.Duration(0))
+	assert.LessOrEqual(t, slept, 30*time.Second)
+	assert.Empty(t, tracker.statuses)
+
+	// Below the threshold, resets later than the max wait
+	slept = 0
+	tracker.statuses["api.github.com"] = rateLimitStatus{remaining: 10, reset: time.Now().Add(time.Hour)}
+	err := tracker.waitForRateLimit(100, time.Minute)
+	var rateLimitErr *ErrRateLimitExhausted
+	require.True(t, errors.As(err, &rateLimitErr))
+	assert.Equal(t, "api.github.com", rateLimitErr.Host)
+	assert.Zero(t, slept)
+
+	// A zero max wait stops without pausing
+	assert.Error(t, tracker.waitForRateLimit(100, 0))
+	assert.Zero(t, slept)
+}
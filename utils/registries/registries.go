@@ -0,0 +1,256 @@
+package registries
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The clients of the public package registries look up the packages anonymously.
+// A package or a version that doesn't exist in the registry isn't an error, and returns an empty result.
+
+type NpmRegistry struct {
+	// The URL of the registry API, the public npm registry by default
+	Url string
+}
+
+// The package document lists the tags and the release times of all the versions.
+// Scoped packages, such as '@mycompany/lib', are looked up with an escaped slash.
+type npmPackage struct {
+	DistTags struct {
+		Latest string `json:"latest"`
+	} `json:"dist-tags"`
+	Time map[string]time.Time `json:"time"`
+}
+
+func (nr *NpmRegistry) getPackage(packageName string) (npmPackage npmPackage, found bool, err error) {
+	found, err = getJson(getUrlOrDefault(nr.Url, "https://registry.npmjs.org")+"/"+url.PathEscape(packageName), &npmPackage)
+	return
+}
+
+func (nr *NpmRegistry) GetLatestVersion(packageName string) (string, error) {
+	npmPackage, found, err := nr.getPackage(packageName)
+	if !found || err != nil {
+		return "", err
+	}
+	return npmPackage.DistTags.Latest, nil
+}
+
+func (nr *NpmRegistry) GetReleaseTime(packageName, version string) (time.Time, error) {
+	npmPackage, found, err := nr.getPackage(packageName)
+	if !found || err != nil {
+		return time.Time{}, err
+	}
+	return npmPackage.Time[version], nil
+}
+
+// Checks if all the given versions of the package are published in the registry
+func (nr *NpmRegistry) HasVersions(packageName string, versions ...string) (bool, error) {
+	npmPackage, found, err := nr.getPackage(packageName)
+	if !found || err != nil {
+		return false, err
+	}
+	for _, version := range versions {
+		if _, published := npmPackage.Time[version]; !published {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (nr *NpmRegistry) GetPackageUrl(packageName string) string {
+	return "https://www.npmjs.com/package/" + packageName
+}
+
+type PypiRegistry struct {
+	// The URL of the registry API, PyPI by default
+	Url string
+}
+
+func (pr *PypiRegistry) GetLatestVersion(packageName string) (string, error) {
+	var response struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	found, err := getJson(fmt.Sprintf("%s/pypi/%s/json", getUrlOrDefault(pr.Url, "https://pypi.org"), url.PathEscape(packageName)), &response)
+	if !found || err != nil {
+		return "", err
+	}
+	return response.Info.Version, nil
+}
+
+// The release time of a version is the upload time of its earliest file
+func (pr *PypiRegistry) GetReleaseTime(packageName, version string) (releaseTime time.Time, err error) {
+	var response struct {
+		Urls []struct {
+			UploadTime time.Time `json:"upload_time_iso_8601"`
+		} `json:"urls"`
+	}
+	found, err := getJson(fmt.Sprintf("%s/pypi/%s/%s/json", getUrlOrDefault(pr.Url, "https://pypi.org"), url.PathEscape(packageName), url.PathEscape(version)), &response)
+	if !found || err != nil {
+		return
+	}
+	for _, file := range response.Urls {
+		if releaseTime.IsZero() || file.UploadTime.Before(releaseTime) {
+			releaseTime = file.UploadTime
+		}
+	}
+	return
+}
+
+func (pr *PypiRegistry) GetPackageUrl(packageName string) string {
+	return fmt.Sprintf("https://pypi.org/project/%s/", url.PathEscape(packageName))
+}
+
+type MavenRegistry struct {
+	// The URL of the Maven Central search API, the public one by default
+	Url string
+}
+
+type mavenSearchResponse struct {
+	Response struct {
+		Docs []struct {
+			LatestVersion string `json:"latestVersion"`
+			// Milliseconds since the epoch
+			Timestamp int64 `json:"timestamp"`
+		} `json:"docs"`
+	} `json:"response"`
+}
+
+// Maven packages are named 'groupId:artifactId'. Returns the first document that matches the query, or false if none matches.
+func (mr *MavenRegistry) search(query url.Values) (found bool, response mavenSearchResponse, err error) {
+	query.Set("rows", "1")
+	query.Set("wt", "json")
+	if found, err = getJson(getUrlOrDefault(mr.Url, "https://search.maven.org")+"/solrsearch/select?"+query.Encode(), &response); !found || err != nil {
+		return
+	}
+	return len(response.Response.Docs) > 0, response, nil
+}
+
+func (mr *MavenRegistry) GetLatestVersion(packageName string) (string, error) {
+	groupId, artifactId, found := strings.Cut(packageName, ":")
+	if !found {
+		return "", nil
+	}
+	found, response, err := mr.search(url.Values{"q": {fmt.Sprintf("g:%q AND a:%q", groupId, artifactId)}})
+	if !found || err != nil {
+		return "", err
+	}
+	return response.Response.Docs[0].LatestVersion, nil
+}
+
+// The versions are searched in the 'gav' core, which holds a document per version
+func (mr *MavenRegistry) GetReleaseTime(packageName, version string) (time.Time, error) {
+	groupId, artifactId, found := strings.Cut(packageName, ":")
+	if !found {
+		return time.Time{}, nil
+	}
+	found, response, err := mr.search(url.Values{"q": {fmt.Sprintf("g:%q AND a:%q AND v:%q", groupId, artifactId, version)}, "core": {"gav"}})
+	if !found || err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(response.Response.Docs[0].Timestamp), nil
+}
+
+func (mr *MavenRegistry) GetPackageUrl(packageName string) string {
+	groupId, artifactId, _ := strings.Cut(packageName, ":")
+	return fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s", url.PathEscape(groupId), url.PathEscape(artifactId))
+}
+
+type NugetRegistry struct {
+	// The URL of the NuGet API, the public NuGet gallery by default
+	Url string
+}
+
+// The versions are listed by the package content API in ascending order
+func (nr *NugetRegistry) GetLatestVersion(packageName string) (string, error) {
+	var response struct {
+		Versions []string `json:"versions"`
+	}
+	found, err := getJson(fmt.Sprintf("%s/v3-flatcontainer/%s/index.json", getUrlOrDefault(nr.Url, "https://api.nuget.org"), url.PathEscape(strings.ToLower(packageName))), &response)
+	if !found || err != nil || len(response.Versions) == 0 {
+		return "", err
+	}
+	return response.Versions[len(response.Versions)-1], nil
+}
+
+// The release time is the publish time of the version in the package metadata API
+func (nr *NugetRegistry) GetReleaseTime(packageName, version string) (time.Time, error) {
+	var response struct {
+		Published time.Time `json:"published"`
+	}
+	found, err := getJson(fmt.Sprintf("%s/v3/registration5-semver1/%s/%s.json", getUrlOrDefault(nr.Url, "https://api.nuget.org"), url.PathEscape(strings.ToLower(packageName)), url.PathEscape(strings.ToLower(version))), &response)
+	if !found || err != nil {
+		return time.Time{}, err
+	}
+	return response.Published, nil
+}
+
+func (nr *NugetRegistry) GetPackageUrl(packageName string) string {
+	return "https://www.nuget.org/packages/" + url.PathEscape(packageName)
+}
+
+type GoProxy struct {
+	// The URL of the module proxy, the public Go module proxy by default
+	Url string
+}
+
+func (gp *GoProxy) GetReleaseTime(packageName, version string) (time.Time, error) {
+	var response struct {
+		Time time.Time `json:"Time"`
+	}
+	found, err := getJson(fmt.Sprintf("%s/%s/@v/%s.info", getUrlOrDefault(gp.Url, "https://proxy.golang.org"), escapeModulePath(packageName), escapeModulePath(version)), &response)
+	if !found || err != nil {
+		return time.Time{}, err
+	}
+	return response.Time, nil
+}
+
+// The module proxy protocol replaces each upper case letter with an exclamation mark followed by the letter in lower case
+func escapeModulePath(path string) string {
+	var escaped strings.Builder
+	for _, char := range path {
+		if char >= 'A' && char <= 'Z' {
+			escaped.WriteRune('!')
+			char += 'a' - 'A'
+		}
+		escaped.WriteRune(char)
+	}
+	return escaped.String()
+}
+
+func getUrlOrDefault(registryUrl, defaultUrl string) string {
+	if registryUrl == "" {
+		return defaultUrl
+	}
+	return strings.TrimSuffix(registryUrl, "/")
+}
+
+// Sends an anonymous GET request and decodes the JSON response into the target. Returns false if the resource doesn't exist.
+func getJson(resourceUrl string, target any) (found bool, err error) {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return
+	}
+	log.Debug("Sending HTTP GET request to:", resourceUrl)
+	resp, body, _, err := client.SendGet(resourceUrl, true, httputils.HttpClientDetails{}, "")
+	if err != nil {
+		return
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, json.Unmarshal(body, target)
+	case http.StatusNotFound, http.StatusGone:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s responded with status %s", resourceUrl, resp.Status)
+	}
+}
@@ -0,0 +1,82 @@
+package registries
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReleaseTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response string
+		switch r.URL.EscapedPath() {
+		case "/pypi/pypi/requests/2.31.0/json":
+			response = `{"urls":[{"upload_time_iso_8601":"2023-05-22T15:12:44.175Z"},{"upload_time_iso_8601":"2023-05-22T15:12:42.313Z"}]}`
+		case "/maven/solrsearch/select":
+			assert.Equal(t, `g:"org.example" AND a:"core" AND v:"1.0.0"`, r.URL.Query().Get("q"))
+			response = `{"response":{"docs":[{"timestamp":1684768362313}]}}`
+		case "/nuget/v3/registration5-semver1/newtonsoft.json/13.0.1.json":
+			response = `{"published":"2021-03-22T20:10:49.497Z"}`
+		case "/go/github.com/!burnt!sushi/toml/@v/v1.3.2.info":
+			response = `{"Version":"v1.3.2","Time":"2023-06-08T06:42:59Z"}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		registry interface {
+			GetReleaseTime(packageName, version string) (time.Time, error)
+		}
+		packageName  string
+		version      string
+		expectedTime time.Time
+	}{
+		{name: "PyPI", registry: &PypiRegistry{Url: server.URL + "/pypi"}, packageName: "requests", version: "2.31.0", expectedTime: time.Date(2023, 5, 22, 15, 12, 42, 313000000, time.UTC)},
+		{name: "Maven", registry: &MavenRegistry{Url: server.URL + "/maven"}, packageName: "org.example:core", version: "1.0.0", expectedTime: time.UnixMilli(1684768362313)},
+		{name: "NuGet", registry: &NugetRegistry{Url: server.URL + "/nuget/"}, packageName: "Newtonsoft.Json", version: "13.0.1", expectedTime: time.Date(2021, 3, 22, 20, 10, 49, 497000000, time.UTC)},
+		{name: "Go", registry: &GoProxy{Url: server.URL + "/go"}, packageName: "github.com/BurntSushi/toml", version: "v1.3.2", expectedTime: time.Date(2023, 6, 8, 6, 42, 59, 0, time.UTC)},
+		{name: "Not found", registry: &NpmRegistry{Url: server.URL + "/npm"}, packageName: "missing", version: "1.0.0"},
+		{name: "Maven package without a group", registry: &MavenRegistry{Url: server.URL + "/maven"}, packageName: "core", version: "1.0.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releaseTime, err := tc.registry.GetReleaseTime(tc.packageName, tc.version)
+			assert.NoError(t, err)
+			assert.True(t, tc.expectedTime.Equal(releaseTime), "expected %s, got %s", tc.expectedTime, releaseTime)
+		})
+	}
+}
+
+func TestNpmRegistryHasVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/minimatch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(`{"time":{"3.0.4":"2017-05-07T18:03:44.985Z","3.0.5":"2022-02-15T16:34:48.123Z"}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	registry := &NpmRegistry{Url: server.URL}
+	hasVersions, err := registry.HasVersions("minimatch", "3.0.4", "3.0.5")
+	assert.NoError(t, err)
+	assert.True(t, hasVersions)
+	// A version that was published only to a private registry
+	hasVersions, err = registry.HasVersions("minimatch", "3.0.4", "3.0.5-internal")
+	assert.NoError(t, err)
+	assert.False(t, hasVersions)
+	// A private package
+	hasVersions, err = registry.HasVersions("@mycompany/ui", "1.0.0")
+	assert.NoError(t, err)
+	assert.False(t, hasVersions)
+}
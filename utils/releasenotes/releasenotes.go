@@ -0,0 +1,151 @@
+package releasenotes
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/registries"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Links that help reviewers assess the risk of upgrading a package version
+type Links struct {
+	// The page of the fixed version, with its release notes or changelog
+	ReleaseNotes string
+	// The changes between the current version and the fixed version
+	Diff string
+}
+
+func (l Links) IsEmpty() bool {
+	return l.ReleaseNotes == "" && l.Diff == ""
+}
+
+// Resolver generates the release information links of the packages of a technology, based on the public registry of the technology
+type Resolver interface {
+	Resolve(packageName, currentVersion, fixedVersion string) Links
+}
+
+// Returns the resolver of the given technology, or nil if the technology isn't supported.
+// The npm upgrades are linked to their diffs on npmdiff.dev only if npmDiffLinks is set.
+func GetResolver(tech techutils.Technology, npmDiffLinks bool) Resolver {
+	switch tech {
+	case techutils.Npm, techutils.Yarn, techutils.Pnpm:
+		resolver := &NpmResolver{}
+		if npmDiffLinks {
+			resolver.Registry = &registries.NpmRegistry{}
+		}
+		return resolver
+	case techutils.Pip, techutils.Pipenv, techutils.Poetry:
+		return &PypiResolver{}
+	case techutils.Maven, techutils.Gradle:
+		return &MavenResolver{}
+	case techutils.Go:
+		return &GoResolver{}
+	case techutils.Nuget, techutils.Dotnet:
+		return &NugetResolver{}
+	default:
+		return nil
+	}
+}
+
+// Returns the release information links of the given package upgrade, empty links if the technology isn't supported
+func Resolve(tech techutils.Technology, npmDiffLinks bool, packageName, currentVersion, fixedVersion string) Links {
+	resolver := GetResolver(tech, npmDiffLinks)
+	if resolver == nil || packageName == "" || fixedVersion == "" {
+		return Links{}
+	}
+	return resolver.Resolve(packageName, currentVersion, fixedVersion)
+}
+
+type NpmResolver struct {
+	// The public registry that the versions are looked up in before they're linked to npmdiff.dev, which shows only public packages.
+	// Without a registry, the upgrades aren't linked to their diffs.
+	Registry interface {
+		HasVersions(packageName string, versions ...string) (bool, error)
+	}
+}
+
+func (nr *NpmResolver) Resolve(packageName, currentVersion, fixedVersion string) (links Links) {
+	links.ReleaseNotes = fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", packageName, url.PathEscape(fixedVersion))
+	if currentVersion == "" || nr.Registry == nil {
+		return
+	}
+	// Private and internal packages aren't linked, so their names aren't sent to npmdiff.dev
+	public, err := nr.Registry.HasVersions(packageName, currentVersion, fixedVersion)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't check if %s is published in the public npm registry: %s", packageName, err.Error()))
+	}
+	if public {
+		links.Diff = fmt.Sprintf("https://npmdiff.dev/%s/%s/%s/", packageName, url.PathEscape(currentVersion), url.PathEscape(fixedVersion))
+	}
+	return
+}
+
+type PypiResolver struct{}
+
+func (pr *PypiResolver) Resolve(packageName, _, fixedVersion string) Links {
+	return Links{ReleaseNotes: fmt.Sprintf("https://pypi.org/project/%s/%s/", url.PathEscape(packageName), url.PathEscape(fixedVersion))}
+}
+
+type MavenResolver struct{}
+
+// Maven packages are named 'groupId:artifactId'
+func (mr *MavenResolver) Resolve(packageName, _, fixedVersion string) Links {
+	groupId, artifactId, found := strings.Cut(packageName, ":")
+	if !found {
+		return Links{}
+	}
+	return Links{ReleaseNotes: fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", url.PathEscape(groupId), url.PathEscape(artifactId), url.PathEscape(fixedVersion))}
+}
+
+type GoResolver struct{}
+
+// Modules hosted on GitHub also get a comparison between the tags of the current and the fixed versions
+func (gr *GoResolver) Resolve(packageName, currentVersion, fixedVersion string) (links Links) {
+	fixedVersion = toGoVersion(fixedVersion)
+	links.ReleaseNotes = fmt.Sprintf("https://pkg.go.dev/%s@%s", packageName, fixedVersion)
+	pathParts := strings.Split(packageName, "/")
+	if currentVersion == "" || len(pathParts) < 3 || pathParts[0] != "github.com" {
+		return
+	}
+	// Tags of modules in a subdirectory of the repository are prefixed with the subdirectory
+	subDirParts := pathParts[3:]
+	if len(subDirParts) > 0 && isMajorVersionSuffix(subDirParts[len(subDirParts)-1]) {
+		subDirParts = subDirParts[:len(subDirParts)-1]
+	}
+	tagPrefix := ""
+	if len(subDirParts) > 0 {
+		tagPrefix = strings.Join(subDirParts, "/") + "/"
+	}
+	links.Diff = fmt.Sprintf("https://github.com/%s/%s/compare/%s%s...%s%s", pathParts[1], pathParts[2], tagPrefix, toGoVersion(currentVersion), tagPrefix, fixedVersion)
+	return
+}
+
+type NugetResolver struct{}
+
+func (nr *NugetResolver) Resolve(packageName, _, fixedVersion string) Links {
+	return Links{ReleaseNotes: fmt.Sprintf("https://www.nuget.org/packages/%s/%s", url.PathEscape(packageName), url.PathEscape(fixedVersion))}
+}
+
+// Go module versions are semantic versions with a 'v' prefix
+func toGoVersion(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// Checks if the given module path element is a major version suffix, such as 'v2'
+func isMajorVersionSuffix(pathElement string) bool {
+	if len(pathElement) < 2 || pathElement[0] != 'v' {
+		return false
+	}
+	for _, char := range pathElement[1:] {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package releasenotes
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	testCases := []struct {
+		name           string
+		tech           techutils.Technology
+		packageName    string
+		currentVersion string
+		fixedVersion   string
+		expected       Links
+	}{
+		{
+			name:           "npm",
+			tech:           techutils.Npm,
+			packageName:    "minimatch",
+			currentVersion: "3.0.4",
+			fixedVersion:   "3.0.5",
+			// The diffs of the npm upgrades are opt-in
+			expected: Links{ReleaseNotes: "https://www.npmjs.com/package/minimatch/v/3.0.5"},
+		},
+		{
+			name:         "Scoped pnpm package",
+			tech:         techutils.Pnpm,
+			packageName:  "@types/node",
+			fixedVersion: "20.1.0",
+			expected:     Links{ReleaseNotes: "https://www.npmjs.com/package/@types/node/v/20.1.0"},
+		},
+		{
+			name:           "Poetry",
+			tech:           techutils.Poetry,
+			packageName:    "requests",
+			currentVersion: "2.30.0",
+			fixedVersion:   "2.31.0",
+			expected:       Links{ReleaseNotes: "https://pypi.org/project/requests/2.31.0/"},
+		},
+		{
+			name:         "Gradle",
+			tech:         techutils.Gradle,
+			packageName:  "org.apache.logging.log4j:log4j-core",
+			fixedVersion: "2.17.1",
+			expected:     Links{ReleaseNotes: "https://central.sonatype.com/artifact/org.apache.logging.log4j/log4j-core/2.17.1"},
+		},
+		{
+			name:         "Maven package without a group",
+			tech:         techutils.Maven,
+			packageName:  "log4j-core",
+			fixedVersion: "2.17.1",
+		},
+		{
+			name:           "Go module hosted on GitHub",
+			tech:           techutils.Go,
+			packageName:    "github.com/gin-gonic/gin",
+			currentVersion: "1.9.0",
+			fixedVersion:   "v1.9.1",
+			expected:       Links{ReleaseNotes: "https://pkg.go.dev/github.com/gin-gonic/gin@v1.9.1", Diff: "https://github.com/gin-gonic/gin/compare/v1.9.0...v1.9.1"},
+		},
+		{
+			name:           "Go module in a subdirectory with a major version",
+			tech:           techutils.Go,
+			packageName:    "github.com/jfrog/jfrog-cli-core/v2",
+			currentVersion: "2.50.0",
+			fixedVersion:   "2.51.0",
+			expected:       Links{ReleaseNotes: "https://pkg.go.dev/github.com/jfrog/jfrog-cli-core/v2@v2.51.0", Diff: "https://github.com/jfrog/jfrog-cli-core/compare/v2.50.0...v2.51.0"},
+		},
+		{
+			name:           "Go module in a subdirectory",
+			tech:           techutils.Go,
+			packageName:    "github.com/aws/aws-sdk-go-v2/service/s3",
+			currentVersion: "1.40.0",
+			fixedVersion:   "1.40.1",
+			expected:       Links{ReleaseNotes: "https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/s3@v1.40.1", Diff: "https://github.com/aws/aws-sdk-go-v2/compare/service/s3/v1.40.0...service/s3/v1.40.1"},
+		},
+		{
+			name:           "Go module not hosted on GitHub",
+			tech:           techutils.Go,
+			packageName:    "golang.org/x/net",
+			currentVersion: "0.16.0",
+			fixedVersion:   "0.17.0",
+			expected:       Links{ReleaseNotes: "https://pkg.go.dev/golang.org/x/net@v0.17.0"},
+		},
+		{
+			name:         "NuGet",
+			tech:         techutils.Nuget,
+			packageName:  "Newtonsoft.Json",
+			fixedVersion: "13.0.1",
+			expected:     Links{ReleaseNotes: "https://www.nuget.org/packages/Newtonsoft.Json/13.0.1"},
+		},
+		{
+			name:         "Unsupported technology",
+			tech:         techutils.Conan,
+			packageName:  "zlib",
+			fixedVersion: "1.3",
+		},
+		{
+			name:        "No fixed version",
+			tech:        techutils.Npm,
+			packageName: "minimatch",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Resolve(tc.tech, false, tc.packageName, tc.currentVersion, tc.fixedVersion))
+		})
+	}
+}
+
+type testNpmRegistry struct {
+	publicPackages []string
+	err            error
+}
+
+func (tnr *testNpmRegistry) HasVersions(packageName string, _ ...string) (bool, error) {
+	return slices.Contains(tnr.publicPackages, packageName), tnr.err
+}
+
+func TestNpmResolverDiffLinks(t *testing.T) {
+	resolver := &NpmResolver{Registry: &testNpmRegistry{publicPackages: []string{"minimatch"}}}
+	assert.Equal(t, Links{ReleaseNotes: "https://www.npmjs.com/package/minimatch/v/3.0.5", Diff: "https://npmdiff.dev/minimatch/3.0.4/3.0.5/"}, resolver.Resolve("minimatch", "3.0.4", "3.0.5"))
+	// Private packages aren't linked to npmdiff.dev
+	assert.Equal(t, Links{ReleaseNotes: "https://www.npmjs.com/package/@mycompany/ui/v/1.0.1"}, resolver.Resolve("@mycompany/ui", "1.0.0", "1.0.1"))
+
+	resolver = &NpmResolver{Registry: &testNpmRegistry{err: errors.New("registry unavailable")}}
+	assert.Equal(t, Links{ReleaseNotes: "https://www.npmjs.com/package/minimatch/v/3.0.5"}, resolver.Resolve("minimatch", "3.0.4", "3.0.5"))
+
+	assert.NotNil(t, GetResolver(techutils.Yarn, true).(*NpmResolver).Registry)
+	assert.Nil(t, GetResolver(techutils.Yarn, false).(*NpmResolver).Registry)
+}
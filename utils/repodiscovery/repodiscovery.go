@@ -0,0 +1,190 @@
+package repodiscovery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+)
+
+// Repository is a repository of the owner, as listed by the API of the Git provider
+type Repository struct {
+	Name     string
+	Archived bool
+	Fork     bool
+	// Empty if the API of the Git provider doesn't list it, in which case the requests to the repository use its default branch implicitly
+	DefaultBranch string
+}
+
+// Lister lists the repositories of an owner, which is an organization, a group, a workspace or a project, depending on the Git provider.
+// The Git clients list the repositories without their archived and fork states, so they're requested from the API of the Git provider.
+type Lister interface {
+	List() ([]Repository, error)
+}
+
+// Returns the repositories lister of the Git provider
+func NewLister(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, owner string) (Lister, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	switch provider {
+	case vcsutils.GitHub:
+		return &gitHubLister{client: client, owner: owner}, nil
+	case vcsutils.GitLab:
+		return &gitLabLister{client: client, owner: owner}, nil
+	case vcsutils.BitbucketServer:
+		return &bitbucketServerLister{client: client, reposUrl: client.Url("/api/1.0/projects/%s/repos", owner)}, nil
+	case vcsutils.BitbucketCloud:
+		return &bitbucketCloudLister{client: client, reposUrl: client.Url("/repositories/%s", owner)}, nil
+	case vcsutils.AzureRepos:
+		return &azureReposLister{client: client, reposUrl: client.ProjectUrl() + "/_apis/git/repositories"}, nil
+	default:
+		return nil, fmt.Errorf("listing the repositories of an owner isn't supported for %s", provider.String())
+	}
+}
+
+type gitHubLister struct {
+	client *vcsapi.Client
+	owner  string
+}
+
+// The owner is an organization, or a user if no organization was found
+func (gl *gitHubLister) List() ([]Repository, error) {
+	repositories, err := gl.list(gl.client.Url("/orgs/%s/repos?type=all", gl.owner))
+	if vcsapi.IsNotFound(err) {
+		return gl.list(gl.client.Url("/users/%s/repos?type=owner", gl.owner))
+	}
+	return repositories, err
+}
+
+func (gl *gitHubLister) list(reposUrl string) (repositories []Repository, err error) {
+	repos, err := vcsapi.List[struct {
+		Name          string `json:"name"`
+		Archived      bool   `json:"archived"`
+		Fork          bool   `json:"fork"`
+		DefaultBranch string `json:"default_branch"`
+	}](gl.client, reposUrl, vcsapi.PageSize)
+	for _, repo := range repos {
+		repositories = append(repositories, Repository{Name: repo.Name, Archived: repo.Archived, Fork: repo.Fork, DefaultBranch: repo.DefaultBranch})
+	}
+	return
+}
+
+type gitLabLister struct {
+	client *vcsapi.Client
+	owner  string
+}
+
+// The owner is a group, including its subgroups, or a user if no group was found.
+// The names of the projects of the subgroups include the paths of the subgroups.
+func (gl *gitLabLister) List() ([]Repository, error) {
+	repositories, err := gl.list(gl.client.Url("/groups/%s/projects?include_subgroups=true", gl.owner))
+	if vcsapi.IsNotFound(err) {
+		return gl.list(gl.client.Url("/users/%s/projects?owned=true", gl.owner))
+	}
+	return repositories, err
+}
+
+func (gl *gitLabLister) list(projectsUrl string) (repositories []Repository, err error) {
+	projects, err := vcsapi.List[struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		Archived          bool   `json:"archived"`
+		DefaultBranch     string `json:"default_branch"`
+		// Set only for forks
+		ForkedFromProject *struct{} `json:"forked_from_project"`
+	}](gl.client, projectsUrl, vcsapi.PageSize)
+	for _, project := range projects {
+		repositories = append(repositories, Repository{Name: strings.TrimPrefix(project.PathWithNamespace, gl.owner+"/"), Archived: project.Archived, Fork: project.ForkedFromProject != nil, DefaultBranch: project.DefaultBranch})
+	}
+	return
+}
+
+type bitbucketServerLister struct {
+	client   *vcsapi.Client
+	reposUrl string
+}
+
+func (bs *bitbucketServerLister) List() (repositories []Repository, err error) {
+	repos, err := vcsapi.List[struct {
+		Slug string `json:"slug"`
+		// Archived repositories are supported since Bitbucket Server 8.0
+		Archived bool `json:"archived"`
+		// Set only for forks
+		Origin *struct{} `json:"origin"`
+	}](bs.client, bs.reposUrl, vcsapi.PageSize)
+	for _, repo := range repos {
+		repositories = append(repositories, Repository{Name: repo.Slug, Archived: repo.Archived, Fork: repo.Origin != nil})
+	}
+	return
+}
+
+type bitbucketCloudLister struct {
+	client   *vcsapi.Client
+	reposUrl string
+}
+
+// Bitbucket Cloud has no archived repositories
+func (bc *bitbucketCloudLister) List() (repositories []Repository, err error) {
+	repos, err := vcsapi.List[struct {
+		Slug string `json:"slug"`
+		// Set only for forks
+		Parent     *struct{} `json:"parent"`
+		MainBranch *struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}](bc.client, bc.reposUrl, vcsapi.PageSize)
+	for _, repo := range repos {
+		repository := Repository{Name: repo.Slug, Fork: repo.Parent != nil}
+		if repo.MainBranch != nil {
+			repository.DefaultBranch = repo.MainBranch.Name
+		}
+		repositories = append(repositories, repository)
+	}
+	return
+}
+
+type azureReposLister struct {
+	client   *vcsapi.Client
+	reposUrl string
+}
+
+// The repositories of the project are listed in a single response. Disabled repositories are considered archived.
+func (ar *azureReposLister) List() (repositories []Repository, err error) {
+	var repos struct {
+		Value []struct {
+			Name       string `json:"name"`
+			IsDisabled bool   `json:"isDisabled"`
+			IsFork     bool   `json:"isFork"`
+			// The full name of the branch, such as 'refs/heads/main'
+			DefaultBranch string `json:"defaultBranch"`
+		} `json:"value"`
+	}
+	if err = ar.client.Get(fmt.Sprintf("%s?api-version=%s", ar.reposUrl, vcsapi.AzureApiVersion), &repos); err != nil {
+		return nil, err
+	}
+	for _, repo := range repos.Value {
+		repositories = append(repositories, Repository{Name: repo.Name, Archived: repo.IsDisabled, Fork: repo.IsFork, DefaultBranch: strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")})
+	}
+	return
+}
+
+// Returns the topics of the repository. Only GitHub and GitLab have repository topics.
+func GetTopics(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, owner, repository string) ([]string, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	switch provider {
+	case vcsutils.GitHub:
+		var topics struct {
+			Names []string `json:"names"`
+		}
+		err := client.Get(client.RepositoryUrl(owner, repository)+"/topics", &topics)
+		return topics.Names, err
+	case vcsutils.GitLab:
+		var project struct {
+			Topics []string `json:"topics"`
+		}
+		err := client.Get(client.RepositoryUrl(owner, repository), &project)
+		return project.Topics, err
+	default:
+		return nil, fmt.Errorf("the repository topics aren't supported for %s", provider.String())
+	}
+}
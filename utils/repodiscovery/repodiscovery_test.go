@@ -0,0 +1,159 @@
+package repodiscovery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	testCases := []struct {
+		name         string
+		provider     vcsutils.VcsProvider
+		username     string
+		expectedAuth string
+		// The default branch of team-api, which some APIs don't list
+		defaultBranch string
+		// The responses by the path and the query of the requests. Missing paths respond with 404.
+		responses map[string]string
+	}{
+		{
+			name:         "GitHub organization",
+			provider:     vcsutils.GitHub,
+			expectedAuth: "Bearer token",
+			responses: map[string]string{
+				"/orgs/jfrog/repos?type=all&per_page=100&page=1": `[` + strings.Repeat(`{"name":"team-api"},`, 99) + `{"name":"team-web","archived":true}]`,
+				"/orgs/jfrog/repos?type=all&per_page=100&page=2": `[{"name":"team-fork","fork":true}]`,
+			},
+		},
+		{
+			name:          "GitHub user",
+			provider:      vcsutils.GitHub,
+			expectedAuth:  "Bearer token",
+			defaultBranch: "main",
+			responses: map[string]string{
+				"/users/jfrog/repos?type=owner&per_page=100&page=1": `[{"name":"team-api","default_branch":"main"},{"name":"team-web","archived":true},{"name":"team-fork","fork":true}]`,
+			},
+		},
+		{
+			name:          "GitLab",
+			provider:      vcsutils.GitLab,
+			defaultBranch: "main",
+			responses: map[string]string{
+				"/groups/jfrog/projects?include_subgroups=true&per_page=100&page=1": `[{"path_with_namespace":"jfrog/team-api","default_branch":"main"},{"path_with_namespace":"jfrog/team-web","archived":true},{"path_with_namespace":"jfrog/team-fork","forked_from_project":{"id":1}}]`,
+			},
+		},
+		{
+			name:         "Bitbucket Server",
+			provider:     vcsutils.BitbucketServer,
+			username:     "frogbot",
+			expectedAuth: "Basic ZnJvZ2JvdDp0b2tlbg==",
+			responses: map[string]string{
+				"/rest/api/1.0/projects/jfrog/repos?limit=100&start=0": `{"values":[{"slug":"team-api"},{"slug":"team-web","archived":true}],"isLastPage":false,"nextPageStart":2}`,
+				"/rest/api/1.0/projects/jfrog/repos?limit=100&start=2": `{"values":[{"slug":"team-fork","origin":{"slug":"team-api"}}],"isLastPage":true}`,
+			},
+		},
+		{
+			name:          "Azure Repos",
+			provider:      vcsutils.AzureRepos,
+			expectedAuth:  "Basic OnRva2Vu",
+			defaultBranch: "main",
+			responses: map[string]string{
+				"/frogbot-project/_apis/git/repositories?api-version=7.0": `{"value":[{"name":"team-api","defaultBranch":"refs/heads/main"},{"name":"team-web","isDisabled":true},{"name":"team-fork","isFork":true}]}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response, exists := tc.responses[r.URL.RequestURI()]
+				if !exists {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if tc.expectedAuth != "" {
+					assert.Equal(t, tc.expectedAuth, r.Header.Get("Authorization"))
+				} else {
+					assert.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+				}
+				_, err := w.Write([]byte(response))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			lister, err := NewLister(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token", Username: tc.username, Project: "frogbot-project"}, "jfrog")
+			require.NoError(t, err)
+			repositories, err := lister.List()
+			require.NoError(t, err)
+			assert.Contains(t, repositories, Repository{Name: "team-api", DefaultBranch: tc.defaultBranch})
+			assert.Contains(t, repositories, Repository{Name: "team-web", Archived: true})
+			assert.Contains(t, repositories, Repository{Name: "team-fork", Fork: true})
+		})
+	}
+}
+
+func TestBitbucketCloudList(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		response := `{"values":[{"slug":"team-fork","parent":{"slug":"team-api"}}]}`
+		if r.URL.Path == "/repositories/jfrog" {
+			response = `{"values":[{"slug":"team-api","mainbranch":{"name":"main"}}],"next":"` + server.URL + `/page/2"}`
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	lister, err := NewLister(vcsutils.BitbucketCloud, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "jfrog")
+	require.NoError(t, err)
+	repositories, err := lister.List()
+	require.NoError(t, err)
+	assert.Equal(t, []Repository{{Name: "team-api", DefaultBranch: "main"}, {Name: "team-fork", Fork: true}}, repositories)
+}
+
+func TestListError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	lister, err := NewLister(vcsutils.GitHub, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "jfrog")
+	require.NoError(t, err)
+	_, err = lister.List()
+	assert.ErrorContains(t, err, "responded with status 401")
+}
+
+func TestGetTopics(t *testing.T) {
+	var requestedPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.EscapedPath())
+		switch r.URL.EscapedPath() {
+		case "/repos/jfrog/team-api/topics":
+			_, _ = w.Write([]byte(`{"names":["internal-tool","go"]}`))
+		case "/projects/jfrog%2Fteam-api":
+			_, _ = w.Write([]byte(`{"path_with_namespace":"jfrog/team-api","topics":["internal-tool"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	vcsInfo := vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}
+
+	topics, err := GetTopics(vcsutils.GitHub, vcsInfo, "jfrog", "team-api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal-tool", "go"}, topics)
+
+	topics, err = GetTopics(vcsutils.GitLab, vcsInfo, "jfrog", "team-api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal-tool"}, topics)
+	assert.Equal(t, []string{"/repos/jfrog/team-api/topics", "/projects/jfrog%2Fteam-api"}, requestedPaths)
+
+	_, err = GetTopics(vcsutils.GitHub, vcsInfo, "jfrog", "missing")
+	assert.Error(t, err)
+	_, err = GetTopics(vcsutils.BitbucketCloud, vcsInfo, "jfrog", "team-api")
+	assert.ErrorContains(t, err, "the repository topics aren't supported")
+}
@@ -0,0 +1,407 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	reportTitle          = "🐸 Frogbot Scan Report"
+	severityOverview     = "📊 Severity Overview"
+	sourceCodeTitle      = "🔎 Source Code Findings"
+	noIssuesFoundMessage = "✅ Frogbot scanned this repository and did not find any issues"
+	scanErrorsMessage    = "⚠️ Some of the scans failed, so the report may be incomplete"
+	jobSummaryTooLarge   = "⚠️ The scan results exceed the size limit of the job summary. Set JF_REPORT_PATH to upload the full report as an artifact of the workflow run."
+
+	// GitHub rejects the job summary of a step that is larger than 1MiB
+	jobSummaryMaxSize = 1024 * 1024
+
+	reportCSS = `body {
+            font-family: Arial, sans-serif;
+            background-color: #f5f5f5;
+            margin: 20px 40px;
+        }
+        table {
+            border-collapse: collapse;
+            margin: 10px 0;
+        }
+        th, td {
+            padding: 8px;
+            border: 1px solid #ccc;
+        }
+        th {
+            background-color: #f2f2f2;
+        }
+        tr:nth-child(even) {
+            background-color: #f9f9f9;
+        }
+        .chart-row {
+            display: flex;
+            align-items: center;
+            margin: 4px 0;
+        }
+        .chart-label {
+            width: 90px;
+        }
+        .chart-bar {
+            height: 18px;
+            margin-right: 8px;
+            border-radius: 3px;
+        }
+        .Critical { background-color: #8b0000; }
+        .High { background-color: #e0483e; }
+        .Medium { background-color: #f5a623; }
+        .Low { background-color: #f8e71c; }
+        .Unknown { background-color: #9b9b9b; }`
+	// The findings are written by html/template, which escapes their fields
+	reportHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>{{.Title}}</title>
+    <style>
+        ` + reportCSS + `
+    </style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<ul>
+<li>Generated at: {{.GeneratedAt}}</li>
+{{- if .Subject}}
+<li>Scanned: {{.Subject}}</li>
+{{- end}}
+</ul>
+{{- if .Note}}
+<blockquote>{{.Note}}</blockquote>
+{{- end}}
+{{- range .Chart}}
+<div class="chart-row"><span class="chart-label">{{.Severity}}</span><div class="chart-bar {{.Severity}}" style="width: {{.Width}}px"></div>{{.Count}}</div>
+{{- end}}
+{{- range .Tables}}
+<h2>{{.Title}}</h2>
+<table>
+<thead><tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+</body>
+</html>
+`
+)
+
+var reportHTML = template.Must(template.New("report").Parse(reportHTMLTemplate))
+
+var sortedSeverities = []severityutils.Severity{severityutils.Critical, severityutils.High, severityutils.Medium, severityutils.Low, severityutils.Unknown}
+
+// ScanReport holds the results of a single Frogbot run, to be written as a standalone report file
+type ScanReport struct {
+	// Describes the scanned target, for example: the repository and the branch
+	Subject        string
+	Issues         issues.ScansIssuesCollection
+	ResultContext  results.ResultContext
+	IncludeSecrets bool
+	// The vulnerable dependencies that couldn't be fixed automatically during the run
+	UnsupportedFixes []outputwriter.UnsupportedFixRow
+	Writer           outputwriter.OutputWriter
+}
+
+// Writes the report to the given path.
+// An HTML report is generated when the path has an '.html' or '.htm' extension, otherwise a markdown report is generated.
+func (sr *ScanReport) Write(reportPath string) (err error) {
+	var content string
+	switch strings.ToLower(filepath.Ext(reportPath)) {
+	case ".html", ".htm":
+		if content, err = sr.HtmlContent(); err != nil {
+			return
+		}
+	default:
+		content = sr.MarkdownContent()
+	}
+	if err = os.MkdirAll(filepath.Dir(reportPath), 0755); errorutils.CheckError(err) != nil {
+		return
+	}
+	if err = os.WriteFile(reportPath, []byte(content), 0644); errorutils.CheckError(err) != nil {
+		return
+	}
+	log.Info("The scan report was written to:", reportPath)
+	return
+}
+
+// Appends the markdown report to the job summary file of the GitHub Actions step, so the results are shown in the workflow run page.
+// A report that exceeds the size limit of the job summary is replaced with a note.
+func (sr *ScanReport) WriteJobSummary(summaryPath string) (err error) {
+	content := sr.MarkdownContent()
+	if len(content) > jobSummaryMaxSize {
+		content = sr.Writer.MarkAsTitle(reportTitle, 1) + outputwriter.MarkAsQuote(jobSummaryTooLarge)
+	}
+	summaryFile, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if errorutils.CheckError(err) != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(summaryFile.Close()))
+	}()
+	if _, err = summaryFile.WriteString(content + "\n"); errorutils.CheckError(err) != nil {
+		return
+	}
+	log.Info("The scan results were added to the job summary")
+	return
+}
+
+// Generates a markdown report with the scan summary, the SCA issues and the source code findings
+func (sr *ScanReport) MarkdownContent() string {
+	var contentBuilder strings.Builder
+	outputwriter.WriteContent(&contentBuilder, outputwriter.MarkAsBullet("Generated at: "+time.Now().UTC().Format(time.RFC1123)))
+	if sr.Subject != "" {
+		outputwriter.WriteContent(&contentBuilder, outputwriter.MarkAsBullet("Scanned: "+sr.Subject))
+	}
+	return sr.Writer.MarkAsTitle(reportTitle, 1) + contentBuilder.String() + sr.issuesContent()
+}
+
+type htmlReport struct {
+	Title       string
+	GeneratedAt string
+	Subject     string
+	Note        string
+	Chart       []chartRow
+	Tables      []htmlTable
+}
+
+type chartRow struct {
+	Severity string
+	Width    int
+	Count    int
+}
+
+type htmlTable struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+}
+
+func (ht *htmlTable) addRow(cells ...string) {
+	ht.Rows = append(ht.Rows, cells)
+}
+
+// Generates a standalone HTML report with a severity chart followed by the tables of the issues.
+// The report is rendered from the fields of the issues rather than from the markdown report, so their content is escaped.
+func (sr *ScanReport) HtmlContent() (string, error) {
+	report := htmlReport{Title: reportTitle, GeneratedAt: time.Now().UTC().Format(time.RFC1123), Subject: sr.Subject}
+	switch {
+	case sr.Issues.HasErrors():
+		report.Note = scanErrorsMessage
+	case !sr.Issues.IssuesExists(sr.IncludeSecrets):
+		report.Note = noIssuesFoundMessage
+	}
+	report.Chart = sr.severityChart()
+	report.Tables = sr.htmlTables()
+	var contentBuilder strings.Builder
+	if err := reportHTML.Execute(&contentBuilder, report); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return contentBuilder.String(), nil
+}
+
+func (sr *ScanReport) issuesContent() string {
+	// The comments link to the report as the full report, so its content isn't trimmed
+	reportingOptions := sr.Writer.ReportingOptions()
+	sr.Writer.SetReportingOptions(outputwriter.ReportingOptions{})
+	defer sr.Writer.SetReportingOptions(reportingOptions)
+	var contentBuilder strings.Builder
+	if !sr.Issues.IssuesExists(sr.IncludeSecrets) && !sr.Issues.HasErrors() {
+		outputwriter.WriteContent(&contentBuilder, outputwriter.MarkAsQuote(noIssuesFoundMessage))
+		return contentBuilder.String()
+	}
+	outputwriter.WriteContent(&contentBuilder, outputwriter.ScanSummaryContent(sr.Issues, sr.ResultContext, sr.IncludeSecrets, sr.Writer))
+	outputwriter.WriteNewLine(&contentBuilder)
+	outputwriter.WriteContent(&contentBuilder, sr.severityOverviewContent())
+	outputwriter.WriteContent(&contentBuilder, outputwriter.PolicyViolationsContent(sr.Issues, sr.Writer)...)
+	outputwriter.WriteContent(&contentBuilder, outputwriter.GetVulnerabilitiesContent(sr.Issues.ScaVulnerabilities, sr.Writer)...)
+	outputwriter.WriteContent(&contentBuilder, outputwriter.UnsupportedFixesContent(sr.UnsupportedFixes, sr.Writer))
+	outputwriter.WriteContent(&contentBuilder, sr.sourceCodeContent())
+	return contentBuilder.String()
+}
+
+// Table with the number of issues of each severity, per scan category
+func (sr *ScanReport) severityOverviewContent() string {
+	counts := sr.severityCountByScanType()
+	table := outputwriter.NewMarkdownTable("Severity", "SCA", "SAST", "Secrets", "IaC", "Total")
+	for _, severity := range sortedSeverities {
+		total := 0
+		row := []string{sr.Writer.FormattedSeverity(severity.String(), "")}
+		for _, scanType := range reportedScanTypes() {
+			total += counts[scanType][severity]
+			row = append(row, fmt.Sprintf("%d", counts[scanType][severity]))
+		}
+		if total == 0 {
+			continue
+		}
+		table.AddRow(append(row, fmt.Sprintf("%d", total))...)
+	}
+	if !table.HasContent() {
+		return ""
+	}
+	var contentBuilder strings.Builder
+	outputwriter.WriteContent(&contentBuilder, sr.Writer.MarkAsTitle(severityOverview, 2), table.Build())
+	return contentBuilder.String()
+}
+
+func (sr *ScanReport) severityChart() (chart []chartRow) {
+	counts := map[severityutils.Severity]int{}
+	maxCount := 0
+	for _, severityCount := range sr.severityCountByScanType() {
+		for severity, count := range severityCount {
+			counts[severity] += count
+			maxCount = max(maxCount, counts[severity])
+		}
+	}
+	if maxCount == 0 {
+		return
+	}
+	for _, severity := range sortedSeverities {
+		chart = append(chart, chartRow{Severity: severity.String(), Width: counts[severity] * 400 / maxCount, Count: counts[severity]})
+	}
+	return
+}
+
+func reportedScanTypes() []utils.SubScanType {
+	return []utils.SubScanType{utils.ScaScan, utils.SastScan, utils.SecretsScan, utils.IacScan}
+}
+
+func (sr *ScanReport) severityCountByScanType() map[utils.SubScanType]map[severityutils.Severity]int {
+	counts := map[utils.SubScanType]map[severityutils.Severity]int{}
+	for _, scanType := range reportedScanTypes() {
+		if scanType == utils.SecretsScan && !sr.IncludeSecrets {
+			continue
+		}
+		counts[scanType] = sr.Issues.GetScanIssuesSeverityCount(scanType, sr.ResultContext.IncludeVulnerabilities, sr.ResultContext.HasViolationContext())
+	}
+	return counts
+}
+
+// Lists the JAS findings that have a location in the source code (SAST, Secrets and IaC)
+func (sr *ScanReport) sourceCodeContent() string {
+	var contentBuilder strings.Builder
+	sr.writeSourceCodeTable(&contentBuilder, "Static Application Security Testing (SAST)", sr.Issues.SastVulnerabilities, sr.Issues.SastViolations)
+	if sr.IncludeSecrets {
+		sr.writeSourceCodeTable(&contentBuilder, "Secrets", sr.Issues.SecretsVulnerabilities, sr.Issues.SecretsViolations)
+	}
+	sr.writeSourceCodeTable(&contentBuilder, "Infrastructure as Code (IaC)", sr.Issues.IacVulnerabilities, sr.Issues.IacViolations)
+	if contentBuilder.Len() == 0 {
+		return ""
+	}
+	return sr.Writer.MarkAsTitle(sourceCodeTitle, 2) + "\n" + contentBuilder.String()
+}
+
+func (sr *ScanReport) writeSourceCodeTable(contentBuilder *strings.Builder, title string, vulnerabilities, violations []formats.SourceCodeRow) {
+	if len(vulnerabilities) == 0 && len(violations) == 0 {
+		return
+	}
+	table := outputwriter.NewMarkdownTable("Severity", "Type", "File", "Line", "Finding", "Rule").SetDelimiter(sr.Writer.Separator())
+	addRows := func(issueType string, rows []formats.SourceCodeRow) {
+		for _, row := range rows {
+			table.AddRow(sr.Writer.FormattedSeverity(row.Severity, ""), issueType, row.File, fmt.Sprintf("%d", row.StartLine), row.Finding, row.RuleId)
+		}
+	}
+	addRows("Vulnerability", vulnerabilities)
+	addRows("Violation", violations)
+	outputwriter.WriteContent(contentBuilder, sr.Writer.MarkAsTitle(title, 3), table.Build())
+}
+
+// The tables of the HTML report, in the order of the sections of the markdown report
+func (sr *ScanReport) htmlTables() (tables []htmlTable) {
+	counts := sr.severityCountByScanType()
+	overview := htmlTable{Title: severityOverview, Headers: []string{"Severity", "SCA", "SAST", "Secrets", "IaC", "Total"}}
+	for _, severity := range sortedSeverities {
+		total := 0
+		row := []string{severity.String()}
+		for _, scanType := range reportedScanTypes() {
+			total += counts[scanType][severity]
+			row = append(row, fmt.Sprintf("%d", counts[scanType][severity]))
+		}
+		if total > 0 {
+			overview.addRow(append(row, fmt.Sprintf("%d", total))...)
+		}
+	}
+	securityViolations := htmlTable{Title: "Security Violations", Headers: []string{"Severity", "ID", "Contextual Analysis", "Direct Dependencies", "Impacted Dependency", "Watch Name"}}
+	for _, violation := range sr.Issues.ScaViolations {
+		securityViolations.addRow(violation.Severity, getIssueIds(violation), violation.Applicable, getDirectDependencies(violation.Components),
+			results.GetDependencyId(violation.ImpactedDependencyName, violation.ImpactedDependencyVersion), violation.Watch)
+	}
+	licenseViolations := htmlTable{Title: "License Violations", Headers: []string{"Severity", "License", "Direct Dependencies", "Impacted Dependency", "Watch Name"}}
+	for _, violation := range sr.Issues.LicensesViolations {
+		licenseViolations.addRow(violation.Severity, violation.LicenseKey, getDirectDependencies(violation.Components),
+			results.GetDependencyId(violation.ImpactedDependencyName, violation.ImpactedDependencyVersion), violation.Watch)
+	}
+	vulnerabilities := htmlTable{Title: "Vulnerable Dependencies", Headers: []string{"Severity", "ID", "Contextual Analysis", "Direct Dependencies", "Impacted Dependency", "Fixed Versions"}}
+	for _, vulnerability := range sr.Issues.ScaVulnerabilities {
+		vulnerabilities.addRow(vulnerability.Severity, getIssueIds(vulnerability), vulnerability.Applicable, getDirectDependencies(vulnerability.Components),
+			fmt.Sprintf("%s %s", vulnerability.ImpactedDependencyName, vulnerability.ImpactedDependencyVersion), strings.Join(vulnerability.FixedVersions, ", "))
+	}
+	unsupportedFixes := htmlTable{Title: "Known Unfixable Items", Headers: []string{"Severity", "ID", "Impacted Dependency", "Fixed Version", "Reason"}}
+	for _, unsupportedFix := range sr.UnsupportedFixes {
+		unsupportedFixes.addRow(unsupportedFix.Severity, getIssueIds(unsupportedFix.VulnerabilityOrViolationRow),
+			fmt.Sprintf("%s %s", unsupportedFix.ImpactedDependencyName, unsupportedFix.ImpactedDependencyVersion), unsupportedFix.SuggestedFixedVersion, unsupportedFix.Reason)
+	}
+	tables = append(tables, overview, securityViolations, licenseViolations, vulnerabilities, unsupportedFixes)
+	tables = append(tables, getSourceCodeHtmlTable("Static Application Security Testing (SAST)", sr.Issues.SastVulnerabilities, sr.Issues.SastViolations))
+	if sr.IncludeSecrets {
+		tables = append(tables, getSourceCodeHtmlTable("Secrets", sr.Issues.SecretsVulnerabilities, sr.Issues.SecretsViolations))
+	}
+	tables = append(tables, getSourceCodeHtmlTable("Infrastructure as Code (IaC)", sr.Issues.IacVulnerabilities, sr.Issues.IacViolations))
+	// The tables without rows aren't shown
+	return slices.DeleteFunc(tables, func(table htmlTable) bool { return len(table.Rows) == 0 })
+}
+
+func getSourceCodeHtmlTable(title string, vulnerabilities, violations []formats.SourceCodeRow) htmlTable {
+	table := htmlTable{Title: title, Headers: []string{"Severity", "Type", "File", "Line", "Finding", "Rule"}}
+	addRows := func(issueType string, rows []formats.SourceCodeRow) {
+		for _, row := range rows {
+			table.addRow(row.Severity, issueType, row.File, fmt.Sprintf("%d", row.StartLine), row.Finding, row.RuleId)
+		}
+	}
+	addRows("Vulnerability", vulnerabilities)
+	addRows("Violation", violations)
+	return table
+}
+
+// Returns the CVEs of the issue, or the Xray ID of an issue without CVEs
+func getIssueIds(issue formats.VulnerabilityOrViolationRow) string {
+	var ids []string
+	for _, cve := range issue.Cves {
+		if cve.Id != "" {
+			ids = append(ids, cve.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return issue.IssueId
+	}
+	return strings.Join(ids, ", ")
+}
+
+func getDirectDependencies(components []formats.ComponentRow) string {
+	var directDependencies []string
+	for _, component := range components {
+		directDependencies = append(directDependencies, results.GetDependencyId(component.Name, component.Version))
+	}
+	return strings.Join(directDependencies, ", ")
+}
@@ -0,0 +1,194 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestReport(scanIssues issues.ScansIssuesCollection) *ScanReport {
+	return &ScanReport{
+		Subject:       "owner/repo",
+		Issues:        scanIssues,
+		ResultContext: results.ResultContext{IncludeVulnerabilities: true},
+		Writer:        &outputwriter.SimplifiedOutput{},
+	}
+}
+
+func getTestIssues() issues.ScansIssuesCollection {
+	status := 0
+	return issues.ScansIssuesCollection{
+		ScanStatus: formats.ScanStatus{ScaStatusCode: &status, SastStatusCode: &status, IacStatusCode: &status, SecretsStatusCode: &status},
+		ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{
+			{
+				IssueId: "XRAY-1",
+				Cves:    []formats.CveRow{{Id: "CVE-2023-1234"}},
+				ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+					SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+					ImpactedDependencyName:    "lodash",
+					ImpactedDependencyVersion: "4.17.0",
+				},
+				FixedVersions: []string{"4.17.21"},
+			},
+		},
+		SastVulnerabilities: []formats.SourceCodeRow{
+			{
+				SeverityDetails: formats.SeverityDetails{Severity: "Medium"},
+				ScannerInfo:     formats.ScannerInfo{RuleId: "js-xss"},
+				Finding:         "XSS Vulnerability",
+				Location:        formats.Location{File: "index.js", StartLine: 12},
+			},
+		},
+		SecretsVulnerabilities: []formats.SourceCodeRow{
+			{
+				SeverityDetails: formats.SeverityDetails{Severity: "High"},
+				ScannerInfo:     formats.ScannerInfo{RuleId: "aws-key"},
+				Finding:         "AWS key exposed",
+				Location:        formats.Location{File: "config.js", StartLine: 3},
+			},
+		},
+	}
+}
+
+func TestMarkdownContent(t *testing.T) {
+	testCases := []struct {
+		name             string
+		issues           issues.ScansIssuesCollection
+		includeSecrets   bool
+		unsupportedFixes []outputwriter.UnsupportedFixRow
+		expectedContains []string
+		expectedMissing  []string
+	}{
+		{
+			name:             "No issues",
+			expectedContains: []string{reportTitle, "owner/repo", noIssuesFoundMessage},
+			expectedMissing:  []string{severityOverview, sourceCodeTitle},
+		},
+		{
+			name:             "With issues",
+			issues:           getTestIssues(),
+			expectedContains: []string{reportTitle, severityOverview, "CVE-2023-1234", "lodash 4.17.0", sourceCodeTitle, "index.js", "js-xss"},
+			expectedMissing:  []string{noIssuesFoundMessage, "aws-key"},
+		},
+		{
+			name:             "With unsupported fixes",
+			issues:           getTestIssues(),
+			unsupportedFixes: []outputwriter.UnsupportedFixRow{{VulnerabilityOrViolationRow: getTestIssues().ScaVulnerabilities[0], Reason: "Indirect dependency"}},
+			expectedContains: []string{"Known Unfixable Items", "Indirect dependency"},
+		},
+		{
+			name:             "With secrets",
+			issues:           getTestIssues(),
+			includeSecrets:   true,
+			expectedContains: []string{"config.js", "aws-key"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanReport := getTestReport(tc.issues)
+			scanReport.IncludeSecrets = tc.includeSecrets
+			scanReport.UnsupportedFixes = tc.unsupportedFixes
+			content := scanReport.MarkdownContent()
+			for _, expected := range tc.expectedContains {
+				assert.Contains(t, content, expected)
+			}
+			for _, missing := range tc.expectedMissing {
+				assert.NotContains(t, content, missing)
+			}
+		})
+	}
+}
+
+func TestMarkdownContentIgnoresReportingOptions(t *testing.T) {
+	scanIssues := getTestIssues()
+	secondVulnerability := scanIssues.ScaVulnerabilities[0]
+	secondVulnerability.ImpactedDependencyName = "minimist"
+	scanIssues.ScaVulnerabilities = append(scanIssues.ScaVulnerabilities, secondVulnerability)
+	scanReport := getTestReport(scanIssues)
+	reportingOptions := outputwriter.ReportingOptions{ShowSections: []string{outputwriter.SastSection}, MaxRowsPerTable: 1}
+	scanReport.Writer.SetReportingOptions(reportingOptions)
+
+	content := scanReport.MarkdownContent()
+	assert.Contains(t, content, "lodash 4.17.0")
+	assert.Contains(t, content, "minimist 4.17.0")
+	assert.NotContains(t, content, "Showing 1 of 2 rows")
+	// The options of the comments are kept
+	assert.Equal(t, reportingOptions, scanReport.Writer.ReportingOptions())
+}
+
+func TestHtmlContent(t *testing.T) {
+	content, err := getTestReport(getTestIssues()).HtmlContent()
+	require.NoError(t, err)
+	assert.Contains(t, content, "<!DOCTYPE html>")
+	assert.Contains(t, content, `<div class="chart-bar High" style="width: 400px"></div>1`)
+	assert.Contains(t, content, `<div class="chart-bar Medium" style="width: 400px"></div>1`)
+	assert.Contains(t, content, "<table>")
+	assert.Contains(t, content, "<td>CVE-2023-1234</td>")
+	assert.Contains(t, content, "<td>lodash 4.17.0</td>")
+	assert.Contains(t, content, "<td>index.js</td>")
+	assert.NotContains(t, content, "aws-key")
+	assert.NotContains(t, content, noIssuesFoundMessage)
+
+	content, err = getTestReport(issues.ScansIssuesCollection{}).HtmlContent()
+	require.NoError(t, err)
+	assert.Contains(t, content, noIssuesFoundMessage)
+	assert.NotContains(t, content, "<table>")
+}
+
+func TestHtmlContentEscapesFindings(t *testing.T) {
+	scanIssues := getTestIssues()
+	scanIssues.SastVulnerabilities[0].Finding = `<script>alert("xss")</script>`
+	scanIssues.SastVulnerabilities[0].File = `<img src=x onerror=alert(1)>.js`
+	scanIssues.ScaVulnerabilities[0].ImpactedDependencyName = "<b>lodash</b>"
+	scanReport := getTestReport(scanIssues)
+	scanReport.Subject = "<i>owner/repo</i>"
+
+	content, err := scanReport.HtmlContent()
+	require.NoError(t, err)
+	for _, unescaped := range []string{"<script>", "<img", "<b>", "<i>"} {
+		assert.NotContains(t, content, unescaped)
+	}
+	assert.Contains(t, content, "&lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt;")
+	assert.Contains(t, content, "&lt;b&gt;lodash&lt;/b&gt; 4.17.0")
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		name           string
+		reportPath     string
+		expectedPrefix string
+	}{
+		{name: "HTML report", reportPath: "frogbot-report.html", expectedPrefix: "<!DOCTYPE html>"},
+		{name: "Markdown report", reportPath: filepath.Join("reports", "frogbot-report.md"), expectedPrefix: "# " + reportTitle},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportPath := filepath.Join(t.TempDir(), tc.reportPath)
+			require.NoError(t, getTestReport(getTestIssues()).Write(reportPath))
+			content, err := os.ReadFile(reportPath)
+			require.NoError(t, err)
+			assert.Contains(t, string(content), tc.expectedPrefix)
+		})
+	}
+}
+
+func TestWriteJobSummary(t *testing.T) {
+	summaryPath := filepath.Join(t.TempDir(), "step_summary.md")
+	// The job summary may already contain the summaries of the previous steps
+	require.NoError(t, os.WriteFile(summaryPath, []byte("## Build\n"), 0644))
+	scanReport := getTestReport(getTestIssues())
+	require.NoError(t, scanReport.WriteJobSummary(summaryPath))
+	content, err := os.ReadFile(summaryPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "## Build\n"))
+	assert.Contains(t, string(content), "# "+reportTitle)
+	assert.Contains(t, string(content), "CVE-2023-1234")
+}
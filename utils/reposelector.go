@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/repodiscovery"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v2"
+)
+
+// RepositoriesSelector selects the repositories of the owner by glob patterns of their names, instead of listing each repository in the config.
+// The repositories are listed by the API of the Git provider at runtime. A pattern that contains a slash matches the '<owner>/<name>' of the repository.
+type RepositoriesSelector struct {
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+	// Archived and forked repositories are skipped, unless they're included explicitly
+	IncludeArchived bool `yaml:"includeArchived,omitempty"`
+	IncludeForks    bool `yaml:"includeForks,omitempty"`
+}
+
+func (rs *RepositoriesSelector) validate() error {
+	if len(rs.Include) == 0 {
+		return errors.New("the repositories selector must include at least one pattern")
+	}
+	for _, pattern := range append(rs.Include, rs.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid repositories pattern '%s': %s", pattern, err.Error())
+		}
+	}
+	return nil
+}
+
+// Returns the sorted names of the selected repositories
+func (rs *RepositoriesSelector) Select(owner string, repositories []repodiscovery.Repository) (names []string) {
+	for _, repository := range repositories {
+		if (repository.Archived && !rs.IncludeArchived) || (repository.Fork && !rs.IncludeForks) {
+			continue
+		}
+		if matchesRepositoryPattern(rs.Include, owner, repository.Name) && !matchesRepositoryPattern(rs.Exclude, owner, repository.Name) {
+			names = append(names, repository.Name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+func matchesRepositoryPattern(patterns []string, owner, repoName string) bool {
+	for _, pattern := range patterns {
+		name := repoName
+		if strings.Contains(pattern, "/") {
+			name = owner + "/" + repoName
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Replaces each repository of the config that has a repositories selector with the repositories that it selects, which share its params.
+// The repositories that are configured by their names take precedence over the selected ones.
+func expandRepositoriesSelectors(aggregator RepoAggregator, gitParamsFromEnv *Git, commandName string) (RepoAggregator, error) {
+	configuredRepositories := make(map[string]bool)
+	hasSelectors := false
+	for _, repository := range aggregator {
+		if repository.Repositories == nil {
+			configuredRepositories[repository.RepoName] = true
+			continue
+		}
+		if commandName != ScanMultipleRepositories && commandName != FixCampaign {
+			return nil, fmt.Errorf("selecting the repositories by patterns is supported only by the %s and %s commands", ScanMultipleRepositories, FixCampaign)
+		}
+		if repository.RepoName != "" {
+			return nil, fmt.Errorf("the repository name '%s' and the repositories selector can't be configured together", repository.RepoName)
+		}
+		if err := repository.Repositories.validate(); err != nil {
+			return nil, err
+		}
+		hasSelectors = true
+	}
+	if !hasSelectors {
+		return aggregator, nil
+	}
+	lister, err := repodiscovery.NewLister(gitParamsFromEnv.GitProvider, gitParamsFromEnv.VcsInfo, gitParamsFromEnv.RepoOwner)
+	if err != nil {
+		return nil, err
+	}
+	ownerRepositories, err := lister.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the repositories of '%s': %s", gitParamsFromEnv.RepoOwner, err.Error())
+	}
+	var expanded RepoAggregator
+	for _, repository := range aggregator {
+		if repository.Repositories == nil {
+			expanded = append(expanded, repository)
+			continue
+		}
+		selectedNames := repository.Repositories.Select(gitParamsFromEnv.RepoOwner, ownerRepositories)
+		if len(selectedNames) == 0 {
+			log.Warn(fmt.Sprintf("No repository of '%s' matches the patterns: %s", gitParamsFromEnv.RepoOwner, strings.Join(repository.Repositories.Include, ", ")))
+		}
+		for _, repoName := range selectedNames {
+			if configuredRepositories[repoName] {
+				log.Debug(fmt.Sprintf("The '%s' repository is selected by a pattern and configured by its name. Its configured params are used", repoName))
+				continue
+			}
+			configuredRepositories[repoName] = true
+			selected, err := copyRepositoryParams(repository)
+			if err != nil {
+				return nil, err
+			}
+			selected.RepoName = repoName
+			selected.Repositories = nil
+			expanded = append(expanded, selected)
+		}
+	}
+	log.Info(fmt.Sprintf("The config selects %d repositories of '%s'", len(expanded), gitParamsFromEnv.RepoOwner))
+	return expanded, nil
+}
+
+// The params of each selected repository are a deep copy, since the defaults are set for each repository separately
+func copyRepositoryParams(repository Repository) (copied Repository, err error) {
+	content, err := yaml.Marshal(repository.Params)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(content, &copied.Params)
+	return
+}
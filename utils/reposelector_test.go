@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/repodiscovery"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoriesSelectorSelect(t *testing.T) {
+	repositories := []repodiscovery.Repository{
+		{Name: "team-web"},
+		{Name: "team-api"},
+		{Name: "team-legacy"},
+		{Name: "team-archived", Archived: true},
+		{Name: "team-fork", Fork: true},
+		{Name: "frogbot"},
+	}
+	testCases := []struct {
+		name     string
+		selector RepositoriesSelector
+		expected []string
+	}{
+		{name: "Name pattern", selector: RepositoriesSelector{Include: []string{"team-*"}}, expected: []string{"team-api", "team-legacy", "team-web"}},
+		{name: "Full name pattern", selector: RepositoriesSelector{Include: []string{"jfrog/team-*", "other/frogbot"}}, expected: []string{"team-api", "team-legacy", "team-web"}},
+		{name: "Excluded repositories", selector: RepositoriesSelector{Include: []string{"team-*", "frogbot"}, Exclude: []string{"jfrog/team-legacy", "team-w*"}}, expected: []string{"frogbot", "team-api"}},
+		{name: "Archived and forked repositories", selector: RepositoriesSelector{Include: []string{"team-*"}, IncludeArchived: true, IncludeForks: true}, expected: []string{"team-api", "team-archived", "team-fork", "team-legacy", "team-web"}},
+		{name: "No matches", selector: RepositoriesSelector{Include: []string{"other-*"}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.selector.Select("jfrog", repositories))
+		})
+	}
+}
+
+func TestBuildRepoAggregatorWithRepositoriesSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/orgs/jfrog/repos", r.URL.Path)
+		_, err := w.Write([]byte(`[{"name":"team-api"},{"name":"team-web"},{"name":"team-old","archived":true},{"name":"team-legacy"},{"name":"frogbot"}]`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	configFileContent, err := os.ReadFile(filepath.Join("..", "testdata", "config", "frogbot-config-repositories-selector.yml"))
+	require.NoError(t, err)
+	gitParams := &Git{GitProvider: vcsutils.GitHub, VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, RepoOwner: "jfrog"}
+	repoAggregator, err := BuildRepoAggregator("xrayVersion", "xscVersion", nil, configFileContent, gitParams, &coreconfig.ServerDetails{}, ScanMultipleRepositories)
+	require.NoError(t, err)
+	require.Len(t, repoAggregator, 3)
+	// The repository that is configured by its name keeps its params
+	assert.Equal(t, "frogbot", repoAggregator[0].RepoName)
+	assert.Equal(t, "team-api", repoAggregator[1].RepoName)
+	assert.Equal(t, "team-web", repoAggregator[2].RepoName)
+	assert.Equal(t, []string{"dev"}, repoAggregator[2].Branches)
+	for _, repository := range repoAggregator[:2] {
+		assert.Nil(t, repository.Repositories)
+		assert.Equal(t, []string{"master"}, repository.Branches)
+		assert.Equal(t, []string{"api"}, repository.Projects[0].WorkingDirs)
+	}
+	// The selected repositories don't share their params
+	repoAggregator[0].Projects[0].WorkingDirs[0] = "web"
+	assert.Equal(t, []string{"api"}, repoAggregator[1].Projects[0].WorkingDirs)
+}
+
+func TestBuildRepoAggregatorWithInvalidRepositoriesSelector(t *testing.T) {
+	gitParams := &Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", RepoName: "frogbot", Branches: []string{"master"}}
+	testCases := []struct {
+		name          string
+		config        string
+		commandName   string
+		expectedError string
+	}{
+		{name: "Single repository command", config: `[{params: {git: {repositories: {include: ["team-*"]}}}}]`, commandName: ScanRepository, expectedError: "supported only by"},
+		{name: "Repository name and selector", config: `[{params: {git: {repoName: frogbot, repositories: {include: ["team-*"]}}}}]`, commandName: ScanMultipleRepositories, expectedError: "can't be configured together"},
+		{name: "No include patterns", config: `[{params: {git: {repositories: {exclude: ["team-*"]}}}}]`, commandName: ScanMultipleRepositories, expectedError: "at least one pattern"},
+		{name: "Invalid pattern", config: `[{params: {git: {repositories: {include: ["team-["]}}}}]`, commandName: ScanMultipleRepositories, expectedError: "invalid repositories pattern"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := BuildRepoAggregator("xrayVersion", "xscVersion", nil, []byte(tc.config), gitParams, &coreconfig.ServerDetails{}, tc.commandName)
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
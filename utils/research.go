@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/research"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+)
+
+// The provider is shared by all the scanned projects, so each CVE is requested once per run
+var (
+	researchProvider      *research.Provider
+	researchProviderMutex sync.Mutex
+)
+
+func getResearchProvider(serverDetails *config.ServerDetails) *research.Provider {
+	researchProviderMutex.Lock()
+	defer researchProviderMutex.Unlock()
+	if researchProvider == nil {
+		researchProvider = research.NewProvider(serverDetails)
+	}
+	return researchProvider
+}
+
+// Returns the full JFrog research data of the CVEs of the SCA vulnerabilities and violations
+func GetIssuesResearchDetails(serverDetails *config.ServerDetails, issuesCollection *issues.ScansIssuesCollection) map[string]research.Details {
+	cves := datastructures.MakeSet[string]()
+	for _, rows := range [][]formats.VulnerabilityOrViolationRow{issuesCollection.ScaVulnerabilities, issuesCollection.ScaViolations} {
+		for _, row := range rows {
+			for _, cve := range row.Cves {
+				if cve.Id != "" {
+					cves.Add(cve.Id)
+				}
+			}
+		}
+	}
+	if cves.Size() == 0 {
+		return nil
+	}
+	return getResearchProvider(serverDetails).GetDetails(cves.ToSlice())
+}
@@ -0,0 +1,101 @@
+package research
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The Xray API of the full JFrog research data of a CVE
+const cveResearchApiUrl = "api/v1/research/cves/"
+
+// Details are the JFrog research data of a CVE that the scan results don't include
+type Details struct {
+	// How mature the known exploits of the CVE are, for example: 'Proof of Concept' or 'Weaponized'
+	ExploitMaturity string `json:"exploit_maturity,omitempty"`
+	// The context the CVE is exploited from, for example: 'Network' or 'Local'
+	AttackVector string `json:"attack_vector,omitempty"`
+	// The remediation steps of the CVE, including the mitigations that don't require upgrading
+	ExtendedRemediation string `json:"extended_remediation,omitempty"`
+}
+
+func (d Details) IsEmpty() bool {
+	return d.ExploitMaturity == "" && d.AttackVector == "" && d.ExtendedRemediation == ""
+}
+
+// Provider gets the JFrog research data of CVEs from Xray.
+// The responses are cached in memory, so each CVE is requested once per run, even if several projects have it.
+type Provider struct {
+	serverDetails *config.ServerDetails
+	// The research data by the requested CVEs, nil if the CVE has no research data
+	cache map[string]*Details
+	mutex sync.Mutex
+}
+
+func NewProvider(serverDetails *config.ServerDetails) *Provider {
+	return &Provider{serverDetails: serverDetails, cache: make(map[string]*Details)}
+}
+
+// Returns the research data of the given CVEs, the CVEs without research data are omitted.
+// Failing to get the data is logged as a warning, and the remaining CVEs aren't requested.
+func (p *Provider) GetDetails(cves []string) map[string]Details {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	details := make(map[string]Details)
+	var err error
+	for _, cve := range cves {
+		cached, exists := p.cache[cve]
+		if !exists && err == nil {
+			if cached, err = p.requestDetails(cve); err != nil {
+				log.Warn(fmt.Sprintf("Couldn't get the JFrog research data of %s, so the remaining CVEs aren't enriched either: %s", cve, err.Error()))
+				continue
+			}
+			p.cache[cve] = cached
+		}
+		if cached != nil {
+			details[cve] = *cached
+		}
+	}
+	return details
+}
+
+// Returns nil if Xray has no research data of the CVE
+func (p *Provider) requestDetails(cve string) (*Details, error) {
+	if p.serverDetails == nil {
+		return nil, errors.New("the JFrog server details are missing")
+	}
+	xrayManager, err := xray.CreateXrayServiceManager(p.serverDetails)
+	if err != nil {
+		return nil, err
+	}
+	xrayDetails := xrayManager.Config().GetServiceDetails()
+	httpClientDetails := xrayDetails.CreateHttpClientDetails()
+	resp, body, _, err := xrayManager.Client().SendGet(clientutils.AddTrailingSlashIfNeeded(xrayDetails.GetUrl())+cveResearchApiUrl+url.PathEscape(cve), true, &httpClientDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		log.Debug("Xray has no research data of", cve)
+		return nil, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	details := &Details{}
+	if err = errorutils.CheckError(json.Unmarshal(body, details)); err != nil {
+		return nil, err
+	}
+	if details.IsEmpty() {
+		return nil, nil
+	}
+	return details, nil
+}
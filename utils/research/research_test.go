@@ -0,0 +1,52 @@
+package research
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDetails(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.True(t, strings.HasPrefix(r.URL.Path, "/xray/api/v1/research/cves/"))
+		cve := strings.TrimPrefix(r.URL.Path, "/xray/api/v1/research/cves/")
+		requests[cve]++
+		if cve != "CVE-2021-44228" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(`{"exploit_maturity":"Weaponized","attack_vector":"Network","extended_remediation":"Upgrade to 2.17.1, or remove the JndiLookup class from the classpath"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	provider := NewProvider(&config.ServerDetails{XrayUrl: server.URL + "/xray/", AccessToken: "token"})
+	expected := map[string]Details{"CVE-2021-44228": {ExploitMaturity: "Weaponized", AttackVector: "Network", ExtendedRemediation: "Upgrade to 2.17.1, or remove the JndiLookup class from the classpath"}}
+	assert.Equal(t, expected, provider.GetDetails([]string{"CVE-2021-44228", "CVE-2021-44906"}))
+
+	// The responses are cached, including the CVEs without research data
+	assert.Equal(t, expected, provider.GetDetails([]string{"CVE-2021-44906", "CVE-2021-44228"}))
+	assert.Equal(t, map[string]int{"CVE-2021-44228": 1, "CVE-2021-44906": 1}, requests)
+}
+
+func TestGetDetailsFailure(t *testing.T) {
+	requestsCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestsCount++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	provider := NewProvider(&config.ServerDetails{XrayUrl: server.URL + "/xray/", AccessToken: "token"})
+	assert.Empty(t, provider.GetDetails([]string{"CVE-2021-44228", "CVE-2021-44906"}))
+	// The remaining CVEs aren't requested after a failure, and the failures aren't cached
+	assert.Equal(t, 1, requestsCount)
+	assert.Empty(t, provider.GetDetails([]string{"CVE-2021-44228"}))
+	assert.Equal(t, 2, requestsCount)
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type RunExitReason string
+
+const (
+	ExitClean         RunExitReason = "clean"
+	ExitPolicyFailure RunExitReason = "policy_failure"
+	ExitInternalError RunExitReason = "internal_error"
+
+	// The version of the JSON schema of the run summary. Fields may be added to the schema, but aren't renamed or removed without bumping it.
+	RunSummarySchemaVersion = 1
+	// The prefix of the run summary line that is printed to the standard output, so CI jobs can find it
+	RunSummaryLinePrefix = "FROGBOT_RUN_SUMMARY "
+)
+
+var (
+	ExitCodeClean         = coreutils.ExitCodeNoError
+	ExitCodeInternalError = coreutils.ExitCodeError
+	ExitCodePolicyFailure = coreutils.ExitCode{Code: 2}
+)
+
+// RunSummary is the machine-readable outcome of a Frogbot run, which CI jobs can branch on without parsing the logs.
+// It is printed as a single line to the standard output when the command finishes, and written to a file if one is configured.
+type RunSummary struct {
+	mutex  sync.Mutex
+	file   string
+	output io.Writer
+
+	SchemaVersion int           `json:"schemaVersion"`
+	Command       string        `json:"command"`
+	ExitCode      int           `json:"exitCode"`
+	ExitReason    RunExitReason `json:"exitReason"`
+	Error         string        `json:"error,omitempty"`
+	// The number of new vulnerabilities and violations found, by severity
+	Vulnerabilities map[string]int `json:"vulnerabilities"`
+	// The number of pull requests that Frogbot opened and updated
+	PullRequestsCreated int `json:"pullRequestsCreated"`
+	PullRequestsUpdated int `json:"pullRequestsUpdated"`
+}
+
+// The summary of the current run, nil if no command is running
+var runSummary *RunSummary
+
+// Starts collecting the summary of the run. Must be called before the environment variables are sanitized.
+func StartRunSummary(command string) {
+	runSummary = newRunSummary(command, getTrimmedEnv(RunSummaryFileEnv), os.Stdout)
+}
+
+func newRunSummary(command, file string, output io.Writer) *RunSummary {
+	return &RunSummary{
+		file:            file,
+		output:          output,
+		SchemaVersion:   RunSummarySchemaVersion,
+		Command:         command,
+		Vulnerabilities: map[string]int{},
+	}
+}
+
+// Prints the summary of the run and writes it to the configured file. Failing to write the summary doesn't fail the run.
+// Returns the error of the run, with the exit code of its reason.
+func FinishRunSummary(runErr error) error {
+	if runSummary == nil {
+		return runErr
+	}
+	summary := runSummary
+	runSummary = nil
+	exitCode := summary.setOutcome(runErr)
+	if err := summary.write(); err != nil {
+		log.Warn("Failed to write the run summary:", err.Error())
+	}
+	if exitCode == ExitCodePolicyFailure {
+		return coreutils.CliError{ExitCode: exitCode, ErrorMsg: runErr.Error()}
+	}
+	return runErr
+}
+
+// The run fails with a policy failure only if all its errors are policy failures, so an internal error isn't hidden by the issues that were found
+func (rs *RunSummary) setOutcome(runErr error) coreutils.ExitCode {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	exitCode, exitReason := ExitCodeClean, ExitClean
+	if runErr != nil {
+		rs.Error = runErr.Error()
+		exitCode, exitReason = ExitCodeInternalError, ExitInternalError
+		if isPolicyFailure(runErr) {
+			exitCode, exitReason = ExitCodePolicyFailure, ExitPolicyFailure
+		}
+	}
+	rs.ExitCode, rs.ExitReason = exitCode.Code, exitReason
+	return exitCode
+}
+
+func isPolicyFailure(err error) bool {
+	if joinedErr, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joinedErr.Unwrap() {
+			if !isPolicyFailure(e) {
+				return false
+			}
+		}
+		return true
+	}
+	var policyFailure *ErrPolicyFailure
+	return errors.As(err, &policyFailure)
+}
+
+func (rs *RunSummary) write() error {
+	rs.mutex.Lock()
+	content, err := json.Marshal(rs)
+	rs.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintln(rs.output, RunSummaryLinePrefix+string(content)); err != nil {
+		return err
+	}
+	if rs.file == "" {
+		return nil
+	}
+	log.Info("Writing the run summary to:", rs.file)
+	return os.WriteFile(rs.file, content, 0644)
+}
+
+func recordSummaryVulnerabilities(severityCounts map[string]int) {
+	if runSummary == nil {
+		return
+	}
+	runSummary.mutex.Lock()
+	defer runSummary.mutex.Unlock()
+	for severity, count := range severityCounts {
+		runSummary.Vulnerabilities[severity] += count
+	}
+}
+
+func recordSummaryPullRequest(action PullRequestAction) {
+	if runSummary == nil {
+		return
+	}
+	runSummary.mutex.Lock()
+	defer runSummary.mutex.Unlock()
+	switch action {
+	case PullRequestOpened:
+		runSummary.PullRequestsCreated++
+	case PullRequestUpdated:
+		runSummary.PullRequestsUpdated++
+	}
+}
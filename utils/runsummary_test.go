@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinishRunSummary(t *testing.T) {
+	defer func() {
+		runSummary = nil
+	}()
+	policyFailure := &ErrPolicyFailure{Message: "issues were detected by Frogbot"}
+	testCases := []struct {
+		name               string
+		runErr             error
+		expectedExitCode   coreutils.ExitCode
+		expectedExitReason RunExitReason
+	}{
+		{name: "Clean", expectedExitCode: ExitCodeClean, expectedExitReason: ExitClean},
+		{name: "Policy failure", runErr: policyFailure, expectedExitCode: ExitCodePolicyFailure, expectedExitReason: ExitPolicyFailure},
+		{name: "Policy failures of several pull requests", runErr: errors.Join(fmt.Errorf("pull request #1: %w", policyFailure), fmt.Errorf("pull request #2: %w", policyFailure)), expectedExitCode: ExitCodePolicyFailure, expectedExitReason: ExitPolicyFailure},
+		{name: "Internal error", runErr: errors.New("audit failed"), expectedExitCode: ExitCodeInternalError, expectedExitReason: ExitInternalError},
+		{name: "Internal error and policy failure", runErr: errors.Join(policyFailure, errors.New("audit failed")), expectedExitCode: ExitCodeInternalError, expectedExitReason: ExitInternalError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			summaryFile := filepath.Join(t.TempDir(), "frogbot-summary.json")
+			runSummary = newRunSummary(ScanPullRequest, summaryFile, output)
+			RecordIssues(&issues.ScansIssuesCollection{
+				ScaVulnerabilities:  []formats.VulnerabilityOrViolationRow{{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "High"}}}},
+				SastVulnerabilities: []formats.SourceCodeRow{{SeverityDetails: formats.SeverityDetails{Severity: "High"}}, {SeverityDetails: formats.SeverityDetails{Severity: "Low"}}},
+			})
+			recordPullRequest(PullRequestOpened)
+			recordPullRequest(PullRequestUpdated)
+			recordPullRequest(PullRequestUpdated)
+
+			err := FinishRunSummary(tc.runErr)
+			assert.Nil(t, runSummary)
+			var cliError coreutils.CliError
+			if tc.expectedExitCode == ExitCodePolicyFailure {
+				require.ErrorAs(t, err, &cliError)
+				assert.Equal(t, ExitCodePolicyFailure, cliError.ExitCode)
+				assert.Equal(t, tc.runErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tc.runErr, err)
+			}
+
+			line := strings.TrimSpace(output.String())
+			require.True(t, strings.HasPrefix(line, RunSummaryLinePrefix), line)
+			fileContent, err := os.ReadFile(summaryFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, strings.TrimPrefix(line, RunSummaryLinePrefix), string(fileContent))
+
+			var summary RunSummary
+			require.NoError(t, json.Unmarshal(fileContent, &summary))
+			assert.Equal(t, RunSummarySchemaVersion, summary.SchemaVersion)
+			assert.Equal(t, ScanPullRequest, summary.Command)
+			assert.Equal(t, tc.expectedExitCode.Code, summary.ExitCode)
+			assert.Equal(t, tc.expectedExitReason, summary.ExitReason)
+			assert.Equal(t, map[string]int{"High": 2, "Low": 1}, summary.Vulnerabilities)
+			assert.Equal(t, 1, summary.PullRequestsCreated)
+			assert.Equal(t, 2, summary.PullRequestsUpdated)
+			if tc.runErr != nil {
+				assert.Equal(t, tc.runErr.Error(), summary.Error)
+			}
+		})
+	}
+}
+
+func TestFinishRunSummaryNotStarted(t *testing.T) {
+	runErr := &ErrPolicyFailure{Message: "issues were detected by Frogbot"}
+	assert.Equal(t, runErr, FinishRunSummary(runErr))
+}
@@ -0,0 +1,214 @@
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const frogbotToolName = "frogbot"
+
+// Maps the package type prefix of Xray component IDs to the matching package URL type
+var purlTypes = map[string]string{
+	"npm":       "npm",
+	"gav":       "maven",
+	"pypi":      "pypi",
+	"go":        "golang",
+	"nuget":     "nuget",
+	"rubygems":  "gem",
+	"gem":       "gem",
+	"composer":  "composer",
+	"cocoapods": "cocoapods",
+	"conan":     "conan",
+	"cargo":     "cargo",
+	"deb":       "deb",
+	"rpm":       "rpm",
+	"alpine":    "apk",
+	"docker":    "docker",
+	"github":    "github",
+}
+
+// CycloneDxBuilder collects the components found by the SCA scans into a CycloneDX SBOM
+type CycloneDxBuilder struct {
+	// Maps the Xray component ID to its SBOM component
+	components map[string]*cdx.Component
+}
+
+func NewCycloneDxBuilder() *CycloneDxBuilder {
+	return &CycloneDxBuilder{components: map[string]*cdx.Component{}}
+}
+
+// Adds the components of all the SCA scans in the given results
+func (b *CycloneDxBuilder) AddScanResults(scanResults *results.SecurityCommandResults) {
+	if scanResults == nil {
+		return
+	}
+	for _, target := range scanResults.Targets {
+		if target.ScaResults == nil {
+			continue
+		}
+		for _, xrayResult := range target.ScaResults.XrayResults {
+			b.addScanResponse(xrayResult.Scan)
+		}
+	}
+}
+
+func (b *CycloneDxBuilder) addScanResponse(response services.ScanResponse) {
+	for _, vulnerability := range response.Vulnerabilities {
+		b.addComponents(vulnerability.Components)
+	}
+	for _, violation := range response.Violations {
+		b.addComponents(violation.Components)
+	}
+	for _, license := range response.Licenses {
+		for _, component := range b.addComponents(license.Components) {
+			addLicense(component, license.Key)
+		}
+	}
+}
+
+// Adds the given components and the dependencies that brought them into the project.
+// Returns the SBOM components of the given component IDs.
+func (b *CycloneDxBuilder) addComponents(components map[string]services.Component) (added []*cdx.Component) {
+	for componentId, component := range components {
+		added = append(added, b.addComponent(componentId))
+		for _, impactPath := range component.ImpactPaths {
+			// The first node of each impact path is the scanned project itself
+			for i := 1; i < len(impactPath); i++ {
+				b.addComponent(impactPath[i].ComponentId)
+			}
+		}
+	}
+	return
+}
+
+func (b *CycloneDxBuilder) addComponent(componentId string) *cdx.Component {
+	if component, exists := b.components[componentId]; exists {
+		return component
+	}
+	component := toCycloneDxComponent(componentId)
+	b.components[componentId] = component
+	return component
+}
+
+func (b *CycloneDxBuilder) IsEmpty() bool {
+	return len(b.components) == 0
+}
+
+// Builds the SBOM. The components are sorted by their reference, so the same scan results always produce the same inventory.
+func (b *CycloneDxBuilder) Build(subject, frogbotVersion string) *cdx.BOM {
+	components := make([]cdx.Component, 0, len(b.components))
+	for _, component := range b.components {
+		components = append(components, *component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].BOMRef < components[j].BOMRef
+	})
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Tools: &cdx.ToolsChoice{
+			Components: &[]cdx.Component{{Type: cdx.ComponentTypeApplication, Name: frogbotToolName, Version: frogbotVersion}},
+		},
+	}
+	if subject != "" {
+		bom.Metadata.Component = &cdx.Component{Type: cdx.ComponentTypeApplication, BOMRef: subject, Name: subject}
+	}
+	bom.Components = &components
+	return bom
+}
+
+// Writes the SBOM in the CycloneDX JSON format to the given path
+func (b *CycloneDxBuilder) Write(sbomPath, subject, frogbotVersion string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(sbomPath), 0755); errorutils.CheckError(err) != nil {
+		return
+	}
+	sbomFile, err := os.Create(sbomPath)
+	if errorutils.CheckError(err) != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(sbomFile.Close()))
+	}()
+	if err = cdx.NewBOMEncoder(sbomFile, cdx.BOMFileFormatJSON).SetPretty(true).Encode(b.Build(subject, frogbotVersion)); errorutils.CheckError(err) != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("A CycloneDX SBOM with %d components was written to: %s", len(b.components), sbomPath))
+	return
+}
+
+func toCycloneDxComponent(componentId string) *cdx.Component {
+	component := &cdx.Component{Type: cdx.ComponentTypeLibrary, BOMRef: componentId}
+	component.Name, component.Version, _ = techutils.SplitComponentId(componentId)
+	packageType, _, found := strings.Cut(componentId, "://")
+	if !found {
+		return component
+	}
+	if packageType == "gav" {
+		// Maven identifier structure: gav://group:artifact:version
+		if group, artifact, found := strings.Cut(component.Name, ":"); found {
+			component.Group, component.Name = group, artifact
+		}
+	}
+	component.PackageURL = toPackageUrl(packageType, component.Group, component.Name, component.Version)
+	return component
+}
+
+// Returns the package URL (purl) of the component, or an empty string for unsupported package types
+func toPackageUrl(packageType, namespace, name, version string) string {
+	purlType, exists := purlTypes[packageType]
+	if !exists {
+		return ""
+	}
+	if namespace == "" {
+		// Scoped npm packages and Go modules keep their namespace in the name, for example: @types/node
+		if lastSlashIndex := strings.LastIndex(name, "/"); lastSlashIndex != -1 {
+			namespace, name = name[:lastSlashIndex], name[lastSlashIndex+1:]
+		}
+	}
+	purl := "pkg:" + purlType + "/"
+	if namespace != "" {
+		purl += escapePath(namespace) + "/"
+	}
+	purl += url.PathEscape(name)
+	if version != "" {
+		purl += "@" + url.PathEscape(version)
+	}
+	return purl
+}
+
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func addLicense(component *cdx.Component, licenseKey string) {
+	if licenseKey == "" {
+		return
+	}
+	if component.Licenses == nil {
+		component.Licenses = &cdx.Licenses{}
+	}
+	for _, license := range *component.Licenses {
+		if license.License != nil && license.License.Name == licenseKey {
+			return
+		}
+	}
+	// Xray license keys aren't always valid SPDX IDs, so they are reported as license names
+	*component.Licenses = append(*component.Licenses, cdx.LicenseChoice{License: &cdx.License{Name: licenseKey}})
+}
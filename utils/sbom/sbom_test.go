@@ -0,0 +1,93 @@
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestScanResults() *results.SecurityCommandResults {
+	scanResults := results.NewCommandResults("")
+	target := scanResults.NewScanResults(results.ScanTarget{Target: "target"})
+	target.NewScaScanResults(0, services.ScanResponse{
+		Vulnerabilities: []services.Vulnerability{{
+			IssueId: "XRAY-1",
+			Components: map[string]services.Component{
+				"npm://minimist:1.2.5": {ImpactPaths: [][]services.ImpactPathNode{{
+					{ComponentId: "npm://my-project:1.0.0"},
+					{ComponentId: "npm://@types/mkdirp:0.5.1"},
+					{ComponentId: "npm://minimist:1.2.5"},
+				}}},
+			},
+		}},
+		Licenses: []services.License{{
+			Key: "MIT",
+			Components: map[string]services.Component{
+				"npm://minimist:1.2.5":                             {},
+				"gav://org.apache.logging.log4j:log4j-core:2.14.0": {},
+			},
+		}},
+	})
+	return scanResults
+}
+
+func TestBuild(t *testing.T) {
+	builder := NewCycloneDxBuilder()
+	assert.True(t, builder.IsEmpty())
+	builder.AddScanResults(getTestScanResults())
+	bom := builder.Build("owner/repo", "1.0.0")
+
+	require.NotNil(t, bom.Metadata)
+	assert.Equal(t, "owner/repo", bom.Metadata.Component.Name)
+	require.NotNil(t, bom.Metadata.Tools.Components)
+	assert.Equal(t, []cdx.Component{{Type: cdx.ComponentTypeApplication, Name: frogbotToolName, Version: "1.0.0"}}, *bom.Metadata.Tools.Components)
+
+	require.NotNil(t, bom.Components)
+	expected := []cdx.Component{
+		{Type: cdx.ComponentTypeLibrary, BOMRef: "gav://org.apache.logging.log4j:log4j-core:2.14.0", Group: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.0", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.0", Licenses: &cdx.Licenses{{License: &cdx.License{Name: "MIT"}}}},
+		{Type: cdx.ComponentTypeLibrary, BOMRef: "npm://@types/mkdirp:0.5.1", Name: "@types/mkdirp", Version: "0.5.1", PackageURL: "pkg:npm/@types/mkdirp@0.5.1"},
+		{Type: cdx.ComponentTypeLibrary, BOMRef: "npm://minimist:1.2.5", Name: "minimist", Version: "1.2.5", PackageURL: "pkg:npm/minimist@1.2.5", Licenses: &cdx.Licenses{{License: &cdx.License{Name: "MIT"}}}},
+	}
+	assert.Equal(t, expected, *bom.Components)
+}
+
+func TestToPackageUrl(t *testing.T) {
+	testCases := []struct {
+		componentId string
+		expected    string
+	}{
+		{componentId: "pypi://pyjwt:1.7.1", expected: "pkg:pypi/pyjwt@1.7.1"},
+		{componentId: "go://github.com/gin-gonic/gin:v1.7.0", expected: "pkg:golang/github.com/gin-gonic/gin@v1.7.0"},
+		{componentId: "nuget://Newtonsoft.Json:12.0.1", expected: "pkg:nuget/Newtonsoft.Json@12.0.1"},
+		{componentId: "generic://sha256:1234/file.zip", expected: ""},
+		{componentId: "no-package-type", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.componentId, func(t *testing.T) {
+			assert.Equal(t, tc.expected, toCycloneDxComponent(tc.componentId).PackageURL)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	builder := NewCycloneDxBuilder()
+	builder.AddScanResults(getTestScanResults())
+	sbomPath := filepath.Join(t.TempDir(), "sbom", "frogbot-sbom.json")
+	require.NoError(t, builder.Write(sbomPath, "owner/repo", "1.0.0"))
+
+	sbomFile, err := os.Open(sbomPath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, sbomFile.Close())
+	}()
+	bom := cdx.NewBOM()
+	require.NoError(t, cdx.NewBOMDecoder(sbomFile, cdx.BOMFileFormatJSON).Decode(bom))
+	assert.Equal(t, cdx.BOMFormat, bom.BOMFormat)
+	assert.Len(t, *bom.Components, 3)
+}
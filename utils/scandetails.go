@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	clientservices "github.com/jfrog/jfrog-client-go/xsc/services"
@@ -11,9 +13,12 @@ import (
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-security/commands/audit"
+	"github.com/jfrog/jfrog-cli-security/jas"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/results"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xscservices "github.com/jfrog/jfrog-client-go/xsc/services"
 )
@@ -28,11 +33,23 @@ type ScanDetails struct {
 	failOnInstallationErrors bool
 	fixableOnly              bool
 	disableJas               bool
+	jasScanners              JasScanners
+	excludePatterns          []string
 	skipAutoInstall          bool
 	minSeverityFilter        severityutils.Severity
 	baseBranch               string
 	configProfile            *clientservices.ConfigProfile
 	allowPartialResults      bool
+	targetCves               []string
+	packageHandlerPlugins    map[string]string
+	jasEntitlementChecked    bool
+	jasStatusUnknown         bool
+	// The reason that the JAS scans were skipped, since the Xray version doesn't support them
+	jasUnsupportedReason    string
+	capabilities            Capabilities
+	directDependencies      []string
+	resultsContextParams    *resultsContextParams
+	repositoryResultContext results.ResultContext
 
 	results.ResultContext
 	MultiScanId string
@@ -41,6 +58,16 @@ type ScanDetails struct {
 	StartTime   time.Time
 }
 
+// The parameters the results context of the repository is created with,
+// so it can be created again for projects that are scanned with their own watches or JFrog project
+type resultsContextParams struct {
+	httpCloneUrl           string
+	watches                []string
+	jfrogProjectKey        string
+	includeVulnerabilities bool
+	includeLicenses        bool
+}
+
 func NewScanDetails(client vcsclient.VcsClient, server *config.ServerDetails, git *Git) *ScanDetails {
 	return &ScanDetails{client: client, ServerDetails: server, Git: git}
 }
@@ -48,6 +75,7 @@ func NewScanDetails(client vcsclient.VcsClient, server *config.ServerDetails, gi
 func (sc *ScanDetails) SetJfrogVersions(xrayVersion, xscVersion string) *ScanDetails {
 	sc.XrayVersion = xrayVersion
 	sc.XscVersion = xscVersion
+	sc.capabilities = DetectCapabilities(xrayVersion, xscVersion)
 	return sc
 }
 
@@ -56,21 +84,60 @@ func (sc *ScanDetails) SetDisableJas(disable bool) *ScanDetails {
 	return sc
 }
 
+func (sc *ScanDetails) SetJasScanners(jasScanners JasScanners) *ScanDetails {
+	sc.jasScanners = jasScanners
+	return sc
+}
+
+// Sets the exclude patterns of the scanned repository, see GetExcludePatterns
+func (sc *ScanDetails) SetExcludePatterns(excludePatterns []string) *ScanDetails {
+	sc.excludePatterns = excludePatterns
+	return sc
+}
+
+func (sc *ScanDetails) ExcludePatterns() []string {
+	return sc.excludePatterns
+}
+
 func (sc *ScanDetails) SetFailOnInstallationErrors(toFail bool) *ScanDetails {
 	sc.failOnInstallationErrors = toFail
 	return sc
 }
 
+// Sets the project to scan.
+// A project with its own watches or JFrog project is scanned with them, instead of the ones of the repository.
 func (sc *ScanDetails) SetProject(project *Project) *ScanDetails {
 	sc.Project = project
+	if sc.resultsContextParams == nil {
+		return sc
+	}
+	if project == nil || !project.HasViolationsContext() {
+		sc.ResultContext = sc.repositoryResultContext
+		return sc
+	}
+	watches, jfrogProjectKey := project.GetViolationsContext(sc.resultsContextParams.watches, sc.resultsContextParams.jfrogProjectKey)
+	sc.ResultContext = sc.createResultsContext(watches, jfrogProjectKey)
 	return sc
 }
 
 func (sc *ScanDetails) SetResultsContext(httpCloneUrl string, watches []string, jfrogProjectKey string, includeVulnerabilities, includeLicenses bool) *ScanDetails {
-	sc.ResultContext = audit.CreateAuditResultsContext(sc.ServerDetails, sc.XrayVersion, watches, sc.RepoPath, jfrogProjectKey, httpCloneUrl, includeVulnerabilities, includeLicenses)
+	sc.resultsContextParams = &resultsContextParams{
+		httpCloneUrl:           httpCloneUrl,
+		watches:                watches,
+		jfrogProjectKey:        jfrogProjectKey,
+		includeVulnerabilities: includeVulnerabilities,
+		includeLicenses:        includeLicenses,
+	}
+	sc.ResultContext = sc.createResultsContext(watches, jfrogProjectKey)
+	sc.repositoryResultContext = sc.ResultContext
 	return sc
 }
 
+func (sc *ScanDetails) createResultsContext(watches []string, jfrogProjectKey string) results.ResultContext {
+	params := sc.resultsContextParams
+	return audit.CreateAuditResultsContext(sc.ServerDetails, sc.XrayVersion, watches, sc.RepoPath, jfrogProjectKey, params.httpCloneUrl, params.includeVulnerabilities, params.includeLicenses)
+}
+
 func (sc *ScanDetails) SetFixableOnly(fixable bool) *ScanDetails {
 	sc.fixableOnly = fixable
 	return sc
@@ -98,6 +165,16 @@ func (sc *ScanDetails) SetAllowPartialResults(allowPartialResults bool) *ScanDet
 	return sc
 }
 
+func (sc *ScanDetails) SetPackageHandlerPlugins(packageHandlerPlugins map[string]string) *ScanDetails {
+	sc.packageHandlerPlugins = packageHandlerPlugins
+	return sc
+}
+
+func (sc *ScanDetails) SetTargetCves(targetCves []string) *ScanDetails {
+	sc.targetCves = targetCves
+	return sc
+}
+
 func (sc *ScanDetails) SetBaseBranch(branch string) *ScanDetails {
 	sc.baseBranch = branch
 	return sc
@@ -146,24 +223,122 @@ func (sc *ScanDetails) AllowPartialResults() bool {
 	return sc.allowPartialResults
 }
 
+func (sc *ScanDetails) TargetCves() []string {
+	return sc.targetCves
+}
+
+// Returns the path of the package handler plugin of the technology, or an empty string if the technology has no plugin
+func (sc *ScanDetails) PackageHandlerPlugin(tech string) string {
+	for pluginTech, pluginPath := range sc.packageHandlerPlugins {
+		if strings.EqualFold(pluginTech, tech) {
+			return pluginPath
+		}
+	}
+	return ""
+}
+
+// Returns the direct dependencies found by the last audit, as Xray component IDs
+func (sc *ScanDetails) DirectDependencies() []string {
+	return sc.directDependencies
+}
+
+// Returns true if the JAS entitlement couldn't be checked, so the JAS scans were skipped and the scan coverage was reduced
+func (sc *ScanDetails) JasStatusUnknown() bool {
+	return sc.jasStatusUnknown
+}
+
+// Returns the reason that the JAS scans were skipped since the Xray version doesn't support them, or an empty string if they are supported
+func (sc *ScanDetails) JasUnsupportedReason() string {
+	return sc.jasUnsupportedReason
+}
+
+// Checks the JAS entitlement before the audit, as a failing entitlement request fails the whole audit.
+// If the entitlement can't be checked even after retries, the JAS scans are skipped and only the SCA scan runs.
+// The JAS scans are skipped as well if the Xray version doesn't support them.
+// The entitlement is checked once, for all the scans of the repository.
+func (sc *ScanDetails) shouldRunJas() bool {
+	if sc.DisableJas() || !sc.jasScanners.isAnyEnabled() {
+		return false
+	}
+	if !sc.capabilities.IsSupported(JasFeature) {
+		if sc.jasUnsupportedReason == "" {
+			sc.jasUnsupportedReason = sc.capabilities.UnsupportedReason(JasFeature)
+			log.Warn("The Xray version doesn't support JFrog Advanced Security, so the Advanced Security scans are skipped:", sc.jasUnsupportedReason)
+		}
+		return false
+	}
+	if !sc.jasEntitlementChecked {
+		sc.jasEntitlementChecked = true
+		if err := sc.checkJasEntitlement(); err != nil {
+			log.Warn("Couldn't check the JFrog Advanced Security entitlement, so the Advanced Security scans are skipped:", err.Error())
+			sc.jasStatusUnknown = true
+		}
+	}
+	return !sc.jasStatusUnknown
+}
+
+func (sc *ScanDetails) checkJasEntitlement() error {
+	return RunWithAccessTokenRefresh(sc.ServerDetails, sc.runJasEntitlementCheck)
+}
+
+func (sc *ScanDetails) runJasEntitlementCheck() error {
+	xrayManager, err := xray.CreateXrayServiceManager(sc.ServerDetails)
+	if err != nil {
+		return err
+	}
+	retryExecutor := clientutils.RetryExecutor{
+		MaxRetries:               jasEntitlementRetries,
+		RetriesIntervalMilliSecs: jasEntitlementRetriesIntervalMilliSecs,
+		ErrorMessage:             "Failed to check the JFrog Advanced Security entitlement",
+		ExecutionHandler: func() (shouldRetry bool, err error) {
+			_, err = jas.IsEntitledForJas(xrayManager, sc.XrayVersion)
+			return err != nil, err
+		},
+	}
+	return retryExecutor.Execute()
+}
+
+// Runs the audit of the working directories. If the audit fails since the JFrog access token expired during the run,
+// the token is refreshed and the audit runs again.
 func (sc *ScanDetails) RunInstallAndAudit(workDirs ...string) (auditResults *results.SecurityCommandResults) {
+	_ = RunWithAccessTokenRefresh(sc.ServerDetails, func() error {
+		auditResults = sc.runInstallAndAudit(workDirs...)
+		return auditResults.GetErrors()
+	})
+	return
+}
+
+func (sc *ScanDetails) runInstallAndAudit(workDirs ...string) (auditResults *results.SecurityCommandResults) {
+	restoreGoWorkspaceMode, err := disableGoWorkspaceModeIfNeeded(workDirs)
+	if err != nil {
+		log.Warn("Failed to detect whether the scanned directories belong to a Go workspace:", err.Error())
+	}
+	defer func() {
+		if e := restoreGoWorkspaceMode(); e != nil {
+			log.Warn("Failed to restore the workspace mode of the Go commands:", e.Error())
+		}
+	}()
+	installCommandName, installCommandArgs, requirementsFile := sc.getInstallCommand(workDirs)
 	auditBasicParams := (&utils.AuditBasicParams{}).
 		SetXrayVersion(sc.XrayVersion).
 		SetXscVersion(sc.XscVersion).
-		SetPipRequirementsFile(sc.PipRequirementsFile).
+		SetPipRequirementsFile(requirementsFile).
 		SetUseWrapper(*sc.UseWrapper).
 		SetMaxTreeDepth(sc.MaxPnpmTreeDepth).
 		SetDepsRepo(sc.DepsRepo).
 		SetIgnoreConfigFile(true).
 		SetServerDetails(sc.ServerDetails).
-		SetInstallCommandName(sc.InstallCommandName).
-		SetInstallCommandArgs(sc.InstallCommandArgs).
+		SetInstallCommandName(installCommandName).
+		SetInstallCommandArgs(installCommandArgs).
 		SetTechnologies(sc.GetTechFromInstallCmdIfExists()).
 		SetSkipAutoInstall(sc.skipAutoInstall).
 		SetAllowPartialResults(sc.allowPartialResults).
-		SetExclusions(sc.PathExclusions).
+		SetExclusions(append(slices.Clone(sc.PathExclusions), toScannersExclusions(sc.excludePatterns)...)).
 		SetIsRecursiveScan(sc.IsRecursiveScan).
-		SetUseJas(!sc.DisableJas())
+		SetUseJas(sc.shouldRunJas())
+	if scansToPerform := sc.getScansToPerform(); len(scansToPerform) > 0 {
+		auditBasicParams.SetScansToPerform(scansToPerform)
+	}
 
 	auditParams := audit.NewAuditParams().
 		SetWorkingDirs(workDirs).
@@ -175,7 +350,22 @@ func (sc *ScanDetails) RunInstallAndAudit(workDirs ...string) (auditResults *res
 		SetMultiScanId(sc.MultiScanId).
 		SetStartTime(sc.StartTime)
 
-	return audit.RunAudit(auditParams)
+	auditResults = audit.RunAudit(auditParams)
+	sc.directDependencies = *auditBasicParams.DirectDependencies()
+	return
+}
+
+// Returns the sub scans to perform, or nil to perform all of them
+func (sc *ScanDetails) getScansToPerform() []utils.SubScanType {
+	scansToPerform := sc.jasScanners.ScansToPerform()
+	if len(sc.targetCves) == 0 {
+		return scansToPerform
+	}
+	// Only CVEs are relevant when targeting specific CVEs, so the other scanners are skipped
+	if scansToPerform == nil || slices.Contains(scansToPerform, utils.ContextualAnalysisScan) {
+		return []utils.SubScanType{utils.ScaScan, utils.ContextualAnalysisScan}
+	}
+	return []utils.SubScanType{utils.ScaScan}
 }
 
 func (sc *ScanDetails) SetXscGitInfoContext(scannedBranch, gitProject string, client vcsclient.VcsClient) *ScanDetails {
@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"testing"
 
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,3 +22,98 @@ func TestGetFullPathWorkingDirs(t *testing.T) {
 		assert.Contains(t, fullPathWds, expectedWd)
 	}
 }
+
+func TestSetProjectResultsContext(t *testing.T) {
+	scanDetails := NewScanDetails(nil, &config.ServerDetails{}, &Git{}).
+		SetResultsContext("", []string{"watch-1"}, "proj", false, true)
+	repositoryResultContext := scanDetails.ResultContext
+	assert.Equal(t, []string{"watch-1"}, repositoryResultContext.Watches)
+	assert.Equal(t, "proj", repositoryResultContext.ProjectKey)
+
+	// A project with its own watches and JFrog project is scanned with them
+	scanDetails.SetProject(&Project{Watches: []string{"watch-2", "watch-3"}, JFrogProjectKey: "other-proj"})
+	assert.Equal(t, []string{"watch-2", "watch-3"}, scanDetails.ResultContext.Watches)
+	assert.Equal(t, "other-proj", scanDetails.ResultContext.ProjectKey)
+	assert.True(t, scanDetails.ResultContext.IncludeLicenses)
+
+	// The watches of the repository are used with the JFrog project of the project
+	scanDetails.SetProject(&Project{JFrogProjectKey: "other-proj"})
+	assert.Equal(t, []string{"watch-1"}, scanDetails.ResultContext.Watches)
+	assert.Equal(t, "other-proj", scanDetails.ResultContext.ProjectKey)
+
+	// A project without its own watches and JFrog project is scanned with the ones of the repository
+	scanDetails.SetProject(&Project{WorkingDirs: []string{"."}})
+	assert.Equal(t, repositoryResultContext, scanDetails.ResultContext)
+}
+
+func TestShouldRunJas(t *testing.T) {
+	previousInterval := jasEntitlementRetriesIntervalMilliSecs
+	jasEntitlementRetriesIntervalMilliSecs = 0
+	defer func() {
+		jasEntitlementRetriesIntervalMilliSecs = previousInterval
+	}()
+
+	disabled := false
+	testCases := []struct {
+		name                     string
+		disableJas               bool
+		jasScanners              JasScanners
+		xrayVersion              string
+		failedRequests           int
+		expectedRunJas           bool
+		expectedJasStatusUnknown bool
+		expectedJasUnsupported   bool
+		expectedRequests         int
+	}{
+		{name: "JAS disabled", disableJas: true},
+		{name: "All the JAS scanners disabled", jasScanners: JasScanners{EnableApplicability: &disabled, EnableSecrets: &disabled, EnableIac: &disabled, EnableSast: &disabled}},
+		{name: "Entitlement checked", expectedRunJas: true, expectedRequests: 1},
+		{name: "Entitlement checked after a retry", failedRequests: 1, expectedRunJas: true, expectedRequests: 2},
+		{name: "Entitlement check failed", failedRequests: jasEntitlementRetries + 1, expectedJasStatusUnknown: true, expectedRequests: jasEntitlementRetries + 1},
+		// The entitlement isn't checked if the Xray version doesn't support JAS
+		{name: "Xray version without JAS", xrayVersion: "3.60.0", expectedJasUnsupported: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/xray/api/v1/entitlements/feature/contextual_analysis", r.URL.Path)
+				requests++
+				if requests <= tc.failedRequests {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, err := w.Write([]byte(`{"feature_id":"contextual_analysis","entitled":true}`))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			xrayVersion := tc.xrayVersion
+			if xrayVersion == "" {
+				xrayVersion = "3.107.0"
+			}
+			scanDetails := NewScanDetails(nil, &config.ServerDetails{XrayUrl: server.URL + "/xray/", AccessToken: "token"}, &Git{}).SetJfrogVersions(xrayVersion, "").SetDisableJas(tc.disableJas).SetJasScanners(tc.jasScanners)
+			assert.Equal(t, tc.expectedRunJas, scanDetails.shouldRunJas())
+			assert.Equal(t, tc.expectedJasStatusUnknown, scanDetails.JasStatusUnknown())
+			assert.Equal(t, tc.expectedJasUnsupported, scanDetails.JasUnsupportedReason() != "")
+			// The entitlement is checked once for all the scans
+			assert.Equal(t, tc.expectedRunJas, scanDetails.shouldRunJas())
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
+
+func TestGetScansToPerform(t *testing.T) {
+	enabled, disabled := true, false
+	scanDetails := NewScanDetails(nil, &config.ServerDetails{}, &Git{})
+	assert.Nil(t, scanDetails.getScansToPerform())
+
+	scanDetails.SetJasScanners(JasScanners{EnableApplicability: &enabled, EnableSecrets: &enabled, EnableIac: &disabled, EnableSast: &disabled})
+	assert.Equal(t, []utils.SubScanType{utils.ScaScan, utils.ContextualAnalysisScan, utils.SecretsScan}, scanDetails.getScansToPerform())
+
+	// Only the SCA and the contextual analysis are relevant when targeting specific CVEs
+	scanDetails.SetTargetCves([]string{"CVE-2021-44228"})
+	assert.Equal(t, []utils.SubScanType{utils.ScaScan, utils.ContextualAnalysisScan}, scanDetails.getScansToPerform())
+	scanDetails.SetJasScanners(JasScanners{EnableApplicability: &disabled})
+	assert.Equal(t, []utils.SubScanType{utils.ScaScan}, scanDetails.getScansToPerform())
+}
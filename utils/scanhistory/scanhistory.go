@@ -0,0 +1,142 @@
+package scanhistory
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+)
+
+// The oldest summaries of each branch and project are removed beyond this number, to keep the history small
+const maxSummariesPerScope = 100
+
+// History holds the summaries of the scans of the scheduled runs, to track the vulnerabilities of the branches over time
+type History struct {
+	Summaries []Summary `json:"summaries"`
+}
+
+// Summary describes the vulnerabilities that a scan of a project in a branch found
+type Summary struct {
+	Time   time.Time `json:"time"`
+	Branch string    `json:"branch"`
+	// The working directories of the scanned project
+	Project string `json:"project"`
+	// The number of vulnerabilities of each severity
+	Severities map[string]int `json:"severities"`
+	// The CVE IDs of the vulnerabilities, or their Xray issue IDs if they have no CVE
+	Cves []string `json:"cves"`
+}
+
+// Delta is the change of the vulnerabilities since the last scan of the project in the branch
+type Delta struct {
+	New      []string
+	Resolved []string
+}
+
+func (d *Delta) String() string {
+	return fmt.Sprintf("+%d new, −%d resolved", len(d.New), len(d.Resolved))
+}
+
+// Summarizes the SCA vulnerabilities and violations of a scan
+func NewSummary(branch, project string, scanIssues *issues.ScansIssuesCollection) Summary {
+	summary := Summary{Time: time.Now(), Branch: branch, Project: project, Severities: map[string]int{}}
+	for _, row := range append(slices.Clone(scanIssues.ScaVulnerabilities), scanIssues.ScaViolations...) {
+		for _, id := range getIds(row) {
+			if slices.Contains(summary.Cves, id) {
+				continue
+			}
+			summary.Cves = append(summary.Cves, id)
+			summary.Severities[row.Severity]++
+		}
+	}
+	slices.Sort(summary.Cves)
+	return summary
+}
+
+func getIds(row formats.VulnerabilityOrViolationRow) (ids []string) {
+	for _, cve := range row.Cves {
+		if cve.Id != "" {
+			ids = append(ids, cve.Id)
+		}
+	}
+	if len(ids) == 0 && row.IssueId != "" {
+		ids = append(ids, row.IssueId)
+	}
+	return
+}
+
+// Loads the history from the storage. A missing history means that no scan was recorded yet.
+func Load(storage Storage) (*History, error) {
+	history := &History{}
+	content, err := storage.Read()
+	if err != nil || content == nil {
+		return history, err
+	}
+	if err = json.Unmarshal(content, history); err != nil {
+		return nil, fmt.Errorf("failed to parse the scan history: %s", err.Error())
+	}
+	return history, nil
+}
+
+func (h *History) Save(storage Storage) error {
+	content, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.Write(content)
+}
+
+// Adds the summary to the history, and returns the change since the last scan of the project in the branch.
+// Returns nil if the project wasn't scanned in the branch before.
+func (h *History) Add(summary Summary) (delta *Delta) {
+	var previous *Summary
+	scopeSummaries := 0
+	for i := range h.Summaries {
+		if h.Summaries[i].isSameScope(summary) {
+			previous = &h.Summaries[i]
+			scopeSummaries++
+		}
+	}
+	if previous != nil {
+		delta = &Delta{New: difference(summary.Cves, previous.Cves), Resolved: difference(previous.Cves, summary.Cves)}
+	}
+	// The oldest summaries of the scope are removed
+	exceeding := scopeSummaries + 1 - maxSummariesPerScope
+	summaries := make([]Summary, 0, len(h.Summaries)+1)
+	for _, existing := range h.Summaries {
+		if exceeding > 0 && existing.isSameScope(summary) {
+			exceeding--
+			continue
+		}
+		summaries = append(summaries, existing)
+	}
+	h.Summaries = append(summaries, summary)
+	return
+}
+
+// Returns the time of the latest scan in the history, or nil if no scan was recorded yet
+func (h *History) LastScanTime() (lastScanTime *time.Time) {
+	for i := range h.Summaries {
+		if lastScanTime == nil || h.Summaries[i].Time.After(*lastScanTime) {
+			lastScanTime = &h.Summaries[i].Time
+		}
+	}
+	return
+}
+
+func (s *Summary) isSameScope(other Summary) bool {
+	return s.Branch == other.Branch && s.Project == other.Project
+}
+
+// Returns the IDs of the first list that aren't in the second list
+func difference(ids, otherIds []string) (diff []string) {
+	for _, id := range ids {
+		if !slices.Contains(otherIds, id) {
+			diff = append(diff, id)
+		}
+	}
+	return
+}
@@ -0,0 +1,114 @@
+package scanhistory
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScanIssues(cvesBySeverity map[string][]string) *issues.ScansIssuesCollection {
+	scanIssues := &issues.ScansIssuesCollection{}
+	for severity, cves := range cvesBySeverity {
+		for _, cve := range cves {
+			row := formats.VulnerabilityOrViolationRow{IssueId: "XRAY-" + cve, ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: severity}}}
+			if cve != "" {
+				row.Cves = []formats.CveRow{{Id: cve}}
+			}
+			scanIssues.ScaVulnerabilities = append(scanIssues.ScaVulnerabilities, row)
+		}
+	}
+	return scanIssues
+}
+
+func TestNewSummary(t *testing.T) {
+	scanIssues := newScanIssues(map[string][]string{"Critical": {"CVE-2022-3517"}, "High": {"CVE-2021-44906", "CVE-2020-28469"}})
+	// The same CVE of another dependency is counted once
+	scanIssues.ScaViolations = []formats.VulnerabilityOrViolationRow{{Cves: []formats.CveRow{{Id: "CVE-2022-3517"}}, ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Critical"}}}}
+	// Vulnerabilities without a CVE are identified by their Xray issue ID
+	scanIssues.ScaVulnerabilities = append(scanIssues.ScaVulnerabilities, formats.VulnerabilityOrViolationRow{IssueId: "XRAY-264729", ImpactedDependencyDetails: formats.ImpactedDependencyDetails{SeverityDetails: formats.SeverityDetails{Severity: "Low"}}})
+
+	summary := NewSummary("main", ".", scanIssues)
+	assert.Equal(t, "main", summary.Branch)
+	assert.Equal(t, []string{"CVE-2020-28469", "CVE-2021-44906", "CVE-2022-3517", "XRAY-264729"}, summary.Cves)
+	assert.Equal(t, map[string]int{"Critical": 1, "High": 2, "Low": 1}, summary.Severities)
+}
+
+func TestHistoryAdd(t *testing.T) {
+	history := &History{}
+	assert.Nil(t, history.Add(Summary{Branch: "main", Project: ".", Cves: []string{"CVE-1", "CVE-2"}}))
+	// Each branch and project is tracked separately
+	assert.Nil(t, history.Add(Summary{Branch: "dev", Project: ".", Cves: []string{"CVE-1"}}))
+
+	delta := history.Add(Summary{Branch: "main", Project: ".", Cves: []string{"CVE-2", "CVE-3", "CVE-4"}})
+	require.NotNil(t, delta)
+	assert.Equal(t, []string{"CVE-3", "CVE-4"}, delta.New)
+	assert.Equal(t, []string{"CVE-1"}, delta.Resolved)
+	assert.Equal(t, "+2 new, −1 resolved", delta.String())
+	assert.Len(t, history.Summaries, 3)
+
+	// The oldest summaries of a branch and project are removed
+	for i := 0; i < maxSummariesPerScope; i++ {
+		history.Add(Summary{Time: time.Unix(int64(i), 0), Branch: "main", Project: "."})
+	}
+	assert.Len(t, history.Summaries, maxSummariesPerScope+1)
+	assert.Equal(t, "dev", history.Summaries[0].Branch)
+	assert.Equal(t, time.Unix(0, 0), history.Summaries[1].Time)
+}
+
+func TestFileStorage(t *testing.T) {
+	storage := NewStorage(filepath.Join(t.TempDir(), "history", "frogbot-history.json"), "", nil)
+	// A missing history is empty
+	history, err := Load(storage)
+	require.NoError(t, err)
+	assert.Empty(t, history.Summaries)
+
+	history.Add(Summary{Branch: "main", Project: ".", Cves: []string{"CVE-1"}, Severities: map[string]int{"High": 1}})
+	require.NoError(t, history.Save(storage))
+	loadedHistory, err := Load(storage)
+	require.NoError(t, err)
+	assert.Equal(t, "main", loadedHistory.Summaries[0].Branch)
+	assert.Equal(t, []string{"CVE-1"}, loadedHistory.Summaries[0].Cves)
+	assert.Nil(t, NewStorage("", "", nil))
+}
+
+func TestArtifactoryStorage(t *testing.T) {
+	var storedContent []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/artifactory/frogbot-generic/history/repo.json", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodGet:
+			if storedContent == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, err := w.Write(storedContent)
+			assert.NoError(t, err)
+		case http.MethodPut:
+			var err error
+			storedContent, err = io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+	storage := NewStorage("", "/frogbot-generic/history/repo.json", &config.ServerDetails{ArtifactoryUrl: server.URL + "/artifactory/", AccessToken: "token"})
+
+	history, err := Load(storage)
+	require.NoError(t, err)
+	assert.Empty(t, history.Summaries)
+	history.Add(Summary{Branch: "main", Project: ".", Cves: []string{"CVE-1"}})
+	require.NoError(t, history.Save(storage))
+	loadedHistory, err := Load(storage)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CVE-1"}, loadedHistory.Summaries[0].Cves)
+}
@@ -0,0 +1,105 @@
+package scanhistory
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Storage reads and writes the history. Reading a missing history returns no content and no error.
+type Storage interface {
+	Read() ([]byte, error)
+	Write(content []byte) error
+}
+
+// Returns the storage of the history, in Artifactory if its path in Artifactory is set, or in the local file otherwise.
+// Returns nil if neither is set.
+func NewStorage(file, artifactoryPath string, serverDetails *config.ServerDetails) Storage {
+	switch {
+	case artifactoryPath != "":
+		return &ArtifactoryStorage{serverDetails: serverDetails, path: strings.Trim(artifactoryPath, "/")}
+	case file != "":
+		return &FileStorage{path: file}
+	default:
+		return nil
+	}
+}
+
+// FileStorage keeps the history in a local file, which the CI persists between the runs, in its cache for example
+type FileStorage struct {
+	path string
+}
+
+func (fs *FileStorage) Read() ([]byte, error) {
+	content, err := os.ReadFile(fs.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return content, err
+}
+
+func (fs *FileStorage) Write(content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(fs.path), 0755); err != nil {
+		return err
+	}
+	log.Debug("Writing the scan history to:", fs.path)
+	return os.WriteFile(fs.path, content, 0644)
+}
+
+// ArtifactoryStorage keeps the history in a path of a generic repository in Artifactory, such as 'frogbot-generic/history/repo.json'
+type ArtifactoryStorage struct {
+	serverDetails *config.ServerDetails
+	path          string
+}
+
+func (as *ArtifactoryStorage) url() string {
+	return strings.TrimSuffix(as.serverDetails.ArtifactoryUrl, "/") + "/" + as.path
+}
+
+func (as *ArtifactoryStorage) httpClientDetails() httputils.HttpClientDetails {
+	return httputils.HttpClientDetails{User: as.serverDetails.User, Password: as.serverDetails.Password, AccessToken: as.serverDetails.AccessToken}
+}
+
+func (as *ArtifactoryStorage) Read() ([]byte, error) {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Downloading the scan history from:", as.url())
+	resp, body, _, err := client.SendGet(as.url(), true, as.httpClientDetails(), "")
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to download the scan history from %s, Artifactory responded with status %s", as.url(), resp.Status)
+	}
+}
+
+func (as *ArtifactoryStorage) Write(content []byte) error {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return err
+	}
+	log.Debug("Uploading the scan history to:", as.url())
+	resp, _, err := client.SendPut(as.url(), content, as.httpClientDetails(), "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload the scan history to %s, Artifactory responded with status %s", as.url(), resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/prcomments"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// ScanProgressComment is the comment that Frogbot adds to a pull request when it starts to scan it, so the reviewers know that the scan is running.
+// The comment is deleted once the results are added, or edited to show the reason if the scan fails.
+// A nil ScanProgressComment does nothing, as when the progress comment is disabled or couldn't be added.
+type ScanProgressComment struct {
+	repo          *Repository
+	client        vcsclient.VcsClient
+	pullRequestID int
+	commentID     int64
+	done          bool
+}
+
+// Adds the progress comment to the pull request if it's enabled, and returns it.
+// The progress comments of previous scans that didn't complete, such as scans whose process crashed, are deleted first.
+// Failing to add the comment doesn't fail the scan.
+func AddScanProgressComment(repo *Repository, client vcsclient.VcsClient, pullRequestID int) *ScanProgressComment {
+	if !repo.ScanProgressComment {
+		return nil
+	}
+	if err := deleteScanProgressComments(repo, client, pullRequestID); err != nil {
+		log.Warn("Couldn't delete the progress comments of previous scans:", err.Error())
+	}
+	if err := client.AddPullRequestComment(context.Background(), repo.RepoOwner, repo.RepoName, outputwriter.GetScanProgressCommentContent(repo.OutputWriter), pullRequestID); err != nil {
+		log.Warn("Couldn't add the scan progress comment:", err.Error())
+		return nil
+	}
+	// The Git client doesn't return the ID of the added comment, so the newest progress comment is looked up
+	comments, err := getScanProgressComments(repo, client, pullRequestID)
+	if err != nil || len(comments) == 0 {
+		log.Warn("Couldn't find the added scan progress comment, so it isn't replaced by the results of the scan")
+		return nil
+	}
+	return &ScanProgressComment{repo: repo, client: client, pullRequestID: pullRequestID, commentID: comments[0].ID}
+}
+
+// Deletes the progress comment after the results of the scan were added to the pull request
+func (spc *ScanProgressComment) Remove() {
+	if spc == nil || spc.done {
+		return
+	}
+	spc.done = true
+	prDetails := spc.repo.PullRequestDetails
+	if err := spc.client.DeletePullRequestComment(context.Background(), prDetails.Target.Owner, prDetails.Target.Repository, spc.pullRequestID, int(spc.commentID)); err != nil {
+		log.Warn(fmt.Sprintf("Couldn't delete the scan progress comment %d: %s", spc.commentID, err.Error()))
+	}
+}
+
+// Edits the progress comment to show the reason of the failure, if the scan failed before its results were added
+func (spc *ScanProgressComment) Fail(scanErr error) {
+	if spc == nil || spc.done || scanErr == nil {
+		return
+	}
+	spc.done = true
+	prDetails := spc.repo.PullRequestDetails
+	editor, err := prcomments.NewEditor(spc.repo.GitProvider, spc.repo.VcsInfo, prDetails.Target.Owner, prDetails.Target.Repository)
+	if err == nil {
+		err = editor.EditComment(spc.pullRequestID, spc.commentID, outputwriter.GetScanFailedCommentContent(scanErr.Error(), spc.repo.OutputWriter))
+	}
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't edit the scan progress comment %d with the failure of the scan: %s", spc.commentID, err.Error()))
+	}
+}
+
+func deleteScanProgressComments(repo *Repository, client vcsclient.VcsClient, pullRequestID int) error {
+	comments, err := getScanProgressComments(repo, client, pullRequestID)
+	if err != nil {
+		return err
+	}
+	prDetails := repo.PullRequestDetails
+	for _, comment := range comments {
+		if err = client.DeletePullRequestComment(context.Background(), prDetails.Target.Owner, prDetails.Target.Repository, pullRequestID, int(comment.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the progress comments of the pull request, from the newest to the oldest
+func getScanProgressComments(repo *Repository, client vcsclient.VcsClient, pullRequestID int) (progressComments []vcsclient.CommentInfo, err error) {
+	prDetails := repo.PullRequestDetails
+	comments, err := GetSortedPullRequestComments(client, prDetails.Target.Owner, prDetails.Target.Repository, pullRequestID)
+	if err != nil {
+		return
+	}
+	for _, comment := range comments {
+		if outputwriter.IsScanProgressComment(comment.Content) {
+			progressComments = append(progressComments, comment)
+		}
+	}
+	return
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanProgressComment(t *testing.T) {
+	var editedPath, editedContent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		editedPath, editedContent = r.URL.Path, body["body"]
+	}))
+	defer server.Close()
+
+	repo := &Repository{Params: Params{Git: Git{RepoOwner: "jfrog", RepoName: "frogbot", VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL}}, Scan: Scan{ScanProgressComment: true}}}
+	repo.GitProvider = vcsutils.GitHub
+	repo.PullRequestDetails = vcsclient.PullRequestInfo{ID: 3, Target: vcsclient.BranchInfo{Owner: "jfrog", Repository: "frogbot"}}
+	repo.setOutputWriterDetails()
+	progressContent := outputwriter.GetScanProgressCommentContent(repo.OutputWriter)
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	// The progress comment of a crashed scan is deleted before the progress comment is added
+	gomock.InOrder(
+		client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 3).Return([]vcsclient.CommentInfo{{ID: 1, Content: progressContent}, {ID: 2, Content: "LGTM"}}, nil),
+		client.EXPECT().DeletePullRequestComment(context.Background(), "jfrog", "frogbot", 3, 1).Return(nil),
+		client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", progressContent, 3).Return(nil),
+		client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 3).Return([]vcsclient.CommentInfo{{ID: 2, Content: "LGTM"}, {ID: 4, Content: progressContent}}, nil),
+	)
+	progressComment := AddScanProgressComment(repo, client, 3)
+	require.NotNil(t, progressComment)
+	assert.Equal(t, int64(4), progressComment.commentID)
+
+	// The progress comment shows the failure of the scan
+	progressComment.Fail(errors.New("failed to audit the source branch"))
+	assert.Equal(t, "/repos/jfrog/frogbot/issues/comments/4", editedPath)
+	assert.Equal(t, outputwriter.GetScanFailedCommentContent("failed to audit the source branch", repo.OutputWriter), editedContent)
+	// The failed scan doesn't add results, so the edited progress comment is kept
+	progressComment.Remove()
+
+	// The progress comment is deleted once the results are added
+	progressComment = &ScanProgressComment{repo: repo, client: client, pullRequestID: 3, commentID: 5}
+	client.EXPECT().DeletePullRequestComment(context.Background(), "jfrog", "frogbot", 3, 5).Return(nil)
+	progressComment.Remove()
+	editedPath = ""
+	progressComment.Fail(errors.New("couldn't write the scan report"))
+	assert.Empty(t, editedPath)
+
+	// The progress comment is disabled
+	repo.ScanProgressComment = false
+	progressComment = AddScanProgressComment(repo, client, 3)
+	assert.Nil(t, progressComment)
+	progressComment.Remove()
+	progressComment.Fail(errors.New("failed"))
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/secretvalidation"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The validator is shared by all the scanned repositories, so each secret is validated once per run
+var (
+	secretValidator      *secretvalidation.Validator
+	secretValidatorMutex sync.Mutex
+)
+
+func getSecretValidator() *secretvalidation.Validator {
+	secretValidatorMutex.Lock()
+	defer secretValidatorMutex.Unlock()
+	if secretValidator == nil {
+		secretValidator = secretvalidation.NewValidator()
+	}
+	return secretValidator
+}
+
+// Tags the detected secrets as active or inactive by validating them with their providers.
+// Must be called while the files of the secrets still exist, before their paths are converted to relative paths.
+// Secrets that were already validated by Xray keep their status.
+func ValidateSecrets(issuesCollection *issues.ScansIssuesCollection) {
+	validator := getSecretValidator()
+	for _, secrets := range [][]formats.SourceCodeRow{issuesCollection.SecretsVulnerabilities, issuesCollection.SecretsViolations} {
+		for i := range secrets {
+			validateSecret(validator, &secrets[i])
+		}
+	}
+}
+
+func validateSecret(validator *secretvalidation.Validator, secret *formats.SourceCodeRow) {
+	if secret.Applicability != nil && secret.Applicability.Status != "" {
+		return
+	}
+	content, err := os.ReadFile(secret.File)
+	if err != nil {
+		log.Debug("Couldn't read the file of a detected secret:", err.Error())
+		return
+	}
+	fileContent := string(content)
+	value := secretvalidation.ExtractSecret(fileContent, secret.StartLine, secret.StartColumn, secret.EndLine, secret.EndColumn)
+	if value == "" {
+		return
+	}
+	// Only a conclusive result is shown, as the status of unsupported secrets isn't meaningful to the reader
+	if status := validator.Validate(value, fileContent); status == jasutils.Active || status == jasutils.Inactive {
+		secret.Applicability = &formats.Applicability{Status: status.String()}
+	}
+}
+
+// Sorts the secrets so the active secrets are listed first and the inactive secrets are listed last
+func SortSecretsByValidationStatus(secrets []formats.SourceCodeRow) {
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return getValidationStatusPriority(secrets[i]) < getValidationStatusPriority(secrets[j])
+	})
+}
+
+func getValidationStatusPriority(secret formats.SourceCodeRow) int {
+	switch getSecretValidationStatus(secret) {
+	case jasutils.Active.String():
+		return 0
+	case jasutils.Inactive.String():
+		return 2
+	default:
+		return 1
+	}
+}
+
+func getSecretValidationStatus(secret formats.SourceCodeRow) string {
+	if secret.Applicability == nil {
+		return ""
+	}
+	return secret.Applicability.Status
+}
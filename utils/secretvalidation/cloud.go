@@ -0,0 +1,169 @@
+package secretvalidation
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+)
+
+const (
+	awsRegion            = "us-east-1"
+	awsStsService        = "sts"
+	awsGetCallerIdentity = "Action=GetCallerIdentity&Version=2011-06-15"
+	gcpScope             = "https://www.googleapis.com/auth/cloud-platform"
+	gcpJwtGrantType      = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+// Validates AWS access keys by the STS GetCallerIdentity action, which requires no permissions
+func (v *Validator) validateAwsKeys(keyId, secretKey string) (jasutils.TokenValidationStatus, error) {
+	stsUrl, err := url.Parse(v.AwsStsUrl)
+	if err != nil {
+		return "", err
+	}
+	headers := signAwsRequest(http.MethodPost, stsUrl.Host, awsGetCallerIdentity, keyId, secretKey, time.Now().UTC())
+	statusCode, _, err := sendRequest(http.MethodPost, v.AwsStsUrl+"/", headers, []byte(awsGetCallerIdentity))
+	if err != nil {
+		return "", err
+	}
+	return statusByResponseCode(v.AwsStsUrl, statusCode, []int{http.StatusOK}, []int{http.StatusForbidden})
+}
+
+// Returns the headers of an STS request, signed by AWS Signature Version 4
+func signAwsRequest(method, host, body, keyId, secretKey string, signTime time.Time) map[string]string {
+	amzDate := signTime.Format("20060102T150405Z")
+	date := signTime.Format("20060102")
+	contentType := "application/x-www-form-urlencoded; charset=utf-8"
+	signedHeaders := "content-type;host;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		"/",
+		"",
+		fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-date:%s\n", contentType, host, amzDate),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+	scope := strings.Join([]string{date, awsRegion, awsStsService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex(canonicalRequest)}, "\n")
+	signingKey := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, awsRegion, awsStsService, "aws4_request"} {
+		signingKey = hmacSha256(signingKey, part)
+	}
+	return map[string]string{
+		"Content-Type":  contentType,
+		"X-Amz-Date":    amzDate,
+		"Authorization": fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", keyId, scope, signedHeaders, hex.EncodeToString(hmacSha256(signingKey, stringToSign))),
+	}
+}
+
+func sha256Hex(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSha256(key []byte, content string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
+
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// Returns the GCP service account key of a key file, or nil if the file isn't a service account key file
+func parseServiceAccountKey(fileContent string) *serviceAccountKey {
+	key := &serviceAccountKey{}
+	if err := json.Unmarshal([]byte(fileContent), key); err != nil || key.Type != "service_account" || key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil
+	}
+	return key
+}
+
+// Validates a GCP service account key by exchanging a signed assertion for an access token
+func (v *Validator) validateServiceAccountKey(key *serviceAccountKey) (jasutils.TokenValidationStatus, error) {
+	privateKey, err := parseRsaPrivateKey(key.PrivateKey)
+	if err != nil {
+		// A key that can't be parsed can't be used to authenticate
+		return jasutils.Inactive, nil
+	}
+	assertion, err := createJwtAssertion(key.ClientEmail, v.GcpTokenUrl, privateKey, time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{"grant_type": {gcpJwtGrantType}, "assertion": {assertion}}
+	statusCode, body, err := sendRequest(http.MethodPost, v.GcpTokenUrl, map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, []byte(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	if statusCode == http.StatusBadRequest || statusCode == http.StatusUnauthorized {
+		var response struct {
+			Error string `json:"error"`
+		}
+		// Deleted keys and disabled service accounts are rejected as invalid grants
+		if json.Unmarshal(body, &response) == nil && response.Error == "invalid_grant" {
+			return jasutils.Inactive, nil
+		}
+	}
+	return statusByResponseCode(v.GcpTokenUrl, statusCode, []int{http.StatusOK}, nil)
+}
+
+func parseRsaPrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("the private key isn't PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key isn't an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Returns a JWT assertion of the service account, signed by RS256
+func createJwtAssertion(clientEmail, audience string, privateKey *rsa.PrivateKey, issueTime time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   clientEmail,
+		"scope": gcpScope,
+		"aud":   audience,
+		"iat":   issueTime.Unix(),
+		"exp":   issueTime.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
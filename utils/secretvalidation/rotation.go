@@ -0,0 +1,70 @@
+package secretvalidation
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+type SecretType string
+
+const (
+	GitHubToken          SecretType = "GitHub token"
+	SlackToken           SecretType = "Slack token"
+	AwsAccessKey         SecretType = "AWS access key"
+	GcpServiceAccountKey SecretType = "GCP service account key"
+	PrivateKey           SecretType = "Private key"
+	GenericSecret        SecretType = "Secret"
+)
+
+var rotationGuidance = map[SecretType]string{
+	GitHubToken:          "Revoke the token in the Developer settings of its owner, or in the personal access tokens of the organization, and create a new token with the minimal scopes.",
+	SlackToken:           "Regenerate the token in the OAuth & Permissions page of the Slack app, or revoke it with the auth.revoke API method.",
+	AwsAccessKey:         "Deactivate and delete the access key in IAM, create a new one, and review the CloudTrail events of the key ID since it was committed.",
+	GcpServiceAccountKey: "Delete the key in the Keys tab of the service account, create a new key if it's still needed, and review the audit logs of the service account.",
+	PrivateKey:           "Replace the key pair, revoke the certificates and remove the public key from every server and service that trusts it.",
+	GenericSecret:        "Rotate the secret in the service that issued it, and update the applications that use it from a secrets manager instead of the source code.",
+}
+
+// Returns the type of a detected secret by its snippet and the file it was found in.
+// The secrets scanner masks the snippets except for their first 3 characters, which identify the prefixes of the tokens.
+func GetSecretType(snippet, file string) SecretType {
+	// The quotes of a quoted secret are a part of its visible characters
+	visible := strings.TrimLeft(strings.TrimRight(snippet, "*"), `"' `)
+	switch {
+	case hasTokenPrefix(visible, "ghp_", "gho_", "ghu_", "ghs_", "ghr_", "github_pat_"):
+		return GitHubToken
+	case hasTokenPrefix(visible, "xoxa-", "xoxb-", "xoxp-", "xoxo-", "xoxs-", "xoxr-"):
+		return SlackToken
+	case hasTokenPrefix(visible, "AKIA", "ASIA"):
+		return AwsAccessKey
+	case hasTokenPrefix(visible, "-----BEGIN"):
+		// The private keys of service accounts are found in the JSON key files
+		if strings.EqualFold(filepath.Ext(file), ".json") {
+			return GcpServiceAccountKey
+		}
+		return PrivateKey
+	default:
+		return GenericSecret
+	}
+}
+
+// Returns true if the visible characters of a masked secret are the start of any of the prefixes. At least 2 characters are required.
+func hasTokenPrefix(visible string, prefixes ...string) bool {
+	if len(visible) < 2 {
+		return false
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(prefix, visible) || strings.HasPrefix(visible, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the steps to rotate a secret of the type
+func GetRotationGuidance(secretType SecretType) string {
+	if guidance, exists := rotationGuidance[secretType]; exists {
+		return guidance
+	}
+	return rotationGuidance[GenericSecret]
+}
@@ -0,0 +1,196 @@
+package secretvalidation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	DefaultGitHubApiUrl = "https://api.github.com"
+	DefaultSlackApiUrl  = "https://slack.com/api"
+	DefaultAwsStsUrl    = "https://sts.amazonaws.com"
+	DefaultGcpTokenUrl  = "https://oauth2.googleapis.com/token"
+)
+
+var (
+	gitHubTokenRegex = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`)
+	slackTokenRegex  = regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)
+	awsKeyIdRegex    = regexp.MustCompile(`\bAKIA[A-Z0-9]{16}\b`)
+	// The secret access key is looked up next to its key ID, since it has no distinctive prefix
+	awsSecretKeyRegex = regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`)
+)
+
+// Validator checks whether detected secrets are live credentials, by calling the verification endpoints of their providers.
+// GitHub tokens, Slack tokens, AWS access keys and GCP service account keys are supported.
+// The endpoints can be replaced for testing.
+type Validator struct {
+	GitHubApiUrl string
+	SlackApiUrl  string
+	AwsStsUrl    string
+	// The service account keys are always exchanged with this URL, rather than with the token URI of the key file, so the signed assertions aren't sent elsewhere
+	GcpTokenUrl string
+	// The statuses of the validated credentials, so credentials that are found in several places are validated once
+	statuses map[string]jasutils.TokenValidationStatus
+	mutex    sync.Mutex
+}
+
+func NewValidator() *Validator {
+	return &Validator{
+		GitHubApiUrl: DefaultGitHubApiUrl,
+		SlackApiUrl:  DefaultSlackApiUrl,
+		AwsStsUrl:    DefaultAwsStsUrl,
+		GcpTokenUrl:  DefaultGcpTokenUrl,
+		statuses:     make(map[string]jasutils.TokenValidationStatus),
+	}
+}
+
+// Returns the status of a detected secret:
+// Active if the provider accepts it, Inactive if the provider rejects it, Unsupported if its type can't be validated and Unavailable if the provider couldn't be reached.
+// The content of the file of the secret is used to complete the credentials that the secret is a part of, like the secret key of an AWS access key ID.
+func (v *Validator) Validate(secret, fileContent string) jasutils.TokenValidationStatus {
+	switch {
+	case gitHubTokenRegex.MatchString(secret):
+		token := gitHubTokenRegex.FindString(secret)
+		return v.validateOnce(token, func() (jasutils.TokenValidationStatus, error) { return v.validateGitHubToken(token) })
+	case slackTokenRegex.MatchString(secret):
+		token := slackTokenRegex.FindString(secret)
+		return v.validateOnce(token, func() (jasutils.TokenValidationStatus, error) { return v.validateSlackToken(token) })
+	case awsKeyIdRegex.MatchString(secret) || awsSecretKeyRegex.MatchString(secret):
+		keyId, secretKey := findAwsKeys(secret, fileContent)
+		if keyId == "" || secretKey == "" {
+			return jasutils.Unsupported
+		}
+		return v.validateOnce(keyId+":"+secretKey, func() (jasutils.TokenValidationStatus, error) { return v.validateAwsKeys(keyId, secretKey) })
+	default:
+		serviceAccountKey := parseServiceAccountKey(fileContent)
+		if serviceAccountKey == nil {
+			return jasutils.Unsupported
+		}
+		return v.validateOnce(serviceAccountKey.PrivateKey, func() (jasutils.TokenValidationStatus, error) {
+			return v.validateServiceAccountKey(serviceAccountKey)
+		})
+	}
+}
+
+// Validates the credentials unless they were already validated, so credentials that are found in several places are validated once
+func (v *Validator) validateOnce(credentials string, validate func() (jasutils.TokenValidationStatus, error)) jasutils.TokenValidationStatus {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if status, exists := v.statuses[credentials]; exists {
+		return status
+	}
+	status, err := validate()
+	if err != nil {
+		log.Debug("Couldn't validate a detected secret:", err.Error())
+		status = jasutils.Unavailable
+	}
+	v.statuses[credentials] = status
+	return status
+}
+
+// Returns the AWS access key ID and its secret access key, from the secret or from the file it was found in
+func findAwsKeys(secret, fileContent string) (keyId, secretKey string) {
+	if keyId = awsKeyIdRegex.FindString(secret); keyId == "" {
+		keyId = awsKeyIdRegex.FindString(fileContent)
+	}
+	if match := awsSecretKeyRegex.FindStringSubmatch(secret); match != nil {
+		secretKey = match[1]
+	} else if match = awsSecretKeyRegex.FindStringSubmatch(fileContent); match != nil {
+		secretKey = match[1]
+	}
+	return
+}
+
+// Extracts the secret of a finding from the content of its file, by the 1-based location of the finding.
+// The end column is exclusive.
+func ExtractSecret(fileContent string, startLine, startColumn, endLine, endColumn int) string {
+	lines := strings.Split(fileContent, "\n")
+	if startLine < 1 || startLine > len(lines) {
+		return ""
+	}
+	if endLine < startLine || endLine > len(lines) {
+		endLine = startLine
+	}
+	selectedLines := lines[startLine-1 : endLine]
+	lastLine := selectedLines[len(selectedLines)-1]
+	if endColumn >= 1 && endColumn-1 <= len(lastLine) {
+		selectedLines[len(selectedLines)-1] = lastLine[:endColumn-1]
+	}
+	if startColumn >= 1 && startColumn-1 <= len(selectedLines[0]) {
+		selectedLines[0] = selectedLines[0][startColumn-1:]
+	}
+	return strings.Join(selectedLines, "\n")
+}
+
+// Sends a request to a verification endpoint, and returns the response status code and body
+func sendRequest(method, url string, headers map[string]string, content []byte) (int, []byte, error) {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return 0, nil, err
+	}
+	log.Debug(fmt.Sprintf("Sending HTTP %s request to: %s", method, url))
+	resp, body, _, err := client.Send(method, url, content, false, true, httputils.HttpClientDetails{Headers: headers}, "")
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+func statusByResponseCode(url string, statusCode int, activeCodes, inactiveCodes []int) (jasutils.TokenValidationStatus, error) {
+	for _, code := range activeCodes {
+		if statusCode == code {
+			return jasutils.Active, nil
+		}
+	}
+	for _, code := range inactiveCodes {
+		if statusCode == code {
+			return jasutils.Inactive, nil
+		}
+	}
+	return "", fmt.Errorf("%s responded with unexpected status %d", url, statusCode)
+}
+
+func (v *Validator) validateGitHubToken(token string) (jasutils.TokenValidationStatus, error) {
+	url := v.GitHubApiUrl + "/user"
+	statusCode, _, err := sendRequest(http.MethodGet, url, map[string]string{"Authorization": "Bearer " + token, "Accept": "application/vnd.github+json"}, nil)
+	if err != nil {
+		return "", err
+	}
+	return statusByResponseCode(url, statusCode, []int{http.StatusOK}, []int{http.StatusUnauthorized})
+}
+
+func (v *Validator) validateSlackToken(token string) (jasutils.TokenValidationStatus, error) {
+	url := v.SlackApiUrl + "/auth.test"
+	statusCode, body, err := sendRequest(http.MethodPost, url, map[string]string{"Authorization": "Bearer " + token}, nil)
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("%s responded with unexpected status %d", url, statusCode)
+	}
+	// Slack responds with status 200 to rejected tokens as well
+	var response struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return "", err
+	}
+	switch {
+	case response.Ok:
+		return jasutils.Active, nil
+	case response.Error == "invalid_auth" || response.Error == "not_authed" || response.Error == "token_revoked" || response.Error == "token_expired" || response.Error == "account_inactive":
+		return jasutils.Inactive, nil
+	default:
+		return "", fmt.Errorf("%s responded with unexpected error '%s'", url, response.Error)
+	}
+}
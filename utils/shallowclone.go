@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Clones the branch into wd with a depth of 1, including its submodules if they're enabled.
+// The clone doesn't download the archive of the branch from the API of the Git provider, which is slow for large repositories and counts against its rate limit.
+// The .git directories are removed, so wd holds the same files as a downloaded archive.
+func shallowClone(client vcsclient.VcsClient, gitParams *Git, repoOwner, repoName, branch, wd string) (err error) {
+	repositoryInfo, err := client.GetRepositoryInfo(context.Background(), repoOwner, repoName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the clone URL of <%s/%s>: %s", repoOwner, repoName, err.Error())
+	}
+	gitManager := NewGitManager().SetAuth(gitParams.Username, gitParams.Token)
+	gitManager.remoteGitUrl = repositoryInfo.CloneInfo.HTTP
+	gitManager.remoteName = vcsutils.RemoteName
+	gitManager.git = &Git{Submodules: gitParams.Submodules}
+	if err = gitManager.Clone(wd, branch); err != nil {
+		return
+	}
+	gitDirs := []string{filepath.Join(wd, git.GitDirName)}
+	if gitParams.Submodules {
+		submodulePaths, e := GetSubmodulePaths(wd)
+		if e != nil {
+			return e
+		}
+		for _, submodulePath := range submodulePaths {
+			gitDirs = append(gitDirs, filepath.Join(wd, submodulePath, git.GitDirName))
+		}
+	}
+	for _, gitDir := range gitDirs {
+		err = errors.Join(err, os.RemoveAll(gitDir))
+	}
+	log.Debug("Repository clone completed")
+	return
+}
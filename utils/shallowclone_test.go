@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadRepoToTempDirWithShallowClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("The git executable is required to create the test repository")
+	}
+	repo := t.TempDir()
+	runGit := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=frogbot", "-c", "user.email=frogbot@jfrog.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	runGit("init", "--initial-branch=master")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "package.json"), []byte("{}"), 0644))
+	runGit("add", ".")
+	runGit("commit", "-m", "Initial commit")
+
+	// The branch is cloned without its archive, and without the .git directory
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().GetRepositoryInfo(context.Background(), "jfrog", "frogbot").Return(vcsclient.RepositoryInfo{CloneInfo: vcsclient.CloneInfo{HTTP: repo}}, nil)
+	wd, cleanup, err := DownloadRepoToTempDir(mockVcsClient, "jfrog", "frogbot", "master", &Git{ShallowClone: true})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(wd, "package.json"))
+	assert.NoDirExists(t, filepath.Join(wd, ".git"))
+	assert.NoError(t, cleanup())
+
+	// The archive is downloaded if the clone fails
+	mockVcsClient = testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().GetRepositoryInfo(context.Background(), "jfrog", "frogbot").Return(vcsclient.RepositoryInfo{}, errors.New("rate limit exceeded"))
+	mockVcsClient.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "master", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, _, localPath string) error {
+		return os.WriteFile(filepath.Join(localPath, "pom.xml"), []byte("<project/>"), 0644)
+	})
+	wd, cleanup, err = DownloadRepoToTempDir(mockVcsClient, "jfrog", "frogbot", "master", &Git{ShallowClone: true})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(wd, "pom.xml"))
+	assert.NoError(t, cleanup())
+
+	// The partial clone of a missing branch is removed before the download
+	mockVcsClient = testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().GetRepositoryInfo(context.Background(), "jfrog", "frogbot").Return(vcsclient.RepositoryInfo{CloneInfo: vcsclient.CloneInfo{HTTP: repo}}, nil)
+	mockVcsClient.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "missing", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, _, localPath string) error {
+		entries, err := os.ReadDir(localPath)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+		return os.WriteFile(filepath.Join(localPath, "pom.xml"), []byte("<project/>"), 0644)
+	})
+	_, cleanup, err = DownloadRepoToTempDir(mockVcsClient, "jfrog", "frogbot", "missing", &Git{ShallowClone: true})
+	assert.NoError(t, err)
+	assert.NoError(t, cleanup())
+}
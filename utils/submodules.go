@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	biutils "github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+)
+
+const gitModulesFile = ".gitmodules"
+
+// Returns the paths of the submodules that are declared in the .gitmodules file of the repository in wd, relative to wd.
+// Returns nil if the repository has no submodules.
+func GetSubmodulePaths(wd string) ([]string, error) {
+	content, err := os.ReadFile(filepath.Join(wd, gitModulesFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	modules := config.NewModules()
+	if err = modules.Unmarshal(content); err != nil {
+		return nil, fmt.Errorf("couldn't parse the %s file: %s", gitModulesFile, err.Error())
+	}
+	var submodulePaths []string
+	for _, submodule := range modules.Submodules {
+		if err = submodule.Validate(); err != nil {
+			return nil, fmt.Errorf("the submodule '%s' in the %s file is invalid: %s", submodule.Name, gitModulesFile, err.Error())
+		}
+		submodulePaths = append(submodulePaths, filepath.FromSlash(submodule.Path))
+	}
+	slices.Sort(submodulePaths)
+	return submodulePaths, nil
+}
+
+// Returns the full paths of the working directories of the project in baseWd.
+// If submodules are enabled, each submodule of the repository is an implicit working directory, and its path is returned as well.
+func GetFullPathWorkingDirsWithSubmodules(workingDirs []string, baseWd string, submodules bool) (fullPathWds, submodulePaths []string, err error) {
+	fullPathWds = GetFullPathWorkingDirs(workingDirs, baseWd)
+	if !submodules {
+		return
+	}
+	if submodulePaths, err = GetSubmodulePaths(baseWd); err != nil {
+		return
+	}
+	for _, submodulePath := range submodulePaths {
+		if submoduleWd := filepath.Join(baseWd, submodulePath); !slices.Contains(fullPathWds, submoduleWd) {
+			fullPathWds = append(fullPathWds, submoduleWd)
+		}
+	}
+	if len(submodulePaths) > 0 {
+		log.Info("Scanning the Git submodules as working directories:", strings.Join(submodulePaths, ", "))
+	}
+	return
+}
+
+// Returns true if wd is the directory of one of the submodules of the repository in baseWd, or is inside it
+func IsSubmoduleWd(wd, baseWd string, submodulePaths []string) bool {
+	for _, submodulePath := range submodulePaths {
+		submoduleWd := filepath.Join(baseWd, submodulePath)
+		if wd == submoduleWd || strings.HasPrefix(wd, submoduleWd+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// The locations of the source code findings are relative to the scanned working directory.
+// The scans of the submodules are set to run in baseWd, so their findings are attributed to the path of the submodule in the repository.
+func AttributeResultsToSubmodules(scanResults *results.SecurityCommandResults, baseWd string, submodulePaths []string) {
+	if scanResults == nil || len(submodulePaths) == 0 {
+		return
+	}
+	for _, target := range scanResults.Targets {
+		if target.JasResults == nil || !IsSubmoduleWd(target.Target, baseWd, submodulePaths) {
+			continue
+		}
+		jasResults := target.JasResults
+		for _, scanResults := range slices.Concat(
+			jasResults.ApplicabilityScanResults,
+			jasResults.JasVulnerabilities.SecretsScanResults, jasResults.JasVulnerabilities.IacScanResults, jasResults.JasVulnerabilities.SastScanResults,
+			jasResults.JasViolations.SecretsScanResults, jasResults.JasViolations.IacScanResults, jasResults.JasViolations.SastScanResults,
+		) {
+			setRunsWorkingDirectory(scanResults.Scan, baseWd)
+		}
+	}
+}
+
+func setRunsWorkingDirectory(runs []*sarif.Run, wd string) {
+	for _, run := range runs {
+		for _, invocation := range run.Invocations {
+			if invocation.WorkingDirectory != nil {
+				invocation.WorkingDirectory.WithUri(wd)
+			}
+		}
+	}
+}
+
+// Downloads the submodules of the repository into wd, which holds the downloaded branch.
+// The archives of the Git providers don't include the submodules, so the branch is cloned with its submodules to a temp directory,
+// and the submodules are copied from it at the commits that the branch points to.
+func downloadSubmodules(client vcsclient.VcsClient, gitParams *Git, repoOwner, repoName, branch, wd string) (err error) {
+	submodulePaths, err := GetSubmodulePaths(wd)
+	if err != nil || len(submodulePaths) == 0 {
+		return
+	}
+	log.Debug(fmt.Sprintf("Downloading the Git submodules of <%s/%s/%s>: %s", repoOwner, repoName, branch, strings.Join(submodulePaths, ", ")))
+	repositoryInfo, err := client.GetRepositoryInfo(context.Background(), repoOwner, repoName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the clone URL of <%s/%s>: %s", repoOwner, repoName, err.Error())
+	}
+	cloneWd, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, fileutils.RemoveTempDir(cloneWd))
+	}()
+	gitManager := NewGitManager().SetAuth(gitParams.Username, gitParams.Token)
+	gitManager.remoteGitUrl = repositoryInfo.CloneInfo.HTTP
+	gitManager.remoteName = vcsutils.RemoteName
+	gitManager.git = &Git{Submodules: true}
+	if err = gitManager.Clone(cloneWd, branch); err != nil {
+		return
+	}
+	for _, submodulePath := range submodulePaths {
+		if err = biutils.CopyDir(filepath.Join(cloneWd, submodulePath), filepath.Join(wd, submodulePath), true, []string{git.GitDirName}); err != nil {
+			return fmt.Errorf("failed to copy the submodule '%s': %s", submodulePath, err.Error())
+		}
+	}
+	log.Debug("Submodules download completed")
+	return
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-security/utils/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/results"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGitModules = `[submodule "ui"]
+	path = libs/ui
+	url = https://github.com/jfrog/ui.git
+[submodule "common"]
+	path = common
+	url = ../common.git
+`
+
+func TestGetSubmodulePaths(t *testing.T) {
+	wd := t.TempDir()
+	submodulePaths, err := GetSubmodulePaths(wd)
+	assert.NoError(t, err)
+	assert.Empty(t, submodulePaths)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wd, gitModulesFile), []byte(testGitModules), 0644))
+	submodulePaths, err = GetSubmodulePaths(wd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"common", filepath.Join("libs", "ui")}, submodulePaths)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wd, gitModulesFile), []byte("[submodule \"ui\"]\n\tpath = libs/ui\n"), 0644))
+	_, err = GetSubmodulePaths(wd)
+	assert.ErrorContains(t, err, "the submodule 'ui' in the .gitmodules file is invalid")
+}
+
+func TestGetFullPathWorkingDirsWithSubmodules(t *testing.T) {
+	baseWd := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseWd, gitModulesFile), []byte(testGitModules), 0644))
+
+	fullPathWds, submodulePaths, err := GetFullPathWorkingDirsWithSubmodules([]string{RootDir, "common"}, baseWd, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{baseWd, filepath.Join(baseWd, "common")}, fullPathWds)
+	assert.Empty(t, submodulePaths)
+
+	// A submodule that is already a working directory isn't scanned twice
+	fullPathWds, submodulePaths, err = GetFullPathWorkingDirsWithSubmodules([]string{RootDir, "common"}, baseWd, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{baseWd, filepath.Join(baseWd, "common"), filepath.Join(baseWd, "libs", "ui")}, fullPathWds)
+	assert.Equal(t, []string{"common", filepath.Join("libs", "ui")}, submodulePaths)
+}
+
+func TestIsSubmoduleWd(t *testing.T) {
+	baseWd := filepath.Join("tmp", "repo")
+	submodulePaths := []string{filepath.Join("libs", "ui")}
+	assert.True(t, IsSubmoduleWd(filepath.Join(baseWd, "libs", "ui"), baseWd, submodulePaths))
+	assert.True(t, IsSubmoduleWd(filepath.Join(baseWd, "libs", "ui", "app"), baseWd, submodulePaths))
+	assert.False(t, IsSubmoduleWd(filepath.Join(baseWd, "libs", "ui-kit"), baseWd, submodulePaths))
+	assert.False(t, IsSubmoduleWd(baseWd, baseWd, submodulePaths))
+}
+
+func TestAttributeResultsToSubmodules(t *testing.T) {
+	baseWd := filepath.Join("tmp", "repo")
+	submoduleWd := filepath.Join(baseWd, "libs", "ui")
+	newSastRun := func(wd string) *sarif.Run {
+		return sarifutils.CreateRunWithDummyResultsInWd(wd, sarifutils.CreateResultWithOneLocation(filepath.Join(wd, "src", "app.js"), 1, 1, 1, 1, "eval(input)", "sast-rule", "error"))
+	}
+	newTarget := func(wd string) *results.TargetResults {
+		return &results.TargetResults{
+			ScanTarget: results.ScanTarget{Target: wd},
+			JasResults: &results.JasScansResults{JasVulnerabilities: results.JasScanResults{SastScanResults: []results.ScanResult[[]*sarif.Run]{{Scan: []*sarif.Run{newSastRun(wd)}}}}},
+		}
+	}
+	scanResults := &results.SecurityCommandResults{Targets: []*results.TargetResults{newTarget(baseWd), newTarget(submoduleWd)}}
+	AttributeResultsToSubmodules(scanResults, baseWd, []string{filepath.Join("libs", "ui")})
+
+	getRelativeFile := func(target *results.TargetResults) string {
+		run := target.JasResults.JasVulnerabilities.SastScanResults[0].Scan[0]
+		return sarifutils.GetRelativeLocationFileName(run.Results[0].Locations[0], run.Invocations)
+	}
+	assert.Equal(t, filepath.Join("src", "app.js"), getRelativeFile(scanResults.Targets[0]))
+	assert.Equal(t, filepath.Join("libs", "ui", "src", "app.js"), getRelativeFile(scanResults.Targets[1]))
+}
+
+func TestDownloadRepoToTempDirWithSubmodules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("The git executable is required to create the test repositories")
+	}
+	reposDir := t.TempDir()
+	runGit := func(dir string, args ...string) {
+		args = append([]string{"-c", "user.name=frogbot", "-c", "user.email=frogbot@jfrog.com", "-c", "protocol.file.allow=always"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	submoduleRepo := filepath.Join(reposDir, "ui")
+	require.NoError(t, os.MkdirAll(submoduleRepo, 0755))
+	runGit(submoduleRepo, "init", "--initial-branch=master")
+	require.NoError(t, os.WriteFile(filepath.Join(submoduleRepo, "package.json"), []byte("{}"), 0644))
+	runGit(submoduleRepo, "add", ".")
+	runGit(submoduleRepo, "commit", "-m", "Initial commit")
+
+	repo := filepath.Join(reposDir, "frogbot")
+	require.NoError(t, os.MkdirAll(repo, 0755))
+	runGit(repo, "init", "--initial-branch=master")
+	runGit(repo, "submodule", "add", submoduleRepo, filepath.Join("libs", "ui"))
+	runGit(repo, "commit", "-m", "Add the ui submodule")
+	gitModules, err := os.ReadFile(filepath.Join(repo, gitModulesFile))
+	require.NoError(t, err)
+
+	// The archive of the branch doesn't include the content of the submodules
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "master", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, _, localPath string) error {
+		return os.WriteFile(filepath.Join(localPath, gitModulesFile), gitModules, 0644)
+	})
+	mockVcsClient.EXPECT().GetRepositoryInfo(context.Background(), "jfrog", "frogbot").Return(vcsclient.RepositoryInfo{CloneInfo: vcsclient.CloneInfo{HTTP: repo}}, nil)
+	wd, cleanup, err := DownloadRepoToTempDir(mockVcsClient, "jfrog", "frogbot", "master", &Git{Submodules: true})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cleanup())
+	}()
+	assert.FileExists(t, filepath.Join(wd, "libs", "ui", "package.json"))
+	assert.NoFileExists(t, filepath.Join(wd, "libs", "ui", ".git"))
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// The prefix of the error logs, which the failed commands end their output with
+const errorLogPrefix = "[Error] "
+
+// Runs a Frogbot command in a new process of the running executable, with the given environment variables.
+// The commands change the working directory and the environment variables of their process, so running each command in its own process
+// lets several commands run at the same time. Returns the combined output of the process.
+func RunCommandProcess(env []string, args ...string) (output []byte, err error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+	var outputBuffer bytes.Buffer
+	cmd := exec.Command(executable, args...)
+	cmd.Env = env
+	cmd.Stdout = &outputBuffer
+	cmd.Stderr = &outputBuffer
+	err = cmd.Run()
+	output = outputBuffer.Bytes()
+	if err != nil {
+		err = getCommandProcessError(output, err)
+	}
+	return
+}
+
+// Returns the error that the command logged last, which may span several lines, or the exit error of the process if the command didn't log an error
+func getCommandProcessError(output []byte, exitErr error) error {
+	lastErrorLog := bytes.LastIndex(output, []byte(errorLogPrefix))
+	if lastErrorLog == -1 {
+		return exitErr
+	}
+	if errorLog := strings.TrimSpace(string(output[lastErrorLog+len(errorLogPrefix):])); errorLog != "" {
+		return errors.New(errorLog)
+	}
+	return exitErr
+}
+
+// Returns the environment variables with the overrides, which take precedence over the variables with the same names
+func EnvWithOverrides(env []string, overrides map[string]string) []string {
+	result := append([]string{}, env...)
+	for name, value := range overrides {
+		result = append(result, name+"="+value)
+	}
+	return result
+}
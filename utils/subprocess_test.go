@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCommandProcessError(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	output := "[Info] Scanning repository: repo-1\n[Error] couldn't clone\n[Info] Retrying\n[Error] repository 'repo-1' scan failed with the following error:\nthe clone failed\n"
+	assert.EqualError(t, getCommandProcessError([]byte(output), exitErr), "repository 'repo-1' scan failed with the following error:\nthe clone failed")
+	assert.Equal(t, exitErr, getCommandProcessError([]byte("[Info] Scanning repository: repo-1\n"), exitErr))
+	assert.Equal(t, exitErr, getCommandProcessError([]byte("[Error] \n"), exitErr))
+}
+
+func TestEnvWithOverrides(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "JF_GIT_REPO=repo-1"}
+	assert.Equal(t, []string{"PATH=/usr/bin", "JF_GIT_REPO=repo-1", "JF_GIT_REPO=repo-2"}, EnvWithOverrides(env, map[string]string{"JF_GIT_REPO": "repo-2"}))
+	// The given environment isn't changed
+	assert.Equal(t, []string{"PATH=/usr/bin", "JF_GIT_REPO=repo-1"}, env)
+}
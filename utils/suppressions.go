@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/policy"
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Replying to a Frogbot review comment with this command suppresses the findings of the comment
+const SuppressFindingsCommand = "/frogbot suppress"
+
+// The access level of the Maintainer role of GitLab projects
+const gitLabMaintainerAccessLevel = 40
+
+// PullRequestSuppressions holds the findings that were suppressed in the discussions of a merge request.
+// A finding is suppressed by replying to its Frogbot review comment with the suppress command.
+// The Frogbot comments of the suppressed findings are kept when rescanning, so the suppressions persist across pushes, including force-pushes.
+type PullRequestSuppressions struct {
+	findingIds *datastructures.Set[string]
+	// The Frogbot review comments of the suppressed findings, and the other Frogbot comments that are kept when rescanning
+	commentIds *datastructures.Set[int64]
+}
+
+func NewPullRequestSuppressions() *PullRequestSuppressions {
+	return &PullRequestSuppressions{findingIds: datastructures.MakeSet[string](), commentIds: datastructures.MakeSet[int64]()}
+}
+
+type gitLabDiscussion struct {
+	Id    string       `json:"id"`
+	Notes []gitLabNote `json:"notes"`
+}
+
+type gitLabNote struct {
+	Id     int64  `json:"id"`
+	Body   string `json:"body"`
+	Author struct {
+		Id       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// Returns the findings that were suppressed in the discussions of the pull request, when the comment suppressions are allowed.
+// Only the suppress commands of the suppression approvers are accepted, or of the members with the Maintainer role or higher if no approvers are configured.
+// Only GitLab lists the review comments with their discussion threads, so there are no suppressions in the other Git providers.
+func GetPullRequestSuppressions(repo *Repository, pullRequestID int) (*PullRequestSuppressions, error) {
+	suppressions := NewPullRequestSuppressions()
+	if !repo.CommentSuppressions || repo.GitProvider != vcsutils.GitLab {
+		return suppressions, nil
+	}
+	client := vcsapi.NewClient(repo.GitProvider, repo.VcsInfo)
+	projectUrl := client.RepositoryUrl(repo.RepoOwner, repo.RepoName)
+	discussions, err := vcsapi.List[gitLabDiscussion](client, fmt.Sprintf("%s/merge_requests/%d/discussions", projectUrl, pullRequestID), vcsapi.PageSize)
+	if err != nil {
+		return suppressions, fmt.Errorf("couldn't list the merge request discussions: %s", err.Error())
+	}
+	approvers := &suppressionApprovers{client: client, projectUrl: projectUrl, usernames: repo.SuppressionApprovers, approvedUserIds: map[int64]bool{}}
+	for _, discussion := range discussions {
+		if err = suppressions.addThread(discussion.Notes, approvers); err != nil {
+			return suppressions, err
+		}
+	}
+	if suppressions.findingIds.Size() > 0 {
+		log.Info(fmt.Sprintf("%d findings were suppressed in the merge request discussions", suppressions.findingIds.Size()))
+	}
+	return suppressions, nil
+}
+
+func (s *PullRequestSuppressions) addThread(thread []gitLabNote, approvers *suppressionApprovers) (err error) {
+	var frogbotComments []gitLabNote
+	suppressed := false
+	for _, comment := range thread {
+		if outputwriter.IsFrogbotComment(comment.Body) {
+			frogbotComments = append(frogbotComments, comment)
+			continue
+		}
+		if suppressed || !isSuppressFindingsComment(comment.Body) {
+			continue
+		}
+		if suppressed, err = approvers.isApprover(comment.Author.Id, comment.Author.Username); err != nil {
+			return
+		}
+		if !suppressed {
+			log.Info(fmt.Sprintf("Ignoring the suppress command of %s, who isn't allowed to suppress findings", comment.Author.Username))
+		}
+	}
+	if !suppressed {
+		return
+	}
+	for _, comment := range frogbotComments {
+		s.commentIds.Add(comment.Id)
+		for _, findingId := range outputwriter.GetFindingIds(comment.Body) {
+			s.findingIds.Add(findingId)
+		}
+	}
+	return
+}
+
+// suppressionApprovers checks whether the authors of the suppress commands are allowed to suppress findings
+type suppressionApprovers struct {
+	client     *vcsapi.Client
+	projectUrl string
+	// The configured approvers. If empty, the members with the Maintainer role or higher are the approvers.
+	usernames []string
+	// The results of the checks of the project members, by their user ID
+	approvedUserIds map[int64]bool
+}
+
+func (sa *suppressionApprovers) isApprover(userId int64, username string) (bool, error) {
+	if len(sa.usernames) > 0 {
+		return slices.ContainsFunc(sa.usernames, func(approver string) bool { return strings.EqualFold(approver, username) }), nil
+	}
+	if approved, checked := sa.approvedUserIds[userId]; checked {
+		return approved, nil
+	}
+	var member struct {
+		AccessLevel int `json:"access_level"`
+	}
+	// The members of the project include the members of its parent groups
+	err := sa.client.Get(fmt.Sprintf("%s/members/all/%d", sa.projectUrl, userId), &member)
+	if err != nil && !vcsapi.IsNotFound(err) {
+		return false, fmt.Errorf("couldn't get the role of %s in the project: %s", username, err.Error())
+	}
+	sa.approvedUserIds[userId] = member.AccessLevel >= gitLabMaintainerAccessLevel
+	return sa.approvedUserIds[userId], nil
+}
+
+func isSuppressFindingsComment(content string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(content)), SuppressFindingsCommand)
+}
+
+// Returns true if the comment is the Frogbot review comment of suppressed findings, which should be kept when rescanning
+func (s *PullRequestSuppressions) IsSuppressedFindingsComment(comment vcsclient.CommentInfo) bool {
+	return s != nil && s.commentIds.Exists(comment.ID)
+}
+
+// Keeps the Frogbot comments when rescanning, like the review comments of the suppressed findings
+func (s *PullRequestSuppressions) keepComments(commentIds ...int64) {
+	for _, commentId := range commentIds {
+		s.commentIds.Add(commentId)
+	}
+}
+
+// Moves the suppressed issues to the ignored issues of the collection, so they aren't reported as findings.
+// Exposed secrets are compromised even if the pull request isn't merged, and the findings that break the blocking rules of the policy must be fixed,
+// so they are reported even if they were suppressed.
+func (s *PullRequestSuppressions) FilterIssues(issuesCollection *issues.ScansIssuesCollection, repoPolicy *policy.Policy) {
+	if s == nil || issuesCollection == nil || s.findingIds.Size() == 0 {
+		return
+	}
+	isScaIssueSuppressed := func(issue formats.VulnerabilityOrViolationRow) bool {
+		return s.findingIds.Exists(issues.GetScaFindingId(issue)) && !repoPolicy.BlocksScaIssue(issue, issuesCollection.Licenses)
+	}
+	isSourceCodeIssueSuppressed := func(issue formats.SourceCodeRow) bool {
+		return s.findingIds.Exists(issues.GetSourceCodeFindingId(issue)) && !repoPolicy.BlocksSourceCodeIssue(issue)
+	}
+	ignoreIssues(issuesCollection, isScaIssueSuppressed, isSourceCodeIssueSuppressed, false)
+}
+
+// Moves the issues with the given finding IDs to the ignored issues of the collection
+func ignoreIssuesByFindingId(issuesCollection *issues.ScansIssuesCollection, findingIds *datastructures.Set[string]) {
+	isScaIssueIgnored := func(issue formats.VulnerabilityOrViolationRow) bool {
+		return findingIds.Exists(issues.GetScaFindingId(issue))
+	}
+	isSourceCodeIssueIgnored := func(issue formats.SourceCodeRow) bool {
+		return findingIds.Exists(issues.GetSourceCodeFindingId(issue))
+	}
+	ignoreIssues(issuesCollection, isScaIssueIgnored, isSourceCodeIssueIgnored, true)
+}
+
+func ignoreIssues(issuesCollection *issues.ScansIssuesCollection, isScaIssueIgnored func(formats.VulnerabilityOrViolationRow) bool, isSourceCodeIssueIgnored func(formats.SourceCodeRow) bool, ignoreSecrets bool) {
+	var ignoredVulnerabilities, ignoredViolations []formats.VulnerabilityOrViolationRow
+	issuesCollection.ScaVulnerabilities, ignoredVulnerabilities = splitSuppressedRows(issuesCollection.ScaVulnerabilities, isScaIssueIgnored)
+	issuesCollection.ScaViolations, ignoredViolations = splitSuppressedRows(issuesCollection.ScaViolations, isScaIssueIgnored)
+	issuesCollection.IgnoredScaIssues = append(issuesCollection.IgnoredScaIssues, append(ignoredVulnerabilities, ignoredViolations...)...)
+	issuesCollection.IgnoredIacIssues = append(issuesCollection.IgnoredIacIssues, splitSuppressedSourceCodeIssues(&issuesCollection.IacVulnerabilities, &issuesCollection.IacViolations, isSourceCodeIssueIgnored)...)
+	if ignoreSecrets {
+		issuesCollection.IgnoredSecretsIssues = append(issuesCollection.IgnoredSecretsIssues, splitSuppressedSourceCodeIssues(&issuesCollection.SecretsVulnerabilities, &issuesCollection.SecretsViolations, isSourceCodeIssueIgnored)...)
+	}
+	issuesCollection.IgnoredSastIssues = append(issuesCollection.IgnoredSastIssues, splitSuppressedSourceCodeIssues(&issuesCollection.SastVulnerabilities, &issuesCollection.SastViolations, isSourceCodeIssueIgnored)...)
+}
+
+func splitSuppressedRows[T any](rows []T, isSuppressed func(T) bool) (reportedRows, suppressedRows []T) {
+	for _, row := range rows {
+		if isSuppressed(row) {
+			suppressedRows = append(suppressedRows, row)
+		} else {
+			reportedRows = append(reportedRows, row)
+		}
+	}
+	return
+}
+
+func splitSuppressedSourceCodeIssues(vulnerabilities, violations *[]formats.SourceCodeRow, isSuppressed func(formats.SourceCodeRow) bool) []formats.SourceCodeRow {
+	var suppressedVulnerabilities, suppressedViolations []formats.SourceCodeRow
+	*vulnerabilities, suppressedVulnerabilities = splitSuppressedRows(*vulnerabilities, isSuppressed)
+	*violations, suppressedViolations = splitSuppressedRows(*violations, isSuppressed)
+	return append(suppressedVulnerabilities, suppressedViolations...)
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/policy"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPullRequestSuppressions(t *testing.T) {
+	note := func(id int64, author string, body string) map[string]any {
+		authorIds := map[string]int64{"frogbot": 1, "maintainer": 2, "developer": 3}
+		return map[string]any{"id": id, "body": body, "author": map[string]any{"id": authorIds[author], "username": author}}
+	}
+	frogbotNote := func(id int64, findingIds ...string) map[string]any {
+		return note(id, "frogbot", outputwriter.MarkdownComment(outputwriter.ReviewCommentId)+"A Frogbot review comment"+outputwriter.FindingIdsComment(findingIds...))
+	}
+	discussions := []map[string]any{
+		{"id": "thread-1", "notes": []map[string]any{frogbotNote(1, "finding-1", "finding-2"), note(2, "maintainer", " /Frogbot suppress - a false positive")}},
+		{"id": "thread-2", "notes": []map[string]any{frogbotNote(3, "finding-3"), note(4, "developer", "Is this a false positive?")}},
+		{"id": "thread-3", "notes": []map[string]any{frogbotNote(5, "finding-4"), note(6, "developer", "/frogbot suppress")}},
+		{"id": "thread-4", "notes": []map[string]any{frogbotNote(7, "finding-5")}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response any
+		switch r.URL.Path {
+		case "/projects/owner/repo/merge_requests/1/discussions":
+			response = discussions
+		case "/projects/owner/repo/members/all/2":
+			response = map[string]any{"access_level": 40}
+		case "/projects/owner/repo/members/all/3":
+			response = map[string]any{"access_level": 30}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		content, err := json.Marshal(response)
+		assert.NoError(t, err)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name                 string
+		gitProvider          vcsutils.VcsProvider
+		commentSuppressions  bool
+		approvers            []string
+		expectedFindingIds   []string
+		expectedKeptComments []int64
+	}{
+		{name: "Maintainers", gitProvider: vcsutils.GitLab, commentSuppressions: true, expectedFindingIds: []string{"finding-1", "finding-2"}, expectedKeptComments: []int64{1}},
+		{name: "Approvers", gitProvider: vcsutils.GitLab, commentSuppressions: true, approvers: []string{"Developer"}, expectedFindingIds: []string{"finding-4"}, expectedKeptComments: []int64{5}},
+		{name: "Comment suppressions disallowed", gitProvider: vcsutils.GitLab},
+		{name: "Other Git provider", gitProvider: vcsutils.GitHub, commentSuppressions: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &Repository{Params: Params{
+				Scan: Scan{CommentSuppressions: tc.commentSuppressions, SuppressionApprovers: tc.approvers},
+				Git:  Git{GitProvider: tc.gitProvider, RepoOwner: "owner", RepoName: "repo", VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}},
+			}}
+			suppressions, err := GetPullRequestSuppressions(repo, 1)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedFindingIds, suppressions.findingIds.ToSlice())
+			assert.ElementsMatch(t, tc.expectedKeptComments, suppressions.commentIds.ToSlice())
+		})
+	}
+}
+
+func TestPullRequestSuppressionsFilterIssues(t *testing.T) {
+	log4j := formats.VulnerabilityOrViolationRow{
+		IssueId:                   "XRAY-191789",
+		Cves:                      []formats.CveRow{{Id: "CVE-2021-44228"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "org.apache.logging.log4j:log4j-core", ImpactedDependencyVersion: "2.14.1"},
+	}
+	lodash := formats.VulnerabilityOrViolationRow{
+		IssueId:                   "XRAY-1234",
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"},
+	}
+	sastFinding := formats.SourceCodeRow{ScannerInfo: formats.ScannerInfo{RuleId: "js-insecure-random"}, Location: formats.Location{File: "src/test/random.js"}}
+	secretFinding := formats.SourceCodeRow{ScannerInfo: formats.ScannerInfo{RuleId: "REQ.SECRET.KEYS"}, Location: formats.Location{File: "config/secrets.yml"}}
+
+	deniedPackage := formats.VulnerabilityOrViolationRow{
+		IssueId:                   "XRAY-5678",
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: "event-stream", ImpactedDependencyVersion: "3.3.6"},
+	}
+
+	suppressions := NewPullRequestSuppressions()
+	for _, suppressed := range []formats.VulnerabilityOrViolationRow{log4j, deniedPackage} {
+		suppressions.findingIds.Add(issues.GetScaFindingId(suppressed))
+	}
+	for _, suppressed := range []formats.SourceCodeRow{sastFinding, secretFinding} {
+		suppressions.findingIds.Add(issues.GetSourceCodeFindingId(suppressed))
+	}
+	issuesCollection := &issues.ScansIssuesCollection{
+		ScaVulnerabilities:     []formats.VulnerabilityOrViolationRow{log4j, lodash, deniedPackage},
+		SastVulnerabilities:    []formats.SourceCodeRow{sastFinding},
+		SecretsVulnerabilities: []formats.SourceCodeRow{secretFinding},
+	}
+	suppressions.FilterIssues(issuesCollection, &policy.Policy{DeniedPackages: []string{"event-stream"}})
+
+	// The secrets and the findings that break the policy are reported even if they were suppressed
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{lodash, deniedPackage}, issuesCollection.ScaVulnerabilities)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{log4j}, issuesCollection.IgnoredScaIssues)
+	assert.Empty(t, issuesCollection.SastVulnerabilities)
+	assert.Equal(t, []formats.SourceCodeRow{sastFinding}, issuesCollection.IgnoredSastIssues)
+	assert.Equal(t, []formats.SourceCodeRow{secretFinding}, issuesCollection.SecretsVulnerabilities)
+	assert.Empty(t, issuesCollection.IgnoredSecretsIssues)
+
+	// No suppressions
+	var noSuppressions *PullRequestSuppressions
+	assert.NotPanics(t, func() { noSuppressions.FilterIssues(issuesCollection, nil) })
+}
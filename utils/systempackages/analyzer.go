@@ -0,0 +1,175 @@
+package systempackages
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayutils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// The root node of the scanned graph, whose child nodes are the packages of the package lists
+const rootNodeId = "frogbot-system-packages"
+
+// XrayScanner runs Xray graph scans. The Xray services manager implements it.
+type XrayScanner interface {
+	ScanGraph(params services.XrayGraphScanParams) (scanId string, err error)
+	GetScanGraphResults(scanId, xrayVersion string, includeVulnerabilities, includeLicenses, xscEnabled bool) (*services.ScanResponse, error)
+}
+
+// Analyzer scans the packages that the Conda environment files and the package lists of apt and apk pin, with Xray.
+// The audit doesn't detect these files, such as the package lists of Docker build contexts, so their packages are read from the files instead.
+type Analyzer struct {
+	scanner     XrayScanner
+	xrayVersion string
+	// The Xray IDs of the packages of the target branch. Their vulnerabilities aren't added by the pull request, so they aren't reported.
+	targetComponents *datastructures.Set[string]
+	// The packages of package lists that were already scanned, so each vulnerable package is reported once for all the projects
+	reported *datastructures.Set[string]
+}
+
+// Returns nil if the scan of the system packages isn't enabled, which disables the analysis
+func NewAnalyzer(enabled bool, serverDetails *config.ServerDetails, xrayVersion string) (*Analyzer, error) {
+	if !enabled {
+		return nil, nil
+	}
+	xrayManager, err := xray.CreateXrayServiceManager(serverDetails)
+	if err != nil {
+		return nil, err
+	}
+	return newAnalyzer(xrayManager, xrayVersion), nil
+}
+
+func newAnalyzer(scanner XrayScanner, xrayVersion string) *Analyzer {
+	return &Analyzer{scanner: scanner, xrayVersion: xrayVersion, reported: datastructures.MakeSet[string]()}
+}
+
+// Sets the package lists of the target branch of a pull request, so only the packages that the pull request adds are scanned by the next analysis
+func (a *Analyzer) SetTargetBranch(dirs ...string) error {
+	if a == nil {
+		return nil
+	}
+	components, err := FindComponents(dirs...)
+	if err != nil {
+		return err
+	}
+	a.targetComponents = datastructures.MakeSet[string]()
+	for _, component := range components {
+		a.targetComponents.Add(component.XrayId)
+	}
+	return nil
+}
+
+// Returns the vulnerabilities of the packages of the package lists in the directories.
+// The paths of the package lists are reported relative to the root directory of the repository.
+func (a *Analyzer) Analyze(rootDir string, dirs ...string) (vulnerabilities []issues.SystemPackageVulnerability, err error) {
+	if a == nil {
+		return
+	}
+	targetComponents := a.targetComponents
+	a.targetComponents = nil
+	components, err := FindComponents(dirs...)
+	if err != nil {
+		return
+	}
+	var componentsToScan []Component
+	for _, component := range components {
+		if relativePath, e := filepath.Rel(rootDir, component.File); e == nil {
+			component.File = filepath.ToSlash(relativePath)
+		}
+		key := component.File + "|" + component.XrayId
+		if a.reported.Exists(key) || (targetComponents != nil && targetComponents.Exists(component.XrayId)) {
+			continue
+		}
+		a.reported.Add(key)
+		componentsToScan = append(componentsToScan, component)
+	}
+	if len(componentsToScan) == 0 {
+		return
+	}
+	log.Info(fmt.Sprintf("Scanning %d packages of Conda environment files and OS package lists...", len(componentsToScan)))
+	scanResponse, err := a.scan(componentsToScan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan the packages of the package lists: %s", err.Error())
+	}
+	return getVulnerabilities(scanResponse, componentsToScan), nil
+}
+
+// Returns the packages of the package lists in the directories
+func FindComponents(dirs ...string) (components []Component, err error) {
+	packageLists, err := FindPackageLists(dirs...)
+	if err != nil {
+		return
+	}
+	for _, packageList := range packageLists {
+		var packageListComponents []Component
+		if packageListComponents, err = ParsePackageList(packageList); err != nil {
+			return
+		}
+		components = append(components, packageListComponents...)
+	}
+	return
+}
+
+func (a *Analyzer) scan(components []Component) (*services.ScanResponse, error) {
+	graph := &xrayutils.GraphNode{Id: rootNodeId}
+	addedIds := datastructures.MakeSet[string]()
+	for _, component := range components {
+		// The same package may be pinned by several package lists
+		if !addedIds.Exists(component.XrayId) {
+			addedIds.Add(component.XrayId)
+			graph.Nodes = append(graph.Nodes, &xrayutils.GraphNode{Id: component.XrayId})
+		}
+	}
+	scanId, err := a.scanner.ScanGraph(services.XrayGraphScanParams{
+		DependenciesGraph:      graph,
+		IncludeVulnerabilities: true,
+		ScanType:               services.Dependency,
+		XrayVersion:            a.xrayVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a.scanner.GetScanGraphResults(scanId, a.xrayVersion, true, false, false)
+}
+
+// Returns a vulnerability of each package that the Xray vulnerabilities impact, in the order of the packages
+func getVulnerabilities(scanResponse *services.ScanResponse, components []Component) (vulnerabilities []issues.SystemPackageVulnerability) {
+	if scanResponse == nil {
+		return
+	}
+	for _, component := range components {
+		for _, xrayVulnerability := range scanResponse.Vulnerabilities {
+			impactedComponent, isImpacted := xrayVulnerability.Components[component.XrayId]
+			if !isImpacted {
+				continue
+			}
+			var cves []string
+			for _, cve := range xrayVulnerability.Cves {
+				if cve.Id != "" {
+					cves = append(cves, cve.Id)
+				}
+			}
+			vulnerabilities = append(vulnerabilities, issues.SystemPackageVulnerability{
+				File:          component.File,
+				Line:          component.Line,
+				PackageType:   component.Type,
+				Name:          component.Name,
+				Version:       component.Version,
+				Severity:      xrayVulnerability.Severity,
+				IssueId:       xrayVulnerability.IssueId,
+				Cves:          cves,
+				FixedVersions: impactedComponent.FixedVersions,
+			})
+		}
+	}
+	if len(vulnerabilities) > 0 {
+		log.Info(fmt.Sprintf("Found %d vulnerabilities in the packages of Conda environment files and OS package lists", len(vulnerabilities)))
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package systempackages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockXrayScanner struct {
+	scannedIds []string
+	response   *services.ScanResponse
+}
+
+func (ms *mockXrayScanner) ScanGraph(params services.XrayGraphScanParams) (string, error) {
+	ms.scannedIds = nil
+	for _, node := range params.DependenciesGraph.Nodes {
+		ms.scannedIds = append(ms.scannedIds, node.Id)
+	}
+	return "scan-id", nil
+}
+
+func (ms *mockXrayScanner) GetScanGraphResults(string, string, bool, bool, bool) (*services.ScanResponse, error) {
+	return ms.response, nil
+}
+
+func TestAnalyzer(t *testing.T) {
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "docker"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "environment.yml"), []byte("dependencies:\n  - numpy=1.21.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "docker", "apt.txt"), []byte("curl=7.68.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(targetDir, "environment.yml"), []byte("dependencies:\n  - numpy=1.21.0\n"), 0644))
+	scanner := &mockXrayScanner{response: &services.ScanResponse{Vulnerabilities: []services.Vulnerability{
+		{IssueId: "XRAY-1", Severity: "High", Cves: []services.Cve{{Id: "CVE-2021-41495"}}, Components: map[string]services.Component{"conda://numpy:1.21.0": {FixedVersions: []string{"[1.22.0]"}}}},
+		{IssueId: "XRAY-2", Severity: "Low", Components: map[string]services.Component{"deb://curl:7.68.0": {}}},
+	}}}
+
+	// Only the packages that the pull request adds are scanned
+	analyzer := newAnalyzer(scanner, "3.107.0")
+	require.NoError(t, analyzer.SetTargetBranch(targetDir))
+	vulnerabilities, err := analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deb://curl:7.68.0"}, scanner.scannedIds)
+	assert.Equal(t, []issues.SystemPackageVulnerability{{File: "docker/apt.txt", Line: 1, PackageType: DebianType, Name: "curl", Version: "7.68.0", Severity: "Low", IssueId: "XRAY-2"}}, vulnerabilities)
+
+	// All the packages are scanned without a target branch
+	analyzer = newAnalyzer(scanner, "3.107.0")
+	vulnerabilities, err = analyzer.Analyze(sourceDir, sourceDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"conda://numpy:1.21.0", "deb://curl:7.68.0"}, scanner.scannedIds)
+	require.Len(t, vulnerabilities, 2)
+	assert.Contains(t, vulnerabilities, issues.SystemPackageVulnerability{File: "environment.yml", Line: 2, PackageType: CondaType, Name: "numpy", Version: "1.21.0", Severity: "High", IssueId: "XRAY-1", Cves: []string{"CVE-2021-41495"}, FixedVersions: []string{"[1.22.0]"}})
+
+	// The packages are reported once for all the projects
+	scanner.scannedIds = nil
+	vulnerabilities, err = analyzer.Analyze(sourceDir, filepath.Join(sourceDir, "docker"))
+	require.NoError(t, err)
+	assert.Empty(t, vulnerabilities)
+	assert.Empty(t, scanner.scannedIds)
+
+	// The analysis is disabled
+	var disabled *Analyzer
+	vulnerabilities, err = disabled.Analyze(sourceDir, sourceDir)
+	assert.NoError(t, err)
+	assert.Empty(t, vulnerabilities)
+}
@@ -0,0 +1,198 @@
+package systempackages
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	CondaType  = "Conda"
+	PypiType   = "PyPI"
+	DebianType = "Debian"
+	AlpineType = "Alpine"
+)
+
+var (
+	// The environment files of Conda, which may pin the Conda packages and the pip packages of the environment
+	condaEnvironmentFiles = []string{"environment.yml", "environment.yaml"}
+	// The package lists of apt, as the apt buildpack and repo2docker name them, and of apk
+	aptListFiles = []string{"Aptfile", "apt.txt", "apt-packages.txt"}
+	apkListFiles = []string{"apk.txt", "apk-packages.txt"}
+	// The directories that don't contain the package lists of the project
+	skippedDirs = []string{".git", "node_modules", "vendor"}
+	// The match specifications of Conda and pip that aren't pinned to a version, such as 'numpy>=1.0', 'numpy=1.*' or 'numpy=1.0|1.1'
+	unpinnedSpecRegex = regexp.MustCompile(`[<>!~*|,]`)
+)
+
+// Component is a package that a Conda environment file or an OS package list pins to a version
+type Component struct {
+	// CondaType, PypiType, DebianType or AlpineType
+	Type    string
+	Name    string
+	Version string
+	// The Xray component ID, such as 'conda://numpy:1.21.0' or 'deb://openssl:3.0.11'
+	XrayId string
+	// The path of the package list and the line of the package
+	File string
+	Line int
+}
+
+// Returns true for the Conda environment files and the package lists of apt and apk
+func IsPackageList(fileName string) bool {
+	return slices.Contains(condaEnvironmentFiles, fileName) || slices.Contains(aptListFiles, fileName) || slices.Contains(apkListFiles, fileName)
+}
+
+// Returns the paths of the package lists in the directories and their subdirectories
+func FindPackageLists(dirs ...string) (packageLists []string, err error) {
+	visited := map[string]bool{}
+	for _, dir := range dirs {
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, innerErr error) error {
+			if innerErr != nil {
+				return innerErr
+			}
+			if d.IsDir() {
+				if path != dir && slices.Contains(skippedDirs, d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			// The working directories of a project may be nested
+			if IsPackageList(d.Name()) && !visited[path] {
+				visited[path] = true
+				packageLists = append(packageLists, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for package lists in '%s': %s", dir, err.Error())
+		}
+	}
+	return
+}
+
+// Returns the packages that the package list pins to a version. Xray requires the version of the scanned components, so the other packages are skipped.
+func ParsePackageList(path string) (components []Component, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the package list '%s': %s", path, err.Error())
+	}
+	fileName := filepath.Base(path)
+	switch {
+	case slices.Contains(condaEnvironmentFiles, fileName):
+		components, err = parseCondaEnvironment(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the Conda environment file '%s': %s", path, err.Error())
+		}
+	case slices.Contains(aptListFiles, fileName):
+		components, err = parseOsPackageList(content, DebianType, "deb://")
+	default:
+		components, err = parseOsPackageList(content, AlpineType, "alpine://")
+	}
+	for i := range components {
+		components[i].File = path
+	}
+	return
+}
+
+// Parses the dependencies of a Conda environment file, including the pip packages of its 'pip' dependency
+func parseCondaEnvironment(content []byte) (components []Component, err error) {
+	var environment struct {
+		Dependencies []yaml.Node `yaml:"dependencies"`
+	}
+	if err = yaml.Unmarshal(content, &environment); err != nil {
+		return
+	}
+	for _, dependency := range environment.Dependencies {
+		switch dependency.Kind {
+		case yaml.ScalarNode:
+			if component, ok := parseCondaSpec(dependency.Value); ok {
+				component.Line = dependency.Line
+				components = append(components, component)
+			}
+		case yaml.MappingNode:
+			// The pip packages are listed under a 'pip' key, such as: - pip: ["requests==2.25.1"]
+			for i := 0; i+1 < len(dependency.Content); i += 2 {
+				if dependency.Content[i].Value != "pip" {
+					continue
+				}
+				for _, pipDependency := range dependency.Content[i+1].Content {
+					if component, ok := parsePipSpec(pipDependency.Value); ok {
+						component.Line = pipDependency.Line
+						components = append(components, component)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// Parses a match specification of Conda, such as 'numpy=1.21.0', 'numpy==1.21.0=py39h_0', 'conda-forge::numpy=1.21.0' or 'numpy 1.21.0'
+func parseCondaSpec(spec string) (component Component, ok bool) {
+	if index := strings.Index(spec, "::"); index >= 0 {
+		spec = spec[index+2:]
+	}
+	var name, version string
+	if fields := strings.Fields(spec); len(fields) > 1 {
+		name, version = fields[0], fields[1]
+	} else {
+		name, version, _ = strings.Cut(strings.Replace(spec, "==", "=", 1), "=")
+		// The build string follows the version
+		version, _, _ = strings.Cut(version, "=")
+	}
+	if !isPinned(spec, version) {
+		return
+	}
+	return Component{Type: CondaType, Name: name, Version: version, XrayId: fmt.Sprintf("conda://%s:%s", name, version)}, true
+}
+
+// Parses a requirement of pip that is pinned with '==', such as 'requests==2.25.1' or 'requests[socks]==2.25.1'
+func parsePipSpec(spec string) (component Component, ok bool) {
+	spec, _, _ = strings.Cut(spec, ";")
+	name, version, found := strings.Cut(strings.ReplaceAll(spec, " ", ""), "==")
+	if !found || strings.HasPrefix(name, "-") {
+		log.Debug(fmt.Sprintf("Skipping the pip package '%s', since it isn't pinned to a version", spec))
+		return
+	}
+	if !isPinned(spec, version) {
+		return
+	}
+	name, _, _ = strings.Cut(name, "[")
+	return Component{Type: PypiType, Name: strings.ToLower(name), Version: version, XrayId: fmt.Sprintf("pypi://%s:%s", strings.ToLower(name), version)}, true
+}
+
+func isPinned(spec, version string) bool {
+	if version == "" || unpinnedSpecRegex.MatchString(spec) {
+		log.Debug(fmt.Sprintf("Skipping the package '%s', since it isn't pinned to a version", spec))
+		return false
+	}
+	return true
+}
+
+// Parses a package list of apt or apk, with packages that are pinned as 'name=version'.
+// The packages are separated by whitespaces or lines, and the lines that start with '#' are comments.
+func parseOsPackageList(content []byte, packageType, xrayPrefix string) (components []Component, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		for _, field := range strings.Fields(line) {
+			name, version, found := strings.Cut(field, "=")
+			if !found || version == "" {
+				log.Debug(fmt.Sprintf("Skipping the OS package '%s', since it isn't pinned to a version", field))
+				continue
+			}
+			components = append(components, Component{Type: packageType, Name: name, Version: version, XrayId: fmt.Sprintf("%s%s:%s", xrayPrefix, name, version), Line: lineNumber})
+		}
+	}
+	return components, scanner.Err()
+}
@@ -0,0 +1,74 @@
+package systempackages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCondaEnvironment(t *testing.T) {
+	content := `name: ml
+channels:
+  - conda-forge
+dependencies:
+  - python=3.9
+  - numpy==1.21.0=py39h_0
+  - conda-forge::pandas=1.3.5
+  - scipy 1.7.3
+  - matplotlib>=3.4
+  - requests
+  - pip
+  - pip:
+      - flask==2.0.1
+      - Jinja2[i18n]==3.0.1 ; python_version >= "3.6"
+      - gunicorn>=20.0
+      - -r requirements.txt
+`
+	components, err := parseCondaEnvironment([]byte(content))
+	require.NoError(t, err)
+	assert.Equal(t, []Component{
+		{Type: CondaType, Name: "python", Version: "3.9", XrayId: "conda://python:3.9", Line: 5},
+		{Type: CondaType, Name: "numpy", Version: "1.21.0", XrayId: "conda://numpy:1.21.0", Line: 6},
+		{Type: CondaType, Name: "pandas", Version: "1.3.5", XrayId: "conda://pandas:1.3.5", Line: 7},
+		{Type: CondaType, Name: "scipy", Version: "1.7.3", XrayId: "conda://scipy:1.7.3", Line: 8},
+		{Type: PypiType, Name: "flask", Version: "2.0.1", XrayId: "pypi://flask:2.0.1", Line: 13},
+		{Type: PypiType, Name: "jinja2", Version: "3.0.1", XrayId: "pypi://jinja2:3.0.1", Line: 14},
+	}, components)
+
+	_, err = parseCondaEnvironment([]byte("dependencies: ["))
+	assert.Error(t, err)
+}
+
+func TestParseOsPackageList(t *testing.T) {
+	content := `# The packages of the build image
+curl=7.68.0-1ubuntu2.7 git
+openssl=1.1.1f-1ubuntu2.16  # pinned by the security team
+`
+	components, err := parseOsPackageList([]byte(content), DebianType, "deb://")
+	require.NoError(t, err)
+	assert.Equal(t, []Component{
+		{Type: DebianType, Name: "curl", Version: "7.68.0-1ubuntu2.7", XrayId: "deb://curl:7.68.0-1ubuntu2.7", Line: 2},
+		{Type: DebianType, Name: "openssl", Version: "1.1.1f-1ubuntu2.16", XrayId: "deb://openssl:1.1.1f-1ubuntu2.16", Line: 3},
+	}, components)
+}
+
+func TestFindAndParsePackageLists(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "docker"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "node_modules", "lib"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "environment.yml"), []byte("dependencies:\n  - numpy=1.21.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "docker", "apk.txt"), []byte("busybox=1.35.0-r17\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "node_modules", "lib", "apt.txt"), []byte("curl=7.68.0\n"), 0644))
+
+	// The package lists of nested working directories are found once
+	packageLists, err := FindPackageLists(rootDir, filepath.Join(rootDir, "docker"))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{filepath.Join(rootDir, "environment.yml"), filepath.Join(rootDir, "docker", "apk.txt")}, packageLists)
+
+	components, err := ParsePackageList(filepath.Join(rootDir, "docker", "apk.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, []Component{{Type: AlpineType, Name: "busybox", Version: "1.35.0-r17", XrayId: "alpine://busybox:1.35.0-r17", File: filepath.Join(rootDir, "docker", "apk.txt"), Line: 1}}, components)
+}
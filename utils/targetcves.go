@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+)
+
+// Reads the target CVE IDs and packages from an input file.
+// Each line lists one or more comma separated CVE IDs (CVE-2021-44228) or packages with an optional version (log4j-core@2.14.1).
+// Empty lines and lines that start with '#' are ignored.
+func ReadTargetCvesFile(path string) ([]string, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the target CVEs file '%s': %s", path, err.Error())
+	}
+	var entries []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, strings.Split(line, ",")...)
+	}
+	targetCves, err := normalizeTargetCves(entries)
+	if err != nil {
+		return nil, err
+	}
+	if len(targetCves) == 0 {
+		return nil, fmt.Errorf("the target CVEs file '%s' doesn't list any CVE ID or package", path)
+	}
+	return targetCves, nil
+}
+
+// Validates the target CVE IDs and packages. The CVE IDs are converted to upper case.
+func normalizeTargetCves(entries []string) (targetCves []string, err error) {
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		if cveIdRegex.MatchString(entry) {
+			entry = strings.ToUpper(entry)
+		} else if _, _, valid := splitPackageTarget(entry); !valid {
+			return nil, fmt.Errorf("the target '%s' is invalid. Expected a CVE ID in the format: CVE-2021-44228, or a package in the format: package@version", entry)
+		}
+		targetCves = append(targetCves, entry)
+	}
+	return
+}
+
+// Returns true if the vulnerability is related to one of the given CVE IDs, or is in one of the given packages
+func IsTargetedVulnerability(vulnerability formats.VulnerabilityOrViolationRow, targetCves []string) bool {
+	for _, target := range targetCves {
+		if cveIdRegex.MatchString(target) {
+			if IsTargetedCve(vulnerability, []string{target}) {
+				return true
+			}
+			continue
+		}
+		if packageName, packageVersion, _ := splitPackageTarget(target); isTargetedPackage(vulnerability, packageName, packageVersion) {
+			return true
+		}
+	}
+	return false
+}
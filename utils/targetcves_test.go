@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTargetCvesFile(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expected      []string
+		expectedError bool
+	}{
+		{name: "CVEs and packages", content: "# Emergency fixes\ncve-2021-44228\r\n\nlog4j-core@2.14.1,, @mycompany/ui\n", expected: []string{"CVE-2021-44228", "log4j-core@2.14.1", "@mycompany/ui"}},
+		{name: "Package without a version", content: "lodash@", expectedError: true},
+		{name: "Comments only", content: "# Nothing to fix yet\n", expectedError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targetCvesFile := filepath.Join(t.TempDir(), "target-cves.txt")
+			require.NoError(t, os.WriteFile(targetCvesFile, []byte(tc.content), 0644))
+			targetCves, err := ReadTargetCvesFile(targetCvesFile)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, targetCves)
+		})
+	}
+
+	targetCves, err := ReadTargetCvesFile(filepath.Join("..", "testdata", "config", "target-cves.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"CVE-2021-44228", "log4j-core@2.14.1", "@mycompany/ui"}, targetCves)
+
+	_, err = ReadTargetCvesFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.ErrorContains(t, err, "couldn't read the target CVEs file")
+}
+
+func TestIsTargetedVulnerability(t *testing.T) {
+	vulnerability := formats.VulnerabilityOrViolationRow{
+		Cves: []formats.CveRow{{Id: "CVE-2021-44228"}},
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			ImpactedDependencyName:    "log4j-core",
+			ImpactedDependencyVersion: "2.14.1",
+		},
+	}
+	testCases := []struct {
+		name       string
+		targetCves []string
+		expected   bool
+	}{
+		{name: "Matching CVE", targetCves: []string{"CVE-2021-45046", "CVE-2021-44228"}, expected: true},
+		{name: "Matching package", targetCves: []string{"log4j-core"}, expected: true},
+		{name: "Matching package and version", targetCves: []string{"log4j-core@2.14.1"}, expected: true},
+		{name: "Other version", targetCves: []string{"log4j-core@2.15.0"}, expected: false},
+		{name: "Other CVE and package", targetCves: []string{"CVE-2021-45046", "log4j-api"}, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsTargetedVulnerability(vulnerability, tc.targetCves))
+		})
+	}
+}
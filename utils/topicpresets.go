@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jfrog/frogbot/v2/utils/repodiscovery"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v2"
+)
+
+const configTopicPresetsKey = "topicPresets"
+
+// The params of the 'topicPresets' entry of the config, by the repository topics that select them
+type topicPresets map[string]map[interface{}]interface{}
+
+// Removes the 'topicPresets' entry from the config and returns its presets.
+// The entry maps repository topics to params, such as 'internal-tool' to the params of a report-only scan.
+func extractTopicPresets(yamlContent []byte) ([]byte, topicPresets, error) {
+	var entries []map[interface{}]interface{}
+	if err := yaml.Unmarshal(yamlContent, &entries); err != nil {
+		return nil, nil, err
+	}
+	var presets topicPresets
+	var repositories []map[interface{}]interface{}
+	for _, entry := range entries {
+		entryPresets, isPresets := entry[configTopicPresetsKey]
+		if !isPresets {
+			repositories = append(repositories, entry)
+			continue
+		}
+		if presets != nil {
+			return nil, nil, errors.New("the frogbot config may include only one 'topicPresets' entry")
+		}
+		presetsMap, isMap := entryPresets.(map[interface{}]interface{})
+		if !isMap || len(entry) > 1 {
+			return nil, nil, errors.New("the 'topicPresets' entry of the frogbot config must be a map of repository topics to params")
+		}
+		presets = topicPresets{}
+		for topic, params := range presetsMap {
+			paramsMap, isParamsMap := params.(map[interface{}]interface{})
+			if !isParamsMap {
+				return nil, nil, fmt.Errorf("the '%v' preset of the 'topicPresets' entry of the frogbot config must be a map of params", topic)
+			}
+			presets[fmt.Sprint(topic)] = paramsMap
+		}
+	}
+	if presets == nil {
+		return yamlContent, nil, nil
+	}
+	content, err := yaml.Marshal(repositories)
+	return content, presets, err
+}
+
+// Applies the presets of the repository topics to the params of the repository.
+// The params of the repository, including the defaults of the config, override the presets.
+// If several topics have presets, they're applied in the alphabetical order of the topics.
+func (tp topicPresets) apply(repository *Repository, topics []string) error {
+	var matchedTopics []string
+	for _, topic := range topics {
+		if _, exists := tp[topic]; exists {
+			matchedTopics = append(matchedTopics, topic)
+		}
+	}
+	if len(matchedTopics) == 0 {
+		return nil
+	}
+	sort.Strings(matchedTopics)
+	merged := map[interface{}]interface{}{}
+	for _, topic := range matchedTopics {
+		merged = mergeConfigMaps(merged, tp[topic])
+	}
+	content, err := yaml.Marshal(repository.Params)
+	if err != nil {
+		return err
+	}
+	var params map[interface{}]interface{}
+	if err = yaml.Unmarshal(content, &params); err != nil {
+		return err
+	}
+	if content, err = yaml.Marshal(mergeConfigMaps(merged, params)); err != nil {
+		return err
+	}
+	var presetParams Params
+	if err = yaml.Unmarshal(content, &presetParams); err != nil {
+		return fmt.Errorf("failed to apply the presets of the %v topics to the '%s' repository: %s", matchedTopics, repository.RepoName, err.Error())
+	}
+	repository.Params = presetParams
+	log.Info(fmt.Sprintf("Applied the configuration presets of the %v topics to the '%s' repository", matchedTopics, repository.RepoName))
+	return nil
+}
+
+// Applies the presets of the topics of each repository, which are requested from the Git provider
+func applyTopicPresets(aggregator RepoAggregator, presets topicPresets, gitParamsFromEnv *Git) error {
+	if len(presets) == 0 {
+		return nil
+	}
+	for i := range aggregator {
+		repoName := aggregator[i].RepoName
+		if repoName == "" {
+			repoName = gitParamsFromEnv.RepoName
+		}
+		topics, err := repodiscovery.GetTopics(gitParamsFromEnv.GitProvider, gitParamsFromEnv.VcsInfo, gitParamsFromEnv.RepoOwner, repoName)
+		if err != nil {
+			return fmt.Errorf("failed to get the topics of the '%s' repository, which select its configuration presets: %s", repoName, err.Error())
+		}
+		if err = presets.apply(&aggregator[i], topics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
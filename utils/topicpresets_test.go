@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicPresets(t *testing.T) {
+	config := `
+- topicPresets:
+    internal-tool:
+      scan:
+        skipAutoFix: true
+        minSeverity: High
+    legacy:
+      scan:
+        minSeverity: Critical
+- defaults:
+    scan:
+      fixableOnly: true
+- params:
+    git:
+      repoName: tools
+- params:
+    git:
+      repoName: api
+    scan:
+      minSeverity: Low
+- params:
+    git:
+      repoName: web
+`
+	var requestedPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		switch r.URL.Path {
+		case "/repos/jfrog/tools/topics":
+			_, _ = w.Write([]byte(`{"names":["legacy","internal-tool"]}`))
+		case "/repos/jfrog/api/topics":
+			_, _ = w.Write([]byte(`{"names":["internal-tool"]}`))
+		default:
+			_, _ = w.Write([]byte(`{"names":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	aggregator, presets, err := unmarshalFrogbotConfigYaml([]byte(config))
+	require.NoError(t, err)
+	require.Len(t, aggregator, 3)
+	assert.Len(t, presets, 2)
+	gitParams := &Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", VcsInfo: vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}}
+	require.NoError(t, applyTopicPresets(aggregator, presets, gitParams))
+	assert.Equal(t, []string{"/repos/jfrog/tools/topics", "/repos/jfrog/api/topics", "/repos/jfrog/web/topics"}, requestedPaths)
+
+	// The presets are applied in the alphabetical order of the topics, and the defaults are kept
+	assert.True(t, aggregator[0].DetectionOnly)
+	assert.Equal(t, "Critical", aggregator[0].MinSeverity)
+	assert.True(t, aggregator[0].FixableOnly)
+	assert.Equal(t, "tools", aggregator[0].RepoName)
+	// The params of the repository override the presets
+	assert.True(t, aggregator[1].DetectionOnly)
+	assert.Equal(t, "Low", aggregator[1].MinSeverity)
+	// The repositories without topics that have presets are unchanged
+	assert.False(t, aggregator[2].DetectionOnly)
+	assert.Empty(t, aggregator[2].MinSeverity)
+	assert.True(t, aggregator[2].FixableOnly)
+
+	// The topics aren't requested without presets
+	requestedPaths = nil
+	require.NoError(t, applyTopicPresets(aggregator, nil, gitParams))
+	assert.Empty(t, requestedPaths)
+
+	// The topics of the Git provider can't be requested
+	gitParams.GitProvider = vcsutils.BitbucketCloud
+	assert.ErrorContains(t, applyTopicPresets(aggregator, presets, gitParams), "failed to get the topics of the 'tools' repository")
+}
+
+func TestExtractTopicPresetsErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        string
+		expectedError string
+	}{
+		{name: "Multiple presets entries", config: `[{topicPresets: {}}, {topicPresets: {}}]`, expectedError: "only one 'topicPresets' entry"},
+		{name: "Presets with params", config: `[{topicPresets: {a: {}}, params: {git: {repoName: repo}}}]`, expectedError: "must be a map of repository topics to params"},
+		{name: "Presets that aren't a map", config: `[{topicPresets: [a]}]`, expectedError: "must be a map of repository topics to params"},
+		{name: "Preset that isn't a map", config: `[{topicPresets: {a: [scan]}}]`, expectedError: "the 'a' preset of the 'topicPresets' entry of the frogbot config must be a map of params"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := extractTopicPresets([]byte(tc.config))
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
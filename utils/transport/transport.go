@@ -0,0 +1,57 @@
+// Package transport builds the HTTP clients of Frogbot, which send their requests through the proxy and trust the CA bundle of the run.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+)
+
+var (
+	// A copy of the default transport of the process, taken before any of its settings are changed
+	baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+	mutex   sync.RWMutex
+	proxy   func(*http.Request) (*url.URL, error)
+	rootCAs *x509.CertPool
+)
+
+// Configure sets the proxy and the trusted certificates of the transports that are built afterward.
+// A nil proxy keeps the proxy of the standard environment variables, and nil certificates keep the system certificates.
+func Configure(proxyFunc func(*http.Request) (*url.URL, error), certificates *x509.CertPool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	proxy, rootCAs = proxyFunc, certificates
+}
+
+// New returns a transport with the proxy and the trusted certificates of the run
+func New() *http.Transport {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	transport := baseTransport.Clone()
+	if proxy != nil {
+		transport.Proxy = proxy
+	}
+	if rootCAs != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+	return transport
+}
+
+// NewClient returns an HTTP client with the transport of the run. A zero timeout doesn't limit the requests.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: New(), Timeout: timeout}
+}
+
+// NewHttpClient returns a JFrog HTTP client with the transport of the run
+func NewHttpClient() (*httpclient.HttpClient, error) {
+	return httpclient.ClientBuilder().SetHttpClient(NewClient(0)).Build()
+}
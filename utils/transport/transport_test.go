@@ -0,0 +1,38 @@
+package transport
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	defer Configure(nil, nil)
+
+	// Without settings, the transport keeps the proxy of the environment variables and the system certificates
+	transport := New()
+	assert.NotNil(t, transport.Proxy)
+	assert.True(t, transport.TLSClientConfig == nil || transport.TLSClientConfig.RootCAs == nil)
+
+	proxyUrl, err := url.Parse("http://proxy.example.com:3128")
+	require.NoError(t, err)
+	certificates := x509.NewCertPool()
+	Configure(http.ProxyURL(proxyUrl), certificates)
+	transport = New()
+	req, err := http.NewRequest(http.MethodGet, "https://frogbot.example.com", nil)
+	require.NoError(t, err)
+	actualProxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, proxyUrl, actualProxy)
+	assert.Same(t, certificates, transport.TLSClientConfig.RootCAs)
+	// Each transport is a separate copy
+	assert.NotSame(t, transport, New())
+
+	client, err := NewHttpClient()
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/gofrog/datastructures"
+)
+
+type RiskLevel string
+
+const (
+	LowRisk    RiskLevel = "Low"
+	MediumRisk RiskLevel = "Medium"
+	HighRisk   RiskLevel = "High"
+)
+
+// The semantic version parts that an upgrade changes
+const (
+	PatchUpgrade   = "patch"
+	MinorUpgrade   = "minor"
+	MajorUpgrade   = "major"
+	UnknownUpgrade = "unknown"
+)
+
+// An upgrade that changes more transitive dependencies than this in the lockfiles is riskier
+const maxLowRiskTransitiveChanges = 10
+
+var versionPartsRegex = regexp.MustCompile(`\d+`)
+
+// UpgradeRisk estimates how likely the upgrade of a dependency by a fix pull request is to break the project, so reviewers can prioritize the fixes
+type UpgradeRisk struct {
+	Level RiskLevel
+	// The semantic version part the upgrade changes: patch, minor, major or unknown
+	VersionChange string
+	// The versions of the other dependencies that changed in the regenerated lockfiles
+	TransitiveChanges int
+	// Whether the project has tests that may catch breaking changes
+	HasTests bool
+}
+
+// Estimates the risk of an upgrade. Each of a major upgrade, many transitive changes and missing tests raises the risk.
+func NewUpgradeRisk(currentVersion, fixedVersion string, transitiveChanges int, hasTests bool) *UpgradeRisk {
+	risk := &UpgradeRisk{VersionChange: GetVersionChange(currentVersion, fixedVersion), TransitiveChanges: transitiveChanges, HasTests: hasTests}
+	score := 0
+	switch risk.VersionChange {
+	case MajorUpgrade:
+		score += 2
+	case MinorUpgrade, UnknownUpgrade:
+		score++
+	}
+	if transitiveChanges > maxLowRiskTransitiveChanges {
+		score++
+	}
+	if !hasTests {
+		score++
+	}
+	switch {
+	case score >= 2:
+		risk.Level = HighRisk
+	case score == 1:
+		risk.Level = MediumRisk
+	default:
+		risk.Level = LowRisk
+	}
+	return risk
+}
+
+// Returns the semantic version part that an upgrade changes.
+// Upgrades of the minor version of 0.y.z versions are major, as they don't guarantee compatibility.
+func GetVersionChange(currentVersion, fixedVersion string) string {
+	currentParts, fixedParts := versionPartsRegex.FindAllString(currentVersion, 3), versionPartsRegex.FindAllString(fixedVersion, 3)
+	if len(currentParts) == 0 || len(fixedParts) == 0 {
+		return UnknownUpgrade
+	}
+	for i := 0; i < 3; i++ {
+		if getVersionPart(currentParts, i) == getVersionPart(fixedParts, i) {
+			continue
+		}
+		if i == 0 || (i == 1 && getVersionPart(currentParts, 0) == "0") {
+			return MajorUpgrade
+		}
+		if i == 1 {
+			return MinorUpgrade
+		}
+		return PatchUpgrade
+	}
+	return PatchUpgrade
+}
+
+func getVersionPart(parts []string, index int) string {
+	if index >= len(parts) {
+		return "0"
+	}
+	// Leading zeros don't change the version
+	if part := strings.TrimLeft(parts[index], "0"); part != "" {
+		return part
+	}
+	return "0"
+}
+
+// Returns the highest upgrade risk of the fixes, or an empty level if their risk wasn't analyzed
+func GetHighestUpgradeRisk(vulnDetails []*VulnerabilityDetails) (highest RiskLevel) {
+	for _, vuln := range vulnDetails {
+		if vuln.UpgradeRisk == nil {
+			continue
+		}
+		if vuln.UpgradeRisk.Level == HighRisk {
+			return HighRisk
+		}
+		if highest == "" || vuln.UpgradeRisk.Level == MediumRisk {
+			highest = vuln.UpgradeRisk.Level
+		}
+	}
+	return
+}
+
+// Returns the upgrade risk of each upgraded dependency, when the risk of the upgrades was analyzed
+func GetUpgradeRiskRows(vulnDetails []*VulnerabilityDetails) (rows []outputwriter.UpgradeRiskRow) {
+	addedUpgrades := datastructures.MakeSet[string]()
+	for _, vuln := range vulnDetails {
+		upgradeId := vuln.ImpactedDependencyName + vuln.ImpactedDependencyVersion + vuln.SuggestedFixedVersion
+		if vuln.UpgradeRisk == nil || addedUpgrades.Exists(upgradeId) {
+			continue
+		}
+		addedUpgrades.Add(upgradeId)
+		rows = append(rows, outputwriter.UpgradeRiskRow{
+			ImpactedDependencyName:    vuln.ImpactedDependencyName,
+			ImpactedDependencyVersion: vuln.ImpactedDependencyVersion,
+			FixedVersion:              vuln.SuggestedFixedVersion,
+			Risk:                      string(vuln.UpgradeRisk.Level),
+			VersionChange:             vuln.UpgradeRisk.VersionChange,
+			TransitiveChanges:         vuln.UpgradeRisk.TransitiveChanges,
+			HasTests:                  vuln.UpgradeRisk.HasTests,
+		})
+	}
+	return
+}
+
+// Adds the risk badge to the title of a fix pull request, when the risk of its upgrades was analyzed
+func AddUpgradeRiskToTitle(title string, vulnDetails []*VulnerabilityDetails) string {
+	if risk := GetHighestUpgradeRisk(vulnDetails); risk != "" {
+		return "[Risk: " + string(risk) + "] " + title
+	}
+	return title
+}
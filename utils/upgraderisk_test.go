@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVersionChange(t *testing.T) {
+	testCases := []struct {
+		currentVersion string
+		fixedVersion   string
+		expected       string
+	}{
+		{currentVersion: "3.0.4", fixedVersion: "3.0.5", expected: PatchUpgrade},
+		{currentVersion: "2.30.0", fixedVersion: "2.31.0", expected: MinorUpgrade},
+		{currentVersion: "3.10.1", fixedVersion: "4.17.21", expected: MajorUpgrade},
+		{currentVersion: "v1.2.3", fixedVersion: "v1.2.3.1", expected: PatchUpgrade},
+		{currentVersion: "1.2", fixedVersion: "1.2.1", expected: PatchUpgrade},
+		{currentVersion: "1.02.0", fixedVersion: "1.2.1", expected: PatchUpgrade},
+		// The minor upgrades of 0.y.z versions may break the compatibility
+		{currentVersion: "0.9.2", fixedVersion: "0.10.0", expected: MajorUpgrade},
+		{currentVersion: "0.9.2", fixedVersion: "0.9.3", expected: PatchUpgrade},
+		{currentVersion: "latest", fixedVersion: "1.0.0", expected: UnknownUpgrade},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.currentVersion+" "+tc.fixedVersion, func(t *testing.T) {
+			assert.Equal(t, tc.expected, GetVersionChange(tc.currentVersion, tc.fixedVersion))
+		})
+	}
+}
+
+func TestNewUpgradeRisk(t *testing.T) {
+	testCases := []struct {
+		name              string
+		fixedVersion      string
+		transitiveChanges int
+		hasTests          bool
+		expected          RiskLevel
+	}{
+		{name: "Tested patch upgrade", fixedVersion: "1.2.4", hasTests: true, expected: LowRisk},
+		{name: "Untested patch upgrade", fixedVersion: "1.2.4", expected: MediumRisk},
+		{name: "Patch upgrade with many transitive changes", fixedVersion: "1.2.4", transitiveChanges: 11, hasTests: true, expected: MediumRisk},
+		{name: "Tested minor upgrade", fixedVersion: "1.3.0", hasTests: true, expected: MediumRisk},
+		{name: "Untested minor upgrade", fixedVersion: "1.3.0", expected: HighRisk},
+		{name: "Tested major upgrade", fixedVersion: "2.0.0", hasTests: true, expected: HighRisk},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			risk := NewUpgradeRisk("1.2.3", tc.fixedVersion, tc.transitiveChanges, tc.hasTests)
+			assert.Equal(t, tc.expected, risk.Level)
+			assert.Equal(t, tc.transitiveChanges, risk.TransitiveChanges)
+		})
+	}
+}
+
+func TestAddUpgradeRiskToTitle(t *testing.T) {
+	newVulnDetails := func(name string, risk *UpgradeRisk) *VulnerabilityDetails {
+		vulnDetails := NewVulnerabilityDetails(formats.VulnerabilityOrViolationRow{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{ImpactedDependencyName: name, ImpactedDependencyVersion: "1.0.0"}}, "1.0.1")
+		vulnDetails.UpgradeRisk = risk
+		return vulnDetails
+	}
+	title := "[🐸 Frogbot] Update version of minimist to 1.2.6"
+	// The risk isn't added when it wasn't analyzed
+	assert.Equal(t, title, AddUpgradeRiskToTitle(title, []*VulnerabilityDetails{newVulnDetails("minimist", nil)}))
+
+	vulnDetails := []*VulnerabilityDetails{
+		newVulnDetails("minimist", &UpgradeRisk{Level: LowRisk}),
+		newVulnDetails("lodash", &UpgradeRisk{Level: MediumRisk}),
+		newVulnDetails("minimatch", nil),
+	}
+	assert.Equal(t, "[Risk: Medium] "+title, AddUpgradeRiskToTitle(title, vulnDetails))
+	assert.Len(t, GetUpgradeRiskRows(vulnDetails), 2)
+	vulnDetails = append(vulnDetails, newVulnDetails("express", &UpgradeRisk{Level: HighRisk}), newVulnDetails("axios", &UpgradeRisk{Level: LowRisk}))
+	assert.Equal(t, "[Risk: High] "+title, AddUpgradeRiskToTitle(title, vulnDetails))
+}
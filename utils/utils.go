@@ -6,29 +6,46 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
+	"github.com/jfrog/frogbot/v2/utils/exploitability"
 	"github.com/jfrog/frogbot/v2/utils/issues"
+	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/releasenotes"
+	"github.com/jfrog/frogbot/v2/utils/report"
+	"github.com/jfrog/frogbot/v2/utils/transport"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/gofrog/version"
 	"github.com/jfrog/jfrog-cli-core/v2/common/commands"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/usage"
+	"github.com/jfrog/jfrog-cli-security/jas"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
 	"github.com/jfrog/jfrog-cli-security/utils/formats/sarifutils"
 	"github.com/jfrog/jfrog-cli-security/utils/results"
 	"github.com/jfrog/jfrog-cli-security/utils/results/conversion"
+	"github.com/jfrog/jfrog-cli-security/utils/results/conversion/sarifparser"
 	"github.com/jfrog/jfrog-cli-security/utils/results/output"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
-	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/owenrumney/go-sarif/v2/sarif"
 )
 
 const (
@@ -36,6 +53,14 @@ const (
 	ScanAllPullRequests      = "scan-all-pull-requests"
 	ScanRepository           = "scan-repository"
 	ScanMultipleRepositories = "scan-multiple-repositories"
+	FixCampaign              = "fix-campaign"
+	ValidateConfig           = "validate-config"
+	Benchmark                = "benchmark"
+	GenerateBaseline         = "generate-baseline"
+	Upgrade                  = "upgrade"
+	Serve                    = "serve"
+	ReportCoverage           = "report-coverage"
+	ScanLocal                = "scan-local"
 	RootDir                  = "."
 	branchNameRegex          = `[~^:?\\\[\]@{}*]`
 
@@ -47,13 +72,23 @@ const (
 	skipIndirectVulnerabilitiesMsg = "\n%s is an indirect dependency that will not be updated to version %s.\nFixing indirect dependencies can potentially cause conflicts with other dependencies that depend on the previous version.\nFrogbot skips this to avoid potential incompatibilities and breaking changes."
 	skipBuildToolDependencyMsg     = "Skipping vulnerable package %s since it is not defined in your package descriptor file. " +
 		"Update %s version to %s to fix this vulnerability."
-	JfrogHomeDirEnv = "JFROG_CLI_HOME_DIR"
+	skipPluginUnsupportedFixMsg = "Skipping vulnerable package %s since the package handler plugin doesn't support updating it to version %s."
+	JfrogHomeDirEnv             = "JFROG_CLI_HOME_DIR"
+	// The SARIF result property that holds the stable finding ID
+	FindingIdSarifPropertyKey = "frogbotFindingId"
 )
 
 var (
 	TrueVal                 = true
 	FrogbotVersion          = "0.0.0"
 	branchInvalidCharsRegex = regexp.MustCompile(branchNameRegex)
+	// The wait between repository download attempts
+	downloadRetriesIntervalMilliSecs = 5000
+	// The status code of a failed response in the errors of the Git provider clients, for example 'server response: 502 Bad Gateway'
+	responseStatusCodeRegex = regexp.MustCompile(`(?:server response|(?:GET|HEAD|POST|PUT|PATCH|DELETE) \S+): (\d{3})\b`)
+	// The attempts to check the JAS entitlement, before skipping the JAS scans
+	jasEntitlementRetries                  = 3
+	jasEntitlementRetriesIntervalMilliSecs = 2000
 )
 
 var BuildToolsDependenciesMap = map[techutils.Technology][]string{
@@ -71,15 +106,44 @@ type ErrNothingToCommit struct {
 	PackageName string
 }
 
+// ErrPolicyFailure fails the run because of the security issues that were found, rather than because the scan couldn't be completed.
+// Frogbot exits with ExitCodePolicyFailure in this case.
+type ErrPolicyFailure struct {
+	Message string
+}
+
 // Custom error for unsupported fixes
-// Currently we hold two unsupported reasons, indirect and build tools dependencies.
+// The fix of indirect and build tools dependencies isn't supported, nor a fix that a package handler plugin reports as unsupported.
 func (err *ErrUnsupportedFix) Error() string {
-	if err.ErrorType == IndirectDependencyFixNotSupported {
+	switch err.ErrorType {
+	case IndirectDependencyFixNotSupported:
 		return fmt.Sprintf(skipIndirectVulnerabilitiesMsg, err.PackageName, err.FixedVersion)
+	case UnsupportedByPackageHandlerPlugin:
+		return fmt.Sprintf(skipPluginUnsupportedFixMsg, err.PackageName, err.FixedVersion)
 	}
 	return fmt.Sprintf(skipBuildToolDependencyMsg, err.PackageName, err.PackageName, err.FixedVersion)
 }
 
+// Returns a short description of the reason the fix isn't supported
+func (err *ErrUnsupportedFix) Reason() string {
+	switch err.ErrorType {
+	case IndirectDependencyFixNotSupported:
+		return "Indirect dependency"
+	case BuildToolsDependencyFixNotSupported:
+		return "Build tools dependency"
+	case UnsupportedForFixVulnerableVersion:
+		return "Unsupported vulnerable version"
+	case UnsupportedByPackageHandlerPlugin:
+		return "Unsupported by the package handler plugin"
+	default:
+		return string(err.ErrorType)
+	}
+}
+
+func (err *ErrPolicyFailure) Error() string {
+	return err.Message
+}
+
 func (err *ErrNothingToCommit) Error() string {
 	return fmt.Sprintf("there were no changes to commit after fixing the package '%s'.\n"+
 		"Note: Frogbot currently cannot address certain vulnerabilities in some package managers, which may result in the absence of changes", err.PackageName)
@@ -94,6 +158,12 @@ type VulnerabilityDetails struct {
 	IsDirectDependency bool
 	// Cves as a list of string
 	Cves []string
+	// The exploitability of the most exploitable CVE, set when the exploitability enrichment is enabled
+	Exploitability *exploitability.Info
+	// The workspaces of an npm or Yarn monorepo whose package.json files were updated by the fix, set by the package handler
+	UpdatedWorkspaces []string
+	// The estimated risk of the upgrade, set when the upgrade risk analysis is enabled
+	UpgradeRisk *UpgradeRisk
 }
 
 func NewVulnerabilityDetails(vulnerability formats.VulnerabilityOrViolationRow, fixVersion string) *VulnerabilityDetails {
@@ -109,6 +179,16 @@ func (vd *VulnerabilityDetails) SetIsDirectDependency(isDirectDependency bool) {
 	vd.IsDirectDependency = isDirectDependency
 }
 
+func (vd *VulnerabilityDetails) SetExploitability(exploitabilityInfo map[string]exploitability.Info) {
+	info := exploitability.GetMostExploitable(exploitabilityInfo, vd.Cves...)
+	vd.Exploitability = &info
+}
+
+// Returns true if any of the vulnerability CVEs is in the CISA Known Exploited Vulnerabilities catalog
+func (vd *VulnerabilityDetails) IsKnownExploited() bool {
+	return vd.Exploitability != nil && vd.Exploitability.KnownExploited
+}
+
 func (vd *VulnerabilityDetails) SetCves(cves []formats.CveRow) {
 	for _, cve := range cves {
 		vd.Cves = append(vd.Cves, cve.Id)
@@ -130,6 +210,49 @@ func ExtractVulnerabilitiesDetailsToRows(vulnDetails []*VulnerabilityDetails) []
 	return rows
 }
 
+// Returns the release notes links of the dependencies that are upgraded by the given fixes.
+// Dependencies of technologies without a release notes resolver are skipped. The npm upgrades are linked to their diffs only if npmDiffLinks is set.
+func GetReleaseNotesRows(vulnDetails []*VulnerabilityDetails, npmDiffLinks bool) (rows []outputwriter.ReleaseNotesRow) {
+	addedUpgrades := datastructures.MakeSet[string]()
+	for _, vuln := range vulnDetails {
+		upgradeId := vuln.ImpactedDependencyName + vuln.ImpactedDependencyVersion + vuln.SuggestedFixedVersion
+		if addedUpgrades.Exists(upgradeId) {
+			continue
+		}
+		links := releasenotes.Resolve(vuln.Technology, npmDiffLinks, vuln.ImpactedDependencyName, vuln.ImpactedDependencyVersion, vuln.SuggestedFixedVersion)
+		if links.IsEmpty() {
+			continue
+		}
+		addedUpgrades.Add(upgradeId)
+		rows = append(rows, outputwriter.ReleaseNotesRow{
+			ImpactedDependencyName:    vuln.ImpactedDependencyName,
+			ImpactedDependencyVersion: vuln.ImpactedDependencyVersion,
+			FixedVersion:              vuln.SuggestedFixedVersion,
+			ReleaseNotesUrl:           links.ReleaseNotes,
+			DiffUrl:                   links.Diff,
+		})
+	}
+	return
+}
+
+// Returns the workspaces of a monorepo that the fixes of the vulnerabilities updated, for the dependencies that were updated in workspaces
+func GetUpdatedWorkspacesRows(vulnDetails []*VulnerabilityDetails) (rows []outputwriter.UpdatedWorkspacesRow) {
+	addedUpgrades := datastructures.MakeSet[string]()
+	for _, vuln := range vulnDetails {
+		upgradeId := vuln.ImpactedDependencyName + vuln.SuggestedFixedVersion
+		if len(vuln.UpdatedWorkspaces) == 0 || addedUpgrades.Exists(upgradeId) {
+			continue
+		}
+		addedUpgrades.Add(upgradeId)
+		rows = append(rows, outputwriter.UpdatedWorkspacesRow{
+			ImpactedDependencyName: vuln.ImpactedDependencyName,
+			FixedVersion:           vuln.SuggestedFixedVersion,
+			Workspaces:             vuln.UpdatedWorkspaces,
+		})
+	}
+	return
+}
+
 type ErrMissingEnv struct {
 	VariableName string
 }
@@ -236,6 +359,36 @@ func UploadSarifResultsToGithubSecurityTab(scanResults *results.SecurityCommandR
 	return nil
 }
 
+// Converts the audit results to a collection of all the issues that were found
+func ConvertToIssuesCollection(cmdResults *results.SecurityCommandResults, allowedLicenses []string) (*issues.ScansIssuesCollection, error) {
+	simpleJsonResults, err := conversion.NewCommandResultsConvertor(conversion.ResultConvertParams{
+		IncludeVulnerabilities: cmdResults.IncludesVulnerabilities(),
+		HasViolationContext:    cmdResults.HasViolationContext(),
+		AllowedLicenses:        allowedLicenses,
+		IncludeLicenses:        true,
+		SimplifiedOutput:       true,
+	}).ConvertToSimpleJson(cmdResults)
+	if err != nil {
+		return nil, err
+	}
+	return &issues.ScansIssuesCollection{
+		ScanStatus:         simpleJsonResults.Statuses,
+		ScaVulnerabilities: simpleJsonResults.Vulnerabilities,
+		ScaViolations:      simpleJsonResults.SecurityViolations,
+		LicensesViolations: simpleJsonResults.LicensesViolations,
+		Licenses:           simpleJsonResults.Licenses,
+
+		IacVulnerabilities: simpleJsonResults.IacsVulnerabilities,
+		IacViolations:      simpleJsonResults.IacsViolations,
+
+		SecretsVulnerabilities: simpleJsonResults.SecretsVulnerabilities,
+		SecretsViolations:      simpleJsonResults.SecretsViolations,
+
+		SastVulnerabilities: simpleJsonResults.SastVulnerabilities,
+		SastViolations:      simpleJsonResults.SastViolations,
+	}, nil
+}
+
 func GenerateFrogbotSarifReport(extendedResults *results.SecurityCommandResults, allowedLicenses []string) (string, error) {
 	convertor := conversion.NewCommandResultsConvertor(conversion.ResultConvertParams{
 		IncludeVulnerabilities: extendedResults.IncludesVulnerabilities(),
@@ -246,10 +399,38 @@ func GenerateFrogbotSarifReport(extendedResults *results.SecurityCommandResults,
 	if err != nil {
 		return "", err
 	}
+	addFindingIdsToSarifReport(sarifReport)
 	return output.WriteSarifResultsAsString(sarifReport, false)
 }
 
-func DownloadRepoToTempDir(client vcsclient.VcsClient, repoOwner, repoName, branch string) (wd string, cleanup func() error, err error) {
+// Adds the finding ID property to each result, matching the ID shown in the pull request comments
+func addFindingIdsToSarifReport(report *sarif.Report) {
+	for _, run := range report.Runs {
+		isScaRun := sarifutils.GetRunToolName(run) == sarifparser.ScaScannerToolName
+		for _, result := range run.Results {
+			ruleId := sarifutils.GetResultRuleId(result)
+			findingId := issues.GetScaRuleFindingId(ruleId)
+			if !isScaRun {
+				var file, snippet string
+				if len(result.Locations) > 0 {
+					file = sarifutils.GetRelativeLocationFileName(result.Locations[0], run.Invocations)
+					snippet = sarifutils.GetLocationSnippetText(result.Locations[0])
+				}
+				findingId = issues.GetJasFindingId(ruleId, file, snippet)
+			}
+			if result.Properties == nil {
+				result.Properties = sarif.Properties{}
+			}
+			result.Properties[FindingIdSarifPropertyKey] = findingId
+		}
+	}
+}
+
+// Downloads the branch of the repository to a new temp directory.
+// Failed downloads are retried up to the configured number of retries. An archive can't be resumed, so each retry downloads it from the start.
+// If submodules are enabled, the submodules of the repository are downloaded as well.
+// If shallow clones are enabled, the branch is cloned instead, and its archive is downloaded only if the clone fails.
+func DownloadRepoToTempDir(client vcsclient.VcsClient, repoOwner, repoName, branch string, gitParams *Git) (wd string, cleanup func() error, err error) {
 	wd, err = fileutils.CreateTempDir()
 	if err != nil {
 		return
@@ -257,15 +438,73 @@ func DownloadRepoToTempDir(client vcsclient.VcsClient, repoOwner, repoName, bran
 	cleanup = func() error {
 		return fileutils.RemoveTempDir(wd)
 	}
+	if gitParams.ShallowClone {
+		log.Debug(fmt.Sprintf("Cloning <%s/%s/%s> to: '%s'", repoOwner, repoName, branch, wd))
+		if err = shallowClone(client, gitParams, repoOwner, repoName, branch, wd); err == nil {
+			return
+		}
+		log.Warn(fmt.Sprintf("Failed to clone <%s/%s/%s>, so its archive is downloaded instead: %s", repoOwner, repoName, branch, err.Error()))
+		// Remove the partial clone before the download
+		if err = clearDir(wd); err != nil {
+			return
+		}
+	}
 	log.Debug(fmt.Sprintf("Downloading <%s/%s/%s> to: '%s'", repoOwner, repoName, branch, wd))
-	if err = client.DownloadRepository(context.Background(), repoOwner, repoName, branch, wd); err != nil {
+	retryExecutor := clientutils.RetryExecutor{
+		MaxRetries:               gitParams.DownloadRetries,
+		RetriesIntervalMilliSecs: downloadRetriesIntervalMilliSecs,
+		ErrorMessage:             fmt.Sprintf("Failed to download branch: <%s/%s/%s>", repoOwner, repoName, branch),
+		ExecutionHandler: func() (shouldRetry bool, err error) {
+			if err = client.DownloadRepository(context.Background(), repoOwner, repoName, branch, wd); err == nil {
+				return false, nil
+			}
+			// Remove the partially extracted archive before the next attempt
+			return isRetriableDownloadError(err), errors.Join(err, clearDir(wd))
+		},
+	}
+	if err = retryExecutor.Execute(); err != nil {
 		err = fmt.Errorf("failed to download branch: <%s/%s/%s> with error: %s", repoOwner, repoName, branch, err.Error())
 		return
 	}
 	log.Debug("Repository download completed")
+	if gitParams.Submodules {
+		err = downloadSubmodules(client, gitParams, repoOwner, repoName, branch, wd)
+	}
 	return
 }
 
+func clearDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns true if the download failed because of the network or the server, so it may succeed when it's retried.
+// Other failures, like a missing branch or missing permissions, fail the same way on every retry.
+func isRetriableDownloadError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// The Git provider clients don't always wrap the underlying errors, so their messages are checked too
+	message := err.Error()
+	if strings.Contains(message, io.ErrUnexpectedEOF.Error()) || strings.Contains(message, syscall.ECONNRESET.Error()) {
+		return true
+	}
+	if match := responseStatusCodeRegex.FindStringSubmatch(message); match != nil {
+		statusCode, _ := strconv.Atoi(match[1])
+		return statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
 func ValidateSingleRepoConfiguration(configAggregator *RepoAggregator) error {
 	// Multi repository configuration is supported only in the scanallpullrequests and scanmultiplerepositories commands.
 	if len(*configAggregator) > 1 {
@@ -297,7 +536,7 @@ func validateBranchName(branchName string) error {
 	if len(branchName) == 0 {
 		return nil
 	}
-	branchNameWithoutPlaceHolders := formatStringWithPlaceHolders(branchName, "", "", "", "", true)
+	branchNameWithoutPlaceHolders := formatStringWithPlaceHolders(branchName, placeHolderValues{}, true)
 	if branchInvalidCharsRegex.MatchString(branchNameWithoutPlaceHolders) {
 		return errors.New(branchInvalidChars)
 	}
@@ -323,6 +562,9 @@ func BuildServerConfigFile(server *config.ServerDetails) (previousJFrogHomeDir,
 	if err = os.Setenv(JfrogHomeDirEnv, currentJFrogHomeDir); err != nil {
 		return
 	}
+	if err = installCaBundle(currentJFrogHomeDir); err != nil {
+		return
+	}
 	cc := commands.NewConfigCommand(commands.AddOrEdit, "frogbot").SetDetails(server)
 	err = cc.Run()
 	return
@@ -347,6 +589,90 @@ func ConvertSarifPathsToRelative(issues *issues.ScansIssuesCollection, workingDi
 	convertSarifPathsInSast(issues.SastViolations, workingDirs...)
 }
 
+// Sets the locations of the direct dependencies of the SCA issues to the working directories they were found in, relative to the root of the repository.
+// All the branches are scanned with the same working directories, so fullPathWorkingDirs are the working directories of the first branch in branchWds.
+func ConvertScaLocationsToWorkingDirs(issues *issues.ScansIssuesCollection, fullPathWorkingDirs []string, branchWds ...string) {
+	if len(branchWds) == 0 {
+		return
+	}
+	var workingDirs []string
+	for _, fullPathWd := range fullPathWorkingDirs {
+		if workingDir, err := filepath.Rel(branchWds[0], fullPathWd); err == nil {
+			workingDirs = append(workingDirs, workingDir)
+		}
+	}
+	for _, rows := range [][]formats.VulnerabilityOrViolationRow{issues.ScaVulnerabilities, issues.ScaViolations, issues.FixedScaIssues} {
+		for i := range rows {
+			for j := range rows[i].Components {
+				if location := rows[i].Components[j].Location; location != nil {
+					// The location may be shared with other rows, so it's replaced rather than modified
+					rows[i].Components[j].Location = &formats.Location{File: getScaWorkingDir(location.File, workingDirs, branchWds)}
+				}
+			}
+		}
+	}
+}
+
+// Returns the working directory that the path is in. Nested working directories take precedence over the directories that contain them.
+func getScaWorkingDir(path string, workingDirs, branchWds []string) string {
+	for _, branchWd := range branchWds {
+		relativePath, err := filepath.Rel(branchWd, path)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			continue
+		}
+		matchingWd := ""
+		for _, workingDir := range workingDirs {
+			if workingDir != RootDir && len(workingDir) > len(matchingWd) && (relativePath == workingDir || strings.HasPrefix(relativePath, workingDir+string(filepath.Separator))) {
+				matchingWd = workingDir
+			}
+		}
+		if matchingWd == "" {
+			return RootDir
+		}
+		return filepath.ToSlash(matchingWd)
+	}
+	return path
+}
+
+// Keeps only the SCA issues that are related to one of the target CVEs, or are in one of the target packages.
+// Other scanners don't report CVEs, so their issues are removed as well. If no target CVEs are provided, the issues are left untouched.
+func FilterIssuesByTargetCves(issues *issues.ScansIssuesCollection, targetCves []string) {
+	if issues == nil || len(targetCves) == 0 {
+		return
+	}
+	issues.ScaVulnerabilities = filterRowsByTargetCves(issues.ScaVulnerabilities, targetCves)
+	issues.ScaViolations = filterRowsByTargetCves(issues.ScaViolations, targetCves)
+	issues.FixedScaIssues = filterRowsByTargetCves(issues.FixedScaIssues, targetCves)
+	issues.LicensesViolations = nil
+	issues.IacVulnerabilities, issues.IacViolations = nil, nil
+	issues.SecretsVulnerabilities, issues.SecretsViolations = nil, nil
+	issues.SastVulnerabilities, issues.SastViolations = nil, nil
+}
+
+func filterRowsByTargetCves(rows []formats.VulnerabilityOrViolationRow, targetCves []string) (filteredRows []formats.VulnerabilityOrViolationRow) {
+	for _, row := range rows {
+		if IsTargetedVulnerability(row, targetCves) {
+			filteredRows = append(filteredRows, row)
+		}
+	}
+	return
+}
+
+// Returns true if the vulnerability is related to one of the given CVE IDs
+func IsTargetedCve(vulnerability formats.VulnerabilityOrViolationRow, targetCves []string) bool {
+	for _, targetCve := range targetCves {
+		if strings.EqualFold(vulnerability.IssueId, targetCve) {
+			return true
+		}
+		for _, cve := range vulnerability.Cves {
+			if strings.EqualFold(cve.Id, targetCve) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func convertSarifPathsInCveApplicability(vulnerabilities []formats.VulnerabilityOrViolationRow, workingDirs ...string) {
 	for _, row := range vulnerabilities {
 		for _, cve := range row.Cves {
@@ -393,6 +719,53 @@ func convertSarifPathsInSast(sast []formats.SourceCodeRow, workingDirs ...string
 	}
 }
 
+// Removes the findings of the advanced security scanners in files that match the exclusions of their scanners, or the exclude patterns of all the scanners.
+// The paths of the findings must be relative to the root of the repository.
+func FilterJasIssuesByExclusions(issues *issues.ScansIssuesCollection, jasScanners JasScanners, excludePatterns []string) {
+	issues.SecretsVulnerabilities = filterSourceCodeRowsByExclusions(issues.SecretsVulnerabilities, jasScanners.SecretsExclusions, excludePatterns)
+	issues.SecretsViolations = filterSourceCodeRowsByExclusions(issues.SecretsViolations, jasScanners.SecretsExclusions, excludePatterns)
+	issues.IacVulnerabilities = filterSourceCodeRowsByExclusions(issues.IacVulnerabilities, jasScanners.IacExclusions, excludePatterns)
+	issues.IacViolations = filterSourceCodeRowsByExclusions(issues.IacViolations, jasScanners.IacExclusions, excludePatterns)
+	issues.SastVulnerabilities = filterSourceCodeRowsByExclusions(issues.SastVulnerabilities, jasScanners.SastExclusions, excludePatterns)
+	issues.SastViolations = filterSourceCodeRowsByExclusions(issues.SastViolations, jasScanners.SastExclusions, excludePatterns)
+}
+
+func filterSourceCodeRowsByExclusions(rows []formats.SourceCodeRow, exclusions, excludePatterns []string) []formats.SourceCodeRow {
+	if len(exclusions) == 0 && len(excludePatterns) == 0 {
+		return rows
+	}
+	return slices.DeleteFunc(rows, func(row formats.SourceCodeRow) bool {
+		file := filepath.ToSlash(row.Location.File)
+		return isExcludedPath(file, exclusions) || MatchesExcludePatterns(file, excludePatterns)
+	})
+}
+
+// A path is excluded if it or one of its parent directories matches one of the exclusion patterns, for example: src/*/generated
+func isExcludedPath(file string, exclusions []string) bool {
+	file = strings.TrimPrefix(file, "/")
+	for {
+		if slices.ContainsFunc(exclusions, func(exclusion string) bool { return matchesFilePath(strings.TrimSuffix(exclusion, "/"), file) }) {
+			return true
+		}
+		parent := path.Dir(file)
+		if parent == "." || parent == "/" || parent == file {
+			return false
+		}
+		file = parent
+	}
+}
+
+// Returns the details of the current runtime environment, to be shown in the comments footer
+func NewRuntimeDetails(xrayVersion, xscVersion string, scanStartTime time.Time) *outputwriter.RuntimeDetails {
+	return &outputwriter.RuntimeDetails{
+		FrogbotVersion:         FrogbotVersion,
+		XrayVersion:            xrayVersion,
+		XscVersion:             xscVersion,
+		AnalyzerManagerVersion: jas.GetAnalyzerManagerVersion(),
+		ScanDuration:           time.Since(scanStartTime),
+	}
+}
+
 // Normalizes whitespace in text, ensuring that words are separated by a single space, and any extra whitespace is removed.
 func normalizeWhitespaces(text string) string {
 	return strings.Join(strings.Fields(text), " ")
@@ -441,7 +814,7 @@ func (ic *UrlAccessChecker) IsConnected() bool {
 // isUrlAccessible Checks if the url is accessible
 func isUrlAccessible(url string) bool {
 	// Build client
-	client, err := httpclient.ClientBuilder().Build()
+	client, err := transport.NewHttpClient()
 	if err != nil {
 		log.Debug(fmt.Sprintf("Can't check access to '%s', build client:\n%s", url, err.Error()))
 		return false
@@ -469,3 +842,14 @@ func CreateErrorIfPartialResultsDisabled(allowPartial bool, messageForLog string
 	}
 	return err
 }
+
+// Adds the scan report to the job summary of the GitHub Actions step, when running in GitHub Actions. Failing to write the job summary doesn't fail the scan.
+func WriteJobSummaryIfNeeded(scanReport *report.ScanReport) {
+	summaryPath := os.Getenv(GitHubStepSummaryEnv)
+	if summaryPath == "" {
+		return
+	}
+	if err := scanReport.WriteJobSummary(summaryPath); err != nil {
+		log.Warn("Failed to write the job summary:", err.Error())
+	}
+}
@@ -1,16 +1,30 @@
 package utils
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
+	"syscall"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils/issues"
 	"github.com/jfrog/frogbot/v2/utils/outputwriter"
+	"github.com/jfrog/frogbot/v2/utils/report"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-security/utils/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/results/conversion/sarifparser"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/owenrumney/go-sarif/v2/sarif"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChdir(t *testing.T) {
@@ -400,3 +414,247 @@ func TestIsUrlAccessible(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterIssuesByTargetCves(t *testing.T) {
+	log4jVulnerability := formats.VulnerabilityOrViolationRow{Cves: []formats.CveRow{{Id: "CVE-2021-44228"}}}
+	lodashVulnerability := formats.VulnerabilityOrViolationRow{Cves: []formats.CveRow{{Id: "CVE-2021-23337"}}}
+	xrayViolation := formats.VulnerabilityOrViolationRow{IssueId: "XRAY-1234"}
+	newIssues := func() *issues.ScansIssuesCollection {
+		return &issues.ScansIssuesCollection{
+			ScaVulnerabilities:     []formats.VulnerabilityOrViolationRow{log4jVulnerability, lodashVulnerability},
+			ScaViolations:          []formats.VulnerabilityOrViolationRow{xrayViolation, log4jVulnerability},
+			SecretsVulnerabilities: []formats.SourceCodeRow{{Finding: "secret"}},
+		}
+	}
+
+	testCases := []struct {
+		name                       string
+		targetCves                 []string
+		expectedScaVulnerabilities []formats.VulnerabilityOrViolationRow
+		expectedScaViolations      []formats.VulnerabilityOrViolationRow
+		expectSecrets              bool
+	}{
+		{
+			name:                       "No target CVEs",
+			expectedScaVulnerabilities: []formats.VulnerabilityOrViolationRow{log4jVulnerability, lodashVulnerability},
+			expectedScaViolations:      []formats.VulnerabilityOrViolationRow{xrayViolation, log4jVulnerability},
+			expectSecrets:              true,
+		},
+		{
+			name:                       "Target CVE found",
+			targetCves:                 []string{"CVE-2021-44228"},
+			expectedScaVulnerabilities: []formats.VulnerabilityOrViolationRow{log4jVulnerability},
+			expectedScaViolations:      []formats.VulnerabilityOrViolationRow{log4jVulnerability},
+		},
+		{
+			name:       "Target CVE not found",
+			targetCves: []string{"CVE-2022-22965"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuesCollection := newIssues()
+			FilterIssuesByTargetCves(issuesCollection, tc.targetCves)
+			assert.Equal(t, tc.expectedScaVulnerabilities, issuesCollection.ScaVulnerabilities)
+			assert.Equal(t, tc.expectedScaViolations, issuesCollection.ScaViolations)
+			assert.Equal(t, tc.expectSecrets, len(issuesCollection.SecretsVulnerabilities) > 0)
+		})
+	}
+}
+
+func TestConvertScaLocationsToWorkingDirs(t *testing.T) {
+	sourceWd, targetWd := filepath.Join("tmp", "source"), filepath.Join("tmp", "target")
+	componentsAt := func(files ...string) (components []formats.ComponentRow) {
+		for _, file := range files {
+			components = append(components, formats.ComponentRow{Name: "mkdirp", Version: "0.5.1", Location: &formats.Location{File: file}})
+		}
+		return
+	}
+	sharedLocation := &formats.Location{File: filepath.Join(sourceWd, "a", "package.json")}
+	issuesCollection := &issues.ScansIssuesCollection{
+		ScaVulnerabilities: []formats.VulnerabilityOrViolationRow{{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			Components: append(componentsAt(
+				filepath.Join(sourceWd, "package.json"),
+				filepath.Join(sourceWd, "a", "b", "package.json"),
+				filepath.Join(targetWd, "a", "package.json"),
+			), formats.ComponentRow{Name: "mkdirp", Version: "0.5.1", Location: sharedLocation}, formats.ComponentRow{Name: "mkdirp", Version: "0.5.1"}),
+		}}},
+		FixedScaIssues: []formats.VulnerabilityOrViolationRow{{ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			Components: componentsAt(filepath.Join("other", "package.json")),
+		}}},
+	}
+	workingDirs := []string{sourceWd, filepath.Join(sourceWd, "a"), filepath.Join(sourceWd, "a", "b")}
+	ConvertScaLocationsToWorkingDirs(issuesCollection, workingDirs, sourceWd, targetWd)
+
+	var files []string
+	for _, component := range issuesCollection.ScaVulnerabilities[0].Components[:4] {
+		files = append(files, component.Location.File)
+	}
+	// Nested working directories take precedence over the working directories that contain them
+	assert.Equal(t, []string{RootDir, "a/b", "a", "a"}, files)
+	assert.Nil(t, issuesCollection.ScaVulnerabilities[0].Components[4].Location)
+	assert.Equal(t, filepath.Join("other", "package.json"), issuesCollection.FixedScaIssues[0].Components[0].Location.File)
+	// Shared locations aren't modified
+	assert.Equal(t, filepath.Join(sourceWd, "a", "package.json"), sharedLocation.File)
+}
+
+func TestFilterJasIssuesByExclusions(t *testing.T) {
+	rowsAt := func(files ...string) (rows []formats.SourceCodeRow) {
+		for _, file := range files {
+			rows = append(rows, formats.SourceCodeRow{Location: formats.Location{File: file}})
+		}
+		return
+	}
+	issuesCollection := &issues.ScansIssuesCollection{
+		SecretsVulnerabilities: rowsAt("tests/fixtures/keys.pem", "src/config.go"),
+		SastVulnerabilities:    rowsAt("src/api/generated/client.go", "src/api/handler.go", "/src/web/generated/routes.go"),
+		SastViolations:         rowsAt("src/api/generated/client.go"),
+		IacVulnerabilities:     rowsAt("deploy/main.tf"),
+	}
+	getFiles := func(rows []formats.SourceCodeRow) (files []string) {
+		for _, row := range rows {
+			files = append(files, row.Location.File)
+		}
+		return
+	}
+	FilterJasIssuesByExclusions(issuesCollection, JasScanners{SecretsExclusions: []string{"tests/"}, SastExclusions: []string{"src/*/generated"}, IacExclusions: []string{"deploy/*.yaml"}}, nil)
+	assert.Equal(t, []string{"src/config.go"}, getFiles(issuesCollection.SecretsVulnerabilities))
+	assert.Equal(t, []string{"src/api/handler.go"}, getFiles(issuesCollection.SastVulnerabilities))
+	assert.Empty(t, issuesCollection.SastViolations)
+	assert.Equal(t, []string{"deploy/main.tf"}, getFiles(issuesCollection.IacVulnerabilities))
+
+	// The exclude patterns apply to the findings of all the scanners
+	issuesCollection = &issues.ScansIssuesCollection{
+		SecretsVulnerabilities: rowsAt("vendor/github.com/lib/config.go", "src/config.go"),
+		SastVulnerabilities:    rowsAt("web/static/app.min.js", "web/static/app.js"),
+	}
+	FilterJasIssuesByExclusions(issuesCollection, JasScanners{}, DefaultExcludePatterns)
+	assert.Equal(t, []string{"src/config.go"}, getFiles(issuesCollection.SecretsVulnerabilities))
+	assert.Equal(t, []string{"web/static/app.js"}, getFiles(issuesCollection.SastVulnerabilities))
+}
+
+func TestAddFindingIdsToSarifReport(t *testing.T) {
+	scaResult := sarifutils.CreateResultWithOneLocation("package.json", 0, 0, 0, 0, "lodash 4.17.0", "CVE-2023-1234_lodash_4.17.0", "error")
+	sastResult := sarifutils.CreateResultWithOneLocation("index.js", 1, 2, 3, 4, "eval(input)", "sast-rule", "error")
+	report := &sarif.Report{Runs: []*sarif.Run{
+		sarifutils.CreateRunNameWithResults(sarifparser.ScaScannerToolName, scaResult),
+		sarifutils.CreateRunNameWithResults("USAF", sastResult),
+	}}
+	addFindingIdsToSarifReport(report)
+	assert.Equal(t, issues.GetScaRuleFindingId("CVE-2023-1234_lodash_4.17.0"), scaResult.Properties[FindingIdSarifPropertyKey])
+	assert.Equal(t, issues.GetJasFindingId("sast-rule", "index.js", "eval(input)"), sastResult.Properties[FindingIdSarifPropertyKey])
+}
+
+func TestDownloadRepoToTempDir(t *testing.T) {
+	previousInterval := downloadRetriesIntervalMilliSecs
+	downloadRetriesIntervalMilliSecs = 0
+	defer func() {
+		downloadRetriesIntervalMilliSecs = previousInterval
+	}()
+	failingDownload := func(_ context.Context, _, _, _, localPath string) error {
+		// A partially extracted archive
+		assert.NoError(t, os.WriteFile(filepath.Join(localPath, "partial.txt"), []byte("partial"), 0644))
+		return errors.New("unexpected EOF")
+	}
+	successfulDownload := func(_ context.Context, _, _, _, localPath string) error {
+		return os.WriteFile(filepath.Join(localPath, "package.json"), []byte("{}"), 0644)
+	}
+
+	// The download succeeds after a retry, without the files of the failed attempt
+	mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+	gomock.InOrder(
+		mockVcsClient.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "master", gomock.Any()).DoAndReturn(failingDownload),
+		mockVcsClient.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "master", gomock.Any()).DoAndReturn(successfulDownload),
+	)
+	wd, cleanup, err := DownloadRepoToTempDir(mockVcsClient, "jfrog", "frogbot", "master", &Git{DownloadRetries: 1})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(wd, "package.json"))
+	assert.NoFileExists(t, filepath.Join(wd, "partial.txt"))
+	assert.NoError(t, cleanup())
+
+	// The download fails when the retries are exhausted
+	mockVcsClient = testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "master", gomock.Any()).DoAndReturn(failingDownload).Times(2)
+	_, cleanup, err = DownloadRepoToTempDir(mockVcsClient, "jfrog", "frogbot", "master", &Git{DownloadRetries: 1})
+	assert.ErrorContains(t, err, "unexpected EOF")
+	assert.NoError(t, cleanup())
+
+	// A download that fails because of the request isn't retried
+	mockVcsClient = testdata.NewMockVcsClient(gomock.NewController(t))
+	mockVcsClient.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "master", gomock.Any()).Return(errors.New("server response: 404 Not Found")).Times(1)
+	_, cleanup, err = DownloadRepoToTempDir(mockVcsClient, "jfrog", "frogbot", "master", &Git{DownloadRetries: 2})
+	assert.ErrorContains(t, err, "404")
+	assert.NoError(t, cleanup())
+}
+
+func TestIsRetriableDownloadError(t *testing.T) {
+	testCases := []struct {
+		err       error
+		retriable bool
+	}{
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, retriable: true},
+		{err: fmt.Errorf("failed to extract the archive: %w", io.ErrUnexpectedEOF), retriable: true},
+		{err: errors.New("read tcp 10.0.0.1:443: read: connection reset by peer"), retriable: true},
+		{err: errors.New("server response: 502 Bad Gateway"), retriable: true},
+		{err: errors.New("GET https://api.github.com/repos/jfrog/frogbot/tarball/master: 429 API rate limit exceeded []"), retriable: true},
+		{err: errors.New("server response: 404 Not Found"), retriable: false},
+		{err: errors.New("GET https://gitlab.com/api/v4/projects/1/repository/archive.tar.gz: 401 {message: 401 Unauthorized}"), retriable: false},
+		{err: errors.New("branch not found"), retriable: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.retriable, isRetriableDownloadError(tc.err), tc.err.Error())
+	}
+}
+
+func TestGetReleaseNotesRows(t *testing.T) {
+	newVulnDetails := func(tech techutils.Technology, name, version, fixedVersion string) *VulnerabilityDetails {
+		vulnDetails := &VulnerabilityDetails{SuggestedFixedVersion: fixedVersion}
+		vulnDetails.Technology = tech
+		vulnDetails.ImpactedDependencyName = name
+		vulnDetails.ImpactedDependencyVersion = version
+		return vulnDetails
+	}
+	rows := GetReleaseNotesRows([]*VulnerabilityDetails{
+		newVulnDetails(techutils.Npm, "minimatch", "3.0.4", "3.0.5"),
+		// Another vulnerability fixed by the same upgrade
+		newVulnDetails(techutils.Npm, "minimatch", "3.0.4", "3.0.5"),
+		newVulnDetails(techutils.Conan, "zlib", "1.2", "1.3"),
+	}, false)
+	assert.Equal(t, []outputwriter.ReleaseNotesRow{{
+		ImpactedDependencyName:    "minimatch",
+		ImpactedDependencyVersion: "3.0.4",
+		FixedVersion:              "3.0.5",
+		ReleaseNotesUrl:           "https://www.npmjs.com/package/minimatch/v/3.0.5",
+	}}, rows)
+}
+
+func TestGetUpdatedWorkspacesRows(t *testing.T) {
+	newVulnDetails := func(name, fixedVersion string, workspaces ...string) *VulnerabilityDetails {
+		vulnDetails := &VulnerabilityDetails{SuggestedFixedVersion: fixedVersion, UpdatedWorkspaces: workspaces}
+		vulnDetails.ImpactedDependencyName = name
+		return vulnDetails
+	}
+	rows := GetUpdatedWorkspacesRows([]*VulnerabilityDetails{
+		newVulnDetails("minimist", "1.2.6", "packages/api", "packages/web"),
+		// Another vulnerability fixed by the same upgrade
+		newVulnDetails("minimist", "1.2.6", "packages/api", "packages/web"),
+		// Updated in the root package.json
+		newVulnDetails("lodash", "4.17.21"),
+	})
+	assert.Equal(t, []outputwriter.UpdatedWorkspacesRow{{ImpactedDependencyName: "minimist", FixedVersion: "1.2.6", Workspaces: []string{"packages/api", "packages/web"}}}, rows)
+}
+
+func TestWriteJobSummaryIfNeeded(t *testing.T) {
+	scanReport := &report.ScanReport{Subject: "jfrog/frogbot pull request #1", Writer: &outputwriter.StandardOutput{}}
+	// Outside GitHub Actions there's no job summary
+	t.Setenv(GitHubStepSummaryEnv, "")
+	WriteJobSummaryIfNeeded(scanReport)
+
+	summaryPath := filepath.Join(t.TempDir(), "step_summary.md")
+	t.Setenv(GitHubStepSummaryEnv, summaryPath)
+	WriteJobSummaryIfNeeded(scanReport)
+	content, err := os.ReadFile(summaryPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Scanned: jfrog/frogbot pull request #1")
+	assert.Contains(t, string(content), "did not find any issues")
+}
@@ -0,0 +1,262 @@
+// Package vcsapi sends requests to the REST APIs of the Git providers, for the features that the Git clients don't expose.
+// It resolves the API endpoint of the Git provider, authenticates the requests, pages through the listings,
+// and sends the requests with the proxy and the CA bundle of the run.
+package vcsapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/transport"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	DefaultGitHubApiEndpoint         = "https://api.github.com"
+	DefaultGitLabApiEndpoint         = "https://gitlab.com/api/v4"
+	DefaultBitbucketCloudApiEndpoint = "https://api.bitbucket.org/2.0"
+	// The version of the Azure DevOps REST API
+	AzureApiVersion = "7.0"
+	// The number of items of each page of the listings
+	PageSize = 100
+	// The largest page of pull requests that Bitbucket Cloud returns
+	BitbucketCloudPullRequestsPageSize = 50
+)
+
+// Client sends the requests of one Git provider, with the credentials of the Git client
+type Client struct {
+	provider    vcsutils.VcsProvider
+	apiEndpoint string
+	// The Azure DevOps project, empty for the other Git providers
+	project string
+	headers map[string]string
+}
+
+// Returns the client of the Git provider. An empty API endpoint is replaced by the default endpoint of the Git provider,
+// and the REST path of Bitbucket Server is appended to its endpoint if it's missing.
+func NewClient(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo) *Client {
+	apiEndpoint := strings.TrimSuffix(vcsInfo.APIEndpoint, "/")
+	client := &Client{provider: provider, project: vcsInfo.Project}
+	switch provider {
+	case vcsutils.GitHub:
+		apiEndpoint = valueOrDefault(apiEndpoint, DefaultGitHubApiEndpoint)
+		client.headers = map[string]string{"Authorization": "Bearer " + vcsInfo.Token, "Accept": "application/vnd.github+json"}
+	case vcsutils.GitLab:
+		apiEndpoint = valueOrDefault(apiEndpoint, DefaultGitLabApiEndpoint)
+		client.headers = map[string]string{"PRIVATE-TOKEN": vcsInfo.Token}
+	case vcsutils.BitbucketServer:
+		// The REST API is under the 'rest' path of the server, which the API endpoint may omit
+		if !strings.HasSuffix(apiEndpoint, "/rest") {
+			apiEndpoint += "/rest"
+		}
+		client.headers = map[string]string{"Authorization": bitbucketAuthorization(vcsInfo)}
+	case vcsutils.BitbucketCloud:
+		apiEndpoint = valueOrDefault(apiEndpoint, DefaultBitbucketCloudApiEndpoint)
+		client.headers = map[string]string{"Authorization": bitbucketAuthorization(vcsInfo)}
+	case vcsutils.AzureRepos:
+		client.headers = map[string]string{"Authorization": basicAuthHeader("", vcsInfo.Token)}
+	}
+	client.apiEndpoint = apiEndpoint
+	return client
+}
+
+func (c *Client) Provider() vcsutils.VcsProvider {
+	return c.provider
+}
+
+func (c *Client) ApiEndpoint() string {
+	return c.apiEndpoint
+}
+
+// Returns the URL of the path of the API. The path parameters are escaped and replace the verbs of the path format.
+func (c *Client) Url(pathFormat string, pathParams ...string) string {
+	escapedParams := make([]any, len(pathParams))
+	for i, param := range pathParams {
+		escapedParams[i] = url.PathEscape(param)
+	}
+	return c.apiEndpoint + fmt.Sprintf(pathFormat, escapedParams...)
+}
+
+// Returns the URL of the repository in the API, which the paths of its resources are appended to
+func (c *Client) RepositoryUrl(owner, repository string) string {
+	switch c.provider {
+	case vcsutils.GitHub:
+		return c.Url("/repos/%s/%s", owner, repository)
+	case vcsutils.GitLab:
+		// The project ID is the path of the project, including its namespace
+		return c.Url("/projects/%s", owner+"/"+repository)
+	case vcsutils.BitbucketServer:
+		return c.Url("/api/1.0/projects/%s/repos/%s", owner, repository)
+	case vcsutils.BitbucketCloud:
+		return c.Url("/repositories/%s/%s", owner, repository)
+	case vcsutils.AzureRepos:
+		return c.Url("/%s/_apis/git/repositories/%s", c.project, repository)
+	default:
+		return ""
+	}
+}
+
+// Returns the URL of the Azure DevOps project in the API
+func (c *Client) ProjectUrl() string {
+	return c.Url("/%s", c.project)
+}
+
+// Sends a GET request and decodes the JSON response into the target
+func (c *Client) Get(requestUrl string, target any) error {
+	return c.Send(http.MethodGet, requestUrl, nil, target)
+}
+
+// Sends a request with the JSON body, if provided, and decodes the JSON response into the target, if provided
+func (c *Client) Send(method, requestUrl string, body, target any) error {
+	return c.SendWithContentType(method, requestUrl, "application/json", body, target)
+}
+
+// Sends a request with the body, if provided, encoded as JSON with the given content type, and decodes the JSON response into the target, if provided
+func (c *Client) SendWithContentType(method, requestUrl, contentType string, body, target any) error {
+	client, err := transport.NewHttpClient()
+	if err != nil {
+		return err
+	}
+	headers := make(map[string]string, len(c.headers)+1)
+	for key, value := range c.headers {
+		headers[key] = value
+	}
+	var content []byte
+	if body != nil {
+		if content, err = json.Marshal(body); err != nil {
+			return err
+		}
+		headers["Content-Type"] = contentType
+	}
+	log.Debug(fmt.Sprintf("Sending HTTP %s request to: %s", method, requestUrl))
+	resp, respBody, _, err := client.Send(method, requestUrl, content, true, true, httputils.HttpClientDetails{Headers: headers}, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &ResponseError{Url: requestUrl, StatusCode: resp.StatusCode, Status: resp.Status, Body: string(respBody)}
+	}
+	if target == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, target)
+}
+
+// Returns all the items of a listing of the API, by requesting its pages with the pagination of the Git provider:
+// the page number of GitHub and GitLab, the start of the next page of Bitbucket Server, the link to the next page of Bitbucket Cloud,
+// and the skipped items of Azure DevOps. Listings of Azure DevOps that aren't paged must be requested with Get.
+func List[T any](c *Client, listUrl string, pageSize int) (items []T, err error) {
+	switch c.provider {
+	case vcsutils.BitbucketServer:
+		for start, isLastPage := 0, false; !isLastPage; {
+			var page struct {
+				Values        []T  `json:"values"`
+				IsLastPage    bool `json:"isLastPage"`
+				NextPageStart int  `json:"nextPageStart"`
+			}
+			if err = c.Get(withQuery(listUrl, "limit="+strconv.Itoa(pageSize), "start="+strconv.Itoa(start)), &page); err != nil {
+				return nil, err
+			}
+			items = append(items, page.Values...)
+			start, isLastPage = page.NextPageStart, page.IsLastPage
+		}
+	case vcsutils.BitbucketCloud:
+		for nextUrl := withQuery(listUrl, "pagelen="+strconv.Itoa(pageSize)); nextUrl != ""; {
+			var page struct {
+				Values []T    `json:"values"`
+				Next   string `json:"next"`
+			}
+			if err = c.Get(nextUrl, &page); err != nil {
+				return nil, err
+			}
+			items = append(items, page.Values...)
+			nextUrl = page.Next
+		}
+	case vcsutils.AzureRepos:
+		for skip := 0; ; skip += pageSize {
+			var page struct {
+				Value []T `json:"value"`
+			}
+			if err = c.Get(withQuery(listUrl, "$top="+strconv.Itoa(pageSize), "$skip="+strconv.Itoa(skip)), &page); err != nil {
+				return nil, err
+			}
+			items = append(items, page.Value...)
+			if len(page.Value) < pageSize {
+				return
+			}
+		}
+	default:
+		for pageNumber := 1; ; pageNumber++ {
+			var page []T
+			if err = c.Get(withQuery(listUrl, "per_page="+strconv.Itoa(pageSize), "page="+strconv.Itoa(pageNumber)), &page); err != nil {
+				return nil, err
+			}
+			items = append(items, page...)
+			if len(page) < pageSize {
+				return
+			}
+		}
+	}
+	return
+}
+
+// ResponseError is returned when the API responds with a status that isn't successful
+type ResponseError struct {
+	Url        string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s responded with status %s: %s", e.Url, e.Status, e.Body)
+}
+
+// Returns true if the API responded that the requested resource doesn't exist
+func IsNotFound(err error) bool {
+	var responseErr *ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+// Returns true if the API responded that the token isn't allowed to access the requested resource
+func IsForbidden(err error) bool {
+	var responseErr *ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden
+}
+
+// Appends the query parameters to the URL. The parameters are expected to be escaped.
+func withQuery(requestUrl string, params ...string) string {
+	separator := "?"
+	if strings.Contains(requestUrl, "?") {
+		separator = "&"
+	}
+	return requestUrl + separator + strings.Join(params, "&")
+}
+
+// Bitbucket authenticates with the username and an app password, or with a bearer token if no username is provided
+func bitbucketAuthorization(vcsInfo vcsclient.VcsInfo) string {
+	if vcsInfo.Username == "" {
+		return "Bearer " + vcsInfo.Token
+	}
+	return basicAuthHeader(vcsInfo.Username, vcsInfo.Token)
+}
+
+func basicAuthHeader(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
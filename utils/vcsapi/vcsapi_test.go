@@ -0,0 +1,155 @@
+package vcsapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryUrl(t *testing.T) {
+	testCases := []struct {
+		provider    vcsutils.VcsProvider
+		vcsInfo     vcsclient.VcsInfo
+		owner       string
+		expectedUrl string
+	}{
+		{provider: vcsutils.GitHub, owner: "jfrog", expectedUrl: "https://api.github.com/repos/jfrog/frogbot"},
+		{provider: vcsutils.GitLab, vcsInfo: vcsclient.VcsInfo{APIEndpoint: "https://gitlab.example.com/api/v4/"}, owner: "group/sub", expectedUrl: "https://gitlab.example.com/api/v4/projects/group%2Fsub%2Ffrogbot"},
+		{provider: vcsutils.BitbucketServer, vcsInfo: vcsclient.VcsInfo{APIEndpoint: "https://bitbucket.example.com"}, owner: "SEC", expectedUrl: "https://bitbucket.example.com/rest/api/1.0/projects/SEC/repos/frogbot"},
+		{provider: vcsutils.BitbucketServer, vcsInfo: vcsclient.VcsInfo{APIEndpoint: "https://bitbucket.example.com/rest"}, owner: "SEC", expectedUrl: "https://bitbucket.example.com/rest/api/1.0/projects/SEC/repos/frogbot"},
+		{provider: vcsutils.BitbucketCloud, owner: "jfrog", expectedUrl: "https://api.bitbucket.org/2.0/repositories/jfrog/frogbot"},
+		{provider: vcsutils.AzureRepos, vcsInfo: vcsclient.VcsInfo{APIEndpoint: "https://dev.azure.com/jfrog/", Project: "my project"}, owner: "jfrog", expectedUrl: "https://dev.azure.com/jfrog/my%20project/_apis/git/repositories/frogbot"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.provider.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expectedUrl, NewClient(tc.provider, tc.vcsInfo).RepositoryUrl(tc.owner, "frogbot"))
+		})
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	testCases := []struct {
+		provider        vcsutils.VcsProvider
+		username        string
+		expectedHeaders map[string]string
+	}{
+		{provider: vcsutils.GitHub, expectedHeaders: map[string]string{"Authorization": "Bearer token", "Accept": "application/vnd.github+json"}},
+		{provider: vcsutils.GitLab, expectedHeaders: map[string]string{"PRIVATE-TOKEN": "token"}},
+		{provider: vcsutils.BitbucketServer, expectedHeaders: map[string]string{"Authorization": "Bearer token"}},
+		{provider: vcsutils.BitbucketCloud, username: "frogbot", expectedHeaders: map[string]string{"Authorization": basicAuthHeader("frogbot", "token")}},
+		{provider: vcsutils.AzureRepos, expectedHeaders: map[string]string{"Authorization": basicAuthHeader("", "token")}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.provider.String(), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range tc.expectedHeaders {
+					assert.Equal(t, value, r.Header.Get(key), key)
+				}
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				_, err = w.Write(body)
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			client := NewClient(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token", Username: tc.username})
+			var response map[string]string
+			require.NoError(t, client.Send(http.MethodPost, server.URL, map[string]string{"title": "Frogbot"}, &response))
+			assert.Equal(t, map[string]string{"title": "Frogbot"}, response)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	testCases := []struct {
+		provider vcsutils.VcsProvider
+		// Returns the response to the request of a page
+		page func(r *http.Request, serverUrl string) string
+	}{
+		{
+			provider: vcsutils.GitHub,
+			page: func(r *http.Request, _ string) string {
+				assert.Equal(t, "2", r.URL.Query().Get("per_page"))
+				if r.URL.Query().Get("page") == "1" {
+					return `[{"id":1},{"id":2}]`
+				}
+				return `[{"id":3}]`
+			},
+		},
+		{
+			provider: vcsutils.BitbucketServer,
+			page: func(r *http.Request, _ string) string {
+				assert.Equal(t, "2", r.URL.Query().Get("limit"))
+				if r.URL.Query().Get("start") == "0" {
+					return `{"values":[{"id":1},{"id":2}],"isLastPage":false,"nextPageStart":2}`
+				}
+				return `{"values":[{"id":3}],"isLastPage":true}`
+			},
+		},
+		{
+			provider: vcsutils.BitbucketCloud,
+			page: func(r *http.Request, serverUrl string) string {
+				if r.URL.Query().Get("pagelen") == "2" {
+					return fmt.Sprintf(`{"values":[{"id":1},{"id":2}],"next":"%s/items?page=2"}`, serverUrl)
+				}
+				return `{"values":[{"id":3}]}`
+			},
+		},
+		{
+			provider: vcsutils.AzureRepos,
+			page: func(r *http.Request, _ string) string {
+				assert.Equal(t, "2", r.URL.Query().Get("$top"))
+				if r.URL.Query().Get("$skip") == "0" {
+					return `{"value":[{"id":1},{"id":2}]}`
+				}
+				return `{"value":[{"id":3}]}`
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.provider.String(), func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/items", r.URL.Path)
+				_, err := w.Write([]byte(tc.page(r, server.URL)))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+			client := NewClient(tc.provider, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"})
+			items, err := List[struct {
+				Id int `json:"id"`
+			}](client, server.URL+"/items", 2)
+			require.NoError(t, err)
+			assert.Len(t, items, 3)
+			assert.Equal(t, 3, items[2].Id)
+		})
+	}
+}
+
+func TestResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{"message": "Bad credentials"}))
+	}))
+	defer server.Close()
+	client := NewClient(vcsutils.GitHub, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"})
+
+	err := client.Get(server.URL+"/missing", nil)
+	assert.True(t, IsNotFound(err))
+	err = client.Get(server.URL+"/repos", nil)
+	assert.False(t, IsNotFound(err))
+	assert.ErrorContains(t, err, "responded with status 401 Unauthorized")
+	assert.ErrorContains(t, err, "Bad credentials")
+}
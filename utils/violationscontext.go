@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayclient "github.com/jfrog/jfrog-client-go/xray"
+)
+
+const (
+	watchesApiUrl  = "api/v2/watches/"
+	projectsApiUrl = "access/api/v1/projects/"
+)
+
+// Checks that the configured watches and JFrog project exist before scanning.
+// Xray returns no violations for watches or a project that don't exist, so the scan would look clean.
+// The missing ones are logged as a warning, or fail the scan if failOnMissing is set.
+// The check is skipped with a warning if it can't be performed, for example when the token isn't permitted to read the watches.
+func ValidateViolationsContext(serverDetails *config.ServerDetails, watches []string, projectKey string, failOnMissing bool) error {
+	if len(watches) == 0 && projectKey == "" {
+		return nil
+	}
+	missing, err := getMissingViolationsContext(serverDetails, watches, projectKey)
+	if err != nil {
+		log.Warn("Couldn't check that the configured watches and JFrog project exist:", err.Error())
+		return nil
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("the following don't exist in the JFrog platform, so no violations are reported for them: %s", strings.Join(missing, ", "))
+	if failOnMissing {
+		return errors.New(message)
+	}
+	log.Warn(message)
+	return nil
+}
+
+// Checks the watches and the JFrog project of the repository, and of each of its projects that is scanned with its own ones
+func ValidateRepositoryViolationsContext(repository *Repository) error {
+	if err := ValidateViolationsContext(&repository.Server, repository.Watches, repository.JFrogProjectKey, repository.FailOnMissingWatchesOrProject); err != nil {
+		return err
+	}
+	for i := range repository.Projects {
+		if !repository.Projects[i].HasViolationsContext() {
+			continue
+		}
+		watches, jfrogProjectKey := repository.Projects[i].GetViolationsContext(repository.Watches, repository.JFrogProjectKey)
+		if err := ValidateViolationsContext(&repository.Server, watches, jfrogProjectKey, repository.FailOnMissingWatchesOrProject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getMissingViolationsContext(serverDetails *config.ServerDetails, watches []string, projectKey string) (missing []string, err error) {
+	xrayManager, err := xray.CreateXrayServiceManager(serverDetails)
+	if err != nil {
+		return
+	}
+	if projectKey != "" {
+		if serverDetails.Url == "" {
+			// The projects are managed by the Access service, which is reachable only by the platform URL
+			log.Debug("The JFrog platform URL isn't set, so the existence of the JFrog project isn't checked")
+		} else {
+			var exists bool
+			if exists, err = isResourceExists(xrayManager, clientutils.AddTrailingSlashIfNeeded(serverDetails.Url)+projectsApiUrl+url.PathEscape(projectKey)); err != nil {
+				return
+			}
+			if !exists {
+				// The watches of a missing project can't be found either
+				return []string{fmt.Sprintf("JFrog project '%s'", projectKey)}, nil
+			}
+		}
+	}
+	xrayUrl := clientutils.AddTrailingSlashIfNeeded(xrayManager.Config().GetServiceDetails().GetUrl())
+	for _, watch := range watches {
+		watchUrl := xrayUrl + watchesApiUrl + url.PathEscape(watch)
+		if projectKey != "" {
+			watchUrl += "?projectKey=" + url.QueryEscape(projectKey)
+		}
+		var exists bool
+		if exists, err = isResourceExists(xrayManager, watchUrl); err != nil {
+			return
+		}
+		if !exists {
+			missing = append(missing, fmt.Sprintf("watch '%s'", watch))
+		}
+	}
+	return
+}
+
+func isResourceExists(xrayManager *xrayclient.XrayServicesManager, resourceUrl string) (bool, error) {
+	httpClientDetails := xrayManager.Config().GetServiceDetails().CreateHttpClientDetails()
+	resp, body, _, err := xrayManager.Client().SendGet(resourceUrl, true, &httpClientDetails)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateViolationsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xray/api/v2/watches/watch-1", "/access/api/v1/projects/proj":
+			w.WriteHeader(http.StatusOK)
+		case "/xray/api/v2/watches/forbidden-watch":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverDetails := &config.ServerDetails{Url: server.URL + "/", XrayUrl: server.URL + "/xray/", AccessToken: "token"}
+
+	testCases := []struct {
+		name            string
+		watches         []string
+		projectKey      string
+		failOnMissing   bool
+		expectedMissing []string
+		expectedError   string
+		// The existence of the watches and project can't be checked
+		expectedCheckError bool
+	}{
+		{name: "No watches and project"},
+		{name: "Existing watch and project", watches: []string{"watch-1"}, projectKey: "proj"},
+		{name: "Missing watch", watches: []string{"watch-1", "watch-2"}, expectedMissing: []string{"watch 'watch-2'"}},
+		{name: "Missing project", watches: []string{"watch-1"}, projectKey: "other-proj", expectedMissing: []string{"JFrog project 'other-proj'"}},
+		{
+			name:            "Fail on missing watch",
+			watches:         []string{"watch-2"},
+			failOnMissing:   true,
+			expectedMissing: []string{"watch 'watch-2'"},
+			expectedError:   "the following don't exist in the JFrog platform, so no violations are reported for them: watch 'watch-2'",
+		},
+		{name: "Watches can't be read", watches: []string{"forbidden-watch"}, failOnMissing: true, expectedCheckError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateViolationsContext(serverDetails, tc.watches, tc.projectKey, tc.failOnMissing)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			if len(tc.watches) == 0 && tc.projectKey == "" {
+				return
+			}
+			missing, err := getMissingViolationsContext(serverDetails, tc.watches, tc.projectKey)
+			if tc.expectedCheckError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedMissing, missing)
+		})
+	}
+}
+
+func TestValidateRepositoryViolationsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xray/api/v2/watches/watch-1", "/xray/api/v2/watches/watch-2", "/access/api/v1/projects/proj":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	repository := &Repository{Server: config.ServerDetails{Url: server.URL + "/", XrayUrl: server.URL + "/xray/", AccessToken: "token"}}
+	repository.Watches = []string{"watch-1"}
+	repository.FailOnMissingWatchesOrProject = true
+	repository.Projects = []Project{{}, {Watches: []string{"watch-2"}, JFrogProjectKey: "proj"}}
+	assert.NoError(t, ValidateRepositoryViolationsContext(repository))
+
+	// The JFrog project of a project is checked, even when the repository has none
+	repository.Projects = append(repository.Projects, Project{JFrogProjectKey: "other-proj"})
+	assert.EqualError(t, ValidateRepositoryViolationsContext(repository), "the following don't exist in the JFrog platform, so no violations are reported for them: JFrog project 'other-proj'")
+}
@@ -0,0 +1,139 @@
+package workitems
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+)
+
+const (
+	// The number of work items whose titles are requested together from Azure Boards
+	azureWorkItemsPage   = 200
+	jsonPatchContentType = "application/json-patch+json"
+)
+
+type gitHubTracker struct {
+	client    *vcsapi.Client
+	issuesUrl string
+}
+
+func (gt *gitHubTracker) ListOpen() (map[string]string, error) {
+	issues, err := vcsapi.List[struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}](gt.client, fmt.Sprintf("%s?state=open&labels=%s", gt.issuesUrl, FrogbotLabel), vcsapi.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	openIssues := make(map[string]string)
+	for _, issue := range issues {
+		openIssues[issue.Title] = strconv.Itoa(issue.Number)
+	}
+	return openIssues, nil
+}
+
+func (gt *gitHubTracker) Create(content Content) error {
+	issue := map[string]any{"title": content.Title(), "body": content.MarkdownDescription(), "labels": []string{FrogbotLabel}}
+	return gt.client.Send(http.MethodPost, gt.issuesUrl, issue, nil)
+}
+
+func (gt *gitHubTracker) Update(id string, content Content) error {
+	issue := map[string]any{"body": content.MarkdownDescription()}
+	return gt.client.Send(http.MethodPatch, gt.issuesUrl+"/"+id, issue, nil)
+}
+
+type gitLabTracker struct {
+	client    *vcsapi.Client
+	issuesUrl string
+}
+
+func (gl *gitLabTracker) ListOpen() (map[string]string, error) {
+	issues, err := vcsapi.List[struct {
+		// The ID of the issue in the project
+		Iid   int    `json:"iid"`
+		Title string `json:"title"`
+	}](gl.client, fmt.Sprintf("%s?state=opened&labels=%s", gl.issuesUrl, FrogbotLabel), vcsapi.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	openIssues := make(map[string]string)
+	for _, issue := range issues {
+		openIssues[issue.Title] = strconv.Itoa(issue.Iid)
+	}
+	return openIssues, nil
+}
+
+func (gl *gitLabTracker) Create(content Content) error {
+	issue := map[string]any{"title": content.Title(), "description": content.MarkdownDescription(), "labels": FrogbotLabel}
+	return gl.client.Send(http.MethodPost, gl.issuesUrl, issue, nil)
+}
+
+func (gl *gitLabTracker) Update(id string, content Content) error {
+	issue := map[string]any{"description": content.MarkdownDescription()}
+	return gl.client.Send(http.MethodPut, gl.issuesUrl+"/"+id, issue, nil)
+}
+
+type azureBoardsTracker struct {
+	client *vcsapi.Client
+	// The URL of the work item tracking API of the project
+	workItemsUrl string
+	workItemType string
+}
+
+func (at *azureBoardsTracker) ListOpen() (map[string]string, error) {
+	query := map[string]string{
+		"query": fmt.Sprintf("SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.Tags] CONTAINS '%s' AND [System.State] NOT IN ('Closed', 'Done', 'Removed')", FrogbotLabel),
+	}
+	var queryResult struct {
+		WorkItems []struct {
+			Id int `json:"id"`
+		} `json:"workItems"`
+	}
+	if err := at.client.Send(http.MethodPost, fmt.Sprintf("%s/wiql?api-version=%s", at.workItemsUrl, vcsapi.AzureApiVersion), query, &queryResult); err != nil {
+		return nil, err
+	}
+	openWorkItems := make(map[string]string)
+	// The query returns the IDs only, so the titles are requested in batches
+	for start := 0; start < len(queryResult.WorkItems); start += azureWorkItemsPage {
+		var ids []string
+		for _, workItem := range queryResult.WorkItems[start:min(start+azureWorkItemsPage, len(queryResult.WorkItems))] {
+			ids = append(ids, strconv.Itoa(workItem.Id))
+		}
+		var workItems struct {
+			Value []struct {
+				Id     int `json:"id"`
+				Fields struct {
+					Title string `json:"System.Title"`
+				} `json:"fields"`
+			} `json:"value"`
+		}
+		if err := at.client.Get(fmt.Sprintf("%s/workitems?ids=%s&fields=System.Title&api-version=%s", at.workItemsUrl, strings.Join(ids, ","), vcsapi.AzureApiVersion), &workItems); err != nil {
+			return nil, err
+		}
+		for _, workItem := range workItems.Value {
+			openWorkItems[workItem.Fields.Title] = strconv.Itoa(workItem.Id)
+		}
+	}
+	return openWorkItems, nil
+}
+
+// Work items are created and updated by JSON patch documents of their fields
+func (at *azureBoardsTracker) Create(content Content) error {
+	fields := []map[string]string{
+		{"op": "add", "path": "/fields/System.Title", "value": content.Title()},
+		{"op": "add", "path": "/fields/System.Description", "value": content.HtmlDescription()},
+		{"op": "add", "path": "/fields/System.Tags", "value": FrogbotLabel},
+	}
+	createUrl := fmt.Sprintf("%s/workitems/$%s?api-version=%s", at.workItemsUrl, url.PathEscape(at.workItemType), vcsapi.AzureApiVersion)
+	return at.client.SendWithContentType(http.MethodPost, createUrl, jsonPatchContentType, fields, nil)
+}
+
+func (at *azureBoardsTracker) Update(id string, content Content) error {
+	fields := []map[string]string{{"op": "replace", "path": "/fields/System.Description", "value": content.HtmlDescription()}}
+	updateUrl := fmt.Sprintf("%s/workitems/%s?api-version=%s", at.workItemsUrl, id, vcsapi.AzureApiVersion)
+	return at.client.SendWithContentType(http.MethodPatch, updateUrl, jsonPatchContentType, fields, nil)
+}
@@ -0,0 +1,135 @@
+package workitems
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils/vcsapi"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The label of the issues, or the tag of the work items, that Frogbot creates
+	FrogbotLabel             = "frogbot"
+	DefaultAzureWorkItemType = "Issue"
+	titlePrefix              = "[🐸 Frogbot]"
+)
+
+// WorkItem describes a vulnerability that Frogbot can't fix, since no fixed version of the vulnerable component exists
+type WorkItem struct {
+	// The CVE ID, or the Xray issue ID of vulnerabilities without a CVE
+	IssueId          string
+	Component        string
+	ComponentVersion string
+	Severity         string
+	Summary          string
+	// The repository and the branch the vulnerability was found in
+	Repository string
+	Branch     string
+}
+
+// Returns the title of the work item, which identifies it by the CVE and the component, to avoid filing the same vulnerability twice
+func (wi *WorkItem) Title() string {
+	return fmt.Sprintf("%s %s in %s", titlePrefix, wi.IssueId, wi.Component)
+}
+
+func (wi *WorkItem) descriptionLines() []string {
+	lines := []string{
+		fmt.Sprintf("Frogbot found the %s vulnerability in %s %s, in the %s branch of %s.", wi.IssueId, wi.Component, wi.ComponentVersion, wi.Branch, wi.Repository),
+		"No fixed version of the component is available yet, so Frogbot can't open a pull request to fix it.",
+		fmt.Sprintf("Severity: %s", wi.Severity),
+	}
+	if wi.Summary != "" {
+		lines = append(lines, fmt.Sprintf("Summary: %s", wi.Summary))
+	}
+	return lines
+}
+
+func (wi *WorkItem) MarkdownDescription() string {
+	return strings.Join(wi.descriptionLines(), "\n\n")
+}
+
+func (wi *WorkItem) HtmlDescription() string {
+	var builder strings.Builder
+	for _, line := range wi.descriptionLines() {
+		builder.WriteString("<p>" + escapeHtml(line) + "</p>")
+	}
+	return builder.String()
+}
+
+// Content is the title and the description of a work item. The description is rendered according to the issue tracker.
+type Content interface {
+	Title() string
+	MarkdownDescription() string
+	HtmlDescription() string
+}
+
+// Tracker files work items in the issue tracker of the Git provider
+type Tracker interface {
+	// Returns the IDs of the open work items that Frogbot created, by their titles
+	ListOpen() (map[string]string, error)
+	Create(content Content) error
+	// Replaces the description of the work item with the given ID
+	Update(id string, content Content) error
+}
+
+// Returns the tracker of the Git provider: GitHub and GitLab issues, or Azure Boards work items for Azure Repos
+func NewTracker(provider vcsutils.VcsProvider, vcsInfo vcsclient.VcsInfo, repoOwner, repoName, azureWorkItemType string) (Tracker, error) {
+	client := vcsapi.NewClient(provider, vcsInfo)
+	switch provider {
+	case vcsutils.GitHub:
+		return &gitHubTracker{client: client, issuesUrl: client.RepositoryUrl(repoOwner, repoName) + "/issues"}, nil
+	case vcsutils.GitLab:
+		return &gitLabTracker{client: client, issuesUrl: client.RepositoryUrl(repoOwner, repoName) + "/issues"}, nil
+	case vcsutils.AzureRepos:
+		if azureWorkItemType == "" {
+			azureWorkItemType = DefaultAzureWorkItemType
+		}
+		return &azureBoardsTracker{client: client, workItemsUrl: client.ProjectUrl() + "/_apis/wit", workItemType: azureWorkItemType}, nil
+	default:
+		return nil, fmt.Errorf("filing work items isn't supported for %s", provider.String())
+	}
+}
+
+func escapeHtml(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(text)
+}
+
+// Files a work item for each vulnerability that doesn't have an open work item yet.
+// Returns the number of the work items that were created.
+func FileWorkItems(tracker Tracker, workItems []WorkItem) (created int, err error) {
+	openWorkItems, err := tracker.ListOpen()
+	if err != nil {
+		return
+	}
+	for i := range workItems {
+		title := workItems[i].Title()
+		if _, exists := openWorkItems[title]; exists {
+			log.Debug("A work item already exists for:", title)
+			continue
+		}
+		if err = tracker.Create(&workItems[i]); err != nil {
+			return
+		}
+		log.Info("Created a work item for:", title)
+		openWorkItems[title] = ""
+		created++
+	}
+	return
+}
+
+// Updates the open work item with the title of the content, or creates it if no such work item is open
+func CreateOrUpdate(tracker Tracker, content Content) error {
+	openWorkItems, err := tracker.ListOpen()
+	if err != nil {
+		return err
+	}
+	if id, exists := openWorkItems[content.Title()]; exists {
+		log.Debug(fmt.Sprintf("Updating the '%s' work item", content.Title()))
+		return tracker.Update(id, content)
+	}
+	log.Debug(fmt.Sprintf("Creating the '%s' work item", content.Title()))
+	return tracker.Create(content)
+}
@@ -0,0 +1,233 @@
+package workitems
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWorkItem = WorkItem{
+	IssueId:          "CVE-2023-1234",
+	Component:        "minimist",
+	ComponentVersion: "1.2.5",
+	Severity:         "High",
+	Summary:          "Prototype pollution <in> minimist",
+	Repository:       "jfrog/frogbot",
+	Branch:           "main",
+}
+
+func TestWorkItemContent(t *testing.T) {
+	assert.Equal(t, "[🐸 Frogbot] CVE-2023-1234 in minimist", testWorkItem.Title())
+	assert.Equal(t, "Frogbot found the CVE-2023-1234 vulnerability in minimist 1.2.5, in the main branch of jfrog/frogbot.\n\n"+
+		"No fixed version of the component is available yet, so Frogbot can't open a pull request to fix it.\n\n"+
+		"Severity: High\n\n"+
+		"Summary: Prototype pollution <in> minimist", testWorkItem.MarkdownDescription())
+	assert.Contains(t, testWorkItem.HtmlDescription(), "<p>Summary: Prototype pollution &lt;in&gt; minimist</p>")
+}
+
+func TestNewTracker(t *testing.T) {
+	tracker, err := NewTracker(vcsutils.GitHub, vcsclient.VcsInfo{Token: "token"}, "jfrog", "frogbot", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/repos/jfrog/frogbot/issues", tracker.(*gitHubTracker).issuesUrl)
+
+	tracker, err = NewTracker(vcsutils.GitLab, vcsclient.VcsInfo{APIEndpoint: "https://gitlab.example.com/api/v4/", Token: "token"}, "group/sub", "frogbot", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.example.com/api/v4/projects/group%2Fsub%2Ffrogbot/issues", tracker.(*gitLabTracker).issuesUrl)
+
+	tracker, err = NewTracker(vcsutils.AzureRepos, vcsclient.VcsInfo{APIEndpoint: "https://dev.azure.com/jfrog", Token: "token", Project: "security"}, "jfrog", "frogbot", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://dev.azure.com/jfrog/security/_apis/wit", tracker.(*azureBoardsTracker).workItemsUrl)
+	assert.Equal(t, DefaultAzureWorkItemType, tracker.(*azureBoardsTracker).workItemType)
+
+	_, err = NewTracker(vcsutils.BitbucketServer, vcsclient.VcsInfo{}, "jfrog", "frogbot", "")
+	assert.Error(t, err)
+}
+
+func TestGitHubTracker(t *testing.T) {
+	var createdIssue, updatedIssue map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/repos/jfrog/frogbot/issues", r.URL.Path)
+			assert.Equal(t, "open", r.URL.Query().Get("state"))
+			assert.Equal(t, FrogbotLabel, r.URL.Query().Get("labels"))
+			_, err := w.Write([]byte(`[{"number":7,"title":"[🐸 Frogbot] CVE-2021-1111 in lodash"}]`))
+			assert.NoError(t, err)
+		case http.MethodPost:
+			assert.Equal(t, "/repos/jfrog/frogbot/issues", r.URL.Path)
+			readJsonBody(t, r, &createdIssue)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPatch:
+			assert.Equal(t, "/repos/jfrog/frogbot/issues/7", r.URL.Path)
+			readJsonBody(t, r, &updatedIssue)
+		}
+	}))
+	defer server.Close()
+	tracker, err := NewTracker(vcsutils.GitHub, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "jfrog", "frogbot", "")
+	require.NoError(t, err)
+
+	openIssues, err := tracker.ListOpen()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"[🐸 Frogbot] CVE-2021-1111 in lodash": "7"}, openIssues)
+	require.NoError(t, tracker.Create(&testWorkItem))
+	assert.Equal(t, testWorkItem.Title(), createdIssue["title"])
+	assert.Equal(t, testWorkItem.MarkdownDescription(), createdIssue["body"])
+	assert.Equal(t, []any{FrogbotLabel}, createdIssue["labels"])
+	require.NoError(t, tracker.Update("7", &testWorkItem))
+	assert.Equal(t, map[string]any{"body": testWorkItem.MarkdownDescription()}, updatedIssue)
+}
+
+func TestGitLabTracker(t *testing.T) {
+	var createdIssue, updatedIssue map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/projects/group%2Ffrogbot/issues", r.URL.EscapedPath())
+			assert.Equal(t, "opened", r.URL.Query().Get("state"))
+			_, err := w.Write([]byte(`[{"iid":3,"title":"title"}]`))
+			assert.NoError(t, err)
+		case http.MethodPost:
+			assert.Equal(t, "/projects/group%2Ffrogbot/issues", r.URL.EscapedPath())
+			readJsonBody(t, r, &createdIssue)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPut:
+			assert.Equal(t, "/projects/group%2Ffrogbot/issues/3", r.URL.EscapedPath())
+			readJsonBody(t, r, &updatedIssue)
+		}
+	}))
+	defer server.Close()
+	tracker, err := NewTracker(vcsutils.GitLab, vcsclient.VcsInfo{APIEndpoint: server.URL, Token: "token"}, "group", "frogbot", "")
+	require.NoError(t, err)
+
+	openIssues, err := tracker.ListOpen()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"title": "3"}, openIssues)
+	require.NoError(t, tracker.Create(&testWorkItem))
+	assert.Equal(t, testWorkItem.Title(), createdIssue["title"])
+	assert.Equal(t, testWorkItem.MarkdownDescription(), createdIssue["description"])
+	assert.Equal(t, FrogbotLabel, createdIssue["labels"])
+	require.NoError(t, tracker.Update("3", &testWorkItem))
+	assert.Equal(t, map[string]any{"description": testWorkItem.MarkdownDescription()}, updatedIssue)
+}
+
+func TestAzureBoardsTracker(t *testing.T) {
+	var createdFields, updatedFields []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Empty(t, user)
+		assert.Equal(t, "token", password)
+		var response string
+		switch r.URL.Path {
+		case "/jfrog/my project/_apis/wit/wiql":
+			assert.Equal(t, http.MethodPost, r.Method)
+			response = `{"workItems":[{"id":1},{"id":2}]}`
+		case "/jfrog/my project/_apis/wit/workitems":
+			assert.Equal(t, "1,2", r.URL.Query().Get("ids"))
+			response = `{"value":[{"id":1,"fields":{"System.Title":"title 1"}},{"id":2,"fields":{"System.Title":"title 2"}}]}`
+		case "/jfrog/my project/_apis/wit/workitems/$Bug":
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json-patch+json", r.Header.Get("Content-Type"))
+			readJsonBody(t, r, &createdFields)
+			response = `{"id":3}`
+		case "/jfrog/my project/_apis/wit/workitems/2":
+			assert.Equal(t, http.MethodPatch, r.Method)
+			readJsonBody(t, r, &updatedFields)
+			response = `{"id":2}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.Path)
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	tracker, err := NewTracker(vcsutils.AzureRepos, vcsclient.VcsInfo{APIEndpoint: server.URL + "/jfrog", Token: "token", Project: "my project"}, "jfrog", "frogbot", "Bug")
+	require.NoError(t, err)
+
+	openWorkItems, err := tracker.ListOpen()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"title 1": "1", "title 2": "2"}, openWorkItems)
+	require.NoError(t, tracker.Create(&testWorkItem))
+	assert.Equal(t, []map[string]string{
+		{"op": "add", "path": "/fields/System.Title", "value": testWorkItem.Title()},
+		{"op": "add", "path": "/fields/System.Description", "value": testWorkItem.HtmlDescription()},
+		{"op": "add", "path": "/fields/System.Tags", "value": FrogbotLabel},
+	}, createdFields)
+	require.NoError(t, tracker.Update("2", &testWorkItem))
+	assert.Equal(t, []map[string]string{{"op": "replace", "path": "/fields/System.Description", "value": testWorkItem.HtmlDescription()}}, updatedFields)
+}
+
+type trackerMock struct {
+	openWorkItems map[string]string
+	created       []string
+	updated       map[string]string
+	listErr       error
+}
+
+func (tm *trackerMock) ListOpen() (map[string]string, error) {
+	openWorkItems := make(map[string]string)
+	for title, id := range tm.openWorkItems {
+		openWorkItems[title] = id
+	}
+	return openWorkItems, tm.listErr
+}
+
+func (tm *trackerMock) Create(content Content) error {
+	tm.created = append(tm.created, content.Title())
+	return nil
+}
+
+func (tm *trackerMock) Update(id string, content Content) error {
+	if tm.updated == nil {
+		tm.updated = make(map[string]string)
+	}
+	tm.updated[id] = content.Title()
+	return nil
+}
+
+func TestFileWorkItems(t *testing.T) {
+	otherWorkItem := testWorkItem
+	otherWorkItem.IssueId = "CVE-2023-5678"
+	otherBranchWorkItem := testWorkItem
+	otherBranchWorkItem.Branch = "dev"
+
+	// Work items that are open, or that were already filed during the run, aren't filed again
+	tracker := &trackerMock{openWorkItems: map[string]string{otherWorkItem.Title(): "1"}}
+	created, err := FileWorkItems(tracker, []WorkItem{testWorkItem, otherWorkItem, otherBranchWorkItem})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, []string{testWorkItem.Title()}, tracker.created)
+
+	tracker = &trackerMock{listErr: errors.New("unauthorized")}
+	_, err = FileWorkItems(tracker, []WorkItem{testWorkItem})
+	assert.Error(t, err)
+	assert.Empty(t, tracker.created)
+}
+
+func TestCreateOrUpdate(t *testing.T) {
+	tracker := &trackerMock{}
+	require.NoError(t, CreateOrUpdate(tracker, &testWorkItem))
+	assert.Equal(t, []string{testWorkItem.Title()}, tracker.created)
+	assert.Empty(t, tracker.updated)
+
+	tracker = &trackerMock{openWorkItems: map[string]string{testWorkItem.Title(): "5"}}
+	require.NoError(t, CreateOrUpdate(tracker, &testWorkItem))
+	assert.Empty(t, tracker.created)
+	assert.Equal(t, map[string]string{"5": testWorkItem.Title()}, tracker.updated)
+}
+
+func readJsonBody(t *testing.T, r *http.Request, target any) {
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(body, target))
+}
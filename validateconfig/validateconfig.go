@@ -0,0 +1,193 @@
+package validateconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v2"
+)
+
+const TargetCommandFlag = "command"
+
+// The commands whose configuration can be validated
+var supportedCommands = []string{utils.ScanPullRequest, utils.ScanAllPullRequests, utils.ScanRepository, utils.ScanMultipleRepositories, utils.FixCampaign}
+
+// ValidateConfigCmd loads the configuration of a Frogbot command, from the frogbot-config.yml file and the environment variables,
+// checks the connection to the JFrog platform and to the Git provider, and prints a report of what the command would do, without scanning.
+type ValidateConfigCmd struct {
+	// The Frogbot command to validate its configuration
+	TargetCommand string
+	// The writer the report is printed to, the standard output by default
+	output io.Writer
+}
+
+func (vc *ValidateConfigCmd) Run() (err error) {
+	if !slices.Contains(supportedCommands, vc.TargetCommand) {
+		return fmt.Errorf("unsupported command '%s'. The supported commands are: %s", vc.TargetCommand, strings.Join(supportedCommands, ", "))
+	}
+	log.Info(fmt.Sprintf("Validating the Frogbot configuration of the %q command", vc.TargetCommand))
+	// Loading the configuration runs all the validations, and fetches the versions of the JFrog platform services
+	frogbotDetails, err := utils.GetFrogbotDetails(vc.TargetCommand)
+	if err != nil {
+		return fmt.Errorf("the Frogbot configuration is invalid: %s", err.Error())
+	}
+	report := vc.buildReport(frogbotDetails)
+	if err = vc.printReport(report); err != nil {
+		return
+	}
+	if report.GitProvider.ConnectionError != "" {
+		return fmt.Errorf("failed to connect to the Git provider: %s", report.GitProvider.ConnectionError)
+	}
+	log.Info("The Frogbot configuration is valid")
+	return
+}
+
+type configReport struct {
+	Command       string             `yaml:"command"`
+	JFrogPlatform platformReport     `yaml:"jfrogPlatform"`
+	GitProvider   gitProviderReport  `yaml:"gitProvider"`
+	Repositories  []repositoryReport `yaml:"repositories"`
+}
+
+type platformReport struct {
+	Url         string `yaml:"url"`
+	XrayVersion string `yaml:"xrayVersion"`
+	XscVersion  string `yaml:"xscVersion,omitempty"`
+}
+
+type gitProviderReport struct {
+	Provider        string `yaml:"provider"`
+	ApiEndpoint     string `yaml:"apiEndpoint,omitempty"`
+	Connected       bool   `yaml:"connected"`
+	ConnectionError string `yaml:"connectionError,omitempty"`
+}
+
+type repositoryReport struct {
+	Name string `yaml:"name"`
+	// Set only when the repository is scanned by a different JFrog platform instance than the default one
+	JFrogPlatform          *platformReport `yaml:"jfrogPlatform,omitempty"`
+	Branches               []string        `yaml:"branches,omitempty"`
+	PullRequestId          int64           `yaml:"pullRequestId,omitempty"`
+	Watches                []string        `yaml:"watches,omitempty"`
+	JFrogProjectKey        string          `yaml:"jfrogProjectKey,omitempty"`
+	IncludeVulnerabilities bool            `yaml:"includeVulnerabilities"`
+	MinSeverity            string          `yaml:"minSeverity,omitempty"`
+	FixableOnly            bool            `yaml:"fixableOnly"`
+	FailOnSecurityIssues   bool            `yaml:"failOnSecurityIssues"`
+	FixPullRequests        string          `yaml:"fixPullRequests,omitempty"`
+	Campaign               string          `yaml:"campaign,omitempty"`
+	TargetCves             []string        `yaml:"targetCves,omitempty"`
+	TargetCvesFixOnly      bool            `yaml:"targetCvesFixOnly,omitempty"`
+	Projects               []projectReport `yaml:"projects"`
+}
+
+type projectReport struct {
+	WorkingDirs    []string `yaml:"workingDirs"`
+	InstallCommand string   `yaml:"installCommand,omitempty"`
+	DepsRepo       string   `yaml:"repository,omitempty"`
+	PathExclusions []string `yaml:"pathExclusions,omitempty"`
+	RecursiveScan  bool     `yaml:"recursiveScan,omitempty"`
+	// Set only when the project is scanned with its own watches or JFrog project
+	Watches         []string `yaml:"watches,omitempty"`
+	JFrogProjectKey string   `yaml:"jfrogProjectKey,omitempty"`
+}
+
+func (vc *ValidateConfigCmd) buildReport(frogbotDetails *utils.FrogbotDetails) *configReport {
+	report := &configReport{
+		Command:       vc.TargetCommand,
+		JFrogPlatform: platformReport{Url: frogbotDetails.ServerDetails.Url, XrayVersion: frogbotDetails.XrayVersion, XscVersion: frogbotDetails.XscVersion},
+		GitProvider:   getGitProviderReport(frogbotDetails),
+	}
+	for _, repository := range frogbotDetails.Repositories {
+		report.Repositories = append(report.Repositories, vc.getRepositoryReport(&repository, frogbotDetails.ServerDetails.Url))
+	}
+	return report
+}
+
+func getGitProviderReport(frogbotDetails *utils.FrogbotDetails) (report gitProviderReport) {
+	if len(frogbotDetails.Repositories) > 0 {
+		report.Provider = frogbotDetails.Repositories[0].GitProvider.String()
+		report.ApiEndpoint = frogbotDetails.Repositories[0].APIEndpoint
+	}
+	if err := frogbotDetails.GitClient.TestConnection(context.Background()); err != nil {
+		report.ConnectionError = err.Error()
+		return
+	}
+	report.Connected = true
+	return
+}
+
+func (vc *ValidateConfigCmd) getRepositoryReport(repository *utils.Repository, defaultPlatformUrl string) repositoryReport {
+	report := repositoryReport{
+		Name:                   repository.RepoName,
+		Watches:                repository.Watches,
+		JFrogProjectKey:        repository.JFrogProjectKey,
+		IncludeVulnerabilities: repository.IncludeVulnerabilities,
+		MinSeverity:            repository.MinSeverity,
+		FixableOnly:            repository.FixableOnly,
+		FailOnSecurityIssues:   repository.FailOnSecurityIssues != nil && *repository.FailOnSecurityIssues,
+	}
+	if repository.RepoOwner != "" {
+		report.Name = repository.RepoOwner + "/" + repository.RepoName
+	}
+	if repository.Server.Url != defaultPlatformUrl {
+		report.JFrogPlatform = &platformReport{Url: repository.Server.Url, XrayVersion: repository.XrayVersion, XscVersion: repository.XscVersion}
+	}
+	switch vc.TargetCommand {
+	case utils.ScanPullRequest:
+		report.PullRequestId = repository.PullRequestDetails.ID
+	case utils.ScanRepository, utils.ScanMultipleRepositories, utils.FixCampaign:
+		report.Branches = repository.Branches
+		report.FixPullRequests = getFixPullRequestsMode(repository)
+		report.TargetCves = repository.TargetCves
+		report.TargetCvesFixOnly = repository.TargetCvesFixOnly
+		if repository.Campaign != nil {
+			report.Campaign = repository.Campaign.Id
+		}
+	}
+	for _, project := range repository.Projects {
+		report.Projects = append(report.Projects, projectReport{
+			WorkingDirs:     project.WorkingDirs,
+			InstallCommand:  strings.TrimSpace(strings.Join(append([]string{project.InstallCommandName}, project.InstallCommandArgs...), " ")),
+			DepsRepo:        project.DepsRepo,
+			PathExclusions:  project.PathExclusions,
+			RecursiveScan:   project.IsRecursiveScan,
+			Watches:         project.Watches,
+			JFrogProjectKey: project.JFrogProjectKey,
+		})
+	}
+	return report
+}
+
+func getFixPullRequestsMode(repository *utils.Repository) string {
+	switch {
+	case repository.DetectionOnly:
+		return "disabled"
+	case repository.SeparateFixesMinSeverity != "":
+		return fmt.Sprintf("separate for %s severity and higher, aggregated for the rest", repository.SeparateFixesMinSeverity)
+	case repository.AggregateFixes:
+		return "aggregated"
+	default:
+		return "separate"
+	}
+}
+
+func (vc *ValidateConfigCmd) printReport(report *configReport) error {
+	content, err := yaml.Marshal(report)
+	if err != nil {
+		return errors.New("failed to generate the configuration report: " + err.Error())
+	}
+	output := vc.output
+	if output == nil {
+		output = os.Stdout
+	}
+	_, err = fmt.Fprint(output, string(content))
+	return err
+}
@@ -0,0 +1,104 @@
+package validateconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/v2/testdata"
+	"github.com/jfrog/frogbot/v2/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigUnsupportedCommand(t *testing.T) {
+	err := (&ValidateConfigCmd{TargetCommand: "scan-everything"}).Run()
+	assert.ErrorContains(t, err, "unsupported command 'scan-everything'")
+}
+
+func TestBuildReport(t *testing.T) {
+	failOnSecurityIssues := true
+	repository := utils.Repository{
+		Server: config.ServerDetails{Url: "https://other.jfrog.io/"},
+		Params: utils.Params{
+			Git: utils.Git{
+				GitProvider:    vcsutils.GitHub,
+				VcsInfo:        vcsclient.VcsInfo{APIEndpoint: "https://api.github.com"},
+				RepoOwner:      "jfrog",
+				RepoName:       "frogbot",
+				Branches:       []string{"master", "dev"},
+				AggregateFixes: true,
+			},
+			Scan: utils.Scan{
+				FailOnSecurityIssues: &failOnSecurityIssues,
+				MinSeverity:          "High",
+				Projects: []utils.Project{
+					{WorkingDirs: []string{"frontend"}, InstallCommandName: "npm", InstallCommandArgs: []string{"ci"}, DepsRepo: "npm-remote"},
+				},
+			},
+			JFrogPlatform: utils.JFrogPlatform{XrayVersion: "3.80.0", Watches: []string{"watch-1"}, JFrogProjectKey: "proj"},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		targetCommand     string
+		connectionErr     error
+		expectedRepoCheck func(t *testing.T, report repositoryReport)
+	}{
+		{
+			name:          "Scan repository",
+			targetCommand: utils.ScanRepository,
+			expectedRepoCheck: func(t *testing.T, report repositoryReport) {
+				assert.Equal(t, []string{"master", "dev"}, report.Branches)
+				assert.Equal(t, "aggregated", report.FixPullRequests)
+			},
+		},
+		{
+			name:          "Scan pull request",
+			targetCommand: utils.ScanPullRequest,
+			connectionErr: errors.New("bad credentials"),
+			expectedRepoCheck: func(t *testing.T, report repositoryReport) {
+				assert.Empty(t, report.Branches)
+				assert.Empty(t, report.FixPullRequests)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockVcsClient := testdata.NewMockVcsClient(gomock.NewController(t))
+			mockVcsClient.EXPECT().TestConnection(context.Background()).Return(tc.connectionErr)
+			frogbotDetails := &utils.FrogbotDetails{
+				XrayVersion:   "3.90.0",
+				Repositories:  utils.RepoAggregator{repository},
+				ServerDetails: &config.ServerDetails{Url: "https://default.jfrog.io/"},
+				GitClient:     mockVcsClient,
+			}
+			cmd := &ValidateConfigCmd{TargetCommand: tc.targetCommand}
+			report := cmd.buildReport(frogbotDetails)
+
+			assert.Equal(t, platformReport{Url: "https://default.jfrog.io/", XrayVersion: "3.90.0"}, report.JFrogPlatform)
+			assert.Equal(t, "GitHub", report.GitProvider.Provider)
+			assert.Equal(t, tc.connectionErr == nil, report.GitProvider.Connected)
+			require.Len(t, report.Repositories, 1)
+			repoReport := report.Repositories[0]
+			assert.Equal(t, "jfrog/frogbot", repoReport.Name)
+			assert.Equal(t, &platformReport{Url: "https://other.jfrog.io/", XrayVersion: "3.80.0"}, repoReport.JFrogPlatform)
+			assert.Equal(t, []string{"watch-1"}, repoReport.Watches)
+			assert.True(t, repoReport.FailOnSecurityIssues)
+			assert.Equal(t, []projectReport{{WorkingDirs: []string{"frontend"}, InstallCommand: "npm ci", DepsRepo: "npm-remote"}}, repoReport.Projects)
+			tc.expectedRepoCheck(t, repoReport)
+
+			var output bytes.Buffer
+			cmd.output = &output
+			assert.NoError(t, cmd.printReport(report))
+			assert.Contains(t, output.String(), "command: "+tc.targetCommand)
+			assert.Contains(t, output.String(), "name: jfrog/frogbot")
+		})
+	}
+}